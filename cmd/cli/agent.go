@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+	"github.com/kcaldas/genie/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewAgentCommandWithGenie creates the agent command, which works on a task
+// autonomously using a pre-initialized Genie instance
+func NewAgentCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent [task]",
+		Short: "Work on a task autonomously: plan, act, reflect until done",
+		Long: `Run Genie as an agent. It writes a plan, asks you to approve it, then
+iterates (act with tools, reflect on progress) until the task is done or a
+safety limit is reached.
+
+Examples:
+  genie agent "make the failing tests in ./pkg/foo pass"
+  genie agent --max-iterations 5 --max-cost 0.50 "add input validation to the signup handler"
+  genie agent --accept-all "update the changelog for the last release"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, _ := genieProvider()
+			return runAgentCommand(cmd, args, g)
+		},
+	}
+
+	cmd.Flags().Int("max-iterations", genie.DefaultAgentMaxIterations, "Maximum act/reflect iterations")
+	cmd.Flags().Int64("max-tokens", 0, "Stop once the run has used this many billed tokens, cached input included (0 = no limit)")
+	cmd.Flags().Float64("max-cost", 0, "Stop once the run's estimated cost reaches this many USD (0 = no limit)")
+	cmd.Flags().Bool("accept-all", false, "Approve plans and all tool confirmations without asking")
+
+	return cmd
+}
+
+func runAgentCommand(cmd *cobra.Command, args []string, g genie.Genie) error {
+	task := strings.Join(args, " ")
+	maxIterations, _ := cmd.Flags().GetInt("max-iterations")
+	maxTokens, _ := cmd.Flags().GetInt64("max-tokens")
+	maxCost, _ := cmd.Flags().GetFloat64("max-cost")
+	acceptAll, _ := cmd.Flags().GetBool("accept-all")
+
+	logger := logging.GetGlobalLogger()
+	eventBus := g.GetEventBus()
+
+	table, err := pricing.LoadTable(config.NewConfigManager())
+	if err != nil {
+		logger.Warn("using default pricing table", "error", err)
+	}
+
+	opts := []genie.AgentOption{
+		genie.WithAgentLimits(genie.AgentLimits{
+			MaxIterations: maxIterations,
+			MaxTokens:     maxTokens,
+			MaxCost:       maxCost,
+		}),
+		genie.WithAgentPricing(table),
+	}
+	if acceptAll {
+		autoAcceptConfirmations(cmd, eventBus, logger)
+		opts = append(opts, genie.WithAgentAutoApprove())
+	} else {
+		confirmer := &promptConfirmer{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.ErrOrStderr()}
+		confirmer.answerRequests(eventBus)
+		opts = append(opts, genie.WithAgentConfirmer(confirmer))
+	}
+
+	events.SubscribeTo(eventBus, func(e events.AgentProgressEvent) {
+		if e.Phase != "done" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "[agent %d] %s: %s\n", e.Iteration, e.Phase, e.Message)
+		}
+	})
+
+	result, err := genie.NewAgent(g, opts...).Run(context.Background(), task)
+	if result.Summary != "" {
+		fmt.Fprintln(cmd.OutOrStdout(), result.Summary)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Agent stopped: %s after %d iteration(s); %d tokens, %s\n",
+		result.StopReason, result.Iterations,
+		result.Usage.Tokens(), pricing.FormatCost(result.Usage.Cost))
+	return err
+}
+
+// promptConfirmer asks for plan and tool approval on the terminal.
+type promptConfirmer struct {
+	mu  sync.Mutex
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// answerRequests prompts for every confirmation the tools publish while the
+// agent acts, since there is no TUI to answer them.
+func (c *promptConfirmer) answerRequests(eventBus events.EventBus) {
+	events.SubscribeTo(eventBus, func(req events.ToolConfirmationRequest) {
+		confirmed, _ := c.ConfirmExecution(context.Background(), req)
+		response := events.ToolConfirmationResponse{ExecutionID: req.ExecutionID, Confirmed: confirmed}
+		eventBus.Publish(response.Topic(), response)
+	})
+	events.SubscribeTo(eventBus, func(req events.UserConfirmationRequest) {
+		confirmed, _ := c.ConfirmContent(context.Background(), req)
+		response := events.UserConfirmationResponse{ExecutionID: req.ExecutionID, Confirmed: confirmed}
+		eventBus.Publish(response.Topic(), response)
+	})
+}
+
+func (c *promptConfirmer) ConfirmContent(ctx context.Context, req events.UserConfirmationRequest) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(c.out, "\n%s\n\n%s\n\n%s [y/N] ", req.Title, req.Content, req.Message)
+	answer, err := c.in.ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func (c *promptConfirmer) ConfirmExecution(ctx context.Context, req events.ToolConfirmationRequest) (bool, error) {
+	return c.ConfirmContent(ctx, events.UserConfirmationRequest{
+		Title:   fmt.Sprintf("Run %s?", req.ToolName),
+		Content: req.Command,
+		Message: req.Message,
+	})
+}
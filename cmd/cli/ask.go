@@ -7,8 +7,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
 	"github.com/kcaldas/genie/pkg/logging"
 	"github.com/spf13/cobra"
 )
@@ -21,38 +24,38 @@ func truncateContent(content string, maxLength int) string {
 	return content[:maxLength] + "..."
 }
 
-// constructMessage builds the message from stdin and/or command arguments
-func constructMessage(args []string) (string, error) {
-	var parts []string
-
-	// Read stdin if available
-	if hasStdinInput() {
-		stdinContent, err := readStdinInput()
-		if err != nil {
-			return "", err
-		}
-		if stdinContent != "" {
-			parts = append(parts, stdinContent)
-		}
+// constructMessage builds the prompt from the command arguments. Piped
+// input is returned separately, to be attached as the "stdin" context
+// part; without arguments it is the prompt itself.
+func constructMessage(args []string, stdin string) (message, stdinContext string, err error) {
+	message = strings.Join(args, " ")
+	if message != "" {
+		return message, stdin, nil
 	}
-
-	// Add command arguments if provided
-	if len(args) > 0 {
-		argsText := strings.Join(args, " ")
-		parts = append(parts, argsText)
+	if stdin == "" {
+		return "", "", fmt.Errorf("no input provided via stdin or arguments")
 	}
+	return stdin, "", nil
+}
 
-	if len(parts) == 0 {
-		return "", fmt.Errorf("no input provided via stdin or arguments")
+// readAskStdin reads piped input up to the --stdin-limit cap, appending a
+// notice when it had to be truncated
+func readAskStdin(cmd *cobra.Command) (string, error) {
+	if !hasStdinInput() {
+		return "", nil
 	}
-
-	// Join parts with a separator to distinguish stdin content from prompt
-	if len(parts) == 1 {
-		return parts[0], nil
+	limit, _ := cmd.Flags().GetInt("stdin-limit")
+	if limit <= 0 {
+		limit = defaultStdinLimit
 	}
 
-	// When both stdin and args are present, format as: <stdin_content>\n\n<prompt>
-	return strings.Join(parts, "\n\n"), nil
+	content, truncated, err := readLimited(cmd.InOrStdin(), limit)
+	if err != nil || !truncated {
+		return content, err
+	}
+	notice := stdinTruncationNotice(len(content), limit)
+	fmt.Fprintln(cmd.ErrOrStderr(), "Warning: "+notice)
+	return content + "\n\n" + notice, nil
 }
 
 // validateAskArgs validates arguments for the ask command
@@ -81,7 +84,9 @@ Examples:
   genie ask "What is the meaning of life?"
   git diff | genie ask "suggest a commit message"
   find . -name "*.go" | genie ask "what patterns do you see?"
-  cat README.md | genie ask "summarize this"`,
+  cat README.md | genie ask "summarize this"
+  cat error.log | genie ask "why is this failing?"
+  genie ask --schema release.json "describe the changes since v1.2"`,
 		Args: validateAskArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			g, session := genieProvider()
@@ -93,6 +98,13 @@ Examples:
 	// Add flags
 	cmd.Flags().Bool("accept-all", false, "Automatically accept all confirmations (useful for scripting)")
 	cmd.Flags().Bool("debug", false, "Enable debug logging for ask command events")
+	cmd.Flags().Bool("show-cost", false, "Print token usage and estimated cost to stderr when done")
+	cmd.Flags().String("schema", "", "JSON Schema file the answer must match; prints the validated JSON")
+	cmd.Flags().Int("stdin-limit", defaultStdinLimit, "Most bytes of piped input to attach; longer input is truncated with a notice")
+	cmd.Flags().Int32("max-output-tokens", 0, "Longest answer, in tokens (default: the persona's)")
+	cmd.Flags().StringArray("stop", nil, "Stop the answer when the model writes this text (repeatable)")
+	cmd.Flags().Float32("frequency-penalty", 0, "Discourage repeating frequent tokens, -2 to 2 (not supported by Anthropic)")
+	cmd.Flags().Float32("presence-penalty", 0, "Discourage repeating any earlier token, -2 to 2 (not supported by Anthropic)")
 
 	return cmd
 }
@@ -100,14 +112,42 @@ Examples:
 // runAskCommandWithSession runs the ask command using a pre-created session
 func runAskCommandWithSession(cmd *cobra.Command, args []string, g genie.Genie, session genie.Session, eventBus events.EventBus) error {
 	// Construct message from stdin and/or arguments
-	message, err := constructMessage(args)
+	stdin, err := readAskStdin(cmd)
+	if err != nil {
+		return err
+	}
+	message, stdinContext, err := constructMessage(args, stdin)
 	if err != nil {
 		return fmt.Errorf("failed to construct message: %w", err)
 	}
 
+	out, err := outputWriter(cmd)
+	if err != nil {
+		return err
+	}
+	outputControls, err := askOutputControls(cmd)
+	if err != nil {
+		return err
+	}
+	// From here on failures come from the model or tools, not from usage
+	cmd.SilenceUsage = true
+
 	// Check flags
 	acceptAll, _ := cmd.Flags().GetBool("accept-all")
 	debug, _ := cmd.Flags().GetBool("debug")
+	showCost, _ := cmd.Flags().GetBool("show-cost")
+	schemaPath, _ := cmd.Flags().GetString("schema")
+
+	chatOpts := []genie.ChatOption{genie.WithStreaming(true)}
+	if schemaPath != "" {
+		schema, err := ai.LoadResponseSchema(schemaPath)
+		if err != nil {
+			return err
+		}
+		// Stream nothing: only the validated answer is printed
+		chatOpts = []genie.ChatOption{genie.WithResponseSchema(schema)}
+	}
+	chatOpts = append(chatOpts, genie.WithContextPart("stdin", stdinContext), genie.WithOutputControls(outputControls))
 
 	// Check if verbose flag is set from parent command
 	verbose := false
@@ -161,15 +201,15 @@ func runAskCommandWithSession(cmd *cobra.Command, args []string, g genie.Genie,
 					final := strings.TrimSpace(resp.Response)
 					streamedText := strings.TrimSpace(streamedBuilder.String())
 					if final != "" && final != streamedText {
-						fmt.Println()
-						fmt.Println(final)
+						fmt.Fprintln(out)
+						fmt.Fprintln(out, final)
 					} else {
-						fmt.Println()
+						fmt.Fprintln(out)
 					}
 					streamed = false
 					streamedBuilder.Reset()
 				} else {
-					fmt.Println(resp.Response)
+					fmt.Fprintln(out, resp.Response)
 				}
 				done <- nil // Success
 			}
@@ -202,57 +242,40 @@ func runAskCommandWithSession(cmd *cobra.Command, args []string, g genie.Genie,
 	eventBus.Subscribe("chat.chunk", func(event interface{}) {
 		if chunkEvent, ok := event.(events.ChatChunkEvent); ok {
 			if chunkEvent.Chunk != nil && chunkEvent.Chunk.Text != "" {
-				fmt.Print(chunkEvent.Chunk.Text)
+				fmt.Fprint(out, chunkEvent.Chunk.Text)
 				streamedBuilder.WriteString(chunkEvent.Chunk.Text)
 				streamed = true
 			}
 		}
 	})
 
-	// If --accept-all is enabled, automatically respond to confirmation requests
-	if acceptAll {
-		logger.Debug("setting up auto-confirmation handlers")
-
-		// Auto-approve regular tool confirmations
-		logger.Debug("subscribing to tool.confirmation.request events")
-		eventBus.Subscribe("tool.confirmation.request", func(event interface{}) {
-			logger.Debug("received tool.confirmation.request event")
-			if confirmationEvent, ok := event.(events.ToolConfirmationRequest); ok {
-				logger.Debug("auto-accepting tool confirmation", "tool", confirmationEvent.ToolName, "command", confirmationEvent.Command)
-				cmd.Printf("Auto-accepting: %s - %s\n", confirmationEvent.ToolName, confirmationEvent.Command)
-				response := events.ToolConfirmationResponse{
-					ExecutionID: confirmationEvent.ExecutionID,
-					Confirmed:   true,
-				}
-				logger.Debug("publishing tool confirmation response", "topic", response.Topic())
-				eventBus.Publish(response.Topic(), response)
-			} else {
-				logger.Debug("event is not ToolConfirmationRequest", "event_type", fmt.Sprintf("%T", event))
+	// Track usage for --show-cost. A flush marker published on the same
+	// topic lets us wait for every earlier token.count to be recorded.
+	var usage *pricing.Tracker
+	if showCost {
+		table, err := pricing.LoadTable(config.NewConfigManager())
+		if err != nil {
+			logger.Warn("using default pricing table", "error", err)
+		}
+		usage = pricing.NewTracker(table)
+		eventBus.Subscribe("token.count", func(event interface{}) {
+			switch e := event.(type) {
+			case events.TokenCountEvent:
+				usage.Record(e)
+			case chan struct{}:
+				close(e)
 			}
 		})
+	}
 
-		// Auto-approve diff confirmations
-		logger.Debug("subscribing to user.confirmation.request events")
-		eventBus.Subscribe("user.confirmation.request", func(event interface{}) {
-			logger.Debug("received user.confirmation.request event")
-			if confirmEvent, ok := event.(events.UserConfirmationRequest); ok {
-				logger.Debug("auto-accepting user confirmation", "content_type", confirmEvent.ContentType, "file_path", confirmEvent.FilePath)
-				cmd.Printf("Auto-accepting %s: %s\n", confirmEvent.ContentType, confirmEvent.FilePath)
-				response := events.UserConfirmationResponse{
-					ExecutionID: confirmEvent.ExecutionID,
-					Confirmed:   true,
-				}
-				logger.Debug("publishing user confirmation response", "topic", response.Topic())
-				eventBus.Publish(response.Topic(), response)
-			} else {
-				logger.Debug("event is not UserConfirmationRequest", "event_type", fmt.Sprintf("%T", event))
-			}
-		})
+	// If --accept-all is enabled, automatically respond to confirmation requests
+	if acceptAll {
+		autoAcceptConfirmations(cmd, eventBus, logger)
 	}
 
 	// Start chat with Genie
 	logger.Debug("starting chat with Genie")
-	err = g.Chat(context.Background(), message, genie.WithStreaming(true))
+	err = g.Chat(context.Background(), message, chatOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to start chat: %w", err)
 	}
@@ -270,9 +293,92 @@ func runAskCommandWithSession(cmd *cobra.Command, args []string, g genie.Genie,
 	select {
 	case err := <-done:
 		logger.Debug("received completion signal", "error", err)
+		if usage != nil {
+			printUsage(cmd, eventBus, usage)
+		}
 		return err
 	case <-time.After(timeout):
 		logger.Debug("timeout reached")
 		return fmt.Errorf("timeout waiting for response")
 	}
 }
+
+// autoAcceptConfirmations answers every tool and content confirmation
+// request on eventBus with approval, announcing each one on cmd's output.
+func autoAcceptConfirmations(cmd *cobra.Command, eventBus events.EventBus, logger logging.Logger) {
+	logger.Debug("setting up auto-confirmation handlers")
+
+	// Auto-approve regular tool confirmations
+	logger.Debug("subscribing to tool.confirmation.request events")
+	eventBus.Subscribe("tool.confirmation.request", func(event interface{}) {
+		logger.Debug("received tool.confirmation.request event")
+		if confirmationEvent, ok := event.(events.ToolConfirmationRequest); ok {
+			logger.Debug("auto-accepting tool confirmation", "tool", confirmationEvent.ToolName, "command", confirmationEvent.Command)
+			cmd.Printf("Auto-accepting: %s - %s\n", confirmationEvent.ToolName, confirmationEvent.Command)
+			response := events.ToolConfirmationResponse{
+				ExecutionID: confirmationEvent.ExecutionID,
+				Confirmed:   true,
+			}
+			logger.Debug("publishing tool confirmation response", "topic", response.Topic())
+			eventBus.Publish(response.Topic(), response)
+		} else {
+			logger.Debug("event is not ToolConfirmationRequest", "event_type", fmt.Sprintf("%T", event))
+		}
+	})
+
+	// Auto-approve diff confirmations
+	logger.Debug("subscribing to user.confirmation.request events")
+	eventBus.Subscribe("user.confirmation.request", func(event interface{}) {
+		logger.Debug("received user.confirmation.request event")
+		if confirmEvent, ok := event.(events.UserConfirmationRequest); ok {
+			logger.Debug("auto-accepting user confirmation", "content_type", confirmEvent.ContentType, "file_path", confirmEvent.FilePath)
+			cmd.Printf("Auto-accepting %s: %s\n", confirmEvent.ContentType, confirmEvent.FilePath)
+			response := events.UserConfirmationResponse{
+				ExecutionID: confirmEvent.ExecutionID,
+				Confirmed:   true,
+			}
+			logger.Debug("publishing user confirmation response", "topic", response.Topic())
+			eventBus.Publish(response.Topic(), response)
+		} else {
+			logger.Debug("event is not UserConfirmationRequest", "event_type", fmt.Sprintf("%T", event))
+		}
+	})
+}
+
+// printUsage waits for pending token.count events and prints the session's
+// token usage and estimated cost to stderr.
+func printUsage(cmd *cobra.Command, eventBus events.EventBus, usage *pricing.Tracker) {
+	flushed := make(chan struct{})
+	eventBus.Publish("token.count", flushed)
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+	}
+
+	session := usage.Session()
+	fmt.Fprintf(cmd.ErrOrStderr(), "Cost: %s (input: %d, output: %d, cached: %d tokens, %.0f%% cache hit)\n",
+		pricing.FormatCost(session.Cost), session.InputTokens, session.OutputTokens, session.CachedTokens, session.CacheHitRatio()*100)
+	if len(session.UnpricedModels) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "No pricing for: %s\n", strings.Join(session.UnpricedModels, ", "))
+	}
+}
+
+// askOutputControls reads the flags that override the persona's output
+// settings
+func askOutputControls(cmd *cobra.Command) (genie.OutputControls, error) {
+	var controls genie.OutputControls
+	controls.MaxOutputTokens, _ = cmd.Flags().GetInt32("max-output-tokens")
+	controls.StopSequences, _ = cmd.Flags().GetStringArray("stop")
+	controls.FrequencyPenalty, _ = cmd.Flags().GetFloat32("frequency-penalty")
+	controls.PresencePenalty, _ = cmd.Flags().GetFloat32("presence-penalty")
+
+	if controls.MaxOutputTokens < 0 {
+		return controls, fmt.Errorf("--max-output-tokens must be positive")
+	}
+	for name, penalty := range map[string]float32{"frequency-penalty": controls.FrequencyPenalty, "presence-penalty": controls.PresencePenalty} {
+		if penalty < -2 || penalty > 2 {
+			return controls, fmt.Errorf("--%s must be between -2 and 2", name)
+		}
+	}
+	return controls, nil
+}
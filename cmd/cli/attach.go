@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/pipe"
+	"github.com/kcaldas/genie/cmd/tui"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/sessions"
+	"github.com/spf13/cobra"
+)
+
+// newAttachCommand creates the attach command, which opens the TUI in a
+// named session's working directory
+func newAttachCommand() *cobra.Command {
+	var registry *sessions.Registry
+	cmd := &cobra.Command{
+		Use:   "attach <name>",
+		Short: "Open the TUI in a named session, creating it for this directory",
+		Long: `A named session ties a name to a working directory, so each project can
+have its own Genie running at once, each in its own terminal. The first attach
+creates the session for --cwd or the current directory; later ones start Genie
+in that directory from anywhere. A session can be attached by one Genie at a
+time. 'genie sessions list' shows every session and who has it attached.
+
+genie --pipe serves named sessions too: a request with "session": "<name>"
+goes to a Genie started in that session's directory.
+
+Examples:
+  genie attach backend-api --cwd ~/src/backend-api   # Create the session
+  genie attach backend-api                           # Pick it up again from anywhere
+  genie attach backend-api --cwd ~/src/api-v2        # Point it at another directory`,
+		Args: cobra.ExactArgs(1),
+		// The session decides the working directory Genie starts in
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			var err error
+			if registry, err = sessions.DefaultRegistry(); err != nil {
+				return err
+			}
+			session, err := attachSession(registry, args[0], workingDir)
+			if err != nil {
+				return err
+			}
+			workingDir = session.WorkingDir
+			if err := RootCmd.PersistentPreRunE(cmd, args); err != nil {
+				_ = registry.Detach(session.Name)
+				return err
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defer registry.Detach(args[0])
+			if !isTerminalWriter(cmd.OutOrStdout()) {
+				return fmt.Errorf("genie attach needs a terminal; scripts can use genie --pipe with \"session\": %q in their requests", args[0])
+			}
+
+			if accessible {
+				os.Setenv(types.AccessibleEnv, "true")
+			}
+			tuiApp, err := tui.InjectTUI(initialSession)
+			if err != nil {
+				return err
+			}
+			defer tuiApp.Stop()
+			err = tuiApp.StartWithMessage("")
+			tuiApp.Stop()
+			writeJournal(cmd.Context(), config.NewConfigManager(), genieInstance, initialSession, os.Stderr)
+			return err
+		},
+	}
+	return cmd
+}
+
+// attachSession attaches the session called name to the TUI. A new session
+// is created for dir, or the current directory when dir is empty; an
+// existing one moves to dir when it is given.
+func attachSession(registry *sessions.Registry, name, dir string) (sessions.Session, error) {
+	if dir == "" {
+		_, exists, err := registry.Get(name)
+		if err != nil {
+			return sessions.Session{}, err
+		}
+		if !exists {
+			if dir, err = os.Getwd(); err != nil {
+				return sessions.Session{}, fmt.Errorf("failed to get current directory: %w", err)
+			}
+		}
+	}
+	return registry.Attach(name, dir, sessions.ModeTUI)
+}
+
+// openNamedSession starts Genie in a named session's directory for a
+// genie --pipe request naming it. The session stays attached to this
+// process until the server releases it.
+func openNamedSession(registry *sessions.Registry) pipe.Opener {
+	return func(name string) (genie.Genie, func(), error) {
+		session, err := registry.Attach(name, "", sessions.ModePipe)
+		if err != nil {
+			return nil, nil, err
+		}
+		g, err := genie.ProvideGenie()
+		if err != nil {
+			_ = registry.Detach(name)
+			return nil, nil, fmt.Errorf("failed to initialize Genie: %w", err)
+		}
+
+		// Nobody can be asked about trust here: stdin carries requests
+		trusted := workspaceTrusted(openTrustStore(os.Stderr), []string{session.WorkingDir}, trustNow, strings.NewReader(""), os.Stderr, false)
+		var personaPtr *string
+		if persona != "" {
+			personaPtr = &persona
+		}
+		if _, err := g.Start(&session.WorkingDir, personaPtr, startOptions(trusted)...); err != nil {
+			g.Shutdown()
+			_ = registry.Detach(name)
+			return nil, nil, fmt.Errorf("failed to start session %q in %s: %w", name, session.WorkingDir, err)
+		}
+		return g, func() {
+			g.Shutdown()
+			_ = registry.Detach(name)
+		}, nil
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(newAttachCommand())
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/spf13/cobra"
+)
+
+// newAuditCommand creates the audit command, which reviews the tool calls
+// recorded in .genie/audit. It reads files only, so it skips starting Genie.
+func newAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Review the tool calls Genie executed",
+		Long: `Every tool call Genie executes is recorded in .genie/audit/<session>.jsonl
+with its parameters, confirmation outcome, duration, exit status and output size.
+
+Examples:
+  genie audit list                 # Sessions with an audit log, newest first
+  genie audit show                 # Report for the most recent session
+  genie audit show session-1a2b3c  # Report for a specific session
+  genie audit show --json          # Raw JSON lines`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List sessions that have an audit log",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			home, err := auditHome()
+			if err != nil {
+				return err
+			}
+			return runAuditList(cmd.OutOrStdout(), home)
+		},
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show [session-id]",
+		Short: "Show the tool calls of a session (default: the most recent)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			home, err := auditHome()
+			if err != nil {
+				return err
+			}
+			sessionID := ""
+			if len(args) == 1 {
+				sessionID = args[0]
+			}
+			asJSON, _ := cmd.Flags().GetBool("json")
+			return runAuditShow(cmd.OutOrStdout(), home, sessionID, asJSON)
+		},
+	}
+	showCmd.Flags().Bool("json", false, "Print the raw JSON lines instead of a report")
+
+	cmd.AddCommand(listCmd, showCmd)
+	return cmd
+}
+
+// auditHome returns the directory whose .genie/audit is read: --cwd when
+// given, otherwise the current directory.
+func auditHome() (string, error) {
+	if workingDir != "" {
+		return filepath.Abs(workingDir)
+	}
+	home, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return home, nil
+}
+
+func runAuditList(out io.Writer, genieHome string) error {
+	sessions, err := audit.Sessions(genieHome)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Fprintf(out, "No audit logs in %s\n", audit.Dir(genieHome))
+		return nil
+	}
+	for _, session := range sessions {
+		entries, err := audit.Read(session.Path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s  %s  %d tool calls\n", session.ModTime.Local().Format("2006-01-02 15:04"), session.SessionID, len(entries))
+	}
+	return nil
+}
+
+func runAuditShow(out io.Writer, genieHome, sessionID string, asJSON bool) error {
+	sessions, err := audit.Sessions(genieHome)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no audit logs in %s", audit.Dir(genieHome))
+	}
+
+	session := sessions[0]
+	if sessionID != "" {
+		found := false
+		for _, candidate := range sessions {
+			// Allow an unambiguous prefix, like git does for hashes
+			if candidate.SessionID == sessionID || strings.HasPrefix(candidate.SessionID, sessionID) {
+				if found {
+					return fmt.Errorf("session %q is ambiguous", sessionID)
+				}
+				session, found = candidate, true
+				if candidate.SessionID == sessionID {
+					break
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("no audit log for session %q (see genie audit list)", sessionID)
+		}
+	}
+
+	entries, err := audit.Read(session.Path)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return audit.WriteReport(out, session.SessionID, entries)
+}
+
+func init() {
+	RootCmd.AddCommand(newAuditCommand())
+}
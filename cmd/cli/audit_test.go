@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAuditSession(t *testing.T, home, sessionID string, modTime time.Time, entries ...audit.Entry) {
+	t.Helper()
+	path := audit.Path(home, sessionID)
+	log := audit.NewLog(path)
+	for _, entry := range entries {
+		require.NoError(t, log.Append(entry))
+	}
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestRunAuditShow_DefaultsToMostRecentSession(t *testing.T) {
+	home := t.TempDir()
+	now := time.Now()
+	writeAuditSession(t, home, "session-old", now.Add(-time.Hour),
+		audit.Entry{Tool: "readFile", Status: audit.StatusOK})
+	writeAuditSession(t, home, "session-new", now,
+		audit.Entry{Tool: "bash", Params: map[string]any{"command": "go test ./..."}, Status: audit.StatusOK, Confirmation: audit.ConfirmationApproved})
+
+	var out bytes.Buffer
+	require.NoError(t, runAuditShow(&out, home, "", false))
+	assert.Contains(t, out.String(), "Session session-new: 1 tool calls")
+	assert.Contains(t, out.String(), "command=go test ./...")
+
+	// A unique prefix selects a session
+	out.Reset()
+	require.NoError(t, runAuditShow(&out, home, "session-o", false))
+	assert.Contains(t, out.String(), "Session session-old")
+
+	// A shared prefix is ambiguous
+	assert.Error(t, runAuditShow(&out, home, "session-", false))
+	assert.Error(t, runAuditShow(&out, home, "missing", false))
+}
+
+func TestRunAuditShow_NoLogs(t *testing.T) {
+	var out bytes.Buffer
+	assert.Error(t, runAuditShow(&out, t.TempDir(), "", false))
+
+	require.NoError(t, runAuditList(&out, t.TempDir()))
+	assert.Contains(t, out.String(), "No audit logs")
+}
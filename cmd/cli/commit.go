@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/template"
+	"github.com/spf13/cobra"
+)
+
+// CommitTemplateConfigKey names a file holding the prompt genie commit
+// sends to the model, replacing defaultCommitTemplate.
+const CommitTemplateConfigKey = "GENIE_COMMIT_TEMPLATE"
+
+// maxCommitDiffBytes caps the diff sent to the model.
+const maxCommitDiffBytes = 100 * 1024
+
+// emptyTreeHash is git's empty tree, the base when amending a root commit.
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// defaultCommitTemplate asks for a Conventional Commits message. Templates
+// can use {{.diff}}, {{.stat}}, {{.branch}} and {{.previous_message}}.
+const defaultCommitTemplate = `Write a git commit message for the changes below, following the Conventional Commits specification.
+
+Rules:
+- Subject line: <type>(<optional scope>): <description>, at most 72 characters, imperative mood, no trailing period.
+- Types: feat, fix, docs, style, refactor, perf, test, build, ci, chore, revert.
+- Add a body after a blank line only when the change needs explaining: what changed and why, wrapped at 72 columns.
+- Mark breaking changes with "!" after the type and a "BREAKING CHANGE:" footer.
+- Reply with the commit message only: no code fences, no commentary, and do not call any tools.
+{{if .previous_message}}
+The commit being amended currently has this message:
+{{.previous_message}}
+{{end}}
+Branch: {{.branch}}
+
+Files changed:
+{{.stat}}
+
+Diff:
+{{.diff}}`
+
+// NewCommitCommandWithGenie creates the commit command, which has the model
+// write a commit message for the staged changes and commits on approval
+func NewCommitCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commit",
+		Short: "Write a conventional commit message for the staged changes and commit",
+		Long: `Generate a Conventional Commits message for the staged changes, show it
+for approval, and commit with it.
+
+The prompt can be replaced with a template file (--template or
+GENIE_COMMIT_TEMPLATE) using {{.diff}}, {{.stat}}, {{.branch}} and
+{{.previous_message}}.
+
+Examples:
+  genie commit                  # Commit the staged changes
+  genie commit --all            # Include modified tracked files, like git commit -a
+  genie commit --amend          # Rewrite the last commit's message for its new contents
+  genie commit --yes            # Commit without asking`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, session := genieProvider()
+			return runCommitCommand(cmd, g, session.GetWorkingDirectory())
+		},
+	}
+
+	cmd.Flags().BoolP("all", "a", false, "Include modified and deleted tracked files, like git commit -a")
+	cmd.Flags().Bool("amend", false, "Amend the last commit instead of creating a new one")
+	cmd.Flags().BoolP("yes", "y", false, "Commit without asking for confirmation")
+	cmd.Flags().String("template", "", "Prompt template file (default: GENIE_COMMIT_TEMPLATE or the built-in Conventional Commits prompt)")
+
+	return cmd
+}
+
+// commitChanges describes what a commit would contain.
+type commitChanges struct {
+	Diff            string
+	Stat            string
+	Branch          string
+	PreviousMessage string
+}
+
+func runCommitCommand(cmd *cobra.Command, g genie.Genie, dir string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	amend, _ := cmd.Flags().GetBool("amend")
+	yes, _ := cmd.Flags().GetBool("yes")
+	templatePath, _ := cmd.Flags().GetString("template")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	changes, err := gatherCommitChanges(ctx, dir, all, amend)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(changes.Diff) == "" && !amend {
+		return errors.New("nothing to commit: stage changes with git add, or use --all")
+	}
+
+	fmt.Fprintln(cmd.ErrOrStderr(), "Writing commit message...")
+	message, err := generateCommitMessage(ctx, g, templatePath, changes)
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		confirmer := &promptConfirmer{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.ErrOrStderr()}
+		approved, err := confirmer.ConfirmContent(ctx, events.UserConfirmationRequest{
+			Title:       "Commit message",
+			Content:     strings.TrimRight(changes.Stat, "\n") + "\n\n" + message,
+			ContentType: "diff",
+			Message:     "Commit with this message?",
+		})
+		if err != nil {
+			return err
+		}
+		if !approved {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Commit cancelled.")
+			return nil
+		}
+	}
+
+	args := []string{"commit", "--file", "-"}
+	if all {
+		args = append(args, "--all")
+	}
+	if amend {
+		args = append(args, "--amend")
+	}
+	out, err := runGit(ctx, dir, message+"\n", args...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), out)
+	return nil
+}
+
+// generateCommitMessage has the model write a commit message for changes,
+// using the template at templatePath or GENIE_COMMIT_TEMPLATE.
+func generateCommitMessage(ctx context.Context, g genie.Genie, templatePath string, changes commitChanges) (string, error) {
+	if templatePath == "" {
+		templatePath = config.NewConfigManager().GetStringWithDefault(CommitTemplateConfigKey, "")
+	}
+	prompt, err := renderCommitPrompt(templatePath, changes)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := chatAndWait(ctx, g, prompt, genie.WithEphemeral(genie.EphemeralAll))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate commit message: %w", err)
+	}
+	message := stripCodeFence(response)
+	if message == "" {
+		return "", errors.New("the model returned an empty commit message")
+	}
+	return message, nil
+}
+
+// gatherCommitChanges collects the diff git commit would record with the
+// same flags. An amended commit contains the last commit's changes too.
+func gatherCommitChanges(ctx context.Context, dir string, all, amend bool) (commitChanges, error) {
+	var changes commitChanges
+	if _, err := runGit(ctx, dir, "", "rev-parse", "--git-dir"); err != nil {
+		return changes, fmt.Errorf("not a git repository: %s", dir)
+	}
+
+	_, headErr := runGit(ctx, dir, "", "rev-parse", "--verify", "HEAD")
+	hasHead := headErr == nil
+	if amend && !hasHead {
+		return changes, errors.New("nothing to amend: the repository has no commits yet")
+	}
+
+	// base is what the commit is compared against; empty means the index
+	// is compared with HEAD (git diff --cached).
+	base := ""
+	switch {
+	case amend:
+		base = emptyTreeHash
+		if _, err := runGit(ctx, dir, "", "rev-parse", "--verify", "HEAD~1"); err == nil {
+			base = "HEAD~1"
+		}
+	case all && hasHead:
+		base = "HEAD"
+	}
+
+	diffArgs := []string{"diff", "--cached"}
+	if base != "" {
+		diffArgs = append(diffArgs, base)
+		if all {
+			// Compare the working tree, as commit -a stages tracked files
+			diffArgs = []string{"diff", base}
+		}
+	}
+
+	var err error
+	if changes.Diff, err = runGit(ctx, dir, "", diffArgs...); err != nil {
+		return changes, err
+	}
+	if changes.Stat, err = runGit(ctx, dir, "", append(diffArgs, "--stat")...); err != nil {
+		return changes, err
+	}
+	if branch, err := runGit(ctx, dir, "", "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		changes.Branch = strings.TrimSpace(branch)
+	}
+	if amend {
+		previous, err := runGit(ctx, dir, "", "log", "-1", "--format=%B")
+		if err != nil {
+			return changes, err
+		}
+		changes.PreviousMessage = strings.TrimSpace(previous)
+	}
+	return changes, nil
+}
+
+// renderCommitPrompt fills the commit template, read from templatePath when
+// set, with the changes.
+func renderCommitPrompt(templatePath string, changes commitChanges) (string, error) {
+	content := defaultCommitTemplate
+	if templatePath != "" {
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read commit template: %w", err)
+		}
+		content = string(data)
+	}
+
+	prompt, err := template.NewEngine().RenderString(content, map[string]string{
+		"diff":             truncateForPrompt(changes.Diff, maxCommitDiffBytes),
+		"stat":             changes.Stat,
+		"branch":           changes.Branch,
+		"previous_message": changes.PreviousMessage,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render commit template: %w", err)
+	}
+	return prompt, nil
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initGitRepo(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		_, err := runGit(context.Background(), dir, "", args...)
+		require.NoError(t, err)
+	}
+}
+
+func writeAndStage(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	_, err := runGit(context.Background(), dir, "", "add", name)
+	require.NoError(t, err)
+}
+
+func TestGatherCommitChanges(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	ctx := context.Background()
+
+	_, err := gatherCommitChanges(ctx, dir, false, true)
+	assert.ErrorContains(t, err, "nothing to amend")
+
+	writeAndStage(t, dir, "a.txt", "one\n")
+	changes, err := gatherCommitChanges(ctx, dir, false, false)
+	require.NoError(t, err)
+	assert.Contains(t, changes.Diff, "+one")
+	assert.Contains(t, changes.Stat, "a.txt")
+
+	_, err = runGit(ctx, dir, "first\n", "commit", "--file", "-")
+	require.NoError(t, err)
+
+	// Unstaged edits only count with --all
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("two\n"), 0o644))
+	changes, err = gatherCommitChanges(ctx, dir, false, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes.Diff)
+	changes, err = gatherCommitChanges(ctx, dir, true, false)
+	require.NoError(t, err)
+	assert.Contains(t, changes.Diff, "+two")
+
+	// Amending the root commit diffs against the empty tree
+	changes, err = gatherCommitChanges(ctx, dir, false, true)
+	require.NoError(t, err)
+	assert.Contains(t, changes.Diff, "+one")
+	assert.Equal(t, "first", changes.PreviousMessage)
+}
+
+func TestCommitCommandCommitsGeneratedMessage(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	dir := session.GetWorkingDirectory()
+	initGitRepo(t, dir)
+	writeAndStage(t, dir, "greeting.txt", "hello\n")
+
+	changes, err := gatherCommitChanges(context.Background(), dir, false, false)
+	require.NoError(t, err)
+	prompt, err := renderCommitPrompt("", changes)
+	require.NoError(t, err)
+	fixture.ExpectSimpleMessage(prompt, "```\nfeat: add greeting\n```")
+
+	cmd := NewCommitCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--yes"})
+	require.NoError(t, cmd.Execute())
+
+	subject, err := runGit(context.Background(), dir, "", "log", "-1", "--format=%s")
+	require.NoError(t, err)
+	assert.Equal(t, "feat: add greeting", strings.TrimSpace(subject))
+}
+
+func TestCommitCommandDeclined(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	dir := session.GetWorkingDirectory()
+	initGitRepo(t, dir)
+	writeAndStage(t, dir, "greeting.txt", "hello\n")
+
+	changes, err := gatherCommitChanges(context.Background(), dir, false, false)
+	require.NoError(t, err)
+	prompt, err := renderCommitPrompt("", changes)
+	require.NoError(t, err)
+	fixture.ExpectSimpleMessage(prompt, "feat: add greeting")
+
+	cmd := NewCommitCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader("n\n"))
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stderr.String(), "feat: add greeting")
+	assert.Contains(t, stderr.String(), "Commit cancelled.")
+
+	_, err = runGit(context.Background(), dir, "", "rev-parse", "--verify", "HEAD")
+	assert.Error(t, err, "nothing should be committed")
+}
+
+func TestRenderCommitPromptCustomTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commit.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("Summarize {{.branch}}:\n{{.stat}}"), 0o644))
+
+	prompt, err := renderCommitPrompt(path, commitChanges{Branch: "main", Stat: " a.txt | 1 +"})
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize main:\n a.txt | 1 +", prompt)
+
+	_, err = renderCommitPrompt(filepath.Join(t.TempDir(), "missing"), commitChanges{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/credentials"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// newCredentialsCommand creates the credentials command, which stores API
+// keys in the OS keychain or the encrypted credentials file. It needs no
+// model, so it skips starting Genie.
+func newCredentialsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Store API keys in the OS keychain instead of plaintext config",
+		Long: `Store API keys in the OS keychain (macOS Keychain, the Secret Service on
+Linux, Windows Credential Manager) and refer to them from the environment,
+.env or ~/.genie/config.env as keychain:<name>.
+
+With GENIE_CREDENTIALS_BACKEND=file, keys go to ~/.genie/credentials.enc
+instead, encrypted with the passphrase in GENIE_CREDENTIALS_PASSPHRASE.
+
+Examples:
+  genie credentials set openai       # Asks for the key without echoing it
+  echo "$KEY" | genie credentials set anthropic
+  export OPENAI_API_KEY=keychain:openai
+  genie credentials delete openai`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Store a key, read from the terminal or stdin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := credentials.ValidateName(name); err != nil {
+				return err
+			}
+			store, err := openCredentialStore(cmd)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Key for %s: ", name)
+			secret, err := readSecretLine(cmd.InOrStdin(), cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			if secret == "" {
+				return fmt.Errorf("no key given for %s", name)
+			}
+			if err := store.Set(name, secret); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Stored %s in the %s.\nRefer to it as %s, e.g. %s_API_KEY=%s\n",
+				name, store.Name(), credentials.Reference(name), strings.ToUpper(name), credentials.Reference(name))
+			return nil
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Remove a stored key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openCredentialStore(cmd)
+			if err != nil {
+				return err
+			}
+			if err := store.Delete(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s from the %s.\n", args[0], store.Name())
+			return nil
+		},
+	}
+
+	cmd.AddCommand(setCmd, deleteCmd)
+	return cmd
+}
+
+// openCredentialStore opens the configured store, asking for the file
+// passphrase when it is not set and a terminal is attached
+func openCredentialStore(cmd *cobra.Command) (credentials.Store, error) {
+	cfg := config.NewConfigManager()
+	backend := cfg.GetStringWithDefault(credentials.BackendConfigKey, "")
+	passphrase := cfg.GetStringWithDefault(credentials.PassphraseConfigKey, "")
+	if strings.EqualFold(backend, "file") && passphrase == "" && isInteractiveTerminal() {
+		fmt.Fprint(cmd.ErrOrStderr(), "Passphrase for the credentials file: ")
+		var err error
+		if passphrase, err = readSecretLine(os.Stdin, cmd.ErrOrStderr()); err != nil {
+			return nil, err
+		}
+	}
+	return credentials.Open(backend, passphrase)
+}
+
+// readSecretLine reads one line without echoing it when in is a terminal
+func readSecretLine(in io.Reader, out io.Writer) (string, error) {
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		secret, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(out)
+		return strings.TrimSpace(string(secret)), err
+	}
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("no input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func init() {
+	RootCmd.AddCommand(newCredentialsCommand())
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(credentials.BackendConfigKey, "file")
+	t.Setenv(credentials.PassphraseConfigKey, "correct horse")
+
+	run := func(input string, args ...string) (string, error) {
+		cmd := newCredentialsCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(strings.NewReader(input))
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("sk-openai\n", "set", "openai")
+	require.NoError(t, err)
+	assert.Contains(t, out, "OPENAI_API_KEY=keychain:openai")
+
+	store, err := credentials.NewFileStore(filepath.Join(home, ".genie", credentials.FileName), "correct horse")
+	require.NoError(t, err)
+	secret, err := store.Get("openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-openai", secret)
+
+	_, err = run("", "set", "bad name")
+	assert.ErrorContains(t, err, "must not contain spaces")
+
+	_, err = run("", "delete", "openai")
+	require.NoError(t, err)
+	_, err = store.Get("openai")
+	assert.ErrorIs(t, err, credentials.ErrNotFound)
+}
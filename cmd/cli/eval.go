@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/eval"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+	"github.com/kcaldas/genie/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewEvalCommandWithGenie creates the eval command and its subcommands.
+func NewEvalCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Evaluate personas and chains against test suites",
+	}
+	cmd.AddCommand(newEvalRunCommand(genieProvider))
+	return cmd
+}
+
+func newEvalRunCommand(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <suite.yaml>",
+		Short: "Run an evaluation suite and report what passed",
+		Long: `Run every case of a suite against its persona, prompt file or chain, on each
+of its models, and check the outputs with the case assertions: contains,
+not_contains, regex, json_schema, or llm (judged by a grading model). The
+report lists each case with its tokens and cost, and the totals per model.
+
+Cases run in read-only mode, so tools that change files or run commands are
+withheld, unless --accept-all lets them run and approves every confirmation.
+The command fails when any case fails, so it can gate CI.
+
+Examples:
+  genie eval run evals/reviewer.yaml
+  genie eval run evals/reviewer.yaml --model gpt-4o --model anthropic/claude-sonnet-4-5
+  genie eval run evals/reviewer.yaml --json > report.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, session := genieProvider()
+			return runEvalCommand(cmd, g, session, args[0])
+		},
+	}
+
+	cmd.Flags().StringSlice("model", nil, "Model to run the cases on, as model or provider/model (repeatable; replaces the suite's models)")
+	cmd.Flags().Bool("json", false, "Print the report as JSON")
+	cmd.Flags().Bool("accept-all", false, "Let cases use tools that change files or run commands, approving every confirmation")
+
+	return cmd
+}
+
+func runEvalCommand(cmd *cobra.Command, g genie.Genie, session genie.Session, path string) error {
+	modelList, _ := cmd.Flags().GetStringSlice("model")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	acceptAll, _ := cmd.Flags().GetBool("accept-all")
+	logger := logging.GetGlobalLogger()
+
+	suite, err := eval.LoadSuite(path)
+	if err != nil {
+		return err
+	}
+
+	table, err := pricing.LoadTable(config.NewConfigManager())
+	if err != nil {
+		logger.Warn("using default pricing table", "error", err)
+	}
+
+	if acceptAll {
+		autoAcceptConfirmations(cmd, g.GetEventBus(), logger)
+	} else {
+		readOnly := session.GetReadOnlyMode()
+		session.SetReadOnlyMode(true)
+		defer session.SetReadOnlyMode(readOnly)
+	}
+
+	stderr := cmd.ErrOrStderr()
+	opts := []eval.Option{
+		eval.WithPricing(table),
+		eval.WithProgress(func(result eval.CaseResult) {
+			mark := "FAIL"
+			if result.Passed {
+				mark = "PASS"
+			}
+			fmt.Fprintf(stderr, "%s  %s [%s]\n", mark, result.Case, result.Model)
+		}),
+	}
+	if len(modelList) > 0 {
+		opts = append(opts, eval.WithModels(modelList...))
+	}
+
+	report, err := eval.NewRunner(g, session, opts...).Run(cmd.Context(), suite)
+	if asJSON {
+		if writeErr := report.WriteJSON(cmd.OutOrStdout()); writeErr != nil {
+			return writeErr
+		}
+	} else {
+		report.WriteText(cmd.OutOrStdout())
+	}
+	if err != nil {
+		return err
+	}
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d of %d cases failed", failed, len(report.Results))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/eval"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeEvalSuite(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "suite.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: greetings
+cases:
+  - name: says hello
+    input: greet me
+    assert:
+      - contains: Hello
+  - name: is brief
+    input: greet me briefly
+    assert:
+      - regex: "^Hi$"
+`), 0o644))
+	return path
+}
+
+func TestEvalRunReportsAndFailsOnFailedCases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("greet me", "Hello there")
+	fixture.ExpectSimpleMessage("greet me briefly", "Hello there")
+
+	cmd := NewEvalCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var out, progress bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&progress)
+	cmd.SetArgs([]string{"run", writeEvalSuite(t)})
+
+	err := cmd.Execute()
+	require.EqualError(t, err, "1 of 2 cases failed")
+
+	assert.Contains(t, progress.String(), "PASS  says hello [default]")
+	assert.Contains(t, progress.String(), "FAIL  is brief [default]")
+	assert.Contains(t, out.String(), "Suite: greetings")
+	assert.Contains(t, out.String(), "matches /^Hi$/: no match in the output")
+
+	// Cases ran read-only, and the session is back to how it was
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.NotEmpty(t, prompts)
+	for _, prompt := range prompts {
+		for _, tool := range prompt.Functions {
+			assert.NotEqual(t, "writeFile", tool.Name)
+		}
+	}
+	assert.False(t, session.GetReadOnlyMode())
+}
+
+func TestEvalRunPrintsJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("greet me", "Hello")
+	fixture.ExpectSimpleMessage("greet me briefly", "Hi")
+
+	cmd := NewEvalCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"run", writeEvalSuite(t), "--json", "--model", "openai/gpt-4o"})
+	require.NoError(t, cmd.Execute())
+
+	var report eval.Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, "greetings", report.Suite)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "openai/gpt-4o", report.Results[0].Model)
+	assert.Zero(t, report.Failed())
+}
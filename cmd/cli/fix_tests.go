@@ -0,0 +1,410 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+// TestCommandConfigKey names the shell command genie fix-tests runs,
+// replacing the one detected from the project files.
+const TestCommandConfigKey = "GENIE_TEST_COMMAND"
+
+const (
+	// maxTestOutputBytes caps the failure output sent to the model.
+	maxTestOutputBytes = 30 * 1024
+	// maxFailureFiles caps how many files named in the failures are sent.
+	maxFailureFiles = 8
+	// maxFailureFileBytes caps each of those files.
+	maxFailureFileBytes = 32 * 1024
+)
+
+// testCommandMarkers maps project files to the test command they imply, in
+// detection order.
+var testCommandMarkers = []struct {
+	file    string
+	command string
+}{
+	{"go.mod", "go test ./..."},
+	{"Cargo.toml", "cargo test"},
+	{"package.json", "npm test"},
+	{"pyproject.toml", "pytest"},
+	{"setup.py", "pytest"},
+	{"pom.xml", "mvn -q test"},
+	{"build.gradle", "./gradlew test"},
+	{"mix.exs", "mix test"},
+	{"Makefile", "make test"},
+}
+
+// failureLocationPattern matches the path:line references test runners
+// print for failures, such as "pkg/foo/foo_test.go:42".
+var failureLocationPattern = regexp.MustCompile(`([\w./\\-]+\.[A-Za-z0-9]+):\d+`)
+
+// testFix is the patch the model proposes for the failing tests.
+type testFix struct {
+	Explanation string        `json:"explanation"`
+	Edits       []testFixEdit `json:"edits"`
+}
+
+// testFixEdit replaces the single occurrence of OldString in File.
+type testFixEdit struct {
+	File      string `json:"file"`
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
+}
+
+// NewFixTestsCommandWithGenie creates the fix-tests command, which runs the
+// tests and patches the code until they pass
+func NewFixTestsCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fix-tests",
+		Short: "Run the tests and patch the code until they pass",
+		Long: `Run the project's tests and, while they fail, send the failure output and
+the files it points at to the model, show the patch it proposes, apply it
+once you approve, and run the tests again. Stops when the tests pass, when
+you decline a patch, or after --max-attempts patches.
+
+The test command is --command, GENIE_TEST_COMMAND, or detected from the
+project files (go.mod, Cargo.toml, package.json, pyproject.toml, Makefile
+and others).
+
+Examples:
+  genie fix-tests                                   # Detect and run the test command
+  genie fix-tests --command "go test ./pkg/parser"  # Fix one package
+  genie fix-tests --max-attempts 5 --yes            # Apply patches without asking`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, session := genieProvider()
+			return runFixTestsCommand(cmd, g, session)
+		},
+	}
+
+	cmd.Flags().String("command", "", "Shell command that runs the tests (default: GENIE_TEST_COMMAND or detected)")
+	cmd.Flags().Int("max-attempts", 3, "Maximum number of patches to try")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "Timeout for each test run")
+	cmd.Flags().BoolP("yes", "y", false, "Apply patches without asking for confirmation")
+
+	return cmd
+}
+
+func runFixTestsCommand(cmd *cobra.Command, g genie.Genie, session genie.Session) error {
+	command, _ := cmd.Flags().GetString("command")
+	maxAttempts, _ := cmd.Flags().GetInt("max-attempts")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	dir := session.GetWorkingDirectory()
+	if command == "" {
+		command = config.NewConfigManager().GetStringWithDefault(TestCommandConfigKey, "")
+	}
+	if command == "" {
+		command = detectTestCommand(dir)
+	}
+	if command == "" {
+		return errors.New("no test command found: pass --command or set GENIE_TEST_COMMAND")
+	}
+
+	// The model proposes patches; only this command writes files.
+	readOnly := session.GetReadOnlyMode()
+	session.SetReadOnlyMode(true)
+	defer session.SetReadOnlyMode(readOnly)
+
+	ctx := context.Background()
+	stderr := cmd.ErrOrStderr()
+	confirmer := &promptConfirmer{in: bufio.NewReader(cmd.InOrStdin()), out: stderr}
+	var tried []string
+	for attempt := 0; ; attempt++ {
+		fmt.Fprintf(stderr, "Running %s...\n", command)
+		output, passed, err := runTestCommand(ctx, dir, command, timeout)
+		if err != nil {
+			return err
+		}
+		if passed {
+			if attempt == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Tests pass; nothing to fix.")
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Tests pass after %d patch(es).\n", attempt)
+			}
+			return nil
+		}
+		if attempt == maxAttempts {
+			fmt.Fprint(stderr, output)
+			return fmt.Errorf("tests still fail after %d patch(es)", maxAttempts)
+		}
+
+		fmt.Fprintf(stderr, "Tests failed; asking for a fix (attempt %d/%d)...\n", attempt+1, maxAttempts)
+		fix, err := proposeTestFix(ctx, g, fixTestsPrompt(dir, command, output, tried))
+		if err != nil {
+			return err
+		}
+		changes, err := planTestFix(dir, fix)
+		if err != nil {
+			return fmt.Errorf("the proposed patch cannot be applied: %w", err)
+		}
+
+		if !yes {
+			approved, err := confirmer.ConfirmContent(ctx, events.UserConfirmationRequest{
+				Title:       "Proposed fix",
+				Content:     fix.Explanation + "\n\n" + changes.diff(),
+				ContentType: "diff",
+				Message:     "Apply this patch and re-run the tests?",
+			})
+			if err != nil {
+				return err
+			}
+			if !approved {
+				fmt.Fprintln(stderr, "Patch declined; stopping.")
+				return nil
+			}
+		}
+		if err := changes.apply(); err != nil {
+			return err
+		}
+		tried = append(tried, fix.Explanation)
+	}
+}
+
+// detectTestCommand guesses the test command from the files in dir.
+func detectTestCommand(dir string) string {
+	for _, marker := range testCommandMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker.file)); err == nil {
+			return marker.command
+		}
+	}
+	return ""
+}
+
+// runTestCommand runs command through the shell in dir and returns its
+// combined output and whether it exited successfully. Only failing to
+// start or timing out is an error.
+func runTestCommand(ctx context.Context, dir, command string, timeout time.Duration) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return output.String(), false, fmt.Errorf("%s timed out after %s", command, timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return output.String(), false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to run %s: %w", command, err)
+	}
+	return output.String(), true, nil
+}
+
+// failureFiles returns the files in dir that the test output points at, in
+// order of first mention.
+func failureFiles(dir, output string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, match := range failureLocationPattern.FindAllStringSubmatch(output, -1) {
+		path := filepath.Clean(match[1])
+		if filepath.IsAbs(path) {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			path = rel
+		}
+		if seen[path] || strings.HasPrefix(path, "..") {
+			continue
+		}
+		seen[path] = true
+		if info, err := os.Stat(filepath.Join(dir, path)); err == nil && info.Mode().IsRegular() {
+			files = append(files, filepath.ToSlash(path))
+			if len(files) == maxFailureFiles {
+				break
+			}
+		}
+	}
+	return files
+}
+
+// fixTestsPrompt asks for a patch for the failures in output, quoting the
+// files they mention and the fixes already tried.
+func fixTestsPrompt(dir, command, output string, tried []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The test command `%s` fails. Find the cause and propose the smallest patch that makes the tests pass.\n\n", command)
+	b.WriteString("Fix the code under test rather than weakening or deleting tests, unless a test is clearly wrong. ")
+	b.WriteString("Read more files with the tools if you need them.\n\n")
+	b.WriteString("Answer with JSON: {\"explanation\": \"...\", \"edits\": [{\"file\": \"...\", \"old_string\": \"...\", \"new_string\": \"...\"}]}. ")
+	b.WriteString("Each old_string must be copied exactly from the file and occur in it exactly once; an empty old_string creates a new file containing new_string. ")
+	b.WriteString("File paths are relative to the project root.\n")
+
+	if len(tried) > 0 {
+		b.WriteString("\nThese patches were already applied and did not make the tests pass:\n")
+		for i, explanation := range tried {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, explanation)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nTest output:\n```\n%s\n```\n", truncateForPrompt(strings.TrimSpace(output), maxTestOutputBytes))
+	for _, file := range failureFiles(dir, output) {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n```\n%s\n```\n", file, truncateForPrompt(string(content), maxFailureFileBytes))
+	}
+	return b.String()
+}
+
+// testFixSchema is the JSON shape a proposed fix must take.
+func testFixSchema() *ai.Schema {
+	return &ai.Schema{
+		Type: ai.TypeObject,
+		Properties: map[string]*ai.Schema{
+			"explanation": {Type: ai.TypeString, Description: "What caused the failure and how the patch fixes it."},
+			"edits": {
+				Type:     ai.TypeArray,
+				MinItems: 1,
+				Items: &ai.Schema{
+					Type: ai.TypeObject,
+					Properties: map[string]*ai.Schema{
+						"file":       {Type: ai.TypeString},
+						"old_string": {Type: ai.TypeString},
+						"new_string": {Type: ai.TypeString},
+					},
+					Required: []string{"file", "old_string", "new_string"},
+				},
+			},
+		},
+		Required: []string{"explanation", "edits"},
+	}
+}
+
+func proposeTestFix(ctx context.Context, g genie.Genie, prompt string) (testFix, error) {
+	var fix testFix
+	response, err := chatAndWait(ctx, g, prompt,
+		genie.WithEphemeral(genie.EphemeralAll),
+		genie.WithResponseSchema(testFixSchema()))
+	if err != nil {
+		return fix, fmt.Errorf("failed to get a fix: %w", err)
+	}
+	if err := json.Unmarshal([]byte(ai.ExtractJSON(response)), &fix); err != nil {
+		return fix, fmt.Errorf("failed to parse the proposed fix: %w", err)
+	}
+	return fix, nil
+}
+
+// fileChange is the new content of one file touched by a fix.
+type fileChange struct {
+	path   string
+	name   string
+	before string
+	after  string
+	exists bool
+}
+
+type fileChanges []*fileChange
+
+// planTestFix applies the fix's edits in memory, checking that every edit
+// stays inside dir and matches its file exactly once.
+func planTestFix(dir string, fix testFix) (fileChanges, error) {
+	var changes fileChanges
+	byPath := make(map[string]*fileChange)
+	for i, edit := range fix.Edits {
+		name := filepath.Clean(filepath.FromSlash(edit.File))
+		if edit.File == "" || filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("edit %d: %q is not a path inside the project", i+1, edit.File)
+		}
+		path := filepath.Join(dir, name)
+
+		change, ok := byPath[path]
+		if !ok {
+			change = &fileChange{path: path, name: filepath.ToSlash(name)}
+			if data, err := os.ReadFile(path); err == nil {
+				change.before, change.exists = string(data), true
+			} else if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("edit %d: %w", i+1, err)
+			}
+			change.after = change.before
+			byPath[path] = change
+			changes = append(changes, change)
+		}
+
+		if edit.OldString == "" {
+			if change.after != "" {
+				return nil, fmt.Errorf("edit %d: %s already exists; old_string must not be empty", i+1, edit.File)
+			}
+			change.after = edit.NewString
+			continue
+		}
+		switch count := strings.Count(change.after, edit.OldString); count {
+		case 1:
+			change.after = strings.Replace(change.after, edit.OldString, edit.NewString, 1)
+		case 0:
+			return nil, fmt.Errorf("edit %d: old_string not found in %s", i+1, edit.File)
+		default:
+			return nil, fmt.Errorf("edit %d: old_string occurs %d times in %s", i+1, count, edit.File)
+		}
+	}
+	return changes, nil
+}
+
+// diff renders the changes as a unified diff for review.
+func (c fileChanges) diff() string {
+	var b strings.Builder
+	for _, change := range c {
+		from := "a/" + change.name
+		if !change.exists {
+			from = "/dev/null"
+		}
+		text, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(change.before),
+			B:        difflib.SplitLines(change.after),
+			FromFile: from,
+			ToFile:   "b/" + change.name,
+			Context:  3,
+		})
+		b.WriteString(text)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// apply writes the changed files.
+func (c fileChanges) apply() error {
+	for _, change := range c {
+		mode := os.FileMode(0o644)
+		if info, err := os.Stat(change.path); err == nil {
+			mode = info.Mode().Perm()
+		} else if err := os.MkdirAll(filepath.Dir(change.path), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", change.name, err)
+		}
+		if err := os.WriteFile(change.path, []byte(change.after), mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, detectTestCommand(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Makefile"), nil, 0o644))
+	assert.Equal(t, "make test", detectTestCommand(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), nil, 0o644))
+	assert.Equal(t, "go test ./...", detectTestCommand(dir), "language manifests win over a Makefile")
+}
+
+func TestFailureFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "sum.go"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "sum_test.go"), nil, 0o644))
+
+	output := "--- FAIL: TestSum\n    pkg/sum_test.go:12: got 3, want 4\n" +
+		"panic at " + filepath.Join(dir, "pkg", "sum.go") + ":7\n" +
+		"    pkg/sum_test.go:20: again\n/usr/lib/go/src/testing/testing.go:1595\n"
+	assert.Equal(t, []string{"pkg/sum_test.go", "pkg/sum.go"}, failureFiles(dir, output))
+}
+
+func TestPlanTestFix(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sum.go"), []byte("return a - b\n"), 0o644))
+
+	changes, err := planTestFix(dir, testFix{Edits: []testFixEdit{
+		{File: "sum.go", OldString: "a - b", NewString: "a + b"},
+		{File: "docs/sum.md", NewString: "Adds.\n"},
+	}})
+	require.NoError(t, err)
+	diff := changes.diff()
+	assert.Contains(t, diff, "-return a - b")
+	assert.Contains(t, diff, "+return a + b")
+	assert.Contains(t, diff, "--- /dev/null")
+
+	require.NoError(t, changes.apply())
+	data, err := os.ReadFile(filepath.Join(dir, "docs", "sum.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "Adds.\n", string(data))
+
+	for _, edit := range []testFixEdit{
+		{File: "sum.go", OldString: "missing", NewString: "x"},
+		{File: "../outside.go", NewString: "x"},
+		{File: "sum.go", NewString: "overwrite"},
+	} {
+		_, err := planTestFix(dir, testFix{Edits: []testFixEdit{edit}})
+		assert.Error(t, err, edit.File)
+	}
+}
+
+func TestFixTestsCommandPatchesUntilGreen(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell test command")
+	}
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	dir := session.GetWorkingDirectory()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "status.txt"), []byte("broken\n"), 0o644))
+
+	command := "grep -q fixed status.txt"
+	fixture.ExpectSimpleMessage(fixTestsPrompt(dir, command, "", nil),
+		`{"explanation":"status.txt must say fixed","edits":[{"file":"status.txt","old_string":"broken","new_string":"fixed"}]}`)
+
+	cmd := NewFixTestsCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader("y\n"))
+	cmd.SetArgs([]string{"--command", command})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, stderr.String(), "+fixed")
+	assert.Equal(t, "Tests pass after 1 patch(es).\n", stdout.String())
+	assert.False(t, session.GetReadOnlyMode(), "read-only mode is restored")
+}
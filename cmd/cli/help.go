@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/help"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// newHelpCommand replaces cobra's help command so genie help also shows
+// the topic pages and exports man pages
+func newHelpCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "help [command | topic]",
+		Short: "Help about any command, or a topic such as personas or tools",
+		Long: `Show help for a command, or read a topic page: a guide to a part of Genie
+that spans commands.
+
+Examples:
+  genie help ask                          # Help for a command
+  genie help topics                       # List the topics
+  genie help policies                     # Read a topic
+  genie help policies --man | man -l -    # Read it as a man page
+  genie help --man-dir /usr/local/share/man  # Install genie(1) and the topic pages`,
+		// Help must work before a backend is configured
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			names := []string{"topics"}
+			for _, topic := range help.Topics() {
+				names = append(names, topic.Name)
+			}
+			for _, sub := range cmd.Root().Commands() {
+				if sub.IsAvailableCommand() {
+					names = append(names, sub.Name())
+				}
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: runHelpCommand,
+	}
+
+	cmd.Flags().Bool("man", false, "Print the topic, or genie itself, as a man page")
+	cmd.Flags().String("man-dir", "", "Write genie.1 and a genie-<topic>.7 page per topic under this directory's man1 and man7")
+	return cmd
+}
+
+func runHelpCommand(cmd *cobra.Command, args []string) error {
+	asMan, _ := cmd.Flags().GetBool("man")
+	manDir, _ := cmd.Flags().GetString("man-dir")
+	root := cmd.Root()
+	out := cmd.OutOrStdout()
+
+	if manDir != "" {
+		return writeManPages(out, manDir, root)
+	}
+	if len(args) == 0 {
+		if asMan {
+			_, err := io.WriteString(out, rootManPage(root, time.Now()))
+			return err
+		}
+		return root.Help()
+	}
+	if args[0] == "topics" {
+		listTopics(out)
+		return nil
+	}
+	if topic, ok := help.Lookup(args[0]); ok {
+		if asMan {
+			_, err := io.WriteString(out, topic.ManPage(time.Now()))
+			return err
+		}
+		return showTopic(out, topic)
+	}
+
+	target, _, err := root.Find(args)
+	if err != nil || target == nil || target == root {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("unknown help topic %q: run 'genie help topics' to list the topics", strings.Join(args, " "))
+	}
+	target.InitDefaultHelpFlag()
+	target.InitDefaultVersionFlag()
+	return target.Help()
+}
+
+func listTopics(out io.Writer) {
+	fmt.Fprintln(out, "Help topics:")
+	for _, topic := range help.Topics() {
+		fmt.Fprintf(out, "  %-10s %s\n", topic.Name, topic.Summary)
+	}
+	fmt.Fprintln(out, "\nRun 'genie help <topic>' to read one, or 'genie help <topic> --man' for a man page.")
+}
+
+// showTopic renders the topic for a terminal, or prints its Markdown when
+// the output is piped or colors are off
+func showTopic(out io.Writer, topic help.Topic) error {
+	if color, err := useColor(colorMode, out); err != nil || !color {
+		_, err := io.WriteString(out, topic.Markdown)
+		return err
+	}
+
+	width := 80
+	if f, ok := out.(*os.File); ok {
+		if w, _, err := term.GetSize(int(f.Fd())); err == nil {
+			width = min(w, 100)
+		}
+	}
+	rendered, err := help.Render(topic.Markdown, width)
+	if err != nil {
+		_, err := io.WriteString(out, topic.Markdown)
+		return err
+	}
+	_, err = io.WriteString(out, rendered)
+	return err
+}
+
+// rootManPage describes genie and its commands as a section 1 man page
+func rootManPage(root *cobra.Command, date time.Time) string {
+	var md strings.Builder
+	fmt.Fprintf(&md, "# %s\n\n", root.Name())
+	fmt.Fprintf(&md, "## Synopsis\n\n`%s [flags]`\n\n`%s <command> [flags]`\n\n", root.Name(), root.Name())
+	if root.Long != "" {
+		fmt.Fprintf(&md, "## Description\n\n%s\n\n", root.Long)
+	}
+
+	md.WriteString("## Commands\n\n")
+	commands := root.Commands()
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name() < commands[j].Name() })
+	for _, sub := range commands {
+		if sub.IsAvailableCommand() || sub.Name() == "help" {
+			fmt.Fprintf(&md, "- `%s`: %s\n", sub.Name(), sub.Short)
+		}
+	}
+
+	var flags []string
+	addFlag := func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		name := "--" + flag.Name
+		if flag.Shorthand != "" {
+			name = "-" + flag.Shorthand + ", " + name
+		}
+		flags = append(flags, fmt.Sprintf("- `%s`: %s", name, flag.Usage))
+	}
+	root.NonInheritedFlags().VisitAll(addFlag)
+	root.InheritedFlags().VisitAll(addFlag)
+	if len(flags) > 0 {
+		md.WriteString("\n## Options\n\n" + strings.Join(flags, "\n") + "\n")
+	}
+
+	md.WriteString("\n## See also\n\n")
+	var pages []string
+	for _, topic := range help.Topics() {
+		pages = append(pages, topic.ManName()+"(7)")
+	}
+	md.WriteString(strings.Join(pages, ", ") + "\n")
+
+	return help.ManPage(root.Name(), 1, root.Short, md.String(), date)
+}
+
+// writeManPages installs genie.1 and the topic pages under dir, in the
+// man1 and man7 layout man(1) searches
+func writeManPages(out io.Writer, dir string, root *cobra.Command) error {
+	now := time.Now()
+	pages := map[string]string{
+		filepath.Join(dir, "man1", root.Name()+".1"): rootManPage(root, now),
+	}
+	for _, topic := range help.Topics() {
+		pages[filepath.Join(dir, "man7", topic.ManName()+".7")] = topic.ManPage(now)
+	}
+
+	paths := make([]string, 0, len(pages))
+	for path := range pages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(pages[path]), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, path)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.SetHelpCommand(newHelpCommand())
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelpCommand(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		root := &cobra.Command{Use: "genie", Short: "Genie AI coding assistant"}
+		root.AddCommand(&cobra.Command{Use: "ask", Short: "Ask the AI a question", Run: func(*cobra.Command, []string) {}})
+		root.SetHelpCommand(newHelpCommand())
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs(append([]string{"help"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("topics")
+	require.NoError(t, err)
+	assert.Contains(t, out, "policies")
+	assert.Contains(t, out, "Policies decide what the model may touch.")
+
+	// Piped output is the page's Markdown
+	out, err = run("policies")
+	require.NoError(t, err)
+	assert.Contains(t, out, "# Policies\n")
+
+	out, err = run("tools", "--man")
+	require.NoError(t, err)
+	assert.Contains(t, out, `.TH "GENIE-TOOLS" "7"`)
+
+	out, err = run("ask")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Ask the AI a question")
+
+	_, err = run("bogus")
+	assert.ErrorContains(t, err, `unknown help topic "bogus"`)
+
+	dir := t.TempDir()
+	_, err = run("--man-dir", dir)
+	require.NoError(t, err)
+	page, err := os.ReadFile(filepath.Join(dir, "man1", "genie.1"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "\\fBask\\fR: Ask the AI a question")
+	assert.Contains(t, string(page), "genie\\-policies(7)")
+	assert.FileExists(t, filepath.Join(dir, "man7", "genie-serving.7"))
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/history"
+	"github.com/spf13/cobra"
+)
+
+// newHistoryCommand creates the history command, which searches the
+// commands typed in the TUI. It reads files only, so it skips starting
+// Genie.
+func newHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Search the messages and commands typed in the TUI",
+		Long: `Every message and command typed in the TUI is kept in the project's
+.genie/history and in the user-wide ~/.genie/history, with the time and
+session it was entered in.
+
+Examples:
+  genie history search migration           # Both scopes, newest first
+  genie history search --scope project :model
+  genie history search --limit 5 refactor`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	searchCmd := &cobra.Command{
+		Use:   "search <term>",
+		Short: "Find history entries containing a term (case-insensitive)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scope, _ := cmd.Flags().GetString("scope")
+			limit, _ := cmd.Flags().GetInt("limit")
+			scopes, err := historyScopes(scope)
+			if err != nil {
+				return err
+			}
+			return runHistorySearch(cmd.OutOrStdout(), strings.Join(args, " "), scopes, limit)
+		},
+	}
+	searchCmd.Flags().String("scope", "all", "Where to search: project, user or all")
+	searchCmd.Flags().Int("limit", 20, "Most entries to show; 0 shows all")
+
+	cmd.AddCommand(searchCmd)
+	return cmd
+}
+
+// historyScopes returns the history files a --scope value covers. The
+// project is --cwd when given, otherwise the current directory, as for
+// genie audit.
+func historyScopes(scope string) ([]history.Scope, error) {
+	var scopes []history.Scope
+	if scope == "all" || scope == history.ScopeProject {
+		home, err := auditHome()
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, history.Scope{Name: history.ScopeProject, Path: history.ProjectHistoryPath(home)})
+	}
+	if scope == "all" || scope == history.ScopeUser {
+		path, err := history.UserHistoryPath()
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, history.Scope{Name: history.ScopeUser, Path: path})
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("invalid --scope %q: use project, user or all", scope)
+	}
+	return scopes, nil
+}
+
+func runHistorySearch(out io.Writer, term string, scopes []history.Scope, limit int) error {
+	matches, err := history.Search(term, scopes...)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		fmt.Fprintf(out, "No history entries match %q\n", term)
+		return nil
+	}
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	for _, match := range matches {
+		when := "unknown         "
+		if !match.Time.IsZero() {
+			when = match.Time.Local().Format("2006-01-02 15:04")
+		}
+		session := match.SessionID
+		if len(session) > 8 {
+			session = session[:8]
+		}
+		fmt.Fprintf(out, "%s  %-7s  %-8s  %s\n", when, match.Scope, session, strings.ReplaceAll(match.Command, "\n", " ⏎ "))
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(newHistoryCommand())
+}
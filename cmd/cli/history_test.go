@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHistorySearch(t *testing.T) {
+	scope := history.Scope{Name: history.ScopeProject, Path: filepath.Join(t.TempDir(), "history")}
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	for i, command := range []string{"add a users table", "explain the\nusers migration", "run tests"} {
+		entry := history.Entry{Command: command, Time: base.Add(time.Duration(i) * time.Minute), SessionID: "0123456789abcdef"}
+		require.NoError(t, history.AppendEntry(scope.Path, entry, 10))
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, runHistorySearch(&out, "users", []history.Scope{scope}, 0))
+	assert.Equal(t,
+		"2026-03-01 10:01  project  01234567  explain the ⏎ users migration\n"+
+			"2026-03-01 10:00  project  01234567  add a users table\n",
+		out.String())
+
+	out.Reset()
+	require.NoError(t, runHistorySearch(&out, "users", []history.Scope{scope}, 1))
+	assert.NotContains(t, out.String(), "add a users table")
+
+	out.Reset()
+	require.NoError(t, runHistorySearch(&out, "deploy", []history.Scope{scope}, 0))
+	assert.Contains(t, out.String(), "No history entries match")
+}
+
+func TestHistoryScopes(t *testing.T) {
+	scopes, err := historyScopes("all")
+	require.NoError(t, err)
+	assert.Len(t, scopes, 2)
+
+	scopes, err = historyScopes(history.ScopeUser)
+	require.NoError(t, err)
+	require.Len(t, scopes, 1)
+	assert.Equal(t, history.ScopeUser, scopes[0].Name)
+
+	_, err = historyScopes("team")
+	assert.Error(t, err)
+}
@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/spf13/cobra"
+)
+
+// HookTimeoutConfigKey sets how long a git hook may wait for Genie before
+// it gives up and lets the commit proceed.
+const HookTimeoutConfigKey = "GENIE_HOOK_TIMEOUT"
+
+// HookSkipEnv disables installed hooks for one command when set to 1, as in
+// GENIE_HOOK_SKIP=1 git commit.
+const HookSkipEnv = "GENIE_HOOK_SKIP"
+
+// hookMarker identifies hook scripts genie hook install wrote, so they can
+// be replaced and removed without touching other hooks.
+const hookMarker = "# genie-hook: managed by genie hook install"
+
+const defaultHookTimeout = 30 * time.Second
+
+// errHookTimeout reports that a hook ran out of time.
+var errHookTimeout = errors.New("timed out")
+
+// Hook names genie installs.
+const (
+	hookPrepareCommitMsg = "prepare-commit-msg"
+	hookPreCommit        = "pre-commit"
+)
+
+// newHookCommand creates the hook command, which installs git hooks that
+// run Genie on commit and serves as their entry point.
+func newHookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Run Genie from git hooks",
+		Long: `Install git hooks that write the commit message with Genie and, optionally,
+review the staged changes before each commit.
+
+Hooks never block a commit on Genie itself: when the model is slow or
+unavailable they give up after the timeout and let git continue. Set
+GENIE_HOOK_SKIP=1 to bypass them for one command.
+
+Examples:
+  genie hook install                   # Generate commit messages
+  genie hook install --lint            # Also review staged changes, failing on errors
+  genie hook install --timeout 1m      # Give the model more time
+  genie hook uninstall                 # Remove Genie's hooks`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install Genie's git hooks in the current repository",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lint, _ := cmd.Flags().GetBool("lint")
+			force, _ := cmd.Flags().GetBool("force")
+			failOn, _ := cmd.Flags().GetString("fail-on")
+			// An explicit timeout is written into the scripts; otherwise
+			// GENIE_HOOK_TIMEOUT applies when they run.
+			var timeout time.Duration
+			if cmd.Flags().Changed("timeout") {
+				timeout, _ = cmd.Flags().GetDuration("timeout")
+			}
+			if severityRank[failOn] == 0 {
+				return fmt.Errorf("unknown severity %q: use error, warning or note", failOn)
+			}
+			dir, err := hookRepoDir()
+			if err != nil {
+				return err
+			}
+			hooksDir, err := gitHooksDir(cmd.Context(), dir)
+			if err != nil {
+				return err
+			}
+			installed, err := installHooks(hooksDir, hookScripts(lint, timeout, failOn), force)
+			for _, name := range installed {
+				fmt.Fprintf(cmd.OutOrStdout(), "Installed %s\n", filepath.Join(hooksDir, name))
+			}
+			return err
+		},
+	}
+	installCmd.Flags().Bool("lint", false, "Also install a pre-commit hook that reviews the staged changes")
+	installCmd.Flags().String("fail-on", severityError, "Severity that makes the pre-commit review reject the commit: error, warning or note")
+	installCmd.Flags().Duration("timeout", 0, "How long each hook waits for Genie before letting the commit proceed (default: GENIE_HOOK_TIMEOUT or 30s)")
+	installCmd.Flags().Bool("force", false, "Replace existing hooks that Genie did not install")
+
+	uninstallCmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the git hooks Genie installed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := hookRepoDir()
+			if err != nil {
+				return err
+			}
+			hooksDir, err := gitHooksDir(cmd.Context(), dir)
+			if err != nil {
+				return err
+			}
+			removed, err := uninstallHooks(hooksDir)
+			if err != nil {
+				return err
+			}
+			if len(removed) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No Genie hooks installed.")
+			}
+			for _, name := range removed {
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", filepath.Join(hooksDir, name))
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(installCmd, uninstallCmd, newHookRunCommand())
+	return cmd
+}
+
+// newHookRunCommand creates the hidden entry point the installed scripts
+// call. Unlike the other hook subcommands it starts Genie.
+func newHookRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "run",
+		Short:  "Run a git hook (called by the installed hook scripts)",
+		Hidden: true,
+	}
+
+	prepareCmd := &cobra.Command{
+		Use:   hookPrepareCommitMsg + " <message-file> [source] [commit]",
+		Short: "Write a commit message into git's message file",
+		Args:  cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := ""
+			if len(args) > 1 {
+				source = args[1]
+			}
+			return runHook(cmd, func(ctx context.Context, g genie.Genie, dir string) error {
+				return runPrepareCommitMsgHook(ctx, g, dir, args[0], source)
+			})
+		},
+	}
+
+	preCommitCmd := &cobra.Command{
+		Use:   hookPreCommit,
+		Short: "Review the staged changes and reject the commit on findings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			failOn, _ := cmd.Flags().GetString("fail-on")
+			return runHook(cmd, func(ctx context.Context, g genie.Genie, dir string) error {
+				return runPreCommitHook(ctx, g, dir, failOn, cmd.ErrOrStderr())
+			})
+		},
+	}
+	preCommitCmd.Flags().String("fail-on", severityError, "Severity that rejects the commit")
+
+	cmd.PersistentFlags().Duration("timeout", 0, "Give up and let git continue after this long (default: GENIE_HOOK_TIMEOUT or 30s)")
+	cmd.AddCommand(prepareCmd, preCommitCmd)
+	return cmd
+}
+
+// errHookRejected carries a hook's decision to stop the commit, as opposed
+// to Genie failing, which never blocks git.
+type errHookRejected struct{ reason string }
+
+func (e *errHookRejected) Error() string { return e.reason }
+
+// runHook starts Genie and runs fn within the hook timeout. Timeouts and
+// failures are reported as warnings so the commit goes ahead; only a
+// rejection from fn fails the hook.
+func runHook(cmd *cobra.Command, fn func(ctx context.Context, g genie.Genie, dir string) error) error {
+	if os.Getenv(HookSkipEnv) == "1" {
+		return nil
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		timeout = hookTimeout()
+	}
+
+	err := withHookTimeout(timeout, func(ctx context.Context) error {
+		if err := RootCmd.PersistentPreRunE(cmd, nil); err != nil {
+			return err
+		}
+		return fn(ctx, genieInstance, initialSession.GetWorkingDirectory())
+	})
+
+	var rejected *errHookRejected
+	if errors.As(err, &rejected) {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "genie %s: skipped (%v)\n", cmd.Name(), err)
+	}
+	return nil
+}
+
+// hookTimeout returns the configured hook timeout.
+func hookTimeout() time.Duration {
+	value := config.NewConfigManager().GetStringWithDefault(HookTimeoutConfigKey, "")
+	if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultHookTimeout
+}
+
+// withHookTimeout runs fn and stops waiting for it after timeout, even when
+// fn does not honor its context, so a stuck model or MCP server cannot hang
+// git.
+func withHookTimeout(timeout time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w after %s", errHookTimeout, timeout)
+	}
+}
+
+// runPrepareCommitMsgHook writes a generated message at the top of git's
+// message file, above the comments git put there. Commits whose message
+// came from -m, -F, -c, a merge or a squash are left alone.
+func runPrepareCommitMsgHook(ctx context.Context, g genie.Genie, dir, messageFile, source string) error {
+	if source != "" && source != "template" {
+		return nil
+	}
+	if !filepath.IsAbs(messageFile) {
+		messageFile = filepath.Join(dir, messageFile)
+	}
+	existing, err := os.ReadFile(messageFile)
+	if err != nil {
+		return err
+	}
+	if hasCommitMessage(string(existing)) {
+		return nil
+	}
+
+	changes, err := gatherCommitChanges(ctx, dir, false, false)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(changes.Diff) == "" {
+		return nil
+	}
+	message, err := generateCommitMessage(ctx, g, "", changes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(messageFile, []byte(message+"\n"+string(existing)), 0o644)
+}
+
+// hasCommitMessage reports whether git's message file already holds text
+// other than comments, such as a template the user filled in.
+func hasCommitMessage(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return true
+		}
+	}
+	return false
+}
+
+// runPreCommitHook reviews the staged changes and rejects the commit when a
+// finding is at least as severe as failOn.
+func runPreCommitHook(ctx context.Context, g genie.Genie, dir, failOn string, out io.Writer) error {
+	diff, err := runGit(ctx, dir, "", "diff", "--cached")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return nil
+	}
+	if persona == "" {
+		useReviewPersona(ctx, g, initialSession)
+	}
+
+	report, err := reviewChanges(ctx, g, "the staged changes", diff, 1, io.Discard)
+	if err != nil {
+		return err
+	}
+	count := countAtLeast(report.Findings, failOn)
+	if count == 0 {
+		return nil
+	}
+	if err := writeMarkdownReport(out, report); err != nil {
+		return err
+	}
+	return &errHookRejected{reason: fmt.Sprintf("commit rejected: review found %d finding(s) of severity %s or higher (bypass with %s=1 or --no-verify)", count, failOn, HookSkipEnv)}
+}
+
+// hookRepoDir returns the repository the hook commands act on.
+func hookRepoDir() (string, error) {
+	if workingDir != "" {
+		return workingDir, nil
+	}
+	return os.Getwd()
+}
+
+// gitHooksDir returns the hooks directory of the repository at dir,
+// honoring core.hooksPath.
+func gitHooksDir(ctx context.Context, dir string) (string, error) {
+	out, err := runGit(ctx, dir, "", "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %s", dir)
+	}
+	hooksDir := strings.TrimSpace(out)
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(dir, hooksDir)
+	}
+	return hooksDir, nil
+}
+
+// hookScripts returns the scripts to install, keyed by hook name. A zero
+// timeout leaves it to GENIE_HOOK_TIMEOUT.
+func hookScripts(lint bool, timeout time.Duration, failOn string) map[string]string {
+	flags := ""
+	if timeout > 0 {
+		flags = " --timeout " + timeout.String()
+	}
+	scripts := map[string]string{
+		hookPrepareCommitMsg: hookScript(fmt.Sprintf(`genie -q hook run %s%s "$@" </dev/null || true`, hookPrepareCommitMsg, flags)),
+	}
+	if lint {
+		scripts[hookPreCommit] = hookScript(fmt.Sprintf(`exec genie -q hook run %s%s --fail-on %s </dev/null`, hookPreCommit, flags, failOn))
+	}
+	return scripts
+}
+
+func hookScript(command string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+[ "$%s" = "1" ] && exit 0
+command -v genie >/dev/null 2>&1 || exit 0
+%s
+`, hookMarker, HookSkipEnv, command)
+}
+
+// installHooks writes scripts into hooksDir, refusing to replace hooks that
+// Genie did not install unless force is set. It returns the hooks written.
+func installHooks(hooksDir string, scripts map[string]string, force bool) ([]string, error) {
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	var installed []string
+	for _, name := range []string{hookPrepareCommitMsg, hookPreCommit} {
+		script, ok := scripts[name]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(hooksDir, name)
+		if existing, err := os.ReadFile(path); err == nil && !force && !strings.Contains(string(existing), hookMarker) {
+			return installed, fmt.Errorf("%s already has a %s hook; use --force to replace it, or call genie hook run %s from it", hooksDir, name, name)
+		}
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			return installed, fmt.Errorf("failed to write %s hook: %w", name, err)
+		}
+		if err := os.Chmod(path, 0o755); err != nil {
+			return installed, err
+		}
+		installed = append(installed, name)
+	}
+	return installed, nil
+}
+
+// uninstallHooks removes the hooks Genie installed from hooksDir and
+// returns their names.
+func uninstallHooks(hooksDir string) ([]string, error) {
+	var removed []string
+	for _, name := range []string{hookPrepareCommitMsg, hookPreCommit} {
+		path := filepath.Join(hooksDir, name)
+		existing, err := os.ReadFile(path)
+		if err != nil || !strings.Contains(string(existing), hookMarker) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s hook: %w", name, err)
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+func init() {
+	RootCmd.AddCommand(newHookCommand())
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallAndUninstallHooks(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	hooksDir, err := gitHooksDir(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git", "hooks"), hooksDir)
+
+	installed, err := installHooks(hooksDir, hookScripts(true, 45*time.Second, severityWarning), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{hookPrepareCommitMsg, hookPreCommit}, installed)
+
+	script, err := os.ReadFile(filepath.Join(hooksDir, hookPreCommit))
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "genie -q hook run pre-commit --timeout 45s --fail-on warning")
+	info, err := os.Stat(filepath.Join(hooksDir, hookPrepareCommitMsg))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode().Perm()&0o100, "hooks must be executable")
+
+	// Reinstalling replaces Genie's own hooks
+	_, err = installHooks(hooksDir, hookScripts(false, 0, severityError), false)
+	require.NoError(t, err)
+	script, err = os.ReadFile(filepath.Join(hooksDir, hookPrepareCommitMsg))
+	require.NoError(t, err)
+	assert.NotContains(t, string(script), "--timeout", "GENIE_HOOK_TIMEOUT applies without an explicit timeout")
+
+	removed, err := uninstallHooks(hooksDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{hookPrepareCommitMsg, hookPreCommit}, removed)
+	assert.NoFileExists(t, filepath.Join(hooksDir, hookPrepareCommitMsg))
+}
+
+func TestInstallHooksKeepsForeignHooks(t *testing.T) {
+	hooksDir := t.TempDir()
+	foreign := filepath.Join(hooksDir, hookPrepareCommitMsg)
+	require.NoError(t, os.WriteFile(foreign, []byte("#!/bin/sh\nlefthook run prepare-commit-msg\n"), 0o755))
+
+	_, err := installHooks(hooksDir, hookScripts(false, time.Minute, severityError), false)
+	assert.ErrorContains(t, err, "--force")
+
+	removed, err := uninstallHooks(hooksDir)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.FileExists(t, foreign)
+
+	_, err = installHooks(hooksDir, hookScripts(false, time.Minute, severityError), true)
+	require.NoError(t, err)
+}
+
+func TestPrepareCommitMsgHookWritesMessage(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	dir := session.GetWorkingDirectory()
+	initGitRepo(t, dir)
+	writeAndStage(t, dir, "greeting.txt", "hello\n")
+
+	changes, err := gatherCommitChanges(context.Background(), dir, false, false)
+	require.NoError(t, err)
+	prompt, err := renderCommitPrompt("", changes)
+	require.NoError(t, err)
+	fixture.ExpectSimpleMessage(prompt, "feat: add greeting")
+
+	messageFile := filepath.Join(dir, ".git", "COMMIT_EDITMSG")
+	comments := "\n# Please enter the commit message for your changes.\n"
+	require.NoError(t, os.WriteFile(messageFile, []byte(comments), 0o644))
+
+	// A message given with -m is kept
+	require.NoError(t, runPrepareCommitMsgHook(context.Background(), fixture.Genie, dir, messageFile, "message"))
+	data, err := os.ReadFile(messageFile)
+	require.NoError(t, err)
+	assert.Equal(t, comments, string(data))
+
+	require.NoError(t, runPrepareCommitMsgHook(context.Background(), fixture.Genie, dir, messageFile, ""))
+	data, err = os.ReadFile(messageFile)
+	require.NoError(t, err)
+	assert.Equal(t, "feat: add greeting\n"+comments, string(data))
+}
+
+func TestWithHookTimeoutStopsWaiting(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	err := withHookTimeout(10*time.Millisecond, func(ctx context.Context) error {
+		<-release // ignores its context, like a stuck subprocess
+		return nil
+	})
+	assert.True(t, errors.Is(err, errHookTimeout))
+}
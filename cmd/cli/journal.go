@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/journal"
+)
+
+// journalTimeout bounds asking the model for the digest when Genie exits
+const journalTimeout = time.Minute
+
+// writeJournal appends a digest of the session to the day's journal when
+// GENIE_JOURNAL is on, saying where on out. Sessions that neither chatted
+// nor ran tools are not journaled. When the model cannot write the digest,
+// the session's activity is journaled as a list.
+func writeJournal(ctx context.Context, cfg config.Manager, g genie.Genie, session genie.Session, out io.Writer) {
+	if g == nil || session == nil || !cfg.GetBoolWithDefault(journal.ConfigKey, false) {
+		return
+	}
+	entries, err := audit.Read(audit.Path(session.GetGenieHomeDirectory(), session.GetID()))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(out, "Journal: failed to read the audit log: %v\n", err)
+	}
+	activity := journal.FromAudit(entries)
+	turns, _ := g.GetChatHistory()
+	if len(turns) == 0 && activity.Empty() {
+		return
+	}
+	dir, err := journal.Dir(cfg)
+	if err != nil {
+		fmt.Fprintf(out, "Journal: %v\n", err)
+		return
+	}
+
+	fmt.Fprintln(out, "Writing the session to your journal...")
+	ctx, cancel := context.WithTimeout(ctx, journalTimeout)
+	defer cancel()
+	digest, err := chatAndWait(ctx, g, journal.Prompt(activity), genie.WithEphemeral(genie.EphemeralAll))
+	if err != nil || digest == "" {
+		digest = activity.String()
+	}
+
+	path, err := journal.Append(dir, filepath.Base(session.GetWorkingDirectory()), digest, time.Now())
+	if err != nil {
+		fmt.Fprintf(out, "Journal: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "Journal: %s\n", path)
+}
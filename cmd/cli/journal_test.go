@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/kcaldas/genie/pkg/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJournal(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(journal.ConfigKey, "true")
+	t.Setenv(journal.DirConfigKey, dir)
+
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession(genie.WithChatHistory(genie.ChatHistoryTurn{User: "fix the tests", Assistant: "fixed"}))
+	log := audit.NewLog(audit.Path(session.GetGenieHomeDirectory(), session.GetID()))
+	require.NoError(t, log.Append(audit.Entry{Tool: "editFile", Params: map[string]any{"path": "parser.go"}, Status: audit.StatusOK}))
+	activity := journal.FromAudit([]audit.Entry{{Tool: "editFile", Params: map[string]any{"path": "parser.go"}, Status: audit.StatusOK}})
+	fixture.ExpectSimpleMessage(journal.Prompt(activity), "- Fixed the failing parser tests")
+
+	var out bytes.Buffer
+	writeJournal(context.Background(), config.NewConfigManager(), fixture.Genie, session, &out)
+
+	data, err := os.ReadFile(journal.Path(dir, time.Now()))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "- Fixed the failing parser tests")
+	assert.Contains(t, out.String(), "Journal: "+journal.Path(dir, time.Now()))
+
+	turns, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Len(t, turns, 1, "the digest is not part of the conversation")
+}
+
+func TestWriteJournalIsOptIn(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(journal.DirConfigKey, dir)
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession(genie.WithChatHistory(genie.ChatHistoryTurn{User: "hi", Assistant: "hello"}))
+
+	var out bytes.Buffer
+	writeJournal(context.Background(), config.NewConfigManager(), fixture.Genie, session, &out)
+	assert.Empty(t, out.String())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/memory"
+	"github.com/spf13/cobra"
+)
+
+// newMemoryCommand creates the memory command, which manages the facts
+// Genie remembers across sessions. It reads files only, so it skips
+// starting Genie.
+func newMemoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "memory",
+		Short: "Manage the facts Genie remembers across sessions",
+		Long: `When you ask Genie to remember something, the fact is stored in
+~/.genie/memories.json, either for the current project or for every project.
+Before each message, the facts related to it are recalled into Genie's context.
+
+Examples:
+  genie memory list          # Memories that apply in this project
+  genie memory list --all    # Memories of every project
+  genie memory forget 1a2b   # Delete a memory by ID or ID prefix`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List remembered facts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := memory.Open(config.NewConfigManager())
+			if err != nil {
+				return err
+			}
+			workspace := ""
+			if all, _ := cmd.Flags().GetBool("all"); !all {
+				if workspace, err = auditHome(); err != nil {
+					return err
+				}
+			}
+			return runMemoryList(cmd.OutOrStdout(), store, workspace)
+		},
+	}
+	listCmd.Flags().Bool("all", false, "List the memories of every project, not only this one")
+
+	forgetCmd := &cobra.Command{
+		Use:   "forget <id>",
+		Short: "Delete a remembered fact",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := memory.Open(config.NewConfigManager())
+			if err != nil {
+				return err
+			}
+			return runMemoryForget(cmd.OutOrStdout(), store, args[0])
+		},
+	}
+
+	cmd.AddCommand(listCmd, forgetCmd)
+	return cmd
+}
+
+// runMemoryList prints the memories that apply in workspace, or every
+// memory when workspace is empty.
+func runMemoryList(out io.Writer, store *memory.Store, workspace string) error {
+	memories, err := store.List(workspace)
+	if err != nil {
+		return err
+	}
+	if len(memories) == 0 {
+		fmt.Fprintln(out, "No memories yet. Ask Genie to remember something.")
+		return nil
+	}
+	for _, m := range memories {
+		scope := m.Scope
+		if workspace != "" && scope != memory.GlobalScope {
+			scope = "project"
+		}
+		fmt.Fprintf(out, "%s  %s  [%s]  %s\n", m.ID, m.CreatedAt.Local().Format("2006-01-02"), scope, oneLine(m.Text))
+	}
+	return nil
+}
+
+func runMemoryForget(out io.Writer, store *memory.Store, id string) error {
+	forgotten, err := store.Forget(id)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Forgot %s: %s\n", forgotten.ID, oneLine(forgotten.Text))
+	return nil
+}
+
+// oneLine keeps a multi-line fact on its listing's line
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func init() {
+	RootCmd.AddCommand(newMemoryCommand())
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMemoryListAndForget(t *testing.T) {
+	store := memory.NewStore(filepath.Join(t.TempDir(), memory.FileName), memory.LocalEmbedder{})
+	workspace := t.TempDir()
+	other := t.TempDir()
+	ctx := context.Background()
+	port, err := store.Add(ctx, "The staging database\nlistens on port 5433", workspace)
+	require.NoError(t, err)
+	_, err = store.Add(ctx, "Use pnpm, not npm", memory.GlobalScope)
+	require.NoError(t, err)
+	_, err = store.Add(ctx, "Deploys need a VPN", other)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, runMemoryList(&out, store, workspace))
+	assert.Contains(t, out.String(), port.ID+"  ")
+	assert.Contains(t, out.String(), "[project]  The staging database listens on port 5433")
+	assert.Contains(t, out.String(), "[global]  Use pnpm, not npm")
+	assert.NotContains(t, out.String(), "VPN")
+
+	out.Reset()
+	require.NoError(t, runMemoryList(&out, store, ""))
+	assert.Contains(t, out.String(), "["+other+"]  Deploys need a VPN")
+
+	out.Reset()
+	require.NoError(t, runMemoryForget(&out, store, port.ID[:5]))
+	assert.Contains(t, out.String(), "Forgot "+port.ID)
+	assert.Error(t, runMemoryForget(&out, store, port.ID))
+}
+
+func TestRunMemoryList_Empty(t *testing.T) {
+	store := memory.NewStore(filepath.Join(t.TempDir(), memory.FileName), memory.LocalEmbedder{})
+	var out bytes.Buffer
+	require.NoError(t, runMemoryList(&out, store, ""))
+	assert.Contains(t, out.String(), "No memories yet")
+}
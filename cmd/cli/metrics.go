@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/metrics"
+)
+
+// serveMetrics exposes turn metrics for Prometheus at addr/metrics until
+// the returned function is called. The address is bound up front so a
+// port clash fails the command instead of surfacing later in the log.
+func serveMetrics(addr string, bus events.Subscriber) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serve metrics on %s: %w", addr, err)
+	}
+
+	collector := metrics.NewCollector()
+	detach := collector.Attach(bus)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", collector.Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Metrics server stopped", "addr", addr, "error", err)
+		}
+	}()
+
+	return func() {
+		_ = server.Close()
+		detach()
+	}, nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeMetrics(t *testing.T) {
+	// Find a free port
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := probe.Addr().String()
+	require.NoError(t, probe.Close())
+
+	bus := events.NewEventBus()
+	stop, err := serveMetrics(addr, bus)
+	require.NoError(t, err)
+	defer stop()
+
+	_, err = serveMetrics(addr, bus)
+	assert.ErrorContains(t, err, "failed to serve metrics", "a taken port fails up front")
+
+	bus.PublishSync(events.ChatStartedEvent{}.Topic(), events.ChatStartedEvent{RequestID: "r1"})
+	bus.PublishSync(events.ChatResponseEvent{}.Topic(), events.ChatResponseEvent{RequestID: "r1"})
+
+	resp, err := http.Get("http://" + addr + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "genie_turns_total 1\n")
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/sessiontemplates"
+	"github.com/kcaldas/genie/cmd/tui"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// contextCommandTimeout bounds each command a template captures.
+	contextCommandTimeout = 5 * time.Minute
+	// maxContextOutputBytes caps each captured output pinned to the session.
+	maxContextOutputBytes = 30 * 1024
+)
+
+// NewNewCommandWithGenie creates the new command, which starts a session from
+// a workflow template: its opening prompt, pinned context and persona
+func NewNewCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	var selected sessiontemplates.Template
+
+	cmd := &cobra.Command{
+		Use:   "new <template> [description...]",
+		Short: "Start a session from a workflow template such as bugfix or feature",
+		Long: `Start a session pre-seeded for a common workflow: the template's opening
+prompt is sent as the first message, its files and captured command output
+are pinned, and its recommended persona is used unless --persona is given.
+
+Templates are YAML files in .genie/templates (project) or ~/.genie/templates
+(user), overriding the built-in bugfix, feature and review templates of the
+same name. The description after the template name replaces {{args}} in the
+prompt.
+
+Examples:
+  genie new --list                                # Show the available templates
+  genie new bugfix "login fails with an empty password"
+  genie new feature "export reports as CSV"
+  genie new review --persona engineer             # Override the template's persona`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if list, _ := cmd.Flags().GetBool("list"); list {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
+		// Listing needs no backend; starting one applies the template's
+		// persona before Genie starts
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if list, _ := cmd.Flags().GetBool("list"); list {
+				return nil
+			}
+			manager, err := discoverSessionTemplates()
+			if err != nil {
+				return err
+			}
+			template, ok := manager.GetTemplate(args[0])
+			if !ok {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("no template named %q; run 'genie new --list' to see the available templates", args[0])
+			}
+			selected = template
+			if persona == "" {
+				persona = template.Persona
+			}
+			return RootCmd.PersistentPreRunE(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list, _ := cmd.Flags().GetBool("list"); list {
+				manager, err := discoverSessionTemplates()
+				if err != nil {
+					return err
+				}
+				return printSessionTemplates(cmd.OutOrStdout(), manager)
+			}
+
+			g, session := genieProvider()
+			cmd.SilenceUsage = true
+			message, err := seedSession(cmd.Context(), g, session.GetWorkingDirectory(), selected, strings.Join(args[1:], " "), cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			// Without a terminal to draw on, answer the opening prompt as
+			// plain text, like genie ask
+			if !isTerminalWriter(cmd.OutOrStdout()) {
+				return runAskCommandWithSession(cmd, []string{message}, g, session, g.GetEventBus())
+			}
+
+			if accessible {
+				os.Setenv(types.AccessibleEnv, "true")
+			}
+			tuiApp, err := tui.InjectTUI(session)
+			if err != nil {
+				return err
+			}
+			defer tuiApp.Stop()
+			err = tuiApp.StartWithMessage(message)
+			tuiApp.Stop()
+			writeJournal(cmd.Context(), config.NewConfigManager(), g, session, os.Stderr)
+			return err
+		},
+	}
+
+	cmd.Flags().BoolP("list", "l", false, "List the available templates")
+
+	return cmd
+}
+
+// discoverSessionTemplates loads the templates for the working directory,
+// leaving out the project's own when the workspace is not trusted.
+func discoverSessionTemplates() (*sessiontemplates.Manager, error) {
+	projectRoot := workingDir
+	if projectRoot == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		projectRoot = cwd
+	}
+
+	manager := sessiontemplates.NewManager()
+	if !decideWorkspaceTrust() {
+		manager.DisableProjectSources()
+	}
+	if err := manager.DiscoverTemplates(projectRoot, os.UserHomeDir); err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
+
+func printSessionTemplates(out io.Writer, manager *sessiontemplates.Manager) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPERSONA\tSOURCE\tDESCRIPTION")
+	for _, name := range manager.GetTemplateNames() {
+		template, _ := manager.GetTemplate(name)
+		persona := template.Persona
+		if persona == "" {
+			persona = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, persona, template.Source, template.Description)
+	}
+	return w.Flush()
+}
+
+// seedSession pins the template's files and the output of its context
+// commands, and returns the opening prompt. Captured output is saved under
+// .genie/context so it stays pinned, and refreshed, like any other file.
+func seedSession(ctx context.Context, g genie.Genie, dir string, template sessiontemplates.Template, args string, status io.Writer) (string, error) {
+	patterns := append([]string{}, template.Pin...)
+
+	if len(template.Context) > 0 {
+		contextDir := filepath.Join(dir, ".genie", "context")
+		if err := os.MkdirAll(contextDir, 0o755); err != nil {
+			return "", fmt.Errorf("failed to create context directory: %w", err)
+		}
+		for _, command := range template.Context {
+			fmt.Fprintf(status, "Running %s...\n", command.Run)
+			output, _, err := runTestCommand(ctx, dir, command.Run, contextCommandTimeout)
+			if err != nil {
+				return "", fmt.Errorf("context %s: %w", command.Name, err)
+			}
+			content := fmt.Sprintf("$ %s\n%s", command.Run, truncateForPrompt(output, maxContextOutputBytes))
+			path := filepath.Join(contextDir, command.Name+".txt")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return "", fmt.Errorf("failed to save context %s: %w", command.Name, err)
+			}
+			patterns = append(patterns, filepath.Join(".genie", "context", command.Name+".txt"))
+		}
+	}
+
+	if len(patterns) > 0 {
+		pinned, err := g.PinFiles(ctx, patterns...)
+		if err != nil {
+			return "", fmt.Errorf("failed to pin template context: %w", err)
+		}
+		if len(pinned) > 0 {
+			fmt.Fprintf(status, "Pinned %s\n", strings.Join(pinned, ", "))
+		}
+	}
+
+	return template.Render(args), nil
+}
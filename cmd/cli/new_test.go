@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/sessiontemplates"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedSessionPinsFilesAndCapturedOutput(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	dir := session.GetWorkingDirectory()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example\n"), 0o644))
+
+	template, err := sessiontemplates.Parse("bugfix", []byte(`
+prompt: "Fix this bug: {{args}}"
+pin: [go.mod]
+context:
+  - name: failing-tests
+    run: echo FAIL TestLogin
+`))
+	require.NoError(t, err)
+
+	var status bytes.Buffer
+	message, err := seedSession(context.Background(), fixture.Genie, dir, template, "login fails", &status)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix this bug: login fails", message)
+
+	captured, err := os.ReadFile(filepath.Join(dir, ".genie", "context", "failing-tests.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "$ echo FAIL TestLogin\nFAIL TestLogin\n", string(captured))
+
+	pinned, err := fixture.Genie.GetPinnedFiles()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"go.mod", filepath.Join(".genie", "context", "failing-tests.txt")}, pinned)
+	assert.Contains(t, status.String(), "Pinned")
+}
+
+func TestPrintSessionTemplates(t *testing.T) {
+	manager := sessiontemplates.NewManager()
+	require.NoError(t, manager.DiscoverTemplates(t.TempDir(), func() (string, error) { return t.TempDir(), nil }))
+
+	var out bytes.Buffer
+	require.NoError(t, printSessionTemplates(&out, manager))
+	assert.Contains(t, out.String(), "NAME")
+	assert.Regexp(t, `bugfix\s+engineer\s+built-in`, out.String())
+	assert.Regexp(t, `review\s+reviewer\s+built-in`, out.String())
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// Values of the --color flag
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// isTerminalWriter reports whether w writes to a terminal
+func isTerminalWriter(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && isatty.IsTerminal(f.Fd())
+}
+
+// useColor decides whether output to w may carry ANSI escape codes. In
+// auto mode that is only when w is a terminal and NO_COLOR is unset.
+func useColor(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto, "":
+		return os.Getenv("NO_COLOR") == "" && isTerminalWriter(w), nil
+	default:
+		return false, fmt.Errorf("invalid --color value %q: use auto, always or never", mode)
+	}
+}
+
+// outputWriter returns where a command prints its answer: stdout, with
+// escape codes removed unless --color allows them
+func outputWriter(cmd *cobra.Command) (io.Writer, error) {
+	out := cmd.OutOrStdout()
+	color, err := useColor(colorMode, out)
+	if err != nil {
+		return nil, err
+	}
+	if color {
+		return out, nil
+	}
+	return &plainWriter{w: out}, nil
+}
+
+// plainWriter removes ANSI escape sequences from everything written
+// through it. It keeps its parser state between writes, so a sequence
+// split across streamed chunks is removed too.
+type plainWriter struct {
+	w     io.Writer
+	state int
+}
+
+// plainWriter parser states
+const (
+	plainText   = iota
+	plainEscape // After ESC
+	plainCSI    // Inside ESC [ ... final byte
+	plainOSC    // Inside ESC ] ... BEL or ESC \
+	plainOSCEsc // ESC inside an OSC, usually the start of its terminator
+)
+
+func (p *plainWriter) Write(b []byte) (int, error) {
+	text := make([]byte, 0, len(b))
+	for _, c := range b {
+		switch p.state {
+		case plainText:
+			if c == 0x1b {
+				p.state = plainEscape
+			} else {
+				text = append(text, c)
+			}
+		case plainEscape:
+			switch c {
+			case '[':
+				p.state = plainCSI
+			case ']':
+				p.state = plainOSC
+			default:
+				p.state = plainText
+			}
+		case plainCSI:
+			if c >= 0x40 && c <= 0x7e {
+				p.state = plainText
+			}
+		case plainOSC:
+			switch c {
+			case 0x07:
+				p.state = plainText
+			case 0x1b:
+				p.state = plainOSCEsc
+			}
+		case plainOSCEsc:
+			p.state = plainText
+			if c != '\\' {
+				p.state = plainOSC
+			}
+		}
+	}
+	if _, err := p.w.Write(text); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
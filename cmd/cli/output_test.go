@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlainWriterStripsEscapeCodes(t *testing.T) {
+	var buf bytes.Buffer
+	w := &plainWriter{w: &buf}
+
+	// Color split across chunks, as a streamed answer may arrive
+	for _, chunk := range []string{"\x1b[1;3", "1mred\x1b", "[0m and ", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x07", "\n"} {
+		n, err := fmt.Fprint(w, chunk)
+		require.NoError(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+	assert.Equal(t, "red and link\n", buf.String())
+}
+
+func TestUseColor(t *testing.T) {
+	var buf bytes.Buffer
+
+	color, err := useColor(colorAlways, &buf)
+	require.NoError(t, err)
+	assert.True(t, color)
+
+	color, err = useColor(colorAuto, &buf)
+	require.NoError(t, err)
+	assert.False(t, color, "auto mode keeps color for terminals only")
+
+	color, err = useColor(colorNever, &buf)
+	require.NoError(t, err)
+	assert.False(t, color)
+
+	_, err = useColor("sometimes", &buf)
+	assert.Error(t, err)
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	personas "github.com/kcaldas/genie/pkg/persona"
+	"github.com/spf13/cobra"
+)
+
+// NewPersonaCommandWithGenie creates the persona command and its
+// subcommands.
+func NewPersonaCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "persona",
+		Short: "Create and manage personas",
+	}
+	cmd.AddCommand(newPersonaNewCommand(genieProvider))
+	return cmd
+}
+
+func newPersonaNewCommand(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "new [id]",
+		Short: "Create a persona with a guided wizard",
+		Long: `Create a persona step by step: its ID, name and purpose, then the tools it
+may use. Tools are suggested from the purpose and the registered catalog;
+type a prefix to complete a tool name, @ for tool sets, or ? to list them
+all. The generated prompt.yaml is validated before it is written.
+
+Personas are written to ~/.genie/personas/<id>/prompt.yaml, or to the
+project's .genie/personas with --project. Flags answer the matching
+questions up front, so the wizard can also run unattended.
+
+Examples:
+  genie persona new
+  genie persona new sql_helper --purpose "write and explain SQL queries"
+  genie persona new reviewer2 --tools @essentials,readFile,gitDiff --project --yes`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, session := genieProvider()
+			return runPersonaNew(cmd, g, session, args)
+		},
+	}
+
+	cmd.Flags().String("name", "", "Display name (default: the ID in title case)")
+	cmd.Flags().String("purpose", "", "What the persona is for, in a sentence")
+	cmd.Flags().StringSlice("tools", nil, "Required tools, comma-separated; @name adds a tool set")
+	cmd.Flags().Bool("project", false, "Write to the project's .genie/personas instead of ~/.genie/personas")
+	cmd.Flags().Bool("force", false, "Replace a persona with the same ID")
+	cmd.Flags().BoolP("yes", "y", false, "Write without asking for confirmation")
+
+	catalog := func() []string {
+		g, _ := genieProvider()
+		if g == nil {
+			return nil
+		}
+		registry, err := g.GetToolsRegistry()
+		if err != nil {
+			return nil
+		}
+		return personas.ToolCatalog(registry)
+	}
+	_ = cmd.RegisterFlagCompletionFunc("tools", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Complete the last entry of a comma-separated list
+		done, last := "", toComplete
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			done, last = toComplete[:i+1], toComplete[i+1:]
+		}
+		var completions []string
+		for _, match := range personas.CompleteTool(last, catalog()) {
+			completions = append(completions, done+match)
+		}
+		return completions, cobra.ShellCompDirectiveNoSpace
+	})
+
+	return cmd
+}
+
+func runPersonaNew(cmd *cobra.Command, g genie.Genie, session genie.Session, args []string) error {
+	registry, err := g.GetToolsRegistry()
+	if err != nil {
+		return err
+	}
+	wizard := &personaWizard{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.ErrOrStderr(), catalog: personas.ToolCatalog(registry)}
+
+	draft := personas.Draft{}
+	draft.Name, _ = cmd.Flags().GetString("name")
+	draft.Purpose, _ = cmd.Flags().GetString("purpose")
+	draft.Tools, _ = cmd.Flags().GetStringSlice("tools")
+	project, _ := cmd.Flags().GetBool("project")
+	force, _ := cmd.Flags().GetBool("force")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	if len(args) > 0 {
+		draft.ID = args[0]
+		if err := personas.ValidateID(draft.ID); err != nil {
+			return err
+		}
+	} else if draft.ID, err = wizard.askID(); err != nil {
+		return err
+	}
+
+	if !cmd.Flags().Changed("name") {
+		if draft.Name, err = wizard.ask(fmt.Sprintf("Name [%s]: ", personas.TitleFromID(draft.ID))); err != nil {
+			return err
+		}
+	}
+	for strings.TrimSpace(draft.Purpose) == "" {
+		if draft.Purpose, err = wizard.ask("What is it for? "); err != nil {
+			return err
+		}
+	}
+	if len(draft.Tools) == 0 {
+		if draft.Tools, err = wizard.askTools(personas.SuggestTools(draft.Purpose, registry)); err != nil {
+			return err
+		}
+	} else if draft.Tools, err = wizard.resolveTools(draft.Tools); err != nil {
+		return err
+	}
+
+	data, err := draft.YAML()
+	if err != nil {
+		return err
+	}
+	if err := personas.ValidatePrompt(data, registry); err != nil {
+		return fmt.Errorf("the generated persona is not valid:\n%w", err)
+	}
+
+	dir, err := personaDir(session, project)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, draft.ID, "prompt.yaml")
+	if !yes {
+		fmt.Fprintf(wizard.out, "\n%s\n", data)
+		answer, err := wizard.ask(fmt.Sprintf("Write %s? [Y/n] ", path))
+		if err != nil {
+			return err
+		}
+		if answer = strings.ToLower(answer); answer != "" && answer != "y" && answer != "yes" {
+			return errors.New("persona not written")
+		}
+	}
+	if path, err = personas.WriteDraft(dir, draft.ID, data, force); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Created %s\nUse it with: genie --persona %s\n", path, draft.ID)
+	return nil
+}
+
+// personaDir is where new personas go: the user's or the project's
+func personaDir(session genie.Session, project bool) (string, error) {
+	if project {
+		return filepath.Join(session.GetWorkingDirectory(), ".genie", "personas"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".genie", "personas"), nil
+}
+
+// personaWizard asks the questions of genie persona new, one line each
+type personaWizard struct {
+	in      *bufio.Reader
+	out     io.Writer
+	catalog []string
+}
+
+// ask prints question and returns the trimmed answer. Input that ends
+// mid-wizard is an error rather than an endless loop.
+func (w *personaWizard) ask(question string) (string, error) {
+	fmt.Fprint(w.out, question)
+	answer, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", errors.New("persona creation cancelled: no more input")
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+func (w *personaWizard) askID() (string, error) {
+	for {
+		id, err := w.ask("Persona ID (e.g. sql_helper): ")
+		if err != nil {
+			return "", err
+		}
+		if err := personas.ValidateID(id); err != nil {
+			fmt.Fprintln(w.out, err)
+			continue
+		}
+		return id, nil
+	}
+}
+
+// askTools offers the suggested tools and lets the user accept or replace
+// them, completing partial names against the catalog
+func (w *personaWizard) askTools(suggested []string) ([]string, error) {
+	fmt.Fprintf(w.out, "Suggested tools: %s\n", strings.Join(suggested, ", "))
+	for {
+		answer, err := w.ask("Tools (comma-separated; Enter accepts the suggestion, ? lists all): ")
+		if err != nil {
+			return nil, err
+		}
+		switch answer {
+		case "":
+			return suggested, nil
+		case "?":
+			fmt.Fprintf(w.out, "Available: %s\n", strings.Join(w.catalog, ", "))
+			continue
+		}
+		tools, err := w.resolveTools(strings.Split(answer, ","))
+		if err != nil {
+			fmt.Fprintln(w.out, err)
+			continue
+		}
+		return tools, nil
+	}
+}
+
+// resolveTools completes each entry to the one catalog entry it names
+func (w *personaWizard) resolveTools(entries []string) ([]string, error) {
+	var resolved []string
+	var problems []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		switch matches := personas.CompleteTool(entry, w.catalog); len(matches) {
+		case 0:
+			problems = append(problems, fmt.Sprintf("unknown tool %s", entry))
+		case 1:
+			resolved = append(resolved, matches[0])
+		default:
+			problems = append(problems, fmt.Sprintf("%s could be %s", entry, strings.Join(matches, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	if len(resolved) == 0 {
+		return nil, errors.New("choose at least one tool")
+	}
+	return resolved, nil
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonaNewWizard(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+
+	cmd := NewPersonaCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var out, prompts bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&prompts)
+	// An invalid ID is asked again; "git" is ambiguous and "nope" unknown,
+	// so the tools are asked again too
+	cmd.SetIn(strings.NewReader(strings.Join([]string{
+		"Bad ID", "sql_helper",
+		"",
+		"write and explain SQL queries",
+		"?", "@ess, git, nope", "@ess, readF, gitDiff",
+		"y",
+	}, "\n") + "\n"))
+	cmd.SetArgs([]string{"new"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, prompts.String(), "must be lowercase")
+	assert.Contains(t, prompts.String(), "Available: @docker, @essentials, @github")
+	assert.Contains(t, prompts.String(), "git could be")
+	assert.Contains(t, prompts.String(), "unknown tool nope")
+
+	path := filepath.Join(home, ".genie", "personas", "sql_helper", "prompt.yaml")
+	assert.Contains(t, out.String(), "Created "+path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "name: Sql Helper")
+	assert.Contains(t, string(data), "- '@essentials'\n- readFile\n- gitDiff\n")
+	assert.Contains(t, string(data), "Your purpose: write and explain SQL queries.")
+}
+
+func TestPersonaNewFromFlags(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	newCmd := func(args ...string) error {
+		cmd := NewPersonaCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(strings.NewReader(""))
+		cmd.SetArgs(append([]string{"new"}, args...))
+		return cmd.Execute()
+	}
+
+	args := []string{"ops", "--name", "Ops", "--purpose", "run deployments", "--tools", "bash", "--project", "--yes"}
+	require.NoError(t, newCmd(args...))
+	path := filepath.Join(session.GetWorkingDirectory(), ".genie", "personas", "ops", "prompt.yaml")
+	assert.FileExists(t, path)
+
+	assert.ErrorContains(t, newCmd(args...), "already exists")
+	require.NoError(t, newCmd(append(args, "--force")...))
+
+	// Unanswered questions fail instead of waiting forever
+	assert.ErrorContains(t, newCmd("other", "--project"), "no more input")
+	assert.ErrorContains(t, newCmd("other", "--name", "x", "--purpose", "y", "--tools", "missing", "--yes"), "unknown tool missing")
+}
@@ -0,0 +1,456 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	personas "github.com/kcaldas/genie/pkg/persona"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/mattn/go-runewidth"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultPlaygroundWidth is the split view's width when neither --width
+// nor COLUMNS sets it
+const defaultPlaygroundWidth = 120
+
+const playgroundHelp = `Commands:
+  run, r               Run the test input against the current prompt
+  edit, e              Edit the prompt in $VISUAL or $EDITOR, then run it
+  input, i [text]      Show the test input, or replace it
+  show, s [n]          Show run n (default: the last) as prompt | output
+  diff, d [a [b]]      Compare runs a and b (default: the last two)
+  save                 Write the current prompt to the persona
+  help, ?              Show this help
+  quit, q              Leave the playground
+`
+
+// NewPlaygroundCommandWithGenie creates the playground command with access
+// to the initialized Genie instance
+func NewPlaygroundCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "playground",
+		Short: "Iterate on a persona's prompt against a fixed test input",
+		Long: `Load a persona's prompt.yaml into a draft, run a fixed test input against it,
+edit the draft and run it again, and compare the outputs of the revisions
+side by side. Runs don't touch the conversation history, and the persona is
+only changed when you save.
+
+The draft is a file you can also keep open in another editor or pane: every
+run reads it again. Saving writes a project or user persona in place; a
+built-in persona is saved as a user persona that overrides it.
+
+Examples:
+  genie playground --persona writer
+  genie playground --persona reviewer --input-file testdata/sample.diff`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, session := genieProvider()
+			return runPlayground(cmd, g, session)
+		},
+	}
+
+	cmd.Flags().String("input", "", "Test input to run against each revision")
+	cmd.Flags().String("input-file", "", "Read the test input from a file")
+	cmd.Flags().Int("width", 0, "Width of the split view (default: $COLUMNS, or 120)")
+
+	return cmd
+}
+
+// playgroundRun is one run of the test input against a prompt revision
+type playgroundRun struct {
+	revision int
+	prompt   []byte
+	input    string
+	output   string
+	err      error
+	duration time.Duration
+}
+
+// playground is an interactive session of genie playground
+type playground struct {
+	ctx context.Context
+	g   genie.Genie
+	// target is where save writes the persona
+	target    string
+	draftPath string
+	saved     []byte
+	input     string
+	runs      []playgroundRun
+	width     int
+	in        *bufio.Reader
+	out       io.Writer // Run outputs and views
+	messages  io.Writer // Prompts and notes
+}
+
+func runPlayground(cmd *cobra.Command, g genie.Genie, session genie.Session) error {
+	ctx := toolctx.WithGenieHome(cmd.Context(), session.GetGenieHomeDirectory())
+	ctx = toolctx.WithWorkingDir(ctx, session.GetWorkingDirectory())
+	if !session.IsWorkspaceTrusted() {
+		ctx = toolctx.WithWorkspaceTrusted(ctx, false)
+	}
+
+	personaID := session.GetPersona().GetID()
+	data, path, source, err := personas.FindPromptYAML(ctx, personaID)
+	if err != nil {
+		return err
+	}
+	if source == personas.PersonaSourceInternal {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, ".genie", "personas", personaID, "prompt.yaml")
+	}
+
+	draftDir, err := os.MkdirTemp("", "genie-playground-")
+	if err != nil {
+		return err
+	}
+	draftPath := filepath.Join(draftDir, "prompt.yaml")
+	if err := os.WriteFile(draftPath, data, 0644); err != nil {
+		return err
+	}
+
+	p := &playground{
+		ctx:       ctx,
+		g:         g,
+		target:    path,
+		draftPath: draftPath,
+		saved:     data,
+		width:     playgroundWidth(cmd),
+		in:        bufio.NewReader(cmd.InOrStdin()),
+		out:       cmd.OutOrStdout(),
+		messages:  cmd.ErrOrStderr(),
+	}
+	defer p.cleanup(draftDir)
+
+	p.input, _ = cmd.Flags().GetString("input")
+	if inputFile, _ := cmd.Flags().GetString("input-file"); inputFile != "" {
+		content, err := os.ReadFile(inputFile)
+		if err != nil {
+			return fmt.Errorf("failed to read test input: %w", err)
+		}
+		p.input = strings.TrimSpace(string(content))
+	}
+
+	fmt.Fprintf(p.messages, "Playground for persona %s (%s)\nDraft: %s\n", personaID, source, draftPath)
+	for p.input == "" {
+		if p.input, err = p.ask("Test input: "); err != nil {
+			return err
+		}
+	}
+	fmt.Fprint(p.messages, playgroundHelp)
+	p.run()
+	return p.loop()
+}
+
+func playgroundWidth(cmd *cobra.Command) int {
+	if width, _ := cmd.Flags().GetInt("width"); width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultPlaygroundWidth
+}
+
+// loop reads commands until quit or the end of input
+func (p *playground) loop() error {
+	for {
+		line, err := p.ask("playground> ")
+		if err != nil {
+			return nil
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		command, rest := fields[0], strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+
+		switch command {
+		case "run", "r":
+			p.run()
+		case "edit", "e":
+			if err := p.edit(); err != nil {
+				fmt.Fprintln(p.messages, err)
+				continue
+			}
+			p.run()
+		case "input", "i":
+			if rest == "" {
+				fmt.Fprintf(p.messages, "Test input: %s\n", p.input)
+				continue
+			}
+			p.input = rest
+			fmt.Fprintln(p.messages, "Test input changed; run to try it")
+		case "show", "s":
+			if err := p.show(fields[1:]); err != nil {
+				fmt.Fprintln(p.messages, err)
+			}
+		case "diff", "d":
+			if err := p.diff(fields[1:]); err != nil {
+				fmt.Fprintln(p.messages, err)
+			}
+		case "save":
+			if err := p.save(); err != nil {
+				fmt.Fprintln(p.messages, err)
+			}
+		case "help", "?":
+			fmt.Fprint(p.messages, playgroundHelp)
+		case "quit", "q", "exit":
+			return nil
+		default:
+			fmt.Fprintf(p.messages, "Unknown command %q; type help for the list\n", command)
+		}
+	}
+}
+
+// ask prints question and returns the trimmed answer
+func (p *playground) ask(question string) (string, error) {
+	fmt.Fprint(p.messages, question)
+	answer, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", errors.New("no more input")
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// run reads the draft, runs the test input against it and prints the
+// output. A draft that changed since the last run is a new revision.
+func (p *playground) run() {
+	data, err := os.ReadFile(p.draftPath)
+	if err != nil {
+		fmt.Fprintf(p.messages, "Failed to read the draft: %v\n", err)
+		return
+	}
+	registry, err := p.g.GetToolsRegistry()
+	if err != nil {
+		fmt.Fprintf(p.messages, "Failed to get tools: %v\n", err)
+		return
+	}
+	if err := personas.ValidatePrompt(data, registry); err != nil {
+		fmt.Fprintf(p.messages, "The draft is not valid, fix it and run again:\n%v\n", err)
+		return
+	}
+
+	revision := 1
+	if len(p.runs) > 0 {
+		last := p.runs[len(p.runs)-1]
+		revision = last.revision
+		if !bytes.Equal(last.prompt, data) {
+			revision++
+		}
+	}
+
+	fmt.Fprintf(p.messages, "Running revision %d...\n", revision)
+	started := time.Now()
+	output, err := chatAndWait(p.ctx, p.g, p.input, genie.WithPromptYAML(data), genie.WithEphemeral(genie.EphemeralAll))
+	run := playgroundRun{revision: revision, prompt: data, input: p.input, output: output, err: err, duration: time.Since(started)}
+	p.runs = append(p.runs, run)
+
+	fmt.Fprintf(p.out, "%s\n%s\n", p.runTitle(len(p.runs)), run.result())
+	if len(p.runs) > 1 && p.runs[len(p.runs)-2].result() != run.result() {
+		fmt.Fprintf(p.messages, "The output changed; diff compares it with run %d\n", len(p.runs)-1)
+	}
+}
+
+// result is what a run produced, its output or its error
+func (r playgroundRun) result() string {
+	if r.err != nil {
+		return "Error: " + r.err.Error()
+	}
+	return r.output
+}
+
+func (p *playground) runTitle(n int) string {
+	run := p.runs[n-1]
+	return fmt.Sprintf("── Run %d · revision %d · %s ──", n, run.revision, run.duration.Round(100*time.Millisecond))
+}
+
+// edit opens the draft in the user's editor and waits for it to close
+func (p *playground) edit() error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.CommandContext(p.ctx, args[0], append(args[1:], p.draftPath)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// runArgs picks the runs named by args, one-based, defaulting to the
+// last count runs
+func (p *playground) runArgs(args []string, count int) ([]int, error) {
+	if len(p.runs) < count {
+		return nil, fmt.Errorf("needs %d runs, there are %d", count, len(p.runs))
+	}
+	picked := make([]int, count)
+	for i := range picked {
+		picked[i] = len(p.runs) - count + i + 1
+	}
+	for i, arg := range args {
+		if i >= count {
+			break
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(p.runs) {
+			return nil, fmt.Errorf("no run %s; runs are 1 to %d", arg, len(p.runs))
+		}
+		picked[i] = n
+	}
+	return picked, nil
+}
+
+// show prints a run as its prompt's instruction beside its output
+func (p *playground) show(args []string) error {
+	picked, err := p.runArgs(args, 1)
+	if err != nil {
+		return err
+	}
+	run := p.runs[picked[0]-1]
+	fmt.Fprintln(p.out, p.runTitle(picked[0]))
+	fmt.Fprint(p.out, splitView("Instruction", promptInstruction(run.prompt), "Output", run.result(), p.width))
+	return nil
+}
+
+// diff prints how the prompt changed between two runs, then their
+// outputs side by side
+func (p *playground) diff(args []string) error {
+	picked, err := p.runArgs(args, 2)
+	if err != nil {
+		return err
+	}
+	a, b := p.runs[picked[0]-1], p.runs[picked[1]-1]
+
+	fmt.Fprintf(p.out, "%s\n%s\n", p.runTitle(picked[0]), p.runTitle(picked[1]))
+	if a.input != b.input {
+		fmt.Fprintln(p.out, "The runs had different test inputs.")
+	}
+	if bytes.Equal(a.prompt, b.prompt) {
+		fmt.Fprintln(p.out, "Same prompt revision.")
+	} else {
+		promptDiff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(a.prompt)),
+			B:        difflib.SplitLines(string(b.prompt)),
+			FromFile: fmt.Sprintf("revision %d", a.revision),
+			ToFile:   fmt.Sprintf("revision %d", b.revision),
+			Context:  2,
+		})
+		fmt.Fprint(p.out, promptDiff)
+	}
+	fmt.Fprint(p.out, splitView(fmt.Sprintf("Run %d", picked[0]), a.result(), fmt.Sprintf("Run %d", picked[1]), b.result(), p.width))
+	return nil
+}
+
+// save writes the draft to the persona's prompt.yaml
+func (p *playground) save() error {
+	data, err := os.ReadFile(p.draftPath)
+	if err != nil {
+		return err
+	}
+	registry, err := p.g.GetToolsRegistry()
+	if err != nil {
+		return err
+	}
+	if err := personas.ValidatePrompt(data, registry); err != nil {
+		return fmt.Errorf("not saved, the draft is not valid:\n%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.target), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p.target, data, 0644); err != nil {
+		return err
+	}
+	p.saved = data
+	fmt.Fprintf(p.messages, "Saved %s\n", p.target)
+	return nil
+}
+
+// cleanup removes the draft, unless it holds changes that were not saved
+func (p *playground) cleanup(draftDir string) {
+	data, err := os.ReadFile(p.draftPath)
+	if err == nil && !bytes.Equal(data, p.saved) {
+		fmt.Fprintf(p.messages, "The draft has unsaved changes; it is kept at %s\n", p.draftPath)
+		return
+	}
+	os.RemoveAll(draftDir)
+}
+
+// promptInstruction is the instruction of a prompt.yaml, or all of it
+// when it does not parse
+func promptInstruction(data []byte) string {
+	var prompt struct {
+		Instruction string `yaml:"instruction"`
+	}
+	if err := yaml.Unmarshal(data, &prompt); err != nil || prompt.Instruction == "" {
+		return string(data)
+	}
+	return prompt.Instruction
+}
+
+// splitView lays out two texts as columns of equal width, wrapping long
+// lines
+func splitView(leftTitle, left, rightTitle, right string, width int) string {
+	column := (width - 3) / 2
+	if column < 10 {
+		column = 10
+	}
+	leftLines := wrapColumn(left, column)
+	rightLines := wrapColumn(right, column)
+
+	var b strings.Builder
+	pad := func(s string) string {
+		return s + strings.Repeat(" ", max(0, column-runewidth.StringWidth(s)))
+	}
+	fmt.Fprintf(&b, "%s │ %s\n", pad(runewidth.Truncate(leftTitle, column, "…")), runewidth.Truncate(rightTitle, column, "…"))
+	fmt.Fprintf(&b, "%s─┼─%s\n", strings.Repeat("─", column), strings.Repeat("─", column))
+	for i := 0; i < max(len(leftLines), len(rightLines)); i++ {
+		var l, r string
+		if i < len(leftLines) {
+			l = leftLines[i]
+		}
+		if i < len(rightLines) {
+			r = rightLines[i]
+		}
+		b.WriteString(strings.TrimRight(pad(l)+" │ "+r, " ") + "\n")
+	}
+	return b.String()
+}
+
+// wrapColumn wraps text to width display cells, at spaces where it can
+func wrapColumn(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(strings.ReplaceAll(text, "\t", "    "), "\n"), "\n") {
+		for runewidth.StringWidth(line) > width {
+			cut := runewidth.Truncate(line, width, "")
+			if space := strings.LastIndex(cut, " "); space > 0 {
+				cut = cut[:space]
+			}
+			lines = append(lines, cut)
+			line = strings.TrimLeft(line[len(cut):], " ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
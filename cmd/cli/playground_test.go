@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaygroundRunsEditsDiffsAndSaves(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test editor is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	require.NoError(t, os.WriteFile(editor, []byte("#!/bin/sh\nprintf '# tightened\\n' >> \"$1\"\n"), 0o755))
+	t.Setenv("VISUAL", editor)
+
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("summarize this", "A long summary")
+	fixture.ExpectSimpleMessage("summarize that", "Short")
+
+	cmd := NewPlaygroundCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var out, messages bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&messages)
+	cmd.SetIn(strings.NewReader(strings.Join([]string{
+		"edit",
+		"input summarize that",
+		"run",
+		"diff 1 3",
+		"show 9",
+		"save",
+		"quit",
+	}, "\n") + "\n"))
+	cmd.SetArgs([]string{"--input", "summarize this", "--width", "60"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, out.String(), "── Run 1 · revision 1")
+	assert.Contains(t, out.String(), "── Run 2 · revision 2")
+	assert.Contains(t, out.String(), "── Run 3 · revision 2")
+	assert.Contains(t, out.String(), "+# tightened")
+	assert.Regexp(t, `A long summary\s+│ Short`, out.String())
+	assert.Contains(t, messages.String(), "The output changed; diff compares it with run 2")
+	assert.Contains(t, messages.String(), "no run 9")
+
+	// Every run used the draft, without touching the conversation
+	history, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	// A built-in persona is saved as a user persona that overrides it
+	saved, err := os.ReadFile(filepath.Join(home, ".genie", "personas", session.GetPersona().GetID(), "prompt.yaml"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(saved), "# tightened\n"))
+}
+
+func TestSplitViewWrapsColumns(t *testing.T) {
+	view := splitView("Left", "one two three four", "Right", "short", 23)
+	assert.Equal(t, "Left       │ Right\n"+
+		"───────────┼───────────\n"+
+		"one two    │ short\n"+
+		"three four │\n", view)
+}
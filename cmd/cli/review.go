@@ -0,0 +1,565 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// reviewPersonaID is the built-in persona genie review switches to unless
+// --persona picks another one.
+const reviewPersonaID = "reviewer"
+
+// maxReviewChunkBytes caps the diff sent to the model in one review turn.
+const maxReviewChunkBytes = 40 * 1024
+
+// Finding severities, from most to least severe. They double as SARIF
+// result levels.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityNote    = "note"
+)
+
+var severityRank = map[string]int{severityError: 3, severityWarning: 2, severityNote: 1}
+
+// reviewFinding is one problem the model reported in the diff.
+type reviewFinding struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	EndLine    int    `json:"end_line,omitempty"`
+	Severity   string `json:"severity"`
+	Title      string `json:"title"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// reviewReport is the aggregated outcome of reviewing every chunk.
+type reviewReport struct {
+	Target   string
+	Files    int
+	Chunks   int
+	Findings []reviewFinding
+}
+
+// NewReviewCommandWithGenie creates the review command, which runs the
+// reviewer persona over a diff and reports its findings
+func NewReviewCommandWithGenie(genieProvider func() (genie.Genie, genie.Session)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review [ref-range]",
+		Short: "Review a diff and report findings as markdown or SARIF",
+		Long: `Review the changes between two refs, a pull request, or the working tree,
+and report severity-tagged findings with file and line references.
+
+The range follows git diff: "main..feature", "main...feature", or two refs
+("main feature"). A single ref reviews what HEAD adds on top of it. With no
+range, the uncommitted changes are reviewed. Large diffs are reviewed in
+chunks, split between files and then between hunks; --parallel reviews
+several chunks at once.
+
+The report is markdown for people or SARIF 2.1.0 for CI code scanning
+annotations. --fail-on makes the command exit non-zero when a finding
+reaches the given severity.
+
+Examples:
+  genie review                           # Review uncommitted changes
+  genie review main                      # Review what HEAD adds to main
+  genie review v1.2.0..v1.3.0            # Review a release range
+  genie review --pr 123                  # Review a pull request (needs gh)
+  genie review main --format sarif -o review.sarif --fail-on error`,
+		Args: cobra.RangeArgs(0, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, session := genieProvider()
+			return runReviewCommand(cmd, g, session, args)
+		},
+	}
+
+	cmd.Flags().Int("pr", 0, "Review a GitHub pull request by number, using the gh CLI")
+	cmd.Flags().String("format", "markdown", "Report format: markdown or sarif")
+	cmd.Flags().StringP("output", "o", "", "Write the report to a file instead of stdout")
+	cmd.Flags().String("fail-on", "", "Exit with an error when a finding is at least this severe: error, warning or note")
+	cmd.Flags().Int("parallel", 1, "Review up to this many chunks of a large diff at once")
+
+	return cmd
+}
+
+func runReviewCommand(cmd *cobra.Command, g genie.Genie, session genie.Session, args []string) error {
+	pr, _ := cmd.Flags().GetInt("pr")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	parallel, _ := cmd.Flags().GetInt("parallel")
+
+	if format != "markdown" && format != "sarif" {
+		return fmt.Errorf("unknown format %q: use markdown or sarif", format)
+	}
+	if failOn != "" && severityRank[failOn] == 0 {
+		return fmt.Errorf("unknown severity %q: use error, warning or note", failOn)
+	}
+	if pr > 0 && len(args) > 0 {
+		return errors.New("--pr cannot be combined with a ref range")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	dir := session.GetWorkingDirectory()
+	target, diff, err := reviewDiff(ctx, dir, args, pr)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return fmt.Errorf("nothing to review: %s has no changes", target)
+	}
+
+	if persona == "" {
+		useReviewPersona(ctx, g, session)
+	}
+
+	report, err := reviewChanges(ctx, g, target, diff, parallel, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if format == "sarif" {
+		err = writeSARIFReport(out, report)
+	} else {
+		err = writeMarkdownReport(out, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if failOn != "" {
+		if count := countAtLeast(report.Findings, failOn); count > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("review found %d finding(s) of severity %s or higher", count, failOn)
+		}
+	}
+	return nil
+}
+
+// reviewDiff returns the diff to review and a description of where it
+// came from.
+func reviewDiff(ctx context.Context, dir string, args []string, pr int) (string, string, error) {
+	if pr > 0 {
+		out, err := tools.RunGitHubCLI(ctx, dir, nil, "pr", "diff", strconv.Itoa(pr))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get the diff of PR #%d: %w", pr, err)
+		}
+		return fmt.Sprintf("PR #%d", pr), string(out), nil
+	}
+	if _, err := runGit(ctx, dir, "", "rev-parse", "--git-dir"); err != nil {
+		return "", "", fmt.Errorf("not a git repository: %s", dir)
+	}
+
+	var target string
+	var diffArgs []string
+	switch {
+	case len(args) == 2:
+		target = args[0] + ".." + args[1]
+		diffArgs = []string{"diff", args[0], args[1]}
+	case len(args) == 1 && strings.Contains(args[0], ".."):
+		target = args[0]
+		diffArgs = []string{"diff", args[0]}
+	case len(args) == 1:
+		target = args[0] + "...HEAD"
+		diffArgs = []string{"diff", target}
+	default:
+		target = "the working tree"
+		diffArgs = []string{"diff", "HEAD"}
+		if _, err := runGit(ctx, dir, "", "rev-parse", "--verify", "HEAD"); err != nil {
+			diffArgs = []string{"diff", "--cached"}
+		}
+	}
+	for _, arg := range diffArgs[1:] {
+		if strings.HasPrefix(arg, "-") && arg != "--cached" {
+			return "", "", fmt.Errorf("invalid ref %q", arg)
+		}
+	}
+	diff, err := runGit(ctx, dir, "", diffArgs...)
+	if err != nil {
+		return "", "", err
+	}
+	return target, diff, nil
+}
+
+// reviewChanges reviews diff chunk by chunk, up to parallel chunks at a
+// time, reporting progress to progress, and collects the findings sorted
+// by severity. The first chunk that fails stops the review.
+func reviewChanges(ctx context.Context, g genie.Genie, target, diff string, parallel int, progress io.Writer) (reviewReport, error) {
+	chunks := splitDiff(diff, maxReviewChunkBytes)
+	report := reviewReport{Target: target, Files: len(diffFiles(diff)), Chunks: len(chunks)}
+
+	requests := make([]genie.BatchRequest, len(chunks))
+	for i, chunk := range chunks {
+		requests[i] = genie.BatchRequest{
+			ID:      fmt.Sprintf("chunk %d of %d", i+1, len(chunks)),
+			Message: reviewChunkPrompt(target, chunk),
+			Options: []genie.ChatOption{genie.WithResponseSchema(reviewResponseSchema())},
+		}
+	}
+	fmt.Fprintf(progress, "Reviewing %d chunk(s)...\n", len(chunks))
+	results, _ := genie.ChatBatch(ctx, g, requests,
+		genie.WithBatchConcurrency(parallel),
+		genie.WithBatchFailFast(),
+		genie.WithBatchProgress(func(done, total int, result genie.BatchResult) {
+			if result.Err == nil {
+				fmt.Fprintf(progress, "Reviewed %s (%d/%d done)\n", result.ID, done, total)
+			}
+		}))
+
+	for _, result := range results {
+		err := result.Err
+		if err == nil {
+			var findings []reviewFinding
+			findings, err = parseReviewFindings(result.Response)
+			report.Findings = append(report.Findings, findings...)
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return report, fmt.Errorf("failed to review %s: %w", result.ID, err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	sortFindings(report.Findings)
+	return report, nil
+}
+
+// useReviewPersona switches the session to the reviewer persona when it is
+// available; otherwise the session keeps its persona.
+func useReviewPersona(ctx context.Context, g genie.Genie, session genie.Session) {
+	personas, err := g.ListPersonas(ctx)
+	if err != nil {
+		return
+	}
+	for _, p := range personas {
+		if p.GetID() == reviewPersonaID {
+			session.SetPersona(p)
+			return
+		}
+	}
+}
+
+// splitDiff packs the per-file sections of a unified diff into chunks of at
+// most limit bytes. A file larger than the limit is split between hunks,
+// each part repeating the file header.
+func splitDiff(diff string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+	for _, file := range diffFiles(diff) {
+		if current.Len()+len(file) > limit {
+			flush()
+		}
+		if len(file) <= limit {
+			current.WriteString(file)
+			continue
+		}
+		header, hunks := splitHunks(file)
+		for _, hunk := range hunks {
+			if current.Len() > 0 && current.Len()+len(hunk) > limit {
+				flush()
+			}
+			if current.Len() == 0 {
+				current.WriteString(header)
+			}
+			current.WriteString(truncateForPrompt(hunk, limit))
+		}
+		flush()
+	}
+	flush()
+	return chunks
+}
+
+// diffFiles splits a unified diff at its "diff --git" headers.
+func diffFiles(diff string) []string {
+	var files []string
+	start := -1
+	offset := 0
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			if start >= 0 {
+				files = append(files, diff[start:offset])
+			}
+			start = offset
+		}
+		offset += len(line)
+	}
+	if start >= 0 {
+		files = append(files, diff[start:])
+	} else if strings.TrimSpace(diff) != "" {
+		files = append(files, diff)
+	}
+	return files
+}
+
+// splitHunks separates a file's diff into its header and its "@@" hunks.
+func splitHunks(file string) (string, []string) {
+	var header strings.Builder
+	var hunks []string
+	var hunk strings.Builder
+	for _, line := range strings.SplitAfter(file, "\n") {
+		if strings.HasPrefix(line, "@@") && hunk.Len() > 0 {
+			hunks = append(hunks, hunk.String())
+			hunk.Reset()
+		}
+		if hunk.Len() == 0 && !strings.HasPrefix(line, "@@") {
+			header.WriteString(line)
+			continue
+		}
+		hunk.WriteString(line)
+	}
+	if hunk.Len() > 0 {
+		hunks = append(hunks, hunk.String())
+	}
+	return header.String(), hunks
+}
+
+// reviewResponseSchema is the JSON shape each chunk's review must take.
+func reviewResponseSchema() *ai.Schema {
+	return &ai.Schema{
+		Type: ai.TypeObject,
+		Properties: map[string]*ai.Schema{
+			"findings": {
+				Type: ai.TypeArray,
+				Items: &ai.Schema{
+					Type: ai.TypeObject,
+					Properties: map[string]*ai.Schema{
+						"file":       {Type: ai.TypeString, Description: "Repo-relative path of the changed file."},
+						"line":       {Type: ai.TypeInteger, Description: "Line in the new version of the file.", Minimum: 1},
+						"end_line":   {Type: ai.TypeInteger, Description: "Last line of the problem when it spans several lines."},
+						"severity":   {Type: ai.TypeString, Enum: []string{severityError, severityWarning, severityNote}},
+						"title":      {Type: ai.TypeString, Description: "One-line summary of the problem."},
+						"message":    {Type: ai.TypeString, Description: "What is wrong and why it matters."},
+						"suggestion": {Type: ai.TypeString, Description: "How to fix it."},
+					},
+					Required: []string{"file", "line", "severity", "title", "message"},
+				},
+			},
+		},
+		Required: []string{"findings"},
+	}
+}
+
+// reviewChunkPrompt asks the model to review one chunk of the diff.
+func reviewChunkPrompt(target, chunk string) string {
+	return fmt.Sprintf(`Review this part of the diff of %s.
+
+Report problems in the added or changed lines as JSON: {"findings": [...]}, where each finding has file, line (in the new version of the file), optional end_line, severity (error, warning or note), title, message and optional suggestion. Reply with {"findings": []} when there is nothing to report.
+
+Diff:
+%s`, target, chunk)
+}
+
+// parseReviewFindings reads the findings out of the model's answer for
+// one chunk.
+func parseReviewFindings(response string) ([]reviewFinding, error) {
+	var answer struct {
+		Findings []reviewFinding `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(ai.ExtractJSON(response)), &answer); err != nil {
+		return nil, fmt.Errorf("failed to parse review: %w", err)
+	}
+	return answer.Findings, nil
+}
+
+// sortFindings orders findings by severity, then by file and line.
+func sortFindings(findings []reviewFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
+
+func countAtLeast(findings []reviewFinding, severity string) int {
+	count := 0
+	for _, f := range findings {
+		if severityRank[f.Severity] >= severityRank[severity] {
+			count++
+		}
+	}
+	return count
+}
+
+func (f reviewFinding) location() string {
+	if f.EndLine > f.Line {
+		return fmt.Sprintf("%s:%d-%d", f.File, f.Line, f.EndLine)
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+func writeMarkdownReport(w io.Writer, report reviewReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Review of %s\n\n", report.Target)
+	fmt.Fprintf(&b, "%d file(s) reviewed in %d chunk(s): %d error(s), %d warning(s), %d note(s).\n",
+		report.Files, report.Chunks,
+		countSeverity(report.Findings, severityError),
+		countSeverity(report.Findings, severityWarning),
+		countSeverity(report.Findings, severityNote))
+	if len(report.Findings) == 0 {
+		b.WriteString("\nNo findings.\n")
+	}
+	for _, heading := range []struct{ severity, title string }{
+		{severityError, "Errors"},
+		{severityWarning, "Warnings"},
+		{severityNote, "Notes"},
+	} {
+		if countSeverity(report.Findings, heading.severity) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", heading.title)
+		for _, f := range report.Findings {
+			if f.Severity != heading.severity {
+				continue
+			}
+			fmt.Fprintf(&b, "\n### `%s` %s\n\n%s\n", f.location(), f.Title, f.Message)
+			if f.Suggestion != "" {
+				fmt.Fprintf(&b, "\n**Suggestion:** %s\n", f.Suggestion)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func countSeverity(findings []reviewFinding, severity string) int {
+	count := 0
+	for _, f := range findings {
+		if f.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// SARIF 2.1.0 types, limited to what code scanning annotations use.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+func writeSARIFReport(w io.Writer, report reviewReport) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "genie",
+			InformationURI: "https://github.com/kcaldas/genie",
+			Rules: []sarifRule{
+				{ID: "genie-review/" + severityError, ShortDescription: sarifMessage{Text: "Blocking problem found in review"}},
+				{ID: "genie-review/" + severityWarning, ShortDescription: sarifMessage{Text: "Likely problem found in review"}},
+				{ID: "genie-review/" + severityNote, ShortDescription: sarifMessage{Text: "Review suggestion"}},
+			},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, f := range report.Findings {
+		text := f.Title + "\n\n" + f.Message
+		if f.Suggestion != "" {
+			text += "\n\nSuggestion: " + f.Suggestion
+		}
+		region := sarifRegion{StartLine: max(f.Line, 1)}
+		if f.EndLine > f.Line {
+			region.EndLine = f.EndLine
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  "genie-review/" + f.Severity,
+			Level:   f.Severity,
+			Message: sarifMessage{Text: text},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: f.File},
+				Region:           region,
+			}}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fileDiff(name string, hunks ...string) string {
+	diff := fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", name, name, name, name)
+	for i, hunk := range hunks {
+		diff += fmt.Sprintf("@@ -%d,1 +%d,1 @@\n%s\n", i+1, i+1, hunk)
+	}
+	return diff
+}
+
+func TestSplitDiffPacksFilesIntoChunks(t *testing.T) {
+	a := fileDiff("a.go", "+"+strings.Repeat("a", 40))
+	b := fileDiff("b.go", "+"+strings.Repeat("b", 40))
+	c := fileDiff("c.go", "+"+strings.Repeat("c", 40))
+
+	chunks := splitDiff(a+b+c, len(a)+len(b))
+	assert.Equal(t, []string{a + b, c}, chunks)
+	assert.Equal(t, []string{a + b + c}, splitDiff(a+b+c, 10*len(a)))
+}
+
+func TestSplitDiffSplitsLargeFileBetweenHunks(t *testing.T) {
+	first := "+" + strings.Repeat("x", 100)
+	second := "+" + strings.Repeat("y", 100)
+	file := fileDiff("big.go", first, second)
+
+	chunks := splitDiff(file, 200)
+	require.Len(t, chunks, 2)
+	for _, chunk := range chunks {
+		assert.True(t, strings.HasPrefix(chunk, "diff --git a/big.go b/big.go\n"), "every part repeats the file header")
+	}
+	assert.Contains(t, chunks[0], first)
+	assert.Contains(t, chunks[1], second)
+}
+
+func TestWriteSARIFReport(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeSARIFReport(&out, reviewReport{Findings: []reviewFinding{
+		{File: "main.go", Line: 12, EndLine: 14, Severity: severityError, Title: "Unchecked error", Message: "The error is dropped."},
+	}}))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(out.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Equal(t, "genie", log.Runs[0].Tool.Driver.Name)
+	require.Len(t, log.Runs[0].Results, 1)
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "genie-review/error", result.RuleID)
+	assert.Equal(t, "main.go", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, sarifRegion{StartLine: 12, EndLine: 14}, result.Locations[0].PhysicalLocation.Region)
+}
+
+func TestReviewCommandReportsFindings(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	dir := session.GetWorkingDirectory()
+	initGitRepo(t, dir)
+	writeAndStage(t, dir, "main.go", "package main\n")
+	_, err := runGit(context.Background(), dir, "", "commit", "-q", "-m", "init")
+	require.NoError(t, err)
+	writeAndStage(t, dir, "main.go", "package main\n\nfunc main() { panic(nil) }\n")
+
+	target, diff, err := reviewDiff(context.Background(), dir, nil, 0)
+	require.NoError(t, err)
+	fixture.ExpectSimpleMessage(reviewChunkPrompt(target, diff),
+		`{"findings":[{"file":"main.go","line":3,"severity":"warning","title":"Panic on startup","message":"main always panics."},`+
+			`{"file":"main.go","line":1,"severity":"note","title":"Missing doc","message":"Add a package comment."}]}`)
+
+	cmd := NewReviewCommandWithGenie(func() (genie.Genie, genie.Session) { return fixture.Genie, session })
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--fail-on", "warning"})
+	err = cmd.Execute()
+	assert.ErrorContains(t, err, "1 finding(s) of severity warning or higher")
+
+	report := stdout.String()
+	assert.Contains(t, report, "# Review of the working tree")
+	assert.Contains(t, report, "0 error(s), 1 warning(s), 1 note(s)")
+	assert.Contains(t, report, "### `main.go:3` Panic on startup")
+	assert.Less(t, strings.Index(report, "## Warnings"), strings.Index(report, "## Notes"))
+}
+
+func TestReviewDiffRanges(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	ctx := context.Background()
+	writeAndStage(t, dir, "a.txt", "one\n")
+	_, err := runGit(ctx, dir, "", "commit", "-q", "-m", "first")
+	require.NoError(t, err)
+	_, err = runGit(ctx, dir, "", "tag", "v1")
+	require.NoError(t, err)
+	writeAndStage(t, dir, "a.txt", "two\n")
+	_, err = runGit(ctx, dir, "", "commit", "-q", "-m", "second")
+	require.NoError(t, err)
+
+	for _, args := range [][]string{{"v1..HEAD"}, {"v1", "HEAD"}, {"v1"}} {
+		_, diff, err := reviewDiff(ctx, dir, args, 0)
+		require.NoError(t, err, args)
+		assert.Contains(t, diff, "+two", args)
+	}
+
+	_, diff, err := reviewDiff(ctx, dir, nil, 0)
+	require.NoError(t, err)
+	assert.Empty(t, diff, "a clean working tree has nothing to review")
+}
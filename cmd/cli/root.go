@@ -1,34 +1,62 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/kcaldas/genie/cmd/bootstrap"
+	"github.com/kcaldas/genie/cmd/pipe"
 	"github.com/kcaldas/genie/cmd/tui"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/budget"
+	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/httpclient"
 	"github.com/kcaldas/genie/pkg/logging"
+	"github.com/kcaldas/genie/pkg/offline"
+	"github.com/kcaldas/genie/pkg/plugin"
+	"github.com/kcaldas/genie/pkg/sessions"
+	"github.com/kcaldas/genie/pkg/team"
+	"github.com/kcaldas/genie/pkg/telemetry"
 	"github.com/kcaldas/genie/pkg/version"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Global flags
-	workingDir  string
-	allowedDirs []string
-	verbose     bool
-	quiet       bool
-	persona     string
+	workingDir     string
+	allowedDirs    []string
+	scopeDirs      []string
+	verbose        bool
+	quiet          bool
+	persona        string
+	readOnly       bool
+	trustNow       bool
+	pipeMode       bool
+	metricsAddr    string
+	colorMode      string
+	accessible     bool
+	offlineMode    bool
+	overrideBudget bool
 
 	// Genie instance - initialized once and reused
 	genieInstance  genie.Genie
 	initialSession genie.Session
+
+	// stopTracing flushes exported spans on exit
+	stopTracing func(context.Context) error
 )
 
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
-	Use:     "genie",
-	Short:   "Genie AI coding assistant",
-	Long:    `Genie is an AI coding assistant that helps with software engineering tasks.`,
+	Use:   "genie",
+	Short: "Genie AI coding assistant",
+	Long: `Genie is an AI coding assistant that helps with software engineering tasks.
+
+Run 'genie help topics' for guides to personas, tools, policies, themes and
+serving.`,
 	Version: version.GetVersion(),
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Configure logger based on flags
@@ -42,8 +70,66 @@ var RootCmd = &cobra.Command{
 		}
 		logging.SetGlobalLogger(logger)
 
-		// Initialize Genie once for all commands
+		// Export OpenTelemetry traces when an OTLP endpoint is configured
 		var err error
+		configManager := config.NewConfigManager()
+
+		// The team's checked-in .genie/team.yaml fills in the settings the
+		// user left unset
+		if cwd, err := os.Getwd(); err == nil {
+			teamConfig, err := team.Load(cwd)
+			if err != nil {
+				return err
+			}
+			if teamConfig != nil {
+				teamConfig.Apply(decideWorkspaceTrust())
+			}
+		}
+
+		stopTracing, err = telemetry.Setup(cmd.Context(), configManager)
+		if err != nil {
+			return err
+		}
+
+		// Say so up front when TLS verification is off, before the TUI
+		// takes over the screen
+		if httpclient.Insecure(configManager) {
+			fmt.Fprintln(os.Stderr, httpclient.InsecureWarning)
+		}
+
+		// Load the Go plugins in ~/.genie/plugins; one that fails to load
+		// is skipped rather than keeping Genie from starting
+		for _, err := range plugin.LoadInstalled(configManager) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		if overrideBudget {
+			os.Setenv(budget.OverrideConfigKey, "true")
+		}
+
+		// Decide once whether to run offline, so Genie doesn't probe the
+		// network again, and say how it will answer
+		if offlineMode {
+			os.Setenv(offline.ConfigKey, "true")
+		}
+		if offline.Detect(cmd.Context(), configManager) {
+			os.Setenv(offline.ConfigKey, "true")
+			if !quiet {
+				fmt.Fprintln(os.Stderr, offline.Describe(cmd.Context(), configManager))
+			}
+		} else {
+			os.Setenv(offline.ConfigKey, "false")
+		}
+
+		// On a first run in a terminal, offer to configure a backend
+		// rather than fail with the environment variables to set
+		if !pipeMode && !offline.Enabled(configManager) && isInteractiveTerminal() && !backendConfigured(configManager) {
+			if err := offerFirstRunSetup(cmd); err != nil {
+				return err
+			}
+		}
+
+		// Initialize Genie once for all commands
 		genieInstance, err = bootstrap.Genie()
 		if err != nil {
 			return fmt.Errorf("failed to initialize Genie: %w", err)
@@ -60,12 +146,7 @@ var RootCmd = &cobra.Command{
 			personaPtr = &persona
 		}
 
-		var startOpts []genie.StartOption
-		if len(allowedDirs) > 0 {
-			startOpts = append(startOpts, genie.WithAllowedDirs(allowedDirs...))
-		}
-
-		initialSession, err = genieInstance.Start(workingDirPtr, personaPtr, startOpts...)
+		initialSession, err = genieInstance.Start(workingDirPtr, personaPtr, startOptions(decideWorkspaceTrust())...)
 		if err != nil {
 			return err // Return the original error without wrapping
 		}
@@ -78,8 +159,43 @@ var RootCmd = &cobra.Command{
 		if genieInstance != nil {
 			genieInstance.Shutdown()
 		}
+		if stopTracing != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := stopTracing(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to flush traces: %v\n", err)
+			}
+		}
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Stdin carries JSON-RPC requests in pipe mode
+		if pipeMode {
+			if metricsAddr != "" {
+				stop, err := serveMetrics(metricsAddr, genieInstance.GetEventBus())
+				if err != nil {
+					return err
+				}
+				defer stop()
+			}
+			server := pipe.NewServer(genieInstance, cmd.OutOrStdout())
+			if registry, err := sessions.DefaultRegistry(); err == nil {
+				server.WithSessions(openNamedSession(registry))
+			}
+			return server.Serve(cmd.Context(), cmd.InOrStdin())
+		}
+		if metricsAddr != "" {
+			return fmt.Errorf("--metrics-addr requires --pipe")
+		}
+
+		// Without a terminal to draw on, answer the piped prompt as plain
+		// text, like genie ask
+		if !isTerminalWriter(cmd.OutOrStdout()) {
+			if !hasStdinInput() {
+				return fmt.Errorf("stdout is not a terminal: pipe a prompt on stdin or use 'genie ask \"...\"'")
+			}
+			return runAskCommandWithSession(cmd, args, genieInstance, initialSession, genieInstance.GetEventBus())
+		}
+
 		// Check for stdin input before starting TUI
 		var stdinContent string
 		if hasStdinInput() {
@@ -90,6 +206,11 @@ var RootCmd = &cobra.Command{
 			stdinContent = content
 		}
 
+		// The TUI reads accessible mode from its config or the environment
+		if accessible {
+			os.Setenv(types.AccessibleEnv, "true")
+		}
+
 		// No subcommand provided - start TUI mode
 		tuiApp, err := tui.InjectTUI(initialSession)
 		if err != nil {
@@ -98,15 +219,46 @@ var RootCmd = &cobra.Command{
 		defer tuiApp.Stop()
 
 		// Start the TUI with the initial message if provided
-		return tuiApp.StartWithMessage(stdinContent)
+		err = tuiApp.StartWithMessage(stdinContent)
+		tuiApp.Stop()
+		writeJournal(cmd.Context(), config.NewConfigManager(), genieInstance, initialSession, os.Stderr)
+		return err
 	},
 }
 
+// startOptions returns the options Genie starts with from the global
+// flags, for a workspace that is trusted or not
+func startOptions(trusted bool) []genie.StartOption {
+	var opts []genie.StartOption
+	if len(allowedDirs) > 0 {
+		opts = append(opts, genie.WithAllowedDirs(allowedDirs...))
+	}
+	if len(scopeDirs) > 0 {
+		opts = append(opts, genie.WithScope(scopeDirs...))
+	}
+	if readOnly {
+		opts = append(opts, genie.WithReadOnlyMode())
+	}
+	if !trusted {
+		opts = append(opts, genie.WithUntrustedWorkspace())
+	}
+	return opts
+}
+
 func init() {
 	// Global flags available to all commands
 	RootCmd.PersistentFlags().StringVar(&workingDir, "cwd", "", "working directory for Genie operations")
 	RootCmd.PersistentFlags().StringArrayVar(&allowedDirs, "allow-dir", nil, "additional directory that file tools may access (repeatable)")
+	RootCmd.PersistentFlags().StringArrayVar(&scopeDirs, "scope", nil, "confine file tools and searches to this directory of a monorepo, e.g. services/payments/... (repeatable)")
 	RootCmd.PersistentFlags().StringVar(&persona, "persona", "", "persona to use (e.g., engineer, product_owner, persona_creator)")
+	RootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "plan mode: disable tools that modify files or run side-effecting commands")
+	RootCmd.PersistentFlags().BoolVar(&overrideBudget, "override-budget", false, "ignore the request, token and cost budgets (GENIE_BUDGET_*) for this run")
+	RootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "run without network: answer with a local Ollama or LM Studio and withhold tools that need the network (detected automatically unless GENIE_OFFLINE is set)")
+	RootCmd.PersistentFlags().BoolVar(&trustNow, "trust-workspace", false, "trust the current workspace and load its project personas, skills, commands and .mcp.json")
+	RootCmd.Flags().BoolVar(&pipeMode, "pipe", false, "serve JSON-RPC over stdin/stdout, one JSON object per line (for editor plugins)")
+	RootCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "with --pipe, serve Prometheus metrics at http://<addr>/metrics (e.g. localhost:9464)")
+	RootCmd.Flags().BoolVar(&accessible, "accessible", false, "screen-reader friendly TUI: no spinners or box-drawing, plain-line status updates and labels in words")
+	RootCmd.PersistentFlags().StringVar(&colorMode, "color", colorAuto, "when answers printed to stdout keep ANSI colors: auto (only on a terminal), always or never")
 	RootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output (debug level)")
 	RootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "quiet output (errors only)")
 
@@ -121,6 +273,40 @@ func addCommands() {
 		return genieInstance, initialSession
 	}))
 
+	RootCmd.AddCommand(NewAgentCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewCommitCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewReviewCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewFixTestsCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewPersonaCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewPlaygroundCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewEvalCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewNewCommandWithGenie(func() (genie.Genie, genie.Session) {
+		return genieInstance, initialSession
+	}))
+
+	RootCmd.AddCommand(NewSetupCommand())
+
 	// Future commands can be added here:
 	// RootCmd.AddCommand(NewIdeasCommand(...))
 	// RootCmd.AddCommand(NewConfigCommand(...))
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/kcaldas/genie/pkg/sessions"
+	"github.com/spf13/cobra"
+)
+
+// newSessionsCommand creates the sessions command, which shows and manages
+// the named sessions of genie attach. It reads the registry only, so it
+// skips starting Genie.
+func newSessionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List and remove the named sessions of genie attach",
+		Long: `A named session ties a name to a working directory, so each project can
+have its own Genie running at once. Sessions are kept in ~/.genie/sessions.json
+with the process that has them attached, if any.
+
+Examples:
+  genie sessions list                # Every session and whether it is attached
+  genie sessions remove backend-api  # Forget a session that is not attached`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List named sessions and the processes attached to them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := sessions.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+			return runSessionsList(cmd.OutOrStdout(), registry)
+		},
+	}
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Forget a named session that is not attached",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := sessions.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			if err := registry.Remove(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed session %s\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.AddCommand(listCmd, removeCmd)
+	return cmd
+}
+
+func runSessionsList(out io.Writer, registry *sessions.Registry) error {
+	list, err := registry.List()
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Fprintln(out, "No named sessions yet. Start one with 'genie attach <name>'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tDIRECTORY")
+	for _, session := range list {
+		status := "idle since " + session.LastUsed.Local().Format("2006-01-02 15:04")
+		if session.Active() {
+			status = fmt.Sprintf("attached (%s, pid %d)", session.Mode, session.PID)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", session.Name, status, session.WorkingDir)
+	}
+	return w.Flush()
+}
+
+func init() {
+	RootCmd.AddCommand(newSessionsCommand())
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/sessions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSessionsList(t *testing.T) {
+	registry := sessions.NewRegistry(filepath.Join(t.TempDir(), sessions.FileName))
+
+	var out bytes.Buffer
+	require.NoError(t, runSessionsList(&out, registry))
+	assert.Contains(t, out.String(), "No named sessions yet")
+
+	apiDir, webDir := t.TempDir(), t.TempDir()
+	_, err := registry.Attach("web", webDir, sessions.ModeTUI)
+	require.NoError(t, err)
+	require.NoError(t, registry.Detach("web"))
+	_, err = registry.Attach("api", apiDir, sessions.ModePipe)
+	require.NoError(t, err)
+
+	out.Reset()
+	require.NoError(t, runSessionsList(&out, registry))
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Contains(t, string(lines[1]), "api")
+	assert.Contains(t, string(lines[1]), "attached (pipe, pid")
+	assert.Contains(t, string(lines[1]), apiDir)
+	assert.Contains(t, string(lines[2]), "idle since")
+}
+
+func TestAttachSession(t *testing.T) {
+	registry := sessions.NewRegistry(filepath.Join(t.TempDir(), sessions.FileName))
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	// A new session is created for the current directory
+	session, err := attachSession(registry, "api", "")
+	require.NoError(t, err)
+	assert.Equal(t, cwd, session.WorkingDir)
+	require.NoError(t, registry.Detach("api"))
+
+	// --cwd moves it; without it, the session keeps its directory
+	dir := t.TempDir()
+	session, err = attachSession(registry, "api", dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, session.WorkingDir)
+	require.NoError(t, registry.Detach("api"))
+	session, err = attachSession(registry, "api", "")
+	require.NoError(t, err)
+	assert.Equal(t, dir, session.WorkingDir)
+
+	_, err = attachSession(registry, "no/slashes", "")
+	assert.ErrorContains(t, err, "invalid session name")
+}
@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/credentials"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/llm/anthropic"
+	"github.com/kcaldas/genie/pkg/llm/genai"
+	"github.com/kcaldas/genie/pkg/llm/lmstudio"
+	"github.com/kcaldas/genie/pkg/llm/models"
+	"github.com/kcaldas/genie/pkg/llm/ollama"
+	"github.com/kcaldas/genie/pkg/llm/openai"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// setupBackend is a backend the setup wizard offers
+type setupBackend struct {
+	Name  string
+	Label string
+	// Provider is the GENIE_LLM_PROVIDER serving the backend
+	Provider string
+	// GenAIBackend is the GENAI_BACKEND of the genai provider's backends
+	GenAIBackend string
+	// KeyVar is the variable asked for, if any; Secret hides its input
+	KeyVar    string
+	KeyPrompt string
+	Secret    bool
+	// DefaultModel is offered first; local backends have none since they
+	// serve whatever the user has pulled
+	DefaultModel string
+}
+
+var setupBackends = []setupBackend{
+	{Name: "gemini", Label: "Google Gemini API", Provider: "genai", GenAIBackend: "gemini", KeyVar: "GEMINI_API_KEY", KeyPrompt: "Gemini API key (https://aistudio.google.com/apikey)", Secret: true, DefaultModel: "gemini-2.5-flash"},
+	{Name: "vertex", Label: "Google Vertex AI (uses gcloud credentials)", Provider: "genai", GenAIBackend: "vertex", KeyVar: "GOOGLE_CLOUD_PROJECT", KeyPrompt: "Google Cloud project ID", DefaultModel: "gemini-2.5-flash"},
+	{Name: "openai", Label: "OpenAI", Provider: "openai", KeyVar: "OPENAI_API_KEY", KeyPrompt: "OpenAI API key", Secret: true, DefaultModel: "gpt-5-mini"},
+	{Name: "anthropic", Label: "Anthropic", Provider: "anthropic", KeyVar: "ANTHROPIC_API_KEY", KeyPrompt: "Anthropic API key", Secret: true, DefaultModel: "claude-sonnet-4-5"},
+	{Name: "ollama", Label: "Ollama (local models)", Provider: "ollama"},
+	{Name: "lmstudio", Label: "LM Studio (local models)", Provider: "lmstudio"},
+	{Name: "mock", Label: "Mock (scripted answers, to try Genie without a key)", Provider: "genai", GenAIBackend: "mock", DefaultModel: "gemini-2.5-flash"},
+}
+
+// backendKeys are the settings that configure a backend: with none of
+// them, and no local or mock provider chosen, Genie cannot answer
+var backendKeys = []string{"GEMINI_API_KEY", "GOOGLE_CLOUD_PROJECT", "OPENAI_API_KEY", "ANTHROPIC_API_KEY", "ANTHROPIC_AUTH_TOKEN", "GENIE_REPLAY_FILE"}
+
+// backendConfigured reports whether any LLM backend is configured, so
+// the first run can offer setup instead of failing.
+func backendConfigured(cfg config.Manager) bool {
+	for _, key := range backendKeys {
+		if cfg.GetStringWithDefault(key, "") != "" {
+			return true
+		}
+	}
+	switch strings.ToLower(cfg.GetStringWithDefault("GENIE_LLM_PROVIDER", "")) {
+	case "ollama", "lmstudio", "lm-studio", "mock":
+		return true
+	}
+	return strings.EqualFold(cfg.GetStringWithDefault("GENAI_BACKEND", ""), "mock")
+}
+
+// NewSetupCommand creates the setup command, which configures the LLM
+// backend. It runs before Genie can start, so it skips starting Genie.
+func NewSetupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Choose the LLM backend, API key and default model",
+		Long: `Walk through configuring Genie: pick a backend, enter its API key, choose a
+default model, and check the connection with a test call. The settings are
+saved in ~/.genie/config.env, readable only by you. Environment variables
+and a .env file in the working directory take precedence over it.
+
+Genie offers this setup on its own when it starts in a terminal with no
+backend configured.`,
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return newSetupWizard(cmd.InOrStdin(), cmd.ErrOrStderr()).run(cmd.Context())
+		},
+	}
+	return cmd
+}
+
+// offerFirstRunSetup runs the setup wizard when no backend is configured,
+// after asking. Declining leaves the configuration as it is.
+func offerFirstRunSetup(cmd *cobra.Command) error {
+	wizard := newSetupWizard(cmd.InOrStdin(), cmd.ErrOrStderr())
+	fmt.Fprintln(wizard.out, "Welcome to Genie! No LLM backend is configured yet.")
+	answer, err := wizard.ask("Set one up now? [Y/n] ")
+	if err != nil {
+		return err
+	}
+	if !isYes(answer, true) {
+		fmt.Fprintln(wizard.out, "Skipped. Run 'genie setup' at any time, or see docs/CONFIGURATION.md for the environment variables.")
+		return nil
+	}
+	return wizard.run(cmd.Context())
+}
+
+// setupWizard asks the questions of genie setup, one line each
+type setupWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	// readSecret reads an API key without echoing it
+	readSecret func() (string, error)
+	// verify makes a test call to model on the configured backend
+	verify func(ctx context.Context, backend setupBackend, model string) error
+	// save stores the chosen settings
+	save func(values map[string]string) error
+	// credentials opens the store API keys can go to instead of the
+	// config file; nil or an error leaves them in the file
+	credentials func() (credentials.Store, error)
+}
+
+func newSetupWizard(in io.Reader, out io.Writer) *setupWizard {
+	w := &setupWizard{in: bufio.NewReader(in), out: out, verify: verifyBackend, save: config.SaveUserConfig}
+	w.readSecret = func() (string, error) {
+		if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			return readSecretLine(f, w.out)
+		}
+		return w.readLine()
+	}
+	w.credentials = func() (credentials.Store, error) {
+		return credentials.Open(os.Getenv(credentials.BackendConfigKey), os.Getenv(credentials.PassphraseConfigKey))
+	}
+	return w
+}
+
+// run asks for the settings until the test call succeeds or the user
+// stops trying, then saves them
+func (w *setupWizard) run(ctx context.Context) error {
+	for {
+		backend, err := w.askBackend()
+		if err != nil {
+			return err
+		}
+		values := map[string]string{"GENIE_LLM_PROVIDER": backend.Provider}
+		if backend.GenAIBackend != "" {
+			values["GENAI_BACKEND"] = backend.GenAIBackend
+		}
+		if backend.KeyVar != "" {
+			if values[backend.KeyVar], err = w.askKey(backend); err != nil {
+				return err
+			}
+		}
+		model, err := w.askModel(backend)
+		if err != nil {
+			return err
+		}
+		values["GENIE_MODEL_NAME"] = model
+
+		// The test call reads its settings from the environment
+		for key, value := range values {
+			os.Setenv(key, value)
+		}
+		fmt.Fprintf(w.out, "Checking %s with %s...\n", backend.Label, model)
+		if err := w.verify(ctx, backend, model); err != nil {
+			fmt.Fprintf(w.out, "The test call failed: %v\n", err)
+			answer, err := w.ask("Try again? [Y/n] ")
+			if err != nil {
+				return err
+			}
+			if isYes(answer, true) {
+				continue
+			}
+			if answer, err = w.ask("Save these settings anyway? [y/N] "); err != nil {
+				return err
+			}
+			if !isYes(answer, false) {
+				return errors.New("setup cancelled: settings not saved")
+			}
+		} else {
+			fmt.Fprintln(w.out, "Connected.")
+		}
+
+		if backend.Secret {
+			if err := w.offerKeychain(backend, values); err != nil {
+				return err
+			}
+		}
+		if err := w.save(values); err != nil {
+			return err
+		}
+		path, _ := config.UserConfigPath()
+		fmt.Fprintf(w.out, "Saved to %s. Run 'genie setup' again to change it.\n\n", path)
+		return nil
+	}
+}
+
+// offerKeychain moves the API key into the credential store, when one is
+// available and the user agrees, leaving a keychain: reference in values
+func (w *setupWizard) offerKeychain(backend setupBackend, values map[string]string) error {
+	if w.credentials == nil {
+		return nil
+	}
+	store, err := w.credentials()
+	if err != nil {
+		return nil
+	}
+	answer, err := w.ask(fmt.Sprintf("Store the key in the %s instead of the config file? [Y/n] ", store.Name()))
+	if err != nil || !isYes(answer, true) {
+		return err
+	}
+	if err := store.Set(backend.Name, values[backend.KeyVar]); err != nil {
+		fmt.Fprintf(w.out, "%v; keeping the key in the config file.\n", err)
+		return nil
+	}
+	values[backend.KeyVar] = credentials.Reference(backend.Name)
+	return nil
+}
+
+func (w *setupWizard) askBackend() (setupBackend, error) {
+	fmt.Fprintln(w.out, "\nWhich backend should Genie use?")
+	for i, backend := range setupBackends {
+		fmt.Fprintf(w.out, "  %d. %s\n", i+1, backend.Label)
+	}
+	for {
+		answer, err := w.ask("Backend [1]: ")
+		if err != nil {
+			return setupBackend{}, err
+		}
+		if answer == "" {
+			return setupBackends[0], nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(setupBackends) {
+			return setupBackends[n-1], nil
+		}
+		for _, backend := range setupBackends {
+			if strings.EqualFold(answer, backend.Name) {
+				return backend, nil
+			}
+		}
+		fmt.Fprintf(w.out, "Pick a number from 1 to %d.\n", len(setupBackends))
+	}
+}
+
+func (w *setupWizard) askKey(backend setupBackend) (string, error) {
+	for {
+		fmt.Fprintf(w.out, "%s: ", backend.KeyPrompt)
+		read := w.readLine
+		if backend.Secret {
+			read = w.readSecret
+		}
+		key, err := read()
+		if err != nil {
+			return "", err
+		}
+		if key != "" {
+			return key, nil
+		}
+	}
+}
+
+// askModel offers the catalog models of the backend's provider; any
+// other name is accepted too
+func (w *setupWizard) askModel(backend setupBackend) (string, error) {
+	known := models.Known(backend.Provider)
+	if len(known) > 0 && backend.GenAIBackend != "mock" {
+		fmt.Fprintf(w.out, "Known models: %s\n", strings.Join(known, ", "))
+	}
+	for {
+		question := "Model: "
+		if backend.DefaultModel != "" {
+			question = fmt.Sprintf("Model [%s]: ", backend.DefaultModel)
+		}
+		model, err := w.ask(question)
+		if err != nil {
+			return "", err
+		}
+		if model == "" {
+			model = backend.DefaultModel
+		}
+		if model != "" {
+			return model, nil
+		}
+	}
+}
+
+// ask prints question and returns the trimmed answer
+func (w *setupWizard) ask(question string) (string, error) {
+	fmt.Fprint(w.out, question)
+	return w.readLine()
+}
+
+// readLine returns the next trimmed line. Input that ends mid-wizard is
+// an error rather than an endless loop.
+func (w *setupWizard) readLine() (string, error) {
+	answer, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", errors.New("setup cancelled: no more input")
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+func isYes(answer string, byDefault bool) bool {
+	switch strings.ToLower(answer) {
+	case "":
+		return byDefault
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// verifyBackend sends a short prompt to model straight to the backend's
+// client, so a persona's own provider cannot get in the way
+func verifyBackend(ctx context.Context, backend setupBackend, model string) error {
+	eventBus := events.NewEventBus()
+	var gen ai.Gen
+	var err error
+	switch backend.Provider {
+	case "openai":
+		gen, err = openai.NewClient(eventBus)
+	case "anthropic":
+		gen, err = anthropic.NewClient(eventBus)
+	case "ollama":
+		gen, err = ollama.NewClient(eventBus)
+	case "lmstudio":
+		gen, err = lmstudio.NewClient(eventBus)
+	default:
+		gen, err = genai.NewClient(eventBus)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	_, err = gen.GenerateContent(ctx, ai.Prompt{
+		Name:         "setup-check",
+		Text:         "Reply with the single word OK.",
+		LLMProvider:  backend.Provider,
+		ModelName:    model,
+		DisableCache: true,
+	}, false)
+	return err
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearBackendEnv unsets the backend settings for the test, restoring them
+// afterwards
+func clearBackendEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range append(backendKeys, "GENIE_LLM_PROVIDER", "GENAI_BACKEND", "GENIE_MODEL_NAME") {
+		t.Setenv(key, "")
+	}
+}
+
+func TestSetupWizard(t *testing.T) {
+	clearBackendEnv(t)
+	var out bytes.Buffer
+	wizard := newSetupWizard(strings.NewReader(strings.Join([]string{
+		"9", "openai", "sk-wrong", "", "y",
+		"anthropic", "sk-right", "claude-haiku-4-5",
+	}, "\n")+"\n"), &out)
+
+	wizard.credentials = nil
+	var checked []string
+	wizard.verify = func(ctx context.Context, backend setupBackend, model string) error {
+		checked = append(checked, backend.Name+"/"+model)
+		if config.NewConfigManager().GetStringWithDefault(backend.KeyVar, "") == "sk-wrong" {
+			return errors.New("401 unauthorized")
+		}
+		return nil
+	}
+	var saved map[string]string
+	wizard.save = func(values map[string]string) error {
+		saved = values
+		return nil
+	}
+
+	require.NoError(t, wizard.run(context.Background()))
+
+	assert.Equal(t, []string{"openai/gpt-5-mini", "anthropic/claude-haiku-4-5"}, checked)
+	assert.Contains(t, out.String(), "Pick a number from 1 to")
+	assert.Contains(t, out.String(), "The test call failed: 401 unauthorized")
+	assert.Contains(t, out.String(), "Connected.")
+	assert.Equal(t, map[string]string{
+		"GENIE_LLM_PROVIDER": "anthropic",
+		"ANTHROPIC_API_KEY":  "sk-right",
+		"GENIE_MODEL_NAME":   "claude-haiku-4-5",
+	}, saved)
+}
+
+func TestSetupWizard_KeepsSettingsThatFailTheCheck(t *testing.T) {
+	clearBackendEnv(t)
+	wizard := newSetupWizard(strings.NewReader("ollama\nllama3.1\nn\nn\n"), &bytes.Buffer{})
+	wizard.credentials = nil
+	wizard.verify = func(ctx context.Context, backend setupBackend, model string) error {
+		return errors.New("connection refused")
+	}
+	wizard.save = func(values map[string]string) error {
+		t.Fatal("declined settings were saved")
+		return nil
+	}
+
+	// Declining both retrying and saving cancels
+	err := wizard.run(context.Background())
+	assert.EqualError(t, err, "setup cancelled: settings not saved")
+
+	wizard = newSetupWizard(strings.NewReader("ollama\nllama3.1\nn\ny\n"), &bytes.Buffer{})
+	wizard.credentials = nil
+	wizard.verify = func(ctx context.Context, backend setupBackend, model string) error {
+		return errors.New("connection refused")
+	}
+	var saved map[string]string
+	wizard.save = func(values map[string]string) error {
+		saved = values
+		return nil
+	}
+	require.NoError(t, wizard.run(context.Background()))
+	assert.Equal(t, map[string]string{"GENIE_LLM_PROVIDER": "ollama", "GENIE_MODEL_NAME": "llama3.1"}, saved)
+}
+
+func TestSetupWizard_StoresKeyInKeychain(t *testing.T) {
+	clearBackendEnv(t)
+	t.Setenv("HOME", t.TempDir())
+	store, err := credentials.NewFileStore(t.TempDir()+"/credentials.enc", "passphrase")
+	require.NoError(t, err)
+
+	wizard := newSetupWizard(strings.NewReader("gemini\nAIza-key\n\n\n"), &bytes.Buffer{})
+	wizard.credentials = func() (credentials.Store, error) { return store, nil }
+	wizard.verify = func(ctx context.Context, backend setupBackend, model string) error { return nil }
+	var saved map[string]string
+	wizard.save = func(values map[string]string) error {
+		saved = values
+		return nil
+	}
+	require.NoError(t, wizard.run(context.Background()))
+
+	assert.Equal(t, "keychain:gemini", saved["GEMINI_API_KEY"])
+	secret, err := store.Get("gemini")
+	require.NoError(t, err)
+	assert.Equal(t, "AIza-key", secret)
+}
+
+func TestBackendConfigured(t *testing.T) {
+	clearBackendEnv(t)
+	cfg := config.NewConfigManager()
+	assert.False(t, backendConfigured(cfg))
+
+	t.Setenv("GENIE_LLM_PROVIDER", "ollama")
+	assert.True(t, backendConfigured(cfg))
+
+	t.Setenv("GENIE_LLM_PROVIDER", "openai")
+	assert.False(t, backendConfigured(cfg))
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	assert.True(t, backendConfigured(cfg))
+
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("GENAI_BACKEND", "mock")
+	assert.True(t, backendConfigured(cfg))
+}
@@ -3,12 +3,17 @@ package cli
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/mattn/go-isatty"
 )
 
+// defaultStdinLimit caps how much piped input ask attaches to a prompt
+const defaultStdinLimit = 256 * 1024
+
 // hasStdinInput checks if data is available from stdin (pipe or redirect)
 func hasStdinInput() bool {
 	return !isatty.IsTerminal(os.Stdin.Fd())
@@ -36,3 +41,31 @@ func readStdinInput() (string, error) {
 
 	return result, nil
 }
+
+// readLimited reads at most limit bytes from r and reports whether there
+// was more. Truncated input is cut at the last complete line when there
+// is one, and never inside a UTF-8 sequence.
+func readLimited(r io.Reader, limit int) (string, bool, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	if len(data) <= limit {
+		return strings.TrimSuffix(string(data), "\n"), false, nil
+	}
+
+	data = data[:limit]
+	if i := strings.LastIndexByte(string(data), '\n'); i > 0 {
+		data = data[:i]
+	}
+	for len(data) > 0 && !utf8.Valid(data) {
+		data = data[:len(data)-1]
+	}
+	return string(data), true, nil
+}
+
+// stdinTruncationNotice tells the model, and the user on stderr, that
+// piped input was cut short
+func stdinTruncationNotice(kept, limit int) string {
+	return fmt.Sprintf("[stdin truncated: only the first %d bytes are included (limit %d bytes)]", kept, limit)
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/trust"
+	"github.com/mattn/go-isatty"
+)
+
+// workspaceTrust remembers this run's trust decision, so commands that read
+// project configuration before Genie starts don't ask twice.
+var workspaceTrust *bool
+
+// decideWorkspaceTrust returns whether project-local configuration may load
+// this run, asking about unknown workspaces the first time it is called.
+func decideWorkspaceTrust() bool {
+	if workspaceTrust == nil {
+		trusted := workspaceTrusted(openTrustStore(os.Stderr), trustDirs(workingDir), trustNow, os.Stdin, os.Stderr, isInteractiveTerminal())
+		workspaceTrust = &trusted
+	}
+	return *workspaceTrust
+}
+
+// openTrustStore returns the trust store in ~/.genie, or nil after warning
+// on out when it cannot be opened.
+func openTrustStore(out io.Writer) *trust.Store {
+	store, err := trust.DefaultStore()
+	if err != nil {
+		fmt.Fprintf(out, "Warning: %v; project configuration will be treated as untrusted.\n", err)
+		return nil
+	}
+	return store
+}
+
+// workspaceTrusted decides whether project-local configuration in the
+// directories Genie reads from (the launch directory and --cwd) may load.
+// Directories without any such configuration, and the home directory, need
+// no decision. Unknown directories are asked about when a terminal is
+// attached and otherwise treated as untrusted, as is every directory with
+// configuration when store is nil.
+func workspaceTrusted(store *trust.Store, dirs []string, trustNow bool, in io.Reader, out io.Writer, interactive bool) bool {
+	reader := bufio.NewReader(in)
+	trusted := true
+	for _, dir := range dirs {
+		if trust.IsHomeDir(dir) {
+			continue
+		}
+		found := trust.ProjectConfig(dir)
+		if len(found) == 0 {
+			continue
+		}
+
+		if trustNow {
+			if store != nil {
+				if err := store.Set(dir, true); err != nil {
+					fmt.Fprintf(out, "Warning: %v\n", err)
+				}
+			}
+			continue
+		}
+		if store == nil {
+			trusted = false
+			continue
+		}
+
+		decision, known, err := store.Lookup(dir)
+		if err != nil {
+			fmt.Fprintf(out, "Warning: %v\n", err)
+		}
+		if known {
+			trusted = trusted && decision.Trusted
+			continue
+		}
+
+		if !interactive {
+			fmt.Fprintf(out, "Workspace %s is not trusted; ignoring its %s. Run with --trust-workspace to trust it.\n", dir, strings.Join(found, ", "))
+			trusted = false
+			continue
+		}
+
+		answer := askWorkspaceTrust(reader, out, dir, found)
+		if err := store.Set(dir, answer); err != nil {
+			fmt.Fprintf(out, "Warning: %v\n", err)
+		}
+		trusted = trusted && answer
+	}
+	return trusted
+}
+
+func askWorkspaceTrust(reader *bufio.Reader, out io.Writer, dir string, found []string) bool {
+	fmt.Fprintf(out, "Do you trust this workspace?\n  %s\n\n", dir)
+	fmt.Fprintf(out, "It contains project configuration Genie would load: %s.\n", strings.Join(found, ", "))
+	fmt.Fprintln(out, "Personas, skills and commands can steer the model, and .mcp.json, hooks and other settings can run programs.")
+	fmt.Fprint(out, "Trust it? [y/N] ")
+
+	line, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	default:
+		fmt.Fprintln(out, "Not trusted: only your user and built-in configuration will load.")
+		return false
+	}
+}
+
+// trustDirs returns the distinct absolute directories project configuration
+// is read from.
+func trustDirs(workingDir string) []string {
+	var dirs []string
+	if cwd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, cwd)
+	}
+	if workingDir != "" {
+		if abs, err := filepath.Abs(workingDir); err == nil && (len(dirs) == 0 || abs != dirs[0]) {
+			dirs = append(dirs, abs)
+		}
+	}
+	return dirs
+}
+
+// isInteractiveTerminal reports whether the user can answer a prompt.
+func isInteractiveTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stderr.Fd())
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/trust"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWorkspaceWithConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".genie", "commands"), 0o755))
+	return dir
+}
+
+func TestWorkspaceTrusted_AsksOnceAndRemembers(t *testing.T) {
+	store := trust.NewStore(filepath.Join(t.TempDir(), trust.FileName))
+	dir := newWorkspaceWithConfig(t)
+
+	var out bytes.Buffer
+	assert.True(t, workspaceTrusted(store, []string{dir}, false, strings.NewReader("y\n"), &out, true))
+	assert.Contains(t, out.String(), "Do you trust this workspace?")
+	assert.Contains(t, out.String(), filepath.Join(".genie", "commands"))
+
+	// The decision is remembered: no prompt, even without a terminal
+	out.Reset()
+	assert.True(t, workspaceTrusted(store, []string{dir}, false, strings.NewReader(""), &out, false))
+	assert.Empty(t, out.String())
+}
+
+func TestWorkspaceTrusted_DeclineAndNonInteractive(t *testing.T) {
+	store := trust.NewStore(filepath.Join(t.TempDir(), trust.FileName))
+
+	declined := newWorkspaceWithConfig(t)
+	var out bytes.Buffer
+	assert.False(t, workspaceTrusted(store, []string{declined}, false, strings.NewReader("\n"), &out, true))
+
+	unknown := newWorkspaceWithConfig(t)
+	out.Reset()
+	assert.False(t, workspaceTrusted(store, []string{unknown}, false, strings.NewReader(""), &out, false))
+	assert.Contains(t, out.String(), "--trust-workspace")
+	_, known, err := store.Lookup(unknown)
+	require.NoError(t, err)
+	assert.False(t, known, "non-interactive runs don't record a decision")
+
+	// --trust-workspace trusts and records it
+	assert.True(t, workspaceTrusted(store, []string{unknown}, true, strings.NewReader(""), &out, false))
+	decision, known, err := store.Lookup(unknown)
+	require.NoError(t, err)
+	assert.True(t, known && decision.Trusted)
+}
+
+func TestWorkspaceTrusted_NoProjectConfigNeedsNoDecision(t *testing.T) {
+	store := trust.NewStore(filepath.Join(t.TempDir(), trust.FileName))
+	var out bytes.Buffer
+	assert.True(t, workspaceTrusted(store, []string{t.TempDir()}, false, strings.NewReader(""), &out, false))
+	assert.Empty(t, out.String())
+}
+
+func TestWorkspaceTrusted_WithoutStoreIsUntrusted(t *testing.T) {
+	var out bytes.Buffer
+	assert.False(t, workspaceTrusted(nil, []string{newWorkspaceWithConfig(t)}, false, strings.NewReader("y\n"), &out, true))
+	assert.True(t, workspaceTrusted(nil, []string{t.TempDir()}, false, strings.NewReader(""), &out, true))
+	assert.True(t, workspaceTrusted(nil, []string{newWorkspaceWithConfig(t)}, true, strings.NewReader(""), &out, false))
+}
+
+func TestWorkspaceTrusted_AsksAboutLifecycleHooks(t *testing.T) {
+	store := trust.NewStore(filepath.Join(t.TempDir(), trust.FileName))
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".genie"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".genie", "hooks.yaml"), []byte("session_start:\n  - run: ./setup.sh\n"), 0o644))
+
+	var out bytes.Buffer
+	assert.False(t, workspaceTrusted(store, []string{dir}, false, strings.NewReader("n\n"), &out, true))
+	assert.Contains(t, out.String(), "Do you trust this workspace?")
+	assert.Contains(t, out.String(), filepath.Join(".genie", "hooks.yaml"))
+}
@@ -24,11 +24,22 @@ func newUpdateCommand() *cobra.Command {
 		Short: "Update genie to the latest version",
 		Long: `Update genie to the latest version from GitHub releases.
 
+The archive for this platform is checked against the SHA-256 sums the
+release publishes in checksums.txt, and the binary is only replaced once the
+new one is fully written, so a failed update leaves genie as it was.
+
+In the TUI, the status bar says when a newer release is out. Turn that off
+with ':config --global update-check off' or GENIE_UPDATE_CHECK=false.
+
 Examples:
   genie update                    # Update to latest version
   genie update --check            # Check for updates without updating
   genie update --version v1.2.3   # Update to specific version
   genie update --force            # Force update even if same version`,
+		// Updating must not need a configured LLM backend
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
 		RunE: runUpdateCommand,
 	}
 
@@ -41,7 +52,7 @@ Examples:
 }
 
 func runUpdateCommand(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Create updater
 	updater, err := update.NewUpdater()
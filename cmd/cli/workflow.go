@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+// chatAndWait sends one chat turn and blocks until its response arrives.
+// Workflow commands use it to get a single answer from the model.
+func chatAndWait(ctx context.Context, g genie.Genie, message string, opts ...genie.ChatOption) (string, error) {
+	requestID := uuid.NewString()
+	responses := make(chan events.ChatResponseEvent, 1)
+	defer events.SubscribeTo(g.GetEventBus(), func(e events.ChatResponseEvent) {
+		if e.RequestID == requestID {
+			responses <- e
+		}
+	})()
+
+	if err := g.Chat(ctx, message, append(opts, genie.WithRequestID(requestID))...); err != nil {
+		return "", err
+	}
+	select {
+	case response := <-responses:
+		if response.Error != nil {
+			return "", response.Error
+		}
+		return strings.TrimSpace(response.Response), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// runGit runs git in dir, feeding it stdin when non-empty, and returns its
+// standard output. Failures carry git's own error message.
+func runGit(ctx context.Context, dir, stdin string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return stdout.String(), fmt.Errorf("git %s: %s", args[0], message)
+	}
+	return stdout.String(), nil
+}
+
+// stripCodeFence removes a markdown code fence the model may wrap a
+// requested plain-text answer in.
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[len(lines)-1]) != "```" {
+		return text
+	}
+	return strings.TrimSpace(strings.Join(lines[1:len(lines)-1], "\n"))
+}
+
+// truncateForPrompt caps text at limit bytes, noting how much was cut.
+func truncateForPrompt(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n... (truncated: %d of %d bytes shown)", text[:cut], cut, len(text))
+}
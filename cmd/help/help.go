@@ -0,0 +1,95 @@
+// Package help holds Genie's extended help: topic pages on personas,
+// tools, policies, themes and serving, written in Markdown. The CLI shows
+// them with genie help <topic> and exports them as man pages; the TUI
+// shows them with :help <topic>.
+package help
+
+import (
+	"embed"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+)
+
+//go:embed topics/*.md
+var topicFiles embed.FS
+
+// order is how topics are listed
+var order = []string{"personas", "tools", "policies", "themes", "serving"}
+
+// Topic is a help page.
+type Topic struct {
+	// Name is what genie help and :help take, e.g. "personas"
+	Name string
+	// Title is the page's heading
+	Title string
+	// Summary is the page's opening sentence
+	Summary string
+	// Markdown is the whole page
+	Markdown string
+}
+
+// Topics returns every help topic, in the order they are listed.
+func Topics() []Topic {
+	topics := make([]Topic, 0, len(order))
+	for _, name := range order {
+		if topic, ok := Lookup(name); ok {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// Lookup returns the topic called name, ignoring case.
+func Lookup(name string) (Topic, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	data, err := topicFiles.ReadFile("topics/" + name + ".md")
+	if err != nil {
+		return Topic{}, false
+	}
+	topic := Topic{Name: name, Markdown: string(data)}
+	topic.Title, topic.Summary = describe(topic.Markdown)
+	return topic, true
+}
+
+// describe returns a page's heading and the first sentence of its first
+// paragraph
+func describe(markdown string) (title, summary string) {
+	var paragraph []string
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case title == "" && strings.HasPrefix(line, "# "):
+			title = strings.TrimPrefix(line, "# ")
+		case line == "" || strings.HasPrefix(line, "#"):
+			if len(paragraph) > 0 {
+				return title, firstSentence(strings.Join(paragraph, " "))
+			}
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	return title, firstSentence(strings.Join(paragraph, " "))
+}
+
+func firstSentence(text string) string {
+	if i := strings.Index(text, ". "); i >= 0 {
+		return text[:i+1]
+	}
+	return text
+}
+
+// Render formats Markdown for a terminal width columns wide.
+func Render(markdown string, width int) (string, error) {
+	if width < 20 {
+		width = 80
+	}
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return "", err
+	}
+	return renderer.Render(markdown)
+}
@@ -0,0 +1,47 @@
+package help
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopics(t *testing.T) {
+	topics := Topics()
+	require.Len(t, topics, len(order), "every listed topic has a page")
+	for _, topic := range topics {
+		assert.NotEmpty(t, topic.Title, topic.Name)
+		assert.NotEmpty(t, topic.Summary, topic.Name)
+		assert.True(t, strings.HasSuffix(topic.Summary, "."), "summary of %s is a sentence: %q", topic.Name, topic.Summary)
+	}
+
+	topic, ok := Lookup(" Policies ")
+	require.True(t, ok)
+	assert.Equal(t, "policies", topic.Name)
+	assert.Equal(t, "Policies", topic.Title)
+	assert.Equal(t, "genie-policies", topic.ManName())
+
+	_, ok = Lookup("../help")
+	assert.False(t, ok)
+}
+
+func TestManPage(t *testing.T) {
+	markdown := "# Sample\n\nA sample page. It has `code` and **bold** text.\n\n" +
+		"## Options\n\n- `--flag`: does things\n- .dotted item\n\n" +
+		"```bash\ngenie --persona reviewer\n.not a request\n```\n\n### Details\n\nBack\\slash.\n"
+	date := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	page := ManPage("genie-sample", 7, "A sample page.", markdown, date)
+
+	assert.True(t, strings.HasPrefix(page, `.TH "GENIE-SAMPLE" "7" "2026-10-16" "genie `), page)
+	assert.Contains(t, page, ".SH NAME\ngenie\\-sample \\- A sample page\n")
+	assert.Contains(t, page, ".PP\nA sample page. It has \\fBcode\\fR and \\fBbold\\fR text.\n")
+	assert.Contains(t, page, ".SH \"OPTIONS\"\n.IP \\(bu 2\n\\fB\\-\\-flag\\fR: does things\n")
+	assert.Contains(t, page, ".IP \\(bu 2\n\\&.dotted item\n")
+	assert.Contains(t, page, ".RS 4\n.nf\ngenie \\-\\-persona reviewer\n\\&.not a request\n.fi\n.RE\n")
+	assert.Contains(t, page, ".SS \"Details\"\n.PP\nBack\\eslash.\n")
+	assert.NotContains(t, page, "# Sample")
+}
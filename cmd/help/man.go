@@ -0,0 +1,105 @@
+package help
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/version"
+)
+
+// ManName returns the topic's man page name, e.g. genie-personas.
+func (t Topic) ManName() string {
+	return "genie-" + t.Name
+}
+
+// ManPage returns the topic as a section 7 man page.
+func (t Topic) ManPage(date time.Time) string {
+	return ManPage(t.ManName(), 7, t.Summary, t.Markdown, date)
+}
+
+// ManPage converts a Markdown page to roff for man(1). The page's "# "
+// heading becomes the NAME section, "## " headings sections and "### "
+// headings subsections; paragraphs, bullet lists, fenced code blocks and
+// inline code or bold text are kept.
+func ManPage(name string, section int, summary, markdown string, date time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ".TH %q \"%d\" %q %q \"Genie Manual\"\n", strings.ToUpper(name), section, date.Format("2006-01-02"), "genie "+version.GetVersion())
+	sb.WriteString(".SH NAME\n")
+	fmt.Fprintf(&sb, "%s \\- %s\n", escapeRoff(name), inlineRoff(strings.TrimSuffix(summary, ".")))
+
+	inCode := false
+	inParagraph := false
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				sb.WriteString(".fi\n.RE\n")
+			} else {
+				sb.WriteString(".PP\n.RS 4\n.nf\n")
+			}
+			inCode = !inCode
+			inParagraph = false
+			continue
+		}
+		if inCode {
+			sb.WriteString(protectLine(escapeRoff(line)) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			inParagraph = false
+		case strings.HasPrefix(trimmed, "# "):
+			// The heading is the NAME section
+		case strings.HasPrefix(trimmed, "## "):
+			fmt.Fprintf(&sb, ".SH %q\n", strings.ToUpper(strings.TrimPrefix(trimmed, "## ")))
+			inParagraph = false
+		case strings.HasPrefix(trimmed, "### "):
+			fmt.Fprintf(&sb, ".SS %q\n", strings.TrimPrefix(trimmed, "### "))
+			inParagraph = false
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			sb.WriteString(".IP \\(bu 2\n")
+			sb.WriteString(protectLine(inlineRoff(trimmed[2:])) + "\n")
+			inParagraph = true
+		default:
+			if !inParagraph {
+				sb.WriteString(".PP\n")
+				inParagraph = true
+			}
+			sb.WriteString(protectLine(inlineRoff(trimmed)) + "\n")
+		}
+	}
+	if inCode {
+		sb.WriteString(".fi\n.RE\n")
+	}
+	return sb.String()
+}
+
+var (
+	inlineCode = regexp.MustCompile("`([^`]+)`")
+	boldText   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// inlineRoff escapes text and shows inline code and bold text in bold
+func inlineRoff(text string) string {
+	text = escapeRoff(text)
+	text = inlineCode.ReplaceAllString(text, `\fB$1\fR`)
+	return boldText.ReplaceAllString(text, `\fB$1\fR`)
+}
+
+// escapeRoff escapes the characters roff would interpret
+func escapeRoff(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	return strings.ReplaceAll(text, "-", `\-`)
+}
+
+// protectLine keeps a line that starts with a control character from
+// being read as a request
+func protectLine(line string) string {
+	if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+		return `\&` + line
+	}
+	return line
+}
@@ -5,9 +5,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// Entry is one command in the history file, with when and in which
+// session it was entered. Entries from files written before timestamps
+// were recorded have a zero Time and no SessionID.
+type Entry struct {
+	Command   string
+	Time      time.Time
+	SessionID string
+}
+
 // ChatHistory manages command history for TUI with persistent storage
 type ChatHistory interface {
 	AddCommand(command string)
@@ -23,9 +34,12 @@ type ChatHistory interface {
 type FileChatHistory struct {
 	filePath     string
 	commands     []string
+	entries      []Entry // Parallel to commands
 	maxSize      int
 	currentIndex int  // -1 means no selection (at end)
 	saveEnabled  bool // whether to save to disk
+	sessionID    string
+	userPath     string // Global history every command is also appended to
 }
 
 // NewChatHistory creates a new TUI chat history manager
@@ -39,29 +53,42 @@ func NewChatHistory(filePath string, saveEnabled bool) ChatHistory {
 	}
 }
 
+// SetSessionID records the session new commands are entered in
+func (h *FileChatHistory) SetSessionID(sessionID string) {
+	h.sessionID = sessionID
+}
+
+// SetUserHistoryPath makes every new command also go to the user-wide
+// history at path, so it can be searched from any project
+func (h *FileChatHistory) SetUserHistoryPath(path string) {
+	if path != h.filePath {
+		h.userPath = path
+	}
+}
+
+// SwitchFile replaces the history with the one kept in the file at path,
+// which new commands are then saved to
+func (h *FileChatHistory) SwitchFile(path string) error {
+	if h.userPath == path {
+		h.userPath = ""
+	}
+	h.filePath = path
+	h.entries = nil
+	h.commands = make([]string, 0)
+	h.currentIndex = -1
+	return h.Load()
+}
+
 // AddCommand adds a command to history, avoiding duplicates and auto-saving
 func (h *FileChatHistory) AddCommand(command string) {
 	command = strings.TrimSpace(command)
 	if command == "" {
 		return
 	}
+	entry := Entry{Command: command, Time: time.Now(), SessionID: h.sessionID}
 
-	// Remove command if it already exists (avoid duplicates)
-	for i, existing := range h.commands {
-		if existing == command {
-			// Remove the existing occurrence
-			h.commands = append(h.commands[:i], h.commands[i+1:]...)
-			break
-		}
-	}
-
-	// Add command to the end
-	h.commands = append(h.commands, command)
-
-	// Trim to max size (keep last 50)
-	if len(h.commands) > h.maxSize {
-		h.commands = h.commands[len(h.commands)-h.maxSize:]
-	}
+	h.entries = appendEntry(h.entries, entry, h.maxSize)
+	h.commands = commandsOf(h.entries)
 
 	// Reset navigation after adding new command
 	h.currentIndex = -1
@@ -69,9 +96,63 @@ func (h *FileChatHistory) AddCommand(command string) {
 	// Auto-save after adding (if enabled)
 	if h.saveEnabled {
 		h.Save()
+		if h.userPath != "" {
+			_ = AppendEntry(h.userPath, entry, UserHistorySize)
+		}
 	}
 }
 
+// appendEntry adds entry to the end of entries, dropping an earlier entry
+// with the same command and keeping at most maxSize
+func appendEntry(entries []Entry, entry Entry, maxSize int) []Entry {
+	for i, existing := range entries {
+		if existing.Command == entry.Command {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+	entries = append(entries, entry)
+	if len(entries) > maxSize {
+		entries = entries[len(entries)-maxSize:]
+	}
+	return entries
+}
+
+func commandsOf(entries []Entry) []string {
+	commands := make([]string, len(entries))
+	for i, entry := range entries {
+		commands[i] = entry.Command
+	}
+	return commands
+}
+
+// formatEntry writes an entry as one history file line, zsh style:
+// ": <unix time>:<session id>;<escaped command>"
+func formatEntry(entry Entry) string {
+	if entry.Time.IsZero() {
+		return escapeForHistory(entry.Command)
+	}
+	return fmt.Sprintf(": %d:%s;%s", entry.Time.Unix(), entry.SessionID, escapeForHistory(entry.Command))
+}
+
+// parseEntry reads a history file line; lines without the timestamp
+// prefix are plain commands from older files
+func parseEntry(line string) Entry {
+	if rest, ok := strings.CutPrefix(line, ": "); ok {
+		if meta, escaped, ok := strings.Cut(rest, ";"); ok {
+			stamp, sessionID, _ := strings.Cut(meta, ":")
+			if unix, err := strconv.ParseInt(stamp, 10, 64); err == nil {
+				return Entry{
+					Command:   unescapeFromHistory(escaped),
+					Time:      time.Unix(unix, 0),
+					SessionID: sessionID,
+				}
+			}
+		}
+	}
+	return Entry{Command: unescapeFromHistory(line)}
+}
+
 // escapeForHistory escapes a command for storage in history file
 // Converts newlines to \n and backslashes to \\
 func escapeForHistory(command string) string {
@@ -128,65 +209,90 @@ func (h *FileChatHistory) Load() error {
 		return nil
 	}
 
-	file, err := os.Open(h.filePath)
+	entries, err := ReadEntries(h.filePath)
 	if err != nil {
-		return fmt.Errorf("failed to open history file: %w", err)
+		return err
+	}
+
+	// Trim to max size after loading
+	if len(entries) > h.maxSize {
+		entries = entries[len(entries)-h.maxSize:]
+	}
+	h.entries = entries
+	h.commands = commandsOf(entries)
+
+	return nil
+}
+
+// ReadEntries reads a history file, oldest entry first. A missing file
+// has no entries.
+func ReadEntries(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
 	}
 	defer file.Close()
 
-	h.commands = make([]string, 0)
+	var entries []Entry
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for scanner.Scan() {
-		escaped := strings.TrimSpace(scanner.Text())
-		if escaped != "" {
-			// Unescape the command when loading from file
-			command := unescapeFromHistory(escaped)
-			h.commands = append(h.commands, command)
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			entries = append(entries, parseEntry(line))
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read history file: %w", err)
+		return nil, fmt.Errorf("failed to read history file: %w", err)
 	}
-
-	// Trim to max size after loading
-	if len(h.commands) > h.maxSize {
-		h.commands = h.commands[len(h.commands)-h.maxSize:]
-	}
-
-	return nil
+	return entries, nil
 }
 
-// Save writes command history to file
-func (h *FileChatHistory) Save() error {
-	// If saving is disabled, skip writing
-	if !h.saveEnabled {
-		return nil
-	}
-
+// writeEntries replaces the history file at path with entries
+func writeEntries(path string, entries []Entry) error {
 	// Create directory if it doesn't exist
-	dir := filepath.Dir(h.filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("failed to create history directory: %w", err)
 	}
 
-	file, err := os.Create(h.filePath)
+	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create history file: %w", err)
 	}
 	defer file.Close()
 
-	for _, command := range h.commands {
-		// Escape the command before writing to file
-		escaped := escapeForHistory(command)
-		if _, err := fmt.Fprintln(file, escaped); err != nil {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintln(file, formatEntry(entry)); err != nil {
 			return fmt.Errorf("failed to write command to history file: %w", err)
 		}
 	}
-
 	return nil
 }
 
+// AppendEntry adds entry to the history file at path, keeping at most
+// maxSize entries and no duplicate commands
+func AppendEntry(path string, entry Entry, maxSize int) error {
+	entries, err := ReadEntries(path)
+	if err != nil {
+		return err
+	}
+	return writeEntries(path, appendEntry(entries, entry, maxSize))
+}
+
+// Save writes command history to file
+func (h *FileChatHistory) Save() error {
+	// If saving is disabled, skip writing
+	if !h.saveEnabled {
+		return nil
+	}
+
+	return writeEntries(h.filePath, h.entries)
+}
+
 // NavigateNext moves forward in history (towards newer commands)
 func (h *FileChatHistory) NavigateNext() string {
 	if len(h.commands) == 0 {
@@ -0,0 +1,84 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UserHistorySize is how many commands the user-wide history keeps
+const UserHistorySize = 1000
+
+// History scopes
+const (
+	ScopeProject = "project" // .genie/history where Genie was started
+	ScopeUser    = "user"    // ~/.genie/history, shared by every project
+)
+
+// ProjectHistoryPath returns the history file of the project at dir
+func ProjectHistoryPath(dir string) string {
+	return filepath.Join(dir, ".genie", "history")
+}
+
+// UserHistoryPath returns the user-wide history file
+func UserHistoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return ProjectHistoryPath(home), nil
+}
+
+// Scope names a history file to search
+type Scope struct {
+	Name string
+	Path string
+}
+
+// Match is a history entry that matched a search, and the scope it was
+// found in
+type Match struct {
+	Entry
+	Scope string
+}
+
+// Search returns the entries containing term, ignoring case, newest
+// first. A command found in several scopes is reported once, from the
+// first scope it appears in.
+func Search(term string, scopes ...Scope) ([]Match, error) {
+	term = strings.ToLower(term)
+	seen := make(map[string]bool)
+	var matches []Match
+	for _, scope := range scopes {
+		entries, err := ReadEntries(scope.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if seen[entry.Command] || !strings.Contains(strings.ToLower(entry.Command), term) {
+				continue
+			}
+			seen[entry.Command] = true
+			matches = append(matches, Match{Entry: entry, Scope: scope.Name})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Time.After(matches[j].Time)
+	})
+	return matches, nil
+}
+
+// FindPrevious returns the index of the newest command at or before from
+// that contains query, ignoring case, or -1. commands are oldest first, as
+// GetHistory returns them.
+func FindPrevious(commands []string, query string, from int) int {
+	query = strings.ToLower(query)
+	for i := min(from, len(commands)-1); i >= 0; i-- {
+		if strings.Contains(strings.ToLower(commands[i]), query) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,84 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntriesRoundTripWithTimestampAndSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".genie", "history")
+	history := NewChatHistory(path, true).(*FileChatHistory)
+	history.SetSessionID("session-1")
+	history.AddCommand("first line\nsecond line")
+
+	entries, err := ReadEntries(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "first line\nsecond line", entries[0].Command)
+	assert.Equal(t, "session-1", entries[0].SessionID)
+	assert.WithinDuration(t, time.Now(), entries[0].Time, 2*time.Second)
+}
+
+func TestReadEntriesAcceptsPlainLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	require.NoError(t, os.WriteFile(path, []byte("old command\n: 1700000000:abc;new\\ncommand\n: not a stamp\n"), 0644))
+
+	entries, err := ReadEntries(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, Entry{Command: "old command"}, entries[0])
+	assert.Equal(t, Entry{Command: "new\ncommand", Time: time.Unix(1700000000, 0), SessionID: "abc"}, entries[1])
+	assert.Equal(t, ": not a stamp", entries[2].Command)
+
+	entries, err = ReadEntries(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAddCommandAlsoWritesUserHistory(t *testing.T) {
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "user", "history")
+	history := NewChatHistory(filepath.Join(dir, "project", "history"), true).(*FileChatHistory)
+	history.SetUserHistoryPath(userPath)
+	history.AddCommand("hello")
+	history.AddCommand("world")
+	history.AddCommand("hello")
+
+	entries, err := ReadEntries(userPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"world", "hello"}, commandsOf(entries))
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	project := Scope{Name: ScopeProject, Path: filepath.Join(dir, "project")}
+	user := Scope{Name: ScopeUser, Path: filepath.Join(dir, "user")}
+	base := time.Unix(1700000000, 0)
+	require.NoError(t, AppendEntry(project.Path, Entry{Command: "fix the Migration", Time: base.Add(time.Minute)}, 10))
+	require.NoError(t, AppendEntry(user.Path, Entry{Command: "write a migration", Time: base.Add(2 * time.Minute)}, 10))
+	require.NoError(t, AppendEntry(user.Path, Entry{Command: "fix the Migration", Time: base.Add(time.Minute)}, 10))
+	require.NoError(t, AppendEntry(user.Path, Entry{Command: "unrelated", Time: base.Add(3 * time.Minute)}, 10))
+
+	matches, err := Search("MIGRATION", project, user)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "write a migration", matches[0].Command)
+	assert.Equal(t, ScopeUser, matches[0].Scope)
+	assert.Equal(t, "fix the Migration", matches[1].Command)
+	assert.Equal(t, ScopeProject, matches[1].Scope, "a command in both scopes is reported once")
+}
+
+func TestFindPrevious(t *testing.T) {
+	commands := []string{"git status", "run tests", "Git log"}
+
+	assert.Equal(t, 2, FindPrevious(commands, "git", 2))
+	assert.Equal(t, 0, FindPrevious(commands, "git", 1))
+	assert.Equal(t, -1, FindPrevious(commands, "git", -1))
+	assert.Equal(t, 2, FindPrevious(commands, "", 10))
+	assert.Equal(t, -1, FindPrevious(commands, "deploy", 2))
+}
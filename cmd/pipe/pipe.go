@@ -0,0 +1,559 @@
+// Package pipe implements genie --pipe: a JSON-RPC 2.0 protocol over stdio
+// where every request, response and notification is one JSON object per
+// line. It lets editor plugins drive Genie as a child process without
+// rendering a TUI.
+//
+// Client requests:
+//
+//	chat       {"message": "...", "stream": true, "session": "..."}  -> {"requestId", "response"} when the turn ends
+//	cancel     {"requestId": "..."}                -> {"cancelled": n}; no requestId cancels every chat
+//	confirm    {"executionId": "...", "confirmed": true, "edited": "...", "alwaysAllow": false, "feedback": "..."}; edited optionally replaces the command or editable content, alwaysAllow approves a tool for the session, feedback tells the model why a request was denied
+//	listTools  {"session": "..."}                  -> {"tools": [{"name", "description"}]}
+//
+// Server notifications:
+//
+//	chatStarted          {"id", "requestId"}  the chat request's id and the requestId to cancel it with
+//	chunk                {"requestId", "text"}
+//	toolExecuted         {"executionId", "toolName", "success", "cancelled", "timedOut", "message"}
+//	confirmationRequest  {"executionId", "kind": "tool"|"content", ...}; answer with confirm
+//
+// A request naming a session goes to that named session's Genie, started in
+// its working directory on first use (see WithSessions); without one it goes
+// to the Genie the server was created with. Notifications from a named
+// session carry its name in "session".
+package pipe
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+// JSON-RPC error codes. The request-cancelled code follows LSP.
+const (
+	codeParseError       = -32700
+	codeInvalidRequest   = -32600
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeChatFailed       = -32000
+	codeRequestCancelled = -32800
+)
+
+// Confirmation kinds, telling the client which fields of a
+// confirmationRequest are set.
+const (
+	kindTool    = "tool"
+	kindContent = "content"
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type chatParams struct {
+	Message string `json:"message"`
+	Stream  *bool  `json:"stream,omitempty"`
+	Session string `json:"session,omitempty"`
+}
+
+type sessionParams struct {
+	Session string `json:"session,omitempty"`
+}
+
+type cancelParams struct {
+	RequestID string `json:"requestId,omitempty"`
+}
+
+type confirmParams struct {
+	ExecutionID string `json:"executionId"`
+	Confirmed   bool   `json:"confirmed"`
+	Edited      string `json:"edited,omitempty"`
+	AlwaysAllow bool   `json:"alwaysAllow,omitempty"`
+	Feedback    string `json:"feedback,omitempty"`
+}
+
+// ToolInfo describes one tool in a listTools result.
+type ToolInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// chatCall is a chat request waiting for its turn to end.
+type chatCall struct {
+	id     json.RawMessage
+	cancel context.CancelFunc
+}
+
+// pendingConfirmation is a confirmation the client has yet to answer and
+// the bus of the Genie waiting for it.
+type pendingConfirmation struct {
+	kind string
+	bus  events.EventBus
+}
+
+// Opener starts the Genie of a named session. release is called once the
+// server stops serving it.
+type Opener func(name string) (g genie.Genie, release func(), err error)
+
+// instance is a Genie the server routes requests to; name is empty for the
+// one the server was created with.
+type instance struct {
+	name        string
+	genie       genie.Genie
+	bus         events.EventBus
+	unsubscribe func()
+	release     func()
+}
+
+// Server answers JSON-RPC requests for one Genie instance and, with
+// WithSessions, for named sessions.
+type Server struct {
+	open Opener
+
+	outMu sync.Mutex
+	out   *json.Encoder
+
+	mu        sync.Mutex
+	instances map[string]*instance           // by session name
+	chats     map[string]*chatCall           // by Genie request ID
+	pending   map[string]pendingConfirmation // by execution ID
+	closed    bool                           // input ended: nobody is left to answer confirmations
+	active    sync.WaitGroup
+}
+
+// NewServer creates a server writing responses and notifications to out.
+// Genie must already be started.
+func NewServer(g genie.Genie, out io.Writer) *Server {
+	return &Server{
+		out:       json.NewEncoder(out),
+		instances: map[string]*instance{"": {genie: g, bus: g.GetEventBus()}},
+		chats:     make(map[string]*chatCall),
+		pending:   make(map[string]pendingConfirmation),
+	}
+}
+
+// WithSessions lets requests name a session, whose Genie open starts on
+// the first request naming it. It returns s.
+func (s *Server) WithSessions(open Opener) *Server {
+	s.open = open
+	return s
+}
+
+// Serve reads requests from in until it ends or ctx is cancelled. When the
+// input ends, chats still running are allowed to finish and any
+// confirmation they ask for is denied.
+func (s *Server) Serve(ctx context.Context, in io.Reader) error {
+	s.subscribe(s.instances[""])
+	defer s.closeInstances()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case line := <-lines:
+			s.handleLine(ctx, line)
+		case err := <-readErr:
+			s.finish()
+			return err
+		case <-ctx.Done():
+			s.cancelChats("")
+			s.finish()
+			return ctx.Err()
+		}
+	}
+}
+
+// finish denies outstanding confirmations and waits for running chats.
+func (s *Server) finish() {
+	s.mu.Lock()
+	s.closed = true
+	pending := s.pending
+	s.pending = make(map[string]pendingConfirmation)
+	s.mu.Unlock()
+
+	for executionID, confirmation := range pending {
+		s.publishConfirmation(executionID, confirmation, confirmParams{})
+	}
+	s.active.Wait()
+}
+
+// closeInstances stops listening to every Genie and releases the named
+// sessions' ones.
+func (s *Server) closeInstances() {
+	s.mu.Lock()
+	instances := s.instances
+	s.instances = map[string]*instance{}
+	s.mu.Unlock()
+
+	for _, inst := range instances {
+		if inst.unsubscribe != nil {
+			inst.unsubscribe()
+		}
+		if inst.release != nil {
+			inst.release()
+		}
+	}
+}
+
+// instance returns the Genie requests naming session go to, starting it on
+// first use.
+func (s *Server) instance(session string) (*instance, error) {
+	s.mu.Lock()
+	inst, ok := s.instances[session]
+	s.mu.Unlock()
+	if ok {
+		return inst, nil
+	}
+	if s.open == nil {
+		return nil, fmt.Errorf("unknown session %q: this server serves no named sessions", session)
+	}
+
+	g, release, err := s.open(session)
+	if err != nil {
+		return nil, err
+	}
+	inst = &instance{name: session, genie: g, bus: g.GetEventBus(), release: release}
+	s.subscribe(inst)
+	s.mu.Lock()
+	s.instances[session] = inst
+	s.mu.Unlock()
+	return inst, nil
+}
+
+// subscribe forwards inst's events to the client.
+func (s *Server) subscribe(inst *instance) {
+	unsubscribers := []func(){
+		inst.bus.Subscribe("chat.chunk", func(event interface{}) {
+			switch e := event.(type) {
+			case events.ChatChunkEvent:
+				if e.Chunk != nil && e.Chunk.Text != "" && s.isOwnChat(e.RequestID) {
+					s.notify("chunk", inst.params(map[string]any{"requestId": e.RequestID, "text": e.Chunk.Text}))
+				}
+			case chan struct{}:
+				close(e) // flush marker, see chatDone
+			}
+		}),
+		events.SubscribeTo(inst.bus, func(e events.ChatResponseEvent) {
+			s.chatDone(inst, e)
+		}),
+		events.SubscribeTo(inst.bus, func(e events.ToolExecutedEvent) {
+			s.notify("toolExecuted", inst.params(map[string]any{
+				"executionId": e.ExecutionID,
+				"toolName":    e.ToolName,
+				"success":     e.Success,
+				"cancelled":   e.Cancelled,
+				"timedOut":    e.TimedOut,
+				"message":     e.Message,
+			}))
+		}),
+		events.SubscribeTo(inst.bus, func(e events.ToolConfirmationRequest) {
+			confirmation := pendingConfirmation{kind: kindTool, bus: inst.bus}
+			if !s.addPending(e.ExecutionID, confirmation) {
+				s.publishConfirmation(e.ExecutionID, confirmation, confirmParams{})
+				return
+			}
+			s.notify("confirmationRequest", inst.params(map[string]any{
+				"executionId": e.ExecutionID,
+				"kind":        kindTool,
+				"toolName":    e.ToolName,
+				"command":     e.Command,
+				"message":     e.Message,
+			}))
+		}),
+		events.SubscribeTo(inst.bus, func(e events.UserConfirmationRequest) {
+			confirmation := pendingConfirmation{kind: kindContent, bus: inst.bus}
+			if !s.addPending(e.ExecutionID, confirmation) {
+				s.publishConfirmation(e.ExecutionID, confirmation, confirmParams{})
+				return
+			}
+			s.notify("confirmationRequest", inst.params(map[string]any{
+				"executionId": e.ExecutionID,
+				"kind":        kindContent,
+				"title":       e.Title,
+				"content":     e.Content,
+				"contentType": e.ContentType,
+				"filePath":    e.FilePath,
+				"message":     e.Message,
+				"editable":    e.Editable,
+			}))
+		}),
+	}
+	inst.unsubscribe = func() {
+		for _, unsubscribe := range unsubscribers {
+			unsubscribe()
+		}
+	}
+}
+
+// params adds the session's name to notification params
+func (inst *instance) params(params map[string]any) map[string]any {
+	if inst.name != "" {
+		params["session"] = inst.name
+	}
+	return params
+}
+
+func (s *Server) handleLine(ctx context.Context, line []byte) {
+	if len(line) == 0 {
+		return
+	}
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		s.reply(nil, nil, &rpcError{Code: codeParseError, Message: err.Error()})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		s.reply(req.ID, nil, &rpcError{Code: codeInvalidRequest, Message: `expected "jsonrpc": "2.0" and a method`})
+		return
+	}
+
+	switch req.Method {
+	case "chat":
+		s.handleChat(ctx, req)
+	case "cancel":
+		var params cancelParams
+		if !s.decodeParams(req, &params) {
+			return
+		}
+		s.reply(req.ID, map[string]int{"cancelled": s.cancelChats(params.RequestID)}, nil)
+	case "confirm":
+		s.handleConfirm(req)
+	case "listTools":
+		s.handleListTools(req)
+	default:
+		s.reply(req.ID, nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)})
+	}
+}
+
+func (s *Server) decodeParams(req request, params any) bool {
+	if len(req.Params) == 0 {
+		return true
+	}
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		s.reply(req.ID, nil, &rpcError{Code: codeInvalidParams, Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleChat(ctx context.Context, req request) {
+	var params chatParams
+	if !s.decodeParams(req, &params) {
+		return
+	}
+	if params.Message == "" {
+		s.reply(req.ID, nil, &rpcError{Code: codeInvalidParams, Message: "message is required"})
+		return
+	}
+	inst, err := s.instance(params.Session)
+	if err != nil {
+		s.reply(req.ID, nil, &rpcError{Code: codeInvalidParams, Message: err.Error()})
+		return
+	}
+
+	requestID := uuid.NewString()
+	chatCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.chats[requestID] = &chatCall{id: req.ID, cancel: cancel}
+	s.mu.Unlock()
+	s.active.Add(1)
+
+	s.notify("chatStarted", inst.params(map[string]any{"id": req.ID, "requestId": requestID}))
+	stream := params.Stream == nil || *params.Stream
+	if err := inst.genie.Chat(chatCtx, params.Message, genie.WithRequestID(requestID), genie.WithStreaming(stream)); err != nil {
+		s.takeChat(requestID)
+		cancel()
+		s.active.Done()
+		s.reply(req.ID, nil, &rpcError{Code: codeChatFailed, Message: err.Error()})
+	}
+}
+
+// chatDone answers the chat request a response event of inst belongs to.
+func (s *Server) chatDone(inst *instance, e events.ChatResponseEvent) {
+	if !s.isOwnChat(e.RequestID) {
+		return
+	}
+
+	// Chunks travel on their own topic; wait for the ones already
+	// published so the client sees them before the result.
+	flushed := make(chan struct{})
+	inst.bus.Publish("chat.chunk", flushed)
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+	}
+
+	call := s.takeChat(e.RequestID)
+	defer s.active.Done()
+	defer call.cancel()
+
+	switch {
+	case errors.Is(e.Error, context.Canceled):
+		s.reply(call.id, nil, &rpcError{Code: codeRequestCancelled, Message: "chat cancelled"})
+	case e.Error != nil:
+		s.reply(call.id, nil, &rpcError{Code: codeChatFailed, Message: e.Error.Error()})
+	default:
+		s.reply(call.id, map[string]string{"requestId": e.RequestID, "response": e.Response}, nil)
+	}
+}
+
+func (s *Server) handleConfirm(req request) {
+	var params confirmParams
+	if !s.decodeParams(req, &params) {
+		return
+	}
+	s.mu.Lock()
+	confirmation, ok := s.pending[params.ExecutionID]
+	delete(s.pending, params.ExecutionID)
+	s.mu.Unlock()
+	if !ok {
+		s.reply(req.ID, nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("no confirmation pending for execution %q", params.ExecutionID)})
+		return
+	}
+	s.publishConfirmation(params.ExecutionID, confirmation, params)
+	s.reply(req.ID, map[string]bool{"confirmed": params.Confirmed}, nil)
+}
+
+func (s *Server) handleListTools(req request) {
+	var params sessionParams
+	if !s.decodeParams(req, &params) {
+		return
+	}
+	inst, err := s.instance(params.Session)
+	if err != nil {
+		s.reply(req.ID, nil, &rpcError{Code: codeInvalidParams, Message: err.Error()})
+		return
+	}
+	registry, err := inst.genie.GetToolsRegistry()
+	if err != nil {
+		s.reply(req.ID, nil, &rpcError{Code: codeChatFailed, Message: err.Error()})
+		return
+	}
+	tools := []ToolInfo{}
+	for _, tool := range registry.GetAll() {
+		if decl := tool.Declaration(); decl != nil {
+			tools = append(tools, ToolInfo{Name: decl.Name, Description: decl.Description})
+		}
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	s.reply(req.ID, map[string]any{"tools": tools}, nil)
+}
+
+// cancelChats cancels one chat, or every chat when requestID is empty, and
+// returns how many were cancelled.
+func (s *Server) cancelChats(requestID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cancelled := 0
+	for id, call := range s.chats {
+		if requestID == "" || id == requestID {
+			call.cancel()
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
+func (s *Server) isOwnChat(requestID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.chats[requestID]
+	return ok
+}
+
+func (s *Server) takeChat(requestID string) *chatCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	call := s.chats[requestID]
+	delete(s.chats, requestID)
+	return call
+}
+
+// addPending records a confirmation the client must answer. It returns
+// false once the input has ended.
+func (s *Server) addPending(executionID string, confirmation pendingConfirmation) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.pending[executionID] = confirmation
+	return true
+}
+
+// publishConfirmation answers a confirmation with the client's answer; the
+// zero answer denies it.
+func (s *Server) publishConfirmation(executionID string, confirmation pendingConfirmation, answer confirmParams) {
+	if confirmation.kind == kindTool {
+		response := events.ToolConfirmationResponse{ExecutionID: executionID, Confirmed: answer.Confirmed, Command: answer.Edited, AlwaysAllow: answer.AlwaysAllow, Feedback: answer.Feedback}
+		confirmation.bus.Publish(response.Topic(), response)
+		return
+	}
+	response := events.UserConfirmationResponse{ExecutionID: executionID, Confirmed: answer.Confirmed, Content: answer.Edited, Feedback: answer.Feedback}
+	confirmation.bus.Publish(response.Topic(), response)
+}
+
+func (s *Server) reply(id json.RawMessage, result any, rpcErr *rpcError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	if result == nil && rpcErr == nil {
+		result = map[string]any{}
+	}
+	s.write(response{JSONRPC: "2.0", ID: id, Result: result, Error: rpcErr})
+}
+
+func (s *Server) notify(method string, params any) {
+	s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) write(message any) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	_ = s.out.Encode(message) // nothing to report to when the client is gone
+}
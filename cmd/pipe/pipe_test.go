@@ -0,0 +1,212 @@
+package pipe_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/pipe"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pipeClient struct {
+	t        *testing.T
+	in       *io.PipeWriter
+	messages chan map[string]any
+	done     chan error
+}
+
+func startPipe(t *testing.T, fixture *genietest.TestFixture) *pipeClient {
+	t.Helper()
+	return startServer(t, func(out io.Writer) *pipe.Server { return pipe.NewServer(fixture.Genie, out) })
+}
+
+func startServer(t *testing.T, newServer func(out io.Writer) *pipe.Server) *pipeClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	client := &pipeClient{t: t, in: inW, messages: make(chan map[string]any, 100), done: make(chan error, 1)}
+
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			var message map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &message); err == nil {
+				client.messages <- message
+			}
+		}
+	}()
+	go func() {
+		client.done <- newServer(outW).Serve(context.Background(), inR)
+		outW.Close()
+	}()
+	return client
+}
+
+func (c *pipeClient) send(line string) {
+	c.t.Helper()
+	_, err := io.WriteString(c.in, line+"\n")
+	require.NoError(c.t, err)
+}
+
+// next returns the next message matching method (notifications) or, when
+// method is empty, the next response.
+func (c *pipeClient) next(method string) map[string]any {
+	c.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case message := <-c.messages:
+			if got, _ := message["method"].(string); got == method {
+				return message
+			}
+		case <-timeout:
+			c.t.Fatalf("timed out waiting for %q", method)
+			return nil
+		}
+	}
+}
+
+func TestServe_ChatStreamsChunksThenResult(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.ExpectSimpleMessage("hello", "Hi there!")
+	fixture.StartAndGetSession()
+	client := startPipe(t, fixture)
+
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"chat","params":{"message":"hello"}}`)
+
+	started := client.next("chatStarted")["params"].(map[string]any)
+	assert.Equal(t, float64(1), started["id"])
+	requestID := started["requestId"].(string)
+	assert.NotEmpty(t, requestID)
+
+	chunk := client.next("chunk")["params"].(map[string]any)
+	assert.Equal(t, "Hi there!", chunk["text"])
+
+	result := client.next("")
+	assert.Equal(t, float64(1), result["id"])
+	assert.Equal(t, map[string]any{"requestId": requestID, "response": "Hi there!"}, result["result"])
+
+	client.in.Close()
+	assert.NoError(t, <-client.done)
+}
+
+func TestServe_ConfirmAnswersPendingRequest(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	client := startPipe(t, fixture)
+
+	answers := make(chan events.ToolConfirmationResponse, 1)
+	events.SubscribeTo(fixture.EventBus, func(e events.ToolConfirmationResponse) { answers <- e })
+
+	// Let Serve subscribe before the request is published
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"cancel"}`)
+	assert.Equal(t, map[string]any{"cancelled": float64(0)}, client.next("")["result"])
+
+	request := events.ToolConfirmationRequest{ExecutionID: "exec-1", ToolName: "bash", Command: "rm -rf build"}
+	fixture.EventBus.Publish(request.Topic(), request)
+	params := client.next("confirmationRequest")["params"].(map[string]any)
+	assert.Equal(t, "tool", params["kind"])
+	assert.Equal(t, "rm -rf build", params["command"])
+
+	client.send(`{"jsonrpc":"2.0","id":2,"method":"confirm","params":{"executionId":"exec-1","confirmed":true}}`)
+	assert.Equal(t, float64(2), client.next("")["id"])
+	select {
+	case answer := <-answers:
+		assert.Equal(t, events.ToolConfirmationResponse{ExecutionID: "exec-1", Confirmed: true}, answer)
+	case <-time.After(5 * time.Second):
+		t.Fatal("confirmation was not published")
+	}
+
+	// The answer is consumed: confirming again is an error
+	client.send(`{"jsonrpc":"2.0","id":3,"method":"confirm","params":{"executionId":"exec-1","confirmed":true}}`)
+	assert.NotNil(t, client.next("")["error"])
+
+	client.in.Close()
+	assert.NoError(t, <-client.done)
+}
+
+func TestServe_Errors(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	client := startPipe(t, fixture)
+
+	errorCode := func() float64 {
+		t.Helper()
+		return client.next("")["error"].(map[string]any)["code"].(float64)
+	}
+
+	client.send(`not json`)
+	assert.Equal(t, float64(-32700), errorCode())
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"explode"}`)
+	assert.Equal(t, float64(-32601), errorCode())
+	client.send(`{"jsonrpc":"2.0","id":2,"method":"chat","params":{}}`)
+	assert.Equal(t, float64(-32602), errorCode())
+
+	client.send(`{"jsonrpc":"2.0","id":3,"method":"listTools"}`)
+	tools := client.next("")["result"].(map[string]any)["tools"].([]any)
+	assert.NotEmpty(t, tools)
+
+	client.in.Close()
+	assert.NoError(t, <-client.done)
+}
+
+func TestServe_RoutesNamedSessions(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	backend := genietest.NewTestFixture(t)
+	backend.ExpectSimpleMessage("hello", "Hi from the backend!")
+	backend.StartAndGetSession()
+
+	var opened []string
+	released := make(chan string, 1)
+	client := startServer(t, func(out io.Writer) *pipe.Server {
+		return pipe.NewServer(fixture.Genie, out).WithSessions(func(name string) (genie.Genie, func(), error) {
+			opened = append(opened, name)
+			if name != "backend-api" {
+				return nil, nil, fmt.Errorf("no session named %q", name)
+			}
+			return backend.Genie, func() { released <- name }, nil
+		})
+	})
+
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"chat","params":{"message":"hello","session":"backend-api","stream":false}}`)
+	started := client.next("chatStarted")["params"].(map[string]any)
+	assert.Equal(t, "backend-api", started["session"])
+	result := client.next("")
+	assert.Equal(t, float64(1), result["id"])
+	assert.Equal(t, "Hi from the backend!", result["result"].(map[string]any)["response"])
+
+	// The session's Genie is started once and kept
+	client.send(`{"jsonrpc":"2.0","id":2,"method":"listTools","params":{"session":"backend-api"}}`)
+	assert.NotEmpty(t, client.next("")["result"].(map[string]any)["tools"])
+	client.send(`{"jsonrpc":"2.0","id":3,"method":"listTools","params":{"session":"web"}}`)
+	assert.Contains(t, client.next("")["error"].(map[string]any)["message"], `no session named "web"`)
+	assert.Equal(t, []string{"backend-api", "web"}, opened)
+
+	client.in.Close()
+	assert.NoError(t, <-client.done)
+	assert.Equal(t, "backend-api", <-released)
+}
+
+func TestServe_NamedSessionsNeedOpener(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	client := startPipe(t, fixture)
+
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"chat","params":{"message":"hello","session":"web"}}`)
+	rpcErr := client.next("")["error"].(map[string]any)
+	assert.Equal(t, float64(-32602), rpcErr["code"])
+	assert.Contains(t, rpcErr["message"], "serves no named sessions")
+
+	client.in.Close()
+	assert.NoError(t, <-client.done)
+}
@@ -0,0 +1,82 @@
+package prompttemplates
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch returns the candidates that contain every character of query in
+// order (case-insensitive), best match first. Prefix matches and characters
+// that follow a word boundary or each other score higher; ties keep the
+// shorter, then alphabetically first, name. An empty query matches every
+// candidate in its original order.
+func FuzzyMatch(query string, candidates []string) []string {
+	if query == "" {
+		return append([]string(nil), candidates...)
+	}
+
+	type scored struct {
+		name  string
+		score int
+	}
+
+	var matches []scored
+	for _, candidate := range candidates {
+		if score, ok := fuzzyScore(query, candidate); ok {
+			matches = append(matches, scored{name: candidate, score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if len(matches[i].name) != len(matches[j].name) {
+			return len(matches[i].name) < len(matches[j].name)
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	names := make([]string, len(matches))
+	for i, match := range matches {
+		names[i] = match.name
+	}
+	return names
+}
+
+func fuzzyScore(query, candidate string) (int, bool) {
+	query = strings.ToLower(query)
+	lower := []rune(strings.ToLower(candidate))
+
+	score := 0
+	if strings.HasPrefix(string(lower), query) {
+		score += 100
+	}
+
+	pos := 0
+	previous := -2
+	for _, qc := range query {
+		found := false
+		for ; pos < len(lower); pos++ {
+			if lower[pos] != qc {
+				continue
+			}
+			score++
+			if pos == previous+1 {
+				score += 5 // consecutive characters
+			}
+			if pos == 0 || !unicode.IsLetter(lower[pos-1]) && !unicode.IsDigit(lower[pos-1]) {
+				score += 10 // start of a word, e.g. the "r" in "code:review"
+			}
+			previous = pos
+			pos++
+			found = true
+			break
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return score, true
+}
@@ -0,0 +1,185 @@
+package prompttemplates
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// variablePattern matches {{name}} placeholders, allowing spaces inside the braces.
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// Template is a reusable prompt snippet loaded from a .md file.
+type Template struct {
+	Name        string
+	Description string
+	Body        string
+	Variables   []string // in order of first appearance
+	Source      string   // "project" or "user"
+}
+
+// Render substitutes every {{variable}} in the template body. All variables
+// must have a value.
+func (t Template) Render(values map[string]string) (string, error) {
+	var missing []string
+	for _, variable := range t.Variables {
+		if _, ok := values[variable]; !ok {
+			missing = append(missing, variable)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing values for: %s", strings.Join(missing, ", "))
+	}
+
+	return variablePattern.ReplaceAllStringFunc(t.Body, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		return values[name]
+	}), nil
+}
+
+// ParseVariables returns the distinct {{variable}} names in body, in order of
+// first appearance.
+func ParseVariables(body string) []string {
+	var variables []string
+	seen := make(map[string]bool)
+	for _, match := range variablePattern.FindAllStringSubmatch(body, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			variables = append(variables, match[1])
+		}
+	}
+	return variables
+}
+
+type Manager struct {
+	templates     map[string]Template
+	templateNames []string // cached, sorted list of template names
+	userOnly      bool     // skip project templates (untrusted workspace)
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		templates:     make(map[string]Template),
+		templateNames: make([]string, 0),
+	}
+}
+
+// DisableProjectSources makes discovery skip the project's .genie/prompts,
+// for workspaces the user has not trusted.
+func (m *Manager) DisableProjectSources() {
+	m.userOnly = true
+}
+
+// GetTemplate returns a Template by its name.
+func (m *Manager) GetTemplate(name string) (Template, bool) {
+	template, ok := m.templates[name]
+	return template, ok
+}
+
+// GetTemplateNames returns the sorted names of all available templates.
+func (m *Manager) GetTemplateNames() []string {
+	return m.templateNames
+}
+
+// Resolve finds the template named query, falling back to the best fuzzy
+// match among the template names.
+func (m *Manager) Resolve(query string) (Template, bool) {
+	if template, ok := m.templates[query]; ok {
+		return template, true
+	}
+	matches := FuzzyMatch(query, m.templateNames)
+	if len(matches) == 0 {
+		return Template{}, false
+	}
+	return m.templates[matches[0]], true
+}
+
+// DiscoverTemplates loads prompt templates from .genie/prompts in the project
+// and the user's home directory. Project templates take precedence.
+func (m *Manager) DiscoverTemplates(projectRoot string, getUserHomeDir func() (string, error)) error {
+	homeDir, err := getUserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	discoveryPaths := []struct {
+		path   string
+		source string
+	}{
+		// User templates load first so project templates override them
+		{filepath.Join(homeDir, ".genie", "prompts"), "user"},
+		{filepath.Join(projectRoot, ".genie", "prompts"), "project"},
+	}
+
+	templates := make(map[string]Template)
+	for _, dp := range discoveryPaths {
+		if m.userOnly && dp.source == "project" {
+			continue
+		}
+		root, err := os.OpenRoot(dp.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("error opening path %s: %w", dp.path, err)
+		}
+
+		err = filepath.WalkDir(dp.path, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+				return nil
+			}
+			relPath, _ := filepath.Rel(dp.path, path)
+			name := strings.TrimSuffix(relPath, ".md")
+			name = strings.ReplaceAll(name, string(filepath.Separator), ":")
+
+			// Read file content using root-scoped API to prevent symlink traversal
+			fileContent, err := fs.ReadFile(root.FS(), relPath)
+			if err != nil {
+				return fmt.Errorf("failed to read prompt file %s: %w", relPath, err)
+			}
+			body := strings.TrimSpace(string(fileContent))
+
+			templates[name] = Template{
+				Name:        name,
+				Description: describe(body),
+				Body:        body,
+				Variables:   ParseVariables(body),
+				Source:      dp.source,
+			}
+			return nil
+		})
+		root.Close()
+		if err != nil {
+			return fmt.Errorf("error walking path %s: %w", dp.path, err)
+		}
+	}
+
+	m.templates = templates
+	m.rebuildTemplateNames()
+	return nil
+}
+
+func (m *Manager) rebuildTemplateNames() {
+	m.templateNames = make([]string, 0, len(m.templates))
+	for name := range m.templates {
+		m.templateNames = append(m.templateNames, name)
+	}
+	sort.Strings(m.templateNames)
+}
+
+// describe uses the first line of the body as the template description.
+func describe(body string) string {
+	description, _, _ := strings.Cut(body, "\n")
+	description = strings.TrimSpace(description)
+	if len(description) > 100 { // Truncate long descriptions for display
+		description = description[:100] + "..."
+	}
+	return description
+}
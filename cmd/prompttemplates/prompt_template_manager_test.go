@@ -0,0 +1,115 @@
+package prompttemplates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrompt(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, ".genie", "prompts", name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestParseVariables(t *testing.T) {
+	variables := ParseVariables("Review {{file}} for {{ concern }}. Focus on {{file}} and {{bad var}}.")
+	assert.Equal(t, []string{"file", "concern"}, variables)
+}
+
+func TestTemplate_Render(t *testing.T) {
+	template := Template{
+		Body:      "Review {{file}} for {{ concern }} issues in {{file}}",
+		Variables: []string{"file", "concern"},
+	}
+
+	rendered, err := template.Render(map[string]string{"file": "main.go", "concern": "security"})
+	require.NoError(t, err)
+	assert.Equal(t, "Review main.go for security issues in main.go", rendered)
+
+	_, err = template.Render(map[string]string{"file": "main.go"})
+	assert.ErrorContains(t, err, "concern")
+}
+
+func TestManager_DisableProjectSources(t *testing.T) {
+	projectDir := t.TempDir()
+	homeDir := t.TempDir()
+	writePrompt(t, projectDir, "review.md", "Project review prompt")
+	writePrompt(t, homeDir, "review.md", "User review prompt")
+
+	manager := NewManager()
+	manager.DisableProjectSources()
+	require.NoError(t, manager.DiscoverTemplates(projectDir, func() (string, error) { return homeDir, nil }))
+
+	review, ok := manager.GetTemplate("review")
+	require.True(t, ok)
+	assert.Equal(t, "user", review.Source)
+}
+
+func TestManager_DiscoverTemplates(t *testing.T) {
+	projectDir := t.TempDir()
+	homeDir := t.TempDir()
+
+	writePrompt(t, projectDir, "review.md", "Review {{file}} carefully\nBe thorough.")
+	writePrompt(t, projectDir, "go/test.md", "Write tests for {{package}}")
+	writePrompt(t, projectDir, "notes.txt", "ignored")
+	writePrompt(t, homeDir, "review.md", "User review prompt")
+	writePrompt(t, homeDir, "explain.md", "Explain {{topic}}")
+
+	manager := NewManager()
+	err := manager.DiscoverTemplates(projectDir, func() (string, error) { return homeDir, nil })
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"explain", "go:test", "review"}, manager.GetTemplateNames())
+
+	review, ok := manager.GetTemplate("review")
+	require.True(t, ok)
+	assert.Equal(t, "project", review.Source)
+	assert.Equal(t, "Review {{file}} carefully", review.Description)
+	assert.Equal(t, []string{"file"}, review.Variables)
+
+	explain, ok := manager.GetTemplate("explain")
+	require.True(t, ok)
+	assert.Equal(t, "user", explain.Source)
+}
+
+func TestManager_DiscoverTemplatesWithoutDirectories(t *testing.T) {
+	manager := NewManager()
+	err := manager.DiscoverTemplates(t.TempDir(), func() (string, error) { return t.TempDir(), nil })
+	require.NoError(t, err)
+	assert.Empty(t, manager.GetTemplateNames())
+}
+
+func TestManager_Resolve(t *testing.T) {
+	projectDir := t.TempDir()
+	writePrompt(t, projectDir, "code-review.md", "Review")
+	writePrompt(t, projectDir, "explain.md", "Explain")
+
+	manager := NewManager()
+	require.NoError(t, manager.DiscoverTemplates(projectDir, func() (string, error) { return t.TempDir(), nil }))
+
+	template, ok := manager.Resolve("explain")
+	require.True(t, ok)
+	assert.Equal(t, "explain", template.Name)
+
+	template, ok = manager.Resolve("crv")
+	require.True(t, ok)
+	assert.Equal(t, "code-review", template.Name)
+
+	_, ok = manager.Resolve("zzz")
+	assert.False(t, ok)
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	names := []string{"code-review", "explain", "go:test", "refactor", "review"}
+
+	assert.Equal(t, []string{"review", "code-review"}, FuzzyMatch("rev", names))
+	assert.Equal(t, []string{"code-review"}, FuzzyMatch("crv", names))
+	assert.Equal(t, []string{"go:test"}, FuzzyMatch("gt", names))
+	assert.Empty(t, FuzzyMatch("xyz", names))
+	assert.Equal(t, names, FuzzyMatch("", names))
+}
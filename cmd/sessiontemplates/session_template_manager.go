@@ -0,0 +1,194 @@
+// Package sessiontemplates loads the workflow templates genie new starts
+// sessions from: an opening prompt, the context to pin and the persona
+// recommended for the work.
+package sessiontemplates
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates/*.yaml
+var builtinTemplates embed.FS
+
+// argsPattern matches the {{args}} placeholder, allowing spaces inside the braces.
+var argsPattern = regexp.MustCompile(`\{\{\s*args\s*\}\}`)
+
+// ContextCommand is a command whose output is captured and pinned when the
+// session starts, such as a failing test run.
+type ContextCommand struct {
+	Name string `yaml:"name"`
+	Run  string `yaml:"run"`
+}
+
+// Template is a workflow loaded from a .yaml file.
+type Template struct {
+	Name        string           `yaml:"-"`
+	Description string           `yaml:"description"`
+	Persona     string           `yaml:"persona"`
+	Prompt      string           `yaml:"prompt"`
+	Pin         []string         `yaml:"pin"`
+	Context     []ContextCommand `yaml:"context"`
+	Source      string           `yaml:"-"` // "project", "user" or "built-in"
+}
+
+// Render returns the opening prompt with args in place of {{args}}. Without
+// the placeholder, args are appended as a paragraph of their own.
+func (t Template) Render(args string) string {
+	args = strings.TrimSpace(args)
+	if argsPattern.MatchString(t.Prompt) {
+		prompt := argsPattern.ReplaceAllLiteralString(t.Prompt, args)
+		return strings.TrimSpace(prompt)
+	}
+	prompt := strings.TrimSpace(t.Prompt)
+	if args == "" {
+		return prompt
+	}
+	if prompt == "" {
+		return args
+	}
+	return prompt + "\n\n" + args
+}
+
+// Parse reads a template from its YAML definition.
+func Parse(name string, data []byte) (Template, error) {
+	var template Template
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return Template{}, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	if strings.TrimSpace(template.Prompt) == "" {
+		return Template{}, fmt.Errorf("invalid template %s: prompt is required", name)
+	}
+	for i, command := range template.Context {
+		if strings.TrimSpace(command.Run) == "" {
+			return Template{}, fmt.Errorf("invalid template %s: context entry %d has no run command", name, i+1)
+		}
+		// The name becomes the file the output is saved to
+		if strings.ContainsAny(command.Name, `/\`) || strings.HasPrefix(command.Name, ".") {
+			return Template{}, fmt.Errorf("invalid template %s: context name %q must be a plain file name", name, command.Name)
+		}
+		if command.Name == "" {
+			template.Context[i].Name = fmt.Sprintf("context-%d", i+1)
+		}
+	}
+	template.Name = name
+	return template, nil
+}
+
+type Manager struct {
+	templates     map[string]Template
+	templateNames []string // cached, sorted list of template names
+	userOnly      bool     // skip project templates (untrusted workspace)
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		templates:     make(map[string]Template),
+		templateNames: make([]string, 0),
+	}
+}
+
+// DisableProjectSources makes discovery skip the project's .genie/templates,
+// for workspaces the user has not trusted.
+func (m *Manager) DisableProjectSources() {
+	m.userOnly = true
+}
+
+// GetTemplate returns a Template by its name.
+func (m *Manager) GetTemplate(name string) (Template, bool) {
+	template, ok := m.templates[name]
+	return template, ok
+}
+
+// GetTemplateNames returns the sorted names of all available templates.
+func (m *Manager) GetTemplateNames() []string {
+	return m.templateNames
+}
+
+// DiscoverTemplates loads the built-in templates, then templates from
+// .genie/templates in the user's home directory and the project. Project
+// templates take precedence, and user templates override built-in ones.
+func (m *Manager) DiscoverTemplates(projectRoot string, getUserHomeDir func() (string, error)) error {
+	homeDir, err := getUserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	templates := make(map[string]Template)
+	builtin, _ := fs.Sub(builtinTemplates, "templates")
+	if err := loadTemplates(builtin, "built-in", templates); err != nil {
+		return err
+	}
+
+	discoveryPaths := []struct {
+		path   string
+		source string
+	}{
+		{filepath.Join(homeDir, ".genie", "templates"), "user"},
+		{filepath.Join(projectRoot, ".genie", "templates"), "project"},
+	}
+	for _, dp := range discoveryPaths {
+		if m.userOnly && dp.source == "project" {
+			continue
+		}
+		// Root-scoped reads prevent symlink traversal out of the directory
+		root, err := os.OpenRoot(dp.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("error opening path %s: %w", dp.path, err)
+		}
+		err = loadTemplates(root.FS(), dp.source, templates)
+		root.Close()
+		if err != nil {
+			return fmt.Errorf("error reading path %s: %w", dp.path, err)
+		}
+	}
+
+	m.templates = templates
+	m.rebuildTemplateNames()
+	return nil
+}
+
+// loadTemplates parses every .yaml or .yml file at the top of fsys into
+// templates, replacing any of the same name.
+func loadTemplates(fsys fs.FS, source string, templates map[string]Template) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read template file %s: %w", entry.Name(), err)
+		}
+		template, err := Parse(strings.TrimSuffix(entry.Name(), ext), data)
+		if err != nil {
+			return err
+		}
+		template.Source = source
+		templates[template.Name] = template
+	}
+	return nil
+}
+
+func (m *Manager) rebuildTemplateNames() {
+	m.templateNames = make([]string, 0, len(m.templates))
+	for name := range m.templates {
+		m.templateNames = append(m.templateNames, name)
+	}
+	sort.Strings(m.templateNames)
+}
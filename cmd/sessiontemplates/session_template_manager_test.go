@@ -0,0 +1,109 @@
+package sessiontemplates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, ".genie", "templates", name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestParse(t *testing.T) {
+	template, err := Parse("bugfix", []byte(`
+description: Fix a bug
+persona: engineer
+prompt: Fix {{args}}
+pin:
+  - go.mod
+context:
+  - name: failing-tests
+    run: go test ./...
+  - run: git status
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "bugfix", template.Name)
+	assert.Equal(t, "engineer", template.Persona)
+	assert.Equal(t, []string{"go.mod"}, template.Pin)
+	assert.Equal(t, []ContextCommand{
+		{Name: "failing-tests", Run: "go test ./..."},
+		{Name: "context-2", Run: "git status"},
+	}, template.Context)
+
+	_, err = Parse("empty", []byte("description: nothing to say\n"))
+	assert.ErrorContains(t, err, "prompt is required")
+
+	_, err = Parse("broken", []byte("prompt: hi\ncontext:\n  - name: tests\n"))
+	assert.ErrorContains(t, err, "no run command")
+
+	_, err = Parse("escape", []byte("prompt: hi\ncontext:\n  - name: ../outside\n    run: ls\n"))
+	assert.ErrorContains(t, err, "plain file name")
+}
+
+func TestTemplate_Render(t *testing.T) {
+	template := Template{Prompt: "Fix the bug: {{ args }}\n"}
+	assert.Equal(t, "Fix the bug: login fails", template.Render(" login fails "))
+	assert.Equal(t, "Fix the bug:", template.Render(""))
+
+	template = Template{Prompt: "Review my changes."}
+	assert.Equal(t, "Review my changes.\n\nFocus on errors", template.Render("Focus on errors"))
+	assert.Equal(t, "Review my changes.", template.Render(""))
+}
+
+func TestManager_DiscoverTemplates(t *testing.T) {
+	projectDir := t.TempDir()
+	homeDir := t.TempDir()
+
+	writeTemplate(t, homeDir, "bugfix.yaml", "description: My bugfix\nprompt: Fix {{args}}\n")
+	writeTemplate(t, homeDir, "triage.yml", "description: User triage\nprompt: Triage\n")
+	writeTemplate(t, projectDir, "triage.yaml", "description: Project triage\npersona: reviewer\nprompt: Triage\n")
+	writeTemplate(t, projectDir, "notes.md", "ignored")
+
+	manager := NewManager()
+	require.NoError(t, manager.DiscoverTemplates(projectDir, func() (string, error) { return homeDir, nil }))
+
+	assert.Equal(t, []string{"bugfix", "feature", "review", "triage"}, manager.GetTemplateNames())
+
+	bugfix, ok := manager.GetTemplate("bugfix")
+	require.True(t, ok)
+	assert.Equal(t, "user", bugfix.Source)
+	assert.Equal(t, "My bugfix", bugfix.Description)
+
+	triage, _ := manager.GetTemplate("triage")
+	assert.Equal(t, "project", triage.Source)
+	assert.Equal(t, "reviewer", triage.Persona)
+
+	review, _ := manager.GetTemplate("review")
+	assert.Equal(t, "built-in", review.Source)
+	assert.NotEmpty(t, review.Context)
+}
+
+func TestManager_DisableProjectSources(t *testing.T) {
+	projectDir := t.TempDir()
+	homeDir := t.TempDir()
+	writeTemplate(t, projectDir, "bugfix.yaml", "prompt: Project bugfix\n")
+
+	manager := NewManager()
+	manager.DisableProjectSources()
+	require.NoError(t, manager.DiscoverTemplates(projectDir, func() (string, error) { return homeDir, nil }))
+
+	bugfix, ok := manager.GetTemplate("bugfix")
+	require.True(t, ok)
+	assert.Equal(t, "built-in", bugfix.Source)
+}
+
+func TestManager_DiscoverTemplates_InvalidTemplate(t *testing.T) {
+	projectDir := t.TempDir()
+	writeTemplate(t, projectDir, "broken.yaml", "prompt: [unterminated\n")
+
+	manager := NewManager()
+	err := manager.DiscoverTemplates(projectDir, func() (string, error) { return t.TempDir(), nil })
+	assert.ErrorContains(t, err, "broken")
+}
@@ -18,6 +18,7 @@ type SlashCommand struct {
 type Manager struct {
 	commands     map[string]SlashCommand
 	commandNames []string // cached list of command names
+	userOnly     bool     // skip project commands (untrusted workspace)
 }
 
 func NewManager() *Manager {
@@ -27,6 +28,12 @@ func NewManager() *Manager {
 	}
 }
 
+// DisableProjectSources makes discovery skip the project's .genie/commands
+// and .claude/commands, for workspaces the user has not trusted.
+func (m *Manager) DisableProjectSources() {
+	m.userOnly = true
+}
+
 // GetCommand returns a SlashCommand by its name.
 func (m *Manager) GetCommand(commandName string) (SlashCommand, bool) {
 	cmd, ok := m.commands[commandName]
@@ -72,6 +79,9 @@ func (m *Manager) DiscoverCommands(projectRoot string, getUserHomeDir func() (st
 	)
 
 	for _, dp := range discoveryPaths {
+		if m.userOnly && dp.source == "project" {
+			continue
+		}
 		root, err := os.OpenRoot(dp.path)
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -1,11 +1,10 @@
 package tui
 
 import (
+	"context"
 	"io"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/awesome-gocui/gocui"
@@ -19,7 +18,11 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/presentation"
 	"github.com/kcaldas/genie/cmd/tui/state"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/logging"
+	"github.com/kcaldas/genie/pkg/offline"
+	"github.com/kcaldas/genie/pkg/plugin"
+	"github.com/kcaldas/genie/pkg/update"
 )
 
 type App struct {
@@ -50,6 +53,7 @@ type App struct {
 
 	// Internal state
 	keybindingsSetup bool
+	resumed          atomic.Bool // suspend restored the screen before its SIGCONT arrived
 }
 
 func NewApp(
@@ -130,6 +134,14 @@ func NewAppWithOutputMode(
 	// Register help command (only one left to register manually)
 	app.commandHandler.RegisterNewCommand(commands.NewHelpCommand(app.helpController))
 
+	// Plugin commands never replace a built-in one
+	for _, cmd := range plugin.Commands(plugin.Registered()) {
+		if app.commandHandler.GetCommand(cmd.Name) != nil {
+			continue
+		}
+		app.commandHandler.RegisterNewCommand(commands.NewPluginCommand(cmd, app.notification))
+	}
+
 	app.commandEventBus.Subscribe("app.exit", func(i interface{}) {
 		app.exit()
 	})
@@ -149,6 +161,9 @@ func NewAppWithOutputMode(
 		})
 	})
 
+	// Open the panels the saved layout preset starts with
+	app.layoutManager.OpenPresetPanels()
+
 	gui.GetGui().Cursor = true // Force cursor enabled for debugging
 
 	theme := presentation.GetThemeForMode(config.Theme, config.OutputMode)
@@ -165,6 +180,7 @@ func NewAppWithOutputMode(
 		if err := app.layoutManager.Layout(gui); err != nil {
 			return err
 		}
+		setFrameLines(gui, !app.configManager.GetConfig().IsAccessibleEnabled())
 		// Set up keybindings after views are created (only once)
 		if !app.keybindingsSetup {
 			if err := app.setupKeybindings(); err != nil {
@@ -182,6 +198,22 @@ func NewAppWithOutputMode(
 	return app, nil
 }
 
+// blankFrameRunes draws every frame edge and corner as a space, so panels
+// keep their titles and spacing without box-drawing characters
+var blankFrameRunes = []rune{' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' '}
+
+// setFrameLines shows or blanks the frame lines of all views, including
+// popups that are created outside the layout manager
+func setFrameLines(gui *gocui.Gui, show bool) {
+	runes := blankFrameRunes
+	if show {
+		runes = nil
+	}
+	for _, view := range gui.Views() {
+		view.FrameRunes = runes
+	}
+}
+
 func (app *App) createKeymap() *Keymap {
 	keymap := NewKeymap()
 
@@ -213,7 +245,13 @@ func (app *App) createKeymap() *Keymap {
 		Description: "Open text area zoom for composing longer messages",
 	})
 	keymap.AddEntry(KeymapEntry{
-		Key: gocui.KeyCtrlZ,
+		Key:         gocui.KeyCtrlZ,
+		Mod:         gocui.ModNone,
+		Action:      FunctionAction(app.suspend),
+		Description: "Suspend Genie to the shell; resume with fg",
+	})
+	keymap.AddEntry(KeymapEntry{
+		Key: gocui.KeyF2,
 		Mod: gocui.ModNone,
 		Action: FunctionAction(func() error {
 			app.layoutManager.ToggleRightPanelZoom()
@@ -222,6 +260,43 @@ func (app *App) createKeymap() *Keymap {
 		Description: "Toggle right panel zoom (confirmations, debug, etc.)",
 	})
 
+	keymap.AddEntry(KeymapEntry{
+		Key:         gocui.KeyArrowLeft,
+		Mod:         gocui.ModShift,
+		Action:      FunctionAction(func() error { return app.resizeSidePanel(-1) }),
+		Description: "Narrow the side panel",
+	})
+	keymap.AddEntry(KeymapEntry{
+		Key:         gocui.KeyArrowRight,
+		Mod:         gocui.ModShift,
+		Action:      FunctionAction(func() error { return app.resizeSidePanel(1) }),
+		Description: "Widen the side panel",
+	})
+	keymap.AddEntry(KeymapEntry{
+		Key:         gocui.KeyArrowUp,
+		Mod:         gocui.ModShift,
+		Action:      FunctionAction(func() error { return app.resizeInput(1) }),
+		Description: "Grow the input panel",
+	})
+	keymap.AddEntry(KeymapEntry{
+		Key:         gocui.KeyArrowDown,
+		Mod:         gocui.ModShift,
+		Action:      FunctionAction(func() error { return app.resizeInput(-1) }),
+		Description: "Shrink the input panel",
+	})
+	keymap.AddEntry(KeymapEntry{
+		Key:         gocui.MouseLeft,
+		Mod:         gocui.ModNone,
+		Action:      FunctionAction(app.handleMouseDown),
+		Description: "Drag a panel border to resize (mouse)",
+	})
+	keymap.AddEntry(KeymapEntry{
+		Key:         gocui.MouseRelease,
+		Mod:         gocui.ModNone,
+		Action:      FunctionAction(app.handleMouseUp),
+		Description: "Drop a dragged panel border (mouse)",
+	})
+
 	keymap.AddEntry(KeymapEntry{
 		Key:         gocui.KeyCtrlSlash,
 		Mod:         gocui.ModNone,
@@ -383,6 +458,7 @@ func (app *App) Run() error {
 func (app *App) RunWithMessage(initialMessage string) error {
 	// Add welcome message first
 	app.notification.ShowWelcomeMessage()
+	app.offerRecovery()
 
 	// Set focus to input after everything is set up using semantic naming
 	app.gui.GetGui().Update(func(g *gocui.Gui) error {
@@ -398,19 +474,37 @@ func (app *App) RunWithMessage(initialMessage string) error {
 		}()
 	}
 
-	// Setup signal handling for graceful shutdown on Ctrl+C
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go app.checkForUpdate()
 
-	go func() {
-		<-sigChan
-		// Gracefully exit the application when receiving SIGINT (Ctrl+C) or SIGTERM
-		app.gui.GetGui().Close()
-	}()
+	app.watchSignals()
 
 	return app.gui.GetGui().MainLoop()
 }
 
+// checkForUpdate tells the status bar when a newer release is out. It
+// stays quiet when the check is turned off, when offline, and when GitHub
+// cannot be reached.
+func (app *App) checkForUpdate() {
+	cfg := config.NewConfigManager()
+	if !app.configManager.GetConfig().IsUpdateCheckEnabled() || !cfg.GetBoolWithDefault(update.CheckConfigKey, true) || offline.Enabled(cfg) {
+		return
+	}
+	updater, err := update.NewUpdater()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	latest, err := updater.NewerVersion(ctx)
+	if err != nil {
+		logging.GetGlobalLogger().Debug("update check failed", "error", err)
+		return
+	}
+	if latest != "" {
+		app.commandEventBus.Emit("update.available", latest)
+	}
+}
+
 func (app *App) Close() {
 	if app.gui.GetGui() != nil {
 		app.gui.GetGui().Close()
@@ -433,8 +527,9 @@ func (app *App) getActiveScrollable() component.Scrollable {
 		case "diff-viewer":
 			c = app.layoutManager.GetComponent("diff-viewer")
 		}
-	} else {
-		// Default to messages component
+	}
+	if c == nil {
+		// Default to messages component, which the theme editor previews in
 		c = app.layoutManager.GetComponent("messages")
 	}
 	return c.(component.Scrollable)
@@ -520,6 +615,39 @@ func (app *App) handleMouseWheelDown() error {
 	return nil
 }
 
+func (app *App) resizeSidePanel(delta int) error {
+	if app.layoutManager.ResizeSidePanel(delta) {
+		return app.saveLayout()
+	}
+	return nil
+}
+
+func (app *App) resizeInput(delta int) error {
+	app.layoutManager.ResizeInput(delta)
+	return app.saveLayout()
+}
+
+func (app *App) handleMouseDown() error {
+	app.layoutManager.StartDrag(app.gui.GetGui().MousePosition())
+	return nil
+}
+
+func (app *App) handleMouseUp() error {
+	if app.layoutManager.EndDrag(app.gui.GetGui().MousePosition()) {
+		return app.saveLayout()
+	}
+	return nil
+}
+
+// saveLayout persists the panel sizes so the next session starts with them
+func (app *App) saveLayout() error {
+	geometry := app.layoutManager.Geometry()
+	_ = app.configManager.UpdateConfig(func(config *types.Config) {
+		config.Layout.Geometry = geometry
+	}, true)
+	return nil
+}
+
 func (app *App) exit() error {
 	// Set a flag to exit the main loop
 	app.gui.GetGui().Update(func(g *gocui.Gui) error {
@@ -14,6 +14,9 @@ type ConfirmationComponent struct {
 	ExecutionID    string // Public so it can be updated
 	message        string
 	onConfirmation func(executionID string, confirmed bool) error
+	onEdit         func(executionID string) error
+	onAlwaysAllow  func(executionID string) error
+	onDenyReason   func(executionID string) error
 }
 
 func NewConfirmationComponent(gui types.Gui, configManager *helpers.ConfigManager, executionID, message string, onConfirmation func(string, bool) error) *ConfirmationComponent {
@@ -50,7 +53,7 @@ func NewConfirmationComponent(gui types.Gui, configManager *helpers.ConfigManage
 }
 
 func (c *ConfirmationComponent) GetKeybindings() []*types.KeyBinding {
-	return []*types.KeyBinding{
+	bindings := []*types.KeyBinding{
 		// Yes keys
 		{
 			View:    c.viewName,
@@ -89,6 +92,78 @@ func (c *ConfirmationComponent) GetKeybindings() []*types.KeyBinding {
 			Handler: c.handleConfirmation(false),
 		},
 	}
+
+	// Approve for the rest of the session, when the request allows it
+	if c.onAlwaysAllow != nil {
+		bindings = append(bindings, &types.KeyBinding{
+			View:    c.viewName,
+			Key:     '3',
+			Handler: c.handleAlwaysAllow,
+		})
+	}
+
+	// Edit keys, when the request offers something to edit
+	if c.onEdit != nil {
+		for _, key := range []rune{'e', 'E'} {
+			bindings = append(bindings, &types.KeyBinding{
+				View:    c.viewName,
+				Key:     key,
+				Handler: c.handleEdit,
+			})
+		}
+	}
+
+	// Deny with a reason for the model, when the request can carry one
+	if c.onDenyReason != nil {
+		for _, key := range []rune{'r', 'R'} {
+			bindings = append(bindings, &types.KeyBinding{
+				View:    c.viewName,
+				Key:     key,
+				Handler: c.handleDenyWithReason,
+			})
+		}
+	}
+
+	return bindings
+}
+
+// SetOnAlwaysAllow enables the 3 key, which calls fn to approve the call
+// and the tool for the rest of the session.
+func (c *ConfirmationComponent) SetOnAlwaysAllow(fn func(executionID string) error) {
+	c.onAlwaysAllow = fn
+}
+
+func (c *ConfirmationComponent) handleAlwaysAllow(g *gocui.Gui, v *gocui.View) error {
+	if c.onAlwaysAllow != nil {
+		return c.onAlwaysAllow(c.ExecutionID)
+	}
+	return nil
+}
+
+// SetOnEdit enables the e key, which calls fn to edit the proposed
+// command or content before approving it.
+func (c *ConfirmationComponent) SetOnEdit(fn func(executionID string) error) {
+	c.onEdit = fn
+}
+
+func (c *ConfirmationComponent) handleEdit(g *gocui.Gui, v *gocui.View) error {
+	if c.onEdit != nil {
+		return c.onEdit(c.ExecutionID)
+	}
+	return nil
+}
+
+// SetOnDenyWithReason enables the r key, which calls fn to decline with
+// a reason that is returned to the model.
+func (c *ConfirmationComponent) SetOnDenyWithReason(fn func(executionID string) error) {
+	c.onDenyReason = fn
+}
+
+func (c *ConfirmationComponent) handleDenyWithReason(g *gocui.Gui, v *gocui.View) error {
+	if c.onDenyReason != nil {
+		return c.onDenyReason(c.ExecutionID)
+	}
+	return nil
 }
 
 func (c *ConfirmationComponent) handleConfirmation(confirmed bool) func(*gocui.Gui, *gocui.View) error {
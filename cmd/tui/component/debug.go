@@ -17,6 +17,9 @@ type DebugComponent struct {
 	debugState *state.DebugState
 	isVisible  bool
 	eventBus   *events.CommandEventBus
+
+	// lineEntries maps each rendered line to its entry ID (0 for turn headers)
+	lineEntries []int
 }
 
 func NewDebugComponent(gui types.Gui, debugState *state.DebugState, configManager *helpers.ConfigManager, eventBus *events.CommandEventBus) *DebugComponent {
@@ -35,8 +38,8 @@ func NewDebugComponent(gui types.Gui, debugState *state.DebugState, configManage
 	ctx.SetWindowProperties(types.WindowProperties{
 		Focusable:   true,
 		Editable:    false,
-		Wrap:        true,
-		Autoscroll:  true,
+		Wrap:        false, // One line per row so the cursor maps to entries
+		Autoscroll:  false,
 		Highlight:   true,
 		Frame:       true,
 		BorderStyle: types.BorderStyleSingle, // Debug panel uses single border
@@ -84,12 +87,17 @@ func (c *DebugComponent) GetKeybindings() []*types.KeyBinding {
 		{
 			View:    c.viewName,
 			Key:     gocui.KeyArrowUp,
-			Handler: c.scrollUp,
+			Handler: c.cursorUp,
 		},
 		{
 			View:    c.viewName,
 			Key:     gocui.KeyArrowDown,
-			Handler: c.scrollDown,
+			Handler: c.cursorDown,
+		},
+		{
+			View:    c.viewName,
+			Key:     gocui.KeyEnter,
+			Handler: c.toggleSelectedEntry,
 		},
 		{
 			View:    c.viewName,
@@ -121,48 +129,80 @@ func (c *DebugComponent) Render() error {
 		return err
 	}
 
-	v.Clear()
+	// Follow new events while the cursor sits on the last line
+	_, oy := v.Origin()
+	_, cy := v.Cursor()
+	following := oy+cy >= len(c.lineEntries)-1
 
-	messages := c.debugState.GetDebugMessages()
-	for _, msg := range messages {
-		fmt.Fprintln(v, msg)
-	}
+	lines := FormatDebugInspector(c.debugState.GetEntries(), c.debugState.GetTurns(), c.debugState.IsExpanded)
+	c.lineEntries = make([]int, len(lines))
 
-	// Auto-scroll to bottom if there are messages
-	if len(messages) > 0 {
-		c.ScrollToBottom()
+	v.Clear()
+	v.Title = c.title()
+	theme := c.GetTheme()
+	for i, line := range lines {
+		c.lineEntries[i] = line.EntryID
+		color := ""
+		switch {
+		case line.Kind == DebugLineTurn:
+			color = presentation.ConvertColorToAnsi(theme.Secondary)
+		case line.Kind == DebugLineDetail:
+			color = presentation.ConvertColorToAnsi(theme.Muted)
+		case line.Failed:
+			color = presentation.ConvertColorToAnsi(theme.Error)
+		}
+		if color != "" {
+			fmt.Fprintln(v, color+line.Text+"\033[0m")
+		} else {
+			fmt.Fprintln(v, line.Text)
+		}
 	}
 
+	if following && len(lines) > 0 {
+		c.selectLine(len(lines) - 1)
+	}
 	return nil
 }
 
-// OnMessageAdded should be called when a new debug message is added to state
-func (c *DebugComponent) OnMessageAdded() error {
-	// If not visible, nothing to do - message is already in state
-	if !c.isVisible {
-		return nil
+// title shows the active filter next to the panel name
+func (c *DebugComponent) title() string {
+	if filter := c.debugState.GetFilter(); filter != "" {
+		return fmt.Sprintf(" Debug · filter: %s ", filter)
 	}
+	return " Debug "
+}
+
+// OnMessageAdded should be called when a new entry is added to state
+func (c *DebugComponent) OnMessageAdded() error {
+	return c.Render()
+}
 
+// selectLine moves the cursor to a buffer line, scrolling it into view
+func (c *DebugComponent) selectLine(line int) {
 	v := c.GetView()
-	if v == nil {
-		return nil
+	if v == nil || line < 0 || line >= len(c.lineEntries) {
+		return
 	}
-
-	// Get the latest message (last one in the list with timestamp)
-	messages := c.debugState.GetDebugMessages()
-	if len(messages) == 0 {
-		return nil
+	_, height := v.Size()
+	_, oy := v.Origin()
+	if line < oy {
+		oy = line
+	} else if height > 0 && line >= oy+height {
+		oy = line - height + 1
 	}
+	_ = v.SetOrigin(0, oy)
+	_ = v.SetCursor(0, line-oy)
+}
 
-	latestMessage := messages[len(messages)-1]
-
-	// Append the new message with same formatting as Render()
-	fmt.Fprintln(v, latestMessage)
-
-	// Always auto-scroll to bottom to show the new message
-	c.ScrollToBottom()
-
-	return nil
+// selectedLine returns the buffer line under the cursor
+func (c *DebugComponent) selectedLine() int {
+	v := c.GetView()
+	if v == nil {
+		return -1
+	}
+	_, oy := v.Origin()
+	_, cy := v.Cursor()
+	return oy + cy
 }
 
 func (c *DebugComponent) IsVisible() bool {
@@ -170,6 +210,10 @@ func (c *DebugComponent) IsVisible() bool {
 }
 
 func (c *DebugComponent) SetVisible(visible bool) {
+	if visible && !c.isVisible {
+		// A reopened panel starts following the newest events
+		c.lineEntries = nil
+	}
 	c.isVisible = visible
 }
 
@@ -177,12 +221,36 @@ func (c *DebugComponent) ToggleVisibility() {
 	c.isVisible = !c.isVisible
 }
 
-func (c *DebugComponent) scrollUp(g *gocui.Gui, v *gocui.View) error {
-	return c.ScrollUp()
+func (c *DebugComponent) cursorUp(g *gocui.Gui, v *gocui.View) error {
+	c.selectLine(c.selectedLine() - 1)
+	return nil
+}
+
+func (c *DebugComponent) cursorDown(g *gocui.Gui, v *gocui.View) error {
+	c.selectLine(c.selectedLine() + 1)
+	return nil
 }
 
-func (c *DebugComponent) scrollDown(g *gocui.Gui, v *gocui.View) error {
-	return c.ScrollDown()
+// toggleSelectedEntry expands or collapses the entry under the cursor,
+// including when the cursor is on one of its detail lines.
+func (c *DebugComponent) toggleSelectedEntry(g *gocui.Gui, v *gocui.View) error {
+	line := c.selectedLine()
+	if line < 0 || line >= len(c.lineEntries) || c.lineEntries[line] == 0 {
+		return nil
+	}
+	id := c.lineEntries[line]
+	c.debugState.ToggleExpanded(id)
+	if err := c.Render(); err != nil {
+		return err
+	}
+	// Keep the cursor on the entry's own row
+	for i, entryID := range c.lineEntries {
+		if entryID == id {
+			c.selectLine(i)
+			break
+		}
+	}
+	return nil
 }
 
 func (c *DebugComponent) clearDebugMessages(g *gocui.Gui, v *gocui.View) error {
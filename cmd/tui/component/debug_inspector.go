@@ -0,0 +1,117 @@
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/state"
+)
+
+// DebugLineKind tells the debug panel how to style a line.
+type DebugLineKind int
+
+const (
+	// DebugLineTurn heads the entries of one chat turn
+	DebugLineTurn DebugLineKind = iota
+	// DebugLineEntry is the one-line row of an event
+	DebugLineEntry
+	// DebugLineDetail is part of an expanded entry's JSON
+	DebugLineDetail
+)
+
+// DebugLine is one plain-text line of the event inspector.
+type DebugLine struct {
+	Text    string
+	Kind    DebugLineKind
+	EntryID int // 0 for turn headers
+	Failed  bool
+}
+
+const debugTurnPreviewLength = 60
+
+// FormatDebugInspector lays entries out grouped under their turns. Entries
+// with details get a ▸ marker, ▾ when expanded, followed by their details
+// as indented JSON.
+func FormatDebugInspector(entries []state.DebugEntry, turns []state.DebugTurn, expanded func(id int) bool) []DebugLine {
+	var lines []DebugLine
+	currentTurn := -1
+	for _, entry := range entries {
+		if entry.Turn != currentTurn {
+			currentTurn = entry.Turn
+			lines = append(lines, DebugLine{Text: debugTurnHeader(currentTurn, turns), Kind: DebugLineTurn})
+		}
+
+		marker := " "
+		isExpanded := len(entry.Details) > 0 && expanded(entry.ID)
+		if len(entry.Details) > 0 {
+			marker = "▸"
+			if isExpanded {
+				marker = "▾"
+			}
+		}
+		row := fmt.Sprintf("%s %s  %-26s", marker, entry.Time.Format("15:04:05.000"), entry.Type)
+		if entry.ToolName != "" {
+			row += "  " + entry.ToolName
+		}
+		if entry.Summary != "" {
+			row += "  " + firstLine(entry.Summary)
+		}
+		lines = append(lines, DebugLine{
+			Text:    strings.TrimRight(row, " "),
+			Kind:    DebugLineEntry,
+			EntryID: entry.ID,
+			Failed:  entry.Failed,
+		})
+
+		if isExpanded {
+			for _, detail := range strings.Split(debugDetailsJSON(entry.Details), "\n") {
+				lines = append(lines, DebugLine{Text: "    " + detail, Kind: DebugLineDetail, EntryID: entry.ID})
+			}
+		}
+	}
+	return lines
+}
+
+// DebugInspectorText renders lines as plain text for copying and export.
+func DebugInspectorText(lines []DebugLine) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line.Text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func debugTurnHeader(number int, turns []state.DebugTurn) string {
+	if number == 0 {
+		return "── Before first turn"
+	}
+	for _, turn := range turns {
+		if turn.Number != number {
+			continue
+		}
+		message := firstLine(turn.Message)
+		if len([]rune(message)) > debugTurnPreviewLength {
+			message = string([]rune(message)[:debugTurnPreviewLength]) + "…"
+		}
+		return fmt.Sprintf("── Turn %d · %s", number, message)
+	}
+	return fmt.Sprintf("── Turn %d", number)
+}
+
+func debugDetailsJSON(details map[string]any) string {
+	data, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", details)
+	}
+	return string(data)
+}
+
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i] + " …"
+	}
+	return text
+}
@@ -0,0 +1,40 @@
+package component
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDebugInspector(t *testing.T) {
+	at := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	entries := []state.DebugEntry{
+		{ID: 1, Turn: 1, Time: at, Type: "chat.started", Summary: "fix the tests"},
+		{ID: 2, Turn: 1, Time: at, Type: "tool.executed", ToolName: "bash", Summary: "Executed",
+			Details: map[string]any{"parameters": map[string]any{"command": "go test"}}},
+		{ID: 3, Turn: 1, Time: at, Type: "tool.executed", ToolName: "readFile", Summary: "Failed: not found",
+			Failed: true, Details: map[string]any{"result": nil}},
+	}
+	turns := []state.DebugTurn{{Number: 1, RequestID: "req", Message: "fix the tests\nplease"}}
+	expanded := func(id int) bool { return id == 2 }
+
+	text := DebugInspectorText(FormatDebugInspector(entries, turns, expanded))
+
+	assert.Equal(t, `── Turn 1 · fix the tests …
+  15:04:05.000  chat.started                fix the tests
+▾ 15:04:05.000  tool.executed               bash  Executed
+    {
+      "parameters": {
+        "command": "go test"
+      }
+    }
+▸ 15:04:05.000  tool.executed               readFile  Failed: not found
+`, text)
+
+	lines := FormatDebugInspector(entries, turns, expanded)
+	assert.Equal(t, DebugLineTurn, lines[0].Kind)
+	assert.Equal(t, 2, lines[3].EntryID, "detail lines belong to their entry")
+	assert.True(t, lines[len(lines)-1].Failed)
+}
@@ -16,6 +16,7 @@ type DiffViewerComponent struct {
 	content   string
 	title     string
 	isVisible bool
+	hunks     []int // line each hunk starts on in the last render
 }
 
 func NewDiffViewerComponent(gui types.Gui, title string, configManager *helpers.ConfigManager, eventBus *events.CommandEventBus) *DiffViewerComponent {
@@ -71,6 +72,13 @@ func NewDiffViewerComponent(gui types.Gui, title string, configManager *helpers.
 	// (Can add more as needed when diff-generating commands are implemented)
 	_ = diffViewerUpdateHandler // Will be used when diff commands are implemented
 
+	// Redraw in the new layout when :diff changes it
+	eventBus.Subscribe("diff.mode.changed", func(e interface{}) {
+		ctx.gui.PostUIUpdate(func() {
+			ctx.Render()
+		})
+	})
+
 	return ctx
 }
 
@@ -116,6 +124,16 @@ func (c *DiffViewerComponent) GetKeybindings() []*types.KeyBinding {
 			Key:     gocui.KeyEnd,
 			Handler: c.goToBottom,
 		},
+		{
+			View:    c.viewName,
+			Key:     'n',
+			Handler: c.nextHunk,
+		},
+		{
+			View:    c.viewName,
+			Key:     'p',
+			Handler: c.prevHunk,
+		},
 	}
 }
 
@@ -136,10 +154,13 @@ func (c *DiffViewerComponent) Render() error {
 
 	v.Clear()
 
+	c.hunks = nil
 	if c.content != "" {
-		// Process diff content with theme colors
-		formattedContent := c.FormatDiff(c.content)
-		fmt.Fprint(v, formattedContent)
+		// Process diff content with theme colors, in the configured layout
+		width, _ := v.Size()
+		rendered := c.RenderDiff(c.content, width)
+		c.hunks = rendered.Hunks
+		fmt.Fprint(v, rendered.Text)
 	}
 
 	return nil
@@ -173,8 +194,9 @@ func (c *DiffViewerComponent) SetTitle(title string) {
 	c.BaseComponent.SetTitle(fmt.Sprintf(" %s ", title))
 }
 
-// FormatDiff applies diff theme colors to diff content
-func (c *DiffViewerComponent) FormatDiff(content string) string {
+// RenderDiff lays diff content out in the configured mode, width columns
+// wide, with diff theme colors
+func (c *DiffViewerComponent) RenderDiff(content string, width int) presentation.DiffView {
 	config := c.GetConfig()
 
 	// Get diff theme - use custom theme if set, otherwise use mapping
@@ -188,7 +210,42 @@ func (c *DiffViewerComponent) FormatDiff(content string) string {
 
 	diffTheme := presentation.GetDiffTheme(diffThemeName)
 	formatter := presentation.NewDiffFormatter(diffTheme)
-	return formatter.Format(content)
+	return formatter.RenderDiff(content, presentation.DiffViewOptions{
+		Mode:     config.DiffMode,
+		Width:    width,
+		Collapse: config.IsDiffCollapseEnabled(),
+		ASCII:    config.IsAccessibleEnabled(),
+	})
+}
+
+// NextHunk scrolls the hunk after the top of the view to the top
+func (c *DiffViewerComponent) NextHunk() error {
+	v := c.GetView()
+	if v == nil {
+		return nil
+	}
+	ox, oy := v.Origin()
+	for _, line := range c.hunks {
+		if line > oy {
+			return v.SetOrigin(ox, line)
+		}
+	}
+	return nil
+}
+
+// PrevHunk scrolls the hunk before the top of the view to the top
+func (c *DiffViewerComponent) PrevHunk() error {
+	v := c.GetView()
+	if v == nil {
+		return nil
+	}
+	ox, oy := v.Origin()
+	for i := len(c.hunks) - 1; i >= 0; i-- {
+		if c.hunks[i] < oy {
+			return v.SetOrigin(ox, c.hunks[i])
+		}
+	}
+	return nil
 }
 
 // Internal keybinding handlers
@@ -200,6 +257,14 @@ func (c *DiffViewerComponent) scrollDown(g *gocui.Gui, v *gocui.View) error {
 	return c.ScrollDown()
 }
 
+func (c *DiffViewerComponent) nextHunk(g *gocui.Gui, v *gocui.View) error {
+	return c.NextHunk()
+}
+
+func (c *DiffViewerComponent) prevHunk(g *gocui.Gui, v *gocui.View) error {
+	return c.PrevHunk()
+}
+
 func (c *DiffViewerComponent) scrollLeft(g *gocui.Gui, v *gocui.View) error {
 	ox, oy := v.Origin()
 	if ox > 0 {
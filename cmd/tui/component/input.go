@@ -1,7 +1,9 @@
 package component
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/awesome-gocui/gocui"
 	"github.com/gdamore/tcell/v2"
@@ -10,6 +12,7 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/shell"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/mattn/go-runewidth"
 )
 
 type InputComponent struct {
@@ -20,9 +23,27 @@ type InputComponent struct {
 	lastContent     string
 	shellEditor     shell.Shell
 	completer       *shell.Completer
+	pendingValue    *valueRequest
+	search          *historySearch
+	paste           pasteTracker
 }
 
-func NewInputComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, clipboard *helpers.Clipboard, historyManager history.ChatHistory, commandSuggester *shell.CommandSuggester, slashCommandSuggester *shell.SlashCommandSuggester) *InputComponent {
+// historySearch is a reverse incremental search through the history
+// (Ctrl+R): typing refines the query and each Ctrl+R finds an older match.
+type historySearch struct {
+	query    string
+	original string // Input to restore when the search is cancelled
+	match    int    // History index of the command shown
+}
+
+// valueRequest is a question asked on the input line; the next submit answers
+// it instead of being sent.
+type valueRequest struct {
+	label   string
+	onValue func(value string, ok bool)
+}
+
+func NewInputComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, clipboard *helpers.Clipboard, historyManager history.ChatHistory, commandSuggester *shell.CommandSuggester, slashCommandSuggester *shell.SlashCommandSuggester, promptTemplateSuggester *shell.PromptTemplateSuggester, modelSuggester *shell.ModelSuggester) *InputComponent {
 	completer := shell.NewCompleter()
 
 	shellEditor := shell.NewBasicShell(completer, historyManager)
@@ -66,6 +87,8 @@ func NewInputComponent(gui types.Gui, configManager *helpers.ConfigManager, comm
 
 	ctx.RegisterSuggester(commandSuggester)
 	ctx.RegisterSuggester(slashCommandSuggester)
+	ctx.RegisterSuggester(promptTemplateSuggester)
+	ctx.RegisterSuggester(modelSuggester)
 
 	return ctx
 }
@@ -79,10 +102,176 @@ func (c *InputComponent) SetView(view *gocui.View) {
 	c.BaseComponent.SetView(view)
 
 	if view != nil {
-		view.Editor = c.shellEditor
+		view.Editor = gocui.EditorFunc(c.edit)
 	}
 }
 
+// edit passes keys to the shell editor. While searching history, typing
+// refines the search instead and any other key ends it, keeping the match.
+func (c *InputComponent) edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	if c.search != nil {
+		switch {
+		case key == gocui.KeySpace || (ch != 0 && mod&gocui.ModAlt == 0):
+			if ch == 0 {
+				ch = ' '
+			}
+			c.search.query += string(ch)
+			c.showSearchMatch(v, c.search.match)
+			return
+		case key == gocui.KeyBackspace || key == gocui.KeyBackspace2:
+			if c.search.query != "" {
+				c.search.query = c.search.query[:len(c.search.query)-1]
+				c.showSearchMatch(v, len(c.history.GetHistory())-1)
+			}
+			return
+		default:
+			c.endSearch()
+		}
+	}
+
+	text := typedText(key, ch, mod)
+	if c.paste.active && (text == "" || time.Since(c.paste.lastKey) >= pasteBurstGap) {
+		c.finishPaste()
+	}
+	if text != "" && c.paste.key(time.Now(), text, c.shellEditor.GetInputBuffer()) {
+		c.schedulePasteEnd()
+		if key == gocui.KeyTab {
+			// Pasted tabs are text, not completion requests
+			key, ch = 0, '\t'
+		}
+	}
+	c.shellEditor.Edit(v, key, ch, mod)
+}
+
+// typedText returns what a key types into the input, or "" for keys that
+// edit or move instead
+func typedText(key gocui.Key, ch rune, mod gocui.Modifier) string {
+	switch {
+	case ch != 0 && mod&gocui.ModAlt == 0:
+		return string(ch)
+	case key == gocui.KeySpace:
+		return " "
+	case key == gocui.KeyTab:
+		return "\t"
+	default:
+		return ""
+	}
+}
+
+// schedulePasteEnd finishes the paste once its keys stop coming
+func (c *InputComponent) schedulePasteEnd() {
+	if c.paste.timer != nil {
+		c.paste.timer.Stop()
+	}
+	c.paste.timer = time.AfterFunc(pasteSettle, func() {
+		c.gui.PostUIUpdate(func() {
+			if c.paste.active && time.Since(c.paste.lastKey) >= pasteBurstGap {
+				c.finishPaste()
+			}
+		})
+	})
+}
+
+// finishPaste folds a paste of several lines into a placeholder, and
+// offers to attach files dropped onto the terminal
+func (c *InputComponent) finishPaste() {
+	input := c.shellEditor.GetInputBuffer()
+	text, start, end := c.paste.finish(input)
+	if paths := helpers.DroppedPaths(text); paths != nil && c.pendingValue == nil {
+		c.offerDroppedFiles(input[:start]+input[end:], start, input[start:end], paths)
+		return
+	}
+	folded, ok := c.paste.fold(input, text, start, end)
+	if v := c.GetView(); ok && v != nil {
+		c.shellEditor.SetInputBuffer(folded, v)
+	}
+}
+
+// offerDroppedFiles asks what to do with files dropped into the draft at
+// position at: attach them as context for the next message, mention them,
+// or keep the raw paths the terminal typed
+func (c *InputComponent) offerDroppedFiles(draft string, at int, raw string, paths []string) {
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = helpers.DisplayPath(path)
+	}
+
+	label := fmt.Sprintf("Dropped %s: a attach as context, m @-mention, Enter keep the path", strings.Join(names, ", "))
+	c.AskValue(label, func(value string, ok bool) {
+		insert := raw
+		switch {
+		case ok && value == "a":
+			for _, path := range paths {
+				c.commandEventBus.Emit("file.attach", path)
+			}
+			insert = ""
+		case ok && value == "m":
+			mentions := make([]string, len(names))
+			for i, name := range names {
+				mentions[i] = fileMention(name)
+			}
+			insert = strings.Join(mentions, " ") + " "
+		}
+		c.SetText(draft[:at] + insert + draft[at:])
+	})
+}
+
+// fileMention refers to a file in a message as @path, quoted when the
+// path has spaces
+func fileMention(name string) string {
+	if strings.ContainsAny(name, " \t") {
+		return `@"` + name + `"`
+	}
+	return "@" + name
+}
+
+// handleReverseSearch starts a history search, or moves to the next older
+// match when one is running
+func (c *InputComponent) handleReverseSearch(g *gocui.Gui, v *gocui.View) error {
+	if c.pendingValue != nil {
+		return nil
+	}
+	if c.search == nil {
+		count := len(c.history.GetHistory())
+		c.search = &historySearch{original: c.shellEditor.GetInputBuffer(), match: count}
+		c.showSearchMatch(v, count-1)
+		return nil
+	}
+	c.showSearchMatch(v, c.search.match-1)
+	return nil
+}
+
+// showSearchMatch shows the newest command at or before history index
+// from that contains the query
+func (c *InputComponent) showSearchMatch(v *gocui.View, from int) {
+	if c.search.query == "" {
+		c.SetTitle(" reverse-i-search (type to search, Ctrl+R older, Esc cancel) ")
+		return
+	}
+	commands := c.history.GetHistory()
+	i := history.FindPrevious(commands, c.search.query, from)
+	if i < 0 {
+		c.SetTitle(fmt.Sprintf(" failed reverse-i-search: %s ", c.search.query))
+		return
+	}
+	c.search.match = i
+	c.shellEditor.SetInputBuffer(commands[i], v)
+	c.SetTitle(fmt.Sprintf(" reverse-i-search: %s ", c.search.query))
+}
+
+// endSearch leaves search mode with the match in the input
+func (c *InputComponent) endSearch() {
+	c.search = nil
+	c.SetTitle("")
+}
+
+// cancelSearch leaves search mode and restores what was typed before it
+func (c *InputComponent) cancelSearch(v *gocui.View) {
+	original := c.search.original
+	c.endSearch()
+	c.shellEditor.SetInputBuffer(original, v)
+}
+
 func (c *InputComponent) GetKeybindings() []*types.KeyBinding {
 	return []*types.KeyBinding{
 		{
@@ -100,6 +289,11 @@ func (c *InputComponent) GetKeybindings() []*types.KeyBinding {
 			Key:     gocui.KeyArrowDown,
 			Handler: c.navigateHistoryDown,
 		},
+		{
+			View:    c.viewName,
+			Key:     gocui.KeyCtrlR,
+			Handler: c.handleReverseSearch,
+		},
 		{
 			View:    c.viewName,
 			Key:     gocui.KeyCtrlC,
@@ -206,7 +400,55 @@ func (c *InputComponent) GetKeybindings() []*types.KeyBinding {
 	}
 }
 
+// Text returns what has been typed and not sent yet.
+func (c *InputComponent) Text() string {
+	return c.shellEditor.GetInputBuffer()
+}
+
+// SetText replaces the input content and moves the cursor to its end.
+func (c *InputComponent) SetText(text string) {
+	if v := c.GetView(); v != nil {
+		c.shellEditor.SetInputBuffer(text, v)
+	}
+}
+
+// AskValue uses the input line to ask the user for a single value. The next
+// submit calls onValue with what was typed instead of sending it; Esc calls
+// onValue with ok set to false.
+func (c *InputComponent) AskValue(label string, onValue func(value string, ok bool)) {
+	c.pendingValue = &valueRequest{label: label, onValue: onValue}
+	c.SetTitle(" " + label + " ")
+	if v := c.GetView(); v != nil {
+		c.shellEditor.ClearInput(v)
+	}
+}
+
+func (c *InputComponent) answerValue(v *gocui.View, value string, ok bool) {
+	request := c.pendingValue
+	c.pendingValue = nil
+	c.SetTitle("")
+	c.shellEditor.ClearInput(v)
+	request.onValue(value, ok)
+}
+
 func (c *InputComponent) handleSubmit(g *gocui.Gui, v *gocui.View) error {
+	// Enter in the middle of a paste is a line break in it
+	if c.paste.key(time.Now(), "\n", c.shellEditor.GetInputBuffer()) {
+		c.schedulePasteEnd()
+		return nil
+	}
+	if c.paste.active {
+		c.finishPaste()
+	}
+
+	if c.pendingValue != nil {
+		c.answerValue(v, c.paste.expand(strings.TrimSpace(c.shellEditor.GetInputBuffer())), true)
+		return nil
+	}
+	if c.search != nil {
+		c.endSearch()
+	}
+
 	input := strings.TrimSpace(c.shellEditor.GetInputBuffer())
 	if input == "" {
 		return nil
@@ -217,6 +459,8 @@ func (c *InputComponent) handleSubmit(g *gocui.Gui, v *gocui.View) error {
 	input = strings.ReplaceAll(input, "\n", " ")
 	// Clean up any extra spaces that might result from the replacement
 	input = strings.Join(strings.Fields(input), " ")
+	// Pastes keep their line breaks
+	input = c.paste.expand(input)
 
 	c.history.AddCommand(input)
 	c.shellEditor.ResetHistoryNavigation() // History navigation reset is now handled by BasicShell
@@ -239,6 +483,15 @@ func (c *InputComponent) handleSubmit(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (c *InputComponent) handleEsc(g *gocui.Gui, v *gocui.View) error {
+	if c.pendingValue != nil {
+		c.answerValue(v, "", false)
+		return nil
+	}
+	if c.search != nil {
+		c.cancelSearch(v)
+		return nil
+	}
+
 	c.commandEventBus.Emit("user.input.cancel", "")
 
 	// Ensure the input field remains properly rendered after ESC
@@ -256,16 +509,26 @@ func (c *InputComponent) handleEsc(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (c *InputComponent) navigateHistoryUp(g *gocui.Gui, v *gocui.View) error {
+	if c.search != nil {
+		c.endSearch()
+	}
 	c.shellEditor.NavigateHistoryUp(v)
 	return nil
 }
 
 func (c *InputComponent) navigateHistoryDown(g *gocui.Gui, v *gocui.View) error {
+	if c.search != nil {
+		c.endSearch()
+	}
 	c.shellEditor.NavigateHistoryDown(v)
 	return nil
 }
 
 func (c *InputComponent) clearInput(g *gocui.Gui, v *gocui.View) error {
+	if c.search != nil {
+		c.cancelSearch(v)
+		return nil
+	}
 	input := strings.TrimSpace(c.shellEditor.GetInputBuffer())
 
 	if input == "" {
@@ -274,6 +537,7 @@ func (c *InputComponent) clearInput(g *gocui.Gui, v *gocui.View) error {
 
 	c.shellEditor.ClearInput(v)
 	c.shellEditor.ResetHistoryNavigation()
+	c.paste.expand("")
 	return nil
 }
 
@@ -299,6 +563,8 @@ func combineAtPosition(currentContent string, position int, newContent string) s
 	return before + newContent + after
 }
 
+// cursorToStringPosition turns a cursor position, whose x counts terminal
+// cells, into a byte offset in content
 func cursorToStringPosition(content string, cursorX, cursorY int) int {
 	if content == "" {
 		return 0
@@ -318,15 +584,19 @@ func cursorToStringPosition(content string, cursorX, cursorY int) int {
 		position += len(lines[i]) + 1
 	}
 
-	line := lines[cursorY]
-	if cursorX > len(line) {
-		cursorX = len(line)
+	// Wide characters take two cells; a cursor on the second lands after it
+	col := 0
+	for i, r := range lines[cursorY] {
+		if col >= cursorX {
+			return position + i
+		}
+		col += runewidth.RuneWidth(r)
 	}
-	position += cursorX
-
-	return position
+	return position + len(lines[cursorY])
 }
 
+// stringPositionToCursor turns a byte offset in content into a cursor
+// position whose x counts terminal cells
 func stringPositionToCursor(content string, position int) (int, int) {
 	if content == "" || position <= 0 {
 		return 0, 0
@@ -343,8 +613,7 @@ func stringPositionToCursor(content string, position int) (int, int) {
 		lineLength := len(line)
 
 		if currentPos+lineLength >= position {
-			x := position - currentPos
-			return x, lineIndex
+			return runewidth.StringWidth(line[:position-currentPos]), lineIndex
 		}
 
 		currentPos += lineLength + 1
@@ -352,7 +621,7 @@ func stringPositionToCursor(content string, position int) (int, int) {
 
 	if len(lines) > 0 {
 		lastLine := lines[len(lines)-1]
-		return len(lastLine), len(lines) - 1
+		return runewidth.StringWidth(lastLine), len(lines) - 1
 	}
 
 	return 0, 0
@@ -151,6 +151,13 @@ func TestCursorToStringPosition(t *testing.T) {
 			cursorY:  5,
 			expected: 11, // Length of entire content
 		},
+		{
+			name:     "after wide characters",
+			content:  "日本 ok",
+			cursorX:  4, // Two cells each
+			cursorY:  0,
+			expected: 6, // Three bytes each
+		},
 	}
 
 	for _, tt := range tests {
@@ -218,6 +225,13 @@ func TestStringPositionToCursor(t *testing.T) {
 			expectedX: 5,
 			expectedY: 0,
 		},
+		{
+			name:      "after wide characters",
+			content:   "日本 ok",
+			position:  6,
+			expectedX: 4,
+			expectedY: 0,
+		},
 		{
 			name:      "negative position",
 			content:   "Test",
@@ -10,8 +10,13 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/presentation"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
 )
 
+// trimMarker flags the context part that is trimmed first as the
+// conversation grows
+const trimMarker = "✂"
+
 // LLMContextViewerComponent provides a full-screen modal for viewing LLM context data.
 // It displays context parts organized by provider with dual-panel navigation similar
 // to the previous TUI implementation.
@@ -66,7 +71,7 @@ func (c *LLMContextViewerComponent) setupInternalLayout() {
 				Children: []*boxlayout.Box{
 					{
 						Window: "context-keys", // Left panel: context keys list
-						Size:   25,             // Fixed width for keys (slightly wider than help dialog)
+						Size:   32,             // Fixed width for keys and their token counts
 					},
 					{
 						Window: "context-content", // Right panel: context content
@@ -182,6 +187,13 @@ func (c *LLMContextViewerComponent) GetKeybindings() []*types.KeyBinding {
 			Mod:     gocui.ModNone,
 			Handler: c.handleRefresh,
 		},
+		// Include or leave out the selected part
+		{
+			View:    c.viewName,
+			Key:     gocui.KeySpace,
+			Mod:     gocui.ModNone,
+			Handler: c.handleToggle,
+		},
 	}
 
 	// Also bind to internal views for better focus handling
@@ -259,6 +271,12 @@ func (c *LLMContextViewerComponent) GetKeybindings() []*types.KeyBinding {
 				Mod:     gocui.ModNone,
 				Handler: c.handleRefresh,
 			},
+			{
+				View:    viewName,
+				Key:     gocui.KeySpace,
+				Mod:     gocui.ModNone,
+				Handler: c.handleToggle,
+			},
 			{
 				View:    viewName,
 				Key:     gocui.KeyEsc,
@@ -332,6 +350,17 @@ func (c *LLMContextViewerComponent) handleRefresh(g *gocui.Gui, v *gocui.View) e
 	return nil
 }
 
+// handleToggle includes or leaves out the selected part from the following
+// turns
+func (c *LLMContextViewerComponent) handleToggle(g *gocui.Gui, v *gocui.View) error {
+	if c.selectedContextKey >= len(c.contextKeys) {
+		return nil
+	}
+	// Like refresh, a failed toggle leaves the viewer as it was
+	_ = c.dataProvider.HandleComponentEvent("toggle", c.contextKeys[c.selectedContextKey])
+	return nil
+}
+
 func (c *LLMContextViewerComponent) getInternalViewName(windowName string) string {
 	return c.viewName + "-" + windowName
 }
@@ -430,18 +459,84 @@ func (c *LLMContextViewerComponent) renderContextKeysPanel() error {
 		return nil
 	}
 
-	// Render context keys with arrow indicator
+	// Render context keys with arrow indicator, token count and trim marker
+	usage := c.dataProvider.GetContextUsage()
+	nextToTrim := ""
+	if usage != nil {
+		nextToTrim = usage.NextToTrim()
+	}
 	for i, key := range c.contextKeys {
+		arrow := "  "
 		if i == c.selectedContextKey {
-			fmt.Fprintf(view, "► %-20s\n", key)
-		} else {
-			fmt.Fprintf(view, "  %-20s\n", key)
+			arrow = "► "
+		}
+		tokens := ""
+		if part := findPartUsage(usage, key); part != nil {
+			tokens = formatTokenCount(int32(part.Tokens))
+		}
+		state := "● "
+		if key == genie.InstructionsContextKey {
+			state = "  "
+		} else if !c.dataProvider.IsContextPartEnabled(key) {
+			state = "○ "
 		}
+		marker := ""
+		if key == nextToTrim {
+			marker = " " + trimMarker
+		}
+		fmt.Fprintf(view, "%s%s%-14s %6s%s\n", arrow, state, key, tokens, marker)
 	}
 
 	return nil
 }
 
+// findPartUsage returns the token count of a context part, or nil when it
+// was not counted
+func findPartUsage(usage *genie.ContextUsage, key string) *genie.ContextPartUsage {
+	if usage == nil {
+		return nil
+	}
+	for i := range usage.Parts {
+		if usage.Parts[i].Key == key {
+			return &usage.Parts[i]
+		}
+	}
+	return nil
+}
+
+// partTitle describes the selected part's size against its budget
+func partTitle(key string, part *genie.ContextPartUsage) string {
+	if part == nil {
+		return fmt.Sprintf(" {%s} ", key)
+	}
+	if part.Budget > 0 {
+		return fmt.Sprintf(" {%s} %s of %s tokens ", key, formatTokenCount(int32(part.Tokens)), formatTokenCount(int32(part.Budget)))
+	}
+	return fmt.Sprintf(" {%s} %s tokens ", key, formatTokenCount(int32(part.Tokens)))
+}
+
+// usageSummary describes how much of the model's window the context takes
+func usageSummary(usage *genie.ContextUsage) string {
+	if usage == nil {
+		return ""
+	}
+	summary := formatTokenCount(int32(usage.TotalTokens())) + " tokens"
+	if usage.Estimated {
+		summary = "~" + summary
+	}
+	if usage.ContextWindow > 0 {
+		summary += fmt.Sprintf(" (%.1f%% of %s", usage.WindowPercent(), formatTokenCount(int32(usage.ContextWindow)))
+		if usage.Model != "" {
+			summary += " " + usage.Model
+		}
+		summary += ")"
+	}
+	if next := usage.NextToTrim(); next != "" {
+		summary += fmt.Sprintf(" · %s %s trims next", trimMarker, next)
+	}
+	return summary
+}
+
 func (c *LLMContextViewerComponent) renderContextContentPanel() error {
 	view := c.GetInternalView("context-content")
 	if view == nil {
@@ -471,7 +566,10 @@ func (c *LLMContextViewerComponent) renderContextContentPanel() error {
 	contextParts := c.dataProvider.GetContextData()
 	content, exists := contextParts[selectedKey]
 
-	view.Title = fmt.Sprintf(" {%s} ", selectedKey)
+	view.Title = partTitle(selectedKey, findPartUsage(c.dataProvider.GetContextUsage(), selectedKey))
+	if !c.dataProvider.IsContextPartEnabled(selectedKey) {
+		view.Title += "(off, Space turns it on) "
+	}
 
 	if !exists || content == "" {
 		theme := c.GetTheme()
@@ -527,7 +625,10 @@ func (c *LLMContextViewerComponent) renderNavigationTipsPanel() error {
 	view.Title = ""        // No title needed
 
 	// Simple navigation instructions with left padding like status bar, using secondary color
-	text := "↑↓ Navigate | PgUp/PgDn Scroll | Home/End Jump | r Refresh | Esc/q Close"
+	text := "↑↓ Navigate | Space On/Off | PgUp/PgDn Scroll | Home/End Jump | r Refresh | Esc/q Close"
+	if summary := usageSummary(c.dataProvider.GetContextUsage()); summary != "" {
+		text = summary + " | " + text
+	}
 	text = " " + text // Add left padding like status bar
 
 	// Apply secondary color for system UI elements
@@ -12,11 +12,15 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/types"
 )
 
+// MessagesComponent shows the conversation. Messages are kept formatted in
+// a line-indexed transcript and only the lines that fit the view are
+// written to it, so rendering cost does not grow with the session.
 type MessagesComponent struct {
 	*BaseComponent
-	*ScrollableBase
 	stateAccessor    *state.ChatState
 	messageFormatter *presentation.MessageFormatter
+	transcript       *transcript
+	top              int // Transcript line shown first, as of the last draw
 }
 
 func NewMessagesComponent(gui types.Gui, state *state.ChatState, configManager *helpers.ConfigManager, eventBus *events.CommandEventBus) *MessagesComponent {
@@ -28,11 +32,9 @@ func NewMessagesComponent(gui types.Gui, state *state.ChatState, configManager *
 		BaseComponent:    NewBaseComponent("messages", "messages", gui, configManager),
 		stateAccessor:    state,
 		messageFormatter: mf,
+		transcript:       newTranscript(),
 	}
 
-	// Initialize ScrollableBase with a getter for this component's view
-	ctx.ScrollableBase = NewScrollableBase(ctx.GetView)
-
 	// Configure MessagesComponent specific properties based on config
 	config := configManager.GetConfig()
 	showBorder := config.IsShowMessagesBorderEnabled()
@@ -46,8 +48,8 @@ func NewMessagesComponent(gui types.Gui, state *state.ChatState, configManager *
 	ctx.SetWindowProperties(types.WindowProperties{
 		Focusable:   true,
 		Editable:    false, // Back to non-editable
-		Wrap:        true,
-		Autoscroll:  true,
+		Wrap:        false, // The transcript wraps lines itself
+		Autoscroll:  false, // Scrolling moves the transcript window instead
 		Highlight:   true,
 		Frame:       showBorder,
 		BorderStyle: types.BorderStyleSingle,
@@ -83,8 +85,9 @@ func NewMessagesComponent(gui types.Gui, state *state.ChatState, configManager *
 	eventBus.Subscribe("theme.changed", func(e interface{}) {
 		// Recreate message formatter with new theme
 		if mf, err := presentation.NewMessageFormatter(ctx.GetConfig(), ctx.GetTheme()); err == nil {
-			ctx.messageFormatter = mf
 			ctx.gui.PostUIUpdate(func() {
+				ctx.messageFormatter = mf
+				ctx.transcript.invalidate()
 				ctx.Render()
 			})
 		}
@@ -118,6 +121,11 @@ func (c *MessagesComponent) GetKeybindings() []*types.KeyBinding {
 	}
 }
 
+// Messages returns the messages of the transcript.
+func (c *MessagesComponent) Messages() []types.Message {
+	return c.stateAccessor.GetMessages()
+}
+
 func (c *MessagesComponent) Render() error {
 	// Call base render to apply theme colors
 	if err := c.BaseComponent.Render(); err != nil {
@@ -129,30 +137,93 @@ func (c *MessagesComponent) Render() error {
 		return nil
 	}
 
-	v.Clear()
+	c.transcript.sync(c.stateAccessor.GetMessages())
+	c.draw()
+	return nil
+}
 
-	// Get current view width for dynamic formatting
-	width, _ := v.Size()
+// draw writes the visible window of the transcript to the view. Messages
+// are only formatted when they are in the window and changed or were
+// wrapped at another width.
+func (c *MessagesComponent) draw() {
+	v := c.GetView()
+	if v == nil {
+		return
+	}
 
-	messages := c.stateAccessor.GetMessages()
-	for _, msg := range messages {
-		formatted := c.messageFormatter.FormatMessageWithWidth(msg, width)
-		fmt.Fprint(v, formatted)
+	width, height := v.Size()
+	c.transcript.setWidth(width)
+	lines, top := c.transcript.window(height, c.messageFormatter.FormatMessageWithCodeIndex)
+	c.top = top
+
+	v.Clear()
+	v.SetOrigin(0, 0)
+	for i, line := range lines {
+		if i > 0 {
+			fmt.Fprint(v, "\n")
+		}
+		// Every line carries its own colors, starting from a clean slate
+		fmt.Fprint(v, "\033[0m", line)
 	}
+}
 
-	c.ScrollToBottom()
+func (c *MessagesComponent) viewHeight() int {
+	if v := c.GetView(); v != nil {
+		_, height := v.Size()
+		return max(height, 1)
+	}
+	return 1
+}
 
+// ScrollUp scrolls the transcript up by one line
+func (c *MessagesComponent) ScrollUp() error {
+	c.transcript.scroll(-1, c.viewHeight())
+	c.draw()
+	return nil
+}
+
+// ScrollDown scrolls the transcript down by one line
+func (c *MessagesComponent) ScrollDown() error {
+	c.transcript.scroll(1, c.viewHeight())
+	c.draw()
+	return nil
+}
+
+// PageUp scrolls the transcript up by one page
+func (c *MessagesComponent) PageUp() error {
+	height := c.viewHeight()
+	c.transcript.scroll(-height, height)
+	c.draw()
+	return nil
+}
+
+// PageDown scrolls the transcript down by one page
+func (c *MessagesComponent) PageDown() error {
+	height := c.viewHeight()
+	c.transcript.scroll(height, height)
+	c.draw()
+	return nil
+}
+
+// ScrollToTop shows the first message
+func (c *MessagesComponent) ScrollToTop() error {
+	c.transcript.scrollToTop(c.viewHeight())
+	c.draw()
+	return nil
+}
+
+// ScrollToBottom shows the newest lines and keeps following them
+func (c *MessagesComponent) ScrollToBottom() error {
+	c.transcript.scrollToBottom()
+	c.draw()
 	return nil
 }
 
 func (c *MessagesComponent) copySelectedMessage(g *gocui.Gui, v *gocui.View) error {
 	_, cy := v.Cursor()
-	_, oy := v.Origin()
-	lineNum := oy + cy
 
-	messages := c.stateAccessor.GetMessages()
-	if lineNum < len(messages) {
-		_ = messages[lineNum].Content
+	if msg, ok := c.transcript.messageAt(c.top + cy); ok {
+		_ = msg.Content
 		// TODO: Implement clipboard functionality
 		// For now, just log that we would copy
 	}
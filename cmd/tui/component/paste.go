@@ -0,0 +1,101 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Terminals type a paste into the input as a burst of keys, line breaks
+// arriving as Enter. gocui does not pass on tcell's bracketed paste events,
+// so a paste is told from typing by its speed: keys closer together than
+// pasteBurstGap belong to one paste, which is over once the keys pause for
+// pasteSettle.
+const (
+	pasteBurstGap = 10 * time.Millisecond
+	pasteSettle   = 40 * time.Millisecond
+)
+
+// pasteTracker gathers a paste typed into the input, so its line breaks
+// stay in one draft instead of each line being sent on its own. The input
+// shows a single line, so a paste of several lines is folded into a
+// placeholder such as "[pasted 120 lines]" and expanded on send.
+type pasteTracker struct {
+	lastKey   time.Time
+	lastText  string // What the last key typed, in case it started a paste
+	lastInput string // The input before the last key
+	active    bool
+	before    string          // The input before the paste
+	text      strings.Builder // The paste so far, line breaks included
+	timer     *time.Timer
+	pastes    map[string]string // Placeholder → pasted text
+}
+
+// key records a key that typed text into input, and reports whether it is
+// part of a paste
+func (p *pasteTracker) key(now time.Time, text, input string) bool {
+	inPaste := !p.lastKey.IsZero() && now.Sub(p.lastKey) < pasteBurstGap
+	if inPaste {
+		if !p.active {
+			// The key before this one started the paste
+			p.active = true
+			p.before = p.lastInput
+			p.text.Reset()
+			p.text.WriteString(p.lastText)
+		}
+		p.text.WriteString(text)
+	} else {
+		p.lastInput = input
+	}
+	p.lastKey, p.lastText = now, text
+	return inPaste
+}
+
+// finish ends the paste and returns what was pasted and where it went in
+// input, which holds it without its line breaks: input[start:end]
+func (p *pasteTracker) finish(input string) (text string, start, end int) {
+	p.active = false
+	text = strings.TrimRight(p.text.String(), "\n")
+	p.text.Reset()
+
+	// The paste went in at one place; everything around it is unchanged
+	prefix := 0
+	for prefix < len(p.before) && prefix < len(input) && p.before[prefix] == input[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(p.before)-prefix && suffix < len(input)-prefix &&
+		p.before[len(p.before)-1-suffix] == input[len(input)-1-suffix] {
+		suffix++
+	}
+	return text, prefix, len(input) - suffix
+}
+
+// fold replaces a paste of several lines at input[start:end] with its
+// placeholder. ok is false for a single line, which is left as it is.
+func (p *pasteTracker) fold(input, text string, start, end int) (folded string, ok bool) {
+	if !strings.Contains(text, "\n") {
+		return input, false
+	}
+
+	lines := strings.Count(text, "\n") + 1
+	label := fmt.Sprintf("[pasted %d lines]", lines)
+	for n := 2; p.pastes[label] != ""; n++ {
+		label = fmt.Sprintf("[pasted %d lines #%d]", lines, n)
+	}
+	if p.pastes == nil {
+		p.pastes = make(map[string]string)
+	}
+	p.pastes[label] = text
+	return input[:start] + label + input[end:], true
+}
+
+// expand replaces the placeholders left in input with what was pasted,
+// and forgets the pastes
+func (p *pasteTracker) expand(input string) string {
+	for label, text := range p.pastes {
+		input = strings.ReplaceAll(input, label, text)
+	}
+	p.pastes = nil
+	return input
+}
@@ -0,0 +1,66 @@
+package component
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// typeKeys feeds text to the tracker as keys gap apart, the way the input
+// component does, and returns the input they leave
+func typeKeys(p *pasteTracker, start time.Time, gap time.Duration, input string, text string) (string, time.Time) {
+	now := start
+	for _, r := range text {
+		now = now.Add(gap)
+		p.key(now, string(r), input)
+		if r != '\n' {
+			input += string(r)
+		}
+	}
+	return input, now
+}
+
+func finishAndFold(p *pasteTracker, input string) (string, bool) {
+	text, start, end := p.finish(input)
+	return p.fold(input, text, start, end)
+}
+
+func TestPasteTrackerFoldsMultiLinePaste(t *testing.T) {
+	p := &pasteTracker{}
+	input, _ := typeKeys(p, time.Now(), time.Millisecond, "explain ", "func a() {\n\treturn\n}\n")
+	assert.True(t, p.active)
+
+	text, start, end := p.finish(input)
+	assert.Equal(t, "func a() {\n\treturn\n}", text)
+	assert.Equal(t, "func a() {\treturn}", input[start:end])
+	folded, ok := p.fold(input, text, start, end)
+	assert.True(t, ok)
+	assert.Equal(t, "explain [pasted 3 lines]", folded)
+	assert.Equal(t, "explain func a() {\n\treturn\n}", p.expand(folded))
+	assert.Empty(t, p.pastes, "pastes are forgotten once sent")
+}
+
+func TestPasteTrackerIgnoresTyping(t *testing.T) {
+	p := &pasteTracker{}
+	input, _ := typeKeys(p, time.Now(), 80*time.Millisecond, "", "hello")
+	assert.False(t, p.active)
+	assert.Equal(t, "hello", input)
+
+	// A fast single-line paste stays as typed
+	input, _ = typeKeys(p, time.Now().Add(time.Second), time.Millisecond, input, " world")
+	folded, ok := finishAndFold(p, input)
+	assert.False(t, ok)
+	assert.Equal(t, "hello world", folded)
+}
+
+func TestPasteTrackerNumbersRepeatedPlaceholders(t *testing.T) {
+	p := &pasteTracker{}
+	input, now := typeKeys(p, time.Now(), time.Millisecond, "", "a\nb")
+	input, _ = finishAndFold(p, input)
+
+	input, _ = typeKeys(p, now.Add(time.Second), time.Millisecond, input+" ", "c\nd")
+	input, _ = finishAndFold(p, input)
+	assert.Equal(t, "[pasted 2 lines] [pasted 2 lines #2]", input)
+	assert.Equal(t, "a\nb c\nd", p.expand(input))
+}
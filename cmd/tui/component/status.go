@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/presentation"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
 )
 
 type StatusComponent struct {
@@ -20,12 +22,16 @@ type StatusComponent struct {
 	leftComponent   *StatusSectionComponent
 	centerComponent *StatusSectionComponent
 	rightComponent  *StatusSectionComponent
-	ticker          *time.Ticker
-	isRunning       bool
+	ticker          *time.Ticker // runs only while loading or confirming
+	loading         bool         // a request is in flight
 	startTime       time.Time
 	tokenCount      int32
+	sessionCost     float64
+	updateVersion   string // a newer release, once the update check finds one
+	confirmations   int    // confirmations waiting for the user, the one on screen included
+	workDir         string // the session's working directory
 	stopCh          chan struct{}
-	mu              sync.RWMutex // protects timer state
+	mu              sync.RWMutex // protects loading and ticker state
 }
 
 // formatTokenCount formats token count with K/M abbreviations
@@ -124,7 +130,6 @@ func NewStatusComponent(gui types.Gui, state types.IStateAccessor, configManager
 		leftComponent:   NewStatusSectionComponent("status-left", "status-left", gui, configManager),
 		centerComponent: NewStatusSectionComponent("status-center", "status-center", gui, configManager),
 		rightComponent:  NewStatusSectionComponent("status-right", "status-right", gui, configManager),
-		stopCh:          make(chan struct{}),
 	}
 
 	// Configure StatusComponent specific properties
@@ -154,8 +159,11 @@ func NewStatusComponent(gui types.Gui, state types.IStateAccessor, configManager
 		if activeCount, ok := e.(int); ok {
 			// Only start status updates for the first request
 			if activeCount == 1 {
-				ctx.startStatusUpdates()
+				ctx.setLoading(true)
 				ctx.tokenCount = 0
+				ctx.gui.PostUIUpdate(func() {
+					ctx.Render()
+				})
 			}
 		}
 	})
@@ -169,11 +177,22 @@ func NewStatusComponent(gui types.Gui, state types.IStateAccessor, configManager
 		}
 	})
 
+	eventBus.Subscribe("usage.cost", func(e interface{}) {
+		if cost, ok := e.(float64); ok {
+			ctx.mu.Lock()
+			ctx.sessionCost = cost
+			ctx.mu.Unlock()
+			ctx.gui.PostUIUpdate(func() {
+				ctx.Render()
+			})
+		}
+	})
+
 	eventBus.Subscribe("request.finished", func(e interface{}) {
 		if isLastRequest, ok := e.(bool); ok {
 			// Only stop status updates when all requests are done
 			if isLastRequest {
-				ctx.stopStatusUpdates()
+				ctx.setLoading(false)
 				// Reset to Ready status when all requests are done
 				ctx.SetLeftToReady()
 				ctx.gui.PostUIUpdate(func() {
@@ -183,6 +202,40 @@ func NewStatusComponent(gui types.Gui, state types.IStateAccessor, configManager
 		}
 	})
 
+	eventBus.Subscribe("update.available", func(e interface{}) {
+		if latest, ok := e.(string); ok {
+			ctx.mu.Lock()
+			ctx.updateVersion = latest
+			ctx.mu.Unlock()
+			ctx.gui.PostUIUpdate(func() {
+				ctx.Render()
+			})
+		}
+	})
+
+	// A confirmation animates its own spinner, with or without a request,
+	// and a badge counts the ones queued behind it
+	eventBus.Subscribe("confirmation.changed", func(e interface{}) {
+		ctx.mu.Lock()
+		if pending, ok := e.(int); ok {
+			ctx.confirmations = pending
+		}
+		ctx.syncTicker()
+		ctx.mu.Unlock()
+		ctx.gui.PostUIUpdate(func() {
+			ctx.Render()
+		})
+	})
+
+	eventBus.Subscribe("workdir.changed", func(e interface{}) {
+		if dir, ok := e.(string); ok {
+			ctx.SetWorkDir(dir)
+			ctx.gui.PostUIUpdate(func() {
+				ctx.Render()
+			})
+		}
+	})
+
 	// Set initial Ready status
 	ctx.SetLeftToReady()
 	ctx.gui.PostUIUpdate(func() {
@@ -192,41 +245,44 @@ func NewStatusComponent(gui types.Gui, state types.IStateAccessor, configManager
 	return ctx
 }
 
-func (c *StatusComponent) startStatusUpdates() {
+// setLoading records whether a request is in flight and starts or stops
+// the spinner to match
+func (c *StatusComponent) setLoading(loading bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Don't start if already running
-	if c.isRunning {
-		return
+	if loading && !c.loading {
+		c.startTime = time.Now()
 	}
+	c.loading = loading
+	c.syncTicker()
+}
 
-	c.startTime = time.Now()
-	c.ticker = time.NewTicker(100 * time.Millisecond) // Update 10 times per second for smooth spinner
-	c.isRunning = true
-	c.stopCh = make(chan struct{})
+// syncTicker runs the spinner ticker only while there is something to
+// animate: a request in flight or a confirmation waiting for the user.
+// Idle, nothing redraws until the next state change. Callers hold c.mu.
+func (c *StatusComponent) syncTicker() {
+	// A screen reader would announce every tick, so accessible mode shows
+	// one steady line instead of a spinner and a running timer
+	active := (c.loading || c.stateAccessor.IsWaitingConfirmation()) && !c.GetConfig().IsAccessibleEnabled()
 
-	go func() {
-		ticker := c.ticker
-		stopCh := c.stopCh
+	if !active {
+		c.stopTicker()
+		return
+	}
+	if c.ticker != nil {
+		return
+	}
 
-		defer func() {
-			c.mu.Lock()
-			c.isRunning = false
-			c.mu.Unlock()
-		}()
+	// 10 times per second for a smooth spinner, less often over SSH
+	ticker := time.NewTicker(c.GetConfig().StatusRefresh())
+	stopCh := make(chan struct{})
+	c.ticker, c.stopCh = ticker, stopCh
 
+	go func() {
 		for {
 			select {
 			case <-ticker.C:
-				c.mu.RLock()
-				isRunning := c.isRunning
-				c.mu.RUnlock()
-
-				if !isRunning {
-					return
-				}
-
 				if c.gui != nil {
 					c.gui.PostUIUpdate(func() {
 						c.Render()
@@ -239,39 +295,62 @@ func (c *StatusComponent) startStatusUpdates() {
 	}()
 }
 
-func (c *StatusComponent) stopStatusUpdates() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// getElapsedSeconds returns the elapsed time since status updates started
+func (c *StatusComponent) getElapsedSeconds() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	if !c.isRunning {
-		return
+	if !c.loading || c.startTime.IsZero() {
+		return 0
 	}
+	return int(time.Since(c.startTime).Seconds())
+}
 
-	c.isRunning = false
-	if c.ticker != nil {
-		c.ticker.Stop()
-		c.ticker = nil
-	}
-	if c.stopCh != nil {
-		close(c.stopCh)
-		c.stopCh = nil
+// stopTicker stops the spinner ticker if it runs. Callers hold c.mu.
+func (c *StatusComponent) stopTicker() {
+	if c.ticker == nil {
+		return
 	}
+	c.ticker.Stop()
+	c.ticker = nil
+	close(c.stopCh)
+	c.stopCh = nil
 }
 
-// getElapsedSeconds returns the elapsed time since status updates started
-func (c *StatusComponent) getElapsedSeconds() int {
+// availableUpdate returns the newer release found by the update check, if any
+func (c *StatusComponent) availableUpdate() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.updateVersion
+}
+
+// SetWorkDir sets the working directory shown at the end of the status bar
+func (c *StatusComponent) SetWorkDir(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workDir = dir
+}
+
+// pendingConfirmations returns how many confirmations wait for the user
+func (c *StatusComponent) pendingConfirmations() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return c.confirmations
+}
 
-	if !c.isRunning || c.startTime.IsZero() {
-		return 0
-	}
-	return int(time.Since(c.startTime).Seconds())
+// isLoading reports whether a request is in flight
+func (c *StatusComponent) isLoading() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loading
 }
 
 // Close stops any running status updates and cleans up resources
 func (c *StatusComponent) Close() {
-	c.stopStatusUpdates()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loading = false
+	c.stopTicker()
 }
 
 // SetLeftText sets the text to display on the left side of the status bar
@@ -330,11 +409,12 @@ func (c *StatusComponent) getReadyIndicator() string {
 }
 
 func (c *StatusComponent) getSpinnerFrame() string {
+	// Advance one frame per redraw, however often that is
+	config := c.GetConfig()
 	frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-	frame := frames[time.Now().UnixNano()/100000000%int64(len(frames))]
+	frame := frames[time.Now().UnixNano()/int64(config.StatusRefresh())%int64(len(frames))]
 
 	// Color the spinner with error color
-	config := c.GetConfig()
 	theme := presentation.GetThemeForMode(config.Theme, config.OutputMode)
 	errorColor := presentation.ConvertColorToAnsi(theme.Error)
 	resetColor := "\033[0m"
@@ -391,11 +471,25 @@ func (c *StatusComponent) Render() error {
 	}
 
 	// Update spinner based on current state - confirmation takes priority
-	if c.stateAccessor.IsWaitingConfirmation() {
+	if c.GetConfig().IsAccessibleEnabled() {
+		if c.stateAccessor.IsWaitingConfirmation() {
+			text := "Waiting for your confirmation"
+			if pending := c.pendingConfirmations(); pending > 1 {
+				text = fmt.Sprintf("Waiting for your confirmation, %d more queued", pending-1)
+			}
+			c.SetLeftText(text)
+		} else if c.isLoading() {
+			c.SetLeftText("Genie is thinking, press ESC to cancel")
+		}
+	} else if c.stateAccessor.IsWaitingConfirmation() {
 		spinner := c.getConfirmationSpinnerFrame()
-		c.SetLeftText("Your call " + spinner)
-	} else if c.isRunning {
-		// Show loading status with spinner when status updates are running
+		text := "Your call " + spinner
+		if pending := c.pendingConfirmations(); pending > 1 {
+			text += fmt.Sprintf(" [%d queued]", pending-1)
+		}
+		c.SetLeftText(text)
+	} else if c.isLoading() {
+		// Show loading status with spinner while a request is in flight
 		spinner := c.getSpinnerFrame()
 		seconds := c.getElapsedSeconds()
 		thinkingText := c.getThinkingText(&seconds) // Use our own elapsed time
@@ -425,6 +519,16 @@ func (c *StatusComponent) Render() error {
 			centerText = secondaryColor + centerText + resetColor
 		}
 		c.centerComponent.SetText(centerText)
+	} else if latest := c.availableUpdate(); latest != "" {
+		theme := c.GetTheme()
+		secondaryColor := presentation.ConvertColorToAnsi(theme.Secondary)
+		resetColor := "\033[0m"
+
+		centerText := fmt.Sprintf("Genie %s is available: genie update", latest)
+		if secondaryColor != "" {
+			centerText = secondaryColor + centerText + resetColor
+		}
+		c.centerComponent.SetText(centerText)
 	} else if c.centerComponent.text == "" || strings.Contains(c.centerComponent.text, "Debug is ON") {
 		// Only clear if it's empty or was showing debug status
 		c.centerComponent.SetText("")
@@ -440,7 +544,18 @@ func (c *StatusComponent) Render() error {
 	tertiaryColor := presentation.ConvertColorToAnsi(theme.TextTertiary)
 	resetColor := "\033[0m"
 
+	c.mu.RLock()
+	sessionCost := c.sessionCost
+	workDir := c.workDir
+	c.mu.RUnlock()
+
 	rightText := fmt.Sprintf("Tokens: %s | Msgs: %d | Mem: %dMB", formatTokenCount(c.tokenCount), msgCount, memMB)
+	if sessionCost > 0 {
+		rightText = fmt.Sprintf("Tokens: %s | Cost: %s | Msgs: %d | Mem: %dMB", formatTokenCount(c.tokenCount), pricing.FormatCost(sessionCost), msgCount, memMB)
+	}
+	if workDir != "" {
+		rightText += " | Dir: " + filepath.Base(workDir)
+	}
 	if tertiaryColor != "" {
 		rightText = tertiaryColor + rightText + resetColor
 	}
@@ -176,6 +176,15 @@ func TestStatusComponent(t *testing.T) {
 		assert.Contains(t, status.GetRightComponent().(*StatusSectionComponent).GetText(), "MB")
 	})
 
+	t.Run("working directory", func(t *testing.T) {
+		configManager, _ := helpers.NewConfigManager()
+		status := NewStatusComponent(gui, createTestStateAccessor(), configManager, eventBus)
+		status.SetWorkDir("/home/dev/src/backend-api")
+
+		assert.NoError(t, status.Render())
+		assert.Contains(t, status.GetRightComponent().(*StatusSectionComponent).GetText(), "| Dir: backend-api")
+	})
+
 	t.Run("dynamic right content with state changes", func(t *testing.T) {
 		stateAccessor := createTestStateAccessor()
 		// Add test message
@@ -228,6 +237,68 @@ func TestStatusComponent(t *testing.T) {
 	})
 }
 
+func TestStatusComponentAccessible(t *testing.T) {
+	t.Setenv(types.AccessibleEnv, "1")
+	gui := &mockGuiCommon{}
+	eventBus := events.NewCommandEventBus()
+	stateAccessor := createTestStateAccessor()
+	configManager, _ := helpers.NewConfigManager()
+	status := NewStatusComponent(gui, stateAccessor, configManager, eventBus)
+	defer status.Close()
+
+	status.setLoading(true)
+	assert.Nil(t, status.ticker, "no spinner ticks in accessible mode")
+	assert.NoError(t, status.Render())
+	assert.Equal(t, "Genie is thinking, press ESC to cancel", status.GetLeftComponent().(*StatusSectionComponent).GetText())
+
+	stateAccessor.SetWaitingConfirmation(true)
+	assert.NoError(t, status.Render())
+	assert.Equal(t, "Waiting for your confirmation", status.GetLeftComponent().(*StatusSectionComponent).GetText())
+}
+
+func TestStatusComponentTickerRunsOnlyWhileActive(t *testing.T) {
+	gui := &mockGuiCommon{}
+	eventBus := events.NewCommandEventBus()
+	stateAccessor := createTestStateAccessor()
+	status := NewStatusComponent(gui, stateAccessor, createTestConfigManager(), eventBus)
+	defer status.Close()
+
+	assert.Nil(t, status.ticker, "idle status does not tick")
+
+	status.setLoading(true)
+	assert.NotNil(t, status.ticker)
+	status.setLoading(false)
+	assert.Nil(t, status.ticker)
+
+	// A confirmation keeps the spinner going after the request is done
+	stateAccessor.SetWaitingConfirmation(true)
+	status.setLoading(true)
+	status.setLoading(false)
+	assert.NotNil(t, status.ticker)
+
+	stateAccessor.SetWaitingConfirmation(false)
+	status.mu.Lock()
+	status.syncTicker()
+	status.mu.Unlock()
+	assert.Nil(t, status.ticker)
+}
+
+func TestStatusComponentUpdateNotice(t *testing.T) {
+	t.Setenv("GENIE_DEBUG_LEVEL", "")
+	gui := &mockGuiCommon{}
+	eventBus := events.NewCommandEventBus()
+	status := NewStatusComponent(gui, createTestStateAccessor(), createTestConfigManager(), eventBus)
+	defer status.Close()
+
+	assert.NoError(t, status.Render())
+	assert.Empty(t, status.GetCenterComponent().(*StatusSectionComponent).GetText())
+
+	eventBus.Emit("update.available", "1.4.0")
+	assert.Eventually(t, func() bool { return status.availableUpdate() == "1.4.0" }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, status.Render())
+	assert.Contains(t, status.GetCenterComponent().(*StatusSectionComponent).GetText(), "Genie 1.4.0 is available: genie update")
+}
+
 // TestStatusComponentIntegration tests integration with real state
 func TestStatusComponentIntegration(t *testing.T) {
 	eventBus := events.NewCommandEventBus()
@@ -342,3 +413,22 @@ func TestStatusComponentIntegration(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestStatusComponentConfirmationQueueBadge(t *testing.T) {
+	gui := &mockGuiCommon{}
+	eventBus := events.NewCommandEventBus()
+	stateAccessor := createTestStateAccessor()
+	status := NewStatusComponent(gui, stateAccessor, createTestConfigManager(), eventBus)
+	defer status.Close()
+
+	stateAccessor.SetWaitingConfirmation(true)
+	eventBus.Emit("confirmation.changed", 1)
+	eventBus.WaitForPendingEvents()
+	assert.NoError(t, status.Render())
+	assert.NotContains(t, status.GetLeftComponent().(*StatusSectionComponent).GetText(), "queued")
+
+	eventBus.Emit("confirmation.changed", 3)
+	eventBus.WaitForPendingEvents()
+	assert.NoError(t, status.Render())
+	assert.Contains(t, status.GetLeftComponent().(*StatusSectionComponent).GetText(), "[2 queued]")
+}
@@ -0,0 +1,212 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/awesome-gocui/gocui"
+	"github.com/kcaldas/genie/cmd/events"
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// lightnessStep is how much + and - lighten or darken a color
+const lightnessStep = 8
+
+// ThemeEditorComponent lists a theme's color roles beside the conversation
+// so they can be changed while the messages show the result. It edits a
+// draft; the ThemeEditorController previews, saves or discards it.
+type ThemeEditorComponent struct {
+	*BaseComponent
+	eventBus *events.CommandEventBus
+
+	mu        sync.RWMutex
+	name      string
+	draft     *presentation.Theme
+	selected  int
+	isVisible bool
+}
+
+func NewThemeEditorComponent(gui types.Gui, configManager *helpers.ConfigManager, eventBus *events.CommandEventBus) *ThemeEditorComponent {
+	ctx := &ThemeEditorComponent{
+		BaseComponent: NewBaseComponent("theme-editor", "theme-editor", gui, configManager),
+		eventBus:      eventBus,
+		isVisible:     false,
+	}
+
+	ctx.SetTitle(" Theme Editor ")
+	ctx.SetWindowProperties(types.WindowProperties{
+		Focusable:   true,
+		Editable:    false,
+		Wrap:        false,
+		Autoscroll:  false,
+		Highlight:   false,
+		Frame:       true,
+		BorderStyle: types.BorderStyleSingle,
+		FocusStyle:  types.FocusStyleBorder,
+	})
+
+	eventBus.Subscribe("theme.changed", func(e interface{}) {
+		ctx.gui.PostUIUpdate(func() {
+			ctx.Render()
+		})
+	})
+
+	return ctx
+}
+
+func (c *ThemeEditorComponent) GetKeybindings() []*types.KeyBinding {
+	return []*types.KeyBinding{
+		{View: c.viewName, Key: gocui.KeyArrowUp, Handler: c.selectPrevious},
+		{View: c.viewName, Key: 'k', Handler: c.selectPrevious},
+		{View: c.viewName, Key: gocui.KeyArrowDown, Handler: c.selectNext},
+		{View: c.viewName, Key: 'j', Handler: c.selectNext},
+		{View: c.viewName, Key: '+', Handler: c.lighten},
+		{View: c.viewName, Key: '=', Handler: c.lighten},
+		{View: c.viewName, Key: '-', Handler: c.darken},
+		{View: c.viewName, Key: gocui.KeyEnter, Handler: c.emit("theme.editor.edit")},
+		{View: c.viewName, Key: 's', Handler: c.emit("theme.editor.save")},
+		{View: c.viewName, Key: gocui.KeyEsc, Handler: c.emit("theme.editor.cancel")},
+		{View: c.viewName, Key: 'q', Handler: c.emit("theme.editor.cancel")},
+	}
+}
+
+// Edit starts editing a draft saved under name
+func (c *ThemeEditorComponent) Edit(name string, draft *presentation.Theme) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.name = name
+	c.draft = draft
+	c.selected = 0
+}
+
+// Draft returns the name and theme being edited
+func (c *ThemeEditorComponent) Draft() (string, *presentation.Theme) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.name, c.draft
+}
+
+// SelectedRole returns the theme role under the cursor
+func (c *ThemeEditorComponent) SelectedRole() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return presentation.ThemeRoles[c.selected]
+}
+
+// SetSelectedColor changes the color of the role under the cursor
+func (c *ThemeEditorComponent) SetSelectedColor(hexColor string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.draft == nil {
+		return fmt.Errorf("no theme is being edited")
+	}
+	return c.draft.SetColor(presentation.ThemeRoles[c.selected], hexColor)
+}
+
+func (c *ThemeEditorComponent) Render() error {
+	if !c.isVisible {
+		return nil
+	}
+
+	v := c.GetView()
+	if v == nil {
+		return nil
+	}
+
+	if err := c.BaseComponent.Render(); err != nil {
+		return err
+	}
+
+	name, draft := c.Draft()
+	if draft == nil {
+		return nil
+	}
+	c.mu.RLock()
+	selected := c.selected
+	c.mu.RUnlock()
+
+	v.Title = fmt.Sprintf(" Theme Editor · %s ", name)
+	v.Clear()
+	muted := presentation.ConvertColorToAnsi(c.GetTheme().Muted)
+	for i, role := range presentation.ThemeRoles {
+		marker := "  "
+		if i == selected {
+			marker = "▶ "
+		}
+		color := draft.Color(role)
+		fmt.Fprintf(v, "%s%s██\033[0m %-14s %s\n", marker, presentation.ConvertColorToAnsi(color), role, color)
+	}
+	fmt.Fprintln(v)
+	help := []string{
+		"↑/↓ select  Enter set color",
+		"+/- lighter/darker",
+		"s save  Esc discard",
+	}
+	fmt.Fprint(v, muted+strings.Join(help, "\n")+"\033[0m")
+
+	// Keep the selected role in view on short terminals
+	_, height := v.Size()
+	_, oy := v.Origin()
+	if selected < oy {
+		_ = v.SetOrigin(0, selected)
+	} else if height > 0 && selected >= oy+height {
+		_ = v.SetOrigin(0, selected-height+1)
+	}
+	return nil
+}
+
+func (c *ThemeEditorComponent) IsVisible() bool {
+	return c.isVisible
+}
+
+func (c *ThemeEditorComponent) SetVisible(visible bool) {
+	c.isVisible = visible
+}
+
+func (c *ThemeEditorComponent) selectPrevious(g *gocui.Gui, v *gocui.View) error {
+	c.moveSelection(-1)
+	return c.Render()
+}
+
+func (c *ThemeEditorComponent) selectNext(g *gocui.Gui, v *gocui.View) error {
+	c.moveSelection(1)
+	return c.Render()
+}
+
+func (c *ThemeEditorComponent) moveSelection(delta int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.selected = min(max(c.selected+delta, 0), len(presentation.ThemeRoles)-1)
+}
+
+func (c *ThemeEditorComponent) lighten(g *gocui.Gui, v *gocui.View) error {
+	return c.adjustLightness(lightnessStep)
+}
+
+func (c *ThemeEditorComponent) darken(g *gocui.Gui, v *gocui.View) error {
+	return c.adjustLightness(-lightnessStep)
+}
+
+func (c *ThemeEditorComponent) adjustLightness(delta int) error {
+	_, draft := c.Draft()
+	if draft == nil {
+		return nil
+	}
+	color := presentation.AdjustLightness(draft.Color(c.SelectedRole()), delta)
+	if err := c.SetSelectedColor(color); err != nil {
+		return nil
+	}
+	c.eventBus.Emit("theme.editor.changed", "")
+	return nil
+}
+
+// emit returns a handler that hands the key over to the controller
+func (c *ThemeEditorComponent) emit(topic string) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		c.eventBus.Emit(topic, "")
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package component
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/awesome-gocui/gocui"
+	"github.com/kcaldas/genie/cmd/events"
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// TodoPanelComponent shows the model's todo list beside the conversation
+type TodoPanelComponent struct {
+	*BaseComponent
+	*ScrollableBase
+	mu        sync.RWMutex
+	todos     []presentation.TodoItem
+	isVisible bool
+}
+
+func NewTodoPanelComponent(gui types.Gui, configManager *helpers.ConfigManager, eventBus *events.CommandEventBus) *TodoPanelComponent {
+	ctx := &TodoPanelComponent{
+		BaseComponent: NewBaseComponent("todos", "todos", gui, configManager),
+		isVisible:     false,
+	}
+
+	ctx.ScrollableBase = NewScrollableBase(ctx.GetView)
+
+	ctx.SetTitle(" Todos ")
+	ctx.SetWindowProperties(types.WindowProperties{
+		Focusable:   true,
+		Editable:    false,
+		Wrap:        true,
+		Autoscroll:  false,
+		Highlight:   false,
+		Frame:       true,
+		BorderStyle: types.BorderStyleSingle,
+		FocusStyle:  types.FocusStyleBorder,
+	})
+
+	eventBus.Subscribe("theme.changed", func(e interface{}) {
+		ctx.gui.PostUIUpdate(func() {
+			ctx.Render()
+		})
+	})
+
+	return ctx
+}
+
+func (c *TodoPanelComponent) GetKeybindings() []*types.KeyBinding {
+	return []*types.KeyBinding{
+		{
+			View:    c.viewName,
+			Key:     gocui.KeyArrowUp,
+			Handler: c.scrollUp,
+		},
+		{
+			View:    c.viewName,
+			Key:     gocui.KeyArrowDown,
+			Handler: c.scrollDown,
+		},
+	}
+}
+
+// SetTodos replaces the displayed list
+func (c *TodoPanelComponent) SetTodos(todos []presentation.TodoItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.todos = append([]presentation.TodoItem(nil), todos...)
+}
+
+// GetTodos returns the displayed list
+func (c *TodoPanelComponent) GetTodos() []presentation.TodoItem {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]presentation.TodoItem(nil), c.todos...)
+}
+
+func (c *TodoPanelComponent) Render() error {
+	if !c.isVisible {
+		return nil
+	}
+
+	v := c.GetView()
+	if v == nil {
+		return nil
+	}
+
+	if err := c.BaseComponent.Render(); err != nil {
+		return err
+	}
+
+	todos := c.GetTodos()
+	completed := 0
+	for _, todo := range todos {
+		if todo.Status == "completed" {
+			completed++
+		}
+	}
+	v.Title = fmt.Sprintf(" Todos %d/%d ", completed, len(todos))
+
+	v.Clear()
+	fmt.Fprint(v, presentation.NewTodoFormatter(c.GetTheme()).FormatTodoList(todos))
+	return nil
+}
+
+func (c *TodoPanelComponent) IsVisible() bool {
+	return c.isVisible
+}
+
+func (c *TodoPanelComponent) SetVisible(visible bool) {
+	c.isVisible = visible
+}
+
+func (c *TodoPanelComponent) scrollUp(g *gocui.Gui, v *gocui.View) error {
+	return c.ScrollUp()
+}
+
+func (c *TodoPanelComponent) scrollDown(g *gocui.Gui, v *gocui.View) error {
+	return c.ScrollDown()
+}
@@ -0,0 +1,278 @@
+package component
+
+import (
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/mattn/go-runewidth"
+)
+
+// transcript is the line-indexed buffer behind the messages view. Each
+// message is formatted and wrapped once per width and kept until its
+// content changes, so long sessions only pay for the messages that are
+// on screen. Re-wrapping after a resize is lazy: messages keep their old
+// height until they scroll into view.
+type transcript struct {
+	entries []transcriptEntry
+	width   int
+
+	// The top line is anchored to a line within a message, so it stays
+	// put when messages above are re-wrapped or the last one grows. When
+	// follow is set the newest line is kept at the bottom instead.
+	follow       bool
+	anchorID     int64
+	anchorOffset int
+}
+
+type transcriptEntry struct {
+	msg       types.Message
+	codeIndex int
+	width     int // Width lines were wrapped at; 0 when they need formatting
+	lines     []string
+}
+
+// formatFunc renders a message as the messages view would print it
+type formatFunc func(msg types.Message, width, codeIndex int) string
+
+func newTranscript() *transcript {
+	return &transcript{follow: true}
+}
+
+// sync replaces the messages, keeping the lines of those that did not
+// change. A new user message scrolls back to the bottom.
+func (t *transcript) sync(messages []types.Message) {
+	cached := make(map[int64]transcriptEntry, len(t.entries))
+	for _, entry := range t.entries {
+		cached[entry.msg.ID] = entry
+	}
+
+	entries := make([]transcriptEntry, len(messages))
+	codeIndex := 1
+	for i, msg := range messages {
+		entry, ok := cached[msg.ID]
+		if !ok && msg.Role == "user" {
+			t.follow = true
+		}
+		if !ok || entry.msg != msg || entry.codeIndex != codeIndex {
+			// Old lines, if any, stand in for the height until re-formatted
+			entry.msg = msg
+			entry.codeIndex = codeIndex
+			entry.width = 0
+		}
+		entries[i] = entry
+
+		// Code block labels are numbered across the transcript so :yank
+		// can address any visible block
+		if presentation.HasCodeBlockLabels(msg) {
+			codeIndex += presentation.CountCodeBlocks(msg.Content)
+		}
+	}
+	t.entries = entries
+}
+
+// setWidth sets the width to wrap at; messages wrapped at another width
+// are re-wrapped as they come into view
+func (t *transcript) setWidth(width int) {
+	t.width = max(width, 1)
+}
+
+// invalidate forces every message to be formatted again, e.g. after a
+// theme change
+func (t *transcript) invalidate() {
+	for i := range t.entries {
+		t.entries[i].width = 0
+	}
+}
+
+func (t *transcript) stale(i int) bool {
+	return t.entries[i].width != t.width
+}
+
+// entryHeight is the number of lines a message takes, or its last known
+// height while it waits to be re-wrapped
+func (t *transcript) entryHeight(i int) int {
+	return max(len(t.entries[i].lines), 1)
+}
+
+// index returns the first line of every message and the total line count
+func (t *transcript) index() ([]int, int) {
+	starts := make([]int, len(t.entries))
+	total := 0
+	for i := range t.entries {
+		starts[i] = total
+		total += t.entryHeight(i)
+	}
+	return starts, total
+}
+
+// entryAt returns the message that contains line
+func entryAt(starts []int, line int) int {
+	i := len(starts) - 1
+	for i > 0 && starts[i] > line {
+		i--
+	}
+	return i
+}
+
+// top returns the first line of a window of the given height
+func (t *transcript) top(starts []int, total, height int) int {
+	bottom := max(total-height, 0)
+	if t.follow {
+		return bottom
+	}
+	for i, entry := range t.entries {
+		if entry.msg.ID == t.anchorID {
+			offset := min(t.anchorOffset, t.entryHeight(i)-1)
+			return min(starts[i]+offset, bottom)
+		}
+	}
+	// The anchored message is gone, e.g. after :clear
+	t.follow = true
+	return bottom
+}
+
+// setTop anchors the window at line, following the bottom once it reaches
+// the last line
+func (t *transcript) setTop(line, total, height int) {
+	bottom := max(total-height, 0)
+	if line >= bottom || len(t.entries) == 0 {
+		t.follow = true
+		return
+	}
+	line = max(line, 0)
+	starts, _ := t.index()
+	i := entryAt(starts, line)
+	t.follow = false
+	t.anchorID = t.entries[i].msg.ID
+	t.anchorOffset = line - starts[i]
+}
+
+// scroll moves the window by delta lines
+func (t *transcript) scroll(delta, height int) {
+	starts, total := t.index()
+	t.setTop(t.top(starts, total, height)+delta, total, height)
+}
+
+func (t *transcript) scrollToTop(height int) {
+	_, total := t.index()
+	t.setTop(0, total, height)
+}
+
+func (t *transcript) scrollToBottom() {
+	t.follow = true
+}
+
+// window formats the messages in view and returns the lines to show, and
+// the transcript line the first of them is
+func (t *transcript) window(height int, format formatFunc) ([]string, int) {
+	height = max(height, 1)
+	for {
+		starts, total := t.index()
+		top := t.top(starts, total, height)
+
+		// Formatting changes heights, which moves the window: repeat until
+		// every message in it is up to date
+		formatted := false
+		for i := entryAt(starts, top); i >= 0 && i < len(t.entries) && starts[i] < top+height; i++ {
+			if t.stale(i) {
+				entry := &t.entries[i]
+				entry.lines = wrapANSI(format(entry.msg, t.width, entry.codeIndex), t.width)
+				entry.width = t.width
+				formatted = true
+			}
+		}
+		if formatted {
+			continue
+		}
+
+		lines := make([]string, 0, height)
+		for i := entryAt(starts, top); i >= 0 && i < len(t.entries) && len(lines) < height; i++ {
+			from := max(top-starts[i], 0)
+			for _, line := range t.entries[i].lines[from:] {
+				if len(lines) == height {
+					break
+				}
+				lines = append(lines, line)
+			}
+		}
+		return lines, top
+	}
+}
+
+// messageAt returns the message shown on a transcript line
+func (t *transcript) messageAt(line int) (types.Message, bool) {
+	if len(t.entries) == 0 || line < 0 {
+		return types.Message{}, false
+	}
+	starts, total := t.index()
+	if line >= total {
+		return types.Message{}, false
+	}
+	return t.entries[entryAt(starts, line)].msg, true
+}
+
+// wrapANSI splits formatted text into lines no wider than width. Escape
+// sequences take no space, and the colors active at the end of a line are
+// repeated at the start of the next so any line can be drawn on its own.
+func wrapANSI(text string, width int) []string {
+	text = strings.TrimSuffix(text, "\n")
+	var lines []string
+	var line strings.Builder
+	active := ""
+	col := 0
+
+	breakLine := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		line.WriteString(active)
+		col = 0
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			breakLine()
+		case r == '\x1b':
+			end := escapeEnd(runes, i)
+			seq := string(runes[i:end])
+			line.WriteString(seq)
+			if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+				if params := seq[2 : len(seq)-1]; params == "" || params == "0" {
+					active = ""
+				} else {
+					active += seq
+				}
+			}
+			i = end - 1
+		default:
+			cell, w := string(r), runewidth.RuneWidth(r)
+			if r == '\t' {
+				// The view expands tabs to four cells
+				cell, w = "    ", 4
+			}
+			if col+w > width && col > 0 {
+				breakLine()
+			}
+			line.WriteString(cell)
+			col += w
+		}
+	}
+	return append(lines, line.String())
+}
+
+// escapeEnd returns the index just past the escape sequence at start
+func escapeEnd(runes []rune, start int) int {
+	i := start + 1
+	if i >= len(runes) || runes[i] != '[' {
+		return min(i+1, len(runes))
+	}
+	for i++; i < len(runes); i++ {
+		if runes[i] >= 0x40 && runes[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(runes)
+}
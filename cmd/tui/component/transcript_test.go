@@ -0,0 +1,123 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFormat prints each message as "<id>:<n>" lines, one per line of
+// content, and counts how often each message was formatted
+func countingFormat(calls map[int64]int) formatFunc {
+	return func(msg types.Message, width, codeIndex int) string {
+		calls[msg.ID]++
+		var out strings.Builder
+		for n := range strings.Split(msg.Content, "\n") {
+			fmt.Fprintf(&out, "%d:%d\n", msg.ID, n)
+		}
+		return out.String()
+	}
+}
+
+// lines builds content with n lines
+func lines(n int) string {
+	return strings.TrimSuffix(strings.Repeat("x\n", n), "\n")
+}
+
+func messages(heights ...int) []types.Message {
+	msgs := make([]types.Message, len(heights))
+	for i, h := range heights {
+		msgs[i] = types.Message{ID: int64(i + 1), Role: "assistant", Content: lines(h)}
+	}
+	return msgs
+}
+
+func TestTranscriptFormatsOnlyVisibleMessages(t *testing.T) {
+	calls := map[int64]int{}
+	tr := newTranscript()
+	tr.setWidth(40)
+	tr.sync(messages(5, 5, 5, 5, 5, 5, 5, 5, 5, 5))
+
+	window, top := tr.window(4, countingFormat(calls))
+	assert.Equal(t, []string{"10:1", "10:2", "10:3", "10:4"}, window)
+	assert.Equal(t, 22, top, "messages never formatted count as one line")
+	assert.NotContains(t, calls, int64(1), "messages far above the window are not formatted")
+
+	// Unchanged messages are not formatted again
+	tr.sync(messages(5, 5, 5, 5, 5, 5, 5, 5, 5, 5))
+	tr.window(4, countingFormat(calls))
+	assert.Equal(t, 1, calls[10])
+}
+
+func TestTranscriptKeepsScrollPositionWhileTheLastMessageGrows(t *testing.T) {
+	calls := map[int64]int{}
+	tr := newTranscript()
+	tr.setWidth(40)
+	msgs := messages(3, 3, 3)
+	tr.sync(msgs)
+	tr.window(3, countingFormat(calls))
+
+	tr.scroll(-3, 3)
+	window, _ := tr.window(3, countingFormat(calls))
+	require.Equal(t, []string{"2:0", "2:1", "2:2"}, window)
+
+	msgs[2].Content = lines(10)
+	tr.sync(msgs)
+	window, _ = tr.window(3, countingFormat(calls))
+	assert.Equal(t, []string{"2:0", "2:1", "2:2"}, window)
+
+	// A new user message returns to the bottom
+	tr.sync(append(msgs, types.Message{ID: 9, Role: "user", Content: "hi"}))
+	window, _ = tr.window(3, countingFormat(calls))
+	assert.Equal(t, []string{"3:8", "3:9", "9:0"}, window)
+}
+
+func TestTranscriptScrollFollowsBottom(t *testing.T) {
+	calls := map[int64]int{}
+	tr := newTranscript()
+	tr.setWidth(40)
+	tr.sync(messages(4, 4))
+	tr.window(2, countingFormat(calls))
+
+	tr.scrollToTop(2)
+	window, top := tr.window(2, countingFormat(calls))
+	assert.Equal(t, []string{"1:0", "1:1"}, window)
+	assert.Equal(t, 0, top)
+	assert.False(t, tr.follow)
+
+	tr.scroll(100, 2)
+	assert.True(t, tr.follow, "scrolling to the last line follows new output")
+}
+
+func TestTranscriptRewrapsOnResize(t *testing.T) {
+	calls := map[int64]int{}
+	tr := newTranscript()
+	tr.setWidth(40)
+	tr.sync(messages(2, 2))
+	tr.window(10, countingFormat(calls))
+	require.Equal(t, map[int64]int{1: 1, 2: 1}, calls)
+
+	tr.setWidth(20)
+	tr.window(10, countingFormat(calls))
+	assert.Equal(t, map[int64]int{1: 2, 2: 2}, calls)
+}
+
+func TestWrapANSI(t *testing.T) {
+	assert.Equal(t, []string{"abc", "de"}, wrapANSI("abcde\n", 3))
+	assert.Equal(t, []string{"a", "", "b"}, wrapANSI("a\n\nb", 3))
+
+	// Colors carry over to continuation lines and escapes take no space
+	red := "\x1b[31m"
+	reset := "\x1b[0m"
+	assert.Equal(t,
+		[]string{red + "abc", red + "d" + reset + "e", "f"},
+		wrapANSI(red+"abcd"+reset+"e\nf", 3))
+
+	// Wide runes and tabs
+	assert.Equal(t, []string{"世", "界"}, wrapANSI("世界", 3))
+	assert.Equal(t, []string{"a    ", "b"}, wrapANSI("a\tb", 5))
+}
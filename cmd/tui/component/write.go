@@ -16,6 +16,7 @@ type WriteComponent struct {
 	commandEventBus *events.CommandEventBus
 	history         history.ChatHistory
 	onClose         func() error
+	onSubmit        func(text string) error // replaces sending the text as a message
 }
 
 func NewWriteComponent(
@@ -85,12 +86,25 @@ func (c *WriteComponent) GetKeybindings() []*types.KeyBinding {
 	return keybindings
 }
 
+// SetOnSubmit makes Ctrl+S hand the text to fn instead of sending it as a
+// chat message, for editing text on behalf of another component.
+func (c *WriteComponent) SetOnSubmit(fn func(text string) error) {
+	c.onSubmit = fn
+}
+
 func (c *WriteComponent) handleSubmit(g *gocui.Gui, v *gocui.View) error {
 	input := strings.TrimSpace(v.Buffer())
 	if input == "" {
 		return c.handleCancel(g, v)
 	}
 
+	if c.onSubmit != nil {
+		if err := c.onSubmit(v.Buffer()); err != nil {
+			return err
+		}
+		return c.handleCancel(g, v)
+	}
+
 	// Add to history
 	c.history.AddCommand(input)
 
@@ -1,19 +1,28 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/presentation"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
 	core_events "github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
 	"github.com/kcaldas/genie/pkg/logging"
+	"github.com/kcaldas/genie/pkg/tools"
 )
 
 // finishedRequestMemory bounds how many completed request IDs are
@@ -21,6 +30,10 @@ import (
 // bus topic than the response) cannot resurrect a finished message.
 const finishedRequestMemory = 16
 
+// maxAttachmentBytes caps a file attached to a message, as the CLI caps
+// piped input
+const maxAttachmentBytes = 256 * 1024
+
 type ChatController struct {
 	*BaseController
 	genie           genie.Genie
@@ -31,12 +44,92 @@ type ChatController struct {
 	requestManager *helpers.RequestContextManager
 	todoFormatter  *presentation.TodoFormatter
 
+	// Cost accounting per turn and per session
+	usage *pricing.Tracker
+
+	// Completion notifications for long turns, and the duration and tool
+	// calls shown under the answer that ends one
+	notifier      *helpers.Notifier
+	turnMu        sync.Mutex
+	turnStartedAt time.Time
+	turnToolCalls int
+
 	// Streaming state. chat.chunk and chat.response are distinct bus
 	// topics and therefore delivered on distinct goroutines, so all
 	// access must hold streamingMu.
 	streamingMu      sync.Mutex
 	streamingMsgs    map[string]*streamingMessage
 	finishedRequests []string
+
+	// Tool calls whose output was truncated, oldest first, so the user can
+	// expand and collapse the full output on demand.
+	outputMu         sync.Mutex
+	outputStore      *tools.OutputStore
+	truncatedOutputs []*truncatedOutput
+
+	// Results of the tool calls shown in the transcript, oldest first, and
+	// the last diff proposed for confirmation, so they can be yanked
+	toolResults []toolResult
+	lastDiff    proposedDiff
+
+	// The model's reasoning, oldest first, shown collapsed until expanded
+	// with :thoughts
+	thoughts []*thoughtBlock
+
+	// Files attached to the next message, sent as context parts
+	attachMu    sync.Mutex
+	attachments []attachment
+
+	// JSON schema the answers must match, set with :schema
+	schemaMu           sync.Mutex
+	responseSchema     *ai.Schema
+	responseSchemaPath string
+
+	// Answers sampled per turn, and the candidates of the last turn
+	// waiting to be picked from
+	candidateMu sync.Mutex
+	sampleCount int
+	candidates  []candidate
+
+	// Notes files waiting for the :summarize answer of a request
+	summaryMu    sync.Mutex
+	summaryNotes map[string]string
+}
+
+type truncatedOutput struct {
+	messageID int64
+	handles   []string
+	preview   string
+	expanded  bool
+}
+
+// thoughtBlock is the reasoning the model published before one answer or
+// tool call. Streamed pieces are appended while it is the last message.
+type thoughtBlock struct {
+	messageID int64
+	text      string
+	expanded  bool
+}
+
+type toolResult struct {
+	toolName string
+	result   map[string]any
+	handles  []string
+}
+
+type proposedDiff struct {
+	filePath string
+	content  string
+}
+
+type candidate struct {
+	messageID int64
+	content   string
+}
+
+type attachment struct {
+	name    string
+	content string
 }
 
 type streamingMessage struct {
@@ -58,20 +151,34 @@ func NewChatController(
 		stateAccessor:   state,
 		commandEventBus: commandEventBus,
 		requestManager:  helpers.NewRequestContextManager(commandEventBus),
+		notifier:        helpers.NewNotifier(),
+		outputStore:     tools.NewOutputStore(tools.DefaultOutputDir(), 0),
 		streamingMsgs:   make(map[string]*streamingMessage),
 	}
 
 	c.todoFormatter = presentation.NewTodoFormatter(c.GetTheme())
 
+	pricingTable, err := pricing.LoadTable(config.NewConfigManager())
+	if err != nil {
+		c.logger().Debug("Using default pricing table", "error", err)
+	}
+	c.usage = pricing.NewTracker(pricingTable)
+
 	eventBus := genieService.GetEventBus()
 	eventBus.Subscribe("chat.response", func(e interface{}) {
 		if event, ok := e.(core_events.ChatResponseEvent); ok {
 			c.logger().Debug("Event consumed", "topic", event.Topic())
 
 			// Finish the request
-			c.requestManager.FinishRequest()
+			lastRequest := c.requestManager.FinishRequest()
 
 			canceled := errors.Is(event.Error, context.Canceled)
+			var elapsed time.Duration
+			var toolCalls int
+			if lastRequest {
+				elapsed, toolCalls = c.finishTurn(event.Error, canceled)
+			}
+			notesPath, saveSummary := c.takeSummaryNotes(event.RequestID)
 
 			if buffer, ok := c.takeStreamingMessage(event.RequestID); ok {
 				if event.Error != nil {
@@ -91,7 +198,12 @@ func NewChatController(
 						msg.Role = "assistant"
 						msg.Content = content
 						msg.ContentType = "markdown"
+						msg.Duration = elapsed
+						msg.ToolCalls = toolCalls
 					})
+					if saveSummary {
+						c.saveSummary(notesPath, content)
+					}
 				}
 
 				if !canceled {
@@ -111,12 +223,19 @@ func NewChatController(
 				} else {
 					c.logger().Debug("Chat canceled by user")
 				}
+			} else if len(event.Candidates) > 1 {
+				c.showCandidates(event.Candidates)
 			} else {
 				state.AddMessage(types.Message{
 					Role:        "assistant",
 					Content:     event.Response,
 					ContentType: "markdown",
+					Duration:    elapsed,
+					ToolCalls:   toolCalls,
 				})
+				if saveSummary {
+					c.saveSummary(notesPath, event.Response)
+				}
 			}
 			c.renderMessages()
 		}
@@ -136,6 +255,11 @@ func NewChatController(
 	eventBus.Subscribe("chat.notification", func(e interface{}) {
 		if event, ok := e.(core_events.NotificationEvent); ok {
 			c.logger().Debug("Event consumed", "topic", event.Topic())
+			if event.ContentType == "thought" {
+				c.addThought(event.Message)
+				c.renderMessages()
+				return
+			}
 			role := "assistant"
 			if event.Role != "" {
 				role = event.Role
@@ -156,35 +280,71 @@ func NewChatController(
 		}
 	})
 
-	eventBus.Subscribe("tool.executed", func(e interface{}) {
-		if event, ok := e.(core_events.ToolExecutedEvent); ok {
-			c.logger().Debug("Event consumed", "topic", event.Topic())
-			// Check if tool execution should be hidden
-			config := c.GetConfig()
-			if toolConfig, exists := config.ToolConfigs[event.ToolName]; exists && toolConfig.Hide {
-				return // Skip showing this tool execution
-			}
+	// Delivered synchronously: an async queue could drop tool lines under
+	// load or render them after the turn's final response.
+	core_events.SubscribeTo(eventBus, func(event core_events.ToolExecutedEvent) {
+		c.logger().Debug("Event consumed", "topic", event.Topic())
+		c.turnMu.Lock()
+		c.turnToolCalls++
+		c.turnMu.Unlock()
+
+		// Check if tool execution should be hidden
+		config := c.GetConfig()
+		if toolConfig, exists := config.ToolConfigs[event.ToolName]; exists && toolConfig.Hide {
+			return // Skip showing this tool execution
+		}
 
-			// Format the function call display for chat
-			formattedCall := presentation.FormatToolCall(event.ToolName, event.Parameters, c.GetConfig())
+		// Format the function call display for chat
+		formattedCall := presentation.FormatToolCall(event.ToolName, event.Parameters, c.GetConfig())
 
-			// Add formatted call to chat messages
-			// Use assistant role for success (green) and error role for failures (red)
-			role := "assistant"
-			if !event.Success {
-				role = "error"
-			}
+		// Add formatted call to chat messages
+		// Use assistant role for success (green) and error role for failures (red)
+		role := "assistant"
+		if !event.Success {
+			role = "error"
+		}
 
-			// Format the result preview
-			resultPreview := presentation.FormatToolResult(event.ToolName, event.Result, c.todoFormatter, c.GetConfig())
+		// Format the result preview
+		resultPreview := presentation.FormatToolResult(event.ToolName, event.Result, c.todoFormatter, c.GetConfig())
+		if event.ToolName == "viewImage" && len(event.OutputHandles) > 0 {
+			resultPreview = presentation.FormatImageResult(event.Result, c.imageData(event.OutputHandles), c.GetConfig())
+		}
 
-			chatMsg := formattedCall + resultPreview
-			state.AddMessage(types.Message{
-				Role:    role,
-				Content: chatMsg,
-			})
+		chatMsg := formattedCall + resultPreview
+		if config.IsAccessibleEnabled() {
+			chatMsg = toolOutcomeLine(event) + "\n" + chatMsg
+		}
+		if event.Cancelled {
+			chatMsg += "\n   (cancelled)"
+		} else if event.TimedOut {
+			chatMsg += "\n   (" + strings.ToLower(event.Message) + ")"
+		}
+		if len(event.OutputHandles) > 0 && event.ToolName != "viewImage" {
+			chatMsg += "\n   (output truncated, :output to expand)"
+		}
+		messageID := state.AddMessage(types.Message{
+			Role:    role,
+			Content: chatMsg,
+		})
+		c.trackToolResult(event)
+		if len(event.OutputHandles) > 0 {
+			c.trackTruncatedOutput(messageID, event.OutputHandles, chatMsg)
+		}
 
-			c.renderMessages()
+		c.renderMessages()
+	})
+
+	// Show sub-agent activity nested under the runAgent call
+	eventBus.Subscribe("subagent.activity", func(e interface{}) {
+		if event, ok := e.(core_events.SubAgentEvent); ok {
+			c.logger().Debug("Event consumed", "topic", event.Topic())
+			if content := formatSubAgentEvent(event); content != "" {
+				state.AddMessage(types.Message{
+					Role:    "system",
+					Content: content,
+				})
+				c.renderMessages()
+			}
 		}
 	})
 
@@ -211,6 +371,12 @@ func NewChatController(
 				}
 			}
 
+			if event.ContentType == "diff" && event.Content != "" {
+				c.outputMu.Lock()
+				c.lastDiff = proposedDiff{filePath: event.FilePath, content: event.Content}
+				c.outputMu.Unlock()
+			}
+
 			// Show confirmation message in chat
 			state.AddMessage(types.Message{
 				Role:    "system",
@@ -234,7 +400,9 @@ func NewChatController(
 	eventBus.Subscribe("token.count", func(e interface{}) {
 		if event, ok := e.(core_events.TokenCountEvent); ok {
 			c.logger().Debug("Event consumed", "topic", event.Topic())
+			c.usage.Record(event)
 			commandEventBus.Emit("token.count", event.TotalTokens)
+			commandEventBus.Emit("usage.cost", c.usage.Session().Cost)
 		}
 	})
 
@@ -247,6 +415,18 @@ func NewChatController(
 		}
 	})
 
+	// Files dropped onto the input, or added by command, go with the next message
+	commandEventBus.Subscribe("file.attach", func(event interface{}) {
+		if path, ok := event.(string); ok {
+			name, err := c.AttachFile(path)
+			if err != nil {
+				c.AddErrorMessage(err.Error())
+				return
+			}
+			c.AddSystemMessage(fmt.Sprintf("Attached %s; it goes with your next message.", name))
+		}
+	})
+
 	// Subscribe to user cancel input
 	commandEventBus.Subscribe("user.input.cancel", func(event interface{}) {
 		c.CancelChat()
@@ -275,24 +455,98 @@ func NewChatController(
 }
 
 // logger returns the current global logger (updated dynamically when debug is toggled)
+// formatSubAgentEvent renders sub-agent activity as an indented line so it
+// reads as part of the enclosing runAgent call.
+func formatSubAgentEvent(event core_events.SubAgentEvent) string {
+	switch event.Phase {
+	case "started":
+		return fmt.Sprintf("  ↳ sub-agent started (%s)", event.Message)
+	case "tool":
+		if event.Message != "" {
+			return fmt.Sprintf("  ↳ sub-agent: %s — %s", event.ToolName, event.Message)
+		}
+		return fmt.Sprintf("  ↳ sub-agent: %s", event.ToolName)
+	case "finished":
+		if event.Error != "" {
+			return fmt.Sprintf("  ↳ sub-agent failed: %s", event.Error)
+		}
+		if event.Message != "" {
+			return fmt.Sprintf("  ↳ sub-agent finished: %s", event.Message)
+		}
+		return "  ↳ sub-agent finished"
+	}
+	return ""
+}
+
 func (c *ChatController) logger() logging.Logger {
 	return logging.GetGlobalLogger()
 }
 
-func (c *ChatController) handleChatMessage(message string) error {
+func (c *ChatController) handleChatMessage(message string, extraOpts ...genie.ChatOption) error {
+	return c.sendChat(message, message, extraOpts...)
+}
+
+// sendChat sends message to the model and shows display as the user's
+// message, which differs for commands that send a prompt of their own.
+func (c *ChatController) sendChat(display, message string, extraOpts ...genie.ChatOption) error {
 	// Add user message to display
 	c.stateAccessor.AddMessage(types.Message{
 		Role:    "user",
-		Content: message,
+		Content: display,
 	})
 
+	c.turnMu.Lock()
+	if c.turnStartedAt.IsZero() {
+		c.turnStartedAt = time.Now()
+		c.turnToolCalls = 0
+		c.usage.StartTurn()
+	}
+	c.turnMu.Unlock()
+
 	// Start a new request and get the shared context
 	ctx := c.requestManager.StartRequest()
 
+	// Screen readers announce new lines, not the status bar spinner
+	if c.GetConfig().IsAccessibleEnabled() {
+		c.stateAccessor.AddMessage(types.Message{
+			Role:    "system",
+			Content: "Genie is thinking",
+		})
+	}
+
+	// Structured answers are not streamed: attempts that fail validation
+	// and get repaired would otherwise flash by in the transcript
+	chatOpts := []genie.ChatOption{genie.WithStreaming(true)}
+	if schema, _ := c.ResponseSchema(); schema != nil {
+		chatOpts = []genie.ChatOption{genie.WithResponseSchema(schema)}
+	}
+	for _, file := range c.takeAttachments() {
+		chatOpts = append(chatOpts, genie.WithContextPart(file.name, file.content))
+	}
+	// Sending on keeps the candidate already in the conversation
+	if n := c.startSampling(); n > 1 {
+		chatOpts = append(chatOpts, genie.WithCandidates(n))
+	}
+	config := c.GetConfig()
+	if config.IsShowThoughtsEnabled() {
+		chatOpts = append(chatOpts, genie.WithShowThoughts(true))
+	}
+	chatOpts = append(chatOpts, genie.WithOutputControls(genie.OutputControls{
+		MaxOutputTokens:  int32(config.MaxOutputTokens),
+		StopSequences:    config.StopSequences,
+		FrequencyPenalty: float32(config.FrequencyPenalty),
+		PresencePenalty:  float32(config.PresencePenalty),
+	}))
+	chatOpts = append(chatOpts, extraOpts...)
+
 	// Use the shared context for this request
-	if err := c.genie.Chat(ctx, message, genie.WithStreaming(true)); err != nil {
+	if err := c.genie.Chat(ctx, message, chatOpts...); err != nil {
 		// Clean up on immediate failure
-		c.requestManager.FinishRequest()
+		if c.requestManager.FinishRequest() {
+			c.turnMu.Lock()
+			c.turnStartedAt = time.Time{}
+			c.turnMu.Unlock()
+		}
 
 		c.stateAccessor.AddMessage(types.Message{
 			Role:    "error",
@@ -304,6 +558,41 @@ func (c *ChatController) handleChatMessage(message string) error {
 	return nil
 }
 
+// finishTurn ends the current turn and, when enabled, notifies the user if
+// the turn ran longer than the configured threshold. It returns how long
+// the turn took and how many tools it called.
+func (c *ChatController) finishTurn(err error, canceled bool) (time.Duration, int) {
+	c.turnMu.Lock()
+	startedAt := c.turnStartedAt
+	toolCalls := c.turnToolCalls
+	c.turnStartedAt = time.Time{}
+	c.turnToolCalls = 0
+	c.turnMu.Unlock()
+
+	if startedAt.IsZero() {
+		return 0, 0
+	}
+	elapsed := time.Since(startedAt)
+	config := c.GetConfig()
+	if canceled || !config.IsNotificationsEnabled() {
+		return elapsed, toolCalls
+	}
+
+	threshold := time.Duration(config.NotificationThresholdSeconds) * time.Second
+	if !c.notifier.ShouldNotify(elapsed, threshold) {
+		return elapsed, toolCalls
+	}
+
+	message := fmt.Sprintf("Response ready after %s", elapsed.Round(time.Second))
+	if err != nil {
+		message = fmt.Sprintf("Request failed after %s", elapsed.Round(time.Second))
+	}
+	if notifyErr := c.notifier.Notify("Genie", message); notifyErr != nil {
+		c.logger().Debug("Notification failed", "error", notifyErr)
+	}
+	return elapsed, toolCalls
+}
+
 func (c *ChatController) handleChatChunk(event core_events.ChatChunkEvent) {
 	if event.Chunk == nil {
 		return
@@ -377,6 +666,380 @@ func (c *ChatController) takeStreamingMessage(requestID string) (*streamingMessa
 	return buffer, ok
 }
 
+// GetUsage returns token and cost totals for the latest turn and the session.
+func (c *ChatController) GetUsage() (turn pricing.Usage, session pricing.Usage) {
+	return c.usage.Turn(), c.usage.Session()
+}
+
+// maxTrackedOutputs bounds how many truncated tool calls can be expanded.
+const maxTrackedOutputs = 50
+
+// thoughtPreviewRunes is how much of a collapsed thought's first line shows
+const thoughtPreviewRunes = 100
+
+// SetResponseSchema loads the JSON schema at path and requires every
+// following answer to match it.
+func (c *ChatController) SetResponseSchema(path string) error {
+	schema, err := ai.LoadResponseSchema(path)
+	if err != nil {
+		return err
+	}
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+	c.responseSchema = schema
+	c.responseSchemaPath = path
+	return nil
+}
+
+// ClearResponseSchema goes back to free-form answers.
+func (c *ChatController) ClearResponseSchema() {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+	c.responseSchema = nil
+	c.responseSchemaPath = ""
+}
+
+// ResponseSchema returns the active response schema and the file it was
+// loaded from, or nil when answers are free-form.
+func (c *ChatController) ResponseSchema() (*ai.Schema, string) {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+	return c.responseSchema, c.responseSchemaPath
+}
+
+// SetSampleCount makes each following turn sample n answers to pick from;
+// 1 goes back to a single answer.
+func (c *ChatController) SetSampleCount(n int) error {
+	if n < 1 || n > genie.MaxCandidates {
+		return fmt.Errorf("sample between 1 and %d answers", genie.MaxCandidates)
+	}
+	c.candidateMu.Lock()
+	defer c.candidateMu.Unlock()
+	c.sampleCount = n
+	return nil
+}
+
+// SampleCount returns how many answers each turn samples.
+func (c *ChatController) SampleCount() int {
+	c.candidateMu.Lock()
+	defer c.candidateMu.Unlock()
+	return max(c.sampleCount, 1)
+}
+
+// startSampling forgets the last turn's candidates and returns how many
+// answers the new turn samples.
+func (c *ChatController) startSampling() int {
+	c.candidateMu.Lock()
+	defer c.candidateMu.Unlock()
+	c.candidates = nil
+	return c.sampleCount
+}
+
+// showCandidates adds each sampled answer under a numbered heading. The
+// first is the one the model remembers until another is picked.
+func (c *ChatController) showCandidates(answers []string) {
+	c.candidateMu.Lock()
+	defer c.candidateMu.Unlock()
+	c.candidates = nil
+	for i, answer := range answers {
+		id := c.stateAccessor.AddMessage(types.Message{
+			Role:        "assistant",
+			Content:     candidateContent(i, len(answers), answer),
+			ContentType: "markdown",
+		})
+		c.candidates = append(c.candidates, candidate{messageID: id, content: answer})
+	}
+	c.stateAccessor.AddMessage(types.Message{
+		Role:    "system",
+		Content: fmt.Sprintf("%d candidates. Keep one with :sample pick <n>; candidate 1 is kept if you carry on.", len(answers)),
+	})
+}
+
+func candidateContent(index, total int, answer string) string {
+	return fmt.Sprintf("**Candidate %d of %d**\n\n%s", index+1, total, answer)
+}
+
+// PickCandidate makes candidate n (1-based) of the last turn its answer:
+// it replaces the recorded answer in what the model remembers, and the
+// other candidates are folded away in the messages view.
+func (c *ChatController) PickCandidate(n int) error {
+	c.candidateMu.Lock()
+	defer c.candidateMu.Unlock()
+	if len(c.candidates) == 0 {
+		return fmt.Errorf("no candidates to pick from: the last turn had a single answer")
+	}
+	if n < 1 || n > len(c.candidates) {
+		return fmt.Errorf("pick a candidate between 1 and %d", len(c.candidates))
+	}
+
+	turns, err := c.genie.GetChatHistory()
+	if err != nil {
+		return err
+	}
+	if len(turns) == 0 || turns[len(turns)-1].Assistant != c.candidates[0].content {
+		return fmt.Errorf("the conversation has moved on since these candidates")
+	}
+	chosen := c.candidates[n-1]
+	turns[len(turns)-1].Assistant = chosen.content
+	if err := c.genie.ReplaceChatHistory(turns); err != nil {
+		return fmt.Errorf("failed to keep candidate %d: %w", n, err)
+	}
+
+	total := len(c.candidates)
+	for i, cand := range c.candidates {
+		if i == n-1 {
+			c.stateAccessor.UpdateMessageByID(cand.messageID, func(msg *types.Message) {
+				msg.Content = cand.content
+			})
+			continue
+		}
+		c.stateAccessor.UpdateMessageByID(cand.messageID, func(msg *types.Message) {
+			msg.Role = "system"
+			msg.Content = fmt.Sprintf("Candidate %d of %d (not kept)", i+1, total)
+			msg.ContentType = "text"
+		})
+	}
+	c.candidates = nil
+	c.renderMessages()
+	return nil
+}
+
+func (c *ChatController) trackTruncatedOutput(messageID int64, handles []string, preview string) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	c.truncatedOutputs = append(c.truncatedOutputs, &truncatedOutput{
+		messageID: messageID,
+		handles:   handles,
+		preview:   preview,
+	})
+	if len(c.truncatedOutputs) > maxTrackedOutputs {
+		c.truncatedOutputs = c.truncatedOutputs[len(c.truncatedOutputs)-maxTrackedOutputs:]
+	}
+}
+
+// imageData loads the image a viewImage result carried in full, when its
+// base64 was too long to keep in the result
+func (c *ChatController) imageData(handles []string) []byte {
+	for _, handle := range handles {
+		text, err := c.outputStore.Load(handle)
+		if err != nil {
+			continue
+		}
+		if data, err := base64.StdEncoding.DecodeString(text); err == nil {
+			return data
+		}
+	}
+	return nil
+}
+
+func (c *ChatController) trackToolResult(event core_events.ToolExecutedEvent) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	c.toolResults = append(c.toolResults, toolResult{
+		toolName: event.ToolName,
+		result:   event.Result,
+		handles:  event.OutputHandles,
+	})
+	if len(c.toolResults) > maxTrackedOutputs {
+		c.toolResults = c.toolResults[len(c.toolResults)-maxTrackedOutputs:]
+	}
+}
+
+// ToolResult returns the full result of a tool call shown in the
+// transcript. index counts back from the most recent call, starting at 1,
+// like ToggleToolOutput.
+func (c *ChatController) ToolResult(index int) (toolName string, text string, err error) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	if len(c.toolResults) == 0 {
+		return "", "", fmt.Errorf("no tool results yet")
+	}
+	if index < 1 || index > len(c.toolResults) {
+		return "", "", fmt.Errorf("tool result %d not found (1-%d available)", index, len(c.toolResults))
+	}
+	result := c.toolResults[len(c.toolResults)-index]
+
+	// Truncated fields were stored in full; copy those rather than what
+	// the model saw
+	if len(result.handles) > 0 {
+		var parts []string
+		for _, handle := range result.handles {
+			text, err := c.outputStore.Load(handle)
+			if err != nil {
+				return "", "", err
+			}
+			parts = append(parts, strings.TrimRight(text, "\n"))
+		}
+		return result.toolName, presentation.StripANSI(strings.Join(parts, "\n\n")), nil
+	}
+	return result.toolName, presentation.StripANSI(presentation.ToolResultText(result.result)), nil
+}
+
+// AttachFile reads a text file to send as context with the next message,
+// under its path relative to the working directory. Attaching a file
+// again replaces the earlier copy.
+func (c *ChatController) AttachFile(path string) (string, error) {
+	name := helpers.DisplayPath(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot attach %s: %w", name, err)
+	}
+	if info.Size() > maxAttachmentBytes {
+		return "", fmt.Errorf("cannot attach %s: %d KB is over the %d KB limit", name, info.Size()/1024, maxAttachmentBytes/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot attach %s: %w", name, err)
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return "", fmt.Errorf("cannot attach %s: it is not a text file", name)
+	}
+
+	c.attachMu.Lock()
+	defer c.attachMu.Unlock()
+	c.attachments = slices.DeleteFunc(c.attachments, func(a attachment) bool { return a.name == name })
+	c.attachments = append(c.attachments, attachment{name: name, content: string(data)})
+	return name, nil
+}
+
+// takeAttachments returns the files attached since the last message and
+// clears them
+func (c *ChatController) takeAttachments() []attachment {
+	c.attachMu.Lock()
+	defer c.attachMu.Unlock()
+	attachments := c.attachments
+	c.attachments = nil
+	return attachments
+}
+
+// LastDiff returns the most recent diff proposed for confirmation and the
+// file it changes
+func (c *ChatController) LastDiff() (filePath string, diff string, ok bool) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+	return c.lastDiff.filePath, c.lastDiff.content, c.lastDiff.content != ""
+}
+
+// ToggleToolOutput expands or collapses the full output of a truncated tool
+// call. index counts back from the most recent truncated call, starting at 1.
+// It returns whether the output is now expanded.
+func (c *ChatController) ToggleToolOutput(index int) (bool, error) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	if len(c.truncatedOutputs) == 0 {
+		return false, fmt.Errorf("no truncated tool output to expand")
+	}
+	if index < 1 || index > len(c.truncatedOutputs) {
+		return false, fmt.Errorf("tool output %d not found (1-%d available)", index, len(c.truncatedOutputs))
+	}
+	output := c.truncatedOutputs[len(c.truncatedOutputs)-index]
+
+	content := output.preview
+	if !output.expanded {
+		config := c.GetConfig()
+		theme := presentation.GetThemeForMode(config.Theme, config.OutputMode)
+		var full strings.Builder
+		full.WriteString(output.preview)
+		for _, handle := range output.handles {
+			text, err := c.outputStore.Load(handle)
+			if err != nil {
+				return false, err
+			}
+			full.WriteString("\n\n")
+			full.WriteString(presentation.SanitizeANSI(strings.TrimRight(text, "\n"), theme, ""))
+		}
+		content = full.String()
+	}
+
+	if !c.stateAccessor.UpdateMessageByID(output.messageID, func(msg *types.Message) {
+		msg.Content = content
+	}) {
+		return false, fmt.Errorf("tool output is no longer in the conversation")
+	}
+	output.expanded = !output.expanded
+	c.renderMessages()
+	return output.expanded, nil
+}
+
+// addThought adds reasoning to the transcript, joining it to the block
+// above when nothing has been shown since.
+func (c *ChatController) addThought(text string) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	if n := len(c.thoughts); n > 0 {
+		last := c.thoughts[n-1]
+		if msg := c.stateAccessor.GetLastMessage(); msg != nil && msg.ID == last.messageID {
+			last.text += text
+			c.stateAccessor.UpdateMessageByID(last.messageID, func(msg *types.Message) {
+				msg.Content = formatThought(last.text, last.expanded)
+			})
+			return
+		}
+	}
+
+	block := &thoughtBlock{text: text}
+	block.messageID = c.stateAccessor.AddMessage(types.Message{
+		Role:        "assistant",
+		Content:     formatThought(text, false),
+		ContentType: "thought",
+	})
+	c.thoughts = append(c.thoughts, block)
+	if len(c.thoughts) > maxTrackedOutputs {
+		c.thoughts = c.thoughts[len(c.thoughts)-maxTrackedOutputs:]
+	}
+}
+
+// ToggleThought expands or collapses a block of the model's reasoning.
+// index counts back from the most recent block, starting at 1. It returns
+// whether the block is now expanded.
+func (c *ChatController) ToggleThought(index int) (bool, error) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	if len(c.thoughts) == 0 {
+		return false, fmt.Errorf("no thoughts yet; turn them on with :config set show_thoughts on")
+	}
+	if index < 1 || index > len(c.thoughts) {
+		return false, fmt.Errorf("thought %d not found (1-%d available)", index, len(c.thoughts))
+	}
+	block := c.thoughts[len(c.thoughts)-index]
+	if !c.stateAccessor.UpdateMessageByID(block.messageID, func(msg *types.Message) {
+		msg.Content = formatThought(block.text, !block.expanded)
+	}) {
+		return false, fmt.Errorf("thought %d is no longer in the conversation", index)
+	}
+	block.expanded = !block.expanded
+	c.renderMessages()
+	return block.expanded, nil
+}
+
+// formatThought shows reasoning in full, or collapsed to a header and its
+// first line
+func formatThought(text string, expanded bool) string {
+	text = strings.TrimSpace(text)
+	size := ""
+	if lines := strings.Count(text, "\n") + 1; lines > 1 {
+		size = fmt.Sprintf("%d lines, ", lines)
+	}
+	if expanded {
+		return fmt.Sprintf("Thought (%s:thoughts to collapse)\n%s", size, text)
+	}
+
+	first, _, more := strings.Cut(text, "\n")
+	if runes := []rune(first); len(runes) > thoughtPreviewRunes {
+		first, more = string(runes[:thoughtPreviewRunes]), true
+	}
+	if !more {
+		return "Thought\n" + first
+	}
+	return fmt.Sprintf("Thought (%s:thoughts to expand)\n%s…", size, first)
+}
+
 func (c *ChatController) ClearConversation() error {
 	c.stateAccessor.ClearMessages()
 	c.renderMessages()
@@ -395,6 +1058,73 @@ func (c *ChatController) GetConversationHistory() []types.Message {
 	return c.stateAccessor.GetMessages()
 }
 
+// RestoreTranscript adds messages saved from an earlier run to the
+// transcript. They are shown only; the model does not remember them.
+func (c *ChatController) RestoreTranscript(messages []types.Message) {
+	for _, msg := range messages {
+		c.stateAccessor.AddMessage(msg)
+	}
+	c.renderMessages()
+}
+
+// toolOutcomeLine says in words how a tool call ended, for accessible
+// mode where success and failure must not be told apart by color alone
+func toolOutcomeLine(event core_events.ToolExecutedEvent) string {
+	switch {
+	case event.Cancelled:
+		return fmt.Sprintf("Tool %s cancelled", event.ToolName)
+	case event.TimedOut:
+		return fmt.Sprintf("Tool %s timed out", event.ToolName)
+	case !event.Success:
+		return fmt.Sprintf("Tool %s failed", event.ToolName)
+	default:
+		return fmt.Sprintf("Tool %s finished", event.ToolName)
+	}
+}
+
+// ReplaceConversation shows messages in place of the current
+// conversation. Each message gets a new ID.
+func (c *ChatController) ReplaceConversation(messages []types.Message) {
+	c.stateAccessor.ClearMessages()
+	for _, msg := range messages {
+		c.stateAccessor.AddMessage(msg)
+	}
+	c.renderMessages()
+}
+
+// Retry sends the last prompt again in place of its answer: the turn is
+// dropped from what the model remembers and the prompt is re-sent with opts
+// added to the usual options. The previous answer stays on screen.
+func (c *ChatController) Retry(opts ...genie.ChatOption) error {
+	if c.IsBusy() {
+		return fmt.Errorf("wait for the current response to finish, or cancel it, before retrying")
+	}
+	turns, err := c.genie.GetChatHistory()
+	if err != nil {
+		return err
+	}
+	if len(turns) == 0 || turns[len(turns)-1].User == "" {
+		return fmt.Errorf("nothing to retry: send a message first")
+	}
+	last := turns[len(turns)-1]
+	if err := c.genie.ReplaceChatHistory(turns[:len(turns)-1]); err != nil {
+		return fmt.Errorf("failed to drop the last answer: %w", err)
+	}
+
+	// handleChatMessage reports a failure to send; nothing was asked, so
+	// the model keeps the answer it had
+	if err := c.handleChatMessage(last.User, opts...); err != nil {
+		_ = c.genie.ReplaceChatHistory(turns)
+	}
+	c.renderMessages()
+	return nil
+}
+
+// IsBusy reports whether a chat request is still running
+func (c *ChatController) IsBusy() bool {
+	return c.requestManager.HasActiveRequests()
+}
+
 func (c *ChatController) CancelChat() {
 	cancelledCount := c.requestManager.CancelAll()
 
@@ -432,8 +1162,26 @@ func (c *ChatController) ShowWelcomeMessage() {
 	welcomeMsg := fmt.Sprintf("Hello! I'm %s! Type :? for help.", personaName)
 	c.AddSystemMessage(welcomeMsg)
 
-	if missing := c.genie.MissingTools(); len(missing) > 0 {
-		c.AddSystemMessage(fmt.Sprintf("⚠ %d tool(s) not available: %v", len(missing), missing))
+	if missing := helpers.FormatMissingTools(c.genie.MissingTools()); missing != "" {
+		c.AddSystemMessage(missing)
+	}
+	var invalid []string
+	for _, issue := range c.genie.ToolIssues() {
+		if issue.Fatal {
+			invalid = append(invalid, "- "+issue.String())
+		}
+	}
+	if len(invalid) > 0 {
+		c.AddSystemMessage(fmt.Sprintf("⚠ %d tool schema problem(s); tools are not sent to a provider that rejects them:\n%s",
+			len(invalid), strings.Join(invalid, "\n")))
+	}
+	if conflicts := c.genie.ToolConflicts(); len(conflicts) > 0 {
+		lines := make([]string, len(conflicts))
+		for i, conflict := range conflicts {
+			lines[i] = "- " + conflict.String()
+		}
+		c.AddSystemMessage(fmt.Sprintf("⚠ %d tool name conflict(s); set namespaceTools or overrideTools on a server in .mcp.json to choose:\n%s",
+			len(conflicts), strings.Join(lines, "\n")))
 	}
 
 	// Emit persona change event to update title
@@ -1,13 +1,18 @@
 package controllers
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/awesome-gocui/gocui"
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/state"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
 	"github.com/kcaldas/genie/pkg/genie/genietest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -147,6 +152,114 @@ func TestChatController_HandleInput(t *testing.T) {
 	}
 }
 
+func TestChatController_Retry(t *testing.T) {
+	stateAccessor := state.NewStateAccessor(state.NewChatState(100), state.NewUIState())
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession(genie.WithChatHistory(genie.ChatHistoryTurn{User: "name a color", Assistant: "blue"}))
+	fixture.ExpectSimpleMessage("name a color", "red")
+
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		stateAccessor,
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+
+	require.NoError(t, controller.Retry(genie.WithTemperature(0.1)))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	turns, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []genie.ChatHistoryTurn{{User: "name a color", Assistant: "red"}}, turns)
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 1)
+	assert.Equal(t, float32(0.1), prompts[0].Temperature)
+}
+
+func TestChatController_TurnDuration(t *testing.T) {
+	stateAccessor := state.NewStateAccessor(state.NewChatState(100), state.NewUIState())
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("name a color", "blue")
+
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		stateAccessor,
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+
+	require.NoError(t, controller.handleChatMessage("name a color"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	var answer *types.Message
+	assert.Eventually(t, func() bool {
+		answer = stateAccessor.GetLastMessage()
+		return answer != nil && answer.Role == "assistant" && answer.Duration > 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, answer.ToolCalls)
+	assert.False(t, answer.Time.IsZero())
+	assert.Zero(t, stateAccessor.GetMessages()[0].Duration, "only the answer ends the turn")
+}
+
+func TestChatController_RetryWithoutHistory(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		state.NewStateAccessor(state.NewChatState(100), state.NewUIState()),
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+
+	assert.ErrorContains(t, controller.Retry(), "nothing to retry")
+}
+
+func TestChatController_PickCandidate(t *testing.T) {
+	stateAccessor := state.NewStateAccessor(state.NewChatState(100), state.NewUIState())
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("name a color", "blue")
+
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		stateAccessor,
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+	assert.Error(t, controller.SetSampleCount(genie.MaxCandidates+1))
+	require.NoError(t, controller.SetSampleCount(2))
+	assert.ErrorContains(t, controller.PickCandidate(1), "no candidates")
+
+	require.NoError(t, controller.handleChatMessage("name a color"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+	assert.Eventually(t, func() bool {
+		return len(stateAccessor.GetMessages()) == 4
+	}, time.Second, 10*time.Millisecond, "the prompt, two candidates and how to pick one")
+
+	// Both candidates are "blue" from the mock; keeping the second one
+	// still rewrites the recorded turn and folds the first away
+	assert.ErrorContains(t, controller.PickCandidate(3), "between 1 and 2")
+	require.NoError(t, controller.PickCandidate(2))
+	messages := stateAccessor.GetMessages()
+	assert.Equal(t, "Candidate 1 of 2 (not kept)", messages[1].Content)
+	assert.Equal(t, "assistant", messages[2].Role)
+	assert.Equal(t, "blue", messages[2].Content)
+
+	turns, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []genie.ChatHistoryTurn{{User: "name a color", Assistant: "blue"}}, turns)
+	assert.ErrorContains(t, controller.PickCandidate(1), "no candidates", "a turn is picked from once")
+}
+
 func TestChatController_ClearConversation(t *testing.T) {
 	// Setup
 	chatState := state.NewChatState(100)
@@ -224,3 +337,134 @@ func TestChatController_GetConversationHistory(t *testing.T) {
 		assert.Equal(t, msg.Content, history[i].Content)
 	}
 }
+
+func TestChatController_AttachFile(t *testing.T) {
+	chatState := state.NewChatState(100)
+	stateAccessor := state.NewStateAccessor(chatState, state.NewUIState())
+	fixture := genietest.NewTestFixture(t)
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		stateAccessor,
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+
+	dir := t.TempDir()
+	notes := filepath.Join(dir, "notes.txt")
+	binary := filepath.Join(dir, "image.png")
+	require.NoError(t, os.WriteFile(notes, []byte("first"), 0644))
+	require.NoError(t, os.WriteFile(binary, []byte{0x89, 'P', 'N', 'G', 0}, 0644))
+
+	_, err := controller.AttachFile(notes)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(notes, []byte("second"), 0644))
+	name, err := controller.AttachFile(notes)
+	require.NoError(t, err)
+
+	_, err = controller.AttachFile(binary)
+	assert.ErrorContains(t, err, "not a text file")
+
+	attachments := controller.takeAttachments()
+	require.Len(t, attachments, 1, "attaching again replaces the earlier copy")
+	assert.Equal(t, attachment{name: name, content: "second"}, attachments[0])
+	assert.Empty(t, controller.takeAttachments())
+}
+
+func TestChatController_Thoughts(t *testing.T) {
+	chatState := state.NewChatState(100)
+	stateAccessor := state.NewStateAccessor(chatState, state.NewUIState())
+	fixture := genietest.NewTestFixture(t)
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		stateAccessor,
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+
+	_, err := controller.ToggleThought(1)
+	assert.ErrorContains(t, err, "show_thoughts")
+
+	// Streamed pieces join into one collapsed block
+	controller.addThought("First I check the tests.")
+	controller.addThought("\nThen I read the handler.")
+	messages := stateAccessor.GetMessages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "thought", messages[0].ContentType)
+	assert.Equal(t, "Thought (2 lines, :thoughts to expand)\nFirst I check the tests.…", messages[0].Content)
+
+	expanded, err := controller.ToggleThought(1)
+	require.NoError(t, err)
+	assert.True(t, expanded)
+	assert.Contains(t, stateAccessor.GetMessages()[0].Content, "Then I read the handler.")
+
+	// Anything shown in between starts a new block
+	stateAccessor.AddMessage(types.Message{Role: "assistant", Content: "Done."})
+	controller.addThought("Short one.")
+	messages = stateAccessor.GetMessages()
+	require.Len(t, messages, 3)
+	assert.Equal(t, "Thought\nShort one.", messages[2].Content)
+
+	_, err = controller.ToggleThought(3)
+	assert.ErrorContains(t, err, "1-2 available")
+}
+
+func TestChatController_Summarize(t *testing.T) {
+	stateAccessor := state.NewStateAccessor(state.NewChatState(100), state.NewUIState())
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession(genie.WithChatHistory(genie.ChatHistoryTurn{User: "rename the config flag", Assistant: "done"}))
+	fixture.ExpectSimpleMessage(summaryPrompt, "## Decisions\nRenamed the flag.")
+
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		stateAccessor,
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+
+	require.NoError(t, controller.Summarize(true))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	notes := summaryNotesPath(session.GetGenieHomeDirectory(), time.Now())
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(notes)
+		return err == nil && strings.Contains(string(data), "## Decisions\nRenamed the flag.")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	turns, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []genie.ChatHistoryTurn{{User: "rename the config flag", Assistant: "done"}}, turns,
+		"the summary is not part of the conversation")
+	assert.Equal(t, ":summarize --save", stateAccessor.GetMessages()[0].Content)
+}
+
+func TestChatController_SummarizeWithoutHistory(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	controller := NewChatController(
+		&mockComponent{key: "test", viewName: "test"},
+		&mockGuiCommon{},
+		fixture.Genie,
+		state.NewStateAccessor(state.NewChatState(100), state.NewUIState()),
+		createTestConfigManager(),
+		events.NewCommandEventBus(),
+	)
+
+	assert.ErrorContains(t, controller.Summarize(false), "nothing to summarize")
+}
+
+func TestAppendSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes", "2026-10-16.md")
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	require.NoError(t, appendSummary(path, "first\n", at))
+	require.NoError(t, appendSummary(path, "second", at.Add(time.Hour)))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Session summary, 2026-10-16 09:30\n\nfirst\n\n---\n\n# Session summary, 2026-10-16 10:30\n\nsecond\n", string(data))
+}
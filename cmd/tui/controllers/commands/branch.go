@@ -0,0 +1,299 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+// branchDiffPreview is how many characters of each side of a turn the
+// diff shows
+const branchDiffPreview = 160
+
+type BranchCommand struct {
+	BaseCommand
+	genieService genie.Genie
+	conversation ConversationController
+	store        *helpers.BranchStore
+	notification types.Notification
+
+	// The branch the conversation is on, and the one it was forked from
+	current   string
+	parent    string
+	createdAt time.Time
+}
+
+func NewBranchCommand(genieService genie.Genie, conversation ConversationController, store *helpers.BranchStore, notification types.Notification) *BranchCommand {
+	return &BranchCommand{
+		BaseCommand: BaseCommand{
+			Name:        "branch",
+			Description: "Fork the conversation into named branches, switch between them and compare them",
+			Usage:       ":branch [list|create <name>|switch <name>|diff <name> [other]|delete <name>]",
+			Examples: []string{
+				":branch",
+				":branch create experiment-1",
+				":branch switch main",
+				":branch diff experiment-1",
+				":branch diff experiment-1 experiment-2",
+				":branch delete experiment-1",
+			},
+			Aliases:  []string{"br"},
+			Category: "Chat",
+		},
+		genieService: genieService,
+		conversation: conversation,
+		store:        store,
+		notification: notification,
+		current:      helpers.MainBranch,
+	}
+}
+
+func (c *BranchCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	var err error
+	switch args[0] {
+	case "list", "ls":
+		err = c.list()
+	case "create", "new", "fork":
+		err = c.requireName(args, c.create)
+	case "switch", "checkout":
+		err = c.requireName(args, c.switchTo)
+	case "delete", "rm":
+		err = c.requireName(args, c.delete)
+	case "diff":
+		if len(args) < 2 {
+			err = fmt.Errorf("usage: :branch diff <name> [other]")
+			break
+		}
+		other := c.current
+		if len(args) > 2 {
+			other = args[2]
+		}
+		err = c.diff(args[1], other)
+	default:
+		err = fmt.Errorf("unknown branch action %q. Usage: %s", args[0], c.Usage)
+	}
+	if err != nil {
+		c.notification.AddErrorMessage(err.Error())
+	}
+	return nil
+}
+
+func (c *BranchCommand) requireName(args []string, action func(string) error) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: :branch %s <name>", args[0])
+	}
+	if err := helpers.ValidateBranchName(args[1]); err != nil {
+		return err
+	}
+	return action(args[1])
+}
+
+// snapshot captures the conversation as the current branch
+func (c *BranchCommand) snapshot() (*helpers.Branch, error) {
+	turns, err := c.genieService.GetChatHistory()
+	if err != nil {
+		return nil, err
+	}
+	return &helpers.Branch{
+		Name:      c.current,
+		Parent:    c.parent,
+		CreatedAt: c.createdAt,
+		Turns:     turns,
+		Messages:  c.conversation.GetConversationHistory(),
+	}, nil
+}
+
+// saveCurrent saves the conversation under the current branch. A
+// conversation with no turns yet is only saved over a branch it was
+// loaded from, so starting Genie and switching away does not wipe a
+// branch saved in an earlier run.
+func (c *BranchCommand) saveCurrent() error {
+	branch, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+	if len(branch.Turns) == 0 && c.createdAt.IsZero() {
+		return nil
+	}
+	if err := c.store.Save(branch); err != nil {
+		return fmt.Errorf("failed to save branch %s: %w", branch.Name, err)
+	}
+	c.createdAt = branch.CreatedAt
+	return nil
+}
+
+func (c *BranchCommand) create(name string) error {
+	if c.store.Exists(name) || name == c.current {
+		return fmt.Errorf("branch %s already exists; use :branch switch %s", name, name)
+	}
+	if err := c.saveCurrent(); err != nil {
+		return err
+	}
+
+	branch, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+	branch.Name = name
+	branch.Parent = c.current
+	branch.CreatedAt = time.Time{}
+	if err := c.store.Save(branch); err != nil {
+		return fmt.Errorf("failed to save branch %s: %w", name, err)
+	}
+
+	from := c.current
+	c.current, c.parent, c.createdAt = name, branch.Parent, branch.CreatedAt
+	c.notification.AddSystemMessage(fmt.Sprintf("Created branch %s from %s (%s). You are now on %s; :branch switch %s goes back.",
+		name, from, pluralTurns(len(branch.Turns)), name, from))
+	return nil
+}
+
+func (c *BranchCommand) switchTo(name string) error {
+	if name == c.current {
+		return fmt.Errorf("already on branch %s", name)
+	}
+	if c.conversation.IsBusy() {
+		return fmt.Errorf("wait for the current response to finish, or cancel it, before switching branches")
+	}
+	branch, err := c.store.Load(name)
+	if errors.Is(err, helpers.ErrBranchNotFound) {
+		return fmt.Errorf("no branch named %s; create it with :branch create %s", name, name)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.saveCurrent(); err != nil {
+		return err
+	}
+	if err := c.genieService.ReplaceChatHistory(branch.Turns); err != nil {
+		return fmt.Errorf("failed to restore branch %s: %w", name, err)
+	}
+
+	from := c.current
+	c.current, c.parent, c.createdAt = branch.Name, branch.Parent, branch.CreatedAt
+	c.conversation.ReplaceConversation(branch.Messages)
+	c.notification.AddSystemMessage(fmt.Sprintf("Switched from %s to branch %s (%s)", from, name, pluralTurns(len(branch.Turns))))
+	return nil
+}
+
+func (c *BranchCommand) delete(name string) error {
+	if name == c.current {
+		return fmt.Errorf("cannot delete branch %s while on it; switch to another branch first", name)
+	}
+	if err := c.store.Delete(name); err != nil {
+		return err
+	}
+	c.notification.AddSystemMessage(fmt.Sprintf("Deleted branch %s", name))
+	return nil
+}
+
+func (c *BranchCommand) list() error {
+	branches, err := c.store.List()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("Branches:\n")
+	listed := false
+	for _, branch := range branches {
+		if branch.Name == c.current {
+			listed = true
+			// The saved copy may be behind the live conversation
+			if turns, err := c.genieService.GetChatHistory(); err == nil {
+				branch.Turns = turns
+			}
+		}
+		b.WriteString(formatBranchLine(branch, branch.Name == c.current))
+	}
+	if !listed {
+		turns, _ := c.genieService.GetChatHistory()
+		b.WriteString(formatBranchLine(&helpers.Branch{Name: c.current, Parent: c.parent, Turns: turns}, true))
+	}
+	b.WriteString("\nCreate one with :branch create <name>, compare with :branch diff <name>.")
+	c.notification.AddSystemMessage(b.String())
+	return nil
+}
+
+func formatBranchLine(branch *helpers.Branch, current bool) string {
+	marker := "  "
+	if current {
+		marker = "* "
+	}
+	line := marker + branch.Name + " — " + pluralTurns(len(branch.Turns))
+	if branch.Parent != "" {
+		line += ", forked from " + branch.Parent
+	}
+	if !branch.UpdatedAt.IsZero() {
+		line += ", saved " + branch.UpdatedAt.Local().Format("2006-01-02 15:04")
+	}
+	return line + "\n"
+}
+
+func (c *BranchCommand) diff(nameA, nameB string) error {
+	if nameA == nameB {
+		return fmt.Errorf("cannot diff branch %s with itself", nameA)
+	}
+	// Diff the live conversation, not a stale save of it
+	if nameA == c.current || nameB == c.current {
+		if err := c.saveCurrent(); err != nil {
+			return err
+		}
+	}
+	a, err := c.store.Load(nameA)
+	if err != nil {
+		return err
+	}
+	b, err := c.store.Load(nameB)
+	if err != nil {
+		return err
+	}
+
+	diff := helpers.DiffBranches(a, b)
+	var out strings.Builder
+	fmt.Fprintf(&out, "## %s vs %s\n\nShared: %s\n", a.Name, b.Name, pluralTurns(diff.Shared))
+	writeBranchTurns(&out, a.Name, diff.Shared, diff.OnlyA)
+	writeBranchTurns(&out, b.Name, diff.Shared, diff.OnlyB)
+	c.notification.AddAssistantMessage(out.String())
+	return nil
+}
+
+func writeBranchTurns(out *strings.Builder, name string, shared int, turns []genie.ChatHistoryTurn) {
+	fmt.Fprintf(out, "\n### Only on %s (%s)\n\n", name, pluralTurns(len(turns)))
+	if len(turns) == 0 {
+		out.WriteString("Nothing after the shared turns.\n")
+		return
+	}
+	for i, turn := range turns {
+		fmt.Fprintf(out, "%d. **User:** %s\n", shared+i+1, previewTurnText(turn.User))
+		fmt.Fprintf(out, "   **Assistant:** %s\n", previewTurnText(turn.Assistant))
+	}
+}
+
+// previewTurnText shortens text to one line for the diff
+func previewTurnText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return "_(empty)_"
+	}
+	if runes := []rune(text); len(runes) > branchDiffPreview {
+		return string(runes[:branchDiffPreview]) + "…"
+	}
+	return text
+}
+
+func pluralTurns(n int) string {
+	if n == 1 {
+		return "1 turn"
+	}
+	return fmt.Sprintf("%d turns", n)
+}
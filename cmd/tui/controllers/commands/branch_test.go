@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConversation struct {
+	messages []types.Message
+	busy     bool
+}
+
+func (f *fakeConversation) GetConversationHistory() []types.Message { return f.messages }
+func (f *fakeConversation) ReplaceConversation(messages []types.Message) {
+	f.messages = append([]types.Message(nil), messages...)
+}
+func (f *fakeConversation) IsBusy() bool { return f.busy }
+
+// chat records a turn the way a real exchange would
+func (f *fakeConversation) chat(service *MockGenieService, user, assistant string) {
+	service.chatHistory = append(service.chatHistory, genie.ChatHistoryTurn{User: user, Assistant: assistant})
+	f.messages = append(f.messages,
+		types.Message{Role: "user", Content: user},
+		types.Message{Role: "assistant", Content: assistant})
+}
+
+func newTestBranchCommand(t *testing.T) (*BranchCommand, *MockGenieService, *fakeConversation, *types.MockNotification) {
+	service := &MockGenieService{}
+	conversation := &fakeConversation{}
+	notification := &types.MockNotification{}
+	store := helpers.NewBranchStore(t.TempDir())
+	return NewBranchCommand(service, conversation, store, notification), service, conversation, notification
+}
+
+func TestBranchCommandForksAndSwitches(t *testing.T) {
+	cmd, service, conversation, notification := newTestBranchCommand(t)
+
+	conversation.chat(service, "plan the migration", "step 1, step 2")
+	require.NoError(t, cmd.Execute([]string{"create", "experiment-1"}))
+	require.Empty(t, notification.ErrorMessages)
+	assert.Equal(t, "experiment-1", cmd.current)
+
+	conversation.chat(service, "try approach A", "A done")
+	require.NoError(t, cmd.Execute([]string{"switch", "main"}))
+	require.Empty(t, notification.ErrorMessages)
+	assert.Equal(t, []genie.ChatHistoryTurn{{User: "plan the migration", Assistant: "step 1, step 2"}}, service.chatHistory)
+	assert.Len(t, conversation.messages, 2)
+
+	conversation.chat(service, "try approach B", "B done")
+	require.NoError(t, cmd.Execute([]string{"diff", "experiment-1"}))
+	require.Len(t, notification.AssistantMessages, 1)
+	diff := notification.AssistantMessages[0]
+	assert.Contains(t, diff, "Shared: 1 turn")
+	assert.Contains(t, diff, "Only on experiment-1 (1 turn)")
+	assert.Contains(t, diff, "2. **User:** try approach A")
+	assert.Contains(t, diff, "2. **User:** try approach B")
+
+	// Switching back restores the fork with its own turns
+	require.NoError(t, cmd.Execute([]string{"switch", "experiment-1"}))
+	assert.Equal(t, "try approach A", service.chatHistory[1].User)
+	assert.Equal(t, "A done", conversation.messages[3].Content)
+}
+
+func TestBranchCommandErrors(t *testing.T) {
+	cmd, service, conversation, notification := newTestBranchCommand(t)
+	conversation.chat(service, "hello", "hi")
+
+	require.NoError(t, cmd.Execute([]string{"create", "../escape"}))
+	require.NoError(t, cmd.Execute([]string{"switch", "missing"}))
+	require.NoError(t, cmd.Execute([]string{"create", "main"}))
+	require.NoError(t, cmd.Execute([]string{"delete", "main"}))
+	assert.Len(t, notification.ErrorMessages, 4)
+
+	require.NoError(t, cmd.Execute([]string{"create", "other"}))
+	conversation.busy = true
+	require.NoError(t, cmd.Execute([]string{"switch", "main"}))
+	assert.Contains(t, notification.ErrorMessages[4], "wait for the current response")
+	assert.Equal(t, "other", cmd.current)
+}
+
+func TestBranchCommandList(t *testing.T) {
+	cmd, service, conversation, notification := newTestBranchCommand(t)
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Contains(t, notification.SystemMessages[0], "* main — 0 turns")
+
+	conversation.chat(service, "hello", "hi")
+	require.NoError(t, cmd.Execute([]string{"create", "idea"}))
+	require.NoError(t, cmd.Execute([]string{"list"}))
+	listing := notification.SystemMessages[len(notification.SystemMessages)-1]
+	assert.Contains(t, listing, "* idea — 1 turn, forked from main")
+	assert.Contains(t, listing, "  main — 1 turn")
+}
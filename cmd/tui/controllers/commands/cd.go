@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/events"
+	"github.com/kcaldas/genie/cmd/history"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/trust"
+)
+
+// historySwitcher is the part of the input history that moves to another
+// project's history file
+type historySwitcher interface {
+	SwitchFile(path string) error
+}
+
+type CdCommand struct {
+	BaseCommand
+	genieService    genie.Genie
+	conversation    ConversationController
+	chatHistory     history.ChatHistory
+	commandEventBus *events.CommandEventBus
+	notification    types.Notification
+	trustStore      *trust.Store // nil when ~/.genie cannot be found
+}
+
+func NewCdCommand(genieService genie.Genie, conversation ConversationController, chatHistory history.ChatHistory, commandEventBus *events.CommandEventBus, notification types.Notification) *CdCommand {
+	store, _ := trust.DefaultStore()
+	return &CdCommand{
+		BaseCommand: BaseCommand{
+			Name:        "cd",
+			Description: "Switch the session to another working directory",
+			Usage:       ":cd [path]\n\nTools work in the new directory from the next message on, and its project context (GENIE.md and the like), personas, skills and memories apply. When Genie works where it was started, the project's .genie moves along: its input history and lifecycle hooks. The conversation is kept and a :scope is lifted. MCP servers stay those of the directory Genie started in. A directory with project configuration you have not trusted loads without it; start Genie there with --trust-workspace to trust it. Without a path, :cd shows the working directory.",
+			Examples: []string{
+				":cd",
+				":cd ../api",
+				":cd ~/src/frontend",
+			},
+			Category: "Chat",
+		},
+		genieService:    genieService,
+		conversation:    conversation,
+		chatHistory:     chatHistory,
+		commandEventBus: commandEventBus,
+		notification:    notification,
+		trustStore:      store,
+	}
+}
+
+func (c *CdCommand) Execute(args []string) error {
+	session, err := c.genieService.GetSession()
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if len(args) == 0 {
+		c.notification.AddSystemMessage("Working directory: " + session.GetWorkingDirectory())
+		return nil
+	}
+	if c.conversation.IsBusy() {
+		return fmt.Errorf("wait for the current response to finish, or cancel it, before changing directory")
+	}
+
+	dir, err := resolveDir(strings.Join(args, " "), session.GetWorkingDirectory())
+	if err != nil {
+		return err
+	}
+	trusted, ignored := c.workspaceTrusted(dir)
+
+	previousHome := session.GetGenieHomeDirectory()
+	session, err = c.genieService.ChangeWorkingDirectory(dir, trusted)
+	if err != nil {
+		return err
+	}
+
+	if home := session.GetGenieHomeDirectory(); home != previousHome {
+		if switcher, ok := c.chatHistory.(historySwitcher); ok {
+			if err := switcher.SwitchFile(history.ProjectHistoryPath(home)); err != nil {
+				c.notification.AddErrorMessage(fmt.Sprintf("Failed to load the input history of %s: %v", home, err))
+			}
+		}
+	}
+	c.commandEventBus.Emit("workdir.changed", session.GetWorkingDirectory())
+
+	message := "Working directory: " + session.GetWorkingDirectory()
+	if len(ignored) > 0 {
+		message += fmt.Sprintf(". This workspace is not trusted, so its %s are ignored; start Genie there with --trust-workspace to load them.", strings.Join(ignored, ", "))
+	}
+	c.notification.AddSystemMessage(message)
+	return nil
+}
+
+// workspaceTrusted decides whether dir's project-local configuration may
+// load, returning what is ignored when it may not. Nobody is asked: only a
+// workspace trusted before, or one without such configuration, is trusted.
+func (c *CdCommand) workspaceTrusted(dir string) (bool, []string) {
+	if trust.IsHomeDir(dir) {
+		return true, nil
+	}
+	found := trust.ProjectConfig(dir)
+	if len(found) == 0 {
+		return true, nil
+	}
+	if c.trustStore != nil {
+		if decision, known, err := c.trustStore.Lookup(dir); err == nil && known && decision.Trusted {
+			return true, nil
+		}
+	}
+	return false, found
+}
+
+// resolveDir expands a leading ~ and makes path absolute against base
+func resolveDir(path, base string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path), nil
+}
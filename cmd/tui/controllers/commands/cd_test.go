@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/events"
+	"github.com/kcaldas/genie/cmd/history"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/trust"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCdCommand(t *testing.T) {
+	root := t.TempDir()
+	start := filepath.Join(root, "api")
+	other := filepath.Join(root, "web")
+	require.NoError(t, os.MkdirAll(start, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(other, ".genie"), 0755))
+	require.NoError(t, os.WriteFile(history.ProjectHistoryPath(other), []byte("npm test\n"), 0644))
+
+	service := &MockGenieService{mockSession: &mockSession{workingDir: start, genieHomeDir: start}}
+	conversation := &fakeConversation{}
+	chatHistory := history.NewChatHistory(history.ProjectHistoryPath(start), true)
+	chatHistory.AddCommand("go test ./...")
+	bus := events.NewCommandEventBus()
+	changed := make(chan string, 4)
+	bus.Subscribe("workdir.changed", func(e interface{}) { changed <- e.(string) })
+	notification := &types.MockNotification{}
+	cmd := NewCdCommand(service, conversation, chatHistory, bus, notification)
+	cmd.trustStore = trust.NewStore(filepath.Join(t.TempDir(), trust.FileName))
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, []string{"Working directory: " + start}, notification.SystemMessages)
+
+	require.NoError(t, cmd.Execute([]string{"../web"}))
+	assert.Equal(t, other, service.mockSession.GetWorkingDirectory())
+	assert.True(t, service.changedTrusted)
+	select {
+	case dir := <-changed:
+		assert.Equal(t, other, dir)
+	case <-time.After(time.Second):
+		t.Fatal("workdir.changed was not emitted")
+	}
+	assert.Equal(t, []string{"npm test"}, chatHistory.GetHistory(), "the input history is the new project's")
+
+	conversation.busy = true
+	assert.ErrorContains(t, cmd.Execute([]string{start}), "wait for the current response")
+	conversation.busy = false
+
+	// Project configuration nobody trusted stays off
+	require.NoError(t, os.WriteFile(filepath.Join(start, ".mcp.json"), []byte("{}"), 0644))
+	require.NoError(t, cmd.Execute([]string{start}))
+	assert.False(t, service.changedTrusted)
+	assert.Contains(t, notification.SystemMessages[len(notification.SystemMessages)-1], "not trusted, so its .mcp.json are ignored")
+
+	require.NoError(t, cmd.trustStore.Set(start, true))
+	require.NoError(t, cmd.Execute([]string{"."}))
+	assert.True(t, service.changedTrusted)
+}
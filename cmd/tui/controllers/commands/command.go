@@ -1,5 +1,7 @@
 package commands
 
+import "github.com/kcaldas/genie/cmd/tui/types"
+
 // Command represents a command that can be executed
 type Command interface {
 	// Metadata
@@ -45,3 +47,11 @@ type DebugControllerInterface interface {
 	IsDebugMode() bool
 	SetDebugMode(enabled bool)
 }
+
+// ConversationController is the part of the chat controller that saves
+// and restores the conversation shown in the messages view
+type ConversationController interface {
+	GetConversationHistory() []types.Message
+	ReplaceConversation(messages []types.Message)
+	IsBusy() bool
+}
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/kcaldas/genie/cmd/events"
@@ -24,7 +26,7 @@ func NewConfigCommand(configManager *helpers.ConfigManager, commandEventBus *eve
 	return &ConfigCommand{
 		BaseCommand: BaseCommand{
 			Name:        "config",
-			Description: "Configure TUI settings (cursor, markdown, theme, diff-theme, wrap, timestamps, output, mouse, vim, tools). Use --global to save to global config (~/.genie), otherwise saves to local config (.genie).",
+			Description: "Configure TUI settings (cursor, markdown, theme, diff-theme, wrap, timestamps, output, mouse, clipboard, images, status-refresh, vim, notifications, accessible, update-check, show_thoughts, max_output_tokens, stop_sequences, frequency_penalty, presence_penalty, tools). Use --global to save to global config (~/.genie), otherwise saves to local config (.genie).",
 			Usage:       ":config [--global] <setting> <value> | :config [--global] tool <name> <property> <value> | :config [--global] reset",
 			Examples: []string{
 				":config",
@@ -38,6 +40,11 @@ func NewConfigCommand(configManager *helpers.ConfigManager, commandEventBus *eve
 				":config markdown false",
 				":config mouse true",
 				":config mouse false",
+				":config mouse auto",
+				":config clipboard osc52",
+				":config images off",
+				":config status-refresh 500",
+				":config output auto",
 				":config output true",
 				":config output 256",
 				":config output normal",
@@ -47,6 +54,16 @@ func NewConfigCommand(configManager *helpers.ConfigManager, commandEventBus *eve
 				":config assistantlabel ★",
 				":config systemlabel ■",
 				":config errorlabel ✗",
+				":config notifications on",
+				":config notification-threshold 60",
+				":config accessible on",
+				":config --global update-check off",
+				":config set show_thoughts on",
+				":config set show_timestamps on",
+				":config max_output_tokens 1024",
+				`:config stop_sequences \n\n,END`,
+				":config frequency_penalty 0.5",
+				":config stop_sequences none",
 				":config tool bash accept true",
 				":config --global tool TodoWrite hide true",
 				":config reset",
@@ -84,6 +101,11 @@ func (c *ConfigCommand) Execute(args []string) error {
 		}
 	}
 
+	// Accept ":config set <setting> <value>" as an alias of ":config <setting> <value>"
+	if len(filteredArgs) > 0 && filteredArgs[0] == "set" {
+		filteredArgs = filteredArgs[1:]
+	}
+
 	// Handle reset command
 	if len(filteredArgs) > 0 && filteredArgs[0] == "reset" {
 		return c.resetConfig(global)
@@ -114,8 +136,8 @@ func (c *ConfigCommand) Execute(args []string) error {
 func (c *ConfigCommand) updateConfig(setting, value string, global bool) error {
 	// Validate output mode before updating config
 	if setting == "output" || setting == "outputmode" {
-		if value != "true" && value != "256" && value != "normal" {
-			c.notification.AddErrorMessage("Invalid output mode. Valid options: true, 256, normal")
+		if !slices.Contains([]string{"auto", "true", "256", "16", "normal"}, value) {
+			c.notification.AddErrorMessage("Invalid output mode. Valid options: auto, true, 256, 16, normal")
 			return nil
 		}
 	}
@@ -198,8 +220,14 @@ func (c *ConfigCommand) updateConfig(setting, value string, global bool) error {
 		} else {
 			config.WrapMessages = "disabled"
 		}
-	case "timestamps":
-		config.ShowTimestamps = value == "true" || value == "on" || value == "yes"
+	case "timestamps", "show_timestamps", "show-timestamps", "showtimestamps":
+		config.ShowTimestamps = value == "true" || value == "on" || value == "yes" || value == "enabled"
+		// Messages already on screen gain or lose their times when redrawn
+		c.commandEventBus.Emit("theme.changed", map[string]interface{}{
+			"oldTheme": config.Theme,
+			"newTheme": config.Theme,
+			"config":   config,
+		})
 	case "output", "outputmode":
 		config.OutputMode = value
 		c.notification.AddSystemMessage("Output mode updated. Restart the application for changes to take effect.")
@@ -224,7 +252,9 @@ func (c *ConfigCommand) updateConfig(setting, value string, global bool) error {
 		// Emit event to refresh keybindings
 		c.commandEventBus.Emit("vim.mode.changed", config.VimMode)
 	case "mouse":
-		if value == "true" || value == "on" || value == "yes" || value == "enabled" {
+		if value == "auto" {
+			config.EnableMouse = "auto"
+		} else if value == "true" || value == "on" || value == "yes" || value == "enabled" {
 			config.EnableMouse = "enabled"
 		} else {
 			config.EnableMouse = "disabled"
@@ -236,6 +266,103 @@ func (c *ConfigCommand) updateConfig(setting, value string, global bool) error {
 		} else {
 			c.notification.AddSystemMessage("Mouse support disabled. Terminal native text selection enabled.")
 		}
+	case "clipboard":
+		if value != "auto" && value != "system" && value != "osc52" {
+			c.notification.AddErrorMessage("Invalid clipboard. Use auto, system or osc52")
+			return nil
+		}
+		config.Clipboard = value
+	case "images", "inline-images":
+		switch value {
+		case "auto":
+			config.InlineImages = "auto"
+		case "true", "on", "yes", "enabled":
+			config.InlineImages = "enabled"
+		case "false", "off", "no", "disabled":
+			config.InlineImages = "disabled"
+		default:
+			c.notification.AddErrorMessage("Invalid images setting. Use on, off or auto")
+			return nil
+		}
+	case "statusrefresh", "status-refresh":
+		ms := 0
+		if value != "auto" {
+			var err error
+			ms, err = strconv.Atoi(strings.TrimSuffix(value, "ms"))
+			if err != nil || ms < 20 {
+				c.notification.AddErrorMessage("Invalid status refresh. Use auto or a number of milliseconds of at least 20, e.g. 500")
+				return nil
+			}
+		}
+		// Takes effect from the next response
+		config.StatusRefreshMs = ms
+	case "notifications", "notify":
+		if value == "true" || value == "on" || value == "yes" || value == "enabled" {
+			config.Notifications = "enabled"
+		} else {
+			config.Notifications = "disabled"
+		}
+	case "accessible", "accessibility":
+		if value == "true" || value == "on" || value == "yes" || value == "enabled" {
+			config.Accessible = "enabled"
+		} else {
+			config.Accessible = "disabled"
+		}
+		// Messages already on screen keep their labels until redrawn
+		c.commandEventBus.Emit("theme.changed", map[string]interface{}{
+			"oldTheme": config.Theme,
+			"newTheme": config.Theme,
+			"config":   config,
+		})
+	case "update-check", "update_check", "updatecheck":
+		if value == "true" || value == "on" || value == "yes" || value == "enabled" {
+			config.UpdateCheck = "enabled"
+		} else {
+			config.UpdateCheck = "disabled"
+		}
+		// Takes effect from the next start
+	case "show_thoughts", "show-thoughts", "showthoughts", "thoughts":
+		if value == "true" || value == "on" || value == "yes" || value == "enabled" {
+			config.ShowThoughts = "enabled"
+		} else {
+			config.ShowThoughts = "disabled"
+		}
+		// Takes effect from the next message
+	case "max_output_tokens", "max-output-tokens", "maxoutputtokens", "max_tokens":
+		tokens := 0
+		if !isDefaultValue(value) {
+			var err error
+			tokens, err = strconv.Atoi(value)
+			if err != nil || tokens < 1 {
+				c.notification.AddErrorMessage("Invalid max output tokens. Use a positive number, e.g. 1024, or default")
+				return nil
+			}
+		}
+		config.MaxOutputTokens = tokens
+	case "stop_sequences", "stop-sequences", "stopsequences", "stop":
+		config.StopSequences = parseStopSequences(value)
+	case "frequency_penalty", "frequency-penalty", "frequencypenalty", "presence_penalty", "presence-penalty", "presencepenalty":
+		penalty := 0.0
+		if !isDefaultValue(value) {
+			var err error
+			penalty, err = strconv.ParseFloat(value, 64)
+			if err != nil || penalty < -2 || penalty > 2 {
+				c.notification.AddErrorMessage("Invalid penalty. Use a number from -2 to 2, e.g. 0.5, or default")
+				return nil
+			}
+		}
+		if strings.HasPrefix(setting, "frequency") {
+			config.FrequencyPenalty = penalty
+		} else {
+			config.PresencePenalty = penalty
+		}
+	case "notificationthreshold", "notification-threshold":
+		seconds, err := strconv.Atoi(strings.TrimSuffix(value, "s"))
+		if err != nil || seconds < 0 {
+			c.notification.AddErrorMessage("Invalid notification threshold. Use a number of seconds, e.g. 30")
+			return nil
+		}
+		config.NotificationThresholdSeconds = seconds
 	}
 
 	// Save config
@@ -258,6 +385,28 @@ func (c *ConfigCommand) updateConfig(setting, value string, global bool) error {
 	return nil
 }
 
+// isDefaultValue reports a value that clears a setting back to the
+// persona's own
+func isDefaultValue(value string) bool {
+	return value == "default" || value == "none" || value == "off"
+}
+
+// parseStopSequences splits a comma-separated list of stop sequences,
+// reading \n and \t as a newline and a tab. none or default clears it.
+func parseStopSequences(value string) []string {
+	if isDefaultValue(value) {
+		return nil
+	}
+	unescape := strings.NewReplacer(`\n`, "\n", `\t`, "\t")
+	var sequences []string
+	for _, seq := range strings.Split(value, ",") {
+		if seq = unescape.Replace(strings.TrimSpace(seq)); seq != "" {
+			sequences = append(sequences, seq)
+		}
+	}
+	return sequences
+}
+
 func (c *ConfigCommand) updateToolConfig(toolName, property, value string, global bool) error {
 	// Validate property
 	if property != "accept" && property != "hide" {
@@ -1,29 +1,104 @@
 package commands
 
-import "github.com/kcaldas/genie/cmd/tui/controllers"
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/controllers"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+)
 
 type ContextCommand struct {
 	BaseCommand
-	controller *controllers.LLMContextController
+	controller   *controllers.LLMContextController
+	genieService genie.Genie
+	notification types.Notification
 }
 
-func NewContextCommand(controller *controllers.LLMContextController) *ContextCommand {
+func NewContextCommand(controller *controllers.LLMContextController, genieService genie.Genie, notification types.Notification) *ContextCommand {
 	return &ContextCommand{
 		BaseCommand: BaseCommand{
 			Name:        "context",
-			Description: "Show LLM context viewer with all context parts",
-			Usage:       ":context",
+			Description: "Show LLM context viewer with all context parts, or pin files into every turn",
+			Usage:       ":context [add <path|glob>... | remove <path|glob>... | list]\n\nPinned files are sent with every turn until removed, and read again when they change on disk.",
 			Examples: []string{
 				":context",
 				":ctx",
+				":context add docs/ARCHITECTURE.md",
+				":context add pkg/ctx/*.go",
+				":context remove pkg/ctx/*.go",
+				":context list",
 			},
 			Aliases:  []string{"ctx"},
 			Category: "General",
 		},
-		controller: controller,
+		controller:   controller,
+		genieService: genieService,
+		notification: notification,
 	}
 }
 
 func (c *ContextCommand) Execute(args []string) error {
-	return c.controller.Show()
+	if len(args) == 0 {
+		return c.controller.Show()
+	}
+
+	var err error
+	switch args[0] {
+	case "add", "pin":
+		err = c.add(args[1:])
+	case "remove", "rm", "unpin":
+		err = c.remove(args[1:])
+	case "list", "ls":
+		err = c.list()
+	default:
+		err = fmt.Errorf("unknown context action %q. Usage: :context [add|remove|list]", args[0])
+	}
+	if err != nil {
+		c.notification.AddErrorMessage(err.Error())
+	}
+	return nil
+}
+
+func (c *ContextCommand) add(patterns []string) error {
+	if len(patterns) == 0 {
+		return fmt.Errorf("usage: :context add <path|glob>...")
+	}
+	added, err := c.genieService.PinFiles(context.Background(), patterns...)
+	if err != nil {
+		return err
+	}
+	if len(added) == 0 {
+		c.notification.AddSystemMessage("Those files are already pinned")
+		return nil
+	}
+	c.notification.AddSystemMessage(fmt.Sprintf("Pinned %s; sent with every turn until :context remove", strings.Join(added, ", ")))
+	return nil
+}
+
+func (c *ContextCommand) remove(patterns []string) error {
+	if len(patterns) == 0 {
+		return fmt.Errorf("usage: :context remove <path|glob>...")
+	}
+	removed, err := c.genieService.UnpinFiles(patterns...)
+	if err != nil {
+		return err
+	}
+	c.notification.AddSystemMessage("Unpinned " + strings.Join(removed, ", "))
+	return nil
+}
+
+func (c *ContextCommand) list() error {
+	pinned, err := c.genieService.GetPinnedFiles()
+	if err != nil {
+		return err
+	}
+	if len(pinned) == 0 {
+		c.notification.AddSystemMessage("No pinned files. Pin some with :context add <path|glob>.")
+		return nil
+	}
+	c.notification.AddSystemMessage("Pinned files:\n  " + strings.Join(pinned, "\n  "))
+	return nil
 }
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextCommandPinsAndUnpins(t *testing.T) {
+	notification := &types.MockNotification{}
+	cmd := NewContextCommand(nil, &MockGenieService{}, notification)
+
+	require.NoError(t, cmd.Execute([]string{"add", "docs/*.md", "go.mod"}))
+	require.NoError(t, cmd.Execute([]string{"remove", "go.mod"}))
+	require.NoError(t, cmd.Execute([]string{"list"}))
+
+	require.Empty(t, notification.ErrorMessages)
+	assert.Equal(t, []string{
+		"Pinned docs/*.md, go.mod; sent with every turn until :context remove",
+		"Unpinned go.mod",
+		"No pinned files. Pin some with :context add <path|glob>.",
+	}, notification.SystemMessages)
+}
+
+func TestContextCommandErrors(t *testing.T) {
+	notification := &types.MockNotification{}
+	cmd := NewContextCommand(nil, &MockGenieService{}, notification)
+
+	require.NoError(t, cmd.Execute([]string{"add"}))
+	require.NoError(t, cmd.Execute([]string{"drop", "go.mod"}))
+	assert.Len(t, notification.ErrorMessages, 2)
+	assert.Contains(t, notification.ErrorMessages[1], "unknown context action")
+}
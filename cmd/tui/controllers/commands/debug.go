@@ -19,10 +19,14 @@ func NewDebugCommand(controller *controllers.DebugController, notification *cont
 	return &DebugCommand{
 		BaseCommand: BaseCommand{
 			Name:        "debug",
-			Description: "Toggle debug logging on/off (use F12 to view debug panel)",
-			Usage:       ":debug",
+			Description: "Toggle debug logging, or filter and export the F12 event inspector",
+			Usage:       ":debug [filter <types or tools> | export [file]]",
 			Examples: []string{
 				":debug",
+				":debug filter tool.executed bash",
+				":debug filter",
+				":debug export",
+				":debug export events.txt",
 			},
 			Aliases:  []string{},
 			Category: "Development",
@@ -33,6 +37,17 @@ func NewDebugCommand(controller *controllers.DebugController, notification *cont
 }
 
 func (c *DebugCommand) Execute(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "filter":
+			return c.setFilter(args[1:])
+		case "export":
+			return c.export(args[1:])
+		default:
+			return fmt.Errorf("unknown :debug option %q (use filter or export)", args[0])
+		}
+	}
+
 	// Toggle debug level via environment variable approach
 	currentLevel := os.Getenv("GENIE_DEBUG_LEVEL")
 
@@ -57,9 +72,32 @@ func (c *DebugCommand) Execute(args []string) error {
 	// Show status message with file location
 	debugFile := logging.GetDebugFilePath("genie-debug.log")
 
-	message := fmt.Sprintf("Debug logging %s. File: %s. Use F12 to view debug panel or tail externally.",
+	message := fmt.Sprintf("Debug logging %s. File: %s. Use F12 to inspect events or tail the file externally.",
 		status, debugFile)
 	c.notification.AddSystemMessage(message)
 
 	return nil
 }
+
+// setFilter limits the inspector to the given event types or tool names;
+// no terms clears the filter.
+func (c *DebugCommand) setFilter(terms []string) error {
+	filter := strings.Join(terms, " ")
+	c.controller.SetFilter(filter)
+	if filter == "" {
+		c.notification.AddSystemMessage("Debug inspector filter cleared.")
+	} else {
+		c.notification.AddSystemMessage(fmt.Sprintf("Debug inspector showing events matching: %s", filter))
+	}
+	return nil
+}
+
+// export writes the inspector's current view to a file
+func (c *DebugCommand) export(args []string) error {
+	path, err := c.controller.ExportView(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	c.notification.AddSystemMessage(fmt.Sprintf("Debug inspector view exported to %s", path))
+	return nil
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/events"
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+type DiffCommand struct {
+	BaseCommand
+	configManager   *helpers.ConfigManager
+	commandEventBus *events.CommandEventBus
+	notification    types.Notification
+}
+
+func NewDiffCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, notification types.Notification) *DiffCommand {
+	return &DiffCommand{
+		BaseCommand: BaseCommand{
+			Name:        "diff",
+			Description: "Choose how the diff viewer lays out changes",
+			Usage:       ":diff [mode unified|side-by-side] [collapse on|off]\n\nIn the diff viewer, n and p jump to the next and previous hunk. Collapse folds long runs of unchanged lines.",
+			Examples: []string{
+				":diff",
+				":diff mode side-by-side",
+				":diff mode unified",
+				":diff collapse off",
+			},
+			Category: "Configuration",
+		},
+		configManager:   configManager,
+		commandEventBus: commandEventBus,
+		notification:    notification,
+	}
+}
+
+func (c *DiffCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		config := c.configManager.GetConfig()
+		mode := config.DiffMode
+		if mode == "" {
+			mode = presentation.DiffModeUnified
+		}
+		collapse := "on"
+		if !config.IsDiffCollapseEnabled() {
+			collapse = "off"
+		}
+		c.notification.AddSystemMessage(fmt.Sprintf("Diffs: %s, unchanged regions collapse %s.\nModes: %s", mode, collapse, strings.Join(presentation.DiffModes, ", ")))
+		return nil
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("usage: :diff mode <%s> or :diff collapse <on|off>", strings.Join(presentation.DiffModes, "|"))
+	}
+
+	var update func(*types.Config)
+	var message string
+	switch setting, value := args[0], args[1]; setting {
+	case "mode":
+		if value == "split" {
+			value = presentation.DiffModeSideBySide
+		}
+		if !slices.Contains(presentation.DiffModes, value) {
+			c.notification.AddErrorMessage(fmt.Sprintf("Unknown diff mode %q. Available: %s", value, strings.Join(presentation.DiffModes, ", ")))
+			return nil
+		}
+		update = func(config *types.Config) { config.DiffMode = value }
+		message = fmt.Sprintf("Diffs are shown %s", value)
+	case "collapse":
+		enabled := value == "true" || value == "on" || value == "yes" || value == "enabled"
+		update = func(config *types.Config) {
+			config.DiffCollapse = "disabled"
+			if enabled {
+				config.DiffCollapse = "enabled"
+			}
+		}
+		message = "Diffs show every unchanged line"
+		if enabled {
+			message = "Diffs fold long runs of unchanged lines"
+		}
+	default:
+		return fmt.Errorf("unknown diff setting %q. Usage: :diff mode <%s> or :diff collapse <on|off>", setting, strings.Join(presentation.DiffModes, "|"))
+	}
+
+	if err := c.configManager.UpdateConfig(update, true); err != nil {
+		c.notification.AddErrorMessage(fmt.Sprintf("%s, but the setting could not be saved: %v", message, err))
+	} else {
+		c.notification.AddSystemMessage(message)
+	}
+	c.commandEventBus.Emit("diff.mode.changed", nil)
+	return nil
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"github.com/kcaldas/genie/cmd/help"
 	"github.com/kcaldas/genie/cmd/tui/controllers"
 )
 
@@ -14,14 +15,14 @@ func NewHelpCommand(helpController controllers.HelpControllerInterface) *HelpCom
 		BaseCommand: BaseCommand{
 			Name:        "help",
 			Description: "Show help message with available commands and shortcuts",
-			Usage:       ":help [command]",
+			Usage:       ":help [/ | topic]",
 			Examples: []string{
 				":help",
 				":?",
 				":help /",
 				":help slash",
-				":help config",
-				":help theme",
+				":help personas",
+				":help policies",
 			},
 			Aliases:   []string{"h", "?"},
 			Category:  "General",
@@ -40,6 +41,13 @@ func (c *HelpCommand) Execute(args []string) error {
 		return nil
 	}
 
+	// Topic pages: personas, tools, policies, themes, serving
+	if len(args) > 0 {
+		if topic, ok := help.Lookup(args[0]); ok {
+			return c.helpController.ShowTopic(topic.Name)
+		}
+	}
+
 	// Default help behavior
 	if err := c.helpController.ToggleHelp(); err != nil {
 		return err
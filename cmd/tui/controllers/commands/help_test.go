@@ -23,6 +23,11 @@ func (m *MockHelpController) ShowSlashCommandsHelp() error {
 	return args.Error(0)
 }
 
+func (m *MockHelpController) ShowTopic(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
 func (m *MockHelpController) ToggleHelp() error {
 	args := m.Called()
 	return args.Error(0)
@@ -64,6 +69,12 @@ func TestHelpCommand_Execute(t *testing.T) {
 			expectedMethod: "ToggleHelp",
 			description:    "Should call ToggleHelp for other arguments",
 		},
+		{
+			name:           "topic argument",
+			args:           []string{"Policies"},
+			expectedMethod: "ShowTopic",
+			description:    "Should call ShowTopic when the argument names a help topic",
+		},
 		{
 			name:           "multiple arguments with slash",
 			args:           []string{"/", "extra"},
@@ -79,6 +90,8 @@ func TestHelpCommand_Execute(t *testing.T) {
 			// Set up expectation based on which method should be called
 			if tt.expectedMethod == "ShowSlashCommandsHelp" {
 				mockController.On("ShowSlashCommandsHelp").Return(nil)
+			} else if tt.expectedMethod == "ShowTopic" {
+				mockController.On("ShowTopic", "policies").Return(nil)
 			} else {
 				mockController.On("ToggleHelp").Return(nil)
 			}
@@ -100,7 +113,7 @@ func TestHelpCommand_Metadata(t *testing.T) {
 	// Test command metadata
 	assert.Equal(t, "help", helpCommand.GetName())
 	assert.Equal(t, "Show help message with available commands and shortcuts", helpCommand.GetDescription())
-	assert.Equal(t, ":help [command]", helpCommand.GetUsage())
+	assert.Equal(t, ":help [/ | topic]", helpCommand.GetUsage())
 	assert.Contains(t, helpCommand.GetAliases(), "h")
 	assert.Contains(t, helpCommand.GetAliases(), "?")
 	assert.Equal(t, "General", helpCommand.GetCategory())
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/layout"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+type LayoutCommand struct {
+	BaseCommand
+	layoutManager *layout.LayoutManager
+	configManager *helpers.ConfigManager
+	notification  types.Notification
+}
+
+func NewLayoutCommand(layoutManager *layout.LayoutManager, configManager *helpers.ConfigManager, notification types.Notification) *LayoutCommand {
+	return &LayoutCommand{
+		BaseCommand: BaseCommand{
+			Name:        "layout",
+			Description: "Switch between layout presets; panel sizes are remembered",
+			Usage:       ":layout [default|single|split|debug]",
+			Examples: []string{
+				":layout",
+				":layout single",
+				":layout split",
+				":layout debug",
+				":layout default",
+			},
+			Aliases:  []string{},
+			Category: "Configuration",
+		},
+		layoutManager: layoutManager,
+		configManager: configManager,
+		notification:  notification,
+	}
+}
+
+func (c *LayoutCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		geometry := c.layoutManager.Geometry()
+		preset := geometry.Preset
+		if preset == "" {
+			preset = layout.PresetDefault
+		}
+		c.notification.AddSystemMessage(fmt.Sprintf(
+			"Layout: %s\nWidths (todo : messages : side): %d : %d : %d\nInput height: %d rows\n\nPresets: %s\nResize with Shift+←/→ (side panel) and Shift+↑/↓ (input), or drag a panel border.",
+			preset,
+			geometry.LeftWeight, geometry.MessagesWeight, geometry.RightWeight,
+			geometry.InputHeight,
+			strings.Join(layout.PresetNames, ", "),
+		))
+		return nil
+	}
+
+	preset := args[0]
+	if err := c.layoutManager.ApplyPreset(preset); err != nil {
+		c.notification.AddErrorMessage(fmt.Sprintf("%v. Available layouts: %s", err, strings.Join(layout.PresetNames, ", ")))
+		return nil
+	}
+
+	geometry := c.layoutManager.Geometry()
+	if err := c.configManager.UpdateConfig(func(config *types.Config) {
+		config.Layout.Geometry = geometry
+	}, true); err != nil {
+		c.notification.AddErrorMessage(fmt.Sprintf("Layout changed to %s but could not be saved: %v", preset, err))
+		return nil
+	}
+	c.notification.AddSystemMessage(fmt.Sprintf("Layout changed to %s", preset))
+	return nil
+}
@@ -21,12 +21,28 @@ func (m *MockPersona) GetSource() string { return m.source }
 
 // mockSession implements the genie.Session interface for testing
 type mockSession struct {
-	persona genie.Persona
+	persona       genie.Persona
+	modelProvider string
+	modelName     string
+	readOnlyMode  bool
+	workingDir    string
+	genieHomeDir  string
+	scope         []string
 }
 
-func (m *mockSession) GetID() string                   { return "test-id" }
-func (m *mockSession) GetWorkingDirectory() string     { return "/test/dir" }
-func (m *mockSession) GetGenieHomeDirectory() string   { return "/test/home" }
+func (m *mockSession) GetID() string { return "test-id" }
+func (m *mockSession) GetWorkingDirectory() string {
+	if m.workingDir == "" {
+		return "/test/dir"
+	}
+	return m.workingDir
+}
+func (m *mockSession) GetGenieHomeDirectory() string {
+	if m.genieHomeDir == "" {
+		return "/test/home"
+	}
+	return m.genieHomeDir
+}
 func (m *mockSession) GetAllowedDirectories() []string { return nil }
 func (m *mockSession) GetCreatedAt() string            { return "test-time" }
 func (m *mockSession) GetPersona() genie.Persona {
@@ -42,6 +58,27 @@ func (m *mockSession) GetCommitAuthor() (string, string) { return "", "" }
 func (m *mockSession) SetDeniedPaths([]string)           {}
 func (m *mockSession) SetReadOnlyPaths([]string)         {}
 func (m *mockSession) SetCommitAuthor(string, string)    {}
+func (m *mockSession) SetWorkingDirectory(dir string)    { m.workingDir = dir }
+func (m *mockSession) SetGenieHomeDirectory(dir string)  { m.genieHomeDir = dir }
+func (m *mockSession) GetModel() (string, string)        { return m.modelProvider, m.modelName }
+func (m *mockSession) SetModel(provider, model string) {
+	m.modelProvider, m.modelName = provider, model
+}
+func (m *mockSession) GetReadOnlyMode() bool              { return m.readOnlyMode }
+func (m *mockSession) SetReadOnlyMode(enabled bool)       { m.readOnlyMode = enabled }
+func (m *mockSession) GetDisabledContextParts() []string  { return nil }
+func (m *mockSession) SetContextPartEnabled(string, bool) {}
+func (m *mockSession) GetPinnedFiles() []string           { return nil }
+func (m *mockSession) SetPinnedFiles([]string)            {}
+func (m *mockSession) GetScope() []string                 { return m.scope }
+func (m *mockSession) SetScope(dirs []string)             { m.scope = dirs }
+func (m *mockSession) IsWorkspaceTrusted() bool           { return true }
+func (m *mockSession) SetWorkspaceTrusted(bool)           {}
+func (m *mockSession) IsToolApproved(string, string) bool { return false }
+func (m *mockSession) ApproveTool(string, string)         {}
+func (m *mockSession) GetToolApprovals() []genie.ToolApproval {
+	return nil
+}
 
 // MockGenieService implements genie.Genie for testing
 type MockGenieService struct {
@@ -49,6 +86,10 @@ type MockGenieService struct {
 	mockPersonas      []genie.Persona
 	mockPersonasError error
 	mockSession       genie.Session
+	chatHistory       []genie.ChatHistoryTurn
+	missingTools      []genie.MissingTool
+	toolsRegistry     tools.Registry
+	changedTrusted    bool
 }
 
 func (m *MockGenieService) Start(workingDir *string, persona *string, _ ...genie.StartOption) (genie.Session, error) {
@@ -63,6 +104,22 @@ func (m *MockGenieService) GetContext(ctx context.Context) (map[string]string, e
 	return map[string]string{}, nil
 }
 
+func (m *MockGenieService) GetContextUsage(ctx context.Context) (*genie.ContextUsage, error) {
+	return &genie.ContextUsage{}, nil
+}
+
+func (m *MockGenieService) PinFiles(ctx context.Context, patterns ...string) ([]string, error) {
+	return patterns, nil
+}
+
+func (m *MockGenieService) UnpinFiles(patterns ...string) ([]string, error) {
+	return patterns, nil
+}
+
+func (m *MockGenieService) GetPinnedFiles() ([]string, error) {
+	return nil, nil
+}
+
 func (m *MockGenieService) GetStatus() *genie.Status {
 	return m.mockStatus
 }
@@ -86,14 +143,39 @@ func (m *MockGenieService) GetSession() (genie.Session, error) {
 }
 
 func (m *MockGenieService) GetToolsRegistry() (tools.Registry, error) {
-	return nil, nil
+	return m.toolsRegistry, nil
+}
+
+func (m *MockGenieService) ChangeWorkingDirectory(dir string, trusted bool) (genie.Session, error) {
+	session, _ := m.GetSession()
+	session.SetWorkingDirectory(dir)
+	session.SetGenieHomeDirectory(dir)
+	m.changedTrusted = trusted
+	return session, nil
 }
 
 func (m *MockGenieService) RecalculateContextBudget(ctx context.Context) error {
 	return nil
 }
 
-func (m *MockGenieService) MissingTools() []string {
+func (m *MockGenieService) GetChatHistory() ([]genie.ChatHistoryTurn, error) {
+	return m.chatHistory, nil
+}
+
+func (m *MockGenieService) ReplaceChatHistory(turns []genie.ChatHistoryTurn) error {
+	m.chatHistory = append([]genie.ChatHistoryTurn(nil), turns...)
+	return nil
+}
+
+func (m *MockGenieService) MissingTools() []genie.MissingTool {
+	return m.missingTools
+}
+
+func (m *MockGenieService) ToolIssues() []tools.ToolIssue {
+	return nil
+}
+
+func (m *MockGenieService) ToolConflicts() []tools.ToolConflict {
 	return nil
 }
 
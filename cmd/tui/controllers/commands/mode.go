@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+type ModeCommand struct {
+	BaseCommand
+	notification types.Notification
+	genieService genie.Genie
+}
+
+func NewModeCommand(notification types.Notification, genieService genie.Genie) *ModeCommand {
+	return &ModeCommand{
+		BaseCommand: BaseCommand{
+			Name:        "mode",
+			Description: "Switch between plan (read-only) and act mode",
+			Usage:       ":mode [plan | act]\n\nIn plan mode tools that write files, run side-effecting commands or start agents are disabled; the model can only read and propose plans or diffs. Same as starting with --read-only.",
+			Examples: []string{
+				":mode",
+				":mode plan",
+				":mode act",
+			},
+			Category: "Chat",
+		},
+		notification: notification,
+		genieService: genieService,
+	}
+}
+
+func (c *ModeCommand) Execute(args []string) error {
+	session, err := c.genieService.GetSession()
+	if err != nil {
+		return fmt.Errorf("failed to get current session: %w", err)
+	}
+
+	if len(args) == 0 {
+		if session.GetReadOnlyMode() {
+			c.notification.AddSystemMessage("Mode: plan (read-only). Use :mode act to allow changes.")
+		} else {
+			c.notification.AddSystemMessage("Mode: act. Use :mode plan to make the session read-only.")
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "plan", "read-only", "readonly":
+		session.SetReadOnlyMode(true)
+		c.notification.AddSystemMessage("Switched to plan mode: file writes and side-effecting commands are disabled.")
+	case "act":
+		session.SetReadOnlyMode(false)
+		c.notification.AddSystemMessage("Switched to act mode: all tools are available.")
+	default:
+		return fmt.Errorf("unknown mode %q. Usage: :mode [plan | act]", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModeCommand(t *testing.T) {
+	notification := &types.MockNotification{}
+	session := &mockSession{}
+	cmd := NewModeCommand(notification, &MockGenieService{mockSession: session})
+
+	require.NoError(t, cmd.Execute([]string{"plan"}))
+	assert.True(t, session.GetReadOnlyMode())
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Contains(t, notification.SystemMessages[1], "Mode: plan")
+
+	require.NoError(t, cmd.Execute([]string{"act"}))
+	assert.False(t, session.GetReadOnlyMode())
+
+	assert.Error(t, cmd.Execute([]string{"yolo"}))
+}
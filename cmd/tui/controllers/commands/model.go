@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/models"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+type ModelCommand struct {
+	BaseCommand
+	notification types.Notification
+	genieService genie.Genie
+}
+
+func NewModelCommand(notification types.Notification, genieService genie.Genie) *ModelCommand {
+	return &ModelCommand{
+		BaseCommand: BaseCommand{
+			Name:        "model",
+			Description: "Show or switch the model used for the rest of the session",
+			Usage:       ":model [name | provider/name | list | reset]\n\nThe provider is inferred for known models; use provider/name for others (e.g. ollama/llama3.1). Switching persona goes back to that persona's model.",
+			Examples: []string{
+				":model",
+				":model gemini-2.5-pro",
+				":model anthropic/claude-sonnet-4-5",
+				":model list",
+				":model reset",
+			},
+			Category: "Chat",
+		},
+		notification: notification,
+		genieService: genieService,
+	}
+}
+
+func (c *ModelCommand) Execute(args []string) error {
+	session, err := c.genieService.GetSession()
+	if err != nil {
+		return fmt.Errorf("failed to get current session: %w", err)
+	}
+
+	if len(args) == 0 {
+		c.showCurrent(session)
+		return nil
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		c.executeList()
+		return nil
+	case "reset":
+		session.SetModel("", "")
+		c.recalculateBudget(session)
+		c.notification.AddSystemMessage("Model reset to the persona default.")
+		return nil
+	}
+
+	provider, model := models.Parse(args[0])
+	if model == "" {
+		return fmt.Errorf("invalid model %q. Usage: :model <name> or :model <provider>/<name>", args[0])
+	}
+	session.SetModel(provider, model)
+	c.recalculateBudget(session)
+
+	if provider == "" {
+		c.notification.AddSystemMessage(fmt.Sprintf("Switched to model %s (unknown model, using the persona's provider).", model))
+	} else {
+		c.notification.AddSystemMessage(fmt.Sprintf("Switched to model %s (%s).", model, provider))
+	}
+	return nil
+}
+
+func (c *ModelCommand) showCurrent(session genie.Session) {
+	if provider, model := session.GetModel(); model != "" {
+		if provider != "" {
+			model = provider + "/" + model
+		}
+		c.notification.AddSystemMessage(fmt.Sprintf("Model: %s (set with :model, :model reset for the persona default)", model))
+		return
+	}
+
+	status := c.genieService.GetStatus()
+	if status != nil && status.Model != "" {
+		c.notification.AddSystemMessage(fmt.Sprintf("Model: %s on %s", status.Model, status.Backend))
+		return
+	}
+	c.notification.AddSystemMessage("Model: persona default")
+}
+
+func (c *ModelCommand) executeList() {
+	var builder strings.Builder
+	builder.WriteString("Known models (any model the provider accepts also works):\n")
+	for _, provider := range models.Providers() {
+		fmt.Fprintf(&builder, "  %s: %s\n", provider, strings.Join(models.Known(provider), ", "))
+	}
+	builder.WriteString("  ollama, lmstudio: use ollama/<name> or lmstudio/<name> for local models\n")
+	c.notification.AddSystemMessage(builder.String())
+}
+
+// recalculateBudget resizes the context budget for the new model's window
+func (c *ModelCommand) recalculateBudget(session genie.Session) {
+	ctx := toolctx.WithGenieHome(context.Background(), session.GetGenieHomeDirectory())
+	ctx = toolctx.WithWorkingDir(ctx, session.GetWorkingDirectory())
+	if persona := session.GetPersona(); persona != nil {
+		ctx = toolctx.WithPersona(ctx, persona.GetID())
+	}
+	_ = c.genieService.RecalculateContextBudget(ctx)
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelCommand_Switch(t *testing.T) {
+	notification := &types.MockNotification{}
+	session := &mockSession{}
+	cmd := NewModelCommand(notification, &MockGenieService{mockSession: session})
+
+	require.NoError(t, cmd.Execute([]string{"claude-sonnet-4-5"}))
+	provider, model := session.GetModel()
+	assert.Equal(t, "anthropic", provider)
+	assert.Equal(t, "claude-sonnet-4-5", model)
+
+	require.NoError(t, cmd.Execute([]string{"ollama/llama3.1"}))
+	provider, model = session.GetModel()
+	assert.Equal(t, "ollama", provider)
+	assert.Equal(t, "llama3.1", model)
+
+	require.NoError(t, cmd.Execute([]string{"my-finetune"}))
+	provider, model = session.GetModel()
+	assert.Empty(t, provider)
+	assert.Equal(t, "my-finetune", model)
+	assert.Contains(t, notification.SystemMessages[2], "using the persona's provider")
+
+	require.NoError(t, cmd.Execute([]string{"reset"}))
+	_, model = session.GetModel()
+	assert.Empty(t, model)
+}
+
+func TestModelCommand_ShowCurrent(t *testing.T) {
+	notification := &types.MockNotification{}
+	session := &mockSession{}
+	mockGenie := &MockGenieService{
+		mockSession: session,
+		mockStatus:  &genie.Status{Model: "gemini-2.5-flash (persona)", Backend: "genai"},
+	}
+	cmd := NewModelCommand(notification, mockGenie)
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, "Model: gemini-2.5-flash (persona) on genai", notification.SystemMessages[0])
+
+	session.SetModel("genai", "gemini-2.5-pro")
+	require.NoError(t, cmd.Execute(nil))
+	assert.Contains(t, notification.SystemMessages[1], "Model: genai/gemini-2.5-pro")
+}
+
+func TestModelCommand_List(t *testing.T) {
+	notification := &types.MockNotification{}
+	cmd := NewModelCommand(notification, &MockGenieService{})
+
+	require.NoError(t, cmd.Execute([]string{"list"}))
+	require.Len(t, notification.SystemMessages, 1)
+	assert.Contains(t, notification.SystemMessages[0], "genai: gemini-3-pro-preview, gemini-2.5-pro")
+	assert.Contains(t, notification.SystemMessages[0], "anthropic:")
+}
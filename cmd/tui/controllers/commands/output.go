@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kcaldas/genie/cmd/tui/controllers"
+)
+
+type OutputCommand struct {
+	BaseCommand
+	controller *controllers.ChatController
+}
+
+func NewOutputCommand(controller *controllers.ChatController) *OutputCommand {
+	return &OutputCommand{
+		BaseCommand: BaseCommand{
+			Name:        "output",
+			Description: "Expand or collapse the full output of a truncated tool call (1 = most recent)",
+			Usage:       ":output [n]",
+			Examples: []string{
+				":output",
+				":output 2",
+			},
+			Aliases:  []string{"out"},
+			Category: "Chat",
+		},
+		controller: controller,
+	}
+}
+
+func (c *OutputCommand) Execute(args []string) error {
+	index := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid output number '%s'. Use a positive number, e.g. :output 2", args[0])
+		}
+		index = n
+	}
+
+	expanded, err := c.controller.ToggleToolOutput(index)
+	if err != nil {
+		c.controller.AddErrorMessage(err.Error())
+		return nil
+	}
+
+	if expanded {
+		c.controller.AddSystemMessage(fmt.Sprintf("Expanded tool output %d. Run :output %d again to collapse.", index, index))
+	} else {
+		c.controller.AddSystemMessage(fmt.Sprintf("Collapsed tool output %d.", index))
+	}
+	return nil
+}
@@ -3,12 +3,15 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/types"
 	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/persona"
 	"github.com/kcaldas/genie/pkg/toolctx"
 )
 
@@ -25,7 +28,7 @@ func NewPersonaCommand(notification types.Notification, genieService genie.Genie
 		BaseCommand: BaseCommand{
 			Name:        "persona",
 			Description: "Manage personas",
-			Usage:       ":persona list (or :p -l) | :persona swap <persona_id> (or :p -s <persona_id>) | :persona cycle add/remove <persona_id> | :persona next",
+			Usage:       ":persona list (or :p -l) | :persona swap <persona_id> (or :p -s <persona_id>) | :persona cycle add/remove <persona_id> | :persona next | :persona new <persona_id> <purpose>",
 			Examples: []string{
 				":persona list",
 				":p -l",
@@ -34,6 +37,7 @@ func NewPersonaCommand(notification types.Notification, genieService genie.Genie
 				":persona cycle add engineer",
 				":persona cycle remove engineer",
 				":persona next",
+				":persona new sql_helper write and explain SQL queries",
 			},
 			Aliases:  []string{"p"},
 			Category: "Persona",
@@ -68,8 +72,13 @@ func (c *PersonaCommand) Execute(args []string) error {
 		return c.executeCycle(args[1], args[2])
 	case "next":
 		return c.executeCycleNext()
+	case "new":
+		if len(args) < 3 {
+			return fmt.Errorf("new requires a persona ID and its purpose. Usage: :persona new <persona_id> <purpose>")
+		}
+		return c.executeNew(args[1], strings.Join(args[2:], " "))
 	default:
-		return fmt.Errorf("unknown subcommand '%s'. Available: list, swap, cycle, next", subcommand)
+		return fmt.Errorf("unknown subcommand '%s'. Available: list, swap, cycle, next, new", subcommand)
 	}
 }
 
@@ -156,6 +165,9 @@ func (c *PersonaCommand) executeSwap(personaId string) error {
 	// Provide success feedback
 	c.notification.AddSystemMessage(fmt.Sprintf("Switched to persona '%s' (%s) from %s",
 		personaId, foundPersona.GetName(), foundPersona.GetSource()))
+	if missing := helpers.FormatMissingTools(c.genieService.MissingTools()); missing != "" {
+		c.notification.AddSystemMessage(missing)
+	}
 
 	// Emit persona change event to update UI title
 	c.commandEventBus.Emit("persona.changed", map[string]interface{}{
@@ -165,6 +177,40 @@ func (c *PersonaCommand) executeSwap(personaId string) error {
 	return nil
 }
 
+// executeNew creates a user persona from its purpose, with the suggested
+// tools. genie persona new offers the same with every choice.
+func (c *PersonaCommand) executeNew(personaId, purpose string) error {
+	if err := persona.ValidateID(personaId); err != nil {
+		return err
+	}
+	registry, err := c.genieService.GetToolsRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to get tools: %w", err)
+	}
+
+	draft := persona.Draft{ID: personaId, Purpose: purpose, Tools: persona.SuggestTools(purpose, registry)}
+	data, err := draft.YAML()
+	if err != nil {
+		return err
+	}
+	if err := persona.ValidatePrompt(data, registry); err != nil {
+		return fmt.Errorf("the generated persona is not valid: %w", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path, err := persona.WriteDraft(filepath.Join(home, ".genie", "personas"), personaId, data, false)
+	if err != nil {
+		return err
+	}
+
+	c.notification.AddSystemMessage(fmt.Sprintf("Created persona '%s' with tools %s at %s\nEdit it to refine the instruction, then use :persona swap %s",
+		personaId, strings.Join(draft.Tools, ", "), path, personaId))
+	return nil
+}
+
 func (c *PersonaCommand) executeCycle(action, personaId string) error {
 	ctx := context.Background()
 
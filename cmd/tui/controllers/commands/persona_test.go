@@ -1,12 +1,15 @@
 package commands
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	genieevents "github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/tools"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -249,6 +252,28 @@ func TestPersonaCommand_Execute(t *testing.T) {
 		assert.Contains(t, err.Error(), "Usage: :persona swap <persona_id>")
 	})
 
+	t.Run("new subcommand", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		mockGenie.toolsRegistry = tools.NewDefaultRegistry(&genieevents.NoOpEventBus{}, tools.NewTodoManager(), nil, nil)
+		mockNotification.SystemMessages = []string{} // Reset
+
+		err := cmd.Execute([]string{"new", "sql_helper", "explain", "SQL", "queries"})
+		assert.NoError(t, err)
+		path := filepath.Join(home, ".genie", "personas", "sql_helper", "prompt.yaml")
+		assert.FileExists(t, path)
+		assert.Len(t, mockNotification.SystemMessages, 1)
+		assert.Contains(t, mockNotification.SystemMessages[0], "Created persona 'sql_helper'")
+		assert.Contains(t, mockNotification.SystemMessages[0], path)
+
+		err = cmd.Execute([]string{"new", "sql_helper", "again"})
+		assert.ErrorContains(t, err, "already exists")
+		err = cmd.Execute([]string{"new", "sql_helper"})
+		assert.ErrorContains(t, err, "Usage: :persona new <persona_id> <purpose>")
+		err = cmd.Execute([]string{"new", "Bad ID", "anything"})
+		assert.ErrorContains(t, err, "must be lowercase")
+	})
+
 	t.Run("integration - error displayed to user", func(t *testing.T) {
 		// This test verifies that command errors are properly shown to users
 		// Test the command directly since error handling is in the command handler
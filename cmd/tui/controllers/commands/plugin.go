@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/plugin"
+)
+
+// PluginCommand runs a command a plugin adds to the TUI
+type PluginCommand struct {
+	BaseCommand
+	run          func(ctx context.Context, args []string) (string, error)
+	notification types.Notification
+}
+
+func NewPluginCommand(cmd plugin.Command, notification types.Notification) *PluginCommand {
+	usage := cmd.Usage
+	if usage == "" {
+		usage = ":" + cmd.Name
+	}
+	return &PluginCommand{
+		BaseCommand: BaseCommand{
+			Name:        cmd.Name,
+			Description: cmd.Description,
+			Usage:       usage,
+			Category:    "Plugins",
+		},
+		run:          cmd.Run,
+		notification: notification,
+	}
+}
+
+func (c *PluginCommand) Execute(args []string) error {
+	if c.run == nil {
+		return nil
+	}
+	output, err := c.run(context.Background(), args)
+	if err != nil {
+		c.notification.AddErrorMessage(err.Error())
+		return nil
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		c.notification.AddSystemMessage(output)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginCommand_Execute(t *testing.T) {
+	notification := &types.MockNotification{}
+	var got []string
+	cmd := NewPluginCommand(plugin.Command{
+		Name:        "ticket",
+		Description: "Show a ticket",
+		Run: func(ctx context.Context, args []string) (string, error) {
+			got = args
+			if len(args) == 0 {
+				return "", errors.New("usage: :ticket <id>")
+			}
+			return "PROJ-1: Fix the login page\n", nil
+		},
+	}, notification)
+
+	assert.Equal(t, "ticket", cmd.GetName())
+	assert.Equal(t, ":ticket", cmd.GetUsage())
+	assert.Equal(t, "Plugins", cmd.GetCategory())
+
+	require.NoError(t, cmd.Execute([]string{"PROJ-1"}))
+	assert.Equal(t, []string{"PROJ-1"}, got)
+	assert.Equal(t, []string{"PROJ-1: Fix the login page"}, notification.SystemMessages)
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, []string{"usage: :ticket <id>"}, notification.ErrorMessages)
+}
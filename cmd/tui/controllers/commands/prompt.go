@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/controllers"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+type PromptCommand struct {
+	BaseCommand
+	controller   *controllers.PromptController
+	notification types.Notification
+}
+
+func NewPromptCommand(controller *controllers.PromptController, notification types.Notification) *PromptCommand {
+	return &PromptCommand{
+		BaseCommand: BaseCommand{
+			Name:        "prompt",
+			Description: "Insert a prompt template from .genie/prompts, filling in its {{variables}}",
+			Usage:       ":prompt [name] [variable=value ...]\n\nWithout a name, lists the available templates. Names are fuzzy-matched and Tab completes them. Variables not given inline are asked for one at a time.",
+			Examples: []string{
+				":prompt",
+				":prompt review",
+				":prompt review file=main.go",
+			},
+			Category: "Chat",
+		},
+		controller:   controller,
+		notification: notification,
+	}
+}
+
+func (c *PromptCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.executeList()
+	}
+
+	values := make(map[string]string)
+	for _, arg := range args[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid variable %q. Usage: :prompt <name> variable=value", arg)
+		}
+		values[key] = value
+	}
+	return c.controller.Insert(args[0], values)
+}
+
+func (c *PromptCommand) executeList() error {
+	if err := c.controller.Discover(); err != nil {
+		return fmt.Errorf("failed to discover prompt templates: %w", err)
+	}
+
+	templates := c.controller.GetTemplates()
+	if len(templates) == 0 {
+		c.notification.AddSystemMessage("No prompt templates found. Add Markdown files with {{variables}} to .genie/prompts/ or ~/.genie/prompts/.")
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Available prompt templates:\n")
+	for _, template := range templates {
+		fmt.Fprintf(&builder, "  • %s (%s)", template.Name, template.Source)
+		if len(template.Variables) > 0 {
+			fmt.Fprintf(&builder, " [%s]", strings.Join(template.Variables, ", "))
+		}
+		if template.Description != "" {
+			fmt.Fprintf(&builder, " - %s", template.Description)
+		}
+		builder.WriteString("\n")
+	}
+	c.notification.AddSystemMessage(builder.String())
+	return nil
+}
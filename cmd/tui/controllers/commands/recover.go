@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/kcaldas/genie/cmd/tui/recovery"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// TranscriptRestorer is the part of the chat controller that puts saved
+// messages back in the transcript
+type TranscriptRestorer interface {
+	RestoreTranscript(messages []types.Message)
+}
+
+// InputSetter is the part of the input component that fills in a draft
+type InputSetter interface {
+	SetText(text string)
+}
+
+type RecoverCommand struct {
+	BaseCommand
+	dir          string
+	restorer     TranscriptRestorer
+	input        InputSetter
+	notification types.Notification
+}
+
+func NewRecoverCommand(dir string, restorer TranscriptRestorer, input InputSetter, notification types.Notification) *RecoverCommand {
+	return &RecoverCommand{
+		BaseCommand: BaseCommand{
+			Name:        "recover",
+			Description: "Restore the transcript and unsent input saved when Genie crashed or was killed",
+			Usage:       ":recover [discard]\n\nWhen Genie crashes, or is killed by SIGTERM or SIGHUP such as when its terminal closes, it saves the transcript and the message you were typing in .genie/recovery. :recover puts the newest snapshot back: the messages in the transcript, for reference only, and the draft in the input. :recover discard deletes the snapshots.",
+			Examples: []string{
+				":recover",
+				":recover discard",
+			},
+			Category: "Chat",
+		},
+		dir:          dir,
+		restorer:     restorer,
+		input:        input,
+		notification: notification,
+	}
+}
+
+func (c *RecoverCommand) Execute(args []string) error {
+	if len(args) > 0 {
+		if args[0] != "discard" {
+			return fmt.Errorf("unknown option %q. Usage: :recover [discard]", args[0])
+		}
+		if err := recovery.Discard(c.dir); err != nil {
+			c.notification.AddErrorMessage(err.Error())
+			return nil
+		}
+		c.notification.AddSystemMessage("Deleted the crash recovery snapshots.")
+		return nil
+	}
+
+	snapshot, _, err := recovery.Latest(c.dir)
+	if err != nil {
+		c.notification.AddErrorMessage(err.Error())
+		return nil
+	}
+	if snapshot == nil {
+		c.notification.AddSystemMessage("There is nothing to recover.")
+		return nil
+	}
+	c.restorer.RestoreTranscript(snapshot.Messages)
+	if snapshot.Input != "" {
+		c.input.SetText(snapshot.Input)
+	}
+	if err := recovery.Discard(c.dir); err != nil {
+		c.notification.AddErrorMessage(err.Error())
+	}
+	c.notification.AddSystemMessage(fmt.Sprintf("Restored %d messages saved at %s. The model does not remember them; send what you need again.",
+		len(snapshot.Messages), snapshot.SavedAt.Local().Format("Jan 2 15:04")))
+	return nil
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/recovery"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRestorer struct {
+	messages []types.Message
+	input    string
+}
+
+func (f *fakeRestorer) RestoreTranscript(messages []types.Message) {
+	f.messages = append(f.messages, messages...)
+}
+
+func (f *fakeRestorer) SetText(text string) {
+	f.input = text
+}
+
+func TestRecoverCommand_Execute(t *testing.T) {
+	dir := t.TempDir()
+	notification := &types.MockNotification{}
+	restorer := &fakeRestorer{}
+	cmd := NewRecoverCommand(dir, restorer, restorer, notification)
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, []string{"There is nothing to recover."}, notification.SystemMessages)
+
+	_, err := recovery.Save(dir, &recovery.Snapshot{
+		SavedAt:  time.Now(),
+		Reason:   "index out of range",
+		Input:    "and then refactor the parser",
+		Messages: []types.Message{{Role: "user", Content: "fix the tests"}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, []types.Message{{Role: "user", Content: "fix the tests"}}, restorer.messages)
+	assert.Equal(t, "and then refactor the parser", restorer.input)
+	assert.Contains(t, notification.SystemMessages[1], "Restored 1 messages")
+
+	snapshot, _, err := recovery.Latest(dir)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot, "a restored snapshot is not offered again")
+
+	assert.ErrorContains(t, cmd.Execute([]string{"all"}), "unknown option")
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/models"
+)
+
+// Retrier is the part of the chat controller that re-sends the last prompt
+type Retrier interface {
+	Retry(opts ...genie.ChatOption) error
+}
+
+type RetryCommand struct {
+	BaseCommand
+	chat         Retrier
+	notification types.Notification
+}
+
+func NewRetryCommand(chat Retrier, notification types.Notification) *RetryCommand {
+	return &RetryCommand{
+		BaseCommand: BaseCommand{
+			Name:        "retry",
+			Description: "Send your last message again, dropping the answer from the conversation",
+			Usage:       ":retry [--model name | provider/name] [--temp value]\n\nThe previous answer stays on screen but the model no longer sees it. --model and --temp apply to the retried turn only; use :model to switch for the rest of the session.",
+			Examples: []string{
+				":retry",
+				":retry --temp 0.2",
+				":retry --model gemini-2.5-pro",
+				":retry --model anthropic/claude-sonnet-4-5 --temp 0",
+			},
+			Aliases:  []string{"regenerate"},
+			Category: "Chat",
+		},
+		chat:         chat,
+		notification: notification,
+	}
+}
+
+// retryOverrides are the settings a retried turn runs with
+type retryOverrides struct {
+	provider    string
+	model       string
+	temperature *float32
+}
+
+func (c *RetryCommand) Execute(args []string) error {
+	overrides, err := parseRetryArgs(args)
+	if err != nil {
+		c.notification.AddErrorMessage(err.Error())
+		return nil
+	}
+
+	var opts []genie.ChatOption
+	var changes []string
+	if overrides.model != "" {
+		opts = append(opts, genie.WithModel(overrides.provider, overrides.model))
+		changes = append(changes, "model "+overrides.model)
+	}
+	if overrides.temperature != nil {
+		opts = append(opts, genie.WithTemperature(*overrides.temperature))
+		changes = append(changes, fmt.Sprintf("temperature %g", *overrides.temperature))
+	}
+
+	message := "Retried your last message"
+	if len(changes) > 0 {
+		message += " with " + strings.Join(changes, " and ")
+	}
+	if err := c.chat.Retry(opts...); err != nil {
+		c.notification.AddErrorMessage(err.Error())
+		return nil
+	}
+	c.notification.AddSystemMessage(message + "; the previous answer was dropped from the conversation.")
+	return nil
+}
+
+// parseRetryArgs reads --model and --temp, given as "--flag value" or
+// "--flag=value"
+func parseRetryArgs(args []string) (retryOverrides, error) {
+	var overrides retryOverrides
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		if !hasValue {
+			if i+1 >= len(args) {
+				return overrides, fmt.Errorf("%s needs a value. Usage: :retry [--model name] [--temp value]", flag)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch flag {
+		case "--model", "-m":
+			provider, model := models.Parse(value)
+			if model == "" {
+				return overrides, fmt.Errorf("invalid model %q. Use <name> or <provider>/<name>", value)
+			}
+			overrides.provider, overrides.model = provider, model
+		case "--temp", "--temperature", "-t":
+			temperature, err := strconv.ParseFloat(value, 32)
+			if err != nil || temperature < 0 || temperature > 2 {
+				return overrides, fmt.Errorf("invalid temperature %q: use a number between 0 and 2", value)
+			}
+			t := float32(temperature)
+			overrides.temperature = &t
+		default:
+			return overrides, fmt.Errorf("unknown option %q. Usage: :retry [--model name] [--temp value]", flag)
+		}
+	}
+	return overrides, nil
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRetrier struct {
+	calls [][]genie.ChatOption
+	err   error
+}
+
+func (f *fakeRetrier) Retry(opts ...genie.ChatOption) error {
+	f.calls = append(f.calls, opts)
+	return f.err
+}
+
+func TestParseRetryArgs(t *testing.T) {
+	overrides, err := parseRetryArgs([]string{"--model", "claude-sonnet-4-5", "--temp=0"})
+	require.NoError(t, err)
+	assert.Equal(t, "anthropic", overrides.provider)
+	assert.Equal(t, "claude-sonnet-4-5", overrides.model)
+	require.NotNil(t, overrides.temperature)
+	assert.Equal(t, float32(0), *overrides.temperature)
+
+	overrides, err = parseRetryArgs([]string{"--model=ollama/llama3.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "ollama", overrides.provider)
+	assert.Nil(t, overrides.temperature)
+
+	_, err = parseRetryArgs([]string{"--temp", "3"})
+	assert.ErrorContains(t, err, "between 0 and 2")
+	_, err = parseRetryArgs([]string{"--model"})
+	assert.ErrorContains(t, err, "needs a value")
+	_, err = parseRetryArgs([]string{"--top-p", "0.5"})
+	assert.ErrorContains(t, err, "unknown option")
+}
+
+func TestRetryCommand_Execute(t *testing.T) {
+	notification := &types.MockNotification{}
+	retrier := &fakeRetrier{}
+	cmd := NewRetryCommand(retrier, notification)
+
+	require.NoError(t, cmd.Execute(nil))
+	require.Len(t, retrier.calls, 1)
+	assert.Empty(t, retrier.calls[0])
+	assert.Equal(t, "Retried your last message; the previous answer was dropped from the conversation.", notification.SystemMessages[0])
+
+	require.NoError(t, cmd.Execute([]string{"--model", "gemini-2.5-pro", "--temp", "0.2"}))
+	require.Len(t, retrier.calls, 2)
+	assert.Len(t, retrier.calls[1], 2)
+	assert.Contains(t, notification.SystemMessages[1], "with model gemini-2.5-pro and temperature 0.2")
+
+	require.NoError(t, cmd.Execute([]string{"--temp", "hot"}))
+	assert.Len(t, retrier.calls, 2, "invalid options retry nothing")
+
+	retrier.err = errors.New("nothing to retry: send a message first")
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, []string{
+		`invalid temperature "hot": use a number between 0 and 2`,
+		"nothing to retry: send a message first",
+	}, notification.ErrorMessages)
+}
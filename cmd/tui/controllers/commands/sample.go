@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// Sampler is the part of the chat controller that samples several answers
+// per turn and keeps the one picked
+type Sampler interface {
+	SetSampleCount(n int) error
+	SampleCount() int
+	PickCandidate(n int) error
+}
+
+type SampleCommand struct {
+	BaseCommand
+	sampler      Sampler
+	notification types.Notification
+}
+
+func NewSampleCommand(sampler Sampler, notification types.Notification) *SampleCommand {
+	return &SampleCommand{
+		BaseCommand: BaseCommand{
+			Name:        "sample",
+			Description: "Get several candidate answers per turn and keep the best one",
+			Usage:       ":sample [<n> | off | pick <n>]\n\nEach turn samples n answers in parallel and shows them all; :sample pick <n> keeps one as the answer the model remembers. Candidates only get tools that read, so parallel runs can't make conflicting changes.",
+			Examples: []string{
+				":sample",
+				":sample 3",
+				":sample pick 2",
+				":sample off",
+			},
+			Category: "Chat",
+		},
+		sampler:      sampler,
+		notification: notification,
+	}
+}
+
+func (c *SampleCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		if n := c.sampler.SampleCount(); n > 1 {
+			c.notification.AddSystemMessage(fmt.Sprintf("Sampling %d answers per turn. Use :sample off for a single answer.", n))
+		} else {
+			c.notification.AddSystemMessage("Sampling is off. Use :sample <n> to get n candidate answers per turn.")
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "off":
+		_ = c.sampler.SetSampleCount(1)
+		c.notification.AddSystemMessage("Sampling off: one answer per turn.")
+	case "pick":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: :sample pick <n>")
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid candidate %q: use its number", args[1])
+		}
+		if err := c.sampler.PickCandidate(n); err != nil {
+			c.notification.AddErrorMessage(err.Error())
+			return nil
+		}
+		c.notification.AddSystemMessage(fmt.Sprintf("Kept candidate %d as the answer.", n))
+	default:
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("unknown subcommand %q. Usage: :sample [<n> | off | pick <n>]", args[0])
+		}
+		if err := c.sampler.SetSampleCount(n); err != nil {
+			c.notification.AddErrorMessage(err.Error())
+			return nil
+		}
+		if n == 1 {
+			c.notification.AddSystemMessage("Sampling off: one answer per turn.")
+			return nil
+		}
+		c.notification.AddSystemMessage(fmt.Sprintf("Sampling %d answers per turn; keep one with :sample pick <n>.", n))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSampler struct {
+	count  int
+	picked []int
+}
+
+func (f *fakeSampler) SetSampleCount(n int) error {
+	if n < 1 || n > 5 {
+		return errors.New("sample between 1 and 5 answers")
+	}
+	f.count = n
+	return nil
+}
+
+func (f *fakeSampler) SampleCount() int { return max(f.count, 1) }
+
+func (f *fakeSampler) PickCandidate(n int) error {
+	f.picked = append(f.picked, n)
+	return nil
+}
+
+func TestSampleCommand(t *testing.T) {
+	notification := &types.MockNotification{}
+	sampler := &fakeSampler{}
+	cmd := NewSampleCommand(sampler, notification)
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Contains(t, notification.SystemMessages[0], "Sampling is off")
+
+	require.NoError(t, cmd.Execute([]string{"3"}))
+	assert.Equal(t, 3, sampler.count)
+	require.NoError(t, cmd.Execute(nil))
+	assert.Contains(t, notification.SystemMessages[2], "Sampling 3 answers per turn")
+
+	require.NoError(t, cmd.Execute([]string{"pick", "2"}))
+	assert.Equal(t, []int{2}, sampler.picked)
+
+	require.NoError(t, cmd.Execute([]string{"9"}))
+	assert.Equal(t, []string{"sample between 1 and 5 answers"}, notification.ErrorMessages)
+
+	require.NoError(t, cmd.Execute([]string{"off"}))
+	assert.Equal(t, 1, sampler.count)
+
+	assert.Error(t, cmd.Execute([]string{"pick"}))
+	assert.Error(t, cmd.Execute([]string{"often"}))
+}
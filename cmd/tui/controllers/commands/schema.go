@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/kcaldas/genie/cmd/tui/controllers"
+)
+
+type SchemaCommand struct {
+	BaseCommand
+	controller *controllers.ChatController
+}
+
+func NewSchemaCommand(controller *controllers.ChatController) *SchemaCommand {
+	return &SchemaCommand{
+		BaseCommand: BaseCommand{
+			Name:        "schema",
+			Description: "Require answers to be JSON matching a schema file",
+			Usage:       ":schema [set <path> | clear]\n\nWhile a schema is set, answers are validated against it and the model is asked to repair answers that don't match.",
+			Examples: []string{
+				":schema",
+				":schema set release.json",
+				":schema clear",
+			},
+			Category: "Chat",
+		},
+		controller: controller,
+	}
+}
+
+func (c *SchemaCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		if _, path := c.controller.ResponseSchema(); path != "" {
+			c.controller.AddSystemMessage(fmt.Sprintf("Answers must match the schema in %s. Use :schema clear for free-form answers.", path))
+		} else {
+			c.controller.AddSystemMessage("No response schema set. Use :schema set <path> to require JSON answers.")
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "set":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: :schema set <path>")
+		}
+		if err := c.controller.SetResponseSchema(args[1]); err != nil {
+			c.controller.AddErrorMessage(err.Error())
+			return nil
+		}
+		c.controller.AddSystemMessage(fmt.Sprintf("Answers must now match the schema in %s.", args[1]))
+	case "clear":
+		c.controller.ClearResponseSchema()
+		c.controller.AddSystemMessage("Response schema cleared.")
+	default:
+		return fmt.Errorf("unknown subcommand %q. Usage: :schema [set <path> | clear]", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+type ScopeCommand struct {
+	BaseCommand
+	genieService genie.Genie
+	notification types.Notification
+}
+
+func NewScopeCommand(genieService genie.Genie, notification types.Notification) *ScopeCommand {
+	return &ScopeCommand{
+		BaseCommand: BaseCommand{
+			Name:        "scope",
+			Description: "Confine file tools and searches to part of a monorepo",
+			Usage:       ":scope [path...] | :scope clear\n\nFrom the next message on, file tools only read and change paths inside the given directories of the working directory, searches without a path cover only them, and their GENIE.md joins the project context. Directories above the scope can still be listed, to find the way in. bash is not confined. :scope clear works on the whole workspace again, as does :cd. Without arguments, :scope shows the current scope.",
+			Examples: []string{
+				":scope",
+				":scope services/payments/...",
+				":scope services/payments libs/money",
+				":scope clear",
+			},
+			Category: "Chat",
+		},
+		genieService: genieService,
+		notification: notification,
+	}
+}
+
+func (c *ScopeCommand) Execute(args []string) error {
+	session, err := c.genieService.GetSession()
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if len(args) == 0 {
+		if scope := session.GetScope(); len(scope) > 0 {
+			c.notification.AddSystemMessage("Scope: " + strings.Join(scope, ", "))
+		} else {
+			c.notification.AddSystemMessage("No scope: file tools work on the whole workspace.")
+		}
+		return nil
+	}
+
+	if len(args) == 1 && (args[0] == "clear" || args[0] == "off") {
+		session.SetScope(nil)
+		c.notification.AddSystemMessage("Scope cleared: file tools work on the whole workspace.")
+		return nil
+	}
+
+	scope, err := genie.ResolveScope(session.GetWorkingDirectory(), args)
+	if err != nil {
+		return err
+	}
+	session.SetScope(scope)
+	if len(scope) == 0 {
+		c.notification.AddSystemMessage("Scope cleared: file tools work on the whole workspace.")
+		return nil
+	}
+	c.notification.AddSystemMessage(fmt.Sprintf("Scope: %s. File tools and searches stay inside it from the next message on.", strings.Join(scope, ", ")))
+	return nil
+}
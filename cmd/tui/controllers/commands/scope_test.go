@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeCommand(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "services", "payments"), 0755))
+	session := &mockSession{workingDir: root}
+	notification := &types.MockNotification{}
+	cmd := NewScopeCommand(&MockGenieService{mockSession: session}, notification)
+
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, "No scope: file tools work on the whole workspace.", notification.SystemMessages[0])
+
+	require.NoError(t, cmd.Execute([]string{"services/payments/..."}))
+	assert.Equal(t, []string{"services/payments"}, session.GetScope())
+	assert.Contains(t, notification.SystemMessages[1], "Scope: services/payments.")
+
+	assert.ErrorContains(t, cmd.Execute([]string{"services/search"}), "does not exist")
+	assert.Equal(t, []string{"services/payments"}, session.GetScope(), "a bad path keeps the scope")
+
+	require.NoError(t, cmd.Execute([]string{"clear"}))
+	assert.Empty(t, session.GetScope())
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/metrics"
+)
+
+// statsRecentTurns is how many of the latest turns :stats lists.
+const statsRecentTurns = 5
+
+type StatsCommand struct {
+	BaseCommand
+	collector    *metrics.Collector
+	notification types.Notification
+}
+
+func NewStatsCommand(collector *metrics.Collector, notification types.Notification) *StatsCommand {
+	return &StatsCommand{
+		BaseCommand: BaseCommand{
+			Name:        "stats",
+			Description: "Show latency metrics: time to first token, total latency, tool vs model time, retries",
+			Usage:       ":stats",
+			Examples: []string{
+				":stats",
+			},
+			Aliases:  []string{"perf"},
+			Category: "System",
+		},
+		collector:    collector,
+		notification: notification,
+	}
+}
+
+func (c *StatsCommand) Execute(args []string) error {
+	c.notification.AddSystemMessage(formatStats(c.collector.Summary(), c.collector.Turns()))
+	return nil
+}
+
+func formatStats(summary metrics.Summary, turns []metrics.Turn) string {
+	if summary.Turns == 0 {
+		return "No completed turns yet. Stats appear after the first response."
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Performance (last %d turns)\n", summary.Turns)
+	fmt.Fprintf(&sb, "  Latency:     avg %s | p50 %s | p95 %s\n",
+		formatStatDuration(summary.AvgLatency), formatStatDuration(summary.P50Latency), formatStatDuration(summary.P95Latency))
+	fmt.Fprintf(&sb, "  First token: avg %s\n", formatStatDuration(summary.AvgTimeToFirstToken))
+	fmt.Fprintf(&sb, "  Time split:  model %s | tools %s\n", formatStatDuration(summary.ModelTime), formatStatDuration(summary.ToolTime))
+	fmt.Fprintf(&sb, "  Tool calls:  %d | retries: %d | failed turns: %d\n", summary.ToolCalls, summary.Retries, summary.FailedTurns)
+
+	sb.WriteString("\nRecent turns\n")
+	first := max(len(turns)-statsRecentTurns, 0)
+	for i := len(turns) - 1; i >= first; i-- {
+		turn := turns[i]
+		status := ""
+		if turn.Failed {
+			status = "  failed"
+		}
+		fmt.Fprintf(&sb, "  %s  total %s | first token %s | model %s | tools %s (%d calls) | retries %d%s\n",
+			turn.Started.Format("15:04:05"),
+			formatStatDuration(turn.Total),
+			formatStatDuration(turn.TimeToFirstToken),
+			formatStatDuration(turn.ModelTime),
+			formatStatDuration(turn.ToolTime),
+			turn.ToolCalls,
+			turn.Retries,
+			status)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatStatDuration shows milliseconds below a second and tenths above;
+// zero reads as "-" since it means the figure wasn't measured.
+func formatStatDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return d.Round(100 * time.Millisecond).String()
+	}
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatStats(t *testing.T) {
+	assert.Contains(t, formatStats(metrics.Summary{}, nil), "No completed turns yet")
+
+	started := time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC)
+	turns := []metrics.Turn{
+		{Started: started, Total: 2 * time.Second, ModelTime: 2 * time.Second},
+		{Started: started.Add(time.Minute), Total: 4250 * time.Millisecond, TimeToFirstToken: 640 * time.Millisecond,
+			ToolTime: time.Second, ModelTime: 3250 * time.Millisecond, ToolCalls: 2, Retries: 1, Failed: true},
+	}
+	summary := metrics.Summary{Turns: 2, FailedTurns: 1, ToolCalls: 2, Retries: 1,
+		AvgLatency: 3125 * time.Millisecond, P50Latency: 2 * time.Second, P95Latency: 4250 * time.Millisecond,
+		AvgTimeToFirstToken: 640 * time.Millisecond, ToolTime: time.Second, ModelTime: 5250 * time.Millisecond}
+
+	text := formatStats(summary, turns)
+
+	assert.Contains(t, text, "Latency:     avg 3.1s | p50 2s | p95 4.3s")
+	assert.Contains(t, text, "First token: avg 640ms")
+	assert.Contains(t, text, "Tool calls:  2 | retries: 1 | failed turns: 1")
+	assert.Contains(t, text, "09:31:00  total 4.3s | first token 640ms | model 3.3s | tools 1s (2 calls) | retries 1  failed")
+	assert.Contains(t, text, "09:30:00  total 2s | first token - |", "unmeasured figures show as -")
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// Summarizer is the part of the chat controller that asks for a summary
+// of the session
+type Summarizer interface {
+	Summarize(save bool) error
+}
+
+type SummarizeCommand struct {
+	BaseCommand
+	summarizer   Summarizer
+	notification types.Notification
+}
+
+func NewSummarizeCommand(summarizer Summarizer, notification types.Notification) *SummarizeCommand {
+	return &SummarizeCommand{
+		BaseCommand: BaseCommand{
+			Name:        "summarize",
+			Description: "Summarize the session: decisions, changes made and open questions",
+			Usage:       ":summarize [--save]\n\nThe model writes a structured summary of the conversation so far into the transcript; the request and summary are not added to what it remembers. --save also appends the summary to .genie/notes/<date>.md to share or keep.",
+			Examples: []string{
+				":summarize",
+				":summarize --save",
+			},
+			Aliases:  []string{"summary"},
+			Category: "Chat",
+		},
+		summarizer:   summarizer,
+		notification: notification,
+	}
+}
+
+func (c *SummarizeCommand) Execute(args []string) error {
+	save := false
+	for _, arg := range args {
+		switch arg {
+		case "--save", "-s", "save":
+			save = true
+		default:
+			return fmt.Errorf("unknown option %q. Usage: :summarize [--save]", arg)
+		}
+	}
+	if err := c.summarizer.Summarize(save); err != nil {
+		c.notification.AddErrorMessage(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSummarizer struct {
+	calls []bool
+	err   error
+}
+
+func (f *fakeSummarizer) Summarize(save bool) error {
+	f.calls = append(f.calls, save)
+	return f.err
+}
+
+func TestSummarizeCommand_Execute(t *testing.T) {
+	notification := &types.MockNotification{}
+	summarizer := &fakeSummarizer{}
+	cmd := NewSummarizeCommand(summarizer, notification)
+
+	require.NoError(t, cmd.Execute(nil))
+	require.NoError(t, cmd.Execute([]string{"--save"}))
+	assert.Equal(t, []bool{false, true}, summarizer.calls)
+
+	assert.ErrorContains(t, cmd.Execute([]string{"--all"}), "unknown option")
+	assert.Len(t, summarizer.calls, 2)
+
+	summarizer.err = errors.New("nothing to summarize yet: send a message first")
+	require.NoError(t, cmd.Execute(nil))
+	assert.Equal(t, []string{"nothing to summarize yet: send a message first"}, notification.ErrorMessages)
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"slices"
 	"strings"
 
@@ -16,16 +17,21 @@ type ThemeCommand struct {
 	configManager   *helpers.ConfigManager
 	commandEventBus *events.CommandEventBus
 	notification    *controllers.ChatController
+	editor          *controllers.ThemeEditorController
 }
 
-func NewThemeCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, notification *controllers.ChatController) *ThemeCommand {
+func NewThemeCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, notification *controllers.ChatController, editor *controllers.ThemeEditorController) *ThemeCommand {
 	return &ThemeCommand{
 		BaseCommand: BaseCommand{
 			Name:        "theme",
 			Description: "Change the color theme or list available themes",
-			Usage:       ":theme [theme_name]",
+			Usage:       ":theme [theme_name|preview [theme_name]|edit [theme_name]]",
 			Examples: []string{
 				":theme",
+				":theme preview",
+				":theme preview monokai",
+				":theme edit",
+				":theme edit ocean",
 				":theme dark",
 				":theme light",
 				":theme monokai",
@@ -36,6 +42,7 @@ func NewThemeCommand(configManager *helpers.ConfigManager, commandEventBus *even
 		configManager:   configManager,
 		commandEventBus: commandEventBus,
 		notification:    notification,
+		editor:          editor,
 	}
 }
 
@@ -47,16 +54,31 @@ func (c *ThemeCommand) Execute(args []string) error {
 		outputMode := config.OutputMode
 		glamourStyle := presentation.GetGlamourStyleForTheme(currentTheme)
 
-		content := fmt.Sprintf("Available themes: %s\n\nCurrent theme: %s\nOutput mode: %s\nMarkdown style: %s\n\nUsage: :theme <name>",
+		content := fmt.Sprintf("Available themes: %s\n\nCurrent theme: %s\nOutput mode: %s (%s)\nMarkdown style: %s\n\nUsage: :theme <name>, :theme preview [name], :theme edit [name]",
 			strings.Join(themes, ", "),
 			currentTheme,
 			outputMode,
+			presentation.ActiveColorProfile(),
 			glamourStyle)
 
 		c.notification.AddSystemMessage(content)
 		return nil
 	}
 
+	switch args[0] {
+	case "preview":
+		return c.preview(args[1:])
+	case "edit":
+		name := ""
+		if len(args) > 1 {
+			name = args[1]
+		}
+		if err := c.editor.Open(name); err != nil {
+			c.notification.AddErrorMessage(err.Error())
+		}
+		return nil
+	}
+
 	themeName := args[0]
 
 	// Validate theme exists by checking against available theme names
@@ -91,3 +113,32 @@ func (c *ThemeCommand) Execute(args []string) error {
 	// Final UI refresh to show the theme change message
 	return nil
 }
+
+// preview shows each theme color as this terminal renders it, with the
+// palette entry it degrades to when the terminal lacks true color.
+func (c *ThemeCommand) preview(args []string) error {
+	themeName := c.configManager.GetConfig().Theme
+	if len(args) > 0 {
+		themeName = args[0]
+		if !slices.Contains(presentation.GetThemeNames(), themeName) {
+			c.notification.AddErrorMessage(fmt.Sprintf("Unknown theme: %s. Available themes: %s", themeName, strings.Join(presentation.GetThemeNames(), ", ")))
+			return nil
+		}
+	}
+	theme := presentation.GetTheme(themeName)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Theme %s in %s (TERM=%s, COLORTERM=%s)\n\n",
+		themeName, presentation.ActiveColorProfile(), os.Getenv("TERM"), os.Getenv("COLORTERM"))
+	for _, role := range presentation.ThemeRoles {
+		color := theme.Color(role)
+		swatch := presentation.ConvertColorToAnsi(color) + "██████" + "\033[0m"
+		line := fmt.Sprintf("%s  %-14s %s", swatch, role, color)
+		if index := presentation.PaletteIndex(color); index >= 0 {
+			line += fmt.Sprintf(" → palette %d", index)
+		}
+		b.WriteString(line + "\n")
+	}
+	c.notification.AddSystemMessage(strings.TrimSuffix(b.String(), "\n"))
+	return nil
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kcaldas/genie/cmd/tui/controllers"
+)
+
+type ThoughtsCommand struct {
+	BaseCommand
+	controller *controllers.ChatController
+}
+
+func NewThoughtsCommand(controller *controllers.ChatController) *ThoughtsCommand {
+	return &ThoughtsCommand{
+		BaseCommand: BaseCommand{
+			Name:        "thoughts",
+			Description: "Expand or collapse the model's reasoning (1 = most recent). Turn it on with :config set show_thoughts on",
+			Usage:       ":thoughts [n]",
+			Examples: []string{
+				":thoughts",
+				":thoughts 2",
+			},
+			Aliases:  []string{"think"},
+			Category: "Chat",
+		},
+		controller: controller,
+	}
+}
+
+func (c *ThoughtsCommand) Execute(args []string) error {
+	index := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid thought number '%s'. Use a positive number, e.g. :thoughts 2", args[0])
+		}
+		index = n
+	}
+
+	if _, err := c.controller.ToggleThought(index); err != nil {
+		c.controller.AddErrorMessage(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/kcaldas/genie/cmd/tui/controllers"
+)
+
+type TodosCommand struct {
+	BaseCommand
+	controller *controllers.TodoController
+	chat       *controllers.ChatController
+}
+
+func NewTodosCommand(controller *controllers.TodoController, chat *controllers.ChatController) *TodosCommand {
+	return &TodosCommand{
+		BaseCommand: BaseCommand{
+			Name:        "todos",
+			Description: "Show or hide the todo list panel",
+			Usage:       ":todos",
+			Examples: []string{
+				":todos",
+			},
+			Aliases:  []string{"todo"},
+			Category: "Chat",
+		},
+		controller: controller,
+		chat:       chat,
+	}
+}
+
+func (c *TodosCommand) Execute(args []string) error {
+	if c.controller.TogglePanel() && len(c.controller.GetTodos()) == 0 {
+		c.chat.AddSystemMessage("No todos yet. The panel updates as soon as the assistant writes a todo list.")
+	}
+	return nil
+}
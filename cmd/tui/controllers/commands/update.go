@@ -35,16 +35,16 @@ func (c *UpdateCommand) GetDescription() string {
 
 // GetUsage returns the command usage
 func (c *UpdateCommand) GetUsage() string {
-	return "/update [check|now|version <version>]"
+	return ":update [check|now|version <version>]"
 }
 
 // GetExamples returns command examples
 func (c *UpdateCommand) GetExamples() []string {
 	return []string{
-		"/update check - Check for available updates",
-		"/update now - Update to latest version",
-		"/update version v1.2.3 - Update to specific version",
-		"/update force - Force reinstall current version",
+		":update check - Check for available updates",
+		":update now - Update to latest version",
+		":update version v1.2.3 - Update to specific version",
+		":update force - Force reinstall current version",
 	}
 }
 
@@ -82,7 +82,7 @@ func (c *UpdateCommand) Execute(args []string) error {
 		return c.performUpdate(false, "")
 	case "version":
 		if len(args) < 2 {
-			c.notification.AddSystemMessage("❌ Please specify a version: /update version v1.2.3")
+			c.notification.AddSystemMessage("❌ Please specify a version: :update version v1.2.3")
 			return nil
 		}
 		return c.performUpdate(false, args[1])
@@ -95,10 +95,10 @@ func (c *UpdateCommand) Execute(args []string) error {
 
 func (c *UpdateCommand) showUpdateHelp() error {
 	help := `Update Commands:
-/update check          - Check for available updates
-/update now           - Update to latest version
-/update version <ver> - Update to specific version (e.g., v1.2.3)
-/update force         - Force reinstall current version
+:update check          - Check for available updates
+:update now            - Update to latest version
+:update version <ver>  - Update to specific version (e.g., v1.2.3)
+:update force          - Force reinstall current version
 
 Current version: ` + version.GetVersion()
 
@@ -125,7 +125,7 @@ func (c *UpdateCommand) checkForUpdates() error {
 	}
 
 	if updateInfo.UpdateNeeded {
-		msg := fmt.Sprintf("🎉 Update available!\nCurrent: %s → Latest: %s\n\nUse '/update now' to update.",
+		msg := fmt.Sprintf("🎉 Update available!\nCurrent: %s → Latest: %s\n\nUse ':update now' to update.",
 			updateInfo.CurrentVersion, updateInfo.LatestVersion)
 
 		if updateInfo.ReleaseNotes != "" {
@@ -173,7 +173,7 @@ func (c *UpdateCommand) performUpdate(force bool, targetVersion string) error {
 		}
 
 		if !updateInfo.UpdateNeeded {
-			c.notification.AddSystemMessage(fmt.Sprintf("✅ Already using latest version (%s). Use '/update force' to reinstall.", updateInfo.LatestVersion))
+			c.notification.AddSystemMessage(fmt.Sprintf("✅ Already using latest version (%s). Use ':update force' to reinstall.", updateInfo.LatestVersion))
 			return nil
 		}
 
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/controllers"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+)
+
+type UsageCommand struct {
+	BaseCommand
+	controller *controllers.ChatController
+}
+
+func NewUsageCommand(controller *controllers.ChatController) *UsageCommand {
+	return &UsageCommand{
+		BaseCommand: BaseCommand{
+			Name:        "usage",
+			Description: "Show token usage and estimated cost for the last turn and the session",
+			Usage:       ":usage",
+			Examples: []string{
+				":usage",
+			},
+			Aliases:  []string{"cost"},
+			Category: "System",
+		},
+		controller: controller,
+	}
+}
+
+func (c *UsageCommand) Execute(args []string) error {
+	turn, session := c.controller.GetUsage()
+
+	var sb strings.Builder
+	sb.WriteString("Usage\n")
+	sb.WriteString(formatUsageLine("Last turn", turn))
+	sb.WriteString("\n")
+	sb.WriteString(formatUsageLine("Session", session))
+
+	if len(session.UnpricedModels) > 0 {
+		sb.WriteString(fmt.Sprintf("\nNo pricing for: %s (set GENIE_PRICING_FILE to add rates)", strings.Join(session.UnpricedModels, ", ")))
+	}
+
+	c.controller.AddSystemMessage(sb.String())
+	return nil
+}
+
+func formatUsageLine(label string, usage pricing.Usage) string {
+	line := fmt.Sprintf("  %-10s %s | in: %d | out: %d | cached: %d (%.0f%% hit)",
+		label+":", pricing.FormatCost(usage.Cost), usage.InputTokens, usage.OutputTokens, usage.CachedTokens, usage.CacheHitRatio()*100)
+	if usage.CacheWriteTokens > 0 {
+		line += fmt.Sprintf(" | cache writes: %d", usage.CacheWriteTokens)
+	}
+	return line
+}
@@ -2,10 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/kcaldas/genie/cmd/tui/controllers"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
 	"github.com/kcaldas/genie/cmd/tui/state"
 	"github.com/kcaldas/genie/cmd/tui/types"
 )
@@ -21,8 +23,8 @@ func NewYankCommand(chatState *state.ChatState, clipboardHelper *helpers.Clipboa
 	return &YankCommand{
 		BaseCommand: BaseCommand{
 			Name:        "yank",
-			Description: "Copy messages to clipboard (vim-style)",
-			Usage:       ":y[count][direction] | :y-[count]",
+			Description: "Copy messages, code blocks, diffs or tool results to clipboard (vim-style)",
+			Usage:       ":y[count][direction] | :y-[count] | :yc[block] | :yank diff | :yank tool [n] | :yank session",
 			Examples: []string{
 				":y",
 				":y3",
@@ -30,6 +32,12 @@ func NewYankCommand(chatState *state.ChatState, clipboardHelper *helpers.Clipboa
 				":y5j",
 				":y-1",
 				":y-3",
+				":yc",
+				":yc2",
+				":yank diff",
+				":yank tool",
+				":yank tool 2",
+				":yank session",
 			},
 			Aliases:  []string{"y"},
 			Category: "Clipboard",
@@ -47,6 +55,22 @@ func (c *YankCommand) Execute(args []string) error {
 	count := 1
 	direction := "k" // default to up (k = previous messages)
 
+	if len(args) > 0 {
+		switch args[0] {
+		case "diff":
+			return c.yankDiff()
+		case "tool":
+			return c.yankToolResult(args[1:])
+		case "session":
+			return c.yankSession()
+		}
+	}
+
+	// :yc[N] copies code block N as labelled in the messages view
+	if len(args) > 0 && strings.HasPrefix(args[0], "c") {
+		return c.yankCodeBlock(strings.TrimPrefix(args[0], "c"))
+	}
+
 	if len(args) > 0 {
 		arg := args[0]
 		// Parse count and direction from argument like "2k", "3j", "5"
@@ -98,23 +122,118 @@ func (c *YankCommand) Execute(args []string) error {
 		return nil
 	}
 
-	// Format messages for clipboard
+	c.copy(formatYankedMessages(messages), description)
+	return nil
+}
+
+// formatYankedMessages formats messages for the clipboard, each prefixed
+// with its time and role; answers that end a turn are followed by how long
+// it took
+func formatYankedMessages(messages []types.Message) string {
 	var content strings.Builder
 	for i, msg := range messages {
 		if i > 0 {
 			content.WriteString("\n---\n\n")
 		}
+		if !msg.Time.IsZero() {
+			fmt.Fprintf(&content, "[%s] ", msg.Time.Local().Format("15:04:05"))
+		}
 		fmt.Fprintf(&content, "[%s] %s", strings.ToUpper(msg.Role), msg.Content)
+		if summary := msg.TurnSummary(); summary != "" {
+			fmt.Fprintf(&content, "\n\n(%s)", summary)
+		}
 	}
+	return content.String()
+}
 
-	// Copy to clipboard
-	if err := c.clipboardHelper.Copy(content.String()); err != nil {
+// copy puts text on the clipboard and reports what was copied
+func (c *YankCommand) copy(text, description string) {
+	if err := c.clipboardHelper.Copy(text); err != nil {
 		c.notification.AddErrorMessage(fmt.Sprintf("Failed to copy to clipboard: %v", err))
+		return
+	}
+	c.notification.AddSystemMessage(fmt.Sprintf("Copied %s to clipboard.", description))
+}
+
+// yankDiff copies the diff of the most recent change Genie proposed
+func (c *YankCommand) yankDiff() error {
+	filePath, diff, ok := c.notification.LastDiff()
+	if !ok {
+		c.notification.AddSystemMessage("No diff has been proposed yet.")
 		return nil
 	}
+	description := "the last diff"
+	if filePath != "" {
+		description = "the diff for " + filePath
+	}
+	c.copy(diff, description)
+	return nil
+}
+
+// yankToolResult copies the full result of a tool call, counting back
+// from the most recent one
+func (c *YankCommand) yankToolResult(args []string) error {
+	n := 1
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("invalid tool result %q (use :yank tool <number>, 1 is the most recent)", args[0])
+		}
+		n = parsed
+	}
 
-	// Success message
-	c.notification.AddSystemMessage(fmt.Sprintf("Copied %s to clipboard.", description))
+	toolName, text, err := c.notification.ToolResult(n)
+	if err != nil {
+		c.notification.AddErrorMessage(err.Error())
+		return nil
+	}
+
+	description := fmt.Sprintf("the %s result", toolName)
+	if n > 1 {
+		description = fmt.Sprintf("the %s result (%d back)", toolName, n)
+	}
+	c.copy(text, description)
+	return nil
+}
+
+// yankSession copies the whole conversation as shown in the messages view
+func (c *YankCommand) yankSession() error {
+	messages := c.chatState.GetMessages()
+	if len(messages) == 0 {
+		c.notification.AddSystemMessage("No messages to copy.")
+		return nil
+	}
+	c.copy(formatYankedMessages(messages), fmt.Sprintf("the session (%d messages)", len(messages)))
+	return nil
+}
+
+// yankCodeBlock copies the code of a single block without its fences. An
+// empty index copies the most recent block.
+func (c *YankCommand) yankCodeBlock(index string) error {
+	blocks := presentation.CodeBlocks(c.chatState.GetMessages())
+	if len(blocks) == 0 {
+		c.notification.AddSystemMessage("No code blocks to copy.")
+		return nil
+	}
+
+	block := blocks[len(blocks)-1]
+	if index != "" {
+		n, err := strconv.Atoi(index)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid code block %q (use :yc<number>, e.g. :yc2)", index)
+		}
+		if n > len(blocks) {
+			return fmt.Errorf("no code block %d (there are %d)", n, len(blocks))
+		}
+		block = blocks[n-1]
+	}
+
+	if err := c.clipboardHelper.Copy(block.Code); err != nil {
+		c.notification.AddErrorMessage(fmt.Sprintf("Failed to copy to clipboard: %v", err))
+		return nil
+	}
+
+	c.notification.AddSystemMessage(fmt.Sprintf("Copied code block %d to clipboard.", block.Index))
 	return nil
 }
 
@@ -3,6 +3,9 @@ package commands
 import (
 	"testing"
 
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/state"
+	"github.com/kcaldas/genie/cmd/tui/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -248,3 +251,16 @@ func BenchmarkParseYankArgument(b *testing.B) {
 		cmd.parseYankArgument(arg)
 	}
 }
+
+func TestYankCommand_CodeBlock(t *testing.T) {
+	chatState := state.NewChatState(100)
+	chatState.AddMessage(types.Message{Role: "assistant", ContentType: "markdown", Content: "```go\nfmt.Println(1)\n```\n\n```sh\necho 2\n```"})
+
+	cmd := NewYankCommand(chatState, helpers.NewClipboard(nil), nil)
+
+	err := cmd.Execute([]string{"c3"})
+	assert.EqualError(t, err, "no code block 3 (there are 2)")
+
+	err = cmd.Execute([]string{"cx"})
+	assert.Error(t, err)
+}
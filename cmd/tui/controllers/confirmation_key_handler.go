@@ -24,3 +24,21 @@ func (c *ConfirmationKeyHandler) InterpretKey(key interface{}) (confirmed bool,
 		return false, false
 	}
 }
+
+// IsAlwaysAllowKey reports whether a key approves the call and the tool
+// for the rest of the session
+func (c *ConfirmationKeyHandler) IsAlwaysAllowKey(key interface{}) bool {
+	return key == '3'
+}
+
+// IsEditKey reports whether a key asks to edit the proposed command or
+// content before approving it
+func (c *ConfirmationKeyHandler) IsEditKey(key interface{}) bool {
+	return key == 'e' || key == 'E'
+}
+
+// IsDenyWithReasonKey reports whether a key declines with a reason that
+// is returned to the model
+func (c *ConfirmationKeyHandler) IsDenyWithReasonKey(key interface{}) bool {
+	return key == 'r' || key == 'R'
+}
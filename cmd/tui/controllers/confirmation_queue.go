@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"sync"
+
+	"github.com/kcaldas/genie/cmd/events"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// ConfirmationQueue puts confirmation requests on screen one at a time,
+// whichever controller received them, so requests from parallel tools and
+// sub-agents wait their turn instead of replacing each other. Every queued
+// request is answered: by the user, or with a denial when the queue is
+// cancelled.
+type ConfirmationQueue struct {
+	stateAccessor   types.IStateAccessor
+	commandEventBus *events.CommandEventBus
+
+	mu      sync.Mutex
+	active  *queuedConfirmation
+	waiting []*queuedConfirmation
+}
+
+type queuedConfirmation struct {
+	executionID string
+	show        func() error // puts the dialog on screen
+	deny        func()       // answers no and takes the dialog down if it is up
+}
+
+func NewConfirmationQueue(stateAccessor types.IStateAccessor, commandEventBus *events.CommandEventBus) *ConfirmationQueue {
+	q := &ConfirmationQueue{
+		stateAccessor:   stateAccessor,
+		commandEventBus: commandEventBus,
+	}
+
+	// Cancelling the request denies everything it was waiting on
+	commandEventBus.Subscribe("user.input.cancel", func(event interface{}) {
+		q.Cancel()
+	})
+
+	return q
+}
+
+// Enqueue shows a confirmation right away when none is on screen, or after
+// the ones ahead of it. It returns the request's place in the line, 0 when
+// shown at once.
+func (q *ConfirmationQueue) Enqueue(executionID string, show func() error, deny func()) (int, error) {
+	item := &queuedConfirmation{executionID: executionID, show: show, deny: deny}
+
+	q.mu.Lock()
+	if q.active != nil {
+		q.waiting = append(q.waiting, item)
+		position := len(q.waiting)
+		q.mu.Unlock()
+		q.notify()
+		return position, nil
+	}
+	q.active = item
+	q.mu.Unlock()
+
+	q.notify()
+	return 0, show()
+}
+
+// Done records that the confirmation on screen was answered and shows the
+// next one. It reports whether another confirmation took its place.
+func (q *ConfirmationQueue) Done(executionID string) (bool, error) {
+	q.mu.Lock()
+	if q.active == nil || q.active.executionID != executionID {
+		q.mu.Unlock()
+		return false, nil
+	}
+	q.active = nil
+	if len(q.waiting) > 0 {
+		q.active = q.waiting[0]
+		q.waiting = q.waiting[1:]
+	}
+	next := q.active
+	q.mu.Unlock()
+
+	q.notify()
+	if next == nil {
+		return false, nil
+	}
+	return true, next.show()
+}
+
+// Cancel denies the confirmation on screen and every one waiting.
+func (q *ConfirmationQueue) Cancel() {
+	q.mu.Lock()
+	var pending []*queuedConfirmation
+	if q.active != nil {
+		pending = append(pending, q.active)
+	}
+	pending = append(pending, q.waiting...)
+	q.active = nil
+	q.waiting = nil
+	q.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	q.notify()
+	for _, item := range pending {
+		item.deny()
+	}
+}
+
+// Status returns how many confirmations wait behind the one on screen,
+// and whether one is on screen.
+func (q *ConfirmationQueue) Status() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiting), q.active != nil
+}
+
+// notify updates the waiting state and tells the status bar how many
+// confirmations are pending, for its badge
+func (q *ConfirmationQueue) notify() {
+	queued, active := q.Status()
+	pending := queued
+	if active {
+		pending++
+	}
+	q.stateAccessor.SetWaitingConfirmation(pending > 0)
+	q.commandEventBus.Emit("confirmation.changed", pending)
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/component"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tool and user confirmations share one queue, so a writeFile diff that
+// arrives while a bash command is waiting is shown after it, not over it.
+func TestConfirmationQueue_ToolAndUserConfirmationsTakeTurns(t *testing.T) {
+	tool, env := newToolConfirmationController(t)
+	user := NewUserConfirmationController(
+		env.gui,
+		env.stateAccessor,
+		env.layoutManager,
+		env.inputComponent,
+		component.NewDiffViewerComponent(env.gui, "Diff", env.configManager, env.commandEventBus),
+		component.NewTextViewerComponent(env.gui, "Text", env.configManager, env.commandEventBus),
+		env.configManager,
+		env.queue,
+		env.eventBus,
+		env.commandEventBus,
+	)
+	toolResponses := env.subscribeToolResponses()
+	userResponses := env.subscribeUserResponses()
+
+	require.NoError(t, tool.HandleToolConfirmationRequest(toolRequest("exec-bash", "bash")))
+	require.NoError(t, user.HandleUserConfirmationRequest(userRequest("exec-write")))
+	require.NoError(t, tool.HandleToolConfirmationRequest(toolRequest("exec-bash-2", "bash")))
+
+	require.NotNil(t, tool.ConfirmationComponent)
+	assert.Equal(t, "exec-bash", tool.ConfirmationComponent.ExecutionID)
+	assert.Nil(t, user.ConfirmationComponent, "the write waits its turn")
+	queued, active := env.queue.Status()
+	assert.Equal(t, 2, queued)
+	assert.True(t, active)
+
+	_, err := tool.HandleKeyPress('1')
+	require.NoError(t, err)
+	assert.Equal(t, "exec-bash", waitForToolResponse(t, toolResponses).ExecutionID)
+	assert.Nil(t, tool.ConfirmationComponent)
+	require.NotNil(t, user.ConfirmationComponent)
+	assert.Equal(t, "exec-write", user.ConfirmationComponent.ExecutionID)
+
+	_, err = user.HandleKeyPress('2')
+	require.NoError(t, err)
+	assert.Equal(t, "exec-write", waitForUserResponse(t, userResponses).ExecutionID)
+	require.NotNil(t, tool.ConfirmationComponent)
+	assert.Equal(t, "exec-bash-2", tool.ConfirmationComponent.ExecutionID)
+
+	_, err = tool.HandleKeyPress('1')
+	require.NoError(t, err)
+	assert.Equal(t, "exec-bash-2", waitForToolResponse(t, toolResponses).ExecutionID)
+	assert.False(t, env.stateAccessor.IsWaitingConfirmation())
+}
+
+func TestConfirmationQueue_ToolConfirmationsNoLongerReplaceEachOther(t *testing.T) {
+	controller, env := newToolConfirmationController(t)
+	responses := env.subscribeToolResponses()
+
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-a", "bash")))
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-b", "bash")))
+
+	assert.Equal(t, "exec-a", controller.ConfirmationComponent.ExecutionID)
+	messages := env.stateAccessor.GetMessages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Content, "Confirmation request queued (position 1)")
+
+	for _, id := range []string{"exec-a", "exec-b"} {
+		_, err := controller.HandleKeyPress('1')
+		require.NoError(t, err)
+		resp := waitForToolResponse(t, responses)
+		assert.Equal(t, id, resp.ExecutionID)
+		assert.True(t, resp.Confirmed)
+	}
+}
+
+func TestConfirmationQueue_CancelDeniesEveryPendingRequest(t *testing.T) {
+	controller, env := newToolConfirmationController(t)
+	responses := env.subscribeToolResponses()
+
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-a", "bash")))
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-b", "bash")))
+
+	env.commandEventBus.Emit("user.input.cancel", nil)
+	env.commandEventBus.WaitForPendingEvents()
+
+	denied := map[string]bool{}
+	for range 2 {
+		resp := waitForToolResponse(t, responses)
+		assert.False(t, resp.Confirmed)
+		denied[resp.ExecutionID] = true
+	}
+	assert.Equal(t, map[string]bool{"exec-a": true, "exec-b": true}, denied)
+	assert.Nil(t, controller.ConfirmationComponent)
+	assert.False(t, env.stateAccessor.IsWaitingConfirmation())
+}
+
+func TestConfirmationQueue_ReportsPendingCountToStatusBar(t *testing.T) {
+	controller, env := newToolConfirmationController(t)
+	counts := make(chan int, 8)
+	env.commandEventBus.Subscribe("confirmation.changed", func(e interface{}) {
+		if n, ok := e.(int); ok {
+			counts <- n
+		}
+	})
+
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-a", "bash")))
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-b", "bash")))
+	_, err := controller.HandleKeyPress('2')
+	require.NoError(t, err)
+	env.commandEventBus.WaitForPendingEvents()
+
+	close(counts)
+	var seen []int
+	for n := range counts {
+		seen = append(seen, n)
+	}
+	assert.ElementsMatch(t, []int{1, 2, 1}, seen)
+}
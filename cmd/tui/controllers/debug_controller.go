@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/tui/component"
@@ -12,6 +16,7 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/layout"
 	"github.com/kcaldas/genie/cmd/tui/state"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	core_events "github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie"
 	"github.com/kcaldas/genie/pkg/logging"
 )
@@ -24,10 +29,7 @@ type DebugController struct {
 	clipboard       *helpers.Clipboard
 	commandEventBus *events.CommandEventBus
 
-	// File-based debugging
-	debugFile      *os.File
-	debugLogger    logging.Logger
-	tailingEnabled bool
+	renderPending atomic.Bool
 }
 
 func NewDebugController(
@@ -67,15 +69,132 @@ func NewDebugController(
 		c.CopyDebugMessages()
 	})
 
+	c.subscribeToGenieEvents(genieService.GetEventBus())
+
 	return c
 }
 
+// inspectedTopics are the Genie events the inspector records. Streaming
+// chunks are left out: one turn produces hundreds of them.
+var inspectedTopics = []string{
+	core_events.ChatStartedEvent{}.Topic(),
+	core_events.ChatResponseEvent{}.Topic(),
+	core_events.ToolStartingEvent{}.Topic(),
+	core_events.ToolExecutedEvent{}.Topic(),
+	core_events.ToolCallMessageEvent{}.Topic(),
+	core_events.ToolConfirmationRequest{}.Topic(),
+	core_events.ToolConfirmationResponse{}.Topic(),
+	core_events.UserConfirmationRequest{}.Topic(),
+	core_events.UserConfirmationResponse{}.Topic(),
+	core_events.NotificationEvent{}.Topic(),
+	core_events.TokenCountEvent{}.Topic(),
+	core_events.ModelRetryEvent{}.Topic(),
+	core_events.AgentProgressEvent{}.Topic(),
+	core_events.SubAgentEvent{}.Topic(),
+	core_events.SkillInvokedEvent{}.Topic(),
+	core_events.SkillClearedEvent{}.Topic(),
+}
+
+// subscribeToGenieEvents records Genie's events for the inspector. The
+// handlers only append to state, so they are cheap enough to run inline
+// with PublishSync and keep every topic in publish order.
+func (c *DebugController) subscribeToGenieEvents(bus core_events.EventBus) {
+	for _, topic := range inspectedTopics {
+		topic := topic
+		bus.Subscribe(topic, func(e interface{}) {
+			if started, ok := e.(core_events.ChatStartedEvent); ok {
+				c.debugState.StartTurn(started.RequestID, started.Message)
+			}
+			c.debugState.AddEntry(describeDebugEvent(topic, e))
+			c.scheduleRender()
+		})
+	}
+}
+
+// scheduleRender re-renders the visible panel, coalescing bursts of events
+// into a single UI update.
+func (c *DebugController) scheduleRender() {
+	if !c.debugComponent.IsVisible() || !c.renderPending.CompareAndSwap(false, true) {
+		return
+	}
+	c.gui.PostUIUpdate(func() {
+		c.renderPending.Store(false)
+		c.debugComponent.Render()
+	})
+}
+
+// describeDebugEvent turns a Genie event into an inspector entry.
+func describeDebugEvent(topic string, e interface{}) state.DebugEntry {
+	entry := state.DebugEntry{Type: topic}
+	switch event := e.(type) {
+	case core_events.ChatStartedEvent:
+		entry.Summary = event.Message
+		entry.Details = map[string]any{"request_id": event.RequestID, "message": event.Message}
+	case core_events.ChatResponseEvent:
+		entry.Details = map[string]any{"request_id": event.RequestID, "response": event.Response}
+		if event.Error != nil {
+			entry.Failed = true
+			entry.Summary = "Error: " + event.Error.Error()
+			entry.Details["error"] = event.Error.Error()
+		} else {
+			entry.Summary = fmt.Sprintf("%d characters", len(event.Response))
+		}
+	case core_events.ToolStartingEvent:
+		entry.ToolName = event.ToolName
+		entry.Details = map[string]any{"execution_id": event.ExecutionID, "parameters": event.Parameters}
+	case core_events.ToolExecutedEvent:
+		entry.ToolName = event.ToolName
+		entry.Summary = event.Message
+		entry.Failed = !event.Success
+		entry.Details = map[string]any{
+			"execution_id": event.ExecutionID,
+			"parameters":   event.Parameters,
+			"result":       event.Result,
+		}
+	case core_events.ToolCallMessageEvent:
+		entry.ToolName = event.ToolName
+		entry.Summary = event.Message
+	case core_events.ToolConfirmationRequest:
+		entry.ToolName = event.ToolName
+		entry.Summary = event.Message
+		entry.Details = map[string]any{"execution_id": event.ExecutionID, "command": event.Command}
+	case core_events.NotificationEvent:
+		entry.Summary = event.Message
+		entry.Failed = event.Error != nil || event.Role == "error"
+	case core_events.TokenCountEvent:
+		entry.Summary = fmt.Sprintf("%s %s: %d in, %d out", event.Provider, event.Model, event.InputTokens, event.OutputTokens)
+		entry.Details = debugEventDetails(event)
+	case core_events.ModelRetryEvent:
+		entry.Summary = fmt.Sprintf("Attempt %d failed: %s", event.Attempt, event.Error)
+		entry.Failed = true
+	case core_events.SubAgentEvent:
+		entry.ToolName = event.ToolName
+		entry.Summary = strings.TrimSpace(event.Phase + " " + event.Message)
+		entry.Failed = event.Error != ""
+		entry.Details = debugEventDetails(event)
+	default:
+		entry.Details = debugEventDetails(event)
+	}
+	return entry
+}
+
+// debugEventDetails converts an event struct into a map for display.
+func debugEventDetails(event interface{}) map[string]any {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil
+	}
+	var details map[string]any
+	if err := json.Unmarshal(data, &details); err != nil {
+		return nil
+	}
+	return details
+}
+
 // AddDebugMessage adds a debug message and notifies component
 func (c *DebugController) AddDebugMessage(message string) {
 	c.debugState.AddDebugMessage(message)
-	c.gui.PostUIUpdate(func() {
-		c.debugComponent.OnMessageAdded()
-	})
+	c.scheduleRender()
 }
 
 // ClearDebugMessages clears all debug messages and triggers render
@@ -89,11 +208,39 @@ func (c *DebugController) GetDebugMessages() []string {
 	return c.debugState.GetDebugMessages()
 }
 
-// CopyDebugMessages copy all debug messages to clipboard
+// CopyDebugMessages copies the inspector's current view to the clipboard
 func (c *DebugController) CopyDebugMessages() {
-	messages := c.debugState.GetDebugMessages()
-	messagesStr := strings.Join(messages, "\n")
-	c.clipboard.Copy(messagesStr)
+	c.clipboard.Copy(c.currentView())
+}
+
+// SetFilter limits the inspector to matching event types or tool names
+func (c *DebugController) SetFilter(filter string) {
+	c.debugState.SetFilter(filter)
+	c.renderDebugComponent()
+}
+
+// GetFilter returns the inspector's current filter
+func (c *DebugController) GetFilter() string {
+	return c.debugState.GetFilter()
+}
+
+// ExportView writes the inspector's current view, honoring the filter and
+// expanded entries, to path and returns the path written. An empty path
+// picks a timestamped file in the temp directory.
+func (c *DebugController) ExportView(path string) (string, error) {
+	if path == "" {
+		path = filepath.Join(os.TempDir(), fmt.Sprintf("genie-events-%s.txt", time.Now().Format("20060102-150405")))
+	}
+	if err := os.WriteFile(path, []byte(c.currentView()), 0o644); err != nil {
+		return "", fmt.Errorf("failed to export debug view: %w", err)
+	}
+	return path, nil
+}
+
+// currentView renders the filtered entries as plain text
+func (c *DebugController) currentView() string {
+	lines := component.FormatDebugInspector(c.debugState.GetEntries(), c.debugState.GetTurns(), c.debugState.IsExpanded)
+	return component.DebugInspectorText(lines)
 }
 
 // Debug method removed - debug logging is now handled by the centralized logging system
@@ -145,35 +292,8 @@ func (c *DebugController) toggleDebugPanel() {
 		isVisible := debugPanel.IsVisible()
 		debugPanel.SetVisible(!isVisible)
 
-		// If becoming visible, show debug file content instead of in-memory messages
 		if !isVisible {
-			c.loadDebugFileContent()
 			debugPanel.Render()
 		}
 	}
 }
-
-// loadDebugFileContent reads the debug file and displays it in the debug component
-func (c *DebugController) loadDebugFileContent() {
-	// Get debug file path using the centralized helper
-	debugFile := logging.GetDebugFilePath("genie-debug.log")
-
-	// Read the file content
-	if content, err := os.ReadFile(debugFile); err == nil {
-		// Clear current debug messages and replace with file content
-		c.debugState.ClearDebugMessages()
-
-		// Split file content into lines and add as debug messages
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			if strings.TrimSpace(line) != "" {
-				c.debugState.AddDebugMessage(line)
-			}
-		}
-	} else {
-		// If file doesn't exist or can't be read, show a message
-		c.debugState.ClearDebugMessages()
-		c.debugState.AddDebugMessage(fmt.Sprintf("Debug file not found or unreadable: %s", debugFile))
-		c.debugState.AddDebugMessage("Use :debug command to enable debug logging")
-	}
-}
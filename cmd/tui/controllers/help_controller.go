@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/kcaldas/genie/cmd/help"
 	"github.com/kcaldas/genie/cmd/tui/component"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/layout"
@@ -19,6 +21,7 @@ type HelpRenderer interface {
 type HelpControllerInterface interface {
 	ShowHelp() error
 	ShowSlashCommandsHelp() error
+	ShowTopic(name string) error
 	ToggleHelp() error
 	IsVisible() bool
 }
@@ -102,6 +105,29 @@ func (c *HelpController) ShowSlashCommandsHelp() error {
 	return nil
 }
 
+// ShowTopic displays a help topic page in the text viewer panel
+func (c *HelpController) ShowTopic(name string) error {
+	topic, ok := help.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown help topic %q", name)
+	}
+
+	c.layoutManager.ShowRightPanel("text-viewer")
+	c.textViewerComponent.SetContentWithType(topic.Markdown, "markdown")
+	c.textViewerComponent.SetTitle("Help: " + topic.Title)
+
+	// Small delay to ensure proper rendering
+	time.Sleep(50 * time.Millisecond)
+
+	c.PostUIUpdate(func() {
+		c.PostUIUpdate(func() {
+			c.textViewerComponent.Render()
+		})
+	})
+
+	return nil
+}
+
 // ToggleHelp toggles help visibility
 func (c *HelpController) ToggleHelp() error {
 	// If right panel is visible and showing text-viewer, hide it
@@ -22,6 +22,8 @@ type LLMContextController struct {
 	contextComponent *component.LLMContextViewerComponent
 	commandEventBus  *events.CommandEventBus
 	contextData      map[string]string // Store context data in controller
+	contextUsage     *genie.ContextUsage
+	disabledParts    map[string]bool // Parts the session leaves out of the prompt
 }
 
 func NewLLMContextController(
@@ -126,6 +128,30 @@ func (c *LLMContextController) GetContextData() map[string]string {
 	return c.contextData
 }
 
+// GetContextUsage returns the token counts of the current context parts
+func (c *LLMContextController) GetContextUsage() *genie.ContextUsage {
+	return c.contextUsage
+}
+
+// IsContextPartEnabled reports whether a part is sent with the following turns
+func (c *LLMContextController) IsContextPartEnabled(key string) bool {
+	return !c.disabledParts[key]
+}
+
+// ToggleContextPart includes or leaves out a part from the following turns
+// of the session
+func (c *LLMContextController) ToggleContextPart(key string) error {
+	if key == genie.InstructionsContextKey {
+		return fmt.Errorf("the persona's instructions are always sent")
+	}
+	session, err := c.genie.GetSession()
+	if err != nil {
+		return fmt.Errorf("failed to get current session: %w", err)
+	}
+	session.SetContextPartEnabled(key, !c.IsContextPartEnabled(key))
+	return c.RefreshContext()
+}
+
 // loadContextData fetches context from Genie service
 func (c *LLMContextController) loadContextData() error {
 	ctx := context.Background()
@@ -135,6 +161,20 @@ func (c *LLMContextController) loadContextData() error {
 	}
 
 	c.contextData = contextParts
+
+	c.disabledParts = make(map[string]bool)
+	if session, err := c.genie.GetSession(); err == nil {
+		for _, key := range session.GetDisabledContextParts() {
+			c.disabledParts[key] = true
+		}
+	}
+
+	// The viewer still works without counts, so a failure is only logged
+	usage, err := c.genie.GetContextUsage(ctx)
+	if err != nil {
+		c.logger().Debug(fmt.Sprintf("Failed to count context tokens: %v", err))
+	}
+	c.contextUsage = usage
 	return nil
 }
 
@@ -145,6 +185,12 @@ func (c *LLMContextController) HandleComponentEvent(eventName string, data inter
 		return c.RefreshContext()
 	case "close":
 		return c.Close()
+	case "toggle":
+		key, ok := data.(string)
+		if !ok {
+			return fmt.Errorf("toggle needs a context part key")
+		}
+		return c.ToggleContextPart(key)
 	default:
 		return fmt.Errorf("unknown event: %s", eventName)
 	}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/prompttemplates"
+	"github.com/kcaldas/genie/cmd/tui/component"
+	"github.com/kcaldas/genie/cmd/tui/layout"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/mitchellh/go-homedir"
+)
+
+// PromptController fills in prompt templates from .genie/prompts and puts
+// the result in the input for the user to review before sending. Variables
+// without a value are asked for one at a time on the input line.
+type PromptController struct {
+	gui             types.Gui
+	manager         *prompttemplates.Manager
+	input           *component.InputComponent
+	writeController *WriteController
+	layoutManager   *layout.LayoutManager
+	notification    types.Notification
+}
+
+func NewPromptController(
+	gui types.Gui,
+	manager *prompttemplates.Manager,
+	input *component.InputComponent,
+	writeController *WriteController,
+	layoutManager *layout.LayoutManager,
+	notification types.Notification,
+) *PromptController {
+	controller := &PromptController{
+		gui:             gui,
+		manager:         manager,
+		input:           input,
+		writeController: writeController,
+		layoutManager:   layoutManager,
+		notification:    notification,
+	}
+
+	// Discover templates on startup so the :prompt suggestions work right away
+	if err := controller.Discover(); err != nil {
+		notification.AddSystemMessage(fmt.Sprintf("Error discovering prompt templates: %v", err))
+	}
+
+	return controller
+}
+
+// Discover reloads the templates from the project and user prompt directories
+func (c *PromptController) Discover() error {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current working directory: %w", err)
+	}
+	return c.manager.DiscoverTemplates(projectRoot, homedir.Dir)
+}
+
+// GetTemplates returns the available templates sorted by name
+func (c *PromptController) GetTemplates() []prompttemplates.Template {
+	names := c.manager.GetTemplateNames()
+	templates := make([]prompttemplates.Template, 0, len(names))
+	for _, name := range names {
+		if template, ok := c.manager.GetTemplate(name); ok {
+			templates = append(templates, template)
+		}
+	}
+	return templates
+}
+
+// Insert renders the template matching name and places it in the input.
+// values pre-fills variables; the rest are asked for interactively.
+func (c *PromptController) Insert(name string, values map[string]string) error {
+	template, ok := c.manager.Resolve(name)
+	if !ok {
+		return fmt.Errorf("no prompt template matches %q (templates live in .genie/prompts)", name)
+	}
+
+	collected := make(map[string]string, len(template.Variables))
+	for key, value := range values {
+		collected[key] = value
+	}
+
+	c.gui.PostUIUpdate(func() {
+		c.collect(template, collected)
+	})
+	return nil
+}
+
+// collect asks for the next missing variable, or inserts the rendered
+// template once every variable has a value.
+func (c *PromptController) collect(template prompttemplates.Template, values map[string]string) {
+	for _, variable := range template.Variables {
+		if _, ok := values[variable]; ok {
+			continue
+		}
+		_ = c.layoutManager.FocusPanel("input")
+		c.input.AskValue(fmt.Sprintf("%s › %s (Esc to cancel)", template.Name, variable), func(value string, ok bool) {
+			if !ok {
+				c.notification.AddSystemMessage(fmt.Sprintf("Prompt %s cancelled.", template.Name))
+				return
+			}
+			values[variable] = value
+			c.collect(template, values)
+		})
+		return
+	}
+
+	rendered, err := template.Render(values)
+	if err != nil {
+		c.notification.AddSystemMessage(fmt.Sprintf("Error rendering prompt %s: %v", template.Name, err))
+		return
+	}
+
+	// The input line is single-line, so multi-line prompts open in the
+	// write component where they can be reviewed as written
+	if strings.Contains(rendered, "\n") {
+		if err := c.writeController.ShowWithContent(rendered); err != nil {
+			c.notification.AddSystemMessage(fmt.Sprintf("Error opening prompt %s: %v", template.Name, err))
+		}
+		return
+	}
+	_ = c.layoutManager.FocusPanel("input")
+	c.input.SetText(rendered)
+}
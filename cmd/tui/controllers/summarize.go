@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+// summaryPrompt asks for a summary someone who missed the session can act on
+const summaryPrompt = `Summarize our session so far for someone who was not part of it. Use these Markdown sections, leaving out any that would be empty:
+
+## Goal
+What we set out to do, in one or two sentences.
+
+## Decisions
+The decisions we made and why.
+
+## Changes
+The changes made to files, commands run with lasting effects, and anything created or deleted. Name files.
+
+## Open questions
+What is unresolved, unverified or left to do.
+
+Be concise and factual. Use only what happened in this conversation; do not call any tools.`
+
+// Summarize asks the model for a structured summary of the session so far,
+// shown in the transcript. The summary turn is left out of what the model
+// remembers. With save, the summary is also appended to
+// .genie/notes/<date>.md once it arrives.
+func (c *ChatController) Summarize(save bool) error {
+	if c.IsBusy() {
+		return fmt.Errorf("wait for the current response to finish, or cancel it, before summarizing")
+	}
+	turns, err := c.genie.GetChatHistory()
+	if err != nil {
+		return err
+	}
+	if len(turns) == 0 {
+		return fmt.Errorf("nothing to summarize yet: send a message first")
+	}
+
+	requestID := uuid.NewString()
+	if save {
+		session, err := c.genie.GetSession()
+		if err != nil {
+			return err
+		}
+		c.summaryMu.Lock()
+		if c.summaryNotes == nil {
+			c.summaryNotes = make(map[string]string)
+		}
+		c.summaryNotes[requestID] = summaryNotesPath(session.GetGenieHomeDirectory(), time.Now())
+		c.summaryMu.Unlock()
+	}
+
+	display := ":summarize"
+	if save {
+		display += " --save"
+	}
+	err = c.sendChat(display, summaryPrompt,
+		genie.WithRequestID(requestID),
+		genie.WithEphemeral(genie.EphemeralAll),
+		// A summary is one plain answer, whatever :sample and :schema say
+		genie.WithCandidates(1),
+		genie.WithResponseSchema(nil),
+		genie.WithStreaming(true),
+	)
+	if err != nil {
+		c.takeSummaryNotes(requestID)
+	}
+	c.renderMessages()
+	return nil
+}
+
+// summaryNotesPath returns the notes file of the day now falls on.
+func summaryNotesPath(genieHome string, now time.Time) string {
+	return filepath.Join(genieHome, ".genie", "notes", now.Format("2006-01-02")+".md")
+}
+
+// takeSummaryNotes returns and forgets the notes file waiting for the
+// answer to requestID, if any.
+func (c *ChatController) takeSummaryNotes(requestID string) (string, bool) {
+	c.summaryMu.Lock()
+	defer c.summaryMu.Unlock()
+	path, ok := c.summaryNotes[requestID]
+	delete(c.summaryNotes, requestID)
+	return path, ok
+}
+
+// saveSummary appends summary to the notes file at path, under a heading
+// with the time, so several summaries a day share the file.
+func (c *ChatController) saveSummary(path, summary string) {
+	if strings.TrimSpace(summary) == "" {
+		return
+	}
+	message := fmt.Sprintf("Saved the summary to %s", path)
+	if err := appendSummary(path, summary, time.Now()); err != nil {
+		message = fmt.Sprintf("Failed to save the summary: %v", err)
+	}
+	c.stateAccessor.AddMessage(types.Message{Role: "system", Content: message})
+}
+
+func appendSummary(path, summary string, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if info.Size() > 0 {
+		b.WriteString("\n---\n\n")
+	}
+	fmt.Fprintf(&b, "# Session summary, %s\n\n%s\n", now.Format("2006-01-02 15:04"), strings.TrimSpace(summary))
+	_, err = file.WriteString(b.String())
+	return err
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kcaldas/genie/cmd/events"
+	"github.com/kcaldas/genie/cmd/tui/component"
+	"github.com/kcaldas/genie/cmd/tui/helpers"
+	"github.com/kcaldas/genie/cmd/tui/layout"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// ThemeEditorController runs the theme editor panel. While a theme is
+// being edited its draft is registered under the name it will be saved
+// as and made the active theme, so the messages view previews every
+// change. Saving writes the draft to the themes directory; discarding
+// puts the previous theme back.
+type ThemeEditorController struct {
+	*BaseController
+	layoutManager   *layout.LayoutManager
+	editor          *component.ThemeEditorComponent
+	input           *component.InputComponent
+	commandEventBus *events.CommandEventBus
+	notification    types.Notification
+
+	// What to restore when the edit is discarded
+	originalTheme string
+	replaced      *presentation.Theme
+}
+
+func NewThemeEditorController(
+	gui types.Gui,
+	layoutManager *layout.LayoutManager,
+	editor *component.ThemeEditorComponent,
+	input *component.InputComponent,
+	configManager *helpers.ConfigManager,
+	commandEventBus *events.CommandEventBus,
+	notification types.Notification,
+) *ThemeEditorController {
+	c := &ThemeEditorController{
+		BaseController:  NewBaseController(editor, gui, configManager),
+		layoutManager:   layoutManager,
+		editor:          editor,
+		input:           input,
+		commandEventBus: commandEventBus,
+		notification:    notification,
+	}
+
+	commandEventBus.Subscribe("theme.editor.changed", func(interface{}) {
+		c.PostUIUpdate(c.preview)
+	})
+	commandEventBus.Subscribe("theme.editor.edit", func(interface{}) {
+		c.PostUIUpdate(c.askColor)
+	})
+	commandEventBus.Subscribe("theme.editor.save", func(interface{}) {
+		c.PostUIUpdate(c.save)
+	})
+	commandEventBus.Subscribe("theme.editor.cancel", func(interface{}) {
+		c.PostUIUpdate(c.discard)
+	})
+
+	return c
+}
+
+// Open starts editing the custom theme name, or a copy of the active theme
+// when name is empty. A custom theme that exists is edited in place; a new
+// one starts from the active theme. Built-in themes cannot be overwritten,
+// so editing one starts a copy named "<name>-custom".
+func (c *ThemeEditorController) Open(name string) error {
+	if c.IsEditing() {
+		return fmt.Errorf("already editing theme %s; save or discard it first", c.editingName())
+	}
+
+	current := c.GetConfig().Theme
+	base := current
+	switch {
+	case name == "" && presentation.IsBuiltinTheme(current):
+		name = "custom"
+	case name == "":
+		name = current
+	case presentation.IsBuiltinTheme(name):
+		base = name
+		name += "-custom"
+	case !presentation.IsValidThemeName(name):
+		return fmt.Errorf("invalid theme name %q: use letters, digits, - and _", name)
+	}
+
+	c.originalTheme = current
+	c.replaced = nil
+	if slices.Contains(presentation.GetThemeNames(), name) {
+		c.replaced = presentation.GetTheme(name)
+		base = name
+	}
+
+	c.editor.Edit(name, presentation.GetTheme(base).Clone())
+	c.layoutManager.ShowRightPanel("theme-editor")
+	c.preview()
+	c.PostUIUpdate(func() {
+		_ = c.layoutManager.FocusPanel(layout.PanelThemeEditor)
+		c.editor.Render()
+	})
+	return nil
+}
+
+// IsEditing reports whether the editor panel is open
+func (c *ThemeEditorController) IsEditing() bool {
+	return c.layoutManager.IsRightPanelVisible() && c.layoutManager.GetRightPanelMode() == "theme-editor"
+}
+
+func (c *ThemeEditorController) editingName() string {
+	name, _ := c.editor.Draft()
+	return name
+}
+
+// preview makes the draft the active theme without saving the config
+func (c *ThemeEditorController) preview() {
+	name, draft := c.editor.Draft()
+	if draft == nil {
+		return
+	}
+	presentation.RegisterTheme(name, draft.Clone())
+	c.applyTheme(name, false)
+}
+
+// applyTheme switches the active theme and lets components restyle
+func (c *ThemeEditorController) applyTheme(name string, save bool) {
+	oldTheme := c.GetConfig().Theme
+	_ = c.configManager.UpdateConfig(func(config *types.Config) {
+		config.Theme = name
+	}, save)
+	c.commandEventBus.Emit("theme.changed", map[string]interface{}{
+		"oldTheme": oldTheme,
+		"newTheme": name,
+		"config":   c.GetConfig(),
+	})
+}
+
+// askColor asks for a new value for the selected role on the input line
+func (c *ThemeEditorController) askColor() {
+	role := c.editor.SelectedRole()
+	_ = c.layoutManager.FocusPanel(layout.PanelInput)
+	c.input.AskValue(fmt.Sprintf("%s color #RRGGBB (Esc to cancel)", role), func(value string, ok bool) {
+		defer c.PostUIUpdate(func() {
+			_ = c.layoutManager.FocusPanel(layout.PanelThemeEditor)
+		})
+		if !ok || value == "" {
+			return
+		}
+		if err := c.editor.SetSelectedColor(value); err != nil {
+			c.notification.AddErrorMessage(err.Error())
+			return
+		}
+		c.preview()
+	})
+}
+
+func (c *ThemeEditorController) save() {
+	name, draft := c.editor.Draft()
+	if draft == nil {
+		return
+	}
+	path, err := presentation.SaveCustomTheme(c.configManager.ThemesDir(), name, draft)
+	if err != nil {
+		c.notification.AddErrorMessage(fmt.Sprintf("Failed to save theme %s: %v", name, err))
+		return
+	}
+	c.close()
+	c.applyTheme(name, true)
+	c.notification.AddSystemMessage(fmt.Sprintf("Theme %s saved to %s", name, path))
+}
+
+func (c *ThemeEditorController) discard() {
+	name, _ := c.editor.Draft()
+	if c.replaced != nil {
+		presentation.RegisterTheme(name, c.replaced)
+	} else {
+		presentation.UnregisterTheme(name)
+	}
+	c.close()
+	c.applyTheme(c.originalTheme, false)
+	c.notification.AddSystemMessage(fmt.Sprintf("Theme edits discarded; back to %s", c.originalTheme))
+}
+
+func (c *ThemeEditorController) close() {
+	c.layoutManager.HideRightPanel()
+	_ = c.layoutManager.FocusPanel(layout.PanelInput)
+}
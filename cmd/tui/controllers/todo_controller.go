@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"sync"
+
+	"github.com/kcaldas/genie/cmd/tui/component"
+	"github.com/kcaldas/genie/cmd/tui/layout"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	core_events "github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+// TodoController keeps the todo panel in sync with the model's todo list.
+// The panel opens the first time the list has items and stays closed once
+// the user collapses it.
+type TodoController struct {
+	gui           types.Gui
+	panel         *component.TodoPanelComponent
+	layoutManager *layout.LayoutManager
+
+	mu        sync.Mutex
+	collapsed bool
+}
+
+func NewTodoController(
+	genieService genie.Genie,
+	gui types.Gui,
+	panel *component.TodoPanelComponent,
+	layoutManager *layout.LayoutManager,
+) *TodoController {
+	c := &TodoController{
+		gui:           gui,
+		panel:         panel,
+		layoutManager: layoutManager,
+	}
+
+	core_events.SubscribeTo(genieService.GetEventBus(), func(event core_events.ToolExecutedEvent) {
+		if event.ToolName != "TodoWrite" || !event.Success {
+			return
+		}
+		todos, err := presentation.ParseTodoItems(event.Result["todos"])
+		if err != nil {
+			return
+		}
+		c.panel.SetTodos(todos)
+		c.gui.PostUIUpdate(func() {
+			c.refresh(len(todos) > 0)
+		})
+	})
+
+	return c
+}
+
+// TogglePanel shows or hides the todo panel and reports whether it is now visible
+func (c *TodoController) TogglePanel() bool {
+	panel := c.layoutManager.GetPanel(layout.PanelLeft)
+	if panel == nil {
+		return false
+	}
+
+	visible := !panel.IsVisible()
+	c.mu.Lock()
+	c.collapsed = !visible
+	c.mu.Unlock()
+
+	panel.SetVisible(visible)
+	if visible {
+		panel.Render()
+	}
+	return visible
+}
+
+// GetTodos returns the list currently shown in the panel
+func (c *TodoController) GetTodos() []presentation.TodoItem {
+	return c.panel.GetTodos()
+}
+
+func (c *TodoController) refresh(hasTodos bool) {
+	panel := c.layoutManager.GetPanel(layout.PanelLeft)
+	if panel == nil {
+		return
+	}
+
+	c.mu.Lock()
+	collapsed := c.collapsed
+	c.mu.Unlock()
+
+	if !panel.IsVisible() && hasTodos && !collapsed {
+		panel.SetVisible(true)
+	}
+	panel.Render()
+}
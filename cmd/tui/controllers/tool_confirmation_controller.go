@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/awesome-gocui/gocui"
 	"github.com/kcaldas/genie/cmd/events"
@@ -11,6 +12,7 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/presentation"
 	"github.com/kcaldas/genie/cmd/tui/types"
 	core_events "github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
 	"github.com/kcaldas/genie/pkg/logging"
 )
 
@@ -21,7 +23,9 @@ type ToolConfirmationController struct {
 	layoutManager         *layout.LayoutManager
 	inputComponent        types.Component
 	configManager         *helpers.ConfigManager
+	queue                 *ConfirmationQueue
 	ConfirmationComponent *component.ConfirmationComponent
+	pending               core_events.ToolConfirmationRequest // the confirmation on screen
 	textViewerComponent   *component.TextViewerComponent
 	eventBus              core_events.EventBus
 	commandEventBus       *events.CommandEventBus
@@ -34,6 +38,7 @@ func NewToolConfirmationController(
 	inputComponent types.Component,
 	textViewerComponent *component.TextViewerComponent,
 	configManager *helpers.ConfigManager,
+	queue *ConfirmationQueue,
 	eventBus core_events.EventBus,
 	commandEventBus *events.CommandEventBus,
 ) *ToolConfirmationController {
@@ -45,6 +50,7 @@ func NewToolConfirmationController(
 		inputComponent:         inputComponent,
 		textViewerComponent:    textViewerComponent,
 		configManager:          configManager,
+		queue:                  queue,
 		eventBus:               eventBus,
 		commandEventBus:        commandEventBus,
 	}
@@ -56,25 +62,6 @@ func NewToolConfirmationController(
 		}
 	})
 
-	// Subscribe to user cancel input
-	commandEventBus.Subscribe("user.input.cancel", func(event interface{}) {
-		// Skip if no confirmation is active
-		if c.ConfirmationComponent == nil {
-			return
-		}
-		c.stateAccessor.SetWaitingConfirmation(false)
-		c.ConfirmationComponent = nil
-		// All gocui state modifications must run on the main loop
-		c.gui.GetGui().Update(func(g *gocui.Gui) error {
-			c.layoutManager.HideRightPanel()
-			c.layoutManager.SwapComponent("input", c.inputComponent)
-			if err := c.inputComponent.Render(); err != nil {
-				return err
-			}
-			return c.focusPanelByName("input")
-		})
-	})
-
 	return &c
 }
 
@@ -96,17 +83,45 @@ func (tc *ToolConfirmationController) HandleToolConfirmationRequest(event core_e
 		return nil
 	}
 
-	// Set confirmation state
-	tc.stateAccessor.SetWaitingConfirmation(true)
+	// Wait behind any confirmation already on screen
+	position, err := tc.queue.Enqueue(event.ExecutionID,
+		func() error { return tc.present(event) },
+		func() { tc.deny(event.ExecutionID) },
+	)
+	if position > 0 {
+		tc.stateAccessor.AddMessage(types.Message{
+			Role:    "system",
+			Content: fmt.Sprintf("Confirmation request queued (position %d): %s", position, event.Message),
+		})
+	}
+	return err
+}
+
+// present puts the confirmation dialog for event on screen
+func (tc *ToolConfirmationController) present(event core_events.ToolConfirmationRequest) error {
+	// The tool can be approved for the rest of the session, and commands
+	// can be corrected before they run
+	hint := fmt.Sprintf("1 - Yes | 2 - No | 3 - Always allow %s", genie.NewToolApproval(event.ToolName, event.Command))
+	if event.Command != "" {
+		hint += " | e - Edit"
+	}
+	hint += " | r - No, because..."
 
 	// Always create a new confirmation component for tool confirmations
-	tc.ConfirmationComponent = component.NewConfirmationComponent(
+	confirmation := component.NewConfirmationComponent(
 		tc.gui,
 		tc.configManager,
 		event.ExecutionID,
-		"1 - Yes | 2 - No",
+		hint,
 		tc.HandleToolConfirmationResponse, // Connect to controller's response handler
 	)
+	confirmation.SetOnAlwaysAllow(tc.AlwaysAllow)
+	confirmation.SetOnDenyWithReason(tc.DenyWithReason)
+	if event.Command != "" {
+		confirmation.SetOnEdit(tc.EditCommand)
+	}
+	tc.pending = event
+	tc.ConfirmationComponent = confirmation
 
 	title := fmt.Sprintf("Tool: %s", event.ToolName)
 	message := event.Message
@@ -115,7 +130,7 @@ func (tc *ToolConfirmationController) HandleToolConfirmationRequest(event core_e
 	// All gocui state modifications must run on the main loop
 	tc.gui.GetGui().Update(func(g *gocui.Gui) error {
 		// Swap to confirmation component
-		tc.layoutManager.SwapComponent("input", tc.ConfirmationComponent)
+		tc.layoutManager.SwapComponent("input", confirmation)
 
 		// Apply secondary theme color to border and title
 		if view, err := g.View("input"); err == nil {
@@ -127,7 +142,7 @@ func (tc *ToolConfirmationController) HandleToolConfirmationRequest(event core_e
 			}
 		}
 
-		if err := tc.ConfirmationComponent.Render(); err != nil {
+		if err := confirmation.Render(); err != nil {
 			tc.logger().Debug("Failed to render confirmation component", "error", err)
 		}
 
@@ -159,39 +174,150 @@ func (tc *ToolConfirmationController) HandleKeyPress(key interface{}) (bool, err
 		return false, nil
 	}
 
+	executionID := tc.ConfirmationComponent.ExecutionID
+	if tc.IsEditKey(key) && tc.pending.Command != "" {
+		return true, tc.EditCommand(executionID)
+	}
+	if tc.IsAlwaysAllowKey(key) {
+		return true, tc.AlwaysAllow(executionID)
+	}
+	if tc.IsDenyWithReasonKey(key) {
+		return true, tc.DenyWithReason(executionID)
+	}
+
 	// Use the embedded key handler to interpret the key
 	confirmed, handled := tc.InterpretKey(key)
 	if handled {
-		executionID := tc.ConfirmationComponent.ExecutionID
 		return true, tc.HandleToolConfirmationResponse(executionID, confirmed)
 	}
 
 	return false, nil
 }
 
+// EditCommand opens the proposed command in the editor; saving it approves
+// the edited command, and closing the editor returns to the confirmation.
+func (tc *ToolConfirmationController) EditCommand(executionID string) error {
+	tc.commandEventBus.Emit("write.edit", EditRequest{
+		Title:   "Edit command - Ctrl+S runs it",
+		Content: tc.pending.Command,
+		OnSubmit: func(edited string) error {
+			// The confirmation may have been answered or cancelled meanwhile
+			if tc.ConfirmationComponent == nil || tc.ConfirmationComponent.ExecutionID != executionID {
+				return nil
+			}
+			return tc.respond(core_events.ToolConfirmationResponse{
+				ExecutionID: executionID,
+				Confirmed:   true,
+				Command:     strings.TrimSpace(edited),
+			})
+		},
+	})
+	return nil
+}
+
+// DenyWithReason opens the editor for the reason the call is declined;
+// saving it declines and returns the reason to the model, and closing the
+// editor returns to the confirmation.
+func (tc *ToolConfirmationController) DenyWithReason(executionID string) error {
+	tc.commandEventBus.Emit("write.edit", EditRequest{
+		Title: "Why not? Ctrl+S declines and tells the model",
+		OnSubmit: func(reason string) error {
+			// The confirmation may have been answered or cancelled meanwhile
+			if tc.ConfirmationComponent == nil || tc.ConfirmationComponent.ExecutionID != executionID {
+				return nil
+			}
+			return tc.respond(core_events.ToolConfirmationResponse{
+				ExecutionID: executionID,
+				Confirmed:   false,
+				Feedback:    strings.TrimSpace(reason),
+			})
+		},
+	})
+	return nil
+}
+
+// AlwaysAllow approves the call and the tool, for commands running the
+// same program, for the rest of the session.
+func (tc *ToolConfirmationController) AlwaysAllow(executionID string) error {
+	approval := genie.NewToolApproval(tc.pending.ToolName, tc.pending.Command)
+	tc.stateAccessor.AddMessage(types.Message{
+		Role:    "system",
+		Content: fmt.Sprintf("Allowed %s without asking for the rest of this session", approval),
+	})
+	return tc.respond(core_events.ToolConfirmationResponse{
+		ExecutionID: executionID,
+		Confirmed:   true,
+		AlwaysAllow: true,
+	})
+}
+
 func (tc *ToolConfirmationController) HandleToolConfirmationResponse(executionID string, confirmed bool) error {
+	return tc.respond(core_events.ToolConfirmationResponse{
+		ExecutionID: executionID,
+		Confirmed:   confirmed,
+	})
+}
+
+// respond publishes the answer, with the user's edit of the command when
+// there is one, and restores the input.
+func (tc *ToolConfirmationController) respond(response core_events.ToolConfirmationResponse) error {
+	// An unchanged command runs as proposed
+	if response.Command == tc.pending.Command {
+		response.Command = ""
+	}
+
 	// Clear confirmation state
-	tc.stateAccessor.SetWaitingConfirmation(false)
 	tc.ConfirmationComponent = nil
+	tc.pending = core_events.ToolConfirmationRequest{}
 
 	// Publish confirmation response
-	tc.logger().Debug(fmt.Sprintf("Event published: tool.confirmation.response (confirmed=%v)", confirmed))
+	tc.logger().Debug(fmt.Sprintf("Event published: tool.confirmation.response (confirmed=%v, edited=%v, always=%v, feedback=%v)", response.Confirmed, response.Command != "", response.AlwaysAllow, response.Feedback != ""))
+	tc.eventBus.Publish("tool.confirmation.response", response)
+
+	// All gocui state modifications must run on the main loop
+	tc.gui.GetGui().Update(func(g *gocui.Gui) error {
+		tc.layoutManager.HideRightPanel()
+		return nil
+	})
+
+	// The next queued confirmation, if any, takes the input's place
+	if next, err := tc.queue.Done(response.ExecutionID); next || err != nil {
+		return err
+	}
+	tc.restoreInput()
+	return nil
+}
+
+// deny answers no for a confirmation the queue cancelled, taking its
+// dialog down if it is on screen.
+func (tc *ToolConfirmationController) deny(executionID string) {
 	tc.eventBus.Publish("tool.confirmation.response", core_events.ToolConfirmationResponse{
 		ExecutionID: executionID,
-		Confirmed:   confirmed,
+		Confirmed:   false,
 	})
 
-	// All gocui state modifications must run on the main loop
+	if tc.ConfirmationComponent == nil || tc.ConfirmationComponent.ExecutionID != executionID {
+		return
+	}
+	tc.ConfirmationComponent = nil
+	tc.pending = core_events.ToolConfirmationRequest{}
 	tc.gui.GetGui().Update(func(g *gocui.Gui) error {
 		tc.layoutManager.HideRightPanel()
+		return nil
+	})
+	tc.restoreInput()
+}
+
+// restoreInput swaps the input back in place of the confirmation dialog
+func (tc *ToolConfirmationController) restoreInput() {
+	// All gocui state modifications must run on the main loop
+	tc.gui.GetGui().Update(func(g *gocui.Gui) error {
 		tc.layoutManager.SwapComponent("input", tc.inputComponent)
 		if err := tc.inputComponent.Render(); err != nil {
 			return err
 		}
 		return tc.focusPanelByName("input")
 	})
-
-	return nil
 }
 
 func (tc *ToolConfirmationController) focusPanelByName(panelName string) error {
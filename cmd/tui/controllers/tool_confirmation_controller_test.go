@@ -38,6 +38,7 @@ type confirmationTestEnv struct {
 	layoutManager   *layout.LayoutManager
 	inputComponent  *mockComponent
 	configManager   *helpers.ConfigManager
+	queue           *ConfirmationQueue
 	eventBus        core_events.EventBus
 	commandEventBus *events.CommandEventBus
 }
@@ -58,15 +59,18 @@ func newConfirmationTestEnv(t *testing.T) *confirmationTestEnv {
 
 	chatState := state.NewChatState(100)
 	uiState := state.NewUIState()
+	stateAccessor := state.NewStateAccessor(chatState, uiState)
+	commandEventBus := events.NewCommandEventBus()
 
 	return &confirmationTestEnv{
 		gui:             &simulatorGui{gui: g},
-		stateAccessor:   state.NewStateAccessor(chatState, uiState),
+		stateAccessor:   stateAccessor,
 		layoutManager:   layout.NewLayoutManager(g, &layout.LayoutConfig{}),
 		inputComponent:  &mockComponent{key: "input", viewName: "input"},
 		configManager:   configManager,
+		queue:           NewConfirmationQueue(stateAccessor, commandEventBus),
 		eventBus:        core_events.NewEventBus(),
-		commandEventBus: events.NewCommandEventBus(),
+		commandEventBus: commandEventBus,
 	}
 }
 
@@ -146,6 +150,7 @@ func newToolConfirmationController(t *testing.T) (*ToolConfirmationController, *
 		env.inputComponent,
 		textViewer,
 		env.configManager,
+		env.queue,
 		env.eventBus,
 		env.commandEventBus,
 	)
@@ -292,7 +297,7 @@ func TestToolConfirmationController_AutoAcceptOnlyAppliesToConfiguredTool(t *tes
 	require.NotNil(t, controller.ConfirmationComponent)
 }
 
-func TestToolConfirmationController_UserCancelClearsStateWithoutResponse(t *testing.T) {
+func TestToolConfirmationController_UserCancelDeniesPendingConfirmation(t *testing.T) {
 	controller, env := newToolConfirmationController(t)
 	responses := env.subscribeToolResponses()
 
@@ -305,8 +310,9 @@ func TestToolConfirmationController_UserCancelClearsStateWithoutResponse(t *test
 	assert.False(t, env.stateAccessor.IsWaitingConfirmation(), "cancel should clear waiting state")
 	assert.Nil(t, controller.ConfirmationComponent, "cancel should discard the pending confirmation")
 
-	env.drainBus(t)
-	assert.Empty(t, responses, "cancel does not publish a confirmation response")
+	resp := waitForToolResponse(t, responses)
+	assert.Equal(t, "exec-cancel", resp.ExecutionID)
+	assert.False(t, resp.Confirmed, "a cancelled confirmation is denied")
 }
 
 func TestToolConfirmationController_UserCancelWithoutActiveConfirmationIsNoOp(t *testing.T) {
@@ -318,3 +324,108 @@ func TestToolConfirmationController_UserCancelWithoutActiveConfirmationIsNoOp(t
 	assert.False(t, env.stateAccessor.IsWaitingConfirmation())
 	assert.Nil(t, controller.ConfirmationComponent)
 }
+
+// subscribeEditRequests captures write.edit requests sent to the editor.
+func (e *confirmationTestEnv) subscribeEditRequests() chan EditRequest {
+	requests := make(chan EditRequest, 4)
+	e.commandEventBus.Subscribe("write.edit", func(data interface{}) {
+		if req, ok := data.(EditRequest); ok {
+			requests <- req
+		}
+	})
+	return requests
+}
+
+func waitForEditRequest(t *testing.T, ch chan EditRequest) EditRequest {
+	t.Helper()
+	select {
+	case req := <-ch:
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for write.edit")
+		return EditRequest{}
+	}
+}
+
+func TestToolConfirmationController_EditApprovesEditedCommand(t *testing.T) {
+	controller, env := newToolConfirmationController(t)
+	responses := env.subscribeToolResponses()
+	edits := env.subscribeEditRequests()
+
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-edit", "bash")))
+
+	handled, err := controller.HandleKeyPress('e')
+	require.NoError(t, err)
+	assert.True(t, handled)
+
+	edit := waitForEditRequest(t, edits)
+	assert.Equal(t, "rm -rf ./build", edit.Content)
+	assert.True(t, env.stateAccessor.IsWaitingConfirmation(), "editing keeps the confirmation pending")
+
+	require.NoError(t, edit.OnSubmit("rm -rf ./build/cache\n"))
+
+	resp := waitForToolResponse(t, responses)
+	assert.Equal(t, "exec-edit", resp.ExecutionID)
+	assert.True(t, resp.Confirmed)
+	assert.Equal(t, "rm -rf ./build/cache", resp.Command)
+	assert.Nil(t, controller.ConfirmationComponent)
+}
+
+func TestToolConfirmationController_EditWithoutCommandIsIgnored(t *testing.T) {
+	controller, env := newToolConfirmationController(t)
+
+	request := toolRequest("exec-nocmd", "bash")
+	request.Command = ""
+	require.NoError(t, controller.HandleToolConfirmationRequest(request))
+
+	handled, err := controller.HandleKeyPress('e')
+	require.NoError(t, err)
+	assert.False(t, handled, "there is nothing to edit")
+	assert.True(t, env.stateAccessor.IsWaitingConfirmation())
+}
+
+func TestToolConfirmationController_AlwaysAllowApprovesForTheSession(t *testing.T) {
+	controller, env := newToolConfirmationController(t)
+	responses := env.subscribeToolResponses()
+
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-always", "Bash")))
+	assert.Contains(t, controller.ConfirmationComponent.GetTitle(), "3 - Always allow Bash: rm")
+
+	handled, err := controller.HandleKeyPress('3')
+	require.NoError(t, err)
+	assert.True(t, handled)
+
+	resp := waitForToolResponse(t, responses)
+	assert.Equal(t, "exec-always", resp.ExecutionID)
+	assert.True(t, resp.Confirmed)
+	assert.True(t, resp.AlwaysAllow)
+
+	messages := env.stateAccessor.GetMessages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Content, "Allowed Bash: rm without asking")
+}
+
+func TestToolConfirmationController_DenyWithReasonReturnsFeedback(t *testing.T) {
+	controller, env := newToolConfirmationController(t)
+	responses := env.subscribeToolResponses()
+	edits := env.subscribeEditRequests()
+
+	require.NoError(t, controller.HandleToolConfirmationRequest(toolRequest("exec-reason", "bash")))
+	assert.Contains(t, controller.ConfirmationComponent.GetTitle(), "r - No, because...")
+
+	handled, err := controller.HandleKeyPress('r')
+	require.NoError(t, err)
+	assert.True(t, handled)
+
+	edit := waitForEditRequest(t, edits)
+	assert.Empty(t, edit.Content)
+	assert.True(t, env.stateAccessor.IsWaitingConfirmation(), "typing the reason keeps the confirmation pending")
+
+	require.NoError(t, edit.OnSubmit("use the staging DB instead\n"))
+
+	resp := waitForToolResponse(t, responses)
+	assert.Equal(t, "exec-reason", resp.ExecutionID)
+	assert.False(t, resp.Confirmed)
+	assert.Equal(t, "use the staging DB instead", resp.Feedback)
+	assert.Nil(t, controller.ConfirmationComponent)
+}
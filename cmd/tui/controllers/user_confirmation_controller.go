@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/awesome-gocui/gocui"
 	"github.com/kcaldas/genie/cmd/events"
@@ -21,16 +22,15 @@ type UserConfirmationController struct {
 	layoutManager         *layout.LayoutManager
 	inputComponent        types.Component
 	configManager         *helpers.ConfigManager
+	queue                 *ConfirmationQueue
 	ConfirmationComponent *component.ConfirmationComponent
 	diffViewerComponent   *component.DiffViewerComponent
 	textViewerComponent   *component.TextViewerComponent
 	eventBus              core_events.EventBus
 	commandEventBus       *events.CommandEventBus
 
-	// Queue management
-	confirmationQueue      []core_events.UserConfirmationRequest
-	processingConfirmation bool
-	currentContentType     string // Track content type for the current confirmation
+	currentContentType string // Track content type for the current confirmation
+	currentEditable    string // Editable text offered by the current confirmation
 }
 
 func NewUserConfirmationController(
@@ -41,6 +41,7 @@ func NewUserConfirmationController(
 	diffViewerComponent *component.DiffViewerComponent,
 	textViewerComponent *component.TextViewerComponent,
 	configManager *helpers.ConfigManager,
+	queue *ConfirmationQueue,
 	eventBus core_events.EventBus,
 	commandEventBus *events.CommandEventBus,
 ) *UserConfirmationController {
@@ -53,6 +54,7 @@ func NewUserConfirmationController(
 		diffViewerComponent:    diffViewerComponent,
 		textViewerComponent:    textViewerComponent,
 		configManager:          configManager,
+		queue:                  queue,
 		eventBus:               eventBus,
 		commandEventBus:        commandEventBus,
 	}
@@ -62,25 +64,6 @@ func NewUserConfirmationController(
 			c.HandleUserConfirmationRequest(event)
 		}
 	})
-	// Subscribe to user cancel input
-	commandEventBus.Subscribe("user.input.cancel", func(event interface{}) {
-		// Skip if no confirmation is being processed
-		if !c.processingConfirmation {
-			return
-		}
-		c.stateAccessor.SetWaitingConfirmation(false)
-		c.processingConfirmation = false
-		c.ConfirmationComponent = nil
-		// All gocui state modifications must run on the main loop
-		c.gui.GetGui().Update(func(g *gocui.Gui) error {
-			c.layoutManager.HideRightPanel()
-			c.layoutManager.SwapComponent("input", c.inputComponent)
-			if err := c.inputComponent.Render(); err != nil {
-				return err
-			}
-			return c.focusPanelByName("input")
-		})
-	})
 
 	return &c
 }
@@ -103,25 +86,19 @@ func (uc *UserConfirmationController) HandleUserConfirmationRequest(event core_e
 		return nil
 	}
 
-	// Set confirmation state
-	uc.stateAccessor.SetWaitingConfirmation(true)
-	// Add to queue if we're already processing a confirmation
-	if uc.processingConfirmation {
-		uc.confirmationQueue = append(uc.confirmationQueue, event)
-
+	// Wait behind any confirmation already on screen
+	position, err := uc.queue.Enqueue(event.ExecutionID,
+		func() error { return uc.processConfirmationRequest(event) },
+		func() { uc.deny(event.ExecutionID) },
+	)
+	if position > 0 {
 		// Show queued message to user
-		queuePosition := len(uc.confirmationQueue)
 		uc.stateAccessor.AddMessage(types.Message{
 			Role:    "system",
-			Content: fmt.Sprintf("Confirmation request queued (position %d): %s", queuePosition, event.Message),
+			Content: fmt.Sprintf("Confirmation request queued (position %d): %s", position, event.Message),
 		})
-		return nil
 	}
-
-	// Mark as processing
-	uc.processingConfirmation = true
-
-	return uc.processConfirmationRequest(event)
+	return err
 }
 
 func (uc *UserConfirmationController) processConfirmationRequest(event core_events.UserConfirmationRequest) error {
@@ -140,14 +117,26 @@ func (uc *UserConfirmationController) processConfirmationRequest(event core_even
 		cancelText = "Cancel"
 	}
 
+	hint := fmt.Sprintf("1 - %s | 2 - %s", confirmText, cancelText)
+	if event.Editable != "" {
+		hint += " | e - Edit"
+	}
+	hint += fmt.Sprintf(" | r - %s, because...", cancelText)
+
 	// Always create a new confirmation component for user confirmations
-	uc.ConfirmationComponent = component.NewConfirmationComponent(
+	confirmation := component.NewConfirmationComponent(
 		uc.gui,
 		uc.configManager,
 		event.ExecutionID,
-		fmt.Sprintf("1 - %s | 2 - %s", confirmText, cancelText),
+		hint,
 		uc.HandleUserConfirmationResponse, // Connect to controller's response handler
 	)
+	if event.Editable != "" {
+		confirmation.SetOnEdit(uc.EditContent)
+	}
+	confirmation.SetOnDenyWithReason(uc.DenyWithReason)
+	uc.currentEditable = event.Editable
+	uc.ConfirmationComponent = confirmation
 
 	// Store the content type for this confirmation and set active type
 	uc.currentContentType = event.ContentType
@@ -167,13 +156,17 @@ func (uc *UserConfirmationController) processConfirmationRequest(event core_even
 		if event.FilePath != "" {
 			viewerTitle = fmt.Sprintf("Markdown: %s", event.FilePath)
 		}
+	} else if event.ContentType == "plan" && event.Content != "" {
+		// Plans are markdown; show them alongside the approve/reject prompt
+		viewerMode = "text-viewer"
+		viewerTitle = title
 	}
 	viewerContent := event.Content
 
 	// All gocui state modifications must run on the main loop
 	uc.gui.GetGui().Update(func(g *gocui.Gui) error {
 		// Swap to confirmation component
-		uc.layoutManager.SwapComponent("input", uc.ConfirmationComponent)
+		uc.layoutManager.SwapComponent("input", confirmation)
 
 		// Apply secondary theme color to border and title
 		if view, err := g.View("input"); err == nil {
@@ -185,7 +178,7 @@ func (uc *UserConfirmationController) processConfirmationRequest(event core_even
 			}
 		}
 
-		if err := uc.ConfirmationComponent.Render(); err != nil {
+		if err := confirmation.Render(); err != nil {
 			uc.logger().Debug("Failed to render confirmation component", "error", err)
 		}
 
@@ -233,51 +226,121 @@ func (uc *UserConfirmationController) HandleKeyPress(key interface{}) (bool, err
 		return false, nil
 	}
 
+	executionID := uc.ConfirmationComponent.ExecutionID
+	if uc.IsEditKey(key) && uc.currentEditable != "" {
+		return true, uc.EditContent(executionID)
+	}
+	if uc.IsDenyWithReasonKey(key) {
+		return true, uc.DenyWithReason(executionID)
+	}
+
 	// Use the embedded key handler to interpret the key
 	confirmed, handled := uc.InterpretKey(key)
 	if handled {
-		executionID := uc.ConfirmationComponent.ExecutionID
 		return true, uc.HandleUserConfirmationResponse(executionID, confirmed)
 	}
 
 	return false, nil
 }
 
+// EditContent opens the proposed content in the editor; saving it approves
+// the edited content, and closing the editor returns to the confirmation.
+func (uc *UserConfirmationController) EditContent(executionID string) error {
+	uc.commandEventBus.Emit("write.edit", EditRequest{
+		Title:   "Edit content - Ctrl+S approves it",
+		Content: uc.currentEditable,
+		OnSubmit: func(edited string) error {
+			// The confirmation may have been answered or cancelled meanwhile
+			if uc.ConfirmationComponent == nil || uc.ConfirmationComponent.ExecutionID != executionID {
+				return nil
+			}
+			return uc.respond(core_events.UserConfirmationResponse{
+				ExecutionID: executionID,
+				Confirmed:   true,
+				Content:     edited,
+			})
+		},
+	})
+	return nil
+}
+
+// DenyWithReason opens the editor for the reason the request is declined;
+// saving it declines and returns the reason to the model, and closing the
+// editor returns to the confirmation.
+func (uc *UserConfirmationController) DenyWithReason(executionID string) error {
+	uc.commandEventBus.Emit("write.edit", EditRequest{
+		Title: "Why not? Ctrl+S declines and tells the model",
+		OnSubmit: func(reason string) error {
+			// The confirmation may have been answered or cancelled meanwhile
+			if uc.ConfirmationComponent == nil || uc.ConfirmationComponent.ExecutionID != executionID {
+				return nil
+			}
+			return uc.respond(core_events.UserConfirmationResponse{
+				ExecutionID: executionID,
+				Confirmed:   false,
+				Feedback:    strings.TrimSpace(reason),
+			})
+		},
+	})
+	return nil
+}
+
 func (uc *UserConfirmationController) HandleUserConfirmationResponse(executionID string, confirmed bool) error {
+	return uc.respond(core_events.UserConfirmationResponse{
+		ExecutionID: executionID,
+		Confirmed:   confirmed,
+	})
+}
+
+// respond publishes the answer, with the user's edit of the content or
+// reason for declining when there is one, and moves on to the next queued
+// confirmation.
+func (uc *UserConfirmationController) respond(response core_events.UserConfirmationResponse) error {
+	// Unchanged content is approved as proposed
+	if response.Content == uc.currentEditable {
+		response.Content = ""
+	}
+
 	// Clear confirmation state
-	uc.stateAccessor.SetWaitingConfirmation(false)
 	uc.ConfirmationComponent = nil
+	uc.currentEditable = ""
 
 	// Hide viewer panel if it was shown
-	if uc.currentContentType == "diff" || uc.currentContentType == "markdown" {
+	if uc.currentContentType == "diff" || uc.currentContentType == "markdown" || uc.currentContentType == "plan" {
 		uc.layoutManager.HideRightPanel()
 	}
 
 	// Publish confirmation response
-	uc.logger().Debug(fmt.Sprintf("Event published: user.confirmation.response (confirmed=%v)", confirmed))
-	uc.eventBus.Publish("user.confirmation.response", core_events.UserConfirmationResponse{
-		ExecutionID: executionID,
-		Confirmed:   confirmed,
-	})
+	uc.logger().Debug(fmt.Sprintf("Event published: user.confirmation.response (confirmed=%v, edited=%v, feedback=%v)", response.Confirmed, response.Content != "", response.Feedback != ""))
+	uc.eventBus.Publish("user.confirmation.response", response)
 
 	// Process next confirmation from queue
-	return uc.processNextConfirmation()
+	if next, err := uc.queue.Done(response.ExecutionID); next || err != nil {
+		return err
+	}
+	uc.restoreInput()
+	return nil
 }
 
-func (uc *UserConfirmationController) processNextConfirmation() error {
-	// Check if there are more confirmations in the queue
-	if len(uc.confirmationQueue) > 0 {
-		// Get the next confirmation from the queue
-		nextEvent := uc.confirmationQueue[0]
-		uc.confirmationQueue = uc.confirmationQueue[1:] // Remove from queue
+// deny answers no for a confirmation the queue cancelled, taking its
+// dialog down if it is on screen.
+func (uc *UserConfirmationController) deny(executionID string) {
+	uc.eventBus.Publish("user.confirmation.response", core_events.UserConfirmationResponse{
+		ExecutionID: executionID,
+		Confirmed:   false,
+	})
 
-		// Process it immediately
-		return uc.processConfirmationRequest(nextEvent)
+	if uc.ConfirmationComponent == nil || uc.ConfirmationComponent.ExecutionID != executionID {
+		return
 	}
+	uc.ConfirmationComponent = nil
+	uc.currentEditable = ""
+	uc.layoutManager.HideRightPanel()
+	uc.restoreInput()
+}
 
-	// No more confirmations - restore input component and mark as not processing
-	uc.processingConfirmation = false
-
+// restoreInput swaps the input back in place of the confirmation dialog
+func (uc *UserConfirmationController) restoreInput() {
 	// All gocui state modifications must run on the main loop
 	uc.gui.GetGui().Update(func(g *gocui.Gui) error {
 		uc.layoutManager.SwapComponent("input", uc.inputComponent)
@@ -286,8 +349,6 @@ func (uc *UserConfirmationController) processNextConfirmation() error {
 		}
 		return uc.focusPanelByName("input")
 	})
-
-	return nil
 }
 
 func (uc *UserConfirmationController) focusPanelByName(panelName string) error {
@@ -298,7 +359,8 @@ func (uc *UserConfirmationController) focusPanelByName(panelName string) error {
 	return nil
 }
 
-// GetConfirmationQueueStatus returns information about pending confirmations
+// GetConfirmationQueueStatus returns how many confirmations wait behind the
+// one on screen, and whether one is on screen
 func (uc *UserConfirmationController) GetConfirmationQueueStatus() (int, bool) {
-	return len(uc.confirmationQueue), uc.processingConfirmation
+	return uc.queue.Status()
 }
@@ -23,6 +23,7 @@ func newUserConfirmationController(t *testing.T) (*UserConfirmationController, *
 		diffViewer,
 		textViewer,
 		env.configManager,
+		env.queue,
 		env.eventBus,
 		env.commandEventBus,
 	)
@@ -235,7 +236,7 @@ func TestUserConfirmationController_KeyPressWithoutActiveConfirmation(t *testing
 	assert.Empty(t, responses)
 }
 
-func TestUserConfirmationController_UserCancelClearsStateWithoutResponse(t *testing.T) {
+func TestUserConfirmationController_UserCancelDeniesPendingConfirmation(t *testing.T) {
 	controller, env := newUserConfirmationController(t)
 	responses := env.subscribeUserResponses()
 
@@ -252,8 +253,9 @@ func TestUserConfirmationController_UserCancelClearsStateWithoutResponse(t *test
 	assert.Equal(t, 0, queued)
 	assert.False(t, processing, "cancel should stop processing")
 
-	env.drainBus(t)
-	assert.Empty(t, responses, "cancel does not publish a confirmation response")
+	resp := waitForUserResponse(t, responses)
+	assert.Equal(t, "exec-cancel", resp.ExecutionID)
+	assert.False(t, resp.Confirmed, "a cancelled confirmation is denied")
 }
 
 func TestUserConfirmationController_UserCancelWithoutActiveConfirmationIsNoOp(t *testing.T) {
@@ -268,3 +270,44 @@ func TestUserConfirmationController_UserCancelWithoutActiveConfirmationIsNoOp(t
 	_, processing := controller.GetConfirmationQueueStatus()
 	assert.False(t, processing)
 }
+
+func TestUserConfirmationController_EditApprovesEditedContent(t *testing.T) {
+	controller, env := newUserConfirmationController(t)
+	responses := env.subscribeUserResponses()
+	edits := env.subscribeEditRequests()
+
+	request := userRequest("exec-edit")
+	request.Editable = "package main\n"
+	require.NoError(t, controller.HandleUserConfirmationRequest(request))
+
+	handled, err := controller.HandleKeyPress('e')
+	require.NoError(t, err)
+	assert.True(t, handled)
+
+	edit := waitForEditRequest(t, edits)
+	assert.Equal(t, "package main\n", edit.Content)
+	require.NoError(t, edit.OnSubmit("package app\n"))
+
+	resp := waitForUserResponse(t, responses)
+	assert.Equal(t, "exec-edit", resp.ExecutionID)
+	assert.True(t, resp.Confirmed)
+	assert.Equal(t, "package app\n", resp.Content)
+}
+
+func TestUserConfirmationController_UnchangedEditApprovesAsProposed(t *testing.T) {
+	controller, env := newUserConfirmationController(t)
+	responses := env.subscribeUserResponses()
+	edits := env.subscribeEditRequests()
+
+	request := userRequest("exec-same")
+	request.Editable = "package main\n"
+	require.NoError(t, controller.HandleUserConfirmationRequest(request))
+
+	_, err := controller.HandleKeyPress('E')
+	require.NoError(t, err)
+	require.NoError(t, waitForEditRequest(t, edits).OnSubmit("package main\n"))
+
+	resp := waitForUserResponse(t, responses)
+	assert.True(t, resp.Confirmed)
+	assert.Empty(t, resp.Content, "unchanged content needs no replacement")
+}
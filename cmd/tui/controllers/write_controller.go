@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"strings"
+
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/history"
 	"github.com/kcaldas/genie/cmd/tui/component"
@@ -9,6 +11,15 @@ import (
 	"github.com/kcaldas/genie/cmd/tui/types"
 )
 
+// EditRequest asks the write controller to open text for editing rather
+// than for composing a message. OnSubmit receives the edited text, which
+// keeps Content's trailing newline convention.
+type EditRequest struct {
+	Title    string
+	Content  string
+	OnSubmit func(edited string) error
+}
+
 type WriteController struct {
 	gui             types.Gui
 	configManager   *helpers.ConfigManager
@@ -39,6 +50,13 @@ func NewWriteController(
 		}
 	})
 
+	// Subscribe to edit requests, e.g. from a confirmation awaiting approval
+	commandEventBus.Subscribe("write.edit", func(data interface{}) {
+		if req, ok := data.(EditRequest); ok {
+			controller.ShowEditor(req)
+		}
+	})
+
 	return controller
 }
 
@@ -47,6 +65,22 @@ func (c *WriteController) Show() error {
 }
 
 func (c *WriteController) ShowWithContent(initialContent string) error {
+	return c.show("", initialContent, nil)
+}
+
+// ShowEditor opens req.Content for editing; Ctrl+S hands the result to
+// req.OnSubmit and Esc discards it.
+func (c *WriteController) ShowEditor(req EditRequest) error {
+	return c.show(req.Title, req.Content, func(text string) error {
+		edited := strings.TrimRight(text, "\n")
+		if strings.HasSuffix(req.Content, "\n") {
+			edited += "\n"
+		}
+		return req.OnSubmit(edited)
+	})
+}
+
+func (c *WriteController) show(title, initialContent string, onSubmit func(string) error) error {
 	// Disable all panel keybindings so write component is the only thing handling events
 	c.layoutManager.DisableAllKeybindings()
 
@@ -72,6 +106,13 @@ func (c *WriteController) ShowWithContent(initialContent string) error {
 		},
 	)
 
+	if title != "" {
+		writeComponent.SetTitle(title)
+	}
+	if onSubmit != nil {
+		writeComponent.SetOnSubmit(onSubmit)
+	}
+
 	// Show the write component using its Show() method
 	err := writeComponent.Show()
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/awesome-gocui/gocui"
+	"github.com/kcaldas/genie/cmd/help"
 	"github.com/kcaldas/genie/cmd/slashcommands"
 	"github.com/kcaldas/genie/cmd/tui/controllers/commands"
 )
@@ -67,6 +68,13 @@ func (h *ManPageHelpRenderer) RenderHelp() string {
 	sb.WriteString("\n## EXAMPLES\n")
 	sb.WriteString(h.generateExamples())
 
+	// Topics section
+	sb.WriteString("\n## TOPICS\n")
+	sb.WriteString("Guides to parts of Genie that span commands. Type `:help` followed by the topic name.\n\n")
+	for _, topic := range help.Topics() {
+		fmt.Fprintf(&sb, "- `:help %s` - %s\n", topic.Name, topic.Summary)
+	}
+
 	// Footer
 	sb.WriteString("\n## SEE ALSO\n")
 	sb.WriteString("For more information, visit the Genie documentation.\n")
@@ -224,11 +232,14 @@ func (h *ManPageHelpRenderer) findShortcutForCommand(commandName string) string
 func (h *ManPageHelpRenderer) formatKeyName(key gocui.Key, mod gocui.Modifier) string {
 	var parts []string
 
-	// Add modifiers - note: gocui may not have all these modifiers
-	// For now, we'll just handle ModNone and add basic logic for future modifiers
-	if mod != gocui.ModNone {
-		// If there are non-zero modifiers in the future, we can handle them here
-		// For now, most shortcuts use ModNone
+	// Add modifiers
+	switch mod {
+	case gocui.ModNone:
+	case gocui.ModShift:
+		parts = append(parts, "Shift")
+	case gocui.ModAlt:
+		parts = append(parts, "Alt")
+	default:
 		parts = append(parts, fmt.Sprintf("Mod(%d)", mod))
 	}
 
@@ -276,6 +287,7 @@ func (h *ManPageHelpRenderer) getKeyName(key gocui.Key) string {
 		gocui.MouseLeft:      "Left Click",
 		gocui.MouseMiddle:    "Middle Click",
 		gocui.MouseRight:     "Right Click",
+		gocui.MouseRelease:   "Mouse Release",
 	}
 
 	// Check if we have a name for this key
@@ -0,0 +1,170 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+// MainBranch is the branch a conversation starts on
+const MainBranch = "main"
+
+var branchNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ErrBranchNotFound is returned when no branch has the requested name
+var ErrBranchNotFound = errors.New("branch not found")
+
+// Branch is a saved conversation: what the model remembers and what the
+// messages view shows
+type Branch struct {
+	Name      string                  `json:"name"`
+	Parent    string                  `json:"parent,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+	Turns     []genie.ChatHistoryTurn `json:"turns"`
+	Messages  []types.Message         `json:"messages"`
+}
+
+// BranchStore keeps one JSON file per branch in a directory
+type BranchStore struct {
+	dir string
+}
+
+// NewBranchStore creates a store for the project at genieHome. Branches
+// live in .genie/branches, next to the project's history.
+func NewBranchStore(genieHome string) *BranchStore {
+	return &BranchStore{dir: filepath.Join(genieHome, ".genie", "branches")}
+}
+
+// ValidateBranchName rejects names that are not safe as file names
+func ValidateBranchName(name string) error {
+	if !branchNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid branch name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+func (s *BranchStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Exists reports whether a branch is saved under name
+func (s *BranchStore) Exists(name string) bool {
+	_, err := os.Stat(s.path(name))
+	return err == nil
+}
+
+// Save writes the branch, replacing any branch saved under its name
+func (s *BranchStore) Save(branch *Branch) error {
+	if err := ValidateBranchName(branch.Name); err != nil {
+		return err
+	}
+	now := time.Now()
+	if branch.CreatedAt.IsZero() {
+		branch.CreatedAt = now
+	}
+	branch.UpdatedAt = now
+
+	data, err := json.MarshalIndent(branch, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	// Write then rename, so a crash never leaves half a branch behind
+	tmp := s.path(branch.Name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(branch.Name))
+}
+
+// Load reads the branch saved under name
+func (s *BranchStore) Load(name string) (*Branch, error) {
+	if err := ValidateBranchName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrBranchNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var branch Branch
+	if err := json.Unmarshal(data, &branch); err != nil {
+		return nil, fmt.Errorf("branch %s is corrupted: %w", name, err)
+	}
+	branch.Name = name
+	return &branch, nil
+}
+
+// List returns every saved branch, sorted by name. Unreadable files are
+// skipped.
+func (s *BranchStore) List() ([]*Branch, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var branches []*Branch
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if branch, err := s.Load(name); err == nil {
+			branches = append(branches, branch)
+		}
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].Name < branches[j].Name
+	})
+	return branches, nil
+}
+
+// Delete removes the branch saved under name
+func (s *BranchStore) Delete(name string) error {
+	if err := ValidateBranchName(name); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrBranchNotFound, name)
+	}
+	return err
+}
+
+// BranchDiff is where two branches part ways: the turns they share, then
+// the turns only one of them has
+type BranchDiff struct {
+	Shared int
+	OnlyA  []genie.ChatHistoryTurn
+	OnlyB  []genie.ChatHistoryTurn
+}
+
+// DiffBranches compares the conversations of two branches turn by turn
+func DiffBranches(a, b *Branch) BranchDiff {
+	shared := 0
+	for shared < len(a.Turns) && shared < len(b.Turns) && a.Turns[shared] == b.Turns[shared] {
+		shared++
+	}
+	return BranchDiff{
+		Shared: shared,
+		OnlyA:  a.Turns[shared:],
+		OnlyB:  b.Turns[shared:],
+	}
+}
@@ -1,21 +1,142 @@
 package helpers
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
 	"github.com/atotto/clipboard"
+	"github.com/kcaldas/genie/cmd/tui/types"
 )
 
-type Clipboard struct{}
+// maxOSC52Bytes bounds what is sent through the terminal; many terminals
+// silently drop larger OSC 52 sequences
+const maxOSC52Bytes = 100000
+
+type Clipboard struct {
+	configManager *ConfigManager
+	env           types.TerminalEnvironment
+	// terminal opens the terminal OSC 52 sequences are written to
+	terminal func() (io.WriteCloser, error)
+}
+
+// NewClipboard creates a clipboard that follows the Clipboard setting of
+// configManager, when given
+func NewClipboard(configManager *ConfigManager) *Clipboard {
+	return &Clipboard{
+		configManager: configManager,
+		env:           types.DetectTerminalEnvironment(),
+		terminal:      openTerminal,
+	}
+}
 
-func NewClipboard() *Clipboard {
-	return &Clipboard{}
+func openTerminal() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
 }
 
+// mode returns the Clipboard setting: "system", "osc52" or "auto"
+func (h *Clipboard) mode() string {
+	if h.configManager == nil {
+		return "auto"
+	}
+	switch mode := h.configManager.GetConfig().Clipboard; mode {
+	case "system", "osc52":
+		return mode
+	default:
+		return "auto"
+	}
+}
+
+// Copy writes text to the system clipboard, falling back to the Windows
+// clipboard tools when no native clipboard is reachable, as under WSL,
+// and then to the terminal's clipboard through OSC 52. Over SSH the system
+// clipboard belongs to the remote machine, so "auto" goes straight to
+// OSC 52.
 func (h *Clipboard) Copy(text string) error {
-	return clipboard.WriteAll(text)
+	mode := h.mode()
+	if mode == "osc52" || (mode == "auto" && h.env.SSH) {
+		return h.CopyOSC52(text)
+	}
+
+	err := clipboard.WriteAll(text)
+	if err == nil {
+		return nil
+	}
+	if path, lookErr := exec.LookPath("clip.exe"); lookErr == nil {
+		cmd := exec.Command(path)
+		cmd.Stdin = strings.NewReader(text)
+		if cmd.Run() == nil {
+			return nil
+		}
+	}
+	if mode == "system" {
+		return err
+	}
+	if osc52Err := h.CopyOSC52(text); osc52Err != nil {
+		return fmt.Errorf("%w; %v", err, osc52Err)
+	}
+	return nil
+}
+
+// CopyOSC52 asks the terminal to put text on its clipboard. The terminal
+// does not answer, so success means the request was sent; terminals that
+// do not support OSC 52 ignore it.
+func (h *Clipboard) CopyOSC52(text string) error {
+	if len(text) > maxOSC52Bytes {
+		return fmt.Errorf("%d bytes is too much for the terminal clipboard (max %d)", len(text), maxOSC52Bytes)
+	}
+	if h.terminal == nil {
+		return fmt.Errorf("no terminal for OSC 52")
+	}
+	tty, err := h.terminal()
+	if err != nil {
+		return fmt.Errorf("no terminal for OSC 52: %w", err)
+	}
+	defer tty.Close()
+	_, err = io.WriteString(tty, passThroughMultiplexer(osc52Sequence(text), h.env))
+	return err
 }
 
+// osc52Sequence is the escape sequence that sets the clipboard to text
+func osc52Sequence(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+}
+
+// passThroughMultiplexer wraps seq so tmux or screen hand it to the outer
+// terminal instead of swallowing it. tmux also needs the escapes inside
+// doubled, and allow-passthrough set on tmux 3.3 and later.
+func passThroughMultiplexer(seq string, env types.TerminalEnvironment) string {
+	switch {
+	case env.Tmux:
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case env.Screen:
+		return "\x1bP" + seq + "\x1b\\"
+	default:
+		return seq
+	}
+}
+
+// Paste reads the system clipboard, with the same Windows fallback as Copy.
 func (h *Clipboard) Paste() (string, error) {
-	return clipboard.ReadAll()
+	text, err := clipboard.ReadAll()
+	if err == nil {
+		return text, nil
+	}
+	for _, shell := range []string{"pwsh.exe", "powershell.exe"} {
+		path, lookErr := exec.LookPath(shell)
+		if lookErr != nil {
+			continue
+		}
+		out, runErr := exec.Command(path, "-NoLogo", "-NoProfile", "-NonInteractive", "-Command", "Get-Clipboard -Raw").Output()
+		if runErr == nil {
+			return strings.TrimSuffix(string(bytes.ReplaceAll(out, []byte("\r\n"), []byte("\n"))), "\n"), nil
+		}
+	}
+	return "", err
 }
 
 func (h *Clipboard) IsAvailable() bool {
@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestClipboardCopyOSC52(t *testing.T) {
+	out := &bytes.Buffer{}
+	c := &Clipboard{terminal: func() (io.WriteCloser, error) { return nopWriteCloser{out}, nil }}
+
+	require.NoError(t, c.CopyOSC52("hello"))
+	assert.Equal(t, "\x1b]52;c;aGVsbG8=\x07", out.String())
+
+	out.Reset()
+	err := c.CopyOSC52(strings.Repeat("x", maxOSC52Bytes+1))
+	assert.ErrorContains(t, err, "too much")
+	assert.Empty(t, out.String())
+}
+
+func TestClipboardCopyOSC52WithoutTerminal(t *testing.T) {
+	c := &Clipboard{terminal: func() (io.WriteCloser, error) { return nil, errors.New("no tty") }}
+	assert.ErrorContains(t, c.CopyOSC52("hello"), "no tty")
+}
+
+func TestClipboardCopyOverSSHUsesOSC52(t *testing.T) {
+	out := &bytes.Buffer{}
+	c := &Clipboard{
+		env:      types.TerminalEnvironment{SSH: true, Tmux: true},
+		terminal: func() (io.WriteCloser, error) { return nopWriteCloser{out}, nil },
+	}
+
+	require.NoError(t, c.Copy("hello"))
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\x07\x1b\\", out.String())
+}
+
+func TestPassThroughMultiplexer(t *testing.T) {
+	seq := osc52Sequence("hi")
+	assert.Equal(t, seq, passThroughMultiplexer(seq, types.TerminalEnvironment{SSH: true}))
+	assert.Equal(t, "\x1bP"+seq+"\x1b\\", passThroughMultiplexer(seq, types.TerminalEnvironment{Screen: true}))
+}
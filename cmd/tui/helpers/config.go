@@ -15,6 +15,7 @@ import (
 type ConfigManager struct {
 	globalConfigPath string
 	localConfigPath  string
+	themesDir        string
 	config           *types.Config
 	loaded           bool
 	mu               sync.RWMutex
@@ -39,11 +40,16 @@ func NewConfigManager() (*ConfigManager, error) {
 	return &ConfigManager{
 		globalConfigPath: filepath.Join(globalConfigDir, "settings.tui.json"),
 		localConfigPath:  filepath.Join(workingDir, ".genie", "settings.tui.json"),
+		themesDir:        filepath.Join(globalConfigDir, "themes"),
 		loaded:           false,
 	}, nil
 }
 
 func (h *ConfigManager) Load() (*types.Config, error) {
+	// Custom themes are registered first so the config can select one. A
+	// broken theme file only loses that theme; the config still loads.
+	_ = presentation.LoadCustomThemes(h.themesDir)
+
 	// Start with defaults
 	config := h.GetDefaultConfig()
 
@@ -185,6 +191,11 @@ func (h *ConfigManager) DeleteLocalConfig() error {
 	return os.Remove(h.localConfigPath)
 }
 
+// ThemesDir is where custom themes are saved and loaded from
+func (h *ConfigManager) ThemesDir() string {
+	return h.themesDir
+}
+
 // GetConfig returns the current config (thread-safe with lazy loading)
 func (h *ConfigManager) GetConfig() *types.Config {
 	h.mu.RLock()
@@ -259,13 +270,26 @@ func (h *ConfigManager) GetDefaultConfig() *types.Config {
 		Theme:              "default",
 		WrapMessages:       "enabled", // Default to wrapping messages
 		ShowTimestamps:     false,
-		OutputMode:         "true",    // Default to 24-bit color with enhanced Unicode support
+		OutputMode:         "auto",    // Default to the colors the terminal supports
 		GlamourTheme:       "auto",    // Use automatic theme mapping by default
 		DiffTheme:          "auto",    // Use automatic theme mapping by default
+		DiffMode:           "unified", // Default to one column, like git diff
+		DiffCollapse:       "enabled", // Default to folding long unchanged regions
 		ShowMessagesBorder: "enabled", // Default to showing borders
 		MaxChatMessages:    500,       // Default to 500 messages for better context
 		VimMode:            false,     // Default to normal editing mode
-		EnableMouse:        "enabled", // Default to gocui mouse support enabled
+		EnableMouse:        "auto",    // Default to mouse support, except over SSH and in tmux or screen
+		Clipboard:          "auto",    // Default to OSC 52 over SSH, the system clipboard otherwise
+		InlineImages:       "auto",    // Default to thumbnails in terminals that can show images
+
+		Notifications:                "disabled", // Default to no completion notifications
+		NotificationThresholdSeconds: 30,
+
+		Accessible: "disabled", // Default to spinners, borders and symbols
+
+		UpdateCheck: "enabled", // Default to a status bar notice for new releases
+
+		ShowThoughts: "disabled", // Default to answers only
 
 		// Default message role labels
 		UserLabel:      "○",
@@ -298,22 +322,14 @@ func (h *ConfigManager) GetDefaultConfig() *types.Config {
 // GetGocuiOutputMode converts the string config to the appropriate gocui.OutputMode
 // This controls terminal color depth and Unicode character support:
 //
-//   - "true": 24-bit color (16M colors) with enhanced Unicode support (recommended)
+//   - "auto": detect the terminal's colors (default)
+//   - "true": 24-bit color (16M colors) with enhanced Unicode support
 //   - "256": 256-color mode with standard Unicode
+//   - "16": 16 ANSI colors
 //   - "normal": 8-color mode with basic character support
-//
-// Defaults to OutputTrue for the best experience on modern terminals.
 func (h *ConfigManager) GetGocuiOutputMode(outputMode string) gocui.OutputMode {
-	switch outputMode {
-	case "normal":
-		return gocui.OutputNormal // 8-color mode
-	case "256":
-		return gocui.Output256 // 256-color mode
-	case "simulator":
+	if outputMode == "simulator" {
 		return gocui.OutputSimulator // Simulator mode for testing
-	case "true", "":
-		return gocui.OutputTrue // 24-bit color (default)
-	default:
-		return gocui.OutputTrue // Default to best mode
 	}
+	return presentation.ColorProfileForMode(outputMode).GocuiOutputMode()
 }
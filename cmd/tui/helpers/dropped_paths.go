@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// DroppedPaths returns the files named by text when it is what a terminal
+// types for files dragged onto it: one or more absolute paths, each quoted,
+// escaped with backslashes or written as a file:// URL. It returns nil
+// when any part is not an existing file, so ordinary pastes are left
+// alone.
+func DroppedPaths(text string) []string {
+	words := splitDroppedWords(strings.TrimSpace(text))
+	if len(words) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(words))
+	for _, word := range words {
+		if strings.HasPrefix(word, "file://") {
+			u, err := url.Parse(word)
+			if err != nil {
+				return nil
+			}
+			word = u.Path
+		}
+		if !filepath.IsAbs(word) {
+			return nil
+		}
+		info, err := os.Stat(word)
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		paths = append(paths, filepath.Clean(word))
+	}
+	return paths
+}
+
+// splitDroppedWords splits text on unquoted spaces the way a shell would,
+// which is how terminals write the paths they drop. Backslashes escape
+// except on Windows, where they separate directories.
+func splitDroppedWords(text string) []string {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range text {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && runtime.GOOS != "windows":
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// DisplayPath returns path relative to the working directory when it is
+// inside it, and as it is otherwise
+func DisplayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDroppedPaths(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "main.go")
+	spaced := filepath.Join(dir, "My Notes.txt")
+	require.NoError(t, os.WriteFile(plain, []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(spaced, []byte("notes\n"), 0644))
+
+	escapedSpaced := filepath.Join(dir, `My\ Notes.txt`)
+	assert.Equal(t, []string{plain}, DroppedPaths(plain+" "))
+	assert.Equal(t, []string{spaced}, DroppedPaths(escapedSpaced))
+	assert.Equal(t, []string{spaced, plain}, DroppedPaths("'"+spaced+"' "+plain))
+	assert.Equal(t, []string{spaced}, DroppedPaths("file://"+filepath.ToSlash(filepath.Join(dir, "My%20Notes.txt"))))
+
+	assert.Nil(t, DroppedPaths("main.go"), "relative paths are ordinary text")
+	assert.Nil(t, DroppedPaths(dir), "directories are not attached")
+	assert.Nil(t, DroppedPaths(plain+" "+filepath.Join(dir, "missing.go")))
+	assert.Nil(t, DroppedPaths("look at "+plain))
+}
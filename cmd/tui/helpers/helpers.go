@@ -12,7 +12,7 @@ func NewHelpers() (*Helpers, error) {
 	}
 
 	return &Helpers{
-		Clipboard: NewClipboard(),
+		Clipboard: NewClipboard(configHelper),
 		Config:    configHelper,
 	}, nil
 }
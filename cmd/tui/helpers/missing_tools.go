@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/genie"
+)
+
+// FormatMissingTools describes the tools a persona requires that the
+// session runs without, one per line with the reason when known. It
+// returns "" when nothing is missing.
+func FormatMissingTools(missing []genie.MissingTool) string {
+	if len(missing) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "⚠ %d tool(s) not available; continuing without them:", len(missing))
+	for _, tool := range missing {
+		b.WriteString("\n- " + tool.Name)
+		if tool.Reason != "" {
+			b.WriteString(": " + tool.Reason)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/logging"
+)
+
+// DefaultNotificationThreshold is how long a turn must run before the
+// user is notified that it finished.
+const DefaultNotificationThreshold = 30 * time.Second
+
+// notifyTimeout bounds a desktop notification command; one still running
+// after it is killed.
+const notifyTimeout = 10 * time.Second
+
+// Notifier alerts the user that a long-running turn has completed. It
+// always rings the terminal bell and, when available, raises a desktop
+// notification through osascript (macOS) or notify-send (Linux).
+type Notifier struct {
+	out     io.Writer
+	goos    string
+	lookup  func(string) (string, error)
+	command func(name string, args ...string) error
+}
+
+// NewNotifier creates a notifier that rings the bell on stdout and uses
+// the platform notification command when one is installed.
+func NewNotifier() *Notifier {
+	return &Notifier{
+		out:     os.Stdout,
+		goos:    runtime.GOOS,
+		lookup:  exec.LookPath,
+		command: startNotificationCommand,
+	}
+}
+
+// startNotificationCommand starts name without waiting for it, so a slow
+// or hung notifier never holds up the caller. Its failure is logged.
+func startNotificationCommand(name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	cmd := exec.CommandContext(ctx, name, args...)
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return err
+	}
+	go func() {
+		defer cancel()
+		if err := cmd.Wait(); err != nil {
+			logging.GetGlobalLogger().Debug("Desktop notification failed", "command", name, "error", err)
+		}
+	}()
+	return nil
+}
+
+// ShouldNotify reports whether a turn that took elapsed is long enough to
+// warrant a notification. A non-positive threshold falls back to the default.
+func (n *Notifier) ShouldNotify(elapsed, threshold time.Duration) bool {
+	if threshold <= 0 {
+		threshold = DefaultNotificationThreshold
+	}
+	return elapsed >= threshold
+}
+
+// Notify rings the terminal bell and sends a desktop notification without
+// waiting for it to show. The bell is best effort; the returned error only
+// reflects starting the desktop notification.
+func (n *Notifier) Notify(title, message string) error {
+	if n.out != nil {
+		_, _ = io.WriteString(n.out, "\a")
+	}
+
+	name, args := n.desktopCommand(title, message)
+	if name == "" {
+		return nil
+	}
+	if _, err := n.lookup(name); err != nil {
+		return nil
+	}
+	if err := n.command(name, args...); err != nil {
+		return fmt.Errorf("failed to send desktop notification: %w", err)
+	}
+	return nil
+}
+
+func (n *Notifier) desktopCommand(title, message string) (string, []string) {
+	switch n.goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		return "osascript", []string{"-e", script}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{title, message}
+	default:
+		return "", nil
+	}
+}
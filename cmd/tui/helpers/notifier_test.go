@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNotifier(goos string, installed bool) (*Notifier, *bytes.Buffer, *[][]string) {
+	out := &bytes.Buffer{}
+	calls := &[][]string{}
+	n := &Notifier{
+		out:  out,
+		goos: goos,
+		lookup: func(name string) (string, error) {
+			if !installed {
+				return "", errors.New("not found")
+			}
+			return "/usr/bin/" + name, nil
+		},
+		command: func(name string, args ...string) error {
+			*calls = append(*calls, append([]string{name}, args...))
+			return nil
+		},
+	}
+	return n, out, calls
+}
+
+func TestNotifier_ShouldNotify(t *testing.T) {
+	n := NewNotifier()
+
+	assert.False(t, n.ShouldNotify(5*time.Second, 10*time.Second))
+	assert.True(t, n.ShouldNotify(10*time.Second, 10*time.Second))
+	assert.False(t, n.ShouldNotify(time.Second, 0), "zero threshold uses the default")
+	assert.True(t, n.ShouldNotify(DefaultNotificationThreshold, 0))
+}
+
+func TestNotifier_NotifyLinux(t *testing.T) {
+	n, out, calls := newTestNotifier("linux", true)
+
+	require.NoError(t, n.Notify("Genie", "Response ready"))
+
+	assert.Equal(t, "\a", out.String())
+	require.Len(t, *calls, 1)
+	assert.Equal(t, []string{"notify-send", "Genie", "Response ready"}, (*calls)[0])
+}
+
+func TestNotifier_NotifyDarwin(t *testing.T) {
+	n, _, calls := newTestNotifier("darwin", true)
+
+	require.NoError(t, n.Notify("Genie", `say "hi"`))
+
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "osascript", (*calls)[0][0])
+	assert.Equal(t, `display notification "say \"hi\"" with title "Genie"`, (*calls)[0][2])
+}
+
+func TestNotifier_BellOnlyWhenCommandMissing(t *testing.T) {
+	n, out, calls := newTestNotifier("linux", false)
+
+	require.NoError(t, n.Notify("Genie", "done"))
+
+	assert.Equal(t, "\a", out.String())
+	assert.Empty(t, *calls)
+}
+
+func TestStartNotificationCommand_DoesNotWait(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep is not installed")
+	}
+	start := time.Now()
+	require.NoError(t, startNotificationCommand("sleep", "5"))
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	assert.Error(t, startNotificationCommand("genie-no-such-notifier"))
+}
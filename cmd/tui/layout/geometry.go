@@ -0,0 +1,227 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// Layout presets
+const (
+	PresetDefault = "default" // todo and side panels open when needed
+	PresetSingle  = "single"  // messages only
+	PresetSplit   = "split"   // messages and side panel at equal width
+	PresetDebug   = "debug"   // debug panel open beside the messages
+)
+
+// PresetNames lists the presets :layout accepts
+var PresetNames = []string{PresetDefault, PresetSingle, PresetSplit, PresetDebug}
+
+const (
+	resizeStep     = 2  // Weight change per keyboard resize
+	minColumnWidth = 12 // Narrowest a dragged column may become
+	minInputHeight = 3  // One line of text between the borders
+)
+
+// DefaultGeometry is the layout before any preset or resizing: messages
+// twice as wide as the todo and side panels
+func DefaultGeometry() types.LayoutGeometry {
+	return types.LayoutGeometry{
+		LeftWeight:     10,
+		MessagesWeight: 20,
+		RightWeight:    10,
+		InputHeight:    3,
+	}
+}
+
+// PresetGeometry returns the panel sizes a preset starts from
+func PresetGeometry(name string) (types.LayoutGeometry, error) {
+	geometry := DefaultGeometry()
+	switch name {
+	case PresetDefault, "":
+		return geometry, nil
+	case PresetSingle, PresetDebug:
+	case PresetSplit:
+		geometry.RightWeight = geometry.MessagesWeight
+	default:
+		return geometry, fmt.Errorf("unknown layout %q", name)
+	}
+	geometry.Preset = name
+	return geometry, nil
+}
+
+// normalizeGeometry fills unset sizes with the defaults
+func normalizeGeometry(geometry types.LayoutGeometry) types.LayoutGeometry {
+	defaults := DefaultGeometry()
+	if geometry.LeftWeight <= 0 {
+		geometry.LeftWeight = defaults.LeftWeight
+	}
+	if geometry.MessagesWeight <= 0 {
+		geometry.MessagesWeight = defaults.MessagesWeight
+	}
+	if geometry.RightWeight <= 0 {
+		geometry.RightWeight = defaults.RightWeight
+	}
+	if geometry.InputHeight < minInputHeight {
+		geometry.InputHeight = defaults.InputHeight
+	}
+	return geometry
+}
+
+// Geometry returns the current panel arrangement, ready to persist
+func (lm *LayoutManager) Geometry() types.LayoutGeometry {
+	return lm.geometry
+}
+
+// SetGeometry resizes the panels
+func (lm *LayoutManager) SetGeometry(geometry types.LayoutGeometry) {
+	lm.geometry = normalizeGeometry(geometry)
+	lm.reRenderAfterResize()
+}
+
+// ApplyPreset switches to a preset's sizes and shows the panels it keeps
+// open. Split opens the side panel in its last mode; single closes the
+// todo and side panels.
+func (lm *LayoutManager) ApplyPreset(name string) error {
+	geometry, err := PresetGeometry(name)
+	if err != nil {
+		return err
+	}
+	lm.SetGeometry(geometry)
+
+	switch name {
+	case PresetSingle:
+		lm.HideRightPanel()
+		if panel := lm.panels[PanelLeft]; panel != nil {
+			panel.SetVisible(false)
+		}
+	case PresetSplit:
+		lm.ShowRightPanel(lm.rightPanelMode)
+	case PresetDebug:
+		lm.ShowRightPanel("debug")
+	}
+	return nil
+}
+
+// OpenPresetPanels opens the panels the persisted preset starts with, for
+// use at startup
+func (lm *LayoutManager) OpenPresetPanels() {
+	if lm.geometry.Preset == PresetDebug {
+		lm.ShowRightPanel("debug")
+	}
+}
+
+// ResizeSidePanel widens (positive delta) or narrows the visible side
+// panel: the right panel when open, the todo panel otherwise. It reports
+// whether a panel was resized.
+func (lm *LayoutManager) ResizeSidePanel(delta int) bool {
+	weight := &lm.geometry.LeftWeight
+	switch {
+	case lm.getVisibleRightPanel() != "":
+		weight = &lm.geometry.RightWeight
+	case !lm.isPanelVisible(PanelLeft):
+		return false
+	}
+	*weight = min(max(*weight+delta*resizeStep, resizeStep), lm.geometry.MessagesWeight*4)
+	lm.reRenderAfterResize()
+	return true
+}
+
+// ResizeInput grows or shrinks the input panel by delta rows, leaving at
+// least half the screen to the panels above it
+func (lm *LayoutManager) ResizeInput(delta int) {
+	lm.setInputHeight(lm.geometry.InputHeight + delta)
+}
+
+func (lm *LayoutManager) setInputHeight(height int) {
+	maxHeight := max(lm.lastHeight/2, minInputHeight)
+	lm.geometry.InputHeight = min(max(height, minInputHeight), maxHeight)
+	lm.reRenderAfterResize()
+}
+
+// Borders that can be dragged with the mouse
+const (
+	dragNone = iota
+	dragLeftBorder
+	dragRightBorder
+	dragInputBorder
+)
+
+// StartDrag begins resizing when the mouse is pressed on a border between
+// panels, and reports whether it was
+func (lm *LayoutManager) StartDrag(x, y int) bool {
+	lm.dragging = lm.borderAt(x, y)
+	return lm.dragging != dragNone
+}
+
+// EndDrag moves the dragged border to where the mouse was released and
+// reports whether the geometry changed
+func (lm *LayoutManager) EndDrag(x, y int) bool {
+	border := lm.dragging
+	lm.dragging = dragNone
+
+	messages := lm.panels[PanelMessages]
+	if messages == nil {
+		return false
+	}
+	m := messages.Dimensions
+
+	switch border {
+	case dragRightBorder:
+		right := lm.panels[lm.getVisibleRightPanel()]
+		if right == nil {
+			return false
+		}
+		x = min(max(x, m.X0+minColumnWidth), right.Dimensions.X1-minColumnWidth)
+		lm.geometry.RightWeight = scaleWeight(lm.geometry.MessagesWeight, right.Dimensions.X1-x, x-m.X0)
+		lm.reRenderAfterResize()
+	case dragLeftBorder:
+		left := lm.panels[PanelLeft]
+		if left == nil {
+			return false
+		}
+		x = min(max(x, left.Dimensions.X0+minColumnWidth), m.X1-minColumnWidth)
+		lm.geometry.LeftWeight = scaleWeight(lm.geometry.MessagesWeight, x-left.Dimensions.X0, m.X1-x)
+		lm.reRenderAfterResize()
+	case dragInputBorder:
+		input := lm.panels[PanelInput]
+		if input == nil {
+			return false
+		}
+		lm.setInputHeight(input.Dimensions.Y1 - y + 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// scaleWeight returns the weight that gives a column width when a column
+// of otherWidth has otherWeight
+func scaleWeight(otherWeight, width, otherWidth int) int {
+	if otherWidth <= 0 {
+		return otherWeight
+	}
+	return max(otherWeight*width/otherWidth, 1)
+}
+
+// borderAt finds the panel border under a mouse position. Adjacent panels
+// each draw their own frame, so a border is two columns (or rows) wide.
+func (lm *LayoutManager) borderAt(x, y int) int {
+	messages := lm.panels[PanelMessages]
+	if messages == nil || !messages.IsVisible() {
+		return dragNone
+	}
+	m := messages.Dimensions
+	if y >= m.Y0 && y <= m.Y1 {
+		if right := lm.getVisibleRightPanel(); right != "" && x >= m.X1 && x <= lm.panels[right].Dimensions.X0 {
+			return dragRightBorder
+		}
+		if lm.isPanelVisible(PanelLeft) && x >= lm.panels[PanelLeft].Dimensions.X1 && x <= m.X0 {
+			return dragLeftBorder
+		}
+	}
+	if input := lm.panels[PanelInput]; input != nil && input.IsVisible() && (y == input.Dimensions.Y0 || y == m.Y1) {
+		return dragInputBorder
+	}
+	return dragNone
+}
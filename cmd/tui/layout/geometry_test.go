@@ -0,0 +1,100 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/awesome-gocui/gocui"
+	"github.com/jesseduffield/lazycore/pkg/boxlayout"
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLayoutManager(t *testing.T, geometry types.LayoutGeometry) *LayoutManager {
+	t.Helper()
+	g, err := gocui.NewGui(gocui.OutputSimulator, true)
+	require.NoError(t, err)
+	t.Cleanup(g.Close)
+
+	lm := NewLayoutManager(g, &LayoutConfig{Geometry: geometry})
+	lm.lastWidth, lm.lastHeight = 90, 40
+	return lm
+}
+
+// addPanel places a visible panel at fixed dimensions, as a layout pass would
+func addPanel(lm *LayoutManager, name string, dims boxlayout.Dimensions) {
+	lm.panels[name] = &Panel{Name: name, Visible: true, Dimensions: dims}
+}
+
+func TestPresetGeometry(t *testing.T) {
+	geometry, err := PresetGeometry(PresetDefault)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultGeometry(), geometry)
+
+	geometry, err = PresetGeometry(PresetSplit)
+	require.NoError(t, err)
+	assert.Equal(t, PresetSplit, geometry.Preset)
+	assert.Equal(t, geometry.MessagesWeight, geometry.RightWeight)
+
+	_, err = PresetGeometry("quad")
+	assert.Error(t, err)
+}
+
+func TestNewLayoutManagerFillsUnsetSizes(t *testing.T) {
+	lm := newTestLayoutManager(t, types.LayoutGeometry{Preset: PresetDebug, RightWeight: 14})
+
+	geometry := lm.Geometry()
+	assert.Equal(t, PresetDebug, geometry.Preset)
+	assert.Equal(t, 14, geometry.RightWeight)
+	assert.Equal(t, DefaultGeometry().MessagesWeight, geometry.MessagesWeight)
+	assert.Equal(t, DefaultGeometry().InputHeight, geometry.InputHeight)
+}
+
+func TestResizeSidePanel(t *testing.T) {
+	lm := newTestLayoutManager(t, types.LayoutGeometry{})
+	assert.False(t, lm.ResizeSidePanel(1), "nothing to resize without a side panel")
+
+	addPanel(lm, PanelLeft, boxlayout.Dimensions{})
+	require.True(t, lm.ResizeSidePanel(1))
+	assert.Equal(t, DefaultGeometry().LeftWeight+resizeStep, lm.Geometry().LeftWeight)
+
+	addPanel(lm, PanelDebug, boxlayout.Dimensions{})
+	require.True(t, lm.ResizeSidePanel(-1))
+	assert.Equal(t, DefaultGeometry().RightWeight-resizeStep, lm.Geometry().RightWeight, "the right panel wins when open")
+}
+
+func TestResizeInputStaysWithinBounds(t *testing.T) {
+	lm := newTestLayoutManager(t, types.LayoutGeometry{})
+
+	lm.ResizeInput(-5)
+	assert.Equal(t, minInputHeight, lm.Geometry().InputHeight)
+
+	lm.ResizeInput(100)
+	assert.Equal(t, 20, lm.Geometry().InputHeight, "at most half the screen")
+}
+
+func TestDragRightBorder(t *testing.T) {
+	lm := newTestLayoutManager(t, types.LayoutGeometry{})
+	addPanel(lm, PanelMessages, boxlayout.Dimensions{X0: 0, X1: 59, Y0: 0, Y1: 29})
+	addPanel(lm, PanelDebug, boxlayout.Dimensions{X0: 60, X1: 89, Y0: 0, Y1: 29})
+	addPanel(lm, PanelInput, boxlayout.Dimensions{X0: 0, X1: 89, Y0: 30, Y1: 32})
+
+	assert.False(t, lm.StartDrag(30, 10), "inside a panel is not a border")
+
+	require.True(t, lm.StartDrag(60, 10))
+	require.True(t, lm.EndDrag(45, 10))
+	// Messages 45 columns wide at weight 20 leaves 44 columns for the panel
+	assert.Equal(t, 19, lm.Geometry().RightWeight)
+
+	assert.False(t, lm.EndDrag(45, 10), "a drag ends once")
+}
+
+func TestDragInputBorder(t *testing.T) {
+	lm := newTestLayoutManager(t, types.LayoutGeometry{})
+	addPanel(lm, PanelMessages, boxlayout.Dimensions{X0: 0, X1: 89, Y0: 0, Y1: 29})
+	addPanel(lm, PanelInput, boxlayout.Dimensions{X0: 0, X1: 89, Y0: 30, Y1: 32})
+
+	require.True(t, lm.StartDrag(10, 30))
+	require.True(t, lm.EndDrag(10, 25))
+	assert.Equal(t, 8, lm.Geometry().InputHeight)
+}
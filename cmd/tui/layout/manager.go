@@ -1,6 +1,8 @@
 package layout
 
 import (
+	"time"
+
 	"github.com/awesome-gocui/gocui"
 	"github.com/jesseduffield/lazycore/pkg/boxlayout"
 	"github.com/kcaldas/genie/cmd/tui/types"
@@ -8,13 +10,14 @@ import (
 
 // Panel name constants - using semantic names
 const (
-	PanelStatus     = "status"      // top panel
-	PanelLeft       = "left"        // left panel (unused currently)
-	PanelMessages   = "messages"    // center panel
-	PanelDebug      = "debug"       // right panel (debug component)
-	PanelTextViewer = "text-viewer" // right panel (text viewer component)
-	PanelDiffViewer = "diff-viewer" // right panel (diff viewer component)
-	PanelInput      = "input"       // bottom panel
+	PanelStatus      = "status"       // top panel
+	PanelLeft        = "left"         // left panel (todo list)
+	PanelMessages    = "messages"     // center panel
+	PanelDebug       = "debug"        // right panel (debug component)
+	PanelTextViewer  = "text-viewer"  // right panel (text viewer component)
+	PanelDiffViewer  = "diff-viewer"  // right panel (diff viewer component)
+	PanelThemeEditor = "theme-editor" // right panel (theme editor component)
+	PanelInput       = "input"        // bottom panel
 )
 
 // Navigation order for TAB cycling - excludes status panel from navigation
@@ -24,6 +27,7 @@ var defaultNavigationOrder = []string{
 	PanelDebug,
 	PanelTextViewer,
 	PanelDiffViewer,
+	PanelThemeEditor,
 	PanelLeft,
 }
 
@@ -36,26 +40,31 @@ type LayoutManager struct {
 
 	// Right panel state management
 	rightPanelVisible bool
-	rightPanelMode    string // "debug", "text-viewer", or "diff-viewer"
+	rightPanelMode    string // "debug", "text-viewer", "diff-viewer" or "theme-editor"
 	rightPanelZoomed  bool   // Whether right panel is zoomed (takes most of the space)
+
+	// Panel sizes, and the border being dragged with the mouse
+	geometry types.LayoutGeometry
+	dragging int
+
+	// Resize handling: the pending re-render, and the too small screen
+	resizeTimer          *time.Timer
+	tooSmallShown        bool
+	cursorBeforeTooSmall bool
 }
 
 type LayoutConfig struct {
-	MessagesWeight int  // Weight for messages panel
-	InputHeight    int  // Fixed height for input panel
-	DebugWeight    int  // Weight for debug panel when visible
-	StatusHeight   int  // Fixed height for status panel
-	ShowSidebar    bool // Legacy field - kept for compatibility
-	CompactMode    bool // Compact mode toggle
-	MinPanelWidth  int  // Minimum panel width
-	MinPanelHeight int  // Minimum panel height
+	Geometry       types.LayoutGeometry // Panel sizes and the preset they came from
+	StatusHeight   int                  // Fixed height for status panel
+	ShowSidebar    bool                 // Legacy field - kept for compatibility
+	CompactMode    bool                 // Compact mode toggle
+	MinPanelWidth  int                  // Minimum panel width
+	MinPanelHeight int                  // Minimum panel height
 }
 
 func NewDefaultLayoutConfig(config *types.Config) *LayoutConfig {
 	return &LayoutConfig{
-		MessagesWeight: 3,                                    // Messages panel weight (main content)
-		InputHeight:    4,                                    // Input panel height
-		DebugWeight:    1,                                    // Debug panel weight when shown
+		Geometry:       config.Layout.Geometry,               // Persisted panel sizes
 		StatusHeight:   2,                                    // Status bar height
 		ShowSidebar:    config.Layout.IsShowSidebarEnabled(), // Keep legacy field
 		CompactMode:    config.Layout.CompactMode,            // Keep compact mode
@@ -74,6 +83,8 @@ func NewLayoutManager(gui *gocui.Gui, config *LayoutConfig) *LayoutManager {
 		rightPanelVisible: false,
 		rightPanelMode:    "debug", // Default to debug mode
 		rightPanelZoomed:  false,   // Default to normal size
+
+		geometry: normalizeGeometry(config.Geometry),
 	}
 }
 
@@ -86,12 +97,19 @@ func (lm *LayoutManager) Layout(g *gocui.Gui) error {
 	}
 
 	// Detect resize and trigger message re-render if needed
+	firstLayout := lm.lastWidth == 0 && lm.lastHeight == 0
 	sizeChanged := (lm.lastWidth != maxX || lm.lastHeight != maxY)
 	if sizeChanged {
 		lm.lastWidth = maxX
 		lm.lastHeight = maxY
 	}
 
+	// Panels squeezed below their frames make gocui refuse the views
+	if tooSmall(maxX, maxY) {
+		return lm.showTooSmall(maxX, maxY)
+	}
+	lm.hideTooSmall()
+
 	rootBox := lm.buildLayoutTree()
 	panelDimensions := boxlayout.ArrangeWindows(rootBox, 0, 0, maxX, maxY)
 
@@ -107,11 +125,15 @@ func (lm *LayoutManager) Layout(g *gocui.Gui) error {
 		}
 	}
 
-	// Re-render messages only on size change to handle wrapping
-	if sizeChanged {
+	// Re-render messages only on size change to handle wrapping, once a
+	// burst of resizes is over
+	switch {
+	case firstLayout:
 		if messagesPanel := lm.panels[PanelMessages]; messagesPanel != nil {
 			messagesPanel.Render()
 		}
+	case sizeChanged:
+		lm.scheduleResizeRender()
 	}
 
 	return nil
@@ -132,7 +154,7 @@ func (lm *LayoutManager) buildLayoutTree() *boxlayout.Box {
 
 	// Add input panel if visible
 	if lm.isPanelVisible(PanelInput) {
-		panels = append(panels, lm.createPanelBox(PanelInput, 3, 0))
+		panels = append(panels, lm.createPanelBox(PanelInput, lm.geometry.InputHeight, 0))
 	}
 
 	// Add status panel if visible
@@ -158,12 +180,12 @@ func (lm *LayoutManager) buildCenterColumns() []*boxlayout.Box {
 
 	// Left panel
 	if lm.isPanelVisible(PanelLeft) {
-		columns = append(columns, lm.createPanelBox(PanelLeft, 0, 1))
+		columns = append(columns, lm.createPanelBox(PanelLeft, 0, lm.geometry.LeftWeight))
 	}
 
 	// Messages panel (main content) - adjust weight based on zoom state
 	if lm.isPanelVisible(PanelMessages) {
-		messagesWeight := 2 // Default weight
+		messagesWeight := lm.geometry.MessagesWeight
 		if lm.rightPanelZoomed {
 			messagesWeight = 1 // Reduced weight when right panel is zoomed
 		}
@@ -172,7 +194,7 @@ func (lm *LayoutManager) buildCenterColumns() []*boxlayout.Box {
 
 	// Right panel (only one visible at a time) - adjust weight based on zoom state
 	if rightPanel := lm.getVisibleRightPanel(); rightPanel != "" {
-		rightPanelWeight := 1 // Default weight
+		rightPanelWeight := lm.geometry.RightWeight
 		if lm.rightPanelZoomed {
 			rightPanelWeight = 4 // Much larger weight when zoomed
 		}
@@ -265,7 +287,7 @@ func (lm *LayoutManager) isPanelVisible(panelName string) bool {
 
 // getVisibleRightPanel returns the name of the visible right panel (priority order)
 func (lm *LayoutManager) getVisibleRightPanel() string {
-	rightPanels := []string{PanelDebug, PanelTextViewer, PanelDiffViewer}
+	rightPanels := []string{PanelDebug, PanelTextViewer, PanelDiffViewer, PanelThemeEditor}
 	for _, panelName := range rightPanels {
 		if lm.isPanelVisible(panelName) {
 			return panelName
@@ -428,7 +450,7 @@ func (lm *LayoutManager) ShowRightPanel(mode string) {
 	lm.rightPanelMode = mode
 
 	// Hide all right panel components first
-	rightPanels := []string{PanelDebug, PanelTextViewer, PanelDiffViewer}
+	rightPanels := []string{PanelDebug, PanelTextViewer, PanelDiffViewer, PanelThemeEditor}
 	for _, panelName := range rightPanels {
 		if panel := lm.panels[panelName]; panel != nil {
 			panel.SetVisible(false)
@@ -444,13 +466,15 @@ func (lm *LayoutManager) ShowRightPanel(mode string) {
 		targetPanel = PanelTextViewer
 	case "diff-viewer":
 		targetPanel = PanelDiffViewer
+	case "theme-editor":
+		targetPanel = PanelThemeEditor
 	}
 
 	if targetPanel != "" {
 		if panel := lm.panels[targetPanel]; panel != nil {
 			panel.SetVisible(true)
 			// Trigger render to update content
-			if mode == "diff-viewer" {
+			if mode == "diff-viewer" || mode == "theme-editor" {
 				panel.Render()
 			}
 		}
@@ -462,7 +486,7 @@ func (lm *LayoutManager) HideRightPanel() {
 	lm.rightPanelVisible = false
 
 	// Hide all right panel components
-	rightPanels := []string{PanelDebug, PanelTextViewer, PanelDiffViewer}
+	rightPanels := []string{PanelDebug, PanelTextViewer, PanelDiffViewer, PanelThemeEditor}
 	for _, panelName := range rightPanels {
 		if panel := lm.panels[panelName]; panel != nil {
 			panel.SetVisible(false)
@@ -534,7 +558,7 @@ func (lm *LayoutManager) ZoomRightPanel() {
 		})
 
 		// Re-render components that need width updates
-		lm.reRenderAfterZoom()
+		lm.reRenderAfterResize()
 	}
 }
 
@@ -548,7 +572,7 @@ func (lm *LayoutManager) UnzoomRightPanel() {
 		})
 
 		// Re-render components that need width updates
-		lm.reRenderAfterZoom()
+		lm.reRenderAfterResize()
 	}
 }
 
@@ -561,23 +585,23 @@ func (lm *LayoutManager) ToggleRightPanelZoom() {
 	}
 }
 
-// reRenderAfterZoom re-renders components that need width updates after zoom changes
-func (lm *LayoutManager) reRenderAfterZoom() {
+// reRenderAfterResize re-renders components that need width updates after zoom or geometry changes
+func (lm *LayoutManager) reRenderAfterResize() {
 	// Use GUI update to ensure rendering happens after layout changes
 	lm.gui.Update(func(g *gocui.Gui) error {
+		// Nothing is laid out while the too small screen is up
+		if tooSmall(lm.lastWidth, lm.lastHeight) {
+			return nil
+		}
+
 		// Re-render help if text-viewer is visible to update for new width
 		if textViewerPanel := lm.panels[PanelTextViewer]; textViewerPanel != nil && textViewerPanel.IsVisible() {
 			textViewerPanel.Render()
 		}
 
-		// Re-render messages and scroll to bottom after zoom
+		// Re-render messages; they keep their scroll position while re-wrapping
 		if messagesPanel := lm.panels[PanelMessages]; messagesPanel != nil {
 			messagesPanel.Render()
-
-			// Cast to scrollable interface and scroll to bottom
-			if scrollable, ok := messagesPanel.Component.(interface{ ScrollToBottom() error }); ok {
-				scrollable.ScrollToBottom()
-			}
 		}
 		return nil
 	})
@@ -0,0 +1,80 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+const (
+	// MinWidth and MinHeight are the smallest terminal the panels fit in;
+	// below them the layout shows the too small screen instead
+	MinWidth  = 40
+	MinHeight = 10
+
+	// resizeSettle is how long the terminal size must hold still before
+	// the messages are wrapped again, so dragging a window edge does not
+	// re-render the transcript on every step
+	resizeSettle = 100 * time.Millisecond
+
+	tooSmallView = "too-small"
+)
+
+// tooSmall reports whether a terminal of width by height cannot fit the
+// panels
+func tooSmall(width, height int) bool {
+	return width < MinWidth || height < MinHeight
+}
+
+// scheduleResizeRender re-renders the components that wrap to their width
+// once the terminal stops changing size. Layout runs on the main loop, so
+// the timer needs no locking.
+func (lm *LayoutManager) scheduleResizeRender() {
+	if lm.resizeTimer != nil {
+		lm.resizeTimer.Stop()
+	}
+	lm.resizeTimer = time.AfterFunc(resizeSettle, lm.reRenderAfterResize)
+}
+
+// showTooSmall covers the screen with a notice asking for a bigger
+// terminal. The panels stay where they were, underneath, until the
+// terminal is big enough to lay them out again.
+func (lm *LayoutManager) showTooSmall(width, height int) error {
+	view, err := lm.gui.SetView(tooSmallView, -1, -1, width, height, 0)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	if !lm.tooSmallShown {
+		lm.tooSmallShown = true
+		lm.cursorBeforeTooSmall = lm.gui.Cursor
+	}
+	lm.gui.Cursor = false
+	view.Frame = false
+	if _, err := lm.gui.SetViewOnTop(tooSmallView); err != nil {
+		return err
+	}
+
+	view.Clear()
+	lines := []string{
+		"Terminal too small",
+		fmt.Sprintf("%d×%d, Genie needs %d×%d", width, height, MinWidth, MinHeight),
+	}
+	fmt.Fprint(view, strings.Repeat("\n", max((height-len(lines))/2, 0)))
+	for _, line := range lines {
+		pad := max((width-len([]rune(line)))/2, 0)
+		fmt.Fprintln(view, strings.Repeat(" ", pad)+line)
+	}
+	return nil
+}
+
+// hideTooSmall removes the notice once the terminal is big enough again
+func (lm *LayoutManager) hideTooSmall() {
+	if !lm.tooSmallShown {
+		return
+	}
+	lm.tooSmallShown = false
+	lm.gui.Cursor = lm.cursorBeforeTooSmall
+	lm.gui.DeleteView(tooSmallView)
+}
@@ -0,0 +1,42 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/awesome-gocui/gocui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTooSmallScreen(t *testing.T) {
+	lm := newTestLayoutManager(t, DefaultGeometry())
+	lm.gui.Cursor = true
+
+	require.NoError(t, lm.showTooSmall(30, 8))
+	view, err := lm.gui.View(tooSmallView)
+	require.NoError(t, err)
+	assert.Contains(t, view.Buffer(), "Terminal too small")
+	assert.Contains(t, view.Buffer(), "30×8, Genie needs 40×10")
+	assert.False(t, lm.gui.Cursor)
+
+	// Shrinking further keeps the cursor state from before the first notice
+	require.NoError(t, lm.showTooSmall(20, 5))
+	lm.hideTooSmall()
+	assert.True(t, lm.gui.Cursor)
+	_, err = lm.gui.View(tooSmallView)
+	assert.ErrorIs(t, err, gocui.ErrUnknownView)
+}
+
+func TestLayout_DebouncesResizeRender(t *testing.T) {
+	lm := newTestLayoutManager(t, DefaultGeometry())
+	// The test manager starts at 90×40, so each pass sees a new size
+	require.NoError(t, lm.Layout(lm.gui))
+	first := lm.resizeTimer
+	require.NotNil(t, first)
+	lm.lastWidth--
+	require.NoError(t, lm.Layout(lm.gui))
+
+	assert.False(t, first.Stop(), "the second resize cancels the first render")
+	assert.True(t, lm.resizeTimer.Stop(), "only the last render is pending")
+	assert.False(t, lm.tooSmallShown)
+}
@@ -22,6 +22,8 @@ func NewLayoutBuilder(
 	textViewerComponent *component.TextViewerComponent,
 	diffViewerComponent *component.DiffViewerComponent,
 	debugComponent *component.DebugComponent,
+	todoPanelComponent *component.TodoPanelComponent,
+	themeEditorComponent *component.ThemeEditorComponent,
 ) *LayoutBuilder {
 	// Create layout config and manager
 	config := configManager.GetConfig()
@@ -41,6 +43,8 @@ func NewLayoutBuilder(
 		textViewerComponent,
 		diffViewerComponent,
 		debugComponent,
+		todoPanelComponent,
+		themeEditorComponent,
 	)
 
 	// Setup status sub-components
@@ -57,14 +61,18 @@ func (lb *LayoutBuilder) setupComponents(
 	textViewerComponent *component.TextViewerComponent,
 	diffViewerComponent *component.DiffViewerComponent,
 	debugComponent *component.DebugComponent,
+	todoPanelComponent *component.TodoPanelComponent,
+	themeEditorComponent *component.ThemeEditorComponent,
 ) {
 	// Map components using semantic names
-	lb.layoutManager.SetComponent("messages", messagesComponent)      // messages in center
-	lb.layoutManager.SetComponent("input", inputComponent)            // input at bottom
-	lb.layoutManager.SetComponent("text-viewer", textViewerComponent) // text viewer on right side
-	lb.layoutManager.SetComponent("diff-viewer", diffViewerComponent) // diff viewer on right side
-	lb.layoutManager.SetComponent("status", statusComponent)          // status at top
-	lb.layoutManager.SetComponent("debug", debugComponent)            // debug on right side
+	lb.layoutManager.SetComponent("messages", messagesComponent)        // messages in center
+	lb.layoutManager.SetComponent("input", inputComponent)              // input at bottom
+	lb.layoutManager.SetComponent("text-viewer", textViewerComponent)   // text viewer on right side
+	lb.layoutManager.SetComponent("diff-viewer", diffViewerComponent)   // diff viewer on right side
+	lb.layoutManager.SetComponent("status", statusComponent)            // status at top
+	lb.layoutManager.SetComponent("debug", debugComponent)              // debug on right side
+	lb.layoutManager.SetComponent("left", todoPanelComponent)           // todo list on left side
+	lb.layoutManager.SetComponent("theme-editor", themeEditorComponent) // theme editor on right side
 }
 
 // setupStatusSubComponents registers the status bar sub-components
@@ -0,0 +1,249 @@
+package presentation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/mattn/go-runewidth"
+)
+
+// SanitizeANSI prepares text Genie did not write, such as tool output, for
+// a view. Colored output keeps its colors in the theme's terms: red, green,
+// yellow, blue, magenta, cyan and grey become the theme's error, success,
+// warning, primary, tertiary, secondary and muted colors, and 256 and true
+// colors are fitted to the terminal's color profile. Bold, dim, italic and
+// underline are kept; backgrounds are dropped so the theme's stays. Every
+// other escape sequence (cursor movement, screen clears, window titles,
+// hyperlinks) and control character is removed, since gocui would print
+// them as text. A reset restores base, the color the text is shown in.
+func SanitizeANSI(text string, theme *types.Theme, base string) string {
+	return sanitizeANSI(text, func(params []int) string {
+		return themeSGR(params, theme, base)
+	})
+}
+
+// StripANSI removes every escape sequence and control character from text
+// Genie did not write, leaving plain text for copying.
+func StripANSI(text string) string {
+	return sanitizeANSI(text, func([]int) string { return "" })
+}
+
+// sanitizeANSI removes escape sequences and control characters from text,
+// replacing each SGR sequence with what sgr returns for its parameters.
+// Carriage returns keep what a terminal would show, the text after the
+// last one on a line, and a backspace erases the character before it.
+func sanitizeANSI(text string, sgr func(params []int) string) string {
+	if !strings.ContainsFunc(text, isControl) {
+		return text
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var out []rune
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\x1b':
+			end, final := escapeSequenceEnd(runes, i)
+			if final == 'm' {
+				out = append(out, []rune(sgr(sgrParams(string(runes[i+2:end-1]))))...)
+			}
+			i = end - 1
+		case r == '\r':
+			// Overwritten like a progress bar redrawing itself
+			start := len(out)
+			for start > 0 && out[start-1] != '\n' {
+				start--
+			}
+			if i+1 < len(runes) && runes[i+1] != '\n' {
+				out = out[:start]
+			}
+		case r == '\b':
+			if len(out) > 0 && out[len(out)-1] != '\n' {
+				out = out[:len(out)-1]
+			}
+		case r == '\n' || r == '\t' || !isControl(r):
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+// isControl reports whether r is an escape or a control character other
+// than newline and tab
+func isControl(r rune) bool {
+	return (r < 0x20 && r != '\n' && r != '\t') || (r >= 0x7f && r <= 0x9f)
+}
+
+// escapeSequenceEnd returns the index just past the escape sequence at
+// start and, for CSI sequences, their final character
+func escapeSequenceEnd(runes []rune, start int) (int, rune) {
+	i := start + 1
+	if i >= len(runes) {
+		return i, 0
+	}
+	switch runes[i] {
+	case '[':
+		// CSI: parameters and intermediates, then a final byte
+		for i++; i < len(runes); i++ {
+			if runes[i] >= 0x40 && runes[i] <= 0x7e {
+				return i + 1, runes[i]
+			}
+		}
+		return len(runes), 0
+	case ']', 'P', '_', '^':
+		// OSC and other strings end at BEL or ESC \
+		for i++; i < len(runes); i++ {
+			if runes[i] == '\a' {
+				return i + 1, 0
+			}
+			if runes[i] == '\x1b' && i+1 < len(runes) && runes[i+1] == '\\' {
+				return i + 2, 0
+			}
+		}
+		return len(runes), 0
+	case '(', ')', '*', '+', '#', '%':
+		// Character set designations take one more character
+		return min(i+2, len(runes)), 0
+	}
+	return i + 1, 0
+}
+
+// sgrParams parses the parameters of an SGR sequence; empty ones are 0
+func sgrParams(params string) []int {
+	var parsed []int
+	for _, param := range strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' }) {
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return nil
+		}
+		parsed = append(parsed, n)
+	}
+	if len(parsed) == 0 {
+		return []int{0}
+	}
+	return parsed
+}
+
+// themeSGR rewrites one SGR sequence in theme colors, as escapes gocui
+// parses: one attribute per sequence
+func themeSGR(params []int, theme *types.Theme, base string) string {
+	var out strings.Builder
+	for i := 0; i < len(params); i++ {
+		switch p := params[i]; {
+		case p == 0:
+			out.WriteString(ansiReset + base)
+		case p >= 1 && p <= 4:
+			fmt.Fprintf(&out, "\033[%dm", p)
+		case p == 39:
+			if base != "" {
+				out.WriteString(base)
+			} else {
+				out.WriteString("\033[39m")
+			}
+		case p >= 30 && p <= 37:
+			out.WriteString(themeANSIColor(p-30, theme, base))
+		case p >= 90 && p <= 97:
+			out.WriteString(themeANSIColor(p-90+8, theme, base))
+		case p == 38 || p == 48:
+			hex, skip := extendedColor(params[i+1:])
+			if p == 38 && hex != "" {
+				out.WriteString(themeHexColor(hex, base))
+			}
+			i += skip
+		}
+		// Backgrounds and the remaining attributes are dropped
+	}
+	return out.String()
+}
+
+// themeANSIColor returns the theme's version of ANSI color index (0-15)
+func themeANSIColor(index int, theme *types.Theme, base string) string {
+	var hex string
+	switch index % 8 {
+	case 1:
+		hex = theme.Error
+	case 2:
+		hex = theme.Success
+	case 3:
+		hex = theme.Warning
+	case 4:
+		hex = theme.Primary
+	case 5:
+		hex = theme.Tertiary
+	case 6:
+		hex = theme.Secondary
+	}
+	if index == 8 {
+		// Bright black is the grey of secondary output
+		hex = theme.Muted
+	}
+	return themeHexColor(hex, base)
+}
+
+// themeHexColor sets hex in the active color profile, or falls back to
+// base when the theme has no color for it
+func themeHexColor(hex, base string) string {
+	if color := ConvertColorToAnsi(hex); color != "" {
+		return color
+	}
+	if base != "" {
+		return base
+	}
+	return "\033[39m"
+}
+
+// extendedColor reads the color of a 38 or 48 parameter: 5;n from the
+// 256-color palette or 2;r;g;b. It returns the color as hex, empty for
+// the 16 basic colors the theme does not cover, and how many parameters
+// it used.
+func extendedColor(params []int) (string, int) {
+	if len(params) >= 2 && params[0] == 5 {
+		n := params[1]
+		switch {
+		case n < 16:
+			return "", 2
+		case n < 232:
+			n -= 16
+			return fmt.Sprintf("#%02x%02x%02x", cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]), 2
+		case n < 256:
+			gray := 8 + 10*(n-232)
+			return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray), 2
+		}
+		return "", 2
+	}
+	if len(params) >= 4 && params[0] == 2 {
+		clamp := func(v int) int { return min(max(v, 0), 255) }
+		return fmt.Sprintf("#%02x%02x%02x", clamp(params[1]), clamp(params[2]), clamp(params[3])), 4
+	}
+	return "", len(params)
+}
+
+// truncateANSI shortens text with SGR sequences to width cells, ending it
+// with tail when it is cut. Sequences take no space and are never split.
+func truncateANSI(text string, width int, tail string) string {
+	if runewidth.StringWidth(StripANSI(text)) <= width {
+		return text
+	}
+	limit := width - runewidth.StringWidth(tail)
+	var out strings.Builder
+	used := 0
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\x1b' {
+			end, _ := escapeSequenceEnd(runes, i)
+			out.WriteString(string(runes[i:end]))
+			i = end - 1
+			continue
+		}
+		w := runewidth.RuneWidth(runes[i])
+		if used+w > limit {
+			break
+		}
+		out.WriteRune(runes[i])
+		used += w
+	}
+	return out.String() + tail
+}
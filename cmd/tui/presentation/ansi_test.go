@@ -0,0 +1,50 @@
+package presentation
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain", text: "ok\tdone\n", want: "ok\tdone\n"},
+		{name: "colors", text: "\x1b[1;32mPASS\x1b[0m TestFoo", want: "PASS TestFoo"},
+		{name: "cursor and screen", text: "\x1b[2J\x1b[Hhello\x1b[K", want: "hello"},
+		{name: "title and hyperlink", text: "\x1b]0;title\a\x1b]8;;https://x.dev\x1b\\link\x1b]8;;\x1b\\", want: "link"},
+		{name: "charset", text: "\x1b(Bok", want: "ok"},
+		{name: "progress", text: "10%\r50%\r100%\nnext", want: "100%\nnext"},
+		{name: "crlf", text: "a\r\nb", want: "a\nb"},
+		{name: "backspace", text: "ab\bc", want: "ac"},
+		{name: "bell and nul", text: "a\a\x00b", want: "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripANSI(tt.text))
+		})
+	}
+}
+
+func TestSanitizeANSI_ThemeColors(t *testing.T) {
+	SetColorProfile(ColorProfileTrueColor)
+	theme := &types.Theme{Error: "#ff0000", Success: "#00ff00", Muted: "#808080"}
+	base := "\x1b[38;2;1;2;3m"
+
+	got := SanitizeANSI("\x1b[31mFAIL\x1b[0m \x1b[1;92mok\x1b[m \x1b[90mskip\x1b[39m", theme, base)
+	assert.Equal(t, "\x1b[38;2;255;0;0mFAIL\x1b[0m"+base+" \x1b[1m\x1b[38;2;0;255;0mok\x1b[0m"+base+" \x1b[38;2;128;128;128mskip"+base, got)
+
+	// Colors the theme lacks fall back to base; backgrounds are dropped
+	assert.Equal(t, base+"x", SanitizeANSI("\x1b[35;44mx", theme, base))
+	assert.Equal(t, "\x1b[38;2;255;135;0mx", SanitizeANSI("\x1b[48;5;21;38;5;208mx", theme, base))
+	assert.Equal(t, "\x1b[38;2;10;20;30mx", SanitizeANSI("\x1b[38;2;10;20;30mx", theme, base))
+}
+
+func TestTruncateANSI(t *testing.T) {
+	assert.Equal(t, "\x1b[31mabc\x1b[0m", truncateANSI("\x1b[31mabc\x1b[0m", 3, "..."))
+	assert.Equal(t, "\x1b[31mab\x1b[0m...", truncateANSI("\x1b[31mab\x1b[0mcdef", 5, "..."))
+}
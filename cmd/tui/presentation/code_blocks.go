@@ -0,0 +1,188 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/quick"
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// CodeBlock is a fenced code block found in a chat message. Index is
+// 1-based and counts blocks across the whole transcript, matching the label
+// the messages view draws above each block.
+type CodeBlock struct {
+	Index    int
+	Language string
+	Code     string
+}
+
+// contentSegment is either plain markdown text or a fenced code block.
+type contentSegment struct {
+	text  string
+	block *CodeBlock
+}
+
+// HasCodeBlockLabels reports whether a message's code blocks are rendered
+// with index labels. Only markdown messages are, so only their blocks count
+// towards the numbering.
+func HasCodeBlockLabels(msg types.Message) bool {
+	return msg.ContentType == "markdown"
+}
+
+// CodeBlocks returns the labelled code blocks of messages in display order.
+func CodeBlocks(messages []types.Message) []CodeBlock {
+	var blocks []CodeBlock
+	for _, msg := range messages {
+		if !HasCodeBlockLabels(msg) {
+			continue
+		}
+		for _, segment := range splitCodeBlocks(msg.Content, len(blocks)+1) {
+			if segment.block != nil {
+				blocks = append(blocks, *segment.block)
+			}
+		}
+	}
+	return blocks
+}
+
+// CountCodeBlocks returns the number of fenced code blocks in content.
+func CountCodeBlocks(content string) int {
+	count := 0
+	for _, segment := range splitCodeBlocks(content, 1) {
+		if segment.block != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// splitCodeBlocks splits markdown into text and fenced code segments,
+// numbering blocks from firstIndex. Only fences indented by at most three
+// spaces are recognized; fences nested deeper (e.g. inside list items) stay
+// part of the text. An unclosed fence runs to the end of the content, which
+// is what a partially streamed response looks like.
+func splitCodeBlocks(content string, firstIndex int) []contentSegment {
+	var segments []contentSegment
+	var text []string
+	lines := strings.Split(content, "\n")
+
+	flushText := func() {
+		if len(text) > 0 {
+			segments = append(segments, contentSegment{text: strings.Join(text, "\n")})
+			text = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		fence, info, ok := openingFence(lines[i])
+		if !ok {
+			text = append(text, lines[i])
+			continue
+		}
+
+		var code []string
+		j := i + 1
+		for ; j < len(lines); j++ {
+			if isClosingFence(lines[j], fence) {
+				break
+			}
+			code = append(code, lines[j])
+		}
+
+		flushText()
+		language, _, _ := strings.Cut(info, " ")
+		segments = append(segments, contentSegment{block: &CodeBlock{
+			Index:    firstIndex,
+			Language: language,
+			Code:     strings.Join(code, "\n"),
+		}})
+		firstIndex++
+		i = j
+	}
+	flushText()
+	return segments
+}
+
+func openingFence(line string) (fence string, info string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", "", false
+	}
+	for _, marker := range []string{"```", "~~~"} {
+		if !strings.HasPrefix(trimmed, marker) {
+			continue
+		}
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, marker[:1]))
+		info = strings.TrimSpace(trimmed[n:])
+		if marker == "```" && strings.Contains(info, "`") {
+			return "", "", false // inline code, not a fence
+		}
+		return trimmed[:n], info, true
+	}
+	return "", "", false
+}
+
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(line)-len(strings.TrimLeft(line, " ")) <= 3 &&
+		len(trimmed) >= len(fence) &&
+		strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// highlightCode colors code with chroma for the given output mode. Styles
+// without color (ascii, notty) return the code unchanged.
+func highlightCode(code, language string, profile ColorProfile, glamourStyle string) string {
+	chromaStyle := chromaStyleForGlamour(glamourStyle)
+	if chromaStyle == "" {
+		return code
+	}
+
+	var highlighted strings.Builder
+	if err := quick.Highlight(&highlighted, code, language, chromaFormatter(profile), chromaStyle); err != nil {
+		return code
+	}
+	return strings.TrimRight(highlighted.String(), "\n")
+}
+
+// chromaFormatter picks the terminal formatter matching the color profile.
+// 16-color terminals get terminal8, as gocui doesn't parse the bright codes
+// terminal16 emits.
+func chromaFormatter(profile ColorProfile) string {
+	switch profile {
+	case ColorProfileANSI, ColorProfileANSI16:
+		return "terminal8"
+	case ColorProfileANSI256:
+		return "terminal256"
+	default:
+		return "terminal16m"
+	}
+}
+
+// chromaStyleForGlamour maps glamour styles to the closest chroma style so
+// code blocks match the surrounding markdown
+func chromaStyleForGlamour(glamourStyle string) string {
+	switch glamourStyle {
+	case "ascii", "notty":
+		return ""
+	case "light":
+		return "github"
+	case "dracula":
+		return "dracula"
+	case "tokyo-night":
+		return "tokyonight-night"
+	case "pink":
+		return "rose-pine"
+	default:
+		return "monokai"
+	}
+}
+
+// formatCodeBlockLabel renders the index label drawn above a code block
+func formatCodeBlockLabel(block CodeBlock, color string) string {
+	label := fmt.Sprintf("[%d]", block.Index)
+	if block.Language != "" {
+		label += " " + block.Language
+	}
+	return fmt.Sprintf("%s%s · :yc%d to copy\033[0m", color, label, block.Index)
+}
@@ -0,0 +1,58 @@
+package presentation
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCodeBlocks(t *testing.T) {
+	content := "Intro with `inline` code\n\n```go\nfunc main() {}\n```\n\nMiddle\n\n~~~~\nplain ``` text\n~~~~\n\n    ```\n    indented too deep\n    ```"
+
+	segments := splitCodeBlocks(content, 3)
+	require.Len(t, segments, 5)
+
+	assert.Equal(t, "Intro with `inline` code\n", segments[0].text)
+	require.NotNil(t, segments[1].block)
+	assert.Equal(t, CodeBlock{Index: 3, Language: "go", Code: "func main() {}"}, *segments[1].block)
+	assert.Equal(t, "\nMiddle\n", segments[2].text)
+	require.NotNil(t, segments[3].block)
+	assert.Equal(t, 4, segments[3].block.Index)
+	assert.Equal(t, "plain ``` text", segments[3].block.Code)
+	assert.Nil(t, segments[4].block)
+	assert.Contains(t, segments[4].text, "indented too deep")
+}
+
+func TestSplitCodeBlocks_UnclosedFence(t *testing.T) {
+	segments := splitCodeBlocks("Streaming:\n```python\nprint('hi')", 1)
+	require.Len(t, segments, 2)
+	assert.Equal(t, CodeBlock{Index: 1, Language: "python", Code: "print('hi')"}, *segments[1].block)
+}
+
+func TestCodeBlocks_NumberedAcrossMarkdownMessages(t *testing.T) {
+	messages := []types.Message{
+		{Role: "user", Content: "```\nnot labelled\n```"},
+		{Role: "assistant", ContentType: "markdown", Content: "```sh\nls\n```\n```sh\npwd\n```"},
+		{Role: "assistant", ContentType: "markdown", Content: "No code here"},
+		{Role: "assistant", ContentType: "markdown", Content: "```json\n{}\n```"},
+	}
+
+	blocks := CodeBlocks(messages)
+	require.Len(t, blocks, 3)
+	assert.Equal(t, "ls", blocks[0].Code)
+	assert.Equal(t, 2, blocks[1].Index)
+	assert.Equal(t, CodeBlock{Index: 3, Language: "json", Code: "{}"}, blocks[2])
+	assert.Equal(t, 2, CountCodeBlocks(messages[1].Content))
+}
+
+func TestHighlightCode(t *testing.T) {
+	code := "package main\n\nfunc main() {}"
+
+	assert.Equal(t, code, highlightCode(code, "go", ColorProfileTrueColor, "notty"))
+
+	highlighted := highlightCode(code, "go", ColorProfileTrueColor, "dark")
+	assert.Contains(t, highlighted, "\x1b[")
+	assert.Contains(t, highlighted, "main")
+}
@@ -0,0 +1,278 @@
+package presentation
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/awesome-gocui/gocui"
+	"github.com/muesli/termenv"
+)
+
+// ColorProfile is how many colors the terminal can show. Theme hex colors
+// are degraded to the nearest color the profile has.
+type ColorProfile int
+
+const (
+	// ColorProfileANSI is the 8 basic ANSI colors
+	ColorProfileANSI ColorProfile = iota
+	// ColorProfileANSI16 adds the 8 bright variants
+	ColorProfileANSI16
+	// ColorProfileANSI256 is the xterm 256-color palette
+	ColorProfileANSI256
+	// ColorProfileTrueColor shows theme colors exactly
+	ColorProfileTrueColor
+)
+
+func (p ColorProfile) String() string {
+	switch p {
+	case ColorProfileANSI:
+		return "8 colors"
+	case ColorProfileANSI16:
+		return "16 colors"
+	case ColorProfileANSI256:
+		return "256 colors"
+	default:
+		return "true color"
+	}
+}
+
+// GocuiOutputMode is the gocui mode whose escape parsing matches p.
+func (p ColorProfile) GocuiOutputMode() gocui.OutputMode {
+	switch p {
+	case ColorProfileANSI:
+		return gocui.OutputNormal
+	case ColorProfileANSI16, ColorProfileANSI256:
+		return gocui.Output256
+	default:
+		return gocui.OutputTrue
+	}
+}
+
+// termenvProfile is the glamour color profile matching p.
+func (p ColorProfile) termenvProfile() termenv.Profile {
+	switch p {
+	case ColorProfileANSI, ColorProfileANSI16:
+		return termenv.ANSI
+	case ColorProfileANSI256:
+		return termenv.ANSI256
+	default:
+		return termenv.TrueColor
+	}
+}
+
+var activeProfile atomic.Int32
+
+func init() {
+	activeProfile.Store(int32(ColorProfileTrueColor))
+}
+
+// SetColorProfile sets the profile theme colors are rendered in. The TUI
+// sets it once, when it picks the gocui output mode.
+func SetColorProfile(p ColorProfile) {
+	activeProfile.Store(int32(p))
+}
+
+// ActiveColorProfile returns the profile theme colors are rendered in.
+func ActiveColorProfile() ColorProfile {
+	return ColorProfile(activeProfile.Load())
+}
+
+// ColorProfileForMode resolves the output_mode setting: "true", "256",
+// "16" and "normal" force a profile; "auto" or empty detects one.
+func ColorProfileForMode(outputMode string) ColorProfile {
+	switch outputMode {
+	case "true":
+		return ColorProfileTrueColor
+	case "256":
+		return ColorProfileANSI256
+	case "16":
+		return ColorProfileANSI16
+	case "normal":
+		return ColorProfileANSI
+	default:
+		return DetectColorProfile(os.Getenv)
+	}
+}
+
+// DetectColorProfile guesses the terminal's colors from COLORTERM, TERM
+// and the variables terminal emulators set about themselves.
+func DetectColorProfile(getenv func(string) string) ColorProfile {
+	colorTerm := strings.ToLower(getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorProfileTrueColor
+	}
+	if getenv("WT_SESSION") != "" {
+		return ColorProfileTrueColor // Windows Terminal
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty":
+		return ColorProfileTrueColor
+	case "Apple_Terminal":
+		return ColorProfileANSI256
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case term == "" && runtime.GOOS == "windows":
+		return ColorProfileTrueColor // Windows 10+ console
+	case strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"), strings.Contains(term, "direct"):
+		return ColorProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return ColorProfileANSI256
+	case term == "", term == "dumb", term == "vt100", term == "ansi", strings.HasPrefix(term, "cons"):
+		return ColorProfileANSI
+	default:
+		// linux, xterm, screen, tmux and friends all have at least 16
+		return ColorProfileANSI16
+	}
+}
+
+// nearestANSI16 maps a color to an ANSI color (0-15) by hue rather than
+// distance: themes favor muted tones, which distance would turn gray,
+// losing the difference between, say, an error and a success.
+func nearestANSI16(r, g, b int) int {
+	hi, lo := max(r, g, b), min(r, g, b)
+	if hi-lo < 40 {
+		switch lightness := (hi + lo) / 2; {
+		case lightness < 48:
+			return 0
+		case lightness < 160:
+			return 8
+		case lightness < 220:
+			return 7
+		default:
+			return 15
+		}
+	}
+
+	// ANSI hues, 60° apart starting at red: red, yellow, green, cyan,
+	// blue, magenta
+	hueColors := [6]int{1, 3, 2, 6, 4, 5}
+	sector := int(math.Round(hue(r, g, b)/60)) % 6
+	color := hueColors[sector]
+	if hi > 200 {
+		color += 8
+	}
+	return color
+}
+
+// hue returns the HSV hue of a color in degrees.
+func hue(r, g, b int) float64 {
+	hi, lo := max(r, g, b), min(r, g, b)
+	delta := float64(hi - lo)
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod(float64(g-b)/delta, 6)
+	case g:
+		h = float64(b-r)/delta + 2
+	default:
+		h = float64(r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// nearestANSI8 maps a color to one of the 8 basic colors. Grays that
+// would be bright black become white, which 8-color terminals draw gray.
+func nearestANSI8(r, g, b int) int {
+	color := nearestANSI16(r, g, b)
+	if color == 8 {
+		return 7
+	}
+	return color % 8
+}
+
+// cubeLevels are the channel values of the xterm 6×6×6 color cube.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// nearest256 maps a color to the closest xterm 256-palette entry from the
+// color cube or the gray ramp.
+func nearest256(r, g, b int) int {
+	cubeIndex := func(v int) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(v-level) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := cubeIndex(r), cubeIndex(g), cubeIndex(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDist := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// Gray ramp 232-255 runs from 8 to 238 in steps of 10
+	grayStep := min(max((r+g+b)/3-8+5, 0)/10, 23)
+	grayLevel := 8 + 10*grayStep
+	if distance(r, g, b, grayLevel, grayLevel, grayLevel) < cubeDist {
+		return 232 + grayStep
+	}
+	return cube
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return dr*dr + dg*dg + db*db
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// paletteIndex returns the palette entry a hex color degrades to under p,
+// or -1 when p shows it exactly.
+func paletteIndex(p ColorProfile, hexColor string) int {
+	r, g, b := hexToRGB(hexColor)
+	switch p {
+	case ColorProfileANSI:
+		return nearestANSI8(r, g, b)
+	case ColorProfileANSI16:
+		return nearestANSI16(r, g, b)
+	case ColorProfileANSI256:
+		return nearest256(r, g, b)
+	default:
+		return -1
+	}
+}
+
+// ansiColor returns the SGR escape setting a hex color as foreground, or
+// background when bg is set, degraded to profile p.
+func ansiColor(p ColorProfile, hexColor string, bg bool) string {
+	if len(hexColor) != 7 || hexColor[0] != '#' {
+		return ""
+	}
+	base := 38
+	if bg {
+		base = 48
+	}
+	switch index := paletteIndex(p, hexColor); {
+	case index < 0:
+		r, g, b := hexToRGB(hexColor)
+		return fmt.Sprintf("\033[%d;2;%d;%d;%dm", base, r, g, b)
+	case p == ColorProfileANSI:
+		// gocui's 8-color mode only understands the basic 30-37/40-47 codes
+		return fmt.Sprintf("\033[%dm", base-8+index)
+	default:
+		return fmt.Sprintf("\033[%d;5;%dm", base, index)
+	}
+}
+
+// PaletteIndex returns the palette entry a hex color is shown as under the
+// active profile, or -1 when it is shown exactly.
+func PaletteIndex(hexColor string) int {
+	if len(hexColor) != 7 || hexColor[0] != '#' {
+		return -1
+	}
+	return paletteIndex(ActiveColorProfile(), hexColor)
+}
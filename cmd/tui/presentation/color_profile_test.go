@@ -0,0 +1,103 @@
+package presentation
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want ColorProfile
+	}{
+		{name: "colorterm truecolor", env: map[string]string{"COLORTERM": "truecolor", "TERM": "xterm"}, want: ColorProfileTrueColor},
+		{name: "colorterm 24bit", env: map[string]string{"COLORTERM": "24bit"}, want: ColorProfileTrueColor},
+		{name: "windows terminal", env: map[string]string{"WT_SESSION": "abc"}, want: ColorProfileTrueColor},
+		{name: "iterm", env: map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM": "xterm-256color"}, want: ColorProfileTrueColor},
+		{name: "apple terminal", env: map[string]string{"TERM_PROGRAM": "Apple_Terminal", "TERM": "xterm-256color"}, want: ColorProfileANSI256},
+		{name: "xterm-256color", env: map[string]string{"TERM": "xterm-256color"}, want: ColorProfileANSI256},
+		{name: "tmux-256color", env: map[string]string{"TERM": "tmux-256color"}, want: ColorProfileANSI256},
+		{name: "xterm-direct", env: map[string]string{"TERM": "xterm-direct"}, want: ColorProfileTrueColor},
+		{name: "xterm", env: map[string]string{"TERM": "xterm"}, want: ColorProfileANSI16},
+		{name: "linux console", env: map[string]string{"TERM": "linux"}, want: ColorProfileANSI16},
+		{name: "vt100", env: map[string]string{"TERM": "vt100"}, want: ColorProfileANSI},
+		{name: "dumb", env: map[string]string{"TERM": "dumb"}, want: ColorProfileANSI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.want, DetectColorProfile(getenv))
+		})
+	}
+}
+
+func TestDetectColorProfile_NoTerm(t *testing.T) {
+	want := ColorProfileANSI
+	if runtime.GOOS == "windows" {
+		want = ColorProfileTrueColor
+	}
+	assert.Equal(t, want, DetectColorProfile(func(string) string { return "" }))
+}
+
+func TestColorProfileForMode(t *testing.T) {
+	assert.Equal(t, ColorProfileTrueColor, ColorProfileForMode("true"))
+	assert.Equal(t, ColorProfileANSI256, ColorProfileForMode("256"))
+	assert.Equal(t, ColorProfileANSI16, ColorProfileForMode("16"))
+	assert.Equal(t, ColorProfileANSI, ColorProfileForMode("normal"))
+
+	t.Setenv("COLORTERM", "")
+	t.Setenv("WT_SESSION", "")
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("TERM", "xterm-256color")
+	assert.Equal(t, ColorProfileANSI256, ColorProfileForMode("auto"))
+	assert.Equal(t, ColorProfileANSI256, ColorProfileForMode(""))
+}
+
+func TestNearestANSI16KeepsThemeHues(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want int
+	}{
+		{"#C85450", 1},  // matte red
+		{"#6B9B6B", 2},  // matte green
+		{"#6B8CAF", 4},  // matte blue
+		{"#D4A854", 11}, // bright yellow
+		{"#FF0000", 9},  // bright red
+		{"#202020", 0},  // near black
+		{"#8A8A8A", 8},  // mid gray
+		{"#B0B0B0", 7},  // light gray
+		{"#E8E8E8", 15}, // off-white
+	}
+	for _, tt := range tests {
+		t.Run(tt.hex, func(t *testing.T) {
+			assert.Equal(t, tt.want, nearestANSI16(hexToRGB(tt.hex)))
+		})
+	}
+}
+
+func TestNearestANSI8(t *testing.T) {
+	assert.Equal(t, 1, nearestANSI8(hexToRGB("#FF0000")))
+	assert.Equal(t, 3, nearestANSI8(hexToRGB("#D4A854")))
+	assert.Equal(t, 7, nearestANSI8(hexToRGB("#8A8A8A")))
+	assert.Equal(t, 7, nearestANSI8(hexToRGB("#E8E8E8")))
+}
+
+func TestNearest256(t *testing.T) {
+	assert.Equal(t, 16, nearest256(hexToRGB("#000000")))
+	assert.Equal(t, 231, nearest256(hexToRGB("#FFFFFF")))
+	assert.Equal(t, 196, nearest256(hexToRGB("#FF0000")))
+	assert.Equal(t, 244, nearest256(hexToRGB("#808080")))
+}
+
+func TestAnsiColor(t *testing.T) {
+	assert.Equal(t, "\033[38;2;200;84;80m", ansiColor(ColorProfileTrueColor, "#C85450", false))
+	assert.Equal(t, "\033[48;2;200;84;80m", ansiColor(ColorProfileTrueColor, "#C85450", true))
+	assert.Equal(t, "\033[38;5;167m", ansiColor(ColorProfileANSI256, "#C85450", false))
+	assert.Equal(t, "\033[38;5;1m", ansiColor(ColorProfileANSI16, "#C85450", false))
+	assert.Equal(t, "\033[31m", ansiColor(ColorProfileANSI, "#C85450", false))
+	assert.Equal(t, "\033[41m", ansiColor(ColorProfileANSI, "#C85450", true))
+	assert.Equal(t, "", ansiColor(ColorProfileANSI16, "red", false))
+}
@@ -0,0 +1,187 @@
+package presentation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ThemeRoles are the Theme fields a theme colors, in the order the theme
+// editor and :theme preview list them.
+var ThemeRoles = []string{
+	"Primary", "Secondary", "Tertiary",
+	"Error", "Warning", "Success", "Muted",
+	"TextPrimary", "TextSecondary", "TextTertiary",
+	"BorderDefault", "BorderFocused", "BorderMuted",
+	"TitleDefault", "TitleFocused", "TitleMuted",
+}
+
+var (
+	themesMu      sync.RWMutex
+	builtinThemes = themeNames()
+
+	hexColorPattern  = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+	themeNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+)
+
+func themeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// IsBuiltinTheme reports whether name is one of the themes Genie ships.
+func IsBuiltinTheme(name string) bool {
+	return slices.Contains(builtinThemes, name)
+}
+
+// IsHexColor reports whether s is a #RRGGBB color.
+func IsHexColor(s string) bool {
+	return hexColorPattern.MatchString(s)
+}
+
+// IsValidThemeName reports whether name can be used as a custom theme's
+// file name.
+func IsValidThemeName(name string) bool {
+	return themeNamePattern.MatchString(name)
+}
+
+// Color returns the color of a theme role, or "" for an unknown role.
+func (t *Theme) Color(role string) string {
+	field := reflect.ValueOf(t).Elem().FieldByName(role)
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return ""
+	}
+	return field.String()
+}
+
+// SetColor sets the color of a theme role.
+func (t *Theme) SetColor(role, hexColor string) error {
+	if !IsHexColor(hexColor) {
+		return fmt.Errorf("invalid color %q: use #RRGGBB", hexColor)
+	}
+	field := reflect.ValueOf(t).Elem().FieldByName(role)
+	if !slices.Contains(ThemeRoles, role) || !field.IsValid() {
+		return fmt.Errorf("unknown theme role %q", role)
+	}
+	field.SetString(strings.ToUpper(hexColor))
+	return nil
+}
+
+// Clone returns a copy of the theme that can be edited on its own.
+func (t *Theme) Clone() *Theme {
+	clone := *t
+	return &clone
+}
+
+// RegisterTheme adds or replaces a theme under name, making it available
+// to :theme and the config.
+func RegisterTheme(name string, theme *Theme) {
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	Themes[name] = theme
+}
+
+// UnregisterTheme removes a custom theme. Built-in themes stay.
+func UnregisterTheme(name string) {
+	if IsBuiltinTheme(name) {
+		return
+	}
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	delete(Themes, name)
+}
+
+// LoadCustomThemes registers every <name>.json theme in dir. Roles a file
+// leaves out keep the default theme's colors, and files named after a
+// built-in theme are skipped so the shipped themes stay intact. A missing
+// dir is not an error; unreadable files are reported but do not stop the
+// others from loading.
+func LoadCustomThemes(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok || IsBuiltinTheme(name) || !IsValidThemeName(name) {
+			continue
+		}
+		theme, err := readCustomTheme(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		RegisterTheme(name, theme)
+	}
+	return errors.Join(errs...)
+}
+
+func readCustomTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	theme := GetTheme("default").Clone()
+	if err := json.Unmarshal(data, theme); err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+	for _, role := range ThemeRoles {
+		if !IsHexColor(theme.Color(role)) {
+			return nil, fmt.Errorf("invalid theme %s: %s is not a #RRGGBB color", path, role)
+		}
+	}
+	return theme, nil
+}
+
+// SaveCustomTheme writes theme to dir as <name>.json and registers it,
+// returning the file's path.
+func SaveCustomTheme(dir, name string, theme *Theme) (string, error) {
+	if !IsValidThemeName(name) {
+		return "", fmt.Errorf("invalid theme name %q: use letters, digits, - and _", name)
+	}
+	if IsBuiltinTheme(name) {
+		return "", fmt.Errorf("%s is a built-in theme; save under another name", name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(theme, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name+".json")
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", err
+	}
+	RegisterTheme(name, theme.Clone())
+	return path, nil
+}
+
+// AdjustLightness makes a hex color lighter (positive delta) or darker
+// (negative) by delta out of 255 on every channel.
+func AdjustLightness(hexColor string, delta int) string {
+	if !IsHexColor(hexColor) {
+		return hexColor
+	}
+	r, g, b := hexToRGB(hexColor)
+	clamp := func(v int) int { return min(max(v+delta, 0), 255) }
+	return fmt.Sprintf("#%02X%02X%02X", clamp(r), clamp(g), clamp(b))
+}
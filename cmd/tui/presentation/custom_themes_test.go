@@ -0,0 +1,90 @@
+package presentation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeSetColor(t *testing.T) {
+	theme := GetTheme("default").Clone()
+
+	require.NoError(t, theme.SetColor("Error", "#ff0000"))
+	assert.Equal(t, "#FF0000", theme.Color("Error"))
+	assert.NotEqual(t, "#FF0000", GetTheme("default").Error, "clone must not share colors")
+
+	assert.Error(t, theme.SetColor("Error", "red"))
+	assert.Error(t, theme.SetColor("Nope", "#FF0000"))
+	assert.Equal(t, "", theme.Color("Nope"))
+}
+
+func TestSaveAndLoadCustomTheme(t *testing.T) {
+	dir := t.TempDir()
+	theme := GetTheme("default").Clone()
+	require.NoError(t, theme.SetColor("Primary", "#123456"))
+
+	path, err := SaveCustomTheme(dir, "ocean", theme)
+	require.NoError(t, err)
+	t.Cleanup(func() { UnregisterTheme("ocean") })
+	assert.Equal(t, filepath.Join(dir, "ocean.json"), path)
+	assert.Equal(t, "#123456", GetTheme("ocean").Primary)
+
+	UnregisterTheme("ocean")
+	assert.NotContains(t, GetThemeNames(), "ocean")
+
+	require.NoError(t, LoadCustomThemes(dir))
+	assert.Contains(t, GetThemeNames(), "ocean")
+	assert.Equal(t, "#123456", GetTheme("ocean").Primary)
+}
+
+func TestSaveCustomThemeRejectsBuiltinAndInvalidNames(t *testing.T) {
+	dir := t.TempDir()
+	theme := GetTheme("default").Clone()
+
+	_, err := SaveCustomTheme(dir, "default", theme)
+	assert.Error(t, err)
+	_, err = SaveCustomTheme(dir, "../escape", theme)
+	assert.Error(t, err)
+}
+
+func TestLoadCustomThemes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("partial.json", `{"Error": "#AA0000"}`)
+	write("broken.json", `{`)
+	write("badcolor.json", `{"Primary": "green"}`)
+	write("default.json", `{"Primary": "#000001"}`)
+	write("notes.txt", `ignored`)
+	t.Cleanup(func() {
+		UnregisterTheme("partial")
+		UnregisterTheme("broken")
+		UnregisterTheme("badcolor")
+	})
+
+	err := LoadCustomThemes(dir)
+	assert.Error(t, err, "broken files are reported")
+
+	names := GetThemeNames()
+	assert.Contains(t, names, "partial")
+	assert.NotContains(t, names, "broken")
+	assert.NotContains(t, names, "badcolor")
+
+	partial := GetTheme("partial")
+	assert.Equal(t, "#AA0000", partial.Error)
+	assert.Equal(t, GetTheme("default").Primary, partial.Primary, "missing roles come from the default theme")
+	assert.NotEqual(t, "#000001", GetTheme("default").Primary, "built-in themes are not overridden")
+
+	assert.NoError(t, LoadCustomThemes(filepath.Join(dir, "missing")))
+}
+
+func TestAdjustLightness(t *testing.T) {
+	assert.Equal(t, "#182818", AdjustLightness("#102010", 8))
+	assert.Equal(t, "#FFFFFF", AdjustLightness("#FAFAFA", 8))
+	assert.Equal(t, "#000000", AdjustLightness("#040404", -8))
+	assert.Equal(t, "bogus", AdjustLightness("bogus", 8))
+}
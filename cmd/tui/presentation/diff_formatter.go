@@ -2,6 +2,8 @@ package presentation
 
 import (
 	"strings"
+
+	"github.com/mattn/go-runewidth"
 )
 
 // DiffFormatter formats diff output with diff-specific colors
@@ -155,24 +157,18 @@ func (f *DiffFormatter) FormatSideBySide(oldContent, newContent string, width in
 		var oldLine, newLine string
 
 		if i < len(oldLines) {
-			oldLine = oldLines[i]
-			if len(oldLine) > halfWidth {
-				oldLine = oldLine[:halfWidth-3] + "..."
-			}
+			oldLine = runewidth.Truncate(oldLines[i], halfWidth, "...")
 		}
 
 		if i < len(newLines) {
-			newLine = newLines[i]
-			if len(newLine) > halfWidth {
-				newLine = newLine[:halfWidth-3] + "..."
-			}
+			newLine = runewidth.Truncate(newLines[i], halfWidth, "...")
 		}
 
 		// Format the line
 		if oldLine != "" && newLine != "" && oldLine != newLine {
 			// Changed line
 			result.WriteString(removeBg + removeFg + oldLine + reset)
-			result.WriteString(strings.Repeat(" ", halfWidth-len(oldLine)+2))
+			result.WriteString(strings.Repeat(" ", halfWidth-runewidth.StringWidth(oldLine)+2))
 			result.WriteString(addBg + addFg + newLine + reset)
 		} else if oldLine != "" && newLine == "" {
 			// Removed line
@@ -185,7 +181,7 @@ func (f *DiffFormatter) FormatSideBySide(oldContent, newContent string, width in
 			// Unchanged line
 			result.WriteString(oldLine)
 			if newLine != "" {
-				result.WriteString(strings.Repeat(" ", halfWidth-len(oldLine)+2))
+				result.WriteString(strings.Repeat(" ", halfWidth-runewidth.StringWidth(oldLine)+2))
 				result.WriteString(newLine)
 			}
 		}
@@ -0,0 +1,416 @@
+package presentation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff layouts
+const (
+	DiffModeUnified    = "unified"
+	DiffModeSideBySide = "side-by-side"
+)
+
+// DiffModes lists the layouts the diff viewer supports
+var DiffModes = []string{DiffModeUnified, DiffModeSideBySide}
+
+// diffFoldContext is how many unchanged lines stay visible on each side of
+// a folded region
+const diffFoldContext = 2
+
+// diffWordSimilarity is how alike a removed and an added line must be for
+// their changed words to be highlighted; below it the lines are simply
+// different and highlighting every word would only add noise
+const diffWordSimilarity = 0.4
+
+var (
+	hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+	diffTokenPattern  = regexp.MustCompile(`[\p{L}\p{N}_]+|\s+|.`)
+)
+
+const (
+	wordHighlightOn  = "\033[7m"
+	wordHighlightOff = "\033[27m"
+	ansiReset        = "\033[0m"
+)
+
+// DiffViewOptions control how RenderDiff lays out a unified diff
+type DiffViewOptions struct {
+	Mode     string // DiffModeUnified (default) or DiffModeSideBySide
+	Width    int    // Columns available, for side-by-side
+	Collapse bool   // Fold long runs of unchanged lines
+	ASCII    bool   // Separate columns and mark folds without Unicode symbols
+}
+
+// DiffView is a unified diff rendered for the diff viewer
+type DiffView struct {
+	Text  string
+	Hunks []int // Line of Text each hunk starts on, for hunk navigation
+}
+
+// diffHunk is one @@ section of a unified diff
+type diffHunk struct {
+	header   string
+	oldStart int
+	oldCount int
+	newStart int
+	lines    []string // body lines, prefix included
+}
+
+// diffSpan is a run of a changed line, marked when it differs from the
+// line it replaced
+type diffSpan struct {
+	text    string
+	changed bool
+}
+
+// diffCell is one side of a side-by-side row
+type diffCell struct {
+	line  int
+	spans []diffSpan
+	color string
+}
+
+// RenderDiff renders a unified diff with changed words highlighted, in the
+// layout opts asks for. Lines that are not part of a hunk, such as file
+// headers, are shown as they are.
+func (f *DiffFormatter) RenderDiff(content string, opts DiffViewOptions) DiffView {
+	if f.diffTheme == nil {
+		// Without a theme lines keep their layout but get no colors
+		f = NewDiffFormatter(&DiffTheme{})
+	}
+	r := diffRenderer{f: f, opts: opts}
+	if opts.Mode == DiffModeSideBySide && opts.Width > 0 {
+		r.sideBySide = true
+	}
+
+	var hunk *diffHunk
+	prevEnd := 0
+	flush := func() {
+		if hunk != nil {
+			r.hunk(hunk, prevEnd)
+			prevEnd = hunk.oldStart + hunk.oldCount
+			hunk = nil
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		if match := hunkHeaderPattern.FindStringSubmatch(line); match != nil {
+			flush()
+			hunk = &diffHunk{
+				header:   line,
+				oldStart: atoiOr(match[1], 0),
+				oldCount: atoiOr(match[2], 1),
+				newStart: atoiOr(match[3], 0),
+			}
+			continue
+		}
+		if hunk != nil && strings.HasPrefix(line, `\`) {
+			continue // "\ No newline at end of file"
+		}
+		if hunk != nil && isHunkBodyLine(line) {
+			hunk.lines = append(hunk.lines, line)
+			continue
+		}
+		flush()
+		prevEnd = 0 // a new file's hunks count from its own start
+		r.emit(f.formatLine(line))
+	}
+	flush()
+
+	return DiffView{Text: strings.Join(r.out, "\n"), Hunks: r.hunks}
+}
+
+// diffRenderer accumulates the rendered lines of one diff
+type diffRenderer struct {
+	f          *DiffFormatter
+	opts       DiffViewOptions
+	sideBySide bool
+	out        []string
+	hunks      []int
+}
+
+func (r *diffRenderer) emit(line string) {
+	r.out = append(r.out, line)
+}
+
+func (r *diffRenderer) addedColor() string {
+	return ConvertColorToAnsiBg(r.f.diffTheme.AddedBg) + ConvertColorToAnsi(r.f.diffTheme.AddedFg)
+}
+
+func (r *diffRenderer) removedColor() string {
+	return ConvertColorToAnsiBg(r.f.diffTheme.RemovedBg) + ConvertColorToAnsi(r.f.diffTheme.RemovedFg)
+}
+
+func (r *diffRenderer) contextColor() string {
+	return ConvertColorToAnsiBg(r.f.diffTheme.ContextBg) + ConvertColorToAnsi(r.f.diffTheme.ContextFg)
+}
+
+func (r *diffRenderer) hunkColor() string {
+	return ConvertColorToAnsiBg(r.f.diffTheme.HunkBg) + ConvertColorToAnsi(r.f.diffTheme.HunkFg)
+}
+
+// fold marks n unchanged lines that are not shown
+func (r *diffRenderer) fold(n int) {
+	symbol := "⋯"
+	if r.opts.ASCII {
+		symbol = "..."
+	}
+	noun := "lines"
+	if n == 1 {
+		noun = "line"
+	}
+	r.emit(r.hunkColor() + fmt.Sprintf("%s %d unchanged %s %s", symbol, n, noun, symbol) + ansiReset)
+}
+
+// hunk renders one hunk; prevEnd is the old line the previous hunk ended
+// before, so the lines between hunks can be counted when folding
+func (r *diffRenderer) hunk(h *diffHunk, prevEnd int) {
+	if r.opts.Collapse && prevEnd > 0 && h.oldStart > prevEnd {
+		r.fold(h.oldStart - prevEnd)
+	}
+	r.hunks = append(r.hunks, len(r.out))
+	r.emit(r.hunkColor() + h.header + ansiReset)
+
+	oldLine, newLine := h.oldStart, h.newStart
+	for i := 0; i < len(h.lines); {
+		// Gather a run of unchanged lines, or of removed and added ones
+		context := isContextLine(h.lines[i])
+		j := i
+		for j < len(h.lines) && isContextLine(h.lines[j]) == context {
+			j++
+		}
+		run := h.lines[i:j]
+
+		if context {
+			r.context(run, oldLine, newLine, i == 0, j == len(h.lines))
+			oldLine += len(run)
+			newLine += len(run)
+		} else {
+			var removed, added []string
+			for _, line := range run {
+				switch {
+				case strings.HasPrefix(line, "-"):
+					removed = append(removed, line[1:])
+				case strings.HasPrefix(line, "+"):
+					added = append(added, line[1:])
+				}
+			}
+			r.change(removed, added, oldLine, newLine)
+			oldLine += len(removed)
+			newLine += len(added)
+		}
+		i = j
+	}
+}
+
+// context renders a run of unchanged lines, folding its middle when it is
+// long. Runs at the start or end of a hunk keep only the lines next to the
+// change.
+func (r *diffRenderer) context(run []string, oldLine, newLine int, first, last bool) {
+	keepHead, keepTail := diffFoldContext, diffFoldContext
+	if first {
+		keepHead = 0
+	}
+	if last {
+		keepTail = 0
+	}
+	hidden := len(run) - keepHead - keepTail
+	if !r.opts.Collapse || hidden < 3 {
+		keepHead, hidden = len(run), 0
+	}
+
+	for i, line := range run {
+		if i == keepHead && hidden > 0 {
+			r.fold(hidden)
+		}
+		if i >= keepHead && i < keepHead+hidden {
+			continue
+		}
+		text := strings.TrimPrefix(line, " ")
+		if !r.sideBySide {
+			r.emit(r.f.formatLine(line))
+			continue
+		}
+		spans := []diffSpan{{text: text}}
+		color := r.contextColor()
+		r.row(&diffCell{line: oldLine + i, spans: spans, color: color}, &diffCell{line: newLine + i, spans: spans, color: color})
+	}
+}
+
+// change renders removed lines replaced by added ones, highlighting the
+// words that changed between lines at the same position
+func (r *diffRenderer) change(removed, added []string, oldLine, newLine int) {
+	removedSpans := make([][]diffSpan, len(removed))
+	addedSpans := make([][]diffSpan, len(added))
+	for i := range removed {
+		removedSpans[i] = []diffSpan{{text: removed[i]}}
+	}
+	for i := range added {
+		addedSpans[i] = []diffSpan{{text: added[i]}}
+	}
+	for i := 0; i < len(removed) && i < len(added); i++ {
+		removedSpans[i], addedSpans[i] = wordSpans(removed[i], added[i])
+	}
+
+	if !r.sideBySide {
+		for _, spans := range removedSpans {
+			r.emit(r.removedColor() + "-" + renderSpans(spans) + ansiReset)
+		}
+		for _, spans := range addedSpans {
+			r.emit(r.addedColor() + "+" + renderSpans(spans) + ansiReset)
+		}
+		return
+	}
+
+	rows := max(len(removed), len(added))
+	for i := 0; i < rows; i++ {
+		var left, right *diffCell
+		if i < len(removed) {
+			left = &diffCell{line: oldLine + i, spans: removedSpans[i], color: r.removedColor()}
+		}
+		if i < len(added) {
+			right = &diffCell{line: newLine + i, spans: addedSpans[i], color: r.addedColor()}
+		}
+		r.row(left, right)
+	}
+}
+
+// row renders a side-by-side row; a nil cell leaves its column blank
+func (r *diffRenderer) row(left, right *diffCell) {
+	separator := " │ "
+	if r.opts.ASCII {
+		separator = " | "
+	}
+	width := (r.opts.Width - runewidth.StringWidth(separator)) / 2
+	r.emit(r.cell(left, width) + separator + r.cell(right, width))
+}
+
+// cell renders a line number and text in exactly width columns
+func (r *diffRenderer) cell(c *diffCell, width int) string {
+	if c == nil {
+		return strings.Repeat(" ", max(width, 0))
+	}
+	number := fmt.Sprintf("%4d ", c.line)
+	textWidth := width - len(number)
+	if textWidth < 1 {
+		return strings.Repeat(" ", max(width, 0))
+	}
+
+	// Tabs would throw the columns out of line
+	spans := make([]diffSpan, len(c.spans))
+	total := 0
+	for i, span := range c.spans {
+		spans[i] = diffSpan{text: strings.ReplaceAll(span.text, "\t", "    "), changed: span.changed}
+		total += runewidth.StringWidth(spans[i].text)
+	}
+
+	// Lines too long for the column end in an ellipsis
+	budget := textWidth
+	truncated := total > textWidth
+	if truncated {
+		budget = textWidth - 1
+	}
+
+	var b strings.Builder
+	used := 0
+	for _, span := range spans {
+		var part strings.Builder
+		for _, ch := range span.text {
+			w := runewidth.RuneWidth(ch)
+			if used+w > budget {
+				break
+			}
+			part.WriteRune(ch)
+			used += w
+		}
+		if span.changed && part.Len() > 0 {
+			b.WriteString(wordHighlightOn + part.String() + wordHighlightOff)
+		} else {
+			b.WriteString(part.String())
+		}
+	}
+	if truncated {
+		b.WriteString("…")
+		used++
+	}
+	return c.color + number + b.String() + strings.Repeat(" ", max(textWidth-used, 0)) + ansiReset
+}
+
+// wordSpans splits a removed line and the added line that replaced it into
+// spans, marking the words that differ. Lines too different to compare
+// word by word come back as single unmarked spans.
+func wordSpans(removed, added string) ([]diffSpan, []diffSpan) {
+	a := diffTokenPattern.FindAllString(removed, -1)
+	b := diffTokenPattern.FindAllString(added, -1)
+	matcher := difflib.NewMatcher(a, b)
+	if matcher.Ratio() < diffWordSimilarity {
+		return []diffSpan{{text: removed}}, []diffSpan{{text: added}}
+	}
+
+	var oldSpans, newSpans []diffSpan
+	for _, op := range matcher.GetOpCodes() {
+		oldText := strings.Join(a[op.I1:op.I2], "")
+		newText := strings.Join(b[op.J1:op.J2], "")
+		changed := op.Tag != 'e'
+		oldSpans = appendSpan(oldSpans, oldText, changed)
+		newSpans = appendSpan(newSpans, newText, changed)
+	}
+	return oldSpans, newSpans
+}
+
+// appendSpan adds text to spans, merging it into the last span when both
+// are marked the same
+func appendSpan(spans []diffSpan, text string, changed bool) []diffSpan {
+	if text == "" {
+		return spans
+	}
+	if n := len(spans); n > 0 && spans[n-1].changed == changed {
+		spans[n-1].text += text
+		return spans
+	}
+	return append(spans, diffSpan{text: text, changed: changed})
+}
+
+// renderSpans writes spans with the changed ones in reverse video, which
+// shows in the line's own color whatever the theme
+func renderSpans(spans []diffSpan) string {
+	var b strings.Builder
+	for _, span := range spans {
+		if span.changed {
+			b.WriteString(wordHighlightOn + span.text + wordHighlightOff)
+		} else {
+			b.WriteString(span.text)
+		}
+	}
+	return b.String()
+}
+
+// isHunkBodyLine reports whether line belongs to the hunk before it
+func isHunkBodyLine(line string) bool {
+	if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+		return false
+	}
+	return line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "+") ||
+		strings.HasPrefix(line, "-") || strings.HasPrefix(line, `\`)
+}
+
+// isContextLine reports whether a hunk line is unchanged
+func isContextLine(line string) bool {
+	return !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-")
+}
+
+func atoiOr(s string, fallback int) int {
+	if s == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
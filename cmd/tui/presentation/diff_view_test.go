@@ -0,0 +1,92 @@
+package presentation
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*m")
+
+const sampleDiff = `--- main.go
++++ main.go
+@@ -1,10 +1,10 @@
+ package main
+
+ import "fmt"
+
+ func main() {
+-	fmt.Println("hello world")
++	fmt.Println("hello there")
+ 	one()
+ 	two()
+ 	three()
+@@ -40,3 +40,3 @@
+ func last() {
+-	return 1
++	return 2
+ }
+`
+
+func TestDiffFormatter_RenderDiffHighlightsChangedWords(t *testing.T) {
+	view := NewDiffFormatter(nil).RenderDiff(sampleDiff, DiffViewOptions{})
+
+	assert.Contains(t, view.Text, `-	fmt.Println("hello `+wordHighlightOn+"world"+wordHighlightOff)
+	assert.Contains(t, view.Text, `+	fmt.Println("hello `+wordHighlightOn+"there"+wordHighlightOff)
+
+	// Lines with nothing in common are not highlighted word by word
+	removed, added := wordSpans("alpha beta", "1234")
+	assert.Equal(t, []diffSpan{{text: "alpha beta"}}, removed)
+	assert.Equal(t, []diffSpan{{text: "1234"}}, added)
+}
+
+func TestDiffFormatter_RenderDiffCollapsesUnchangedRegions(t *testing.T) {
+	view := NewDiffFormatter(nil).RenderDiff(sampleDiff, DiffViewOptions{Collapse: true})
+	lines := strings.Split(ansiPattern.ReplaceAllString(view.Text, ""), "\n")
+
+	assert.Equal(t, []string{
+		"--- main.go",
+		"+++ main.go",
+		"@@ -1,10 +1,10 @@",
+		"⋯ 3 unchanged lines ⋯",
+		"",
+		" func main() {",
+		`-	fmt.Println("hello world")`,
+		`+	fmt.Println("hello there")`,
+		" 	one()",
+		" 	two()",
+		" 	three()",
+		"⋯ 29 unchanged lines ⋯",
+		"@@ -40,3 +40,3 @@",
+		" func last() {",
+		"-	return 1",
+		"+	return 2",
+		" }",
+	}, lines)
+	assert.Equal(t, []int{2, 12}, view.Hunks)
+
+	expanded := NewDiffFormatter(nil).RenderDiff(sampleDiff, DiffViewOptions{})
+	assert.NotContains(t, expanded.Text, "unchanged")
+	assert.Equal(t, []int{2, 13}, expanded.Hunks)
+}
+
+func TestDiffFormatter_RenderDiffSideBySide(t *testing.T) {
+	view := NewDiffFormatter(nil).RenderDiff(sampleDiff, DiffViewOptions{Mode: DiffModeSideBySide, Width: 81})
+	lines := strings.Split(ansiPattern.ReplaceAllString(view.Text, ""), "\n")
+	require.Len(t, lines, 16)
+
+	changed := lines[8]
+	assert.Equal(t, `   6     fmt.Println("hello world")     │    6     fmt.Println("hello there")    `, changed)
+	for _, line := range lines[3:12] {
+		assert.Equal(t, 81, runewidth.StringWidth(line), "rows fill the width: %q", line)
+	}
+
+	// Lines too long for a column are cut with an ellipsis
+	long := "@@ -1 +1 @@\n-" + strings.Repeat("x", 80) + "\n+y\n"
+	lines = strings.Split(ansiPattern.ReplaceAllString(NewDiffFormatter(nil).RenderDiff(long, DiffViewOptions{Mode: DiffModeSideBySide, Width: 41}).Text, ""), "\n")
+	assert.Equal(t, "   1 "+strings.Repeat("x", 13)+"… │    1 y"+strings.Repeat(" ", 13), lines[1])
+}
@@ -0,0 +1,189 @@
+package presentation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	// The formats viewImage reads that the standard library decodes;
+	// others get a placeholder
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+const (
+	// thumbnailCols and thumbnailRows bound a thumbnail in cells. Each cell
+	// shows two pixels stacked, so the box is 40x24 roughly square pixels.
+	thumbnailCols = 40
+	thumbnailRows = 12
+
+	upperHalfBlock = "▀"
+	lowerHalfBlock = "▄"
+)
+
+// FormatImageResult formats a viewImage result: a line naming the image,
+// followed by a thumbnail when the config shows images and the image
+// decodes. data is the image file; when nil it is decoded from the
+// result's data_base64.
+//
+// gocui cannot pass kitty, iTerm2 or sixel images through a view, so the
+// thumbnail is drawn with colored half blocks, which every terminal able
+// to show images can also show.
+func FormatImageResult(result map[string]any, data []byte, config *types.Config) string {
+	theme := GetThemeForMode(config.Theme, config.OutputMode)
+	tertiaryColor := ConvertColorToAnsi(theme.TextTertiary)
+	resetColor := "\033[0m"
+
+	if success, _ := result["success"].(bool); !success {
+		message, _ := result["error"].(string)
+		if message == "" {
+			message = "unknown error"
+		}
+		return fmt.Sprintf("\n%s└─ %s%s", tertiaryColor, message, resetColor)
+	}
+
+	if data == nil {
+		if encoded, ok := result["data_base64"].(string); ok {
+			data, _ = base64.StdEncoding.DecodeString(encoded)
+		}
+	}
+
+	var details []string
+	if path, _ := result["path"].(string); path != "" {
+		details = append(details, path)
+	}
+	if imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		details = append(details, fmt.Sprintf("%d×%d", imageConfig.Width, imageConfig.Height))
+	}
+	if size := resultSize(result["size_bytes"]); size > 0 {
+		details = append(details, formatImageSize(size))
+	}
+	label := strings.TrimSpace("[image] " + strings.Join(details, " · "))
+	header := fmt.Sprintf("\n%s└─ %s%s", tertiaryColor, label, resetColor)
+
+	if !config.ShowsInlineImages() || len(data) == 0 {
+		return header
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		mimeType, _ := result["mime_type"].(string)
+		return header + fmt.Sprintf("\n   %s(no preview for %s)%s", tertiaryColor, mimeType, resetColor)
+	}
+
+	var lines []string
+	for _, row := range renderThumbnail(img, thumbnailCols, thumbnailRows) {
+		lines = append(lines, "   "+row)
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+// renderThumbnail draws img in at most cols x rows cells, two pixels per
+// cell: the upper half block in the top pixel's color over the bottom
+// pixel's color. Images are shrunk, never enlarged, keeping their aspect
+// ratio. Transparent pixels show the terminal's background.
+func renderThumbnail(img image.Image, cols, rows int) []string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil
+	}
+
+	scale := min(float64(cols)/float64(width), float64(rows*2)/float64(height), 1)
+	targetWidth := max(1, int(float64(width)*scale))
+	targetHeight := max(1, int(float64(height)*scale))
+
+	pixels := make([][]thumbnailPixel, targetHeight)
+	for y := range pixels {
+		pixels[y] = make([]thumbnailPixel, targetWidth)
+		y0 := bounds.Min.Y + y*height/targetHeight
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*height/targetHeight)
+		for x := range pixels[y] {
+			x0 := bounds.Min.X + x*width/targetWidth
+			x1 := max(x0+1, bounds.Min.X+(x+1)*width/targetWidth)
+			pixels[y][x] = averagePixel(img, x0, y0, x1, y1)
+		}
+	}
+
+	profile := ActiveColorProfile()
+	resetColor := "\033[0m"
+	var lines []string
+	for y := 0; y < targetHeight; y += 2 {
+		var line strings.Builder
+		for x := 0; x < targetWidth; x++ {
+			top := pixels[y][x]
+			bottom := thumbnailPixel{}
+			if y+1 < targetHeight {
+				bottom = pixels[y+1][x]
+			}
+			switch {
+			case top.opaque && bottom.opaque:
+				line.WriteString(ansiColor(profile, top.hex, false) + ansiColor(profile, bottom.hex, true) + upperHalfBlock + resetColor)
+			case top.opaque:
+				line.WriteString(ansiColor(profile, top.hex, false) + upperHalfBlock + resetColor)
+			case bottom.opaque:
+				line.WriteString(ansiColor(profile, bottom.hex, false) + lowerHalfBlock + resetColor)
+			default:
+				line.WriteString(" ")
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// thumbnailPixel is one pixel of a thumbnail, as a hex color
+type thumbnailPixel struct {
+	hex    string
+	opaque bool
+}
+
+// averagePixel averages the colors of the pixels in [x0,x1) x [y0,y1).
+// The area counts as transparent when most of it is.
+func averagePixel(img image.Image, x0, y0, x1, y1 int) thumbnailPixel {
+	var r, g, b, a, count uint64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			pr, pg, pb, pa := img.At(x, y).RGBA()
+			r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+			count++
+		}
+	}
+	if a < count*0x8000 {
+		return thumbnailPixel{}
+	}
+	// The colors are alpha-premultiplied; dividing by the alpha undoes it
+	return thumbnailPixel{
+		hex:    fmt.Sprintf("#%02x%02x%02x", r*0xff/a, g*0xff/a, b*0xff/a),
+		opaque: true,
+	}
+}
+
+// resultSize reads size_bytes, an int64 from the tool or a float64 once
+// the result has been through JSON
+func resultSize(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// formatImageSize formats a file size for people, e.g. "12.3 KB"
+func formatImageSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,68 @@
+package presentation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// twoBandImage is red over blue, with a transparent right half when
+// transparent is set
+func twoBandImage(width, height int, transparent bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if y >= height/2 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			if transparent && x >= width/2 {
+				c = color.NRGBA{}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestFormatImageResultDrawsThumbnail(t *testing.T) {
+	var encoded bytes.Buffer
+	require.NoError(t, png.Encode(&encoded, twoBandImage(4, 4, false)))
+	result := map[string]any{
+		"success":     true,
+		"path":        "logo.png",
+		"mime_type":   "image/png",
+		"size_bytes":  int64(2048),
+		"data_base64": base64.StdEncoding.EncodeToString(encoded.Bytes()),
+	}
+
+	output := FormatImageResult(result, nil, &types.Config{InlineImages: "enabled"})
+	lines := strings.Split(output, "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "└─ [image] logo.png · 4×4 · 2.0 KB", ansiPattern.ReplaceAllString(lines[1], ""))
+	red := "\033[38;2;255;0;0m\033[48;2;255;0;0m" + upperHalfBlock + "\033[0m"
+	blue := "\033[38;2;0;0;255m\033[48;2;0;0;255m" + upperHalfBlock + "\033[0m"
+	assert.Equal(t, "   "+strings.Repeat(red, 4), lines[2])
+	assert.Equal(t, "   "+strings.Repeat(blue, 4), lines[3])
+
+	placeholder := FormatImageResult(result, nil, &types.Config{InlineImages: "disabled"})
+	assert.Equal(t, "\n└─ [image] logo.png · 4×4 · 2.0 KB", ansiPattern.ReplaceAllString(placeholder, ""))
+	assert.NotContains(t, FormatToolResult("viewImage", result, nil, &types.Config{InlineImages: "disabled"}), result["data_base64"])
+}
+
+func TestRenderThumbnailFitsAndKeepsTransparency(t *testing.T) {
+	lines := renderThumbnail(twoBandImage(400, 100, true), thumbnailCols, thumbnailRows)
+	require.Len(t, lines, 5, "400x100 shrinks to 40x10 pixels, two per row")
+	for _, line := range lines {
+		plain := ansiPattern.ReplaceAllString(line, "")
+		assert.Equal(t, strings.Repeat(upperHalfBlock, 20)+strings.Repeat(" ", 20), plain)
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/mattn/go-runewidth"
 )
 
 type MessageFormatter struct {
@@ -30,6 +30,13 @@ func NewMessageFormatter(config *types.Config, theme *types.Theme) (*MessageForm
 }
 
 func (f *MessageFormatter) FormatMessageWithWidth(msg types.Message, width int) string {
+	return f.FormatMessageWithCodeIndex(msg, width, 1)
+}
+
+// FormatMessageWithCodeIndex formats msg like FormatMessageWithWidth, labelling
+// its code blocks starting at firstCodeIndex so labels stay unique across the
+// transcript.
+func (f *MessageFormatter) FormatMessageWithCodeIndex(msg types.Message, width int, firstCodeIndex int) string {
 	var output strings.Builder
 
 	roleColor := f.getRoleColor(msg.Role)
@@ -37,8 +44,8 @@ func (f *MessageFormatter) FormatMessageWithWidth(msg types.Message, width int)
 
 	header := fmt.Sprintf("%s%s\033[0m ", roleColor, rolePrefix)
 
-	if f.config.ShowTimestamps {
-		timestamp := time.Now().Format("15:04:05")
+	if f.config.ShowTimestamps && !msg.Time.IsZero() {
+		timestamp := msg.Time.Local().Format("15:04:05")
 		header = fmt.Sprintf("[%s] %s", timestamp, header)
 	}
 
@@ -53,9 +60,8 @@ func (f *MessageFormatter) FormatMessageWithWidth(msg types.Message, width int)
 		errorColor := ConvertColorToAnsi(f.theme.Error)
 		content = fmt.Sprintf("%s%s%s", errorColor, content, "\033[0m")
 	} else if msg.ContentType == "thought" {
-		// Apply muted styling for thought messages (internal AI thinking)
-		mutedColor := ConvertColorToAnsi(f.theme.Muted)
-		content = fmt.Sprintf("%s%s%s", mutedColor, content, "\033[0m")
+		// The model's reasoning is a muted block set off by a bar
+		content = f.formatThoughtBlock(content, width)
 	} else if (msg.Role == "user" || msg.Role == "system") && msg.ContentType != "markdown" {
 		// Apply role-specific text color for user and system messages (but not markdown)
 		textColor := f.getRoleTextColor(msg.Role)
@@ -64,25 +70,7 @@ func (f *MessageFormatter) FormatMessageWithWidth(msg types.Message, width int)
 
 	// Process markdown AFTER applying text colors (based on content type)
 	if f.config.IsMarkdownRenderingEnabled() && msg.ContentType == "markdown" {
-		// Create renderer with dynamic width instead of using cached one
-		renderer, err := createMarkdownRendererWithWidth(f.theme, f.config.Theme, f.config.GlamourTheme, width-2)
-		if err == nil {
-			rendered, err := renderer.Render(content)
-			if err == nil {
-				content = strings.TrimSpace(rendered)
-
-				// SOLUTION: Remove ANSI escape sequences only from the BEGINNING
-				// These invisible sequences at the start cause the "extra spaces" effect
-				// but we want to preserve colors in the rest of the content
-				ansiRegex := regexp.MustCompile(`^\x1b\[[0-9;]*m`)
-				for ansiRegex.MatchString(content) {
-					content = ansiRegex.ReplaceAllString(content, "")
-				}
-
-				// Trim again after removing leading ANSI sequences
-				content = strings.TrimSpace(content)
-			}
-		}
+		content = f.renderMarkdown(content, width, firstCodeIndex)
 	}
 
 	// Only apply additional wrapping if markdown rendering is disabled
@@ -92,11 +80,69 @@ func (f *MessageFormatter) FormatMessageWithWidth(msg types.Message, width int)
 	}
 
 	output.WriteString(content)
+	if summary := msg.TurnSummary(); f.config.ShowTimestamps && summary != "" {
+		fmt.Fprintf(&output, "\n  %s%s\033[0m", ConvertColorToAnsi(f.theme.Muted), summary)
+	}
 	output.WriteString("\n\n")
 
 	return output.String()
 }
 
+// renderMarkdown renders prose with glamour and fenced code blocks with
+// chroma, drawing an index label above each block. Content that fails to
+// render is returned unchanged.
+func (f *MessageFormatter) renderMarkdown(content string, width int, firstCodeIndex int) string {
+	// Create renderer with dynamic width instead of using cached one
+	renderer, err := createMarkdownRendererWithWidth(f.theme, f.config.Theme, f.config.GlamourTheme, width-2)
+	if err != nil {
+		return content
+	}
+	glamourStyle := getGlamourStyle(f.config.Theme, f.config.GlamourTheme)
+	labelColor := ConvertColorToAnsi(f.theme.Muted)
+
+	var parts []string
+	for _, segment := range splitCodeBlocks(content, firstCodeIndex) {
+		if segment.block != nil {
+			code := highlightCode(segment.block.Code, segment.block.Language, ActiveColorProfile(), glamourStyle)
+			parts = append(parts, "  "+formatCodeBlockLabel(*segment.block, labelColor)+"\n"+indentLines(code, "  "))
+			continue
+		}
+		if strings.TrimSpace(segment.text) == "" {
+			continue
+		}
+		rendered, err := renderer.Render(segment.text)
+		if err != nil {
+			return content
+		}
+		rendered = strings.TrimSpace(rendered)
+
+		// SOLUTION: Remove ANSI escape sequences only from the BEGINNING
+		// These invisible sequences at the start cause the "extra spaces" effect
+		// but we want to preserve colors in the rest of the content
+		ansiRegex := regexp.MustCompile(`^\x1b\[[0-9;]*m`)
+		for ansiRegex.MatchString(rendered) {
+			rendered = ansiRegex.ReplaceAllString(rendered, "")
+		}
+
+		// Trim again after removing leading ANSI sequences, keeping the
+		// document margin for segments that follow a code block
+		rendered = strings.TrimSpace(rendered)
+		if len(parts) > 0 {
+			rendered = "  " + rendered
+		}
+		parts = append(parts, rendered)
+	}
+	return strings.TrimLeft(strings.Join(parts, "\n\n"), " ")
+}
+
+func indentLines(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
 // getRoleColor returns accent colors for UI elements (indicators, prefixes)
 func (f *MessageFormatter) getRoleColor(role string) string {
 	var color string
@@ -137,7 +183,22 @@ func (f *MessageFormatter) getRoleTextColor(role string) string {
 	return ConvertColorToAnsi(color)
 }
 
+// accessibleRolePrefixes name each role in words, since the default
+// symbols differ only by color
+var accessibleRolePrefixes = map[string]string{
+	"user":      "You:",
+	"assistant": "Genie:",
+	"system":    "Info:",
+	"error":     "Error:",
+}
+
 func (f *MessageFormatter) getRolePrefix(role string) string {
+	if f.config.IsAccessibleEnabled() {
+		if prefix, ok := accessibleRolePrefixes[role]; ok {
+			return prefix
+		}
+		return accessibleRolePrefixes["user"]
+	}
 	switch role {
 	case "user":
 		return f.config.UserLabel
@@ -152,36 +213,77 @@ func (f *MessageFormatter) getRolePrefix(role string) string {
 	}
 }
 
+// formatThoughtBlock mutes reasoning and draws a bar down its left side,
+// or a plain "> " quote in accessible mode. Lines are wrapped first so the
+// bar runs the full height of the block.
+func (f *MessageFormatter) formatThoughtBlock(content string, width int) string {
+	bar := "│ "
+	if f.config.IsAccessibleEnabled() {
+		bar = "> "
+	}
+	if f.config.IsWrapMessagesEnabled() && width > 10 {
+		content = f.wrapText(content, width-4)
+	}
+	mutedColor := ConvertColorToAnsi(f.theme.Muted)
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = mutedColor + bar + line + "\033[0m"
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
+
+// wrapText breaks lines at spaces so none is wider than width terminal
+// cells. CJK characters and most emoji take two cells. Words wider than a
+// whole line, such as CJK sentences, which have no spaces, are split
+// between characters.
 func (f *MessageFormatter) wrapText(text string, width int) string {
 	var wrapped strings.Builder
 	lines := strings.Split(text, "\n")
 
 	for _, line := range lines {
-		if len(line) <= width {
+		if runewidth.StringWidth(line) <= width {
 			wrapped.WriteString(line)
 			wrapped.WriteString("\n")
 			continue
 		}
 
-		words := strings.Fields(line)
-		currentLine := ""
-
-		for _, word := range words {
-			if len(currentLine)+len(word)+1 > width {
-				wrapped.WriteString(currentLine)
-				wrapped.WriteString("\n")
-				currentLine = word
-			} else {
-				if currentLine != "" {
-					currentLine += " "
+		var currentLine strings.Builder
+		currentWidth := 0
+		breakLine := func() {
+			wrapped.WriteString(currentLine.String())
+			wrapped.WriteString("\n")
+			currentLine.Reset()
+			currentWidth = 0
+		}
+
+		for _, word := range strings.Fields(line) {
+			wordWidth := runewidth.StringWidth(word)
+			if currentWidth > 0 && currentWidth+wordWidth+1 > width {
+				breakLine()
+			}
+			for wordWidth > width {
+				head := runewidth.Truncate(word, width, "")
+				if head == "" {
+					break // A single character wider than the line
 				}
-				currentLine += word
+				currentLine.WriteString(head)
+				breakLine()
+				word = word[len(head):]
+				wordWidth = runewidth.StringWidth(word)
+			}
+			if word == "" {
+				continue
 			}
+			if currentWidth > 0 {
+				currentLine.WriteString(" ")
+				currentWidth++
+			}
+			currentLine.WriteString(word)
+			currentWidth += wordWidth
 		}
 
-		if currentLine != "" {
-			wrapped.WriteString(currentLine)
-			wrapped.WriteString("\n")
+		if currentWidth > 0 {
+			breakLine()
 		}
 	}
 
@@ -258,6 +360,7 @@ func createMarkdownRendererWithWidth(theme *types.Theme, themeName string, glamo
 	return glamour.NewTermRenderer(
 		glamour.WithStandardStyle(glamourStyle),
 		glamour.WithWordWrap(width),
+		glamour.WithColorProfile(ActiveColorProfile().termenvProfile()),
 	)
 }
 
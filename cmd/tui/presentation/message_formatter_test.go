@@ -0,0 +1,77 @@
+package presentation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageFormatterAccessibleLabels(t *testing.T) {
+	config := &types.Config{Theme: "default", UserLabel: "○", ErrorLabel: "●", MarkdownRendering: "disabled"}
+	formatter, err := NewMessageFormatter(config, GetThemeForMode("default", "true"))
+	require.NoError(t, err)
+
+	msg := types.Message{Role: "error", Content: "boom"}
+	assert.Contains(t, formatter.FormatMessageWithWidth(msg, 80), "●")
+
+	// Errors must not be told apart by color alone
+	config.Accessible = "enabled"
+	formatted := formatter.FormatMessageWithWidth(msg, 80)
+	assert.Contains(t, formatted, "Error:")
+	assert.NotContains(t, formatted, "●")
+	assert.Contains(t, formatter.FormatMessageWithWidth(types.Message{Role: "user", Content: "hi"}, 80), "You:")
+}
+
+func TestMessageFormatterThoughtBlock(t *testing.T) {
+	config := &types.Config{Theme: "default", AssistantLabel: "●", MarkdownRendering: "disabled"}
+	formatter, err := NewMessageFormatter(config, GetThemeForMode("default", "true"))
+	require.NoError(t, err)
+
+	msg := types.Message{Role: "assistant", Content: "Thought (2 lines, :thoughts to collapse)\nstep one\nstep two", ContentType: "thought"}
+	formatted := formatter.FormatMessageWithWidth(msg, 80)
+	assert.Contains(t, formatted, "│ step one")
+	assert.Contains(t, formatted, "│ step two")
+
+	config.Accessible = "enabled"
+	formatted = formatter.FormatMessageWithWidth(msg, 80)
+	assert.Contains(t, formatted, "> step one")
+	assert.NotContains(t, formatted, "│")
+}
+
+func TestMessageFormatterWrapTextWideCharacters(t *testing.T) {
+	formatter, err := NewMessageFormatter(&types.Config{Theme: "default"}, GetThemeForMode("default", "true"))
+	require.NoError(t, err)
+
+	// Each CJK character and emoji takes two cells
+	for _, line := range strings.Split(formatter.wrapText("日本語のテキストを折り返す 🎉🎉🎉 done", 10), "\n") {
+		assert.LessOrEqual(t, runewidth.StringWidth(line), 10, "line %q", line)
+	}
+	assert.Equal(t, "日本語のテ\nキストを折\nり返す\n🎉🎉🎉\ndone", formatter.wrapText("日本語のテキストを折り返す 🎉🎉🎉 done", 10))
+	assert.Equal(t, "short line", formatter.wrapText("short line", 10))
+}
+
+func TestMessageFormatterTimestampsAndTurnSummary(t *testing.T) {
+	config := &types.Config{Theme: "default", MarkdownRendering: "disabled"}
+	formatter, err := NewMessageFormatter(config, GetThemeForMode("default", "true"))
+	require.NoError(t, err)
+
+	sent := time.Date(2026, 10, 16, 9, 30, 5, 0, time.Local)
+	msg := types.Message{Role: "assistant", Content: "done", Time: sent, Duration: 14200 * time.Millisecond, ToolCalls: 3}
+	formatted := formatter.FormatMessageWithWidth(msg, 80)
+	assert.NotContains(t, formatted, "09:30:05")
+	assert.NotContains(t, formatted, "answered in")
+
+	config.ShowTimestamps = true
+	formatted = formatter.FormatMessageWithWidth(msg, 80)
+	assert.Contains(t, formatted, "[09:30:05]")
+	assert.Contains(t, formatted, "answered in 14.2s, 3 tool calls")
+
+	// Messages restored without a time get no made-up one
+	formatted = formatter.FormatMessageWithWidth(types.Message{Role: "user", Content: "hi"}, 80)
+	assert.NotRegexp(t, `\[\d\d:\d\d:\d\d\]`, formatted)
+}
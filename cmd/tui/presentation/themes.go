@@ -336,39 +336,28 @@ var Themes = map[string]*Theme{
 	},
 }
 
-// GetThemeColor converts a theme hex color to gocui.Attribute using gocui.GetColor
+// GetThemeColor converts a theme hex color to gocui.Attribute, degraded
+// to the active color profile
 func GetThemeColor(hexColor string) gocui.Attribute {
+	if len(hexColor) == 7 && hexColor[0] == '#' {
+		if index := paletteIndex(ActiveColorProfile(), hexColor); index >= 0 {
+			return gocui.Get256Color(int32(index))
+		}
+	}
 	return gocui.GetColor(hexColor)
 }
 
-// ConvertColorToAnsi converts hex color to ANSI escape sequence for text coloring
+// ConvertColorToAnsi converts hex color to the ANSI escape sequence for
+// text coloring, degraded to the active color profile: true color where
+// the terminal has it, the nearest palette color otherwise
 func ConvertColorToAnsi(hexColor string) string {
-	// Simple conversion - most modern terminals support hex colors directly
-	// in ANSI escape sequences, but for maximum compatibility, we can convert
-	// to RGB and use the standard ANSI true color format
-
-	if len(hexColor) == 7 && hexColor[0] == '#' {
-		// Convert hex to RGB
-		r, g, b := hexToRGB(hexColor)
-		// Return ANSI true color escape sequence
-		return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
-	}
-
-	// Return empty string for invalid or empty hex
-	return ""
+	// Returns an empty string for invalid or empty hex
+	return ansiColor(ActiveColorProfile(), hexColor, false)
 }
 
 // ConvertColorToAnsiBg converts hex color to ANSI escape sequence for background coloring
 func ConvertColorToAnsiBg(hexColor string) string {
-	if len(hexColor) == 7 && hexColor[0] == '#' {
-		// Convert hex to RGB
-		r, g, b := hexToRGB(hexColor)
-		// Return ANSI true color escape sequence for background
-		return fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b)
-	}
-
-	// Return empty string for invalid or empty hex
-	return ""
+	return ansiColor(ActiveColorProfile(), hexColor, true)
 }
 
 // hexToRGB converts hex color to RGB values
@@ -396,6 +385,8 @@ func GetThemeFocusColors(theme *types.Theme) (gocui.Attribute, gocui.Attribute)
 
 // GetTheme returns the theme by name
 func GetTheme(name string) *Theme {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
 	if theme, ok := Themes[name]; ok {
 		return theme
 	}
@@ -404,11 +395,9 @@ func GetTheme(name string) *Theme {
 
 // GetThemeNames returns all available theme names
 func GetThemeNames() []string {
-	names := make([]string, 0, len(Themes))
-	for name := range Themes {
-		names = append(names, name)
-	}
-	return names
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	return themeNames()
 }
 
 // Legacy compatibility functions (these can be removed once all code is updated)
@@ -173,6 +173,11 @@ func (f *TodoFormatter) getCheckbox(status string) string {
 
 // parseTodos converts interface{} todos to []TodoItem
 func (f *TodoFormatter) parseTodos(todosInterface interface{}) ([]TodoItem, error) {
+	return ParseTodoItems(todosInterface)
+}
+
+// ParseTodoItems converts the "todos" field of a todo tool result to []TodoItem
+func ParseTodoItems(todosInterface interface{}) ([]TodoItem, error) {
 	switch v := todosInterface.(type) {
 	case []map[string]interface{}:
 		todos := make([]TodoItem, 0, len(v))
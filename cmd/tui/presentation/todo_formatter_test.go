@@ -73,3 +73,18 @@ func TestTodoFormatter_Checkboxes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTodoItems(t *testing.T) {
+	todos, err := ParseTodoItems([]map[string]interface{}{
+		{"id": "1", "content": "Plan", "status": "completed", "priority": "high"},
+		{"id": "2", "content": "Build", "status": "in_progress", "priority": "medium"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []TodoItem{
+		{ID: "1", Content: "Plan", Status: "completed", Priority: "high"},
+		{ID: "2", Content: "Build", Status: "in_progress", Priority: "medium"},
+	}, todos)
+
+	_, err = ParseTodoItems("not todos")
+	assert.Error(t, err)
+}
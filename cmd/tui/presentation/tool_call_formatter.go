@@ -1,18 +1,23 @@
 package presentation
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/mattn/go-runewidth"
 )
 
 // FormatToolCall formats tool calls for display in the chat interface
 func FormatToolCall(toolName string, params map[string]any, config *types.Config) string {
-	// Special case for TodoWrite - just show "Updated Todos"
-	if toolName == "TodoWrite" {
+	// Special case for todo tools - the list itself is shown as the result
+	switch toolName {
+	case "TodoWrite":
 		return "Updated Todos"
+	case "TodoRead":
+		return "Read Todos"
 	}
 
 	// Get theme colors for formatting
@@ -35,8 +40,9 @@ func FormatToolCall(toolName string, params map[string]any, config *types.Config
 		switch v := value.(type) {
 		case string:
 			// Truncate long strings
-			if len(v) > 50 {
-				valueStr = fmt.Sprintf(`"%s..."`, v[:50])
+			v = StripANSI(v)
+			if runewidth.StringWidth(v) > 50 {
+				valueStr = fmt.Sprintf(`"%s..."`, runewidth.Truncate(v, 50, ""))
 			} else {
 				valueStr = fmt.Sprintf(`"%s"`, v)
 			}
@@ -77,8 +83,13 @@ func FormatToolResult(toolName string, result map[string]any, todoFormatter *Tod
 		return formatWriteFileResult(result, config)
 	}
 
+	// Images are named and shown as thumbnails, never as base64
+	if toolName == "viewImage" {
+		return FormatImageResult(result, nil, config)
+	}
+
 	// Handle todo tools with special formatting
-	if toolName == "TodoWrite" && todoFormatter != nil {
+	if (toolName == "TodoWrite" || toolName == "TodoRead") && todoFormatter != nil {
 		// Use TodoFormatter for todo tools
 		formattedTodos := todoFormatter.FormatTodoToolResult(result)
 
@@ -109,6 +120,8 @@ func FormatToolResult(toolName string, result map[string]any, todoFormatter *Tod
 		preview = output
 	} else if data, ok := result["data"].(string); ok && data != "" {
 		preview = data
+	} else if text, ok := result["text"].(string); ok && text != "" {
+		preview = text
 	} else {
 		// Fallback to first string value found
 		for _, v := range result {
@@ -120,8 +133,9 @@ func FormatToolResult(toolName string, result map[string]any, todoFormatter *Tod
 	}
 
 	if preview != "" {
-		// Clean up the preview
-		preview = strings.TrimSpace(preview)
+		// Clean up the preview; colored output keeps its colors in the
+		// theme's terms, resets falling back to the preview's own color
+		preview = strings.TrimSpace(SanitizeANSI(preview, theme, tertiaryColor))
 
 		// Show first N lines of the preview (with smart truncation)
 		const maxLines = 3
@@ -142,9 +156,7 @@ func FormatToolResult(toolName string, result map[string]any, todoFormatter *Tod
 				break
 			}
 			// Trim long lines
-			if len(line) > 80 {
-				line = line[:77] + "..."
-			}
+			line = truncateANSI(line, 80, "...")
 			if i == 0 {
 				// First line gets the L-shaped character
 				resultLines = append(resultLines, fmt.Sprintf("%s└─ %s%s", tertiaryColor, line, resetColor))
@@ -165,6 +177,22 @@ func FormatToolResult(toolName string, result map[string]any, todoFormatter *Tod
 	return ""
 }
 
+// ToolResultText returns the full text of a tool result for copying: the
+// same field the preview shows when it is text, otherwise the whole result
+// as indented JSON
+func ToolResultText(result map[string]any) string {
+	for _, key := range []string{"content", "output", "data", "results", "result"} {
+		if text, ok := result[key].(string); ok && text != "" {
+			return text
+		}
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Sprint(result)
+	}
+	return string(data)
+}
+
 // formatWriteFileResult formats writeFile tool results with diff content
 func formatWriteFileResult(result map[string]any, config *types.Config) string {
 	if len(result) == 0 {
@@ -0,0 +1,23 @@
+package presentation
+
+import (
+	"testing"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolResultText(t *testing.T) {
+	assert.Equal(t, "file contents", ToolResultText(map[string]any{"content": "file contents", "success": true}))
+	assert.Equal(t, "ok", ToolResultText(map[string]any{"output": "ok"}))
+	assert.Equal(t, "{\n  \"count\": 2\n}", ToolResultText(map[string]any{"count": 2}))
+}
+
+func TestFormatToolResult_SanitizesOutput(t *testing.T) {
+	config := &types.Config{Theme: "default"}
+	preview := FormatToolResult("bash", map[string]any{"output": "\x1b[2J\x1b[32mok\x1b[0m  \tpkg\x1b]0;title\a"}, nil, config)
+	assert.NotContains(t, preview, "\x1b[2J")
+	assert.NotContains(t, preview, "title")
+	assert.Contains(t, preview, "ok")
+	assert.Equal(t, "ok  \tpkg", StripANSI(preview)[len("\n└─ "):])
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/component"
+	"github.com/kcaldas/genie/cmd/tui/recovery"
+	"github.com/kcaldas/genie/pkg/logging"
+)
+
+// snapshot captures what a crash would lose: the transcript and the
+// message being typed
+func (app *App) snapshot() *recovery.Snapshot {
+	s := &recovery.Snapshot{SavedAt: time.Now()}
+	if messages, ok := app.layoutManager.GetComponent("messages").(*component.MessagesComponent); ok {
+		s.Messages = messages.Messages()
+	}
+	if input, ok := app.layoutManager.GetComponent("input").(*component.InputComponent); ok {
+		s.Input = input.Text()
+	}
+	return s
+}
+
+// saveSnapshot keeps the transcript and draft in .genie/recovery, noting
+// reason as what ended the TUI, and returns the file's path. It saves
+// nothing when there is nothing to restore.
+func (app *App) saveSnapshot(reason string) (string, error) {
+	snapshot := app.snapshot()
+	snapshot.Reason = reason
+	if snapshot.Empty() {
+		return "", nil
+	}
+	dir, err := recovery.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return recovery.Save(dir, snapshot)
+}
+
+// offerRecovery tells the user when the last run crashed and left a
+// snapshot to restore
+func (app *App) offerRecovery() {
+	dir, err := recovery.DefaultDir()
+	if err != nil {
+		return
+	}
+	snapshot, _, err := recovery.Latest(dir)
+	if err != nil {
+		logging.GetGlobalLogger().Warn("failed to read crash recovery snapshot", "error", err)
+		return
+	}
+	if snapshot == nil {
+		return
+	}
+	app.notification.AddSystemMessage(fmt.Sprintf("Genie stopped unexpectedly (%s) on %s and saved %d messages and your unsent input. :recover restores them, :recover discard deletes them.",
+		snapshot.Reason, snapshot.SavedAt.Local().Format("Jan 2 at 15:04"), len(snapshot.Messages)))
+}
+
+// recoverPanic restores the terminal after the TUI panicked with value,
+// saves what was on screen to .genie/recovery and prints the panic and its
+// stack to out. It returns the error the TUI exits with.
+func (t *TUI) recoverPanic(value any, out io.Writer) error {
+	stack := string(debug.Stack())
+	// The terminal must be usable again before anything is printed
+	t.Stop()
+
+	snapshot := t.app.snapshot()
+	snapshot.Reason = fmt.Sprint(value)
+	snapshot.Stack = stack
+
+	fmt.Fprintf(out, "Genie crashed: %v\n\n%s\n", value, stack)
+	if snapshot.Empty() {
+		return fmt.Errorf("genie crashed: %v", value)
+	}
+	dir, err := recovery.DefaultDir()
+	if err == nil {
+		var path string
+		if path, err = recovery.Save(dir, snapshot); err == nil {
+			fmt.Fprintf(out, "Your conversation and unsent input were saved to %s.\nStart Genie here again and run :recover to restore them.\n\n", path)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(out, "Could not save your conversation: %v\n\n", err)
+	}
+	return fmt.Errorf("genie crashed: %v", value)
+}
@@ -0,0 +1,107 @@
+// Package recovery keeps what the TUI had on screen when it crashed or was
+// killed: the transcript and the unsent input. The TUI saves a snapshot
+// when it panics or receives SIGTERM or SIGHUP and offers to restore the
+// newest one on the next launch.
+package recovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+)
+
+// Snapshot is what the TUI had when it crashed. Reason is the panic or
+// the signal that ended it.
+type Snapshot struct {
+	SavedAt  time.Time       `json:"saved_at"`
+	Reason   string          `json:"reason"`
+	Stack    string          `json:"stack,omitempty"`
+	Input    string          `json:"input,omitempty"`
+	Messages []types.Message `json:"messages,omitempty"`
+}
+
+// Empty reports whether the snapshot holds nothing worth restoring.
+func (s *Snapshot) Empty() bool {
+	return strings.TrimSpace(s.Input) == "" && len(s.Messages) == 0
+}
+
+// Dir returns where the snapshots of projectRoot are kept.
+func Dir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".genie", "recovery")
+}
+
+// DefaultDir returns the snapshot directory of the current directory,
+// where Genie keeps the project's .genie.
+func DefaultDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return Dir(cwd), nil
+}
+
+// Save writes s to dir and returns the file's path.
+func Save(dir string, s *Snapshot) (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode recovery snapshot: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, s.SavedAt.UTC().Format("20060102-150405.000")+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write recovery snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// Latest returns the newest snapshot in dir and its path, or nil when
+// there is none.
+func Latest(dir string) (*Snapshot, string, error) {
+	paths, err := list(dir)
+	if err != nil || len(paths) == 0 {
+		return nil, "", err
+	}
+	path := paths[len(paths)-1]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read recovery snapshot: %w", err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &s, path, nil
+}
+
+// Discard deletes every snapshot in dir.
+func Discard(dir string) error {
+	paths, err := list(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to delete recovery snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// list returns the snapshot files in dir, oldest first; their names sort
+// by the time they were saved
+func list(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
@@ -0,0 +1,45 @@
+package recovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/cmd/tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLatest(t *testing.T) {
+	dir := Dir(t.TempDir())
+
+	snapshot, _, err := Latest(dir)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	first := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	_, err = Save(dir, &Snapshot{SavedAt: first, Reason: "first", Input: "draft"})
+	require.NoError(t, err)
+	path, err := Save(dir, &Snapshot{
+		SavedAt:  first.Add(time.Minute),
+		Reason:   "second",
+		Messages: []types.Message{{Role: "assistant", Content: "Done.", ContentType: "markdown"}},
+	})
+	require.NoError(t, err)
+
+	snapshot, latest, err := Latest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, path, latest)
+	assert.Equal(t, "second", snapshot.Reason)
+	assert.Equal(t, "Done.", snapshot.Messages[0].Content)
+	assert.False(t, snapshot.Empty())
+
+	require.NoError(t, Discard(dir))
+	snapshot, _, err = Latest(dir)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+}
+
+func TestSnapshot_Empty(t *testing.T) {
+	assert.True(t, (&Snapshot{Input: "  \n"}).Empty())
+	assert.False(t, (&Snapshot{Input: "half a thought"}).Empty())
+}
@@ -2,9 +2,11 @@ package shell
 
 import (
 	"strings"
+	"unicode/utf8"
 
 	"github.com/awesome-gocui/gocui"
 	"github.com/kcaldas/genie/cmd/history"
+	"github.com/mattn/go-runewidth"
 )
 
 // BasicShell implements the gocui.Editor interface and provides core shell functionalities.
@@ -17,8 +19,9 @@ type BasicShell struct {
 
 	buffer       string // Clean command buffer
 	postDisplay  string // Suggestion text after cursor
-	cursorPos    int
-	scrollOffset int // Horizontal scroll offset for long input
+	replacement  string // Suggestion that replaces the buffer instead of extending it
+	cursorPos    int    // Byte offset in buffer, always at the start of a character
+	scrollOffset int    // Horizontal scroll offset for long input, in terminal cells
 }
 
 // NewBasicShell creates a new instance of BasicShell.
@@ -51,17 +54,17 @@ func (s *BasicShell) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modif
 	switch key {
 	case gocui.KeyArrowLeft:
 		if s.cursorPos > 0 {
-			s.cursorPos--
+			s.cursorPos = s.previousChar()
 		}
 	case gocui.KeyArrowRight:
 		if s.cursorPos < len(s.buffer) {
-			s.cursorPos++
+			s.cursorPos = s.nextChar()
 		}
 	case gocui.KeyArrowUp:
 	case gocui.KeyArrowDown:
 	case gocui.KeyDelete:
 		if s.cursorPos < len(s.buffer) {
-			s.buffer = s.buffer[:s.cursorPos] + s.buffer[s.cursorPos+1:]
+			s.buffer = s.buffer[:s.cursorPos] + s.buffer[s.nextChar():]
 		}
 	default:
 		if IsUnboundSpecialKey(key) {
@@ -69,7 +72,7 @@ func (s *BasicShell) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modif
 		}
 		if ch != 0 {
 			s.buffer = s.buffer[:s.cursorPos] + string(ch) + s.buffer[s.cursorPos:]
-			s.cursorPos++
+			s.cursorPos += utf8.RuneLen(ch)
 		} else if key == gocui.KeySpace {
 			s.buffer = s.buffer[:s.cursorPos] + " " + s.buffer[s.cursorPos:]
 			s.cursorPos++
@@ -88,13 +91,20 @@ func (s *BasicShell) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modif
 func (s *BasicShell) updateSuggestion() {
 	if s.cursorPos == len(s.buffer) {
 		suggestion := s.completer.Suggest(s.buffer)
-		if suggestion != "" && suggestion != s.buffer {
-			s.postDisplay = suggestion[len(s.buffer):]
-		} else {
+		s.replacement = ""
+		switch {
+		case suggestion == "" || suggestion == s.buffer:
 			s.postDisplay = ""
+		case strings.HasPrefix(suggestion, s.buffer):
+			s.postDisplay = suggestion[len(s.buffer):]
+		default:
+			// Fuzzy suggestions don't extend what was typed; show them as a hint
+			s.postDisplay = "  → " + suggestion
+			s.replacement = suggestion
 		}
 	} else {
 		s.postDisplay = ""
+		s.replacement = ""
 	}
 }
 
@@ -108,13 +118,16 @@ func (s *BasicShell) render(v *gocui.View) {
 		return
 	}
 
+	// Columns are terminal cells: CJK characters and most emoji take two
+	cursorCol := runewidth.StringWidth(s.buffer[:s.cursorPos])
+
 	// Calculate scroll offset to keep cursor visible
-	if s.cursorPos < s.scrollOffset {
+	if cursorCol < s.scrollOffset {
 		// Cursor moved left of visible area
-		s.scrollOffset = s.cursorPos
-	} else if s.cursorPos >= s.scrollOffset+width-1 {
+		s.scrollOffset = cursorCol
+	} else if cursorCol >= s.scrollOffset+width-1 {
 		// Cursor moved right of visible area (leave 1 char margin)
-		s.scrollOffset = s.cursorPos - width + 2
+		s.scrollOffset = cursorCol - width + 2
 		if s.scrollOffset < 0 {
 			s.scrollOffset = 0
 		}
@@ -122,40 +135,63 @@ func (s *BasicShell) render(v *gocui.View) {
 
 	// Clear and render visible portion of buffer
 	v.Clear()
-
-	// Calculate visible text
-	visibleBuffer := s.buffer
-	if s.scrollOffset > 0 && s.scrollOffset < len(visibleBuffer) {
-		visibleBuffer = visibleBuffer[s.scrollOffset:]
-	}
-
-	// Truncate if still too long
-	if len(visibleBuffer) > width {
-		visibleBuffer = visibleBuffer[:width]
-	}
-
-	v.Write([]byte(visibleBuffer))
+	v.Write([]byte(visibleCells(s.buffer, s.scrollOffset, width)))
 
 	// Add suggestion if at end of buffer and have room
 	if s.postDisplay != "" && s.cursorPos == len(s.buffer) {
-		remainingSpace := width - (s.cursorPos - s.scrollOffset)
+		remainingSpace := width - (cursorCol - s.scrollOffset)
 		if remainingSpace > 0 {
-			suggestionText := s.postDisplay
-			if len(suggestionText) > remainingSpace {
-				suggestionText = suggestionText[:remainingSpace]
-			}
+			suggestionText := runewidth.Truncate(s.postDisplay, remainingSpace, "")
 			v.Write([]byte("\x1b[2m" + suggestionText + "\x1b[0m"))
 		}
 	}
 
-	// Set cursor position relative to scroll offset
-	cursorX := s.cursorPos - s.scrollOffset
+	// Set cursor position relative to scroll offset. The input does not
+	// wrap, so the view takes the cursor in cells rather than characters.
+	cursorX := cursorCol - s.scrollOffset
 	if cursorX < 0 {
 		cursorX = 0
 	} else if cursorX >= width {
 		cursorX = width - 1
 	}
-	v.SetCursor(cursorX, 0)
+	v.SetCursorUnrestricted(cursorX, 0)
+}
+
+// visibleCells returns the part of text that fits in width cells starting
+// at column from. A wide character cut by the left edge becomes spaces so
+// the rest stays in place; one cut by the right edge is left out.
+func visibleCells(text string, from, width int) string {
+	var visible strings.Builder
+	col, used := 0, 0
+	for _, r := range text {
+		start := col
+		col += runewidth.RuneWidth(r)
+		switch {
+		case col <= from:
+			continue
+		case start < from:
+			visible.WriteString(strings.Repeat(" ", col-from))
+			used += col - from
+			continue
+		case used+col-start > width:
+			return visible.String()
+		}
+		visible.WriteRune(r)
+		used += col - start
+	}
+	return visible.String()
+}
+
+// previousChar returns where the character before the cursor starts
+func (s *BasicShell) previousChar() int {
+	_, size := utf8.DecodeLastRuneInString(s.buffer[:s.cursorPos])
+	return s.cursorPos - size
+}
+
+// nextChar returns where the character after the cursor ends
+func (s *BasicShell) nextChar() int {
+	_, size := utf8.DecodeRuneInString(s.buffer[s.cursorPos:])
+	return s.cursorPos + size
 }
 
 // GetInputBuffer returns the current content of the input buffer.
@@ -229,7 +265,7 @@ func (s *BasicShell) ResetHistoryNavigation() {
 // triggerCompletion attempts to trigger and display a completion.
 func (s *BasicShell) triggerCompletion(v *gocui.View) {
 	suggestion := s.completer.Suggest(s.buffer)
-	if suggestion != "" && strings.HasPrefix(suggestion, s.buffer) && suggestion != s.buffer {
+	if suggestion != "" && suggestion != s.buffer {
 		s.buffer = suggestion
 		s.cursorPos = len(s.buffer)
 		s.render(v)
@@ -240,6 +276,15 @@ func (s *BasicShell) triggerCompletion(v *gocui.View) {
 func (s *BasicShell) applyCompletionIfPossible(v *gocui.View) bool {
 	s.updateSuggestion()
 
+	if s.replacement != "" {
+		s.buffer = s.replacement
+		s.cursorPos = len(s.buffer)
+		s.postDisplay = ""
+		s.replacement = ""
+		s.render(v)
+		return true
+	}
+
 	if s.postDisplay != "" {
 		s.buffer = s.buffer + s.postDisplay
 		s.cursorPos = len(s.buffer)
@@ -254,8 +299,9 @@ func (s *BasicShell) applyCompletionIfPossible(v *gocui.View) bool {
 // handleBackspace performs backspace operation on our shell abstraction
 func (s *BasicShell) handleBackspace(v *gocui.View) {
 	if len(s.buffer) > 0 && s.cursorPos > 0 {
-		s.buffer = s.buffer[:s.cursorPos-1] + s.buffer[s.cursorPos:]
-		s.cursorPos--
+		start := s.previousChar()
+		s.buffer = s.buffer[:start] + s.buffer[s.cursorPos:]
+		s.cursorPos = start
 	}
 
 	s.render(v)
@@ -306,7 +352,8 @@ func (s *BasicShell) findNextWordBoundary() int {
 	return pos
 }
 
-// isWhitespace checks if a character is whitespace
+// isWhitespace checks if a character is whitespace. Bytes of multi-byte
+// characters are never ASCII whitespace, so scanning bytes is safe.
 func (s *BasicShell) isWhitespace(ch byte) bool {
 	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
 }
@@ -352,7 +399,7 @@ func (s *BasicShell) MoveBackwardChar(v *gocui.View) {
 	s.buffer = s.extractCleanBuffer(viewBuffer)
 
 	if s.cursorPos > 0 {
-		s.cursorPos--
+		s.cursorPos = s.previousChar()
 	}
 	s.render(v)
 }
@@ -4,7 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/awesome-gocui/gocui"
 	"github.com/kcaldas/genie/cmd/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestView is a minimal interface needed for testing BasicShell methods
@@ -444,3 +447,32 @@ func TestBasicShell_HistoryIntegration(t *testing.T) {
 		}
 	})
 }
+
+// TestBasicShell_WideCharacters types, moves over and deletes characters
+// that take several bytes and two cells
+func TestBasicShell_WideCharacters(t *testing.T) {
+	g, err := gocui.NewGui(gocui.OutputSimulator, true)
+	require.NoError(t, err)
+	t.Cleanup(g.Close)
+	v, _ := g.SetView("input", 0, 0, 11, 2, 0)
+
+	shell := NewBasicShell(NewCompleter(), history.NewChatHistory("", false))
+	for _, ch := range "日本🎉" {
+		shell.Edit(v, 0, ch, gocui.ModNone)
+	}
+	assert.Equal(t, "日本🎉", shell.GetInputBuffer())
+	x, _ := v.Cursor()
+	assert.Equal(t, 6, x, "the cursor counts cells")
+
+	shell.Edit(v, gocui.KeyArrowLeft, 0, gocui.ModNone)
+	shell.Edit(v, gocui.KeyBackspace2, 0, gocui.ModNone)
+	assert.Equal(t, "日🎉", shell.GetInputBuffer())
+	shell.Edit(v, gocui.KeyDelete, 0, gocui.ModNone)
+	assert.Equal(t, "日", shell.GetInputBuffer())
+}
+
+func TestVisibleCells(t *testing.T) {
+	assert.Equal(t, "日本", visibleCells("日本語", 0, 5))
+	assert.Equal(t, " 本語", visibleCells("日本語", 1, 5), "a character cut on the left becomes a space")
+	assert.Equal(t, "abc", visibleCells("abc", 0, 10))
+}
@@ -0,0 +1,52 @@
+package shell
+
+import (
+	"strings"
+)
+
+const modelCommandPrefix = ":model "
+
+// ModelSuggester suggests model names from the known-model catalog after
+// ":model "
+type ModelSuggester struct {
+	names []string
+}
+
+// NewModelSuggester creates a model suggester for the given model names
+func NewModelSuggester(names []string) *ModelSuggester {
+	return &ModelSuggester{
+		names: names,
+	}
+}
+
+// GetSuggestions returns ":model <name>" suggestions for names starting with
+// what has been typed so far
+func (ms *ModelSuggester) GetSuggestions(input string) []string {
+	if !ms.ShouldSuggest(input) {
+		return []string{}
+	}
+
+	query := strings.ToLower(strings.TrimPrefix(input, modelCommandPrefix))
+	suggestions := make([]string, 0)
+	for _, name := range ms.names {
+		if name == query {
+			// Already complete
+			return []string{}
+		}
+		if strings.HasPrefix(strings.ToLower(name), query) {
+			suggestions = append(suggestions, modelCommandPrefix+name)
+		}
+	}
+	return suggestions
+}
+
+// ShouldSuggest returns true while the model name is being typed
+func (ms *ModelSuggester) ShouldSuggest(input string) bool {
+	return strings.HasPrefix(input, modelCommandPrefix) &&
+		!strings.Contains(strings.TrimPrefix(input, modelCommandPrefix), " ")
+}
+
+// GetPrefix returns the prefix this suggester handles
+func (ms *ModelSuggester) GetPrefix() string {
+	return modelCommandPrefix
+}
@@ -0,0 +1,27 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelSuggester_Suggestions(t *testing.T) {
+	suggester := NewModelSuggester([]string{"claude-sonnet-4-5", "gemini-2.5-flash", "gemini-2.5-pro"})
+
+	assert.Equal(t, []string{":model gemini-2.5-flash", ":model gemini-2.5-pro"}, suggester.GetSuggestions(":model gem"))
+	assert.Equal(t, []string{":model claude-sonnet-4-5"}, suggester.GetSuggestions(":model CL"))
+	assert.Empty(t, suggester.GetSuggestions(":model gemini-2.5-pro"))
+	assert.Empty(t, suggester.GetSuggestions(":model gpt"))
+	assert.Empty(t, suggester.GetSuggestions(":model reset now"))
+	assert.Empty(t, suggester.GetSuggestions(":help"))
+}
+
+func TestModelSuggester_WithCompleter(t *testing.T) {
+	completer := NewCompleter()
+	completer.RegisterSuggester(NewCommandSuggester(&MockRegistryForTesting{commandNames: []string{"model"}}))
+	completer.RegisterSuggester(NewModelSuggester([]string{"gemini-2.5-pro"}))
+
+	assert.Equal(t, ":model", completer.Suggest(":mo"))
+	assert.Equal(t, ":model gemini-2.5-pro", completer.Suggest(":model gemini"))
+}
@@ -0,0 +1,57 @@
+package shell
+
+import (
+	"strings"
+
+	"github.com/kcaldas/genie/cmd/prompttemplates"
+)
+
+const promptCommandPrefix = ":prompt "
+
+// PromptTemplateManager interface defines what the PromptTemplateSuggester needs from a prompt template manager
+type PromptTemplateManager interface {
+	GetTemplateNames() []string
+}
+
+// PromptTemplateSuggester suggests prompt template names after ":prompt ",
+// fuzzy-matching what has been typed so far
+type PromptTemplateSuggester struct {
+	manager PromptTemplateManager
+}
+
+// NewPromptTemplateSuggester creates a new prompt template suggester with a prompt template manager
+func NewPromptTemplateSuggester(manager PromptTemplateManager) *PromptTemplateSuggester {
+	return &PromptTemplateSuggester{
+		manager: manager,
+	}
+}
+
+// GetSuggestions returns ":prompt <name>" suggestions, best fuzzy match first
+func (ps *PromptTemplateSuggester) GetSuggestions(input string) []string {
+	if !ps.ShouldSuggest(input) {
+		return []string{}
+	}
+
+	query := strings.TrimPrefix(input, promptCommandPrefix)
+	names := prompttemplates.FuzzyMatch(query, ps.manager.GetTemplateNames())
+	suggestions := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == query {
+			// Already complete
+			return []string{}
+		}
+		suggestions = append(suggestions, promptCommandPrefix+name)
+	}
+	return suggestions
+}
+
+// ShouldSuggest returns true while the template name is being typed
+func (ps *PromptTemplateSuggester) ShouldSuggest(input string) bool {
+	return strings.HasPrefix(input, promptCommandPrefix) &&
+		!strings.Contains(strings.TrimPrefix(input, promptCommandPrefix), " ")
+}
+
+// GetPrefix returns the prefix this suggester handles
+func (ps *PromptTemplateSuggester) GetPrefix() string {
+	return promptCommandPrefix
+}
@@ -0,0 +1,37 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPromptTemplateManager struct {
+	names []string
+}
+
+func (m *mockPromptTemplateManager) GetTemplateNames() []string {
+	return m.names
+}
+
+func TestPromptTemplateSuggester_Suggestions(t *testing.T) {
+	suggester := NewPromptTemplateSuggester(&mockPromptTemplateManager{
+		names: []string{"code-review", "explain", "review"},
+	})
+
+	assert.Equal(t, []string{":prompt code-review", ":prompt explain", ":prompt review"}, suggester.GetSuggestions(":prompt "))
+	assert.Equal(t, []string{":prompt review", ":prompt code-review"}, suggester.GetSuggestions(":prompt rev"))
+	assert.Equal(t, []string{":prompt code-review"}, suggester.GetSuggestions(":prompt crv"))
+	assert.Empty(t, suggester.GetSuggestions(":prompt review"))
+	assert.Empty(t, suggester.GetSuggestions(":prompt review file=x"))
+	assert.Empty(t, suggester.GetSuggestions(":help"))
+}
+
+func TestPromptTemplateSuggester_WithCompleter(t *testing.T) {
+	completer := NewCompleter()
+	completer.RegisterSuggester(NewCommandSuggester(&MockRegistryForTesting{commandNames: []string{"prompt", "persona"}}))
+	completer.RegisterSuggester(NewPromptTemplateSuggester(&mockPromptTemplateManager{names: []string{"code-review"}}))
+
+	assert.Equal(t, ":prompt", completer.Suggest(":pr"))
+	assert.Equal(t, ":prompt code-review", completer.Suggest(":prompt cr"))
+}
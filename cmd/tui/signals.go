@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kcaldas/genie/pkg/logging"
+)
+
+// watchSignals closes the TUI cleanly when the process is asked to stop
+// and hands the job control signals to handleJobControl. SIGTERM and
+// SIGHUP, sent when the terminal window closes, also save the transcript
+// and the draft so :recover can bring them back.
+func (app *App) watchSignals() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, append([]os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}, jobControlSignals...)...)
+
+	go func() {
+		for sig := range sigChan {
+			if app.handleJobControl(sig) {
+				continue
+			}
+			// A second signal kills Genie the usual way if closing hangs
+			signal.Stop(sigChan)
+			app.terminate(sig)
+			return
+		}
+	}()
+}
+
+// terminate ends the TUI because of sig. An interrupt is the user quitting
+// and saves nothing.
+func (app *App) terminate(sig os.Signal) {
+	if sig != os.Interrupt {
+		if _, err := app.saveSnapshot(sig.String()); err != nil {
+			logging.GetGlobalLogger().Warn("failed to save crash recovery snapshot", "signal", sig.String(), "error", err)
+		}
+	}
+	app.gui.GetGui().Close()
+}
@@ -0,0 +1,73 @@
+//go:build !windows
+
+package tui
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/awesome-gocui/gocui"
+	"github.com/kcaldas/genie/pkg/logging"
+)
+
+// jobControlSignals are the signals the shell uses to stop and continue
+// Genie: SIGTSTP from kill -TSTP and SIGCONT from fg or bg.
+var jobControlSignals = []os.Signal{syscall.SIGTSTP, syscall.SIGCONT}
+
+// mouseTracking turns the terminal's mouse reporting back on; gocui only
+// enables it when its main loop starts
+const mouseTracking = "\x1b[?1003h\x1b[?1006h"
+
+// handleJobControl suspends or redraws the TUI for sig and reports whether
+// sig was a job control signal.
+func (app *App) handleJobControl(sig os.Signal) bool {
+	switch sig {
+	case syscall.SIGTSTP:
+		app.gui.GetGui().Update(func(*gocui.Gui) error { return app.suspend() })
+	case syscall.SIGCONT:
+		app.gui.GetGui().Update(func(*gocui.Gui) error { return app.continued() })
+	default:
+		return false
+	}
+	return true
+}
+
+// suspend gives the terminal back to the shell and stops Genie the way
+// Ctrl+Z stops any other program. It runs on the main loop, which stays
+// blocked until the shell continues Genie with fg, so nothing is drawn
+// while the shell owns the terminal.
+func (app *App) suspend() error {
+	gocui.Suspend()
+	app.resumed.Store(true)
+	// The terminal is in raw mode, so Ctrl+Z arrives as a key and Genie
+	// stops its process group itself, as the terminal would have
+	if err := syscall.Kill(0, syscall.SIGSTOP); err != nil {
+		app.resumed.Store(false)
+		logging.GetGlobalLogger().Warn("failed to suspend", "error", err)
+	}
+	return app.resume()
+}
+
+// continued redraws the TUI after something other than suspend stopped
+// Genie, such as kill -STOP. The shell resets the terminal when a job
+// stops, so the screen is set up again from scratch.
+func (app *App) continued() error {
+	if app.resumed.Swap(false) {
+		// suspend already restored the screen
+		return nil
+	}
+	gocui.Suspend()
+	return app.resume()
+}
+
+// resume takes the terminal back after suspend. The main loop redraws
+// every view at the terminal's current size on its next pass.
+func (app *App) resume() error {
+	if err := gocui.Resume(); err != nil {
+		return err
+	}
+	if app.gui.GetGui().Mouse {
+		os.Stdout.WriteString(mouseTracking)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package tui
+
+import "os"
+
+// jobControlSignals is empty: Windows has no job control.
+var jobControlSignals []os.Signal
+
+func (app *App) handleJobControl(os.Signal) bool {
+	return false
+}
+
+func (app *App) suspend() error {
+	app.notification.AddSystemMessage("Suspending with Ctrl+Z is not supported on Windows.")
+	return nil
+}
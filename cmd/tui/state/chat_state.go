@@ -2,6 +2,7 @@ package state
 
 import (
 	"sync"
+	"time"
 
 	"github.com/kcaldas/genie/cmd/tui/types"
 )
@@ -59,6 +60,9 @@ func (s *ChatState) AddMessage(msg types.Message) int64 {
 
 	s.nextID++
 	msg.ID = s.nextID
+	if msg.Time.IsZero() {
+		msg.Time = time.Now()
+	}
 	s.messages = append(s.messages, msg)
 
 	if len(s.messages) > s.maxMessages {
@@ -2,6 +2,7 @@ package state
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kcaldas/genie/cmd/tui/types"
 	"github.com/stretchr/testify/assert"
@@ -90,3 +91,17 @@ func TestChatState_RangeReadsReturnCopies(t *testing.T) {
 	ranged[1].Content = "mutated"
 	assert.Equal(t, "two", s.GetMessages()[1].Content, "GetMessageRange must not alias internal storage")
 }
+
+func TestChatState_AddMessageKeepsTime(t *testing.T) {
+	s := NewChatState(10)
+
+	before := time.Now()
+	s.AddMessage(types.Message{Role: "user", Content: "now"})
+	restored := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.AddMessage(types.Message{Role: "user", Content: "restored", Time: restored})
+
+	msgs := s.GetMessages()
+	require.Len(t, msgs, 2)
+	assert.False(t, msgs[0].Time.Before(before), "new messages are stamped when added")
+	assert.Equal(t, restored, msgs[1].Time, "restored messages keep their time")
+}
@@ -1,63 +1,193 @@
 package state
 
 import (
+	"strings"
 	"sync"
+	"time"
 )
 
-// DebugState manages debug display buffer for F12 panel
+// DebugEntry is one event shown in the F12 inspector.
+type DebugEntry struct {
+	ID       int
+	Turn     int // Chat turn the event happened in; 0 before the first turn
+	Time     time.Time
+	Type     string // Event topic, e.g. "tool.executed", or "log" for messages
+	ToolName string
+	Summary  string
+	Failed   bool
+	Details  map[string]any // Shown as pretty JSON when the entry is expanded
+}
+
+// DebugTurn identifies a chat turn the inspector groups entries under.
+type DebugTurn struct {
+	Number    int
+	RequestID string
+	Message   string
+}
+
+// DebugState holds the events shown in the F12 inspector, along with the
+// inspector's filter and which entries are expanded.
 // Note: This is only for displaying debug content in the TUI panel.
 // Actual debug logging is handled by the centralized logging system.
 type DebugState struct {
-	mu          sync.RWMutex
-	messages    []string
-	maxMessages int
+	mu         sync.RWMutex
+	entries    []DebugEntry
+	turns      []DebugTurn
+	nextID     int
+	maxEntries int
+	filter     string
+	expanded   map[int]bool
 }
 
 // NewDebugState creates a new debug state
 func NewDebugState() *DebugState {
 	return &DebugState{
-		messages:    []string{},
-		maxMessages: 1000,
+		entries:    []DebugEntry{},
+		maxEntries: 1000,
+		expanded:   make(map[int]bool),
 	}
 }
 
-// GetDebugMessages returns a copy of all debug messages
-func (s *DebugState) GetDebugMessages() []string {
+// StartTurn opens a new turn that later entries are grouped under.
+func (s *DebugState) StartTurn(requestID, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.turns = append(s.turns, DebugTurn{
+		Number:    len(s.turns) + 1,
+		RequestID: requestID,
+		Message:   message,
+	})
+}
+
+// GetTurns returns a copy of the turns seen so far
+func (s *DebugState) GetTurns() []DebugTurn {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	turns := make([]DebugTurn, len(s.turns))
+	copy(turns, s.turns)
+	return turns
+}
+
+// AddEntry records entry in the current turn and returns it with its ID set
+func (s *DebugState) AddEntry(entry DebugEntry) DebugEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	entry.ID = s.nextID
+	entry.Turn = len(s.turns)
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	s.entries = append(s.entries, entry)
+
+	// Trim old entries if we exceed the limit
+	if len(s.entries) > s.maxEntries {
+		// Keep the last 90% of entries
+		keepFrom := s.maxEntries / 10
+		for _, dropped := range s.entries[:keepFrom] {
+			delete(s.expanded, dropped.ID)
+		}
+		s.entries = s.entries[keepFrom:]
+	}
+	return entry
+}
+
+// GetEntries returns the entries matching the current filter
+func (s *DebugState) GetEntries() []DebugEntry {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	messagesCopy := make([]string, len(s.messages))
-	copy(messagesCopy, s.messages)
-	return messagesCopy
+	entries := make([]DebugEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if matchesDebugFilter(entry, s.filter) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
 }
 
-// AddDebugMessage adds a message to the display buffer (used for F12 panel display)
-func (s *DebugState) AddDebugMessage(msg string) {
+// matchesDebugFilter reports whether entry's type or tool name contains any
+// of the space-separated terms in filter, ignoring case.
+func matchesDebugFilter(entry DebugEntry, filter string) bool {
+	terms := strings.Fields(strings.ToLower(filter))
+	if len(terms) == 0 {
+		return true
+	}
+	eventType := strings.ToLower(entry.Type)
+	toolName := strings.ToLower(entry.ToolName)
+	for _, term := range terms {
+		if strings.Contains(eventType, term) || (toolName != "" && strings.Contains(toolName, term)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFilter limits GetEntries to the given event types or tool names; an
+// empty filter shows everything.
+func (s *DebugState) SetFilter(filter string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.filter = strings.TrimSpace(filter)
+}
 
-	s.messages = append(s.messages, msg)
+// GetFilter returns the current filter
+func (s *DebugState) GetFilter() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filter
+}
 
-	// Trim old messages if we exceed the limit
-	if len(s.messages) > s.maxMessages {
-		// Keep the last 90% of messages
-		keepFrom := s.maxMessages / 10
-		s.messages = s.messages[keepFrom:]
+// ToggleExpanded expands or collapses the entry with the given ID
+func (s *DebugState) ToggleExpanded(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.expanded[id] {
+		delete(s.expanded, id)
+	} else {
+		s.expanded[id] = true
 	}
 }
 
-// ClearDebugMessages clears all debug messages
+// IsExpanded reports whether the entry with the given ID is expanded
+func (s *DebugState) IsExpanded(id int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.expanded[id]
+}
+
+// GetDebugMessages returns the summaries of all entries, oldest first
+func (s *DebugState) GetDebugMessages() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]string, len(s.entries))
+	for i, entry := range s.entries {
+		messages[i] = entry.Summary
+	}
+	return messages
+}
+
+// AddDebugMessage records a plain log message as a "log" entry
+func (s *DebugState) AddDebugMessage(msg string) {
+	s.AddEntry(DebugEntry{Type: "log", Summary: msg})
+}
+
+// ClearDebugMessages clears all entries. Turns are kept so numbering
+// carries on and new entries still group under the running turn.
 func (s *DebugState) ClearDebugMessages() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.messages = []string{}
+	s.entries = []DebugEntry{}
+	s.expanded = make(map[int]bool)
 }
 
-// SetMaxMessages sets the maximum number of debug messages to keep
+// SetMaxMessages sets the maximum number of entries to keep
 func (s *DebugState) SetMaxMessages(max int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if max > 0 {
-		s.maxMessages = max
+		s.maxEntries = max
 	}
 }
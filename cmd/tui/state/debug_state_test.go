@@ -0,0 +1,55 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugState_GroupsEntriesByTurn(t *testing.T) {
+	state := NewDebugState()
+
+	state.AddDebugMessage("startup")
+	state.StartTurn("req-1", "fix the tests")
+	state.AddEntry(DebugEntry{Type: "chat.started"})
+	state.AddEntry(DebugEntry{Type: "tool.executed", ToolName: "bash"})
+
+	entries := state.GetEntries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, 0, entries[0].Turn)
+	assert.Equal(t, 1, entries[1].Turn)
+	assert.Equal(t, 1, entries[2].Turn)
+	assert.Equal(t, []DebugTurn{{Number: 1, RequestID: "req-1", Message: "fix the tests"}}, state.GetTurns())
+
+	// Clearing keeps the running turn for later entries
+	state.ClearDebugMessages()
+	assert.Equal(t, 1, state.AddEntry(DebugEntry{Type: "chat.response"}).Turn)
+}
+
+func TestDebugState_Filter(t *testing.T) {
+	state := NewDebugState()
+	state.AddEntry(DebugEntry{Type: "tool.starting", ToolName: "readFile"})
+	state.AddEntry(DebugEntry{Type: "tool.executed", ToolName: "bash"})
+	state.AddEntry(DebugEntry{Type: "token.count"})
+
+	state.SetFilter("BASH token")
+	var types []string
+	for _, entry := range state.GetEntries() {
+		types = append(types, entry.Type)
+	}
+	assert.Equal(t, []string{"tool.executed", "token.count"}, types)
+
+	state.SetFilter("")
+	assert.Len(t, state.GetEntries(), 3)
+}
+
+func TestDebugState_ToggleExpanded(t *testing.T) {
+	state := NewDebugState()
+	entry := state.AddEntry(DebugEntry{Type: "tool.executed"})
+
+	state.ToggleExpanded(entry.ID)
+	assert.True(t, state.IsExpanded(entry.ID))
+	state.ToggleExpanded(entry.ID)
+	assert.False(t, state.IsExpanded(entry.ID))
+}
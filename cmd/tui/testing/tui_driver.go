@@ -27,6 +27,9 @@ type TUIDriver struct {
 
 // NewTUIDriver creates a new TUI driver for testing
 func NewTUIDriver(t *testing.T) *TUIDriver {
+	// Keep the commands tests type out of the user-wide history
+	t.Setenv("HOME", t.TempDir())
+
 	// Create genie test fixture
 	genieFixture := genietest.NewTestFixture(t)
 	session := genieFixture.StartAndGetSession()
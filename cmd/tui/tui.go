@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"os"
+	"sync"
+
 	"github.com/awesome-gocui/gocui"
 )
 
 type TUI struct {
-	app *App
+	app      *App
+	stopOnce sync.Once
 }
 
 // New creates a TUI with an injected App instance
@@ -17,8 +21,16 @@ func (t *TUI) Start() error {
 	return t.StartWithMessage("")
 }
 
-func (t *TUI) StartWithMessage(initialMessage string) error {
-	err := t.app.RunWithMessage(initialMessage)
+func (t *TUI) StartWithMessage(initialMessage string) (err error) {
+	// A panic in a key handler or layout would otherwise leave the
+	// terminal in raw mode and lose the conversation
+	defer func() {
+		if value := recover(); value != nil {
+			err = t.recoverPanic(value, os.Stderr)
+		}
+	}()
+
+	err = t.app.RunWithMessage(initialMessage)
 	// Handle gocui.ErrQuit as successful exit, not an error
 	if err == gocui.ErrQuit {
 		return nil
@@ -26,8 +38,10 @@ func (t *TUI) StartWithMessage(initialMessage string) error {
 	return err
 }
 
+// Stop restores the terminal. It is safe to call more than once, so
+// callers can stop early and still defer it.
 func (t *TUI) Stop() {
-	t.app.Close()
+	t.stopOnce.Do(t.app.Close)
 }
 
 // GetApp returns the internal App instance for testing
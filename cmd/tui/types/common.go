@@ -1,6 +1,10 @@
 package types
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	"github.com/awesome-gocui/gocui"
 )
 
@@ -8,7 +12,32 @@ type Message struct {
 	ID          int64 // Stable identifier assigned by ChatState; survives window slides
 	Role        string
 	Content     string
-	ContentType string // "text" or "markdown"
+	ContentType string    // "text" or "markdown"
+	Time        time.Time // When the message was added; set by ChatState
+
+	// Set on the answer that ends a turn: how long the turn took from the
+	// user's message and how many tools it called
+	Duration  time.Duration
+	ToolCalls int
+}
+
+// TurnSummary describes the turn m ends, such as "answered in 14.2s,
+// 3 tool calls", or returns "" for messages that end none.
+func (m Message) TurnSummary() string {
+	if m.Duration <= 0 {
+		return ""
+	}
+	elapsed := fmt.Sprintf("%.1fs", m.Duration.Seconds())
+	if m.Duration >= time.Minute {
+		elapsed = m.Duration.Round(time.Second).String()
+	}
+	switch m.ToolCalls {
+	case 0:
+		return "answered in " + elapsed
+	case 1:
+		return "answered in " + elapsed + ", 1 tool call"
+	}
+	return fmt.Sprintf("answered in %s, %d tool calls", elapsed, m.ToolCalls)
 }
 
 type BorderStyle string
@@ -89,9 +118,11 @@ type Config struct {
 
 	// Terminal output configuration
 	// OutputMode controls gocui color and Unicode support:
-	// - "true": 24-bit color with enhanced Unicode support (default, recommended)
-	// - "normal": 8-color mode with basic Unicode
+	// - "auto": detect what the terminal supports (default)
+	// - "true": 24-bit color with enhanced Unicode support
 	// - "256": 256-color mode
+	// - "16": 16 ANSI colors
+	// - "normal": 8-color mode with basic Unicode
 	OutputMode string
 
 	// Markdown rendering configuration
@@ -106,6 +137,12 @@ type Config struct {
 	// Set to "auto" to use theme-based mapping, or specify a specific diff theme
 	DiffTheme string
 
+	// DiffMode lays diffs out "unified" (default) or "side-by-side"
+	DiffMode string
+	// DiffCollapse folds long runs of unchanged lines in diffs: "enabled"
+	// (default) or "disabled"
+	DiffCollapse string
+
 	// Component border settings
 	ShowMessagesBorder string // "enabled" or "disabled" (default: "enabled")
 
@@ -116,8 +153,22 @@ type Config struct {
 	VimMode bool // Enable vim-style editing mode (default: false)
 
 	// Mouse configuration
-	EnableMouse string // Enable gocui mouse support for UI interactions: "enabled" or "disabled" (default: "enabled")
-	// When "disabled", allows terminal native text selection
+	EnableMouse string // Enable gocui mouse support for UI interactions: "enabled", "disabled" or "auto" (default: "auto")
+	// When "disabled", allows terminal native text selection. "auto" disables it over SSH and in tmux or screen.
+
+	// Clipboard selects how text is copied: "system", "osc52" (through the
+	// terminal) or "auto", which uses OSC 52 over SSH and the system
+	// clipboard otherwise (default: "auto")
+	Clipboard string
+
+	// InlineImages draws a thumbnail of images the viewImage tool reads in
+	// the messages view: "auto" (default: in terminals that can show
+	// images), "enabled" or "disabled"
+	InlineImages string
+
+	// StatusRefreshMs is how often the status spinner redraws, in
+	// milliseconds. 0 picks 100 locally and 500 over SSH.
+	StatusRefreshMs int
 
 	// Message role labels/symbols
 	UserLabel      string // Symbol for user messages (default: "○")
@@ -131,6 +182,29 @@ type Config struct {
 	// Persona management
 	PersonaCycleList []string // List of persona IDs for cycling through
 
+	// Completion notifications
+	Notifications                string // "enabled" or "disabled" (default: "disabled")
+	NotificationThresholdSeconds int    // Minimum turn duration before notifying (default: 30)
+
+	// Screen-reader friendly output: no spinners or box-drawing, status
+	// changes as plain lines and words instead of color-only symbols
+	Accessible string // "enabled" or "disabled" (default: "disabled")
+
+	// UpdateCheck shows a notice in the status bar when a newer release
+	// is out: "enabled" (default) or "disabled"
+	UpdateCheck string
+
+	// ShowThoughts requests the model's reasoning and shows it in a
+	// collapsed block above the answer: "enabled" or "disabled" (default)
+	ShowThoughts string
+
+	// Output controls sent with every message, overriding the persona's.
+	// Zero values keep the persona's own settings.
+	MaxOutputTokens  int      // Longest answer, in tokens
+	StopSequences    []string // The answer ends when the model writes one of these
+	FrequencyPenalty float64  // -2 to 2; discourages repeating frequent tokens
+	PresencePenalty  float64  // -2 to 2; discourages repeating any earlier token
+
 	Layout LayoutConfig
 }
 
@@ -147,6 +221,18 @@ type LayoutConfig struct {
 	ExpandedSidePanel bool
 	ShowBorders       bool       // Global borders on/off
 	FocusStyle        FocusStyle // Default focus style for all components
+	Geometry          LayoutGeometry
+}
+
+// LayoutGeometry is the persisted arrangement of the panels: the preset it
+// started from and the sizes the user resized them to. Column widths are
+// weights relative to each other; zero fields fall back to the defaults.
+type LayoutGeometry struct {
+	Preset         string // "single", "split", "debug", or empty for the default layout
+	LeftWeight     int    // Todo panel width
+	MessagesWeight int    // Messages panel width
+	RightWeight    int    // Side panel width (debug, help, diffs)
+	InputHeight    int    // Input panel rows, including its border
 }
 
 // IsStringBoolEnabled returns true if a string boolean field is enabled
@@ -165,9 +251,33 @@ func IsStringBoolEnabledWithDefault(value string) bool {
 	return value == "enabled" || value == "true" || value == ""
 }
 
-// IsMouseEnabled returns true if mouse is enabled in config
+// AccessibleEnv turns accessible mode on for one run, without saving it.
+// genie --accessible sets it.
+const AccessibleEnv = "GENIE_ACCESSIBLE"
+
+// IsAccessibleEnabled returns true if accessible mode is on in config or
+// for this run
+func (c *Config) IsAccessibleEnabled() bool {
+	env := os.Getenv(AccessibleEnv)
+	return IsStringBoolEnabled(c.Accessible) || IsStringBoolEnabled(env) || env == "1"
+}
+
+// IsMouseEnabled returns true if mouse is enabled in config for the
+// terminal Genie runs in
 func (c *Config) IsMouseEnabled() bool {
-	return IsStringBoolEnabledWithDefault(c.EnableMouse)
+	return c.IsMouseEnabledIn(DetectTerminalEnvironment())
+}
+
+// ShowsInlineImages returns true if viewed images get a thumbnail in the
+// terminal Genie runs in
+func (c *Config) ShowsInlineImages() bool {
+	return c.ShowsInlineImagesIn(DetectTerminalEnvironment())
+}
+
+// StatusRefresh returns how often the status spinner redraws in the
+// terminal Genie runs in
+func (c *Config) StatusRefresh() time.Duration {
+	return c.StatusRefreshIn(DetectTerminalEnvironment())
 }
 
 // IsShowCursorEnabled returns true if cursor is enabled in config
@@ -185,11 +295,31 @@ func (c *Config) IsWrapMessagesEnabled() bool {
 	return IsStringBoolEnabledWithDefault(c.WrapMessages)
 }
 
+// IsDiffCollapseEnabled returns true if unchanged regions of diffs are folded
+func (c *Config) IsDiffCollapseEnabled() bool {
+	return IsStringBoolEnabledWithDefault(c.DiffCollapse)
+}
+
 // IsShowMessagesBorderEnabled returns true if messages border is enabled in config
 func (c *Config) IsShowMessagesBorderEnabled() bool {
 	return IsStringBoolEnabledWithDefault(c.ShowMessagesBorder)
 }
 
+// IsShowThoughtsEnabled returns true if the model's reasoning is shown
+func (c *Config) IsShowThoughtsEnabled() bool {
+	return IsStringBoolEnabled(c.ShowThoughts)
+}
+
+// IsNotificationsEnabled returns true if completion notifications are enabled in config
+func (c *Config) IsNotificationsEnabled() bool {
+	return IsStringBoolEnabled(c.Notifications)
+}
+
+// IsUpdateCheckEnabled returns true if Genie looks for newer releases
+func (c *Config) IsUpdateCheckEnabled() bool {
+	return IsStringBoolEnabledWithDefault(c.UpdateCheck)
+}
+
 // IsShowSidebarEnabled returns true if sidebar is enabled in config
 func (lc *LayoutConfig) IsShowSidebarEnabled() bool {
 	return IsStringBoolEnabledWithDefault(lc.ShowSidebar)
@@ -2,6 +2,7 @@ package types
 
 import (
 	"github.com/awesome-gocui/gocui"
+	"github.com/kcaldas/genie/pkg/genie"
 )
 
 type Component interface {
@@ -72,5 +73,7 @@ type IStateAccessor interface {
 // LLMContextDataProvider is the interface components use to interact with the LLMContextController
 type LLMContextDataProvider interface {
 	GetContextData() map[string]string
+	GetContextUsage() *genie.ContextUsage // nil when the tokens could not be counted
+	IsContextPartEnabled(key string) bool
 	HandleComponentEvent(eventName string, data interface{}) error
 }
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMessageTurnSummary(t *testing.T) {
+	tests := []struct {
+		duration  time.Duration
+		toolCalls int
+		expected  string
+	}{
+		{0, 2, ""},
+		{1500 * time.Millisecond, 0, "answered in 1.5s"},
+		{14200 * time.Millisecond, 1, "answered in 14.2s, 1 tool call"},
+		{14200 * time.Millisecond, 3, "answered in 14.2s, 3 tool calls"},
+		{72400 * time.Millisecond, 12, "answered in 1m12s, 12 tool calls"},
+	}
+	for _, tt := range tests {
+		msg := Message{Role: "assistant", Duration: tt.duration, ToolCalls: tt.toolCalls}
+		if got := msg.TurnSummary(); got != tt.expected {
+			t.Errorf("TurnSummary() with %v and %d tool calls = %q, want %q", tt.duration, tt.toolCalls, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// Status spinner redraw intervals. Over SSH every redraw is a round trip,
+// so the spinner slows down rather than lag behind the keyboard.
+const (
+	LocalStatusRefresh  = 100 * time.Millisecond
+	RemoteStatusRefresh = 500 * time.Millisecond
+)
+
+// TerminalEnvironment is what Genie can tell about where its terminal
+// runs from the environment
+type TerminalEnvironment struct {
+	SSH    bool // Running over SSH, so the system clipboard is not the user's
+	Tmux   bool // Inside tmux, which needs escape sequences passed through
+	Screen bool // Inside GNU screen, likewise
+
+	// Graphics is the image protocol the terminal speaks: "kitty",
+	// "iterm2", "sixel", or empty when it is not known to show images
+	Graphics string
+}
+
+// DetectTerminalEnvironment inspects the environment of this process
+func DetectTerminalEnvironment() TerminalEnvironment {
+	return detectTerminalEnvironment(os.Getenv)
+}
+
+func detectTerminalEnvironment(getenv func(string) string) TerminalEnvironment {
+	return TerminalEnvironment{
+		SSH:    getenv("SSH_CONNECTION") != "" || getenv("SSH_CLIENT") != "" || getenv("SSH_TTY") != "",
+		Tmux:   getenv("TMUX") != "",
+		Screen: getenv("STY") != "" || (getenv("TMUX") == "" && strings.HasPrefix(getenv("TERM"), "screen")),
+
+		Graphics: detectGraphics(getenv),
+	}
+}
+
+// detectGraphics names the image protocol of the terminal, from the
+// variables the terminals that draw images set
+func detectGraphics(getenv func(string) string) string {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case term == "xterm-kitty" || getenv("KITTY_WINDOW_ID") != "" || term == "xterm-ghostty" || program == "ghostty":
+		return "kitty"
+	case program == "iTerm.app" || program == "WezTerm" || getenv("LC_TERMINAL") == "iTerm2":
+		return "iterm2"
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || term == "mlterm" || term == "contour":
+		return "sixel"
+	}
+	return ""
+}
+
+// Multiplexed reports whether a terminal multiplexer sits between Genie and
+// the terminal
+func (e TerminalEnvironment) Multiplexed() bool {
+	return e.Tmux || e.Screen
+}
+
+// IsMouseEnabledIn returns true if mouse support is on in env. "auto"
+// turns it off over SSH and inside tmux or screen, where mouse reporting
+// floods the connection and fights the multiplexer's own mouse handling.
+func (c *Config) IsMouseEnabledIn(env TerminalEnvironment) bool {
+	if c.EnableMouse == "auto" {
+		return !env.SSH && !env.Multiplexed()
+	}
+	return IsStringBoolEnabledWithDefault(c.EnableMouse)
+}
+
+// ShowsInlineImagesIn returns true if viewed images get a thumbnail in the
+// messages view in env. "auto" shows them in terminals that can draw
+// images, which all have the colors a thumbnail needs.
+func (c *Config) ShowsInlineImagesIn(env TerminalEnvironment) bool {
+	if c.IsAccessibleEnabled() {
+		return false // a picture made of characters means nothing to a screen reader
+	}
+	if c.InlineImages == "" || c.InlineImages == "auto" {
+		return env.Graphics != ""
+	}
+	return IsStringBoolEnabled(c.InlineImages)
+}
+
+// StatusRefreshIn returns how often the status spinner redraws in env. A
+// positive StatusRefreshMs overrides the automatic choice.
+func (c *Config) StatusRefreshIn(env TerminalEnvironment) time.Duration {
+	if c.StatusRefreshMs > 0 {
+		return time.Duration(c.StatusRefreshMs) * time.Millisecond
+	}
+	if env.SSH {
+		return RemoteStatusRefresh
+	}
+	return LocalStatusRefresh
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func envFrom(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestDetectTerminalEnvironment(t *testing.T) {
+	assert.Equal(t, TerminalEnvironment{}, detectTerminalEnvironment(envFrom(map[string]string{"TERM": "xterm-256color"})))
+	assert.Equal(t, TerminalEnvironment{SSH: true, Tmux: true},
+		detectTerminalEnvironment(envFrom(map[string]string{"SSH_CONNECTION": "10.0.0.1 52311 10.0.0.2 22", "TMUX": "/tmp/tmux-1000/default,1,0", "TERM": "screen-256color"})))
+	assert.Equal(t, TerminalEnvironment{Screen: true}, detectTerminalEnvironment(envFrom(map[string]string{"STY": "1234.pts-0.host"})))
+
+	assert.Equal(t, "kitty", detectTerminalEnvironment(envFrom(map[string]string{"TERM": "xterm-kitty"})).Graphics)
+	assert.Equal(t, "iterm2", detectTerminalEnvironment(envFrom(map[string]string{"TERM_PROGRAM": "WezTerm"})).Graphics)
+	assert.Equal(t, "sixel", detectTerminalEnvironment(envFrom(map[string]string{"TERM": "foot"})).Graphics)
+}
+
+func TestConfigAdaptsToTerminalEnvironment(t *testing.T) {
+	local := TerminalEnvironment{}
+	remote := TerminalEnvironment{SSH: true}
+	tmux := TerminalEnvironment{Tmux: true}
+
+	config := &Config{EnableMouse: "auto"}
+	assert.True(t, config.IsMouseEnabledIn(local))
+	assert.False(t, config.IsMouseEnabledIn(remote))
+	assert.False(t, config.IsMouseEnabledIn(tmux))
+
+	config.EnableMouse = "enabled"
+	assert.True(t, config.IsMouseEnabledIn(remote), "an explicit setting overrides detection")
+
+	assert.Equal(t, LocalStatusRefresh, config.StatusRefreshIn(local))
+	assert.Equal(t, RemoteStatusRefresh, config.StatusRefreshIn(remote))
+	config.StatusRefreshMs = 250
+	assert.Equal(t, 250*time.Millisecond, config.StatusRefreshIn(local))
+
+	kitty := TerminalEnvironment{Graphics: "kitty"}
+	assert.False(t, config.ShowsInlineImagesIn(local))
+	assert.True(t, config.ShowsInlineImagesIn(kitty))
+	config.InlineImages = "disabled"
+	assert.False(t, config.ShowsInlineImagesIn(kitty))
+	config.InlineImages = "enabled"
+	assert.True(t, config.ShowsInlineImagesIn(local))
+	config.Accessible = "enabled"
+	assert.False(t, config.ShowsInlineImagesIn(local), "screen readers get the placeholder")
+}
@@ -4,25 +4,28 @@
 package tui
 
 import (
-	"path/filepath"
-
 	"github.com/awesome-gocui/gocui"
 	"github.com/google/wire"
 	"github.com/kcaldas/genie/cmd/bootstrap"
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/history"
+	"github.com/kcaldas/genie/cmd/prompttemplates"
 	"github.com/kcaldas/genie/cmd/slashcommands"
 	"github.com/kcaldas/genie/cmd/tui/component"
 	"github.com/kcaldas/genie/cmd/tui/controllers"
 	"github.com/kcaldas/genie/cmd/tui/controllers/commands"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/layout"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/recovery"
 	"github.com/kcaldas/genie/cmd/tui/shell"
 	"github.com/kcaldas/genie/cmd/tui/state"
 	"github.com/kcaldas/genie/cmd/tui/types"
 	pkgEvents "github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/models"
 	"github.com/kcaldas/genie/pkg/logging"
+	"github.com/kcaldas/genie/pkg/metrics"
 )
 
 // ============================================================================
@@ -63,9 +66,24 @@ func ProvideEventBus(genieService genie.Genie) pkgEvents.EventBus {
 	return genieService.GetEventBus()
 }
 
-// ProvideSlashCommandManager provides a shared instance of SlashCommandManager
-func ProvideSlashCommandManager() *slashcommands.Manager {
-	return slashcommands.NewManager()
+// ProvideSlashCommandManager provides a shared instance of SlashCommandManager.
+// Untrusted workspaces only get the user's own commands.
+func ProvideSlashCommandManager(session genie.Session) *slashcommands.Manager {
+	manager := slashcommands.NewManager()
+	if !session.IsWorkspaceTrusted() {
+		manager.DisableProjectSources()
+	}
+	return manager
+}
+
+// ProvidePromptTemplateManager provides a shared instance of the prompt template manager.
+// Untrusted workspaces only get the user's own templates.
+func ProvidePromptTemplateManager(session genie.Session) *prompttemplates.Manager {
+	manager := prompttemplates.NewManager()
+	if !session.IsWorkspaceTrusted() {
+		manager.DisableProjectSources()
+	}
+	return manager
 }
 
 // ============================================================================
@@ -77,23 +95,31 @@ func ProvideConfigManager() (*helpers.ConfigManager, error) {
 	return nil, nil
 }
 
-func ProvideClipboard() *helpers.Clipboard {
+func ProvideClipboard(configManager *helpers.ConfigManager) *helpers.Clipboard {
 	wire.Build(helpers.NewClipboard)
 	return nil
 }
 
 // ProvideHistoryPath provides the chat history file path based on session's genie home directory
 func ProvideHistoryPath(session genie.Session) HistoryPath {
-	return HistoryPath(filepath.Join(session.GetGenieHomeDirectory(), ".genie", "history"))
+	return HistoryPath(history.ProjectHistoryPath(session.GetGenieHomeDirectory()))
 }
 
 func ProvideHistoryPathString(historyPath HistoryPath) string {
 	return string(historyPath)
 }
 
-// ProvideChatHistory provides a shared chat history manager
-func ProvideChatHistory(historyPath HistoryPath) history.ChatHistory {
-	return history.NewChatHistory(string(historyPath), true)
+// ProvideChatHistory provides a shared chat history manager. Commands are
+// stamped with the session and also kept in the user-wide history.
+func ProvideChatHistory(historyPath HistoryPath, session genie.Session) history.ChatHistory {
+	chatHistory := history.NewChatHistory(string(historyPath), true)
+	if fileHistory, ok := chatHistory.(*history.FileChatHistory); ok {
+		fileHistory.SetSessionID(session.GetID())
+		if userPath, err := history.UserHistoryPath(); err == nil {
+			fileHistory.SetUserHistoryPath(userPath)
+		}
+	}
+	return chatHistory
 }
 
 // ============================================================================
@@ -103,9 +129,12 @@ func ProvideChatHistory(historyPath HistoryPath) history.ChatHistory {
 // NewGocuiGui - Production GUI provider (uses config-based output mode)
 func NewGocuiGui(configManager *helpers.ConfigManager) (*gocui.Gui, error) {
 	config := configManager.GetConfig()
-	guiOutputMode := configManager.GetGocuiOutputMode(config.OutputMode)
 
-	g, err := gocui.NewGui(guiOutputMode, true)
+	// Theme colors written as ANSI escapes degrade to the palette gocui renders
+	profile := presentation.ColorProfileForMode(config.OutputMode)
+	presentation.SetColorProfile(profile)
+
+	g, err := gocui.NewGui(profile.GocuiOutputMode(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +174,14 @@ func ProvideDebugState() *state.DebugState {
 	return state.NewDebugState()
 }
 
+// ProvideMetricsCollector starts measuring turns for :stats. It stays
+// subscribed for the life of the TUI.
+func ProvideMetricsCollector(genieService genie.Genie) *metrics.Collector {
+	collector := metrics.NewCollector()
+	collector.Attach(genieService.GetEventBus())
+	return collector
+}
+
 func ProvideStateAccessor(chatState *state.ChatState, uiState *state.UIState) *state.StateAccessor {
 	return state.NewStateAccessor(chatState, uiState)
 }
@@ -162,6 +199,8 @@ func ProvideLayoutBuilder(
 	textViewerComponent *component.TextViewerComponent,
 	diffViewerComponent *component.DiffViewerComponent,
 	debugComponent *component.DebugComponent,
+	todoPanelComponent *component.TodoPanelComponent,
+	themeEditorComponent *component.ThemeEditorComponent,
 ) *LayoutBuilder {
 	return NewLayoutBuilder(
 		gui,
@@ -172,6 +211,8 @@ func ProvideLayoutBuilder(
 		textViewerComponent,
 		diffViewerComponent,
 		debugComponent,
+		todoPanelComponent,
+		themeEditorComponent,
 	)
 }
 
@@ -188,19 +229,19 @@ func ProvideMessagesComponent(gui types.Gui, chatState *state.ChatState, configM
 	return nil, nil
 }
 
-func ProvideInputComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, clipboard *helpers.Clipboard, chatHistory history.ChatHistory, commandSuggester *shell.CommandSuggester, slashCommandSuggester *shell.SlashCommandSuggester) (*component.InputComponent, error) {
+func ProvideInputComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, clipboard *helpers.Clipboard, chatHistory history.ChatHistory, commandSuggester *shell.CommandSuggester, slashCommandSuggester *shell.SlashCommandSuggester, promptTemplateSuggester *shell.PromptTemplateSuggester, modelSuggester *shell.ModelSuggester) (*component.InputComponent, error) {
 	wire.Build(
 		component.NewInputComponent,
 	)
 	return nil, nil
 }
 
-func ProvideStatusComponent(gui types.Gui, stateAccessor *state.StateAccessor, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus) (*component.StatusComponent, error) {
-	wire.Build(
-		wire.Bind(new(types.IStateAccessor), new(*state.StateAccessor)),
-		component.NewStatusComponent,
-	)
-	return nil, nil
+// ProvideStatusComponent provides the status bar, showing the session's
+// working directory
+func ProvideStatusComponent(gui types.Gui, stateAccessor *state.StateAccessor, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, session genie.Session) (*component.StatusComponent, error) {
+	statusComponent := component.NewStatusComponent(gui, stateAccessor, configManager, commandEventBus)
+	statusComponent.SetWorkDir(session.GetWorkingDirectory())
+	return statusComponent, nil
 }
 
 func ProvideTextViewerComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus) (*component.TextViewerComponent, error) {
@@ -224,6 +265,16 @@ func ProvideDebugComponent(gui types.Gui, debugState *state.DebugState, configMa
 	return nil, nil
 }
 
+func ProvideTodoPanelComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus) (*component.TodoPanelComponent, error) {
+	wire.Build(component.NewTodoPanelComponent)
+	return nil, nil
+}
+
+func ProvideThemeEditorComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus) (*component.ThemeEditorComponent, error) {
+	wire.Build(component.NewThemeEditorComponent)
+	return nil, nil
+}
+
 // ============================================================================
 // Controller Providers
 // ============================================================================
@@ -238,6 +289,15 @@ func ProvideDebugController(genieService genie.Genie, gui types.Gui, debugState
 	return nil, nil
 }
 
+func ProvideTodoController(genieService genie.Genie, gui types.Gui, todoPanelComponent *component.TodoPanelComponent, layoutManager *layout.LayoutManager) (*controllers.TodoController, error) {
+	wire.Build(controllers.NewTodoController)
+	return nil, nil
+}
+
+func ProvideThemeEditorController(gui types.Gui, layoutManager *layout.LayoutManager, themeEditorComponent *component.ThemeEditorComponent, inputComponent *component.InputComponent, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, notification types.Notification) *controllers.ThemeEditorController {
+	return controllers.NewThemeEditorController(gui, layoutManager, themeEditorComponent, inputComponent, configManager, commandEventBus, notification)
+}
+
 func ProvideChatController(messagesComponent *component.MessagesComponent, gui types.Gui, genieService genie.Genie, stateAccessor *state.StateAccessor, configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus) (*controllers.ChatController, error) {
 	wire.Build(
 		wire.Bind(new(types.Component), new(*component.MessagesComponent)),
@@ -255,7 +315,13 @@ func ProvideLLMContextController(gui types.Gui, genieService genie.Genie, layout
 	return nil, nil
 }
 
-func ProvideToolConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, eventBus pkgEvents.EventBus, commandEventBus *events.CommandEventBus) (*controllers.ToolConfirmationController, error) {
+// ProvideConfirmationQueue shares one queue between the tool and user
+// confirmation controllers, so their dialogs take turns
+func ProvideConfirmationQueue(stateAccessor *state.StateAccessor, commandEventBus *events.CommandEventBus) *controllers.ConfirmationQueue {
+	return controllers.NewConfirmationQueue(stateAccessor, commandEventBus)
+}
+
+func ProvideToolConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, confirmationQueue *controllers.ConfirmationQueue, eventBus pkgEvents.EventBus, commandEventBus *events.CommandEventBus) (*controllers.ToolConfirmationController, error) {
 	wire.Build(
 		wire.Bind(new(types.IStateAccessor), new(*state.StateAccessor)),
 		wire.Bind(new(types.Component), new(*component.InputComponent)),
@@ -264,7 +330,7 @@ func ProvideToolConfirmationController(gui types.Gui, stateAccessor *state.State
 	return nil, nil
 }
 
-func ProvideUserConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, diffViewerComponent *component.DiffViewerComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, eventBus pkgEvents.EventBus, commandEventBus *events.CommandEventBus) (*controllers.UserConfirmationController, error) {
+func ProvideUserConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, diffViewerComponent *component.DiffViewerComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, confirmationQueue *controllers.ConfirmationQueue, eventBus pkgEvents.EventBus, commandEventBus *events.CommandEventBus) (*controllers.UserConfirmationController, error) {
 	wire.Build(
 		wire.Bind(new(types.IStateAccessor), new(*state.StateAccessor)),
 		wire.Bind(new(types.Component), new(*component.InputComponent)),
@@ -278,6 +344,10 @@ func ProvideWriteController(gui types.Gui, configManager *helpers.ConfigManager,
 	return nil, nil
 }
 
+func ProvidePromptController(gui types.Gui, manager *prompttemplates.Manager, inputComponent *component.InputComponent, writeController *controllers.WriteController, layoutManager *layout.LayoutManager, notification types.Notification) *controllers.PromptController {
+	return controllers.NewPromptController(gui, manager, inputComponent, writeController, layoutManager, notification)
+}
+
 func ProvideSlashCommandController(commandEventBus *events.CommandEventBus, slashCommandManager *slashcommands.Manager, notification types.Notification) *controllers.SlashCommandController {
 	return controllers.NewSlashCommandController(commandEventBus, slashCommandManager, notification)
 }
@@ -298,8 +368,20 @@ func ProvideSlashCommandSuggester(manager *slashcommands.Manager) *shell.SlashCo
 	return shell.NewSlashCommandSuggester(manager)
 }
 
-func ProvideContextCommand(llmContextController *controllers.LLMContextController) *commands.ContextCommand {
-	return commands.NewContextCommand(llmContextController)
+func ProvidePromptTemplateSuggester(manager *prompttemplates.Manager) *shell.PromptTemplateSuggester {
+	return shell.NewPromptTemplateSuggester(manager)
+}
+
+func ProvideModelSuggester() *shell.ModelSuggester {
+	var names []string
+	for _, model := range models.All() {
+		names = append(names, model.Name)
+	}
+	return shell.NewModelSuggester(names)
+}
+
+func ProvideContextCommand(llmContextController *controllers.LLMContextController, genieService genie.Genie, notification types.Notification) *commands.ContextCommand {
+	return commands.NewContextCommand(llmContextController, genieService, notification)
 }
 
 func ProvideClearCommand(chatController *controllers.ChatController) *commands.ClearCommand {
@@ -322,8 +404,8 @@ func ProvideYankCommand(chatState *state.ChatState, clipboard *helpers.Clipboard
 	return commands.NewYankCommand(chatState, clipboard, chatController)
 }
 
-func ProvideThemeCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, chatController *controllers.ChatController) *commands.ThemeCommand {
-	return commands.NewThemeCommand(configManager, commandEventBus, chatController)
+func ProvideThemeCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, chatController *controllers.ChatController, themeEditorController *controllers.ThemeEditorController) *commands.ThemeCommand {
+	return commands.NewThemeCommand(configManager, commandEventBus, chatController, themeEditorController)
 }
 
 func ProvideConfigCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, gui types.Gui, chatController *controllers.ChatController) *commands.ConfigCommand {
@@ -346,6 +428,87 @@ func ProvidePersonaCommand(notification types.Notification, genieService genie.G
 	return commands.NewPersonaCommand(notification, genieService, commandEventBus, configManager)
 }
 
+func ProvideOutputCommand(chatController *controllers.ChatController) *commands.OutputCommand {
+	return commands.NewOutputCommand(chatController)
+}
+
+func ProvideThoughtsCommand(chatController *controllers.ChatController) *commands.ThoughtsCommand {
+	return commands.NewThoughtsCommand(chatController)
+}
+
+func ProvideUsageCommand(chatController *controllers.ChatController) *commands.UsageCommand {
+	return commands.NewUsageCommand(chatController)
+}
+
+func ProvideStatsCommand(collector *metrics.Collector, notification types.Notification) *commands.StatsCommand {
+	return commands.NewStatsCommand(collector, notification)
+}
+
+func ProvideModelCommand(notification types.Notification, genieService genie.Genie) *commands.ModelCommand {
+	return commands.NewModelCommand(notification, genieService)
+}
+
+func ProvideModeCommand(notification types.Notification, genieService genie.Genie) *commands.ModeCommand {
+	return commands.NewModeCommand(notification, genieService)
+}
+
+func ProvideRetryCommand(chatController *controllers.ChatController) *commands.RetryCommand {
+	return commands.NewRetryCommand(chatController, chatController)
+}
+
+func ProvideSampleCommand(chatController *controllers.ChatController) *commands.SampleCommand {
+	return commands.NewSampleCommand(chatController, chatController)
+}
+
+func ProvideSummarizeCommand(chatController *controllers.ChatController) *commands.SummarizeCommand {
+	return commands.NewSummarizeCommand(chatController, chatController)
+}
+
+func ProvideRecoverCommand(chatController *controllers.ChatController, inputComponent *component.InputComponent) *commands.RecoverCommand {
+	dir, err := recovery.DefaultDir()
+	if err != nil {
+		dir = recovery.Dir(".")
+	}
+	return commands.NewRecoverCommand(dir, chatController, inputComponent, chatController)
+}
+
+func ProvideSchemaCommand(chatController *controllers.ChatController) *commands.SchemaCommand {
+	return commands.NewSchemaCommand(chatController)
+}
+
+func ProvidePromptCommand(promptController *controllers.PromptController, notification types.Notification) *commands.PromptCommand {
+	return commands.NewPromptCommand(promptController, notification)
+}
+
+func ProvideTodosCommand(todoController *controllers.TodoController, chatController *controllers.ChatController) *commands.TodosCommand {
+	return commands.NewTodosCommand(todoController, chatController)
+}
+
+func ProvideDiffCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, notification types.Notification) *commands.DiffCommand {
+	return commands.NewDiffCommand(configManager, commandEventBus, notification)
+}
+
+func ProvideLayoutCommand(layoutManager *layout.LayoutManager, configManager *helpers.ConfigManager, notification types.Notification) *commands.LayoutCommand {
+	return commands.NewLayoutCommand(layoutManager, configManager, notification)
+}
+
+// ProvideBranchCommand saves branches in the project's .genie directory,
+// next to its history
+func ProvideBranchCommand(genieService genie.Genie, chatController *controllers.ChatController, session genie.Session, notification types.Notification) *commands.BranchCommand {
+	store := helpers.NewBranchStore(session.GetGenieHomeDirectory())
+	return commands.NewBranchCommand(genieService, chatController, store, notification)
+}
+
+// ProvideCdCommand provides :cd, which moves the input history along with
+// the project's .genie directory
+func ProvideCdCommand(genieService genie.Genie, chatController *controllers.ChatController, chatHistory history.ChatHistory, commandEventBus *events.CommandEventBus, notification types.Notification) *commands.CdCommand {
+	return commands.NewCdCommand(genieService, chatController, chatHistory, commandEventBus, notification)
+}
+
+func ProvideScopeCommand(genieService genie.Genie, notification types.Notification) *commands.ScopeCommand {
+	return commands.NewScopeCommand(genieService, notification)
+}
+
 func ProvideCommandHandler(
 	commandEventBus *events.CommandEventBus,
 	chatController *controllers.ChatController,
@@ -362,21 +525,57 @@ func ProvideCommandHandler(
 	writeCommand *commands.WriteCommand,
 	updateCommand *commands.UpdateCommand,
 	personaCommand *commands.PersonaCommand,
+	promptCommand *commands.PromptCommand,
+	outputCommand *commands.OutputCommand,
+	thoughtsCommand *commands.ThoughtsCommand,
+	usageCommand *commands.UsageCommand,
+	todosCommand *commands.TodosCommand,
+	layoutCommand *commands.LayoutCommand,
+	diffCommand *commands.DiffCommand,
+	schemaCommand *commands.SchemaCommand,
+	modelCommand *commands.ModelCommand,
+	modeCommand *commands.ModeCommand,
+	statsCommand *commands.StatsCommand,
+	branchCommand *commands.BranchCommand,
+	retryCommand *commands.RetryCommand,
+	sampleCommand *commands.SampleCommand,
+	summarizeCommand *commands.SummarizeCommand,
+	recoverCommand *commands.RecoverCommand,
+	cdCommand *commands.CdCommand,
+	scopeCommand *commands.ScopeCommand,
 ) *commands.CommandHandler {
 	handler := commands.NewCommandHandler(commandEventBus, chatController, registry)
 
 	// Register all commands (except help for now)
 	// Order of registration doesn't matter functionally, but keeping alphabetical for readability
+	handler.RegisterNewCommand(branchCommand)
+	handler.RegisterNewCommand(cdCommand)
 	handler.RegisterNewCommand(clearCommand)
 	handler.RegisterNewCommand(configCommand)
 	handler.RegisterNewCommand(contextCommand)
 	handler.RegisterNewCommand(debugCommand)
 	handler.RegisterNewCommand(demoCommand)
+	handler.RegisterNewCommand(diffCommand)
 	handler.RegisterNewCommand(exitCommand)
+	handler.RegisterNewCommand(modelCommand)
+	handler.RegisterNewCommand(modeCommand)
+	handler.RegisterNewCommand(outputCommand)
+	handler.RegisterNewCommand(thoughtsCommand)
 	handler.RegisterNewCommand(personaCommand)
+	handler.RegisterNewCommand(promptCommand)
+	handler.RegisterNewCommand(recoverCommand)
+	handler.RegisterNewCommand(retryCommand)
+	handler.RegisterNewCommand(sampleCommand)
+	handler.RegisterNewCommand(schemaCommand)
+	handler.RegisterNewCommand(scopeCommand)
+	handler.RegisterNewCommand(statsCommand)
 	handler.RegisterNewCommand(statusCommand)
+	handler.RegisterNewCommand(summarizeCommand)
 	handler.RegisterNewCommand(themeCommand)
+	handler.RegisterNewCommand(todosCommand)
+	handler.RegisterNewCommand(layoutCommand)
 	handler.RegisterNewCommand(updateCommand)
+	handler.RegisterNewCommand(usageCommand)
 	handler.RegisterNewCommand(writeCommand)
 	handler.RegisterNewCommand(yankCommand)
 
@@ -393,6 +592,7 @@ func InitializeConfirmationControllers(
 	toolController *controllers.ToolConfirmationController,
 	userController *controllers.UserConfirmationController,
 	slashCommandController *controllers.SlashCommandController,
+	promptController *controllers.PromptController,
 ) *ConfirmationInitializer {
 	// Controllers are created and have subscribed to events during construction
 	// We don't need to do anything with them here
@@ -409,6 +609,7 @@ var StateSet = wire.NewSet(
 	ProvideUIState,
 	ProvideDebugState,
 	ProvideStateAccessor,
+	ProvideMetricsCollector,
 )
 
 // ComponentSet - UI components
@@ -419,6 +620,8 @@ var ComponentSet = wire.NewSet(
 	ProvideTextViewerComponent,
 	ProvideDiffViewerComponent,
 	ProvideDebugComponent,
+	ProvideTodoPanelComponent,
+	ProvideThemeEditorComponent,
 )
 
 // LayoutSet - Layout management
@@ -438,8 +641,12 @@ var ControllerSet = wire.NewSet(
 	ProvideLLMContextController,
 	ProvideWriteController,
 	ProvideSlashCommandController,
+	ProvidePromptController,
+	ProvideTodoController,
+	ProvideThemeEditorController,
 
 	// Confirmation controllers
+	ProvideConfirmationQueue,
 	ProvideToolConfirmationController,
 	ProvideUserConfirmationController,
 	InitializeConfirmationControllers,
@@ -462,6 +669,24 @@ var CommandProvidersSet = wire.NewSet(
 	ProvideWriteCommand,
 	ProvideUpdateCommand,
 	ProvidePersonaCommand,
+	ProvideOutputCommand,
+	ProvideThoughtsCommand,
+	ProvideUsageCommand,
+	ProvidePromptCommand,
+	ProvideTodosCommand,
+	ProvideLayoutCommand,
+	ProvideDiffCommand,
+	ProvideSchemaCommand,
+	ProvideModelCommand,
+	ProvideModeCommand,
+	ProvideStatsCommand,
+	ProvideBranchCommand,
+	ProvideRetryCommand,
+	ProvideSampleCommand,
+	ProvideSummarizeCommand,
+	ProvideRecoverCommand,
+	ProvideCdCommand,
+	ProvideScopeCommand,
 )
 
 // CommandSet - All commands and command handler
@@ -470,6 +695,8 @@ var CommandSet = wire.NewSet(
 	ProvideCommandRegistry,
 	ProvideCommandSuggester,
 	ProvideSlashCommandSuggester,
+	ProvidePromptTemplateSuggester,
+	ProvideModelSuggester,
 
 	// All command providers
 	CommandProvidersSet,
@@ -498,6 +725,7 @@ var CoreServicesSet = wire.NewSet(
 	ProvideConfigManager,
 	ProvideClipboard,
 	ProvideSlashCommandManager,
+	ProvidePromptTemplateManager,
 )
 
 // AllComponentsSet - All UI components and layout
@@ -535,6 +763,7 @@ var TestAppDepsSet = wire.NewSet(
 	ProvideConfigManager,
 	ProvideClipboard,
 	ProvideSlashCommandManager, // Add this line
+	ProvidePromptTemplateManager,
 
 	AllComponentsSet,
 	AllControllersSet,
@@ -12,19 +12,23 @@ import (
 	"github.com/kcaldas/genie/cmd/bootstrap"
 	"github.com/kcaldas/genie/cmd/events"
 	"github.com/kcaldas/genie/cmd/history"
+	"github.com/kcaldas/genie/cmd/prompttemplates"
 	"github.com/kcaldas/genie/cmd/slashcommands"
 	"github.com/kcaldas/genie/cmd/tui/component"
 	"github.com/kcaldas/genie/cmd/tui/controllers"
 	"github.com/kcaldas/genie/cmd/tui/controllers/commands"
 	"github.com/kcaldas/genie/cmd/tui/helpers"
 	"github.com/kcaldas/genie/cmd/tui/layout"
+	"github.com/kcaldas/genie/cmd/tui/presentation"
+	"github.com/kcaldas/genie/cmd/tui/recovery"
 	"github.com/kcaldas/genie/cmd/tui/shell"
 	"github.com/kcaldas/genie/cmd/tui/state"
 	"github.com/kcaldas/genie/cmd/tui/types"
 	events2 "github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/models"
 	"github.com/kcaldas/genie/pkg/logging"
-	"path/filepath"
+	"github.com/kcaldas/genie/pkg/metrics"
 )
 
 // Injectors from wire.go:
@@ -37,8 +41,8 @@ func ProvideConfigManager() (*helpers.ConfigManager, error) {
 	return configManager, nil
 }
 
-func ProvideClipboard() *helpers.Clipboard {
-	clipboard := helpers.NewClipboard()
+func ProvideClipboard(configManager *helpers.ConfigManager) *helpers.Clipboard {
+	clipboard := helpers.NewClipboard(configManager)
 	return clipboard
 }
 
@@ -47,16 +51,11 @@ func ProvideMessagesComponent(gui types.Gui, chatState *state.ChatState, configM
 	return messagesComponent, nil
 }
 
-func ProvideInputComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus, clipboard *helpers.Clipboard, chatHistory history.ChatHistory, commandSuggester *shell.CommandSuggester, slashCommandSuggester *shell.SlashCommandSuggester) (*component.InputComponent, error) {
-	inputComponent := component.NewInputComponent(gui, configManager, commandEventBus2, clipboard, chatHistory, commandSuggester, slashCommandSuggester)
+func ProvideInputComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus, clipboard *helpers.Clipboard, chatHistory history.ChatHistory, commandSuggester *shell.CommandSuggester, slashCommandSuggester *shell.SlashCommandSuggester, promptTemplateSuggester *shell.PromptTemplateSuggester, modelSuggester *shell.ModelSuggester) (*component.InputComponent, error) {
+	inputComponent := component.NewInputComponent(gui, configManager, commandEventBus2, clipboard, chatHistory, commandSuggester, slashCommandSuggester, promptTemplateSuggester, modelSuggester)
 	return inputComponent, nil
 }
 
-func ProvideStatusComponent(gui types.Gui, stateAccessor *state.StateAccessor, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus) (*component.StatusComponent, error) {
-	statusComponent := component.NewStatusComponent(gui, stateAccessor, configManager, commandEventBus2)
-	return statusComponent, nil
-}
-
 func ProvideTextViewerComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus) (*component.TextViewerComponent, error) {
 	string2 := _wireStringValue
 	textViewerComponent := component.NewTextViewerComponent(gui, string2, configManager, commandEventBus2)
@@ -82,11 +81,26 @@ func ProvideDebugComponent(gui types.Gui, debugState *state.DebugState, configMa
 	return debugComponent, nil
 }
 
+func ProvideTodoPanelComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus) (*component.TodoPanelComponent, error) {
+	todoPanelComponent := component.NewTodoPanelComponent(gui, configManager, commandEventBus2)
+	return todoPanelComponent, nil
+}
+
+func ProvideThemeEditorComponent(gui types.Gui, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus) (*component.ThemeEditorComponent, error) {
+	themeEditorComponent := component.NewThemeEditorComponent(gui, configManager, commandEventBus2)
+	return themeEditorComponent, nil
+}
+
 func ProvideDebugController(genieService genie.Genie, gui types.Gui, debugState *state.DebugState, debugComponent *component.DebugComponent, layoutManager *layout.LayoutManager, clipboard *helpers.Clipboard, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus) (*controllers.DebugController, error) {
 	debugController := controllers.NewDebugController(genieService, gui, debugState, debugComponent, layoutManager, clipboard, configManager, commandEventBus2)
 	return debugController, nil
 }
 
+func ProvideTodoController(genieService genie.Genie, gui types.Gui, todoPanelComponent *component.TodoPanelComponent, layoutManager *layout.LayoutManager) (*controllers.TodoController, error) {
+	todoController := controllers.NewTodoController(genieService, gui, todoPanelComponent, layoutManager)
+	return todoController, nil
+}
+
 func ProvideChatController(messagesComponent *component.MessagesComponent, gui types.Gui, genieService genie.Genie, stateAccessor *state.StateAccessor, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus) (*controllers.ChatController, error) {
 	chatController := controllers.NewChatController(messagesComponent, gui, genieService, stateAccessor, configManager, commandEventBus2)
 	return chatController, nil
@@ -97,13 +111,19 @@ func ProvideLLMContextController(gui types.Gui, genieService genie.Genie, layout
 	return llmContextController, nil
 }
 
-func ProvideToolConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, eventBus events2.EventBus, commandEventBus2 *events.CommandEventBus) (*controllers.ToolConfirmationController, error) {
-	toolConfirmationController := controllers.NewToolConfirmationController(gui, stateAccessor, layoutManager, inputComponent, textViewerComponent, configManager, eventBus, commandEventBus2)
+// ProvideConfirmationQueue shares one queue between the tool and user
+// confirmation controllers, so their dialogs take turns
+func ProvideConfirmationQueue(stateAccessor *state.StateAccessor, commandEventBus *events.CommandEventBus) *controllers.ConfirmationQueue {
+	return controllers.NewConfirmationQueue(stateAccessor, commandEventBus)
+}
+
+func ProvideToolConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, confirmationQueue *controllers.ConfirmationQueue, eventBus events2.EventBus, commandEventBus2 *events.CommandEventBus) (*controllers.ToolConfirmationController, error) {
+	toolConfirmationController := controllers.NewToolConfirmationController(gui, stateAccessor, layoutManager, inputComponent, textViewerComponent, configManager, confirmationQueue, eventBus, commandEventBus2)
 	return toolConfirmationController, nil
 }
 
-func ProvideUserConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, diffViewerComponent *component.DiffViewerComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, eventBus events2.EventBus, commandEventBus2 *events.CommandEventBus) (*controllers.UserConfirmationController, error) {
-	userConfirmationController := controllers.NewUserConfirmationController(gui, stateAccessor, layoutManager, inputComponent, diffViewerComponent, textViewerComponent, configManager, eventBus, commandEventBus2)
+func ProvideUserConfirmationController(gui types.Gui, stateAccessor *state.StateAccessor, layoutManager *layout.LayoutManager, inputComponent *component.InputComponent, diffViewerComponent *component.DiffViewerComponent, textViewerComponent *component.TextViewerComponent, configManager *helpers.ConfigManager, confirmationQueue *controllers.ConfirmationQueue, eventBus events2.EventBus, commandEventBus2 *events.CommandEventBus) (*controllers.UserConfirmationController, error) {
+	userConfirmationController := controllers.NewUserConfirmationController(gui, stateAccessor, layoutManager, inputComponent, diffViewerComponent, textViewerComponent, configManager, confirmationQueue, eventBus, commandEventBus2)
 	return userConfirmationController, nil
 }
 
@@ -129,20 +149,23 @@ func InjectTUI(session genie.Session) (*TUI, error) {
 	if err != nil {
 		return nil, err
 	}
-	clipboard := ProvideClipboard()
+	clipboard := ProvideClipboard(configManager)
 	historyPath := ProvideHistoryPath(session)
-	chatHistory := ProvideChatHistory(historyPath)
+	chatHistory := ProvideChatHistory(historyPath, session)
 	commandRegistry := ProvideCommandRegistry()
 	commandSuggester := ProvideCommandSuggester(commandRegistry)
-	manager := ProvideSlashCommandManager()
+	manager := ProvideSlashCommandManager(session)
 	slashCommandSuggester := ProvideSlashCommandSuggester(manager)
-	inputComponent, err := ProvideInputComponent(typesGui, configManager, eventsCommandEventBus, clipboard, chatHistory, commandSuggester, slashCommandSuggester)
+	prompttemplatesManager := ProvidePromptTemplateManager(session)
+	promptTemplateSuggester := ProvidePromptTemplateSuggester(prompttemplatesManager)
+	modelSuggester := ProvideModelSuggester()
+	inputComponent, err := ProvideInputComponent(typesGui, configManager, eventsCommandEventBus, clipboard, chatHistory, commandSuggester, slashCommandSuggester, promptTemplateSuggester, modelSuggester)
 	if err != nil {
 		return nil, err
 	}
 	uiState := ProvideUIState()
 	stateAccessor := ProvideStateAccessor(chatState, uiState)
-	statusComponent, err := ProvideStatusComponent(typesGui, stateAccessor, configManager, eventsCommandEventBus)
+	statusComponent, err := ProvideStatusComponent(typesGui, stateAccessor, configManager, eventsCommandEventBus, session)
 	if err != nil {
 		return nil, err
 	}
@@ -159,7 +182,15 @@ func InjectTUI(session genie.Session) (*TUI, error) {
 	if err != nil {
 		return nil, err
 	}
-	layoutBuilder := ProvideLayoutBuilder(gui, configManager, messagesComponent, inputComponent, statusComponent, textViewerComponent, diffViewerComponent, debugComponent)
+	todoPanelComponent, err := ProvideTodoPanelComponent(typesGui, configManager, eventsCommandEventBus)
+	if err != nil {
+		return nil, err
+	}
+	themeEditorComponent, err := ProvideThemeEditorComponent(typesGui, configManager, eventsCommandEventBus)
+	if err != nil {
+		return nil, err
+	}
+	layoutBuilder := ProvideLayoutBuilder(gui, configManager, messagesComponent, inputComponent, statusComponent, textViewerComponent, diffViewerComponent, debugComponent, todoPanelComponent, themeEditorComponent)
 	layoutManager := ProvideLayoutManager(layoutBuilder)
 	genieGenie, err := ProvideGenie()
 	if err != nil {
@@ -173,7 +204,7 @@ func InjectTUI(session genie.Session) (*TUI, error) {
 	if err != nil {
 		return nil, err
 	}
-	contextCommand := ProvideContextCommand(llmContextController)
+	contextCommand := ProvideContextCommand(llmContextController, genieGenie, chatController)
 	clearCommand := ProvideClearCommand(chatController)
 	debugController, err := ProvideDebugController(genieGenie, typesGui, debugState, debugComponent, layoutManager, clipboard, configManager, eventsCommandEventBus)
 	if err != nil {
@@ -184,7 +215,8 @@ func InjectTUI(session genie.Session) (*TUI, error) {
 	demoCommand := ProvideDemoCommand(eventBus, chatController)
 	exitCommand := ProvideExitCommand(eventsCommandEventBus)
 	yankCommand := ProvideYankCommand(chatState, clipboard, chatController)
-	themeCommand := ProvideThemeCommand(configManager, eventsCommandEventBus, chatController)
+	themeEditorController := ProvideThemeEditorController(typesGui, layoutManager, themeEditorComponent, inputComponent, configManager, eventsCommandEventBus, chatController)
+	themeCommand := ProvideThemeCommand(configManager, eventsCommandEventBus, chatController, themeEditorController)
 	configCommand := ProvideConfigCommand(configManager, eventsCommandEventBus, typesGui, chatController)
 	statusCommand := ProvideStatusCommand(chatController, genieGenie)
 	writeController, err := ProvideWriteController(typesGui, configManager, eventsCommandEventBus, layoutManager, chatHistory)
@@ -194,17 +226,42 @@ func InjectTUI(session genie.Session) (*TUI, error) {
 	writeCommand := ProvideWriteCommand(writeController)
 	updateCommand := ProvideUpdateCommand(chatController)
 	personaCommand := ProvidePersonaCommand(chatController, genieGenie, eventsCommandEventBus, configManager)
-	commandHandler := ProvideCommandHandler(eventsCommandEventBus, chatController, commandRegistry, contextCommand, clearCommand, debugCommand, demoCommand, exitCommand, yankCommand, themeCommand, configCommand, statusCommand, writeCommand, updateCommand, personaCommand)
-	toolConfirmationController, err := ProvideToolConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, textViewerComponent, configManager, eventBus, eventsCommandEventBus)
+	promptController := ProvidePromptController(typesGui, prompttemplatesManager, inputComponent, writeController, layoutManager, chatController)
+	promptCommand := ProvidePromptCommand(promptController, chatController)
+	outputCommand := ProvideOutputCommand(chatController)
+	thoughtsCommand := ProvideThoughtsCommand(chatController)
+	usageCommand := ProvideUsageCommand(chatController)
+	todoController, err := ProvideTodoController(genieGenie, typesGui, todoPanelComponent, layoutManager)
 	if err != nil {
 		return nil, err
 	}
-	userConfirmationController, err := ProvideUserConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, diffViewerComponent, textViewerComponent, configManager, eventBus, eventsCommandEventBus)
+	todosCommand := ProvideTodosCommand(todoController, chatController)
+	layoutCommand := ProvideLayoutCommand(layoutManager, configManager, chatController)
+	diffCommand := ProvideDiffCommand(configManager, eventsCommandEventBus, chatController)
+	schemaCommand := ProvideSchemaCommand(chatController)
+	modelCommand := ProvideModelCommand(chatController, genieGenie)
+	modeCommand := ProvideModeCommand(chatController, genieGenie)
+	collector := ProvideMetricsCollector(genieGenie)
+	statsCommand := ProvideStatsCommand(collector, chatController)
+	branchCommand := ProvideBranchCommand(genieGenie, chatController, session, chatController)
+	retryCommand := ProvideRetryCommand(chatController)
+	sampleCommand := ProvideSampleCommand(chatController)
+	summarizeCommand := ProvideSummarizeCommand(chatController)
+	recoverCommand := ProvideRecoverCommand(chatController, inputComponent)
+	cdCommand := ProvideCdCommand(genieGenie, chatController, chatHistory, eventsCommandEventBus, chatController)
+	scopeCommand := ProvideScopeCommand(genieGenie, chatController)
+	commandHandler := ProvideCommandHandler(eventsCommandEventBus, chatController, commandRegistry, contextCommand, clearCommand, debugCommand, demoCommand, exitCommand, yankCommand, themeCommand, configCommand, statusCommand, writeCommand, updateCommand, personaCommand, promptCommand, outputCommand, thoughtsCommand, usageCommand, todosCommand, layoutCommand, diffCommand, schemaCommand, modelCommand, modeCommand, statsCommand, branchCommand, retryCommand, sampleCommand, summarizeCommand, recoverCommand, cdCommand, scopeCommand)
+	confirmationQueue := ProvideConfirmationQueue(stateAccessor, eventsCommandEventBus)
+	toolConfirmationController, err := ProvideToolConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, textViewerComponent, configManager, confirmationQueue, eventBus, eventsCommandEventBus)
+	if err != nil {
+		return nil, err
+	}
+	userConfirmationController, err := ProvideUserConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, diffViewerComponent, textViewerComponent, configManager, confirmationQueue, eventBus, eventsCommandEventBus)
 	if err != nil {
 		return nil, err
 	}
 	slashCommandController := ProvideSlashCommandController(eventsCommandEventBus, manager, chatController)
-	confirmationInitializer := InitializeConfirmationControllers(toolConfirmationController, userConfirmationController, slashCommandController)
+	confirmationInitializer := InitializeConfirmationControllers(toolConfirmationController, userConfirmationController, slashCommandController, promptController)
 	app, err := NewApp(typesGui, eventsCommandEventBus, configManager, layoutManager, commandHandler, chatController, uiState, confirmationInitializer, manager)
 	if err != nil {
 		return nil, err
@@ -230,20 +287,23 @@ func InjectTestApp(genieService genie.Genie, session genie.Session, outputMode g
 	if err != nil {
 		return nil, err
 	}
-	clipboard := ProvideClipboard()
+	clipboard := ProvideClipboard(configManager)
 	historyPath := ProvideHistoryPath(session)
-	chatHistory := ProvideChatHistory(historyPath)
+	chatHistory := ProvideChatHistory(historyPath, session)
 	commandRegistry := ProvideCommandRegistry()
 	commandSuggester := ProvideCommandSuggester(commandRegistry)
-	manager := ProvideSlashCommandManager()
+	manager := ProvideSlashCommandManager(session)
 	slashCommandSuggester := ProvideSlashCommandSuggester(manager)
-	inputComponent, err := ProvideInputComponent(typesGui, configManager, eventsCommandEventBus, clipboard, chatHistory, commandSuggester, slashCommandSuggester)
+	prompttemplatesManager := ProvidePromptTemplateManager(session)
+	promptTemplateSuggester := ProvidePromptTemplateSuggester(prompttemplatesManager)
+	modelSuggester := ProvideModelSuggester()
+	inputComponent, err := ProvideInputComponent(typesGui, configManager, eventsCommandEventBus, clipboard, chatHistory, commandSuggester, slashCommandSuggester, promptTemplateSuggester, modelSuggester)
 	if err != nil {
 		return nil, err
 	}
 	uiState := ProvideUIState()
 	stateAccessor := ProvideStateAccessor(chatState, uiState)
-	statusComponent, err := ProvideStatusComponent(typesGui, stateAccessor, configManager, eventsCommandEventBus)
+	statusComponent, err := ProvideStatusComponent(typesGui, stateAccessor, configManager, eventsCommandEventBus, session)
 	if err != nil {
 		return nil, err
 	}
@@ -260,7 +320,15 @@ func InjectTestApp(genieService genie.Genie, session genie.Session, outputMode g
 	if err != nil {
 		return nil, err
 	}
-	layoutBuilder := ProvideLayoutBuilder(gui, configManager, messagesComponent, inputComponent, statusComponent, textViewerComponent, diffViewerComponent, debugComponent)
+	todoPanelComponent, err := ProvideTodoPanelComponent(typesGui, configManager, eventsCommandEventBus)
+	if err != nil {
+		return nil, err
+	}
+	themeEditorComponent, err := ProvideThemeEditorComponent(typesGui, configManager, eventsCommandEventBus)
+	if err != nil {
+		return nil, err
+	}
+	layoutBuilder := ProvideLayoutBuilder(gui, configManager, messagesComponent, inputComponent, statusComponent, textViewerComponent, diffViewerComponent, debugComponent, todoPanelComponent, themeEditorComponent)
 	layoutManager := ProvideLayoutManager(layoutBuilder)
 	chatController, err := ProvideChatController(messagesComponent, typesGui, genieService, stateAccessor, configManager, eventsCommandEventBus)
 	if err != nil {
@@ -270,7 +338,7 @@ func InjectTestApp(genieService genie.Genie, session genie.Session, outputMode g
 	if err != nil {
 		return nil, err
 	}
-	contextCommand := ProvideContextCommand(llmContextController)
+	contextCommand := ProvideContextCommand(llmContextController, genieService, chatController)
 	clearCommand := ProvideClearCommand(chatController)
 	debugController, err := ProvideDebugController(genieService, typesGui, debugState, debugComponent, layoutManager, clipboard, configManager, eventsCommandEventBus)
 	if err != nil {
@@ -281,7 +349,8 @@ func InjectTestApp(genieService genie.Genie, session genie.Session, outputMode g
 	demoCommand := ProvideDemoCommand(eventBus, chatController)
 	exitCommand := ProvideExitCommand(eventsCommandEventBus)
 	yankCommand := ProvideYankCommand(chatState, clipboard, chatController)
-	themeCommand := ProvideThemeCommand(configManager, eventsCommandEventBus, chatController)
+	themeEditorController := ProvideThemeEditorController(typesGui, layoutManager, themeEditorComponent, inputComponent, configManager, eventsCommandEventBus, chatController)
+	themeCommand := ProvideThemeCommand(configManager, eventsCommandEventBus, chatController, themeEditorController)
 	configCommand := ProvideConfigCommand(configManager, eventsCommandEventBus, typesGui, chatController)
 	statusCommand := ProvideStatusCommand(chatController, genieService)
 	writeController, err := ProvideWriteController(typesGui, configManager, eventsCommandEventBus, layoutManager, chatHistory)
@@ -291,17 +360,42 @@ func InjectTestApp(genieService genie.Genie, session genie.Session, outputMode g
 	writeCommand := ProvideWriteCommand(writeController)
 	updateCommand := ProvideUpdateCommand(chatController)
 	personaCommand := ProvidePersonaCommand(chatController, genieService, eventsCommandEventBus, configManager)
-	commandHandler := ProvideCommandHandler(eventsCommandEventBus, chatController, commandRegistry, contextCommand, clearCommand, debugCommand, demoCommand, exitCommand, yankCommand, themeCommand, configCommand, statusCommand, writeCommand, updateCommand, personaCommand)
-	toolConfirmationController, err := ProvideToolConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, textViewerComponent, configManager, eventBus, eventsCommandEventBus)
+	promptController := ProvidePromptController(typesGui, prompttemplatesManager, inputComponent, writeController, layoutManager, chatController)
+	promptCommand := ProvidePromptCommand(promptController, chatController)
+	outputCommand := ProvideOutputCommand(chatController)
+	thoughtsCommand := ProvideThoughtsCommand(chatController)
+	usageCommand := ProvideUsageCommand(chatController)
+	todoController, err := ProvideTodoController(genieService, typesGui, todoPanelComponent, layoutManager)
 	if err != nil {
 		return nil, err
 	}
-	userConfirmationController, err := ProvideUserConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, diffViewerComponent, textViewerComponent, configManager, eventBus, eventsCommandEventBus)
+	todosCommand := ProvideTodosCommand(todoController, chatController)
+	layoutCommand := ProvideLayoutCommand(layoutManager, configManager, chatController)
+	diffCommand := ProvideDiffCommand(configManager, eventsCommandEventBus, chatController)
+	schemaCommand := ProvideSchemaCommand(chatController)
+	modelCommand := ProvideModelCommand(chatController, genieService)
+	modeCommand := ProvideModeCommand(chatController, genieService)
+	collector := ProvideMetricsCollector(genieService)
+	statsCommand := ProvideStatsCommand(collector, chatController)
+	branchCommand := ProvideBranchCommand(genieService, chatController, session, chatController)
+	retryCommand := ProvideRetryCommand(chatController)
+	sampleCommand := ProvideSampleCommand(chatController)
+	summarizeCommand := ProvideSummarizeCommand(chatController)
+	recoverCommand := ProvideRecoverCommand(chatController, inputComponent)
+	cdCommand := ProvideCdCommand(genieService, chatController, chatHistory, eventsCommandEventBus, chatController)
+	scopeCommand := ProvideScopeCommand(genieService, chatController)
+	commandHandler := ProvideCommandHandler(eventsCommandEventBus, chatController, commandRegistry, contextCommand, clearCommand, debugCommand, demoCommand, exitCommand, yankCommand, themeCommand, configCommand, statusCommand, writeCommand, updateCommand, personaCommand, promptCommand, outputCommand, thoughtsCommand, usageCommand, todosCommand, layoutCommand, diffCommand, schemaCommand, modelCommand, modeCommand, statsCommand, branchCommand, retryCommand, sampleCommand, summarizeCommand, recoverCommand, cdCommand, scopeCommand)
+	confirmationQueue := ProvideConfirmationQueue(stateAccessor, eventsCommandEventBus)
+	toolConfirmationController, err := ProvideToolConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, textViewerComponent, configManager, confirmationQueue, eventBus, eventsCommandEventBus)
+	if err != nil {
+		return nil, err
+	}
+	userConfirmationController, err := ProvideUserConfirmationController(typesGui, stateAccessor, layoutManager, inputComponent, diffViewerComponent, textViewerComponent, configManager, confirmationQueue, eventBus, eventsCommandEventBus)
 	if err != nil {
 		return nil, err
 	}
 	slashCommandController := ProvideSlashCommandController(eventsCommandEventBus, manager, chatController)
-	confirmationInitializer := InitializeConfirmationControllers(toolConfirmationController, userConfirmationController, slashCommandController)
+	confirmationInitializer := InitializeConfirmationControllers(toolConfirmationController, userConfirmationController, slashCommandController, promptController)
 	app, err := NewApp(typesGui, eventsCommandEventBus, configManager, layoutManager, commandHandler, chatController, uiState, confirmationInitializer, manager)
 	if err != nil {
 		return nil, err
@@ -337,31 +431,56 @@ func ProvideEventBus(genieService genie.Genie) events2.EventBus {
 	return genieService.GetEventBus()
 }
 
-// ProvideSlashCommandManager provides a shared instance of SlashCommandManager
-func ProvideSlashCommandManager() *slashcommands.Manager {
-	return slashcommands.NewManager()
+// ProvideSlashCommandManager provides a shared instance of SlashCommandManager.
+// Untrusted workspaces only get the user's own commands.
+func ProvideSlashCommandManager(session genie.Session) *slashcommands.Manager {
+	manager := slashcommands.NewManager()
+	if !session.IsWorkspaceTrusted() {
+		manager.DisableProjectSources()
+	}
+	return manager
+}
+
+// ProvidePromptTemplateManager provides a shared instance of the prompt template manager.
+// Untrusted workspaces only get the user's own templates.
+func ProvidePromptTemplateManager(session genie.Session) *prompttemplates.Manager {
+	manager := prompttemplates.NewManager()
+	if !session.IsWorkspaceTrusted() {
+		manager.DisableProjectSources()
+	}
+	return manager
 }
 
 // ProvideHistoryPath provides the chat history file path based on session's genie home directory
 func ProvideHistoryPath(session genie.Session) HistoryPath {
-	return HistoryPath(filepath.Join(session.GetGenieHomeDirectory(), ".genie", "history"))
+	return HistoryPath(history.ProjectHistoryPath(session.GetGenieHomeDirectory()))
 }
 
 func ProvideHistoryPathString(historyPath HistoryPath) string {
 	return string(historyPath)
 }
 
-// ProvideChatHistory provides a shared chat history manager
-func ProvideChatHistory(historyPath HistoryPath) history.ChatHistory {
-	return history.NewChatHistory(string(historyPath), true)
+// ProvideChatHistory provides a shared chat history manager. Commands are
+// stamped with the session and also kept in the user-wide history.
+func ProvideChatHistory(historyPath HistoryPath, session genie.Session) history.ChatHistory {
+	chatHistory := history.NewChatHistory(string(historyPath), true)
+	if fileHistory, ok := chatHistory.(*history.FileChatHistory); ok {
+		fileHistory.SetSessionID(session.GetID())
+		if userPath, err := history.UserHistoryPath(); err == nil {
+			fileHistory.SetUserHistoryPath(userPath)
+		}
+	}
+	return chatHistory
 }
 
 // NewGocuiGui - Production GUI provider (uses config-based output mode)
 func NewGocuiGui(configManager *helpers.ConfigManager) (*gocui.Gui, error) {
 	config := configManager.GetConfig()
-	guiOutputMode := configManager.GetGocuiOutputMode(config.OutputMode)
 
-	g, err := gocui.NewGui(guiOutputMode, true)
+	profile := presentation.ColorProfileForMode(config.OutputMode)
+	presentation.SetColorProfile(profile)
+
+	g, err := gocui.NewGui(profile.GocuiOutputMode(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -397,6 +516,14 @@ func ProvideDebugState() *state.DebugState {
 	return state.NewDebugState()
 }
 
+// ProvideMetricsCollector starts measuring turns for :stats. It stays
+// subscribed for the life of the TUI.
+func ProvideMetricsCollector(genieService genie.Genie) *metrics.Collector {
+	collector := metrics.NewCollector()
+	collector.Attach(genieService.GetEventBus())
+	return collector
+}
+
 func ProvideStateAccessor(chatState *state.ChatState, uiState *state.UIState) *state.StateAccessor {
 	return state.NewStateAccessor(chatState, uiState)
 }
@@ -410,6 +537,8 @@ func ProvideLayoutBuilder(
 	textViewerComponent *component.TextViewerComponent,
 	diffViewerComponent *component.DiffViewerComponent,
 	debugComponent *component.DebugComponent,
+	todoPanelComponent *component.TodoPanelComponent,
+	themeEditorComponent *component.ThemeEditorComponent,
 ) *LayoutBuilder {
 	return NewLayoutBuilder(
 		gui,
@@ -420,6 +549,8 @@ func ProvideLayoutBuilder(
 		textViewerComponent,
 		diffViewerComponent,
 		debugComponent,
+		todoPanelComponent,
+		themeEditorComponent,
 	)
 }
 
@@ -427,11 +558,27 @@ func ProvideLayoutManager(layoutBuilder *LayoutBuilder) *layout.LayoutManager {
 	return layoutBuilder.GetLayoutManager()
 }
 
+// ProvideStatusComponent provides the status bar, showing the session's
+// working directory
+func ProvideStatusComponent(gui types.Gui, stateAccessor *state.StateAccessor, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus, session genie.Session) (*component.StatusComponent, error) {
+	statusComponent := component.NewStatusComponent(gui, stateAccessor, configManager, commandEventBus2)
+	statusComponent.SetWorkDir(session.GetWorkingDirectory())
+	return statusComponent, nil
+}
+
 // ProvideGlobalLogger provides the global logger instance
 func ProvideGlobalLogger() logging.Logger {
 	return logging.GetGlobalLogger()
 }
 
+func ProvideThemeEditorController(gui types.Gui, layoutManager *layout.LayoutManager, themeEditorComponent *component.ThemeEditorComponent, inputComponent *component.InputComponent, configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus, notification types.Notification) *controllers.ThemeEditorController {
+	return controllers.NewThemeEditorController(gui, layoutManager, themeEditorComponent, inputComponent, configManager, commandEventBus2, notification)
+}
+
+func ProvidePromptController(gui types.Gui, manager *prompttemplates.Manager, inputComponent *component.InputComponent, writeController *controllers.WriteController, layoutManager *layout.LayoutManager, notification types.Notification) *controllers.PromptController {
+	return controllers.NewPromptController(gui, manager, inputComponent, writeController, layoutManager, notification)
+}
+
 func ProvideSlashCommandController(commandEventBus2 *events.CommandEventBus, slashCommandManager *slashcommands.Manager, notification types.Notification) *controllers.SlashCommandController {
 	return controllers.NewSlashCommandController(commandEventBus2, slashCommandManager, notification)
 }
@@ -448,8 +595,20 @@ func ProvideSlashCommandSuggester(manager *slashcommands.Manager) *shell.SlashCo
 	return shell.NewSlashCommandSuggester(manager)
 }
 
-func ProvideContextCommand(llmContextController *controllers.LLMContextController) *commands.ContextCommand {
-	return commands.NewContextCommand(llmContextController)
+func ProvidePromptTemplateSuggester(manager *prompttemplates.Manager) *shell.PromptTemplateSuggester {
+	return shell.NewPromptTemplateSuggester(manager)
+}
+
+func ProvideModelSuggester() *shell.ModelSuggester {
+	var names []string
+	for _, model := range models.All() {
+		names = append(names, model.Name)
+	}
+	return shell.NewModelSuggester(names)
+}
+
+func ProvideContextCommand(llmContextController *controllers.LLMContextController, genieService genie.Genie, notification types.Notification) *commands.ContextCommand {
+	return commands.NewContextCommand(llmContextController, genieService, notification)
 }
 
 func ProvideClearCommand(chatController *controllers.ChatController) *commands.ClearCommand {
@@ -472,8 +631,8 @@ func ProvideYankCommand(chatState *state.ChatState, clipboard *helpers.Clipboard
 	return commands.NewYankCommand(chatState, clipboard, chatController)
 }
 
-func ProvideThemeCommand(configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus, chatController *controllers.ChatController) *commands.ThemeCommand {
-	return commands.NewThemeCommand(configManager, commandEventBus2, chatController)
+func ProvideThemeCommand(configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus, chatController *controllers.ChatController, themeEditorController *controllers.ThemeEditorController) *commands.ThemeCommand {
+	return commands.NewThemeCommand(configManager, commandEventBus2, chatController, themeEditorController)
 }
 
 func ProvideConfigCommand(configManager *helpers.ConfigManager, commandEventBus2 *events.CommandEventBus, gui types.Gui, chatController *controllers.ChatController) *commands.ConfigCommand {
@@ -496,6 +655,87 @@ func ProvidePersonaCommand(notification types.Notification, genieService genie.G
 	return commands.NewPersonaCommand(notification, genieService, commandEventBus2, configManager)
 }
 
+func ProvideOutputCommand(chatController *controllers.ChatController) *commands.OutputCommand {
+	return commands.NewOutputCommand(chatController)
+}
+
+func ProvideThoughtsCommand(chatController *controllers.ChatController) *commands.ThoughtsCommand {
+	return commands.NewThoughtsCommand(chatController)
+}
+
+func ProvideUsageCommand(chatController *controllers.ChatController) *commands.UsageCommand {
+	return commands.NewUsageCommand(chatController)
+}
+
+func ProvideStatsCommand(collector *metrics.Collector, notification types.Notification) *commands.StatsCommand {
+	return commands.NewStatsCommand(collector, notification)
+}
+
+func ProvideModelCommand(notification types.Notification, genieService genie.Genie) *commands.ModelCommand {
+	return commands.NewModelCommand(notification, genieService)
+}
+
+func ProvideModeCommand(notification types.Notification, genieService genie.Genie) *commands.ModeCommand {
+	return commands.NewModeCommand(notification, genieService)
+}
+
+func ProvideRetryCommand(chatController *controllers.ChatController) *commands.RetryCommand {
+	return commands.NewRetryCommand(chatController, chatController)
+}
+
+func ProvideSampleCommand(chatController *controllers.ChatController) *commands.SampleCommand {
+	return commands.NewSampleCommand(chatController, chatController)
+}
+
+func ProvideSummarizeCommand(chatController *controllers.ChatController) *commands.SummarizeCommand {
+	return commands.NewSummarizeCommand(chatController, chatController)
+}
+
+func ProvideRecoverCommand(chatController *controllers.ChatController, inputComponent *component.InputComponent) *commands.RecoverCommand {
+	dir, err := recovery.DefaultDir()
+	if err != nil {
+		dir = recovery.Dir(".")
+	}
+	return commands.NewRecoverCommand(dir, chatController, inputComponent, chatController)
+}
+
+func ProvideSchemaCommand(chatController *controllers.ChatController) *commands.SchemaCommand {
+	return commands.NewSchemaCommand(chatController)
+}
+
+func ProvidePromptCommand(promptController *controllers.PromptController, notification types.Notification) *commands.PromptCommand {
+	return commands.NewPromptCommand(promptController, notification)
+}
+
+func ProvideTodosCommand(todoController *controllers.TodoController, chatController *controllers.ChatController) *commands.TodosCommand {
+	return commands.NewTodosCommand(todoController, chatController)
+}
+
+func ProvideDiffCommand(configManager *helpers.ConfigManager, commandEventBus *events.CommandEventBus, notification types.Notification) *commands.DiffCommand {
+	return commands.NewDiffCommand(configManager, commandEventBus, notification)
+}
+
+func ProvideLayoutCommand(layoutManager *layout.LayoutManager, configManager *helpers.ConfigManager, notification types.Notification) *commands.LayoutCommand {
+	return commands.NewLayoutCommand(layoutManager, configManager, notification)
+}
+
+// ProvideBranchCommand saves branches in the project's .genie directory,
+// next to its history
+func ProvideBranchCommand(genieService genie.Genie, chatController *controllers.ChatController, session genie.Session, notification types.Notification) *commands.BranchCommand {
+	store := helpers.NewBranchStore(session.GetGenieHomeDirectory())
+	return commands.NewBranchCommand(genieService, chatController, store, notification)
+}
+
+// ProvideCdCommand provides :cd, which moves the input history along with
+// the project's .genie directory
+func ProvideCdCommand(genieService genie.Genie, chatController *controllers.ChatController, chatHistory history.ChatHistory, commandEventBus2 *events.CommandEventBus, notification types.Notification) *commands.CdCommand {
+	return commands.NewCdCommand(genieService, chatController, chatHistory, commandEventBus2, notification)
+}
+
+func ProvideScopeCommand(genieService genie.Genie, notification types.Notification) *commands.ScopeCommand {
+	return commands.NewScopeCommand(genieService, notification)
+}
+
 func ProvideCommandHandler(commandEventBus2 *events.CommandEventBus,
 	chatController *controllers.ChatController,
 	registry *commands.CommandRegistry,
@@ -511,19 +751,55 @@ func ProvideCommandHandler(commandEventBus2 *events.CommandEventBus,
 	writeCommand *commands.WriteCommand,
 	updateCommand *commands.UpdateCommand,
 	personaCommand *commands.PersonaCommand,
+	promptCommand *commands.PromptCommand,
+	outputCommand *commands.OutputCommand,
+	thoughtsCommand *commands.ThoughtsCommand,
+	usageCommand *commands.UsageCommand,
+	todosCommand *commands.TodosCommand,
+	layoutCommand *commands.LayoutCommand,
+	diffCommand *commands.DiffCommand,
+	schemaCommand *commands.SchemaCommand,
+	modelCommand *commands.ModelCommand,
+	modeCommand *commands.ModeCommand,
+	statsCommand *commands.StatsCommand,
+	branchCommand *commands.BranchCommand,
+	retryCommand *commands.RetryCommand,
+	sampleCommand *commands.SampleCommand,
+	summarizeCommand *commands.SummarizeCommand,
+	recoverCommand *commands.RecoverCommand,
+	cdCommand *commands.CdCommand,
+	scopeCommand *commands.ScopeCommand,
 ) *commands.CommandHandler {
 	handler := commands.NewCommandHandler(commandEventBus2, chatController, registry)
 
+	handler.RegisterNewCommand(branchCommand)
+	handler.RegisterNewCommand(cdCommand)
 	handler.RegisterNewCommand(clearCommand)
 	handler.RegisterNewCommand(configCommand)
 	handler.RegisterNewCommand(contextCommand)
 	handler.RegisterNewCommand(debugCommand)
 	handler.RegisterNewCommand(demoCommand)
+	handler.RegisterNewCommand(diffCommand)
 	handler.RegisterNewCommand(exitCommand)
+	handler.RegisterNewCommand(modelCommand)
+	handler.RegisterNewCommand(modeCommand)
+	handler.RegisterNewCommand(outputCommand)
+	handler.RegisterNewCommand(thoughtsCommand)
 	handler.RegisterNewCommand(personaCommand)
+	handler.RegisterNewCommand(promptCommand)
+	handler.RegisterNewCommand(recoverCommand)
+	handler.RegisterNewCommand(retryCommand)
+	handler.RegisterNewCommand(sampleCommand)
+	handler.RegisterNewCommand(schemaCommand)
+	handler.RegisterNewCommand(scopeCommand)
+	handler.RegisterNewCommand(statsCommand)
 	handler.RegisterNewCommand(statusCommand)
+	handler.RegisterNewCommand(summarizeCommand)
 	handler.RegisterNewCommand(themeCommand)
+	handler.RegisterNewCommand(todosCommand)
+	handler.RegisterNewCommand(layoutCommand)
 	handler.RegisterNewCommand(updateCommand)
+	handler.RegisterNewCommand(usageCommand)
 	handler.RegisterNewCommand(writeCommand)
 	handler.RegisterNewCommand(yankCommand)
 
@@ -536,6 +812,7 @@ func InitializeConfirmationControllers(
 	toolController *controllers.ToolConfirmationController,
 	userController *controllers.UserConfirmationController,
 	slashCommandController *controllers.SlashCommandController,
+	promptController *controllers.PromptController,
 ) *ConfirmationInitializer {
 
 	return &ConfirmationInitializer{}
@@ -547,6 +824,7 @@ var StateSet = wire.NewSet(
 	ProvideUIState,
 	ProvideDebugState,
 	ProvideStateAccessor,
+	ProvideMetricsCollector,
 )
 
 // ComponentSet - UI components
@@ -557,6 +835,8 @@ var ComponentSet = wire.NewSet(
 	ProvideTextViewerComponent,
 	ProvideDiffViewerComponent,
 	ProvideDebugComponent,
+	ProvideTodoPanelComponent,
+	ProvideThemeEditorComponent,
 )
 
 // LayoutSet - Layout management
@@ -575,7 +855,11 @@ var ControllerSet = wire.NewSet(
 	ProvideLLMContextController,
 	ProvideWriteController,
 	ProvideSlashCommandController,
+	ProvidePromptController,
+	ProvideTodoController,
+	ProvideThemeEditorController,
 
+	ProvideConfirmationQueue,
 	ProvideToolConfirmationController,
 	ProvideUserConfirmationController,
 	InitializeConfirmationControllers, wire.Bind(new(types.Notification), new(*controllers.ChatController)),
@@ -595,6 +879,24 @@ var CommandProvidersSet = wire.NewSet(
 	ProvideWriteCommand,
 	ProvideUpdateCommand,
 	ProvidePersonaCommand,
+	ProvideOutputCommand,
+	ProvideThoughtsCommand,
+	ProvideUsageCommand,
+	ProvidePromptCommand,
+	ProvideTodosCommand,
+	ProvideLayoutCommand,
+	ProvideDiffCommand,
+	ProvideSchemaCommand,
+	ProvideModelCommand,
+	ProvideModeCommand,
+	ProvideStatsCommand,
+	ProvideBranchCommand,
+	ProvideRetryCommand,
+	ProvideSampleCommand,
+	ProvideSummarizeCommand,
+	ProvideRecoverCommand,
+	ProvideCdCommand,
+	ProvideScopeCommand,
 )
 
 // CommandSet - All commands and command handler
@@ -603,6 +905,8 @@ var CommandSet = wire.NewSet(
 	ProvideCommandRegistry,
 	ProvideCommandSuggester,
 	ProvideSlashCommandSuggester,
+	ProvidePromptTemplateSuggester,
+	ProvideModelSuggester,
 
 	CommandProvidersSet,
 
@@ -625,6 +929,7 @@ var CoreServicesSet = wire.NewSet(
 	ProvideConfigManager,
 	ProvideClipboard,
 	ProvideSlashCommandManager,
+	ProvidePromptTemplateManager,
 )
 
 // AllComponentsSet - All UI components and layout
@@ -658,6 +963,7 @@ var TestAppDepsSet = wire.NewSet(
 	ProvideConfigManager,
 	ProvideClipboard,
 	ProvideSlashCommandManager,
+	ProvidePromptTemplateManager,
 
 	AllComponentsSet,
 	AllControllersSet,
@@ -2,9 +2,11 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"os"
 	"strings"
 	"sync"
@@ -63,6 +65,7 @@ func (c *CaptureMiddleware) GenerateContent(ctx context.Context, prompt Prompt,
 	interaction := c.capture.StartInteraction(prompt, args)
 	interaction.LLMProvider = c.providerName
 	interaction.Debug = debug
+	tools := recordToolCalls(&prompt)
 
 	startTime := time.Now()
 
@@ -79,6 +82,7 @@ func (c *CaptureMiddleware) GenerateContent(ctx context.Context, prompt Prompt,
 	duration := time.Since(startTime)
 
 	// Complete the capture
+	interaction.ToolCalls = tools.calls()
 	c.capture.CompleteInteraction(interaction, response, err, duration)
 
 	if c.debugMode {
@@ -111,6 +115,7 @@ func (c *CaptureMiddleware) GenerateContentStream(ctx context.Context, prompt Pr
 	interaction := c.capture.StartInteraction(prompt, args)
 	interaction.LLMProvider = c.providerName
 	interaction.Debug = debug
+	tools := recordToolCalls(&prompt)
 
 	startTime := time.Now()
 
@@ -122,11 +127,12 @@ func (c *CaptureMiddleware) GenerateContentStream(ctx context.Context, prompt Pr
 
 	stream, err := c.underlying.GenerateContentStream(ctx, prompt, debug, args...)
 	if err != nil {
+		interaction.ToolCalls = tools.calls()
 		c.capture.CompleteInteraction(interaction, "", err, time.Since(startTime))
 		return nil, err
 	}
 
-	return c.wrapStream(stream, interaction, startTime), nil
+	return c.wrapStream(stream, interaction, tools, startTime), nil
 }
 
 // GenerateContentAttr implements the Gen interface with capture
@@ -145,6 +151,7 @@ func (c *CaptureMiddleware) GenerateContentAttr(ctx context.Context, prompt Prom
 	for _, attr := range attrs {
 		interaction.Attrs = append(interaction.Attrs, CapturedAttr(attr))
 	}
+	tools := recordToolCalls(&prompt)
 
 	startTime := time.Now()
 
@@ -159,6 +166,7 @@ func (c *CaptureMiddleware) GenerateContentAttr(ctx context.Context, prompt Prom
 	duration := time.Since(startTime)
 
 	// Complete the capture
+	interaction.ToolCalls = tools.calls()
 	c.capture.CompleteInteraction(interaction, response, err, duration)
 
 	if c.debugMode {
@@ -187,6 +195,7 @@ func (c *CaptureMiddleware) GenerateContentAttrStream(ctx context.Context, promp
 	for _, attr := range attrs {
 		interaction.Attrs = append(interaction.Attrs, CapturedAttr(attr))
 	}
+	tools := recordToolCalls(&prompt)
 
 	startTime := time.Now()
 
@@ -196,11 +205,12 @@ func (c *CaptureMiddleware) GenerateContentAttrStream(ctx context.Context, promp
 
 	stream, err := c.underlying.GenerateContentAttrStream(ctx, prompt, debug, attrs)
 	if err != nil {
+		interaction.ToolCalls = tools.calls()
 		c.capture.CompleteInteraction(interaction, "", err, time.Since(startTime))
 		return nil, err
 	}
 
-	return c.wrapStream(stream, interaction, startTime), nil
+	return c.wrapStream(stream, interaction, tools, startTime), nil
 }
 
 // CountTokens delegates to the underlying LLM client
@@ -250,11 +260,12 @@ func (c *CaptureMiddleware) PrintCaptureSummary() {
 	fmt.Println(c.capture.GetSummary())
 }
 
-func (c *CaptureMiddleware) wrapStream(stream Stream, interaction *Interaction, start time.Time) Stream {
+func (c *CaptureMiddleware) wrapStream(stream Stream, interaction *Interaction, tools *toolCallRecorder, start time.Time) Stream {
 	return &capturedStream{
 		underlying:  stream,
 		capture:     c.capture,
 		interaction: interaction,
+		tools:       tools,
 		startTime:   start,
 		debugMode:   c.debugMode,
 	}
@@ -264,6 +275,7 @@ type capturedStream struct {
 	underlying  Stream
 	capture     *InteractionCapture
 	interaction *Interaction
+	tools       *toolCallRecorder
 	startTime   time.Time
 	debugMode   bool
 	builder     strings.Builder
@@ -295,6 +307,7 @@ func (c *capturedStream) finish(response string, err error) {
 			response = c.builder.String()
 		}
 		duration := time.Since(c.startTime)
+		c.interaction.ToolCalls = c.tools.calls()
 		c.capture.CompleteInteraction(c.interaction, response, err, duration)
 		if c.debugMode {
 			if err != nil {
@@ -309,6 +322,63 @@ func (c *capturedStream) finish(response string, err error) {
 	})
 }
 
+// toolCallRecorder collects the tool calls of one interaction. Providers
+// may run tools concurrently.
+type toolCallRecorder struct {
+	mu       sync.Mutex
+	recorded []CapturedToolCall
+}
+
+// recordToolCalls wraps the prompt's handlers so every tool call the model
+// makes during the interaction, and its result, is recorded
+func recordToolCalls(prompt *Prompt) *toolCallRecorder {
+	recorder := &toolCallRecorder{}
+	if len(prompt.Handlers) == 0 {
+		return recorder
+	}
+	handlers := make(map[string]HandlerFunc, len(prompt.Handlers))
+	for name, handler := range prompt.Handlers {
+		handlers[name] = func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			call := CapturedToolCall{Name: name, Args: maps.Clone(args)}
+			result, err := handler(ctx, args)
+			call.Result = capturableResult(result)
+			if err != nil {
+				call.Error = err.Error()
+			}
+			recorder.mu.Lock()
+			recorder.recorded = append(recorder.recorded, call)
+			recorder.mu.Unlock()
+			return result, err
+		}
+	}
+	prompt.Handlers = handlers
+	return recorder
+}
+
+// capturableResult keeps a tool result as it will be saved. A result that
+// cannot be written as JSON would stop the whole capture file from saving,
+// so it is replaced by the reason.
+func capturableResult(result map[string]any) map[string]any {
+	if result == nil {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return map[string]any{"_capture_error": err.Error()}
+	}
+	var captured map[string]any
+	if err := json.Unmarshal(data, &captured); err != nil {
+		return map[string]any{"_capture_error": err.Error()}
+	}
+	return captured
+}
+
+func (r *toolCallRecorder) calls() []CapturedToolCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]CapturedToolCall(nil), r.recorded...)
+}
+
 // Configuration helpers
 
 // GetCaptureConfigFromEnv creates capture config from environment variables
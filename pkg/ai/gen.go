@@ -40,27 +40,43 @@ type Image struct {
 }
 
 type Prompt struct {
-	Name              string   `yaml:"name"`
-	Instruction       string   `yaml:"instruction"`
-	Text              string   `yaml:"text"`
-	Images            []*Image `yaml:"images"`
-	LLMProvider       string   `yaml:"llm_provider"`
-	RequiredTools     []string `yaml:"required_tools"`
-	Functions         []*FunctionDeclaration
-	ResponseSchema    *Schema                `yaml:"response_schema"`
-	Handlers          map[string]HandlerFunc `yaml:"-"`
-	ModelName         string                 `yaml:"model_name"`
-	MaxTokens         int32                  `yaml:"max_tokens"`
-	Temperature       float32                `yaml:"temperature"`
-	TopP              float32                `yaml:"top_p"`
-	MaxToolIterations int32                  `yaml:"max_tool_iterations"`
-	ContextBudget     int                    `yaml:"context_budget"`
-	MissingTools      []string               `yaml:"-"`
+	Name           string   `yaml:"name"`
+	Instruction    string   `yaml:"instruction"`
+	Text           string   `yaml:"text"`
+	Images         []*Image `yaml:"images"`
+	LLMProvider    string   `yaml:"llm_provider"`
+	RequiredTools  []string `yaml:"required_tools"`
+	Functions      []*FunctionDeclaration
+	ResponseSchema *Schema                `yaml:"response_schema"`
+	Handlers       map[string]HandlerFunc `yaml:"-"`
+	ModelName      string                 `yaml:"model_name"`
+	MaxTokens      int32                  `yaml:"max_tokens"`
+	Temperature    float32                `yaml:"temperature"`
+	TopP           float32                `yaml:"top_p"`
+	// StopSequences end the answer as soon as the model writes one of them
+	StopSequences []string `yaml:"stop_sequences"`
+	// FrequencyPenalty and PresencePenalty (-2 to 2) discourage repeating
+	// tokens by how often, or whether, they appeared. Providers without
+	// them (Anthropic) ignore them.
+	FrequencyPenalty  float32  `yaml:"frequency_penalty"`
+	PresencePenalty   float32  `yaml:"presence_penalty"`
+	MaxToolIterations int32    `yaml:"max_tool_iterations"`
+	ContextBudget     int      `yaml:"context_budget"`
+	MissingTools      []string `yaml:"-"`
+	// MissingToolReasons explains, by name, why a missing tool is
+	// unavailable when it is known (a binary not installed, an MCP server
+	// that did not connect)
+	MissingToolReasons map[string]string `yaml:"-"`
 	// DisableCache asks LLM clients to skip provider-side prompt caching for
 	// this single call (e.g. Anthropic cache_control markers). Set by callers
 	// who know the prefix is not worth caching — verification probes, one-off
 	// throwaway prompts. Persisted caches built from prior calls are unaffected.
 	DisableCache bool `yaml:"-"`
+	// ShowThoughts asks LLM clients to request the model's reasoning where
+	// the provider offers it (Gemini thoughts, Anthropic extended thinking,
+	// reasoning_content from OpenAI-compatible servers) and publish it as
+	// "thought" notifications.
+	ShowThoughts bool `yaml:"-"`
 	// SystemPromptFiles carries the tool-read files accumulator. Lives in its
 	// own cacheable block so readFile churn doesn't invalidate the main
 	// system cache. Placed BEFORE SystemPromptUserContext so users sharing
@@ -20,10 +20,20 @@ type Interaction struct {
 	Duration    time.Duration          `json:"duration"`
 	LLMProvider string                 `json:"llm_provider"`
 	Tools       []string               `json:"tools"`
+	ToolCalls   []CapturedToolCall     `json:"tool_calls,omitempty"`
 	Context     map[string]interface{} `json:"context,omitempty"`
 	Debug       bool                   `json:"debug"`
 }
 
+// CapturedToolCall is a tool the model called during an interaction, with
+// the result it was given
+type CapturedToolCall struct {
+	Name   string         `json:"name"`
+	Args   map[string]any `json:"args,omitempty"`
+	Result map[string]any `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
 // CapturedPrompt represents a prompt that can be serialized
 type CapturedPrompt struct {
 	Name        string                 `json:"name"`
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+// redactionRules replace sensitive values with a placeholder naming what
+// was removed. Order matters: specific token formats run before the
+// broader patterns that could match part of them.
+var redactionRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), "[REDACTED_PRIVATE_KEY]"},
+	{regexp.MustCompile(`\b(?:sk|pk|rk)-[A-Za-z0-9_-]{20,}\b`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), "[REDACTED_AWS_KEY]"},
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{20,}=*`), "Bearer [REDACTED_TOKEN]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[REDACTED_EMAIL]"},
+}
+
+// Redact replaces email addresses, API keys, access tokens and private keys
+// in text with placeholders.
+func Redact(text string) string {
+	for _, rule := range redactionRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
+
+// newRedactMiddleware redacts the prompt text, instructions, system context
+// and template data before they leave the machine.
+func newRedactMiddleware(config.Manager) (Middleware, error) {
+	return Funcs{BeforeFunc: func(ctx context.Context, req *Request) error {
+		req.Prompt.Text = Redact(req.Prompt.Text)
+		req.Prompt.Instruction = Redact(req.Prompt.Instruction)
+		req.Prompt.SystemPromptFiles = Redact(req.Prompt.SystemPromptFiles)
+		req.Prompt.SystemPromptUserContext = Redact(req.Prompt.SystemPromptUserContext)
+
+		args := make([]string, len(req.Args))
+		for i, arg := range req.Args {
+			args[i] = Redact(arg)
+		}
+		req.Args = args
+
+		attrs := make([]ai.Attr, len(req.Attrs))
+		for i, attr := range req.Attrs {
+			attrs[i] = ai.Attr{Key: attr.Key, Value: Redact(attr.Value)}
+		}
+		req.Attrs = attrs
+		return nil
+	}}, nil
+}
+
+// newLogMiddleware logs each request's prompt and the response size.
+func newLogMiddleware(config.Manager) (Middleware, error) {
+	return Funcs{
+		BeforeFunc: func(ctx context.Context, req *Request) error {
+			attrs := make(map[string]string, len(req.Attrs))
+			for _, attr := range req.Attrs {
+				attrs[attr.Key] = attr.Value
+			}
+			slog.Info("LLM request",
+				"prompt", req.Prompt.Name,
+				"provider", req.Prompt.LLMProvider,
+				"model", req.Prompt.ModelName,
+				"stream", req.Stream,
+				"text", req.Prompt.Text,
+				"data", attrs,
+				"args", req.Args,
+			)
+			return nil
+		},
+		AfterFunc: func(ctx context.Context, req *Request, response string) (string, error) {
+			if !req.Stream {
+				slog.Info("LLM response", "prompt", req.Prompt.Name, "chars", len(response))
+			}
+			return response, nil
+		},
+	}, nil
+}
@@ -0,0 +1,187 @@
+// Package middleware wraps an ai.Gen with interceptors that see every
+// request before it reaches the provider and every response before it
+// reaches the caller. Typical uses are redacting sensitive data from
+// prompts, logging prompts, and post-processing answers.
+//
+// Interceptors run once per ai.Gen call. A call covers a whole agentic turn,
+// so tool calls and tool results exchanged with the model inside the turn
+// are not intercepted.
+package middleware
+
+import (
+	"context"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// Request is an ai.Gen call as seen by a Middleware. Before hooks may
+// rewrite any field.
+type Request struct {
+	Prompt ai.Prompt
+	Debug  bool
+	// Args holds the key/value pairs of GenerateContent, GenerateContentStream
+	// and CountTokens calls.
+	Args []string
+	// Attrs holds the template data of the *Attr calls.
+	Attrs  []ai.Attr
+	Stream bool
+	// Count is set on token counting calls, which do not run the model
+	Count bool
+}
+
+// Middleware intercepts ai.Gen calls.
+type Middleware interface {
+	// Before runs ahead of the provider call and may rewrite req. Returning
+	// an error aborts the call with that error.
+	Before(ctx context.Context, req *Request) error
+
+	// After runs on the response text of a successful call and returns the
+	// text handed to the caller. Streaming responses are passed through one
+	// text chunk at a time. Token counting calls have no response.
+	After(ctx context.Context, req *Request, response string) (string, error)
+}
+
+// Funcs adapts plain functions to Middleware. Nil hooks pass through.
+type Funcs struct {
+	BeforeFunc func(ctx context.Context, req *Request) error
+	AfterFunc  func(ctx context.Context, req *Request, response string) (string, error)
+}
+
+// Before calls BeforeFunc when set.
+func (f Funcs) Before(ctx context.Context, req *Request) error {
+	if f.BeforeFunc == nil {
+		return nil
+	}
+	return f.BeforeFunc(ctx, req)
+}
+
+// After calls AfterFunc when set.
+func (f Funcs) After(ctx context.Context, req *Request, response string) (string, error) {
+	if f.AfterFunc == nil {
+		return response, nil
+	}
+	return f.AfterFunc(ctx, req, response)
+}
+
+// Wrap chains middlewares around gen. The first middleware is the
+// outermost: its Before runs first and its After runs last.
+func Wrap(gen ai.Gen, middlewares ...Middleware) ai.Gen {
+	if len(middlewares) == 0 {
+		return gen
+	}
+	return &chain{underlying: gen, middlewares: middlewares}
+}
+
+type chain struct {
+	underlying  ai.Gen
+	middlewares []Middleware
+}
+
+func (c *chain) before(ctx context.Context, req *Request) error {
+	for _, mw := range c.middlewares {
+		if err := mw.Before(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *chain) after(ctx context.Context, req *Request, response string) (string, error) {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		var err error
+		if response, err = c.middlewares[i].After(ctx, req, response); err != nil {
+			return "", err
+		}
+	}
+	return response, nil
+}
+
+func (c *chain) GenerateContent(ctx context.Context, p ai.Prompt, debug bool, args ...string) (string, error) {
+	req := &Request{Prompt: p, Debug: debug, Args: args}
+	if err := c.before(ctx, req); err != nil {
+		return "", err
+	}
+	response, err := c.underlying.GenerateContent(ctx, req.Prompt, req.Debug, req.Args...)
+	if err != nil {
+		return "", err
+	}
+	return c.after(ctx, req, response)
+}
+
+func (c *chain) GenerateContentAttr(ctx context.Context, p ai.Prompt, debug bool, attrs []ai.Attr) (string, error) {
+	req := &Request{Prompt: p, Debug: debug, Attrs: attrs}
+	if err := c.before(ctx, req); err != nil {
+		return "", err
+	}
+	response, err := c.underlying.GenerateContentAttr(ctx, req.Prompt, req.Debug, req.Attrs)
+	if err != nil {
+		return "", err
+	}
+	return c.after(ctx, req, response)
+}
+
+func (c *chain) GenerateContentStream(ctx context.Context, p ai.Prompt, debug bool, args ...string) (ai.Stream, error) {
+	req := &Request{Prompt: p, Debug: debug, Args: args, Stream: true}
+	if err := c.before(ctx, req); err != nil {
+		return nil, err
+	}
+	stream, err := c.underlying.GenerateContentStream(ctx, req.Prompt, req.Debug, req.Args...)
+	if err != nil || stream == nil {
+		return stream, err
+	}
+	return &chainStream{Stream: stream, ctx: ctx, chain: c, req: req}, nil
+}
+
+func (c *chain) GenerateContentAttrStream(ctx context.Context, p ai.Prompt, debug bool, attrs []ai.Attr) (ai.Stream, error) {
+	req := &Request{Prompt: p, Debug: debug, Attrs: attrs, Stream: true}
+	if err := c.before(ctx, req); err != nil {
+		return nil, err
+	}
+	stream, err := c.underlying.GenerateContentAttrStream(ctx, req.Prompt, req.Debug, req.Attrs)
+	if err != nil || stream == nil {
+		return stream, err
+	}
+	return &chainStream{Stream: stream, ctx: ctx, chain: c, req: req}, nil
+}
+
+func (c *chain) CountTokens(ctx context.Context, p ai.Prompt, debug bool, args ...string) (*ai.TokenCount, error) {
+	req := &Request{Prompt: p, Debug: debug, Args: args, Count: true}
+	if err := c.before(ctx, req); err != nil {
+		return nil, err
+	}
+	return c.underlying.CountTokens(ctx, req.Prompt, req.Debug, req.Args...)
+}
+
+func (c *chain) CountTokensAttr(ctx context.Context, p ai.Prompt, debug bool, attrs []ai.Attr) (*ai.TokenCount, error) {
+	req := &Request{Prompt: p, Debug: debug, Attrs: attrs, Count: true}
+	if err := c.before(ctx, req); err != nil {
+		return nil, err
+	}
+	return c.underlying.CountTokensAttr(ctx, req.Prompt, req.Debug, req.Attrs)
+}
+
+func (c *chain) GetStatus() *ai.Status {
+	return c.underlying.GetStatus()
+}
+
+// chainStream runs the After hooks on each text chunk.
+type chainStream struct {
+	ai.Stream
+	ctx   context.Context
+	chain *chain
+	req   *Request
+}
+
+func (s *chainStream) Recv() (*ai.StreamChunk, error) {
+	chunk, err := s.Stream.Recv()
+	if err != nil || chunk == nil || chunk.Text == "" {
+		return chunk, err
+	}
+	text, err := s.chain.after(s.ctx, s.req, chunk.Text)
+	if err != nil {
+		return nil, err
+	}
+	processed := *chunk
+	processed.Text = text
+	return &processed, nil
+}
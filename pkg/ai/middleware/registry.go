@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+// ConfigKey lists the middlewares to chain, by registered name and in
+// order, e.g. "redact,log".
+const ConfigKey = "GENIE_AI_MIDDLEWARE"
+
+// Factory builds a named middleware from configuration.
+type Factory func(cfg config.Manager) (Middleware, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		"log":    newLogMiddleware,
+		"redact": newRedactMiddleware,
+	}
+)
+
+// Register makes a middleware available to GENIE_AI_MIDDLEWARE under name.
+// Registering an existing name replaces it.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(name)] = factory
+}
+
+// Names returns the registered middleware names, sorted.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FromConfig builds the middlewares named in GENIE_AI_MIDDLEWARE. Unknown
+// names are an error so a typo can't silently disable redaction.
+func FromConfig(cfg config.Manager) ([]Middleware, error) {
+	var middlewares []Middleware
+	for _, name := range strings.Split(cfg.GetStringWithDefault(ConfigKey, ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		registryMu.RLock()
+		factory, ok := registry[name]
+		registryMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown AI middleware %q in %s (available: %s)", name, ConfigKey, strings.Join(Names(), ", "))
+		}
+		mw, err := factory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AI middleware %q: %w", name, err)
+		}
+		middlewares = append(middlewares, mw)
+	}
+	return middlewares, nil
+}
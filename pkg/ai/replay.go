@@ -28,18 +28,25 @@ import (
 // with an error replay that error (as a new error carrying the captured
 // message). Streaming calls replay the full recorded response as a single
 // chunk. Token counts were not captured, so CountTokens* return zero counts.
+//
+// Tools are not run: the recorded response already reflects the recorded
+// tool results. With RunTools, the recorded tool calls are made again
+// through the prompt's handlers, with the recorded arguments, before the
+// response is served, so tool events, confirmations and side effects
+// happen as they did in the recording.
 type ReplayGen struct {
 	mu           sync.Mutex
 	interactions []Interaction
 	replayed     []bool
 	source       string
+	runTools     bool
 }
 
 var _ Gen = (*ReplayGen)(nil)
 
 // NewReplayGen loads a capture file (as written by CaptureMiddleware or
 // SaveInteractionsToFile) and returns a Gen that replays it.
-func NewReplayGen(path string) (Gen, error) {
+func NewReplayGen(path string) (*ReplayGen, error) {
 	interactions, err := LoadInteractionsFromFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("replay: %w", err)
@@ -61,19 +68,27 @@ func NewReplayGenFromInteractions(interactions []Interaction) *ReplayGen {
 	}
 }
 
+// RunTools sets whether replayed interactions make their recorded tool calls
+// again.
+func (r *ReplayGen) RunTools(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runTools = enabled
+}
+
 // GenerateContent replays the recorded response for a matching interaction.
 func (r *ReplayGen) GenerateContent(ctx context.Context, p Prompt, debug bool, args ...string) (string, error) {
-	return r.replay(p, args, nil)
+	return r.replay(ctx, p, args, nil)
 }
 
 // GenerateContentAttr replays the recorded response for a matching interaction.
 func (r *ReplayGen) GenerateContentAttr(ctx context.Context, p Prompt, debug bool, attrs []Attr) (string, error) {
-	return r.replay(p, nil, attrs)
+	return r.replay(ctx, p, nil, attrs)
 }
 
 // GenerateContentStream replays a matching interaction as a single-chunk stream.
 func (r *ReplayGen) GenerateContentStream(ctx context.Context, p Prompt, debug bool, args ...string) (Stream, error) {
-	response, err := r.replay(p, args, nil)
+	response, err := r.replay(ctx, p, args, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +97,7 @@ func (r *ReplayGen) GenerateContentStream(ctx context.Context, p Prompt, debug b
 
 // GenerateContentAttrStream replays a matching interaction as a single-chunk stream.
 func (r *ReplayGen) GenerateContentAttrStream(ctx context.Context, p Prompt, debug bool, attrs []Attr) (Stream, error) {
-	response, err := r.replay(p, nil, attrs)
+	response, err := r.replay(ctx, p, nil, attrs)
 	if err != nil {
 		return nil, err
 	}
@@ -109,23 +124,47 @@ func (r *ReplayGen) GetStatus() *Status {
 	}
 }
 
-func (r *ReplayGen) replay(p Prompt, args []string, attrs []Attr) (string, error) {
+func (r *ReplayGen) replay(ctx context.Context, p Prompt, args []string, attrs []Attr) (string, error) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	idx := r.matchLocked(p.Name, args, attrs)
 	if idx < 0 {
-		return "", r.unmatchedErrorLocked(p, args, attrs)
+		err := r.unmatchedErrorLocked(p, args, attrs)
+		r.mu.Unlock()
+		return "", err
 	}
 	r.replayed[idx] = true
-
 	rec := r.interactions[idx]
+	runTools := r.runTools
+	r.mu.Unlock()
+
+	// Tools run unlocked: a tool may start turns of its own (sub-agents)
+	if runTools {
+		if err := r.runToolCalls(ctx, p, rec.ToolCalls); err != nil {
+			return "", err
+		}
+	}
 	if rec.Error != nil {
 		return "", errors.New(rec.Error.Message)
 	}
 	return rec.Response, nil
 }
 
+// runToolCalls makes the recorded tool calls through the prompt's handlers.
+// Tool failures are part of the recording and do not stop the replay.
+func (r *ReplayGen) runToolCalls(ctx context.Context, p Prompt, calls []CapturedToolCall) error {
+	for _, call := range calls {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		handler, ok := p.Handlers[call.Name]
+		if !ok {
+			return fmt.Errorf("replay(%s): prompt %q has no handler for recorded tool %q", r.source, p.Name, call.Name)
+		}
+		handler(ctx, call.Args)
+	}
+	return nil
+}
+
 func (r *ReplayGen) matchLocked(name string, args []string, attrs []Attr) int {
 	// Prefer an exact match on prompt name + args + attrs.
 	for i := range r.interactions {
@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -196,3 +197,69 @@ func TestReplayStatusAndTokenCounts(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int32(0), tc.TotalTokens)
 }
+
+// toolCallingGen calls a tool through the prompt's handlers before
+// answering, as providers do inside a turn.
+type toolCallingGen struct {
+	*scriptedCaptureGen
+	tool string
+	args map[string]any
+}
+
+func (g *toolCallingGen) GenerateContent(ctx context.Context, p Prompt, debug bool, args ...string) (string, error) {
+	result, err := p.Handlers[g.tool](ctx, g.args)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("the file says %v", result["content"]), nil
+}
+
+func TestReplayRepeatsRecordedToolCalls(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "capture.json")
+	mw := newCaptureForTest(t, &toolCallingGen{scriptedCaptureGen: &scriptedCaptureGen{}, tool: "readFile", args: map[string]any{"path": "a.go"}}, file)
+
+	var calls []map[string]any
+	chat := Prompt{Name: "chat", Handlers: map[string]HandlerFunc{
+		"readFile": func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			calls = append(calls, args)
+			return map[string]any{"content": "package a"}, nil
+		},
+	}}
+
+	// Record: the tool call and its result are part of the interaction
+	resp, err := mw.GenerateContent(ctx, chat, false, "q", "read a.go")
+	require.NoError(t, err)
+	assert.Equal(t, "the file says package a", resp)
+	assert.Equal(t, []CapturedToolCall{{
+		Name:   "readFile",
+		Args:   map[string]any{"path": "a.go"},
+		Result: map[string]any{"content": "package a"},
+	}}, mw.GetLastInteraction().ToolCalls)
+	require.Len(t, calls, 1)
+
+	// Replay without tools serves the response alone
+	replay, err := NewReplayGen(file)
+	require.NoError(t, err)
+	got, err := replay.GenerateContent(ctx, chat, false, "q", "read a.go")
+	require.NoError(t, err)
+	assert.Equal(t, "the file says package a", got)
+	assert.Len(t, calls, 1)
+
+	// Replay with tools makes the recorded call again first
+	replay, err = NewReplayGen(file)
+	require.NoError(t, err)
+	replay.RunTools(true)
+	got, err = replay.GenerateContent(ctx, chat, false, "q", "read a.go")
+	require.NoError(t, err)
+	assert.Equal(t, "the file says package a", got)
+	require.Len(t, calls, 2)
+	assert.Equal(t, map[string]any{"path": "a.go"}, calls[1])
+
+	// A recorded tool the prompt no longer has cannot be replayed
+	replay, err = NewReplayGen(file)
+	require.NoError(t, err)
+	replay.RunTools(true)
+	_, err = replay.GenerateContent(ctx, Prompt{Name: "chat"}, false, "q", "read a.go")
+	assert.ErrorContains(t, err, `no handler for recorded tool "readFile"`)
+}
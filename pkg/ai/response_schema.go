@@ -0,0 +1,319 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchema is the subset of JSON Schema that maps onto Schema.
+type jsonSchema struct {
+	Type          json.RawMessage        `json:"type"`
+	Format        string                 `json:"format"`
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description"`
+	Nullable      bool                   `json:"nullable"`
+	Items         *jsonSchema            `json:"items"`
+	MinItems      int64                  `json:"minItems"`
+	MaxItems      int64                  `json:"maxItems"`
+	Enum          []any                  `json:"enum"`
+	Properties    map[string]*jsonSchema `json:"properties"`
+	Required      []string               `json:"required"`
+	MinProperties int64                  `json:"minProperties"`
+	MaxProperties int64                  `json:"maxProperties"`
+	Minimum       *float64               `json:"minimum"`
+	Maximum       *float64               `json:"maximum"`
+	MinLength     int64                  `json:"minLength"`
+	MaxLength     int64                  `json:"maxLength"`
+	Pattern       string                 `json:"pattern"`
+	Ref           string                 `json:"$ref"`
+}
+
+// LoadResponseSchema reads a JSON Schema file for use as a prompt's
+// ResponseSchema.
+func LoadResponseSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	schema, err := ParseJSONSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// ParseJSONSchema converts a JSON Schema document into a Schema. Only the
+// keywords Schema can express are supported; references ($ref) are rejected
+// rather than silently dropped.
+func ParseJSONSchema(data []byte) (*Schema, error) {
+	var raw jsonSchema
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	return convertJSONSchema(&raw, "$")
+}
+
+func convertJSONSchema(raw *jsonSchema, path string) (*Schema, error) {
+	if raw.Ref != "" {
+		return nil, fmt.Errorf("%s: $ref is not supported", path)
+	}
+
+	schemaType, nullable, err := parseJSONSchemaType(raw.Type)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if schemaType == 0 {
+		switch {
+		case len(raw.Properties) > 0:
+			schemaType = TypeObject
+		case raw.Items != nil:
+			schemaType = TypeArray
+		default:
+			return nil, fmt.Errorf("%s: missing type", path)
+		}
+	}
+
+	schema := &Schema{
+		Type:          schemaType,
+		Format:        raw.Format,
+		Title:         raw.Title,
+		Description:   raw.Description,
+		Nullable:      raw.Nullable || nullable,
+		MinItems:      raw.MinItems,
+		MaxItems:      raw.MaxItems,
+		Required:      raw.Required,
+		MinProperties: raw.MinProperties,
+		MaxProperties: raw.MaxProperties,
+		MinLength:     raw.MinLength,
+		MaxLength:     raw.MaxLength,
+		Pattern:       raw.Pattern,
+	}
+	if raw.Minimum != nil {
+		schema.Minimum = *raw.Minimum
+	}
+	if raw.Maximum != nil {
+		schema.Maximum = *raw.Maximum
+	}
+	for _, value := range raw.Enum {
+		schema.Enum = append(schema.Enum, fmt.Sprint(value))
+	}
+	if raw.Items != nil {
+		if schema.Items, err = convertJSONSchema(raw.Items, path+"[]"); err != nil {
+			return nil, err
+		}
+	}
+	if len(raw.Properties) > 0 {
+		schema.Properties = make(map[string]*Schema, len(raw.Properties))
+		for name, property := range raw.Properties {
+			if schema.Properties[name], err = convertJSONSchema(property, path+"."+name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return schema, nil
+}
+
+// parseJSONSchemaType accepts "type" as a string or as a list with at most
+// one non-null type, e.g. ["string", "null"].
+func parseJSONSchemaType(raw json.RawMessage) (Type, bool, error) {
+	if len(raw) == 0 {
+		return 0, false, nil
+	}
+	var names []string
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		names = []string{single}
+	} else if err := json.Unmarshal(raw, &names); err != nil {
+		return 0, false, fmt.Errorf("invalid type %s", raw)
+	}
+
+	var schemaType Type
+	nullable := false
+	for _, name := range names {
+		if name == "null" {
+			nullable = true
+			continue
+		}
+		if schemaType != 0 {
+			return 0, false, fmt.Errorf("multiple types are not supported: %s", raw)
+		}
+		switch name {
+		case "string":
+			schemaType = TypeString
+		case "number":
+			schemaType = TypeNumber
+		case "integer":
+			schemaType = TypeInteger
+		case "boolean":
+			schemaType = TypeBoolean
+		case "array":
+			schemaType = TypeArray
+		case "object":
+			schemaType = TypeObject
+		default:
+			return 0, false, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	return schemaType, nullable, nil
+}
+
+// SchemaValidationError lists every way a value violates a Schema.
+type SchemaValidationError struct {
+	Problems []string
+}
+
+func (e *SchemaValidationError) Error() string {
+	return "response does not match schema: " + strings.Join(e.Problems, "; ")
+}
+
+var jsonFencePattern = regexp.MustCompile("(?s)^```[A-Za-z]*\\s*\n(.*?)\n?```$")
+
+// ExtractJSON returns the JSON document in a model answer, dropping the
+// Markdown code fence models often wrap it in.
+func ExtractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if match := jsonFencePattern.FindStringSubmatch(text); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return text
+}
+
+// ValidateJSON checks that text is a JSON document matching the schema. A
+// mismatch is reported as a *SchemaValidationError.
+func (s *Schema) ValidateJSON(text string) error {
+	var value any
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return &SchemaValidationError{Problems: []string{fmt.Sprintf("not valid JSON: %v", err)}}
+	}
+	if decoder.More() {
+		return &SchemaValidationError{Problems: []string{"not valid JSON: unexpected content after the document"}}
+	}
+
+	var problems []string
+	s.validate(value, "$", &problems)
+	if len(problems) > 0 {
+		return &SchemaValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *Schema) validate(value any, path string, problems *[]string) {
+	fail := func(format string, args ...any) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if value == nil {
+		if !s.Nullable {
+			fail("must not be null")
+		}
+		return
+	}
+
+	switch s.Type {
+	case TypeString:
+		str, ok := value.(string)
+		if !ok {
+			fail("expected string")
+			return
+		}
+		length := int64(utf8.RuneCountInString(str))
+		if s.MinLength > 0 && length < s.MinLength {
+			fail("shorter than %d characters", s.MinLength)
+		}
+		if s.MaxLength > 0 && length > s.MaxLength {
+			fail("longer than %d characters", s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(str) {
+				fail("does not match pattern %q", s.Pattern)
+			}
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			fail("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+	case TypeNumber, TypeInteger:
+		number, ok := value.(json.Number)
+		if !ok {
+			if s.Type == TypeInteger {
+				fail("expected integer")
+			} else {
+				fail("expected number")
+			}
+			return
+		}
+		f, err := number.Float64()
+		if err != nil {
+			fail("invalid number %s", number)
+			return
+		}
+		if s.Type == TypeInteger && f != math.Trunc(f) {
+			fail("expected integer")
+		}
+		if s.Minimum != 0 && f < s.Minimum {
+			fail("less than minimum %v", s.Minimum)
+		}
+		if s.Maximum != 0 && f > s.Maximum {
+			fail("greater than maximum %v", s.Maximum)
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, number.String()) {
+			fail("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean")
+		}
+	case TypeArray:
+		items, ok := value.([]any)
+		if !ok {
+			fail("expected array")
+			return
+		}
+		if s.MinItems > 0 && int64(len(items)) < s.MinItems {
+			fail("fewer than %d items", s.MinItems)
+		}
+		if s.MaxItems > 0 && int64(len(items)) > s.MaxItems {
+			fail("more than %d items", s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case TypeObject:
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("expected object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		if s.MinProperties > 0 && int64(len(object)) < s.MinProperties {
+			fail("fewer than %d properties", s.MinProperties)
+		}
+		if s.MaxProperties > 0 && int64(len(object)) > s.MaxProperties {
+			fail("more than %d properties", s.MaxProperties)
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := s.Properties[name]; ok {
+				property.validate(object[name], path+"."+name, problems)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJSONSchema = `{
+  "title": "release",
+  "type": "object",
+  "required": ["version", "changes"],
+  "properties": {
+    "version": {"type": "string", "pattern": "^v[0-9]+"},
+    "draft": {"type": ["boolean", "null"]},
+    "changes": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "type": "object",
+        "required": ["kind"],
+        "properties": {
+          "kind": {"type": "string", "enum": ["fix", "feature"]},
+          "issue": {"type": "integer", "minimum": 1}
+        }
+      }
+    }
+  }
+}`
+
+func TestParseJSONSchema(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(testJSONSchema))
+	require.NoError(t, err)
+
+	assert.Equal(t, "release", schema.Title)
+	assert.Equal(t, TypeObject, schema.Type)
+	assert.Equal(t, []string{"version", "changes"}, schema.Required)
+	assert.Equal(t, TypeBoolean, schema.Properties["draft"].Type)
+	assert.True(t, schema.Properties["draft"].Nullable)
+
+	changes := schema.Properties["changes"]
+	assert.Equal(t, TypeArray, changes.Type)
+	assert.Equal(t, int64(1), changes.MinItems)
+	assert.Equal(t, []string{"fix", "feature"}, changes.Items.Properties["kind"].Enum)
+	assert.Equal(t, float64(1), changes.Items.Properties["issue"].Minimum)
+}
+
+func TestParseJSONSchemaRejectsUnsupported(t *testing.T) {
+	_, err := ParseJSONSchema([]byte(`{"type": "object", "properties": {"a": {"$ref": "#/defs/a"}}}`))
+	assert.ErrorContains(t, err, "$.a: $ref is not supported")
+
+	_, err = ParseJSONSchema([]byte(`{"type": ["string", "integer"]}`))
+	assert.ErrorContains(t, err, "multiple types")
+
+	_, err = ParseJSONSchema([]byte(`{"description": "no type"}`))
+	assert.ErrorContains(t, err, "missing type")
+}
+
+func TestSchemaValidateJSON(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(testJSONSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    string
+		problems []string
+	}{
+		{
+			name:  "valid",
+			input: `{"version": "v1.2", "draft": null, "changes": [{"kind": "fix", "issue": 12}]}`,
+		},
+		{
+			name:     "not JSON",
+			input:    `Here you go: {"version": "v1"}`,
+			problems: []string{"not valid JSON: invalid character 'H' looking for beginning of value"},
+		},
+		{
+			name:  "nested problems",
+			input: `{"version": "1.2", "changes": [{"kind": "chore", "issue": 1.5}, {}]}`,
+			problems: []string{
+				`$.changes[0].issue: expected integer`,
+				`$.changes[0].kind: must be one of fix, feature`,
+				`$.changes[1]: missing required property "kind"`,
+				`$.version: does not match pattern "^v[0-9]+"`,
+			},
+		},
+		{
+			name:  "wrong types",
+			input: `{"version": 2, "changes": []}`,
+			problems: []string{
+				`$.changes: fewer than 1 items`,
+				`$.version: expected string`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateJSON(tt.input)
+			if tt.problems == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *SchemaValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, tt.problems, validationErr.Problems)
+		})
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	assert.Equal(t, `{"a": 1}`, ExtractJSON("```json\n{\"a\": 1}\n```"))
+	assert.Equal(t, `{"a": 1}`, ExtractJSON("  {\"a\": 1}\n"))
+	assert.Equal(t, "[1]", ExtractJSON("```\n[1]\n```"))
+}
@@ -50,6 +50,29 @@ func IsRetryable(err error) bool {
 	return !errors.As(err, &permanent)
 }
 
+// RetryObserver is told about each failed model request that is about to
+// be retried; attempt counts from 1.
+type RetryObserver func(attempt int, err error)
+
+type retryObserverKey struct{}
+
+// WithRetryObserver returns a context whose model requests report their
+// retries to observer.
+func WithRetryObserver(ctx context.Context, observer RetryObserver) context.Context {
+	return context.WithValue(ctx, retryObserverKey{}, observer)
+}
+
+// NotifyRetry reports a retry to the observer attached to ctx, if any.
+// Code that retries model requests calls it before backing off.
+func NotifyRetry(ctx context.Context, attempt int, err error) {
+	if ctx == nil {
+		return
+	}
+	if observer, ok := ctx.Value(retryObserverKey{}).(RetryObserver); ok && observer != nil {
+		observer(attempt, err)
+	}
+}
+
 // RetryMiddleware wraps an AI Gen implementation to add retry logic
 type RetryMiddleware struct {
 	underlying     Gen
@@ -128,6 +151,7 @@ func retryWithBackoff[T any](ctx context.Context, maxRetries int, initialBackoff
 		}
 
 		log.Printf("Attempt %d to %s failed: %v. Retrying in %v...", i+1, what, err, backoff)
+		NotifyRetry(ctx, i+1, err)
 		select {
 		case <-time.After(backoff):
 		case <-ctx.Done():
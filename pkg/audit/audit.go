@@ -0,0 +1,190 @@
+// Package audit keeps a per-session record of every tool call Genie
+// executed, so users can review what an agent actually did to the
+// machine. Each session appends JSON lines to
+// <genie home>/.genie/audit/<session>.jsonl.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+)
+
+// ConfigKey turns the audit trail off when set to false (default on).
+const ConfigKey = "GENIE_AUDIT"
+
+// maxParamLength caps string parameters in the log; file contents passed
+// to writeFile don't belong in an audit record.
+const maxParamLength = 500
+
+// Tool call statuses.
+const (
+	StatusOK     = "ok"     // handler succeeded and the tool reported success
+	StatusFailed = "failed" // the tool ran but reported failure (success=false)
+	StatusError  = "error"  // the handler returned an error
+)
+
+// Confirmation outcomes.
+const (
+	ConfirmationApproved = "approved"
+	ConfirmationDenied   = "denied"
+)
+
+// Entry is one executed tool call.
+type Entry struct {
+	Time         time.Time      `json:"time"`
+	SessionID    string         `json:"session_id"`
+	Tool         string         `json:"tool"`
+	Params       map[string]any `json:"params,omitempty"`
+	Confirmation string         `json:"confirmation,omitempty"` // empty when no confirmation was asked
+	DurationMS   int64          `json:"duration_ms"`
+	Status       string         `json:"status"`
+	ExitCode     *int           `json:"exit_code,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	OutputBytes  int            `json:"output_bytes"`
+}
+
+// Dir returns the audit directory for a genie home.
+func Dir(genieHome string) string {
+	return filepath.Join(genieHome, ".genie", "audit")
+}
+
+// Path returns the audit file for a session.
+func Path(genieHome, sessionID string) string {
+	return filepath.Join(Dir(genieHome), sessionID+".jsonl")
+}
+
+// Log appends entries to one session's audit file.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog returns a log writing to path. The file and its directory are
+// created on the first Append.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Append writes one entry. String parameters are redacted and truncated.
+func (l *Log) Append(entry Entry) error {
+	entry.Params = sanitizeParams(entry.Params)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+func sanitizeParams(params map[string]any) map[string]any {
+	if len(params) == 0 {
+		return nil
+	}
+	clean := make(map[string]any, len(params))
+	for key, value := range params {
+		if strings.HasPrefix(key, "_") {
+			continue // internal display hints, not tool input
+		}
+		if text, ok := value.(string); ok {
+			text = middleware.Redact(text)
+			if len(text) > maxParamLength {
+				cut := maxParamLength
+				for cut > 0 && !utf8.RuneStart(text[cut]) {
+					cut--
+				}
+				text = fmt.Sprintf("%s… (%d bytes)", text[:cut], len(text))
+			}
+			value = text
+		}
+		clean[key] = value
+	}
+	return clean
+}
+
+// Read loads every entry of an audit file. Malformed lines, e.g. one cut
+// short by a crash, are skipped.
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// SessionFile is a session's audit file.
+type SessionFile struct {
+	SessionID string
+	Path      string
+	ModTime   time.Time
+}
+
+// Sessions lists the audited sessions under a genie home, most recently
+// active first.
+func Sessions(genieHome string) ([]SessionFile, error) {
+	dirEntries, err := os.ReadDir(Dir(genieHome))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	var sessions []SessionFile
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, SessionFile{
+			SessionID: strings.TrimSuffix(name, ".jsonl"),
+			Path:      filepath.Join(Dir(genieHome), name),
+			ModTime:   info.ModTime(),
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ModTime.After(sessions[j].ModTime)
+	})
+	return sessions, nil
+}
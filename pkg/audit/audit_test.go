@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_AppendAndRead(t *testing.T) {
+	home := t.TempDir()
+	log := NewLog(Path(home, "session-1"))
+
+	require.NoError(t, log.Append(Entry{
+		Time:      time.Now(),
+		SessionID: "session-1",
+		Tool:      "writeFile",
+		Params: map[string]any{
+			"file_path":        "main.go",
+			"content":          strings.Repeat("x", 2000),
+			"_display_message": "Writing main.go",
+			"token":            "Bearer abcdefghijklmnopqrstuvwxyz0123456789",
+		},
+		Confirmation: ConfirmationDenied,
+		Status:       StatusFailed,
+	}))
+	require.NoError(t, log.Append(Entry{SessionID: "session-1", Tool: "readFile", Status: StatusOK}))
+
+	entries, err := Read(Path(home, "session-1"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	params := entries[0].Params
+	assert.Equal(t, "main.go", params["file_path"])
+	assert.NotContains(t, params, "_display_message")
+	assert.Contains(t, params["content"], "(2000 bytes)")
+	assert.Less(t, len(params["content"].(string)), 600)
+	assert.NotContains(t, params["token"], "abcdefghijklmnopqrstuvwxyz")
+	assert.Equal(t, ConfirmationDenied, entries[0].Confirmation)
+	assert.Equal(t, "readFile", entries[1].Tool)
+}
+
+func TestRead_SkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"tool":"bash","status":"ok"}`+"\n"+`{"tool":"trunc`), 0o600))
+
+	entries, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "bash", entries[0].Tool)
+}
+
+func TestSessions_NewestFirst(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, NewLog(Path(home, "old")).Append(Entry{Tool: "bash"}))
+	require.NoError(t, NewLog(Path(home, "new")).Append(Entry{Tool: "bash"}))
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(Path(home, "old"), past, past))
+
+	sessions, err := Sessions(home)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "new", sessions[0].SessionID)
+	assert.Equal(t, "old", sessions[1].SessionID)
+
+	none, err := Sessions(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestWriteReport(t *testing.T) {
+	exitCode := 1
+	entries := []Entry{
+		{Time: time.Now(), Tool: "bash", Params: map[string]any{"command": "go test ./..."}, Status: StatusFailed, ExitCode: &exitCode, Error: "command failed", DurationMS: 1500, OutputBytes: 2048},
+		{Time: time.Now(), Tool: "writeFile", Params: map[string]any{"file_path": "main.go"}, Confirmation: ConfirmationDenied, Status: StatusFailed},
+		{Time: time.Now(), Tool: "bash", Params: map[string]any{"command": "ls"}, Status: StatusOK, DurationMS: 20},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReport(&out, "session-1", entries))
+	report := out.String()
+
+	assert.Contains(t, report, "Session session-1: 3 tool calls, 2 failed, 1 denied, 1.52s total")
+	assert.Contains(t, report, "failed (exit 1)")
+	assert.Contains(t, report, "command=go test ./... · command failed")
+	assert.Contains(t, report, "2.0 KB")
+	assert.Contains(t, report, "denied")
+	assert.Contains(t, report, "Tools: bash ×2, writeFile ×1")
+}
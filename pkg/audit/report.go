@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// summaryParams are shown first in a report line because they say the
+// most about what a call touched.
+var summaryParams = []string{"command", "file_path", "path", "source", "destination", "pattern"}
+
+const maxSummaryLength = 80
+
+// WriteReport renders a human-readable report of a session's tool calls:
+// a summary line, one line per call, and per-tool counts.
+func WriteReport(w io.Writer, sessionID string, entries []Entry) error {
+	var failed, denied int
+	var total time.Duration
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Status != StatusOK {
+			failed++
+		}
+		if entry.Confirmation == ConfirmationDenied {
+			denied++
+		}
+		total += time.Duration(entry.DurationMS) * time.Millisecond
+		counts[entry.Tool]++
+	}
+
+	fmt.Fprintf(w, "Session %s: %d tool calls, %d failed, %d denied, %s total\n\n",
+		sessionID, len(entries), failed, denied, total.Round(time.Millisecond))
+	if len(entries) == 0 {
+		return nil
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		status := entry.Status
+		if entry.ExitCode != nil {
+			status = fmt.Sprintf("%s (exit %d)", status, *entry.ExitCode)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.Local().Format("15:04:05"),
+			entry.Tool,
+			status,
+			confirmationLabel(entry.Confirmation),
+			(time.Duration(entry.DurationMS) * time.Millisecond).String(),
+			formatBytes(entry.OutputBytes),
+			summarize(entry),
+		)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	tools := make([]string, 0, len(counts))
+	for tool := range counts {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if counts[tools[i]] != counts[tools[j]] {
+			return counts[tools[i]] > counts[tools[j]]
+		}
+		return tools[i] < tools[j]
+	})
+	parts := make([]string, len(tools))
+	for i, tool := range tools {
+		parts[i] = fmt.Sprintf("%s ×%d", tool, counts[tool])
+	}
+	_, err := fmt.Fprintf(w, "\nTools: %s\n", strings.Join(parts, ", "))
+	return err
+}
+
+func confirmationLabel(confirmation string) string {
+	if confirmation == "" {
+		return "-"
+	}
+	return confirmation
+}
+
+// summarize picks the parameters worth showing on a report line, followed
+// by the error when the call failed.
+func summarize(entry Entry) string {
+	var parts []string
+	for _, key := range summaryParams {
+		if value, ok := entry.Params[key]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+	if len(parts) == 0 {
+		keys := make([]string, 0, len(entry.Params))
+		for key := range entry.Params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s=%v", key, entry.Params[key]))
+		}
+	}
+	summary := strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	if entry.Error != "" {
+		summary = strings.TrimSpace(summary + " · " + entry.Error)
+	}
+	if runes := []rune(summary); len(runes) > maxSummaryLength {
+		summary = string(runes[:maxSummaryLength-1]) + "…"
+	}
+	return summary
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
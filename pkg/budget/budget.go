@@ -0,0 +1,240 @@
+// Package budget caps how much Genie uses the model: requests per hour,
+// tokens per day and dollars per session. Once a budget is used up, every
+// further model call is refused with a message saying which budget ran
+// out, until the window moves on or the user overrides it.
+//
+// Requests are ai.Gen calls: a chat turn, with all its tool calls, is one
+// request, as are sub-agent runs and summaries. Budgets are checked before
+// each request, so the request that crosses a limit still finishes.
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+)
+
+const (
+	// RequestsPerHourConfigKey caps the requests in any hour.
+	RequestsPerHourConfigKey = "GENIE_BUDGET_REQUESTS_PER_HOUR"
+
+	// TokensPerDayConfigKey caps the tokens used in a day, input and
+	// output together.
+	TokensPerDayConfigKey = "GENIE_BUDGET_TOKENS_PER_DAY"
+
+	// CostPerSessionConfigKey caps the estimated cost of a session, in USD.
+	CostPerSessionConfigKey = "GENIE_BUDGET_COST_PER_SESSION"
+
+	// OverrideConfigKey lifts every budget when true; --override-budget
+	// sets it.
+	OverrideConfigKey = "GENIE_BUDGET_OVERRIDE"
+)
+
+// Limits are the configured budgets. Zero means unlimited.
+type Limits struct {
+	RequestsPerHour int
+	TokensPerDay    int64
+	CostPerSession  float64
+}
+
+// LimitsFromConfig reads the budgets from configuration.
+func LimitsFromConfig(cfg config.Manager) (Limits, error) {
+	var limits Limits
+	var err error
+	if limits.RequestsPerHour, err = parseLimit(cfg, RequestsPerHourConfigKey, strconv.Atoi); err != nil {
+		return Limits{}, err
+	}
+	if limits.TokensPerDay, err = parseLimit(cfg, TokensPerDayConfigKey, func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	}); err != nil {
+		return Limits{}, err
+	}
+	if limits.CostPerSession, err = parseLimit(cfg, CostPerSessionConfigKey, func(s string) (float64, error) {
+		return strconv.ParseFloat(strings.TrimPrefix(s, "$"), 64)
+	}); err != nil {
+		return Limits{}, err
+	}
+	return limits, nil
+}
+
+func parseLimit[T int | int64 | float64](cfg config.Manager, key string, parse func(string) (T, error)) (T, error) {
+	value := strings.TrimSpace(cfg.GetStringWithDefault(key, ""))
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := parse(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid %s %q: want a non-negative number", key, value)
+	}
+	return limit, nil
+}
+
+// Set reports whether any budget is configured.
+func (l Limits) Set() bool {
+	return l.RequestsPerHour > 0 || l.TokensPerDay > 0 || l.CostPerSession > 0
+}
+
+// ExceededError is returned for a request made after a budget ran out.
+type ExceededError struct {
+	Budget string
+	Used   string
+	Limit  string
+	// Until is when the budget allows requests again; zero for the
+	// session's cost
+	Until time.Time
+}
+
+func (e *ExceededError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s budget exceeded: used %s of %s", e.Budget, e.Used, e.Limit)
+	if !e.Until.IsZero() {
+		fmt.Fprintf(&b, ". It resets at %s", e.Until.Local().Format("15:04"))
+	}
+	b.WriteString(". Raise the limit, or run genie with --override-budget to go on anyway")
+	return b.String()
+}
+
+// IsExceeded reports whether err is a request refused for a budget.
+func IsExceeded(err error) bool {
+	var exceeded *ExceededError
+	return errors.As(err, &exceeded)
+}
+
+// Budget enforces Limits over a store shared across sessions and the
+// cost of the current session.
+type Budget struct {
+	limits  Limits
+	store   *Store
+	tracker *pricing.Tracker
+	now     func() time.Time
+}
+
+// New returns the Budget of a session, or nil when no budget is set or
+// it is overridden. It records the usage bus reports.
+func New(cfg config.Manager, bus events.Subscriber) (*Budget, error) {
+	if cfg.GetBoolWithDefault(OverrideConfigKey, false) {
+		return nil, nil
+	}
+	limits, err := LimitsFromConfig(cfg)
+	if err != nil || !limits.Set() {
+		return nil, err
+	}
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	table, err := pricing.LoadTable(cfg)
+	if err != nil {
+		slog.Warn("Using built-in pricing for the cost budget", "error", err)
+	}
+	b := newBudget(limits, NewStore(path), table)
+	events.SubscribeTo(bus, b.Record)
+	return b, nil
+}
+
+func newBudget(limits Limits, store *Store, table *pricing.Table) *Budget {
+	return &Budget{
+		limits:  limits,
+		store:   store,
+		tracker: pricing.NewTracker(table),
+		now:     time.Now,
+	}
+}
+
+// Check returns an ExceededError when a budget is used up.
+func (b *Budget) Check() error {
+	now := b.now()
+	if b.limits.CostPerSession > 0 {
+		if cost := b.SessionCost(); cost >= b.limits.CostPerSession {
+			return &ExceededError{
+				Budget: "Session cost",
+				Used:   pricing.FormatCost(cost),
+				Limit:  pricing.FormatCost(b.limits.CostPerSession),
+			}
+		}
+	}
+	if b.limits.TokensPerDay > 0 {
+		tokens, err := b.store.TokensOn(now)
+		if err != nil {
+			return err
+		}
+		if tokens >= b.limits.TokensPerDay {
+			year, month, day := now.Date()
+			return &ExceededError{
+				Budget: "Daily token",
+				Used:   strconv.FormatInt(tokens, 10),
+				Limit:  strconv.FormatInt(b.limits.TokensPerDay, 10),
+				Until:  time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()),
+			}
+		}
+	}
+	if b.limits.RequestsPerHour > 0 {
+		requests, err := b.store.Requests(now)
+		if err != nil {
+			return err
+		}
+		if len(requests) >= b.limits.RequestsPerHour {
+			// A request slot frees up an hour after the request holding it
+			return &ExceededError{
+				Budget: "Hourly request",
+				Used:   strconv.Itoa(len(requests)),
+				Limit:  strconv.Itoa(b.limits.RequestsPerHour),
+				Until:  requests[len(requests)-b.limits.RequestsPerHour].Add(time.Hour),
+			}
+		}
+	}
+	return nil
+}
+
+// Record adds the tokens and cost of a model response to the budgets.
+// Estimated counts cost nothing and are skipped.
+func (b *Budget) Record(e events.TokenCountEvent) {
+	if e.Estimate {
+		return
+	}
+	b.tracker.Record(e)
+
+	tokens := int64(e.TotalTokens)
+	if tokens == 0 {
+		tokens = int64(e.InputTokens) + int64(e.OutputTokens) + int64(e.CachedTokens) + int64(e.CacheCreationInputTokens)
+	}
+	if tokens > 0 && b.limits.TokensPerDay > 0 {
+		if err := b.store.RecordTokens(b.now(), tokens); err != nil {
+			slog.Warn("Failed to record token usage", "error", err)
+		}
+	}
+}
+
+// SessionCost returns the estimated cost of the session so far.
+func (b *Budget) SessionCost() float64 {
+	return b.tracker.Session().Cost
+}
+
+// Middleware refuses model calls once a budget is used up and counts the
+// calls it lets through. Token counting calls are free and always pass.
+func (b *Budget) Middleware() middleware.Middleware {
+	return middleware.Funcs{BeforeFunc: func(ctx context.Context, req *middleware.Request) error {
+		if req.Count {
+			return nil
+		}
+		if err := b.Check(); err != nil {
+			return ai.NonRetryable(err)
+		}
+		if b.limits.RequestsPerHour > 0 {
+			if err := b.store.RecordRequest(b.now()); err != nil {
+				slog.Warn("Failed to record request", "error", err)
+			}
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,130 @@
+package budget
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBudget(t *testing.T, limits Limits, now *time.Time) *Budget {
+	t.Helper()
+	b := newBudget(limits, NewStore(filepath.Join(t.TempDir(), FileName)), nil)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestLimitsFromConfig(t *testing.T) {
+	t.Setenv(RequestsPerHourConfigKey, "60")
+	t.Setenv(TokensPerDayConfigKey, "")
+	t.Setenv(CostPerSessionConfigKey, "$2.50")
+	limits, err := LimitsFromConfig(config.NewConfigManager())
+	require.NoError(t, err)
+	assert.Equal(t, Limits{RequestsPerHour: 60, CostPerSession: 2.5}, limits)
+	assert.True(t, limits.Set())
+
+	t.Setenv(TokensPerDayConfigKey, "lots")
+	_, err = LimitsFromConfig(config.NewConfigManager())
+	assert.ErrorContains(t, err, TokensPerDayConfigKey)
+}
+
+func TestNew_NilWithoutLimitsOrWhenOverridden(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(RequestsPerHourConfigKey, "")
+	t.Setenv(TokensPerDayConfigKey, "")
+	t.Setenv(CostPerSessionConfigKey, "")
+	b, err := New(config.NewConfigManager(), events.NewEventBus())
+	require.NoError(t, err)
+	assert.Nil(t, b)
+
+	t.Setenv(RequestsPerHourConfigKey, "10")
+	t.Setenv(OverrideConfigKey, "true")
+	b, err = New(config.NewConfigManager(), events.NewEventBus())
+	require.NoError(t, err)
+	assert.Nil(t, b)
+}
+
+func TestMiddleware_RequestsPerHour(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	b := testBudget(t, Limits{RequestsPerHour: 2}, &now)
+	mw := b.Middleware()
+	ctx := context.Background()
+
+	require.NoError(t, mw.Before(ctx, &middleware.Request{}))
+	now = now.Add(10 * time.Minute)
+	require.NoError(t, mw.Before(ctx, &middleware.Request{}))
+	require.NoError(t, mw.Before(ctx, &middleware.Request{Count: true}), "token counting is free")
+
+	now = now.Add(10 * time.Minute)
+	err := mw.Before(ctx, &middleware.Request{})
+	require.Error(t, err)
+	assert.True(t, IsExceeded(err))
+	assert.False(t, ai.IsRetryable(err))
+	assert.Contains(t, err.Error(), "used 2 of 2")
+	assert.Contains(t, err.Error(), "--override-budget")
+
+	var exceeded *ExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC), exceeded.Until)
+
+	// An hour after the first request its slot is free again
+	now = exceeded.Until
+	assert.NoError(t, mw.Before(ctx, &middleware.Request{}))
+}
+
+func TestRecord_TokensPerDay(t *testing.T) {
+	now := time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC)
+	b := testBudget(t, Limits{TokensPerDay: 1000}, &now)
+
+	b.Record(events.TokenCountEvent{InputTokens: 400, OutputTokens: 100, Estimate: true})
+	require.NoError(t, b.Check(), "estimates are not usage")
+
+	b.Record(events.TokenCountEvent{InputTokens: 900, OutputTokens: 100})
+	err := b.Check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Daily token budget exceeded: used 1000 of 1000")
+
+	now = now.Add(2 * time.Hour)
+	assert.NoError(t, b.Check(), "the count starts over the next day")
+}
+
+func TestRecord_CostPerSession(t *testing.T) {
+	now := time.Now()
+	b := testBudget(t, Limits{CostPerSession: 1}, &now)
+
+	b.Record(events.TokenCountEvent{Model: "claude-sonnet-4", InputTokens: 200_000, TotalTokens: 200_000})
+	require.NoError(t, b.Check())
+	b.Record(events.TokenCountEvent{Model: "claude-sonnet-4", InputTokens: 200_000, TotalTokens: 200_000})
+
+	err := b.Check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Session cost budget exceeded")
+	assert.NotContains(t, err.Error(), "resets")
+}
+
+func TestStore_SharedAcrossBudgets(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), FileName)
+	first := NewStore(path)
+	require.NoError(t, first.RecordRequest(now))
+	require.NoError(t, first.RecordTokens(now, 42))
+
+	second := NewStore(path)
+	requests, err := second.Requests(now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+	tokens, err := second.TokensOn(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), tokens)
+
+	requests, err = second.Requests(now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+}
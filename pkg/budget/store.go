@@ -0,0 +1,147 @@
+package budget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileName is the store of budget usage in ~/.genie.
+const FileName = "budget.json"
+
+// dayFormat keys the daily token counts by local date
+const dayFormat = "2006-01-02"
+
+// Store keeps the usage that outlives a session: the times of recent
+// requests and the tokens used per day. It is a small JSON file shared by
+// every Genie process of the user.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// storeData is the structure of budget.json
+type storeData struct {
+	Requests []time.Time      `json:"requests,omitempty"`
+	Tokens   map[string]int64 `json:"tokens,omitempty"`
+}
+
+// NewStore returns the store at path. The file is created on the first
+// recorded use.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns ~/.genie/budget.json.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".genie", FileName), nil
+}
+
+// Requests returns the times of the requests made in the hour before now,
+// oldest first.
+func (s *Store) Requests(now time.Time) ([]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return recent(data.Requests, now), nil
+}
+
+// TokensOn returns the tokens used on the day now falls on.
+func (s *Store) TokensOn(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	return data.Tokens[now.Format(dayFormat)], nil
+}
+
+// RecordRequest adds a request made at now.
+func (s *Store) RecordRequest(now time.Time) error {
+	return s.update(now, func(data *storeData) {
+		data.Requests = append(data.Requests, now)
+	})
+}
+
+// RecordTokens adds tokens used at now to that day's count.
+func (s *Store) RecordTokens(now time.Time, tokens int64) error {
+	return s.update(now, func(data *storeData) {
+		data.Tokens[now.Format(dayFormat)] += tokens
+	})
+}
+
+// update applies change to the stored usage, dropping requests older than
+// an hour and the token counts of other days, which no budget looks at.
+func (s *Store) update(now time.Time, change func(*storeData)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.load()
+	if err != nil {
+		return err
+	}
+	data.Requests = recent(data.Requests, now)
+	today := now.Format(dayFormat)
+	for day := range data.Tokens {
+		if day != today {
+			delete(data.Tokens, day)
+		}
+	}
+	change(&data)
+	return s.save(data)
+}
+
+func (s *Store) load() (storeData, error) {
+	data := storeData{Tokens: make(map[string]int64)}
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return data, nil
+	}
+	if err != nil {
+		return data, fmt.Errorf("failed to read budget usage: %w", err)
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return data, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	if data.Tokens == nil {
+		data.Tokens = make(map[string]int64)
+	}
+	return data, nil
+}
+
+func (s *Store) save(data storeData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create budget directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write budget usage: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// recent returns the times within the hour before now
+func recent(times []time.Time, now time.Time) []time.Time {
+	var kept []time.Time
+	for _, t := range times {
+		if now.Sub(t) < time.Hour {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
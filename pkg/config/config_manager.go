@@ -2,7 +2,6 @@ package config
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"time"
 
@@ -38,12 +37,14 @@ func NewConfigManager() Manager {
 	// Try to load .env file, but don't complain if it doesn't exist
 	// Users can provide config via environment variables instead
 	_ = godotenv.Load()
+	// Then ~/.genie/config.env, written by genie setup
+	loadUserConfig()
 	return &DefaultManager{}
 }
 
 // GetString gets a configuration value by key, returns error if not found
 func (m *DefaultManager) GetString(key string) (string, error) {
-	value := os.Getenv(key)
+	value := m.lookup(key)
 	if value == "" {
 		return "", fmt.Errorf("configuration key %s not found", key)
 	}
@@ -52,7 +53,7 @@ func (m *DefaultManager) GetString(key string) (string, error) {
 
 // GetStringWithDefault gets a configuration value by key, returns default if not found
 func (m *DefaultManager) GetStringWithDefault(key, defaultValue string) string {
-	value := os.Getenv(key)
+	value := m.lookup(key)
 	if value == "" {
 		return defaultValue
 	}
@@ -61,7 +62,7 @@ func (m *DefaultManager) GetStringWithDefault(key, defaultValue string) string {
 
 // RequireString gets a configuration value by key, panics if not found
 func (m *DefaultManager) RequireString(key string) string {
-	value := os.Getenv(key)
+	value := m.lookup(key)
 	if value == "" {
 		panic(fmt.Sprintf("required configuration key %s not found", key))
 	}
@@ -70,7 +71,7 @@ func (m *DefaultManager) RequireString(key string) string {
 
 // GetInt gets an integer configuration value by key, returns error if not found or invalid
 func (m *DefaultManager) GetInt(key string) (int, error) {
-	value := os.Getenv(key)
+	value := m.lookup(key)
 	if value == "" {
 		return 0, fmt.Errorf("configuration key %s not found", key)
 	}
@@ -83,7 +84,7 @@ func (m *DefaultManager) GetInt(key string) (int, error) {
 
 // GetIntWithDefault gets an integer configuration value by key, returns default if not found or invalid
 func (m *DefaultManager) GetIntWithDefault(key string, defaultValue int) int {
-	value := os.Getenv(key)
+	value := m.lookup(key)
 	if value == "" {
 		return defaultValue
 	}
@@ -96,7 +97,7 @@ func (m *DefaultManager) GetIntWithDefault(key string, defaultValue int) int {
 
 // GetBoolWithDefault gets a boolean configuration value by key, returns default if not found or invalid
 func (m *DefaultManager) GetBoolWithDefault(key string, defaultValue bool) bool {
-	value := os.Getenv(key)
+	value := m.lookup(key)
 	if value == "" {
 		return defaultValue
 	}
@@ -109,7 +110,7 @@ func (m *DefaultManager) GetBoolWithDefault(key string, defaultValue bool) bool
 
 // GetDurationWithDefault gets a duration configuration value by key, returns default if not found or invalid
 func (m *DefaultManager) GetDurationWithDefault(key string, defaultValue time.Duration) time.Duration {
-	value := os.Getenv(key)
+	value := m.lookup(key)
 	if value == "" {
 		return defaultValue
 	}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/credentials"
+	"github.com/kcaldas/genie/pkg/logging"
+)
+
+// resolved caches the credentials that keychain: references resolved to,
+// so the keychain is asked once per name
+var resolved sync.Map
+
+// lookup returns the value of key, resolving a keychain:<name> reference
+// to the stored credential. A reference that cannot be resolved reads as
+// unset, with a warning.
+func (m *DefaultManager) lookup(key string) string {
+	value := os.Getenv(key)
+	name, ok := credentials.ParseReference(value)
+	if !ok {
+		return value
+	}
+	if secret, ok := resolved.Load(name); ok {
+		return secret.(string)
+	}
+
+	store, err := credentials.Open(os.Getenv(credentials.BackendConfigKey), os.Getenv(credentials.PassphraseConfigKey))
+	if err != nil {
+		logging.GetGlobalLogger().Warn("cannot resolve credential reference", "key", key, "error", err)
+		return ""
+	}
+	secret, err := store.Get(name)
+	if err != nil {
+		logging.GetGlobalLogger().Warn("cannot resolve credential reference", "key", key, "error", err)
+		return ""
+	}
+	resolved.Store(name, secret)
+	return secret
+}
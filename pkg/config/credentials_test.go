@@ -0,0 +1,34 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_ResolvesCredentialReferences(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(credentials.BackendConfigKey, "file")
+	t.Setenv(credentials.PassphraseConfigKey, "correct horse")
+
+	store, err := credentials.NewFileStore(filepath.Join(home, ".genie", credentials.FileName), "correct horse")
+	require.NoError(t, err)
+	require.NoError(t, store.Set("config-test", "sk-from-store"))
+
+	t.Setenv("GENIE_TEST_API_KEY", "keychain:config-test")
+	t.Setenv("GENIE_TEST_MISSING_KEY", "keychain:config-test-missing")
+	manager := NewConfigManager()
+
+	value, err := manager.GetString("GENIE_TEST_API_KEY")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-from-store", value)
+
+	// A reference that does not resolve reads as unset
+	assert.Equal(t, "fallback", manager.GetStringWithDefault("GENIE_TEST_MISSING_KEY", "fallback"))
+	_, err = manager.GetString("GENIE_TEST_MISSING_KEY")
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+)
+
+// UserConfigFile is the name of the user config file in ~/.genie. It holds
+// KEY=value lines, like a .env file, and is readable by its owner only
+// since it may contain API keys.
+const UserConfigFile = "config.env"
+
+// UserConfigPath returns the path of ~/.genie/config.env.
+func UserConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".genie", UserConfigFile), nil
+}
+
+// loadUserConfig sets the values of the user config file that the
+// environment or the working directory's .env did not set
+func loadUserConfig() {
+	path, err := UserConfigPath()
+	if err != nil {
+		return
+	}
+	_ = godotenv.Load(path)
+}
+
+// SaveUserConfig merges values into the user config file, creating it
+// with owner-only permissions, and sets them in the environment of the
+// running process.
+func SaveUserConfig(values map[string]string) error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+
+	merged := make(map[string]string)
+	if existing, err := godotenv.Read(path); err == nil {
+		merged = existing
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	maps.Copy(merged, values)
+
+	content, err := godotenv.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to encode user config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	// WriteFile keeps the mode of a file that already exists
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		os.Setenv(key, value)
+	}
+	return nil
+}
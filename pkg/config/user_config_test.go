@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveUserConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GENIE_TEST_KEY", "")
+	t.Setenv("GENIE_TEST_MODEL", "")
+	path := filepath.Join(home, ".genie", UserConfigFile)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("GENIE_TEST_MODEL=old\nOTHER=kept\n"), 0644))
+
+	require.NoError(t, SaveUserConfig(map[string]string{"GENIE_TEST_KEY": "secret value", "GENIE_TEST_MODEL": "new"}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `GENIE_TEST_KEY="secret value"`)
+	assert.Contains(t, string(data), `GENIE_TEST_MODEL="new"`)
+	assert.Contains(t, string(data), `OTHER="kept"`)
+	assert.Equal(t, "secret value", os.Getenv("GENIE_TEST_KEY"))
+}
+
+func TestNewConfigManager_LoadsUserConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GENIE_TEST_KEY", "from-env")
+	t.Setenv("GENIE_TEST_MODEL", "")
+	os.Unsetenv("GENIE_TEST_MODEL")
+	path := filepath.Join(home, ".genie", UserConfigFile)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte("GENIE_TEST_KEY=from-file\nGENIE_TEST_MODEL=from-file\n"), 0600))
+
+	manager := NewConfigManager()
+
+	// The environment wins over the file
+	assert.Equal(t, "from-env", manager.GetStringWithDefault("GENIE_TEST_KEY", ""))
+	assert.Equal(t, "from-file", manager.GetStringWithDefault("GENIE_TEST_MODEL", ""))
+}
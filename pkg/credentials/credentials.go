@@ -0,0 +1,99 @@
+// Package credentials keeps API keys out of plaintext configuration. Keys
+// are stored in the OS keychain (macOS Keychain, the Secret Service on
+// Linux, Windows Credential Manager) or in a passphrase-encrypted file, and
+// config values refer to them as keychain:<name>, e.g.
+// OPENAI_API_KEY=keychain:openai.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReferencePrefix marks a config value that names a stored credential.
+const ReferencePrefix = "keychain:"
+
+// Service is the service name credentials are stored under in the OS
+// keychain.
+const Service = "genie"
+
+const (
+	// BackendConfigKey selects where credentials are stored: "keychain"
+	// (the default) or "file".
+	BackendConfigKey = "GENIE_CREDENTIALS_BACKEND"
+	// PassphraseConfigKey holds the passphrase of the encrypted file.
+	PassphraseConfigKey = "GENIE_CREDENTIALS_PASSPHRASE"
+	// FileName is the encrypted file in ~/.genie.
+	FileName = "credentials.enc"
+)
+
+var (
+	// ErrNotFound is returned for a name with no stored credential.
+	ErrNotFound = errors.New("credential not found")
+	// ErrUnsupported is returned when no OS keychain is available.
+	ErrUnsupported = errors.New("no OS keychain available")
+)
+
+// Store saves secrets by name.
+type Store interface {
+	// Name describes where the secrets are kept, for messages
+	Name() string
+	Get(name string) (string, error)
+	Set(name, secret string) error
+	Delete(name string) error
+}
+
+// ParseReference returns the credential name a config value refers to.
+func ParseReference(value string) (string, bool) {
+	name, ok := strings.CutPrefix(value, ReferencePrefix)
+	if !ok || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// Reference returns the config value that refers to the credential name.
+func Reference(name string) string {
+	return ReferencePrefix + name
+}
+
+// ValidateName checks that name can be used as a credential name.
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("credential name is empty")
+	}
+	if strings.ContainsAny(name, " \t\r\n:/\\") {
+		return fmt.Errorf("credential name %q must not contain spaces, colons or slashes", name)
+	}
+	return nil
+}
+
+// Open returns the store the backend names: "keychain" or empty for the
+// OS keychain, "file" for the encrypted file in ~/.genie, which needs the
+// passphrase.
+func Open(backend, passphrase string) (Store, error) {
+	switch strings.ToLower(backend) {
+	case "", "keychain":
+		return NewKeychain()
+	case "file":
+		path, err := DefaultFilePath()
+		if err != nil {
+			return nil, err
+		}
+		return NewFileStore(path, passphrase)
+	default:
+		return nil, fmt.Errorf("unknown %s %q: use keychain or file", BackendConfigKey, backend)
+	}
+}
+
+// DefaultFilePath returns ~/.genie/credentials.enc.
+func DefaultFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".genie", FileName), nil
+}
@@ -0,0 +1,41 @@
+package credentials
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	name, ok := ParseReference("keychain:openai")
+	assert.True(t, ok)
+	assert.Equal(t, "openai", name)
+
+	for _, value := range []string{"sk-123", "keychain:", ""} {
+		_, ok := ParseReference(value)
+		assert.False(t, ok, value)
+	}
+	assert.Equal(t, "keychain:anthropic", Reference("anthropic"))
+}
+
+func TestValidateName(t *testing.T) {
+	assert.NoError(t, ValidateName("openai-work"))
+	assert.Error(t, ValidateName(""))
+	assert.Error(t, ValidateName("my key"))
+	assert.Error(t, ValidateName("a:b"))
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := Open("file", "")
+	assert.ErrorContains(t, err, PassphraseConfigKey)
+
+	_, err = Open("vault", "")
+	assert.ErrorContains(t, err, "unknown GENIE_CREDENTIALS_BACKEND")
+
+	store, err := Open("file", "secret")
+	require.NoError(t, err)
+	assert.Contains(t, store.Name(), FileName)
+}
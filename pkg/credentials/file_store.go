@@ -0,0 +1,207 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters for deriving the file key from the passphrase
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	keyLength    = 32
+	saltLength   = 16
+	fileVersion1 = 1
+)
+
+// encryptedFile is the JSON layout of the credentials file. Data is the
+// AES-256-GCM sealed JSON object of secrets by name.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// FileStore keeps credentials in a file encrypted with a key derived from
+// a passphrase. The file is rewritten, readable by its owner only, on
+// every change.
+type FileStore struct {
+	path       string
+	passphrase string
+	mu         sync.Mutex
+}
+
+// NewFileStore returns a store backed by the file at path. The file is
+// created on the first Set.
+func NewFileStore(path, passphrase string) (*FileStore, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("the encrypted credentials file needs a passphrase: set %s", PassphraseConfigKey)
+	}
+	return &FileStore{path: path, passphrase: passphrase}, nil
+}
+
+// Name implements Store.
+func (s *FileStore) Name() string {
+	return "encrypted file " + s.path
+}
+
+// Get implements Store.
+func (s *FileStore) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secrets, _, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s in %s", ErrNotFound, name, s.path)
+	}
+	return secret, nil
+}
+
+// Names returns the names of the stored credentials, sorted.
+func (s *FileStore) Names() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secrets, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Set implements Store.
+func (s *FileStore) Set(name, secret string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secrets, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[name] = secret
+	return s.save(secrets, salt)
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secrets, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return fmt.Errorf("%w: %s in %s", ErrNotFound, name, s.path)
+	}
+	delete(secrets, name)
+	return s.save(secrets, salt)
+}
+
+// load decrypts the file; a missing file holds no secrets
+func (s *FileStore) load() (map[string]string, []byte, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", s.path, err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	if file.Version != fileVersion1 {
+		return nil, nil, fmt.Errorf("%s has unsupported version %d", s.path, file.Version)
+	}
+	gcm, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt %s: wrong passphrase or corrupted file", s.path)
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the secrets in %s: %w", s.path, err)
+	}
+	return secrets, file.Salt, nil
+}
+
+// save encrypts secrets with a fresh nonce, keeping the file's salt
+func (s *FileStore) save(secrets map[string]string, salt []byte) error {
+	if salt == nil {
+		salt = make([]byte, saltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(encryptedFile{
+		Version: fileVersion1,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.path), err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *FileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(s.passphrase), salt, scryptN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the credentials key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
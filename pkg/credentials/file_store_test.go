@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".genie", FileName)
+	store, err := NewFileStore(path, "correct horse")
+	require.NoError(t, err)
+
+	_, err = store.Get("openai")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Set("openai", "sk-openai"))
+	require.NoError(t, store.Set("anthropic", "sk-ant"))
+
+	secret, err := store.Get("openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-openai", secret)
+	names, err := store.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"anthropic", "openai"}, names)
+
+	// The file is private and holds no plaintext
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "sk-openai")
+
+	require.NoError(t, store.Delete("openai"))
+	_, err = store.Get("openai")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.Delete("openai"), ErrNotFound)
+}
+
+func TestFileStore_WrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	store, err := NewFileStore(path, "correct horse")
+	require.NoError(t, err)
+	require.NoError(t, store.Set("openai", "sk-openai"))
+
+	other, err := NewFileStore(path, "battery staple")
+	require.NoError(t, err)
+	_, err = other.Get("openai")
+	assert.ErrorContains(t, err, "wrong passphrase")
+	assert.Error(t, other.Set("anthropic", "sk-ant"), "a wrong passphrase must not overwrite the file")
+
+	secret, err := store.Get("openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-openai", secret)
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package credentials
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// runCommand runs a keychain tool with input on stdin, returning its
+// trimmed stdout and its exit code. Tests replace it.
+var runCommand = func(input string, name string, args ...string) (string, int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return strings.TrimSpace(stdout.String()), exitErr.ExitCode(), &commandError{msg: msg}
+		}
+		return strings.TrimSpace(stdout.String()), exitErr.ExitCode(), err
+	}
+	return strings.TrimSpace(stdout.String()), 0, err
+}
+
+// commandError carries what a keychain tool printed when it failed
+type commandError struct {
+	msg string
+}
+
+func (e *commandError) Error() string {
+	return e.msg
+}
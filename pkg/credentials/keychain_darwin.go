@@ -0,0 +1,77 @@
+package credentials
+
+import (
+	"fmt"
+	"strings"
+)
+
+// errSecItemNotFound is the exit code of security(1) for a missing item
+const errSecItemNotFound = 44
+
+// Keychain stores credentials in the macOS Keychain, through the
+// security command, as generic passwords of the genie service.
+type Keychain struct{}
+
+// NewKeychain returns the macOS Keychain store.
+func NewKeychain() (Store, error) {
+	return &Keychain{}, nil
+}
+
+// Name implements Store.
+func (k *Keychain) Name() string {
+	return "macOS Keychain"
+}
+
+// Get implements Store.
+func (k *Keychain) Get(name string) (string, error) {
+	secret, code, err := runCommand("", "security", "find-generic-password", "-s", Service, "-a", name, "-w")
+	if code == errSecItemNotFound {
+		return "", fmt.Errorf("%w: %s in the macOS Keychain", ErrNotFound, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from the macOS Keychain: %w", name, err)
+	}
+	return secret, nil
+}
+
+// Set implements Store. The secret reaches security's interactive mode on
+// stdin rather than as an argument, so other processes never see it. That
+// mode does not exit with an error when a command fails, so the item is
+// read back to confirm it was stored.
+func (k *Keychain) Set(name, secret string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if strings.ContainsAny(secret, "\r\n") {
+		return fmt.Errorf("failed to store %s in the macOS Keychain: the secret must be a single line", name)
+	}
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -l %s -w %s\n",
+		quoteSecurityArg(Service), quoteSecurityArg(name), quoteSecurityArg("Genie: "+name), quoteSecurityArg(secret))
+	if _, _, err := runCommand(command, "security", "-i"); err != nil {
+		return fmt.Errorf("failed to store %s in the macOS Keychain: %w", name, err)
+	}
+	if stored, err := k.Get(name); err != nil || stored != secret {
+		if err == nil {
+			err = fmt.Errorf("the stored secret does not match")
+		}
+		return fmt.Errorf("failed to store %s in the macOS Keychain: %w", name, err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (k *Keychain) Delete(name string) error {
+	_, code, err := runCommand("", "security", "delete-generic-password", "-s", Service, "-a", name)
+	if code == errSecItemNotFound {
+		return fmt.Errorf("%w: %s in the macOS Keychain", ErrNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete %s from the macOS Keychain: %w", name, err)
+	}
+	return nil
+}
+
+// quoteSecurityArg double-quotes an argument for a security -i command line
+func quoteSecurityArg(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
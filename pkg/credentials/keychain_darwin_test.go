@@ -0,0 +1,55 @@
+package credentials
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeychain_Security(t *testing.T) {
+	items := map[string]string{}
+	var calls []string
+	var inputs []string
+	original := runCommand
+	defer func() { runCommand = original }()
+	runCommand = func(input, name string, args ...string) (string, int, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		inputs = append(inputs, input)
+		switch args[0] {
+		case "-i":
+			items["openai"] = `sk-"open\ai`
+		case "find-generic-password":
+			if secret, ok := items[args[4]]; ok {
+				return secret, 0, nil
+			}
+			return "", errSecItemNotFound, errors.New("exit status 44")
+		case "delete-generic-password":
+			delete(items, args[4])
+		}
+		return "", 0, nil
+	}
+
+	keychain := &Keychain{}
+	require.NoError(t, keychain.Set("openai", `sk-"open\ai`))
+	secret, err := keychain.Get("openai")
+	require.NoError(t, err)
+	assert.Equal(t, `sk-"open\ai`, secret)
+
+	require.NoError(t, keychain.Delete("openai"))
+	_, err = keychain.Get("openai")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// The secret travels on stdin, quoted, never in the arguments
+	assert.Equal(t, "security -i", calls[0])
+	assert.Equal(t, `add-generic-password -U -s "genie" -a "openai" -l "Genie: openai" -w "sk-\"open\\ai"`+"\n", inputs[0])
+	for _, call := range calls {
+		assert.NotContains(t, call, "sk-")
+	}
+
+	// A write security did not carry out is reported
+	assert.Error(t, keychain.Set("anthropic", "sk-anthropic"))
+	assert.Error(t, keychain.Set("openai", "line\nbreak"))
+}
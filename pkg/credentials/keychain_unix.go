@@ -0,0 +1,61 @@
+//go:build !darwin && !windows
+
+package credentials
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Keychain stores credentials with the Secret Service (GNOME Keyring,
+// KWallet) through libsecret's secret-tool, under the genie service.
+type Keychain struct{}
+
+// NewKeychain returns the Secret Service store, or ErrUnsupported when
+// secret-tool is not installed.
+func NewKeychain() (Store, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, fmt.Errorf("%w: install secret-tool (libsecret-tools) or set %s=file", ErrUnsupported, BackendConfigKey)
+	}
+	return &Keychain{}, nil
+}
+
+// Name implements Store.
+func (k *Keychain) Name() string {
+	return "Secret Service keyring"
+}
+
+// Get implements Store. secret-tool prints nothing and fails for a
+// missing item.
+func (k *Keychain) Get(name string) (string, error) {
+	secret, code, err := runCommand("", "secret-tool", "lookup", "service", Service, "account", name)
+	if code == 1 && secret == "" {
+		return "", fmt.Errorf("%w: %s in the Secret Service keyring", ErrNotFound, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from the Secret Service keyring: %w", name, err)
+	}
+	return secret, nil
+}
+
+// Set implements Store. The secret goes through stdin.
+func (k *Keychain) Set(name, secret string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if _, _, err := runCommand(secret, "secret-tool", "store", "--label=Genie: "+name, "service", Service, "account", name); err != nil {
+		return fmt.Errorf("failed to store %s in the Secret Service keyring: %w", name, err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (k *Keychain) Delete(name string) error {
+	if _, err := k.Get(name); err != nil {
+		return err
+	}
+	if _, _, err := runCommand("", "secret-tool", "clear", "service", Service, "account", name); err != nil {
+		return fmt.Errorf("failed to delete %s from the Secret Service keyring: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+//go:build !darwin && !windows
+
+package credentials
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeychain_SecretTool(t *testing.T) {
+	items := map[string]string{}
+	var calls []string
+	original := runCommand
+	defer func() { runCommand = original }()
+	runCommand = func(input, name string, args ...string) (string, int, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		account := args[len(args)-1]
+		switch args[0] {
+		case "store":
+			items[account] = input
+		case "lookup":
+			if secret, ok := items[account]; ok {
+				return secret, 0, nil
+			}
+			return "", 1, errors.New("exit status 1")
+		case "clear":
+			delete(items, account)
+		}
+		return "", 0, nil
+	}
+
+	keychain := &Keychain{}
+	require.NoError(t, keychain.Set("openai", "sk-openai"))
+	secret, err := keychain.Get("openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-openai", secret)
+
+	require.NoError(t, keychain.Delete("openai"))
+	_, err = keychain.Get("openai")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// The secret travels on stdin, never in the arguments
+	assert.Equal(t, "secret-tool store --label=Genie: openai service genie account openai", calls[0])
+	for _, call := range calls {
+		assert.NotContains(t, call, "sk-openai")
+	}
+}
@@ -0,0 +1,122 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+)
+
+var (
+	advapi32       = windows.NewLazySystemDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// credential mirrors the Win32 CREDENTIALW structure
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// Keychain stores credentials in the Windows Credential Manager as
+// generic credentials named genie:<name>.
+type Keychain struct{}
+
+// NewKeychain returns the Windows Credential Manager store.
+func NewKeychain() (Store, error) {
+	return &Keychain{}, nil
+}
+
+// Name implements Store.
+func (k *Keychain) Name() string {
+	return "Windows Credential Manager"
+}
+
+func targetName(name string) (*uint16, error) {
+	return windows.UTF16PtrFromString(Service + ":" + name)
+}
+
+// Get implements Store.
+func (k *Keychain) Get(name string) (string, error) {
+	target, err := targetName(name)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	ret, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errors.Is(err, windows.ERROR_NOT_FOUND) {
+			return "", fmt.Errorf("%w: %s in the Windows Credential Manager", ErrNotFound, name)
+		}
+		return "", fmt.Errorf("failed to read %s from the Windows Credential Manager: %w", name, err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// Set implements Store.
+func (k *Keychain) Set(name, secret string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	target, err := targetName(name)
+	if err != nil {
+		return err
+	}
+	user, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		UserName:           user,
+		Persist:            credPersistLocalMachine,
+		CredentialBlobSize: uint32(len(blob)),
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if ret, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); ret == 0 {
+		return fmt.Errorf("failed to store %s in the Windows Credential Manager: %w", name, err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (k *Keychain) Delete(name string) error {
+	target, err := targetName(name)
+	if err != nil {
+		return err
+	}
+	if ret, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); ret == 0 {
+		if errors.Is(err, windows.ERROR_NOT_FOUND) {
+			return fmt.Errorf("%w: %s in the Windows Credential Manager", ErrNotFound, name)
+		}
+		return fmt.Errorf("failed to delete %s from the Windows Credential Manager: %w", name, err)
+	}
+	return nil
+}
@@ -19,6 +19,9 @@ type Message struct {
 type ChatContextPartProvider interface {
 	ContextPartProvider
 	SeedHistory(history []Message)
+	// History returns a copy of every recorded exchange, oldest first,
+	// before any budget trimming.
+	History() []Message
 	SetBudgetStrategy(strategy CollectionBudgetStrategy[Message])
 	// AddTurn records one completed exchange. Empty user or assistant
 	// sides are allowed (ephemeral modes); a fully empty turn is ignored.
@@ -142,6 +145,16 @@ func (m *InMemoryChatContextPartProvider) ClearPart() error {
 	return nil
 }
 
+// History returns a copy of the recorded exchanges, oldest first.
+func (m *InMemoryChatContextPartProvider) History() []Message {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := make([]Message, len(m.messages))
+	copy(history, m.messages)
+	return history
+}
+
 // SeedHistory replaces the current chat history with the provided messages.
 func (m *InMemoryChatContextPartProvider) SeedHistory(history []Message) {
 	m.mu.Lock()
@@ -2,6 +2,7 @@ package ctx
 
 import (
 	"context"
+	"sync"
 )
 
 // ContextPart represents a part of the context with its key
@@ -50,16 +51,27 @@ type ContextManager interface {
 	GetContextParts(ctx context.Context) (map[string]string, error)
 	ClearContext() error
 	SeedChatHistory(history []Message)
+	// ChatHistory returns the recorded conversation, oldest first.
+	ChatHistory() []Message
 	// RecordChatTurn synchronously appends a completed exchange to the
 	// conversation history. The core calls this after each successful
 	// turn; history must never depend on asynchronous event delivery.
 	RecordChatTurn(user, assistant string)
 	SetContextBudget(totalTokens int)
+	// PartBudgets returns the tokens each context part may use before it
+	// is trimmed, by part key. Parts without a budget are left out.
+	PartBudgets() map[string]int
 }
 
 // InMemoryManager implements ContextManager with registry-based providers
 type InMemoryManager struct {
 	registry *ContextPartProviderRegistry
+
+	// The budget given to each provider and the key of the part it last
+	// returned, by registration order
+	mu       sync.Mutex
+	budgets  []int
+	partKeys []string
 }
 
 // NewContextManager creates a new context manager with registry
@@ -76,28 +88,52 @@ func (m *InMemoryManager) SetContextBudget(totalTokens int) {
 		totalShare += s
 	}
 
+	budgets := make([]int, len(m.registry.providers))
 	for i, provider := range m.registry.providers {
 		share := m.registry.budgetShares[i]
 		if totalShare > 0 && share > 0 {
-			provider.SetTokenBudget(int(float64(totalTokens) * share / totalShare))
-		} else {
-			provider.SetTokenBudget(0)
+			budgets[i] = int(float64(totalTokens) * share / totalShare)
 		}
+		provider.SetTokenBudget(budgets[i])
 	}
+
+	m.mu.Lock()
+	m.budgets = budgets
+	m.mu.Unlock()
+}
+
+// PartBudgets returns the budget of each part assembled so far, by key.
+func (m *InMemoryManager) PartBudgets() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	budgets := make(map[string]int)
+	for i, key := range m.partKeys {
+		if key != "" && i < len(m.budgets) && m.budgets[i] > 0 {
+			budgets[key] = m.budgets[i]
+		}
+	}
+	return budgets
 }
 
 // GetContextParts retrieves all context parts from registered providers.
 func (m *InMemoryManager) GetContextParts(ctx context.Context) (map[string]string, error) {
 	parts := make(map[string]string)
-	for _, provider := range m.registry.GetProviders() {
+	providers := m.registry.GetProviders()
+	keys := make([]string, len(providers))
+	for i, provider := range providers {
 		part, err := provider.GetPart(ctx)
 		if err != nil {
 			return nil, err
 		}
+		keys[i] = part.Key
 		if part.Content != "" {
 			parts[part.Key] = part.Content
 		}
 	}
+
+	m.mu.Lock()
+	m.partKeys = keys
+	m.mu.Unlock()
 	return parts, nil
 }
 
@@ -127,6 +163,16 @@ func (m *InMemoryManager) SeedChatHistory(history []Message) {
 	}
 }
 
+// ChatHistory returns the exchanges held by the chat history provider.
+func (m *InMemoryManager) ChatHistory() []Message {
+	for _, provider := range m.registry.GetProviders() {
+		if history, ok := provider.(interface{ History() []Message }); ok {
+			return history.History()
+		}
+	}
+	return nil
+}
+
 // RecordChatTurn appends a completed exchange to the chat history provider.
 func (m *InMemoryManager) RecordChatTurn(user, assistant string) {
 	for _, provider := range m.registry.GetProviders() {
@@ -205,6 +205,40 @@ func TestContextManager_SeedChatHistory(t *testing.T) {
 	assert.Contains(t, parts["chat"], "Assistant: Hello there!")
 }
 
+func TestContextManager_ChatHistory(t *testing.T) {
+	eventBus := events.NewEventBus()
+	registry := NewContextPartProviderRegistry()
+	registry.Register(NewProjectCtxManager(eventBus), 0)
+	registry.Register(NewChatCtxManager(eventBus), 0)
+	manager := NewContextManager(registry)
+
+	assert.Empty(t, manager.ChatHistory())
+
+	manager.SeedChatHistory([]Message{{User: "Hi", Assistant: "Hello"}})
+	manager.RecordChatTurn("Bye", "See you")
+	history := manager.ChatHistory()
+	assert.Equal(t, []Message{{User: "Hi", Assistant: "Hello"}, {User: "Bye", Assistant: "See you"}}, history)
+
+	// The result is a copy
+	history[0].User = "changed"
+	assert.Equal(t, "Hi", manager.ChatHistory()[0].User)
+}
+
+func TestContextManager_PartBudgets(t *testing.T) {
+	eventBus := events.NewEventBus()
+	registry := NewContextPartProviderRegistry()
+	registry.Register(NewProjectCtxManager(eventBus), 0)
+	registry.Register(NewChatCtxManager(eventBus), 0.7)
+	manager := NewContextManager(registry)
+	manager.SetContextBudget(1000)
+
+	// Keys are learned when the parts are assembled
+	assert.Empty(t, manager.PartBudgets())
+	_, err := manager.GetContextParts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"chat": 1000}, manager.PartBudgets(), "unbudgeted parts are left out")
+}
+
 func TestContextManager_GetContextParts_MultipleChatMessages(t *testing.T) {
 	// Create event bus and managers
 	eventBus := events.NewEventBus()
@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
 )
 
+// staleFileNote replaces the contents of a file that changed on disk after
+// the agent read it
+const staleFileNote = "(stale: this file changed on disk after it was read; read it again before relying on its contents)"
+
 type FileContextPartsProvider struct {
 	eventBus         events.EventBus
 	storedFiles      map[string]string // map[filePath]content
@@ -19,6 +25,12 @@ type FileContextPartsProvider struct {
 	lruStrategy      CollectionBudgetStrategy[FileEntry]
 	softTrimStrategy BudgetStrategy
 	tokenBudget      int
+
+	// Files that changed on disk since they were read, and the stored path
+	// of each watched file by absolute path
+	stale   map[string]bool
+	watched map[string]string
+	watcher *FileWatcher // nil when files are not watched
 }
 
 func NewFileContextPartsProvider(eventBus events.EventBus) *FileContextPartsProvider {
@@ -27,6 +39,8 @@ func NewFileContextPartsProvider(eventBus events.EventBus) *FileContextPartsProv
 		storedFiles:  make(map[string]string),
 		orderedFiles: make([]string, 0),
 		fileIndexes:  make(map[string]int),
+		stale:        make(map[string]bool),
+		watched:      make(map[string]string),
 	}
 
 	if eventBus != nil {
@@ -56,6 +70,7 @@ func (p *FileContextPartsProvider) handleToolExecutedEvent(event interface{}) {
 
 		// Store content
 		p.storedFiles[filePath] = result
+		delete(p.stale, filePath)
 
 		// Update order
 		if idx, found := p.fileIndexes[filePath]; found {
@@ -76,6 +91,52 @@ func (p *FileContextPartsProvider) handleToolExecutedEvent(event interface{}) {
 	}
 }
 
+// WatchFiles marks the files read into context stale when they change on
+// disk, so the model is told to read them again instead of working from
+// an outdated copy.
+func (p *FileContextPartsProvider) WatchFiles() error {
+	watcher, err := NewFileWatcher(p.markStale)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.watcher = watcher
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *FileContextPartsProvider) markStale(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if filePath, ok := p.watched[path]; ok {
+		p.stale[filePath] = true
+	}
+}
+
+// watchStoredFiles watches the stored files, resolving relative paths
+// against the working directory. The caller holds mu for writing.
+func (p *FileContextPartsProvider) watchStoredFiles(ctx context.Context) {
+	if p.watcher == nil {
+		return
+	}
+	workingDir, _ := toolctx.WorkingDir(ctx)
+	p.watched = make(map[string]string, len(p.orderedFiles))
+	paths := make([]string, 0, len(p.orderedFiles))
+	for _, filePath := range p.orderedFiles {
+		path := filePath
+		if !filepath.IsAbs(path) {
+			if workingDir == "" {
+				continue
+			}
+			path = filepath.Join(workingDir, path)
+		}
+		path = filepath.Clean(path)
+		p.watched[path] = filePath
+		paths = append(paths, path)
+	}
+	p.watcher.Set(paths)
+}
+
 // GetStoredFiles returns the map of stored file paths to content (for testing)
 func (p *FileContextPartsProvider) GetStoredFiles() map[string]string {
 	p.mu.RLock()
@@ -127,8 +188,10 @@ func formatFileEntryForContext(f FileEntry) string {
 }
 
 func (p *FileContextPartsProvider) GetPart(ctx context.Context) (ContextPart, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.watchStoredFiles(ctx)
 
 	// Build file entries from ordered list
 	entries := make([]FileEntry, 0, len(p.orderedFiles))
@@ -137,6 +200,9 @@ func (p *FileContextPartsProvider) GetPart(ctx context.Context) (ContextPart, er
 		if !ok {
 			continue
 		}
+		if p.stale[filePath] {
+			content = staleFileNote
+		}
 		entries = append(entries, FileEntry{Path: filePath, Content: content})
 	}
 
@@ -207,5 +273,10 @@ func (p *FileContextPartsProvider) ClearPart() error {
 	p.storedFiles = make(map[string]string)
 	p.orderedFiles = make([]string, 0)
 	p.fileIndexes = make(map[string]int) // Clear file indexes as well
+	p.stale = make(map[string]bool)
+	p.watched = make(map[string]string)
+	if p.watcher != nil {
+		p.watcher.Set(nil)
+	}
 	return nil
 }
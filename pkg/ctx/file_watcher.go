@@ -0,0 +1,120 @@
+package ctx
+
+import (
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileWatcher reports when any of a set of files changes on disk. It
+// watches the files' directories rather than the files themselves, so
+// editors that save by writing a new file and renaming it over the old
+// one are still seen.
+type FileWatcher struct {
+	watcher  *fsnotify.Watcher
+	onChange func(path string)
+
+	mu    sync.Mutex
+	files map[string]bool // absolute paths being watched
+	dirs  map[string]int  // watched directories, by how many files they hold
+}
+
+// NewFileWatcher starts a watcher that calls onChange with the absolute
+// path of each watched file that is written, created, removed or renamed.
+// onChange runs on the watcher's goroutine.
+func NewFileWatcher(onChange func(path string)) (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &FileWatcher{
+		watcher:  watcher,
+		onChange: onChange,
+		files:    make(map[string]bool),
+		dirs:     make(map[string]int),
+	}
+	go w.run()
+	return w, nil
+}
+
+func (w *FileWatcher) run() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) &&
+				!event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			path := filepath.Clean(event.Name)
+			w.mu.Lock()
+			watched := w.files[path]
+			w.mu.Unlock()
+			if watched {
+				w.onChange(path)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Debug("File watcher error", "error", err)
+		}
+	}
+}
+
+// Set makes paths the files being watched, replacing the previous set.
+// Paths must be absolute. A directory that cannot be watched is skipped;
+// its files are then only refreshed when their providers check them.
+func (w *FileWatcher) Set(paths []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		next[filepath.Clean(path)] = true
+	}
+	for path := range w.files {
+		if !next[path] {
+			w.release(filepath.Dir(path))
+		}
+	}
+	for path := range next {
+		if w.files[path] {
+			continue
+		}
+		dir := filepath.Dir(path)
+		if w.dirs[dir] == 0 {
+			if err := w.watcher.Add(dir); err != nil {
+				slog.Debug("Cannot watch directory", "dir", dir, "error", err)
+				continue
+			}
+		}
+		w.dirs[dir]++
+	}
+	// Keep only the files whose directory is watched
+	w.files = make(map[string]bool, len(next))
+	for path := range next {
+		if w.dirs[filepath.Dir(path)] > 0 {
+			w.files[path] = true
+		}
+	}
+}
+
+// release drops one file from dir and stops watching dir when it holds
+// no more. The caller holds mu.
+func (w *FileWatcher) release(dir string) {
+	w.dirs[dir]--
+	if w.dirs[dir] <= 0 {
+		delete(w.dirs, dir)
+		_ = w.watcher.Remove(dir)
+	}
+}
+
+// Close stops the watcher
+func (w *FileWatcher) Close() error {
+	return w.watcher.Close()
+}
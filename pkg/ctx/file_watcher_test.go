@@ -0,0 +1,85 @@
+package ctx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcherReportsOnlyWatchedFiles(t *testing.T) {
+	dir := t.TempDir()
+	watchedPath := filepath.Join(dir, "watched.go")
+	otherPath := filepath.Join(dir, "other.go")
+	require.NoError(t, os.WriteFile(watchedPath, []byte("v1"), 0644))
+
+	changed := make(chan string, 10)
+	watcher, err := NewFileWatcher(func(path string) { changed <- path })
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	watcher.Set([]string{watchedPath})
+	require.NoError(t, os.WriteFile(otherPath, []byte("ignored"), 0644))
+	require.NoError(t, os.WriteFile(watchedPath, []byte("v2"), 0644))
+
+	select {
+	case path := <-changed:
+		assert.Equal(t, watchedPath, path)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported for the watched file")
+	}
+
+	// After unwatching, edits are no longer reported
+	watcher.Set(nil)
+	for len(changed) > 0 {
+		<-changed
+	}
+	require.NoError(t, os.WriteFile(watchedPath, []byte("v3"), 0644))
+	select {
+	case path := <-changed:
+		t.Fatalf("unexpected change reported for %s", path)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestFileContextPartsProvider_MarksChangedFilesStale(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+
+	mockBus := new(MockEventBus)
+	mockBus.On("Subscribe", "tool.executed", mock.Anything).Return().Once()
+	provider := NewFileContextPartsProvider(mockBus)
+	require.NoError(t, provider.WatchFiles())
+	defer provider.watcher.Close()
+
+	read := events.ToolExecutedEvent{
+		ToolName:   "readFile",
+		Parameters: map[string]any{"file_path": "main.go"},
+		Result:     map[string]any{"results": "package main"},
+	}
+	provider.handleToolExecutedEvent(read)
+	ctx := toolctx.WithWorkingDir(context.Background(), dir)
+	part, err := provider.GetPart(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "File: main.go\n```\npackage main\n```", part.Content)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}"), 0644))
+	assert.Eventually(t, func() bool {
+		part, _ := provider.GetPart(ctx)
+		return part.Content == "File: main.go\n```\n"+staleFileNote+"\n```"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Reading the file again brings it up to date
+	read.Result = map[string]any{"results": "package main\n\nfunc main() {}"}
+	provider.handleToolExecutedEvent(read)
+	part, err = provider.GetPart(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, part.Content, "func main() {}")
+}
@@ -0,0 +1,157 @@
+package ctx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// MaxPinnedFileBytes is the largest file that is sent when pinned. Larger
+// files stay pinned but are replaced by a note until they shrink.
+const MaxPinnedFileBytes = 256 * 1024
+
+// pinnedFile is a pinned file as last read from disk
+type pinnedFile struct {
+	modTime time.Time
+	size    int64
+	content string
+	err     error
+	stale   bool // Changed on disk since it was read
+}
+
+// PinnedFilesContextPartProvider sends the files pinned into the session
+// with every turn. Pins come from the context (toolctx.PinnedFiles), so
+// the session owns them; the provider only caches what it read and reads
+// a file again when it changes on disk.
+type PinnedFilesContextPartProvider struct {
+	mu      sync.Mutex
+	files   map[string]pinnedFile // by absolute path
+	watcher *FileWatcher          // nil when files are only checked on each turn
+}
+
+// NewPinnedFilesContextPartProvider creates a provider for pinned files
+func NewPinnedFilesContextPartProvider() *PinnedFilesContextPartProvider {
+	return &PinnedFilesContextPartProvider{files: make(map[string]pinnedFile)}
+}
+
+// WatchFiles marks pinned files stale as soon as they change on disk,
+// rather than relying on their size and modification time alone, which
+// can miss quick edits.
+func (p *PinnedFilesContextPartProvider) WatchFiles() error {
+	watcher, err := NewFileWatcher(p.markStale)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.watcher = watcher
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *PinnedFilesContextPartProvider) markStale(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if file, ok := p.files[path]; ok {
+		file.stale = true
+		p.files[path] = file
+	}
+}
+
+// SetTokenBudget is a no-op: pinned files are never trimmed.
+func (p *PinnedFilesContextPartProvider) SetTokenBudget(tokens int) {}
+
+// GetPart reads the pinned files, reusing the cached content of files
+// that have not changed.
+func (p *PinnedFilesContextPartProvider) GetPart(ctx context.Context) (ContextPart, error) {
+	paths, _ := toolctx.PinnedFiles(ctx)
+	workingDir, _ := toolctx.WorkingDir(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var parts []string
+	current := make(map[string]pinnedFile, len(paths))
+	for _, path := range paths {
+		file, changed := p.read(path)
+		current[path] = file
+
+		name := displayPinnedPath(workingDir, path)
+		switch {
+		case file.err != nil:
+			parts = append(parts, fmt.Sprintf("File: %s\n(pinned, but it could not be read: %v)", name, file.err))
+		case changed:
+			parts = append(parts, fmt.Sprintf("File: %s (changed on disk since the last turn)\n```\n%s\n```", name, file.content))
+		default:
+			parts = append(parts, fmt.Sprintf("File: %s\n```\n%s\n```", name, file.content))
+		}
+	}
+	// Forget unpinned files so pinning one again starts fresh
+	p.files = current
+	if p.watcher != nil {
+		p.watcher.Set(paths)
+	}
+
+	return ContextPart{
+		Key:     "pinned_files",
+		Content: strings.Join(parts, "\n\n"),
+	}, nil
+}
+
+// read returns the file at path, reading it only when it is new, stale
+// or has changed size or modification time since it was last read.
+// changed reports a file that was read before and differs now. The caller
+// holds mu.
+func (p *PinnedFilesContextPartProvider) read(path string) (file pinnedFile, changed bool) {
+	cached, seen := p.files[path]
+	info, err := os.Stat(path)
+	if err != nil {
+		return pinnedFile{err: err}, false
+	}
+	if seen && cached.err == nil && !cached.stale && info.ModTime().Equal(cached.modTime) && info.Size() == cached.size {
+		return cached, false
+	}
+
+	file = pinnedFile{modTime: info.ModTime(), size: info.Size()}
+	switch {
+	case info.IsDir():
+		file.err = fmt.Errorf("it is a directory")
+	case info.Size() > MaxPinnedFileBytes:
+		file.err = fmt.Errorf("%d KB is over the %d KB limit", info.Size()/1024, MaxPinnedFileBytes/1024)
+	default:
+		data, err := os.ReadFile(path)
+		if err == nil && bytes.IndexByte(data, 0) >= 0 {
+			err = fmt.Errorf("it is not a text file")
+		}
+		file.content, file.err = string(data), err
+	}
+	return file, seen && cached.err == nil && file.err == nil && file.content != cached.content
+}
+
+// ClearPart forgets the cached contents. The pins themselves belong to
+// the session.
+func (p *PinnedFilesContextPartProvider) ClearPart() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.files = make(map[string]pinnedFile)
+	return nil
+}
+
+// displayPinnedPath shows path relative to the working directory when it
+// is inside it
+func displayPinnedPath(workingDir, path string) string {
+	if workingDir == "" {
+		return path
+	}
+	rel, err := filepath.Rel(workingDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,49 @@
+package ctx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedFilesContextPartProvider_GetPart(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.md")
+	require.NoError(t, os.WriteFile(path, []byte("first draft"), 0644))
+
+	provider := NewPinnedFilesContextPartProvider()
+	ctx := toolctx.WithWorkingDir(context.Background(), dir)
+	ctx = toolctx.WithPinnedFiles(ctx, []string{path, filepath.Join(dir, "missing.go")})
+
+	part, err := provider.GetPart(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "pinned_files", part.Key)
+	assert.Contains(t, part.Content, "File: notes.md\n```\nfirst draft\n```")
+	assert.Contains(t, part.Content, "File: missing.go\n(pinned, but it could not be read")
+
+	// An edit on disk is picked up and flagged once
+	require.NoError(t, os.WriteFile(path, []byte("second draft, longer"), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+	part, err = provider.GetPart(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, part.Content, "File: notes.md (changed on disk since the last turn)\n```\nsecond draft, longer\n```")
+
+	part, err = provider.GetPart(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, part.Content, "File: notes.md\n```\nsecond draft, longer\n```")
+}
+
+func TestPinnedFilesContextPartProvider_NoPins(t *testing.T) {
+	provider := NewPinnedFilesContextPartProvider()
+
+	part, err := provider.GetPart(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pinned_files", part.Key)
+	assert.Empty(t, part.Content)
+}
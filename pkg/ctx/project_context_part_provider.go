@@ -42,22 +42,31 @@ func (m *projectContextPartsProvider) SetTokenBudget(int) {}
 // GetPart returns the concatenated project context
 func (m *projectContextPartsProvider) GetPart(ctx context.Context) (ContextPart, error) {
 	var contents []string
-	var cwdContextPath string
+	included := make(map[string]bool)
 
-	// Extract cwd from context and get its context file
+	// Extract cwd from context and get its context file, then those of the
+	// directories a monorepo session is scoped to
 	cwd, ok := toolctx.WorkingDir(ctx)
 	if ok {
-		content, contextPath := m.getCachedCwdContext(cwd)
-		if content != "" {
-			contents = append(contents, content)
-			cwdContextPath = contextPath
+		dirs := []string{cwd}
+		if scope, ok := toolctx.Scope(ctx); ok {
+			for _, dir := range scope {
+				dirs = append(dirs, filepath.Join(cwd, filepath.FromSlash(dir)))
+			}
+		}
+		for _, dir := range dirs {
+			content, contextPath := m.getCachedCwdContext(dir)
+			if content != "" {
+				contents = append(contents, content)
+				included[contextPath] = true
+			}
 		}
 	}
 
-	// Add all collected context files from tool executions (excluding CWD context)
+	// Add all collected context files from tool executions (excluding the above)
 	m.mu.RLock()
 	for path, content := range m.contextFiles {
-		if path != cwdContextPath { // Avoid duplicating CWD context
+		if !included[path] { // Avoid duplicating CWD context
 			contents = append(contents, content)
 		}
 	}
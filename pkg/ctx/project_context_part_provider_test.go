@@ -596,3 +596,18 @@ func TestProjectCtxManager_ReadsAgentsMdFromFileDirectory_OnReadFileExecution(t
 	assert.NoError(t, err)
 	assert.Contains(t, part.Content, agentsMdContent)
 }
+
+func TestProjectCtxManager_IncludesScopeContextFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "GENIE.md"), []byte("monorepo rules"), 0644))
+	payments := filepath.Join(root, "services", "payments")
+	require.NoError(t, os.MkdirAll(payments, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(payments, "AGENTS.md"), []byte("payments team notes"), 0644))
+
+	manager := NewProjectCtxManager(nil)
+	ctx := toolctx.WithScope(toolctx.WithWorkingDir(context.Background(), root), []string{"services/payments"})
+
+	part, err := manager.GetPart(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "monorepo rules\n\npayments team notes", part.Content)
+}
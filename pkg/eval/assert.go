@@ -0,0 +1,94 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// AssertionResult is the outcome of one assertion on a case's output.
+type AssertionResult struct {
+	Assertion string `json:"assertion"`
+	Passed    bool   `json:"passed"`
+	// Reason explains a failure, or the grader's verdict
+	Reason string `json:"reason,omitempty"`
+}
+
+// grade judges output against an llm assertion's criterion
+type grader func(ctx context.Context, criterion, input, output string) (passed bool, reason string, err error)
+
+// check runs one assertion on output
+func (a Assertion) check(ctx context.Context, input, output string, grade grader) AssertionResult {
+	result := AssertionResult{Assertion: a.String()}
+	switch {
+	case a.Contains != "":
+		result.Passed = strings.Contains(output, a.Contains)
+		if !result.Passed {
+			result.Reason = "not found in the output"
+		}
+	case a.NotContains != "":
+		result.Passed = !strings.Contains(output, a.NotContains)
+		if !result.Passed {
+			result.Reason = "found in the output"
+		}
+	case a.regex != nil:
+		result.Passed = a.regex.MatchString(output)
+		if !result.Passed {
+			result.Reason = "no match in the output"
+		}
+	case a.schema != nil:
+		if err := a.schema.ValidateJSON(ai.ExtractJSON(output)); err != nil {
+			result.Reason = err.Error()
+		} else {
+			result.Passed = true
+		}
+	case a.LLM != "":
+		passed, reason, err := grade(ctx, a.LLM, input, output)
+		if err != nil {
+			result.Reason = fmt.Sprintf("grading failed: %v", err)
+		} else {
+			result.Passed, result.Reason = passed, reason
+		}
+	}
+	return result
+}
+
+// graderPrompt is the persona that answers llm assertions. It has no
+// tools: it only reads the answer it is given.
+var graderPrompt = []byte(`name: Eval Grader
+text: "{{.message}}"
+instruction: |
+  You grade answers for an evaluation suite. Judge strictly whether the
+  answer meets the criterion, using only the answer itself; do not reward
+  an answer for what it could have said. Reply with JSON only.
+`)
+
+// graderSchema is the verdict the grader must reply with
+var graderSchema = &ai.Schema{
+	Type:     ai.TypeObject,
+	Required: []string{"pass", "reason"},
+	Properties: map[string]*ai.Schema{
+		"pass":   {Type: ai.TypeBoolean, Description: "Whether the answer meets the criterion"},
+		"reason": {Type: ai.TypeString, Description: "One sentence explaining the verdict"},
+	},
+}
+
+func graderMessage(criterion, input, output string) string {
+	return fmt.Sprintf("Criterion:\n%s\n\nInput the answer responds to:\n%s\n\nAnswer to grade:\n%s\n\nDoes the answer meet the criterion? Reply with {\"pass\": true or false, \"reason\": \"...\"}.",
+		criterion, input, output)
+}
+
+// parseVerdict reads the grader's JSON reply
+func parseVerdict(answer string) (bool, string, error) {
+	var verdict struct {
+		Pass   bool   `json:"pass"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(ai.ExtractJSON(answer)), &verdict); err != nil {
+		return false, "", fmt.Errorf("unreadable verdict: %w", err)
+	}
+	return verdict.Pass, verdict.Reason, nil
+}
@@ -0,0 +1,91 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertionCheck(t *testing.T) {
+	suite, err := ParseSuite([]byte(`
+cases:
+  - input: hi
+    assert:
+      - contains: Close
+      - not_contains: panic
+      - regex: "^Review"
+      - json_schema:
+          type: object
+          required: [ok]
+          properties:
+            ok: {type: boolean}
+`), t.TempDir())
+	require.NoError(t, err)
+	assertions := suite.Cases[0].Assert
+	noGrader := func(context.Context, string, string, string) (bool, string, error) {
+		t.Fatal("only llm assertions are graded")
+		return false, "", nil
+	}
+
+	tests := []struct {
+		name      string
+		assertion Assertion
+		output    string
+		passed    bool
+		reason    string
+	}{
+		{"contains", assertions[0], "Review: call Close", true, ""},
+		{"contains missing", assertions[0], "Looks good", false, "not found in the output"},
+		{"not contains", assertions[1], "Looks good", true, ""},
+		{"not contains found", assertions[1], "this will panic", false, "found in the output"},
+		{"regex", assertions[2], "Review: ok", true, ""},
+		{"regex no match", assertions[2], "ok", false, "no match in the output"},
+		{"json schema fenced", assertions[3], "```json\n{\"ok\": true}\n```", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.assertion.check(context.Background(), "hi", tt.output, noGrader)
+			assert.Equal(t, tt.passed, result.Passed)
+			assert.Equal(t, tt.reason, result.Reason)
+		})
+	}
+
+	t.Run("json schema mismatch", func(t *testing.T) {
+		result := assertions[3].check(context.Background(), "hi", `{"ok": "yes"}`, noGrader)
+		assert.False(t, result.Passed)
+		assert.NotEmpty(t, result.Reason)
+	})
+}
+
+func TestLLMAssertionUsesTheGrader(t *testing.T) {
+	assertion := Assertion{LLM: "is polite"}
+	var got []string
+	grade := func(_ context.Context, criterion, input, output string) (bool, string, error) {
+		got = []string{criterion, input, output}
+		return false, "it is rude", nil
+	}
+
+	result := assertion.check(context.Background(), "hi", "go away", grade)
+	assert.Equal(t, []string{"is polite", "hi", "go away"}, got)
+	assert.Equal(t, AssertionResult{Assertion: "graded: is polite", Reason: "it is rude"}, result)
+
+	failing := func(context.Context, string, string, string) (bool, string, error) {
+		return true, "", errors.New("model unavailable")
+	}
+	result = assertion.check(context.Background(), "hi", "go away", failing)
+	assert.False(t, result.Passed)
+	assert.Equal(t, "grading failed: model unavailable", result.Reason)
+}
+
+func TestParseVerdict(t *testing.T) {
+	passed, reason, err := parseVerdict("```json\n{\"pass\": true, \"reason\": \"names the bug\"}\n```")
+	require.NoError(t, err)
+	assert.True(t, passed)
+	assert.Equal(t, "names the bug", reason)
+
+	_, _, err = parseVerdict("Yes, it passes.")
+	assert.ErrorContains(t, err, "unreadable verdict")
+}
@@ -0,0 +1,123 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+)
+
+// DefaultModel names the persona's own model in results, when a suite
+// sets no models.
+const DefaultModel = "default"
+
+// CaseResult is the outcome of one case on one model.
+type CaseResult struct {
+	Case   string `json:"case"`
+	Model  string `json:"model"`
+	Passed bool   `json:"passed"`
+	Output string `json:"output"`
+	// Error is set when the case could not run; its assertions are not
+	// checked
+	Error      string            `json:"error,omitempty"`
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+	Duration   time.Duration     `json:"duration_ns"`
+	// Usage counts the case's tokens and cost, grading included
+	Usage pricing.Usage `json:"usage"`
+}
+
+// Report is the outcome of a suite run.
+type Report struct {
+	Suite   string        `json:"suite"`
+	Results []CaseResult  `json:"results"`
+	Usage   pricing.Usage `json:"usage"`
+}
+
+// Failed counts the results that did not pass.
+func (r *Report) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Models lists the models of the results, in the order they ran.
+func (r *Report) Models() []string {
+	var models []string
+	seen := make(map[string]bool)
+	for _, result := range r.Results {
+		if !seen[result.Model] {
+			seen[result.Model] = true
+			models = append(models, result.Model)
+		}
+	}
+	return models
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText writes the report for people: every case by model, the
+// failed assertions, and the totals of each model and of the run.
+func (r *Report) WriteText(w io.Writer) {
+	if r.Suite != "" {
+		fmt.Fprintf(w, "Suite: %s\n", r.Suite)
+	}
+	for _, model := range r.Models() {
+		fmt.Fprintf(w, "\n%s\n", model)
+		var passed, total int
+		var usage pricing.Usage
+		for _, result := range r.Results {
+			if result.Model != model {
+				continue
+			}
+			total++
+			mark := "FAIL"
+			if result.Passed {
+				passed++
+				mark = "PASS"
+			}
+			addUsage(&usage, result.Usage)
+			fmt.Fprintf(w, "  %s  %s  (%s, %s)\n", mark, result.Case, result.Duration.Round(100*time.Millisecond), formatUsage(result.Usage))
+			if result.Error != "" {
+				fmt.Fprintf(w, "        error: %s\n", result.Error)
+			}
+			for _, assertion := range result.Assertions {
+				if !assertion.Passed {
+					fmt.Fprintf(w, "        %s: %s\n", assertion.Assertion, assertion.Reason)
+				}
+			}
+		}
+		fmt.Fprintf(w, "  %d/%d passed, %s\n", passed, total, formatUsage(usage))
+	}
+	fmt.Fprintf(w, "\n%d/%d passed, %s\n", len(r.Results)-r.Failed(), len(r.Results), formatUsage(r.Usage))
+	if len(r.Usage.UnpricedModels) > 0 {
+		fmt.Fprintf(w, "No price known for %s; their tokens are not in the cost.\n", strings.Join(r.Usage.UnpricedModels, ", "))
+	}
+}
+
+func totalTokens(u pricing.Usage) int64 {
+	return u.InputTokens + u.OutputTokens + u.CachedTokens + u.CacheWriteTokens
+}
+
+func formatUsage(u pricing.Usage) string {
+	return fmt.Sprintf("%d tokens, %s", totalTokens(u), pricing.FormatCost(u.Cost))
+}
+
+func addUsage(total *pricing.Usage, u pricing.Usage) {
+	total.InputTokens += u.InputTokens
+	total.OutputTokens += u.OutputTokens
+	total.CachedTokens += u.CachedTokens
+	total.CacheWriteTokens += u.CacheWriteTokens
+	total.Cost += u.Cost
+}
@@ -0,0 +1,49 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportWriteText(t *testing.T) {
+	report := &Report{
+		Suite: "reviewer",
+		Results: []CaseResult{
+			{Case: "spots the error", Model: "gpt-4o", Passed: true, Duration: time.Second, Usage: pricing.Usage{InputTokens: 100, OutputTokens: 20, Cost: 0.01}},
+			{Case: "stays quiet", Model: "gpt-4o", Duration: 2 * time.Second, Usage: pricing.Usage{InputTokens: 80, OutputTokens: 10, Cost: 0.005},
+				Assertions: []AssertionResult{{Assertion: `contains "error"`, Reason: "not found in the output"}}},
+			{Case: "spots the error", Model: "local", Error: "model unavailable"},
+		},
+		Usage: pricing.Usage{InputTokens: 180, OutputTokens: 30, Cost: 0.015, UnpricedModels: []string{"local"}},
+	}
+
+	var out bytes.Buffer
+	report.WriteText(&out)
+	text := out.String()
+
+	assert.Contains(t, text, "Suite: reviewer\n")
+	assert.Contains(t, text, "  PASS  spots the error  (1s, 120 tokens, ")
+	assert.Contains(t, text, "  FAIL  stays quiet  (2s, 90 tokens, ")
+	assert.Contains(t, text, `        contains "error": not found in the output`)
+	assert.Contains(t, text, "        error: model unavailable")
+	assert.Contains(t, text, "  1/2 passed, 210 tokens, ")
+	assert.Contains(t, text, "\n1/3 passed, 210 tokens, ")
+	assert.Contains(t, text, "No price known for local")
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	report := &Report{Suite: "reviewer", Results: []CaseResult{{Case: "a", Model: DefaultModel, Passed: true, Output: "ok"}}}
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJSON(&out))
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, report.Results, decoded.Results)
+}
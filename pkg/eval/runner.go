@@ -0,0 +1,263 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/llm/models"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+	"github.com/kcaldas/genie/pkg/persona"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// Option configures a Runner.
+type Option func(*Runner)
+
+// WithPricing sets the table that prices the tokens each case uses.
+func WithPricing(table *pricing.Table) Option {
+	return func(r *Runner) {
+		r.pricing = table
+	}
+}
+
+// WithModels runs every case on these models, as model or provider/model,
+// in place of the suite's models.
+func WithModels(models ...string) Option {
+	return func(r *Runner) {
+		r.models = models
+	}
+}
+
+// WithProgress calls fn as each case finishes.
+func WithProgress(fn func(CaseResult)) Option {
+	return func(r *Runner) {
+		r.onResult = fn
+	}
+}
+
+// Runner runs suites against a started Genie. Cases run one at a time so
+// the tokens each one uses can be told apart, and none of their turns are
+// kept in the conversation.
+type Runner struct {
+	genie    genie.Genie
+	session  genie.Session
+	pricing  *pricing.Table
+	models   []string
+	onResult func(CaseResult)
+}
+
+// NewRunner creates a runner over g and its session.
+func NewRunner(g genie.Genie, session genie.Session, opts ...Option) *Runner {
+	runner := &Runner{genie: g, session: session}
+	for _, opt := range opts {
+		opt(runner)
+	}
+	if runner.pricing == nil {
+		runner.pricing = pricing.NewTable(nil)
+	}
+	return runner
+}
+
+// suiteRun is the state of one Run
+type suiteRun struct {
+	suite   *Suite
+	tracker *pricing.Tracker
+	// prompts caches prompt.yaml contents by persona ID or file
+	prompts map[string][]byte
+	// model is the model under test
+	model string
+
+	session genie.Session
+	// The session's own model, used when a model is left empty
+	baseProvider, baseModel string
+}
+
+// Run runs every case of suite on each of its models and reports the
+// results. The session's model is restored afterwards. The error is only
+// set when the run stopped early, when ctx is canceled; failed cases are
+// in the report.
+func (r *Runner) Run(ctx context.Context, suite *Suite) (*Report, error) {
+	modelList := suite.Models
+	if len(r.models) > 0 {
+		modelList = r.models
+	}
+	if len(modelList) == 0 {
+		modelList = []string{""}
+	}
+
+	run := &suiteRun{suite: suite, tracker: pricing.NewTracker(r.pricing), prompts: make(map[string][]byte), session: r.session}
+	run.baseProvider, run.baseModel = r.session.GetModel()
+	defer r.session.SetModel(run.baseProvider, run.baseModel)
+	defer events.SubscribeTo(r.genie.GetEventBus(), func(e events.TokenCountEvent) {
+		run.tracker.Record(e)
+	})()
+
+	report := &Report{Suite: suite.Name}
+	for _, model := range modelList {
+		run.model = model
+		for _, c := range suite.Cases {
+			if err := ctx.Err(); err != nil {
+				report.Usage = run.tracker.Session()
+				return report, err
+			}
+			result := r.runCase(ctx, run, c)
+			report.Results = append(report.Results, result)
+			if r.onResult != nil {
+				r.onResult(result)
+			}
+		}
+	}
+	report.Usage = run.tracker.Session()
+	return report, nil
+}
+
+// runCase sends the case through the suite's turns and checks the output
+func (r *Runner) runCase(ctx context.Context, run *suiteRun, c Case) (result CaseResult) {
+	result = CaseResult{Case: c.Name, Model: run.model}
+	if result.Model == "" {
+		result.Model = DefaultModel
+	}
+	run.useModel(run.model)
+	run.tracker.StartTurn()
+	started := time.Now()
+	defer func() {
+		result.Duration = time.Since(started)
+		result.Usage = run.tracker.Turn()
+	}()
+
+	output, err := r.runTurns(ctx, run, c)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = output
+
+	grade := func(ctx context.Context, criterion, input, output string) (bool, string, error) {
+		return r.grade(ctx, run, criterion, input, output)
+	}
+	result.Passed = true
+	for _, assertion := range c.Assert {
+		checked := assertion.check(ctx, c.Input, output, grade)
+		result.Assertions = append(result.Assertions, checked)
+		result.Passed = result.Passed && checked.Passed
+	}
+	return result
+}
+
+// runTurns sends the case input, or runs it through the chain, and
+// returns the last output
+func (r *Runner) runTurns(ctx context.Context, run *suiteRun, c Case) (string, error) {
+	steps := run.suite.Chain
+	if len(steps) == 0 {
+		steps = []Step{{}}
+	}
+
+	previous := ""
+	for i, step := range steps {
+		message := c.Input
+		if step.Message != "" {
+			var err error
+			message, err = ai.RenderTemplateString(step.Message, map[string]string{"input": c.Input, "previous": previous})
+			if err != nil {
+				return "", fmt.Errorf("chain step %d: %w", i+1, err)
+			}
+		}
+
+		personaID := step.Persona
+		if personaID == "" {
+			personaID = c.Persona
+		}
+		opts, err := r.promptOptions(ctx, run, personaID)
+		if err != nil {
+			return "", err
+		}
+
+		previous, err = r.chat(ctx, message, opts...)
+		if err != nil {
+			if len(steps) > 1 {
+				return "", fmt.Errorf("chain step %d: %w", i+1, err)
+			}
+			return "", err
+		}
+	}
+	return previous, nil
+}
+
+// promptOptions picks the prompt of a turn: the given persona, else the
+// suite's persona or prompt file, else the session's persona
+func (r *Runner) promptOptions(ctx context.Context, run *suiteRun, personaID string) ([]genie.ChatOption, error) {
+	if personaID == "" {
+		personaID = run.suite.Persona
+	}
+	key, load := personaID, func() ([]byte, error) {
+		data, _, _, err := persona.FindPromptYAML(r.promptContext(ctx), personaID)
+		return data, err
+	}
+	if personaID == "" {
+		if run.suite.PromptFile == "" {
+			return nil, nil
+		}
+		path := run.suite.PromptFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(run.suite.dir, path)
+		}
+		key, load = path, func() ([]byte, error) {
+			return os.ReadFile(path)
+		}
+	}
+
+	data, ok := run.prompts[key]
+	if !ok {
+		var err error
+		if data, err = load(); err != nil {
+			return nil, err
+		}
+		run.prompts[key] = data
+	}
+	return []genie.ChatOption{genie.WithPromptYAML(data)}, nil
+}
+
+// promptContext locates personas the way the session does
+func (r *Runner) promptContext(ctx context.Context) context.Context {
+	ctx = toolctx.WithGenieHome(ctx, r.session.GetGenieHomeDirectory())
+	ctx = toolctx.WithWorkingDir(ctx, r.session.GetWorkingDirectory())
+	if !r.session.IsWorkspaceTrusted() {
+		ctx = toolctx.WithWorkspaceTrusted(ctx, false)
+	}
+	return ctx
+}
+
+// grade asks the grading model whether output meets criterion
+func (r *Runner) grade(ctx context.Context, run *suiteRun, criterion, input, output string) (bool, string, error) {
+	if run.suite.GraderModel != "" {
+		run.useModel(run.suite.GraderModel)
+		defer run.useModel(run.model)
+	}
+	answer, err := r.chat(ctx, graderMessage(criterion, input, output), genie.WithPromptYAML(graderPrompt), genie.WithResponseSchema(graderSchema))
+	if err != nil {
+		return false, "", err
+	}
+	return parseVerdict(answer)
+}
+
+// useModel points the session at model; empty is the model the session
+// had before the run
+func (run *suiteRun) useModel(model string) {
+	if model == "" {
+		run.session.SetModel(run.baseProvider, run.baseModel)
+		return
+	}
+	run.session.SetModel(models.Parse(model))
+}
+
+// chat sends one ephemeral turn and waits for its response
+func (r *Runner) chat(ctx context.Context, message string, opts ...genie.ChatOption) (string, error) {
+	results, _ := genie.ChatBatch(ctx, r.genie, []genie.BatchRequest{{Message: message, Options: opts}}, genie.WithBatchConcurrency(1))
+	return results[0].Response, results[0].Err
+}
@@ -0,0 +1,159 @@
+package eval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerRunsEveryCaseOnEveryModel(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	session.SetModel("genai", "gemini-2.5-pro")
+	fixture.ExpectSimpleMessage("Review: f.Close()", "The error from Close is ignored")
+	fixture.ExpectSimpleMessage("Review: x := 1", "Looks good")
+
+	suite, err := ParseSuite([]byte(`
+name: reviewer
+persona: reviewer
+models: [openai/gpt-4o, anthropic/claude-sonnet-4-5]
+cases:
+  - name: spots the error
+    input: "Review: f.Close()"
+    assert:
+      - contains: error
+  - name: stays quiet
+    input: "Review: x := 1"
+    assert:
+      - contains: error
+`), t.TempDir())
+	require.NoError(t, err)
+
+	var progress []string
+	report, err := NewRunner(fixture.Genie, session, WithProgress(func(result CaseResult) {
+		progress = append(progress, result.Model+" "+result.Case)
+	})).Run(context.Background(), suite)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"openai/gpt-4o spots the error",
+		"openai/gpt-4o stays quiet",
+		"anthropic/claude-sonnet-4-5 spots the error",
+		"anthropic/claude-sonnet-4-5 stays quiet",
+	}, progress)
+	assert.Equal(t, []string{"openai/gpt-4o", "anthropic/claude-sonnet-4-5"}, report.Models())
+	assert.Equal(t, 2, report.Failed())
+	assert.True(t, report.Results[0].Passed)
+	assert.Equal(t, "The error from Close is ignored", report.Results[0].Output)
+	assert.False(t, report.Results[1].Passed)
+	assert.Equal(t, "not found in the output", report.Results[1].Assertions[0].Reason)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 4)
+	assert.Equal(t, "Reviewer", prompts[0].Name)
+	assert.Equal(t, "openai", prompts[0].LLMProvider)
+	assert.Equal(t, "gpt-4o", prompts[0].ModelName)
+	assert.Equal(t, "anthropic", prompts[3].LLMProvider)
+	assert.Equal(t, "claude-sonnet-4-5", prompts[3].ModelName)
+
+	// The session keeps its model and none of the turns
+	provider, model := session.GetModel()
+	assert.Equal(t, "genai", provider)
+	assert.Equal(t, "gemini-2.5-pro", model)
+	history, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestRunnerRunsChains(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("Plan: add a cache", "1. Add an LRU")
+	fixture.ExpectSimpleMessage("Review this plan:\n1. Add an LRU", "Approved")
+
+	suite, err := ParseSuite([]byte(`
+chain:
+  - name: plan
+    persona: product_owner
+    message: "Plan: {{.input}}"
+  - name: review
+    persona: reviewer
+    message: "Review this plan:\n{{.previous}}"
+cases:
+  - input: add a cache
+    assert:
+      - contains: Approved
+`), t.TempDir())
+	require.NoError(t, err)
+
+	report, err := NewRunner(fixture.Genie, session).Run(context.Background(), suite)
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.True(t, report.Results[0].Passed, report.Results[0].Error)
+	assert.Equal(t, DefaultModel, report.Results[0].Model)
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 2)
+	assert.Equal(t, "Martin", prompts[0].Name)
+	assert.Equal(t, "Reviewer", prompts[1].Name)
+}
+
+func TestRunnerGradesWithTheGraderModel(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("Say hi", "Go away")
+	fixture.ExpectSimpleMessage(graderMessage("is polite", "Say hi", "Go away"), `{"pass": false, "reason": "The answer is rude."}`)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prompt.yaml"), []byte("name: Draft\ntext: \"{{.message}}\"\ninstruction: Be polite.\n"), 0o644))
+	suite, err := ParseSuite([]byte(`
+prompt_file: prompt.yaml
+models: [openai/gpt-4o]
+grader_model: anthropic/claude-sonnet-4-5
+cases:
+  - input: Say hi
+    assert:
+      - llm: is polite
+`), dir)
+	require.NoError(t, err)
+
+	report, err := NewRunner(fixture.Genie, session).Run(context.Background(), suite)
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.False(t, report.Results[0].Passed)
+	assert.Equal(t, AssertionResult{Assertion: "graded: is polite", Reason: "The answer is rude."}, report.Results[0].Assertions[0])
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 2)
+	assert.Equal(t, "Draft", prompts[0].Name)
+	assert.Equal(t, "gpt-4o", prompts[0].ModelName)
+	assert.Equal(t, "Eval Grader", prompts[1].Name)
+	assert.Equal(t, "claude-sonnet-4-5", prompts[1].ModelName)
+}
+
+func TestRunnerReportsCasesThatCannotRun(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+
+	suite, err := ParseSuite([]byte(`
+persona: no-such-persona
+cases:
+  - input: hi
+    assert:
+      - contains: hello
+`), t.TempDir())
+	require.NoError(t, err)
+
+	report, err := NewRunner(fixture.Genie, session).Run(context.Background(), suite)
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.False(t, report.Results[0].Passed)
+	assert.Contains(t, report.Results[0].Error, "no-such-persona")
+	assert.Empty(t, report.Results[0].Assertions)
+}
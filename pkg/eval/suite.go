@@ -0,0 +1,215 @@
+// Package eval runs evaluation suites against personas and chains of
+// personas: each case sends an input, checks the output with assertions
+// (substrings, regular expressions, JSON schemas or a grading model) and
+// the report counts the passes, tokens and cost of every model tried.
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"gopkg.in/yaml.v3"
+)
+
+// Suite is an evaluation suite, as written in a suite YAML file.
+//
+//	name: reviewer smoke test
+//	persona: reviewer
+//	models: [gpt-4o, anthropic/claude-sonnet-4-5]
+//	cases:
+//	  - name: spots the unchecked error
+//	    input: "Review: f.Close()"
+//	    assert:
+//	      - contains: Close
+//	      - llm: points out that the error from Close is ignored
+type Suite struct {
+	Name string `yaml:"name"`
+	// Persona the cases run against; the session's persona when empty
+	Persona string `yaml:"persona"`
+	// PromptFile is a prompt.yaml to run against instead of a persona,
+	// relative to the suite file
+	PromptFile string `yaml:"prompt_file"`
+	// Chain replaces the single turn of each case with these steps
+	Chain []Step `yaml:"chain"`
+	// Models to run every case on, as model or provider/model; the
+	// persona's model when empty
+	Models []string `yaml:"models"`
+	// GraderModel answers llm assertions; the model under test when empty
+	GraderModel string `yaml:"grader_model"`
+	Cases       []Case `yaml:"cases"`
+
+	// dir resolves the suite's relative paths
+	dir string
+}
+
+// Step is one turn of a chain. Its message is a template that can use
+// {{.input}}, the case input, and {{.previous}}, the previous step's
+// output.
+type Step struct {
+	Name string `yaml:"name"`
+	// Persona for this step; the suite's when empty
+	Persona string `yaml:"persona"`
+	Message string `yaml:"message"`
+}
+
+// Case is one test of a suite.
+type Case struct {
+	Name  string `yaml:"name"`
+	Input string `yaml:"input"`
+	// Persona overrides the suite's persona for this case
+	Persona string      `yaml:"persona"`
+	Assert  []Assertion `yaml:"assert"`
+}
+
+// Assertion checks a case's output. Exactly one field is set.
+type Assertion struct {
+	Contains    string `yaml:"contains"`
+	NotContains string `yaml:"not_contains"`
+	Regex       string `yaml:"regex"`
+	// JSONSchema is a schema file relative to the suite, or the schema
+	// written inline; the output must be JSON that matches it
+	JSONSchema any `yaml:"json_schema"`
+	// LLM is a criterion the grading model judges the output against
+	LLM string `yaml:"llm"`
+
+	regex  *regexp.Regexp
+	schema *ai.Schema
+}
+
+// LoadSuite reads and validates a suite file.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite: %w", err)
+	}
+	suite, err := ParseSuite(data, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid suite %s: %w", path, err)
+	}
+	return suite, nil
+}
+
+// ParseSuite parses and validates a suite; dir resolves its relative
+// paths.
+func ParseSuite(data []byte, dir string) (*Suite, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var suite Suite
+	if err := decoder.Decode(&suite); err != nil {
+		return nil, err
+	}
+	suite.dir = dir
+	if err := suite.validate(); err != nil {
+		return nil, err
+	}
+	return &suite, nil
+}
+
+func (s *Suite) validate() error {
+	var problems []error
+	if s.Persona != "" && s.PromptFile != "" {
+		problems = append(problems, errors.New("set persona or prompt_file, not both"))
+	}
+	if len(s.Cases) == 0 {
+		problems = append(problems, errors.New("has no cases"))
+	}
+	for i, step := range s.Chain {
+		if strings.TrimSpace(step.Message) == "" {
+			problems = append(problems, fmt.Errorf("chain step %d has no message", i+1))
+		}
+	}
+
+	names := make(map[string]bool)
+	for i := range s.Cases {
+		c := &s.Cases[i]
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("case %d", i+1)
+		}
+		if names[c.Name] {
+			problems = append(problems, fmt.Errorf("case %q is defined twice", c.Name))
+		}
+		names[c.Name] = true
+		if strings.TrimSpace(c.Input) == "" {
+			problems = append(problems, fmt.Errorf("case %q has no input", c.Name))
+		}
+		if len(c.Assert) == 0 {
+			problems = append(problems, fmt.Errorf("case %q has no assertions", c.Name))
+		}
+		for j := range c.Assert {
+			if err := c.Assert[j].compile(s.dir); err != nil {
+				problems = append(problems, fmt.Errorf("case %q assertion %d: %w", c.Name, j+1, err))
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// compile checks that exactly one check is set and prepares it
+func (a *Assertion) compile(dir string) error {
+	set := 0
+	for _, isSet := range []bool{a.Contains != "", a.NotContains != "", a.Regex != "", a.JSONSchema != nil, a.LLM != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("set exactly one of contains, not_contains, regex, json_schema and llm")
+	}
+
+	switch {
+	case a.Regex != "":
+		regex, err := regexp.Compile(a.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		a.regex = regex
+	case a.JSONSchema != nil:
+		schema, err := loadSchema(a.JSONSchema, dir)
+		if err != nil {
+			return err
+		}
+		a.schema = schema
+	}
+	return nil
+}
+
+// loadSchema reads a json_schema value: a file path or an inline schema
+func loadSchema(value any, dir string) (*ai.Schema, error) {
+	if path, ok := value.(string); ok {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return ai.LoadResponseSchema(path)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json_schema: %w", err)
+	}
+	return ai.ParseJSONSchema(data)
+}
+
+// String describes the assertion in reports
+func (a Assertion) String() string {
+	switch {
+	case a.Contains != "":
+		return fmt.Sprintf("contains %q", a.Contains)
+	case a.NotContains != "":
+		return fmt.Sprintf("does not contain %q", a.NotContains)
+	case a.Regex != "":
+		return fmt.Sprintf("matches /%s/", a.Regex)
+	case a.JSONSchema != nil:
+		if path, ok := a.JSONSchema.(string); ok {
+			return "matches schema " + path
+		}
+		return "matches the JSON schema"
+	default:
+		return "graded: " + a.LLM
+	}
+}
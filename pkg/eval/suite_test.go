@@ -0,0 +1,115 @@
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuite(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "verdict.json"), []byte(`{"type": "object", "required": ["ok"]}`), 0o644))
+
+	suite, err := ParseSuite([]byte(`
+name: reviewer
+persona: reviewer
+models: [gpt-4o, anthropic/claude-sonnet-4-5]
+cases:
+  - name: spots the error
+    input: "Review: f.Close()"
+    assert:
+      - contains: Close
+      - regex: "(?i)error"
+      - llm: points out the ignored error
+  - input: Reply with JSON
+    assert:
+      - json_schema: verdict.json
+      - json_schema:
+          type: object
+          required: [ok]
+`), dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "reviewer", suite.Persona)
+	assert.Equal(t, []string{"gpt-4o", "anthropic/claude-sonnet-4-5"}, suite.Models)
+	require.Len(t, suite.Cases, 2)
+	assert.Equal(t, "case 2", suite.Cases[1].Name)
+	assert.Equal(t, `matches /(?i)error/`, suite.Cases[0].Assert[1].String())
+	assert.NotNil(t, suite.Cases[1].Assert[0].schema)
+	assert.NotNil(t, suite.Cases[1].Assert[1].schema)
+	assert.Equal(t, "matches schema verdict.json", suite.Cases[1].Assert[0].String())
+}
+
+func TestParseSuiteRejectsInvalidSuites(t *testing.T) {
+	tests := []struct {
+		name  string
+		suite string
+		want  string
+	}{
+		{
+			name:  "unknown field",
+			suite: "cases:\n  - input: hi\n    expect: hello\n",
+			want:  "field expect not found",
+		},
+		{
+			name:  "no cases",
+			suite: "name: empty\n",
+			want:  "has no cases",
+		},
+		{
+			name:  "persona and prompt file",
+			suite: "persona: reviewer\nprompt_file: prompt.yaml\ncases:\n  - input: hi\n    assert: [{contains: hi}]\n",
+			want:  "set persona or prompt_file, not both",
+		},
+		{
+			name:  "chain step without message",
+			suite: "chain:\n  - persona: planner\ncases:\n  - input: hi\n    assert: [{contains: hi}]\n",
+			want:  "chain step 1 has no message",
+		},
+		{
+			name:  "duplicate case",
+			suite: "cases:\n  - name: a\n    input: hi\n    assert: [{contains: hi}]\n  - name: a\n    input: ho\n    assert: [{contains: ho}]\n",
+			want:  `case "a" is defined twice`,
+		},
+		{
+			name:  "no assertions",
+			suite: "cases:\n  - input: hi\n",
+			want:  `case "case 1" has no assertions`,
+		},
+		{
+			name:  "two checks in one assertion",
+			suite: "cases:\n  - input: hi\n    assert: [{contains: hi, regex: h}]\n",
+			want:  "set exactly one of",
+		},
+		{
+			name:  "invalid regex",
+			suite: "cases:\n  - input: hi\n    assert: [{regex: \"(\"}]\n",
+			want:  "invalid regex",
+		},
+		{
+			name:  "missing schema file",
+			suite: "cases:\n  - input: hi\n    assert: [{json_schema: missing.json}]\n",
+			want:  "missing.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSuite([]byte(tt.suite), t.TempDir())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoadSuiteNamesTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suite.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("name: empty\n"), 0o644))
+
+	_, err := LoadSuite(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid suite "+path)
+}
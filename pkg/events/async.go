@@ -0,0 +1,104 @@
+package events
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// DefaultAsyncQueueSize is the queue length SubscribeAsync uses when given
+// a size of zero or less.
+const DefaultAsyncQueueSize = 256
+
+// SubscribeAsync attaches a handler that runs on its own goroutine, fed by
+// a queue of at most queueSize events. Publishers, including PublishSync
+// callers such as tool execution, only wait to enqueue, so a slow or stuck
+// handler cannot hold them up.
+//
+// Unlike the bus itself, an async subscriber trades losslessness for that
+// guarantee: when its queue is full, new events are dropped and a warning
+// is logged, and its events can arrive after later synchronous ones. Use it
+// for consumers that can miss events, not for anything that builds the
+// transcript.
+// Events keep their publish order. The returned function unsubscribes and
+// stops the goroutine once the queued events are handled.
+func SubscribeAsync(bus Subscriber, eventType string, queueSize int, handler EventHandler) func() {
+	if queueSize <= 0 {
+		queueSize = DefaultAsyncQueueSize
+	}
+	sub := &asyncSubscriber{
+		topic:   eventType,
+		handler: handler,
+		queue:   make(chan interface{}, queueSize),
+		done:    make(chan struct{}),
+	}
+	go sub.run()
+
+	unsubscribe := bus.Subscribe(eventType, sub.enqueue)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unsubscribe()
+			sub.close()
+		})
+	}
+}
+
+// SubscribeToAsync is the typed form of SubscribeAsync, attaching handler
+// to T's topic. Events on the topic that are not of type T are ignored.
+func SubscribeToAsync[T Event](bus Subscriber, queueSize int, handler func(T)) func() {
+	var zero T
+	return SubscribeAsync(bus, zero.Topic(), queueSize, func(event interface{}) {
+		if typed, ok := event.(T); ok {
+			handler(typed)
+		}
+	})
+}
+
+// asyncSubscriber owns the bounded queue and goroutine behind one
+// SubscribeAsync handler.
+type asyncSubscriber struct {
+	topic   string
+	handler EventHandler
+	queue   chan interface{}
+	done    chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int
+}
+
+func (s *asyncSubscriber) enqueue(event interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- event:
+	default:
+		s.dropped++
+		// Log the first drop and then every power of two, so a stuck
+		// handler doesn't flood the log.
+		if s.dropped&(s.dropped-1) == 0 {
+			slog.Warn("Async event subscriber is falling behind; dropping events",
+				"topic", s.topic, "queue_size", cap(s.queue), "dropped", s.dropped)
+		}
+	}
+}
+
+func (s *asyncSubscriber) run() {
+	defer close(s.done)
+	for event := range s.queue {
+		invokeHandler(s.topic, s.handler, event)
+	}
+}
+
+// close stops accepting events and lets the goroutine finish the queue.
+func (s *asyncSubscriber) close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeAsyncDoesNotBlockPublishSync(t *testing.T) {
+	bus := NewEventBus().(*InMemoryBus)
+	defer bus.Shutdown()
+
+	release := make(chan struct{})
+	received := make(chan int, 10)
+	unsub := SubscribeAsync(bus, "tool.executed", 4, func(event interface{}) {
+		<-release
+		received <- event.(int)
+	})
+	defer unsub()
+
+	published := make(chan struct{})
+	go func() {
+		for i := 1; i <= 3; i++ {
+			bus.PublishSync("tool.executed", i)
+		}
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("PublishSync waited for a stuck async subscriber")
+	}
+
+	close(release)
+	for want := 1; want <= 3; want++ {
+		select {
+		case got := <-received:
+			assert.Equal(t, want, got, "async delivery keeps publish order")
+		case <-time.After(time.Second):
+			t.Fatalf("event %d was not delivered", want)
+		}
+	}
+}
+
+func TestSubscribeAsyncDropsWhenQueueIsFull(t *testing.T) {
+	bus := NewEventBus().(*InMemoryBus)
+	defer bus.Shutdown()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	received := make(chan int, 10)
+	unsub := SubscribeAsync(bus, "test.event", 2, func(event interface{}) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		received <- event.(int)
+	})
+
+	// The handler holds the first event, the next two fill the queue and
+	// the rest are dropped.
+	bus.PublishSync("test.event", 1)
+	<-started
+	for i := 2; i <= 6; i++ {
+		bus.PublishSync("test.event", i)
+	}
+	close(release)
+	unsub()
+
+	var got []int
+	for len(got) < 3 {
+		select {
+		case event := <-received:
+			got = append(got, event)
+		case <-time.After(time.Second):
+			t.Fatalf("queued events were not delivered; got %v", got)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3}, got)
+	select {
+	case event := <-received:
+		t.Fatalf("event %d should have been dropped", event)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSubscribeToAsyncSurvivesPanics(t *testing.T) {
+	bus := NewEventBus().(*InMemoryBus)
+	defer bus.Shutdown()
+
+	received := make(chan string, 2)
+	unsub := SubscribeToAsync(bus, 0, func(e ChatResponseEvent) {
+		if e.RequestID == "bad" {
+			panic("boom")
+		}
+		received <- e.RequestID
+	})
+	defer unsub()
+
+	bus.PublishSync(ChatResponseEvent{}.Topic(), ChatResponseEvent{RequestID: "bad"})
+	bus.PublishSync(ChatResponseEvent{}.Topic(), "not-a-chat-response")
+	bus.PublishSync(ChatResponseEvent{}.Topic(), ChatResponseEvent{RequestID: "good"})
+
+	select {
+	case id := <-received:
+		assert.Equal(t, "good", id)
+	case <-time.After(time.Second):
+		t.Fatal("the subscriber stopped after a panic")
+	}
+}
+
+func TestSubscribeAsyncUnsubscribe(t *testing.T) {
+	bus := NewEventBus().(*InMemoryBus)
+	defer bus.Shutdown()
+
+	unsub := SubscribeAsync(bus, "test.event", 0, func(event interface{}) {})
+	assert.Equal(t, 1, bus.SubscriberCount("test.event"))
+	unsub()
+	unsub()
+	assert.Equal(t, 0, bus.SubscriberCount("test.event"))
+	bus.PublishSync("test.event", 1) // must not panic on the closed queue
+}
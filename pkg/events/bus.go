@@ -1,7 +1,8 @@
 package events
 
 import (
-	"log"
+	"log/slog"
+	"runtime/debug"
 	"sync"
 )
 
@@ -29,6 +30,21 @@ type EventBus interface {
 	Subscriber
 }
 
+// Flusher is implemented by buses that deliver Publish asynchronously.
+// Flush blocks until every event published on the topic before the call
+// has been delivered to its subscribers.
+type Flusher interface {
+	Flush(eventType string)
+}
+
+// Flush waits for pending events on the topic when bus delivers them
+// asynchronously, and returns immediately otherwise.
+func Flush(bus Subscriber, eventType string) {
+	if flusher, ok := bus.(Flusher); ok {
+		flusher.Flush(eventType)
+	}
+}
+
 // InMemoryBus implements EventBus with in-memory storage.
 //
 // Delivery contract: events are delivered asynchronously, in publish
@@ -97,7 +113,7 @@ func (b *InMemoryBus) Publish(eventType string, event interface{}) {
 	}
 
 	worker := b.getOrCreateWorker(eventType)
-	worker.enqueue(eventEnvelope{event: event, handlers: handlers})
+	worker.enqueue(eventEnvelope{topic: eventType, event: event, handlers: handlers})
 }
 
 // PublishSync delivers an event to all subscribers synchronously on the
@@ -106,7 +122,22 @@ func (b *InMemoryBus) Publish(eventType string, event interface{}) {
 func (b *InMemoryBus) PublishSync(eventType string, event interface{}) {
 	handlers := b.handlersFor(eventType)
 	for _, handler := range handlers {
-		invokeHandler(handler, event)
+		invokeHandler(eventType, handler, event)
+	}
+}
+
+// Flush blocks until the events published on the topic so far have been
+// delivered. Events published while it waits are not waited for.
+func (b *InMemoryBus) Flush(eventType string) {
+	b.mu.RLock()
+	worker, ok := b.workers[eventType]
+	b.mu.RUnlock()
+	if !ok {
+		return
+	}
+	delivered := make(chan struct{})
+	if worker.enqueue(eventEnvelope{topic: eventType, delivered: delivered}) {
+		<-delivered
 	}
 }
 
@@ -154,18 +185,24 @@ func (b *InMemoryBus) getOrCreateWorker(eventType string) *topicWorker {
 	return worker
 }
 
-func invokeHandler(h EventHandler, e interface{}) {
+// invokeHandler runs one subscriber, isolating its panics: the panic is
+// logged with its stack and the remaining subscribers still run.
+func invokeHandler(topic string, h EventHandler, e interface{}) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Event handler panicked: %v", r)
+			slog.Error("Event handler panicked", "topic", topic, "panic", r, "stack", string(debug.Stack()))
 		}
 	}()
 	h(e)
 }
 
 type eventEnvelope struct {
+	topic    string
 	event    interface{}
 	handlers []EventHandler
+	// delivered, when set, marks a Flush barrier and is closed once the
+	// events queued ahead of it have been handled.
+	delivered chan struct{}
 }
 
 // topicWorker drains an unbounded FIFO queue on a dedicated goroutine,
@@ -187,15 +224,18 @@ func newTopicWorker() *topicWorker {
 	return w
 }
 
-func (w *topicWorker) enqueue(env eventEnvelope) {
+// enqueue appends env to the queue and reports whether it was accepted;
+// a stopped worker drops it.
+func (w *topicWorker) enqueue(env eventEnvelope) bool {
 	w.mu.Lock()
 	if w.stopped {
 		w.mu.Unlock()
-		return
+		return false
 	}
 	w.queue = append(w.queue, env)
 	w.mu.Unlock()
 	w.cond.Signal()
+	return true
 }
 
 func (w *topicWorker) run() {
@@ -214,7 +254,10 @@ func (w *topicWorker) run() {
 		w.mu.Unlock()
 
 		for _, handler := range env.handlers {
-			invokeHandler(handler, env.event)
+			invokeHandler(env.topic, handler, env.event)
+		}
+		if env.delivered != nil {
+			close(env.delivered)
 		}
 	}
 }
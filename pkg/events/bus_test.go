@@ -181,3 +181,28 @@ func TestEventBus_MultipleEventTypesIsolation(t *testing.T) {
 	assert.Equal(t, []string{"a1"}, typeA)
 	assert.Equal(t, []string{"b1"}, typeB)
 }
+
+func TestEventBus_FlushWaitsForPublishedEvents(t *testing.T) {
+	bus := NewEventBus().(*InMemoryBus)
+	defer bus.Shutdown()
+
+	var handled atomic.Int32
+	bus.Subscribe("slow.event", func(event interface{}) {
+		time.Sleep(20 * time.Millisecond)
+		handled.Add(1)
+	})
+
+	bus.Publish("slow.event", 1)
+	bus.Publish("slow.event", 2)
+	bus.Flush("slow.event")
+
+	assert.Equal(t, int32(2), handled.Load())
+}
+
+func TestEventBus_FlushWithoutWorkerReturns(t *testing.T) {
+	bus := NewEventBus().(*InMemoryBus)
+	defer bus.Shutdown()
+
+	Flush(bus, "never.published")
+	Flush(&NoOpEventBus{}, "never.published")
+}
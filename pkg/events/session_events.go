@@ -7,6 +7,7 @@ import (
 // ToolStartingEvent represents a tool that is about to be executed
 type ToolStartingEvent struct {
 	ExecutionID string
+	RequestID   string // Chat request the call belongs to, when known
 	ToolName    string
 	Parameters  map[string]any
 }
@@ -19,11 +20,18 @@ func (e ToolStartingEvent) Topic() string {
 // ToolExecutedEvent represents a tool that has been executed
 type ToolExecutedEvent struct {
 	ExecutionID string
+	RequestID   string // Chat request the call belongs to, when known
 	ToolName    string
 	Parameters  map[string]any
 	Success     bool           // Whether the tool handler returned without error
+	Cancelled   bool           // The request was cancelled while the tool ran
+	TimedOut    bool           // The tool hit its GENIE_TOOL_TIMEOUT limit
 	Message     string         // Human-readable outcome for display
 	Result      map[string]any // The actual result returned by the tool
+
+	// OutputHandles identify stored full output for result fields that were
+	// truncated before reaching the model. Empty when nothing was truncated.
+	OutputHandles []string
 }
 
 // Topic returns the event topic for tool execution
@@ -48,6 +56,9 @@ func (e ToolConfirmationRequest) Topic() string {
 type ToolConfirmationResponse struct {
 	ExecutionID string
 	Confirmed   bool
+	Command     string // Optional: the command as edited by the user, run instead of the proposed one
+	AlwaysAllow bool   // Approve the tool (for this command's program) for the rest of the session
+	Feedback    string // Optional: why the user declined, returned to the model with the denial
 }
 
 // Topic returns the event topic for tool confirmation responses
@@ -65,6 +76,7 @@ type UserConfirmationRequest struct {
 	Message     string // Optional: custom message
 	ConfirmText string // Optional: custom confirm button text
 	CancelText  string // Optional: custom cancel button text
+	Editable    string // Optional: text the user may edit before approving, e.g. a file's new content
 }
 
 // Topic returns the event topic for user confirmation requests
@@ -76,6 +88,8 @@ func (e UserConfirmationRequest) Topic() string {
 type UserConfirmationResponse struct {
 	ExecutionID string
 	Confirmed   bool
+	Content     string // Optional: the request's Editable text as edited by the user
+	Feedback    string // Optional: why the user declined, returned to the model with the denial
 }
 
 // Topic returns the event topic for user confirmation responses
@@ -85,12 +99,13 @@ func (e UserConfirmationResponse) Topic() string {
 
 // ChatResponseEvent is published when AI generates a response
 type ChatResponseEvent struct {
-	RequestID string
-	Message   string
-	Response  string
-	Error     error
-	UserInput string
-	Ephemeral int // 0=store both, 1=skip input, 2=skip output, 3=skip both
+	RequestID  string
+	Message    string
+	Response   string
+	Candidates []string // Every answer sampled, when the turn asked for several; Response is the first
+	Error      error
+	UserInput  string
+	Ephemeral  int // 0=store both, 1=skip input, 2=skip output, 3=skip both
 }
 
 // Topic returns the event topic for chat responses
@@ -160,6 +175,10 @@ type TokenCountEvent struct {
 	CacheReadInputTokens     int32
 
 	TotalTokens int32
+
+	// Estimate is set when the counts come from a token-counting call rather
+	// than a billed model response, so cost accounting can skip them.
+	Estimate bool
 }
 
 // Topic returns the event topic for token count events
@@ -167,6 +186,47 @@ func (e TokenCountEvent) Topic() string {
 	return "token.count"
 }
 
+// AgentProgressEvent reports progress of an autonomous agent run
+type AgentProgressEvent struct {
+	RunID     string
+	Iteration int
+	Phase     string // "plan", "act", "reflect", "done"
+	Message   string
+}
+
+// Topic returns the event topic for agent progress events
+func (e AgentProgressEvent) Topic() string {
+	return "agent.progress"
+}
+
+// ModelRetryEvent is published when a failed model request is retried
+type ModelRetryEvent struct {
+	RequestID string
+	Attempt   int // The attempt that failed, starting at 1
+	Error     string
+}
+
+// Topic returns the event topic for model retries
+func (e ModelRetryEvent) Topic() string {
+	return "model.retry"
+}
+
+// SubAgentEvent reports activity of a sub-agent spawned by the runAgent tool,
+// so hosts can show nested work under the parent conversation.
+type SubAgentEvent struct {
+	AgentID  string
+	Task     string
+	Phase    string // "started", "tool", "finished"
+	ToolName string // set for "tool"
+	Message  string
+	Error    string // set for "finished" when the sub-agent failed
+}
+
+// Topic returns the event topic for sub-agent activity
+func (e SubAgentEvent) Topic() string {
+	return "subagent.activity"
+}
+
 // SkillInvokedEvent is published when a skill is invoked
 type SkillInvokedEvent struct {
 	Skill interface{} // The loaded skill (can be *skills.Skill but using interface{} to avoid circular import)
@@ -0,0 +1,371 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// DefaultAgentMaxIterations bounds an agent run when no iteration limit is set.
+const DefaultAgentMaxIterations = 10
+
+// AgentLimits are the safety limits of an agent run. A zero MaxTokens or
+// MaxCost disables that limit; a non-positive MaxIterations uses
+// DefaultAgentMaxIterations.
+type AgentLimits struct {
+	MaxIterations int
+	MaxTokens     int64   // billed tokens, cached input included, across the whole run
+	MaxCost       float64 // USD, priced with the agent's pricing table
+}
+
+// AgentStopReason explains why an agent run ended.
+type AgentStopReason string
+
+const (
+	AgentStopDone          AgentStopReason = "done"
+	AgentStopMaxIterations AgentStopReason = "max_iterations"
+	AgentStopMaxTokens     AgentStopReason = "max_tokens"
+	AgentStopMaxCost       AgentStopReason = "max_cost"
+	AgentStopPlanRejected  AgentStopReason = "plan_rejected"
+)
+
+// AgentResult describes a finished agent run.
+type AgentResult struct {
+	Iterations int
+	StopReason AgentStopReason
+	Plan       string        // the last approved plan
+	Summary    string        // final reflection, or the last action when a limit was hit
+	Usage      pricing.Usage // tokens and cost spent by the run
+}
+
+// AgentOption configures an Agent.
+type AgentOption func(*Agent)
+
+// WithAgentLimits sets the safety limits of the run.
+func WithAgentLimits(limits AgentLimits) AgentOption {
+	return func(a *Agent) {
+		a.limits = limits
+	}
+}
+
+// WithAgentAutoApprove skips plan confirmation; every plan and revision is
+// still published, but the agent does not wait for the user.
+func WithAgentAutoApprove() AgentOption {
+	return func(a *Agent) {
+		a.autoApprove = true
+	}
+}
+
+// WithAgentPricing sets the table used to enforce MaxCost.
+func WithAgentPricing(table *pricing.Table) AgentOption {
+	return func(a *Agent) {
+		a.pricing = table
+	}
+}
+
+// WithAgentConfirmer replaces the confirmer used to approve plans. By
+// default plans go through the Genie event bus, so they appear in the same
+// confirmation UI as other plan approvals.
+func WithAgentConfirmer(confirmer tools.Confirmer) AgentOption {
+	return func(a *Agent) {
+		a.confirmer = confirmer
+	}
+}
+
+// Agent drives a started Genie through plan → act → reflect iterations
+// until the model reports the task done or a safety limit is reached. Each
+// step is an ordinary chat turn, so tools, confirmations, and history
+// behave exactly as they do interactively.
+type Agent struct {
+	genie       Genie
+	limits      AgentLimits
+	autoApprove bool
+	confirmer   tools.Confirmer
+	pricing     *pricing.Table
+}
+
+// NewAgent creates an agent over g, which must already be started.
+func NewAgent(g Genie, opts ...AgentOption) *Agent {
+	agent := &Agent{genie: g}
+	for _, opt := range opts {
+		opt(agent)
+	}
+	if agent.limits.MaxIterations <= 0 {
+		agent.limits.MaxIterations = DefaultAgentMaxIterations
+	}
+	if agent.confirmer == nil {
+		agent.confirmer = tools.NewBusConfirmer(g.GetEventBus())
+	}
+	if agent.pricing == nil {
+		agent.pricing = pricing.NewTable(nil)
+	}
+	return agent
+}
+
+// agentRun holds the per-run state shared by the steps of Run.
+type agentRun struct {
+	id      string
+	bus     events.EventBus
+	tracker *pricing.Tracker
+
+	mu      sync.Mutex
+	waiting map[string]chan events.ChatResponseEvent
+}
+
+// Run executes task and returns once the agent is done, a limit is hit, the
+// user rejects a plan, or ctx is canceled. A non-nil error means the run
+// could not continue; the result still reports the progress made.
+func (a *Agent) Run(ctx context.Context, task string) (AgentResult, error) {
+	task = strings.TrimSpace(task)
+	if task == "" {
+		return AgentResult{}, fmt.Errorf("agent task cannot be empty")
+	}
+
+	run := &agentRun{
+		id:      uuid.NewString(),
+		bus:     a.genie.GetEventBus(),
+		tracker: pricing.NewTracker(a.pricing),
+		waiting: make(map[string]chan events.ChatResponseEvent),
+	}
+	defer events.SubscribeTo(run.bus, func(e events.TokenCountEvent) {
+		run.tracker.Record(e)
+	})()
+	defer events.SubscribeTo(run.bus, run.deliver)()
+
+	result := AgentResult{}
+	finish := func(reason AgentStopReason, err error) (AgentResult, error) {
+		result.StopReason = reason
+		result.Usage = run.usage()
+		run.progress(result.Iterations, "done", string(reason))
+		return result, err
+	}
+
+	run.progress(0, "plan", "planning")
+	response, err := a.step(ctx, run, agentPlanPrompt(task))
+	if err != nil {
+		return finish("", err)
+	}
+	plan := extractAgentPlan(response)
+	if approved, err := a.approvePlan(ctx, plan, false); err != nil {
+		return finish("", err)
+	} else if !approved {
+		return finish(AgentStopPlanRejected, nil)
+	}
+	result.Plan = plan
+
+	for result.Iterations < a.limits.MaxIterations {
+		if reason, exceeded := a.budgetExceeded(run); exceeded {
+			return finish(reason, nil)
+		}
+		result.Iterations++
+
+		run.progress(result.Iterations, "act", "working on the plan")
+		action, err := a.step(ctx, run, agentActPrompt(result.Iterations))
+		if err != nil {
+			return finish("", err)
+		}
+		result.Summary = action
+		if reason, exceeded := a.budgetExceeded(run); exceeded {
+			return finish(reason, nil)
+		}
+
+		run.progress(result.Iterations, "reflect", "checking progress")
+		reflection, err := a.step(ctx, run, agentReflectPrompt())
+		if err != nil {
+			return finish("", err)
+		}
+		done, summary, revised := parseAgentReflection(reflection)
+		if summary != "" {
+			result.Summary = summary
+		}
+		if done {
+			return finish(AgentStopDone, nil)
+		}
+		if revised != "" && revised != result.Plan {
+			approved, err := a.approvePlan(ctx, revised, true)
+			if err != nil {
+				return finish("", err)
+			}
+			if !approved {
+				return finish(AgentStopPlanRejected, nil)
+			}
+			result.Plan = revised
+		}
+	}
+	return finish(AgentStopMaxIterations, nil)
+}
+
+// step sends one chat turn and waits for its response.
+func (a *Agent) step(ctx context.Context, run *agentRun, message string) (string, error) {
+	requestID := uuid.NewString()
+	responseCh := make(chan events.ChatResponseEvent, 1)
+	run.mu.Lock()
+	run.waiting[requestID] = responseCh
+	run.mu.Unlock()
+	defer func() {
+		run.mu.Lock()
+		delete(run.waiting, requestID)
+		run.mu.Unlock()
+	}()
+
+	if err := a.genie.Chat(ctx, message, WithRequestID(requestID)); err != nil {
+		return "", err
+	}
+
+	select {
+	case response := <-responseCh:
+		if response.Error != nil {
+			return "", response.Error
+		}
+		return strings.TrimSpace(response.Response), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// approvePlan publishes plan through the plan-confirmation UI and waits for
+// the user's decision unless auto-approval is on.
+func (a *Agent) approvePlan(ctx context.Context, plan string, revision bool) (bool, error) {
+	if plan == "" {
+		return false, fmt.Errorf("agent did not produce a plan")
+	}
+	if a.autoApprove {
+		return true, nil
+	}
+
+	title := "Agent Plan"
+	message := "Approve this plan to let the agent start working?"
+	if revision {
+		title = "Revised Agent Plan"
+		message = "The agent revised its plan. Continue with the new plan?"
+	}
+	return a.confirmer.ConfirmContent(ctx, events.UserConfirmationRequest{
+		ExecutionID: uuid.NewString(),
+		Title:       title,
+		Content:     plan,
+		ContentType: "plan",
+		Message:     message,
+		ConfirmText: "Approve",
+		CancelText:  "Reject",
+	})
+}
+
+func (a *Agent) budgetExceeded(run *agentRun) (AgentStopReason, bool) {
+	usage := run.usage()
+	if a.limits.MaxTokens > 0 && usage.Tokens() >= a.limits.MaxTokens {
+		return AgentStopMaxTokens, true
+	}
+	if a.limits.MaxCost > 0 && usage.Cost >= a.limits.MaxCost {
+		return AgentStopMaxCost, true
+	}
+	return "", false
+}
+
+// usage returns the run's totals so far. Token counts are published
+// asynchronously, so it first waits for the ones already on the bus.
+func (r *agentRun) usage() pricing.Usage {
+	events.Flush(r.bus, events.TokenCountEvent{}.Topic())
+	return r.tracker.Session()
+}
+
+func (r *agentRun) deliver(response events.ChatResponseEvent) {
+	r.mu.Lock()
+	responseCh, ok := r.waiting[response.RequestID]
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case responseCh <- response:
+	default:
+	}
+}
+
+func (r *agentRun) progress(iteration int, phase, message string) {
+	r.bus.Publish(events.AgentProgressEvent{}.Topic(), events.AgentProgressEvent{
+		RunID:     r.id,
+		Iteration: iteration,
+		Phase:     phase,
+		Message:   message,
+	})
+}
+
+func agentPlanPrompt(task string) string {
+	return fmt.Sprintf(`AUTONOMOUS AGENT TASK:
+
+You will complete the task below over several iterations without further input from the user. Start by writing a short, numbered plan. Do not use tools or make changes yet.
+
+Reply with the plan only, under a line containing just "PLAN:".
+
+TASK:
+%s`, task)
+}
+
+func agentActPrompt(iteration int) string {
+	return fmt.Sprintf(`AGENT ITERATION %d:
+
+Carry out the next steps of the approved plan using your tools. Do as much as you can in this turn, then briefly report what you did.`, iteration)
+}
+
+func agentReflectPrompt() string {
+	return `AGENT REFLECTION:
+
+Review the progress against the task and the plan. Reply in this format:
+
+STATUS: DONE or CONTINUE
+SUMMARY: one paragraph on what has been done and what remains
+
+If the plan needs to change, add a line containing just "PLAN:" followed by the full revised plan.`
+}
+
+// extractAgentPlan returns the text after a "PLAN:" marker, or the whole
+// response when the model left the marker out.
+func extractAgentPlan(response string) string {
+	if _, plan, found := cutAgentSection(response, "PLAN:"); found {
+		return plan
+	}
+	return strings.TrimSpace(response)
+}
+
+// parseAgentReflection reads the STATUS, SUMMARY and optional PLAN sections
+// of a reflection. A missing STATUS line counts as CONTINUE.
+func parseAgentReflection(reflection string) (done bool, summary string, plan string) {
+	body, plan, _ := cutAgentSection(reflection, "PLAN:")
+
+	var summaryLines []string
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		upper := strings.ToUpper(trimmed)
+		switch {
+		case strings.HasPrefix(upper, "STATUS:"):
+			done = strings.HasPrefix(strings.TrimSpace(upper[len("STATUS:"):]), "DONE")
+		case strings.HasPrefix(upper, "SUMMARY:"):
+			summaryLines = append(summaryLines, strings.TrimSpace(trimmed[len("SUMMARY:"):]))
+		default:
+			summaryLines = append(summaryLines, line)
+		}
+	}
+	return done, strings.TrimSpace(strings.Join(summaryLines, "\n")), plan
+}
+
+// cutAgentSection splits text at the first line starting with marker
+// (case-insensitive). The marker line's remainder starts the section.
+func cutAgentSection(text, marker string) (before, section string, found bool) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(strings.ToUpper(trimmed), marker) {
+			continue
+		}
+		rest := append([]string{strings.TrimSpace(trimmed[len(marker):])}, lines[i+1:]...)
+		return strings.TrimSpace(strings.Join(lines[:i], "\n")),
+			strings.TrimSpace(strings.Join(rest, "\n")), true
+	}
+	return text, "", false
+}
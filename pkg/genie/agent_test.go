@@ -0,0 +1,167 @@
+package genie_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/kcaldas/genie/pkg/llm/pricing"
+)
+
+type recordingConfirmer struct {
+	mu       sync.Mutex
+	answers  []bool
+	requests []events.UserConfirmationRequest
+}
+
+func (c *recordingConfirmer) ConfirmContent(ctx context.Context, req events.UserConfirmationRequest) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	if len(c.answers) == 0 {
+		return true, nil
+	}
+	answer := c.answers[0]
+	c.answers = c.answers[1:]
+	return answer, nil
+}
+
+func (c *recordingConfirmer) ConfirmExecution(ctx context.Context, req events.ToolConfirmationRequest) (bool, error) {
+	return true, nil
+}
+
+func runAgent(t *testing.T, fixture *genietest.TestFixture, task string, opts ...genie.AgentOption) genie.AgentResult {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := genie.NewAgent(fixture.Genie, opts...).Run(ctx, task)
+	require.NoError(t, err)
+	return result
+}
+
+func TestAgent_RunsUntilDone(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage(genie.AgentPlanPromptForTest("fix the bug"), "PLAN:\n1. Find it\n2. Fix it")
+	fixture.ExpectSimpleMessage(genie.AgentActPromptForTest(1), "found and fixed the bug")
+	fixture.ExpectSimpleMessage(genie.AgentReflectPromptForTest(), "STATUS: DONE\nSUMMARY: The bug is fixed.")
+
+	var phases []string
+	var mu sync.Mutex
+	events.SubscribeTo(fixture.EventBus, func(e events.AgentProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		phases = append(phases, e.Phase)
+	})
+
+	confirmer := &recordingConfirmer{}
+	result := runAgent(t, fixture, "fix the bug", genie.WithAgentConfirmer(confirmer))
+
+	assert.Equal(t, genie.AgentStopDone, result.StopReason)
+	assert.Equal(t, 1, result.Iterations)
+	assert.Equal(t, "1. Find it\n2. Fix it", result.Plan)
+	assert.Equal(t, "The bug is fixed.", result.Summary)
+
+	require.Len(t, confirmer.requests, 1)
+	assert.Equal(t, "plan", confirmer.requests[0].ContentType)
+	assert.Equal(t, "1. Find it\n2. Fix it", confirmer.requests[0].Content)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(phases) == 4 && phases[3] == "done"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAgent_PlanRejected(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage(genie.AgentPlanPromptForTest("delete everything"), "PLAN:\n1. rm -rf /")
+
+	confirmer := &recordingConfirmer{answers: []bool{false}}
+	result := runAgent(t, fixture, "delete everything", genie.WithAgentConfirmer(confirmer))
+
+	assert.Equal(t, genie.AgentStopPlanRejected, result.StopReason)
+	assert.Zero(t, result.Iterations)
+}
+
+func TestAgent_RevisedPlanIsConfirmedAndIterationsAreBounded(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage(genie.AgentPlanPromptForTest("refactor"), "1. Refactor")
+	fixture.ExpectSimpleMessage(genie.AgentActPromptForTest(1), "started")
+	fixture.ExpectSimpleMessage(genie.AgentActPromptForTest(2), "continued")
+	fixture.ExpectSimpleMessage(genie.AgentReflectPromptForTest(), "STATUS: CONTINUE\nSUMMARY: Halfway.\nPLAN:\n1. Refactor\n2. Test")
+
+	confirmer := &recordingConfirmer{}
+	result := runAgent(t, fixture, "refactor",
+		genie.WithAgentConfirmer(confirmer),
+		genie.WithAgentLimits(genie.AgentLimits{MaxIterations: 2}))
+
+	assert.Equal(t, genie.AgentStopMaxIterations, result.StopReason)
+	assert.Equal(t, 2, result.Iterations)
+	assert.Equal(t, "1. Refactor\n2. Test", result.Plan)
+	assert.Equal(t, "Halfway.", result.Summary)
+
+	require.Len(t, confirmer.requests, 2, "initial plan plus one revision")
+	assert.Equal(t, "Revised Agent Plan", confirmer.requests[1].Title)
+}
+
+func TestAgent_StopsWhenCostLimitReached(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage(genie.AgentPlanPromptForTest("spend"), "1. Spend")
+	fixture.EventBus.Subscribe(events.ChatResponseEvent{}.Topic(), func(interface{}) {
+		fixture.EventBus.PublishSync(events.TokenCountEvent{}.Topic(), events.TokenCountEvent{
+			Model:       "priced",
+			InputTokens: 1_000_000,
+		})
+	})
+
+	result := runAgent(t, fixture, "spend",
+		genie.WithAgentAutoApprove(),
+		genie.WithAgentPricing(pricing.NewTable(map[string]pricing.Rate{"priced": {Input: 1}})),
+		genie.WithAgentLimits(genie.AgentLimits{MaxCost: 0.5}))
+
+	assert.Equal(t, genie.AgentStopMaxCost, result.StopReason)
+	assert.Zero(t, result.Iterations)
+	assert.InDelta(t, 1.0, result.Usage.Cost, 1e-9)
+}
+
+func TestAgent_TokenLimitCountsCachedUsagePublishedAsync(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage(genie.AgentPlanPromptForTest("cache"), "1. Read the cache")
+	// A slow subscriber holds the token.count queue, so the usage is not
+	// yet recorded when the agent sees the chat response.
+	fixture.EventBus.Subscribe(events.TokenCountEvent{}.Topic(), func(interface{}) {
+		time.Sleep(50 * time.Millisecond)
+	})
+	fixture.EventBus.Subscribe(events.ChatResponseEvent{}.Topic(), func(interface{}) {
+		fixture.EventBus.Publish(events.TokenCountEvent{}.Topic(), events.TokenCountEvent{
+			Model:                    "local",
+			CachedTokens:             600,
+			CacheCreationInputTokens: 500,
+		})
+	})
+
+	result := runAgent(t, fixture, "cache",
+		genie.WithAgentAutoApprove(),
+		genie.WithAgentLimits(genie.AgentLimits{MaxTokens: 1000}))
+
+	assert.Equal(t, genie.AgentStopMaxTokens, result.StopReason)
+	assert.Zero(t, result.Iterations)
+	assert.Equal(t, int64(1100), result.Usage.Tokens())
+}
@@ -0,0 +1,101 @@
+package genie
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// applyAudit wraps every tool handler of a turn's prompt so each executed
+// call is appended to the session's audit log, with the credentials of
+// tools that redact their parameters hidden. Handlers are copied so the
+// cached persona prompt stays unwrapped.
+func (g *core) applyAudit(prompt *ai.Prompt, sess Session) {
+	if len(prompt.Handlers) == 0 || sess.GetGenieHomeDirectory() == "" {
+		return
+	}
+	if g.configMgr != nil && !g.configMgr.GetBoolWithDefault(audit.ConfigKey, true) {
+		return
+	}
+
+	log := audit.NewLog(audit.Path(sess.GetGenieHomeDirectory(), sess.GetID()))
+	handlers := make(map[string]ai.HandlerFunc, len(prompt.Handlers))
+	for name, handler := range prompt.Handlers {
+		var redact func(map[string]any) map[string]any
+		if g.toolRegistry != nil {
+			if tool, ok := g.toolRegistry.Get(name); ok {
+				if redactor, ok := tool.(tools.ParameterRedactor); ok {
+					redact = redactor.RedactParameters
+				}
+			}
+		}
+		handlers[name] = auditHandler(log, sess.GetID(), name, handler, redact)
+	}
+	prompt.Handlers = handlers
+}
+
+func auditHandler(log *audit.Log, sessionID, toolName string, next ai.HandlerFunc, redact func(map[string]any) map[string]any) ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		var mu sync.Mutex
+		confirmation := ""
+		ctx = toolctx.WithConfirmationObserver(ctx, func(approved bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			if approved {
+				confirmation = audit.ConfirmationApproved
+			} else {
+				confirmation = audit.ConfirmationDenied
+			}
+		})
+
+		start := time.Now()
+		result, err := next(ctx, params)
+
+		recorded := params
+		if redact != nil {
+			recorded = redact(params)
+		}
+		entry := audit.Entry{
+			Time:       start.UTC(),
+			SessionID:  sessionID,
+			Tool:       toolName,
+			Params:     recorded,
+			DurationMS: time.Since(start).Milliseconds(),
+			Status:     audit.StatusOK,
+		}
+		mu.Lock()
+		entry.Confirmation = confirmation
+		mu.Unlock()
+
+		switch {
+		case err != nil:
+			entry.Status = audit.StatusError
+			entry.Error = err.Error()
+		case result != nil:
+			if success, ok := result["success"].(bool); ok && !success {
+				entry.Status = audit.StatusFailed
+			}
+			if message, ok := result["error"].(string); ok {
+				entry.Error = message
+			}
+			if code, ok := result["exit_code"].(int); ok {
+				entry.ExitCode = &code
+			}
+			if data, marshalErr := json.Marshal(result); marshalErr == nil {
+				entry.OutputBytes = len(data)
+			}
+		}
+
+		if appendErr := log.Append(entry); appendErr != nil {
+			slog.Warn("failed to write tool audit entry", "tool", toolName, "error", appendErr)
+		}
+		return result, err
+	}
+}
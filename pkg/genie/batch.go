@@ -0,0 +1,179 @@
+package genie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/events"
+)
+
+// DefaultBatchConcurrency is how many prompts of a batch run at once when
+// no concurrency is set.
+const DefaultBatchConcurrency = 4
+
+// BatchRequest is one prompt of a ChatBatch.
+type BatchRequest struct {
+	// ID names the request in its result and errors; defaults to its
+	// index in the batch
+	ID      string
+	Message string
+	Options []ChatOption
+}
+
+// BatchResult is the outcome of one BatchRequest.
+type BatchResult struct {
+	ID       string
+	Message  string
+	Response string
+	Err      error
+	Duration time.Duration
+}
+
+// BatchOption configures a ChatBatch.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	concurrency int
+	failFast    bool
+	onResult    func(done, total int, result BatchResult)
+}
+
+// WithBatchConcurrency bounds how many prompts are in flight at once. A
+// non-positive n uses DefaultBatchConcurrency.
+func WithBatchConcurrency(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithBatchFailFast cancels the prompts still running or waiting as soon
+// as one fails; they finish with the cancellation error.
+func WithBatchFailFast() BatchOption {
+	return func(o *batchOptions) {
+		o.failFast = true
+	}
+}
+
+// WithBatchProgress calls fn as each prompt finishes, with how many have
+// finished so far. Calls never overlap.
+func WithBatchProgress(fn func(done, total int, result BatchResult)) BatchOption {
+	return func(o *batchOptions) {
+		o.onResult = fn
+	}
+}
+
+// ChatBatch sends independent prompts to g, at most the batch concurrency
+// at a time, and waits for all of them. Results are in request order.
+// Every turn is ephemeral by default, so the prompts neither see each
+// other nor change the conversation; a request's own options come after
+// that default and can override it. The error joins the failed requests'
+// errors, each prefixed with its ID, and is nil when all succeeded.
+func ChatBatch(ctx context.Context, g Genie, requests []BatchRequest, opts ...BatchOption) ([]BatchResult, error) {
+	options := batchOptions{concurrency: DefaultBatchConcurrency}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.concurrency <= 0 {
+		options.concurrency = DefaultBatchConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Route each response to the request waiting for it
+	var mu sync.Mutex
+	waiting := make(map[string]chan events.ChatResponseEvent)
+	defer events.SubscribeTo(g.GetEventBus(), func(e events.ChatResponseEvent) {
+		mu.Lock()
+		responseCh, ok := waiting[e.RequestID]
+		mu.Unlock()
+		if ok {
+			select {
+			case responseCh <- e:
+			default:
+			}
+		}
+	})()
+
+	run := func(request BatchRequest) (string, error) {
+		requestID := uuid.NewString()
+		responseCh := make(chan events.ChatResponseEvent, 1)
+		mu.Lock()
+		waiting[requestID] = responseCh
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(waiting, requestID)
+			mu.Unlock()
+		}()
+
+		chatOpts := append([]ChatOption{WithEphemeral(EphemeralAll)}, request.Options...)
+		chatOpts = append(chatOpts, WithRequestID(requestID))
+		if err := g.Chat(ctx, request.Message, chatOpts...); err != nil {
+			return "", err
+		}
+		select {
+		case response := <-responseCh:
+			if response.Error != nil {
+				return "", response.Error
+			}
+			return strings.TrimSpace(response.Response), nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	results := make([]BatchResult, len(requests))
+	slots := make(chan struct{}, options.concurrency)
+	var wg sync.WaitGroup
+	var doneMu sync.Mutex
+	done := 0
+	for i, request := range requests {
+		if request.ID == "" {
+			request.ID = strconv.Itoa(i)
+		}
+		results[i] = BatchResult{ID: request.ID, Message: request.Message}
+
+		wg.Add(1)
+		go func(i int, request BatchRequest) {
+			defer wg.Done()
+			result := &results[i]
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				if result.Err = ctx.Err(); result.Err == nil {
+					started := time.Now()
+					result.Response, result.Err = run(request)
+					result.Duration = time.Since(started)
+				}
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+			}
+			if result.Err != nil && options.failFast {
+				cancel()
+			}
+
+			doneMu.Lock()
+			defer doneMu.Unlock()
+			done++
+			if options.onResult != nil {
+				options.onResult(done, len(requests), *result)
+			}
+		}(i, request)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.ID, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
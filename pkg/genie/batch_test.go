@@ -0,0 +1,102 @@
+package genie_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+)
+
+func TestChatBatch_RunsPromptsConcurrentlyInOrder(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	fixture.ExpectMessage("document pkg/a").RespondWith("docs for a").WithDelay(50 * time.Millisecond)
+	fixture.ExpectSimpleMessage("document pkg/b", "docs for b")
+	fixture.ExpectSimpleMessage("document pkg/c", "docs for c")
+
+	// Track how many turns run at once
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	events.SubscribeTo(fixture.EventBus, func(e events.ChatStartedEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		inFlight++
+		peak = max(peak, inFlight)
+	})
+	events.SubscribeTo(fixture.EventBus, func(e events.ChatResponseEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		inFlight--
+	})
+
+	var progress []int
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results, err := genie.ChatBatch(ctx, fixture.Genie, []genie.BatchRequest{
+		{ID: "a", Message: "document pkg/a"},
+		{ID: "b", Message: "document pkg/b"},
+		{Message: "document pkg/c"},
+	}, genie.WithBatchConcurrency(2), genie.WithBatchProgress(func(done, total int, result genie.BatchResult) {
+		assert.Equal(t, 3, total)
+		progress = append(progress, done)
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, "docs for a", results[0].Response)
+	assert.Equal(t, "b", results[1].ID)
+	assert.Equal(t, "docs for b", results[1].Response)
+	assert.Equal(t, "2", results[2].ID, "the index names a request without an ID")
+	assert.Equal(t, []int{1, 2, 3}, progress)
+	assert.LessOrEqual(t, peak, 2)
+
+	// The prompts are ephemeral: the conversation is untouched
+	history, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestChatBatch_AggregatesErrors(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("review main.go", "fine")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results, err := genie.ChatBatch(ctx, fixture.Genie, []genie.BatchRequest{
+		{ID: "main.go", Message: "review main.go"},
+		{ID: "util.go", Message: "review util.go"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "util.go: ")
+	assert.NotContains(t, err.Error(), "main.go:")
+	assert.Equal(t, "fine", results[0].Response)
+	assert.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+}
+
+func TestChatBatch_FailFastCancelsTheRest(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	fixture.ExpectMessage("slow").RespondWith("done").WithDelay(2 * time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	started := time.Now()
+	results, err := genie.ChatBatch(ctx, fixture.Genie, []genie.BatchRequest{
+		{Message: "slow"},
+		{Message: "unexpected"},
+	}, genie.WithBatchFailFast())
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(started), time.Second, "the slow prompt is cancelled")
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+}
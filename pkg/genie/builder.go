@@ -5,6 +5,7 @@ import (
 	"github.com/kcaldas/genie/pkg/ctx"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/persona"
+	"github.com/kcaldas/genie/pkg/plugin"
 	"github.com/kcaldas/genie/pkg/tools"
 )
 
@@ -32,8 +33,19 @@ import (
 //	    }),
 //	)
 func NewGenie(opts ...GenieOption) (Genie, error) {
-	options := applyOptions(opts...)
-	return ProvideGenieWithOptions(options)
+	return newGenieWithPlugins(applyOptions(opts...))
+}
+
+// newGenieWithPlugins builds Genie and lets the plugins that observe
+// events subscribe to its bus. Their tools and context parts are wired in
+// by ProvideGenieWithOptions.
+func newGenieWithPlugins(options *GenieOptions) (Genie, error) {
+	g, err := ProvideGenieWithOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	plugin.Subscribe(options.plugins(), g.GetEventBus())
+	return g, nil
 }
 
 // NewGenieWithDefaults creates a new Genie instance with all default settings.
@@ -54,7 +66,7 @@ func NewGenieWithDefaults() (Genie, error) {
 // build-tag configurations: consumers like cmd/bootstrap must compile inside
 // Wire's own wireinject-tagged package load, where wire_gen.go is excluded.
 func ProvideGenie() (Genie, error) {
-	return ProvideGenieWithOptions(applyOptions())
+	return newGenieWithPlugins(applyOptions())
 }
 
 // NewGenieWithComponents assembles a Genie from explicitly provided
@@ -0,0 +1,58 @@
+package genie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// MaxCandidates caps how many answers one turn may sample.
+const MaxCandidates = 5
+
+// turnResult is a turn's answer and, when several were sampled, all of them.
+type turnResult struct {
+	response   string
+	candidates []string
+}
+
+// runCandidates samples options.candidates answers to the prompt in
+// parallel. Runs are not streamed, since their chunks would interleave. A
+// run that fails is dropped; the turn fails only when every run does. The
+// first answer is the turn's response.
+func (g *core) runCandidates(ctx context.Context, prompt *ai.Prompt, promptData map[string]string, options chatRequestOptions) (turnResult, error) {
+	n := min(options.candidates, MaxCandidates)
+	options.stream = false
+
+	answers := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		// Structured runs rewrite the message while repairing answers
+		data := make(map[string]string, len(promptData))
+		for key, value := range promptData {
+			data[key] = value
+		}
+		runPrompt := *prompt
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			answers[i], errs[i] = g.answerPrompt(ctx, &runPrompt, data, options)
+		}()
+	}
+	wg.Wait()
+
+	var result turnResult
+	for i, answer := range answers {
+		if errs[i] == nil {
+			result.candidates = append(result.candidates, answer)
+		}
+	}
+	if len(result.candidates) == 0 {
+		return turnResult{}, fmt.Errorf("all %d candidates failed: %w", n, errors.Join(errs...))
+	}
+	result.response = result.candidates[0]
+	return result, nil
+}
@@ -39,11 +39,20 @@ const (
 type chatRequestOptions struct {
 	images                  []ChatImage
 	promptData              map[string]string
+	contextParts            map[string]string
 	stream                  bool
 	requestID               string
 	ephemeral               EphemeralMode
 	disableCache            bool
+	showThoughts            bool
+	outputControls          OutputControls
+	provider                string
+	model                   string
+	temperature             *float32
+	candidates              int
 	systemPromptUserContext string
+	responseSchema          *ai.Schema
+	promptYAML              []byte
 }
 
 // ChatOption configures a chat request. Options are optional – existing
@@ -100,6 +109,23 @@ func WithPromptData(data map[string]string) ChatOption {
 	}
 }
 
+// WithContextPart attaches extra context to the chat request under name,
+// e.g. "stdin" for input piped to the CLI. Persona templates can place it
+// with {{.name}}; when the template does not reference it, the part is sent
+// ahead of the message under a "## name" heading so the model still sees
+// it. Empty content is ignored.
+func WithContextPart(name, content string) ChatOption {
+	return func(opts *chatRequestOptions) {
+		if name == "" || content == "" {
+			return
+		}
+		if opts.contextParts == nil {
+			opts.contextParts = make(map[string]string)
+		}
+		opts.contextParts[name] = content
+	}
+}
+
 func applyChatOptions(optionFns ...ChatOption) chatRequestOptions {
 	request := chatRequestOptions{
 		promptData: make(map[string]string),
@@ -178,14 +204,112 @@ func WithSystemPromptUserContext(content string) ChatOption {
 // WithoutPromptCache asks the LLM client to skip provider-side prompt caching
 // for this call. Use it for verification probes or other throwaway prompts
 // whose cached prefix would not be reused — caching them just pays the write
-// cost (Anthropic ~125-200% of input price) for nothing. Has no effect on
-// providers without explicit cache controls (Gemini, OpenAI implicit caching).
+// cost (Anthropic ~125-200% of input price) for nothing. Gemini skips its
+// context cache and OpenAI gets no prompt_cache_key; OpenAI may still cache
+// the prompt automatically.
 func WithoutPromptCache() ChatOption {
 	return func(opts *chatRequestOptions) {
 		opts.disableCache = true
 	}
 }
 
+// WithShowThoughts asks the LLM client to request the model's reasoning and
+// publish it as "thought" notifications (chat.notification events with
+// ContentType "thought"): Gemini thoughts, Anthropic extended thinking, and
+// the reasoning OpenAI-compatible servers return. Models that keep their
+// reasoning private, such as OpenAI's own o-series, show nothing.
+func WithShowThoughts(enabled bool) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.showThoughts = enabled
+	}
+}
+
+// OutputControls override the persona's output settings for one request.
+// Zero values keep what the persona's prompt.yaml sets.
+type OutputControls struct {
+	// MaxOutputTokens caps the length of the answer
+	MaxOutputTokens int32
+	// StopSequences end the answer when the model writes one of them
+	StopSequences []string
+	// FrequencyPenalty and PresencePenalty (-2 to 2) discourage repetition.
+	// Anthropic has no penalties and ignores them.
+	FrequencyPenalty float32
+	PresencePenalty  float32
+}
+
+func (c OutputControls) apply(prompt *ai.Prompt) {
+	if c.MaxOutputTokens > 0 {
+		prompt.MaxTokens = c.MaxOutputTokens
+	}
+	if len(c.StopSequences) > 0 {
+		prompt.StopSequences = c.StopSequences
+	}
+	if c.FrequencyPenalty != 0 {
+		prompt.FrequencyPenalty = c.FrequencyPenalty
+	}
+	if c.PresencePenalty != 0 {
+		prompt.PresencePenalty = c.PresencePenalty
+	}
+}
+
+// WithOutputControls overrides the persona's max output tokens, stop
+// sequences and penalties for this call.
+func WithOutputControls(controls OutputControls) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.outputControls = controls
+	}
+}
+
+// applyTurnOverrides points the prompt at the model and temperature chosen
+// for this call only, after the session's own model override.
+func (opts chatRequestOptions) applyTurnOverrides(prompt *ai.Prompt) {
+	if opts.model != "" {
+		prompt.ModelName = opts.model
+		if opts.provider != "" {
+			prompt.LLMProvider = opts.provider
+		}
+	}
+	if opts.temperature != nil {
+		prompt.Temperature = *opts.temperature
+	}
+}
+
+// WithModel runs this call on model, and provider when set, in place of the
+// session's model. The following calls go back to the session's model.
+func WithModel(provider, model string) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.provider = provider
+		opts.model = model
+	}
+}
+
+// WithTemperature overrides the persona's sampling temperature for this call.
+func WithTemperature(temperature float32) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.temperature = &temperature
+	}
+}
+
+// WithCandidates samples n answers for the turn in parallel, at most
+// MaxCandidates. The ChatResponseEvent lists them all in Candidates and the
+// first is recorded in history; replace the turn with ReplaceChatHistory to
+// keep another. Candidates are not streamed, and tools that modify files are
+// withheld from them as in read-only mode, so parallel runs cannot make
+// conflicting changes.
+func WithCandidates(n int) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.candidates = n
+	}
+}
+
+// WithRequestID sets the request ID used to correlate chat.chunk and
+// chat.response events with this call. A random ID is generated when unset.
+func WithRequestID(id string) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.requestID = id
+	}
+}
+
 // WithEphemeral sets the ephemeral mode for a chat turn, controlling what
 // gets stored in conversation history. See EphemeralMode constants.
 func WithEphemeral(mode EphemeralMode) ChatOption {
@@ -193,3 +317,21 @@ func WithEphemeral(mode EphemeralMode) ChatOption {
 		opts.ephemeral = mode
 	}
 }
+
+// WithResponseSchema asks for a final answer that is a JSON document matching
+// schema. The answer is validated and, when it does not match, the model is
+// asked to repair it a bounded number of times before the turn fails.
+func WithResponseSchema(schema *ai.Schema) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.responseSchema = schema
+	}
+}
+
+// WithPromptYAML runs the turn with the prompt defined by yamlContent, a
+// persona prompt.yaml, in place of the session persona's. The persona in
+// use does not change; genie playground uses it to try prompt revisions.
+func WithPromptYAML(yamlContent []byte) ChatOption {
+	return func(opts *chatRequestOptions) {
+		opts.promptYAML = yamlContent
+	}
+}
@@ -0,0 +1,136 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ctx"
+)
+
+// InstructionsContextKey is the part GetContext adds for the persona's
+// prompt. It is always sent, so it cannot be turned off.
+const InstructionsContextKey = "instructions"
+
+// ContextUsage is how much of the model's context window the context
+// parts take, part by part
+type ContextUsage struct {
+	Model         string
+	ContextWindow int                // Tokens the model accepts
+	Budget        int                // Tokens set aside for context parts; the rest is for instructions and the answer
+	Estimated     bool               // Counts are estimates because the backend could not count them
+	Parts         []ContextPartUsage // Largest first
+}
+
+// ContextPartUsage is the size of one context part
+type ContextPartUsage struct {
+	Key      string
+	Tokens   int
+	Budget   int  // Tokens the part may use before it is trimmed; 0 when it is never trimmed
+	Disabled bool // Left out of the prompt by the session
+}
+
+// TotalTokens returns the tokens the enabled parts take together
+func (u *ContextUsage) TotalTokens() int {
+	total := 0
+	for _, part := range u.Parts {
+		if !part.Disabled {
+			total += part.Tokens
+		}
+	}
+	return total
+}
+
+// WindowPercent returns how much of the context window the parts take
+func (u *ContextUsage) WindowPercent() float64 {
+	if u.ContextWindow <= 0 {
+		return 0
+	}
+	return float64(u.TotalTokens()) * 100 / float64(u.ContextWindow)
+}
+
+// NextToTrim returns the key of the budgeted part closest to its budget,
+// which is trimmed first as the conversation grows, or "" when no part
+// has a budget
+func (u *ContextUsage) NextToTrim() string {
+	next, fullest := "", -1.0
+	for _, part := range u.Parts {
+		if part.Budget <= 0 || part.Disabled {
+			continue
+		}
+		if full := float64(part.Tokens) / float64(part.Budget); full > fullest {
+			next, fullest = part.Key, full
+		}
+	}
+	return next
+}
+
+// GetContextUsage counts the tokens of each context part with the active
+// backend. When the backend cannot count, the parts are estimated instead.
+func (g *core) GetContextUsage(reqCtx context.Context) (*ContextUsage, error) {
+	if err := g.ensureStarted(); err != nil {
+		return nil, err
+	}
+	sess, err := g.sessionMgr.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	reqCtx = applySessionContext(reqCtx, sess)
+
+	parts, err := g.contextMgr.GetContextParts(reqCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.budgetMu.Lock()
+	usage := &ContextUsage{
+		Model:         g.contextModel,
+		ContextWindow: ctx.LookupContextWindow(g.contextModel),
+		Budget:        g.contextBudget,
+	}
+	g.budgetMu.Unlock()
+
+	budgets := g.contextMgr.PartBudgets()
+	disabled := sess.GetDisabledContextParts()
+	for key, content := range parts {
+		if content == "" {
+			continue
+		}
+		tokens, err := g.countPartTokens(reqCtx, content)
+		if err != nil {
+			slog.Debug("Token count failed, estimating", "part", key, "error", err)
+			tokens = ctx.EstimateTokens(content)
+			usage.Estimated = true
+		}
+		usage.Parts = append(usage.Parts, ContextPartUsage{
+			Key:      key,
+			Tokens:   tokens,
+			Budget:   budgets[key],
+			Disabled: slices.Contains(disabled, key),
+		})
+	}
+	sort.Slice(usage.Parts, func(i, j int) bool {
+		if usage.Parts[i].Tokens != usage.Parts[j].Tokens {
+			return usage.Parts[i].Tokens > usage.Parts[j].Tokens
+		}
+		return usage.Parts[i].Key < usage.Parts[j].Key
+	})
+	return usage, nil
+}
+
+// countPartTokens counts content with the backend. It is passed as data,
+// not as the prompt text, so template syntax in it is left alone.
+func (g *core) countPartTokens(reqCtx context.Context, content string) (int, error) {
+	prompt := &ai.Prompt{Name: "context-part", Text: "{{.part}}"}
+	count, err := g.promptRunner.CountTokens(reqCtx, prompt, map[string]string{"part": content}, g.eventBus)
+	if err != nil {
+		return 0, err
+	}
+	if count == nil {
+		return 0, fmt.Errorf("backend returned no token count")
+	}
+	return int(count.TotalTokens), nil
+}
@@ -0,0 +1,82 @@
+package genie_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContextUsageCountsEachPart(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage("summarize the repository layout", "cmd holds the binaries, pkg the libraries")
+	require.NoError(t, fixture.StartChat("summarize the repository layout"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	usage, err := fixture.Genie.GetContextUsage(context.Background())
+	require.NoError(t, err)
+
+	var chat *genie.ContextPartUsage
+	for i := range usage.Parts {
+		assert.NotEqual(t, "instructions", usage.Parts[i].Key)
+		if usage.Parts[i].Key == "chat" {
+			chat = &usage.Parts[i]
+		}
+	}
+	require.NotNil(t, chat, "the chat part must be counted")
+	assert.Positive(t, chat.Tokens)
+	assert.Positive(t, chat.Budget, "chat history is trimmed to a budget")
+	assert.Equal(t, "chat", usage.NextToTrim())
+	assert.False(t, usage.Estimated)
+}
+
+func TestContextUsageSummaries(t *testing.T) {
+	usage := &genie.ContextUsage{
+		ContextWindow: 1000,
+		Parts: []genie.ContextPartUsage{
+			{Key: "project", Tokens: 150},
+			{Key: "chat", Tokens: 100, Budget: 400},
+			{Key: "file", Tokens: 90, Budget: 100},
+		},
+	}
+
+	assert.Equal(t, 340, usage.TotalTokens())
+	assert.InDelta(t, 34.0, usage.WindowPercent(), 0.001)
+	assert.Equal(t, "file", usage.NextToTrim(), "the part closest to its budget is trimmed first")
+
+	assert.Empty(t, (&genie.ContextUsage{Parts: []genie.ContextPartUsage{{Key: "project", Tokens: 10}}}).NextToTrim())
+	assert.Zero(t, (&genie.ContextUsage{}).WindowPercent())
+}
+
+func TestDisabledContextPartsAreLeftOutOfTheNextTurn(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage("first question", "first answer")
+	require.NoError(t, fixture.StartChat("first question"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	session.SetContextPartEnabled("chat", false)
+
+	fixture.ExpectSimpleMessage("second question", "second answer")
+	require.NoError(t, fixture.StartChat("second question"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	captured := fixture.MockPromptRunner.CapturedData()
+	require.Len(t, captured, 2)
+	assert.Contains(t, captured[0], "message")
+	assert.NotContains(t, captured[1]["chat"], "first question", "a disabled part must not reach the model")
+
+	usage, err := fixture.Genie.GetContextUsage(context.Background())
+	require.NoError(t, err)
+	for _, part := range usage.Parts {
+		assert.Equal(t, part.Key == "chat", part.Disabled, part.Key)
+	}
+	assert.Empty(t, usage.NextToTrim(), "a disabled part is never trimmed")
+}
@@ -2,25 +2,34 @@ package genie
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"maps"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/ctx"
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/hooks"
 	"github.com/kcaldas/genie/pkg/persona"
+	"github.com/kcaldas/genie/pkg/scripting"
 	"github.com/kcaldas/genie/pkg/toolctx"
 	"github.com/kcaldas/genie/pkg/tools"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type requestIDContextKey struct{}
+// maxSchemaRepairAttempts bounds how many times a structured answer that
+// fails schema validation is sent back to the model for repair.
+const maxSchemaRepairAttempts = 2
 
 // PromptRunner executes prompts - allows mocking prompt execution for testing
 type PromptRunner interface {
@@ -107,7 +116,14 @@ type core struct {
 	configMgr       config.Manager
 	toolRegistry    tools.Registry
 	started         bool
-	missingTools    []string
+	toolIssues      []tools.ToolIssue
+	scripts         *scripting.Engine
+	hooks           *hooks.Config
+
+	// The model and budget the context was last sized for
+	budgetMu      sync.Mutex
+	contextModel  string
+	contextBudget int
 }
 
 // newGenieCore creates a new Genie core instance with dependency injection
@@ -165,9 +181,15 @@ func (g *core) Start(workingDir *string, persona *string, opts ...StartOption) (
 
 	// Initialize tool registry with the working directory
 	// This triggers MCP config discovery for this specific directory
+	if startOpts.untrusted {
+		if disabler, ok := g.toolRegistry.(interface{ DisableProjectConfig() }); ok {
+			disabler.DisableProjectConfig()
+		}
+	}
 	if err := g.toolRegistry.Init(actualWorkingDir); err != nil {
 		return nil, fmt.Errorf("failed to initialize tool registry: %w", err)
 	}
+	g.validateTools()
 
 	// Mark as started
 	g.started = true
@@ -181,10 +203,6 @@ func (g *core) Start(workingDir *string, persona *string, opts ...StartOption) (
 		if err := g.personaManager.SetInMemoryPersonaYAML(startOpts.personaYAML); err != nil {
 			return nil, fmt.Errorf("failed to set in-memory persona: %w", err)
 		}
-		// Capture missing tools from the loaded prompt
-		if prompt, err := g.personaManager.GetPrompt(context.Background()); err == nil && len(prompt.MissingTools) > 0 {
-			g.missingTools = append([]string(nil), prompt.MissingTools...)
-		}
 		// Create a placeholder persona for the session
 		actualPersona = &DefaultPersona{
 			ID:     "in-memory",
@@ -198,11 +216,17 @@ func (g *core) Start(workingDir *string, persona *string, opts ...StartOption) (
 			actualPersonaID = *persona
 		} else {
 			actualPersonaID = "genie" // default persona
+			if g.configMgr != nil {
+				actualPersonaID = g.configMgr.GetStringWithDefault("GENIE_PERSONA", actualPersonaID)
+			}
 		}
 
 		// Look up the persona object - use genie home dir for persona discovery
 		ctx := toolctx.WithGenieHome(context.Background(), genieHomeDir)
 		ctx = toolctx.WithWorkingDir(ctx, actualWorkingDir)
+		if startOpts.untrusted {
+			ctx = toolctx.WithWorkspaceTrusted(ctx, false)
+		}
 		personas, err := g.personaManager.ListPersonas(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list personas: %w", err)
@@ -250,6 +274,19 @@ func (g *core) Start(workingDir *string, persona *string, opts ...StartOption) (
 	if len(startOpts.readOnlyPaths) > 0 {
 		sess.SetReadOnlyPaths(startOpts.readOnlyPaths)
 	}
+	if len(startOpts.scope) > 0 {
+		scope, err := ResolveScope(actualWorkingDir, startOpts.scope)
+		if err != nil {
+			return nil, err
+		}
+		sess.SetScope(scope)
+	}
+	if startOpts.readOnlyMode {
+		sess.SetReadOnlyMode(true)
+	}
+	if startOpts.untrusted {
+		sess.SetWorkspaceTrusted(false)
+	}
 	if startOpts.commitAuthorName != "" || startOpts.commitAuthorEmail != "" {
 		sess.SetCommitAuthor(startOpts.commitAuthorName, startOpts.commitAuthorEmail)
 	}
@@ -259,10 +296,18 @@ func (g *core) Start(workingDir *string, persona *string, opts ...StartOption) (
 	}
 
 	g.configureDefaultTaskExecutor()
+	g.configureDefaultSubAgentRunner()
+	g.startHookScripts(genieHomeDir, actualWorkingDir, !startOpts.untrusted)
+	if err := g.loadLifecycleHooks(genieHomeDir, !startOpts.untrusted); err != nil {
+		return nil, err
+	}
 
 	// Set context budget based on resolved prompt (persona YAML model + budget override env var)
 	startCtx := toolctx.WithGenieHome(context.Background(), genieHomeDir)
 	startCtx = toolctx.WithWorkingDir(startCtx, actualWorkingDir)
+	if startOpts.untrusted {
+		startCtx = toolctx.WithWorkspaceTrusted(startCtx, false)
+	}
 	if actualPersona != nil {
 		startCtx = toolctx.WithPersona(startCtx, actualPersona.GetID())
 	}
@@ -282,13 +327,21 @@ func (g *core) initContextBudget(startCtx context.Context) {
 	var modelName string
 	var promptBudget int
 
-	// Resolve the prompt to get the actual model name from persona YAML
+	// Resolve the prompt to get the actual model name from persona YAML,
+	// unless the session has switched model at runtime
 	if g.personaManager != nil {
 		if prompt, err := g.personaManager.GetPrompt(startCtx); err == nil {
 			modelName = prompt.ModelName
 			promptBudget = prompt.ContextBudget
 		}
 	}
+	if g.sessionMgr != nil {
+		if sess, err := g.sessionMgr.GetSession(); err == nil {
+			if _, model := sess.GetModel(); model != "" {
+				modelName = model
+			}
+		}
+	}
 
 	// Priority: persona YAML context_budget → env var → model lookup
 	explicitBudget := promptBudget
@@ -315,6 +368,10 @@ func (g *core) initContextBudget(startCtx context.Context) {
 	budget := ctx.ContextBudget(explicitBudget, modelName, ratio)
 	g.contextMgr.SetContextBudget(budget)
 
+	g.budgetMu.Lock()
+	g.contextModel, g.contextBudget = modelName, budget
+	g.budgetMu.Unlock()
+
 	slog.Info("Context budget initialized",
 		"explicit_budget", explicitBudget,
 		"model", modelName,
@@ -332,16 +389,40 @@ func (g *core) RecalculateContextBudget(ctx context.Context) error {
 	return nil
 }
 
-func (g *core) MissingTools() []string {
-	return append([]string(nil), g.missingTools...)
+// GetChatHistory returns the exchanges the model currently remembers
+func (g *core) GetChatHistory() ([]ChatHistoryTurn, error) {
+	if err := g.ensureStarted(); err != nil {
+		return nil, err
+	}
+	history := g.contextMgr.ChatHistory()
+	turns := make([]ChatHistoryTurn, len(history))
+	for i, msg := range history {
+		turns[i] = ChatHistoryTurn{User: msg.User, Assistant: msg.Assistant}
+	}
+	return turns, nil
+}
+
+// ReplaceChatHistory makes turns the whole conversation the model
+// remembers, dropping everything recorded before
+func (g *core) ReplaceChatHistory(turns []ChatHistoryTurn) error {
+	if err := g.ensureStarted(); err != nil {
+		return err
+	}
+	if err := g.contextMgr.ClearContext(); err != nil {
+		return fmt.Errorf("failed to clear chat history: %w", err)
+	}
+	g.contextMgr.SeedChatHistory(toContextMessages(turns))
+	return nil
 }
 
 // Shutdown releases external resources owned by the tool registry:
-// background PTY/process sessions and MCP server subprocesses.
+// background PTY/process sessions and MCP server subprocesses. It also
+// stops the hook scripts.
 func (g *core) Shutdown() {
 	if g.toolRegistry != nil {
 		g.toolRegistry.Shutdown()
 	}
+	g.scripts.Close()
 }
 
 func (g *core) configureDefaultTaskExecutor() {
@@ -397,7 +478,14 @@ func (g *core) Chat(ctx context.Context, message string, opts ...ChatOption) err
 			}
 		}()
 
-		response, err := g.processChat(ctx, message, options)
+		// The pre_prompt hooks may block the message or rewrite what the
+		// model sees and remembers
+		prompt, err := g.runPrePromptHooks(ctx, message)
+		var result turnResult
+		if err == nil {
+			result, err = g.runTurn(ctx, prompt, options)
+		}
+		response := result.response
 
 		// Record the completed turn in conversation history BEFORE
 		// publishing the response event: history is correctness state
@@ -406,17 +494,18 @@ func (g *core) Chat(ctx context.Context, message string, opts ...ChatOption) err
 		// not recorded — a partial answer would corrupt every later
 		// turn's view of the conversation.
 		if err == nil {
-			g.recordChatTurn(message, response, options.ephemeral)
+			g.recordChatTurn(prompt, response, options.ephemeral)
 		}
 
 		// Publish response event (success or error) for observers
 		// (TUI rendering, CLI output). Purely notification.
 		responseEvent := events.ChatResponseEvent{
-			RequestID: options.requestID,
-			Message:   message,
-			Response:  response,
-			Error:     err,
-			Ephemeral: int(options.ephemeral),
+			RequestID:  options.requestID,
+			Message:    message,
+			Response:   response,
+			Candidates: result.candidates,
+			Error:      err,
+			Ephemeral:  int(options.ephemeral),
 		}
 		g.eventBus.Publish(responseEvent.Topic(), responseEvent)
 	}(chatOpts)
@@ -479,7 +568,7 @@ func (g *core) GetContext(ctx context.Context) (map[string]string, error) {
 	}
 
 	instructions := fmt.Sprintf("Total tokens count (After substitutions): %d\n\nText: %s\n\nInstructions: %s", tokenCount.TotalTokens, prompt.Text, prompt.Instruction)
-	contextMap["instructions"] = instructions
+	contextMap[InstructionsContextKey] = instructions
 
 	// Return structured context parts
 	return contextMap, nil
@@ -538,19 +627,24 @@ func (g *core) GetToolsRegistry() (tools.Registry, error) {
 }
 
 // processChat handles the actual chat processing logic
-func (g *core) processChat(ctx context.Context, message string, options chatRequestOptions) (string, error) {
+func (g *core) processChat(ctx context.Context, message string, options chatRequestOptions) (turnResult, error) {
 	// Get session (must exist since Start() creates initial session)
 	sess, err := g.sessionMgr.GetSession()
 	if err != nil {
-		return "", fmt.Errorf("session not found: %w - use session ID from Start() method", err)
+		return turnResult{}, fmt.Errorf("session not found: %w - use session ID from Start() method", err)
 	}
 
 	// Add session-derived context (cwd, sandbox dirs, policy, commit
 	// author, genie_home) for tool handlers and prompt composition.
 	ctx = applySessionContext(ctx, sess)
+	ctx = hooks.WithConfig(ctx, g.hooks)
 	if options.requestID != "" {
-		ctx = context.WithValue(ctx, requestIDContextKey{}, options.requestID)
+		ctx = toolctx.WithRequestID(ctx, options.requestID)
 	}
+	ctx = ai.WithRetryObserver(ctx, func(attempt int, err error) {
+		retryEvent := events.ModelRetryEvent{RequestID: options.requestID, Attempt: attempt, Error: err.Error()}
+		g.eventBus.Publish(retryEvent.Topic(), retryEvent)
+	})
 
 	// Create prompt context with structured context parts + message
 	promptData := g.preparePromptData(ctx, message)
@@ -560,6 +654,9 @@ func (g *core) processChat(ctx context.Context, message string, options chatRequ
 	// This keeps user-provided "files" or "project" via WithPromptData free to
 	// flow through the template as-is (test contract).
 	autoFilesContent, autoUserContext := buildSystemContext(promptData, options.systemPromptUserContext)
+	if memories := g.recallMemories(ctx, message, sess.GetWorkingDirectory()); memories != "" {
+		autoUserContext = strings.TrimSpace(autoUserContext + "\n\n" + memories)
+	}
 
 	for key, value := range options.promptData {
 		promptData[key] = value
@@ -567,12 +664,17 @@ func (g *core) processChat(ctx context.Context, message string, options chatRequ
 
 	// Require PersonaManager to be provided via dependency injection
 	if g.personaManager == nil {
-		return "", fmt.Errorf("no PersonaManager provided - prompt creation must be explicitly configured")
+		return turnResult{}, fmt.Errorf("no PersonaManager provided - prompt creation must be explicitly configured")
 	}
 
-	basePrompt, err := g.personaManager.GetPrompt(ctx)
+	var basePrompt *ai.Prompt
+	if options.promptYAML != nil {
+		basePrompt, err = g.personaManager.PromptFromYAML(ctx, options.promptYAML)
+	} else {
+		basePrompt, err = g.personaManager.GetPrompt(ctx)
+	}
 	if err != nil {
-		return "", err
+		return turnResult{}, err
 	}
 
 	// Shallow-clone so per-turn mutations (images) don't leak back into the
@@ -580,19 +682,68 @@ func (g *core) processChat(ctx context.Context, message string, options chatRequ
 	turnPrompt := *basePrompt
 	prompt := &turnPrompt
 	prompt.DisableCache = options.disableCache
+	prompt.ShowThoughts = options.showThoughts
+	options.outputControls.apply(prompt)
+	applyModelOverride(prompt, sess)
+	options.applyTurnOverrides(prompt)
+	rejected := dropRejectedTools(prompt, g.toolIssues)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("gen_ai.system", prompt.LLMProvider),
+		attribute.String("gen_ai.request.model", prompt.ModelName),
+	)
 
 	// Place the auto-loaded values extracted above onto the structured prompt
 	// fields. Anthropic emits each in its own system block with its own cache
 	// marker; other providers concat them onto the main system instruction.
 	prompt.SystemPromptFiles = autoFilesContent
 	prompt.SystemPromptUserContext = autoUserContext
+	// Parallel candidates could make conflicting changes, so they only read
+	if sess.GetReadOnlyMode() || options.candidates > 1 {
+		applyReadOnlyMode(prompt)
+	}
+	if scope := sess.GetScope(); len(scope) > 0 {
+		applyScope(prompt, scope)
+	}
+	applyUnavailableTools(prompt, rejected)
+	g.applyAudit(prompt, sess)
+
+	applyContextParts(prompt, promptData, options.contextParts)
 
 	if len(options.images) > 0 {
 		prompt.Images = mergePromptImages(basePrompt.Images, options.images)
 		promptData["image_count"] = strconv.Itoa(len(options.images))
 	}
 
+	if options.responseSchema != nil {
+		prompt.ResponseSchema = options.responseSchema
+	}
+	if options.candidates > 1 {
+		return g.runCandidates(ctx, prompt, promptData, options)
+	}
+	response, err := g.answerPrompt(ctx, prompt, promptData, options)
+	return turnResult{response: response}, err
+}
+
+// answerPrompt runs the turn's prompt and returns the formatted answer.
+func (g *core) answerPrompt(ctx context.Context, prompt *ai.Prompt, promptData map[string]string, options chatRequestOptions) (string, error) {
+	if prompt.ResponseSchema != nil {
+		return g.runStructuredPrompt(ctx, prompt, promptData, options)
+	}
+
+	response, err := g.runChatPrompt(ctx, prompt, promptData, options)
+	if err != nil {
+		return "", err
+	}
+
+	// Format tool outputs in the response for better user experience
+	formattedResponse := g.outputFormatter.FormatResponse(response)
+
+	return formattedResponse, nil
+}
+
+func (g *core) runChatPrompt(ctx context.Context, prompt *ai.Prompt, promptData map[string]string, options chatRequestOptions) (string, error) {
 	var response string
+	var err error
 	if options.stream {
 		response, err = g.promptRunner.RunPromptStream(ctx, prompt, promptData, g.eventBus)
 	} else {
@@ -601,11 +752,63 @@ func (g *core) processChat(ctx context.Context, message string, options chatRequ
 	if err != nil {
 		return "", fmt.Errorf("failed to execute chat prompt: %w", err)
 	}
+	return response, nil
+}
 
-	// Format tool outputs in the response for better user experience
-	formattedResponse := g.outputFormatter.FormatResponse(response)
+// runStructuredPrompt runs a prompt whose answer must match its
+// ResponseSchema. An answer that does not validate is sent back with the
+// problems found, up to maxSchemaRepairAttempts times; the validated JSON,
+// without any Markdown fence, is the turn's response.
+func (g *core) runStructuredPrompt(ctx context.Context, prompt *ai.Prompt, promptData map[string]string, options chatRequestOptions) (string, error) {
+	message := promptData["message"]
+	var validationErr error
+	for attempt := 0; attempt <= maxSchemaRepairAttempts; attempt++ {
+		response, err := g.runChatPrompt(ctx, prompt, promptData, options)
+		if err != nil {
+			return "", err
+		}
 
-	return formattedResponse, nil
+		answer := ai.ExtractJSON(response)
+		validationErr = prompt.ResponseSchema.ValidateJSON(answer)
+		if validationErr == nil {
+			return answer, nil
+		}
+		slog.Debug("Structured response failed validation", "attempt", attempt+1, "error", validationErr)
+
+		promptData["message"] = schemaRepairMessage(message, response, validationErr)
+	}
+	return "", fmt.Errorf("no valid response after %d repair attempts: %w", maxSchemaRepairAttempts, validationErr)
+}
+
+func schemaRepairMessage(message, answer string, validationErr error) string {
+	var builder strings.Builder
+	builder.WriteString(message)
+	builder.WriteString("\n\nYour previous answer did not match the required JSON schema:\n")
+	var schemaErr *ai.SchemaValidationError
+	if errors.As(validationErr, &schemaErr) {
+		for _, problem := range schemaErr.Problems {
+			fmt.Fprintf(&builder, "- %s\n", problem)
+		}
+	} else {
+		fmt.Fprintf(&builder, "- %v\n", validationErr)
+	}
+	builder.WriteString("\nPrevious answer:\n")
+	builder.WriteString(answer)
+	builder.WriteString("\n\nReply again with only the corrected JSON document.")
+	return builder.String()
+}
+
+// applyModelOverride points the prompt at the session's runtime model, set
+// with Session.SetModel, in place of the persona's model.
+func applyModelOverride(prompt *ai.Prompt, sess Session) {
+	provider, model := sess.GetModel()
+	if model == "" {
+		return
+	}
+	prompt.ModelName = model
+	if provider != "" {
+		prompt.LLMProvider = provider
+	}
 }
 
 func (g *core) preparePromptData(ctx context.Context, message string) map[string]string {
@@ -625,6 +828,13 @@ func (g *core) preparePromptData(ctx context.Context, message string) map[string
 		}
 		contextParts = make(map[string]string)
 	}
+	if g.sessionMgr != nil {
+		if sess, err := g.sessionMgr.GetSession(); err == nil {
+			for _, key := range sess.GetDisabledContextParts() {
+				delete(contextParts, key)
+			}
+		}
+	}
 
 	// Create prompt context with structured context parts + message
 	promptData := make(map[string]string)
@@ -650,6 +860,22 @@ func (g *core) preparePromptData(ctx context.Context, message string) map[string
 	return promptData
 }
 
+// applyContextParts adds per-request context parts to the template data.
+// Parts the prompt template does not reference are prepended to the
+// message under their name, in name order.
+func applyContextParts(prompt *ai.Prompt, promptData map[string]string, parts map[string]string) {
+	var unplaced []string
+	for _, name := range slices.Sorted(maps.Keys(parts)) {
+		promptData[name] = parts[name]
+		if !strings.Contains(prompt.Text, "."+name) {
+			unplaced = append(unplaced, fmt.Sprintf("## %s\n%s", name, parts[name]))
+		}
+	}
+	if len(unplaced) > 0 {
+		promptData["message"] = strings.Join(append(unplaced, promptData["message"]), "\n\n")
+	}
+}
+
 // recordChatTurn applies the turn's ephemeral mode and appends what
 // remains to conversation history.
 func (g *core) recordChatTurn(userMsg, assistantMsg string, mode EphemeralMode) {
@@ -667,15 +893,18 @@ func (g *core) recordChatTurn(userMsg, assistantMsg string, mode EphemeralMode)
 	g.contextMgr.RecordChatTurn(userMsg, assistantMsg)
 }
 
-// buildSystemContext lifts auto-loaded context parts (files, project,
-// active skill content) out of the template data and assembles them
-// for the prompt's structured system blocks, together with any
-// host-supplied user context. Lifted keys are removed from promptData
-// so they cannot double-render through the template.
+// buildSystemContext lifts auto-loaded context parts (pinned and read
+// files, project, active skill content) out of the template data and
+// assembles them for the prompt's structured system blocks, together
+// with any host-supplied user context. Lifted keys are removed from
+// promptData so they cannot double-render through the template.
 func buildSystemContext(promptData map[string]string, hostUserCtx string) (files string, userCtx string) {
-	files = strings.TrimSpace(promptData["files"])
+	// Pinned files come first: they change less often than the files the
+	// agent read, which keeps the start of the block cacheable
+	files = strings.TrimSpace(strings.TrimSpace(promptData["pinned_files"]) + "\n\n" + strings.TrimSpace(promptData["files"]))
 	project := strings.TrimSpace(promptData["project"])
 	skill := strings.TrimSpace(promptData["active_skill"])
+	delete(promptData, "pinned_files")
 	delete(promptData, "files")
 	delete(promptData, "project")
 	delete(promptData, "active_skill")
@@ -697,10 +926,8 @@ func requestIDFromContext(ctx context.Context) string {
 	if ctx == nil {
 		return ""
 	}
-	if value, ok := ctx.Value(requestIDContextKey{}).(string); ok {
-		return value
-	}
-	return ""
+	requestID, _ := toolctx.RequestID(ctx)
+	return requestID
 }
 
 // RequestIDFromContext returns the chat request ID attached to ctx by
@@ -713,7 +940,7 @@ func RequestIDFromContext(ctx context.Context) string {
 
 // applySessionContext attaches per-tool-call values from the session to
 // ctx via the pkg/toolctx contract: genie home, working dir, allowed
-// dirs, denied/read-only paths, persona, and the commit author
+// dirs, denied/read-only paths, scope, persona, and the commit author
 // identity. Optional values are only set when present so callers don't
 // see empty slices / strings when the session didn't configure them.
 func applySessionContext(ctx context.Context, sess Session) context.Context {
@@ -730,6 +957,15 @@ func applySessionContext(ctx context.Context, sess Session) context.Context {
 	if readOnly := sess.GetReadOnlyPaths(); len(readOnly) > 0 {
 		ctx = toolctx.WithReadOnlyPaths(ctx, readOnly)
 	}
+	if !sess.IsWorkspaceTrusted() {
+		ctx = toolctx.WithWorkspaceTrusted(ctx, false)
+	}
+	if pinned := sess.GetPinnedFiles(); len(pinned) > 0 {
+		ctx = toolctx.WithPinnedFiles(ctx, pinned)
+	}
+	if scope := sess.GetScope(); len(scope) > 0 {
+		ctx = toolctx.WithScope(ctx, scope)
+	}
 	if name, email := sess.GetCommitAuthor(); name != "" || email != "" {
 		if name != "" {
 			ctx = toolctx.WithCommitAuthorName(ctx, name)
@@ -743,5 +979,6 @@ func applySessionContext(ctx context.Context, sess Session) context.Context {
 		personaID = persona.GetID()
 	}
 	ctx = toolctx.WithPersona(ctx, personaID)
+	ctx = toolctx.WithApprovals(ctx, sess)
 	return ctx
 }
@@ -2,13 +2,21 @@ package genie_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie"
 	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/kcaldas/genie/pkg/hooks"
+	"github.com/kcaldas/genie/pkg/memory"
+	"github.com/kcaldas/genie/pkg/toolctx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -218,3 +226,394 @@ func TestStartWithChatHistorySeedsChatContext(t *testing.T) {
 	assert.Contains(t, contextMap["chat"], "User: Earlier question")
 	assert.Contains(t, contextMap["chat"], "Assistant: Earlier answer")
 }
+
+func TestReplaceChatHistory(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.StartAndGetSession(genie.WithChatHistory(genie.ChatHistoryTurn{User: "Earlier question", Assistant: "Earlier answer"}))
+
+	turns, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []genie.ChatHistoryTurn{{User: "Earlier question", Assistant: "Earlier answer"}}, turns)
+
+	require.NoError(t, fixture.Genie.ReplaceChatHistory([]genie.ChatHistoryTurn{{User: "Other question", Assistant: "Other answer"}}))
+	contextMap, err := fixture.Genie.GetContext(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, contextMap["chat"], "User: Other question")
+	assert.NotContains(t, contextMap["chat"], "Earlier question")
+
+	require.NoError(t, fixture.Genie.ReplaceChatHistory(nil))
+	turns, err = fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Empty(t, turns)
+}
+
+func TestChatUsesSessionModelOverride(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.UsePrompt(&ai.Prompt{Name: "test", ModelName: "gemini-2.5-flash", LLMProvider: "genai", Temperature: 0.2})
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("first", "ok")
+	fixture.ExpectSimpleMessage("second", "ok")
+
+	require.NoError(t, fixture.StartChat("first"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	session.SetModel("anthropic", "claude-sonnet-4-5")
+	require.NoError(t, fixture.StartChat("second"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 2)
+	assert.Equal(t, "gemini-2.5-flash", prompts[0].ModelName, "persona model is the default")
+	assert.Equal(t, "genai", prompts[0].LLMProvider)
+	assert.Equal(t, "claude-sonnet-4-5", prompts[1].ModelName)
+	assert.Equal(t, "anthropic", prompts[1].LLMProvider)
+	assert.Equal(t, float32(0.2), prompts[1].Temperature, "persona temperature is kept")
+}
+
+func TestChatWithOutputControlsOverridesPersona(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.UsePrompt(&ai.Prompt{Name: "test", MaxTokens: 8000, StopSequences: []string{"END"}, PresencePenalty: 0.5})
+	fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("first", "ok")
+	fixture.ExpectSimpleMessage("second", "ok")
+
+	require.NoError(t, fixture.Genie.Chat(context.Background(), "first", genie.WithOutputControls(genie.OutputControls{
+		MaxOutputTokens:  256,
+		StopSequences:    []string{"\n\n"},
+		FrequencyPenalty: 0.3,
+	})))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+	require.NoError(t, fixture.StartChat("second"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 2)
+	assert.Equal(t, int32(256), prompts[0].MaxTokens)
+	assert.Equal(t, []string{"\n\n"}, prompts[0].StopSequences)
+	assert.Equal(t, float32(0.3), prompts[0].FrequencyPenalty)
+	assert.Equal(t, float32(0.5), prompts[0].PresencePenalty, "unset controls keep the persona's")
+	assert.Equal(t, int32(8000), prompts[1].MaxTokens, "overrides last one request")
+	assert.Equal(t, []string{"END"}, prompts[1].StopSequences)
+}
+
+func TestChatWithModelAndTemperatureOverrideOneTurn(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.UsePrompt(&ai.Prompt{Name: "test", ModelName: "gemini-2.5-flash", LLMProvider: "genai", Temperature: 0.7})
+	session := fixture.StartAndGetSession()
+	session.SetModel("", "gemini-2.5-pro")
+	fixture.ExpectSimpleMessage("first", "ok")
+	fixture.ExpectSimpleMessage("second", "ok")
+
+	require.NoError(t, fixture.Genie.Chat(context.Background(), "first",
+		genie.WithModel("anthropic", "claude-sonnet-4-5"), genie.WithTemperature(0)))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+	require.NoError(t, fixture.StartChat("second"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 2)
+	assert.Equal(t, "claude-sonnet-4-5", prompts[0].ModelName)
+	assert.Equal(t, "anthropic", prompts[0].LLMProvider)
+	assert.Equal(t, float32(0), prompts[0].Temperature, "a zero temperature is an override too")
+	assert.Equal(t, "gemini-2.5-pro", prompts[1].ModelName, "the session's model is back on the next turn")
+	assert.Equal(t, float32(0.7), prompts[1].Temperature)
+}
+
+func TestChatWithPromptYAMLReplacesPersonaPromptForOneTurn(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("first", "ok")
+	fixture.ExpectSimpleMessage("second", "ok")
+
+	draft := []byte("name: draft\ntext: \"{{.message}}\"\ninstruction: You are the draft.\n")
+	require.NoError(t, fixture.Genie.Chat(context.Background(), "first", genie.WithPromptYAML(draft)))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+	require.NoError(t, fixture.StartChat("second"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 2)
+	assert.Equal(t, "draft", prompts[0].Name)
+	assert.Contains(t, prompts[0].Instruction, "You are the draft.")
+	assert.NotEqual(t, "draft", prompts[1].Name, "the persona's prompt is back on the next turn")
+}
+
+func TestChatWithCandidatesSamplesSeveralAnswers(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.UsePrompt(&ai.Prompt{
+		Name:      "test",
+		Functions: []*ai.FunctionDeclaration{{Name: "readFile"}, {Name: "writeFile"}},
+	})
+	fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("name it", "ok")
+
+	require.NoError(t, fixture.Genie.Chat(context.Background(), "name it", genie.WithStreaming(true), genie.WithCandidates(3)))
+	response := fixture.WaitForResponseOrFail(2 * time.Second)
+	require.NoError(t, response.Error)
+	assert.Equal(t, []string{"ok", "ok", "ok"}, response.Candidates)
+	assert.Equal(t, "ok", response.Response)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 3)
+	for _, prompt := range prompts {
+		require.Len(t, prompt.Functions, 1, "candidates only get tools that read")
+		assert.Equal(t, "readFile", prompt.Functions[0].Name)
+	}
+
+	turns, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []genie.ChatHistoryTurn{{User: "name it", Assistant: "ok"}}, turns)
+}
+
+func TestChatReadOnlyModeWithholdsMutatingTools(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	ran := map[string]int{}
+	handler := func(name string) ai.HandlerFunc {
+		return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+			ran[name]++
+			return map[string]any{"success": true}, nil
+		}
+	}
+	fixture.UsePrompt(&ai.Prompt{
+		Name: "test",
+		Functions: []*ai.FunctionDeclaration{
+			{Name: "readFile"}, {Name: "writeFile"}, {Name: "bash"},
+		},
+		Handlers: map[string]ai.HandlerFunc{
+			"readFile":  handler("readFile"),
+			"writeFile": handler("writeFile"),
+			"bash":      handler("bash"),
+		},
+	})
+	session := fixture.StartAndGetSession(genie.WithReadOnlyMode())
+	fixture.ExpectSimpleMessage("explore", "ok")
+	fixture.ExpectSimpleMessage("apply", "ok")
+
+	require.NoError(t, fixture.StartChat("explore"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+	session.SetReadOnlyMode(false)
+	require.NoError(t, fixture.StartChat("apply"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 2)
+
+	planPrompt := prompts[0]
+	var names []string
+	for _, fn := range planPrompt.Functions {
+		names = append(names, fn.Name)
+	}
+	assert.Equal(t, []string{"readFile", "bash"}, names)
+	assert.NotContains(t, planPrompt.Handlers, "writeFile")
+	assert.Contains(t, planPrompt.SystemPromptUserContext, "read-only (plan) mode")
+
+	result, err := planPrompt.Handlers["bash"](context.Background(), map[string]any{"command": "rm -rf build"})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	_, err = planPrompt.Handlers["bash"](context.Background(), map[string]any{"command": "git status | head"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran["bash"], "only the read-only command runs")
+
+	assert.Len(t, prompts[1].Functions, 3, "act mode restores every tool")
+	assert.NotContains(t, prompts[1].SystemPromptUserContext, "plan) mode")
+}
+
+func TestChatRecordsToolCallsInAuditLog(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.UsePrompt(&ai.Prompt{
+		Name:      "test",
+		Functions: []*ai.FunctionDeclaration{{Name: "bash"}},
+		Handlers: map[string]ai.HandlerFunc{
+			"bash": func(ctx context.Context, params map[string]any) (map[string]any, error) {
+				if observe, ok := toolctx.ConfirmationObserver(ctx); ok {
+					observe(true)
+				}
+				return map[string]any{"success": false, "results": "boom", "error": "command failed", "exit_code": 2}, nil
+			},
+		},
+	})
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("run it", "ok")
+	require.NoError(t, fixture.StartChat("run it"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 1)
+	_, err := prompts[0].Handlers["bash"](context.Background(), map[string]any{"command": "make test", "_display_message": "Running tests"})
+	require.NoError(t, err)
+
+	entries, err := audit.Read(audit.Path(session.GetGenieHomeDirectory(), session.GetID()))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "bash", entry.Tool)
+	assert.Equal(t, session.GetID(), entry.SessionID)
+	assert.Equal(t, map[string]any{"command": "make test"}, entry.Params)
+	assert.Equal(t, audit.ConfirmationApproved, entry.Confirmation)
+	assert.Equal(t, audit.StatusFailed, entry.Status)
+	require.NotNil(t, entry.ExitCode)
+	assert.Equal(t, 2, *entry.ExitCode)
+	assert.Equal(t, "command failed", entry.Error)
+	assert.Positive(t, entry.OutputBytes)
+}
+
+func TestChatRedactsCredentialsInAuditLog(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	var sent map[string]any
+	fixture.UsePrompt(&ai.Prompt{
+		Name:      "test",
+		Functions: []*ai.FunctionDeclaration{{Name: "httpRequest"}},
+		Handlers: map[string]ai.HandlerFunc{
+			"httpRequest": func(ctx context.Context, params map[string]any) (map[string]any, error) {
+				sent = params
+				return map[string]any{"success": true}, nil
+			},
+		},
+	})
+	session := fixture.StartAndGetSession()
+	fixture.ExpectSimpleMessage("call it", "ok")
+	require.NoError(t, fixture.StartChat("call it"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 1)
+	_, err := prompts[0].Handlers["httpRequest"](context.Background(), map[string]any{
+		"url":     "http://localhost:8080",
+		"headers": map[string]any{"Authorization": "Bearer s3cret"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"Authorization": "Bearer s3cret"}, sent["headers"])
+
+	entries, err := audit.Read(audit.Path(session.GetGenieHomeDirectory(), session.GetID()))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{"Authorization": "Bearer [REDACTED]"}, entries[0].Params["headers"])
+}
+
+func TestChatReportsTurnTimeout(t *testing.T) {
+	t.Setenv(genie.TurnTimeoutConfigKey, "50ms")
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+	fixture.ExpectMessage("slow question").RespondWith("too late").WithDelay(time.Second)
+
+	require.NoError(t, fixture.StartChat("slow question"))
+	response := fixture.WaitForResponseOrFail(2 * time.Second)
+
+	require.Error(t, response.Error)
+	assert.ErrorIs(t, response.Error, genie.ErrTurnTimeout)
+	assert.Contains(t, response.Error.Error(), genie.TurnTimeoutConfigKey)
+	assert.Empty(t, response.Response)
+}
+
+func TestChatWithContextPart(t *testing.T) {
+	chat := func(t *testing.T, prompt *ai.Prompt) map[string]string {
+		fixture := genietest.NewTestFixture(t)
+		defer fixture.Cleanup()
+		fixture.UsePrompt(prompt)
+		fixture.StartAndGetSession()
+
+		responseChan := make(chan events.ChatResponseEvent, 1)
+		fixture.EventBus.Subscribe("chat.response", func(evt interface{}) {
+			if resp, ok := evt.(events.ChatResponseEvent); ok {
+				responseChan <- resp
+			}
+		})
+
+		err := fixture.Genie.Chat(context.Background(), "why is this failing?", genie.WithContextPart("stdin", "panic: nil map"))
+		require.NoError(t, err)
+		select {
+		case <-responseChan:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for chat response")
+		}
+
+		captures := fixture.MockPromptRunner.CapturedData()
+		require.NotEmpty(t, captures)
+		return captures[len(captures)-1]
+	}
+
+	t.Run("template places the part", func(t *testing.T) {
+		data := chat(t, &ai.Prompt{Name: "test", Text: "{{.stdin}}\n{{.message}}"})
+		assert.Equal(t, "panic: nil map", data["stdin"])
+		assert.Equal(t, "why is this failing?", data["message"])
+	})
+
+	t.Run("part precedes the message otherwise", func(t *testing.T) {
+		data := chat(t, &ai.Prompt{Name: "test", Text: "{{.message}}"})
+		assert.Equal(t, "## stdin\npanic: nil map\n\nwhy is this failing?", data["message"])
+	})
+}
+
+func TestChatRecallsRelatedMemories(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+	session := fixture.StartAndGetSession()
+
+	store, err := memory.Open(config.NewConfigManager())
+	require.NoError(t, err)
+	_, err = store.Add(context.Background(), "The staging database listens on port 5433", session.GetWorkingDirectory())
+	require.NoError(t, err)
+	_, err = store.Add(context.Background(), "Releases are cut on Thursdays", memory.GlobalScope)
+	require.NoError(t, err)
+
+	fixture.ExpectSimpleMessage("connect to the staging database", "ok")
+	require.NoError(t, fixture.StartChat("connect to the staging database"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0].SystemPromptUserContext, "- The staging database listens on port 5433")
+	assert.NotContains(t, prompts[0].SystemPromptUserContext, "Thursdays")
+}
+
+func TestChatRunsPrePromptHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks are POSIX shell commands")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".genie"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".genie", hooks.ConfigFileName), []byte(`
+pre_prompt:
+  - command: "grep -q password && { echo 'no passwords, please' >&2; exit 2; }; exit 0"
+  - command: "grep -q '\"prompt\":\"hello\"' && echo '{\"prompt\": \"hello, briefly\"}'; exit 0"
+`), 0o644))
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+	fixture.StartAndGetSession()
+
+	fixture.ExpectSimpleMessage("hello, briefly", "hi")
+	require.NoError(t, fixture.StartChat("hello"))
+	response := fixture.WaitForResponseOrFail(2 * time.Second)
+	require.NoError(t, response.Error)
+	assert.Equal(t, "hi", response.Response)
+
+	require.NoError(t, fixture.StartChat("my password is hunter2"))
+	response = fixture.WaitForResponseOrFail(2 * time.Second)
+	require.Error(t, response.Error)
+	assert.True(t, hooks.IsBlocked(response.Error))
+	assert.Contains(t, response.Error.Error(), "no passwords, please")
+
+	turns, err := fixture.Genie.GetChatHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []genie.ChatHistoryTurn{{User: "hello, briefly", Assistant: "hi"}}, turns)
+}
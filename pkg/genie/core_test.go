@@ -29,10 +29,20 @@ func (m *MockContextManager) SeedChatHistory(history []ctx.Message) {
 	m.Called(history)
 }
 
+func (m *MockContextManager) ChatHistory() []ctx.Message {
+	args := m.Called()
+	history, _ := args.Get(0).([]ctx.Message)
+	return history
+}
+
 func (m *MockContextManager) RecordChatTurn(user, assistant string) {
 	m.Called(user, assistant)
 }
 
+func (m *MockContextManager) PartBudgets() map[string]int {
+	return nil
+}
+
 func (m *MockContextManager) SetContextBudget(totalTokens int) {
 	m.Called(totalTokens)
 }
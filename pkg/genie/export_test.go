@@ -27,6 +27,30 @@ func NewChildGenieForTest(g Genie) (Genie, events.EventBus, error) {
 	if !ok {
 		return nil, nil, fmt.Errorf("NewChildGenieForTest: %T is not a core genie", g)
 	}
-	executor := newNativeTaskExecutor(parent).(*nativeTaskExecutor)
-	return executor.newChildGenie()
+	return newChildGenie(parent, nil)
+}
+
+// AgentPlanPromptForTest exposes agentPlanPrompt.
+func AgentPlanPromptForTest(task string) string {
+	return agentPlanPrompt(task)
+}
+
+// AgentActPromptForTest exposes agentActPrompt.
+func AgentActPromptForTest(iteration int) string {
+	return agentActPrompt(iteration)
+}
+
+// AgentReflectPromptForTest exposes agentReflectPrompt.
+func AgentReflectPromptForTest() string {
+	return agentReflectPrompt()
+}
+
+// SubAgentPromptForTest exposes subAgentPrompt.
+func SubAgentPromptForTest(task string) string {
+	return subAgentPrompt(task)
+}
+
+// SchemaRepairMessageForTest exposes schemaRepairMessage.
+func SchemaRepairMessageForTest(message, answer string, validationErr error) string {
+	return schemaRepairMessage(message, answer, validationErr)
 }
@@ -19,6 +19,14 @@ type Genie interface {
 	// Context management - returns structured context parts by key
 	GetContext(ctx context.Context) (map[string]string, error)
 
+	// GetContextUsage counts the tokens of each context part against the model's window
+	GetContextUsage(ctx context.Context) (*ContextUsage, error)
+
+	// Pinned files - sent with every turn until unpinned; paths or globs
+	PinFiles(ctx context.Context, patterns ...string) ([]string, error)
+	UnpinFiles(patterns ...string) ([]string, error)
+	GetPinnedFiles() ([]string, error)
+
 	// Status - returns the current status of the AI backend
 	GetStatus() *Status
 
@@ -34,13 +42,35 @@ type Genie interface {
 	// GetToolsRegistry returns the tool registry for dynamic tool introspection
 	GetToolsRegistry() (tools.Registry, error)
 
+	// ChangeWorkingDirectory moves the session to dir for the following
+	// turns: tools work there, and its project context, personas and
+	// memories apply. trusted says whether dir's project-local
+	// configuration may load.
+	ChangeWorkingDirectory(dir string, trusted bool) (Session, error)
+
 	// RecalculateContextBudget recalculates the context token budget.
 	// Call after persona swap to pick up the new model's context window.
 	RecalculateContextBudget(ctx context.Context) error
 
-	// MissingTools returns tools that were listed as required but were not
-	// available in the registry at startup (e.g. MCP servers that failed to connect).
-	MissingTools() []string
+	// GetChatHistory returns the conversation the model remembers, oldest
+	// turn first. ReplaceChatHistory swaps it for turns, so a saved
+	// conversation can be resumed.
+	GetChatHistory() ([]ChatHistoryTurn, error)
+	ReplaceChatHistory(turns []ChatHistoryTurn) error
+
+	// MissingTools returns the tools the current persona requires that
+	// this session cannot offer, e.g. an MCP server that failed to connect
+	// or a binary that is not installed. The session runs without them.
+	MissingTools() []MissingTool
+
+	// ToolIssues returns the problems found in the registered tools'
+	// declarations at startup. Tools with issues fatal to the turn's
+	// provider are not sent to it.
+	ToolIssues() []tools.ToolIssue
+
+	// ToolConflicts returns the tool names offered by more than one source,
+	// such as a built-in tool and an MCP server, and how each was settled
+	ToolConflicts() []tools.ToolConflict
 
 	// Shutdown releases external resources: background PTY/process
 	// sessions and MCP server subprocesses. Call once when the host
@@ -48,6 +78,12 @@ type Genie interface {
 	Shutdown()
 }
 
+// MissingTool is a required tool, or @toolset, the session runs without
+type MissingTool struct {
+	Name   string
+	Reason string // Why it is unavailable; empty when unknown
+}
+
 // Persona represents a discovered persona
 type Persona interface {
 	GetID() string
@@ -67,9 +103,26 @@ type Session interface {
 	GetCreatedAt() string
 	GetPersona() Persona
 	SetPersona(persona Persona)
+	GetModel() (provider, model string) // Runtime model override; empty uses the persona's model
+	SetModel(provider, model string)
+	GetReadOnlyMode() bool // Plan-only mode: mutating tools are withheld from the model
+	SetReadOnlyMode(enabled bool)
+	GetDisabledContextParts() []string // Context part keys left out of the prompt, sorted
+	SetContextPartEnabled(key string, enabled bool)
+	GetPinnedFiles() []string // Absolute paths of the files sent with every turn
+	SetPinnedFiles(paths []string)
+	GetScope() []string // Working-directory-relative dirs file tools are confined to; empty for the whole workspace
+	SetScope(dirs []string)
+	IsWorkspaceTrusted() bool // False when project-local personas, skills and MCP config are ignored
+	SetWorkspaceTrusted(trusted bool)
+	IsToolApproved(toolName, command string) bool // Approved for the rest of the session: no confirmation needed
+	ApproveTool(toolName, command string)
+	GetToolApprovals() []ToolApproval
 	SetDeniedPaths(patterns []string)
 	SetReadOnlyPaths(patterns []string)
 	SetCommitAuthor(name, email string)
+	SetWorkingDirectory(dir string)
+	SetGenieHomeDirectory(dir string)
 }
 
 // SessionManager manages multiple sessions
@@ -82,6 +82,7 @@ func NewTestFixture(t *testing.T, opts ...TestFixtureOption) *TestFixture {
 	registry := ctx.NewContextPartProviderRegistry()
 	registry.Register(projectCtxMgr, 0)
 	registry.Register(chatCtxMgr, 0.7)
+	registry.Register(ctx.NewPinnedFilesContextPartProvider(), 0)
 	contextMgr := ctx.NewContextManager(registry)
 
 	// Create mock LLM with sensible defaults
@@ -179,6 +180,17 @@ func WithRealPromptProcessing() TestFixtureOption {
 	}
 }
 
+// WithReplay serves the model's responses from a capture file, through
+// real prompt processing, so a recorded session runs offline and always
+// the same way. Set replay.RunTools to also repeat its tool calls.
+func WithReplay(replay *ai.ReplayGen) TestFixtureOption {
+	return func(f *TestFixture) {
+		f.promptRunner = genie.NewDefaultPromptRunner(replay, false)
+		f.rebuildGenie()
+		f.MockPromptRunner = nil
+	}
+}
+
 // rebuildGenie reassembles the Genie from the fixture's current components.
 func (f *TestFixture) rebuildGenie() {
 	f.Genie = genie.NewGenieWithComponents(
@@ -6,6 +6,8 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/events"
@@ -22,11 +24,14 @@ type MockResponse struct {
 	Message   string
 	Response  string
 	ToolCalls []MockToolCall
+	Delay     time.Duration
 }
 
 type MockPromptRunner struct {
-	responses       map[string]*MockResponse
-	eventBus        events.EventBus
+	responses map[string]*MockResponse
+	eventBus  events.EventBus
+
+	mu              sync.Mutex // guards the captures; turns may run concurrently
 	capturedPrompts []*ai.Prompt
 	capturedData    []map[string]string
 }
@@ -78,6 +83,13 @@ func (b *MockResponseBuilder) RespondWith(response string) *MockResponseBuilder
 	return b
 }
 
+// WithDelay makes the response take d, or fail with the context's error if
+// the request ends first.
+func (b *MockResponseBuilder) WithDelay(d time.Duration) *MockResponseBuilder {
+	b.response.Delay = d
+	return b
+}
+
 type MockToolBuilder struct {
 	builder  *MockResponseBuilder
 	toolName string
@@ -93,6 +105,7 @@ func (t *MockToolBuilder) Returns(result map[string]any) *MockResponseBuilder {
 }
 
 func (r *MockPromptRunner) RunPrompt(ctx context.Context, prompt *ai.Prompt, data map[string]string, eventBus events.EventBus) (string, error) {
+	r.mu.Lock()
 	if prompt != nil {
 		copyPrompt := *prompt
 		r.capturedPrompts = append(r.capturedPrompts, &copyPrompt)
@@ -101,6 +114,7 @@ func (r *MockPromptRunner) RunPrompt(ctx context.Context, prompt *ai.Prompt, dat
 		dataCopy := maps.Clone(data)
 		r.capturedData = append(r.capturedData, dataCopy)
 	}
+	r.mu.Unlock()
 
 	// Get the message from the prompt context
 	message, exists := data["message"]
@@ -119,6 +133,14 @@ func (r *MockPromptRunner) RunPrompt(ctx context.Context, prompt *ai.Prompt, dat
 		return "", fmt.Errorf("no response configured for message %q - use RespondWith() or ExpectSimpleMessage()", message)
 	}
 
+	if mockResponse.Delay > 0 {
+		select {
+		case <-time.After(mockResponse.Delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
 	// Execute any mocked tool calls and publish events
 	for _, toolCall := range mockResponse.ToolCalls {
 		err := r.executeMockToolCall(ctx, data, toolCall)
@@ -172,11 +194,15 @@ func (r *MockPromptRunner) CountTokens(ctx context.Context, prompt *ai.Prompt, d
 
 // CapturedPrompts returns copies of the prompts captured during RunPrompt invocations.
 func (r *MockPromptRunner) CapturedPrompts() []*ai.Prompt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return append([]*ai.Prompt(nil), r.capturedPrompts...)
 }
 
 // CapturedData returns copies of the prompt data arguments captured during RunPrompt invocations.
 func (r *MockPromptRunner) CapturedData() []map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	copies := make([]map[string]string, 0, len(r.capturedData))
 	for _, data := range r.capturedData {
 		copies = append(copies, maps.Clone(data))
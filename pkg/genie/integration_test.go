@@ -1,11 +1,16 @@
 package genie_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntegrationBasic(t *testing.T) {
@@ -48,3 +53,41 @@ func TestRealPromptProcessing(t *testing.T) {
 		t.Errorf("Expected 'Echo: test', got %q", response.Response)
 	}
 }
+
+func TestReplayedSessionRunsOffline(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "capture.json")
+	require.NoError(t, ai.SaveInteractionsToFile([]ai.Interaction{{
+		ID:       "interaction_1",
+		Prompt:   ai.CapturedPrompt{Name: "Genie"},
+		Response: "The project has a README.",
+		ToolCalls: []ai.CapturedToolCall{{
+			Name:   "listFiles",
+			Args:   map[string]any{"path": ".", "_display_message": "Listing files"},
+			Result: map[string]any{"success": true, "results": "README.md"},
+		}},
+	}}, file))
+	replay, err := ai.NewReplayGen(file)
+	require.NoError(t, err)
+	replay.RunTools(true)
+
+	fixture := genietest.NewTestFixture(t, genietest.WithReplay(replay))
+	require.NoError(t, os.WriteFile(filepath.Join(fixture.TestDir, "README.md"), []byte("# test\n"), 0o644))
+	executed := make(chan events.ToolExecutedEvent, 1)
+	events.SubscribeTo(fixture.EventBus, func(e events.ToolExecutedEvent) {
+		executed <- e
+	})
+
+	fixture.StartAndGetSession()
+	require.NoError(t, fixture.StartChat("what is in this project?"))
+
+	response := fixture.WaitForResponseOrFail(2 * time.Second)
+	assert.Equal(t, "The project has a README.", response.Response)
+	select {
+	case e := <-executed:
+		assert.Equal(t, "listFiles", e.ToolName)
+		assert.True(t, e.Success, e.Message)
+		assert.Contains(t, e.Result["results"], "README.md")
+	case <-time.After(2 * time.Second):
+		t.Fatal("the recorded tool call was not replayed")
+	}
+}
@@ -0,0 +1,46 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/hooks"
+)
+
+// loadLifecycleHooks reads the hooks.yaml files of the user and, in a
+// trusted workspace, of the project. Hooks that fail without blocking are
+// reported in the transcript.
+func (g *core) loadLifecycleHooks(genieHome string, trusted bool) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+	config, err := hooks.LoadConfig(genieHome, home, !trusted)
+	if err != nil {
+		return fmt.Errorf("failed to load hooks: %w", err)
+	}
+	if config != nil {
+		config.Warn = func(err error) {
+			slog.Warn("Hook failed", "error", err)
+			notification := events.NotificationEvent{Message: err.Error(), Role: "error", Error: err}
+			g.eventBus.Publish(notification.Topic(), notification)
+		}
+	}
+	g.hooks = config
+	return nil
+}
+
+// runPrePromptHooks returns the message the pre_prompt hooks let through,
+// possibly rewritten, or the error of the hook that blocked it.
+func (g *core) runPrePromptHooks(ctx context.Context, message string) (string, error) {
+	if g.hooks == nil {
+		return message, nil
+	}
+	if sess, err := g.sessionMgr.GetSession(); err == nil {
+		ctx = applySessionContext(ctx, sess)
+	}
+	return g.hooks.RunPrePrompt(ctx, message)
+}
@@ -0,0 +1,31 @@
+package genie
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kcaldas/genie/pkg/memory"
+)
+
+// recallLimit caps the memories recalled into one turn
+const recallLimit = 5
+
+// recallMemories returns the long-term memories related to message that
+// apply in workspace, formatted for the system context, or "" when there
+// are none or recall is off. Failing to recall never fails the turn.
+func (g *core) recallMemories(ctx context.Context, message, workspace string) string {
+	if g.configMgr == nil || !g.configMgr.GetBoolWithDefault(memory.ConfigKey, true) {
+		return ""
+	}
+	store, err := memory.Open(g.configMgr)
+	if err != nil {
+		slog.Warn("Long-term memory unavailable", "error", err)
+		return ""
+	}
+	matches, err := store.Recall(ctx, message, workspace, recallLimit)
+	if err != nil {
+		slog.Warn("Failed to recall memories, continuing without them", "error", err)
+		return ""
+	}
+	return memory.FormatRecall(matches)
+}
@@ -1,7 +1,9 @@
 package genie
 
 import (
+	"github.com/kcaldas/genie/pkg/ai/middleware"
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/plugin"
 	"github.com/kcaldas/genie/pkg/tools"
 )
 
@@ -25,6 +27,15 @@ type GenieOptions struct {
 
 	// TaskCompletionHandler observes terminal async Task results.
 	TaskCompletionHandler tools.TaskCompletionHandler
+
+	// AIMiddleware intercepts every LLM call, outside the middlewares
+	// chained with GENIE_AI_MIDDLEWARE.
+	AIMiddleware []middleware.Middleware
+
+	// Plugins extend this instance, after the plugins registered with
+	// plugin.Register. Their tools are ignored if CustomRegistry or
+	// CustomRegistryFactory is set.
+	Plugins []plugin.Plugin
 }
 
 // GenieOption is a function that configures GenieOptions
@@ -92,6 +103,45 @@ func WithTaskCompletionHandler(handler tools.TaskCompletionHandler) GenieOption
 	}
 }
 
+// WithAIMiddleware wraps the LLM client with request/response
+// interceptors, e.g. to redact data before it is sent. The first
+// middleware sees each request first.
+//
+// Example:
+//
+//	genie, err := genie.NewGenie(genie.WithAIMiddleware(middleware.Funcs{
+//	    BeforeFunc: func(ctx context.Context, req *middleware.Request) error {
+//	        req.Prompt.Text = scrub(req.Prompt.Text)
+//	        return nil
+//	    },
+//	}))
+func WithAIMiddleware(middlewares ...middleware.Middleware) GenieOption {
+	return func(opts *GenieOptions) {
+		opts.AIMiddleware = append(opts.AIMiddleware, middlewares...)
+	}
+}
+
+// WithPlugins extends Genie with plugins: their tools, context parts and
+// event subscribers. See package plugin.
+//
+// Example:
+//
+//	g, err := genie.NewGenie(genie.WithPlugins(myPlugin))
+func WithPlugins(plugins ...plugin.Plugin) GenieOption {
+	return func(opts *GenieOptions) {
+		opts.Plugins = append(opts.Plugins, plugins...)
+	}
+}
+
+// plugins returns the registered plugins followed by the instance's own.
+func (o *GenieOptions) plugins() []plugin.Plugin {
+	all := plugin.Registered()
+	if o != nil {
+		all = append(all, o.Plugins...)
+	}
+	return all
+}
+
 // applyOptions applies all options to create a final GenieOptions
 func applyOptions(opts ...GenieOption) *GenieOptions {
 	options := &GenieOptions{}
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ctx"
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/plugin"
 	"github.com/kcaldas/genie/pkg/tools"
 	"github.com/stretchr/testify/require"
 )
@@ -328,3 +330,44 @@ func TestApplyOptions_Empty(t *testing.T) {
 	require.Nil(t, opts.CustomRegistryFactory)
 	require.Nil(t, opts.CustomTools)
 }
+
+type testPlugin struct {
+	tool    tools.Tool
+	part    ctx.ContextPartProvider
+	subsBus events.EventBus
+}
+
+func (p *testPlugin) Name() string        { return "test" }
+func (p *testPlugin) Tools() []tools.Tool { return []tools.Tool{p.tool} }
+func (p *testPlugin) ContextParts() []ctx.ContextPartProvider {
+	return []ctx.ContextPartProvider{p.part}
+}
+func (p *testPlugin) Subscribe(bus events.EventBus) { p.subsBus = bus }
+
+func TestWithPlugins(t *testing.T) {
+	p := &testPlugin{tool: newMockTool("plugin_tool"), part: ctx.NewPinnedFilesContextPartProvider()}
+	opts := applyOptions(WithPlugins(p))
+	require.Equal(t, []plugin.Plugin{p}, opts.Plugins)
+
+	eventBus := events.NewEventBus()
+	registry, err := newRegistryWithOptions(eventBus, tools.NewTodoManager(), nil, nil, opts) // nil skillManager for tests
+	require.NoError(t, err)
+	retrievedTool, exists := registry.Get("plugin_tool")
+	require.True(t, exists)
+	require.Equal(t, p.tool, retrievedTool)
+
+	contextRegistry := provideWatchedContextRegistry(eventBus, nil, opts)
+	require.Contains(t, contextRegistry.GetProviders(), p.part)
+
+	g, err := NewGenie(WithPlugins(p))
+	require.NoError(t, err)
+	require.Equal(t, g.GetEventBus(), p.subsBus)
+}
+
+func TestWithPlugins_DuplicateToolName(t *testing.T) {
+	opts := applyOptions(WithPlugins(&testPlugin{tool: newMockTool("readFile")}))
+
+	_, err := newRegistryWithOptions(events.NewEventBus(), tools.NewTodoManager(), nil, nil, opts) // nil skillManager for tests
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "plugin test")
+}
@@ -0,0 +1,185 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// MaxPinnedFiles is how many files may be pinned into a session at once
+const MaxPinnedFiles = 50
+
+// PinFiles pins the files matching each pattern into the session, so they
+// are sent with every turn until unpinned. A pattern is a path or a glob
+// relative to the working directory, where `**` crosses directories. It
+// returns the files newly pinned, relative to the working directory.
+func (g *core) PinFiles(reqCtx context.Context, patterns ...string) ([]string, error) {
+	if err := g.ensureStarted(); err != nil {
+		return nil, err
+	}
+	sess, err := g.sessionMgr.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	reqCtx = applySessionContext(reqCtx, sess)
+
+	pinned := sess.GetPinnedFiles()
+	var added []string
+	for _, pattern := range patterns {
+		paths, err := expandPinPattern(reqCtx, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			if slices.Contains(pinned, path) {
+				continue
+			}
+			if len(pinned) >= MaxPinnedFiles {
+				return nil, fmt.Errorf("cannot pin more than %d files; unpin some first", MaxPinnedFiles)
+			}
+			pinned = append(pinned, path)
+			added = append(added, pinnedDisplayPath(sess, path))
+		}
+	}
+	sess.SetPinnedFiles(pinned)
+	return added, nil
+}
+
+// UnpinFiles stops sending the pinned files matching each pattern. It
+// returns the files unpinned, relative to the working directory.
+func (g *core) UnpinFiles(patterns ...string) ([]string, error) {
+	if err := g.ensureStarted(); err != nil {
+		return nil, err
+	}
+	sess, err := g.sessionMgr.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	pinned := sess.GetPinnedFiles()
+	var removed []string
+	for _, pattern := range patterns {
+		before := len(removed)
+		pinned = slices.DeleteFunc(pinned, func(path string) bool {
+			name := pinnedDisplayPath(sess, path)
+			if pattern == path || pattern == name || tools.MatchGlob(pattern, name) {
+				removed = append(removed, name)
+				return true
+			}
+			return false
+		})
+		if len(removed) == before {
+			return nil, fmt.Errorf("no pinned file matches %s", pattern)
+		}
+	}
+	sess.SetPinnedFiles(pinned)
+	return removed, nil
+}
+
+// GetPinnedFiles returns the pinned files, relative to the working
+// directory, in the order they were pinned.
+func (g *core) GetPinnedFiles() ([]string, error) {
+	if err := g.ensureStarted(); err != nil {
+		return nil, err
+	}
+	sess, err := g.sessionMgr.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	var names []string
+	for _, path := range sess.GetPinnedFiles() {
+		names = append(names, pinnedDisplayPath(sess, path))
+	}
+	return names, nil
+}
+
+// expandPinPattern returns the absolute paths of the files a pattern
+// names. The tool path rules apply: files must be inside the working or
+// allowed directories, not denied, and not reached through symlinks.
+func expandPinPattern(reqCtx context.Context, pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		resolved, ok := tools.ResolvePathWithWorkingDirectory(reqCtx, pattern)
+		if !ok {
+			return nil, tools.FormatPathOutsideWorkspaceError(reqCtx, pattern)
+		}
+		if err := tools.CheckPathPolicy(reqCtx, resolved, tools.IntentRead); err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("cannot pin %s: %w", pattern, err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("cannot pin %s: not a regular file", pattern)
+		}
+		abs, err := filepath.Abs(resolved)
+		if err != nil {
+			return nil, err
+		}
+		return []string{abs}, nil
+	}
+
+	root, err := filepath.Abs(tools.WorkingDirectoryFromContext(reqCtx))
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return fs.SkipDir
+		}
+		if tools.CheckPathPolicy(reqCtx, path, tools.IntentRead) != nil {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err == nil && tools.MatchGlob(pattern, filepath.ToSlash(rel)) {
+			matches = append(matches, path)
+			if len(matches) > MaxPinnedFiles {
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+	if len(matches) > MaxPinnedFiles {
+		return nil, fmt.Errorf("%s matches more than %d files; narrow the pattern", pattern, MaxPinnedFiles)
+	}
+	return matches, nil
+}
+
+// pinnedDisplayPath shows a pinned file relative to the working directory
+// when it is inside it
+func pinnedDisplayPath(sess Session, path string) string {
+	workingDir, err := filepath.Abs(sess.GetWorkingDirectory())
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(workingDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
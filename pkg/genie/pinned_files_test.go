@@ -0,0 +1,66 @@
+package genie_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedFilesAreSentWithEveryTurn(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	write := func(name, content string) {
+		path := filepath.Join(fixture.TestDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write("notes.md", "remember the migration plan")
+	write("pkg/a.go", "package a")
+	write("pkg/b.go", "package b")
+
+	added, err := fixture.Genie.PinFiles(context.Background(), "notes.md", "pkg/*.go")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notes.md", "pkg/a.go", "pkg/b.go"}, added)
+
+	added, err = fixture.Genie.PinFiles(context.Background(), "notes.md")
+	require.NoError(t, err)
+	assert.Empty(t, added, "pinning twice is a no-op")
+
+	fixture.ExpectSimpleMessage("what is the plan?", "migrate")
+	require.NoError(t, fixture.StartChat("what is the plan?"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0].SystemPromptFiles, "File: notes.md\n```\nremember the migration plan\n```")
+	assert.Contains(t, prompts[0].SystemPromptFiles, "File: pkg/b.go")
+
+	removed, err := fixture.Genie.UnpinFiles("pkg/*.go")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pkg/a.go", "pkg/b.go"}, removed)
+
+	pinned, err := fixture.Genie.GetPinnedFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notes.md"}, pinned)
+}
+
+func TestPinFilesRejectsWhatCannotBePinned(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession()
+
+	_, err := fixture.Genie.PinFiles(context.Background(), "missing/*.go")
+	assert.ErrorContains(t, err, "no files match")
+
+	_, err = fixture.Genie.PinFiles(context.Background(), "../outside.txt")
+	assert.Error(t, err)
+
+	_, err = fixture.Genie.UnpinFiles("notes.md")
+	assert.ErrorContains(t, err, "no pinned file matches")
+}
@@ -0,0 +1,69 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// readOnlyModeInstruction tells the model why its write tools are gone so
+// it answers with a plan instead of trying to work around them.
+const readOnlyModeInstruction = `## Read-only mode
+This session is in read-only (plan) mode. Tools that modify files, run side-effecting commands, or start agents are unavailable, and bash only accepts commands that read (ls, cat, grep, git status/log/diff, ...).
+Explore and explain freely. When changes are needed, propose a plan and show them as unified diffs instead of applying them.`
+
+// applyReadOnlyMode strips the mutating tools from a turn's prompt and
+// tells the model it may only read. Handlers are copied so the cached
+// persona prompt keeps its full tool set.
+func applyReadOnlyMode(prompt *ai.Prompt) {
+	functions := make([]*ai.FunctionDeclaration, 0, len(prompt.Functions))
+	handlers := make(map[string]ai.HandlerFunc, len(prompt.Handlers))
+	for _, fn := range prompt.Functions {
+		handler := prompt.Handlers[fn.Name]
+		switch {
+		case tools.IsReadOnlyTool(fn.Name):
+		case fn.Name == "bash" && handler != nil:
+			handler = readOnlyBashHandler(handler)
+		default:
+			continue
+		}
+		functions = append(functions, fn)
+		if handler != nil {
+			handlers[fn.Name] = handler
+		}
+	}
+	prompt.Functions = functions
+	prompt.Handlers = handlers
+
+	if prompt.SystemPromptUserContext == "" {
+		prompt.SystemPromptUserContext = readOnlyModeInstruction
+	} else {
+		prompt.SystemPromptUserContext = strings.TrimRight(prompt.SystemPromptUserContext, "\n") + "\n\n" + readOnlyModeInstruction
+	}
+}
+
+// readOnlyBashHandler refuses bash commands that could change anything,
+// reporting the refusal as a tool result so the model can adjust.
+func readOnlyBashHandler(next ai.HandlerFunc) ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		command, _ := params["command"].(string)
+		if !tools.IsReadOnlyCommand(command) {
+			return map[string]any{
+				"success": false,
+				"results": "",
+				"error":   fmt.Sprintf("read-only mode: %q may modify the workspace; only read-only commands are allowed. Propose the change instead", command),
+			}, nil
+		}
+		if background, _ := params["background"].(bool); background {
+			return map[string]any{
+				"success": false,
+				"results": "",
+				"error":   "read-only mode: background processes are not allowed",
+			}, nil
+		}
+		return next(ctx, params)
+	}
+}
@@ -0,0 +1,85 @@
+package genie
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// ResolveScope turns the paths given to --scope or :scope into the scope
+// a session keeps: directories relative to workingDir, in slash form,
+// sorted, with those inside another one dropped. Paths may be relative to
+// workingDir or absolute and may end in "/..." or "/**". A path naming
+// workingDir or one of its parents lifts the scope, so the result is nil.
+func ResolveScope(workingDir string, paths []string) ([]string, error) {
+	root, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	var dirs []string
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		for _, suffix := range []string{"/...", "/**"} {
+			path = strings.TrimSuffix(path, suffix)
+		}
+		if path == "" {
+			continue
+		}
+		abs := path
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(root, abs)
+		}
+		abs = filepath.Clean(abs)
+		if within(root, abs) {
+			return nil, nil
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || !within(abs, root) {
+			return nil, fmt.Errorf("scope %s is outside the working directory %s", path, root)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("scope %s does not exist", path)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("scope %s is not a directory", path)
+		}
+		dirs = append(dirs, filepath.ToSlash(rel))
+	}
+
+	slices.Sort(dirs)
+	scope := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if n := len(scope); n > 0 && (dir == scope[n-1] || strings.HasPrefix(dir, scope[n-1]+"/")) {
+			continue
+		}
+		scope = append(scope, dir)
+	}
+	if len(scope) == 0 {
+		return nil, nil
+	}
+	return scope, nil
+}
+
+// within reports whether path is dir or inside it
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// applyScope tells the model which part of the workspace it works in, so
+// it searches there instead of running into refusals from the file tools.
+func applyScope(prompt *ai.Prompt, scope []string) {
+	instruction := fmt.Sprintf(`## Scope
+This session is scoped to %s in the workspace. File tools only read and change paths there, and searches without a path cover only it. Keep your work inside the scope; if the task needs files elsewhere, say which and ask the user to widen the scope with :scope.`, strings.Join(scope, ", "))
+	if prompt.SystemPromptUserContext == "" {
+		prompt.SystemPromptUserContext = instruction
+	} else {
+		prompt.SystemPromptUserContext = strings.TrimRight(prompt.SystemPromptUserContext, "\n") + "\n\n" + instruction
+	}
+}
@@ -0,0 +1,51 @@
+package genie_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveScope(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"services/payments/api", "services/search", "libs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module x"), 0644))
+
+	scope, err := genie.ResolveScope(root, []string{"services/search/...", "services/payments/api", filepath.Join(root, "services", "payments"), "libs/**"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"libs", "services/payments", "services/search"}, scope, "sorted, with nested directories folded into their parent")
+
+	scope, err = genie.ResolveScope(root, []string{"libs", "./..."})
+	require.NoError(t, err)
+	assert.Nil(t, scope, "the working directory itself lifts the scope")
+
+	_, err = genie.ResolveScope(root, []string{"../elsewhere"})
+	assert.ErrorContains(t, err, "outside the working directory")
+	_, err = genie.ResolveScope(root, []string{"services/missing"})
+	assert.ErrorContains(t, err, "does not exist")
+	_, err = genie.ResolveScope(root, []string{"go.mod"})
+	assert.ErrorContains(t, err, "not a directory")
+}
+
+func TestStartWithScopeTellsTheModel(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(fixture.TestDir, "services", "payments"), 0755))
+	session := fixture.StartAndGetSession(genie.WithScope("services/payments/..."))
+	assert.Equal(t, []string{"services/payments"}, session.GetScope())
+
+	fixture.ExpectSimpleMessage("where do I work?", "in payments")
+	require.NoError(t, fixture.StartChat("where do I work?"))
+	fixture.WaitForResponseOrFail(2 * time.Second)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0].SystemPromptUserContext, "This session is scoped to services/payments")
+}
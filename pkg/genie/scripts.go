@@ -0,0 +1,42 @@
+package genie
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/scripting"
+)
+
+// startHookScripts loads the Lua hook scripts in ~/.genie/hooks and, in a
+// trusted workspace, the project's .genie/hooks. Scripts that fail to load
+// are reported and skipped.
+func (g *core) startHookScripts(genieHome, workingDir string, trusted bool) {
+	if g.configMgr != nil && !g.configMgr.GetBoolWithDefault(scripting.ConfigKey, true) {
+		return
+	}
+	var dirs []string
+	home, err := os.UserHomeDir()
+	if err == nil {
+		dirs = append(dirs, scripting.Dir(home))
+	}
+	if trusted && filepath.Clean(genieHome) != filepath.Clean(home) {
+		dirs = append(dirs, scripting.Dir(genieHome))
+	}
+
+	opts := scripting.Options{WorkingDir: workingDir}
+	if g.configMgr != nil {
+		opts.Timeout = g.configMgr.GetDurationWithDefault(scripting.TimeoutConfigKey, scripting.DefaultTimeout)
+	}
+	engine, errs := scripting.Load(g.eventBus, opts, dirs...)
+	for _, err := range errs {
+		slog.Warn("Skipping hook script", "error", err)
+		g.eventBus.Publish(events.NotificationEvent{}.Topic(), events.NotificationEvent{
+			Message: err.Error(),
+			Role:    "error",
+			Error:   err,
+		})
+	}
+	g.scripts = engine
+}
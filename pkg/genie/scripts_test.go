@@ -0,0 +1,35 @@
+package genie
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/scripting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartHookScripts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	for _, root := range []string{home, project} {
+		dir := scripting.Dir(root)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "noop.lua"), []byte(`genie.on("chat.response", function() end)`), 0o644))
+	}
+
+	g := &core{eventBus: events.NewEventBus()}
+	g.startHookScripts(project, project, true)
+	assert.Equal(t, 2, g.scripts.Len())
+	g.Shutdown()
+	assert.Equal(t, 0, g.scripts.Len())
+
+	// Project scripts are code the user has not vetted in an untrusted
+	// workspace
+	g.startHookScripts(project, project, false)
+	assert.Equal(t, 1, g.scripts.Len())
+	g.Shutdown()
+}
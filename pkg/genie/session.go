@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/kcaldas/genie/pkg/events"
@@ -30,9 +32,25 @@ type InMemorySession struct {
 	readOnlyPaths     []string // Glob patterns the agent may read but not mutate
 	commitAuthorName  string   // Opaque commit author name set by the host
 	commitAuthorEmail string   // Opaque commit author email set by the host
+	modelProvider     string   // Runtime provider override, empty to infer
+	modelName         string   // Runtime model override, empty for the persona's model
+	readOnlyMode      bool     // Plan-only mode: mutating tools are withheld
+	untrusted         bool     // Workspace not trusted; project-local config is ignored
 	persona           Persona
 	publisher         events.Publisher
 	createdAt         string
+
+	// Context parts left out of the prompt, files pinned into it and the
+	// directories tools are scoped to. The TUI changes them while a turn
+	// may be reading them, hence the lock.
+	partsMu       sync.Mutex
+	disabledParts []string
+	pinnedFiles   []string
+	scope         []string
+
+	// Tools approved for the rest of the session, consulted from tool calls
+	approvalsMu sync.Mutex
+	approvals   []ToolApproval
 }
 
 // NewSession creates a new session with genie home directory, working directory, allowed dirs, persona, and publisher for broadcasting
@@ -64,6 +82,16 @@ func (s *InMemorySession) GetWorkingDirectory() string {
 	return s.workingDir
 }
 
+// SetWorkingDirectory moves file operations to dir
+func (s *InMemorySession) SetWorkingDirectory(dir string) {
+	s.workingDir = dir
+}
+
+// SetGenieHomeDirectory moves where .genie/ config is read from to dir
+func (s *InMemorySession) SetGenieHomeDirectory(dir string) {
+	s.genieHomeDir = dir
+}
+
 // GetAllowedDirectories returns the extra directories that tools may access
 func (s *InMemorySession) GetAllowedDirectories() []string {
 	return s.allowedDirs
@@ -79,9 +107,97 @@ func (s *InMemorySession) GetPersona() Persona {
 	return s.persona
 }
 
-// SetPersona sets the session's selected persona
+// SetPersona sets the session's selected persona. Any runtime model
+// override is dropped so the new persona's own model applies.
 func (s *InMemorySession) SetPersona(persona Persona) {
 	s.persona = persona
+	s.modelProvider = ""
+	s.modelName = ""
+}
+
+// GetModel returns the runtime model override, if any. An empty model
+// means the persona's model (or the GENIE_MODEL_NAME default) is used.
+func (s *InMemorySession) GetModel() (string, string) {
+	return s.modelProvider, s.modelName
+}
+
+// SetModel overrides the model for the following turns. An empty provider
+// keeps the persona's provider; an empty model clears the override.
+func (s *InMemorySession) SetModel(provider, model string) {
+	s.modelProvider = provider
+	s.modelName = model
+}
+
+// GetReadOnlyMode reports whether the session is in read-only (plan) mode.
+func (s *InMemorySession) GetReadOnlyMode() bool {
+	return s.readOnlyMode
+}
+
+// SetReadOnlyMode toggles read-only (plan) mode for the following turns.
+func (s *InMemorySession) SetReadOnlyMode(enabled bool) {
+	s.readOnlyMode = enabled
+}
+
+// IsWorkspaceTrusted reports whether project-local configuration may load.
+func (s *InMemorySession) IsWorkspaceTrusted() bool {
+	return !s.untrusted
+}
+
+// SetWorkspaceTrusted records the user's trust decision for the workspace.
+func (s *InMemorySession) SetWorkspaceTrusted(trusted bool) {
+	s.untrusted = !trusted
+}
+
+// GetDisabledContextParts returns the keys of the context parts left out
+// of the following turns, sorted.
+func (s *InMemorySession) GetDisabledContextParts() []string {
+	s.partsMu.Lock()
+	defer s.partsMu.Unlock()
+	return slices.Clone(s.disabledParts)
+}
+
+// SetContextPartEnabled includes or leaves out the context part with key
+// from the following turns.
+func (s *InMemorySession) SetContextPartEnabled(key string, enabled bool) {
+	s.partsMu.Lock()
+	defer s.partsMu.Unlock()
+	i, disabled := slices.BinarySearch(s.disabledParts, key)
+	switch {
+	case enabled && disabled:
+		s.disabledParts = slices.Delete(s.disabledParts, i, i+1)
+	case !enabled && !disabled:
+		s.disabledParts = slices.Insert(s.disabledParts, i, key)
+	}
+}
+
+// GetPinnedFiles returns the absolute paths of the files included in
+// every turn, in the order they were pinned.
+func (s *InMemorySession) GetPinnedFiles() []string {
+	s.partsMu.Lock()
+	defer s.partsMu.Unlock()
+	return slices.Clone(s.pinnedFiles)
+}
+
+// SetPinnedFiles replaces the files included in every turn.
+func (s *InMemorySession) SetPinnedFiles(paths []string) {
+	s.partsMu.Lock()
+	defer s.partsMu.Unlock()
+	s.pinnedFiles = slices.Clone(paths)
+}
+
+// GetScope returns the directories, relative to the working directory,
+// file tools are confined to. Empty means the whole workspace.
+func (s *InMemorySession) GetScope() []string {
+	s.partsMu.Lock()
+	defer s.partsMu.Unlock()
+	return slices.Clone(s.scope)
+}
+
+// SetScope confines file tools to dirs; nil lifts the scope.
+func (s *InMemorySession) SetScope(dirs []string) {
+	s.partsMu.Lock()
+	defer s.partsMu.Unlock()
+	s.scope = slices.Clone(dirs)
 }
 
 // GetID returns the session's unique identifier
@@ -66,6 +66,10 @@ func TestApplySessionContext(t *testing.T) {
 	email, ok := toolctx.CommitAuthorEmail(ctx)
 	assert.True(t, ok)
 	assert.Equal(t, "conv-2bfe5f1a@actors.mutiro.local", email)
+	approvals, ok := toolctx.Approvals(ctx)
+	assert.True(t, ok)
+	approvals.ApproveTool("Bash", "go test")
+	assert.True(t, sess.IsToolApproved("Bash", "go vet"), "approvals made by tools land on the session")
 }
 
 // TestApplySessionContext_OmitsEmptyOptionals confirms unconfigured
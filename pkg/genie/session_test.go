@@ -32,3 +32,30 @@ func TestNewSessionRecordsCreationTimestamp(t *testing.T) {
 	assert.True(t, createdAt.After(before) && createdAt.Before(after),
 		"createdAt %v must fall within the creation window", createdAt)
 }
+
+func TestSessionModelOverrideClearedOnPersonaSwap(t *testing.T) {
+	s := NewSession("/home", "/work", nil, nil, events.NewEventBus())
+
+	s.SetModel("anthropic", "claude-sonnet-4-5")
+	provider, model := s.GetModel()
+	assert.Equal(t, "anthropic", provider)
+	assert.Equal(t, "claude-sonnet-4-5", model)
+
+	s.SetPersona(&DefaultPersona{ID: "engineer"})
+	provider, model = s.GetModel()
+	assert.Empty(t, provider)
+	assert.Empty(t, model, "a persona swap must restore the persona's own model")
+}
+
+func TestSessionContextPartToggles(t *testing.T) {
+	s := NewSession("/home", "/work", nil, nil, events.NewEventBus())
+
+	s.SetContextPartEnabled("project", false)
+	s.SetContextPartEnabled("chat", false)
+	s.SetContextPartEnabled("chat", false)
+	assert.Equal(t, []string{"chat", "project"}, s.GetDisabledContextParts())
+
+	s.SetContextPartEnabled("chat", true)
+	s.SetContextPartEnabled("files", true)
+	assert.Equal(t, []string{"project"}, s.GetDisabledContextParts())
+}
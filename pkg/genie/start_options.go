@@ -14,6 +14,9 @@ type startOptions struct {
 	allowedDirs       []string
 	deniedPaths       []string
 	readOnlyPaths     []string
+	scope             []string
+	readOnlyMode      bool
+	untrusted         bool
 	commitAuthorName  string
 	commitAuthorEmail string
 }
@@ -78,6 +81,38 @@ func WithReadOnlyPaths(patterns ...string) StartOption {
 	}
 }
 
+// WithScope confines file tools to the given directories of the working
+// directory, such as a team's part of a monorepo. Paths are relative to
+// the working directory or absolute; Start fails when one is not a
+// directory inside it. See ResolveScope.
+func WithScope(dirs ...string) StartOption {
+	return func(opts *startOptions) {
+		for _, d := range dirs {
+			if d != "" {
+				opts.scope = append(opts.scope, d)
+			}
+		}
+	}
+}
+
+// WithReadOnlyMode starts the session in read-only (plan) mode: tools
+// that write files, run side-effecting commands or start agents are not
+// offered to the model. Session.SetReadOnlyMode switches it later.
+func WithReadOnlyMode() StartOption {
+	return func(opts *startOptions) {
+		opts.readOnlyMode = true
+	}
+}
+
+// WithUntrustedWorkspace starts the session in a workspace the user has
+// not trusted: project-local personas, skills and the project .mcp.json
+// are ignored, leaving only user-level and built-in configuration.
+func WithUntrustedWorkspace() StartOption {
+	return func(opts *startOptions) {
+		opts.untrusted = true
+	}
+}
+
 // WithCommitAuthor sets the opaque author identity gitCommit attributes
 // commits to. Genie writes both fields verbatim — they're whatever the
 // host wants. Pass empty values to fall back to the platform default
@@ -110,11 +145,17 @@ func applyStartOptions(optionFns ...StartOption) startOptions {
 }
 
 func (s startOptions) toMessages() []ctx.Message {
-	if len(s.chatHistory) == 0 {
+	return toContextMessages(s.chatHistory)
+}
+
+// toContextMessages converts turns to chat context messages, skipping
+// empty turns. It returns nil when no turn is left.
+func toContextMessages(turns []ChatHistoryTurn) []ctx.Message {
+	if len(turns) == 0 {
 		return nil
 	}
-	messages := make([]ctx.Message, 0, len(s.chatHistory))
-	for _, turn := range s.chatHistory {
+	messages := make([]ctx.Message, 0, len(turns))
+	for _, turn := range turns {
 		if turn.User == "" && turn.Assistant == "" {
 			continue
 		}
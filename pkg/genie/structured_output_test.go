@@ -0,0 +1,83 @@
+package genie_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResponseSchema() *ai.Schema {
+	return &ai.Schema{
+		Type:     ai.TypeObject,
+		Required: []string{"title"},
+		Properties: map[string]*ai.Schema{
+			"title": {Type: ai.TypeString},
+		},
+	}
+}
+
+func TestChatWithResponseSchemaReturnsValidatedJSON(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.StartAndGetSession()
+	message := "name this change"
+	fixture.ExpectSimpleMessage(message, "```json\n{\"title\": \"Add schema mode\"}\n```")
+
+	schema := testResponseSchema()
+	require.NoError(t, fixture.Genie.Chat(context.Background(), message, genie.WithResponseSchema(schema)))
+
+	response := fixture.WaitForResponseOrFail(2 * time.Second)
+	require.NoError(t, response.Error)
+	assert.Equal(t, `{"title": "Add schema mode"}`, response.Response)
+
+	prompts := fixture.MockPromptRunner.CapturedPrompts()
+	require.Len(t, prompts, 1)
+	assert.Same(t, schema, prompts[0].ResponseSchema)
+}
+
+func TestChatWithResponseSchemaRepairsInvalidAnswer(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.StartAndGetSession()
+	schema := testResponseSchema()
+	message := "name this change"
+	invalid := `{"name": "Add schema mode"}`
+	repair := genie.SchemaRepairMessageForTest(message, invalid, schema.ValidateJSON(invalid))
+	fixture.ExpectSimpleMessage(message, invalid)
+	fixture.ExpectSimpleMessage(repair, `{"title": "Add schema mode"}`)
+
+	require.NoError(t, fixture.Genie.Chat(context.Background(), message, genie.WithResponseSchema(schema)))
+
+	response := fixture.WaitForResponseOrFail(2 * time.Second)
+	require.NoError(t, response.Error)
+	assert.Equal(t, `{"title": "Add schema mode"}`, response.Response)
+	assert.Contains(t, repair, `missing required property "title"`)
+	assert.Len(t, fixture.MockPromptRunner.CapturedPrompts(), 2)
+}
+
+func TestChatWithResponseSchemaFailsAfterRepairAttempts(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	defer fixture.Cleanup()
+
+	fixture.StartAndGetSession()
+	schema := testResponseSchema()
+	message := "name this change"
+	invalid := "Sure! The title is: Add schema mode"
+	fixture.ExpectSimpleMessage(message, invalid)
+	fixture.ExpectSimpleMessage(genie.SchemaRepairMessageForTest(message, invalid, schema.ValidateJSON(invalid)), invalid)
+
+	require.NoError(t, fixture.Genie.Chat(context.Background(), message, genie.WithResponseSchema(schema)))
+
+	response := fixture.WaitForResponseOrFail(2 * time.Second)
+	require.Error(t, response.Error)
+	assert.Contains(t, response.Error.Error(), "no valid response after 2 repair attempts")
+	assert.Len(t, fixture.MockPromptRunner.CapturedPrompts(), 3)
+}
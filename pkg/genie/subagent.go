@@ -0,0 +1,173 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// nativeSubAgentRunner runs runAgent delegations in a child Genie. Tool
+// activity is reported on the parent bus as SubAgentEvents, and the child's
+// confirmation requests are relayed to the parent so the host can answer
+// them.
+type nativeSubAgentRunner struct {
+	parent *core
+}
+
+func newNativeSubAgentRunner(parent *core) tools.SubAgentRunner {
+	return &nativeSubAgentRunner{parent: parent}
+}
+
+func (r *nativeSubAgentRunner) RunSubAgent(ctx context.Context, request tools.SubAgentRequest) (tools.SubAgentResult, error) {
+	parentSession, err := r.parent.sessionMgr.GetSession()
+	if err != nil {
+		return tools.SubAgentResult{}, err
+	}
+
+	child, childEvents, err := newChildGenie(r.parent, request.Tools)
+	if err != nil {
+		return tools.SubAgentResult{}, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	agentID := uuid.NewString()
+	parentEvents := r.parent.eventBus
+	publish := func(event events.SubAgentEvent) {
+		event.AgentID = agentID
+		event.Task = request.Task
+		parentEvents.Publish(event.Topic(), event)
+	}
+
+	var (
+		mu     sync.Mutex
+		result tools.SubAgentResult
+	)
+	usage := func() tools.SubAgentResult {
+		mu.Lock()
+		defer mu.Unlock()
+		return result
+	}
+
+	responseCh := make(chan events.ChatResponseEvent, 1)
+	unsubscribers := []func(){
+		events.SubscribeTo(childEvents, func(response events.ChatResponseEvent) {
+			select {
+			case responseCh <- response:
+			default:
+			}
+		}),
+		events.SubscribeTo(childEvents, func(e events.TokenCountEvent) {
+			if e.Estimate {
+				return
+			}
+			mu.Lock()
+			result.InputTokens += int64(e.InputTokens)
+			result.OutputTokens += int64(e.OutputTokens)
+			exhausted := request.MaxTokens > 0 && result.InputTokens+result.OutputTokens >= request.MaxTokens
+			if exhausted {
+				result.BudgetExhausted = true
+			}
+			mu.Unlock()
+			if exhausted {
+				cancel()
+			}
+		}),
+		events.SubscribeTo(childEvents, func(e events.ToolExecutedEvent) {
+			publish(events.SubAgentEvent{Phase: "tool", ToolName: e.ToolName, Message: e.Message})
+		}),
+		// Relay confirmations both ways; each side ignores execution IDs it
+		// did not issue.
+		events.SubscribeTo(childEvents, func(e events.ToolConfirmationRequest) {
+			parentEvents.Publish(e.Topic(), e)
+		}),
+		events.SubscribeTo(childEvents, func(e events.UserConfirmationRequest) {
+			parentEvents.Publish(e.Topic(), e)
+		}),
+		events.SubscribeTo(parentEvents, func(e events.ToolConfirmationResponse) {
+			childEvents.Publish(e.Topic(), e)
+		}),
+		events.SubscribeTo(parentEvents, func(e events.UserConfirmationResponse) {
+			childEvents.Publish(e.Topic(), e)
+		}),
+	}
+	defer func() {
+		for _, unsubscribe := range unsubscribers {
+			unsubscribe()
+		}
+	}()
+
+	workspace := strings.TrimSpace(request.Workspace)
+	if workspace == "" {
+		workspace = parentSession.GetWorkingDirectory()
+	}
+	personaID := request.Persona
+	if personaID == "" && parentSession.GetPersona() != nil {
+		personaID = parentSession.GetPersona().GetID()
+	}
+	var personaPtr *string
+	if personaID != "" {
+		personaPtr = &personaID
+	}
+	if _, err := child.Start(&workspace, personaPtr, childStartOptions(parentSession)...); err != nil {
+		return tools.SubAgentResult{}, err
+	}
+	defer child.Shutdown()
+
+	publish(events.SubAgentEvent{Phase: "started", Message: fmt.Sprintf("persona %s, %d tools", personaID, len(request.Tools))})
+
+	finish := func(output string, err error) (tools.SubAgentResult, error) {
+		final := usage()
+		final.Output = output
+		if final.BudgetExhausted {
+			err = nil
+		}
+		finished := events.SubAgentEvent{Phase: "finished"}
+		if err != nil {
+			finished.Error = err.Error()
+		} else if final.BudgetExhausted {
+			finished.Message = "token budget exhausted"
+		}
+		publish(finished)
+		return final, err
+	}
+
+	if err := child.Chat(runCtx, subAgentPrompt(request.Task), WithoutPromptCache()); err != nil {
+		return finish("", err)
+	}
+
+	select {
+	case response := <-responseCh:
+		if response.Error != nil {
+			return finish("", response.Error)
+		}
+		return finish(strings.TrimSpace(response.Response), nil)
+	case <-runCtx.Done():
+		return finish("", runCtx.Err())
+	}
+}
+
+func (g *core) configureDefaultSubAgentRunner() {
+	tool, ok := g.toolRegistry.Get("runAgent")
+	if !ok {
+		return
+	}
+	if runAgent, ok := tool.(*tools.RunAgentTool); ok {
+		runAgent.SetRunnerIfUnconfigured(newNativeSubAgentRunner(g))
+	}
+}
+
+func subAgentPrompt(task string) string {
+	return fmt.Sprintf(`DELEGATED TASK:
+
+You are a sub-agent working for another assistant, which will only see your final answer. Complete the task with the tools available to you, then reply with a concise summary of your findings, including concrete file references where relevant.
+
+TASK:
+%s`, strings.TrimSpace(task))
+}
@@ -0,0 +1,56 @@
+package genie_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/genie"
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+)
+
+func TestRunAgentDelegatesToChildSession(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	session := fixture.StartAndGetSession(genie.WithChatHistory(genie.ChatHistoryTurn{
+		User:      "Parent-only question",
+		Assistant: "Parent-only answer",
+	}))
+
+	registry, err := fixture.Genie.GetToolsRegistry()
+	require.NoError(t, err)
+	runAgent, ok := registry.Get("runAgent")
+	require.True(t, ok, "runAgent should be registered")
+
+	var mu sync.Mutex
+	var phases []string
+	events.SubscribeTo(fixture.EventBus, func(e events.SubAgentEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		phases = append(phases, e.Phase)
+	})
+
+	fixture.ExpectSimpleMessage(genie.SubAgentPromptForTest("find usages of Foo"), "Foo is used in a.go and b.go")
+
+	ctx := genie.ApplySessionContextForTest(t.Context(), session)
+	result, err := runAgent.Handler()(ctx, map[string]any{
+		"task":  "find usages of Foo",
+		"tools": []any{"searchInFiles", "readFile"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, "Foo is used in a.go and b.go", result["result"])
+
+	captured := fixture.MockPromptRunner.CapturedData()
+	require.NotEmpty(t, captured)
+	assert.NotContains(t, captured[len(captured)-1]["chat"], "Parent-only")
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(phases) == 2 && phases[0] == "started" && phases[1] == "finished"
+	}, time.Second, 10*time.Millisecond)
+}
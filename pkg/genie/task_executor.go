@@ -3,6 +3,7 @@ package genie
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/kcaldas/genie/pkg/config"
@@ -32,7 +33,7 @@ func (e *nativeTaskExecutor) RunTask(runCtx context.Context, request tools.TaskR
 		return tools.TaskResult{Error: err.Error()}, err
 	}
 
-	child, childEvents, err := e.newChildGenie()
+	child, childEvents, err := newChildGenie(e.parent, nil)
 	if err != nil {
 		return tools.TaskResult{Error: err.Error()}, err
 	}
@@ -62,20 +63,11 @@ func (e *nativeTaskExecutor) RunTask(runCtx context.Context, request tools.TaskR
 		reporter.Log("starting native Genie child session")
 	}
 
-	startOptions := []StartOption{
-		WithAllowedDirs(parentSession.GetAllowedDirectories()...),
-		WithDeniedPaths(parentSession.GetDeniedPaths()...),
-		WithReadOnlyPaths(parentSession.GetReadOnlyPaths()...),
-	}
-	if name, email := parentSession.GetCommitAuthor(); name != "" || email != "" {
-		startOptions = append(startOptions, WithCommitAuthor(name, email))
-	}
-
 	var personaPtr *string
 	if personaID != "" {
 		personaPtr = &personaID
 	}
-	if _, err := child.Start(&workspace, personaPtr, startOptions...); err != nil {
+	if _, err := child.Start(&workspace, personaPtr, childStartOptions(parentSession)...); err != nil {
 		return tools.TaskResult{Error: err.Error()}, err
 	}
 
@@ -94,15 +86,16 @@ func (e *nativeTaskExecutor) RunTask(runCtx context.Context, request tools.TaskR
 	}
 }
 
-// newChildGenie assembles an isolated Genie for a Task subagent: its
-// own event bus, session, context, and a registry without the Task
-// tool (no recursive task trees), while sharing the parent's prompt
-// runner, skill manager, and MCP client.
+// newChildGenie assembles an isolated Genie for a Task subagent or a
+// runAgent sub-agent: its own event bus, session, context, and a registry
+// without the Task and runAgent tools (no recursive task trees), while
+// sharing the parent's prompt runner, skill manager, and MCP client. A
+// non-empty allowedTools restricts the child to those tools.
 //
 // It composes the SAME provider functions the Wire graph uses
 // (provideContextRegistry, ProvideSkillManager, ...); when adding a
 // component to the Wire graph in wire.go, mirror it here.
-func (e *nativeTaskExecutor) newChildGenie() (Genie, events.EventBus, error) {
+func newChildGenie(parent *core, allowedTools []string) (Genie, events.EventBus, error) {
 	childEvents := events.NewEventBus()
 	skillManager, err := ProvideSkillManager()
 	if err != nil {
@@ -115,11 +108,14 @@ func (e *nativeTaskExecutor) newChildGenie() (Genie, events.EventBus, error) {
 
 	todoManager := tools.NewTodoManager()
 	toolRegistry := tools.NewDefaultRegistryWithoutTask(childEvents, todoManager, skillManager, mcpClient)
+	if len(allowedTools) > 0 {
+		toolRegistry = tools.NewFilteredRegistry(toolRegistry, allowedTools)
+	}
 	contextRegistry := provideContextRegistry(childEvents, skillManager)
 	contextManager := ctx.NewContextManager(contextRegistry)
 	promptLoader := prompts.NewPromptLoader(childEvents, toolRegistry)
 	personaPromptFactory := persona.NewPersonaPromptFactory(promptLoader, skillManager)
-	configManager := e.parent.configMgr
+	configManager := parent.configMgr
 	if configManager == nil {
 		configManager = config.NewConfigManager()
 	}
@@ -128,7 +124,7 @@ func (e *nativeTaskExecutor) newChildGenie() (Genie, events.EventBus, error) {
 	sessionManager := NewSessionManager(childEvents)
 
 	return newGenieCore(
-		e.parent.promptRunner,
+		parent.promptRunner,
 		sessionManager,
 		contextManager,
 		childEvents,
@@ -139,6 +135,34 @@ func (e *nativeTaskExecutor) newChildGenie() (Genie, events.EventBus, error) {
 	), childEvents, nil
 }
 
+// childStartOptions carries the parent session's path policy, scope,
+// commit author and workspace trust over to a child session.
+func childStartOptions(parentSession Session) []StartOption {
+	startOptions := []StartOption{
+		WithAllowedDirs(parentSession.GetAllowedDirectories()...),
+		WithDeniedPaths(parentSession.GetDeniedPaths()...),
+		WithReadOnlyPaths(parentSession.GetReadOnlyPaths()...),
+		WithScope(absoluteScope(parentSession)...),
+	}
+	if name, email := parentSession.GetCommitAuthor(); name != "" || email != "" {
+		startOptions = append(startOptions, WithCommitAuthor(name, email))
+	}
+	if !parentSession.IsWorkspaceTrusted() {
+		startOptions = append(startOptions, WithUntrustedWorkspace())
+	}
+	return startOptions
+}
+
+// absoluteScope returns the session's scope as absolute paths, which hold
+// for a child working in another directory too
+func absoluteScope(sess Session) []string {
+	var dirs []string
+	for _, dir := range sess.GetScope() {
+		dirs = append(dirs, filepath.Join(sess.GetWorkingDirectory(), filepath.FromSlash(dir)))
+	}
+	return dirs
+}
+
 func nativeTaskPrompt(prompt string) string {
 	return fmt.Sprintf(`DEEP RESEARCH TASK:
 
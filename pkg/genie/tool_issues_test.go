@@ -0,0 +1,55 @@
+package genie
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropRejectedTools(t *testing.T) {
+	var handler ai.HandlerFunc
+	cached := &ai.Prompt{
+		LLMProvider: "genai",
+		Functions:   []*ai.FunctionDeclaration{{Name: "readFile"}, {Name: "mcp_search"}, {Name: "mcp_tags"}},
+		Handlers:    map[string]ai.HandlerFunc{"readFile": handler, "mcp_search": handler, "mcp_tags": handler},
+	}
+	issues := []tools.ToolIssue{
+		{Tool: "mcp_search", Message: "array has no items schema", Fatal: true},
+		{Tool: "mcp_tags", Message: "enum is only allowed on strings", Fatal: true, Providers: []string{"genai"}},
+		{Tool: "readFile", Message: "has no description"},
+	}
+
+	turn := *cached
+	assert.Equal(t, []string{"mcp_search", "mcp_tags"}, dropRejectedTools(&turn, issues))
+	assert.Len(t, turn.Functions, 1)
+	assert.Equal(t, "readFile", turn.Functions[0].Name)
+	assert.Len(t, turn.Handlers, 1)
+	assert.Len(t, cached.Functions, 3, "the cached persona prompt keeps every tool")
+
+	// Only Gemini rejects enums on numbers
+	turn = *cached
+	turn.LLMProvider = "anthropic"
+	dropRejectedTools(&turn, issues)
+	assert.Len(t, turn.Functions, 2)
+}
+
+func TestApplyUnavailableTools(t *testing.T) {
+	prompt := &ai.Prompt{
+		SystemPromptUserContext: "## Project\nUse Go.",
+		MissingTools:            []string{"@github", "ghost"},
+		MissingToolReasons:      map[string]string{"@github": "the GitHub CLI (gh) is not installed"},
+	}
+	applyUnavailableTools(prompt, []string{"mcp_search"})
+
+	assert.True(t, strings.HasPrefix(prompt.SystemPromptUserContext, "## Project\nUse Go.\n\n## Unavailable tools\n"))
+	assert.Contains(t, prompt.SystemPromptUserContext, "Do not call them.")
+	assert.Contains(t, prompt.SystemPromptUserContext, "- @github: the GitHub CLI (gh) is not installed\n- ghost\n- mcp_search: ")
+
+	// Nothing missing leaves the prompt alone
+	complete := &ai.Prompt{SystemPromptUserContext: "## Project"}
+	applyUnavailableTools(complete, nil)
+	assert.Equal(t, "## Project", complete.SystemPromptUserContext)
+}
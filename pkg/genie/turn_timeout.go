@@ -0,0 +1,91 @@
+package genie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// TurnTimeoutConfigKey bounds how long one chat turn, including all of
+	// its tool calls, may run. Zero disables the limit.
+	TurnTimeoutConfigKey = "GENIE_TURN_TIMEOUT"
+
+	// DefaultTurnTimeout is the turn limit applied when none is configured.
+	DefaultTurnTimeout = 30 * time.Minute
+)
+
+// ErrTurnTimeout is wrapped by the ChatResponseEvent error of a turn that
+// ran past GENIE_TURN_TIMEOUT.
+var ErrTurnTimeout = errors.New("turn timed out")
+
+// turnAbandonGrace is how long a timed-out or cancelled turn has to unwind
+// before Chat reports it over without waiting any longer.
+var turnAbandonGrace = 5 * time.Second
+
+// turnTimeout returns the configured limit for a chat turn.
+func (g *core) turnTimeout() time.Duration {
+	if g.configMgr == nil {
+		return DefaultTurnTimeout
+	}
+	return g.configMgr.GetDurationWithDefault(TurnTimeoutConfigKey, DefaultTurnTimeout)
+}
+
+// runTurn runs processChat under the turn timeout. A turn that outlives it
+// fails with ErrTurnTimeout; one that also ignores its expired context is
+// abandoned after turnAbandonGrace, so observers waiting on the response
+// event are never left waiting on a hung model call.
+func (g *core) runTurn(ctx context.Context, message string, options chatRequestOptions) (result turnResult, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "genie.turn",
+		attribute.String("genie.request_id", options.requestID),
+		attribute.Int("genie.message.length", len(message)),
+		attribute.Bool("genie.stream", options.stream),
+	)
+	defer func() { telemetry.End(span, err) }()
+
+	timeout := g.turnTimeout()
+	if timeout <= 0 {
+		return g.processChat(ctx, message, options)
+	}
+	turnCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result turnResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("internal error: %v", r)}
+			}
+		}()
+		result, err := g.processChat(turnCtx, message, options)
+		done <- outcome{result: result, err: err}
+	}()
+
+	var o outcome
+	select {
+	case o = <-done:
+	case <-turnCtx.Done():
+		timer := time.NewTimer(turnAbandonGrace)
+		defer timer.Stop()
+		select {
+		case o = <-done:
+		case <-timer.C:
+			slog.Warn("Chat turn ignored its context; abandoning it", "request_id", options.requestID)
+			o.err = turnCtx.Err()
+		}
+	}
+
+	if o.err != nil && ctx.Err() == nil && errors.Is(turnCtx.Err(), context.DeadlineExceeded) {
+		return turnResult{}, fmt.Errorf("%w: no response after %s (raise %s for longer tasks)", ErrTurnTimeout, timeout, TurnTimeoutConfigKey)
+	}
+	return o.result, o.err
+}
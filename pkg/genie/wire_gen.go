@@ -9,20 +9,27 @@ package genie
 import (
 	"fmt"
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+	"github.com/kcaldas/genie/pkg/budget"
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/ctx"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/llm/anthropic"
 	"github.com/kcaldas/genie/pkg/llm/genai"
 	"github.com/kcaldas/genie/pkg/llm/lmstudio"
+	"github.com/kcaldas/genie/pkg/llm/mock"
 	"github.com/kcaldas/genie/pkg/llm/multiplexer"
 	"github.com/kcaldas/genie/pkg/llm/ollama"
 	"github.com/kcaldas/genie/pkg/llm/openai"
 	"github.com/kcaldas/genie/pkg/mcp"
+	"github.com/kcaldas/genie/pkg/offline"
 	"github.com/kcaldas/genie/pkg/persona"
+	"github.com/kcaldas/genie/pkg/plugin"
 	"github.com/kcaldas/genie/pkg/prompts"
 	"github.com/kcaldas/genie/pkg/skills"
+	"github.com/kcaldas/genie/pkg/team"
 	"github.com/kcaldas/genie/pkg/tools"
+	"log/slog"
 	"strings"
 	"sync"
 )
@@ -35,7 +42,7 @@ import (
 func ProvideGenieWithOptions(options *GenieOptions) (Genie, error) {
 	eventBus := provideNewEventBus()
 	manager := ProvideConfigManager()
-	gen, err := provideAIGen(eventBus, manager)
+	gen, err := provideAIGenWithOptions(eventBus, manager, options)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +54,7 @@ func ProvideGenieWithOptions(options *GenieOptions) (Genie, error) {
 	if err != nil {
 		return nil, err
 	}
-	contextPartProviderRegistry := provideContextRegistry(eventBus, skillsSkillManager)
+	contextPartProviderRegistry := provideWatchedContextRegistry(eventBus, skillsSkillManager, options)
 	contextManager := ctx.NewContextManager(contextPartProviderRegistry)
 	todoManager := ProvideTodoManager()
 	mcpClient, err := ProvideMCPClient()
@@ -245,6 +252,13 @@ func newRegistryWithOptions(
 			return nil, fmt.Errorf("failed to register custom tool: %w (hint: check .mcp.json for conflicting tool names)", err)
 		}
 	}
+	for _, p := range options.plugins() {
+		for _, tool := range plugin.Tools([]plugin.Plugin{p}) {
+			if err := registry.Register(tool); err != nil {
+				return nil, fmt.Errorf("failed to register a tool of plugin %s: %w", p.Name(), err)
+			}
+		}
+	}
 
 	return registry, nil
 }
@@ -273,6 +287,7 @@ func provideAIGen(eb events.EventBus, configManager config.Manager) (ai.Gen, err
 		"anthropic": func() (ai.Gen, error) { return anthropic.NewClient(eb) },
 		"ollama":    func() (ai.Gen, error) { return ollama.NewClient(eb) },
 		"lmstudio":  func() (ai.Gen, error) { return lmstudio.NewClient(eb) },
+		"mock":      func() (ai.Gen, error) { return mock.NewClient(eb) },
 	}
 
 	aliases := map[string]string{
@@ -285,22 +300,62 @@ func provideAIGen(eb events.EventBus, configManager config.Manager) (ai.Gen, err
 		"lm-studio":        "lmstudio",
 	}
 
-	muxClient, err := multiplexer.NewClient(provider, factories, aliases)
+	var baseGen ai.Gen
+	if replayFile := configManager.GetStringWithDefault("GENIE_REPLAY_FILE", ""); replayFile != "" {
+		replay, err := ai.NewReplayGen(replayFile)
+		if err != nil {
+			return nil, err
+		}
+		replay.RunTools(configManager.GetBoolWithDefault("GENIE_REPLAY_TOOLS", false))
+		baseGen = replay
+	} else {
+		muxClient, err := multiplexer.NewClient(provider, factories, aliases)
+		if err != nil {
+			return nil, err
+		}
+		baseGen = muxClient
+
+		captureConfig := ai.GetCaptureConfigFromEnv(muxClient.DefaultProvider())
+		if captureConfig.Enabled {
+			baseGen = ai.NewCaptureMiddleware(baseGen, captureConfig)
+		}
+	}
+
+	middlewares, err := middleware.FromConfig(configManager)
 	if err != nil {
 		return nil, err
 	}
-
-	baseGen := ai.Gen(muxClient)
-	captureProvider := muxClient.DefaultProvider()
-
-	captureConfig := ai.GetCaptureConfigFromEnv(captureProvider)
-	if captureConfig.Enabled {
-		baseGen = ai.NewCaptureMiddleware(baseGen, captureConfig)
+	if offline.Enabled(configManager) {
+		middlewares = append([]middleware.Middleware{offline.NewMiddleware(configManager)}, middlewares...)
 	}
+	if policy := team.PolicyFromConfig(configManager); !policy.Empty() {
+		middlewares = append(middlewares, policy.Middleware(configManager))
+	}
+	budgets, err := budget.New(configManager, eb)
+	if err != nil {
+		return nil, err
+	}
+	if budgets != nil {
+		middlewares = append(middlewares, budgets.Middleware())
+	}
+	baseGen = middleware.Wrap(baseGen, middlewares...)
 
 	return baseGen, nil
 }
 
+// provideAIGenWithOptions adds the integrator's AIMiddleware around the
+// configured AI Gen.
+func provideAIGenWithOptions(eb events.EventBus, configManager config.Manager, options *GenieOptions) (ai.Gen, error) {
+	gen, err := provideAIGen(eb, configManager)
+	if err != nil {
+		return nil, err
+	}
+	if options == nil {
+		return gen, nil
+	}
+	return middleware.Wrap(gen, options.AIMiddleware...), nil
+}
+
 // provideContextRegistry creates the context registry using the given event bus.
 func provideContextRegistry(
 	eb events.EventBus, skillManager2 skills.SkillManager,
@@ -312,6 +367,7 @@ func provideContextRegistry(
 	chatManager := ctx.NewChatCtxManager(eb)
 	fileProvider := ctx.NewFileContextPartsProvider(eb)
 	todoProvider := ctx.NewTodoContextPartProvider(eb)
+	pinnedProvider := ctx.NewPinnedFilesContextPartProvider()
 	skillProvider := skills.NewSkillContextPartProvider(skillManager2, eb)
 
 	chatManager.SetBudgetStrategy(ctx.NewSlidingWindowStrategy())
@@ -322,6 +378,7 @@ func provideContextRegistry(
 	registry.Register(chatManager, 0.7)
 	registry.Register(fileProvider, 0.3)
 	registry.Register(todoProvider, 0)
+	registry.Register(pinnedProvider, 0)
 
 	if skillProvider != nil {
 		registry.Register(skillProvider, 0)
@@ -330,6 +387,32 @@ func provideContextRegistry(
 	return registry
 }
 
+// provideWatchedContextRegistry is provideContextRegistry with the file
+// providers watching their files for changes, plus the context parts of
+// plugins. Sub-agents use the unwatched registry: they are short-lived
+// and the watchers would outlive them.
+func provideWatchedContextRegistry(
+	eb events.EventBus, skillManager2 skills.SkillManager,
+
+	options *GenieOptions,
+) *ctx.ContextPartProviderRegistry {
+	registry := provideContextRegistry(eb, skillManager2)
+	for _, provider := range plugin.ContextParts(options.plugins()) {
+		registry.Register(provider, 0)
+	}
+	for _, provider := range registry.GetProviders() {
+		watchable, ok := provider.(interface{ WatchFiles() error })
+		if !ok {
+			continue
+		}
+
+		if err := watchable.WatchFiles(); err != nil {
+			slog.Warn("Watching context files for changes is disabled", "error", err)
+		}
+	}
+	return registry
+}
+
 // provideDefaultTaskManagerOptions satisfies NewDefaultRegistry's variadic
 // TaskManagerOption parameter for injectors that use plain defaults.
 func provideDefaultTaskManagerOptions() []tools.TaskManagerOption {
@@ -1,8 +1,12 @@
 package genie
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/stretchr/testify/require"
@@ -26,3 +30,36 @@ func TestProvideAIGen_DoesNotWarmUpDefaultProvider(t *testing.T) {
 	require.Equal(t, "genai", status.Backend)
 	require.Contains(t, status.Message, "no valid AI backend configured")
 }
+
+func TestProvideAIGen_ReplaysCaptureFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "capture.json")
+	require.NoError(t, ai.SaveInteractionsToFile([]ai.Interaction{
+		{ID: "1", Prompt: ai.CapturedPrompt{Name: "chat"}, Response: "recorded answer"},
+	}, file))
+	t.Setenv("GENIE_REPLAY_FILE", file)
+
+	gen, err := provideAIGen(events.NewEventBus(), config.NewConfigManager())
+	require.NoError(t, err)
+
+	require.Equal(t, "replay", gen.GetStatus().Backend)
+	response, err := gen.GenerateContent(context.Background(), ai.Prompt{Name: "chat"}, false)
+	require.NoError(t, err)
+	require.Equal(t, "recorded answer", response)
+}
+
+func TestProvideAIGen_MockBackendNeedsNoKeys(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	t.Setenv("GENIE_LLM_PROVIDER", "")
+	t.Setenv("GENAI_BACKEND", "mock")
+	scenario := filepath.Join(t.TempDir(), "scenario.yaml")
+	require.NoError(t, os.WriteFile(scenario, []byte("turns:\n  - match: hi\n    response: scripted hello\n"), 0o644))
+	t.Setenv("GENIE_MOCK_SCENARIO", scenario)
+
+	gen, err := provideAIGen(events.NewEventBus(), config.NewConfigManager())
+	require.NoError(t, err)
+
+	response, err := gen.GenerateContent(context.Background(), ai.Prompt{Name: "chat", LLMProvider: "genai"}, false, "message", "hi")
+	require.NoError(t, err)
+	require.Equal(t, "scripted hello", response)
+}
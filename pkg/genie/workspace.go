@@ -0,0 +1,52 @@
+package genie
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ChangeWorkingDirectory moves the session to dir. Project context files,
+// personas, skills and memories are looked up per turn from the session's
+// directories, so they follow from the next turn. When Genie was started
+// in the directory it works in, the .genie home moves along and the new
+// project's lifecycle hooks replace the old ones; a home set apart with
+// --cwd stays where it is. A scope is lifted. MCP servers and hook
+// scripts stay those of the directory Genie started in.
+func (g *core) ChangeWorkingDirectory(dir string, trusted bool) (Session, error) {
+	if err := g.ensureStarted(); err != nil {
+		return nil, err
+	}
+	sess, err := g.sessionMgr.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("working directory does not exist: %s", dir)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	if filepath.Clean(sess.GetGenieHomeDirectory()) == filepath.Clean(sess.GetWorkingDirectory()) {
+		if err := g.loadLifecycleHooks(dir, trusted); err != nil {
+			return nil, err
+		}
+		sess.SetGenieHomeDirectory(dir)
+	}
+	sess.SetWorkingDirectory(dir)
+	sess.SetWorkspaceTrusted(trusted)
+	// The scope named directories of the old working directory
+	sess.SetScope(nil)
+
+	// The new project may define the persona differently
+	g.initContextBudget(applySessionContext(context.Background(), sess))
+	return sess, nil
+}
@@ -0,0 +1,37 @@
+package genie_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/genie/genietest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeWorkingDirectory(t *testing.T) {
+	fixture := genietest.NewTestFixture(t)
+	fixture.StartAndGetSession().SetScope([]string{"services"})
+
+	other := t.TempDir()
+	session, err := fixture.Genie.ChangeWorkingDirectory(other, false)
+	require.NoError(t, err)
+	assert.Equal(t, other, session.GetWorkingDirectory())
+	assert.Equal(t, other, session.GetGenieHomeDirectory(), "the .genie home follows a session started where it works")
+	assert.False(t, session.IsWorkspaceTrusted())
+	assert.Empty(t, session.GetScope(), "the scope named directories of the old working directory")
+
+	current, err := fixture.Genie.GetSession()
+	require.NoError(t, err)
+	assert.Equal(t, other, current.GetWorkingDirectory())
+
+	_, err = fixture.Genie.ChangeWorkingDirectory(filepath.Join(other, "missing"), true)
+	assert.ErrorContains(t, err, "does not exist")
+
+	file := filepath.Join(other, "notes.md")
+	require.NoError(t, os.WriteFile(file, []byte("notes"), 0644))
+	_, err = fixture.Genie.ChangeWorkingDirectory(file, true)
+	assert.ErrorContains(t, err, "not a directory")
+	assert.Equal(t, other, current.GetWorkingDirectory(), "a failed change leaves the session where it was")
+}
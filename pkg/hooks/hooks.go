@@ -0,0 +1,207 @@
+// Package hooks runs the user's lifecycle hooks: shell commands that
+// .genie/hooks.yaml attaches to fixed points of a turn, for enforcing
+// policy. Each hook gets a JSON payload describing the action on stdin
+// and can let it proceed, block it or change it:
+//
+//	pre_prompt:            # before a message is sent to the model
+//	  - command: ./scripts/no-secrets.sh
+//	pre_tool:              # before a tool runs
+//	  - match: bash        # regexp on the tool name; omitted matches all
+//	    command: ./scripts/check-command.sh
+//	post_tool:             # after a tool ran
+//	  - match: writeFile|editFile
+//	    command: ./scripts/audit.sh
+//	pre_edit:              # before writeFile or editFile writes a file
+//	  - match: '\.go$'     # regexp on the file's path
+//	    command: ./scripts/lint-go.sh
+//	    timeout: 1m
+//
+// A hook that exits 0 lets the action proceed. Its stdout may be a JSON
+// object that blocks the action ({"decision": "block", "reason": "..."})
+// or replaces part of it: "prompt" for pre_prompt, "params" for pre_tool,
+// "result" for post_tool and "content" for pre_edit. A hook that exits 2
+// blocks the action, with its stderr as the reason. Any other failure is
+// reported and the action proceeds, so a broken hook does not stop work.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the hook configuration, read from the user's and the
+// project's .genie directories.
+const ConfigFileName = "hooks.yaml"
+
+// DefaultTimeout bounds a hook that sets no timeout.
+const DefaultTimeout = 30 * time.Second
+
+// Point is a place in a turn where hooks run.
+type Point string
+
+const (
+	PrePrompt Point = "pre_prompt"
+	PreTool   Point = "pre_tool"
+	PostTool  Point = "post_tool"
+	PreEdit   Point = "pre_edit"
+)
+
+// Hook is one command attached to a lifecycle point.
+type Hook struct {
+	// Match is a regexp on the tool name, or for pre_edit the file path;
+	// empty matches everything
+	Match   string `yaml:"match,omitempty"`
+	Command string `yaml:"command"`
+	// Timeout is a duration such as 10s; DefaultTimeout when empty
+	Timeout string `yaml:"timeout,omitempty"`
+
+	match   *regexp.Regexp
+	timeout time.Duration
+	// dir is where the command runs: the directory holding the .genie
+	// directory that configured it
+	dir string
+}
+
+// Config is the structure of hooks.yaml.
+type Config struct {
+	PrePrompt []Hook `yaml:"pre_prompt,omitempty"`
+	PreTool   []Hook `yaml:"pre_tool,omitempty"`
+	PostTool  []Hook `yaml:"post_tool,omitempty"`
+	PreEdit   []Hook `yaml:"pre_edit,omitempty"`
+
+	// Warn reports hooks that failed without blocking; nil logs them
+	Warn func(err error) `yaml:"-"`
+}
+
+// BlockedError is returned when a hook blocks an action.
+type BlockedError struct {
+	Point   Point
+	Command string
+	Reason  string
+}
+
+func (e *BlockedError) Error() string {
+	reason := e.Reason
+	if reason == "" {
+		reason = "no reason given"
+	}
+	return fmt.Sprintf("blocked by %s hook %q: %s", e.Point, e.Command, reason)
+}
+
+// IsBlocked reports whether err is a hook blocking an action.
+func IsBlocked(err error) bool {
+	var blocked *BlockedError
+	return errors.As(err, &blocked)
+}
+
+// LoadConfig reads the user's ~/.genie/hooks.yaml and then the project's
+// .genie/hooks.yaml; the project's hooks run after the user's. userOnly
+// skips the project file, for workspaces the user has not trusted: it
+// names commands Genie would run. It returns nil when neither file
+// configures a hook.
+func LoadConfig(projectRoot, userHome string, userOnly bool) (*Config, error) {
+	roots := []string{userHome}
+	if !userOnly && filepath.Clean(projectRoot) != filepath.Clean(userHome) {
+		roots = append(roots, projectRoot)
+	}
+	config := &Config{}
+	for _, root := range roots {
+		path := filepath.Join(root, ".genie", ConfigFileName)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var file Config
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		for point, hooks := range file.points() {
+			for i := range *hooks {
+				if err := (*hooks)[i].compile(root); err != nil {
+					return nil, fmt.Errorf("%s: %s hook %d: %w", path, point, i+1, err)
+				}
+			}
+		}
+		config.PrePrompt = append(config.PrePrompt, file.PrePrompt...)
+		config.PreTool = append(config.PreTool, file.PreTool...)
+		config.PostTool = append(config.PostTool, file.PostTool...)
+		config.PreEdit = append(config.PreEdit, file.PreEdit...)
+	}
+	if config.Empty() {
+		return nil, nil
+	}
+	return config, nil
+}
+
+// Empty reports whether the config has no hooks.
+func (c *Config) Empty() bool {
+	return c == nil || len(c.PrePrompt)+len(c.PreTool)+len(c.PostTool)+len(c.PreEdit) == 0
+}
+
+func (c *Config) points() map[Point]*[]Hook {
+	return map[Point]*[]Hook{
+		PrePrompt: &c.PrePrompt,
+		PreTool:   &c.PreTool,
+		PostTool:  &c.PostTool,
+		PreEdit:   &c.PreEdit,
+	}
+}
+
+func (h *Hook) compile(dir string) error {
+	if strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if h.Match != "" {
+		match, err := regexp.Compile(h.Match)
+		if err != nil {
+			return fmt.Errorf("invalid match: %w", err)
+		}
+		h.match = match
+	}
+	h.timeout = DefaultTimeout
+	if h.Timeout != "" {
+		timeout, err := time.ParseDuration(h.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q", h.Timeout)
+		}
+		h.timeout = timeout
+	}
+	h.dir = dir
+	return nil
+}
+
+func (h *Hook) matches(subject string) bool {
+	return h.match == nil || h.match.MatchString(subject)
+}
+
+type contextKey struct{}
+
+// WithConfig attaches the hooks of a session to ctx, for the tools run in
+// its turns.
+func WithConfig(ctx context.Context, config *Config) context.Context {
+	if config.Empty() {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, config)
+}
+
+// FromContext returns the hooks attached to ctx, or nil. The methods of a
+// nil Config let every action proceed unchanged.
+func FromContext(ctx context.Context) *Config {
+	if ctx == nil {
+		return nil
+	}
+	config, _ := ctx.Value(contextKey{}).(*Config)
+	return config
+}
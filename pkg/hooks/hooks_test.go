@@ -0,0 +1,185 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, root, content string) {
+	t.Helper()
+	dir := filepath.Join(root, ".genie")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(content), 0o644))
+}
+
+func skipOnWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in these tests are POSIX shell commands")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	home, project := t.TempDir(), t.TempDir()
+
+	config, err := LoadConfig(project, home, false)
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	writeConfig(t, home, "pre_tool:\n  - command: user-check\n")
+	writeConfig(t, project, "pre_tool:\n  - match: bash\n    command: project-check\n    timeout: 5s\npre_edit:\n  - command: lint\n")
+	config, err = LoadConfig(project, home, false)
+	require.NoError(t, err)
+	require.Len(t, config.PreTool, 2)
+	assert.Equal(t, "user-check", config.PreTool[0].Command)
+	assert.Equal(t, home, config.PreTool[0].dir)
+	assert.Equal(t, DefaultTimeout, config.PreTool[0].timeout)
+	assert.Equal(t, project, config.PreTool[1].dir)
+	assert.True(t, config.PreTool[1].matches("bash"))
+	assert.False(t, config.PreTool[1].matches("readFile"))
+	assert.Len(t, config.PreEdit, 1)
+
+	// Untrusted workspaces only get the user's hooks
+	config, err = LoadConfig(project, home, true)
+	require.NoError(t, err)
+	require.Len(t, config.PreTool, 1)
+	assert.Empty(t, config.PreEdit)
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"no command":  "pre_tool:\n  - match: bash\n",
+		"bad match":   "pre_tool:\n  - match: '('\n    command: check\n",
+		"bad timeout": "post_tool:\n  - command: check\n    timeout: soon\n",
+		"unparseable": "pre_tool: [",
+	} {
+		t.Run(name, func(t *testing.T) {
+			project := t.TempDir()
+			writeConfig(t, project, content)
+			_, err := LoadConfig(project, t.TempDir(), false)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRunPrePrompt(t *testing.T) {
+	skipOnWindows(t)
+	config := &Config{PrePrompt: []Hook{
+		{Command: `grep -q '"prompt":"fix it"' && echo '{"prompt": "fix it please"}'; exit 0`},
+	}}
+	require.NoError(t, config.PrePrompt[0].compile(t.TempDir()))
+
+	prompt, err := config.RunPrePrompt(context.Background(), "fix it")
+	require.NoError(t, err)
+	assert.Equal(t, "fix it please", prompt)
+
+	config.PrePrompt = append(config.PrePrompt, Hook{Command: `grep -q secret && { echo "no secrets in prompts" >&2; exit 2; }; exit 0`})
+	require.NoError(t, config.PrePrompt[1].compile(t.TempDir()))
+	_, err = config.RunPrePrompt(context.Background(), "my secret is 42")
+	assert.True(t, IsBlocked(err))
+	assert.ErrorContains(t, err, "no secrets in prompts")
+
+	var nilConfig *Config
+	prompt, err = nilConfig.RunPrePrompt(context.Background(), "unchanged")
+	require.NoError(t, err)
+	assert.Equal(t, "unchanged", prompt)
+}
+
+func TestRunPreTool(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	config := &Config{PreTool: []Hook{
+		{Match: "^bash$", Command: `grep -q 'rm -rf' && echo '{"decision": "block", "reason": "destructive"}'; exit 0`},
+		{Match: "^bash$", Command: `echo '{"params": {"command": "ls -la"}}'`},
+		{Match: "^never$", Command: "exit 2"},
+	}}
+	for i := range config.PreTool {
+		require.NoError(t, config.PreTool[i].compile(dir))
+	}
+
+	params, err := config.RunPreTool(context.Background(), "bash", map[string]any{"command": "ls", "_display_message": "Listing"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"command": "ls -la", "_display_message": "Listing"}, params)
+
+	_, err = config.RunPreTool(context.Background(), "bash", map[string]any{"command": "rm -rf /"})
+	var blocked *BlockedError
+	require.ErrorAs(t, err, &blocked)
+	assert.Equal(t, PreTool, blocked.Point)
+	assert.Equal(t, "destructive", blocked.Reason)
+
+	params, err = config.RunPreTool(context.Background(), "readFile", map[string]any{"path": "a.go"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"path": "a.go"}, params)
+}
+
+func TestRunPostTool(t *testing.T) {
+	skipOnWindows(t)
+	config := &Config{PostTool: []Hook{
+		{Command: `grep -q '"success":false' && { echo "tests are failing, fix them first" >&2; exit 2; }; exit 0`},
+	}}
+	require.NoError(t, config.PostTool[0].compile(t.TempDir()))
+
+	result := config.RunPostTool(context.Background(), "bash", nil, map[string]any{"output": "ok"}, true)
+	assert.Equal(t, map[string]any{"output": "ok"}, result)
+
+	result = config.RunPostTool(context.Background(), "bash", nil, map[string]any{"output": "FAIL"}, false)
+	assert.Equal(t, "FAIL", result["output"])
+	assert.Contains(t, result["hook_feedback"], "tests are failing, fix them first")
+}
+
+func TestRunPreEdit(t *testing.T) {
+	skipOnWindows(t)
+	workingDir := t.TempDir()
+	config := &Config{PreEdit: []Hook{
+		{Match: `\.go$`, Command: `input=$(cat); case "$input" in *'"path":"main.go"'*'"working_dir":"` + workingDir + `"'*) echo '{"content": "package main"}' ;; esac`},
+	}}
+	require.NoError(t, config.PreEdit[0].compile(t.TempDir()))
+	ctx := toolctx.WithWorkingDir(context.Background(), workingDir)
+
+	content, err := config.RunPreEdit(ctx, "writeFile", "main.go", []byte("package  main"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main", string(content))
+
+	content, err = config.RunPreEdit(ctx, "writeFile", "notes.md", []byte("# Notes"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes", string(content))
+}
+
+func TestRun_FailuresDoNotBlock(t *testing.T) {
+	skipOnWindows(t)
+	var warnings []error
+	config := &Config{
+		PreTool: []Hook{
+			{Command: "exit 1"},
+			{Command: "echo '{not json'"},
+			{Command: "sleep 5", Timeout: "50ms"},
+		},
+		Warn: func(err error) { warnings = append(warnings, err) },
+	}
+	for i := range config.PreTool {
+		require.NoError(t, config.PreTool[i].compile(t.TempDir()))
+	}
+
+	params, err := config.RunPreTool(context.Background(), "bash", map[string]any{"command": "ls"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"command": "ls"}, params)
+	require.Len(t, warnings, 3)
+	assert.ErrorContains(t, warnings[0], "failed")
+	assert.ErrorContains(t, warnings[1], "invalid JSON")
+	assert.ErrorContains(t, warnings[2], "timed out")
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	assert.Nil(t, FromContext(WithConfig(ctx, &Config{})))
+
+	config := &Config{PreTool: []Hook{{Command: "check"}}}
+	assert.Same(t, config, FromContext(WithConfig(ctx, config)))
+}
@@ -0,0 +1,226 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/kcaldas/genie/pkg/tools/process"
+)
+
+// blockExitCode is the exit code with which a hook blocks its action
+const blockExitCode = 2
+
+// reply is what a hook may print on stdout
+type reply struct {
+	Decision string         `json:"decision,omitempty"`
+	Reason   string         `json:"reason,omitempty"`
+	Prompt   *string        `json:"prompt,omitempty"`
+	Params   map[string]any `json:"params,omitempty"`
+	Result   map[string]any `json:"result,omitempty"`
+	Content  *string        `json:"content,omitempty"`
+}
+
+// RunPrePrompt runs the pre_prompt hooks on a message about to be sent to
+// the model and returns the message to send.
+func (c *Config) RunPrePrompt(ctx context.Context, prompt string) (string, error) {
+	if c == nil {
+		return prompt, nil
+	}
+	for i := range c.PrePrompt {
+		r, err := c.run(ctx, PrePrompt, &c.PrePrompt[i], map[string]any{"prompt": prompt})
+		if err != nil {
+			return "", err
+		}
+		if r.Prompt != nil {
+			prompt = *r.Prompt
+		}
+	}
+	return prompt, nil
+}
+
+// RunPreTool runs the pre_tool hooks matching tool on the parameters it is
+// about to run with and returns the parameters to run it with.
+func (c *Config) RunPreTool(ctx context.Context, tool string, params map[string]any) (map[string]any, error) {
+	if c == nil {
+		return params, nil
+	}
+	for i := range c.PreTool {
+		if !c.PreTool[i].matches(tool) {
+			continue
+		}
+		r, err := c.run(ctx, PreTool, &c.PreTool[i], map[string]any{"tool": tool, "params": hostless(params)})
+		if err != nil {
+			return nil, err
+		}
+		if r.Params != nil {
+			params = withHostParams(r.Params, params)
+		}
+	}
+	return params, nil
+}
+
+// RunPostTool runs the post_tool hooks matching tool on its result and
+// returns the result to hand the model. Blocking after the fact cannot
+// undo the call, so the hook's reason is added to the result as feedback
+// for the model instead.
+func (c *Config) RunPostTool(ctx context.Context, tool string, params, result map[string]any, success bool) map[string]any {
+	if c == nil {
+		return result
+	}
+	for i := range c.PostTool {
+		if !c.PostTool[i].matches(tool) {
+			continue
+		}
+		r, err := c.run(ctx, PostTool, &c.PostTool[i], map[string]any{
+			"tool":    tool,
+			"params":  hostless(params),
+			"result":  result,
+			"success": success,
+		})
+		var blocked *BlockedError
+		if errors.As(err, &blocked) {
+			result = withFeedback(result, blocked.Error())
+			continue
+		}
+		if r.Result != nil {
+			result = r.Result
+		}
+	}
+	return result
+}
+
+// RunPreEdit runs the pre_edit hooks matching path on the content tool is
+// about to write there and returns the content to write.
+func (c *Config) RunPreEdit(ctx context.Context, tool, path string, content []byte) ([]byte, error) {
+	if c == nil {
+		return content, nil
+	}
+	for i := range c.PreEdit {
+		if !c.PreEdit[i].matches(path) {
+			continue
+		}
+		r, err := c.run(ctx, PreEdit, &c.PreEdit[i], map[string]any{
+			"tool":    tool,
+			"path":    path,
+			"content": string(content),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if r.Content != nil {
+			content = []byte(*r.Content)
+		}
+	}
+	return content, nil
+}
+
+// run runs hook with payload and returns its reply. It returns an error
+// only when the hook blocks the action; other failures are reported
+// through Warn and yield an empty reply.
+func (c *Config) run(ctx context.Context, point Point, hook *Hook, payload map[string]any) (reply, error) {
+	payload["hook"] = point
+	if dir, ok := toolctx.WorkingDir(ctx); ok {
+		payload["working_dir"] = dir
+	}
+	if id, ok := toolctx.SessionID(ctx); ok {
+		payload["session_id"] = id
+	}
+	input, err := json.Marshal(payload)
+	if err != nil {
+		c.warn(fmt.Errorf("%s hook %q: failed to encode its input: %w", point, hook.Command, err))
+		return reply{}, nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	runCtx, cancel := context.WithTimeout(ctx, hook.timeout)
+	defer cancel()
+	cmd := process.DefaultShell().Command(runCtx, hook.Command, false)
+	cmd.Dir = hook.dir
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case runCtx.Err() != nil && ctx.Err() == nil:
+		c.warn(fmt.Errorf("%s hook %q timed out after %s", point, hook.Command, hook.timeout))
+		return reply{}, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == blockExitCode:
+		reason := strings.TrimSpace(stderr.String())
+		if reason == "" {
+			reason = strings.TrimSpace(stdout.String())
+		}
+		return reply{}, &BlockedError{Point: point, Command: hook.Command, Reason: reason}
+	default:
+		c.warn(fmt.Errorf("%s hook %q failed: %w: %s", point, hook.Command, err, strings.TrimSpace(stderr.String())))
+		return reply{}, nil
+	}
+
+	var r reply
+	if out := bytes.TrimSpace(stdout.Bytes()); bytes.HasPrefix(out, []byte("{")) {
+		if err := json.Unmarshal(out, &r); err != nil {
+			c.warn(fmt.Errorf("%s hook %q printed invalid JSON: %w", point, hook.Command, err))
+			return reply{}, nil
+		}
+	}
+	if strings.EqualFold(r.Decision, "block") {
+		return reply{}, &BlockedError{Point: point, Command: hook.Command, Reason: r.Reason}
+	}
+	return r, nil
+}
+
+func (c *Config) warn(err error) {
+	if c.Warn != nil {
+		c.Warn(err)
+		return
+	}
+	slog.Warn("Hook failed", "error", err)
+}
+
+// hostless drops the "_" parameters meant for the host, such as
+// _display_message, from what hooks see
+func hostless(params map[string]any) map[string]any {
+	filtered := make(map[string]any, len(params))
+	for k, v := range params {
+		if !strings.HasPrefix(k, "_") {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// withHostParams adds the host's "_" parameters of original back to the
+// parameters a hook replaced them with
+func withHostParams(replaced, original map[string]any) map[string]any {
+	params := make(map[string]any, len(replaced))
+	for k, v := range replaced {
+		params[k] = v
+	}
+	for k, v := range original {
+		if strings.HasPrefix(k, "_") {
+			params[k] = v
+		}
+	}
+	return params
+}
+
+func withFeedback(result map[string]any, feedback string) map[string]any {
+	withFeedback := make(map[string]any, len(result)+1)
+	for k, v := range result {
+		withFeedback[k] = v
+	}
+	withFeedback["hook_feedback"] = feedback
+	return withFeedback
+}
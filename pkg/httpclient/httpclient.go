@@ -0,0 +1,107 @@
+// Package httpclient builds the HTTP clients Genie talks to LLM providers
+// and the web with, so proxies, custom certificate authorities and TLS
+// settings apply the same way everywhere.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/logging"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	// CACertConfigKey names a PEM file of certificate authorities to trust
+	// on top of the system ones, e.g. a corporate proxy's.
+	CACertConfigKey = "GENIE_CA_CERT"
+	// InsecureConfigKey turns off TLS certificate verification. It is
+	// meant for debugging only.
+	InsecureConfigKey = "GENIE_TLS_INSECURE_SKIP_VERIFY"
+)
+
+// InsecureWarning is shown whenever certificate verification is off.
+const InsecureWarning = "WARNING: " + InsecureConfigKey + " is set: TLS certificates are NOT verified, so API keys and prompts can be intercepted. Use it for debugging only."
+
+var warnOnce sync.Once
+
+// New returns an HTTP client that uses the configured proxy
+// (HTTPS_PROXY, HTTP_PROXY and NO_PROXY, in upper or lower case), trusts
+// the certificate authorities in GENIE_CA_CERT besides the system ones,
+// and skips certificate verification when GENIE_TLS_INSECURE_SKIP_VERIFY
+// is set.
+func New(cfg config.Manager) (*http.Client, error) {
+	transport, err := NewTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// NewTransport returns the transport of New, for callers that wrap it.
+func NewTransport(cfg config.Manager) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxy := ProxyConfig(cfg).ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if path := cfg.GetStringWithDefault(CACertConfigKey, ""); path != "" {
+		pool, err := loadCertPool(path)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if Insecure(cfg) {
+		warnOnce.Do(func() {
+			logging.GetGlobalLogger().Warn(InsecureWarning)
+		})
+		tlsConfig.InsecureSkipVerify = true
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// Insecure reports whether TLS certificate verification is turned off.
+func Insecure(cfg config.Manager) bool {
+	return cfg.GetBoolWithDefault(InsecureConfigKey, false)
+}
+
+// ProxyConfig reads the proxy settings, preferring the upper case
+// variables like curl does for HTTPS_PROXY.
+func ProxyConfig(cfg config.Manager) *httpproxy.Config {
+	get := func(key string) string {
+		return cfg.GetStringWithDefault(key, cfg.GetStringWithDefault(strings.ToLower(key), ""))
+	}
+	return &httpproxy.Config{
+		HTTPProxy:  get("HTTP_PROXY"),
+		HTTPSProxy: get("HTTPS_PROXY"),
+		NoProxy:    get("NO_PROXY"),
+	}
+}
+
+// loadCertPool returns the system pool with the certificates of the PEM
+// file at path added
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s %s: %w", CACertConfigKey, path, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s %s holds no PEM certificates", CACertConfigKey, path)
+	}
+	return pool, nil
+}
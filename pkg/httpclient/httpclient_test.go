@@ -0,0 +1,96 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearNetworkEnv unsets the settings New reads, restoring them afterwards
+func clearNetworkEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy", CACertConfigKey, InsecureConfigKey} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	clearNetworkEnv(t)
+	t.Setenv("https_proxy", "http://proxy.internal:3128")
+	t.Setenv("NO_PROXY", "localhost,.corp.example")
+
+	transport, err := NewTransport(config.NewConfigManager())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.openai.com/v1/chat/completions", nil)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	require.NotNil(t, proxy)
+	assert.Equal(t, "proxy.internal:3128", proxy.Host)
+
+	req = httptest.NewRequest(http.MethodGet, "https://llm.corp.example/v1", nil)
+	proxy, err = transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxy)
+}
+
+func TestNew_CustomCA(t *testing.T) {
+	clearNetworkEnv(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The test server's certificate is self-signed, so it is refused...
+	client, err := New(config.NewConfigManager())
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	assert.ErrorContains(t, err, "certificate")
+
+	// ...unless GENIE_CA_CERT trusts it
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, certPEM, 0600))
+	t.Setenv(CACertConfigKey, caPath)
+	client, err = New(config.NewConfigManager())
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestNew_Insecure(t *testing.T) {
+	clearNetworkEnv(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	t.Setenv(InsecureConfigKey, "true")
+
+	cfg := config.NewConfigManager()
+	assert.True(t, Insecure(cfg))
+	client, err := New(cfg)
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestNew_BadCACert(t *testing.T) {
+	clearNetworkEnv(t)
+	t.Setenv(CACertConfigKey, filepath.Join(t.TempDir(), "missing.pem"))
+	_, err := New(config.NewConfigManager())
+	assert.ErrorContains(t, err, "failed to read GENIE_CA_CERT")
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+	t.Setenv(CACertConfigKey, notPEM)
+	_, err = New(config.NewConfigManager())
+	assert.ErrorContains(t, err, "holds no PEM certificates")
+}
@@ -0,0 +1,211 @@
+// Package journal appends a short digest of each session's work (files
+// touched, commands run, outcomes) to a daily journal file, for standups
+// and timesheets. The facts come from the session's audit log; the model
+// turns them and the conversation into a few lines of prose.
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+const (
+	// ConfigKey turns journaling on when set to true (default off).
+	ConfigKey = "GENIE_JOURNAL"
+
+	// DirConfigKey overrides where the daily journal files are written
+	// (default ~/.genie/journal).
+	DirConfigKey = "GENIE_JOURNAL_DIR"
+
+	// maxCommands caps the commands listed in the prompt and the fallback
+	// digest; a long session would otherwise drown the model in them
+	maxCommands = 30
+)
+
+// fileParams are the parameters naming the files a mutating tool changes
+var fileParams = map[string][]string{
+	"writeFile":     {"path"},
+	"editFile":      {"path"},
+	"appendFile":    {"path"},
+	"removeFile":    {"path"},
+	"makeDirectory": {"path"},
+	"gitRestore":    {"path"},
+	"copyFile":      {"destination"},
+	"moveFile":      {"source", "destination"},
+}
+
+// Command is a shell command the session ran.
+type Command struct {
+	Command string
+	// Outcome is "ok", "failed (exit 1)" and the like
+	Outcome string
+}
+
+// Activity is what a session did, read from its audit log.
+type Activity struct {
+	Files     []string
+	Commands  []Command
+	Commits   []string
+	ToolCalls int
+	Failures  int
+	Denied    int
+}
+
+// Empty reports whether the session executed no tool calls.
+func (a Activity) Empty() bool {
+	return a.ToolCalls == 0
+}
+
+// FromAudit collects the activity of the audited tool calls in entries.
+// Failed and denied calls touched nothing, so their files are left out.
+func FromAudit(entries []audit.Entry) Activity {
+	var activity Activity
+	files := make(map[string]bool)
+	for _, entry := range entries {
+		activity.ToolCalls++
+		if entry.Confirmation == audit.ConfirmationDenied {
+			activity.Denied++
+			continue
+		}
+		if entry.Status != audit.StatusOK {
+			activity.Failures++
+		}
+
+		switch entry.Tool {
+		case "bash":
+			if command, _ := entry.Params["command"].(string); command != "" {
+				activity.Commands = append(activity.Commands, Command{Command: command, Outcome: outcome(entry)})
+			}
+		case "gitCommit":
+			if message, _ := entry.Params["message"].(string); message != "" && entry.Status == audit.StatusOK {
+				subject, _, _ := strings.Cut(message, "\n")
+				activity.Commits = append(activity.Commits, subject)
+			}
+		}
+		if entry.Status != audit.StatusOK {
+			continue
+		}
+		for _, param := range fileParams[entry.Tool] {
+			if path, _ := entry.Params[param].(string); path != "" {
+				files[path] = true
+			}
+		}
+	}
+	for path := range files {
+		activity.Files = append(activity.Files, path)
+	}
+	sort.Strings(activity.Files)
+	return activity
+}
+
+func outcome(entry audit.Entry) string {
+	switch {
+	case entry.Status == audit.StatusOK:
+		return "ok"
+	case entry.ExitCode != nil:
+		return fmt.Sprintf("failed (exit %d)", *entry.ExitCode)
+	default:
+		return "failed"
+	}
+}
+
+// String lists the activity as Markdown bullets. It is the journal entry
+// when the model cannot write a digest.
+func (a Activity) String() string {
+	var b strings.Builder
+	if len(a.Files) > 0 {
+		fmt.Fprintf(&b, "- Files changed: %s\n", strings.Join(a.Files, ", "))
+	}
+	for _, commit := range a.Commits {
+		fmt.Fprintf(&b, "- Committed: %s\n", commit)
+	}
+	commands := a.Commands
+	if len(commands) > maxCommands {
+		commands = commands[len(commands)-maxCommands:]
+		fmt.Fprintf(&b, "- %d earlier commands not shown\n", len(a.Commands)-maxCommands)
+	}
+	for _, command := range commands {
+		fmt.Fprintf(&b, "- Ran `%s`: %s\n", oneLine(command.Command), command.Outcome)
+	}
+	fmt.Fprintf(&b, "- %d tool calls", a.ToolCalls)
+	if a.Failures > 0 {
+		fmt.Fprintf(&b, ", %d failed", a.Failures)
+	}
+	if a.Denied > 0 {
+		fmt.Fprintf(&b, ", %d denied", a.Denied)
+	}
+	return b.String()
+}
+
+// Prompt asks the model for the digest of a session, given its activity.
+func Prompt(activity Activity) string {
+	var b strings.Builder
+	b.WriteString("Write a journal entry for this session, for the user's standup notes and timesheet. ")
+	b.WriteString("In 2 to 5 Markdown bullets, say what was worked on, what changed and how it ended: ")
+	b.WriteString("what was finished, what failed or is still open. Name files and commands where they matter. ")
+	b.WriteString("Be factual and brief; do not call any tools. Reply with the bullets only.\n\n")
+	if activity.Empty() {
+		b.WriteString("No tools were run in this session.")
+	} else {
+		b.WriteString("What the session's tool calls did:\n")
+		b.WriteString(activity.String())
+	}
+	return b.String()
+}
+
+// Dir returns the directory of the daily journal files: GENIE_JOURNAL_DIR
+// or ~/.genie/journal.
+func Dir(cfg config.Manager) (string, error) {
+	if dir := strings.TrimSpace(cfg.GetStringWithDefault(DirConfigKey, "")); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".genie", "journal"), nil
+}
+
+// Path returns the journal file of the day now falls on.
+func Path(dir string, now time.Time) string {
+	return filepath.Join(dir, now.Format("2006-01-02")+".md")
+}
+
+// Append adds the digest of a session in project to the day's journal
+// file in dir and returns the file's path.
+func Append(dir, project, digest string, now time.Time) (string, error) {
+	path := Path(dir, now)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if info.Size() == 0 {
+		fmt.Fprintf(&b, "# %s\n", now.Format("Monday, 2 January 2006"))
+	}
+	fmt.Fprintf(&b, "\n## %s %s\n\n%s\n", now.Format("15:04"), project, strings.TrimSpace(digest))
+	if _, err := file.WriteString(b.String()); err != nil {
+		return "", fmt.Errorf("failed to write journal: %w", err)
+	}
+	return path, nil
+}
+
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
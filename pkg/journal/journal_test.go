@@ -0,0 +1,57 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromAudit(t *testing.T) {
+	exit2 := 2
+	activity := FromAudit([]audit.Entry{
+		{Tool: "readFile", Params: map[string]any{"path": "main.go"}, Status: audit.StatusOK},
+		{Tool: "editFile", Params: map[string]any{"path": "main.go"}, Status: audit.StatusOK},
+		{Tool: "writeFile", Params: map[string]any{"path": "README.md"}, Status: audit.StatusOK},
+		{Tool: "moveFile", Params: map[string]any{"source": "a.go", "destination": "b.go"}, Status: audit.StatusOK},
+		{Tool: "writeFile", Params: map[string]any{"path": "secret.env"}, Status: audit.StatusOK, Confirmation: audit.ConfirmationDenied},
+		{Tool: "removeFile", Params: map[string]any{"path": "gone.txt"}, Status: audit.StatusFailed},
+		{Tool: "bash", Params: map[string]any{"command": "go test ./..."}, Status: audit.StatusFailed, ExitCode: &exit2},
+		{Tool: "bash", Params: map[string]any{"command": "go test ./..."}, Status: audit.StatusOK},
+		{Tool: "gitCommit", Params: map[string]any{"message": "Fix the parser\n\nDetails"}, Status: audit.StatusOK},
+	})
+
+	assert.Equal(t, []string{"README.md", "a.go", "b.go", "main.go"}, activity.Files)
+	assert.Equal(t, []Command{{"go test ./...", "failed (exit 2)"}, {"go test ./...", "ok"}}, activity.Commands)
+	assert.Equal(t, []string{"Fix the parser"}, activity.Commits)
+	assert.Equal(t, 9, activity.ToolCalls)
+	assert.Equal(t, 2, activity.Failures)
+	assert.Equal(t, 1, activity.Denied)
+
+	assert.Equal(t, "- Files changed: README.md, a.go, b.go, main.go\n"+
+		"- Committed: Fix the parser\n"+
+		"- Ran `go test ./...`: failed (exit 2)\n"+
+		"- Ran `go test ./...`: ok\n"+
+		"- 9 tool calls, 2 failed, 1 denied", activity.String())
+	assert.Contains(t, Prompt(activity), activity.String())
+	assert.True(t, FromAudit(nil).Empty())
+}
+
+func TestAppend(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	morning := time.Date(2026, 10, 16, 9, 5, 0, 0, time.Local)
+
+	path, err := Append(dir, "genie", "- Fixed the parser\n", morning)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "2026-10-16.md"), path)
+	_, err = Append(dir, "website", "- Updated the docs", morning.Add(5*time.Hour))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Friday, 16 October 2026\n\n## 09:05 genie\n\n- Fixed the parser\n\n## 14:05 website\n\n- Updated the docs\n", string(data))
+}
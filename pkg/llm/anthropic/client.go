@@ -11,6 +11,7 @@ import (
 
 	anthropic_sdk "github.com/anthropics/anthropic-sdk-go"
 	anthropic_option "github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/anthropics/anthropic-sdk-go/packages/param"
 	"github.com/anthropics/anthropic-sdk-go/packages/ssestream"
 	"github.com/anthropics/anthropic-sdk-go/shared/constant"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/fileops"
+	"github.com/kcaldas/genie/pkg/httpclient"
 	llmshared "github.com/kcaldas/genie/pkg/llm/shared"
 	"github.com/kcaldas/genie/pkg/logging"
 	"github.com/kcaldas/genie/pkg/template"
@@ -37,6 +39,16 @@ const (
 	// regularly tens of minutes, so 5m would be cold most of the time. The
 	// 2x write cost (vs 1.25x for 5m) breaks even after ~2 reads.
 	promptCacheTTLKey = "ANTHROPIC_PROMPT_CACHE_TTL"
+
+	// showThinkingConfigKey turns on extended thinking and publishes the
+	// thinking blocks, like GENIE_SHOW_THOUGHTS does for every provider.
+	showThinkingConfigKey = "ANTHROPIC_SHOW_THINKING"
+
+	// thinkingBudgetConfigKey caps the tokens Claude may spend thinking
+	// per request when thinking is shown. Anthropic requires at least 1024.
+	thinkingBudgetConfigKey = "ANTHROPIC_THINKING_BUDGET"
+	defaultThinkingBudget   = 4096
+	minThinkingBudget       = 1024
 )
 
 // promptCachingEnabled reports whether to attach cache_control markers to
@@ -279,8 +291,15 @@ func (c *Client) ensureInitialized(ctx context.Context) error {
 		return c.initErr
 	}
 
+	httpClient, err := httpclient.New(c.config)
+	if err != nil {
+		c.initErr = ai.NonRetryable(err)
+		return c.initErr
+	}
+
 	opts := []anthropic_option.RequestOption{
 		anthropic_option.WithAPIKey(apiKey),
+		anthropic_option.WithHTTPClient(httpClient),
 	}
 	if baseURL := strings.TrimSpace(c.config.GetStringWithDefault("ANTHROPIC_BASE_URL", "")); baseURL != "" {
 		opts = append(opts, anthropic_option.WithBaseURL(baseURL))
@@ -329,6 +348,7 @@ func (c *Client) buildParams(prompt ai.Prompt) (anthropic_sdk.MessageNewParams,
 	}
 
 	c.applyGenerationConfig(&params, prompt)
+	c.applyThinkingConfig(&params, prompt)
 	c.applyToolingConfig(&params, prompt)
 
 	return params, nil
@@ -412,12 +432,8 @@ func (c *Client) parseResponse(resp *anthropic_sdk.Message, showThinking bool) (
 				textBuilder.WriteString(block.Text)
 			}
 		case "thinking":
-			if showThinking && strings.TrimSpace(block.Thinking) != "" {
-				notification := events.NotificationEvent{
-					Message:     strings.TrimSpace(block.Thinking),
-					ContentType: "thought",
-				}
-				c.eventBus.Publish(notification.Topic(), notification)
+			if showThinking {
+				llmshared.PublishThought(c.eventBus, block.Thinking)
 			}
 		case "tool_use":
 			toolCalls = append(toolCalls, toolCall{
@@ -445,6 +461,42 @@ func (c *Client) applyGenerationConfig(params *anthropic_sdk.MessageNewParams, p
 	} else if topP > 0 {
 		params.TopP = anthropic_sdk.Float(float64(topP))
 	}
+
+	params.StopSequences = llmshared.StopSequences(prompt)
+	if prompt.FrequencyPenalty != 0 || prompt.PresencePenalty != 0 {
+		c.logger.Debug("frequency and presence penalties are not supported; ignoring", "model", params.Model)
+	}
+}
+
+// applyThinkingConfig enables extended thinking when thoughts are to be
+// shown and the model supports it. The thinking budget counts towards
+// max_tokens, so max_tokens grows to leave the answer its own room, and
+// sampling is left at its defaults, which thinking requires.
+func (c *Client) applyThinkingConfig(params *anthropic_sdk.MessageNewParams, prompt ai.Prompt) {
+	if !llmshared.ShowThoughts(c.config, prompt, showThinkingConfigKey) || !supportsThinking(string(params.Model)) {
+		return
+	}
+	budget := int64(c.config.GetIntWithDefault(thinkingBudgetConfigKey, defaultThinkingBudget))
+	if budget < minThinkingBudget {
+		budget = minThinkingBudget
+	}
+	params.Thinking = anthropic_sdk.ThinkingConfigParamOfEnabled(budget)
+	params.MaxTokens += budget
+	if params.Temperature.Valid() || params.TopP.Valid() {
+		c.logger.Debug("temperature and top_p are not supported with thinking; using defaults", "model", params.Model)
+		params.Temperature = param.Opt[float64]{}
+		params.TopP = param.Opt[float64]{}
+	}
+}
+
+// supportsThinking reports whether a model accepts extended thinking:
+// Claude 3.7 Sonnet and the Claude 4 family onwards.
+func supportsThinking(model string) bool {
+	model = strings.ToLower(model)
+	if strings.HasPrefix(model, "claude-3-") {
+		return strings.HasPrefix(model, "claude-3-7")
+	}
+	return strings.HasPrefix(model, "claude-")
 }
 
 func (c *Client) applyToolingConfig(params *anthropic_sdk.MessageNewParams, prompt ai.Prompt) {
@@ -672,6 +724,7 @@ func (c *Client) publishTokenCount(modelName string, tokenCount *ai.TokenCount)
 		InputTokens:  tokenCount.InputTokens,
 		OutputTokens: tokenCount.OutputTokens,
 		TotalTokens:  tokenCount.TotalTokens,
+		Estimate:     true,
 	}
 	c.eventBus.Publish(event.Topic(), event)
 }
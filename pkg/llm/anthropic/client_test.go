@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"sync"
 	"testing"
+	"time"
 
 	anthropic_sdk "github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -462,3 +463,74 @@ func TestClient_GenerateContent_EmptyResponseWithoutTools(t *testing.T) {
 	defer mockAPI.mu.Unlock()
 	require.Len(t, mockAPI.requests, 1)
 }
+
+func TestClient_GenerateContent_SendsStopSequences(t *testing.T) {
+	mockAPI := &mockMessageClient{
+		t:         t,
+		responses: []*anthropic_sdk.Message{newTextMessage("msg-1", "ok")},
+	}
+	client, err := NewClient(&events.NoOpEventBus{}, WithMessageClient(mockAPI))
+	require.NoError(t, err)
+
+	prompt := ai.Prompt{
+		Text:             "Say hello.",
+		ModelName:        "claude-sonnet-4-20250514",
+		StopSequences:    []string{"END", ""},
+		FrequencyPenalty: 0.5,
+	}
+	_, err = client.GenerateContent(context.Background(), prompt, false)
+	require.NoError(t, err)
+
+	require.Len(t, mockAPI.requests, 1)
+	assert.Equal(t, []string{"END"}, mockAPI.requests[0].StopSequences)
+}
+
+func TestClient_ShowThoughtsEnablesThinking(t *testing.T) {
+	t.Setenv("ANTHROPIC_THINKING_BUDGET", "")
+	thinkingResponse := newTextMessage("msg-1", "42")
+	thinkingResponse.Content = append([]anthropic_sdk.ContentBlockUnion{{Type: "thinking", Thinking: "Six sevens are forty-two."}}, thinkingResponse.Content...)
+	mockAPI := &mockMessageClient{
+		t:         t,
+		responses: []*anthropic_sdk.Message{thinkingResponse, newTextMessage("msg-2", "42")},
+	}
+
+	bus := events.NewEventBus()
+	thoughts := make(chan string, 4)
+	bus.Subscribe("chat.notification", func(e interface{}) {
+		if n, ok := e.(events.NotificationEvent); ok && n.ContentType == "thought" {
+			thoughts <- n.Message
+		}
+	})
+	rawClient, err := NewClient(bus, WithMessageClient(mockAPI))
+	require.NoError(t, err)
+
+	prompt := ai.Prompt{
+		Text:         "What is 6x7?",
+		ModelName:    "claude-sonnet-4-20250514",
+		MaxTokens:    256,
+		Temperature:  0.2,
+		ShowThoughts: true,
+	}
+	resp, err := rawClient.GenerateContent(context.Background(), prompt, false)
+	require.NoError(t, err)
+	assert.Equal(t, "42", resp)
+
+	request := mockAPI.requests[0]
+	require.NotNil(t, request.Thinking.OfEnabled)
+	assert.Equal(t, int64(defaultThinkingBudget), request.Thinking.OfEnabled.BudgetTokens)
+	assert.Equal(t, int64(256+defaultThinkingBudget), request.MaxTokens, "the budget must not eat into the answer")
+	assert.False(t, request.Temperature.Valid(), "thinking requires the default temperature")
+	select {
+	case thought := <-thoughts:
+		assert.Equal(t, "Six sevens are forty-two.", thought)
+	case <-time.After(time.Second):
+		t.Fatal("thinking block was not published")
+	}
+
+	// Models without extended thinking are left alone
+	prompt.ModelName = "claude-3-5-sonnet-20241022"
+	_, err = rawClient.GenerateContent(context.Background(), prompt, false)
+	require.NoError(t, err)
+	assert.Nil(t, mockAPI.requests[1].Thinking.OfEnabled)
+	assert.Equal(t, int64(256), mockAPI.requests[1].MaxTokens)
+}
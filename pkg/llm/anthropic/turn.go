@@ -28,6 +28,8 @@ type turnState struct {
 	messages    []anthropic_sdk.MessageParam
 	hasHandlers bool
 	toolUsed    bool
+	// showThinking publishes the thinking blocks as notifications
+	showThinking bool
 }
 
 func (c *Client) newTurn(prompt ai.Prompt) (*turnState, error) {
@@ -40,6 +42,8 @@ func (c *Client) newTurn(prompt ai.Prompt) (*turnState, error) {
 		params:      params,
 		messages:    append([]anthropic_sdk.MessageParam(nil), params.Messages...),
 		hasHandlers: len(prompt.Handlers) > 0,
+
+		showThinking: llmshared.ShowThoughts(c.config, prompt, showThinkingConfigKey),
 	}, nil
 }
 
@@ -64,8 +68,7 @@ func (t *turnState) stepBlocking(ctx context.Context, params anthropic_sdk.Messa
 
 	c.publishUsage(string(params.Model), resp.Usage)
 
-	showThinking := c.config.GetBoolWithDefault("ANTHROPIC_SHOW_THINKING", false)
-	responseText, toolCalls := c.parseResponse(resp, showThinking)
+	responseText, toolCalls := c.parseResponse(resp, t.showThinking)
 	responseText = strings.TrimSpace(responseText)
 
 	if len(toolCalls) == 0 {
@@ -103,7 +106,6 @@ func (t *turnState) stepStreaming(ctx context.Context, params anthropic_sdk.Mess
 	defer stream.Close()
 
 	acc := &anthropic_sdk.Message{}
-	showThinking := c.config.GetBoolWithDefault("ANTHROPIC_SHOW_THINKING", false)
 
 	for stream.Next() {
 		event := stream.Current()
@@ -117,12 +119,8 @@ func (t *turnState) stepStreaming(ctx context.Context, params anthropic_sdk.Mess
 			case anthropic_sdk.ThinkingDelta:
 				thinking := strings.TrimSpace(delta.Thinking)
 				if thinking != "" {
-					if showThinking {
-						notification := events.NotificationEvent{
-							Message:     thinking,
-							ContentType: "thought",
-						}
-						c.eventBus.Publish(notification.Topic(), notification)
+					if t.showThinking {
+						llmshared.PublishThought(c.eventBus, delta.Thinking)
 					}
 					emit(&ai.StreamChunk{Thinking: thinking})
 				}
@@ -205,7 +203,8 @@ func (t *turnState) recordAssistantStep(message anthropic_sdk.MessageParam, tool
 
 // AddToolResults converts executed tool results into a tool_result user
 // message correlated by tool_use ID (plus any image payloads, which
-// follow as separate user messages).
+// follow as separate user messages). Documents are read from the text the
+// tool extracted, without their inline data.
 func (t *turnState) AddToolResults(ctx context.Context, results []llmshared.ToolResult) error {
 	c := t.client
 
@@ -242,6 +241,13 @@ func (t *turnState) AddToolResults(ctx context.Context, results []llmshared.Tool
 				imageMessages = append(imageMessages, anthropic_sdk.NewUserMessage(blocks...))
 			}
 		}
+		if res.Call.Name == "viewDocument" {
+			_, sanitized, err := toolpayload.Extract(result)
+			if err != nil {
+				return fmt.Errorf("invalid viewDocument response: %w", err)
+			}
+			result = sanitized
+		}
 
 		payload, err := json.Marshal(result)
 		if err != nil {
@@ -12,6 +12,8 @@ import (
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/fileops"
+	"github.com/kcaldas/genie/pkg/httpclient"
+	"github.com/kcaldas/genie/pkg/llm/mock"
 	llmshared "github.com/kcaldas/genie/pkg/llm/shared"
 	"github.com/kcaldas/genie/pkg/llm/shared/toolpayload"
 	"github.com/kcaldas/genie/pkg/template"
@@ -24,8 +26,13 @@ type Backend string
 const (
 	BackendVertexAI          Backend    = "vertex"
 	BackendGeminiAPI         Backend    = "gemini"
+	BackendMock              Backend    = "mock"
 	roleFunctionResponse     genai.Role = "user"
 	defaultMaxToolIterations            = 200
+
+	// showThoughtsConfigKey publishes Gemini's thoughts as notifications,
+	// like GENIE_SHOW_THOUGHTS does for every provider
+	showThoughtsConfigKey = "GEMINI_SHOW_THOUGHTS"
 )
 
 // Client implements the ai.Gen interface using Google's unified GenAI package
@@ -41,6 +48,10 @@ type Client struct {
 	callGenerateContentFn func(ctx context.Context, modelName string, contents []*genai.Content, config *genai.GenerateContentConfig, handlers map[string]ai.HandlerFunc) (*genai.GenerateContentResponse, error)
 	// Allows tests to intercept streaming generate content calls.
 	generateContentStreamFn func(ctx context.Context, modelName string, contents []*genai.Content, config *genai.GenerateContentConfig) iter.Seq2[*genai.GenerateContentResponse, error]
+	// Allows tests to intercept context cache creation.
+	createCachedContentFn func(ctx context.Context, modelName string, config *genai.CreateCachedContentConfig) (*genai.CachedContent, error)
+	// Explicit context caches created by this client
+	contextCache contextCache
 	// Lazy initialization
 	mu          sync.Mutex
 	initialized bool
@@ -54,6 +65,10 @@ func NewClient(eventBus events.EventBus) (ai.Gen, error) {
 	configManager := config.NewConfigManager()
 	// Determine backend preference and check basic configuration
 	backend := Backend(configManager.GetStringWithDefault("GENAI_BACKEND", "gemini"))
+	if backend == BackendMock {
+		// Scripted answers, for demos and tests without API keys
+		return mock.NewClient(eventBus)
+	}
 	// Check that at least one backend has basic configuration
 	hasGeminiKey := configManager.GetStringWithDefault("GEMINI_API_KEY", "") != ""
 	hasVertexProject := configManager.GetStringWithDefault("GOOGLE_CLOUD_PROJECT", "") != ""
@@ -120,6 +135,10 @@ func (g *Client) ensureInitialized(ctx context.Context) error {
 // createClientWithBackend attempts to create a client with the specified backend
 func createClientWithBackend(configManager config.Manager, backend Backend) (*genai.Client, Backend, error) {
 	ctx := context.Background()
+	httpClient, err := httpclient.New(configManager)
+	if err != nil {
+		return nil, "", err
+	}
 	switch backend {
 	case BackendGeminiAPI:
 		// Try Gemini API (API key based)
@@ -128,8 +147,9 @@ func createClientWithBackend(configManager config.Manager, backend Backend) (*ge
 			return nil, "", fmt.Errorf("GEMINI_API_KEY not configured")
 		}
 		cfg := &genai.ClientConfig{
-			APIKey:  apiKey,
-			Backend: genai.BackendGeminiAPI,
+			APIKey:     apiKey,
+			Backend:    genai.BackendGeminiAPI,
+			HTTPClient: httpClient,
 		}
 		cfg.HTTPOptions.Headers = ai.DefaultHTTPHeaders()
 		client, err := genai.NewClient(ctx, cfg)
@@ -145,11 +165,19 @@ func createClientWithBackend(configManager config.Manager, backend Backend) (*ge
 		}
 		location := configManager.GetStringWithDefault("GOOGLE_CLOUD_LOCATION", "us-central1")
 		cfg := &genai.ClientConfig{
-			Project:  projectID,
-			Location: location,
-			Backend:  genai.BackendVertexAI,
+			Project:    projectID,
+			Location:   location,
+			Backend:    genai.BackendVertexAI,
+			HTTPClient: httpClient,
 		}
 		cfg.HTTPOptions.Headers = ai.DefaultHTTPHeaders()
+		// With our own HTTP client, the SDK leaves authentication to us
+		if err := cfg.UseDefaultCredentials(); err != nil {
+			return nil, "", fmt.Errorf("error creating Vertex AI client: %w", err)
+		}
+		if quotaProject, err := cfg.Credentials.QuotaProjectID(ctx); err == nil && quotaProject != "" {
+			cfg.HTTPOptions.Headers.Set("X-Goog-User-Project", quotaProject)
+		}
 		client, err := genai.NewClient(ctx, cfg)
 		if err != nil {
 			return nil, "", fmt.Errorf("error creating Vertex AI client: %w", err)
@@ -247,11 +275,11 @@ func (g *Client) GetStatus() *ai.Status {
 	}
 }
 func (g *Client) generateContentWithPrompt(ctx context.Context, p ai.Prompt, debug bool) (string, error) {
-	turn := g.newTurn(p)
+	turn := g.newTurn(ctx, p)
 	return llmshared.RunToolLoop(ctx, turn, p.Handlers, g.loopConfig(p), nil)
 }
 func (g *Client) generateContentStreamWithPrompt(ctx context.Context, p ai.Prompt) (ai.Stream, error) {
-	turn := g.newTurn(p)
+	turn := g.newTurn(ctx, p)
 	streamCtx, cancel := context.WithCancel(ctx)
 	ch := make(chan llmshared.StreamResult, 1)
 	go func() {
@@ -343,25 +371,23 @@ func (g *Client) emitStreamChunk(ctx context.Context, ch chan<- llmshared.Stream
 		return ctx.Err()
 	}
 }
-func (g *Client) responseToStreamChunk(resp *genai.GenerateContentResponse) *ai.StreamChunk {
+
+// responseToStreamChunk converts a streamed response. With showThoughts set,
+// thought parts are also published as notifications.
+func (g *Client) responseToStreamChunk(resp *genai.GenerateContentResponse, showThoughts bool) *ai.StreamChunk {
 	if resp == nil || len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
 		return nil
 	}
 	chunk := &ai.StreamChunk{}
 	var textParts []string
 	var thoughtParts []string
-	showThoughts := g.Config.GetBoolWithDefault("GEMINI_SHOW_THOUGHTS", false)
 	for _, part := range resp.Candidates[0].Content.Parts {
 		switch {
 		case part.Text != "":
 			if part.Thought {
 				thoughtParts = append(thoughtParts, part.Text)
 				if showThoughts {
-					notification := events.NotificationEvent{
-						Message:     fmt.Sprintf("Thinking: %s", part.Text),
-						ContentType: "thought",
-					}
-					g.EventBus.Publish(notification.Topic(), notification)
+					llmshared.PublishThought(g.EventBus, part.Text)
 				}
 			} else {
 				textParts = append(textParts, part.Text)
@@ -454,12 +480,15 @@ func buildGeminiDocumentContent(doc *toolpayload.Payload) *genai.Content {
 	}
 	return genai.NewContentFromParts(parts, genai.RoleUser)
 }
-func (g *Client) joinContentParts(content *genai.Content) string {
+
+// joinContentParts returns the text of a response. With showThoughts set,
+// thought parts are also published as notifications; streamed content
+// passes false, as its thoughts went out chunk by chunk.
+func (g *Client) joinContentParts(content *genai.Content, showThoughts bool) string {
 	var (
 		textParts    []string
 		thoughtParts []string
 		extraParts   []string
-		showThoughts = g.Config.GetBoolWithDefault("GEMINI_SHOW_THOUGHTS", false)
 	)
 	for _, part := range content.Parts {
 		switch {
@@ -467,11 +496,7 @@ func (g *Client) joinContentParts(content *genai.Content) string {
 			if part.Thought {
 				thoughtParts = append(thoughtParts, part.Text)
 				if showThoughts {
-					notification := events.NotificationEvent{
-						Message:     fmt.Sprintf("Thinking: %s", part.Text),
-						ContentType: "thought",
-					}
-					g.EventBus.Publish(notification.Topic(), notification)
+					llmshared.PublishThought(g.EventBus, part.Text)
 				}
 			} else {
 				textParts = append(textParts, part.Text)
@@ -566,7 +591,9 @@ func (g *Client) countTokensWithPrompt(ctx context.Context, p ai.Prompt) (*ai.To
 	return tokenCount, nil
 }
 
-func (g *Client) publishUsageMetadata(modelName string, usage *genai.GenerateContentResponseUsageMetadata) *ai.TokenCount {
+// publishUsageMetadata reports a response's token usage. cacheWriteTokens is
+// the size of a context cache created for the request, if any.
+func (g *Client) publishUsageMetadata(modelName string, usage *genai.GenerateContentResponseUsageMetadata, cacheWriteTokens int32) *ai.TokenCount {
 	if usage == nil {
 		return nil
 	}
@@ -585,6 +612,8 @@ func (g *Client) publishUsageMetadata(modelName string, usage *genai.GenerateCon
 		CachedTokens:         usage.CachedContentTokenCount,
 		CacheReadInputTokens: usage.CachedContentTokenCount,
 		ToolUseTokens:        usage.ToolUsePromptTokenCount,
+
+		CacheCreationInputTokens: cacheWriteTokens,
 	}
 	g.EventBus.Publish(tokenCountEvent.Topic(), tokenCountEvent)
 	if g.Config.GetBoolWithDefault("GENIE_TOKEN_DEBUG", false) {
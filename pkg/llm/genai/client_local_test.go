@@ -72,7 +72,7 @@ func TestClientJoinContentPartsWithFunctionResponse(t *testing.T) {
 		genai.NewPartFromFunctionResponse("todo_update", map[string]any{"status": "complete"}),
 	}, genai.RoleModel)
 
-	result := client.joinContentParts(content)
+	result := client.joinContentParts(content, false)
 	assert.Contains(t, result, "todo_update")
 	assert.Contains(t, result, "complete")
 }
@@ -264,6 +264,50 @@ func TestBuildGenerateConfigNeverForcesToolCalls(t *testing.T) {
 		"tool calling must stay in the default AUTO mode; forcing ANY makes Gemini return empty or malformed candidates")
 }
 
+func TestBuildGenerateConfigRequestsThoughtsWhenShown(t *testing.T) {
+	t.Setenv("GEMINI_INCLUDE_THOUGHTS", "")
+	t.Setenv("GEMINI_SHOW_THOUGHTS", "")
+	t.Setenv("GENIE_SHOW_THOUGHTS", "")
+	client := &Client{
+		Config:   config.NewConfigManager(),
+		EventBus: &events.NoOpEventBus{},
+	}
+
+	prompt := ai.Prompt{Text: "test", ModelName: "gemini-2.5-flash"}
+	cfg := client.buildGenerateConfig(prompt)
+	assert.True(t, cfg == nil || cfg.ThinkingConfig == nil)
+
+	prompt.ShowThoughts = true
+	cfg = client.buildGenerateConfig(prompt)
+	require.NotNil(t, cfg)
+	require.NotNil(t, cfg.ThinkingConfig)
+	assert.True(t, cfg.ThinkingConfig.IncludeThoughts)
+}
+
+func TestBuildGenerateConfigMapsOutputControls(t *testing.T) {
+	client := &Client{
+		Config:   config.NewConfigManager(),
+		EventBus: &events.NoOpEventBus{},
+	}
+
+	prompt := ai.Prompt{
+		Text:             "test",
+		ModelName:        "gemini-2.5-flash",
+		MaxTokens:        128,
+		StopSequences:    []string{"", "END"},
+		FrequencyPenalty: 0.5,
+		PresencePenalty:  -0.5,
+	}
+	cfg := client.buildGenerateConfig(prompt)
+	require.NotNil(t, cfg)
+	assert.Equal(t, int32(128), cfg.MaxOutputTokens)
+	assert.Equal(t, []string{"END"}, cfg.StopSequences)
+	require.NotNil(t, cfg.FrequencyPenalty)
+	assert.Equal(t, float32(0.5), *cfg.FrequencyPenalty)
+	require.NotNil(t, cfg.PresencePenalty)
+	assert.Equal(t, float32(-0.5), *cfg.PresencePenalty)
+}
+
 func TestMalformedFunctionCallRetry_NonStreaming(t *testing.T) {
 	client := &Client{
 		Config:   config.NewConfigManager(),
@@ -0,0 +1,167 @@
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"google.golang.org/genai"
+)
+
+const (
+	// contextCacheConfigKey enables explicit Gemini context caching of the
+	// persona instruction, system prompt files and tool declarations.
+	// Default OFF: explicit caches are billed for storage per hour, which
+	// only pays off for large, stable system prompts.
+	contextCacheConfigKey = "GEMINI_CONTEXT_CACHE"
+
+	// contextCacheTTLKey sets how long a created cache lives (Go duration,
+	// default "1h"). Caches are recreated shortly before they expire.
+	contextCacheTTLKey = "GEMINI_CONTEXT_CACHE_TTL"
+
+	// contextCacheMinTokensKey skips caching prefixes estimated below this
+	// size. Gemini rejects caches under a model-specific minimum (1024-4096
+	// tokens), and small prefixes are cheap anyway.
+	contextCacheMinTokensKey = "GEMINI_CONTEXT_CACHE_MIN_TOKENS"
+
+	defaultContextCacheTTL       = time.Hour
+	defaultContextCacheMinTokens = 4096
+
+	// contextCacheRenewMargin recreates a cache this long before it expires
+	// so a turn never references a cache that disappears mid-turn.
+	contextCacheRenewMargin = 2 * time.Minute
+)
+
+// contextCache remembers the CachedContent created for each distinct
+// model + system prefix + tools combination.
+type contextCache struct {
+	mu      sync.Mutex
+	entries map[string]contextCacheEntry
+}
+
+type contextCacheEntry struct {
+	name    string
+	expires time.Time
+	failed  bool // creation was rejected; don't retry this prefix
+}
+
+func (g *Client) contextCachingEnabled() bool {
+	return g.Config.GetBoolWithDefault(contextCacheConfigKey, false)
+}
+
+func (g *Client) contextCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(g.Config.GetStringWithDefault(contextCacheTTLKey, ""))
+	if err != nil || ttl <= 0 {
+		return defaultContextCacheTTL
+	}
+	return ttl
+}
+
+// buildCacheableSystemParts returns the stable part of the system prompt:
+// the persona instruction and the system prompt files. Per-user context is
+// left out so it can change without invalidating the cache.
+func buildCacheableSystemParts(p ai.Prompt) []*genai.Part {
+	return buildSystemParts(ai.Prompt{Instruction: p.Instruction, SystemPromptFiles: p.SystemPromptFiles})
+}
+
+// cachedContentFor returns the name of a CachedContent holding the prompt's
+// system prefix and tools, creating one when needed. It returns an empty
+// name when caching is disabled, not worthwhile, or failed; created is the
+// cache's token count when this call created it, so the turn can bill it.
+func (g *Client) cachedContentFor(ctx context.Context, p ai.Prompt, cfg *genai.GenerateContentConfig) (name string, created int32) {
+	if !g.contextCachingEnabled() || p.DisableCache || cfg == nil {
+		return "", 0
+	}
+	systemParts := buildCacheableSystemParts(p)
+	if len(systemParts) == 0 {
+		return "", 0
+	}
+
+	toolsJSON, err := json.Marshal(cfg.Tools)
+	if err != nil {
+		return "", 0
+	}
+	var prefix strings.Builder
+	for _, part := range systemParts {
+		prefix.WriteString(part.Text)
+		prefix.WriteString("\x00")
+	}
+	// Rough chars/4 estimate; good enough to skip obviously small prefixes
+	if (prefix.Len()+len(toolsJSON))/4 < g.Config.GetIntWithDefault(contextCacheMinTokensKey, defaultContextCacheMinTokens) {
+		return "", 0
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(p.ModelName + "\x00"))
+	hash.Write([]byte(prefix.String()))
+	hash.Write(toolsJSON)
+	key := hex.EncodeToString(hash.Sum(nil))
+
+	// Holding the lock while creating keeps concurrent turns from creating
+	// duplicate caches for the same prefix.
+	g.contextCache.mu.Lock()
+	defer g.contextCache.mu.Unlock()
+	if g.contextCache.entries == nil {
+		g.contextCache.entries = make(map[string]contextCacheEntry)
+	}
+
+	if entry, ok := g.contextCache.entries[key]; ok {
+		if entry.failed {
+			return "", 0
+		}
+		if time.Until(entry.expires) > contextCacheRenewMargin {
+			return entry.name, 0
+		}
+	}
+
+	ttl := g.contextCacheTTL()
+	cacheConfig := &genai.CreateCachedContentConfig{
+		TTL:               ttl,
+		DisplayName:       "genie-" + key[:12],
+		SystemInstruction: genai.NewContentFromParts(systemParts, genai.RoleUser),
+		Tools:             cfg.Tools,
+	}
+	var cached *genai.CachedContent
+	if g.createCachedContentFn != nil {
+		cached, err = g.createCachedContentFn(ctx, p.ModelName, cacheConfig)
+	} else {
+		cached, err = g.Client.Caches.Create(ctx, p.ModelName, cacheConfig)
+	}
+	if err != nil || cached == nil || cached.Name == "" {
+		log.Printf("WARNING: Gemini context cache not created for model %s, continuing without it: %v", p.ModelName, err)
+		g.contextCache.entries[key] = contextCacheEntry{failed: true}
+		return "", 0
+	}
+
+	expires := cached.ExpireTime
+	if expires.IsZero() {
+		expires = time.Now().Add(ttl)
+	}
+	g.contextCache.entries[key] = contextCacheEntry{name: cached.Name, expires: expires}
+	if cached.UsageMetadata != nil {
+		created = cached.UsageMetadata.TotalTokenCount
+	}
+	return cached.Name, created
+}
+
+// useCachedContent points the turn at a CachedContent. Gemini rejects
+// requests that repeat the cached system instruction or tools, so they are
+// dropped from the config, and only the per-user context stays inline.
+func (t *turnState) useCachedContent(name string, p ai.Prompt) {
+	t.config.CachedContent = name
+	t.config.SystemInstruction = nil
+	t.config.Tools = nil
+
+	t.contents = t.contents[:0]
+	if userCtx := strings.TrimSpace(p.SystemPromptUserContext); userCtx != "" {
+		t.contents = append(t.contents, genai.NewContentFromParts(
+			[]*genai.Part{genai.NewPartFromText(p.SystemPromptUserContext)}, genai.RoleUser))
+	}
+	t.contents = append(t.contents, buildUserContent(p))
+}
@@ -0,0 +1,139 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func newContextCacheTestClient(t *testing.T) *Client {
+	t.Helper()
+	t.Setenv(contextCacheConfigKey, "true")
+	t.Setenv(contextCacheMinTokensKey, "100")
+	return &Client{
+		Config:   config.NewConfigManager(),
+		EventBus: &events.NoOpEventBus{},
+	}
+}
+
+func largeInstructionPrompt() ai.Prompt {
+	return ai.Prompt{
+		Text:                    "hello",
+		ModelName:               "gemini-2.5-pro",
+		Instruction:             strings.Repeat("You are a careful engineer. ", 100),
+		SystemPromptUserContext: "cwd: /tmp/project",
+	}
+}
+
+func TestContextCache_CreatedOnceAndReused(t *testing.T) {
+	client := newContextCacheTestClient(t)
+
+	creates := 0
+	client.createCachedContentFn = func(ctx context.Context, model string, cfg *genai.CreateCachedContentConfig) (*genai.CachedContent, error) {
+		creates++
+		require.NotNil(t, cfg.SystemInstruction)
+		assert.Contains(t, cfg.SystemInstruction.Parts[0].Text, "careful engineer")
+		return &genai.CachedContent{
+			Name:          "cachedContents/abc",
+			ExpireTime:    time.Now().Add(time.Hour),
+			UsageMetadata: &genai.CachedContentUsageMetadata{TotalTokenCount: 700},
+		}, nil
+	}
+
+	var configs []*genai.GenerateContentConfig
+	var contents [][]*genai.Content
+	client.callGenerateContentFn = func(ctx context.Context, model string, c []*genai.Content, cfg *genai.GenerateContentConfig, handlers map[string]ai.HandlerFunc) (*genai.GenerateContentResponse, error) {
+		configs = append(configs, cfg)
+		contents = append(contents, c)
+		return &genai.GenerateContentResponse{
+			Candidates: []*genai.Candidate{{
+				Content: genai.NewContentFromParts([]*genai.Part{genai.NewPartFromText("ok")}, genai.RoleModel),
+			}},
+		}, nil
+	}
+
+	for range 2 {
+		_, err := client.generateContentWithPrompt(context.Background(), largeInstructionPrompt(), false)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, creates)
+	require.Len(t, configs, 2)
+	for i, cfg := range configs {
+		assert.Equal(t, "cachedContents/abc", cfg.CachedContent)
+		assert.Nil(t, cfg.SystemInstruction)
+		assert.Nil(t, cfg.Tools)
+
+		// User context stays inline, followed by the user message
+		require.Len(t, contents[i], 2)
+		assert.Equal(t, "cwd: /tmp/project", contents[i][0].Parts[0].Text)
+		assert.Equal(t, "hello", contents[i][1].Parts[0].Text)
+	}
+}
+
+func TestContextCache_SkippedForSmallPrefixOrDisabledCache(t *testing.T) {
+	client := newContextCacheTestClient(t)
+	client.createCachedContentFn = func(ctx context.Context, model string, cfg *genai.CreateCachedContentConfig) (*genai.CachedContent, error) {
+		t.Fatal("cache should not be created")
+		return nil, nil
+	}
+
+	small := largeInstructionPrompt()
+	small.Instruction = "Be brief."
+	turn := client.newTurn(context.Background(), small)
+	assert.Empty(t, turn.config.CachedContent)
+
+	disabled := largeInstructionPrompt()
+	disabled.DisableCache = true
+	turn = client.newTurn(context.Background(), disabled)
+	assert.Empty(t, turn.config.CachedContent)
+	assert.NotNil(t, turn.config.SystemInstruction)
+}
+
+func TestContextCache_FailedCreationIsNotRetried(t *testing.T) {
+	client := newContextCacheTestClient(t)
+
+	creates := 0
+	client.createCachedContentFn = func(ctx context.Context, model string, cfg *genai.CreateCachedContentConfig) (*genai.CachedContent, error) {
+		creates++
+		return nil, errors.New("cached content too small")
+	}
+
+	for range 2 {
+		turn := client.newTurn(context.Background(), largeInstructionPrompt())
+		assert.Empty(t, turn.config.CachedContent)
+		assert.NotNil(t, turn.config.SystemInstruction)
+	}
+	assert.Equal(t, 1, creates)
+}
+
+func TestContextCache_CacheWriteBilledOnce(t *testing.T) {
+	client := newContextCacheTestClient(t)
+	client.createCachedContentFn = func(ctx context.Context, model string, cfg *genai.CreateCachedContentConfig) (*genai.CachedContent, error) {
+		return &genai.CachedContent{
+			Name:          "cachedContents/abc",
+			UsageMetadata: &genai.CachedContentUsageMetadata{TotalTokenCount: 700},
+		}, nil
+	}
+
+	turn := client.newTurn(context.Background(), largeInstructionPrompt())
+	assert.Equal(t, int32(700), turn.cacheWriteTokens)
+
+	usage := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        750,
+		CachedContentTokenCount: 700,
+		CandidatesTokenCount:    10,
+		TotalTokenCount:         760,
+	}
+	require.NotNil(t, turn.publishUsage(usage))
+	assert.Zero(t, turn.cacheWriteTokens)
+}
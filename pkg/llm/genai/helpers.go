@@ -29,6 +29,19 @@ func buildSystemParts(p ai.Prompt) []*genai.Part {
 }
 
 func (g *Client) buildInitialContents(p ai.Prompt) []*genai.Content {
+	contents := make([]*genai.Content, 0, 2)
+
+	if systemParts := buildSystemParts(p); len(systemParts) > 0 {
+		contents = append(contents, genai.NewContentFromParts(systemParts, genai.RoleUser))
+	}
+
+	contents = append(contents, buildUserContent(p))
+	return contents
+}
+
+// buildUserContent builds the user message of the prompt, text first and
+// then any attached images.
+func buildUserContent(p ai.Prompt) *genai.Content {
 	userParts := []*genai.Part{genai.NewPartFromText(p.Text)}
 	for _, img := range p.Images {
 		if img == nil {
@@ -42,15 +55,7 @@ func (g *Client) buildInitialContents(p ai.Prompt) []*genai.Content {
 		})
 	}
 
-	userContent := genai.NewContentFromParts(userParts, genai.RoleUser)
-	contents := make([]*genai.Content, 0, 2)
-
-	if systemParts := buildSystemParts(p); len(systemParts) > 0 {
-		contents = append(contents, genai.NewContentFromParts(systemParts, genai.RoleUser))
-	}
-
-	contents = append(contents, userContent)
-	return contents
+	return genai.NewContentFromParts(userParts, genai.RoleUser)
 }
 
 func (g *Client) buildGenerateConfig(p ai.Prompt) *genai.GenerateContentConfig {
@@ -90,13 +95,29 @@ func (g *Client) buildGenerateConfig(p ai.Prompt) *genai.GenerateContentConfig {
 		cfg.TopP = &topP
 		used = true
 	}
+	if stop := shared.StopSequences(p); len(stop) > 0 {
+		cfg.StopSequences = stop
+		used = true
+	}
+	if p.FrequencyPenalty != 0 {
+		penalty := p.FrequencyPenalty
+		cfg.FrequencyPenalty = &penalty
+		used = true
+	}
+	if p.PresencePenalty != 0 {
+		penalty := p.PresencePenalty
+		cfg.PresencePenalty = &penalty
+		used = true
+	}
 	if p.MaxTokens > 0 || p.Temperature > 0 || p.TopP > 0 {
 		count := int32(1)
 		cfg.CandidateCount = count
 		used = true
 	}
 
-	includeThoughts := g.Config.GetBoolWithDefault("GEMINI_INCLUDE_THOUGHTS", false)
+	// Thoughts can only be shown when they are requested
+	includeThoughts := g.Config.GetBoolWithDefault("GEMINI_INCLUDE_THOUGHTS", false) ||
+		shared.ShowThoughts(g.Config, p, showThoughtsConfigKey)
 	isGemini3 := strings.Contains(strings.ToLower(p.ModelName), "gemini-3")
 
 	// Gemini 3 requires ThinkingLevel (cannot disable thinking)
@@ -24,15 +24,37 @@ type turnState struct {
 	config    *genai.GenerateContentConfig
 	stepCount int
 	toolUsed  bool
+	// cacheWriteTokens is the size of a context cache created for this
+	// turn; it is billed with the first response's usage.
+	cacheWriteTokens int32
+	showThoughts     bool
 }
 
-func (g *Client) newTurn(p ai.Prompt) *turnState {
-	return &turnState{
+func (g *Client) newTurn(ctx context.Context, p ai.Prompt) *turnState {
+	turn := &turnState{
 		client:    g,
 		modelName: p.ModelName,
 		contents:  g.buildInitialContents(p),
 		config:    g.buildGenerateConfig(p),
+
+		showThoughts: llmshared.ShowThoughts(g.Config, p, showThoughtsConfigKey),
+	}
+	if name, created := g.cachedContentFor(ctx, p, turn.config); name != "" {
+		turn.useCachedContent(name, p)
+		turn.cacheWriteTokens = created
+	}
+	return turn
+}
+
+// publishUsage reports a response's usage, adding any cache this turn
+// created to the first report.
+func (t *turnState) publishUsage(usage *genai.GenerateContentResponseUsageMetadata) *ai.TokenCount {
+	if usage == nil {
+		return nil
 	}
+	cacheWrite := t.cacheWriteTokens
+	t.cacheWriteTokens = 0
+	return t.client.publishUsageMetadata(t.modelName, usage, cacheWrite)
 }
 
 // Step runs one model request. With emit set it streams; otherwise it
@@ -60,7 +82,7 @@ func (t *turnState) stepBlocking(ctx context.Context, emit func(*ai.StreamChunk)
 	if err != nil {
 		return llmshared.StepOutcome{}, fmt.Errorf("error generating content: %w", err)
 	}
-	g.publishUsageMetadata(t.modelName, result.UsageMetadata)
+	t.publishUsage(result.UsageMetadata)
 
 	// Malformed function calls: feed the failure back to the model and
 	// ask the loop to re-run the step.
@@ -125,7 +147,7 @@ func (t *turnState) stepStreaming(ctx context.Context, emit func(*ai.StreamChunk
 			lastFinishMessage = resp.Candidates[0].FinishMessage
 		}
 
-		if chunk := g.responseToStreamChunk(resp); chunk != nil {
+		if chunk := g.responseToStreamChunk(resp, t.showThoughts); chunk != nil {
 			emit(chunk)
 		}
 	}
@@ -155,7 +177,7 @@ func (t *turnState) stepStreaming(ctx context.Context, emit func(*ai.StreamChunk
 		return llmshared.StepOutcome{RetryStep: true}, nil
 	}
 
-	if tc := g.publishUsageMetadata(t.modelName, lastUsageMetadata); tc != nil {
+	if tc := t.publishUsage(lastUsageMetadata); tc != nil {
 		emit(&ai.StreamChunk{TokenCount: tc})
 	}
 
@@ -163,7 +185,7 @@ func (t *turnState) stepStreaming(ctx context.Context, emit func(*ai.StreamChunk
 	if !contentHasFunctionCalls(accumulated) {
 		// The text already reached the consumer via emit; an empty final
 		// step simply ends the stream.
-		return llmshared.StepOutcome{Text: t.client.joinContentParts(accumulated)}, nil
+		return llmshared.StepOutcome{Text: t.client.joinContentParts(accumulated, false)}, nil
 	}
 
 	// Interim text notifications are a blocking-mode concern; in
@@ -193,7 +215,7 @@ func (t *turnState) finalOutcome(content *genai.Content) (llmshared.StepOutcome,
 		}
 		return llmshared.StepOutcome{}, fmt.Errorf("no content in response candidate")
 	}
-	text := t.client.joinContentParts(content)
+	text := t.client.joinContentParts(content, t.showThoughts)
 	if strings.TrimSpace(text) == "" {
 		if t.toolUsed {
 			return llmshared.StepOutcome{}, nil
@@ -219,7 +241,7 @@ func (t *turnState) recordAssistantStep(content *genai.Content, notifyInterimTex
 	compactPriorContents(t.contents)
 
 	if notifyInterimText {
-		if contentStr := strings.TrimSpace(g.joinContentParts(content)); contentStr != "" {
+		if contentStr := strings.TrimSpace(g.joinContentParts(content, t.showThoughts)); contentStr != "" {
 			notification := events.NotificationEvent{Message: contentStr}
 			g.EventBus.Publish(notification.Topic(), notification)
 		}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	llmshared "github.com/kcaldas/genie/pkg/llm/shared"
 	"github.com/kcaldas/genie/pkg/llm/shared/toolpayload"
@@ -67,7 +68,7 @@ func NewClient(eventBus events.EventBus, opts ...Option) (ai.Gen, error) {
 	}
 
 	if strings.TrimSpace(client.BaseURL) == "" {
-		client.BaseURL = client.resolveBaseURL()
+		client.BaseURL = BaseURL(client.Config)
 	}
 
 	if strings.TrimSpace(client.BaseURL) == "" {
@@ -378,6 +379,16 @@ func (c *Client) applyGenerationConfig(req *chatRequest, prompt ai.Prompt, mode
 		value := float32(topP)
 		req.TopP = &value
 	}
+
+	req.Stop = llmshared.StopSequences(prompt)
+	if prompt.FrequencyPenalty != 0 {
+		value := prompt.FrequencyPenalty
+		req.FrequencyPenalty = &value
+	}
+	if prompt.PresencePenalty != 0 {
+		value := prompt.PresencePenalty
+		req.PresencePenalty = &value
+	}
 }
 
 func (c *Client) sendChat(ctx context.Context, req chatRequest) (*chatResponse, error) {
@@ -603,14 +614,17 @@ func (c *Client) buildTokenCount(usage *usage) *ai.TokenCount {
 	}
 }
 
-func (c *Client) resolveBaseURL() string {
-	if env := strings.TrimSpace(c.Config.GetStringWithDefault("GENIE_LMSTUDIO_BASE_URL", "")); env != "" {
+// BaseURL returns the configured LM Studio API URL, ending in /v1:
+// GENIE_LMSTUDIO_BASE_URL, else LMSTUDIO_BASE_URL or LM_STUDIO_BASE_URL,
+// else the local default.
+func BaseURL(cfg config.Manager) string {
+	if env := strings.TrimSpace(cfg.GetStringWithDefault("GENIE_LMSTUDIO_BASE_URL", "")); env != "" {
 		return ensureV1Suffix(env)
 	}
-	if env := strings.TrimSpace(c.Config.GetStringWithDefault("LMSTUDIO_BASE_URL", "")); env != "" {
+	if env := strings.TrimSpace(cfg.GetStringWithDefault("LMSTUDIO_BASE_URL", "")); env != "" {
 		return ensureV1Suffix(env)
 	}
-	if env := strings.TrimSpace(c.Config.GetStringWithDefault("LM_STUDIO_BASE_URL", "")); env != "" {
+	if env := strings.TrimSpace(cfg.GetStringWithDefault("LM_STUDIO_BASE_URL", "")); env != "" {
 		return ensureV1Suffix(env)
 	}
 	return ensureV1Suffix(defaultBaseURL)
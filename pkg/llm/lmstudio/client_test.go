@@ -73,6 +73,48 @@ func TestClient_GenerateContent_SimpleResponse(t *testing.T) {
 	require.Len(t, mockHTTP.requests, 1)
 }
 
+func TestClient_GenerateContent_SendsOutputControls(t *testing.T) {
+	t.Parallel()
+
+	mockHTTP := newMockHTTPClient(t, func(call int, req chatRequest) chatResponse {
+		assert.Equal(t, []string{"END"}, req.Stop)
+		require.NotNil(t, req.FrequencyPenalty)
+		assert.Equal(t, float32(0.5), *req.FrequencyPenalty)
+		assert.Nil(t, req.PresencePenalty)
+		require.NotNil(t, req.MaxTokens)
+		assert.Equal(t, int32(64), *req.MaxTokens)
+		return chatResponse{
+			Model: "local-model",
+			Choices: []chatChoice{{
+				Message: responseMessage{
+					Role:    "assistant",
+					Content: responseContent{parts: []contentPart{{Type: "text", Text: "ok"}}},
+				},
+				FinishReason: "stop",
+			}},
+		}
+	})
+
+	rawClient, err := NewClient(
+		&events.NoOpEventBus{},
+		WithBaseURL("http://test.local"),
+		WithHTTPClient(mockHTTP),
+		WithLogger(logging.NewDisabledLogger()),
+	)
+	require.NoError(t, err)
+
+	prompt := ai.Prompt{
+		Text:             "Say hello.",
+		ModelName:        "local-model",
+		MaxTokens:        64,
+		StopSequences:    []string{"END"},
+		FrequencyPenalty: 0.5,
+	}
+	_, err = rawClient.GenerateContent(context.Background(), prompt, false)
+	require.NoError(t, err)
+	require.Len(t, mockHTTP.requests, 1)
+}
+
 func TestClient_GenerateContent_WithToolCall(t *testing.T) {
 	t.Parallel()
 
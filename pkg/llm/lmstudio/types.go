@@ -23,15 +23,18 @@ const (
 )
 
 type chatRequest struct {
-	Model          string           `json:"model"`
-	Messages       []chatMessage    `json:"messages"`
-	Stream         bool             `json:"stream"`
-	Temperature    *float32         `json:"temperature,omitempty"`
-	MaxTokens      *int32           `json:"max_tokens,omitempty"`
-	TopP           *float32         `json:"top_p,omitempty"`
-	Tools          []toolDefinition `json:"tools,omitempty"`
-	ToolChoice     *string          `json:"tool_choice,omitempty"`
-	ResponseFormat *responseFormat  `json:"response_format,omitempty"`
+	Model            string           `json:"model"`
+	Messages         []chatMessage    `json:"messages"`
+	Stream           bool             `json:"stream"`
+	Temperature      *float32         `json:"temperature,omitempty"`
+	MaxTokens        *int32           `json:"max_tokens,omitempty"`
+	TopP             *float32         `json:"top_p,omitempty"`
+	Stop             []string         `json:"stop,omitempty"`
+	FrequencyPenalty *float32         `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float32         `json:"presence_penalty,omitempty"`
+	Tools            []toolDefinition `json:"tools,omitempty"`
+	ToolChoice       *string          `json:"tool_choice,omitempty"`
+	ResponseFormat   *responseFormat  `json:"response_format,omitempty"`
 }
 
 type chatMessage struct {
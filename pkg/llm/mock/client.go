@@ -0,0 +1,211 @@
+// Package mock is a scripted LLM backend. It answers from a YAML scenario
+// instead of a model, making the scenario's tool calls through the real
+// tools, so the TUI can be demoed, bugs reproduced and confirmation flows
+// tested without API keys.
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+)
+
+// ScenarioConfigKey names the scenario file; the built-in demo scenario is
+// used when it is not set.
+const ScenarioConfigKey = "GENIE_MOCK_SCENARIO"
+
+var _ ai.Gen = (*Client)(nil)
+
+// Client is an ai.Gen that answers from a scenario.
+type Client struct {
+	scenario *Scenario
+	source   string
+
+	mu   sync.Mutex
+	used []bool
+}
+
+// NewClient creates a client for the configured scenario. It publishes no
+// events: its tools publish their own through their handlers.
+func NewClient(_ events.EventBus) (ai.Gen, error) {
+	path := config.NewConfigManager().GetStringWithDefault(ScenarioConfigKey, "")
+	if path == "" {
+		scenario, err := ParseScenario(demoScenario)
+		if err != nil {
+			return nil, fmt.Errorf("invalid demo scenario: %w", err)
+		}
+		return NewClientFromScenario(scenario, "the demo scenario"), nil
+	}
+	scenario, err := LoadScenario(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientFromScenario(scenario, path), nil
+}
+
+// NewClientFromScenario creates a client that answers from scenario;
+// source names it in the status.
+func NewClientFromScenario(scenario *Scenario, source string) *Client {
+	return &Client{scenario: scenario, source: source, used: make([]bool, len(scenario.Turns))}
+}
+
+// GenerateContent answers with the turn that matches the message.
+func (c *Client) GenerateContent(ctx context.Context, p ai.Prompt, debug bool, args ...string) (string, error) {
+	return c.answer(ctx, p, ai.StringsToAttr(args))
+}
+
+// GenerateContentAttr answers with the turn that matches the message.
+func (c *Client) GenerateContentAttr(ctx context.Context, p ai.Prompt, debug bool, attrs []ai.Attr) (string, error) {
+	return c.answer(ctx, p, attrs)
+}
+
+// GenerateContentStream streams the answer word by word.
+func (c *Client) GenerateContentStream(ctx context.Context, p ai.Prompt, debug bool, args ...string) (ai.Stream, error) {
+	return c.GenerateContentAttrStream(ctx, p, debug, ai.StringsToAttr(args))
+}
+
+// GenerateContentAttrStream streams the answer word by word.
+func (c *Client) GenerateContentAttrStream(ctx context.Context, p ai.Prompt, debug bool, attrs []ai.Attr) (ai.Stream, error) {
+	response, err := c.answer(ctx, p, attrs)
+	if err != nil {
+		return nil, err
+	}
+	return newWordStream(response), nil
+}
+
+// CountTokens estimates four characters per token.
+func (c *Client) CountTokens(ctx context.Context, p ai.Prompt, debug bool, args ...string) (*ai.TokenCount, error) {
+	return c.CountTokensAttr(ctx, p, debug, ai.StringsToAttr(args))
+}
+
+// CountTokensAttr estimates four characters per token.
+func (c *Client) CountTokensAttr(ctx context.Context, p ai.Prompt, debug bool, attrs []ai.Attr) (*ai.TokenCount, error) {
+	length := len(p.Instruction) + len(p.Text)
+	for _, attr := range attrs {
+		length += len(attr.Value)
+	}
+	tokens := int32(length/4) + 1
+	return &ai.TokenCount{TotalTokens: tokens, InputTokens: tokens}, nil
+}
+
+// GetStatus reports the scenario in use.
+func (c *Client) GetStatus() *ai.Status {
+	return &ai.Status{
+		Connected: true,
+		Backend:   "mock",
+		Model:     "mock",
+		Message:   "scripted answers from " + c.source,
+	}
+}
+
+// answer finds the turn for the message, makes its tool calls and renders
+// its response
+func (c *Client) answer(ctx context.Context, p ai.Prompt, attrs []ai.Attr) (string, error) {
+	message := messageOf(attrs)
+	turn := c.nextTurn(message)
+	if turn == nil {
+		if c.scenario.Default == "" {
+			return "", fmt.Errorf("mock: no scripted turn for %q in %s", message, c.source)
+		}
+		return c.scenario.Default, nil
+	}
+
+	if turn.Delay > 0 {
+		select {
+		case <-time.After(turn.Delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	for _, call := range turn.ToolCalls {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		handler, ok := p.Handlers[call.Name]
+		if !ok {
+			return "", fmt.Errorf("mock: scripted tool %q is not available to %q", call.Name, p.Name)
+		}
+		args, err := jsonArgs(call.Args)
+		if err != nil {
+			return "", fmt.Errorf("mock: arguments of %q: %w", call.Name, err)
+		}
+		// A failed tool is reported to the model, which carries on
+		handler(ctx, args)
+	}
+	if turn.Error != "" {
+		return "", fmt.Errorf("%s", turn.Error)
+	}
+	return ai.RenderTemplateString(turn.Response, map[string]string{"message": message})
+}
+
+// nextTurn claims the first unused turn that matches message
+func (c *Client) nextTurn(message string) *Turn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.scenario.Turns {
+		turn := &c.scenario.Turns[i]
+		if c.used[i] || !turn.matches(message) {
+			continue
+		}
+		c.used[i] = !turn.Repeat
+		return turn
+	}
+	return nil
+}
+
+// messageOf finds the user message among the prompt data
+func messageOf(attrs []ai.Attr) string {
+	for _, attr := range attrs {
+		if attr.Key == "message" {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// jsonArgs gives the scripted arguments the types a model's JSON would
+// decode to, as tools expect
+func jsonArgs(args map[string]any) (map[string]any, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	decoded := map[string]any{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// wordStream yields the response a word at a time, spaces kept
+type wordStream struct {
+	words []string
+}
+
+func newWordStream(text string) *wordStream {
+	return &wordStream{words: strings.SplitAfter(text, " ")}
+}
+
+func (s *wordStream) Recv() (*ai.StreamChunk, error) {
+	for len(s.words) > 0 {
+		word := s.words[0]
+		s.words = s.words[1:]
+		if word != "" {
+			return &ai.StreamChunk{Text: word}, nil
+		}
+	}
+	return nil, io.EOF
+}
+
+func (s *wordStream) Close() error {
+	s.words = nil
+	return nil
+}
@@ -0,0 +1,157 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, scenario string) *Client {
+	t.Helper()
+	parsed, err := ParseScenario([]byte(scenario))
+	require.NoError(t, err)
+	return NewClientFromScenario(parsed, "test")
+}
+
+func chat(message string) []ai.Attr {
+	return []ai.Attr{{Key: "context", Value: "earlier turns"}, {Key: "message", Value: message}}
+}
+
+func TestClientAnswersFromTheScenario(t *testing.T) {
+	client := newTestClient(t, `
+default: "I don't know about {{.message}}"
+turns:
+  - match: hello
+    response: "Hi! You said: {{.message}}"
+  - match: hello
+    response: Hello again
+  - match: ping
+    repeat: true
+    response: pong
+  - response: anything else, once
+`)
+	ctx := context.Background()
+	answer := func(message string) string {
+		t.Helper()
+		response, err := client.GenerateContentAttr(ctx, ai.Prompt{Name: "chat"}, false, chat(message))
+		require.NoError(t, err)
+		return response
+	}
+
+	assert.Equal(t, "Hi! You said: hello there", answer("hello there"))
+	assert.Equal(t, "Hello again", answer("HELLO"))
+	assert.Equal(t, "pong", answer("ping"))
+	assert.Equal(t, "pong", answer("ping"))
+	assert.Equal(t, "anything else, once", answer("hello"))
+	assert.Equal(t, "I don't know about {{.message}}", answer("weather"), "the default is not a template")
+}
+
+func TestClientFailsWithoutATurnOrDefault(t *testing.T) {
+	client := newTestClient(t, "turns:\n  - {match: hi, response: hello}\n")
+
+	_, err := client.GenerateContent(context.Background(), ai.Prompt{Name: "chat"}, false, "message", "bye")
+	assert.EqualError(t, err, `mock: no scripted turn for "bye" in test`)
+}
+
+func TestClientMakesScriptedToolCalls(t *testing.T) {
+	client := newTestClient(t, `
+turns:
+  - match: list
+    tool_calls:
+      - name: listFiles
+        args: {path: ., max_depth: 1}
+      - name: bash
+        args: {command: ls}
+    response: listed
+  - match: missing
+    tool_calls:
+      - name: deleteEverything
+    response: never
+  - match: broken
+    error: "429 rate limited"
+`)
+	var calls []string
+	var depth any
+	prompt := ai.Prompt{Name: "chat", Handlers: map[string]ai.HandlerFunc{
+		"listFiles": func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			calls = append(calls, "listFiles")
+			depth = args["max_depth"]
+			return map[string]any{"success": true}, nil
+		},
+		"bash": func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			calls = append(calls, "bash "+args["command"].(string))
+			return nil, errors.New("declined")
+		},
+	}}
+	ctx := context.Background()
+
+	response, err := client.GenerateContentAttr(ctx, prompt, false, chat("list"))
+	require.NoError(t, err)
+	assert.Equal(t, "listed", response)
+	assert.Equal(t, []string{"listFiles", "bash ls"}, calls)
+	assert.Equal(t, float64(1), depth, "arguments have the types of decoded JSON")
+
+	_, err = client.GenerateContentAttr(ctx, prompt, false, chat("missing"))
+	assert.EqualError(t, err, `mock: scripted tool "deleteEverything" is not available to "chat"`)
+
+	_, err = client.GenerateContentAttr(ctx, prompt, false, chat("broken"))
+	assert.EqualError(t, err, "429 rate limited")
+}
+
+func TestClientStreamsWordByWord(t *testing.T) {
+	client := newTestClient(t, "turns:\n  - response: one two  three\n")
+
+	stream, err := client.GenerateContentAttrStream(context.Background(), ai.Prompt{Name: "chat"}, false, chat("count"))
+	require.NoError(t, err)
+	var chunks []string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk.Text)
+	}
+	assert.Equal(t, []string{"one ", "two ", " ", "three"}, chunks)
+	assert.Equal(t, "one two  three", strings.Join(chunks, ""))
+}
+
+func TestClientDelayStopsWithTheContext(t *testing.T) {
+	client := newTestClient(t, "turns:\n  - {delay: 1m, response: late}\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GenerateContentAttr(ctx, ai.Prompt{Name: "chat"}, false, chat("hi"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewClientLoadsTheConfiguredScenario(t *testing.T) {
+	t.Setenv(ScenarioConfigKey, "")
+	gen, err := NewClient(events.NewEventBus())
+	require.NoError(t, err)
+	assert.Equal(t, "scripted answers from the demo scenario", gen.GetStatus().Message)
+
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("default: scripted\n"), 0o644))
+	t.Setenv(ScenarioConfigKey, path)
+	gen, err = NewClient(events.NewEventBus())
+	require.NoError(t, err)
+	assert.Equal(t, "mock", gen.GetStatus().Backend)
+	response, err := gen.GenerateContent(context.Background(), ai.Prompt{Name: "chat"}, false, "message", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "scripted", response)
+
+	t.Setenv(ScenarioConfigKey, filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err = NewClient(events.NewEventBus())
+	assert.ErrorContains(t, err, "failed to read mock scenario")
+}
@@ -0,0 +1,129 @@
+package mock
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// demoScenario is served when no scenario file is configured
+//
+//go:embed demo.yaml
+var demoScenario []byte
+
+// Scenario scripts the mock backend's answers.
+//
+//	default: "I have no scripted answer for that."
+//	turns:
+//	  - match: list the files
+//	    tool_calls:
+//	      - name: listFiles
+//	        args: {path: ., _display_message: Listing files}
+//	    response: Here are the files.
+//	  - regex: "(?i)^delete (.+)"
+//	    error: "429 rate limited"
+type Scenario struct {
+	// Default answers messages no turn matches; such messages fail when
+	// it is empty
+	Default string `yaml:"default"`
+	Turns   []Turn `yaml:"turns"`
+}
+
+// Turn is one scripted answer. Turns are tried in order and each is used
+// once unless it repeats; a turn without match or regex answers the next
+// message, whatever it is.
+type Turn struct {
+	// Match is a case-insensitive substring of the user message
+	Match string `yaml:"match"`
+	// Regex is matched against the user message
+	Regex string `yaml:"regex"`
+	// ToolCalls are made, in order, before the answer
+	ToolCalls []ToolCall `yaml:"tool_calls"`
+	// Response is the answer; {{.message}} is the user message
+	Response string `yaml:"response"`
+	// Error fails the turn with this message instead of answering
+	Error string `yaml:"error"`
+	// Delay is waited before answering, as a slow model would
+	Delay  time.Duration `yaml:"delay"`
+	Repeat bool          `yaml:"repeat"`
+
+	regex *regexp.Regexp
+}
+
+// ToolCall is a tool a turn calls, with its arguments.
+type ToolCall struct {
+	Name string         `yaml:"name"`
+	Args map[string]any `yaml:"args"`
+}
+
+// LoadScenario reads and validates a scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock scenario: %w", err)
+	}
+	scenario, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mock scenario %s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+// ParseScenario parses and validates a scenario.
+func ParseScenario(data []byte) (*Scenario, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var scenario Scenario
+	if err := decoder.Decode(&scenario); err != nil {
+		return nil, err
+	}
+
+	var problems []error
+	for i := range scenario.Turns {
+		turn := &scenario.Turns[i]
+		if turn.Response == "" && turn.Error == "" {
+			problems = append(problems, fmt.Errorf("turn %d has no response or error", i+1))
+		}
+		if turn.Match != "" && turn.Regex != "" {
+			problems = append(problems, fmt.Errorf("turn %d sets match and regex; set one", i+1))
+		}
+		if turn.Regex != "" {
+			regex, err := regexp.Compile(turn.Regex)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("turn %d: invalid regex: %w", i+1, err))
+			}
+			turn.regex = regex
+		}
+		for j, call := range turn.ToolCalls {
+			if call.Name == "" {
+				problems = append(problems, fmt.Errorf("turn %d tool call %d has no name", i+1, j+1))
+			}
+		}
+	}
+	if len(scenario.Turns) == 0 && scenario.Default == "" {
+		problems = append(problems, errors.New("has no turns and no default"))
+	}
+	if err := errors.Join(problems...); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// matches reports whether the turn answers message
+func (t *Turn) matches(message string) bool {
+	switch {
+	case t.regex != nil:
+		return t.regex.MatchString(message)
+	case t.Match != "":
+		return strings.Contains(strings.ToLower(message), strings.ToLower(t.Match))
+	default:
+		return true
+	}
+}
@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScenario(t *testing.T) {
+	scenario, err := ParseScenario([]byte(`
+default: no idea
+turns:
+  - match: List The Files
+    tool_calls:
+      - name: listFiles
+        args: {path: ., max_depth: 1}
+    response: done
+  - regex: "^delete (.+)"
+    delay: 250ms
+    error: rate limited
+`))
+	require.NoError(t, err)
+
+	require.Len(t, scenario.Turns, 2)
+	assert.Equal(t, "no idea", scenario.Default)
+	assert.Equal(t, []ToolCall{{Name: "listFiles", Args: map[string]any{"path": ".", "max_depth": 1}}}, scenario.Turns[0].ToolCalls)
+	assert.Equal(t, 250*time.Millisecond, scenario.Turns[1].Delay)
+	assert.True(t, scenario.Turns[0].matches("please list the files"))
+	assert.False(t, scenario.Turns[0].matches("list files"))
+	assert.True(t, scenario.Turns[1].matches("delete build"))
+	assert.False(t, scenario.Turns[1].matches("please delete build"))
+}
+
+func TestParseScenarioRejectsInvalidScenarios(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario string
+		want     string
+	}{
+		{"empty", "{}", "has no turns and no default"},
+		{"unknown field", "turns:\n  - reply: hi\n", "field reply not found"},
+		{"no answer", "turns:\n  - match: hi\n", "turn 1 has no response or error"},
+		{"match and regex", "turns:\n  - {match: hi, regex: hi, response: ok}\n", "turn 1 sets match and regex"},
+		{"invalid regex", "turns:\n  - {regex: \"(\", response: ok}\n", "turn 1: invalid regex"},
+		{"unnamed tool", "turns:\n  - {response: ok, tool_calls: [{args: {a: 1}}]}\n", "turn 1 tool call 1 has no name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScenario([]byte(tt.scenario))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoadScenarioNamesTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+
+	_, err := LoadScenario(path)
+	assert.ErrorContains(t, err, "invalid mock scenario "+path)
+}
+
+func TestDemoScenarioIsValid(t *testing.T) {
+	_, err := ParseScenario(demoScenario)
+	require.NoError(t, err)
+}
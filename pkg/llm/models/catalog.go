@@ -0,0 +1,120 @@
+// Package models lists well-known model names for each LLM provider so hosts
+// can offer completion and infer which provider serves a model. The catalog
+// is a convenience, not an allow-list: any model name the provider accepts
+// can still be used.
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// catalog holds the known models per canonical provider name. Local
+// providers (ollama, lmstudio) serve whatever the user has pulled, so they
+// have no entries.
+var catalog = map[string][]string{
+	"genai": {
+		"gemini-3-pro-preview",
+		"gemini-2.5-pro",
+		"gemini-2.5-flash",
+		"gemini-2.5-flash-lite",
+		"gemini-2.0-flash",
+	},
+	"anthropic": {
+		"claude-opus-4-1",
+		"claude-opus-4-0",
+		"claude-sonnet-4-5",
+		"claude-sonnet-4-0",
+		"claude-haiku-4-5",
+		"claude-3-5-haiku-latest",
+	},
+	"openai": {
+		"gpt-5",
+		"gpt-5-mini",
+		"gpt-5-nano",
+		"gpt-4.1",
+		"gpt-4.1-mini",
+		"gpt-4.1-nano",
+		"gpt-4o",
+		"gpt-4o-mini",
+		"o3",
+		"o4-mini",
+	},
+}
+
+// providerPrefixes maps model name prefixes to the provider serving them,
+// for models missing from the catalog (dated snapshots, previews).
+var providerPrefixes = map[string]string{
+	"gemini-": "genai",
+	"claude-": "anthropic",
+	"gpt-":    "openai",
+	"o1":      "openai",
+	"o3":      "openai",
+	"o4":      "openai",
+}
+
+// Model is a catalog entry.
+type Model struct {
+	Name     string
+	Provider string
+}
+
+// Providers returns the providers that have catalog models, sorted.
+func Providers() []string {
+	providers := make([]string, 0, len(catalog))
+	for provider := range catalog {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// Known returns the catalog models for provider, or nil when the provider
+// has none.
+func Known(provider string) []string {
+	return append([]string(nil), catalog[strings.ToLower(provider)]...)
+}
+
+// All returns every catalog model sorted by name.
+func All() []Model {
+	var all []Model
+	for provider, names := range catalog {
+		for _, name := range names {
+			all = append(all, Model{Name: name, Provider: provider})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// ProviderFor returns the provider serving model: its catalog provider, or
+// the provider owning its name prefix. ok is false for unknown models.
+func ProviderFor(model string) (provider string, ok bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	for provider, names := range catalog {
+		for _, name := range names {
+			if name == model {
+				return provider, true
+			}
+		}
+	}
+	best := ""
+	for prefix, candidate := range providerPrefixes {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, provider = prefix, candidate
+		}
+	}
+	return provider, best != ""
+}
+
+// Parse splits a "provider/model" reference. A bare model name has its
+// provider inferred with ProviderFor and is returned with an empty provider
+// when it is unknown.
+func Parse(ref string) (provider, model string) {
+	ref = strings.TrimSpace(ref)
+	if before, after, found := strings.Cut(ref, "/"); found && before != "" && after != "" {
+		return strings.ToLower(before), after
+	}
+	provider, _ = ProviderFor(ref)
+	return provider, ref
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderFor(t *testing.T) {
+	tests := []struct {
+		model    string
+		provider string
+		ok       bool
+	}{
+		{"gemini-2.5-pro", "genai", true},
+		{"claude-sonnet-4-5", "anthropic", true},
+		{"claude-sonnet-4-5-20250929", "anthropic", true},
+		{"GPT-4o", "openai", true},
+		{"o3-mini", "openai", true},
+		{"llama3.1", "", false},
+	}
+	for _, tt := range tests {
+		provider, ok := ProviderFor(tt.model)
+		assert.Equal(t, tt.provider, provider, tt.model)
+		assert.Equal(t, tt.ok, ok, tt.model)
+	}
+}
+
+func TestParse(t *testing.T) {
+	provider, model := Parse("ollama/qwen2.5-coder:7b")
+	assert.Equal(t, "ollama", provider)
+	assert.Equal(t, "qwen2.5-coder:7b", model)
+
+	provider, model = Parse(" gemini-2.5-flash ")
+	assert.Equal(t, "genai", provider)
+	assert.Equal(t, "gemini-2.5-flash", model)
+
+	provider, model = Parse("my-finetune")
+	assert.Empty(t, provider)
+	assert.Equal(t, "my-finetune", model)
+}
+
+func TestAllIsSortedAndComplete(t *testing.T) {
+	all := All()
+	total := 0
+	for _, provider := range Providers() {
+		total += len(Known(provider))
+	}
+	assert.Len(t, all, total)
+	for i := 1; i < len(all); i++ {
+		assert.Less(t, all[i-1].Name, all[i].Name)
+	}
+	assert.Empty(t, Known("ollama"))
+}
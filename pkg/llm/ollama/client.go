@@ -10,6 +10,7 @@ import (
 	"unicode"
 
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	llmshared "github.com/kcaldas/genie/pkg/llm/shared"
 	"github.com/kcaldas/genie/pkg/llm/shared/toolpayload"
@@ -70,7 +71,7 @@ func NewClient(eventBus events.EventBus, opts ...Option) (ai.Gen, error) {
 	}
 
 	if strings.TrimSpace(client.BaseURL) == "" {
-		client.BaseURL = client.resolveBaseURL()
+		client.BaseURL = BaseURL(client.Config)
 	}
 
 	if strings.TrimSpace(client.BaseURL) == "" {
@@ -205,7 +206,7 @@ func buildOllamaImageMessage(img *toolpayload.Payload) chatMessage {
 func buildOllamaDocumentMessage(doc *toolpayload.Payload) chatMessage {
 	parts := []messagePart{
 		{Type: "text", Text: fmt.Sprintf("Document retrieved from %s (MIME: %s, %d bytes).", toolpayload.SanitizePath(doc.Path), doc.MIMEType, doc.SizeBytes)},
-		{Type: "text", Text: "Inline document attachments are not supported; see tool response."},
+		{Type: "text", Text: "Inline document attachments are not supported; the tool response carries the text extracted from it."},
 	}
 	return chatMessage{
 		Role:    "user",
@@ -309,6 +310,16 @@ func (c *Client) buildOptions(prompt ai.Prompt, mode requestMode) map[string]any
 		opts["top_p"] = topP
 	}
 
+	if stop := llmshared.StopSequences(prompt); len(stop) > 0 {
+		opts["stop"] = stop
+	}
+	if prompt.FrequencyPenalty != 0 {
+		opts["frequency_penalty"] = prompt.FrequencyPenalty
+	}
+	if prompt.PresencePenalty != 0 {
+		opts["presence_penalty"] = prompt.PresencePenalty
+	}
+
 	if mode == countTokensMode {
 		opts["num_predict"] = tokenCountPredict
 	}
@@ -393,11 +404,13 @@ func (c *Client) buildTokenCount(response *chatResponse) *ai.TokenCount {
 	}
 }
 
-func (c *Client) resolveBaseURL() string {
-	if env := strings.TrimSpace(c.Config.GetStringWithDefault("GENIE_OLLAMA_BASE_URL", "")); env != "" {
+// BaseURL returns the configured Ollama server URL: GENIE_OLLAMA_BASE_URL,
+// else OLLAMA_HOST, else the local default.
+func BaseURL(cfg config.Manager) string {
+	if env := strings.TrimSpace(cfg.GetStringWithDefault("GENIE_OLLAMA_BASE_URL", "")); env != "" {
 		return strings.TrimRight(env, "/")
 	}
-	if env := strings.TrimSpace(c.Config.GetStringWithDefault("OLLAMA_HOST", "")); env != "" {
+	if env := strings.TrimSpace(cfg.GetStringWithDefault("OLLAMA_HOST", "")); env != "" {
 		if strings.HasPrefix(env, "http://") || strings.HasPrefix(env, "https://") {
 			return strings.TrimRight(env, "/")
 		}
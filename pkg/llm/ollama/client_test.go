@@ -66,6 +66,44 @@ func TestClient_GenerateContent_SimpleResponse(t *testing.T) {
 	assert.False(t, request.Stream)
 }
 
+func TestClient_GenerateContent_SendsOutputControls(t *testing.T) {
+	t.Parallel()
+
+	mockHTTP := newMockHTTPClient(t, func(call int, req chatRequest) chatResponse {
+		return chatResponse{
+			Model: "llama3",
+			Message: responseMessage{
+				Role:    "assistant",
+				Content: responseContent{parts: []messagePart{{Type: "text", Text: "ok"}}},
+			},
+		}
+	})
+
+	rawClient, err := NewClient(
+		&events.NoOpEventBus{},
+		WithBaseURL("http://test.local"),
+		WithHTTPClient(mockHTTP),
+		WithLogger(logging.NewDisabledLogger()),
+	)
+	require.NoError(t, err)
+
+	prompt := ai.Prompt{
+		Text:             "Say hello.",
+		ModelName:        "llama3",
+		StopSequences:    []string{"END", ""},
+		FrequencyPenalty: 0.5,
+		PresencePenalty:  -0.25,
+	}
+	_, err = rawClient.GenerateContent(context.Background(), prompt, false)
+	require.NoError(t, err)
+
+	require.Len(t, mockHTTP.requests, 1)
+	opts := mockHTTP.requests[0].Options
+	assert.Equal(t, []any{"END"}, opts["stop"])
+	assert.Equal(t, 0.5, opts["frequency_penalty"])
+	assert.Equal(t, -0.25, opts["presence_penalty"])
+}
+
 func TestClient_GenerateContent_WithToolCall(t *testing.T) {
 	t.Parallel()
 
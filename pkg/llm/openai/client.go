@@ -19,6 +19,7 @@ import (
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/fileops"
+	"github.com/kcaldas/genie/pkg/httpclient"
 	llmshared "github.com/kcaldas/genie/pkg/llm/shared"
 	"github.com/kcaldas/genie/pkg/llm/shared/toolpayload"
 	"github.com/kcaldas/genie/pkg/logging"
@@ -248,8 +249,15 @@ func (c *Client) ensureInitialized(ctx context.Context) error {
 		return c.initErr
 	}
 
+	httpClient, err := httpclient.New(c.config)
+	if err != nil {
+		c.initErr = ai.NonRetryable(err)
+		return c.initErr
+	}
+
 	opts := []option.RequestOption{
 		option.WithAPIKey(apiKey),
+		option.WithHTTPClient(httpClient),
 	}
 	if baseURL := strings.TrimSpace(c.config.GetStringWithDefault("OPENAI_BASE_URL", "")); baseURL != "" {
 		opts = append(opts, option.WithBaseURL(baseURL))
@@ -460,6 +468,19 @@ func (c *Client) applyGenerationConfig(params *openai.ChatCompletionNewParams, p
 				c.logger.Debug("top_p not supported for model; using default", "model", targetModel)
 			}
 		}
+		if stop := llmshared.StopSequences(prompt); len(stop) > 0 {
+			if len(stop) > maxStopSequences {
+				c.logger.Debug("too many stop sequences; keeping the first ones", "model", targetModel, "max", maxStopSequences)
+				stop = stop[:maxStopSequences]
+			}
+			params.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: stop}
+		}
+		if prompt.FrequencyPenalty != 0 {
+			params.FrequencyPenalty = openai.Float(float64(prompt.FrequencyPenalty))
+		}
+		if prompt.PresencePenalty != 0 {
+			params.PresencePenalty = openai.Float(float64(prompt.PresencePenalty))
+		}
 	} else {
 		if prompt.Temperature > 0 && prompt.Temperature != 1.0 {
 			c.logger.Debug("temperature not supported for model; using default", "model", targetModel)
@@ -467,6 +488,9 @@ func (c *Client) applyGenerationConfig(params *openai.ChatCompletionNewParams, p
 		if prompt.TopP > 0 && prompt.TopP != 1.0 {
 			c.logger.Debug("top_p not supported for model; using default", "model", targetModel)
 		}
+		if len(prompt.StopSequences) > 0 || prompt.FrequencyPenalty != 0 || prompt.PresencePenalty != 0 {
+			c.logger.Debug("stop sequences and penalties not supported for model; ignoring", "model", targetModel)
+		}
 	}
 
 	if len(prompt.Functions) > 0 {
@@ -476,6 +500,12 @@ func (c *Client) applyGenerationConfig(params *openai.ChatCompletionNewParams, p
 		}
 	}
 
+	if c.promptCacheKeyEnabled() && !prompt.DisableCache {
+		if key := promptCacheKey(targetModel, prompt); key != "" {
+			params.PromptCacheKey = openai.String(key)
+		}
+	}
+
 	if prompt.ResponseSchema != nil {
 		schema := schemaToMap(prompt.ResponseSchema)
 		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
@@ -502,7 +532,7 @@ func buildImageUserMessage(img *toolpayload.Payload) openai.ChatCompletionMessag
 func buildDocumentUserMessage(doc *toolpayload.Payload) openai.ChatCompletionMessageParamUnion {
 	text := toolpayload.SanitizePath(doc.Path)
 	content := fmt.Sprintf("Document retrieved from %s (MIME: %s, %d bytes).", text, doc.MIMEType, doc.SizeBytes)
-	notice := "This provider does not support inline PDFs; refer to the tool response for access."
+	notice := "This provider does not support inline PDFs; the tool response carries the text extracted from it."
 	return openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
 		openai.TextContentPart(content),
 		openai.TextContentPart(notice),
@@ -558,6 +588,7 @@ func (c *Client) publishTokenCount(modelName string, tokenCount *ai.TokenCount)
 		InputTokens:  tokenCount.InputTokens,
 		OutputTokens: tokenCount.OutputTokens,
 		TotalTokens:  tokenCount.TotalTokens,
+		Estimate:     true,
 	}
 	c.eventBus.Publish(event.Topic(), event)
 }
@@ -578,6 +609,9 @@ type tokenMessage struct {
 	Name    string
 }
 
+// maxStopSequences is how many stop sequences the API accepts
+const maxStopSequences = 4
+
 func allowsSamplingParams(model string) bool {
 	model = strings.ToLower(strings.TrimSpace(model))
 	switch {
@@ -3,9 +3,16 @@ package openai
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	openai "github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
@@ -127,6 +134,48 @@ func TestClient_GenerateContent_SimpleResponse(t *testing.T) {
 	assert.Equal(t, "Say hello.", request.Messages[1].OfUser.Content.OfString.Value)
 }
 
+func TestClient_GenerateContent_SendsOutputControls(t *testing.T) {
+	newMockAPI := func() *mockChatCompletions {
+		return &mockChatCompletions{
+			t: t,
+			responses: []*openai.ChatCompletion{
+				newChatCompletion("test", shared.ChatModelGPT4oMini, newChatCompletionMessage("ok", nil), openai.CompletionUsage{}),
+			},
+		}
+	}
+	prompt := ai.Prompt{
+		Text:             "Say hello.",
+		ModelName:        string(shared.ChatModelGPT4oMini),
+		StopSequences:    []string{"1", "2", "3", "4", "5"},
+		FrequencyPenalty: 0.5,
+		PresencePenalty:  0.25,
+	}
+
+	mockAPI := newMockAPI()
+	client, err := NewClient(&events.NoOpEventBus{}, WithChatClient(mockAPI))
+	require.NoError(t, err)
+	_, err = client.GenerateContent(context.Background(), prompt, false)
+	require.NoError(t, err)
+
+	request := mockAPI.requests[0]
+	assert.Equal(t, []string{"1", "2", "3", "4"}, request.Stop.OfStringArray, "the API takes at most four")
+	assert.Equal(t, 0.5, request.FrequencyPenalty.Value)
+	assert.Equal(t, 0.25, request.PresencePenalty.Value)
+
+	// Reasoning models reject them
+	mockAPI = newMockAPI()
+	client, err = NewClient(&events.NoOpEventBus{}, WithChatClient(mockAPI))
+	require.NoError(t, err)
+	prompt.ModelName = "o3-mini"
+	_, err = client.GenerateContent(context.Background(), prompt, false)
+	require.NoError(t, err)
+
+	request = mockAPI.requests[0]
+	assert.Empty(t, request.Stop.OfStringArray)
+	assert.False(t, request.FrequencyPenalty.Valid())
+	assert.False(t, request.PresencePenalty.Valid())
+}
+
 func TestClient_GenerateContent_WithImages(t *testing.T) {
 	mockAPI := &mockChatCompletions{
 		t: t,
@@ -390,3 +439,72 @@ func TestClient_GenerateContent_MissingAPIKey(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "OPENAI_API_KEY")
 }
+
+func TestClient_GenerateContent_TrustsCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"OK"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("OPENAI_BASE_URL", server.URL)
+	t.Setenv("GENIE_CA_CERT", caPath)
+	t.Setenv("GENIE_RETRY_LLM_ENABLED", "false")
+	client, err := NewClient(&events.NoOpEventBus{})
+	require.NoError(t, err)
+
+	answer, err := client.GenerateContent(context.Background(), ai.Prompt{Name: "ca", Text: "Hello?", ModelName: string(shared.ChatModelGPT4oMini)}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "OK", answer)
+}
+
+func TestClient_GenerateContent_PublishesReasoningContent(t *testing.T) {
+	// OpenAI-compatible servers return reasoning outside the standard
+	// message fields; only decoding the raw JSON captures it
+	var completion openai.ChatCompletion
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "test",
+		"object": "chat.completion",
+		"model": "deepseek-reasoner",
+		"choices": [{
+			"index": 0,
+			"finish_reason": "stop",
+			"message": {"role": "assistant", "content": "42", "reasoning_content": "Six sevens are forty-two."}
+		}]
+	}`), &completion))
+
+	send := func(showThoughts bool) func() []string {
+		mockAPI := &mockChatCompletions{t: t, responses: []*openai.ChatCompletion{&completion}}
+		bus := events.NewEventBus()
+		var mu sync.Mutex
+		var thoughts []string
+		bus.Subscribe("chat.notification", func(e interface{}) {
+			if n, ok := e.(events.NotificationEvent); ok && n.ContentType == "thought" {
+				mu.Lock()
+				thoughts = append(thoughts, n.Message)
+				mu.Unlock()
+			}
+		})
+		rawClient, err := NewClient(bus, WithChatClient(mockAPI))
+		require.NoError(t, err)
+		resp, err := rawClient.GenerateContent(context.Background(), ai.Prompt{Text: "What is 6x7?", ModelName: "deepseek-reasoner", ShowThoughts: showThoughts}, false)
+		require.NoError(t, err)
+		assert.Equal(t, "42", resp)
+		return func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]string(nil), thoughts...)
+		}
+	}
+
+	shown := send(true)
+	assert.Eventually(t, func() bool { return len(shown()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"Six sevens are forty-two."}, shown())
+
+	hidden := send(false)
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, hidden())
+}
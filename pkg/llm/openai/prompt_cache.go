@@ -0,0 +1,53 @@
+package openai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+const (
+	// promptCacheConfigKey sends a prompt_cache_key with each request so
+	// OpenAI routes turns sharing a system prefix to the same cache. The
+	// caching itself is automatic; the key only raises the hit rate.
+	// Defaults on for api.openai.com and off for custom OPENAI_BASE_URL
+	// endpoints, which may reject the field.
+	promptCacheConfigKey = "OPENAI_PROMPT_CACHE"
+
+	// promptCacheKeyPrefix names the keys Genie sends, so they are easy to
+	// spot in usage dashboards.
+	promptCacheKeyPrefix = "genie-"
+)
+
+func (c *Client) promptCacheKeyEnabled() bool {
+	customEndpoint := strings.TrimSpace(c.config.GetStringWithDefault("OPENAI_BASE_URL", "")) != ""
+	return c.config.GetBoolWithDefault(promptCacheConfigKey, !customEndpoint)
+}
+
+// promptCacheKey identifies the stable prefix of a request: the model, the
+// persona instruction, the system prompt files and the tool declarations.
+// Per-user context and the conversation are left out, as they come after
+// the prefix and change every turn. It returns "" when there is no prefix
+// worth caching.
+func promptCacheKey(model string, p ai.Prompt) string {
+	instruction := strings.TrimSpace(p.Instruction)
+	if instruction == "" {
+		return ""
+	}
+	hash := sha256.New()
+	hash.Write([]byte(model + "\x00"))
+	hash.Write([]byte(instruction + "\x00"))
+	hash.Write([]byte(strings.TrimSpace(p.SystemPromptFiles) + "\x00"))
+	for _, fn := range p.Functions {
+		if fn == nil {
+			continue
+		}
+		if data, err := json.Marshal(fn); err == nil {
+			hash.Write(data)
+		}
+	}
+	return promptCacheKeyPrefix + hex.EncodeToString(hash.Sum(nil))[:32]
+}
@@ -0,0 +1,59 @@
+package openai
+
+import (
+	"context"
+	"testing"
+
+	openai "github.com/openai/openai-go"
+	"github.com/openai/openai-go/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+)
+
+func TestClient_PromptCacheKey(t *testing.T) {
+	t.Setenv("OPENAI_BASE_URL", "")
+	t.Setenv("OPENAI_PROMPT_CACHE", "")
+
+	send := func(prompt ai.Prompt) openai.ChatCompletionNewParams {
+		mockAPI := &mockChatCompletions{
+			t: t,
+			responses: []*openai.ChatCompletion{
+				newChatCompletion("test", shared.ChatModelGPT4oMini, newChatCompletionMessage("ok", nil), openai.CompletionUsage{}),
+			},
+		}
+		rawClient, err := NewClient(&events.NoOpEventBus{}, WithChatClient(mockAPI))
+		require.NoError(t, err)
+		_, err = rawClient.GenerateContent(context.Background(), prompt, false)
+		require.NoError(t, err)
+		require.Len(t, mockAPI.requests, 1)
+		return mockAPI.requests[0]
+	}
+
+	prompt := ai.Prompt{
+		Instruction: "You are a helpful assistant.",
+		Text:        "Say hello.",
+		ModelName:   string(shared.ChatModelGPT4oMini),
+	}
+	first := send(prompt)
+	require.True(t, first.PromptCacheKey.Valid())
+	assert.Contains(t, first.PromptCacheKey.Value, promptCacheKeyPrefix)
+
+	// The user's message is not part of the prefix; the instruction is
+	prompt.Text = "Say goodbye."
+	assert.Equal(t, first.PromptCacheKey.Value, send(prompt).PromptCacheKey.Value)
+	prompt.Instruction = "You are a terse assistant."
+	assert.NotEqual(t, first.PromptCacheKey.Value, send(prompt).PromptCacheKey.Value)
+
+	prompt.DisableCache = true
+	assert.False(t, send(prompt).PromptCacheKey.Valid())
+
+	// Custom endpoints may not accept the field, so it is opt-in there
+	prompt.DisableCache = false
+	t.Setenv("OPENAI_BASE_URL", "http://localhost:1234/v1")
+	assert.False(t, send(prompt).PromptCacheKey.Valid())
+	t.Setenv("OPENAI_PROMPT_CACHE", "true")
+	assert.True(t, send(prompt).PromptCacheKey.Valid())
+}
@@ -26,6 +26,9 @@ type turnState struct {
 	params   openai.ChatCompletionNewParams
 	messages []openai.ChatCompletionMessageParamUnion
 	toolUsed bool
+	// showThoughts publishes the reasoning OpenAI-compatible servers
+	// return alongside the answer
+	showThoughts bool
 }
 
 func (c *Client) newTurn(prompt ai.Prompt) (*turnState, error) {
@@ -40,7 +43,12 @@ func (c *Client) newTurn(prompt ai.Prompt) (*turnState, error) {
 	}
 	c.applyGenerationConfig(&params, prompt)
 
-	return &turnState{client: c, params: params, messages: messages}, nil
+	return &turnState{
+		client:       c,
+		params:       params,
+		messages:     messages,
+		showThoughts: llmshared.ShowThoughts(c.config, prompt, ""),
+	}, nil
 }
 
 // Step runs one model request. With emit set it streams; otherwise it
@@ -73,6 +81,9 @@ func (t *turnState) stepBlocking(ctx context.Context, params openai.ChatCompleti
 	}
 
 	assistantMessage := resp.Choices[0].Message
+	if t.showThoughts {
+		llmshared.PublishThought(c.eventBus, reasoningText(assistantMessage.JSON.ExtraFields))
+	}
 	content := strings.TrimSpace(assistantMessage.Content)
 	hasToolCalls := len(assistantMessage.ToolCalls) > 0
 
@@ -133,6 +144,9 @@ func (t *turnState) stepStreaming(ctx context.Context, params openai.ChatComplet
 			continue
 		}
 		choice := chunk.Choices[0]
+		if t.showThoughts {
+			llmshared.PublishThought(c.eventBus, reasoningText(choice.Delta.JSON.ExtraFields))
+		}
 		if choice.Delta.Content != "" {
 			emit(&ai.StreamChunk{Text: choice.Delta.Content})
 			assistantBuilder.WriteString(choice.Delta.Content)
@@ -367,3 +381,22 @@ func toolCallChunk(calls []openai.ChatCompletionMessageToolCall) *ai.StreamChunk
 	}
 	return &ai.StreamChunk{ToolCalls: toolChunks}
 }
+
+// reasoningText returns the reasoning an OpenAI-compatible server sent
+// with a message or delta. OpenAI's own models keep theirs hidden in Chat
+// Completions, but servers such as DeepSeek, vLLM, LM Studio and
+// OpenRouter return it as reasoning_content or reasoning, which the SDK
+// only exposes as extra fields.
+func reasoningText[F interface{ Raw() string }](extra map[string]F) string {
+	for _, key := range []string{"reasoning_content", "reasoning"} {
+		field, ok := extra[key]
+		if !ok {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal([]byte(field.Raw()), &text); err == nil && text != "" {
+			return text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+// Package pricing estimates the dollar cost of model usage.
+//
+// Rates are expressed in USD per million tokens and matched against model
+// names by longest prefix, so "claude-sonnet-4-5-20250929" picks up the
+// "claude-sonnet-4" rate. The built-in table can be overridden or extended
+// with a JSON file (see LoadTable).
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+)
+
+// Rate is the price of one million tokens, in USD.
+type Rate struct {
+	Input      float64 `json:"input"`       // uncached input tokens
+	Output     float64 `json:"output"`      // output tokens
+	CacheRead  float64 `json:"cache_read"`  // input tokens served from a cache
+	CacheWrite float64 `json:"cache_write"` // input tokens written to a cache
+}
+
+// freeProviders run models locally and never incur a per-token cost.
+var freeProviders = map[string]bool{
+	"ollama":   true,
+	"lmstudio": true,
+}
+
+// defaultRates holds list prices for commonly used models. Keys are model
+// name prefixes.
+var defaultRates = map[string]Rate{
+	// Anthropic
+	"claude-opus-4":     {Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75},
+	"claude-sonnet-4":   {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-3-5-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-haiku-4":    {Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4, CacheRead: 0.08, CacheWrite: 1},
+
+	// Google
+	"gemini-2.5-pro":        {Input: 1.25, Output: 10, CacheRead: 0.31},
+	"gemini-2.5-flash":      {Input: 0.3, Output: 2.5, CacheRead: 0.075},
+	"gemini-2.5-flash-lite": {Input: 0.1, Output: 0.4, CacheRead: 0.025},
+	"gemini-2.0-flash":      {Input: 0.1, Output: 0.4, CacheRead: 0.025},
+	"gemini-1.5-pro":        {Input: 1.25, Output: 5, CacheRead: 0.3125},
+	"gemini-1.5-flash":      {Input: 0.075, Output: 0.3, CacheRead: 0.01875},
+
+	// OpenAI
+	"gpt-4o":       {Input: 2.5, Output: 10, CacheRead: 1.25},
+	"gpt-4o-mini":  {Input: 0.15, Output: 0.6, CacheRead: 0.075},
+	"gpt-4.1":      {Input: 2, Output: 8, CacheRead: 0.5},
+	"gpt-4.1-mini": {Input: 0.4, Output: 1.6, CacheRead: 0.1},
+	"gpt-4.1-nano": {Input: 0.1, Output: 0.4, CacheRead: 0.025},
+	"gpt-5":        {Input: 1.25, Output: 10, CacheRead: 0.125},
+	"gpt-5-mini":   {Input: 0.25, Output: 2, CacheRead: 0.025},
+	"gpt-5-nano":   {Input: 0.05, Output: 0.4, CacheRead: 0.005},
+	"o3":           {Input: 2, Output: 8, CacheRead: 0.5},
+	"o4-mini":      {Input: 1.1, Output: 4.4, CacheRead: 0.275},
+}
+
+// Table maps model name prefixes to rates.
+type Table struct {
+	rates    map[string]Rate
+	prefixes []string // sorted longest first
+}
+
+// NewTable creates a table from the built-in rates with overrides applied on
+// top. Override keys replace built-in entries with the same prefix.
+func NewTable(overrides map[string]Rate) *Table {
+	rates := make(map[string]Rate, len(defaultRates)+len(overrides))
+	for model, rate := range defaultRates {
+		rates[model] = rate
+	}
+	for model, rate := range overrides {
+		rates[strings.ToLower(model)] = rate
+	}
+
+	prefixes := make([]string, 0, len(rates))
+	for model := range rates {
+		prefixes = append(prefixes, model)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if len(prefixes[i]) != len(prefixes[j]) {
+			return len(prefixes[i]) > len(prefixes[j])
+		}
+		return prefixes[i] < prefixes[j]
+	})
+
+	return &Table{rates: rates, prefixes: prefixes}
+}
+
+// LoadTable builds a table from the built-in rates plus overrides read from
+// the JSON file named by GENIE_PRICING_FILE, falling back to
+// ~/.genie/pricing.json when it exists. The file maps model prefixes to
+// rates, e.g. {"my-model": {"input": 1, "output": 2}}.
+func LoadTable(configManager config.Manager) (*Table, error) {
+	path := configManager.GetStringWithDefault("GENIE_PRICING_FILE", "")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return NewTable(nil), nil
+		}
+		path = filepath.Join(home, ".genie", "pricing.json")
+		if _, err := os.Stat(path); err != nil {
+			return NewTable(nil), nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return NewTable(nil), fmt.Errorf("failed to read pricing file %s: %w", path, err)
+	}
+
+	var overrides map[string]Rate
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return NewTable(nil), fmt.Errorf("failed to parse pricing file %s: %w", path, err)
+	}
+	return NewTable(overrides), nil
+}
+
+// Lookup returns the rate for a model, matching by longest prefix.
+func (t *Table) Lookup(model string) (Rate, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if model == "" {
+		return Rate{}, false
+	}
+	// Strip provider-style path prefixes such as "models/gemini-2.5-pro".
+	if idx := strings.LastIndex(model, "/"); idx >= 0 {
+		model = model[idx+1:]
+	}
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(model, prefix) {
+			return t.rates[prefix], true
+		}
+	}
+	return Rate{}, false
+}
+
+// Cost returns the USD cost of the usage reported by a token count event.
+// The second return value is false when the model has no known rate, in
+// which case the cost is reported as zero.
+func (t *Table) Cost(e events.TokenCountEvent) (float64, bool) {
+	if freeProviders[strings.ToLower(e.Provider)] {
+		return 0, true
+	}
+	rate, ok := t.Lookup(e.Model)
+	if !ok {
+		return 0, false
+	}
+
+	cacheRead := e.CacheReadInputTokens
+	if cacheRead == 0 {
+		cacheRead = e.CachedTokens
+	}
+	cacheReadRate := rate.CacheRead
+	if cacheReadRate == 0 {
+		cacheReadRate = rate.Input
+	}
+	cacheWriteRate := rate.CacheWrite
+	if cacheWriteRate == 0 {
+		cacheWriteRate = rate.Input
+	}
+
+	cost := float64(e.InputTokens)*rate.Input +
+		float64(e.OutputTokens)*rate.Output +
+		float64(cacheRead)*cacheReadRate +
+		float64(e.CacheCreationInputTokens)*cacheWriteRate
+	return cost / 1_000_000, true
+}
+
+// FormatCost renders a USD amount with precision suited to small values.
+func FormatCost(cost float64) string {
+	switch {
+	case cost == 0:
+		return "$0.00"
+	case cost < 0.01:
+		return fmt.Sprintf("$%.4f", cost)
+	case cost < 1:
+		return fmt.Sprintf("$%.3f", cost)
+	default:
+		return fmt.Sprintf("$%.2f", cost)
+	}
+}
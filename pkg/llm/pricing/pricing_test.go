@@ -0,0 +1,140 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_LookupLongestPrefix(t *testing.T) {
+	table := NewTable(nil)
+
+	rate, ok := table.Lookup("gemini-2.5-flash-lite-preview")
+	require.True(t, ok)
+	assert.Equal(t, defaultRates["gemini-2.5-flash-lite"], rate)
+
+	rate, ok = table.Lookup("models/gemini-2.5-flash")
+	require.True(t, ok)
+	assert.Equal(t, defaultRates["gemini-2.5-flash"], rate)
+
+	rate, ok = table.Lookup("claude-sonnet-4-5-20250929")
+	require.True(t, ok)
+	assert.Equal(t, defaultRates["claude-sonnet-4"], rate)
+
+	_, ok = table.Lookup("unknown-model")
+	assert.False(t, ok)
+}
+
+func TestTable_Overrides(t *testing.T) {
+	table := NewTable(map[string]Rate{
+		"gpt-4o":   {Input: 1, Output: 1},
+		"My-Model": {Input: 5, Output: 6},
+	})
+
+	rate, ok := table.Lookup("gpt-4o-2024-08-06")
+	require.True(t, ok)
+	assert.Equal(t, Rate{Input: 1, Output: 1}, rate)
+
+	rate, ok = table.Lookup("my-model-v2")
+	require.True(t, ok)
+	assert.Equal(t, 5.0, rate.Input)
+}
+
+func TestTable_Cost(t *testing.T) {
+	table := NewTable(map[string]Rate{
+		"test-model": {Input: 2, Output: 10, CacheRead: 0.5, CacheWrite: 4},
+	})
+
+	cost, ok := table.Cost(events.TokenCountEvent{
+		Model:                    "test-model",
+		InputTokens:              1_000_000,
+		OutputTokens:             100_000,
+		CacheReadInputTokens:     200_000,
+		CacheCreationInputTokens: 50_000,
+	})
+
+	require.True(t, ok)
+	assert.InDelta(t, 2+1+0.1+0.2, cost, 1e-9)
+}
+
+func TestTable_CostLocalProvidersAreFree(t *testing.T) {
+	cost, ok := NewTable(nil).Cost(events.TokenCountEvent{
+		Provider:    "ollama",
+		Model:       "llama3",
+		InputTokens: 1000,
+	})
+	assert.True(t, ok)
+	assert.Zero(t, cost)
+}
+
+func TestLoadTable_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"custom-model": {"input": 1.5, "output": 3}}`), 0o600))
+	t.Setenv("GENIE_PRICING_FILE", path)
+
+	table, err := LoadTable(config.NewConfigManager())
+	require.NoError(t, err)
+
+	rate, ok := table.Lookup("custom-model")
+	require.True(t, ok)
+	assert.Equal(t, Rate{Input: 1.5, Output: 3}, rate)
+}
+
+func TestLoadTable_InvalidFileFallsBackToDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+	t.Setenv("GENIE_PRICING_FILE", path)
+
+	table, err := LoadTable(config.NewConfigManager())
+	assert.Error(t, err)
+	_, ok := table.Lookup("gpt-4o")
+	assert.True(t, ok)
+}
+
+func TestTracker_TurnAndSession(t *testing.T) {
+	tracker := NewTracker(NewTable(map[string]Rate{"priced": {Input: 1, Output: 2}}))
+
+	tracker.StartTurn()
+	tracker.Record(events.TokenCountEvent{Model: "priced", InputTokens: 1_000_000})
+	tracker.Record(events.TokenCountEvent{Model: "priced", InputTokens: 1_000_000, Estimate: true})
+	assert.InDelta(t, 1.0, tracker.Turn().Cost, 1e-9)
+
+	tracker.StartTurn()
+	tracker.Record(events.TokenCountEvent{Model: "priced", OutputTokens: 500_000})
+	tracker.Record(events.TokenCountEvent{Model: "mystery", InputTokens: 10})
+
+	turn := tracker.Turn()
+	assert.InDelta(t, 1.0, turn.Cost, 1e-9)
+	assert.Equal(t, []string{"mystery"}, turn.UnpricedModels)
+
+	session := tracker.Session()
+	assert.InDelta(t, 2.0, session.Cost, 1e-9)
+	assert.Equal(t, int64(1_000_010), session.InputTokens)
+}
+
+func TestTracker_CacheHitRatio(t *testing.T) {
+	tracker := NewTracker(NewTable(nil))
+	assert.Zero(t, tracker.Session().CacheHitRatio())
+
+	tracker.Record(events.TokenCountEvent{Model: "claude-sonnet-4", InputTokens: 100, CacheCreationInputTokens: 900})
+	tracker.StartTurn()
+	tracker.Record(events.TokenCountEvent{Model: "claude-sonnet-4", InputTokens: 100, CachedTokens: 900, CacheReadInputTokens: 900})
+
+	session := tracker.Session()
+	assert.Equal(t, int64(900), session.CachedTokens)
+	assert.Equal(t, int64(900), session.CacheWriteTokens)
+	assert.InDelta(t, 0.45, session.CacheHitRatio(), 1e-9)
+	assert.InDelta(t, 0.9, tracker.Turn().CacheHitRatio(), 1e-9)
+}
+
+func TestFormatCost(t *testing.T) {
+	assert.Equal(t, "$0.00", FormatCost(0))
+	assert.Equal(t, "$0.0012", FormatCost(0.00123))
+	assert.Equal(t, "$0.123", FormatCost(0.1234))
+	assert.Equal(t, "$12.35", FormatCost(12.345))
+}
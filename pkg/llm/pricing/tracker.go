@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/events"
+)
+
+// Usage aggregates token counts and cost over a span of model calls.
+type Usage struct {
+	InputTokens  int64 // uncached input
+	OutputTokens int64
+	CachedTokens int64 // input served from a provider cache
+	// CacheWriteTokens is input written to a provider cache, billed above
+	// the input rate by Anthropic and as cache storage by Gemini.
+	CacheWriteTokens int64
+	Cost             float64
+
+	// UnpricedModels lists models seen without a known rate; their tokens
+	// are counted but contribute nothing to Cost.
+	UnpricedModels []string
+}
+
+// Tracker accumulates cost per turn and per session from TokenCountEvents.
+// It is safe for concurrent use.
+type Tracker struct {
+	table   *Table
+	mu      sync.Mutex
+	turn    Usage
+	session Usage
+}
+
+// NewTracker creates a tracker that prices usage with table.
+func NewTracker(table *Table) *Tracker {
+	if table == nil {
+		table = NewTable(nil)
+	}
+	return &Tracker{table: table}
+}
+
+// StartTurn resets the per-turn totals. Session totals are kept.
+func (t *Tracker) StartTurn() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.turn = Usage{}
+}
+
+// Record adds the usage reported by e to the turn and session totals and
+// returns the cost of e. Estimated counts are ignored.
+func (t *Tracker) Record(e events.TokenCountEvent) float64 {
+	if e.Estimate {
+		return 0
+	}
+	cost, priced := t.table.Cost(e)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, usage := range []*Usage{&t.turn, &t.session} {
+		usage.InputTokens += int64(e.InputTokens)
+		usage.OutputTokens += int64(e.OutputTokens)
+		usage.CachedTokens += int64(e.CachedTokens)
+		usage.CacheWriteTokens += int64(e.CacheCreationInputTokens)
+		usage.Cost += cost
+		if !priced {
+			usage.addUnpriced(e.Model)
+		}
+	}
+	return cost
+}
+
+// Turn returns the totals since the last StartTurn.
+func (t *Tracker) Turn() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.turn.clone()
+}
+
+// Session returns the totals since the tracker was created.
+func (t *Tracker) Session() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.session.clone()
+}
+
+// Tokens is every billed token: uncached and cached input, cache writes
+// and output.
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CachedTokens + u.CacheWriteTokens
+}
+
+// CacheHitRatio is the share of input tokens served from a cache, between
+// 0 and 1. It is 0 when no input was sent.
+func (u Usage) CacheHitRatio() float64 {
+	total := u.InputTokens + u.CachedTokens + u.CacheWriteTokens
+	if total == 0 {
+		return 0
+	}
+	return float64(u.CachedTokens) / float64(total)
+}
+
+func (u *Usage) addUnpriced(model string) {
+	for _, existing := range u.UnpricedModels {
+		if existing == model {
+			return
+		}
+	}
+	u.UnpricedModels = append(u.UnpricedModels, model)
+}
+
+func (u Usage) clone() Usage {
+	u.UnpricedModels = append([]string(nil), u.UnpricedModels...)
+	return u
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ToolCall is a provider-neutral tool invocation requested by the model.
@@ -157,7 +159,7 @@ func stepWithRetry(ctx context.Context, turn TurnState, cfg LoopConfig, emit fun
 
 	backoff := cfg.StepBackoff
 	for attempt := 0; ; attempt++ {
-		outcome, err = turn.Step(ctx, emit)
+		outcome, err = tracedStep(ctx, turn, attempt, emit)
 		if err == nil {
 			return outcome, nil
 		}
@@ -166,6 +168,7 @@ func stepWithRetry(ctx context.Context, turn TurnState, cfg LoopConfig, emit fun
 		}
 
 		log.Printf("Model step failed (attempt %d): %v. Retrying in %v...", attempt+1, err, backoff)
+		ai.NotifyRetry(ctx, attempt+1, err)
 		select {
 		case <-time.After(backoff):
 		case <-ctx.Done():
@@ -175,6 +178,21 @@ func stepWithRetry(ctx context.Context, turn TurnState, cfg LoopConfig, emit fun
 	}
 }
 
+// tracedStep runs one model request inside a "chat" span, a child of the
+// turn's span.
+func tracedStep(ctx context.Context, turn TurnState, attempt int, emit func(*ai.StreamChunk)) (StepOutcome, error) {
+	ctx, span := telemetry.StartSpan(ctx, "chat",
+		attribute.String("gen_ai.operation.name", "chat"),
+		attribute.Int("genie.llm.attempt", attempt+1),
+	)
+	outcome, err := turn.Step(ctx, emit)
+	if err == nil {
+		span.SetAttributes(attribute.Int("genie.llm.tool_calls", len(outcome.ToolCalls)))
+	}
+	telemetry.End(span, err)
+	return outcome, err
+}
+
 // executeToolCalls runs the requested tools sequentially. Handler
 // errors and unknown tools become ToolResult.Err so the model can see
 // and correct them; a context cancellation stops execution.
@@ -10,6 +10,11 @@ import (
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // scriptedTurn replays a fixed sequence of step outcomes and records
@@ -214,6 +219,35 @@ func TestRunToolLoopRetriesFailedStepWithoutReplayingTools(t *testing.T) {
 	assert.Len(t, *invoked, 1, "step retry must not re-execute tools")
 }
 
+func TestRunToolLoopTracesEachModelRequest(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	turn := &scriptedTurn{steps: []func() (StepOutcome, error){
+		outcome(StepOutcome{ToolCalls: []ToolCall{{Name: "lookup", Args: map[string]any{"q": "x"}}}}),
+		stepErr(errors.New("http 503: overloaded")),
+		outcome(StepOutcome{Text: "recovered"}),
+	}}
+	handlers, _ := echoHandlers(t)
+
+	_, err := RunToolLoop(context.Background(), turn, handlers, LoopConfig{
+		StepRetries: 2,
+		StepBackoff: time.Millisecond,
+	}, nil)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	for _, span := range spans {
+		assert.Equal(t, "chat", span.Name())
+	}
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("genie.llm.tool_calls", 1))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Contains(t, spans[2].Attributes(), attribute.Int("genie.llm.attempt", 2))
+}
+
 func TestRunToolLoopDoesNotRetryCancelledStep(t *testing.T) {
 	turn := &scriptedTurn{steps: []func() (StepOutcome, error){
 		stepErr(context.Canceled),
@@ -15,6 +15,7 @@ import (
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/fileops"
+	"github.com/kcaldas/genie/pkg/httpclient"
 	"github.com/kcaldas/genie/pkg/logging"
 	"github.com/kcaldas/genie/pkg/template"
 )
@@ -45,17 +46,34 @@ func NewLocalClientCore(provider string, eventBus events.EventBus) LocalClientCo
 	if eventBus == nil {
 		eventBus = &events.NoOpEventBus{}
 	}
+	configManager := config.NewConfigManager()
+	var httpClient HTTPDoer
+	if client, err := httpclient.New(configManager); err != nil {
+		httpClient = failingDoer{err: err}
+	} else {
+		httpClient = client
+	}
 	return LocalClientCore{
 		Provider:    provider,
-		Config:      config.NewConfigManager(),
+		Config:      configManager,
 		FileManager: fileops.NewFileOpsManager(),
 		Template:    template.NewEngine(),
 		EventBus:    eventBus,
 		Logger:      logging.NewAPILogger(provider),
-		HTTPClient:  &http.Client{},
+		HTTPClient:  httpClient,
 	}
 }
 
+// failingDoer fails every request with the error that kept the HTTP
+// client from being built, such as an unreadable GENIE_CA_CERT
+type failingDoer struct {
+	err error
+}
+
+func (d failingDoer) Do(*http.Request) (*http.Response, error) {
+	return nil, d.err
+}
+
 // LocalOption configures the shared core of a local provider client.
 // Providers alias this as their exported Option type.
 type LocalOption func(*LocalClientCore)
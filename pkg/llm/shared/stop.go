@@ -0,0 +1,16 @@
+package shared
+
+import "github.com/kcaldas/genie/pkg/ai"
+
+// StopSequences returns the prompt's stop sequences without empty ones,
+// which providers reject or treat as stopping at once. It returns nil
+// when none are set.
+func StopSequences(prompt ai.Prompt) []string {
+	var stop []string
+	for _, seq := range prompt.StopSequences {
+		if seq != "" {
+			stop = append(stop, seq)
+		}
+	}
+	return stop
+}
@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+)
+
+// ShowThoughtsConfigKey shows the model's reasoning for every provider.
+// Provider-specific keys (GEMINI_SHOW_THOUGHTS, ANTHROPIC_SHOW_THINKING)
+// still work on their own.
+const ShowThoughtsConfigKey = "GENIE_SHOW_THOUGHTS"
+
+// ShowThoughts reports whether a call should request and publish the
+// model's reasoning: the prompt asks for it, or GENIE_SHOW_THOUGHTS or the
+// provider's own key is set.
+func ShowThoughts(cfg config.Manager, prompt ai.Prompt, providerKey string) bool {
+	if prompt.ShowThoughts {
+		return true
+	}
+	if cfg == nil {
+		return false
+	}
+	if cfg.GetBoolWithDefault(ShowThoughtsConfigKey, false) {
+		return true
+	}
+	return providerKey != "" && cfg.GetBoolWithDefault(providerKey, false)
+}
+
+// PublishThought publishes a piece of the model's reasoning as a "thought"
+// notification. Streamed pieces are published as they arrive, untrimmed,
+// so consumers can join them back together. Blank text is dropped.
+func PublishThought(publisher events.Publisher, text string) {
+	if publisher == nil || strings.TrimSpace(text) == "" {
+		return
+	}
+	notification := events.NotificationEvent{
+		Message:     text,
+		ContentType: "thought",
+	}
+	publisher.Publish(notification.Topic(), notification)
+}
@@ -47,6 +47,10 @@ func Extract(input map[string]any) (*Payload, map[string]any, error) {
 	}
 
 	base64Str, ok := input["data_base64"].(string)
+	if _, hasText := input["text"]; !ok && hasText {
+		// Documents read as extracted text carry no attachment
+		return nil, sanitized, nil
+	}
 	if !ok || base64Str == "" {
 		delete(sanitized, "data_base64")
 		delete(sanitized, "data_url")
@@ -0,0 +1,458 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// initializeTimeout bounds the handshake; servers index the workspace
+	// before answering, which takes a while on large ones
+	initializeTimeout = 60 * time.Second
+
+	// shutdownTimeout bounds the polite shutdown before the process is killed
+	shutdownTimeout = 3 * time.Second
+
+	// diagnosticsSettle is how long diagnostics must stay unchanged before
+	// they count as complete: servers often publish syntax errors first
+	// and type errors a moment later
+	diagnosticsSettle = 500 * time.Millisecond
+)
+
+// Client talks JSON-RPC to one language server process over stdio.
+type Client struct {
+	name       string
+	languageID string
+	cmd        *exec.Cmd
+	stdin      io.WriteCloser
+
+	writeMu sync.Mutex
+
+	mu          sync.Mutex
+	nextID      int64
+	pending     map[int64]chan rpcResponse
+	versions    map[string]int // open documents and their versions
+	diagnostics map[string][]Diagnostic
+	published   map[string]int // diagnostics notifications per URI
+	changed     chan struct{}  // closed and replaced on every notification
+	done        chan struct{}  // closed when the server exits
+	exitErr     error
+}
+
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcResponse struct {
+	result json.RawMessage
+	err    error
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Start launches the server for the workspace at root and completes the
+// initialize handshake.
+func Start(ctx context.Context, name string, config ServerConfig, root string) (*Client, error) {
+	cmd := exec.Command(config.Command, config.Args...)
+	cmd.Dir = root
+	cmd.Env = os.Environ()
+	for key, value := range config.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	// Servers log to stderr; nobody reads it and a full pipe would block them
+	cmd.Stderr = io.Discard
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start language server %s (%s): %w", name, config.Command, err)
+	}
+
+	languageID := config.LanguageID
+	if languageID == "" {
+		languageID = name
+	}
+	c := &Client{
+		name:        name,
+		languageID:  languageID,
+		cmd:         cmd,
+		stdin:       stdin,
+		pending:     make(map[int64]chan rpcResponse),
+		versions:    make(map[string]int),
+		diagnostics: make(map[string][]Diagnostic),
+		published:   make(map[string]int),
+		changed:     make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go c.readLoop(bufio.NewReader(stdout))
+
+	initCtx, cancel := context.WithTimeout(ctx, initializeTimeout)
+	defer cancel()
+	params := map[string]any{
+		"processId": os.Getpid(),
+		"rootUri":   FileURI(root),
+		"workspaceFolders": []map[string]string{
+			{"uri": FileURI(root), "name": root},
+		},
+		"capabilities": map[string]any{
+			"textDocument": map[string]any{
+				"publishDiagnostics": map[string]any{},
+				"definition":         map[string]any{"linkSupport": true},
+				"references":         map[string]any{},
+				"synchronization":    map[string]any{"didSave": false},
+			},
+			"workspace": map[string]any{
+				"configuration":    true,
+				"workspaceFolders": true,
+			},
+		},
+		"clientInfo": map[string]string{"name": "genie"},
+	}
+	if len(config.InitializationOptions) > 0 {
+		params["initializationOptions"] = config.InitializationOptions
+	}
+	if err := c.Call(initCtx, "initialize", params, nil); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("language server %s did not initialize: %w", name, err)
+	}
+	if err := c.Notify("initialized", map[string]any{}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Name returns the server's name in the config.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Alive reports whether the server process is still running.
+func (c *Client) Alive() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Call sends a request and decodes its result into result, when non-nil.
+func (c *Client) Call(ctx context.Context, method string, params any, result any) error {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	reply := make(chan rpcResponse, 1)
+	c.pending[id] = reply
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params}); err != nil {
+		return err
+	}
+
+	select {
+	case response := <-reply:
+		if response.err != nil {
+			return response.err
+		}
+		if result != nil && len(response.result) > 0 {
+			return json.Unmarshal(response.result, result)
+		}
+		return nil
+	case <-c.done:
+		return fmt.Errorf("language server %s exited: %v", c.name, c.exitErr)
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", method, ctx.Err())
+	}
+}
+
+// Notify sends a notification, which has no reply.
+func (c *Client) Notify(method string, params any) error {
+	return c.send(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *Client) send(message any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := fmt.Fprintf(c.stdin, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		return fmt.Errorf("language server %s: %w", c.name, err)
+	}
+	return nil
+}
+
+// readLoop reads messages until the server exits, answering its
+// requests, routing responses and recording diagnostics.
+func (c *Client) readLoop(reader *bufio.Reader) {
+	defer func() {
+		c.exitErr = c.cmd.Wait()
+		close(c.done)
+	}()
+	for {
+		message, err := readMessage(reader)
+		if err != nil {
+			return
+		}
+		switch {
+		case message.Method != "" && len(message.ID) > 0:
+			c.answerServerRequest(message)
+		case message.Method == "textDocument/publishDiagnostics":
+			var params publishDiagnosticsParams
+			if json.Unmarshal(message.Params, &params) == nil {
+				c.mu.Lock()
+				c.diagnostics[params.URI] = params.Diagnostics
+				c.published[params.URI]++
+				close(c.changed)
+				c.changed = make(chan struct{})
+				c.mu.Unlock()
+			}
+		case message.Method == "":
+			id, err := strconv.ParseInt(string(message.ID), 10, 64)
+			if err != nil {
+				continue
+			}
+			c.mu.Lock()
+			reply, ok := c.pending[id]
+			c.mu.Unlock()
+			if !ok {
+				continue
+			}
+			response := rpcResponse{result: message.Result}
+			if message.Error != nil {
+				response.err = fmt.Errorf("%s (code %d)", message.Error.Message, message.Error.Code)
+			}
+			reply <- response
+		}
+	}
+}
+
+// answerServerRequest replies to the requests servers send clients. Genie
+// has no settings to give and shows no progress, so the answers are empty.
+func (c *Client) answerServerRequest(message rpcMessage) {
+	var result any
+	if message.Method == "workspace/configuration" {
+		var params struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		_ = json.Unmarshal(message.Params, &params)
+		result = make([]any, len(params.Items))
+	}
+	_ = c.send(map[string]any{"jsonrpc": "2.0", "id": message.ID, "result": result})
+}
+
+func readMessage(reader *bufio.Reader) (rpcMessage, error) {
+	length := -1
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return rpcMessage{}, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return rpcMessage{}, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return rpcMessage{}, fmt.Errorf("message without Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return rpcMessage{}, err
+	}
+	var message rpcMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return rpcMessage{}, err
+	}
+	return message, nil
+}
+
+// SyncFile tells the server the file's current content: opening it the
+// first time, replacing its text after that, so answers reflect the disk
+// even when the file changed since.
+func (c *Client) SyncFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	uri := FileURI(path)
+
+	c.mu.Lock()
+	version, open := c.versions[uri]
+	version++
+	c.versions[uri] = version
+	c.mu.Unlock()
+
+	if !open {
+		return c.Notify("textDocument/didOpen", map[string]any{
+			"textDocument": map[string]any{
+				"uri":        uri,
+				"languageId": c.languageID,
+				"version":    version,
+				"text":       string(content),
+			},
+		})
+	}
+	return c.Notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": uri, "version": version},
+		"contentChanges": []map[string]string{{"text": string(content)}},
+	})
+}
+
+// Diagnostics syncs the file and waits for the server to publish its
+// diagnostics, until they settle or ctx ends. complete is false when the
+// server published nothing new in time; the last known diagnostics are
+// returned then.
+func (c *Client) Diagnostics(ctx context.Context, path string) (diagnostics []Diagnostic, complete bool, err error) {
+	uri := FileURI(path)
+	c.mu.Lock()
+	seen := c.published[uri]
+	c.mu.Unlock()
+
+	if err := c.SyncFile(path); err != nil {
+		return nil, false, err
+	}
+
+	var settle <-chan time.Time
+	for {
+		c.mu.Lock()
+		changed := c.changed
+		if c.published[uri] > seen {
+			seen = c.published[uri]
+			complete = true
+			settle = time.After(diagnosticsSettle)
+		}
+		diagnostics = c.diagnostics[uri]
+		c.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-settle:
+			return diagnostics, true, nil
+		case <-c.done:
+			return diagnostics, complete, fmt.Errorf("language server %s exited: %v", c.name, c.exitErr)
+		case <-ctx.Done():
+			return diagnostics, complete, nil
+		}
+	}
+}
+
+// Definition returns where the symbol at pos in path is defined.
+func (c *Client) Definition(ctx context.Context, path string, pos Position) ([]Location, error) {
+	if err := c.SyncFile(path); err != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	params := textDocumentPositionParams{TextDocument: textDocumentIdentifier{URI: FileURI(path)}, Position: pos}
+	if err := c.Call(ctx, "textDocument/definition", params, &raw); err != nil {
+		return nil, err
+	}
+	return decodeLocations(raw)
+}
+
+// References returns every use of the symbol at pos in path, its
+// declaration included.
+func (c *Client) References(ctx context.Context, path string, pos Position) ([]Location, error) {
+	if err := c.SyncFile(path); err != nil {
+		return nil, err
+	}
+	var locations []Location
+	params := map[string]any{
+		"textDocument": textDocumentIdentifier{URI: FileURI(path)},
+		"position":     pos,
+		"context":      map[string]bool{"includeDeclaration": true},
+	}
+	if err := c.Call(ctx, "textDocument/references", params, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// decodeLocations reads a definition result: null, a Location, a list of
+// Locations, or a list of LocationLinks.
+func decodeLocations(raw json.RawMessage) ([]Location, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+	if strings.HasPrefix(trimmed, "{") {
+		var location Location
+		if err := json.Unmarshal(raw, &location); err != nil {
+			return nil, err
+		}
+		return []Location{location}, nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	locations := make([]Location, 0, len(items))
+	for _, item := range items {
+		var link locationLink
+		if json.Unmarshal(item, &link) == nil && link.TargetURI != "" {
+			locations = append(locations, Location{URI: link.TargetURI, Range: link.TargetSelectionRange})
+			continue
+		}
+		var location Location
+		if err := json.Unmarshal(item, &location); err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// Close asks the server to shut down and kills it if it does not.
+func (c *Client) Close() error {
+	if !c.Alive() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if c.Call(ctx, "shutdown", nil, nil) == nil {
+		_ = c.Notify("exit", nil)
+	}
+	_ = c.stdin.Close()
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		_ = c.cmd.Process.Kill()
+		<-c.done
+	}
+	return nil
+}
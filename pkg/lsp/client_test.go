@@ -0,0 +1,242 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain lets the test binary play a language server when started with
+// GENIE_FAKE_LSP set.
+func TestMain(m *testing.M) {
+	if os.Getenv("GENIE_FAKE_LSP") != "" {
+		fakeServer()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeServer reports a warning on every line containing TODO, defines
+// every symbol on the file's first line and finds it on every line that
+// mentions it.
+func fakeServer() {
+	reader := bufio.NewReader(os.Stdin)
+	write := func(message map[string]any) {
+		message["jsonrpc"] = "2.0"
+		body, _ := json.Marshal(message)
+		fmt.Fprintf(os.Stdout, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	texts := make(map[string]string)
+	for {
+		message, err := readMessage(reader)
+		if err != nil {
+			return
+		}
+		var params struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+			Position Position `json:"position"`
+		}
+		_ = json.Unmarshal(message.Params, &params)
+		uri := params.TextDocument.URI
+
+		switch message.Method {
+		case "initialize":
+			write(map[string]any{"id": message.ID, "result": map[string]any{"capabilities": map[string]any{}}})
+			// Servers ask for settings; the client must answer
+			write(map[string]any{"id": 99, "method": "workspace/configuration", "params": map[string]any{"items": []any{map[string]any{}}}})
+		case "textDocument/didOpen", "textDocument/didChange":
+			texts[uri] = params.TextDocument.Text
+			if len(params.ContentChanges) > 0 {
+				texts[uri] = params.ContentChanges[0].Text
+			}
+			var diagnostics []Diagnostic
+			for i, line := range strings.Split(texts[uri], "\n") {
+				if column := strings.Index(line, "TODO"); column >= 0 {
+					diagnostics = append(diagnostics, Diagnostic{
+						Range:    Range{Start: Position{Line: i, Character: column}, End: Position{Line: i, Character: column + 4}},
+						Severity: SeverityWarning,
+						Source:   "fake",
+						Message:  "unfinished",
+					})
+				}
+			}
+			write(map[string]any{"method": "textDocument/publishDiagnostics", "params": publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics}})
+		case "textDocument/definition":
+			write(map[string]any{"id": message.ID, "result": []any{map[string]any{
+				"targetUri":            uri,
+				"targetRange":          Range{},
+				"targetSelectionRange": Range{Start: Position{Character: 5}, End: Position{Character: 9}},
+			}}})
+		case "textDocument/references":
+			lines := strings.Split(texts[uri], "\n")
+			symbol := symbolAt(lines[params.Position.Line], params.Position.Character)
+			var locations []Location
+			for i, line := range lines {
+				if column := strings.Index(line, symbol); column >= 0 {
+					locations = append(locations, Location{URI: uri, Range: Range{Start: Position{Line: i, Character: column}}})
+				}
+			}
+			write(map[string]any{"id": message.ID, "result": locations})
+		case "shutdown":
+			write(map[string]any{"id": message.ID, "result": nil})
+		case "exit":
+			return
+		}
+	}
+}
+
+func symbolAt(line string, column int) string {
+	start, end := column, column
+	for start > 0 && line[start-1] != ' ' {
+		start--
+	}
+	for end < len(line) && line[end] != ' ' && line[end] != '(' {
+		end++
+	}
+	return line[start:end]
+}
+
+func startFakeServer(t *testing.T, root string) *Client {
+	t.Helper()
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	client, err := Start(context.Background(), "fake", ServerConfig{
+		Command:    executable,
+		Env:        map[string]string{"GENIE_FAKE_LSP": "1"},
+		Extensions: []string{".fake"},
+	}, root)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClientDiagnosticsFollowTheFileOnDisk(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "main.fake")
+	require.NoError(t, os.WriteFile(path, []byte("func main\n  TODO\n"), 0644))
+	client := startFakeServer(t, root)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	diagnostics, complete, err := client.Diagnostics(ctx, path)
+	require.NoError(t, err)
+	assert.True(t, complete)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, 1, diagnostics[0].Range.Start.Line)
+	assert.Equal(t, SeverityWarning, diagnostics[0].Severity)
+	assert.Equal(t, "unfinished", diagnostics[0].Message)
+
+	// A later edit is sent as a change, and the fixed file comes back clean
+	require.NoError(t, os.WriteFile(path, []byte("func main\n"), 0644))
+	diagnostics, complete, err = client.Diagnostics(ctx, path)
+	require.NoError(t, err)
+	assert.True(t, complete)
+	assert.Empty(t, diagnostics)
+}
+
+func TestClientDefinitionAndReferences(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "main.fake")
+	require.NoError(t, os.WriteFile(path, []byte("func main\nmain()\nother()\nmain()\n"), 0644))
+	client := startFakeServer(t, root)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	definitions, err := client.Definition(ctx, path, Position{Line: 1, Character: 0})
+	require.NoError(t, err)
+	require.Len(t, definitions, 1, "location links are read as locations")
+	assert.Equal(t, path, URIPath(definitions[0].URI))
+	assert.Equal(t, Position{Line: 0, Character: 5}, definitions[0].Range.Start)
+
+	references, err := client.References(ctx, path, Position{Line: 1, Character: 1})
+	require.NoError(t, err)
+	var lines []int
+	for _, reference := range references {
+		lines = append(lines, reference.Range.Start.Line)
+	}
+	assert.Equal(t, []int{0, 1, 3}, lines)
+}
+
+func TestClientCloseStopsTheServer(t *testing.T) {
+	client := startFakeServer(t, t.TempDir())
+	require.True(t, client.Alive())
+	require.NoError(t, client.Close())
+	assert.False(t, client.Alive())
+}
+
+func TestDecodeLocations(t *testing.T) {
+	locations, err := decodeLocations(json.RawMessage(`null`))
+	require.NoError(t, err)
+	assert.Empty(t, locations)
+
+	locations, err = decodeLocations(json.RawMessage(`{"uri":"file:///a.go","range":{"start":{"line":3,"character":1},"end":{"line":3,"character":2}}}`))
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, 3, locations[0].Range.Start.Line)
+}
+
+func TestColumnsCountUTF16Units(t *testing.T) {
+	line := "s := \"héllo 😀\" + x"
+	offset := strings.Index(line, "x")
+	column := UTF16Column(line, offset)
+	// é is one unit, the emoji two
+	assert.Equal(t, len([]rune(line[:offset]))+1, column)
+	assert.Equal(t, offset, ByteColumn(line, column))
+}
+
+func TestFileURIRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dir with space", "a.go")
+	uri := FileURI(path)
+	assert.True(t, strings.HasPrefix(uri, "file:///"))
+	assert.NotContains(t, uri, " ")
+	assert.Equal(t, path, URIPath(uri))
+}
+
+func TestLoadConfigProjectOverridesUserAndCanBeSkipped(t *testing.T) {
+	home, project := t.TempDir(), t.TempDir()
+	writeConfig := func(dir, command string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".genie"), 0755))
+		data := fmt.Sprintf(`{"languageServers": {"go": {"command": %q, "extensions": [".go"]}}}`, command)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".genie", ConfigFileName), []byte(data), 0644))
+	}
+	writeConfig(home, "user-gopls")
+	writeConfig(project, "project-gopls")
+
+	config, err := LoadConfig(project, home, false)
+	require.NoError(t, err)
+	name, server, ok := config.serverFor("/src/main.go")
+	require.True(t, ok)
+	assert.Equal(t, "go", name)
+	assert.Equal(t, "project-gopls", server.Command)
+
+	config, err = LoadConfig(project, home, true)
+	require.NoError(t, err)
+	_, server, _ = config.serverFor("/src/main.go")
+	assert.Equal(t, "user-gopls", server.Command, "an untrusted workspace's lsp.json names commands to run")
+}
+
+func TestLoadConfigRejectsServerWithoutExtensions(t *testing.T) {
+	project := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".genie"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".genie", ConfigFileName),
+		[]byte(`{"languageServers": {"go": {"command": "gopls"}}}`), 0644))
+
+	_, err := LoadConfig(project, t.TempDir(), false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extensions are required")
+}
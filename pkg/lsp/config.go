@@ -0,0 +1,132 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigFileName is the language server configuration, read from the
+// project's and the user's .genie directories.
+const ConfigFileName = "lsp.json"
+
+// Config is the structure of lsp.json:
+//
+//	{
+//	  "languageServers": {
+//	    "go": {"command": "gopls", "extensions": [".go"]}
+//	  }
+//	}
+type Config struct {
+	LanguageServers map[string]ServerConfig `json:"languageServers"`
+}
+
+// ServerConfig says how to start a language server and which files it
+// understands.
+type ServerConfig struct {
+	Command    string            `json:"command"`
+	Args       []string          `json:"args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	Extensions []string          `json:"extensions"`
+	// LanguageID names the language to the server; defaults to the
+	// server's name in the config
+	LanguageID string `json:"languageId,omitempty"`
+	// InitializationOptions are passed to the server as they are
+	InitializationOptions json.RawMessage `json:"initializationOptions,omitempty"`
+}
+
+// Validate checks that the server can be started and matched to files.
+func (sc ServerConfig) Validate() error {
+	if strings.TrimSpace(sc.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if len(sc.Extensions) == 0 {
+		return fmt.Errorf("extensions are required, e.g. [\".go\"]")
+	}
+	return nil
+}
+
+// handles reports whether the server understands the file at path.
+func (sc ServerConfig) handles(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, handled := range sc.Extensions {
+		if strings.ToLower(handled) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultServers are used for languages no lsp.json configures, when
+// their command is installed.
+var DefaultServers = map[string]ServerConfig{
+	"go":         {Command: "gopls", Extensions: []string{".go"}},
+	"python":     {Command: "pyright-langserver", Args: []string{"--stdio"}, Extensions: []string{".py"}},
+	"typescript": {Command: "typescript-language-server", Args: []string{"--stdio"}, Extensions: []string{".ts", ".tsx", ".js", ".jsx"}},
+	"rust":       {Command: "rust-analyzer", Extensions: []string{".rs"}},
+}
+
+// LoadConfig reads the user's ~/.genie/lsp.json and then the project's
+// .genie/lsp.json, whose servers replace the user's of the same name.
+// userOnly skips the project file, for workspaces the user has not
+// trusted: it names commands Genie would run.
+func LoadConfig(projectRoot, userHome string, userOnly bool) (*Config, error) {
+	config := &Config{LanguageServers: make(map[string]ServerConfig)}
+	paths := []string{filepath.Join(userHome, ".genie", ConfigFileName)}
+	if !userOnly {
+		paths = append(paths, filepath.Join(projectRoot, ".genie", ConfigFileName))
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var file Config
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		for name, server := range file.LanguageServers {
+			if err := server.Validate(); err != nil {
+				return nil, fmt.Errorf("%s: language server %q: %w", path, name, err)
+			}
+			config.LanguageServers[name] = server
+		}
+	}
+	return config, nil
+}
+
+// serverFor returns the configured server for the file at path, falling
+// back to an installed default one.
+func (c *Config) serverFor(path string) (string, ServerConfig, bool) {
+	for _, name := range sortedNames(c.LanguageServers) {
+		if server := c.LanguageServers[name]; server.handles(path) {
+			return name, server, true
+		}
+	}
+	for _, name := range sortedNames(DefaultServers) {
+		server := DefaultServers[name]
+		if _, configured := c.LanguageServers[name]; configured || !server.handles(path) {
+			continue
+		}
+		if _, err := exec.LookPath(server.Command); err == nil {
+			return name, server, true
+		}
+	}
+	return "", ServerConfig{}, false
+}
+
+func sortedNames(servers map[string]ServerConfig) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,99 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// Manager starts language servers on first use, one per workspace and
+// language, and keeps them running for later questions.
+type Manager struct {
+	mu       sync.Mutex
+	clients  map[string]*Client
+	userHome string
+	userOnly bool
+}
+
+// NewManager creates a manager reading lsp.json from the user's home and
+// each workspace.
+func NewManager() *Manager {
+	home, _ := os.UserHomeDir()
+	return &Manager{
+		clients:  make(map[string]*Client),
+		userHome: home,
+	}
+}
+
+// DisableProjectConfig makes the manager ignore the workspace's
+// .genie/lsp.json, for workspaces the user has not trusted.
+func (m *Manager) DisableProjectConfig() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.userOnly = true
+}
+
+// ClientFor returns the running server for the file at path in the
+// workspace at root, starting it when needed. A context marking the
+// workspace untrusted skips its lsp.json too.
+func (m *Manager) ClientFor(ctx context.Context, root, path string) (*Client, error) {
+	m.mu.Lock()
+	userOnly := m.userOnly
+	m.mu.Unlock()
+	if trusted, ok := toolctx.WorkspaceTrusted(ctx); ok && !trusted {
+		userOnly = true
+	}
+
+	config, err := LoadConfig(root, m.userHome, userOnly)
+	if err != nil {
+		return nil, err
+	}
+	name, server, ok := config.serverFor(path)
+	if !ok {
+		return nil, fmt.Errorf("no language server for %s files; configure one in .genie/%s", extensionOf(path), ConfigFileName)
+	}
+
+	key := root + "\x00" + name
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if client, ok := m.clients[key]; ok && client.Alive() {
+		return client, nil
+	}
+	// Starting holds the lock so concurrent calls share one server
+	client, err := Start(ctx, name, server, root)
+	if err != nil {
+		return nil, err
+	}
+	m.clients[key] = client
+	return client, nil
+}
+
+// Close shuts every server down.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	clients := m.clients
+	m.clients = make(map[string]*Client)
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			_ = client.Close()
+		}(client)
+	}
+	wg.Wait()
+	return nil
+}
+
+func extensionOf(path string) string {
+	if ext := filepath.Ext(path); ext != "" {
+		return ext
+	}
+	return filepath.Base(path)
+}
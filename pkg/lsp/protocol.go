@@ -0,0 +1,126 @@
+package lsp
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The subset of the Language Server Protocol the tools use. Positions are
+// zero-based and count UTF-16 code units, as the protocol specifies.
+
+// Position is a place in a text document.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span of a text document, end exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range in a file.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// locationLink is the richer answer servers may give to a definition
+// request.
+type locationLink struct {
+	TargetURI            string `json:"targetUri"`
+	TargetSelectionRange Range  `json:"targetSelectionRange"`
+}
+
+// Severity ranks a diagnostic.
+type Severity int
+
+const (
+	SeverityError       Severity = 1
+	SeverityWarning     Severity = 2
+	SeverityInformation Severity = 3
+	SeverityHint        Severity = 4
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInformation:
+		return "info"
+	case SeverityHint:
+		return "hint"
+	}
+	return "error" // the protocol says to treat a missing severity as an error
+}
+
+// Diagnostic is a problem the server found in a document.
+type Diagnostic struct {
+	Range    Range           `json:"range"`
+	Severity Severity        `json:"severity,omitempty"`
+	Code     json.RawMessage `json:"code,omitempty"`
+	Source   string          `json:"source,omitempty"`
+	Message  string          `json:"message"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// FileURI returns the file:// URI of an absolute path.
+func FileURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// URIPath returns the path of a file:// URI, or the URI itself when it is
+// not one.
+func URIPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(parsed.Path)
+}
+
+// UTF16Column converts a byte offset into line to the UTF-16 offset
+// positions use.
+func UTF16Column(line string, byteOffset int) int {
+	byteOffset = min(max(byteOffset, 0), len(line))
+	column := 0
+	for _, r := range line[:byteOffset] {
+		column += len(utf16.Encode([]rune{r}))
+	}
+	return column
+}
+
+// ByteColumn converts a UTF-16 offset into line back to a byte offset.
+func ByteColumn(line string, utf16Offset int) int {
+	units := 0
+	for i, r := range line {
+		if units >= utf16Offset {
+			return i
+		}
+		if r == utf8.RuneError {
+			units++
+			continue
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return len(line)
+}
@@ -28,10 +28,13 @@ const (
 type Client struct {
 	config       *Config
 	servers      map[string]*ServerConnection
-	tools        map[string]*MCPTool
+	tools        map[string]*MCPTool // by the name Genie offers them under
+	builtinTools []string            // names MCP tools must not take by default
+	conflicts    []tools.ToolConflict
 	transport    *TransportFactory
 	mu           sync.RWMutex
 	initialized  bool
+	userOnly     bool // Ignore the project .mcp.json (untrusted workspace)
 	serverErrors map[string]string
 	requestID    atomic.Int64
 }
@@ -64,6 +67,9 @@ type MCPTool struct {
 	mcpTool    Tool
 	serverName string
 	client     *Client
+	// name is what the model calls the tool; it differs from the server's
+	// name for it when that clashes with another tool
+	name string
 }
 
 // NewClient creates a new MCP client (uninitialized - call Init to connect)
@@ -78,6 +84,15 @@ func NewClient(config *Config) *Client {
 	}
 }
 
+// DisableProjectConfig makes Init ignore the project .mcp.json so an
+// untrusted workspace cannot start its own servers. User-scoped config
+// still loads. Must be called before Init.
+func (c *Client) DisableProjectConfig() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userOnly = true
+}
+
 // Init initializes the MCP client by discovering config from the working directory
 // and connecting to all configured servers. This should be called after the working
 // directory is known (e.g., from Genie.Start).
@@ -90,7 +105,13 @@ func (c *Client) Init(workingDir string) error {
 	}
 
 	// Try to find and load MCP configuration from the working directory
-	configPath, err := FindConfigFile(workingDir)
+	var configPath string
+	var err error
+	if c.userOnly {
+		configPath, err = FindUserConfigFile()
+	} else {
+		configPath, err = FindConfigFile(workingDir)
+	}
 	if err != nil {
 		// No MCP config found - this is fine, just mark as initialized with no servers
 		c.initialized = true
@@ -122,6 +143,7 @@ func (c *Client) Init(workingDir string) error {
 		}
 		delete(c.serverErrors, serverName)
 	}
+	c.resolveToolNames()
 
 	c.initialized = true
 	return nil
@@ -142,6 +164,7 @@ func (c *Client) ConnectToServers(ctx context.Context) error {
 		}
 		delete(c.serverErrors, serverName)
 	}
+	c.resolveToolNames()
 
 	return nil
 }
@@ -257,21 +280,12 @@ func (c *Client) discoverTools(ctx context.Context, conn *ServerConnection) erro
 		return fmt.Errorf("failed to parse tools result: %w", err)
 	}
 
-	// Store tools in connection
+	// Store tools in connection; resolveToolNames wraps them once every
+	// server has reported its tools
 	conn.mu.Lock()
 	conn.tools = toolsResult.Tools
 	conn.mu.Unlock()
 
-	// Create MCP tool wrappers and register them
-	for _, tool := range toolsResult.Tools {
-		mcpTool := &MCPTool{
-			mcpTool:    tool,
-			serverName: conn.name,
-			client:     c,
-		}
-		c.tools[tool.Name] = mcpTool
-	}
-
 	return nil
 }
 
@@ -350,7 +364,7 @@ func (c *Client) GetToolsByServer() map[string][]tools.Tool {
 // Ensure Client implements the MCPClient interface
 var _ tools.MCPClient = (*Client)(nil)
 
-// CallTool executes an MCP tool
+// CallTool executes an MCP tool by the name Genie offers it under
 func (c *Client) CallTool(ctx context.Context, toolName string, arguments map[string]interface{}) (*CallToolResult, error) {
 	c.mu.RLock()
 	mcpTool, exists := c.tools[toolName]
@@ -388,9 +402,9 @@ func (c *Client) CallTool(ctx context.Context, toolName string, arguments map[st
 		defer cancel()
 	}
 
-	// Send tool call request
+	// Send tool call request under the server's own name for the tool
 	callReq := CallToolRequest{
-		Name:      toolName,
+		Name:      mcpTool.mcpTool.Name,
 		Arguments: arguments,
 	}
 
@@ -455,7 +469,7 @@ func (t *MCPTool) Declaration() *ai.FunctionDeclaration {
 	params := convertMCPSchemaToGenieSchema(t.mcpTool.InputSchema)
 
 	return &ai.FunctionDeclaration{
-		Name:        t.mcpTool.Name,
+		Name:        t.Name(),
 		Description: t.mcpTool.Description,
 		Parameters:  params,
 		Response: &ai.Schema{
@@ -476,11 +490,19 @@ func (t *MCPTool) Declaration() *ai.FunctionDeclaration {
 	}
 }
 
+// Name returns the name the model calls the tool by
+func (t *MCPTool) Name() string {
+	if t.name != "" {
+		return t.name
+	}
+	return t.mcpTool.Name
+}
+
 // Handler returns the execution handler for the MCP tool
 func (t *MCPTool) Handler() ai.HandlerFunc {
 	return func(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
 		// Call the MCP tool through the client
-		result, err := t.client.CallTool(ctx, t.mcpTool.Name, params)
+		result, err := t.client.CallTool(ctx, t.Name(), params)
 		if err != nil {
 			return nil, err
 		}
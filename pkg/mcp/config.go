@@ -25,6 +25,14 @@ type ServerConfig struct {
 	Type    string            `json:"type,omitempty"`
 	URL     string            `json:"url,omitempty"`
 	Headers map[string]string `json:"headers,omitempty"`
+
+	// NamespaceTools always offers this server's tools as
+	// mcp_<server>_<tool>, not only when their names clash
+	NamespaceTools bool `json:"namespaceTools,omitempty"`
+	// OverrideTools lets this server's tools keep their names when they
+	// clash with a built-in tool or another server's tool, which are
+	// renamed or hidden instead
+	OverrideTools bool `json:"overrideTools,omitempty"`
 }
 
 // TransportType represents the type of transport for an MCP server
@@ -75,7 +83,12 @@ func FindConfigFile(projectRoot string) (string, error) {
 		return projectConfig, nil
 	}
 
-	// Then try user-scoped config
+	return FindUserConfigFile()
+}
+
+// FindUserConfigFile looks for a user-scoped MCP config, ignoring any
+// project .mcp.json
+func FindUserConfigFile() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get user home directory: %w", err)
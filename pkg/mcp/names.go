@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// maxToolNameLength is the longest tool name every provider accepts
+const maxToolNameLength = 64
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// NamespacedToolName is the name a server's tool is offered under when
+// its own name clashes with another tool: mcp_<server>_<tool>, limited to
+// the characters and length every provider accepts.
+func NamespacedToolName(serverName, toolName string) string {
+	name := invalidToolNameChars.ReplaceAllString("mcp_"+serverName+"_"+toolName, "_")
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+// ResolveToolNames settles the name of every discovered tool given the
+// names of Genie's built-in tools, and returns the conflicts it found.
+func (c *Client) ResolveToolNames(builtin []string) []tools.ToolConflict {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.builtinTools = append([]string(nil), builtin...)
+	c.resolveToolNames()
+	return append([]tools.ToolConflict(nil), c.conflicts...)
+}
+
+// resolveToolNames rebuilds c.tools from the connected servers. A tool
+// keeps its own name unless another source offers it too. Then the
+// built-in tool keeps it, or the one server set to override, and every
+// other server's tool is namespaced. Servers and names are visited in
+// order so the outcome does not depend on map iteration. The caller
+// holds mu.
+func (c *Client) resolveToolNames() {
+	serverNames := make([]string, 0, len(c.servers))
+	for name := range c.servers {
+		serverNames = append(serverNames, name)
+	}
+	sort.Strings(serverNames)
+
+	// Who offers each name, in server order
+	offeredBy := make(map[string][]*MCPTool)
+	var names []string
+	c.tools = make(map[string]*MCPTool)
+	for _, serverName := range serverNames {
+		conn := c.servers[serverName]
+		conn.mu.RLock()
+		serverTools := conn.tools
+		conn.mu.RUnlock()
+		for _, tool := range serverTools {
+			mcpTool := &MCPTool{mcpTool: tool, serverName: serverName, client: c}
+			if conn.config.NamespaceTools {
+				mcpTool.name = NamespacedToolName(serverName, tool.Name)
+				c.tools[mcpTool.name] = mcpTool
+				continue
+			}
+			if offeredBy[tool.Name] == nil {
+				names = append(names, tool.Name)
+			}
+			offeredBy[tool.Name] = append(offeredBy[tool.Name], mcpTool)
+		}
+	}
+
+	sort.Strings(names)
+	c.conflicts = nil
+	for _, name := range names {
+		offers := offeredBy[name]
+		isBuiltin := slices.Contains(c.builtinTools, name)
+		if len(offers) == 1 && !isBuiltin {
+			offers[0].name = name
+			c.tools[name] = offers[0]
+			continue
+		}
+
+		conflict := tools.ToolConflict{Name: name, Renamed: make(map[string]string)}
+		if isBuiltin {
+			conflict.Sources = append(conflict.Sources, tools.BuiltinToolSource)
+			conflict.Kept = tools.BuiltinToolSource
+		}
+		var overriding []*MCPTool
+		for _, offer := range offers {
+			conflict.Sources = append(conflict.Sources, offer.serverName)
+			if c.servers[offer.serverName].config.OverrideTools {
+				overriding = append(overriding, offer)
+			}
+		}
+		// Two servers both set to override cancel out
+		var winner *MCPTool
+		if len(overriding) == 1 {
+			winner = overriding[0]
+			conflict.Kept = winner.serverName
+		}
+
+		for _, offer := range offers {
+			if offer == winner {
+				offer.name = name
+			} else {
+				offer.name = NamespacedToolName(offer.serverName, name)
+				conflict.Renamed[offer.serverName] = offer.name
+			}
+			c.tools[offer.name] = offer
+		}
+		c.conflicts = append(c.conflicts, conflict)
+	}
+}
+
+// Ensure Client renames clashing tools
+var _ tools.ToolNameResolver = (*Client)(nil)
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newResolvingClient returns a client whose servers offer the named tools
+// without being connected
+func newResolvingClient(servers map[string]ServerConfig, offered map[string][]string) *Client {
+	client := NewClient(&Config{McpServers: servers})
+	for name, config := range servers {
+		conn := &ServerConnection{name: name, config: config, connected: true}
+		for _, toolName := range offered[name] {
+			conn.tools = append(conn.tools, Tool{Name: toolName})
+		}
+		client.servers[name] = conn
+	}
+	return client
+}
+
+func toolNames(client *Client) []string {
+	var names []string
+	for _, tool := range client.GetTools() {
+		names = append(names, tool.Declaration().Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestResolveToolNamesNamespacesClashes(t *testing.T) {
+	client := newResolvingClient(
+		map[string]ServerConfig{"fs": {}, "files": {}},
+		map[string][]string{"fs": {"readFile", "search"}, "files": {"search", "stat"}},
+	)
+
+	conflicts := client.ResolveToolNames([]string{"readFile", "bash"})
+	assert.Equal(t, []string{"mcp_files_search", "mcp_fs_readFile", "mcp_fs_search", "stat"}, toolNames(client))
+
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, "readFile is offered by the built-in tool and MCP server fs; the built-in tool keeps the name; MCP server fs's is mcp_fs_readFile",
+		conflicts[0].String())
+	assert.Equal(t, []string{"files", "fs"}, conflicts[1].Sources, "servers are settled in name order")
+	assert.Empty(t, conflicts[1].Kept, "neither server keeps a name both offer")
+	assert.False(t, conflicts[0].ShadowsBuiltin())
+}
+
+func TestResolveToolNamesHonoursServerConfig(t *testing.T) {
+	client := newResolvingClient(
+		map[string]ServerConfig{"fs": {OverrideTools: true}, "files": {}, "web": {NamespaceTools: true}},
+		map[string][]string{"fs": {"readFile", "search"}, "files": {"search"}, "web": {"fetch"}},
+	)
+
+	conflicts := client.ResolveToolNames([]string{"readFile"})
+	assert.Equal(t, []string{"mcp_files_search", "mcp_web_fetch", "readFile", "search"}, toolNames(client))
+	require.Len(t, conflicts, 2)
+	assert.True(t, conflicts[0].ShadowsBuiltin())
+	assert.Equal(t, "fs", conflicts[1].Kept)
+	assert.Equal(t, map[string]string{"files": "mcp_files_search"}, conflicts[1].Renamed)
+
+	// Calls go to the server under its own name for the tool
+	assert.Equal(t, "search", client.tools["mcp_files_search"].mcpTool.Name)
+	assert.Equal(t, "files", client.tools["mcp_files_search"].serverName)
+}
+
+func TestNamespacedToolName(t *testing.T) {
+	assert.Equal(t, "mcp_my_server_read_file", NamespacedToolName("my.server", "read file"))
+	assert.Len(t, NamespacedToolName("server", string(make([]byte, 100))), 64)
+}
@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/httpclient"
+)
+
+const (
+	// EmbeddingModelConfigKey names the embedding model of an
+	// OpenAI-compatible embeddings API, e.g. text-embedding-3-small or an
+	// Ollama model like nomic-embed-text. Unset, memories are embedded
+	// locally.
+	EmbeddingModelConfigKey = "GENIE_MEMORY_EMBEDDING_MODEL"
+	// EmbeddingURLConfigKey is the base URL of that API (default
+	// https://api.openai.com/v1; http://localhost:11434/v1 for Ollama).
+	EmbeddingURLConfigKey = "GENIE_MEMORY_EMBEDDING_URL"
+	// EmbeddingAPIKeyConfigKey is its API key, defaulting to OPENAI_API_KEY
+	// for api.openai.com.
+	EmbeddingAPIKeyConfigKey = "GENIE_MEMORY_EMBEDDING_API_KEY"
+
+	defaultEmbeddingURL = "https://api.openai.com/v1"
+	embeddingTimeout    = 15 * time.Second
+)
+
+// Embedder turns texts into vectors whose cosine similarity says how
+// related the texts are.
+type Embedder interface {
+	// Name identifies the embedder and its model; vectors are only
+	// compared with vectors of the same name
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// MinScore is the similarity below which a memory is unrelated
+	MinScore() float64
+}
+
+// NewEmbedder returns the embedder the configuration asks for: an
+// embeddings API when GENIE_MEMORY_EMBEDDING_MODEL is set, otherwise the
+// local one. It fails when the HTTP client for the API cannot be built,
+// e.g. because GENIE_CA_CERT is unreadable.
+func NewEmbedder(cfg config.Manager) (Embedder, error) {
+	model := strings.TrimSpace(cfg.GetStringWithDefault(EmbeddingModelConfigKey, ""))
+	if model == "" {
+		return LocalEmbedder{}, nil
+	}
+	baseURL := strings.TrimRight(cfg.GetStringWithDefault(EmbeddingURLConfigKey, defaultEmbeddingURL), "/")
+	apiKey := cfg.GetStringWithDefault(EmbeddingAPIKeyConfigKey, "")
+	if apiKey == "" && baseURL == defaultEmbeddingURL {
+		apiKey = cfg.GetStringWithDefault("OPENAI_API_KEY", "")
+	}
+	client, err := httpclient.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("memory embeddings client: %w", err)
+	}
+	return &APIEmbedder{BaseURL: baseURL, Model: model, APIKey: strings.TrimSpace(apiKey), Client: client}, nil
+}
+
+// localDimensions is the length of local vectors
+const localDimensions = 1024
+
+// LocalEmbedder embeds without a model or the network by hashing words
+// and their character trigrams into a fixed-size vector. It finds facts
+// sharing words or word stems with the message ("deploy" and
+// "deployment"), not synonyms.
+type LocalEmbedder struct{}
+
+// Name identifies the local embedder's vectors.
+func (LocalEmbedder) Name() string { return "local-hash-v1" }
+
+// MinScore is the similarity of texts sharing about one significant word.
+func (LocalEmbedder) MinScore() float64 { return 0.2 }
+
+// Embed hashes each text into a normalized vector.
+func (LocalEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vector := make([]float32, localDimensions)
+		for _, word := range words(text) {
+			addFeature(vector, "w:"+word, 1)
+			padded := "^" + word + "$"
+			runes := []rune(padded)
+			for j := 0; j+3 <= len(runes); j++ {
+				addFeature(vector, "t:"+string(runes[j:j+3]), 0.3)
+			}
+		}
+		normalize(vector)
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// stopWords carry no meaning of their own and would make every fact
+// match every message
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"do": true, "for": true, "from": true, "how": true, "i": true, "in": true, "is": true, "it": true,
+	"me": true, "my": true, "of": true, "on": true, "or": true, "our": true, "should": true, "so": true,
+	"that": true, "the": true, "this": true, "to": true, "use": true, "we": true, "what": true,
+	"when": true, "with": true, "you": true, "your": true, "can": true, "please": true, "always": true,
+	"remember": true, "not": true, "don't": true, "never": true,
+}
+
+// words returns the lower-case words of text without stop words
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	var kept []string
+	for _, field := range fields {
+		field = strings.Trim(field, "'")
+		if field != "" && !stopWords[field] {
+			kept = append(kept, stem(field))
+		}
+	}
+	return kept
+}
+
+// stemSuffixes are cut from longer words so "deployments", "deploying"
+// and "deploy" are the same word
+var stemSuffixes = []string{"ments", "ment", "ings", "ing", "ions", "ion", "ers", "er", "ies", "es", "ed", "s"}
+
+func stem(word string) string {
+	for _, suffix := range stemSuffixes {
+		if len(word) > len(suffix)+3 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+func addFeature(vector []float32, feature string, weight float32) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(feature))
+	sum := h.Sum32()
+	// The top bit picks the sign so colliding features tend to cancel
+	if sum&(1<<31) != 0 {
+		weight = -weight
+	}
+	vector[sum%localDimensions] += weight
+}
+
+func normalize(vector []float32) {
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+}
+
+// APIEmbedder embeds with an OpenAI-compatible /embeddings endpoint,
+// which OpenAI, Ollama, LM Studio and most model servers offer.
+type APIEmbedder struct {
+	BaseURL string
+	Model   string
+	APIKey  string
+	Client  *http.Client
+}
+
+// Name identifies the model's vectors.
+func (e *APIEmbedder) Name() string { return "api:" + e.Model }
+
+// MinScore suits embedding models, which score unrelated texts well
+// above zero.
+func (e *APIEmbedder) MinScore() float64 { return 0.35 }
+
+// Embed asks the API for one vector per text.
+func (e *APIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]any{"model": e.Model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, embeddingTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.BaseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.APIKey)
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embeddings response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embeddings API returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var parsed struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse embeddings response: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, item := range parsed.Data {
+		if item.Index >= 0 && item.Index < len(vectors) {
+			vectors[item.Index] = item.Embedding
+		}
+	}
+	for i, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("embeddings API returned no vector for input %d", i)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,348 @@
+// Package memory keeps the facts a user asked Genie to remember across
+// sessions. Each fact is embedded as a vector; before every turn the
+// facts closest to the user's message are recalled into the model's
+// context. Facts live in ~/.genie/memories.json, either for every
+// project or for one workspace.
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+const (
+	// ConfigKey turns recall off when set to false (default on). The
+	// remember tool and genie memory keep working.
+	ConfigKey = "GENIE_MEMORY"
+
+	// FileName is the store file inside ~/.genie.
+	FileName = "memories.json"
+
+	// GlobalScope is the scope of facts that apply in every project.
+	GlobalScope = "global"
+
+	// maxFactLength caps one fact; memories are facts, not documents
+	maxFactLength = 2000
+)
+
+// Memory is one remembered fact.
+type Memory struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	// Scope is GlobalScope or the absolute path of the workspace the
+	// fact belongs to
+	Scope     string    `json:"scope"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Embedder names what computed Vector; vectors of different
+	// embedders are not comparable, so a mismatch means embed again
+	Embedder string    `json:"embedder,omitempty"`
+	Vector   []float32 `json:"vector,omitempty"`
+}
+
+// Match is a recalled memory and how close it is to the query, from -1
+// to 1.
+type Match struct {
+	Memory
+	Score float64
+}
+
+type storeFile struct {
+	Memories []Memory `json:"memories"`
+}
+
+// Store persists memories in a JSON file.
+type Store struct {
+	path     string
+	embedder Embedder
+	mu       sync.Mutex
+}
+
+// NewStore returns a store backed by the file at path that embeds with
+// embedder. The file is created on the first Add.
+func NewStore(path string, embedder Embedder) *Store {
+	return &Store{path: path, embedder: embedder}
+}
+
+// DefaultPath returns ~/.genie/memories.json.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".genie", FileName), nil
+}
+
+// Add remembers text for scope: GlobalScope or a workspace directory.
+// When the embedder fails, as a remote one can offline, the fact is kept
+// and embedded on a later Recall.
+func (s *Store) Add(ctx context.Context, text, scope string) (Memory, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Memory{}, fmt.Errorf("nothing to remember")
+	}
+	if len(text) > maxFactLength {
+		return Memory{}, fmt.Errorf("a memory is at most %d characters; remember the fact, not the document", maxFactLength)
+	}
+	scope, err := normalizeScope(scope)
+	if err != nil {
+		return Memory{}, err
+	}
+
+	memory := Memory{ID: newID(), Text: text, Scope: scope, CreatedAt: time.Now().UTC()}
+	if vectors, err := s.embedder.Embed(ctx, []string{text}); err == nil && len(vectors) == 1 {
+		memory.Embedder, memory.Vector = s.embedder.Name(), vectors[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return Memory{}, err
+	}
+	file.Memories = append(file.Memories, memory)
+	return memory, s.save(file)
+}
+
+// List returns the memories that apply in workspace, global ones
+// included, oldest first. An empty workspace lists every memory.
+func (s *Store) List(workspace string) ([]Memory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if workspace == "" {
+		return file.Memories, nil
+	}
+	workspace, err = normalizeScope(workspace)
+	if err != nil {
+		return nil, err
+	}
+	var memories []Memory
+	for _, memory := range file.Memories {
+		if applies(memory, workspace) {
+			memories = append(memories, memory)
+		}
+	}
+	return memories, nil
+}
+
+// Forget deletes the memory whose ID is or unambiguously starts with id
+// and returns it.
+func (s *Store) Forget(id string) (Memory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return Memory{}, err
+	}
+	index := -1
+	for i, memory := range file.Memories {
+		if memory.ID == id {
+			index = i
+			break
+		}
+		if id != "" && strings.HasPrefix(memory.ID, id) {
+			if index >= 0 {
+				return Memory{}, fmt.Errorf("memory %q is ambiguous", id)
+			}
+			index = i
+		}
+	}
+	if index < 0 {
+		return Memory{}, fmt.Errorf("no memory %q (see genie memory list)", id)
+	}
+	forgotten := file.Memories[index]
+	file.Memories = append(file.Memories[:index], file.Memories[index+1:]...)
+	return forgotten, s.save(file)
+}
+
+// Recall returns up to limit memories that apply in workspace and score
+// at least the embedder's MinScore against query, best first. Memories
+// embedded by another embedder, or not at all, are embedded again first.
+func (s *Store) Recall(ctx context.Context, query, workspace string, limit int) ([]Match, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || limit <= 0 {
+		return nil, nil
+	}
+	workspace, err := normalizeScope(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.embedStale(ctx, file); err != nil {
+		return nil, err
+	}
+	vectors, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed the message: %w", err)
+	}
+
+	var matches []Match
+	for _, memory := range file.Memories {
+		if !applies(memory, workspace) {
+			continue
+		}
+		if score := cosine(vectors[0], memory.Vector); score >= s.embedder.MinScore() {
+			matches = append(matches, Match{Memory: memory, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// embedStale embeds the memories the current embedder has not, and saves
+// them so it happens once.
+func (s *Store) embedStale(ctx context.Context, file *storeFile) error {
+	var stale []int
+	var texts []string
+	for i, memory := range file.Memories {
+		if memory.Embedder != s.embedder.Name() || len(memory.Vector) == 0 {
+			stale = append(stale, i)
+			texts = append(texts, memory.Text)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	vectors, err := s.embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed memories: %w", err)
+	}
+	for j, i := range stale {
+		file.Memories[i].Embedder, file.Memories[i].Vector = s.embedder.Name(), vectors[j]
+	}
+	return s.save(file)
+}
+
+func (s *Store) load() (*storeFile, error) {
+	file := &storeFile{}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory store %s: %w", s.path, err)
+	}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse memory store %s: %w", s.path, err)
+	}
+	return file, nil
+}
+
+func (s *Store) save(file *storeFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode memory store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create memory store directory: %w", err)
+	}
+	// Write to a temp file and rename so a crash never leaves a truncated store
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write memory store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write memory store: %w", err)
+	}
+	return nil
+}
+
+// normalizeScope makes workspace scopes absolute so the same directory
+// always matches.
+func normalizeScope(scope string) (string, error) {
+	if scope == "" || scope == GlobalScope {
+		return GlobalScope, nil
+	}
+	abs, err := filepath.Abs(scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve workspace path: %w", err)
+	}
+	return filepath.Clean(abs), nil
+}
+
+// applies reports whether memory is recalled in workspace: global
+// memories everywhere, workspace ones in that directory and below it.
+func applies(memory Memory, workspace string) bool {
+	if memory.Scope == GlobalScope {
+		return true
+	}
+	rel, err := filepath.Rel(memory.Scope, workspace)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func newID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// cosine returns the cosine similarity of a and b, or 0 when they differ
+// in length or one is zero.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// Open returns the store in ~/.genie/memories.json with the embedder cfg
+// asks for.
+func Open(cfg config.Manager) (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	embedder, err := NewEmbedder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(path, embedder), nil
+}
+
+// FormatRecall renders recalled memories for the model's context, or ""
+// without any.
+func FormatRecall(matches []Match) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Memories\nFacts the user asked you to remember in earlier sessions. Follow them where they apply:\n")
+	for _, match := range matches {
+		fmt.Fprintf(&b, "- %s\n", match.Text)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
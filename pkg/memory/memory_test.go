@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/httpclient"
+)
+
+// failingEmbedder is an embeddings API that cannot be reached.
+type failingEmbedder struct{ LocalEmbedder }
+
+func (failingEmbedder) Name() string { return "api:offline" }
+
+func (failingEmbedder) Embed(context.Context, []string) ([][]float32, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestStoreRecallsRelatedMemoriesOfTheWorkspace(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(filepath.Join(t.TempDir(), FileName), LocalEmbedder{})
+	workspace := t.TempDir()
+	other := t.TempDir()
+
+	_, err := store.Add(ctx, "Deployments go through the release branch, never main", workspace)
+	require.NoError(t, err)
+	_, err = store.Add(ctx, "Use pnpm instead of npm to install dependencies", GlobalScope)
+	require.NoError(t, err)
+	_, err = store.Add(ctx, "The staging database listens on port 5433", other)
+	require.NoError(t, err)
+
+	matches, err := store.Recall(ctx, "how should I deploy this?", filepath.Join(workspace, "sub"), 5)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "Deployments go through the release branch, never main", matches[0].Text)
+
+	matches, err = store.Recall(ctx, "install the dependencies with npm", workspace, 5)
+	require.NoError(t, err)
+	require.Len(t, matches, 1, "global memories apply everywhere")
+
+	matches, err = store.Recall(ctx, "what port does the staging database use?", workspace, 5)
+	require.NoError(t, err)
+	assert.Empty(t, matches, "another workspace's memories stay there")
+}
+
+func TestStoreListAndForget(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(filepath.Join(t.TempDir(), FileName), LocalEmbedder{})
+	workspace := t.TempDir()
+
+	first, err := store.Add(ctx, "My name is Ana", GlobalScope)
+	require.NoError(t, err)
+	second, err := store.Add(ctx, "Tests need docker running", workspace)
+	require.NoError(t, err)
+	_, err = store.Add(ctx, "   ", GlobalScope)
+	assert.Error(t, err)
+
+	listed, err := store.List(workspace)
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+	listed, err = store.List(t.TempDir())
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+
+	forgotten, err := store.Forget(second.ID[:4])
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, forgotten.ID)
+	_, err = store.Forget(second.ID)
+	assert.ErrorContains(t, err, "no memory")
+
+	listed, err = store.List("")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, first.ID, listed[0].ID)
+}
+
+func TestStoreKeepsFactsTheEmbedderCouldNotEmbed(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), FileName)
+
+	memory, err := NewStore(path, failingEmbedder{}).Add(ctx, "Releases are cut on Thursdays", GlobalScope)
+	require.NoError(t, err)
+	assert.Empty(t, memory.Vector)
+
+	// A working embedder catches up on the next recall
+	store := NewStore(path, LocalEmbedder{})
+	matches, err := store.Recall(ctx, "when are releases cut?", t.TempDir(), 5)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	listed, err := store.List("")
+	require.NoError(t, err)
+	assert.Equal(t, LocalEmbedder{}.Name(), listed[0].Embedder)
+}
+
+func TestAPIEmbedder(t *testing.T) {
+	var got struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		// Out of order, as the API allows
+		_, _ = w.Write([]byte(`{"data": [{"index": 1, "embedding": [0, 1]}, {"index": 0, "embedding": [1, 0]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := &APIEmbedder{BaseURL: server.URL + "/v1", Model: "nomic-embed-text", APIKey: "key", Client: server.Client()}
+	vectors, err := embedder.Embed(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+	assert.Equal(t, "nomic-embed-text", got.Model)
+	assert.Equal(t, []string{"a", "b"}, got.Input)
+	assert.Equal(t, "Bearer key", auth)
+	assert.Equal(t, "api:nomic-embed-text", embedder.Name())
+}
+
+func TestNewEmbedderFailsWithoutHTTPClient(t *testing.T) {
+	t.Setenv(EmbeddingModelConfigKey, "nomic-embed-text")
+	t.Setenv(httpclient.CACertConfigKey, filepath.Join(t.TempDir(), "missing.pem"))
+
+	_, err := NewEmbedder(config.NewConfigManager())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), httpclient.CACertConfigKey)
+}
+
+func TestFormatRecall(t *testing.T) {
+	assert.Empty(t, FormatRecall(nil))
+	assert.Equal(t, "## Memories\nFacts the user asked you to remember in earlier sessions. Follow them where they apply:\n- Use pnpm",
+		FormatRecall([]Match{{Memory: Memory{Text: "Use pnpm"}}}))
+}
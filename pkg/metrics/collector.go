@@ -0,0 +1,232 @@
+// Package metrics measures how long Genie's chat turns take: total
+// latency, time to first streamed token, time spent in tools versus the
+// model, and model retries. A Collector builds these figures from the
+// events Genie already publishes, for display with :stats or scraping
+// by Prometheus.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/events"
+)
+
+// DefaultHistory is how many finished turns a Collector keeps.
+const DefaultHistory = 100
+
+// Turn holds the measurements of one chat turn.
+type Turn struct {
+	RequestID        string
+	Started          time.Time
+	Total            time.Duration
+	TimeToFirstToken time.Duration // Zero when the turn was not streamed
+	ToolTime         time.Duration
+	ModelTime        time.Duration // Total minus ToolTime
+	ToolCalls        int
+	Retries          int
+	Failed           bool
+}
+
+// Summary aggregates the turns a Collector still holds.
+type Summary struct {
+	Turns               int
+	FailedTurns         int
+	ToolCalls           int
+	Retries             int
+	AvgLatency          time.Duration
+	P50Latency          time.Duration
+	P95Latency          time.Duration
+	AvgTimeToFirstToken time.Duration // Over streamed turns only
+	ToolTime            time.Duration
+	ModelTime           time.Duration
+}
+
+// Collector tracks turns from chat, tool and retry events. It is safe for
+// concurrent use.
+type Collector struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	history int
+	active  map[string]*activeTurn
+	turns   []Turn
+	totals  totals
+}
+
+type activeTurn struct {
+	turn       Turn
+	toolStarts map[string]time.Time
+}
+
+// NewCollector creates a collector keeping the last DefaultHistory turns.
+func NewCollector() *Collector {
+	return &Collector{
+		now:     time.Now,
+		history: DefaultHistory,
+		active:  make(map[string]*activeTurn),
+		totals:  newTotals(),
+	}
+}
+
+// Attach subscribes the collector to bus and returns a function that
+// unsubscribes it. The handlers only update counters, so they are safe to
+// run inline with PublishSync.
+func (c *Collector) Attach(bus events.Subscriber) func() {
+	unsubscribers := []func(){
+		events.SubscribeTo(bus, func(e events.ChatStartedEvent) { c.turnStarted(e.RequestID) }),
+		events.SubscribeTo(bus, func(e events.ChatChunkEvent) { c.chunkReceived(e) }),
+		events.SubscribeTo(bus, func(e events.ToolStartingEvent) { c.toolStarted(e.RequestID, e.ExecutionID) }),
+		events.SubscribeTo(bus, func(e events.ToolExecutedEvent) { c.toolFinished(e.RequestID, e.ExecutionID) }),
+		events.SubscribeTo(bus, func(e events.ModelRetryEvent) { c.retried(e.RequestID) }),
+		events.SubscribeTo(bus, func(e events.ChatResponseEvent) { c.turnFinished(e.RequestID, e.Error != nil) }),
+	}
+	return func() {
+		for _, unsubscribe := range unsubscribers {
+			unsubscribe()
+		}
+	}
+}
+
+func (c *Collector) turnStarted(requestID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.active[requestID]; ok {
+		return
+	}
+	c.active[requestID] = &activeTurn{
+		turn:       Turn{RequestID: requestID, Started: c.now()},
+		toolStarts: make(map[string]time.Time),
+	}
+}
+
+func (c *Collector) chunkReceived(e events.ChatChunkEvent) {
+	if e.Chunk == nil || (e.Chunk.Text == "" && e.Chunk.Thinking == "" && len(e.Chunk.ToolCalls) == 0) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if active := c.activeTurn(e.RequestID); active != nil && active.turn.TimeToFirstToken == 0 {
+		active.turn.TimeToFirstToken = c.now().Sub(active.turn.Started)
+	}
+}
+
+func (c *Collector) toolStarted(requestID, executionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if active := c.activeTurn(requestID); active != nil {
+		active.toolStarts[executionID] = c.now()
+	}
+}
+
+func (c *Collector) toolFinished(requestID, executionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.activeTurn(requestID)
+	if active == nil {
+		return
+	}
+	active.turn.ToolCalls++
+	if started, ok := active.toolStarts[executionID]; ok {
+		active.turn.ToolTime += c.now().Sub(started)
+		delete(active.toolStarts, executionID)
+	}
+}
+
+func (c *Collector) retried(requestID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if active := c.activeTurn(requestID); active != nil {
+		active.turn.Retries++
+	}
+}
+
+func (c *Collector) turnFinished(requestID string, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active, ok := c.active[requestID]
+	if !ok {
+		return
+	}
+	delete(c.active, requestID)
+
+	turn := active.turn
+	turn.Total = c.now().Sub(turn.Started)
+	turn.ModelTime = max(turn.Total-turn.ToolTime, 0)
+	turn.Failed = failed
+
+	c.turns = append(c.turns, turn)
+	if len(c.turns) > c.history {
+		c.turns = c.turns[len(c.turns)-c.history:]
+	}
+	c.totals.add(turn)
+}
+
+// activeTurn finds the running turn for requestID. Events that don't carry
+// a request ID are credited to the only running turn, if there is just one.
+// Callers must hold c.mu.
+func (c *Collector) activeTurn(requestID string) *activeTurn {
+	if requestID != "" {
+		return c.active[requestID]
+	}
+	if len(c.active) != 1 {
+		return nil
+	}
+	for _, active := range c.active {
+		return active
+	}
+	return nil
+}
+
+// Turns returns the finished turns still held, oldest first.
+func (c *Collector) Turns() []Turn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	turns := make([]Turn, len(c.turns))
+	copy(turns, c.turns)
+	return turns
+}
+
+// Summary aggregates the finished turns still held.
+func (c *Collector) Summary() Summary {
+	turns := c.Turns()
+	summary := Summary{Turns: len(turns)}
+	if len(turns) == 0 {
+		return summary
+	}
+
+	latencies := make([]time.Duration, 0, len(turns))
+	var total, firstTokenTotal time.Duration
+	streamed := 0
+	for _, turn := range turns {
+		latencies = append(latencies, turn.Total)
+		total += turn.Total
+		summary.ToolTime += turn.ToolTime
+		summary.ModelTime += turn.ModelTime
+		summary.ToolCalls += turn.ToolCalls
+		summary.Retries += turn.Retries
+		if turn.Failed {
+			summary.FailedTurns++
+		}
+		if turn.TimeToFirstToken > 0 {
+			firstTokenTotal += turn.TimeToFirstToken
+			streamed++
+		}
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	summary.AvgLatency = total / time.Duration(len(turns))
+	summary.P50Latency = percentile(latencies, 0.50)
+	summary.P95Latency = percentile(latencies, 0.95)
+	if streamed > 0 {
+		summary.AvgTimeToFirstToken = firstTokenTotal / time.Duration(streamed)
+	}
+	return summary
+}
+
+// percentile picks the nearest-rank value from sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	rank = min(max(rank, 0), len(sorted)-1)
+	return sorted[rank]
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances only when told to.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestCollector(t *testing.T) (*Collector, *fakeClock, events.EventBus) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	collector := NewCollector()
+	collector.now = func() time.Time { return clock.now }
+	bus := events.NewEventBus()
+	t.Cleanup(collector.Attach(bus))
+	return collector, clock, bus
+}
+
+func publish(bus events.EventBus, e events.Event) {
+	bus.PublishSync(e.Topic(), e)
+}
+
+func TestCollectorMeasuresTurn(t *testing.T) {
+	collector, clock, bus := newTestCollector(t)
+
+	publish(bus, events.ChatStartedEvent{RequestID: "r1"})
+	clock.advance(300 * time.Millisecond)
+	publish(bus, events.ChatChunkEvent{RequestID: "r1", Chunk: &ai.StreamChunk{}}) // empty chunks don't count
+	publish(bus, events.ModelRetryEvent{RequestID: "r1", Attempt: 1})
+	clock.advance(200 * time.Millisecond)
+	publish(bus, events.ChatChunkEvent{RequestID: "r1", Chunk: &ai.StreamChunk{Text: "Hi"}})
+	publish(bus, events.ToolStartingEvent{RequestID: "r1", ExecutionID: "e1"})
+	clock.advance(2 * time.Second)
+	publish(bus, events.ToolExecutedEvent{RequestID: "r1", ExecutionID: "e1"})
+	clock.advance(time.Second)
+	publish(bus, events.ChatResponseEvent{RequestID: "r1"})
+
+	turns := collector.Turns()
+	require.Len(t, turns, 1)
+	assert.Equal(t, Turn{
+		RequestID:        "r1",
+		Started:          turns[0].Started,
+		Total:            3500 * time.Millisecond,
+		TimeToFirstToken: 500 * time.Millisecond,
+		ToolTime:         2 * time.Second,
+		ModelTime:        1500 * time.Millisecond,
+		ToolCalls:        1,
+		Retries:          1,
+	}, turns[0])
+}
+
+func TestCollectorSummary(t *testing.T) {
+	collector, clock, bus := newTestCollector(t)
+
+	for i, latency := range []time.Duration{time.Second, 3 * time.Second, 2 * time.Second} {
+		id := string(rune('a' + i))
+		publish(bus, events.ChatStartedEvent{RequestID: id})
+		clock.advance(latency)
+		var err error
+		if i == 1 {
+			err = errors.New("boom")
+		}
+		publish(bus, events.ChatResponseEvent{RequestID: id, Error: err})
+	}
+
+	summary := collector.Summary()
+	assert.Equal(t, 3, summary.Turns)
+	assert.Equal(t, 1, summary.FailedTurns)
+	assert.Equal(t, 2*time.Second, summary.AvgLatency)
+	assert.Equal(t, 2*time.Second, summary.P50Latency)
+	assert.Equal(t, 3*time.Second, summary.P95Latency)
+	assert.Zero(t, summary.AvgTimeToFirstToken, "no turn was streamed")
+}
+
+func TestCollectorCreditsUnattributedToolsToTheOnlyTurn(t *testing.T) {
+	collector, clock, bus := newTestCollector(t)
+
+	publish(bus, events.ChatStartedEvent{RequestID: "r1"})
+	publish(bus, events.ToolStartingEvent{ExecutionID: "e1"})
+	clock.advance(time.Second)
+	publish(bus, events.ToolExecutedEvent{ExecutionID: "e1"})
+	publish(bus, events.ChatResponseEvent{RequestID: "r1"})
+
+	require.Len(t, collector.Turns(), 1)
+	assert.Equal(t, time.Second, collector.Turns()[0].ToolTime)
+}
+
+func TestWritePrometheus(t *testing.T) {
+	collector, clock, bus := newTestCollector(t)
+
+	publish(bus, events.ChatStartedEvent{RequestID: "r1"})
+	clock.advance(1500 * time.Millisecond)
+	publish(bus, events.ChatResponseEvent{RequestID: "r1"})
+	publish(bus, events.ChatStartedEvent{RequestID: "r2"})
+
+	var out strings.Builder
+	require.NoError(t, collector.WritePrometheus(&out))
+	text := out.String()
+
+	assert.Contains(t, text, "# TYPE genie_turns_total counter\ngenie_turns_total 1\n")
+	assert.Contains(t, text, "genie_turns_active 1\n")
+	assert.Contains(t, text, "genie_turn_duration_seconds_bucket{le=\"1\"} 0\n")
+	assert.Contains(t, text, "genie_turn_duration_seconds_bucket{le=\"2\"} 1\n")
+	assert.Contains(t, text, "genie_turn_duration_seconds_sum 1.5\n")
+	assert.Contains(t, text, "genie_time_to_first_token_seconds_count 0\n")
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Histogram buckets, in seconds.
+var (
+	latencyBuckets    = []float64{0.5, 1, 2, 5, 10, 30, 60, 120, 300, 600}
+	firstTokenBuckets = []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 30}
+)
+
+// totals are cumulative over the collector's lifetime, unlike the turn
+// history, as Prometheus counters must never go down.
+type totals struct {
+	turns      int
+	failed     int
+	toolCalls  int
+	retries    int
+	toolTime   time.Duration
+	modelTime  time.Duration
+	latency    histogram
+	firstToken histogram
+}
+
+func newTotals() totals {
+	return totals{
+		latency:    newHistogram(latencyBuckets),
+		firstToken: newHistogram(firstTokenBuckets),
+	}
+}
+
+func (t *totals) add(turn Turn) {
+	t.turns++
+	if turn.Failed {
+		t.failed++
+	}
+	t.toolCalls += turn.ToolCalls
+	t.retries += turn.Retries
+	t.toolTime += turn.ToolTime
+	t.modelTime += turn.ModelTime
+	t.latency.observe(turn.Total.Seconds())
+	if turn.TimeToFirstToken > 0 {
+		t.firstToken.observe(turn.TimeToFirstToken.Seconds())
+	}
+}
+
+type histogram struct {
+	bounds []float64
+	counts []int // Cumulative count per bound
+	count  int
+	sum    float64
+}
+
+func newHistogram(bounds []float64) histogram {
+	return histogram{bounds: bounds, counts: make([]int, len(bounds))}
+}
+
+func (h *histogram) observe(value float64) {
+	h.count++
+	h.sum += value
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+}
+
+// WritePrometheus writes the collector's cumulative metrics in the
+// Prometheus text exposition format.
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	t := c.totals
+	t.latency.counts = append([]int(nil), t.latency.counts...)
+	t.firstToken.counts = append([]int(nil), t.firstToken.counts...)
+	active := len(c.active)
+	c.mu.Unlock()
+
+	p := &promWriter{w: w}
+	p.metric("genie_turns_total", "counter", "Chat turns finished.", strconv.Itoa(t.turns))
+	p.metric("genie_turn_failures_total", "counter", "Chat turns that ended with an error.", strconv.Itoa(t.failed))
+	p.metric("genie_turns_active", "gauge", "Chat turns in progress.", strconv.Itoa(active))
+	p.metric("genie_tool_calls_total", "counter", "Tool calls made by finished turns.", strconv.Itoa(t.toolCalls))
+	p.metric("genie_model_retries_total", "counter", "Failed model requests that were retried.", strconv.Itoa(t.retries))
+	p.metric("genie_tool_time_seconds_total", "counter", "Time finished turns spent running tools.", formatFloat(t.toolTime.Seconds()))
+	p.metric("genie_model_time_seconds_total", "counter", "Time finished turns spent outside tools, waiting on the model.", formatFloat(t.modelTime.Seconds()))
+	p.histogram("genie_turn_duration_seconds", "Total latency of finished chat turns.", t.latency)
+	p.histogram("genie_time_to_first_token_seconds", "Time from the start of a streamed turn to its first token.", t.firstToken)
+	return p.err
+}
+
+// Handler serves WritePrometheus over HTTP.
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = c.WritePrometheus(w)
+	})
+}
+
+// promWriter writes exposition lines, keeping the first error.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func (p *promWriter) metric(name, kind, help, value string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, kind, name, value)
+}
+
+func (p *promWriter) histogram(name, help string, h histogram) {
+	p.printf("# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for i, bound := range h.bounds {
+		p.printf("%s_bucket{le=\"%s\"} %d\n", name, formatFloat(bound), h.counts[i])
+	}
+	p.printf("%s_bucket{le=\"+Inf\"} %d\n%s_sum %s\n%s_count %d\n", name, h.count, name, formatFloat(h.sum), name, h.count)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,94 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// offlineInstruction tells the model which tools are gone and why
+const offlineInstruction = `## Offline mode
+Genie is running without network access. Tools that need the network are unavailable%s, and commands that download packages or call remote services will fail. Work with what is on this machine.`
+
+// NewMiddleware returns the middleware of offline mode. It sends requests
+// for remote providers to the local backend, failing with
+// ErrNoLocalBackend when none is running, and withholds the tools that
+// need the network.
+func NewMiddleware(cfg config.Manager) middleware.Middleware {
+	m := &offlineMiddleware{cfg: cfg}
+	return middleware.Funcs{BeforeFunc: m.before}
+}
+
+type offlineMiddleware struct {
+	cfg config.Manager
+	// local is the backend found by the last successful lookup. A failed
+	// lookup is retried on the next request, so starting Ollama mid-session
+	// is enough.
+	mu    sync.Mutex
+	local *LocalBackend
+}
+
+func (m *offlineMiddleware) before(ctx context.Context, req *middleware.Request) error {
+	if Remote(m.cfg, req.Prompt.LLMProvider) {
+		local, err := m.localBackend(ctx)
+		if err != nil {
+			return ai.NonRetryable(err)
+		}
+		req.Prompt.LLMProvider = local.Provider
+		req.Prompt.ModelName = local.Model
+	}
+	withholdNetworkTools(&req.Prompt)
+	return nil
+}
+
+func (m *offlineMiddleware) localBackend(ctx context.Context) (LocalBackend, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.local != nil {
+		return *m.local, nil
+	}
+	local, err := FindLocalBackend(ctx, m.cfg)
+	if err != nil {
+		return LocalBackend{}, err
+	}
+	m.local = &local
+	return local, nil
+}
+
+// withholdNetworkTools drops the tools that need the network from prompt
+// and tells the model why. Handlers are copied so the caller's map is
+// left alone.
+func withholdNetworkTools(prompt *ai.Prompt) {
+	var withheld []string
+	functions := make([]*ai.FunctionDeclaration, 0, len(prompt.Functions))
+	handlers := make(map[string]ai.HandlerFunc, len(prompt.Handlers))
+	for _, fn := range prompt.Functions {
+		if tools.NeedsNetwork(fn.Name) {
+			withheld = append(withheld, fn.Name)
+			continue
+		}
+		functions = append(functions, fn)
+		if handler, ok := prompt.Handlers[fn.Name]; ok {
+			handlers[fn.Name] = handler
+		}
+	}
+	prompt.Functions = functions
+	prompt.Handlers = handlers
+
+	list := ""
+	if len(withheld) > 0 {
+		list = " (" + strings.Join(withheld, ", ") + ")"
+	}
+	note := fmt.Sprintf(offlineInstruction, list)
+	if prompt.SystemPromptUserContext == "" {
+		prompt.SystemPromptUserContext = note
+	} else {
+		prompt.SystemPromptUserContext = strings.TrimRight(prompt.SystemPromptUserContext, "\n") + "\n\n" + note
+	}
+}
@@ -0,0 +1,75 @@
+package offline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func offlinePrompt(provider string) *middleware.Request {
+	handler := func(context.Context, map[string]any) (map[string]any, error) { return nil, nil }
+	return &middleware.Request{Prompt: ai.Prompt{
+		LLMProvider:             provider,
+		ModelName:               "gpt-5-mini",
+		SystemPromptUserContext: "## Project\nA CLI.",
+		Functions: []*ai.FunctionDeclaration{
+			{Name: "readFile"},
+			{Name: "githubIssue"},
+			{Name: "runBashCommand"},
+		},
+		Handlers: map[string]ai.HandlerFunc{
+			"readFile":       handler,
+			"githubIssue":    handler,
+			"runBashCommand": handler,
+		},
+	}}
+}
+
+func TestMiddleware_RoutesRemoteRequestsToLocalBackend(t *testing.T) {
+	clearOfflineEnv(t)
+	fakeServers(t, running("ollama", "qwen2.5-coder"))
+	req := offlinePrompt("openai")
+	handlers := req.Prompt.Handlers
+
+	require.NoError(t, NewMiddleware(config.NewConfigManager()).Before(context.Background(), req))
+
+	assert.Equal(t, "ollama", req.Prompt.LLMProvider)
+	assert.Equal(t, "qwen2.5-coder", req.Prompt.ModelName)
+
+	var names []string
+	for _, fn := range req.Prompt.Functions {
+		names = append(names, fn.Name)
+	}
+	assert.Equal(t, []string{"readFile", "runBashCommand"}, names)
+	assert.NotContains(t, req.Prompt.Handlers, "githubIssue")
+	assert.Contains(t, handlers, "githubIssue", "the caller's handlers are left alone")
+	assert.Contains(t, req.Prompt.SystemPromptUserContext, "## Project\nA CLI.\n\n## Offline mode")
+	assert.Contains(t, req.Prompt.SystemPromptUserContext, "(githubIssue)")
+}
+
+func TestMiddleware_KeepsLocalProviders(t *testing.T) {
+	clearOfflineEnv(t)
+	fakeServers(t)
+	req := offlinePrompt("lmstudio")
+
+	require.NoError(t, NewMiddleware(config.NewConfigManager()).Before(context.Background(), req))
+
+	assert.Equal(t, "lmstudio", req.Prompt.LLMProvider)
+	assert.Equal(t, "gpt-5-mini", req.Prompt.ModelName)
+	assert.Len(t, req.Prompt.Functions, 2)
+}
+
+func TestMiddleware_FailsWithoutLocalBackend(t *testing.T) {
+	clearOfflineEnv(t)
+	fakeServers(t, stopped("ollama"), stopped("lmstudio"))
+
+	err := NewMiddleware(config.NewConfigManager()).Before(context.Background(), offlinePrompt("anthropic"))
+
+	require.ErrorIs(t, err, ErrNoLocalBackend)
+	assert.False(t, ai.IsRetryable(err))
+}
@@ -0,0 +1,217 @@
+// Package offline decides whether Genie runs without network access and
+// how it degrades then: requests for remote LLM providers go to a local
+// Ollama or LM Studio server, or fail with a clear error when neither is
+// running, and tools that need the network are withheld.
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/httpclient"
+	"github.com/kcaldas/genie/pkg/llm/lmstudio"
+	"github.com/kcaldas/genie/pkg/llm/ollama"
+)
+
+const (
+	// ConfigKey turns offline mode on ("true"), off ("false"), or lets
+	// Genie detect it at startup ("auto", the default).
+	ConfigKey = "GENIE_OFFLINE"
+	// ProviderConfigKey picks the local backend used offline, ollama or
+	// lmstudio. By default the first one running is used.
+	ProviderConfigKey = "GENIE_OFFLINE_PROVIDER"
+	// ModelConfigKey picks the local model used offline. By default the
+	// first model the local server lists is used.
+	ModelConfigKey = "GENIE_OFFLINE_MODEL"
+)
+
+// probeTimeout bounds the network and local server checks
+const probeTimeout = 1500 * time.Millisecond
+
+// ErrNoLocalBackend is returned offline for requests to a remote provider
+// when no local backend is running.
+var ErrNoLocalBackend = errors.New("offline: no local LLM backend is running; start Ollama or LM Studio, or set GENIE_OFFLINE=false once you are back online")
+
+// LocalBackend is the local server requests go to while offline.
+type LocalBackend struct {
+	Provider string
+	Model    string
+}
+
+// localServer lists the models of a local backend, failing when it is
+// not running
+type localServer struct {
+	provider string
+	list     func(ctx context.Context, cfg config.Manager) ([]string, error)
+}
+
+// Replaced in tests
+var (
+	localServers = []localServer{
+		{provider: "ollama", list: ollama.ListModels},
+		{provider: "lmstudio", list: lmstudio.ListModels},
+	}
+	dial = func(ctx context.Context, address string) error {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+)
+
+// Detect reports whether Genie should run offline. GENIE_OFFLINE=true or
+// false decides; otherwise, when the default provider is remote, Genie is
+// offline if its API (or the proxy in front of it) cannot be reached.
+func Detect(ctx context.Context, cfg config.Manager) bool {
+	switch strings.ToLower(cfg.GetStringWithDefault(ConfigKey, "auto")) {
+	case "true", "1", "yes", "on":
+		return true
+	case "false", "0", "no", "off":
+		return false
+	}
+	if !Remote(cfg, "") {
+		return false
+	}
+	endpoint := endpointURL(cfg, "")
+	if proxy, err := httpclient.ProxyConfig(cfg).ProxyFunc()(endpoint); err == nil && proxy != nil {
+		endpoint = proxy
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	return dial(ctx, hostPort(endpoint)) != nil
+}
+
+// Enabled reports whether offline mode is on, without probing the
+// network: the CLI records what Detect found in GENIE_OFFLINE.
+func Enabled(cfg config.Manager) bool {
+	return cfg.GetBoolWithDefault(ConfigKey, false)
+}
+
+// canonical maps provider aliases to the multiplexer's provider names
+func canonical(cfg config.Manager, provider string) string {
+	if provider == "" {
+		provider = cfg.GetStringWithDefault("GENIE_LLM_PROVIDER", "genai")
+	}
+	switch provider = strings.ToLower(provider); provider {
+	case "gemini", "google", "vertex":
+		return "genai"
+	case "openai-chat":
+		return "openai"
+	case "claude", "anthropic-claude":
+		return "anthropic"
+	case "lm-studio":
+		return "lmstudio"
+	}
+	return provider
+}
+
+// Remote reports whether provider (empty for the default one) needs the
+// network. OpenAI and Anthropic pointed at a loopback address through
+// their base URL count as local.
+func Remote(cfg config.Manager, provider string) bool {
+	switch canonical(cfg, provider) {
+	case "ollama", "lmstudio", "mock":
+		return false
+	case "genai":
+		return !strings.EqualFold(cfg.GetStringWithDefault("GENAI_BACKEND", ""), "mock")
+	case "openai", "anthropic":
+		return !isLoopback(endpointURL(cfg, provider).Hostname())
+	}
+	return true
+}
+
+// endpointURL is where provider's requests go
+func endpointURL(cfg config.Manager, provider string) *url.URL {
+	raw := "https://generativelanguage.googleapis.com"
+	switch canonical(cfg, provider) {
+	case "genai":
+		if strings.EqualFold(cfg.GetStringWithDefault("GENAI_BACKEND", ""), "vertex") {
+			raw = "https://aiplatform.googleapis.com"
+		}
+	case "openai":
+		raw = cfg.GetStringWithDefault("OPENAI_BASE_URL", "https://api.openai.com")
+	case "anthropic":
+		raw = cfg.GetStringWithDefault("ANTHROPIC_BASE_URL", "https://api.anthropic.com")
+	}
+	endpoint, err := url.Parse(raw)
+	if err != nil || endpoint.Host == "" {
+		return &url.URL{Scheme: "https", Host: raw}
+	}
+	return endpoint
+}
+
+func hostPort(endpoint *url.URL) string {
+	if endpoint.Port() != "" {
+		return endpoint.Host
+	}
+	port := "443"
+	if endpoint.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(endpoint.Hostname(), port)
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// FindLocalBackend returns the local backend to use offline:
+// GENIE_OFFLINE_PROVIDER when set, else the first of Ollama and LM Studio
+// that is running, with GENIE_OFFLINE_MODEL or the first model it lists.
+func FindLocalBackend(ctx context.Context, cfg config.Manager) (LocalBackend, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	wanted := cfg.GetStringWithDefault(ProviderConfigKey, "")
+	if wanted != "" {
+		wanted = canonical(cfg, wanted)
+	}
+	model := cfg.GetStringWithDefault(ModelConfigKey, "")
+	var failures []string
+	for _, server := range localServers {
+		if wanted != "" && server.provider != wanted {
+			continue
+		}
+		models, err := server.list(ctx, cfg)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		if model != "" {
+			return LocalBackend{Provider: server.provider, Model: model}, nil
+		}
+		if len(models) == 0 {
+			failures = append(failures, fmt.Sprintf("%s has no models", server.provider))
+			continue
+		}
+		return LocalBackend{Provider: server.provider, Model: models[0]}, nil
+	}
+	if wanted != "" && len(failures) == 0 {
+		return LocalBackend{}, fmt.Errorf("%s %q is not a local backend: use ollama or lmstudio", ProviderConfigKey, wanted)
+	}
+	return LocalBackend{}, fmt.Errorf("%w (%s)", ErrNoLocalBackend, strings.Join(failures, "; "))
+}
+
+// Describe tells the user how Genie behaves offline.
+func Describe(ctx context.Context, cfg config.Manager) string {
+	if !Remote(cfg, "") {
+		return "Offline mode: tools that need the network are unavailable."
+	}
+	local, err := FindLocalBackend(ctx, cfg)
+	if err != nil {
+		return "Offline mode: no local LLM backend is running, so requests will fail until Ollama or LM Studio starts. Tools that need the network are unavailable."
+	}
+	return fmt.Sprintf("Offline mode: answering with %s/%s. Tools that need the network are unavailable.", local.Provider, local.Model)
+}
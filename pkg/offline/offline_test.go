@@ -0,0 +1,151 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearOfflineEnv unsets the settings offline mode reads
+func clearOfflineEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{ConfigKey, ProviderConfigKey, ModelConfigKey, "GENIE_LLM_PROVIDER", "GENAI_BACKEND",
+		"OPENAI_BASE_URL", "ANTHROPIC_BASE_URL", "HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"} {
+		t.Setenv(key, "")
+	}
+}
+
+// fakeServers replaces the local servers for the test
+func fakeServers(t *testing.T, servers ...localServer) {
+	t.Helper()
+	saved := localServers
+	localServers = servers
+	t.Cleanup(func() { localServers = saved })
+}
+
+func running(provider string, models ...string) localServer {
+	return localServer{provider: provider, list: func(context.Context, config.Manager) ([]string, error) {
+		return models, nil
+	}}
+}
+
+func stopped(provider string) localServer {
+	return localServer{provider: provider, list: func(context.Context, config.Manager) ([]string, error) {
+		return nil, errors.New(provider + " is not running")
+	}}
+}
+
+func TestDetect(t *testing.T) {
+	var dialed []string
+	saved := dial
+	t.Cleanup(func() { dial = saved })
+	reachable := true
+	dial = func(_ context.Context, address string) error {
+		dialed = append(dialed, address)
+		if reachable {
+			return nil
+		}
+		return errors.New("network is unreachable")
+	}
+
+	t.Run("explicit setting wins", func(t *testing.T) {
+		clearOfflineEnv(t)
+		dialed = nil
+		t.Setenv(ConfigKey, "true")
+		assert.True(t, Detect(context.Background(), config.NewConfigManager()))
+		t.Setenv(ConfigKey, "false")
+		reachable = false
+		assert.False(t, Detect(context.Background(), config.NewConfigManager()))
+		assert.Empty(t, dialed)
+		reachable = true
+	})
+
+	t.Run("auto probes the default provider", func(t *testing.T) {
+		clearOfflineEnv(t)
+		dialed = nil
+		t.Setenv("GENIE_LLM_PROVIDER", "openai")
+		assert.False(t, Detect(context.Background(), config.NewConfigManager()))
+		reachable = false
+		assert.True(t, Detect(context.Background(), config.NewConfigManager()))
+		assert.Equal(t, []string{"api.openai.com:443", "api.openai.com:443"}, dialed)
+		reachable = true
+	})
+
+	t.Run("auto probes the proxy", func(t *testing.T) {
+		clearOfflineEnv(t)
+		dialed = nil
+		t.Setenv("GENIE_LLM_PROVIDER", "anthropic")
+		t.Setenv("HTTPS_PROXY", "http://proxy.internal:3128")
+		Detect(context.Background(), config.NewConfigManager())
+		assert.Equal(t, []string{"proxy.internal:3128"}, dialed)
+	})
+
+	t.Run("local providers never probe", func(t *testing.T) {
+		clearOfflineEnv(t)
+		dialed = nil
+		t.Setenv("GENIE_LLM_PROVIDER", "ollama")
+		reachable = false
+		assert.False(t, Detect(context.Background(), config.NewConfigManager()))
+		assert.Empty(t, dialed)
+		reachable = true
+	})
+}
+
+func TestRemote(t *testing.T) {
+	clearOfflineEnv(t)
+	cfg := config.NewConfigManager()
+	assert.True(t, Remote(cfg, ""), "genai is the default provider")
+	assert.True(t, Remote(cfg, "claude"))
+	assert.False(t, Remote(cfg, "ollama"))
+	assert.False(t, Remote(cfg, "lm-studio"))
+	assert.False(t, Remote(cfg, "mock"))
+
+	t.Setenv("OPENAI_BASE_URL", "http://127.0.0.1:8080/v1")
+	t.Setenv("GENAI_BACKEND", "mock")
+	cfg = config.NewConfigManager()
+	assert.False(t, Remote(cfg, "openai"))
+	assert.False(t, Remote(cfg, "gemini"))
+	assert.True(t, Remote(cfg, "anthropic"))
+}
+
+func TestFindLocalBackend(t *testing.T) {
+	t.Run("first running server and its first model", func(t *testing.T) {
+		clearOfflineEnv(t)
+		fakeServers(t, stopped("ollama"), running("lmstudio", "qwen2.5-coder", "llama3"))
+		local, err := FindLocalBackend(context.Background(), config.NewConfigManager())
+		require.NoError(t, err)
+		assert.Equal(t, LocalBackend{Provider: "lmstudio", Model: "qwen2.5-coder"}, local)
+	})
+
+	t.Run("configured provider and model", func(t *testing.T) {
+		clearOfflineEnv(t)
+		t.Setenv(ProviderConfigKey, "lm-studio")
+		t.Setenv(ModelConfigKey, "llama3")
+		fakeServers(t, running("ollama", "gemma3"), running("lmstudio", "qwen2.5-coder"))
+		local, err := FindLocalBackend(context.Background(), config.NewConfigManager())
+		require.NoError(t, err)
+		assert.Equal(t, LocalBackend{Provider: "lmstudio", Model: "llama3"}, local)
+	})
+
+	t.Run("nothing running", func(t *testing.T) {
+		clearOfflineEnv(t)
+		fakeServers(t, stopped("ollama"), running("lmstudio"))
+		_, err := FindLocalBackend(context.Background(), config.NewConfigManager())
+		require.ErrorIs(t, err, ErrNoLocalBackend)
+		assert.Contains(t, err.Error(), "ollama is not running")
+		assert.Contains(t, err.Error(), "lmstudio has no models")
+	})
+
+	t.Run("remote provider configured", func(t *testing.T) {
+		clearOfflineEnv(t)
+		t.Setenv(ProviderConfigKey, "openai")
+		fakeServers(t, running("ollama", "gemma3"))
+		_, err := FindLocalBackend(context.Background(), config.NewConfigManager())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a local backend")
+	})
+}
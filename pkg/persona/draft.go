@@ -0,0 +1,268 @@
+package persona
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/prompts"
+	"github.com/kcaldas/genie/pkg/tools"
+	"gopkg.in/yaml.v2"
+)
+
+// validPersonaID is a directory name that is safe on every platform and
+// easy to type after --persona
+var validPersonaID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Draft is a persona being created, before it is written as prompt.yaml
+type Draft struct {
+	ID      string
+	Name    string // Defaults to the ID in title case
+	Purpose string // What the persona is for, in the user's words
+	Tools   []string
+	// LLMProvider and ModelName are left out of prompt.yaml when empty, so
+	// the configured defaults apply
+	LLMProvider string
+	ModelName   string
+}
+
+// ValidateID checks that id can name a persona directory
+func ValidateID(id string) error {
+	if !validPersonaID.MatchString(id) {
+		return fmt.Errorf("persona ID %q must be lowercase letters, digits, _ and -, starting with a letter or digit", id)
+	}
+	return nil
+}
+
+// toolSuggestions maps words in a persona's purpose to the tools that
+// purpose usually needs. Words of five letters or more also match longer
+// words they start ("analy" matches "analyze"); shorter ones must match
+// exactly.
+var toolSuggestions = []struct {
+	words []string
+	tools []string
+}{
+	{[]string{"code", "coding", "engineer", "develop", "implement", "refactor", "bug", "bugs", "debug", "fix", "fixes", "fixing", "program"},
+		[]string{"listFiles", "findFiles", "readFile", "searchInFiles", "editFile", "writeFile", "bash"}},
+	{[]string{"review", "audit", "inspect", "analy", "research", "explain", "understand", "explore"},
+		[]string{"listFiles", "findFiles", "readFile", "searchInFiles"}},
+	{[]string{"test", "tests", "testing", "build", "run", "runs", "script", "deploy", "ops", "devops", "shell", "command"},
+		[]string{"bash", "process"}},
+	{[]string{"git", "commit", "history", "change", "diff", "diffs", "release"},
+		[]string{"gitStatus", "gitLog", "gitDiff", "gitShow"}},
+	{[]string{"github", "pull", "pr", "prs", "issue", "ci"},
+		[]string{"@github"}},
+	{[]string{"doc", "docs", "write", "writing", "blog", "readme", "note", "notes", "spec", "specs"},
+		[]string{"listFiles", "readFile", "writeFile", "editFile"}},
+	{[]string{"pdf", "document", "paper"},
+		[]string{"viewDocument"}},
+	{[]string{"image", "screenshot", "design", "ui", "diagram"},
+		[]string{"viewImage"}},
+	{[]string{"plan", "plans", "planning", "task", "tasks", "project", "manage", "product"},
+		[]string{"TodoWrite", "TodoRead"}},
+}
+
+// mentions reports whether any of the purpose's words matches word
+func mentions(purposeWords []string, word string) bool {
+	return slices.ContainsFunc(purposeWords, func(w string) bool {
+		return w == word || (len(word) >= 5 && strings.HasPrefix(w, word))
+	})
+}
+
+// SuggestTools proposes required_tools for a purpose: @essentials, plus
+// the tools its wording calls for, keeping only those the registry has.
+// A purpose that matches nothing gets read-only file access.
+func SuggestTools(purpose string, registry tools.Registry) []string {
+	words := strings.FieldsFunc(strings.ToLower(purpose), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	suggested := []string{"@essentials"}
+	matched := false
+	for _, rule := range toolSuggestions {
+		if !slices.ContainsFunc(rule.words, func(word string) bool { return mentions(words, word) }) {
+			continue
+		}
+		matched = true
+		for _, name := range rule.tools {
+			if !slices.Contains(suggested, name) {
+				suggested = append(suggested, name)
+			}
+		}
+	}
+	if !matched {
+		suggested = append(suggested, "listFiles", "findFiles", "readFile", "searchInFiles")
+	}
+
+	catalog := ToolCatalog(registry)
+	return slices.DeleteFunc(suggested, func(name string) bool { return !slices.Contains(catalog, name) })
+}
+
+// ToolCatalog lists what required_tools can name: every registered tool
+// and every tool set as @name, sorted with the sets first.
+func ToolCatalog(registry tools.Registry) []string {
+	var sets, names []string
+	for _, name := range registry.GetToolSetNames() {
+		sets = append(sets, "@"+name)
+	}
+	names = append(names, registry.Names()...)
+	sort.Strings(sets)
+	sort.Strings(names)
+	return append(sets, names...)
+}
+
+// CompleteTool returns the catalog entries that start with prefix,
+// ignoring case. An exact match is the only completion.
+func CompleteTool(prefix string, catalog []string) []string {
+	if slices.Contains(catalog, prefix) {
+		return []string{prefix}
+	}
+	var matches []string
+	for _, entry := range catalog {
+		if strings.HasPrefix(strings.ToLower(entry), strings.ToLower(prefix)) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// promptFile is prompt.yaml in the order people read it
+type promptFile struct {
+	Name          string   `yaml:"name"`
+	LLMProvider   string   `yaml:"llm_provider,omitempty"`
+	ModelName     string   `yaml:"model_name,omitempty"`
+	RequiredTools []string `yaml:"required_tools,omitempty"`
+	Text          string   `yaml:"text"`
+	Instruction   string   `yaml:"instruction"`
+}
+
+// draftText is the conversation template every built-in persona uses
+const draftText = `{{if .chat}}
+  ## Conversation History
+  {{.chat}}
+{{end}}
+  ## User Message to be handled
+User: {{.message}}
+`
+
+// YAML renders the draft as a prompt.yaml to start editing from
+func (d Draft) YAML() ([]byte, error) {
+	if err := ValidateID(d.ID); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(d.Name)
+	if name == "" {
+		name = TitleFromID(d.ID)
+	}
+	data, err := yaml.Marshal(promptFile{
+		Name:          name,
+		LLMProvider:   d.LLMProvider,
+		ModelName:     d.ModelName,
+		RequiredTools: d.Tools,
+		Text:          draftText,
+		Instruction:   draftInstruction(name, d.Purpose, d.Tools),
+	})
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("# %s persona, created by genie persona new. Edit the instruction to\n# refine how it works; see docs/personas.md for every field.\n", name)
+	return append([]byte(header), data...), nil
+}
+
+// draftInstruction writes a system instruction from the purpose, with a
+// line for each kind of tool the persona has
+func draftInstruction(name, purpose string, toolNames []string) string {
+	purpose = strings.TrimSpace(purpose)
+	if purpose == "" {
+		purpose = "a helpful assistant"
+	}
+	has := func(names ...string) bool {
+		return slices.ContainsFunc(names, func(name string) bool { return slices.Contains(toolNames, name) })
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are %s. Your purpose: %s.\n\n", name, strings.TrimSuffix(purpose, "."))
+	b.WriteString("## How you work\n\n")
+	b.WriteString("- Stay within your purpose; say so when a request falls outside it.\n")
+	b.WriteString("- Be concise and specific. Show code, commands or file references when they help.\n")
+	if has("readFile", "searchInFiles", "findFiles", "listFiles") {
+		b.WriteString("- Read the relevant files before answering or changing anything; never guess at their contents.\n")
+	}
+	if has("writeFile", "editFile") {
+		b.WriteString("- Follow the conventions of the code you change and keep edits focused on the request.\n")
+	}
+	if has("bash") {
+		b.WriteString("- Explain commands that change or delete anything before running them.\n")
+	}
+	if has("@github") {
+		b.WriteString("- Ask before creating issues or posting reviews on GitHub.\n")
+	}
+	if has("@essentials", "TodoWrite") {
+		b.WriteString("- For work with several steps, plan it with TodoWrite and keep the list current.\n")
+	}
+	return b.String()
+}
+
+// TitleFromID is the default name for a persona: code_reviewer becomes
+// Code Reviewer
+func TitleFromID(id string) string {
+	words := strings.FieldsFunc(id, func(r rune) bool { return r == '_' || r == '-' })
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// ValidatePrompt checks a prompt.yaml the way Genie loads it: it must
+// parse, have an instruction, have templates that render, and name only
+// tools the registry has. It returns every problem found.
+func ValidatePrompt(data []byte, registry tools.Registry) error {
+	loader := prompts.NewPromptLoader(&events.NoOpPublisher{}, registry)
+	prompt, err := loader.LoadPromptFromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	var problems []error
+	if strings.TrimSpace(prompt.Name) == "" {
+		problems = append(problems, errors.New("name is empty"))
+	}
+	if strings.TrimSpace(prompt.Instruction) == "" {
+		problems = append(problems, errors.New("instruction is empty"))
+	}
+	sample := map[string]string{"chat": "User: hi", "message": "hello"}
+	if _, err := ai.RenderTemplateString(prompt.Text, sample); err != nil {
+		problems = append(problems, fmt.Errorf("text does not render: %w", err))
+	}
+	if _, err := ai.RenderTemplateString(prompt.Instruction, sample); err != nil {
+		problems = append(problems, fmt.Errorf("instruction does not render: %w", err))
+	}
+	for _, name := range prompt.MissingTools {
+		problems = append(problems, fmt.Errorf("required tool %s is not available", name))
+	}
+	return errors.Join(problems...)
+}
+
+// WriteDraft writes data as dir/<id>/prompt.yaml and returns its path. An
+// existing persona is only replaced when overwrite is set.
+func WriteDraft(dir, id string, data []byte, overwrite bool) (string, error) {
+	if err := ValidateID(id); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, id, "prompt.yaml")
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return "", fmt.Errorf("persona %s already exists at %s", id, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,91 @@
+package persona
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+func newDraftRegistry() tools.Registry {
+	return tools.NewDefaultRegistry(&events.NoOpEventBus{}, tools.NewTodoManager(), nil, nil)
+}
+
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"sql_helper", "reviewer-2", "a"} {
+		assert.NoError(t, ValidateID(id), id)
+	}
+	for _, id := range []string{"", "SQL", "-lead", "../escape", "has space"} {
+		assert.Error(t, ValidateID(id), id)
+	}
+}
+
+func TestSuggestTools(t *testing.T) {
+	registry := newDraftRegistry()
+
+	coding := SuggestTools("Fix bugs and refactor the Go services", registry)
+	assert.Equal(t, "@essentials", coding[0])
+	assert.Subset(t, coding, []string{"readFile", "editFile", "writeFile", "bash"})
+
+	review := SuggestTools("Analyze pull requests for security problems", registry)
+	assert.Subset(t, review, []string{"readFile", "searchInFiles"})
+	assert.NotContains(t, review, "writeFile", "reviewing needs no writes")
+
+	// Words only match whole, or as the start of longer words
+	specific := SuggestTools("Specific advice on cooking", registry)
+	assert.Equal(t, []string{"@essentials", "listFiles", "findFiles", "readFile", "searchInFiles"}, specific)
+
+	// Tools the registry lacks are never suggested
+	assert.Empty(t, SuggestTools("code", tools.NewRegistry()))
+}
+
+func TestCompleteTool(t *testing.T) {
+	catalog := []string{"@essentials", "@github", "gitDiff", "gitLog", "readFile"}
+
+	assert.Equal(t, []string{"@essentials", "@github"}, CompleteTool("@", catalog))
+	assert.Equal(t, []string{"gitDiff", "gitLog"}, CompleteTool("git", catalog))
+	assert.Equal(t, []string{"readFile"}, CompleteTool("READ", catalog))
+	assert.Equal(t, []string{"gitLog"}, CompleteTool("gitLog", catalog))
+	assert.Empty(t, CompleteTool("nope", catalog))
+}
+
+func TestDraftYAMLValidates(t *testing.T) {
+	registry := newDraftRegistry()
+	draft := Draft{ID: "code_helper", Purpose: "implement features", Tools: []string{"@essentials", "readFile", "editFile"}}
+
+	data, err := draft.YAML()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "name: Code Helper")
+	assert.Contains(t, string(data), "Your purpose: implement features.")
+	assert.NotContains(t, string(data), "llm_provider", "unset models use the configured default")
+	require.NoError(t, ValidatePrompt(data, registry))
+
+	draft.Tools = append(draft.Tools, "noSuchTool")
+	data, err = draft.YAML()
+	require.NoError(t, err)
+	assert.ErrorContains(t, ValidatePrompt(data, registry), "noSuchTool")
+
+	assert.ErrorContains(t, ValidatePrompt([]byte("name: x\ninstruction: \"{{.unclosed\"\n"), registry), "instruction")
+}
+
+func TestWriteDraftKeepsExistingPersonas(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := WriteDraft(dir, "helper", []byte("first"), false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "helper", "prompt.yaml"), path)
+
+	_, err = WriteDraft(dir, "helper", []byte("second"), false)
+	assert.ErrorContains(t, err, "already exists")
+
+	_, err = WriteDraft(dir, "helper", []byte("second"), true)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+}
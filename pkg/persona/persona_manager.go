@@ -15,6 +15,7 @@
 // - engineer: Full-featured software engineering assistant
 // - product_owner: Product management and planning focused assistant
 // - persona_creator: Expert in designing custom personas for specific user objectives
+// - reviewer: Read-only code reviewer behind genie review
 package persona
 
 import (
@@ -22,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/config"
@@ -30,6 +32,15 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+const (
+	// DefaultConfigKey names the persona of sessions that pick none.
+	DefaultConfigKey = "GENIE_PERSONA"
+
+	// AllowedConfigKey lists the personas sessions may use, separated by
+	// commas; empty allows all.
+	AllowedConfigKey = "GENIE_ALLOWED_PERSONAS"
+)
+
 // PersonaAwarePromptFactory creates prompts based on persona names
 type PersonaAwarePromptFactory interface {
 	GetPrompt(ctx context.Context, personaName string) (*ai.Prompt, error)
@@ -79,6 +90,9 @@ type PersonaManager interface {
 	// SetInMemoryPersonaYAML sets an in-memory persona from YAML bytes, bypassing file-based discovery.
 	// When set, GetPrompt() will use this persona instead of discovering from files.
 	SetInMemoryPersonaYAML(yamlContent []byte) error
+	// PromptFromYAML loads a prompt from YAML bytes the way a persona's
+	// prompt.yaml is loaded, without changing the persona in use.
+	PromptFromYAML(ctx context.Context, yamlContent []byte) (*ai.Prompt, error)
 }
 
 // DefaultPersonaManager is the default implementation of PersonaManager
@@ -87,6 +101,7 @@ type DefaultPersonaManager struct {
 	configManager       config.Manager
 	publisher           events.Publisher
 	defaultPersona      string
+	allowed             map[string]bool // nil allows every persona
 	userHome            string
 	inMemoryPersonaYAML []byte     // In-memory persona YAML bytes, bypasses file discovery when set
 	inMemoryPrompt      *ai.Prompt // Cached prompt from in-memory persona
@@ -95,14 +110,25 @@ type DefaultPersonaManager struct {
 // NewDefaultPersonaManager creates a new DefaultPersonaManager with the given dependencies
 func NewDefaultPersonaManager(promptFactory PersonaAwarePromptFactory, configManager config.Manager, publisher events.Publisher) PersonaManager {
 	// Check GENIE_PERSONA environment variable via config manager, fallback to "genie"
-	defaultPersona := configManager.GetStringWithDefault("GENIE_PERSONA", "genie")
+	defaultPersona := configManager.GetStringWithDefault(DefaultConfigKey, "genie")
 	userHome, _ := os.UserHomeDir()
 
+	var allowed map[string]bool
+	for _, id := range strings.Split(configManager.GetStringWithDefault(AllowedConfigKey, ""), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			if allowed == nil {
+				allowed = make(map[string]bool)
+			}
+			allowed[id] = true
+		}
+	}
+
 	return &DefaultPersonaManager{
 		promptFactory:  promptFactory,
 		configManager:  configManager,
 		publisher:      publisher,
 		defaultPersona: defaultPersona,
+		allowed:        allowed,
 		userHome:       userHome,
 	}
 }
@@ -118,6 +144,9 @@ func (m *DefaultPersonaManager) GetPrompt(ctx context.Context) (*ai.Prompt, erro
 	if contextPersona, ok := toolctx.Persona(ctx); ok && contextPersona != "" {
 		persona = contextPersona
 	}
+	if !m.isAllowed(persona) {
+		return nil, fmt.Errorf("persona %s is not allowed here; %s permits %s", persona, AllowedConfigKey, m.configManager.GetStringWithDefault(AllowedConfigKey, ""))
+	}
 
 	prompt, err := m.promptFactory.GetPrompt(ctx, persona)
 	if err == nil {
@@ -157,6 +186,15 @@ func (m *DefaultPersonaManager) SetInMemoryPersonaYAML(yamlContent []byte) error
 	return nil
 }
 
+// PromptFromYAML loads a prompt from YAML bytes the way a persona's
+// prompt.yaml is loaded, without changing the persona in use.
+func (m *DefaultPersonaManager) PromptFromYAML(ctx context.Context, yamlContent []byte) (*ai.Prompt, error) {
+	if len(yamlContent) == 0 {
+		return nil, fmt.Errorf("persona YAML content is empty")
+	}
+	return m.promptFactory.GetPromptFromBytes(ctx, yamlContent)
+}
+
 // publishPersonaWarning publishes a notification event when a persona fails to load
 // and falls back to the default persona
 func (m *DefaultPersonaManager) publishPersonaWarning(persona string, err error) {
@@ -203,7 +241,11 @@ func (m *DefaultPersonaManager) ListPersonas(ctx context.Context) ([]Persona, er
 		}
 	}
 
-	// Load project personas (highest priority)
+	// Load project personas (highest priority), unless the user hasn't
+	// trusted this workspace
+	if trusted, ok := toolctx.WorkspaceTrusted(ctx); ok && !trusted {
+		cwd = ""
+	}
 	if cwd != "" {
 		projectPersonas, err := m.discoverPersonasInDir(filepath.Join(cwd, ".genie", "personas"), PersonaSourceProject)
 		if err == nil {
@@ -216,12 +258,19 @@ func (m *DefaultPersonaManager) ListPersonas(ctx context.Context) ([]Persona, er
 	// Convert map to slice
 	personas := make([]Persona, 0, len(personaMap))
 	for _, p := range personaMap {
-		personas = append(personas, p)
+		if m.isAllowed(p.ID) {
+			personas = append(personas, p)
+		}
 	}
 
 	return personas, nil
 }
 
+// isAllowed reports whether sessions may use the persona id
+func (m *DefaultPersonaManager) isAllowed(id string) bool {
+	return m.allowed == nil || m.allowed[id]
+}
+
 // discoverInternalPersonas discovers personas from the embedded filesystem
 func (m *DefaultPersonaManager) discoverInternalPersonas() ([]Persona, error) {
 	var personas []Persona
@@ -41,6 +41,14 @@ func (m *MockPersonaManager) SetInMemoryPersonaYAML(yamlContent []byte) error {
 	return args.Error(0)
 }
 
+func (m *MockPersonaManager) PromptFromYAML(ctx context.Context, yamlContent []byte) (*ai.Prompt, error) {
+	args := m.Called(ctx, yamlContent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ai.Prompt), args.Error(1)
+}
+
 // TestPersonaManagerInterface ensures the interface is properly defined
 func TestPersonaManagerInterface(t *testing.T) {
 	// This test verifies that MockPersonaManager implements PersonaManager
@@ -162,6 +170,7 @@ func TestDefaultPersonaManager_GetPrompt(t *testing.T) {
 
 	// Set up config expectations - should return "genie" as default
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -193,6 +202,7 @@ func TestDefaultPersonaManager_GetPrompt_FactoryError(t *testing.T) {
 
 	// Set up config expectations - should return "genie" as default
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -220,6 +230,7 @@ func TestDefaultPersonaManager_GetPrompt_FallbackToDefault(t *testing.T) {
 	mockConfig := new(MockConfigManager)
 
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -247,6 +258,7 @@ func TestDefaultPersonaManager_ListPersonas_AlwaysReturnsInternalPersonas(t *tes
 
 	// Set up config expectations
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -273,6 +285,7 @@ func TestDefaultPersonaManager_ListPersonas_InternalPersonas(t *testing.T) {
 
 	// Set up config expectations
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -331,6 +344,7 @@ instruction: |
 	mockFactory := new(MockPersonaAwarePromptFactory)
 	mockConfig := new(MockConfigManager)
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := &DefaultPersonaManager{
 		promptFactory:  mockFactory,
@@ -384,6 +398,7 @@ instruction: |
 	mockFactory := new(MockPersonaAwarePromptFactory)
 	mockConfig := new(MockConfigManager)
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -408,6 +423,47 @@ instruction: |
 	assert.True(t, foundProjectPersona, "Should find the project persona")
 }
 
+func TestDefaultPersonaManager_ListPersonas_UntrustedWorkspaceSkipsProjectPersonas(t *testing.T) {
+	tempDir := t.TempDir()
+	projectPersonaDir := filepath.Join(tempDir, ".genie", "personas", "project-persona")
+	assert.NoError(t, os.MkdirAll(projectPersonaDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPersonaDir, "prompt.yaml"), []byte("name: \"Project\"\n"), 0644))
+
+	mockConfig := new(MockConfigManager)
+	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
+	manager := NewDefaultPersonaManager(new(MockPersonaAwarePromptFactory), mockConfig, nil)
+
+	ctx := toolctx.WithWorkingDir(context.Background(), tempDir)
+	ctx = toolctx.WithWorkspaceTrusted(ctx, false)
+	personas, err := manager.ListPersonas(ctx)
+	assert.NoError(t, err)
+	for _, persona := range personas {
+		assert.NotEqual(t, "project-persona", persona.ID, "untrusted workspace must not supply personas")
+	}
+}
+
+func TestDefaultPersonaManager_AllowedPersonas(t *testing.T) {
+	mockFactory := new(MockPersonaAwarePromptFactory)
+	mockConfig := new(MockConfigManager)
+	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("reviewer")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("reviewer, engineer")
+	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
+
+	ctx := toolctx.WithWorkingDir(context.Background(), t.TempDir())
+	personas, err := manager.ListPersonas(ctx)
+	assert.NoError(t, err)
+	var ids []string
+	for _, persona := range personas {
+		ids = append(ids, persona.ID)
+	}
+	assert.ElementsMatch(t, []string{"reviewer", "engineer"}, ids)
+
+	_, err = manager.GetPrompt(toolctx.WithPersona(ctx, "genie"))
+	assert.ErrorContains(t, err, "persona genie is not allowed")
+	mockFactory.AssertNotCalled(t, "GetPrompt", mock.Anything, "genie")
+}
+
 // TestDefaultPersonaManager_ListPersonas_Priority tests priority handling when personas have same ID
 func TestDefaultPersonaManager_ListPersonas_Priority(t *testing.T) {
 	// Create temporary directories for testing
@@ -452,6 +508,7 @@ instruction: |
 	mockFactory := new(MockPersonaAwarePromptFactory)
 	mockConfig := new(MockConfigManager)
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := &DefaultPersonaManager{
 		promptFactory:  mockFactory,
@@ -499,6 +556,7 @@ func TestDefaultPersonaManager_SetInMemoryPersonaYAML(t *testing.T) {
 	mockConfig := new(MockConfigManager)
 
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -534,6 +592,7 @@ func TestDefaultPersonaManager_SetInMemoryPersonaYAML_EmptyContent(t *testing.T)
 	mockConfig := new(MockConfigManager)
 
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -549,6 +608,7 @@ func TestDefaultPersonaManager_SetInMemoryPersonaYAML_OverridesFileDiscovery(t *
 	mockConfig := new(MockConfigManager)
 
 	mockConfig.On("GetStringWithDefault", "GENIE_PERSONA", "genie").Return("genie")
+	mockConfig.On("GetStringWithDefault", AllowedConfigKey, "").Return("")
 
 	manager := NewDefaultPersonaManager(mockFactory, mockConfig, nil)
 
@@ -66,6 +66,11 @@ func (f *PersonaPromptFactory) GetPrompt(ctx context.Context, personaName string
 		}
 	}
 
+	// Untrusted workspaces don't get to supply their own personas
+	if trusted, ok := toolctx.WorkspaceTrusted(ctx); ok && !trusted {
+		genieHome = ""
+	}
+
 	// Try loading personas in order: project > user > internal
 	var prompt ai.Prompt
 	var err error
@@ -0,0 +1,58 @@
+package persona
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// FindPromptYAML returns the prompt.yaml a persona is loaded from, looking
+// where GetPrompt does: the project, then the user's home, then the
+// built-in personas. path is empty for a built-in persona, which has no
+// file on disk.
+func FindPromptYAML(ctx context.Context, personaID string) (data []byte, path string, source PersonaSource, err error) {
+	if err := ValidateID(personaID); err != nil {
+		return nil, "", "", err
+	}
+
+	genieHome, ok := toolctx.GenieHome(ctx)
+	if !ok {
+		genieHome, _ = toolctx.WorkingDir(ctx)
+	}
+	if trusted, ok := toolctx.WorkspaceTrusted(ctx); ok && !trusted {
+		genieHome = ""
+	}
+	userHome, _ := os.UserHomeDir()
+
+	candidates := []struct {
+		dir    string
+		source PersonaSource
+	}{
+		{genieHome, PersonaSourceProject},
+		{userHome, PersonaSourceUser},
+	}
+	for _, candidate := range candidates {
+		if candidate.dir == "" {
+			continue
+		}
+		path := filepath.Join(candidate.dir, ".genie", "personas", personaID, "prompt.yaml")
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return data, path, candidate.source, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", "", fmt.Errorf("unable to read %s persona %q at %s: %w", candidate.source, personaID, path, err)
+		}
+	}
+
+	data, err = personasFS.ReadFile("personas/" + personaID + "/prompt.yaml")
+	if err != nil {
+		return nil, "", "", fmt.Errorf("persona %s not found in any location (project, user, or internal)", personaID)
+	}
+	return data, "", PersonaSourceInternal, nil
+}
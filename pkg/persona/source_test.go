@@ -0,0 +1,52 @@
+package persona
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+func TestFindPromptYAML(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	ctx := toolctx.WithGenieHome(context.Background(), project)
+
+	// Built-in personas have no file
+	data, path, source, err := FindPromptYAML(ctx, "engineer")
+	require.NoError(t, err)
+	assert.NotEmpty(t, data)
+	assert.Empty(t, path)
+	assert.Equal(t, PersonaSourceInternal, source)
+
+	userPath := filepath.Join(home, ".genie", "personas", "engineer", "prompt.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(userPath), 0o755))
+	require.NoError(t, os.WriteFile(userPath, []byte("name: user"), 0o644))
+	data, path, source, err = FindPromptYAML(ctx, "engineer")
+	require.NoError(t, err)
+	assert.Equal(t, "name: user", string(data))
+	assert.Equal(t, userPath, path)
+	assert.Equal(t, PersonaSourceUser, source)
+
+	projectPath := filepath.Join(project, ".genie", "personas", "engineer", "prompt.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(projectPath), 0o755))
+	require.NoError(t, os.WriteFile(projectPath, []byte("name: project"), 0o644))
+	_, path, source, err = FindPromptYAML(ctx, "engineer")
+	require.NoError(t, err)
+	assert.Equal(t, projectPath, path)
+	assert.Equal(t, PersonaSourceProject, source)
+
+	// Untrusted workspaces don't supply personas
+	_, path, _, err = FindPromptYAML(toolctx.WithWorkspaceTrusted(ctx, false), "engineer")
+	require.NoError(t, err)
+	assert.Equal(t, userPath, path)
+
+	_, _, _, err = FindPromptYAML(ctx, "missing")
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+// ConfigKey turns loading plugins from the plugins directory off when set
+// to false (default on).
+const ConfigKey = "GENIE_PLUGINS"
+
+// Symbol is the name Open looks up in a Go plugin. It is either a variable
+// holding a Plugin or a func() Plugin:
+//
+//	var Plugin plugin.Plugin = &myPlugin{}
+const Symbol = "Plugin"
+
+// DefaultDir returns ~/.genie/plugins, where Genie looks for .so files.
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".genie", "plugins"), nil
+}
+
+// LoadDir opens every .so file in dir, in name order. A missing directory
+// has no plugins; a plugin that fails to load is reported in the returned
+// errors without stopping the others.
+func LoadDir(dir string) ([]Plugin, []error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to read plugins directory: %w", err)}
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".so") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var plugins []Plugin
+	var errs []error
+	for _, name := range names {
+		p, err := Open(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, errs
+}
+
+// LoadInstalled registers the plugins in ~/.genie/plugins unless
+// GENIE_PLUGINS is false, and returns the problems loading them. Call it
+// before creating Genie.
+func LoadInstalled(cfg config.Manager) []error {
+	if !cfg.GetBoolWithDefault(ConfigKey, true) {
+		return nil
+	}
+	dir, err := DefaultDir()
+	if err != nil {
+		return []error{err}
+	}
+	plugins, errs := LoadDir(dir)
+	for _, p := range plugins {
+		if err := tryRegister(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// tryRegister is Register reporting a taken name instead of panicking,
+// since installed plugins are not under the program's control.
+func tryRegister(p Plugin) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	Register(p)
+	return nil
+}
+
+// fromSymbol turns the looked-up Symbol of the plugin at path into a Plugin
+func fromSymbol(path string, symbol any) (Plugin, error) {
+	var p Plugin
+	switch v := symbol.(type) {
+	case *Plugin:
+		p = *v
+	case func() Plugin:
+		p = v()
+	case Plugin:
+		p = v
+	default:
+		return nil, fmt.Errorf("plugin %s: %s is a %T, not a plugin.Plugin or func() plugin.Plugin", path, Symbol, symbol)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("plugin %s: %s is nil", path, Symbol)
+	}
+	return p, nil
+}
@@ -0,0 +1,23 @@
+//go:build cgo && (linux || darwin || freebsd)
+
+package plugin
+
+import (
+	"fmt"
+	goplugin "plugin"
+)
+
+// Open loads the Go plugin at path and returns the Plugin it exports as
+// Symbol. The plugin must be built with -buildmode=plugin by the same Go
+// version, with the same versions of the packages it shares with Genie.
+func Open(path string) (Plugin, error) {
+	lib, err := goplugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	symbol, err := lib.Lookup(Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	return fromSymbol(path, symbol)
+}
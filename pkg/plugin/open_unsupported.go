@@ -0,0 +1,12 @@
+//go:build !cgo || !(linux || darwin || freebsd)
+
+package plugin
+
+import "fmt"
+
+// Open loads the Go plugin at path. Go plugins need cgo on Linux, macOS
+// or FreeBSD, so in this build it always fails; register plugins in a
+// custom build instead.
+func Open(path string) (Plugin, error) {
+	return nil, fmt.Errorf("plugin %s: Go plugins are not supported in this build (they need cgo on Linux, macOS or FreeBSD)", path)
+}
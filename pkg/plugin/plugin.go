@@ -0,0 +1,144 @@
+// Package plugin lets third parties extend Genie without forking it. A
+// plugin contributes tools, TUI commands, context parts and event
+// subscribers by implementing the matching interfaces below.
+//
+// Plugins reach Genie in one of three ways:
+//
+//   - Embedders pass them to genie.NewGenie with genie.WithPlugins.
+//   - A custom build registers them with Register, typically from an init
+//     function pulled in by a blank import, as database/sql drivers do.
+//   - Genie loads Go plugins (.so files built with -buildmode=plugin) from
+//     ~/.genie/plugins at startup; see Open.
+//
+// Tools in other languages, or that should run in their own process, are
+// better served by an MCP server configured in .mcp.json.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/ctx"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/tools"
+)
+
+// Plugin is an extension of Genie. On its own it does nothing; it
+// contributes through ToolProvider, CommandProvider, ContextProvider and
+// EventSubscriber.
+type Plugin interface {
+	// Name identifies the plugin in messages and must be unique
+	Name() string
+}
+
+// ToolProvider is a plugin that offers tools to the model. The tools are
+// registered next to the built-in ones; a name clash with a built-in tool
+// fails startup.
+type ToolProvider interface {
+	Plugin
+	Tools() []tools.Tool
+}
+
+// ContextProvider is a plugin that adds parts to the context of every
+// turn, such as the state of an issue tracker.
+type ContextProvider interface {
+	Plugin
+	ContextParts() []ctx.ContextPartProvider
+}
+
+// EventSubscriber is a plugin that observes Genie's events, such as
+// tool.executed or chat.response, for logging or metrics. Subscribe is
+// called once, when Genie is created.
+type EventSubscriber interface {
+	Plugin
+	Subscribe(bus events.EventBus)
+}
+
+// CommandProvider is a plugin that adds commands to the TUI, run as
+// :<name>.
+type CommandProvider interface {
+	Plugin
+	Commands() []Command
+}
+
+// Command is a TUI command a plugin adds.
+type Command struct {
+	Name        string
+	Description string
+	Usage       string
+	// Run executes the command with its arguments; the returned text is
+	// shown in the transcript
+	Run func(ctx context.Context, args []string) (string, error)
+}
+
+var (
+	mu         sync.Mutex
+	registered []Plugin
+)
+
+// Register makes p available to every Genie created afterwards in this
+// process. It panics when p is nil or its name is taken, since both are
+// programming errors found at startup.
+func Register(p Plugin) {
+	if p == nil {
+		panic("plugin: Register called with a nil plugin")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, existing := range registered {
+		if existing.Name() == p.Name() {
+			panic(fmt.Sprintf("plugin: Register called twice for %q", p.Name()))
+		}
+	}
+	registered = append(registered, p)
+}
+
+// Registered returns the registered plugins in registration order.
+func Registered() []Plugin {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Plugin(nil), registered...)
+}
+
+// Tools returns the tools the plugins offer.
+func Tools(plugins []Plugin) []tools.Tool {
+	var all []tools.Tool
+	for _, p := range plugins {
+		if provider, ok := p.(ToolProvider); ok {
+			all = append(all, provider.Tools()...)
+		}
+	}
+	return all
+}
+
+// ContextParts returns the context part providers the plugins offer.
+func ContextParts(plugins []Plugin) []ctx.ContextPartProvider {
+	var all []ctx.ContextPartProvider
+	for _, p := range plugins {
+		if provider, ok := p.(ContextProvider); ok {
+			all = append(all, provider.ContextParts()...)
+		}
+	}
+	return all
+}
+
+// Subscribe lets the plugins that observe events subscribe to bus.
+func Subscribe(plugins []Plugin, bus events.EventBus) {
+	for _, p := range plugins {
+		if subscriber, ok := p.(EventSubscriber); ok {
+			subscriber.Subscribe(bus)
+		}
+	}
+}
+
+// Commands returns the TUI commands the plugins offer.
+func Commands(plugins []Plugin) []Command {
+	var all []Command
+	for _, p := range plugins {
+		if provider, ok := p.(CommandProvider); ok {
+			all = append(all, provider.Commands()...)
+		}
+	}
+	return all
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ctx"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedPlugin string
+
+func (p namedPlugin) Name() string { return string(p) }
+
+type fullPlugin struct {
+	namedPlugin
+	subscribed events.EventBus
+}
+
+func (p *fullPlugin) Tools() []tools.Tool { return []tools.Tool{fakeTool{}} }
+
+func (p *fullPlugin) ContextParts() []ctx.ContextPartProvider {
+	return []ctx.ContextPartProvider{ctx.NewPinnedFilesContextPartProvider()}
+}
+
+func (p *fullPlugin) Subscribe(bus events.EventBus) { p.subscribed = bus }
+
+func (p *fullPlugin) Commands() []Command {
+	return []Command{{Name: "ticket", Run: func(context.Context, []string) (string, error) { return "", nil }}}
+}
+
+type fakeTool struct{}
+
+func (fakeTool) Declaration() *ai.FunctionDeclaration { return &ai.FunctionDeclaration{Name: "ticket"} }
+func (fakeTool) Handler() ai.HandlerFunc              { return nil }
+func (fakeTool) FormatOutput(map[string]any) string   { return "" }
+
+// withRegistry runs a test against an empty set of registered plugins
+func withRegistry(t *testing.T) {
+	t.Helper()
+	mu.Lock()
+	saved := registered
+	registered = nil
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		registered = saved
+		mu.Unlock()
+	})
+}
+
+func TestRegister(t *testing.T) {
+	withRegistry(t)
+
+	Register(namedPlugin("jira"))
+	Register(namedPlugin("metrics"))
+	assert.Panics(t, func() { Register(namedPlugin("jira")) })
+	assert.Panics(t, func() { Register(nil) })
+
+	plugins := Registered()
+	require.Len(t, plugins, 2)
+	assert.Equal(t, "jira", plugins[0].Name())
+	assert.Equal(t, "metrics", plugins[1].Name())
+
+	assert.ErrorContains(t, tryRegister(namedPlugin("metrics")), `Register called twice for "metrics"`)
+}
+
+func TestCapabilities(t *testing.T) {
+	full := &fullPlugin{namedPlugin: "jira"}
+	plugins := []Plugin{namedPlugin("bare"), full}
+
+	assert.Len(t, Tools(plugins), 1)
+	assert.Len(t, ContextParts(plugins), 1)
+	require.Len(t, Commands(plugins), 1)
+	assert.Equal(t, "ticket", Commands(plugins)[0].Name)
+
+	bus := events.NewEventBus()
+	Subscribe(plugins, bus)
+	assert.Equal(t, bus, full.subscribed)
+}
+
+func TestLoadDir(t *testing.T) {
+	plugins, errs := LoadDir(filepath.Join(t.TempDir(), "missing"))
+	assert.Empty(t, plugins)
+	assert.Empty(t, errs)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.so"), []byte("not a plugin"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("notes"), 0o644))
+	plugins, errs = LoadDir(dir)
+	assert.Empty(t, plugins)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "broken.so")
+}
+
+func TestFromSymbol(t *testing.T) {
+	var p Plugin = namedPlugin("jira")
+	for name, symbol := range map[string]any{
+		"variable": &p,
+		"func":     func() Plugin { return namedPlugin("jira") },
+		"value":    namedPlugin("jira"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := fromSymbol("jira.so", symbol)
+			require.NoError(t, err)
+			assert.Equal(t, "jira", got.Name())
+		})
+	}
+
+	_, err := fromSymbol("jira.so", "jira")
+	assert.ErrorContains(t, err, "is a string")
+	var none Plugin
+	_, err = fromSymbol("jira.so", &none)
+	assert.ErrorContains(t, err, "is nil")
+}
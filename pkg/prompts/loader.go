@@ -17,17 +17,23 @@ package prompts
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"runtime/debug"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/hooks"
+	"github.com/kcaldas/genie/pkg/telemetry"
 	"github.com/kcaldas/genie/pkg/toolctx"
 	"github.com/kcaldas/genie/pkg/tools"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v2"
 )
 
@@ -44,6 +50,8 @@ type DefaultLoader struct {
 	Publisher    events.Publisher     // Event publisher for tool execution events
 	ToolRegistry tools.Registry       // Tool registry for getting available tools
 	Config       config.Manager       // Configuration manager for model defaults
+	OutputStore  *tools.OutputStore   // Truncates oversized tool results; nil disables truncation
+	ToolTimeouts ToolTimeouts         // Per-tool execution limits; the zero value sets none
 	promptCache  map[string]ai.Prompt // Cache to store loaded prompts by file path
 	cacheMutex   sync.RWMutex         // Mutex to protect the cache map
 }
@@ -67,8 +75,7 @@ func (l *DefaultLoader) LoadPromptFromFS(filesystem fs.FS, filePath string) (ai.
 		return ai.Prompt{}, fmt.Errorf("error reading prompt file %s: %w", filePath, err)
 	}
 
-	var newPrompt ai.Prompt
-	err = yaml.Unmarshal(data, &newPrompt)
+	newPrompt, err := unmarshalPrompt(data)
 	if err != nil {
 		return ai.Prompt{}, fmt.Errorf("error unmarshaling prompt from %s: %w", filePath, err)
 	}
@@ -98,8 +105,7 @@ func (l *DefaultLoader) LoadPromptFromFS(filesystem fs.FS, filePath string) (ai.
 // This is used for in-memory persona configuration, bypassing file-based discovery.
 // Note: Prompts loaded from bytes are not cached since they may be dynamically generated.
 func (l *DefaultLoader) LoadPromptFromBytes(data []byte) (ai.Prompt, error) {
-	var newPrompt ai.Prompt
-	err := yaml.Unmarshal(data, &newPrompt)
+	newPrompt, err := unmarshalPrompt(data)
 	if err != nil {
 		return ai.Prompt{}, fmt.Errorf("error unmarshaling prompt from bytes: %w", err)
 	}
@@ -116,12 +122,34 @@ func (l *DefaultLoader) LoadPromptFromBytes(data []byte) (ai.Prompt, error) {
 	return newPrompt, nil
 }
 
+// unmarshalPrompt decodes a prompt.yaml. max_output_tokens is accepted as
+// another name for max_tokens, and wins when both are set.
+func unmarshalPrompt(data []byte) (ai.Prompt, error) {
+	var prompt ai.Prompt
+	if err := yaml.Unmarshal(data, &prompt); err != nil {
+		return ai.Prompt{}, err
+	}
+	var aliases struct {
+		MaxOutputTokens int32 `yaml:"max_output_tokens"`
+	}
+	if err := yaml.Unmarshal(data, &aliases); err != nil {
+		return ai.Prompt{}, err
+	}
+	if aliases.MaxOutputTokens > 0 {
+		prompt.MaxTokens = aliases.MaxOutputTokens
+	}
+	return prompt, nil
+}
+
 // NewPromptLoader creates a new PromptLoader using embedded prompts
 func NewPromptLoader(publisher events.Publisher, toolRegistry tools.Registry) Loader {
+	configManager := config.NewConfigManager()
 	return &DefaultLoader{
 		Publisher:    publisher,
 		ToolRegistry: toolRegistry,
-		Config:       config.NewConfigManager(),
+		Config:       configManager,
+		OutputStore:  tools.NewConfiguredOutputStore(configManager),
+		ToolTimeouts: ToolTimeoutsFromConfig(configManager),
 		promptCache:  make(map[string]ai.Prompt),
 	}
 }
@@ -169,29 +197,58 @@ func (l *DefaultLoader) AddTools(prompt *ai.Prompt) error {
 
 	var toolsList []tools.Tool
 	var missingTools []string
+	reasons := make(map[string]string)
+	missing := func(name, reason string) {
+		missingTools = append(missingTools, name)
+		if reason != "" {
+			reasons[name] = reason
+		}
+	}
 
 	// Use existing registry Get method (already O(1) map lookup)
 	for _, toolName := range prompt.RequiredTools {
 		// Check if this is a toolSet reference (starts with @)
 		if strings.HasPrefix(toolName, "@") {
 			setName := strings.TrimPrefix(toolName, "@")
-			if setTools, exists := l.ToolRegistry.GetToolSet(setName); exists {
-				toolsList = append(toolsList, setTools...)
-			} else {
-				missingTools = append(missingTools, toolName)
+			setTools, exists := l.ToolRegistry.GetToolSet(setName)
+			if !exists {
+				missing(toolName, l.mcpServerError(setName))
+				continue
+			}
+			var available []tools.Tool
+			var setReason string
+			for _, tool := range setTools {
+				if err := checkAvailable(tool); err != nil {
+					setReason = err.Error()
+					continue
+				}
+				available = append(available, tool)
+			}
+			if len(available) == 0 {
+				missing(toolName, setReason)
 			}
+			toolsList = append(toolsList, available...)
 		} else {
 			// Regular tool lookup
-			if tool, exists := l.ToolRegistry.Get(toolName); exists {
-				toolsList = append(toolsList, tool)
-			} else {
-				missingTools = append(missingTools, toolName)
+			tool, exists := l.ToolRegistry.Get(toolName)
+			if !exists {
+				missing(toolName, "")
+				continue
+			}
+			if err := checkAvailable(tool); err != nil {
+				missing(toolName, err.Error())
+				continue
 			}
+			toolsList = append(toolsList, tool)
 		}
 	}
 
 	if len(missingTools) > 0 {
 		prompt.MissingTools = missingTools
+		slog.Warn("Persona requires tools that are not available; starting without them", "persona", prompt.Name, "tools", missingTools)
+	}
+	if len(reasons) > 0 {
+		prompt.MissingToolReasons = reasons
 	}
 
 	// Initialize Functions slice if nil
@@ -211,16 +268,144 @@ func (l *DefaultLoader) AddTools(prompt *ai.Prompt) error {
 
 		// Wrap handler with events
 		originalHandler := tool.Handler()
-		wrappedHandler := l.wrapHandlerWithEvents(declaration.Name, originalHandler)
+		var redact func(map[string]any) map[string]any
+		if redactor, ok := tool.(tools.ParameterRedactor); ok {
+			redact = redactor.RedactParameters
+		}
+		wrappedHandler := l.wrapHandlerWithEvents(declaration.Name, originalHandler, redact)
 		prompt.Handlers[declaration.Name] = wrappedHandler
 	}
 
 	return nil
 }
 
-// wrapHandlerWithEvents wraps a tool handler to publish events when executed
-func (l *DefaultLoader) wrapHandlerWithEvents(toolName string, handler ai.HandlerFunc) ai.HandlerFunc {
-	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+// checkAvailable runs the tool's availability check, if it has one
+func checkAvailable(tool tools.Tool) error {
+	if checker, ok := tool.(tools.AvailabilityChecker); ok {
+		return checker.CheckAvailable()
+	}
+	return nil
+}
+
+// mcpServerError explains a missing @toolset that names an MCP server
+// which failed to connect
+func (l *DefaultLoader) mcpServerError(setName string) string {
+	if err, ok := l.ToolRegistry.MCPServerErrors()[setName]; ok {
+		return fmt.Sprintf("MCP server %s did not connect: %s", setName, err)
+	}
+	return ""
+}
+
+// toolCancelGrace is how long a cancelled tool has to wind down and report
+// its own result, such as a killed command's partial output, before the
+// turn moves on without it.
+var toolCancelGrace = 2 * time.Second
+
+// runToolHandler executes handler, converting panics into errors: in
+// streaming mode handlers run inside producer goroutines, where an
+// unrecovered panic would crash the whole process. A positive timeout
+// bounds the call; timedOut reports whether that limit, rather than the
+// caller, ended it. When ctx is cancelled or the limit passes the handler
+// is expected to stop; one that ignores its context is abandoned after
+// toolCancelGrace and a cancelled or timed-out result is returned.
+func runToolHandler(ctx context.Context, toolName string, handler ai.HandlerFunc, params map[string]any, timeout time.Duration) (result map[string]any, timedOut bool, err error) {
+	if parent := ctx; parent != nil && timeout > 0 {
+		toolCtx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		defer func() {
+			timedOut = parent.Err() == nil && errors.Is(toolCtx.Err(), context.DeadlineExceeded)
+		}()
+		ctx = toolCtx
+	}
+
+	type outcome struct {
+		result map[string]any
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("tool %s panicked: %v\n%s", toolName, r, debug.Stack())}
+			}
+		}()
+		result, err := handler(ctx, params)
+		done <- outcome{result: result, err: err}
+	}()
+
+	if ctx == nil {
+		o := <-done
+		return o.result, false, o.err
+	}
+	select {
+	case o := <-done:
+		return o.result, false, o.err
+	case <-ctx.Done():
+	}
+
+	timer := time.NewTimer(toolCancelGrace)
+	defer timer.Stop()
+	select {
+	case o := <-done:
+		return o.result, false, o.err
+	case <-timer.C:
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && timeout > 0 {
+		slog.Warn("Tool ignored its timeout; abandoning it", "tool", toolName, "timeout", timeout)
+		message := fmt.Sprintf("tool %s timed out after %s", toolName, timeout)
+		return map[string]any{
+			"success":   false,
+			"timed_out": true,
+			"error":     message,
+		}, true, errors.New(message)
+	}
+	slog.Warn("Tool ignored cancellation; abandoning it", "tool", toolName)
+	return map[string]any{
+		"success":   false,
+		"cancelled": true,
+		"error":     "cancelled by user",
+	}, false, ctx.Err()
+}
+
+// requestID returns the chat request a tool call belongs to, or "".
+func requestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := toolctx.RequestID(ctx)
+	return id
+}
+
+// eventParams returns the parameters tool events carry: the redacted
+// ones when redact is set, without the "_" parameters meant for the host.
+func eventParams(params map[string]any, redact func(map[string]any) map[string]any) map[string]any {
+	if redact != nil {
+		params = redact(params)
+	}
+	filtered := make(map[string]any)
+	for k, v := range params {
+		if !strings.HasPrefix(k, "_") {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// wrapHandlerWithEvents wraps a tool handler to publish events when
+// executed. redact, when set, hides credentials in the published
+// parameters.
+func (l *DefaultLoader) wrapHandlerWithEvents(toolName string, handler ai.HandlerFunc, redact func(map[string]any) map[string]any) ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (result map[string]any, err error) {
+		ctx, span := telemetry.StartSpan(ctx, "execute_tool "+toolName,
+			attribute.String("gen_ai.operation.name", "execute_tool"),
+			attribute.String("gen_ai.tool.name", toolName),
+		)
+		defer func() { telemetry.End(span, err) }()
+
+		// The pre_tool hooks may block the call or change its parameters
+		lifecycle := hooks.FromContext(ctx)
+		params, blocked := lifecycle.RunPreTool(ctx, toolName, params)
+
 		// Publish tool starting event before execution
 		if l.Publisher != nil {
 			executionID := "unknown"
@@ -229,38 +414,55 @@ func (l *DefaultLoader) wrapHandlerWithEvents(toolName string, handler ai.Handle
 					executionID = id
 				}
 			}
-			filteredParams := make(map[string]any)
-			for k, v := range params {
-				if !strings.HasPrefix(k, "_") {
-					filteredParams[k] = v
-				}
-			}
 			startEvent := events.ToolStartingEvent{
 				ExecutionID: executionID,
+				RequestID:   requestID(ctx),
 				ToolName:    toolName,
-				Parameters:  filteredParams,
+				Parameters:  eventParams(params, redact),
 			}
 			l.Publisher.PublishSync(startEvent.Topic(), startEvent)
 		}
 
-		// Execute the original handler, converting panics into errors:
-		// in streaming mode handlers run inside producer goroutines,
-		// where an unrecovered panic would crash the whole process.
-		result, err := func() (result map[string]any, err error) {
-			defer func() {
-				if r := recover(); r != nil {
-					result = nil
-					err = fmt.Errorf("tool %s panicked: %v\n%s", toolName, r, debug.Stack())
-				}
-			}()
-			return handler(ctx, params)
-		}()
+		timeout := l.ToolTimeouts.For(toolName)
+		var timedOut bool
+		if blocked != nil {
+			// The model sees why, as it does a denied confirmation
+			result = map[string]any{"success": false, "error": blocked.Error()}
+		} else {
+			result, timedOut, err = runToolHandler(ctx, toolName, handler, params, timeout)
+			result = lifecycle.RunPostTool(ctx, toolName, params, result, err == nil)
+		}
+		cancelled := ctx != nil && ctx.Err() != nil
+		span.SetAttributes(
+			attribute.Bool("genie.tool.cancelled", cancelled),
+			attribute.Bool("genie.tool.timed_out", timedOut),
+		)
+
+		// Keep oversized output out of the model context; the full text
+		// stays on disk behind a handle. Pages of stored output are
+		// already cut to size and would only be stored again.
+		var outputHandles []string
+		if err == nil && l.OutputStore != nil && result != nil && toolName != "readToolOutput" {
+			truncated, handles, truncErr := l.OutputStore.Truncate(result)
+			if truncErr != nil {
+				slog.Warn("failed to store oversized tool output", "tool", toolName, "error", truncErr)
+			} else {
+				result, outputHandles = truncated, handles
+			}
+		}
 
 		// Create a message based on the tool and result
 		var message string
-		if err != nil {
+		switch {
+		case blocked != nil:
+			message = "Blocked by hook"
+		case cancelled:
+			message = "Cancelled"
+		case timedOut:
+			message = fmt.Sprintf("Timed out after %s", timeout)
+		case err != nil:
 			message = fmt.Sprintf("Failed: %v", err)
-		} else {
+		default:
 			message = "Executed"
 		}
 
@@ -274,21 +476,18 @@ func (l *DefaultLoader) wrapHandlerWithEvents(toolName string, handler ai.Handle
 				}
 			}
 
-			// Filter out parameters starting with "_"
-			filteredParams := make(map[string]any)
-			for k, v := range params {
-				if !strings.HasPrefix(k, "_") {
-					filteredParams[k] = v
-				}
-			}
-
 			event := events.ToolExecutedEvent{
 				ExecutionID: executionID,
+				RequestID:   requestID(ctx),
 				ToolName:    toolName,
-				Parameters:  filteredParams, // Use filtered parameters
-				Success:     err == nil,
+				Parameters:  eventParams(params, redact),
+				Success:     err == nil && blocked == nil && !cancelled && !timedOut,
+				Cancelled:   cancelled,
+				TimedOut:    timedOut,
 				Message:     message,
 				Result:      result,
+
+				OutputHandles: outputHandles,
 			}
 			l.Publisher.PublishSync(event.Topic(), event)
 		}
@@ -3,9 +3,16 @@ package prompts
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/hooks"
+	"github.com/kcaldas/genie/pkg/tools"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,7 +40,7 @@ func TestWrapHandlerWithEventsPublishesTypedOutcome(t *testing.T) {
 			loader := &DefaultLoader{Publisher: bus}
 			handler := loader.wrapHandlerWithEvents("myTool", func(ctx context.Context, params map[string]any) (map[string]any, error) {
 				return map[string]any{"ok": true}, tt.handlerErr
-			})
+			}, nil)
 
 			_, err := handler(context.Background(), map[string]any{})
 			if tt.handlerErr != nil {
@@ -65,7 +72,7 @@ func TestWrapHandlerWithEventsRecoversPanics(t *testing.T) {
 	loader := &DefaultLoader{Publisher: bus}
 	handler := loader.wrapHandlerWithEvents("explodingTool", func(ctx context.Context, params map[string]any) (map[string]any, error) {
 		panic("nil map write on unexpected params")
-	})
+	}, nil)
 
 	result, err := handler(context.Background(), map[string]any{})
 	require.Error(t, err, "panic must surface as an error")
@@ -75,3 +82,203 @@ func TestWrapHandlerWithEventsRecoversPanics(t *testing.T) {
 	require.Len(t, executed, 1, "the failed execution must still be reported")
 	assert.False(t, executed[0].Success)
 }
+
+// Cancelling the request must stop waiting for the tool and mark the
+// execution as cancelled, whether or not the tool honors its context.
+func TestWrapHandlerWithEventsReportsCancellation(t *testing.T) {
+	defer func(grace time.Duration) { toolCancelGrace = grace }(toolCancelGrace)
+	toolCancelGrace = 20 * time.Millisecond
+
+	handlers := map[string]func(ctx context.Context, params map[string]any) (map[string]any, error){
+		"honors context": func(ctx context.Context, params map[string]any) (map[string]any, error) {
+			<-ctx.Done()
+			return map[string]any{"success": false, "results": "partial"}, nil
+		},
+		"ignores context": func(ctx context.Context, params map[string]any) (map[string]any, error) {
+			time.Sleep(time.Second)
+			return map[string]any{"success": true}, nil
+		},
+	}
+	for name, tool := range handlers {
+		t.Run(name, func(t *testing.T) {
+			bus := events.NewEventBus()
+			var executed []events.ToolExecutedEvent
+			events.SubscribeTo(bus, func(e events.ToolExecutedEvent) {
+				executed = append(executed, e)
+			})
+
+			loader := &DefaultLoader{Publisher: bus}
+			handler := loader.wrapHandlerWithEvents("slowTool", tool, nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+			start := time.Now()
+			_, _ = handler(ctx, map[string]any{})
+			assert.Less(t, time.Since(start), 500*time.Millisecond, "cancellation must not wait for the tool to finish")
+
+			require.Len(t, executed, 1)
+			assert.True(t, executed[0].Cancelled)
+			assert.False(t, executed[0].Success)
+			assert.Equal(t, "Cancelled", executed[0].Message)
+		})
+	}
+}
+
+func TestWrapHandlerWithEventsReportsTimeout(t *testing.T) {
+	defer func(grace time.Duration) { toolCancelGrace = grace }(toolCancelGrace)
+	toolCancelGrace = 20 * time.Millisecond
+
+	handlers := map[string]func(ctx context.Context, params map[string]any) (map[string]any, error){
+		"honors context": func(ctx context.Context, params map[string]any) (map[string]any, error) {
+			<-ctx.Done()
+			return map[string]any{"success": false, "error": "stopped"}, nil
+		},
+		"ignores context": func(ctx context.Context, params map[string]any) (map[string]any, error) {
+			time.Sleep(time.Second)
+			return map[string]any{"success": true}, nil
+		},
+	}
+	for name, tool := range handlers {
+		t.Run(name, func(t *testing.T) {
+			bus := events.NewEventBus()
+			var executed []events.ToolExecutedEvent
+			events.SubscribeTo(bus, func(e events.ToolExecutedEvent) {
+				executed = append(executed, e)
+			})
+
+			loader := &DefaultLoader{
+				Publisher:    bus,
+				ToolTimeouts: ToolTimeouts{Default: time.Hour, PerTool: map[string]time.Duration{"slowTool": 10 * time.Millisecond}},
+			}
+			handler := loader.wrapHandlerWithEvents("slowTool", tool, nil)
+
+			start := time.Now()
+			_, _ = handler(context.Background(), map[string]any{})
+			assert.Less(t, time.Since(start), 500*time.Millisecond, "the timeout must not wait for the tool to finish")
+
+			require.Len(t, executed, 1)
+			assert.True(t, executed[0].TimedOut)
+			assert.False(t, executed[0].Cancelled)
+			assert.False(t, executed[0].Success)
+			assert.Equal(t, "Timed out after 10ms", executed[0].Message)
+		})
+	}
+}
+
+// Oversized tool output must reach the model truncated, with a handle
+// the UI can use to expand it.
+func TestWrapHandlerWithEventsTruncatesLargeOutput(t *testing.T) {
+	bus := events.NewEventBus()
+	var executed []events.ToolExecutedEvent
+	events.SubscribeTo(bus, func(e events.ToolExecutedEvent) {
+		executed = append(executed, e)
+	})
+
+	store := tools.NewOutputStore(t.TempDir(), 10)
+	loader := &DefaultLoader{Publisher: bus, OutputStore: store}
+	full := strings.Repeat("x", 100)
+	handler := loader.wrapHandlerWithEvents("bigTool", func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		return map[string]any{"output": full}, nil
+	}, nil)
+
+	result, err := handler(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Less(t, len(result["output"].(string)), len(full)+200)
+	assert.True(t, strings.HasPrefix(result["output"].(string), strings.Repeat("x", 10)+"\n"))
+
+	require.Len(t, executed, 1)
+	require.Len(t, executed[0].OutputHandles, 1)
+	stored, err := store.Load(executed[0].OutputHandles[0])
+	require.NoError(t, err)
+	assert.Equal(t, full, stored)
+}
+
+// Pages read back with readToolOutput reach the model whole, even when
+// the store pages with a larger limit than the loader truncates at.
+func TestWrapHandlerWithEventsLeavesReadToolOutputPages(t *testing.T) {
+	dir := t.TempDir()
+	pager := tools.NewOutputStore(dir, 40)
+	_, handles, err := pager.Truncate(map[string]any{"output": strings.Repeat("y", 100)})
+	require.NoError(t, err)
+
+	loader := &DefaultLoader{Publisher: events.NewEventBus(), OutputStore: tools.NewOutputStore(dir, 10)}
+	handler := loader.wrapHandlerWithEvents("readToolOutput", tools.NewReadToolOutputTool(pager).Handler(), nil)
+
+	result, err := handler(context.Background(), map[string]any{"handle": handles[0]})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("y", 40), result["content"])
+	assert.NotContains(t, result, "truncated")
+}
+
+func TestWrapHandlerWithEventsPublishesRedactedParameters(t *testing.T) {
+	bus := events.NewEventBus()
+	var started []events.ToolStartingEvent
+	var executed []events.ToolExecutedEvent
+	events.SubscribeTo(bus, func(e events.ToolStartingEvent) { started = append(started, e) })
+	events.SubscribeTo(bus, func(e events.ToolExecutedEvent) { executed = append(executed, e) })
+
+	var got map[string]any
+	loader := &DefaultLoader{Publisher: bus}
+	handler := loader.wrapHandlerWithEvents("httpRequest", func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		got = params
+		return map[string]any{"success": true}, nil
+	}, tools.NewHTTPRequestTool(nil).(tools.ParameterRedactor).RedactParameters)
+
+	_, err := handler(context.Background(), map[string]any{
+		"url":     "http://localhost:8080",
+		"headers": map[string]any{"Authorization": "Bearer s3cret"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"Authorization": "Bearer s3cret"}, got["headers"], "the handler gets the real headers")
+	require.Len(t, started, 1)
+	require.Len(t, executed, 1)
+	assert.Equal(t, map[string]any{"Authorization": "Bearer [REDACTED]"}, started[0].Parameters["headers"])
+	assert.Equal(t, map[string]any{"Authorization": "Bearer [REDACTED]"}, executed[0].Parameters["headers"])
+}
+
+// pre_tool hooks run before the handler and can block it; post_tool hooks
+// see and may annotate its result.
+func TestWrapHandlerWithEventsRunsLifecycleHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks are POSIX shell commands")
+	}
+	project := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".genie"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".genie", hooks.ConfigFileName), []byte(`
+pre_tool:
+  - command: "grep -q 'rm -rf' && { echo destructive >&2; exit 2; }; exit 0"
+post_tool:
+  - command: "echo '{\"result\": {\"output\": \"checked\"}}'"
+`), 0o644))
+	config, err := hooks.LoadConfig(project, t.TempDir(), false)
+	require.NoError(t, err)
+	ctx := hooks.WithConfig(context.Background(), config)
+
+	bus := events.NewEventBus()
+	var executed []events.ToolExecutedEvent
+	events.SubscribeTo(bus, func(e events.ToolExecutedEvent) {
+		executed = append(executed, e)
+	})
+	calls := 0
+	loader := &DefaultLoader{Publisher: bus}
+	handler := loader.wrapHandlerWithEvents("bash", func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		calls++
+		return map[string]any{"output": "done"}, nil
+	}, nil)
+
+	result, err := handler(ctx, map[string]any{"command": "ls"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"output": "checked"}, result)
+
+	result, err = handler(ctx, map[string]any{"command": "rm -rf /"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "a blocked tool must not run")
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "destructive")
+
+	require.Len(t, executed, 2)
+	assert.True(t, executed[0].Success)
+	assert.False(t, executed[1].Success)
+	assert.Equal(t, "Blocked by hook", executed[1].Message)
+}
@@ -2,6 +2,7 @@ package prompts
 
 import (
 	"context"
+	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"github.com/kcaldas/genie/pkg/tools"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockGen implements ai.Gen for testing
@@ -365,6 +367,32 @@ required_tools:
 	assert.Len(t, prompt.Functions, 2, "Should have 2 tools")
 }
 
+// TestPromptLoader_LoadPromptFromBytes_OutputControls tests the output
+// settings, including max_output_tokens as another name for max_tokens
+func TestPromptLoader_LoadPromptFromBytes_OutputControls(t *testing.T) {
+	publisher := &events.NoOpPublisher{}
+	eventBus := &events.NoOpEventBus{}
+	todoManager := tools.NewTodoManager()
+	toolRegistry := tools.NewDefaultRegistry(eventBus, todoManager, nil, nil)
+	loader := NewPromptLoader(publisher, toolRegistry).(*DefaultLoader)
+
+	yamlContent := []byte(`name: "terse"
+instruction: "Answer in one line."
+text: "{{.message}}"
+max_tokens: 4000
+max_output_tokens: 200
+stop_sequences: ["\n\n", "END"]
+frequency_penalty: 0.4
+presence_penalty: -0.2`)
+
+	prompt, err := loader.LoadPromptFromBytes(yamlContent)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(200), prompt.MaxTokens, "max_output_tokens wins over max_tokens")
+	assert.Equal(t, []string{"\n\n", "END"}, prompt.StopSequences)
+	assert.Equal(t, float32(0.4), prompt.FrequencyPenalty)
+	assert.Equal(t, float32(-0.2), prompt.PresencePenalty)
+}
+
 // TestPromptLoader_LoadPromptFromBytes_InvalidYAML tests error handling for invalid YAML
 func TestPromptLoader_LoadPromptFromBytes_InvalidYAML(t *testing.T) {
 	publisher := &events.NoOpPublisher{}
@@ -400,6 +428,42 @@ required_tools:
 	assert.Empty(t, prompt.Functions)
 }
 
+// unavailableTool is a registered tool whose dependency is missing
+type unavailableTool struct {
+	MockTool
+}
+
+func (u *unavailableTool) CheckAvailable() error {
+	return errors.New("the foo CLI is not installed")
+}
+
+func TestPromptLoader_LoadPromptFromBytes_UnavailableTools(t *testing.T) {
+	registry := tools.NewRegistry()
+	require.NoError(t, registry.Register(&MockTool{name: "readFile"}))
+	require.NoError(t, registry.Register(&unavailableTool{MockTool{name: "foo"}}))
+	require.NoError(t, registry.RegisterToolSet("bar", []tools.Tool{&unavailableTool{MockTool{name: "bar_list"}}}))
+	loader := NewPromptLoader(&events.NoOpPublisher{}, registry).(*DefaultLoader)
+
+	prompt, err := loader.LoadPromptFromBytes([]byte(`name: "degraded"
+instruction: "Test"
+text: "{{.message}}"
+required_tools:
+  - "readFile"
+  - "foo"
+  - "@bar"
+  - "ghost"`))
+	require.NoError(t, err)
+
+	require.Len(t, prompt.Functions, 1)
+	assert.Equal(t, "readFile", prompt.Functions[0].Name)
+	assert.NotContains(t, prompt.Handlers, "foo")
+	assert.Equal(t, []string{"foo", "@bar", "ghost"}, prompt.MissingTools)
+	assert.Equal(t, map[string]string{
+		"foo":  "the foo CLI is not installed",
+		"@bar": "the foo CLI is not installed",
+	}, prompt.MissingToolReasons)
+}
+
 // TestPromptLoader_LoadPromptFromBytes_AppliesModelDefaults tests that model defaults are applied
 func TestPromptLoader_LoadPromptFromBytes_AppliesModelDefaults(t *testing.T) {
 	publisher := &events.NoOpPublisher{}
@@ -0,0 +1,59 @@
+package prompts
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+const (
+	// ToolTimeoutConfigKey sets how long any single tool call may run.
+	ToolTimeoutConfigKey = "GENIE_TOOL_TIMEOUT"
+	// ToolTimeoutsConfigKey overrides ToolTimeoutConfigKey for individual
+	// tools, as comma-separated name=duration pairs ("bash=20m,runAgent=0").
+	ToolTimeoutsConfigKey = "GENIE_TOOL_TIMEOUTS"
+
+	// DefaultToolTimeout is the limit applied when none is configured.
+	DefaultToolTimeout = 10 * time.Minute
+)
+
+// ToolTimeouts holds the execution limit for each tool. A zero duration
+// means the tool may run until its turn ends.
+type ToolTimeouts struct {
+	Default time.Duration
+	PerTool map[string]time.Duration
+}
+
+// ToolTimeoutsFromConfig reads the default and per-tool limits. Malformed
+// overrides are logged and ignored rather than failing startup.
+func ToolTimeoutsFromConfig(cfg config.Manager) ToolTimeouts {
+	timeouts := ToolTimeouts{
+		Default: cfg.GetDurationWithDefault(ToolTimeoutConfigKey, DefaultToolTimeout),
+		PerTool: make(map[string]time.Duration),
+	}
+	for _, entry := range strings.Split(cfg.GetStringWithDefault(ToolTimeoutsConfigKey, ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || name == "" || err != nil || d < 0 {
+			slog.Warn("Ignoring invalid tool timeout", "key", ToolTimeoutsConfigKey, "entry", entry)
+			continue
+		}
+		timeouts.PerTool[name] = d
+	}
+	return timeouts
+}
+
+// For returns the limit for toolName, or zero when it has none.
+func (t ToolTimeouts) For(toolName string) time.Duration {
+	if d, ok := t.PerTool[toolName]; ok {
+		return d
+	}
+	return t.Default
+}
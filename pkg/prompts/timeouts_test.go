@@ -0,0 +1,29 @@
+package prompts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolTimeoutsFromConfig(t *testing.T) {
+	t.Setenv(ToolTimeoutConfigKey, "5m")
+	t.Setenv(ToolTimeoutsConfigKey, " bash=20m, runAgent=0 ,broken, readFile=soon")
+
+	timeouts := ToolTimeoutsFromConfig(config.NewConfigManager())
+
+	assert.Equal(t, 20*time.Minute, timeouts.For("bash"))
+	assert.Zero(t, timeouts.For("runAgent"), "an override of 0 removes the limit")
+	assert.Equal(t, 5*time.Minute, timeouts.For("readFile"), "invalid overrides fall back to the default")
+	assert.Equal(t, 5*time.Minute, timeouts.For("listFiles"))
+}
+
+func TestToolTimeoutsDefault(t *testing.T) {
+	t.Setenv(ToolTimeoutConfigKey, "")
+	t.Setenv(ToolTimeoutsConfigKey, "")
+
+	assert.Equal(t, DefaultToolTimeout, ToolTimeoutsFromConfig(config.NewConfigManager()).For("bash"))
+	assert.Zero(t, ToolTimeouts{}.For("bash"))
+}
@@ -0,0 +1,117 @@
+package scripting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"sort"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/tools/process"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// eventTable turns an event into the table handlers receive
+func eventTable(L *lua.LState, event interface{}) *lua.LTable {
+	fields := map[string]any{}
+	switch e := event.(type) {
+	case events.ToolStartingEvent:
+		fields = map[string]any{
+			"request_id": e.RequestID,
+			"tool":       e.ToolName,
+			"params":     e.Parameters,
+		}
+	case events.ToolExecutedEvent:
+		fields = map[string]any{
+			"request_id": e.RequestID,
+			"tool":       e.ToolName,
+			"params":     e.Parameters,
+			"success":    e.Success,
+			"message":    e.Message,
+			"result":     e.Result,
+		}
+	case events.ChatStartedEvent:
+		fields = map[string]any{
+			"request_id": e.RequestID,
+			"message":    e.Message,
+		}
+	case events.ChatResponseEvent:
+		fields = map[string]any{
+			"request_id": e.RequestID,
+			"message":    e.Message,
+			"response":   e.Response,
+		}
+		if e.Error != nil {
+			fields["error"] = e.Error.Error()
+		}
+	}
+	return toLua(L, fields).(*lua.LTable)
+}
+
+// toLua converts a Go value decoded from JSON, or built from maps, slices
+// and scalars, into a Lua value. Lists become 1-based arrays.
+func toLua(L *lua.LState, value any) lua.LValue {
+	switch v := value.(type) {
+	case nil:
+		return lua.LNil
+	case string:
+		return lua.LString(v)
+	case bool:
+		return lua.LBool(v)
+	case int:
+		return lua.LNumber(v)
+	case int64:
+		return lua.LNumber(v)
+	case float64:
+		return lua.LNumber(v)
+	case error:
+		return lua.LString(v.Error())
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		table := L.NewTable()
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			table.RawSetString(fmt.Sprint(key.Interface()), toLua(L, rv.MapIndex(key).Interface()))
+		}
+		return table
+	case reflect.Slice, reflect.Array:
+		table := L.NewTable()
+		for i := 0; i < rv.Len(); i++ {
+			table.RawSetInt(i+1, toLua(L, rv.Index(i).Interface()))
+		}
+		return table
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return lua.LNumber(rv.Convert(reflect.TypeOf(float64(0))).Float())
+	case reflect.Float32:
+		return lua.LNumber(rv.Float())
+	default:
+		return lua.LString(fmt.Sprint(value))
+	}
+}
+
+// run runs command in the user's shell in dir and returns its combined
+// output and exit code; -1 when it could not run or was stopped. Unlike the
+// bash tool it skips the login profile: handlers run often, and Genie's own
+// environment already has the user's PATH.
+func run(ctx context.Context, dir, command string) (string, int) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := process.DefaultShell().Command(ctx, command, false)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return string(output), 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return string(output), exitErr.ExitCode()
+	}
+	return string(output) + err.Error(), -1
+}
@@ -0,0 +1,257 @@
+// Package scripting runs the user's Lua hook scripts: .genie/hooks/*.lua
+// files that react to Genie's events, for automations such as formatting
+// every file the model writes, without writing Go.
+//
+// A script registers handlers with genie.on when it loads:
+//
+//	genie.on("tool.executed", function(event)
+//	  if event.tool == "writeFile" and event.success and event.params.path:match("%.go$") then
+//	    local output, code = genie.run("gofmt -w " .. event.params.path)
+//	    if code ~= 0 then genie.notify("gofmt failed: " .. output) end
+//	  end
+//	end)
+//
+// Scripts can also call genie.run(command), which runs a shell command in
+// the working directory and returns its output and exit code,
+// genie.notify(message), which shows a message in the transcript, and
+// genie.log(message). genie.working_dir is the session's working
+// directory. Lua's standard libraries are available too.
+package scripting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/events"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// ConfigKey turns hook scripts off when set to false (default on).
+	ConfigKey = "GENIE_HOOK_SCRIPTS"
+
+	// TimeoutConfigKey sets how long loading a script or one run of a
+	// handler may take (default 30s).
+	TimeoutConfigKey = "GENIE_HOOK_SCRIPT_TIMEOUT"
+
+	// DefaultTimeout is the default of TimeoutConfigKey.
+	DefaultTimeout = 30 * time.Second
+
+	// DirName is the directory under .genie holding the scripts.
+	DirName = "hooks"
+)
+
+// Topics are the events scripts can handle.
+var Topics = []string{
+	events.ToolStartingEvent{}.Topic(),
+	events.ToolExecutedEvent{}.Topic(),
+	events.ChatStartedEvent{}.Topic(),
+	events.ChatResponseEvent{}.Topic(),
+}
+
+// Engine runs a set of loaded scripts.
+type Engine struct {
+	scripts []*script
+}
+
+// script is one loaded file. A Lua state is not safe for concurrent use,
+// so its handlers run one at a time.
+type script struct {
+	name        string
+	mu          sync.Mutex
+	state       *lua.LState
+	handlers    map[string][]*lua.LFunction
+	unsubscribe []func()
+}
+
+// Options configure an Engine.
+type Options struct {
+	// WorkingDir is where genie.run runs commands
+	WorkingDir string
+	// Timeout bounds loading a script and each run of a handler
+	Timeout time.Duration
+}
+
+// Dir returns the scripts directory of the .genie directory under root.
+func Dir(root string) string {
+	return filepath.Join(root, ".genie", DirName)
+}
+
+// Load runs the .lua files in dirs, in name order per directory, and
+// subscribes their handlers to bus. A missing directory has no scripts; a
+// script that fails to load is reported in the returned errors without
+// stopping the others.
+func Load(bus events.EventBus, opts Options, dirs ...string) (*Engine, []error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	engine := &Engine{}
+	var errs []error
+	for _, dir := range dirs {
+		paths, err := scriptPaths(dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, path := range paths {
+			s, err := load(bus, opts, path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			engine.scripts = append(engine.scripts, s)
+		}
+	}
+	return engine, errs
+}
+
+// Len returns the number of loaded scripts.
+func (e *Engine) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.scripts)
+}
+
+// Close unsubscribes the scripts' handlers and releases their Lua states.
+func (e *Engine) Close() {
+	if e == nil {
+		return
+	}
+	for _, s := range e.scripts {
+		for _, unsubscribe := range s.unsubscribe {
+			unsubscribe()
+		}
+		s.mu.Lock()
+		s.state.Close()
+		s.mu.Unlock()
+	}
+	e.scripts = nil
+}
+
+func scriptPaths(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook scripts directory: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".lua") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func load(bus events.EventBus, opts Options, path string) (*script, error) {
+	s := &script{
+		name:     filepath.Base(path),
+		state:    lua.NewState(),
+		handlers: make(map[string][]*lua.LFunction),
+	}
+	s.state.SetGlobal("genie", s.module(bus, opts))
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	s.state.SetContext(ctx)
+	err := s.state.DoFile(path)
+	s.state.RemoveContext()
+	if err != nil {
+		s.state.Close()
+		return nil, fmt.Errorf("hook script %s: %w", path, err)
+	}
+
+	for _, topic := range Topics {
+		if len(s.handlers[topic]) == 0 {
+			continue
+		}
+		topic := topic
+		s.unsubscribe = append(s.unsubscribe, events.SubscribeAsync(bus, topic, 0, func(event interface{}) {
+			s.handle(bus, opts.Timeout, topic, event)
+		}))
+	}
+	return s, nil
+}
+
+// module builds the genie table scripts call into
+func (s *script) module(bus events.EventBus, opts Options) *lua.LTable {
+	mod := s.state.NewTable()
+	s.state.SetFuncs(mod, map[string]lua.LGFunction{
+		"on": func(L *lua.LState) int {
+			topic := L.CheckString(1)
+			handler := L.CheckFunction(2)
+			if !knownTopic(topic) {
+				L.ArgError(1, fmt.Sprintf("unknown event %q; scripts can handle %s", topic, strings.Join(Topics, ", ")))
+				return 0
+			}
+			s.handlers[topic] = append(s.handlers[topic], handler)
+			return 0
+		},
+		"run": func(L *lua.LState) int {
+			output, code := run(L.Context(), opts.WorkingDir, L.CheckString(1))
+			L.Push(lua.LString(output))
+			L.Push(lua.LNumber(code))
+			return 2
+		},
+		"notify": func(L *lua.LState) int {
+			bus.Publish(events.NotificationEvent{}.Topic(), events.NotificationEvent{
+				Message:     fmt.Sprintf("[%s] %s", s.name, L.CheckString(1)),
+				Role:        "system",
+				ContentType: "text",
+			})
+			return 0
+		},
+		"log": func(L *lua.LState) int {
+			slog.Info("Hook script", "script", s.name, "message", L.CheckString(1))
+			return 0
+		},
+	})
+	mod.RawSetString("working_dir", lua.LString(opts.WorkingDir))
+	return mod
+}
+
+// handle runs the script's handlers for event, one at a time. A failing
+// handler is reported in the transcript and the others still run.
+func (s *script) handle(bus events.EventBus, timeout time.Duration, topic string, event interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state.IsClosed() {
+		return
+	}
+	for _, handler := range s.handlers[topic] {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		s.state.SetContext(ctx)
+		err := s.state.CallByParam(lua.P{Fn: handler, NRet: 0, Protect: true}, eventTable(s.state, event))
+		s.state.RemoveContext()
+		cancel()
+		if err != nil {
+			slog.Warn("Hook script failed", "script", s.name, "event", topic, "error", err)
+			bus.Publish(events.NotificationEvent{}.Topic(), events.NotificationEvent{
+				Message: fmt.Sprintf("Hook script %s failed on %s: %v", s.name, topic, err),
+				Role:    "error",
+				Error:   err,
+			})
+		}
+	}
+}
+
+func knownTopic(topic string) bool {
+	for _, known := range Topics {
+		if topic == known {
+			return true
+		}
+	}
+	return false
+}
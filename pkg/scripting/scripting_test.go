@@ -0,0 +1,131 @@
+package scripting
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, dir, name, source string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(source), 0o644))
+}
+
+func notifications(bus events.EventBus) <-chan events.NotificationEvent {
+	ch := make(chan events.NotificationEvent, 10)
+	events.SubscribeTo(bus, func(e events.NotificationEvent) { ch <- e })
+	return ch
+}
+
+func receive(t *testing.T, ch <-chan events.NotificationEvent) events.NotificationEvent {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+		return events.NotificationEvent{}
+	}
+}
+
+func TestLoad_HandlesToolExecuted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	dir := t.TempDir()
+	workingDir := t.TempDir()
+	writeScript(t, dir, "format.lua", `
+genie.on("tool.executed", function(event)
+  if event.tool == "writeFile" and event.success then
+    local output, code = genie.run("echo formatted " .. event.params.path .. " > done.txt; echo ok")
+    genie.notify(event.params.path .. " " .. event.result.bytes .. " " .. code .. " " .. output)
+  end
+end)
+`)
+	bus := events.NewEventBus()
+	got := notifications(bus)
+	engine, errs := Load(bus, Options{WorkingDir: workingDir}, dir, filepath.Join(dir, "missing"))
+	require.Empty(t, errs)
+	defer engine.Close()
+	assert.Equal(t, 1, engine.Len())
+
+	bus.PublishSync("tool.executed", events.ToolExecutedEvent{ToolName: "readFile", Success: true})
+	bus.PublishSync("tool.executed", events.ToolExecutedEvent{
+		ToolName:   "writeFile",
+		Success:    true,
+		Parameters: map[string]any{"path": "main.go"},
+		Result:     map[string]any{"bytes": 42},
+	})
+
+	e := receive(t, got)
+	assert.Equal(t, "system", e.Role)
+	assert.Equal(t, "[format.lua] main.go 42 0 ok\n", e.Message)
+	written, err := os.ReadFile(filepath.Join(workingDir, "done.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "formatted main.go\n", string(written))
+}
+
+func TestLoad_ReportsBadScripts(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "a_syntax.lua", `genie.on("chat.response", function(`)
+	writeScript(t, dir, "b_topic.lua", `genie.on("file.saved", function() end)`)
+	writeScript(t, dir, "c_good.lua", `genie.on("chat.response", function() end)`)
+	writeScript(t, dir, "notes.txt", `not a script`)
+
+	engine, errs := Load(events.NewEventBus(), Options{}, dir)
+	defer engine.Close()
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "a_syntax.lua")
+	assert.Contains(t, errs[1].Error(), `unknown event "file.saved"`)
+	assert.Equal(t, 1, engine.Len())
+}
+
+func TestHandler_ErrorsAndTimeouts(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "fail.lua", `
+genie.on("chat.response", function(event)
+  if event.error then error("saw " .. event.error) end
+  while true do end
+end)
+`)
+	bus := events.NewEventBus()
+	got := notifications(bus)
+	engine, errs := Load(bus, Options{Timeout: 100 * time.Millisecond}, dir)
+	require.Empty(t, errs)
+	defer engine.Close()
+
+	bus.Publish("chat.response", events.ChatResponseEvent{Error: assert.AnError})
+	e := receive(t, got)
+	assert.Equal(t, "error", e.Role)
+	assert.Contains(t, e.Message, "fail.lua failed on chat.response")
+	assert.Contains(t, e.Message, "saw "+assert.AnError.Error())
+
+	bus.Publish("chat.response", events.ChatResponseEvent{Response: "done"})
+	e = receive(t, got)
+	assert.Equal(t, "error", e.Role)
+	assert.Contains(t, e.Message, "context deadline exceeded")
+}
+
+func TestClose_Unsubscribes(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "notify.lua", `genie.on("chat.started", function(event) genie.notify(event.message) end)`)
+	bus := events.NewEventBus()
+	got := notifications(bus)
+	engine, errs := Load(bus, Options{}, dir)
+	require.Empty(t, errs)
+
+	engine.Close()
+	bus.Publish("chat.started", events.ChatStartedEvent{Message: "hello"})
+	select {
+	case e := <-got:
+		t.Fatalf("unexpected notification %q", e.Message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package sessions
+
+import "os"
+
+// processAlive reports whether a process with pid exists; on Windows
+// FindProcess opens the process and fails when there is none.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
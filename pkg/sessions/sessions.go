@@ -0,0 +1,229 @@
+// Package sessions keeps the registry of named Genie sessions.
+//
+// A named session ties a name such as backend-api to a working directory,
+// so several projects can each have their own Genie running at once and be
+// picked up again by name with genie attach. While a process has a session
+// attached, the registry records its PID so a second Genie does not attach
+// the same session, and genie --pipe routes requests naming a session to a
+// Genie started in its directory.
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileName is the registry file inside ~/.genie.
+const FileName = "sessions.json"
+
+// Modes a session can be attached in.
+const (
+	ModeTUI  = "tui"
+	ModePipe = "pipe"
+)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Session is one named session. PID, Mode and AttachedAt are set while a
+// process has it attached.
+type Session struct {
+	Name       string    `json:"name"`
+	WorkingDir string    `json:"working_dir"`
+	PID        int       `json:"pid,omitempty"`
+	Mode       string    `json:"mode,omitempty"`
+	AttachedAt time.Time `json:"attached_at,omitempty"`
+	LastUsed   time.Time `json:"last_used"`
+}
+
+// Active reports whether a running process has the session attached. A
+// session whose process died without detaching is not active.
+func (s Session) Active() bool {
+	return s.PID != 0 && processAlive(s.PID)
+}
+
+type registryFile struct {
+	Sessions map[string]Session `json:"sessions"`
+}
+
+// Registry persists named sessions in a JSON file.
+type Registry struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewRegistry returns a registry backed by the file at path. The file is
+// created on the first Attach.
+func NewRegistry(path string) *Registry {
+	return &Registry{path: path}
+}
+
+// DefaultRegistry returns the registry in ~/.genie/sessions.json.
+func DefaultRegistry() (*Registry, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return NewRegistry(filepath.Join(home, ".genie", FileName)), nil
+}
+
+// ValidateName checks that name can name a session: up to 64 letters,
+// digits, dots, dashes and underscores, starting with a letter or digit.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid session name %q: use up to 64 letters, digits, '.', '-' and '_', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// Get returns the session called name.
+func (r *Registry) Get(name string) (Session, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, err := r.load()
+	if err != nil {
+		return Session{}, false, err
+	}
+	session, ok := file.Sessions[name]
+	return session, ok, nil
+}
+
+// List returns every session, sorted by name.
+func (r *Registry) List() ([]Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Session, 0, len(file.Sessions))
+	for _, session := range file.Sessions {
+		list = append(list, session)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Attach marks the session called name as attached by this process in
+// mode, creating it when it is new. A non-empty workingDir becomes the
+// session's directory; an empty one keeps the directory it has, so new
+// sessions need one. It fails when another running process has the
+// session attached.
+func (r *Registry) Attach(name, workingDir, mode string) (Session, error) {
+	if err := ValidateName(name); err != nil {
+		return Session{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, err := r.load()
+	if err != nil {
+		return Session{}, err
+	}
+
+	session, exists := file.Sessions[name]
+	if exists && session.Active() && session.PID != os.Getpid() {
+		return Session{}, fmt.Errorf("session %q is already attached by process %d (%s)", name, session.PID, session.Mode)
+	}
+	if workingDir != "" {
+		if workingDir, err = filepath.Abs(workingDir); err != nil {
+			return Session{}, fmt.Errorf("failed to resolve working directory: %w", err)
+		}
+		session.WorkingDir = workingDir
+	}
+	if session.WorkingDir == "" {
+		return Session{}, fmt.Errorf("no session named %q; give it a working directory to create it", name)
+	}
+
+	now := time.Now().UTC()
+	session.Name = name
+	session.PID = os.Getpid()
+	session.Mode = mode
+	session.AttachedAt = now
+	session.LastUsed = now
+	file.Sessions[name] = session
+	return session, r.save(file)
+}
+
+// Detach marks the session called name as no longer attached, if this
+// process attached it.
+func (r *Registry) Detach(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, err := r.load()
+	if err != nil {
+		return err
+	}
+	session, ok := file.Sessions[name]
+	if !ok || session.PID != os.Getpid() {
+		return nil
+	}
+	session.PID = 0
+	session.Mode = ""
+	session.AttachedAt = time.Time{}
+	session.LastUsed = time.Now().UTC()
+	file.Sessions[name] = session
+	return r.save(file)
+}
+
+// Remove forgets the session called name. An attached session cannot be
+// removed.
+func (r *Registry) Remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, err := r.load()
+	if err != nil {
+		return err
+	}
+	session, ok := file.Sessions[name]
+	if !ok {
+		return fmt.Errorf("no session named %q", name)
+	}
+	if session.Active() {
+		return fmt.Errorf("session %q is attached by process %d; quit it first", name, session.PID)
+	}
+	delete(file.Sessions, name)
+	return r.save(file)
+}
+
+func (r *Registry) load() (*registryFile, error) {
+	file := &registryFile{Sessions: make(map[string]Session)}
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session registry %s: %w", r.path, err)
+	}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse session registry %s: %w", r.path, err)
+	}
+	if file.Sessions == nil {
+		file.Sessions = make(map[string]Session)
+	}
+	return file, nil
+}
+
+func (r *Registry) save(file *registryFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session registry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create session registry directory: %w", err)
+	}
+	// Write to a temp file and rename so a crash never leaves a truncated registry
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session registry: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to write session registry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package sessions
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_AttachAndDetach(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), "genie", FileName))
+	dir := t.TempDir()
+
+	_, err := registry.Attach("backend-api", "", ModeTUI)
+	assert.ErrorContains(t, err, "give it a working directory")
+
+	session, err := registry.Attach("backend-api", dir, ModeTUI)
+	require.NoError(t, err)
+	assert.Equal(t, dir, session.WorkingDir)
+	assert.Equal(t, os.Getpid(), session.PID)
+	assert.True(t, session.Active())
+
+	// A fresh registry over the same file sees the session
+	session, ok, err := NewRegistry(registry.path).Get("backend-api")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, ModeTUI, session.Mode)
+
+	require.NoError(t, registry.Detach("backend-api"))
+	session, _, err = registry.Get("backend-api")
+	require.NoError(t, err)
+	assert.False(t, session.Active())
+	assert.Equal(t, dir, session.WorkingDir, "detached sessions keep their directory")
+
+	// Attaching again by name finds the directory
+	session, err = registry.Attach("backend-api", "", ModePipe)
+	require.NoError(t, err)
+	assert.Equal(t, dir, session.WorkingDir)
+}
+
+func TestRegistry_AttachedElsewhere(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), FileName))
+	dir := t.TempDir()
+	writeRegistry(t, registry, Session{Name: "web", WorkingDir: dir, PID: os.Getppid(), Mode: ModeTUI})
+
+	_, err := registry.Attach("web", "", ModeTUI)
+	assert.ErrorContains(t, err, "already attached")
+	assert.ErrorContains(t, registry.Remove("web"), "quit it first")
+	require.NoError(t, registry.Detach("web"), "other processes' sessions are left alone")
+
+	// A process that died without detaching does not hold the session
+	writeRegistry(t, registry, Session{Name: "web", WorkingDir: dir, PID: 1 << 30, Mode: ModeTUI})
+	_, err = registry.Attach("web", "", ModeTUI)
+	require.NoError(t, err)
+}
+
+func TestRegistry_ListAndRemove(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), FileName))
+	for _, name := range []string{"web", "api"} {
+		_, err := registry.Attach(name, t.TempDir(), ModeTUI)
+		require.NoError(t, err)
+		require.NoError(t, registry.Detach(name))
+	}
+
+	list, err := registry.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "api", list[0].Name)
+
+	require.NoError(t, registry.Remove("api"))
+	assert.ErrorContains(t, registry.Remove("api"), "no session named")
+	list, err = registry.List()
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"backend-api", "web_2", "v1.2"} {
+		assert.NoError(t, ValidateName(name), name)
+	}
+	for _, name := range []string{"", "-x", "a/b", "with space", string(make([]byte, 65))} {
+		assert.Error(t, ValidateName(name), name)
+	}
+}
+
+func writeRegistry(t *testing.T, registry *Registry, sessions ...Session) {
+	t.Helper()
+	file := registryFile{Sessions: make(map[string]Session)}
+	for _, session := range sessions {
+		file.Sessions[session.Name] = session
+	}
+	data, err := json.Marshal(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(registry.path, data, 0o600))
+}
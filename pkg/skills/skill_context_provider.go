@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -123,13 +124,13 @@ func (p *SkillContextPartProvider) GetPart(c context.Context) (ctx.ContextPart,
 	contentBuilder.WriteString("\n")
 
 	// Add SKILL.md content with full path header
-	skillFilePath := activeSkill.BaseDir + "/SKILL.md"
+	skillFilePath := filepath.Join(activeSkill.BaseDir, "SKILL.md")
 	fmt.Fprintf(&contentBuilder, "## %s\n%s\n", skillFilePath, activeSkill.Content)
 
 	// Add any loaded files
 	if len(activeSkill.LoadedFiles) > 0 {
 		for relPath, content := range activeSkill.LoadedFiles {
-			fullPath := activeSkill.BaseDir + "/" + relPath
+			fullPath := filepath.Join(activeSkill.BaseDir, relPath)
 			fmt.Fprintf(&contentBuilder, "\n## %s\n%s\n", fullPath, content)
 		}
 	}
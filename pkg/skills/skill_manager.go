@@ -5,6 +5,7 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -19,6 +20,7 @@ var internalSkillsFS embed.FS
 type DefaultSkillManager struct {
 	loader        *SkillLoader
 	genieHome     string
+	untrusted     bool // Workspace not trusted; skip project skills
 	userHome      string
 	skillsCache   map[string]*SkillMetadata // Cache of discovered skills
 	activeSkills  map[string]*Skill         // Active skills per session ID
@@ -50,17 +52,24 @@ func (m *DefaultSkillManager) SetGenieHome(genieHome string) {
 	m.discoveryDone = false // Invalidate cache
 }
 
+// syncWorkspace picks up the genie home and workspace trust from the
+// context, invalidating the cache when either changed.
+func (m *DefaultSkillManager) syncWorkspace(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if genieHome, ok := toolctx.GenieHome(ctx); ok && genieHome != "" && m.genieHome != genieHome {
+		m.genieHome = genieHome
+		m.discoveryDone = false // Invalidate cache to rediscover with new genie home
+	}
+	if trusted, ok := toolctx.WorkspaceTrusted(ctx); ok && m.untrusted == trusted {
+		m.untrusted = !trusted
+		m.discoveryDone = false
+	}
+}
+
 // ListSkills returns metadata for all available skills across all sources
 func (m *DefaultSkillManager) ListSkills(ctx context.Context) ([]SkillMetadata, error) {
-	// Extract genie home from context and update if different
-	if genieHome, ok := toolctx.GenieHome(ctx); ok && genieHome != "" {
-		m.mu.Lock()
-		if m.genieHome != genieHome {
-			m.genieHome = genieHome
-			m.discoveryDone = false // Invalidate cache to rediscover with new genie home
-		}
-		m.mu.Unlock()
-	}
+	m.syncWorkspace(ctx)
 
 	if err := m.ensureDiscovery(); err != nil {
 		return nil, err
@@ -79,15 +88,7 @@ func (m *DefaultSkillManager) ListSkills(ctx context.Context) ([]SkillMetadata,
 
 // GetSkillMetadata returns metadata for a specific skill by name
 func (m *DefaultSkillManager) GetSkillMetadata(ctx context.Context, name string) (*SkillMetadata, error) {
-	// Extract genie home from context and update if different
-	if genieHome, ok := toolctx.GenieHome(ctx); ok && genieHome != "" {
-		m.mu.Lock()
-		if m.genieHome != genieHome {
-			m.genieHome = genieHome
-			m.discoveryDone = false // Invalidate cache to rediscover with new genie home
-		}
-		m.mu.Unlock()
-	}
+	m.syncWorkspace(ctx)
 
 	if err := m.ensureDiscovery(); err != nil {
 		return nil, err
@@ -328,7 +329,7 @@ func (m *DefaultSkillManager) discoverAllSkills() error {
 
 // discoverProjectSkills discovers skills from project directories (.genie/skills and .claude/skills)
 func (m *DefaultSkillManager) discoverProjectSkills() error {
-	if m.genieHome == "" {
+	if m.genieHome == "" || m.untrusted {
 		return nil
 	}
 
@@ -374,7 +375,8 @@ func (m *DefaultSkillManager) discoverInternalSkills() error {
 			continue
 		}
 
-		skillPath := filepath.Join("internal/skills", skillName, "SKILL.md")
+		// Embedded paths always use forward slashes, regardless of OS
+		skillPath := path.Join("internal/skills", skillName, "SKILL.md")
 
 		// Read SKILL.md from embedded filesystem
 		content, err := internalSkillsFS.ReadFile(skillPath)
@@ -429,7 +431,8 @@ func (m *DefaultSkillManager) discoverFromDirectory(dir string, source SkillSour
 
 // loadInternalSkill loads an internal skill from embedded filesystem
 func (m *DefaultSkillManager) loadInternalSkill(name string) (*Skill, error) {
-	skillPath := filepath.Join("internal/skills", name, "SKILL.md")
+	// Embedded paths always use forward slashes, regardless of OS
+	skillPath := path.Join("internal/skills", name, "SKILL.md")
 
 	content, err := internalSkillsFS.ReadFile(skillPath)
 	if err != nil {
@@ -451,7 +454,7 @@ func (m *DefaultSkillManager) loadInternalSkill(name string) (*Skill, error) {
 	skill := &Skill{
 		SkillMetadata: *metadata,
 		Content:       skillContent,
-		BaseDir:       filepath.Dir(skillPath),
+		BaseDir:       path.Dir(skillPath),
 		LoadedFiles:   make(map[string]string),
 	}
 
@@ -479,3 +479,29 @@ func TestManagerConcurrentAccessIsRaceFree(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestUntrustedWorkspaceSkipsProjectSkills(t *testing.T) {
+	manager, userHome, projectRoot := newTestManager(t)
+
+	writeSkillDir(t, filepath.Join(userHome, ".genie", "skills"), "shared", "shared", "User version", "# User")
+	writeSkillDir(t, filepath.Join(projectRoot, ".genie", "skills"), "shared", "shared", "Project version", "# Project")
+	writeSkillDir(t, filepath.Join(projectRoot, ".genie", "skills"), "project-only", "project-only", "Project only", "# P")
+
+	untrusted := toolctx.WithWorkspaceTrusted(context.Background(), false)
+	metadata, err := manager.GetSkillMetadata(untrusted, "shared")
+	if err != nil {
+		t.Fatalf("GetSkillMetadata returned error: %v", err)
+	}
+	if metadata.Source != SkillSourceUser {
+		t.Errorf("Source = %q, want %q in an untrusted workspace", metadata.Source, SkillSourceUser)
+	}
+	if _, err := manager.GetSkillMetadata(untrusted, "project-only"); err == nil {
+		t.Error("project-only skill should not load in an untrusted workspace")
+	}
+
+	// Trusting the workspace rediscovers project skills
+	trusted := toolctx.WithWorkspaceTrusted(context.Background(), true)
+	if _, err := manager.GetSkillMetadata(trusted, "project-only"); err != nil {
+		t.Errorf("project-only skill should load once trusted: %v", err)
+	}
+}
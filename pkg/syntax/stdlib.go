@@ -0,0 +1,29 @@
+//go:build !cgo
+
+package syntax
+
+import (
+	"errors"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+)
+
+// Without cgo there are no tree-sitter grammars; Go's own parser still
+// covers Go.
+func init() {
+	checkers[".go"] = checkGo
+}
+
+func checkGo(content []byte) []Error {
+	_, err := parser.ParseFile(token.NewFileSet(), "", content, parser.AllErrors|parser.SkipObjectResolution)
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return nil
+	}
+	errs := make([]Error, 0, len(list))
+	for _, e := range list {
+		errs = append(errs, Error{Line: e.Pos.Line, Column: e.Pos.Column, Message: "syntax error: " + e.Msg})
+	}
+	return errs
+}
@@ -0,0 +1,77 @@
+// Package syntax finds syntax errors in source files, so edits that break
+// a file can be caught before they are written.
+package syntax
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// maxErrors caps the errors reported for one file; past the first few a
+// parser's errors are mostly echoes of the first.
+const maxErrors = 10
+
+// Error is a syntax error at a 1-indexed line and byte column.
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e Error) String() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// checker parses content and returns its syntax errors
+type checker func(content []byte) []Error
+
+// checkers maps lowercase file extensions to their checker. Builds with
+// cgo use tree-sitter grammars; without it only Go is checked.
+var checkers = map[string]checker{}
+
+// Supported reports whether files like path can be checked.
+func Supported(path string) bool {
+	_, ok := checkers[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// Check returns the syntax errors in content, a file at path. supported
+// is false when there is no parser for the file's language.
+func Check(path string, content []byte) (errs []Error, supported bool) {
+	check, ok := checkers[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, false
+	}
+	errs = check(content)
+	if len(errs) > maxErrors {
+		errs = errs[:maxErrors]
+	}
+	return errs, true
+}
+
+// Introduced returns the errors in after that before did not have, so an
+// edit to a file that was already broken is only blamed for what it
+// broke. Errors are matched by message, since the edit moves lines.
+func Introduced(path string, before, after []byte) []Error {
+	errs, ok := Check(path, after)
+	if !ok || len(errs) == 0 {
+		return nil
+	}
+	existing := make(map[string]int)
+	if before != nil {
+		previous, _ := Check(path, before)
+		for _, err := range previous {
+			existing[err.Message]++
+		}
+	}
+	var introduced []Error
+	for _, err := range errs {
+		if existing[err.Message] > 0 {
+			existing[err.Message]--
+			continue
+		}
+		introduced = append(introduced, err)
+	}
+	return introduced
+}
@@ -0,0 +1,60 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckValidGo(t *testing.T) {
+	errs, supported := Check("main.go", []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"))
+	assert.True(t, supported)
+	assert.Empty(t, errs)
+}
+
+func TestCheckReportsBrokenGo(t *testing.T) {
+	errs, supported := Check("main.go", []byte("package main\n\nfunc main() {\n\tx := (1 + \n}\n"))
+	require.True(t, supported)
+	require.NotEmpty(t, errs)
+	assert.GreaterOrEqual(t, errs[0].Line, 4)
+	assert.Contains(t, errs[0].Message, "syntax error")
+}
+
+func TestCheckUnsupportedLanguage(t *testing.T) {
+	errs, supported := Check("notes.txt", []byte("anything {"))
+	assert.False(t, supported)
+	assert.Empty(t, errs)
+	assert.False(t, Supported("notes.txt"))
+	assert.True(t, Supported("MAIN.GO"), "extensions match regardless of case")
+}
+
+func TestCheckTreeSitterLanguages(t *testing.T) {
+	if !Supported("app.py") {
+		t.Skip("built without cgo; only Go is checked")
+	}
+	errs, _ := Check("app.py", []byte("def greet(name):\n    return f\"hi {name}\"\n"))
+	assert.Empty(t, errs)
+	errs, _ = Check("app.py", []byte("def greet(name:\n    return name\n"))
+	assert.NotEmpty(t, errs)
+
+	errs, _ = Check("config.json", []byte(`{"a": [1, 2}`))
+	assert.NotEmpty(t, errs)
+
+	errs, _ = Check("Main.java", []byte("class Main { void run() { int x = 1 } }"))
+	require.Len(t, errs, 1)
+	assert.Equal(t, `1:36: syntax error: missing ";"`, errs[0].String())
+}
+
+func TestIntroducedIgnoresErrorsTheFileAlreadyHad(t *testing.T) {
+	before := []byte("package main\n\nfunc a() {\n\tx := \n}\n")
+	// The edit adds lines above the existing error, moving it down
+	after := []byte("package main\n\nfunc b() {}\n\nfunc a() {\n\tx := \n}\n")
+	assert.Empty(t, Introduced("main.go", before, after))
+
+	broken := []byte("package main\n\nfunc b( {}\n\nfunc a() {\n\tx := \n}\n")
+	assert.NotEmpty(t, Introduced("main.go", before, broken))
+
+	assert.NotEmpty(t, Introduced("main.go", nil, before), "a new file has nothing to compare against")
+	assert.Empty(t, Introduced("notes.txt", nil, []byte("{")))
+}
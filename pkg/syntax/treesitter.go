@@ -0,0 +1,92 @@
+//go:build cgo
+
+package syntax
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+	tree_sitter_java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+	tree_sitter_json "github.com/tree-sitter/tree-sitter-json/bindings/go"
+	tree_sitter_python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+)
+
+func init() {
+	grammars := map[string]unsafe.Pointer{
+		".go":   tree_sitter_go.Language(),
+		".py":   tree_sitter_python.Language(),
+		".pyi":  tree_sitter_python.Language(),
+		".java": tree_sitter_java.Language(),
+		".json": tree_sitter_json.Language(),
+	}
+	for ext, grammar := range grammars {
+		checkers[ext] = treeSitterChecker(sitter.NewLanguage(grammar))
+	}
+}
+
+func treeSitterChecker(language *sitter.Language) checker {
+	return func(content []byte) []Error {
+		parser := sitter.NewParser()
+		defer parser.Close()
+		if err := parser.SetLanguage(language); err != nil {
+			return nil
+		}
+		tree := parser.Parse(content, nil)
+		if tree == nil {
+			return nil
+		}
+		defer tree.Close()
+
+		var errs []Error
+		collectErrors(tree.RootNode(), content, &errs)
+		return errs
+	}
+}
+
+// collectErrors walks the subtrees that contain errors, reporting each
+// ERROR node once and each token the parser had to assume was missing.
+func collectErrors(node *sitter.Node, content []byte, errs *[]Error) {
+	if len(*errs) >= maxErrors {
+		return
+	}
+	position := node.StartPosition()
+	at := Error{Line: int(position.Row) + 1, Column: int(position.Column) + 1}
+	switch {
+	case node.IsMissing():
+		at.Message = fmt.Sprintf("syntax error: missing %s", describeKind(node.Kind()))
+		*errs = append(*errs, at)
+		return
+	case node.IsError():
+		at.Message = fmt.Sprintf("syntax error: unexpected %s", snippet(content[node.StartByte():node.EndByte()]))
+		*errs = append(*errs, at)
+		return
+	case !node.HasError():
+		return
+	}
+	for i := uint(0); i < node.ChildCount(); i++ {
+		collectErrors(node.Child(i), content, errs)
+	}
+}
+
+// describeKind quotes punctuation and names other node kinds as they are
+func describeKind(kind string) string {
+	if strings.ContainsFunc(kind, func(r rune) bool { return r == '_' || r >= 'a' && r <= 'z' }) {
+		return strings.ReplaceAll(kind, "_", " ")
+	}
+	return fmt.Sprintf("%q", kind)
+}
+
+// snippet quotes the start of the text an ERROR node covers
+func snippet(text []byte) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(string(text)), "\n")
+	if first == "" {
+		return "end of input"
+	}
+	if runes := []rune(first); len(runes) > 30 {
+		first = string(runes[:30]) + "…"
+	}
+	return fmt.Sprintf("%q", first)
+}
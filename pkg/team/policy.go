@@ -0,0 +1,142 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+const (
+	// ProvidersConfigKey lists the providers Genie may call.
+	ProvidersConfigKey = "GENIE_ALLOWED_PROVIDERS"
+
+	// ModelsConfigKey lists the models Genie may use, as patterns in which
+	// * matches any run of characters.
+	ModelsConfigKey = "GENIE_ALLOWED_MODELS"
+
+	// DisabledToolsConfigKey lists the tools withheld from the model.
+	DisabledToolsConfigKey = "GENIE_DISABLED_TOOLS"
+)
+
+// Policy is what the configuration allows Genie to use. Empty lists
+// allow everything.
+type Policy struct {
+	Providers     []string
+	Models        []string
+	DisabledTools []string
+}
+
+// PolicyFromConfig reads the policy from configuration, where team.yaml
+// puts it beneath the user's own settings.
+func PolicyFromConfig(cfg config.Manager) Policy {
+	return Policy{
+		Providers:     List(cfg.GetStringWithDefault(ProvidersConfigKey, "")),
+		Models:        List(cfg.GetStringWithDefault(ModelsConfigKey, "")),
+		DisabledTools: List(cfg.GetStringWithDefault(DisabledToolsConfigKey, "")),
+	}
+}
+
+// List splits a comma-separated setting, dropping empty entries.
+func List(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// Empty reports whether the policy allows everything.
+func (p Policy) Empty() bool {
+	return len(p.Providers)+len(p.Models)+len(p.DisabledTools) == 0
+}
+
+// Middleware refuses requests for providers and models the policy does
+// not allow and withholds its disabled tools from the model. cfg supplies
+// the provider and model of requests that leave them to the defaults.
+func (p Policy) Middleware(cfg config.Manager) middleware.Middleware {
+	return middleware.Funcs{BeforeFunc: func(ctx context.Context, req *middleware.Request) error {
+		provider := canonical(cfg, req.Prompt.LLMProvider)
+		if len(p.Providers) > 0 && !p.allowsProvider(cfg, provider) {
+			return ai.NonRetryable(fmt.Errorf("provider %q is not allowed for this project; %s permits %s",
+				provider, ProvidersConfigKey, strings.Join(p.Providers, ", ")))
+		}
+		model := req.Prompt.ModelName
+		if model == "" {
+			model = cfg.GetModelConfig().ModelName
+		}
+		if len(p.Models) > 0 && !p.allowsModel(model) {
+			return ai.NonRetryable(fmt.Errorf("model %q is not allowed for this project; %s permits %s",
+				model, ModelsConfigKey, strings.Join(p.Models, ", ")))
+		}
+		if len(p.DisabledTools) > 0 {
+			p.withholdTools(&req.Prompt)
+		}
+		return nil
+	}}
+}
+
+func (p Policy) allowsProvider(cfg config.Manager, provider string) bool {
+	for _, allowed := range p.Providers {
+		if canonical(cfg, allowed) == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Policy) allowsModel(model string) bool {
+	model = strings.ToLower(model)
+	for _, pattern := range p.Models {
+		if ok, _ := path.Match(strings.ToLower(pattern), model); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// withholdTools drops the disabled tools from prompt. Handlers are copied
+// so the caller's map is left alone.
+func (p Policy) withholdTools(prompt *ai.Prompt) {
+	disabled := make(map[string]bool, len(p.DisabledTools))
+	for _, name := range p.DisabledTools {
+		disabled[name] = true
+	}
+	functions := make([]*ai.FunctionDeclaration, 0, len(prompt.Functions))
+	handlers := make(map[string]ai.HandlerFunc, len(prompt.Handlers))
+	for _, fn := range prompt.Functions {
+		if disabled[fn.Name] {
+			continue
+		}
+		functions = append(functions, fn)
+		if handler, ok := prompt.Handlers[fn.Name]; ok {
+			handlers[fn.Name] = handler
+		}
+	}
+	prompt.Functions = functions
+	prompt.Handlers = handlers
+}
+
+// canonical maps provider aliases to the multiplexer's provider names
+func canonical(cfg config.Manager, provider string) string {
+	if provider == "" {
+		provider = cfg.GetStringWithDefault("GENIE_LLM_PROVIDER", "genai")
+	}
+	switch provider = strings.ToLower(provider); provider {
+	case "gemini", "google", "vertex":
+		return "genai"
+	case "openai-chat":
+		return "openai"
+	case "claude", "anthropic-claude":
+		return "anthropic"
+	case "lm-studio":
+		return "lmstudio"
+	}
+	return provider
+}
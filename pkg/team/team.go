@@ -0,0 +1,106 @@
+// Package team applies a project's checked-in team configuration,
+// .genie/team.yaml, so everyone working on the project runs Genie the
+// same way:
+//
+//	backends: [anthropic, ollama]          # providers Genie may call
+//	models: ["claude-sonnet-4*", "qwen*"]   # models it may use; * is a wildcard
+//	personas: [team-engineer, reviewer]    # personas sessions may use; the first is the default
+//	banned_tools: [webFetch]               # tools the model never gets
+//	defaults:                              # any other setting
+//	  GENIE_LLM_PROVIDER: anthropic
+//	  GENIE_AI_MIDDLEWARE: redact
+//
+// Every entry becomes a configuration value beneath the user's own: a key
+// already set in the environment, .env or ~/.genie/config.env keeps its
+// value, so a contributor can override the team where they need to.
+package team
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/persona"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the team configuration in the project's .genie directory.
+const FileName = "team.yaml"
+
+// Config is the structure of team.yaml.
+type Config struct {
+	Backends    []string          `yaml:"backends,omitempty"`
+	Models      []string          `yaml:"models,omitempty"`
+	Personas    []string          `yaml:"personas,omitempty"`
+	BannedTools []string          `yaml:"banned_tools,omitempty"`
+	Defaults    map[string]string `yaml:"defaults,omitempty"`
+}
+
+// Path returns where projectRoot keeps its team configuration.
+func Path(projectRoot string) string {
+	return filepath.Join(projectRoot, ".genie", FileName)
+}
+
+// Load reads the team configuration of projectRoot. It returns nil when
+// the project has none.
+func Load(projectRoot string) (*Config, error) {
+	path := Path(projectRoot)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for key := range config.Defaults {
+		if key == "" || strings.ContainsAny(key, "= \t\n") {
+			return nil, fmt.Errorf("%s: invalid setting name %q in defaults", path, key)
+		}
+	}
+	return &config, nil
+}
+
+// Values returns the configuration values the team sets. The policy
+// entries only narrow what Genie may do, so they apply in any workspace;
+// defaults can point Genie at other endpoints or programs and apply only
+// when the user trusts the workspace.
+func (c *Config) Values(trusted bool) map[string]string {
+	values := make(map[string]string)
+	if trusted {
+		for key, value := range c.Defaults {
+			values[key] = value
+		}
+	}
+	set := func(key string, list []string) {
+		if len(list) > 0 {
+			values[key] = strings.Join(list, ",")
+		}
+	}
+	set(ProvidersConfigKey, c.Backends)
+	set(ModelsConfigKey, c.Models)
+	set(DisabledToolsConfigKey, c.BannedTools)
+	set(persona.AllowedConfigKey, c.Personas)
+	if _, ok := values[persona.DefaultConfigKey]; !ok && len(c.Personas) > 0 {
+		values[persona.DefaultConfigKey] = c.Personas[0]
+	}
+	return values
+}
+
+// Apply sets the team's values in the environment of the running process,
+// skipping the keys the user already set, and returns the keys it set.
+func (c *Config) Apply(trusted bool) []string {
+	var applied []string
+	for key, value := range c.Values(trusted) {
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		os.Setenv(key, value)
+		applied = append(applied, key)
+	}
+	return applied
+}
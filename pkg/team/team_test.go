@@ -0,0 +1,114 @@
+package team
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/ai/middleware"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/persona"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const teamYAML = `backends: [anthropic, ollama]
+models: ["claude-sonnet-4*", "qwen*"]
+personas: [reviewer, engineer]
+banned_tools: [webFetch]
+defaults:
+  GENIE_AI_MIDDLEWARE: redact
+`
+
+func writeTeamConfig(t *testing.T, content string) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".genie"), 0o755))
+	require.NoError(t, os.WriteFile(Path(root), []byte(content), 0o644))
+	return root
+}
+
+func TestLoad(t *testing.T) {
+	config, err := Load(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = Load(writeTeamConfig(t, teamYAML))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"anthropic", "ollama"}, config.Backends)
+	assert.Equal(t, []string{"webFetch"}, config.BannedTools)
+	assert.Equal(t, "redact", config.Defaults["GENIE_AI_MIDDLEWARE"])
+
+	_, err = Load(writeTeamConfig(t, "defaults:\n  'BAD KEY': x\n"))
+	assert.ErrorContains(t, err, "invalid setting name")
+	_, err = Load(writeTeamConfig(t, "backends: ["))
+	assert.ErrorContains(t, err, "failed to parse")
+}
+
+func TestValues(t *testing.T) {
+	config, err := Load(writeTeamConfig(t, teamYAML))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		ProvidersConfigKey:       "anthropic,ollama",
+		ModelsConfigKey:          "claude-sonnet-4*,qwen*",
+		DisabledToolsConfigKey:   "webFetch",
+		persona.AllowedConfigKey: "reviewer,engineer",
+		persona.DefaultConfigKey: "reviewer",
+		"GENIE_AI_MIDDLEWARE":    "redact",
+	}, config.Values(true))
+
+	// Untrusted workspaces keep the policy but not the defaults
+	assert.NotContains(t, config.Values(false), "GENIE_AI_MIDDLEWARE")
+	assert.Contains(t, config.Values(false), ProvidersConfigKey)
+}
+
+func TestApply_UserSettingsWin(t *testing.T) {
+	config, err := Load(writeTeamConfig(t, teamYAML))
+	require.NoError(t, err)
+	for key := range config.Values(true) {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	t.Setenv(ModelsConfigKey, "gpt-5*")
+
+	applied := config.Apply(true)
+	assert.NotContains(t, applied, ModelsConfigKey)
+	assert.Equal(t, "gpt-5*", os.Getenv(ModelsConfigKey))
+	assert.Equal(t, "anthropic,ollama", os.Getenv(ProvidersConfigKey))
+	assert.Equal(t, "redact", os.Getenv("GENIE_AI_MIDDLEWARE"))
+}
+
+func TestPolicyMiddleware(t *testing.T) {
+	t.Setenv("GENIE_LLM_PROVIDER", "gemini")
+	t.Setenv(ProvidersConfigKey, "claude, ollama")
+	t.Setenv(ModelsConfigKey, "claude-sonnet-4*")
+	t.Setenv(DisabledToolsConfigKey, "webFetch")
+	cfg := config.NewConfigManager()
+	policy := PolicyFromConfig(cfg)
+	require.False(t, policy.Empty())
+	mw := policy.Middleware(cfg)
+
+	handler := func(context.Context, map[string]any) (map[string]any, error) { return nil, nil }
+	handlers := map[string]ai.HandlerFunc{"readFile": handler, "webFetch": handler}
+	req := &middleware.Request{Prompt: ai.Prompt{
+		LLMProvider: "anthropic",
+		ModelName:   "claude-sonnet-4-5",
+		Functions:   []*ai.FunctionDeclaration{{Name: "readFile"}, {Name: "webFetch"}},
+		Handlers:    handlers,
+	}}
+	require.NoError(t, mw.Before(context.Background(), req))
+	require.Len(t, req.Prompt.Functions, 1)
+	assert.Equal(t, "readFile", req.Prompt.Functions[0].Name)
+	assert.NotContains(t, req.Prompt.Handlers, "webFetch")
+	assert.Contains(t, handlers, "webFetch", "the caller's handlers are left alone")
+
+	err := mw.Before(context.Background(), &middleware.Request{Prompt: ai.Prompt{ModelName: "claude-sonnet-4-5"}})
+	assert.ErrorContains(t, err, `provider "genai" is not allowed`)
+	assert.False(t, ai.IsRetryable(err))
+
+	err = mw.Before(context.Background(), &middleware.Request{Prompt: ai.Prompt{LLMProvider: "ollama", ModelName: "llama3"}})
+	assert.ErrorContains(t, err, `model "llama3" is not allowed`)
+}
@@ -0,0 +1,114 @@
+// Package telemetry traces Genie's work with OpenTelemetry: each chat turn
+// is a span, with one child span per model request and per tool call.
+// Spans are exported over OTLP/HTTP when tracing is enabled, and cost
+// nothing otherwise.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracingConfigKey turns tracing on or off explicitly. When unset,
+	// tracing is on whenever an OTLP endpoint is configured.
+	TracingConfigKey = "GENIE_OTEL_TRACING"
+
+	// ServiceName is the default service.name of exported spans;
+	// OTEL_SERVICE_NAME overrides it.
+	ServiceName = "genie"
+
+	tracerName = "github.com/kcaldas/genie"
+)
+
+// endpointConfigKeys are the standard OTLP exporter settings; setting any
+// of them enables tracing unless TracingConfigKey says otherwise.
+var endpointConfigKeys = []string{
+	"OTEL_EXPORTER_OTLP_ENDPOINT",
+	"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+}
+
+// Enabled reports whether tracing should be exported.
+func Enabled(cfg config.Manager) bool {
+	if cfg.GetBoolWithDefault("OTEL_SDK_DISABLED", false) {
+		return false
+	}
+	configured := false
+	for _, key := range endpointConfigKeys {
+		if cfg.GetStringWithDefault(key, "") != "" {
+			configured = true
+		}
+	}
+	return cfg.GetBoolWithDefault(TracingConfigKey, configured)
+}
+
+// Setup installs a global tracer provider exporting spans over OTLP/HTTP,
+// configured by the standard OTEL_EXPORTER_OTLP_* variables. It returns a
+// function that flushes pending spans and stops the exporter. When tracing
+// is disabled, Setup does nothing and the returned function is a no-op.
+func Setup(ctx context.Context, cfg config.Manager) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !Enabled(cfg) {
+		return noop, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	// Later options win, so OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES
+	// override the defaults here.
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			attribute.String("service.name", ServiceName),
+			attribute.String("service.version", version.Version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("failed to describe trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Tracer returns Genie's tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartSpan starts a span as a child of any span in ctx. A nil ctx, which
+// some tool tests pass, gets a span that records nothing and stays nil.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		return nil, trace.SpanFromContext(context.Background())
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, as the span's error status and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "nothing configured", want: false},
+		{name: "endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318"}, want: true},
+		{name: "traces endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://localhost:4318/v1/traces"}, want: true},
+		{name: "explicitly on", env: map[string]string{TracingConfigKey: "true"}, want: true},
+		{name: "explicitly off", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318", TracingConfigKey: "false"}, want: false},
+		{name: "sdk disabled", env: map[string]string{TracingConfigKey: "true", "OTEL_SDK_DISABLED": "true"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{TracingConfigKey, "OTEL_SDK_DISABLED", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"} {
+				t.Setenv(key, tt.env[key])
+			}
+			assert.Equal(t, tt.want, Enabled(config.NewConfigManager()))
+		})
+	}
+}
+
+func TestStartSpanNestsAndRecordsErrors(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx, turn := StartSpan(context.Background(), "genie.turn")
+	_, tool := StartSpan(ctx, "execute_tool bash")
+	End(tool, errors.New("exit status 1"))
+	End(turn, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "execute_tool bash", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "exit status 1", spans[0].Status().Description)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestStartSpanKeepsNilContext(t *testing.T) {
+	// Some tool tests call handlers with a nil context
+	ctx, span := StartSpan(nil, "execute_tool bash")
+	assert.Nil(t, ctx)
+	assert.NotPanics(t, func() { End(span, errors.New("boom")) })
+}
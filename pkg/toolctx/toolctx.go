@@ -25,11 +25,17 @@ type (
 	allowedDirsKey       struct{}
 	deniedPathsKey       struct{}
 	readOnlyPathsKey     struct{}
+	pinnedFilesKey       struct{}
+	scopeKey             struct{}
+	workspaceTrustedKey  struct{}
 	commitAuthorNameKey  struct{}
 	commitAuthorEmailKey struct{}
 	personaKey           struct{}
 	sessionIDKey         struct{}
+	requestIDKey         struct{}
 	executionIDKey       struct{}
+	confirmationKey      struct{}
+	approvalsKey         struct{}
 )
 
 // WithWorkingDir returns a context carrying the session working
@@ -94,6 +100,44 @@ func ReadOnlyPaths(ctx context.Context) ([]string, bool) {
 	return v, ok
 }
 
+// WithPinnedFiles returns a context carrying the absolute paths of the
+// files pinned into the session context.
+func WithPinnedFiles(ctx context.Context, paths []string) context.Context {
+	return context.WithValue(ctx, pinnedFilesKey{}, paths)
+}
+
+// PinnedFiles returns the pinned file paths and whether they were set.
+func PinnedFiles(ctx context.Context) ([]string, bool) {
+	v, ok := ctx.Value(pinnedFilesKey{}).([]string)
+	return v, ok
+}
+
+// WithScope returns a context carrying the directories, relative to the
+// working directory, that file tools are confined to in a monorepo.
+func WithScope(ctx context.Context, dirs []string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, dirs)
+}
+
+// Scope returns the scope directories and whether they were set.
+func Scope(ctx context.Context) ([]string, bool) {
+	v, ok := ctx.Value(scopeKey{}).([]string)
+	return v, ok
+}
+
+// WithWorkspaceTrusted returns a context recording whether the user
+// trusts the workspace. Untrusted workspaces must not load project-local
+// personas, skills or other configuration.
+func WithWorkspaceTrusted(ctx context.Context, trusted bool) context.Context {
+	return context.WithValue(ctx, workspaceTrustedKey{}, trusted)
+}
+
+// WorkspaceTrusted returns whether the workspace is trusted and whether
+// that was set. Callers treat an unset value as trusted.
+func WorkspaceTrusted(ctx context.Context) (bool, bool) {
+	v, ok := ctx.Value(workspaceTrustedKey{}).(bool)
+	return v, ok
+}
+
 // WithCommitAuthorName returns a context carrying the commit author
 // name the host wants git tools to use.
 func WithCommitAuthorName(ctx context.Context, name string) context.Context {
@@ -140,6 +184,18 @@ func SessionID(ctx context.Context) (string, bool) {
 	return v, ok
 }
 
+// WithRequestID returns a context carrying the ID of the chat request
+// (turn) a tool call belongs to.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the chat request ID and whether it was set.
+func RequestID(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(requestIDKey{}).(string)
+	return v, ok
+}
+
 // WithExecutionID returns a context carrying the per-tool-call
 // execution ID used to correlate tool lifecycle events.
 func WithExecutionID(ctx context.Context, executionID string) context.Context {
@@ -151,3 +207,40 @@ func ExecutionID(ctx context.Context) (string, bool) {
 	v, ok := ctx.Value(executionIDKey{}).(string)
 	return v, ok
 }
+
+// WithConfirmationObserver returns a context carrying a callback that
+// confirmers invoke with the user's answer, so whoever runs the tool
+// (e.g. the audit trail) learns whether it was approved.
+func WithConfirmationObserver(ctx context.Context, observe func(approved bool)) context.Context {
+	return context.WithValue(ctx, confirmationKey{}, observe)
+}
+
+// ConfirmationObserver returns the confirmation callback and whether it
+// was set.
+func ConfirmationObserver(ctx context.Context) (func(approved bool), bool) {
+	v, ok := ctx.Value(confirmationKey{}).(func(approved bool))
+	return v, ok && v != nil
+}
+
+// ApprovalStore remembers the tools the user approved for the rest of a
+// session, so confirmers need not ask again.
+type ApprovalStore interface {
+	// IsToolApproved reports whether running command with toolName was
+	// approved earlier in the session.
+	IsToolApproved(toolName, command string) bool
+	// ApproveTool approves toolName for the rest of the session, scoped
+	// to commands like command.
+	ApproveTool(toolName, command string)
+}
+
+// WithApprovals returns a context carrying the session's tool approvals.
+func WithApprovals(ctx context.Context, approvals ApprovalStore) context.Context {
+	return context.WithValue(ctx, approvalsKey{}, approvals)
+}
+
+// Approvals returns the session's tool approvals and whether they were
+// set.
+func Approvals(ctx context.Context) (ApprovalStore, bool) {
+	v, ok := ctx.Value(approvalsKey{}).(ApprovalStore)
+	return v, ok && v != nil
+}
@@ -18,6 +18,7 @@ func TestStringRoundTrips(t *testing.T) {
 		{"CommitAuthorEmail", WithCommitAuthorEmail, CommitAuthorEmail},
 		{"Persona", WithPersona, Persona},
 		{"SessionID", WithSessionID, SessionID},
+		{"RequestID", WithRequestID, RequestID},
 		{"ExecutionID", WithExecutionID, ExecutionID},
 	}
 	for _, tc := range cases {
@@ -47,6 +48,7 @@ func TestStringAbsentKeys(t *testing.T) {
 		"CommitAuthorEmail": CommitAuthorEmail,
 		"Persona":           Persona,
 		"SessionID":         SessionID,
+		"RequestID":         RequestID,
 		"ExecutionID":       ExecutionID,
 	}
 	for name, get := range getters {
@@ -65,6 +67,8 @@ func TestSliceRoundTrips(t *testing.T) {
 		{"AllowedDirs", WithAllowedDirs, AllowedDirs},
 		{"DeniedPaths", WithDeniedPaths, DeniedPaths},
 		{"ReadOnlyPaths", WithReadOnlyPaths, ReadOnlyPaths},
+		{"PinnedFiles", WithPinnedFiles, PinnedFiles},
+		{"Scope", WithScope, Scope},
 	}
 	want := []string{"/a", "b/**", "*.yaml"}
 	for _, tc := range cases {
@@ -84,6 +88,8 @@ func TestSliceAbsentKeys(t *testing.T) {
 		"AllowedDirs":   AllowedDirs,
 		"DeniedPaths":   DeniedPaths,
 		"ReadOnlyPaths": ReadOnlyPaths,
+		"PinnedFiles":   PinnedFiles,
+		"Scope":         Scope,
 	}
 	for name, get := range getters {
 		if got, ok := get(ctx); ok || got != nil {
@@ -110,3 +116,13 @@ func TestKeysDoNotCollide(t *testing.T) {
 		t.Error("SessionID should not be set")
 	}
 }
+
+func TestWorkspaceTrusted(t *testing.T) {
+	if _, ok := WorkspaceTrusted(context.Background()); ok {
+		t.Error("WorkspaceTrusted should not be set on an empty context")
+	}
+	trusted, ok := WorkspaceTrusted(WithWorkspaceTrusted(context.Background(), false))
+	if !ok || trusted {
+		t.Errorf("got (%v, %v), want (false, true)", trusted, ok)
+	}
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -155,7 +156,7 @@ gh pr create --title "the pr title" --body "$(cat <<'EOF'
 ## Summary
 <1-3 bullet points>
 EOF
-)"`,
+)"` + shellSyntaxNote(process.DefaultShell()),
 		Parameters: &ai.Schema{
 			Type:        ai.TypeObject,
 			Description: "Parameters for executing a bash command",
@@ -255,8 +256,9 @@ func (b *BashTool) Handler() ai.HandlerFunc {
 		explicitConfirmation, _ := params["requires_confirmation"].(bool)
 
 		// Check if command requires confirmation based on global setting or explicit parameter
+		edited := false
 		if b.requiresConfirmation || explicitConfirmation {
-			confirmed, err := b.requestConfirmation(ctx, executionID, command)
+			decision, err := b.requestConfirmation(ctx, executionID, command)
 			if err != nil {
 				return map[string]any{
 					"success": false,
@@ -265,36 +267,54 @@ func (b *BashTool) Handler() ai.HandlerFunc {
 				}, nil
 			}
 
-			if !confirmed {
+			if !decision.Confirmed {
 				return map[string]any{
 					"success": false,
 					"results": "",
-					"error":   "command cancelled by user",
+					"error":   decision.Declined("command cancelled by user"),
 				}, nil
 			}
-		}
-
-		// Check for PTY/background execution
-		usePTY, _ := params["pty"].(bool)
-		background, _ := params["background"].(bool)
 
-		if background && b.processRegistry != nil {
-			return b.executeBackground(ctx, command, params, usePTY)
+			// The user may have corrected the command before approving it
+			if decision.Edited != "" && decision.Edited != command {
+				command = decision.Edited
+				edited = true
+			}
 		}
-		if usePTY && b.processRegistry != nil {
-			return b.executePTYSync(ctx, command, params)
+
+		result, err := b.run(ctx, command, params)
+		if err == nil && edited && result != nil {
+			// Tell the model what actually ran
+			result["edited_command"] = command
 		}
+		return result, err
+	}
+}
 
-		// Default: existing synchronous execution path
-		return b.executeCommand(ctx, command, params)
+// run executes command in the background, in a PTY or synchronously, as
+// params ask.
+func (b *BashTool) run(ctx context.Context, command string, params map[string]any) (map[string]any, error) {
+	// Check for PTY/background execution
+	usePTY, _ := params["pty"].(bool)
+	background, _ := params["background"].(bool)
+
+	if background && b.processRegistry != nil {
+		return b.executeBackground(ctx, command, params, usePTY)
 	}
+	if usePTY && b.processRegistry != nil {
+		return b.executePTYSync(ctx, command, params)
+	}
+
+	// Default: existing synchronous execution path
+	return b.executeCommand(ctx, command, params)
 }
 
-// requestConfirmation requests user confirmation and waits for response
-func (b *BashTool) requestConfirmation(ctx context.Context, executionID, command string) (bool, error) {
+// requestConfirmation requests user confirmation and waits for the
+// decision, which may carry an edited command
+func (b *BashTool) requestConfirmation(ctx context.Context, executionID, command string) (Decision, error) {
 	if b.confirmer == nil {
 		// No confirmer means no way to ask; refuse rather than run unconfirmed.
-		return false, fmt.Errorf("confirmation required but no confirmer is configured")
+		return Decision{}, fmt.Errorf("confirmation required but no confirmer is configured")
 	}
 
 	displayCommand := cleanCommandForDisplay(command)
@@ -305,7 +325,7 @@ func (b *BashTool) requestConfirmation(ctx context.Context, executionID, command
 		Message:     fmt.Sprintf("Execute '%s'? [y/N]", displayCommand),
 	}
 
-	return b.confirmer.ConfirmExecution(ctx, request)
+	return decideExecution(ctx, b.confirmer, request)
 }
 
 // cleanCommandForDisplay removes HEREDOC syntax for better readability in confirmations
@@ -403,16 +423,16 @@ func (b *BashTool) executePTYSync(ctx context.Context, command string, params ma
 	select {
 	case <-session.Done():
 		// Process finished
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.Canceled) {
+			_ = session.Kill()
+			return cancelledCommandResult(session.Buffer.Snapshot()), nil
+		}
+		// The tool call's own time limit passed first
+		return runningSessionResult(session, "the tool time limit"), nil
 	case <-timer.C:
 		// Timeout — return what we have with session_id for further interaction
-		output := session.Buffer.Snapshot()
-		return map[string]any{
-			"success":    false,
-			"results":    output,
-			"session_id": session.ID,
-			"state":      "running",
-			"error":      fmt.Sprintf("command still running after %v, use process tool with session_id to interact", timeout),
-		}, nil
+		return runningSessionResult(session, timeout.String()), nil
 	}
 
 	output := session.Buffer.Snapshot()
@@ -422,9 +442,10 @@ func (b *BashTool) executePTYSync(ctx context.Context, command string, params ma
 	success := exitCode == 0
 
 	result := map[string]any{
-		"success": success,
-		"results": output,
-		"state":   string(state),
+		"success":   success,
+		"results":   output,
+		"state":     string(state),
+		"exit_code": exitCode,
 	}
 
 	if !success {
@@ -435,6 +456,19 @@ func (b *BashTool) executePTYSync(ctx context.Context, command string, params ma
 }
 
 // executeCommand executes the bash command
+// shellSyntaxNote tells the model when commands won't run in a POSIX
+// shell, which only happens on Windows without Git Bash.
+func shellSyntaxNote(shell process.Shell) string {
+	switch shell.Kind {
+	case process.ShellPowerShell:
+		return fmt.Sprintf("\n\n*IMPORTANT:* This machine runs Windows and commands run in PowerShell (%s), not bash. Write PowerShell syntax: Get-ChildItem, Select-String, $env:NAME, ';' between commands.", shell.Name())
+	case process.ShellCmd:
+		return "\n\n*IMPORTANT:* This machine runs Windows and commands run in cmd.exe, not bash. Write cmd syntax: dir, findstr, %NAME%, '&&' between commands."
+	default:
+		return ""
+	}
+}
+
 func (b *BashTool) executeCommand(ctx context.Context, command string, params map[string]any) (map[string]any, error) {
 	cwd := b.resolveCWD(ctx, params)
 
@@ -450,8 +484,9 @@ func (b *BashTool) executeCommand(ctx context.Context, command string, params ma
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Create the command using the user's shell, validated against /etc/shells.
-	cmd := exec.CommandContext(execCtx, process.UserShell(), "-l", "-c", command)
+	// Create the command using the user's shell: $SHELL validated against
+	// /etc/shells on Unix, Git Bash or PowerShell on Windows.
+	cmd := process.DefaultShell().Command(execCtx, command, true)
 
 	// Set working directory if provided
 	if cwd != "" {
@@ -470,30 +505,68 @@ func (b *BashTool) executeCommand(ctx context.Context, command string, params ma
 	// Execute command and capture output
 	output, err := cmd.CombinedOutput()
 
-	// Check for timeout
+	// The request was cancelled: the process group has been killed
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return cancelledCommandResult(string(output)), nil
+	}
+
+	// Check for timeout, either the command's own or the tool call's
 	if execCtx.Err() == context.DeadlineExceeded {
+		message := fmt.Sprintf("command timed out after %v", timeout)
+		if ctx.Err() != nil {
+			message = "command exceeded the tool time limit"
+		}
 		return map[string]any{
 			"success": false,
 			"results": string(output),
-			"error":   fmt.Sprintf("command timed out after %v", timeout),
+			"error":   message,
 		}, nil
 	}
 
 	// Check for other errors
 	if err != nil {
-		return map[string]any{
+		result := map[string]any{
 			"success": false,
 			"results": string(output),
 			"error":   fmt.Sprintf("command failed: %v", err),
-		}, nil
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result["exit_code"] = exitErr.ExitCode()
+		}
+		return result, nil
 	}
 
 	return map[string]any{
-		"success": true,
-		"results": string(output),
+		"success":   true,
+		"results":   string(output),
+		"exit_code": 0,
 	}, nil
 }
 
+// cancelledCommandResult reports a command killed because its request was
+// cancelled, with the output it produced until then.
+func cancelledCommandResult(output string) map[string]any {
+	return map[string]any{
+		"success":   false,
+		"cancelled": true,
+		"results":   output,
+		"error":     "command cancelled",
+	}
+}
+
+// runningSessionResult reports a PTY command still running after the given
+// limit, with its output so far and the session to interact with it.
+func runningSessionResult(session *process.Session, after string) map[string]any {
+	return map[string]any{
+		"success":    false,
+		"results":    session.Buffer.Snapshot(),
+		"session_id": session.ID,
+		"state":      "running",
+		"error":      fmt.Sprintf("command still running after %s, use process tool with session_id to interact", after),
+	}
+}
+
 // FormatOutput formats bash command results for user display
 func (b *BashTool) FormatOutput(result map[string]interface{}) string {
 	success, _ := result["success"].(bool)
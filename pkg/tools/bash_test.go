@@ -4,11 +4,13 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
 )
 
 func TestBashTool_Declaration(t *testing.T) {
@@ -80,6 +82,24 @@ func TestBashTool_CommandTimeout(t *testing.T) {
 	assert.Contains(t, strings.ToLower(result["error"].(string)), "timed out")
 }
 
+func TestBashTool_CommandCancelled(t *testing.T) {
+	bashTool := NewBashTool(nil, false)
+	handler := bashTool.Handler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Second, cancel)
+	start := time.Now()
+	result, err := handler(ctx, map[string]any{
+		"command":    "sleep 5",
+		"timeout_ms": float64(10_000),
+	})
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), 4*time.Second, "the process must be killed on cancel")
+	assert.Equal(t, true, result["cancelled"])
+	assert.False(t, result["success"].(bool))
+}
+
 func TestBashTool_CommandError(t *testing.T) {
 	bashTool := NewBashTool(nil, false)
 	handler := bashTool.Handler()
@@ -197,3 +217,40 @@ EOF
 		})
 	}
 }
+
+func TestBashTool_RunsCommandEditedAtConfirmation(t *testing.T) {
+	bus := events.NewEventBus()
+	events.SubscribeTo(bus, func(req events.ToolConfirmationRequest) {
+		bus.Publish(events.ToolConfirmationResponse{}.Topic(), events.ToolConfirmationResponse{
+			ExecutionID: req.ExecutionID,
+			Confirmed:   true,
+			Command:     "echo edited",
+		})
+	})
+	handler := NewBashTool(bus, true).Handler()
+
+	result, err := handler(context.Background(), map[string]any{"command": "echo proposed"})
+	require.NoError(t, err)
+
+	assert.True(t, result["success"].(bool))
+	assert.Contains(t, result["results"].(string), "edited")
+	assert.NotContains(t, result["results"].(string), "proposed")
+	assert.Equal(t, "echo edited", result["edited_command"])
+}
+
+func TestBashTool_ReturnsReasonGivenForDenial(t *testing.T) {
+	bus := events.NewEventBus()
+	events.SubscribeTo(bus, func(req events.ToolConfirmationRequest) {
+		bus.Publish(events.ToolConfirmationResponse{}.Topic(), events.ToolConfirmationResponse{
+			ExecutionID: req.ExecutionID,
+			Feedback:    "use the staging DB instead",
+		})
+	})
+	handler := NewBashTool(bus, true).Handler()
+
+	result, err := handler(context.Background(), map[string]any{"command": "psql prod"})
+	require.NoError(t, err)
+
+	assert.False(t, result["success"].(bool))
+	assert.Equal(t, "command cancelled by user: use the staging DB instead", result["error"])
+}
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Check is a named command verifying the project, such as its build.
+type Check struct {
+	Name    string
+	Command string
+	// Source says where the command came from: the settings file or the
+	// project file it was detected from
+	Source string
+}
+
+// checkOrder is the order checks run in: a broken build fails the rest,
+// so it goes first, and lint is quicker than the tests.
+var checkOrder = map[string]int{"build": 0, "typecheck": 1, "lint": 2, "test": 3}
+
+// ProjectChecks returns the project's checks in the order they run: the
+// ones in .genie/settings.json, and the ones detected from its build
+// files for names the settings leave out. useSettings false skips the
+// settings file, for workspaces the user has not trusted.
+func ProjectChecks(root string, useSettings bool) ([]Check, error) {
+	checks := make(map[string]Check)
+	for _, check := range DetectChecks(root) {
+		checks[check.Name] = check
+	}
+	if useSettings {
+		settings, err := LoadProjectSettings(root)
+		if err != nil {
+			return nil, err
+		}
+		source := filepath.Join(".genie", ProjectSettingsFile)
+		for name, command := range settings.Checks {
+			if strings.TrimSpace(command) == "" {
+				delete(checks, name)
+				continue
+			}
+			checks[name] = Check{Name: name, Command: command, Source: source}
+		}
+	}
+	return sortChecks(checks), nil
+}
+
+func sortChecks(checks map[string]Check) []Check {
+	sorted := make([]Check, 0, len(checks))
+	for _, check := range checks {
+		sorted = append(sorted, check)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		oi, iKnown := checkOrder[sorted[i].Name]
+		oj, jKnown := checkOrder[sorted[j].Name]
+		switch {
+		case iKnown && jKnown:
+			return oi < oj
+		case iKnown != jKnown:
+			return iKnown
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// DetectChecks guesses the build, lint and test commands from the build
+// files in root. A language's own tooling wins over a Makefile target of
+// the same name, which fills the gaps.
+func DetectChecks(root string) []Check {
+	found := make(map[string]Check)
+	add := func(name, command, source string) {
+		if _, ok := found[name]; !ok {
+			found[name] = Check{Name: name, Command: command, Source: source}
+		}
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(root, name))
+		return err == nil
+	}
+
+	if exists("go.mod") {
+		add("build", "go build ./...", "go.mod")
+		add("lint", "go vet ./...", "go.mod")
+		add("test", "go test ./...", "go.mod")
+	}
+	if exists("Cargo.toml") {
+		add("build", "cargo build", "Cargo.toml")
+		add("lint", "cargo clippy", "Cargo.toml")
+		add("test", "cargo test", "Cargo.toml")
+	}
+	if scripts := packageScripts(filepath.Join(root, "package.json")); scripts != nil {
+		runner := nodeRunner(exists)
+		for _, name := range []string{"build", "typecheck", "lint", "test"} {
+			if script, ok := scripts[name]; ok && !strings.Contains(script, "no test specified") {
+				add(name, runner+" run "+name, "package.json")
+			}
+		}
+	}
+	if exists("pyproject.toml") || exists("setup.py") || exists("setup.cfg") {
+		pyproject, _ := os.ReadFile(filepath.Join(root, "pyproject.toml"))
+		if exists("ruff.toml") || exists(".ruff.toml") || strings.Contains(string(pyproject), "[tool.ruff") {
+			add("lint", "ruff check .", "pyproject.toml")
+		}
+		if exists("pytest.ini") || exists("conftest.py") || exists("tests") || strings.Contains(string(pyproject), "pytest") {
+			add("test", "pytest", "pyproject.toml")
+		}
+	}
+	for _, name := range makeTargets(filepath.Join(root, "Makefile")) {
+		add(name, "make "+name, "Makefile")
+	}
+
+	return sortChecks(found)
+}
+
+// packageScripts returns the scripts of a package.json, or nil without one
+func packageScripts(path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return nil
+	}
+	if pkg.Scripts == nil {
+		return map[string]string{}
+	}
+	return pkg.Scripts
+}
+
+// nodeRunner picks the package manager the project's lockfile belongs to
+func nodeRunner(exists func(string) bool) string {
+	switch {
+	case exists("pnpm-lock.yaml"):
+		return "pnpm"
+	case exists("yarn.lock"):
+		return "yarn"
+	case exists("bun.lockb") || exists("bun.lock"):
+		return "bun"
+	}
+	return "npm"
+}
+
+var makeTargetPattern = regexp.MustCompile(`(?m)^(build|lint|test)\s*:`)
+
+// makeTargets returns the build, lint and test targets of a Makefile
+func makeTargets(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var targets []string
+	for _, match := range makeTargetPattern.FindAllStringSubmatch(string(data), -1) {
+		targets = append(targets, match[1])
+	}
+	return targets
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
 )
 
 // Confirmer requests a user decision and blocks until it is answered
@@ -18,6 +19,55 @@ type Confirmer interface {
 	ConfirmExecution(ctx context.Context, req events.ToolConfirmationRequest) (bool, error)
 }
 
+// Decision is a user's answer to a confirmation request.
+type Decision struct {
+	Confirmed bool
+	// Edited replaces the proposed command or content when the user
+	// changed it before approving; empty means approved as proposed.
+	Edited string
+	// AlwaysAllow approves the tool for the rest of the session too.
+	AlwaysAllow bool
+	// Feedback is the reason the user gave for declining, if any.
+	Feedback string
+}
+
+// Declined describes a denial for the model: message, followed by the
+// user's reason when they gave one, so the agent can adapt.
+func (d Decision) Declined(message string) string {
+	if d.Feedback == "" {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, d.Feedback)
+}
+
+// Decider is a Confirmer that also reports edits the user made before
+// approving and the reason they gave for declining.
+type Decider interface {
+	Confirmer
+	DecideContent(ctx context.Context, req events.UserConfirmationRequest) (Decision, error)
+	DecideExecution(ctx context.Context, req events.ToolConfirmationRequest) (Decision, error)
+}
+
+// decideContent asks c for a decision, falling back to a plain
+// confirmation when c cannot report edits.
+func decideContent(ctx context.Context, c Confirmer, req events.UserConfirmationRequest) (Decision, error) {
+	if d, ok := c.(Decider); ok {
+		return d.DecideContent(ctx, req)
+	}
+	confirmed, err := c.ConfirmContent(ctx, req)
+	return Decision{Confirmed: confirmed}, err
+}
+
+// decideExecution asks c for a decision, falling back to a plain
+// confirmation when c cannot report edits.
+func decideExecution(ctx context.Context, c Confirmer, req events.ToolConfirmationRequest) (Decision, error) {
+	if d, ok := c.(Decider); ok {
+		return d.DecideExecution(ctx, req)
+	}
+	confirmed, err := c.ConfirmExecution(ctx, req)
+	return Decision{Confirmed: confirmed}, err
+}
+
 // BusConfirmer implements Confirmer over the event bus. It subscribes
 // to each response topic exactly once and correlates answers to waiting
 // requests by execution ID, so repeated confirmations never accumulate
@@ -26,20 +76,25 @@ type BusConfirmer struct {
 	bus events.EventBus
 
 	mu      sync.Mutex
-	waiting map[string]chan bool
+	waiting map[string]chan Decision
 }
 
 // NewBusConfirmer creates a Confirmer over the given bus.
 func NewBusConfirmer(bus events.EventBus) *BusConfirmer {
 	c := &BusConfirmer{
 		bus:     bus,
-		waiting: make(map[string]chan bool),
+		waiting: make(map[string]chan Decision),
 	}
 	events.SubscribeTo(bus, func(resp events.UserConfirmationResponse) {
-		c.deliver(resp.ExecutionID, resp.Confirmed)
+		c.deliver(resp.ExecutionID, Decision{Confirmed: resp.Confirmed, Edited: resp.Content, Feedback: resp.Feedback})
 	})
 	events.SubscribeTo(bus, func(resp events.ToolConfirmationResponse) {
-		c.deliver(resp.ExecutionID, resp.Confirmed)
+		c.deliver(resp.ExecutionID, Decision{
+			Confirmed:   resp.Confirmed,
+			Edited:      resp.Command,
+			AlwaysAllow: resp.AlwaysAllow,
+			Feedback:    resp.Feedback,
+		})
 	})
 	return c
 }
@@ -47,9 +102,23 @@ func NewBusConfirmer(bus events.EventBus) *BusConfirmer {
 // ConfirmContent publishes a user.confirmation.request and waits for
 // the matching user.confirmation.response.
 func (c *BusConfirmer) ConfirmContent(ctx context.Context, req events.UserConfirmationRequest) (bool, error) {
+	decision, err := c.DecideContent(ctx, req)
+	return decision.Confirmed, err
+}
+
+// ConfirmExecution publishes a tool.confirmation.request and waits for
+// the matching tool.confirmation.response.
+func (c *BusConfirmer) ConfirmExecution(ctx context.Context, req events.ToolConfirmationRequest) (bool, error) {
+	decision, err := c.DecideExecution(ctx, req)
+	return decision.Confirmed, err
+}
+
+// DecideContent is ConfirmContent that also returns the user's edit of
+// req.Editable.
+func (c *BusConfirmer) DecideContent(ctx context.Context, req events.UserConfirmationRequest) (Decision, error) {
 	answer, cleanup, err := c.register(req.ExecutionID)
 	if err != nil {
-		return false, err
+		return Decision{}, err
 	}
 	defer cleanup()
 
@@ -57,25 +126,42 @@ func (c *BusConfirmer) ConfirmContent(ctx context.Context, req events.UserConfir
 	return c.await(ctx, answer)
 }
 
-// ConfirmExecution publishes a tool.confirmation.request and waits for
-// the matching tool.confirmation.response.
-func (c *BusConfirmer) ConfirmExecution(ctx context.Context, req events.ToolConfirmationRequest) (bool, error) {
+// DecideExecution is ConfirmExecution that also returns the user's edit
+// of req.Command. Tools the user approved for the rest of the session
+// run without asking again.
+func (c *BusConfirmer) DecideExecution(ctx context.Context, req events.ToolConfirmationRequest) (Decision, error) {
+	approvals, hasApprovals := toolctx.Approvals(ctx)
+	if hasApprovals && approvals.IsToolApproved(req.ToolName, req.Command) {
+		if observe, ok := toolctx.ConfirmationObserver(ctx); ok {
+			observe(true)
+		}
+		return Decision{Confirmed: true}, nil
+	}
+
 	answer, cleanup, err := c.register(req.ExecutionID)
 	if err != nil {
-		return false, err
+		return Decision{}, err
 	}
 	defer cleanup()
 
 	c.bus.Publish(req.Topic(), req)
-	return c.await(ctx, answer)
+	decision, err := c.await(ctx, answer)
+	if err == nil && decision.Confirmed && decision.AlwaysAllow && hasApprovals {
+		command := req.Command
+		if decision.Edited != "" {
+			command = decision.Edited
+		}
+		approvals.ApproveTool(req.ToolName, command)
+	}
+	return decision, err
 }
 
-func (c *BusConfirmer) register(executionID string) (chan bool, func(), error) {
+func (c *BusConfirmer) register(executionID string) (chan Decision, func(), error) {
 	if executionID == "" {
 		return nil, nil, fmt.Errorf("confirmation request requires an execution ID")
 	}
 
-	answer := make(chan bool, 1)
+	answer := make(chan Decision, 1)
 	c.mu.Lock()
 	if _, exists := c.waiting[executionID]; exists {
 		c.mu.Unlock()
@@ -92,16 +178,19 @@ func (c *BusConfirmer) register(executionID string) (chan bool, func(), error) {
 	return answer, cleanup, nil
 }
 
-func (c *BusConfirmer) await(ctx context.Context, answer chan bool) (bool, error) {
+func (c *BusConfirmer) await(ctx context.Context, answer chan Decision) (Decision, error) {
 	select {
-	case confirmed := <-answer:
-		return confirmed, nil
+	case decision := <-answer:
+		if observe, ok := toolctx.ConfirmationObserver(ctx); ok {
+			observe(decision.Confirmed)
+		}
+		return decision, nil
 	case <-ctx.Done():
-		return false, fmt.Errorf("confirmation aborted: %w", ctx.Err())
+		return Decision{}, fmt.Errorf("confirmation aborted: %w", ctx.Err())
 	}
 }
 
-func (c *BusConfirmer) deliver(executionID string, confirmed bool) {
+func (c *BusConfirmer) deliver(executionID string, decision Decision) {
 	c.mu.Lock()
 	ch, ok := c.waiting[executionID]
 	c.mu.Unlock()
@@ -109,7 +198,7 @@ func (c *BusConfirmer) deliver(executionID string, confirmed bool) {
 		return // response for a request we are not waiting on
 	}
 	select {
-	case ch <- confirmed:
+	case ch <- decision:
 	default: // already answered
 	}
 }
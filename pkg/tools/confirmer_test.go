@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -134,3 +135,62 @@ func TestBusConfirmerRespectsContextCancellation(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorIs(t, err, context.Canceled)
 }
+
+func TestBusConfirmerDecideExecutionReturnsEditedCommand(t *testing.T) {
+	bus := events.NewEventBus()
+	confirmer := NewBusConfirmer(bus)
+	events.SubscribeTo(bus, func(req events.ToolConfirmationRequest) {
+		bus.Publish(events.ToolConfirmationResponse{}.Topic(), events.ToolConfirmationResponse{
+			ExecutionID: req.ExecutionID,
+			Confirmed:   true,
+			Command:     "ls -la",
+		})
+	})
+
+	decision, err := confirmer.DecideExecution(context.Background(), events.ToolConfirmationRequest{
+		ExecutionID: "exec-1",
+		ToolName:    "bash",
+		Command:     "ls",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Decision{Confirmed: true, Edited: "ls -la"}, decision)
+}
+
+type fakeApprovals struct{ approved map[string]bool }
+
+func (f *fakeApprovals) IsToolApproved(toolName, command string) bool { return f.approved[toolName] }
+func (f *fakeApprovals) ApproveTool(toolName, command string)         { f.approved[toolName] = true }
+
+func TestBusConfirmerRemembersToolsAllowedForTheSession(t *testing.T) {
+	bus := events.NewEventBus()
+	confirmer := NewBusConfirmer(bus)
+	var asked int
+	var mu sync.Mutex
+	events.SubscribeTo(bus, func(req events.ToolConfirmationRequest) {
+		mu.Lock()
+		asked++
+		mu.Unlock()
+		bus.Publish(events.ToolConfirmationResponse{}.Topic(), events.ToolConfirmationResponse{
+			ExecutionID: req.ExecutionID,
+			Confirmed:   true,
+			AlwaysAllow: true,
+		})
+	})
+	approvals := &fakeApprovals{approved: map[string]bool{}}
+	ctx := toolctx.WithApprovals(context.Background(), approvals)
+
+	for i := range 3 {
+		ok, err := confirmer.ConfirmExecution(ctx, events.ToolConfirmationRequest{
+			ExecutionID: fmt.Sprintf("exec-%d", i),
+			ToolName:    "Bash",
+			Command:     "go test",
+		})
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, asked, "only the first call asks")
+	assert.True(t, approvals.approved["Bash"])
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BuiltinToolSource stands for Genie's own tools in a ToolConflict
+const BuiltinToolSource = "built-in"
+
+// ToolConflict is a tool name offered by more than one source: a built-in
+// tool and an MCP server, or several MCP servers.
+type ToolConflict struct {
+	Name string
+	// Sources offering the name: BuiltinToolSource or MCP server names
+	Sources []string
+	// Kept is the source that keeps the name; empty when none does
+	Kept string
+	// Renamed maps each other MCP server to the name its tool is offered
+	// under instead
+	Renamed map[string]string
+}
+
+// ShadowsBuiltin reports a conflict an MCP server won over a built-in tool
+func (c ToolConflict) ShadowsBuiltin() bool {
+	return c.Kept != "" && c.Kept != BuiltinToolSource && slices.Contains(c.Sources, BuiltinToolSource)
+}
+
+func (c ToolConflict) String() string {
+	sources := make([]string, len(c.Sources))
+	for i, source := range c.Sources {
+		sources[i] = describeToolSource(source)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is offered by %s", c.Name, strings.Join(sources, " and "))
+	if c.Kept != "" {
+		fmt.Fprintf(&b, "; %s keeps the name", describeToolSource(c.Kept))
+	}
+	renamed := make([]string, 0, len(c.Renamed))
+	for _, source := range c.Sources {
+		if name, ok := c.Renamed[source]; ok {
+			renamed = append(renamed, fmt.Sprintf("%s's is %s", describeToolSource(source), name))
+		}
+	}
+	if len(renamed) > 0 {
+		b.WriteString("; " + strings.Join(renamed, ", "))
+	}
+	return b.String()
+}
+
+func describeToolSource(source string) string {
+	if source == BuiltinToolSource {
+		return "the built-in tool"
+	}
+	return "MCP server " + source
+}
+
+// ToolNameResolver is implemented by MCP clients that rename their tools
+// when they clash with built-in tools or with each other, rather than
+// letting one silently replace another.
+type ToolNameResolver interface {
+	// ResolveToolNames settles the name of every tool given the names of
+	// the built-in tools, and returns the conflicts it found. GetTools and
+	// GetToolsByServer return the tools under their settled names.
+	ResolveToolNames(builtin []string) []ToolConflict
+}
@@ -0,0 +1,680 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// DockerToolSetName is the toolset personas reference as "@docker".
+const DockerToolSetName = "docker"
+
+const (
+	// dockerTimeout bounds listing containers and images and reading logs
+	dockerTimeout = 60 * time.Second
+
+	// dockerExecDefaultTimeout and dockerExecMaxTimeout bound a command
+	// run inside a container
+	dockerExecDefaultTimeout = 2 * time.Minute
+	dockerExecMaxTimeout     = 30 * time.Minute
+
+	// dockerBuildTimeout bounds an image build
+	dockerBuildTimeout = 30 * time.Minute
+
+	// dockerLogsDefaultTail and dockerLogsMaxTail bound the log lines read
+	dockerLogsDefaultTail = 200
+	dockerLogsMaxTail     = 2000
+
+	// dockerOutputLines is how much of exec and build output is kept: its end
+	dockerOutputLines = 200
+)
+
+// dockerNamePattern matches container names and IDs, and image references
+// when a tag or digest follows; a leading dash would read as a flag.
+var (
+	dockerNamePattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	dockerImagePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:/@-]*$`)
+)
+
+// DockerRunner runs the container CLI with args in dir and returns its
+// standard output, with standard error mixed in when combined is set. The
+// output is returned also when the command fails, with an error wrapping
+// the *exec.ExitError. ctx bounds the run.
+type DockerRunner func(ctx context.Context, dir string, combined bool, args ...string) ([]byte, error)
+
+// containerCLI returns the container CLI on PATH: docker, or podman,
+// which accepts the same commands.
+func containerCLI() (string, error) {
+	for _, name := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("neither docker nor podman is installed")
+}
+
+// RunDockerCLI is the DockerRunner backed by docker, or podman without it.
+func RunDockerCLI(ctx context.Context, dir string, combined bool, args ...string) ([]byte, error) {
+	cli, err := containerCLI()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, cli, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "NO_COLOR=1")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if combined {
+		cmd.Stderr = &stdout
+	}
+	cmd.WaitDelay = 3 * time.Second
+
+	if err := cmd.Run(); err != nil {
+		command := cli + " " + strings.Join(args[:min(1, len(args))], " ")
+		if ctx.Err() == context.DeadlineExceeded {
+			return stdout.Bytes(), fmt.Errorf("%s timed out", command)
+		}
+		// Wrap err so callers can read the exit code
+		if message := strings.TrimSpace(stderr.String()); !combined && message != "" {
+			return stdout.Bytes(), fmt.Errorf("%s: %s (%w)", command, message, err)
+		}
+		return stdout.Bytes(), fmt.Errorf("%s: %w", command, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// NewDockerTools returns the tools of the "@docker" toolset. They drive
+// the docker or podman CLI through run; pass nil for RunDockerCLI.
+// Running a command in a container asks for confirmation first.
+func NewDockerTools(eventBus events.EventBus, run DockerRunner) []Tool {
+	base := dockerTool{publisher: eventBus, run: run}
+	if run == nil {
+		base.run, base.usesCLI = RunDockerCLI, true
+	}
+	if eventBus != nil {
+		base.confirmer = NewBusConfirmer(eventBus)
+	}
+	return []Tool{
+		&DockerContainersTool{base},
+		&DockerImagesTool{base},
+		&DockerLogsTool{base},
+		&DockerExecTool{base},
+		&DockerBuildTool{base},
+	}
+}
+
+// dockerTool holds what every container tool shares.
+type dockerTool struct {
+	publisher events.Publisher
+	confirmer Confirmer
+	run       DockerRunner
+	usesCLI   bool // run is RunDockerCLI, which needs docker or podman on PATH
+}
+
+// CheckAvailable reports a missing container CLI, so personas that ask
+// for @docker start without the tools instead of failing every call.
+func (d dockerTool) CheckAvailable() error {
+	if !d.usesCLI {
+		return nil
+	}
+	_, err := containerCLI()
+	return err
+}
+
+func (d dockerTool) announce(toolName string, params map[string]any) error {
+	if d.publisher == nil {
+		return nil
+	}
+	msg, ok := params["_display_message"].(string)
+	if !ok || msg == "" {
+		return fmt.Errorf("_display_message parameter is required")
+	}
+	d.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{ToolName: toolName, Message: msg})
+	return nil
+}
+
+// docker runs the CLI in the session's working directory within timeout.
+func (d dockerTool) docker(ctx context.Context, timeout time.Duration, combined bool, args ...string) ([]byte, error) {
+	dir := ""
+	if cwd, ok := toolctx.WorkingDir(ctx); ok {
+		dir = cwd
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return d.run(ctx, dir, combined, args...)
+}
+
+// dockerDisplayMessage is the _display_message parameter of the tools.
+func dockerDisplayMessage(example string) *ai.Schema {
+	return &ai.Schema{
+		Type:        ai.TypeString,
+		Description: fmt.Sprintf("Short user-facing status (e.g. '%s').", example),
+		MinLength:   5,
+		MaxLength:   200,
+	}
+}
+
+// dockerContainerParameter names the container a tool works on.
+func dockerContainerParameter() *ai.Schema {
+	return &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Container name or ID, as dockerContainers lists them.",
+		MinLength:   1,
+		MaxLength:   200,
+	}
+}
+
+func dockerContainer(params map[string]any) (string, error) {
+	container, _ := params["container"].(string)
+	if !dockerNamePattern.MatchString(container) {
+		return "", fmt.Errorf("container must be a container name or ID, got %q", container)
+	}
+	return container, nil
+}
+
+// dockerResponseSchema is the response shape shared by the container tools.
+func dockerResponseSchema() *ai.Schema {
+	return &ai.Schema{
+		Type: ai.TypeObject,
+		Properties: map[string]*ai.Schema{
+			"success":   {Type: ai.TypeBoolean},
+			"results":   {Type: ai.TypeString, Description: "Human-readable result"},
+			"exit_code": {Type: ai.TypeInteger, Description: "Exit code of a command run in a container"},
+			"error":     {Type: ai.TypeString},
+		},
+		Required: []string{"success"},
+	}
+}
+
+// dockerTable aligns the tab-separated lines the --format templates print
+// under header.
+func dockerTable(header string, output []byte) string {
+	// Only newlines are trimmed: an empty last cell keeps its tab, and
+	// with it the row's columns
+	rows := strings.TrimRight(string(output), "\r\n")
+	if strings.TrimSpace(rows) == "" {
+		return ""
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\n%s\n", header, rows)
+	_ = w.Flush()
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatDockerOutput renders a container tool result for the host UI.
+func formatDockerOutput(title string, result map[string]interface{}) string {
+	if success, _ := result["success"].(bool); !success {
+		msg, _ := result["error"].(string)
+		if output, _ := result["results"].(string); output != "" {
+			return fmt.Sprintf("**%s failed**: %s\n```\n%s\n```", title, msg, strings.TrimRight(output, "\n"))
+		}
+		return fmt.Sprintf("**%s failed**: %s", title, msg)
+	}
+	if msg, _ := result["results"].(string); msg != "" {
+		return fmt.Sprintf("**%s**\n```\n%s\n```", title, strings.TrimRight(msg, "\n"))
+	}
+	return fmt.Sprintf("**%s**: empty", title)
+}
+
+// DockerContainersTool lists containers.
+type DockerContainersTool struct{ dockerTool }
+
+// Declaration returns the function declaration for dockerContainers.
+func (t *DockerContainersTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name:        "dockerContainers",
+		Description: "List Docker or Podman containers with their ID, name, image, status and ports. Running ones only unless all is set.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for dockerContainers",
+			Properties: map[string]*ai.Schema{
+				"all": {
+					Type:        ai.TypeBoolean,
+					Description: "Include stopped containers.",
+				},
+				"_display_message": dockerDisplayMessage("checking which containers are up"),
+			},
+			Required: []string{"_display_message"},
+		},
+		Response: dockerResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for dockerContainers.
+func (t *DockerContainersTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("dockerContainers", params); err != nil {
+			return nil, err
+		}
+		args := []string{"ps", "--format", "{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Status}}\t{{.Ports}}"}
+		if all, _ := params["all"].(bool); all {
+			args = append(args, "--all")
+		}
+		out, err := t.docker(ctx, dockerTimeout, false, args...)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		results := dockerTable("ID\tNAME\tIMAGE\tSTATUS\tPORTS", out)
+		if results == "" {
+			results = "(no containers)"
+		}
+		return map[string]any{"success": true, "results": results}, nil
+	}
+}
+
+// FormatOutput formats the container list for the host UI.
+func (t *DockerContainersTool) FormatOutput(result map[string]interface{}) string {
+	return formatDockerOutput("Containers", result)
+}
+
+// DockerImagesTool lists local images.
+type DockerImagesTool struct{ dockerTool }
+
+// Declaration returns the function declaration for dockerImages.
+func (t *DockerImagesTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name:        "dockerImages",
+		Description: "List local Docker or Podman images with their repository, tag, ID, size and age.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for dockerImages",
+			Properties: map[string]*ai.Schema{
+				"repository": {
+					Type:        ai.TypeString,
+					Description: "Optional repository to list the tags of, e.g. 'postgres'.",
+					MaxLength:   200,
+				},
+				"_display_message": dockerDisplayMessage("looking for the app image"),
+			},
+			Required: []string{"_display_message"},
+		},
+		Response: dockerResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for dockerImages.
+func (t *DockerImagesTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("dockerImages", params); err != nil {
+			return nil, err
+		}
+		args := []string{"images", "--format", "{{.Repository}}\t{{.Tag}}\t{{.ID}}\t{{.Size}}\t{{.CreatedSince}}"}
+		if repository, _ := params["repository"].(string); repository != "" {
+			if !dockerImagePattern.MatchString(repository) {
+				return failResult(fmt.Sprintf("repository must be an image name, got %q", repository)), nil
+			}
+			args = append(args, repository)
+		}
+		out, err := t.docker(ctx, dockerTimeout, false, args...)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		results := dockerTable("REPOSITORY\tTAG\tID\tSIZE\tCREATED", out)
+		if results == "" {
+			results = "(no images)"
+		}
+		return map[string]any{"success": true, "results": results}, nil
+	}
+}
+
+// FormatOutput formats the image list for the host UI.
+func (t *DockerImagesTool) FormatOutput(result map[string]interface{}) string {
+	return formatDockerOutput("Images", result)
+}
+
+// DockerLogsTool reads the end of a container's logs.
+type DockerLogsTool struct{ dockerTool }
+
+// Declaration returns the function declaration for dockerLogs.
+func (t *DockerLogsTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name:        "dockerLogs",
+		Description: "Read the last lines a container wrote to stdout and stderr, to see why it crashed or misbehaves.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for dockerLogs",
+			Properties: map[string]*ai.Schema{
+				"container": dockerContainerParameter(),
+				"tail": {
+					Type:        ai.TypeInteger,
+					Description: fmt.Sprintf("Lines from the end to return (1–%d, default %d).", dockerLogsMaxTail, dockerLogsDefaultTail),
+					Minimum:     1,
+					Maximum:     dockerLogsMaxTail,
+				},
+				"since": {
+					Type:        ai.TypeString,
+					Description: "Only logs newer than this: a duration like '10m' or a timestamp like '2024-05-01T10:00:00'.",
+					MaxLength:   50,
+				},
+				"timestamps": {
+					Type:        ai.TypeBoolean,
+					Description: "Prefix each line with its timestamp.",
+				},
+				"_display_message": dockerDisplayMessage("reading the api container's logs"),
+			},
+			Required: []string{"container", "_display_message"},
+		},
+		Response: dockerResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for dockerLogs.
+func (t *DockerLogsTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("dockerLogs", params); err != nil {
+			return nil, err
+		}
+		container, err := dockerContainer(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		tail := int64(dockerLogsDefaultTail)
+		if v, ok := numberValue(params, "tail"); ok {
+			tail = max(1, min(v, dockerLogsMaxTail))
+		}
+		args := []string{"logs", "--tail", fmt.Sprint(tail)}
+		if since, _ := params["since"].(string); since != "" {
+			if strings.HasPrefix(since, "-") {
+				return failResult(fmt.Sprintf("since must be a duration or timestamp, got %q", since)), nil
+			}
+			args = append(args, "--since", since)
+		}
+		if timestamps, _ := params["timestamps"].(bool); timestamps {
+			args = append(args, "--timestamps")
+		}
+		args = append(args, container)
+
+		// Containers log to both streams; keep them interleaved
+		out, err := t.docker(ctx, dockerTimeout, true, args...)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		results := strings.TrimRight(string(out), "\n")
+		if results == "" {
+			results = "(no log output)"
+		}
+		return map[string]any{"success": true, "results": results}, nil
+	}
+}
+
+// FormatOutput formats the logs for the host UI.
+func (t *DockerLogsTool) FormatOutput(result map[string]interface{}) string {
+	return formatDockerOutput("Logs", result)
+}
+
+// DockerExecTool runs a command inside a running container after the user
+// confirms it.
+type DockerExecTool struct{ dockerTool }
+
+// Declaration returns the function declaration for dockerExec.
+func (t *DockerExecTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "dockerExec",
+		Description: "Run a shell command inside a running container, e.g. to check its config files, " +
+			"environment or network. The user is asked to confirm first. The command runs with sh -c, " +
+			"without a terminal, so it must not wait for input.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for dockerExec",
+			Properties: map[string]*ai.Schema{
+				"container": dockerContainerParameter(),
+				"command": {
+					Type:        ai.TypeString,
+					Description: "Shell command to run in the container, e.g. 'cat /etc/nginx/nginx.conf'.",
+					MinLength:   1,
+					MaxLength:   4000,
+				},
+				"user": {
+					Type:        ai.TypeString,
+					Description: "Optional user to run as, e.g. 'root'.",
+					MaxLength:   100,
+				},
+				"workdir": {
+					Type:        ai.TypeString,
+					Description: "Optional directory inside the container to run in.",
+					MaxLength:   500,
+				},
+				"timeout_seconds": {
+					Type:        ai.TypeInteger,
+					Description: "Time limit in seconds (default 120, max 1800).",
+					Minimum:     1,
+					Maximum:     float64(dockerExecMaxTimeout / time.Second),
+				},
+				"_display_message": dockerDisplayMessage("checking the nginx config in the container"),
+			},
+			Required: []string{"container", "command", "_display_message"},
+		},
+		Response: dockerResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for dockerExec.
+func (t *DockerExecTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("dockerExec", params); err != nil {
+			return nil, err
+		}
+		container, err := dockerContainer(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		command, _ := params["command"].(string)
+		if strings.TrimSpace(command) == "" {
+			return failResult("command is required"), nil
+		}
+		var options []string
+		if user, _ := params["user"].(string); user != "" {
+			if strings.HasPrefix(user, "-") {
+				return failResult(fmt.Sprintf("invalid user %q", user)), nil
+			}
+			options = append(options, "--user", user)
+		}
+		if workdir, _ := params["workdir"].(string); workdir != "" {
+			if strings.HasPrefix(workdir, "-") {
+				return failResult(fmt.Sprintf("invalid workdir %q", workdir)), nil
+			}
+			options = append(options, "--workdir", workdir)
+		}
+		timeout := dockerExecDefaultTimeout
+		if seconds, ok := numberValue(params, "timeout_seconds"); ok && seconds > 0 {
+			timeout = min(time.Duration(seconds)*time.Second, dockerExecMaxTimeout)
+		}
+
+		if t.confirmer == nil {
+			return failResult("confirmation required but no confirmer is configured"), nil
+		}
+		decision, err := decideExecution(ctx, t.confirmer, events.ToolConfirmationRequest{
+			ExecutionID: uuid.New().String(),
+			ToolName:    "dockerExec",
+			Command:     command,
+			Message:     fmt.Sprintf("Run '%s' in container %s? [y/N]", command, container),
+		})
+		if err != nil {
+			return failResult(fmt.Sprintf("confirmation failed: %v", err)), nil
+		}
+		if !decision.Confirmed {
+			return failResult(decision.Declined("command cancelled by user")), nil
+		}
+		edited := decision.Edited != "" && decision.Edited != command
+		if edited {
+			command = decision.Edited
+		}
+
+		args := append(append([]string{"exec"}, options...), container, "sh", "-c", command)
+		out, err := t.docker(ctx, timeout, true, args...)
+		result := map[string]any{
+			"success":   err == nil,
+			"results":   lastLines(string(out), dockerOutputLines),
+			"exit_code": 0,
+		}
+		if edited {
+			result["edited_command"] = command
+		}
+		if err != nil {
+			result["error"] = err.Error()
+			result["exit_code"] = -1
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				result["exit_code"] = exitErr.ExitCode()
+			}
+		}
+		return result, nil
+	}
+}
+
+// FormatOutput formats the command output for the host UI.
+func (t *DockerExecTool) FormatOutput(result map[string]interface{}) string {
+	return formatDockerOutput("Container command", result)
+}
+
+// DockerBuildTool builds an image from a Dockerfile in the workspace.
+type DockerBuildTool struct{ dockerTool }
+
+// Declaration returns the function declaration for dockerBuild.
+func (t *DockerBuildTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "dockerBuild",
+		Description: "Build a container image from a Dockerfile in the workspace and report the end of the " +
+			"build output, where the failing step is when the build fails.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for dockerBuild",
+			Properties: map[string]*ai.Schema{
+				"context": {
+					Type:        ai.TypeString,
+					Description: "Build context directory in the workspace (default '.').",
+					MaxLength:   500,
+				},
+				"dockerfile": {
+					Type:        ai.TypeString,
+					Description: "Dockerfile path in the workspace (default Dockerfile in the context).",
+					MaxLength:   500,
+				},
+				"tag": {
+					Type:        ai.TypeString,
+					Description: "Optional name:tag for the image, e.g. 'myapp:dev'.",
+					MaxLength:   200,
+				},
+				"target": {
+					Type:        ai.TypeString,
+					Description: "Optional stage of a multi-stage Dockerfile to build.",
+					MaxLength:   100,
+				},
+				"build_args": {
+					Type:        ai.TypeObject,
+					Description: "Optional build arguments, e.g. {\"VERSION\": \"1.2\"}.",
+				},
+				"no_cache": {
+					Type:        ai.TypeBoolean,
+					Description: "Build every step again instead of using the cache.",
+				},
+				"_display_message": dockerDisplayMessage("building the app image"),
+			},
+			Required: []string{"_display_message"},
+		},
+		Response: dockerResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for dockerBuild.
+func (t *DockerBuildTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("dockerBuild", params); err != nil {
+			return nil, err
+		}
+
+		contextDir, _ := params["context"].(string)
+		if contextDir == "" {
+			contextDir = "."
+		}
+		resolvedContext, err := dockerWorkspacePath(ctx, contextDir)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		args := []string{"build"}
+		if dockerfile, _ := params["dockerfile"].(string); dockerfile != "" {
+			resolved, err := dockerWorkspacePath(ctx, dockerfile)
+			if err != nil {
+				return failResult(err.Error()), nil
+			}
+			args = append(args, "--file", resolved)
+		}
+		if tag, _ := params["tag"].(string); tag != "" {
+			if !dockerImagePattern.MatchString(tag) {
+				return failResult(fmt.Sprintf("tag must look like name:tag, got %q", tag)), nil
+			}
+			args = append(args, "--tag", tag)
+		}
+		if target, _ := params["target"].(string); target != "" {
+			if !dockerNamePattern.MatchString(target) {
+				return failResult(fmt.Sprintf("invalid target %q", target)), nil
+			}
+			args = append(args, "--target", target)
+		}
+		if buildArgs, _ := params["build_args"].(map[string]any); len(buildArgs) > 0 {
+			for _, name := range sortedKeys(buildArgs) {
+				args = append(args, "--build-arg", fmt.Sprintf("%s=%v", name, buildArgs[name]))
+			}
+		}
+		if noCache, _ := params["no_cache"].(bool); noCache {
+			args = append(args, "--no-cache")
+		}
+		args = append(args, resolvedContext)
+
+		out, err := t.docker(ctx, dockerBuildTimeout, true, args...)
+		result := map[string]any{
+			"success": err == nil,
+			"results": lastLines(string(out), dockerOutputLines),
+		}
+		if err != nil {
+			result["error"] = err.Error()
+		}
+		return result, nil
+	}
+}
+
+// dockerWorkspacePath resolves a build path, which must be in the workspace
+func dockerWorkspacePath(ctx context.Context, path string) (string, error) {
+	resolved, valid := ResolvePathWithWorkingDirectory(ctx, path)
+	if !valid {
+		return "", FormatPathOutsideWorkspaceError(ctx, path)
+	}
+	if err := CheckPathPolicy(ctx, resolved, IntentRead); err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatOutput formats the build output for the host UI.
+func (t *DockerBuildTool) FormatOutput(result map[string]interface{}) string {
+	return formatDockerOutput("Image build", result)
+}
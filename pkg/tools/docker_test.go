@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocker records container CLI invocations and answers them from
+// canned output keyed by the subcommand.
+type fakeDocker struct {
+	mu       sync.Mutex
+	calls    [][]string
+	combined []bool
+	outputs  map[string]string
+	errs     map[string]error
+}
+
+func (f *fakeDocker) run(ctx context.Context, dir string, combined bool, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, args)
+	f.combined = append(f.combined, combined)
+	return []byte(f.outputs[args[0]]), f.errs[args[0]]
+}
+
+func dockerToolByName(t *testing.T, bus events.EventBus, fake *fakeDocker, name string) Tool {
+	t.Helper()
+	for _, tool := range NewDockerTools(bus, fake.run) {
+		if tool.Declaration().Name == name {
+			return tool
+		}
+	}
+	t.Fatalf("no docker tool named %s", name)
+	return nil
+}
+
+func TestDockerContainersTable(t *testing.T) {
+	fake := &fakeDocker{outputs: map[string]string{
+		"ps": "3f2a\tapi\tmyapp:dev\tUp 2 hours\t0.0.0.0:8080->8080/tcp\n9c1b\tdb\tpostgres:16\tExited (1) 5 minutes ago\t\n",
+	}}
+	tool := dockerToolByName(t, events.NewEventBus(), fake, "dockerContainers")
+
+	result, err := tool.Handler()(context.Background(), map[string]any{"_display_message": "checking containers", "all": true})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, strings.Join([]string{
+		"ID    NAME  IMAGE        STATUS                    PORTS",
+		"3f2a  api   myapp:dev    Up 2 hours                0.0.0.0:8080->8080/tcp",
+		"9c1b  db    postgres:16  Exited (1) 5 minutes ago",
+	}, "\n"), result["results"])
+	assert.Contains(t, fake.calls[0], "--all")
+}
+
+func TestDockerLogsInterleavesStreamsAndCapsTail(t *testing.T) {
+	fake := &fakeDocker{outputs: map[string]string{"logs": "listening on :8080\npanic: nil map\n"}}
+	tool := dockerToolByName(t, events.NewEventBus(), fake, "dockerLogs")
+
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"_display_message": "reading the logs",
+		"container":        "api",
+		"tail":             float64(100000),
+		"since":            "10m",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "listening on :8080\npanic: nil map", result["results"])
+	assert.Equal(t, []string{"logs", "--tail", "2000", "--since", "10m", "api"}, fake.calls[0])
+	assert.True(t, fake.combined[0])
+}
+
+func TestDockerRejectsFlagLikeNames(t *testing.T) {
+	fake := &fakeDocker{}
+	tool := dockerToolByName(t, events.NewEventBus(), fake, "dockerLogs")
+
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"_display_message": "reading the logs",
+		"container":        "--help",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	assert.Empty(t, fake.calls)
+}
+
+func TestDockerExecAsksFirst(t *testing.T) {
+	for _, confirmed := range []bool{false, true} {
+		bus := events.NewEventBus()
+		answerExecutionRequests(bus, confirmed)
+		exitErr := exec.Command("sh", "-c", "exit 3").Run()
+		fake := &fakeDocker{
+			outputs: map[string]string{"exec": "cat: /etc/app.conf: No such file or directory\n"},
+			errs:    map[string]error{"exec": fmt.Errorf("docker exec: %w", exitErr)},
+		}
+		tool := dockerToolByName(t, bus, fake, "dockerExec")
+
+		result, err := tool.Handler()(context.Background(), map[string]any{
+			"_display_message": "checking the config",
+			"container":        "api",
+			"command":          "cat /etc/app.conf",
+			"user":             "root",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, false, result["success"])
+		if !confirmed {
+			assert.Contains(t, result["error"], "cancelled by user")
+			assert.Empty(t, fake.calls, "nothing runs when the user declines")
+			continue
+		}
+		assert.Equal(t, []string{"exec", "--user", "root", "api", "sh", "-c", "cat /etc/app.conf"}, fake.calls[0])
+		assert.Equal(t, 3, result["exit_code"])
+		assert.Contains(t, result["results"], "No such file")
+	}
+}
+
+func TestDockerBuildStaysInWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	ctx := toolctx.WithWorkingDir(context.Background(), workspace)
+	fake := &fakeDocker{outputs: map[string]string{"build": "#5 DONE 0.1s\n"}}
+	tool := dockerToolByName(t, events.NewEventBus(), fake, "dockerBuild")
+
+	result, err := tool.Handler()(ctx, map[string]any{
+		"_display_message": "building the image",
+		"context":          "../elsewhere",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	assert.Empty(t, fake.calls)
+
+	result, err = tool.Handler()(ctx, map[string]any{
+		"_display_message": "building the image",
+		"tag":              "myapp:dev",
+		"build_args":       map[string]any{"VERSION": "1.2", "COMMIT": "abc"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, []string{"build", "--tag", "myapp:dev", "--build-arg", "COMMIT=abc", "--build-arg", "VERSION=1.2", filepath.Clean(workspace)}, fake.calls[0])
+}
+
+func TestDockerToolSetIsRegistered(t *testing.T) {
+	registry := NewDefaultRegistry(events.NewEventBus(), nil, nil, nil)
+	set, ok := registry.GetToolSet(DockerToolSetName)
+	require.True(t, ok)
+	assert.Len(t, set, 5)
+	for _, tool := range set {
+		_, registered := registry.Get(tool.Declaration().Name)
+		assert.True(t, registered)
+	}
+}
+
+func TestDockerToolsNeedACLIOnPath(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	for _, tool := range NewDockerTools(nil, nil) {
+		checker, ok := tool.(AvailabilityChecker)
+		require.True(t, ok, tool.Declaration().Name)
+		assert.ErrorContains(t, checker.CheckAvailable(), "neither docker nor podman is installed")
+	}
+}
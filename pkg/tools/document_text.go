@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+const (
+	// maxDocumentPartBytes bounds each file read from a Word or Excel
+	// archive, which can unpack to far more than the document's size
+	maxDocumentPartBytes = 64 * 1024 * 1024
+
+	docxMIMEType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	xlsxMIMEType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// documentPage is one unit of extracted text: a PDF page, a spreadsheet
+// sheet, or a whole Word document, which has no stored pages.
+type documentPage struct {
+	label string
+	text  string
+}
+
+// extractDocumentText returns the text of a document, page by page.
+func extractDocumentText(mimeType string, data []byte) ([]documentPage, error) {
+	switch mimeType {
+	case "application/pdf":
+		return extractPDFText(data)
+	case docxMIMEType:
+		text, err := extractDOCXText(data)
+		if err != nil {
+			return nil, err
+		}
+		return []documentPage{{label: "Document", text: text}}, nil
+	case xlsxMIMEType:
+		return extractXLSXText(data)
+	}
+	return nil, fmt.Errorf("no text extraction for %s", mimeType)
+}
+
+// extractPDFText reads the text of every page. The PDF reader panics on
+// some malformed files, which is reported as an error.
+func extractPDFText(data []byte) (pages []documentPage, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pages, err = nil, fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	fonts := make(map[string]*pdf.Font)
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		var text string
+		if !page.V.IsNull() {
+			for _, name := range page.Fonts() {
+				if _, ok := fonts[name]; !ok {
+					font := page.Font(name)
+					fonts[name] = &font
+				}
+			}
+			if text, err = page.GetPlainText(fonts); err != nil {
+				return nil, fmt.Errorf("failed to read page %d: %w", i, err)
+			}
+		}
+		pages = append(pages, documentPage{label: fmt.Sprintf("Page %d", i), text: strings.TrimSpace(text)})
+	}
+	return pages, nil
+}
+
+// extractDOCXText reads the paragraphs of a Word document's body.
+func extractDOCXText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open Word document: %w", err)
+	}
+	body, err := readZipFile(archive, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	inRun, inText := false, false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse Word document: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "r":
+				inRun = true
+			case "t":
+				inText = true
+			case "tab":
+				// Paragraph properties list tab stops too; only a tab in a run is text
+				if inRun {
+					text.WriteString("\t")
+				}
+			case "br", "cr":
+				if inRun {
+					text.WriteString("\n")
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "r":
+				inRun = false
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// extractXLSXText reads each sheet of a workbook as tab-separated rows.
+func extractXLSXText(data []byte) ([]documentPage, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spreadsheet: %w", err)
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := unmarshalZipFile(archive, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var relationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := unmarshalZipFile(archive, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(relationships.Items))
+	for _, rel := range relationships.Items {
+		target := strings.TrimPrefix(rel.Target, "/")
+		if !strings.HasPrefix(target, "xl/") {
+			target = path.Join("xl", target)
+		}
+		targets[rel.ID] = target
+	}
+
+	// Workbooks without text cells have no shared strings
+	var shared struct {
+		Items []xlsxRichText `xml:"si"`
+	}
+	if data, err := readZipFile(archive, "xl/sharedStrings.xml"); err == nil {
+		if err := xml.Unmarshal(data, &shared); err != nil {
+			return nil, fmt.Errorf("failed to parse xl/sharedStrings.xml: %w", err)
+		}
+	}
+	sharedStrings := make([]string, len(shared.Items))
+	for i, item := range shared.Items {
+		sharedStrings[i] = item.String()
+	}
+
+	var pages []documentPage
+	for i, sheet := range workbook.Sheets {
+		var worksheet struct {
+			Rows []struct {
+				Cells []struct {
+					Ref    string       `xml:"r,attr"`
+					Type   string       `xml:"t,attr"`
+					Value  string       `xml:"v"`
+					Inline xlsxRichText `xml:"is"`
+				} `xml:"c"`
+			} `xml:"sheetData>row"`
+		}
+		if err := unmarshalZipFile(archive, targets[sheet.ID], &worksheet); err != nil {
+			return nil, err
+		}
+
+		var rows []string
+		for _, row := range worksheet.Rows {
+			var cells []string
+			for _, cell := range row.Cells {
+				value := cell.Value
+				switch cell.Type {
+				case "s":
+					if index, err := strconv.Atoi(value); err == nil && index >= 0 && index < len(sharedStrings) {
+						value = sharedStrings[index]
+					}
+				case "inlineStr":
+					value = cell.Inline.String()
+				case "b":
+					value = strconv.FormatBool(value == "1")
+				}
+				// Keep cells in their columns when the row skips empty ones
+				if column := xlsxColumn(cell.Ref); column > len(cells) {
+					cells = append(cells, make([]string, column-len(cells))...)
+				}
+				cells = append(cells, value)
+			}
+			rows = append(rows, strings.TrimRight(strings.Join(cells, "\t"), "\t"))
+		}
+		pages = append(pages, documentPage{
+			label: fmt.Sprintf("Sheet %d: %s", i+1, sheet.Name),
+			text:  strings.TrimSpace(strings.Join(rows, "\n")),
+		})
+	}
+	return pages, nil
+}
+
+// xlsxRichText is a string cell, plain or split into formatted runs
+type xlsxRichText struct {
+	Text string   `xml:"t"`
+	Runs []string `xml:"r>t"`
+}
+
+func (r xlsxRichText) String() string {
+	return r.Text + strings.Join(r.Runs, "")
+}
+
+// xlsxColumn returns the zero-based column of a cell reference such as
+// "C7", or -1 when there is none.
+func xlsxColumn(ref string) int {
+	column := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		column = column*26 + int(ch-'A'+1)
+	}
+	return column - 1
+}
+
+func readZipFile(archive *zip.Reader, name string) ([]byte, error) {
+	file, err := archive.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("document is missing %s", name)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxDocumentPartBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(data) > maxDocumentPartBytes {
+		return nil, fmt.Errorf("%s unpacks to more than %d bytes", name, maxDocumentPartBytes)
+	}
+	return data, nil
+}
+
+func unmarshalZipFile(archive *zip.Reader, name string, v any) error {
+	data, err := readZipFile(archive, name)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// parsePageRanges reads a page selection such as "1-3,7" or "5-" against
+// a document of count pages, returning the page numbers in order.
+func parsePageRanges(selection string, count int) ([]int, error) {
+	selection = strings.TrimSpace(selection)
+	if selection == "" {
+		pages := make([]int, count)
+		for i := range pages {
+			pages[i] = i + 1
+		}
+		return pages, nil
+	}
+
+	seen := make(map[int]bool)
+	var pages []int
+	for _, part := range strings.Split(selection, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return nil, fmt.Errorf("invalid page range %q", part)
+		}
+		end := start
+		if isRange {
+			if last = strings.TrimSpace(last); last == "" {
+				end = count
+			} else if end, err = strconv.Atoi(last); err != nil {
+				return nil, fmt.Errorf("invalid page range %q", part)
+			}
+		}
+		if start < 1 || end < start || start > count {
+			return nil, fmt.Errorf("page range %q is outside the document's %d pages", part, count)
+		}
+		for page := start; page <= min(end, count); page++ {
+			if !seen[page] {
+				seen[page] = true
+				pages = append(pages, page)
+			}
+		}
+	}
+	sort.Ints(pages)
+	return pages, nil
+}
+
+// formatPageRanges writes page numbers back as ranges, e.g. "1-3,7".
+func formatPageRanges(pages []int) string {
+	var parts []string
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(pages[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", pages[i], pages[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/hooks"
 )
 
 // MaxEditFileSize caps the size of files editFile will read. Beyond
@@ -44,9 +45,18 @@ func (e *EditTool) Declaration() *ai.FunctionDeclaration {
 			"replacement. Both line numbers are 1-indexed and inclusive. " +
 			"replacement is the new content for that range (omit the " +
 			"replacement parameter to delete the lines).\n\n" +
+			"  (3) Notebook cell (.ipynb only): provide cell (1-indexed, " +
+			"as readFile numbers them) and cell_source to replace that " +
+			"cell's source, keeping its outputs. cell_action 'insert' adds " +
+			"a cell of cell_type before that position (one past the last " +
+			"cell appends); 'delete' removes it. In notebooks, " +
+			"str_replace mode matches within cell sources; line ranges " +
+			"are not supported.\n\n" +
 			"Modes are mutually exclusive — provide one set of parameters " +
 			"or the other. The tool writes via temp-file + atomic rename, " +
-			"so concurrent readers never see a partial state.",
+			"so concurrent readers never see a partial state. Edits that " +
+			"would introduce syntax errors into Go, Python, Java or JSON " +
+			"files are refused with the errors, so they can be fixed.",
 		Parameters: &ai.Schema{
 			Type:        ai.TypeObject,
 			Description: "Parameters for editing a file",
@@ -79,6 +89,26 @@ func (e *EditTool) Declaration() *ai.FunctionDeclaration {
 					Type:        ai.TypeString,
 					Description: "(line-range mode) New content for the line range. Use an empty string to delete the lines. Trailing newline is added automatically if missing.",
 				},
+				"cell": {
+					Type:        ai.TypeInteger,
+					Description: "(notebook cell mode) Cell to edit, 1-indexed as readFile shows it.",
+					Minimum:     1,
+				},
+				"cell_source": {
+					Type:        ai.TypeString,
+					Description: "(notebook cell mode) New source for the cell; for insert, the new cell's source.",
+				},
+				"cell_action": {
+					Type:        ai.TypeString,
+					Description: "(notebook cell mode) replace (default), insert or delete.",
+					Enum:        []string{"replace", "insert", "delete"},
+				},
+				"cell_type": {
+					Type:        ai.TypeString,
+					Description: "(notebook cell mode) Type of an inserted cell: code (default), markdown or raw.",
+					Enum:        []string{"code", "markdown", "raw"},
+				},
+				"allow_syntax_errors": allowSyntaxErrorsSchema(),
 				"_display_message": {
 					Type:        ai.TypeString,
 					Description: "Short user-facing status shown in the host UI while this tool runs. Frame it in the user's terms (e.g., 'updating the README intro'). Separate channel from your chat reply — don't repeat it there.",
@@ -166,10 +196,19 @@ func (e *EditTool) Handler() ai.HandlerFunc {
 			updated []byte
 			summary string
 		)
-		switch mode {
-		case editModeStrReplace:
+		notebookFile := isNotebookPath(resolved)
+		switch {
+		case notebookFile && mode == editModeStrReplace:
+			updated, summary, err = applyNotebookStrReplace(original, params)
+		case notebookFile && mode == editModeCell:
+			updated, summary, err = applyNotebookCellEdit(original, params)
+		case notebookFile:
+			err = fmt.Errorf("line ranges do not map onto notebook cells; edit a cell with cell and cell_source, or use old_string and new_string")
+		case mode == editModeCell:
+			err = fmt.Errorf("cell parameters only apply to Jupyter notebooks (.ipynb)")
+		case mode == editModeStrReplace:
 			updated, summary, err = applyStrReplace(original, params)
-		case editModeLineRange:
+		case mode == editModeLineRange:
 			updated, summary, err = applyLineRange(original, params)
 		default:
 			err = fmt.Errorf("unknown edit mode")
@@ -177,6 +216,14 @@ func (e *EditTool) Handler() ai.HandlerFunc {
 		if err != nil {
 			return failResult(err.Error()), nil
 		}
+		if report := syntaxErrorReport(ctx, resolved, original, updated, params); report != "" {
+			return failResult(report), nil
+		}
+
+		updated, err = hooks.FromContext(ctx).RunPreEdit(ctx, "editFile", resolved, updated)
+		if err != nil {
+			return failResult("the change was not applied: " + err.Error()), nil
+		}
 
 		if err := atomicWriteFile(resolved, updated, info.Mode().Perm()); err != nil {
 			return failResult(fmt.Sprintf("write file: %v", err)), nil
@@ -211,6 +258,7 @@ const (
 	editModeUnspecified editMode = iota
 	editModeStrReplace
 	editModeLineRange
+	editModeCell
 )
 
 // pickEditMode determines which mode the caller asked for, returning an
@@ -224,7 +272,18 @@ func pickEditMode(params map[string]any) (editMode, error) {
 
 	strMode := hasOld || hasNew
 	lineMode := hasStart || hasEnd || hasReplacement
+	cellMode := hasNumber(params, "cell") || hasString(params, "cell_source") || hasString(params, "cell_action")
 
+	if cellMode {
+		if strMode || lineMode {
+			return editModeUnspecified, fmt.Errorf(
+				"editFile: cell parameters cannot be combined with str_replace or line-range parameters")
+		}
+		if !hasNumber(params, "cell") {
+			return editModeUnspecified, fmt.Errorf("editFile cell mode requires cell")
+		}
+		return editModeCell, nil
+	}
 	if strMode && lineMode {
 		return editModeUnspecified, fmt.Errorf(
 			"editFile: provide either (old_string + new_string) for str_replace mode, " +
@@ -4,10 +4,12 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/hooks"
 	"github.com/kcaldas/genie/pkg/toolctx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -284,3 +286,41 @@ func TestEditTool_RejectsReadOnly(t *testing.T) {
 	got, _ := os.ReadFile(filepath.Join(workspace, "README.md"))
 	assert.Equal(t, "hello\n", string(got))
 }
+
+func TestEditTool_PreEditHookCanBlock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook is a POSIX shell command")
+	}
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "x.txt"),
+		[]byte("hello there\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, ".genie"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, ".genie", hooks.ConfigFileName), []byte(
+		"pre_edit:\n  - command: \"grep -q TODO && { echo 'no TODOs' >&2; exit 2; }; exit 0\"\n"), 0o644))
+	config, err := hooks.LoadConfig(workspace, t.TempDir(), false)
+	require.NoError(t, err)
+
+	handler := NewEditTool(&events.NoOpPublisher{}).Handler()
+	ctx := hooks.WithConfig(toolctx.WithWorkingDir(context.Background(), workspace), config)
+
+	r, err := handler(ctx, map[string]any{
+		"path":             "x.txt",
+		"old_string":       "there",
+		"new_string":       "TODO",
+		"_display_message": "editing",
+	})
+	require.NoError(t, err)
+	assert.False(t, r["success"].(bool))
+	assert.Contains(t, r["error"].(string), "no TODOs")
+	got, _ := os.ReadFile(filepath.Join(workspace, "x.txt"))
+	assert.Equal(t, "hello there\n", string(got), "file unchanged when a hook blocks the edit")
+
+	r, err = handler(ctx, map[string]any{
+		"path":             "x.txt",
+		"old_string":       "there",
+		"new_string":       "world",
+		"_display_message": "editing",
+	})
+	require.NoError(t, err)
+	assert.True(t, r["success"].(bool))
+}
@@ -0,0 +1,58 @@
+package tools
+
+// filteredRegistry exposes only an allowed subset of another registry's
+// tools. Registration, initialization and shutdown go to the base registry.
+type filteredRegistry struct {
+	Registry
+	allowed map[string]bool
+}
+
+// NewFilteredRegistry returns a view of base restricted to the named tools.
+// Tool sets are filtered too, so personas that list a set only receive its
+// allowed members.
+func NewFilteredRegistry(base Registry, allowed []string) Registry {
+	set := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		set[name] = true
+	}
+	return &filteredRegistry{Registry: base, allowed: set}
+}
+
+func (r *filteredRegistry) GetAll() []Tool {
+	return r.filter(r.Registry.GetAll())
+}
+
+func (r *filteredRegistry) Get(name string) (Tool, bool) {
+	if !r.allowed[name] {
+		return nil, false
+	}
+	return r.Registry.Get(name)
+}
+
+func (r *filteredRegistry) Names() []string {
+	var names []string
+	for _, name := range r.Registry.Names() {
+		if r.allowed[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (r *filteredRegistry) GetToolSet(setName string) ([]Tool, bool) {
+	tools, ok := r.Registry.GetToolSet(setName)
+	if !ok {
+		return nil, false
+	}
+	return r.filter(tools), true
+}
+
+func (r *filteredRegistry) filter(tools []Tool) []Tool {
+	var allowed []Tool
+	for _, tool := range tools {
+		if r.allowed[tool.Declaration().Name] {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
@@ -190,6 +190,10 @@ func (f *FindTool) Handler() ai.HandlerFunc {
 				}
 				return nil
 			}
+			// Directories leading to the scope are walked, not reported
+			if scopeAncestor(ctx, p) {
+				return nil
+			}
 
 			// Type filter
 			isDir := d.IsDir()
@@ -125,7 +125,7 @@ func (g *GitCommitTool) Handler() ai.HandlerFunc {
 		if err != nil {
 			return failResult(err.Error()), nil
 		}
-		if err := CheckPathPolicy(ctx, repoPath, IntentMutate); err != nil {
+		if err := checkRepoPolicy(ctx, repoPath); err != nil {
 			return failResult(err.Error()), nil
 		}
 
@@ -140,6 +140,7 @@ func (g *GitCommitTool) Handler() ai.HandlerFunc {
 		// status — already implicitly single-repo since status is on
 		// one repo.
 		var pathsToAdd []string
+		leftOut := 0
 		if len(explicitPaths) > 0 {
 			pathsToAdd, err = collectExplicitCommitPaths(ctx, repoPath, explicitPaths)
 			if err != nil {
@@ -153,8 +154,18 @@ func (g *GitCommitTool) Handler() ai.HandlerFunc {
 			if st.IsClean() {
 				return failResult("nothing to commit — working tree is clean"), nil
 			}
+			// In a scoped session only the changes inside the scope
+			// are committed
 			for p := range st {
-				pathsToAdd = append(pathsToAdd, p)
+				if inScope(ctx, filepath.Join(repoPath, filepath.FromSlash(p))) {
+					pathsToAdd = append(pathsToAdd, p)
+				} else {
+					leftOut++
+				}
+			}
+			if len(pathsToAdd) == 0 {
+				return failResult(fmt.Sprintf("nothing to commit inside the session's scope (%s); %d changed files lie outside it",
+					strings.Join(ScopeFromContext(ctx), ", "), leftOut)), nil
 			}
 		}
 		sort.Strings(pathsToAdd)
@@ -184,11 +195,15 @@ func (g *GitCommitTool) Handler() ai.HandlerFunc {
 			short = short[:12]
 		}
 
+		results := fmt.Sprintf("committed %s as %s <%s>: %s",
+			short, authorName, authorEmail, firstLine(message))
+		if leftOut > 0 {
+			results += fmt.Sprintf(" (left out %d changed files outside the session's scope)", leftOut)
+		}
 		return map[string]any{
 			"success": true,
-			"results": fmt.Sprintf("committed %s as %s <%s>: %s",
-				short, authorName, authorEmail, firstLine(message)),
-			"sha": hash.String(),
+			"results": results,
+			"sha":     hash.String(),
 		}, nil
 	}
 }
@@ -109,7 +109,7 @@ func (g *GitRestoreTool) Handler() ai.HandlerFunc {
 		if err != nil {
 			return failResult(err.Error()), nil
 		}
-		if err := CheckPathPolicy(ctx, repoPath, IntentMutate); err != nil {
+		if err := checkRepoPolicy(ctx, repoPath); err != nil {
 			return failResult(err.Error()), nil
 		}
 
@@ -0,0 +1,280 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// GitHubToolSetName is the toolset personas reference as "@github".
+const GitHubToolSetName = "github"
+
+// githubTimeout bounds a single gh invocation.
+const githubTimeout = 60 * time.Second
+
+// githubPreviewLength caps the text shown when confirming a post.
+const githubPreviewLength = 2000
+
+// githubRepoPattern matches an OWNER/NAME repository reference.
+var githubRepoPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// GitHubRunner runs the GitHub CLI with args in dir, feeding it stdin when
+// non-nil, and returns its standard output, also when gh exits non-zero
+// (gh pr checks does so for failing checks). Authentication and the
+// default repository come from gh itself (gh auth login, the git remote).
+type GitHubRunner func(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error)
+
+// RunGitHubCLI is the GitHubRunner backed by the gh binary on PATH.
+func RunGitHubCLI(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("the GitHub CLI (gh) is not installed; install it from https://cli.github.com and run gh auth login")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, githubTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Dir = dir
+	// Never prompt: there is no terminal for gh to ask on
+	cmd.Env = append(os.Environ(), "GH_PROMPT_DISABLED=1", "GH_NO_UPDATE_NOTIFIER=1", "NO_COLOR=1")
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("gh %s timed out after %v", strings.Join(args[:min(2, len(args))], " "), githubTimeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return stdout.Bytes(), fmt.Errorf("gh %s: %s", strings.Join(args[:min(2, len(args))], " "), message)
+	}
+	return stdout.Bytes(), nil
+}
+
+// NewGitHubTools returns the tools of the "@github" toolset. They drive the
+// gh CLI through run; pass nil for RunGitHubCLI. Creating issues and posting
+// reviews ask for confirmation first.
+func NewGitHubTools(eventBus events.EventBus, run GitHubRunner) []Tool {
+	base := githubTool{publisher: eventBus, run: run}
+	if run == nil {
+		base.run, base.usesCLI = RunGitHubCLI, true
+	}
+	if eventBus != nil {
+		base.confirmer = NewBusConfirmer(eventBus)
+	}
+	return []Tool{
+		&GitHubListIssuesTool{base},
+		&GitHubIssueTool{base},
+		&GitHubCreateIssueTool{base},
+		&GitHubPullRequestTool{base},
+		&GitHubChecksTool{base},
+		&GitHubReviewTool{base},
+	}
+}
+
+// githubTool holds what every GitHub tool shares.
+type githubTool struct {
+	publisher events.Publisher
+	confirmer Confirmer
+	run       GitHubRunner
+	usesCLI   bool // run is RunGitHubCLI, which needs gh on PATH
+}
+
+// CheckAvailable reports a missing gh binary, so personas that ask for
+// @github start without the GitHub tools instead of failing every call.
+func (g githubTool) CheckAvailable() error {
+	if !g.usesCLI {
+		return nil
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return fmt.Errorf("the GitHub CLI (gh) is not installed; install it from https://cli.github.com")
+	}
+	return nil
+}
+
+// announce publishes the call's display message, which every GitHub tool
+// requires like the git tools do.
+func (g githubTool) announce(toolName string, params map[string]any) error {
+	if g.publisher == nil {
+		return nil
+	}
+	msg, ok := params["_display_message"].(string)
+	if !ok || msg == "" {
+		return fmt.Errorf("_display_message parameter is required")
+	}
+	g.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{ToolName: toolName, Message: msg})
+	return nil
+}
+
+// gh runs gh in the session's working directory.
+func (g githubTool) gh(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	dir := ""
+	if cwd, ok := toolctx.WorkingDir(ctx); ok {
+		dir = cwd
+	}
+	return g.run(ctx, dir, stdin, args...)
+}
+
+// ghJSON runs gh and decodes its JSON output into v.
+func (g githubTool) ghJSON(ctx context.Context, v any, args ...string) error {
+	out, err := g.gh(ctx, nil, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("unexpected gh output: %w", err)
+	}
+	return nil
+}
+
+// confirm asks the user before a call that publishes to GitHub, showing
+// the text that is about to be posted.
+func (g githubTool) confirm(ctx context.Context, toolName, summary, preview string) (Decision, error) {
+	if g.confirmer == nil {
+		return Decision{}, fmt.Errorf("confirmation required but no confirmer is configured")
+	}
+	command := summary
+	if preview = strings.TrimSpace(preview); preview != "" {
+		if runes := []rune(preview); len(runes) > githubPreviewLength {
+			preview = string(runes[:githubPreviewLength]) + "…"
+		}
+		command += "\n\n" + preview
+	}
+	return decideExecution(ctx, g.confirmer, events.ToolConfirmationRequest{
+		ExecutionID: uuid.New().String(),
+		ToolName:    toolName,
+		Command:     command,
+		Message:     fmt.Sprintf("%s? [y/N]", summary),
+	})
+}
+
+// repoArgs returns the --repo flag for an explicit OWNER/NAME parameter;
+// without one gh uses the repository of the working directory.
+func repoArgs(params map[string]any) ([]string, error) {
+	repo, _ := params["repo"].(string)
+	if repo == "" {
+		return nil, nil
+	}
+	if !githubRepoPattern.MatchString(repo) {
+		return nil, fmt.Errorf("repo must look like OWNER/NAME, got %q", repo)
+	}
+	return []string{"--repo", repo}, nil
+}
+
+// apiRepo returns the repository segment of a gh api path. gh fills in
+// the {owner}/{repo} placeholders from the working directory.
+func apiRepo(params map[string]any) (string, error) {
+	repo, _ := params["repo"].(string)
+	if repo == "" {
+		return "{owner}/{repo}", nil
+	}
+	if !githubRepoPattern.MatchString(repo) {
+		return "", fmt.Errorf("repo must look like OWNER/NAME, got %q", repo)
+	}
+	return repo, nil
+}
+
+// requiredNumber reads a positive issue or pull request number.
+func requiredNumber(params map[string]any, key string) (int64, error) {
+	n, ok := numberValue(params, key)
+	if !ok || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive number", key)
+	}
+	return n, nil
+}
+
+// githubCommonProperties are the parameters every GitHub tool accepts.
+func githubCommonProperties(displayExample string) map[string]*ai.Schema {
+	return map[string]*ai.Schema{
+		"repo": {
+			Type:        ai.TypeString,
+			Description: "Optional OWNER/NAME repository. Defaults to the GitHub repository of the working directory.",
+			MaxLength:   200,
+		},
+		"_display_message": {
+			Type:        ai.TypeString,
+			Description: fmt.Sprintf("Short user-facing status (e.g. '%s').", displayExample),
+			MinLength:   5,
+			MaxLength:   200,
+		},
+	}
+}
+
+// githubResponseSchema is the response shape shared by the GitHub tools.
+func githubResponseSchema() *ai.Schema {
+	return &ai.Schema{
+		Type: ai.TypeObject,
+		Properties: map[string]*ai.Schema{
+			"success": {Type: ai.TypeBoolean},
+			"results": {Type: ai.TypeString, Description: "Human-readable result"},
+			"url":     {Type: ai.TypeString, Description: "URL of the created or fetched item, when there is one"},
+			"error":   {Type: ai.TypeString},
+		},
+		Required: []string{"success"},
+	}
+}
+
+// formatGitHubOutput renders a GitHub tool result for the host UI.
+func formatGitHubOutput(title string, result map[string]interface{}) string {
+	if success, _ := result["success"].(bool); !success {
+		if msg, _ := result["error"].(string); msg != "" {
+			return fmt.Sprintf("**%s failed**: %s", title, msg)
+		}
+		return fmt.Sprintf("**%s failed**", title)
+	}
+	if msg, _ := result["results"].(string); msg != "" {
+		return fmt.Sprintf("**%s**\n```\n%s\n```", title, strings.TrimRight(msg, "\n"))
+	}
+	return fmt.Sprintf("**%s**: empty", title)
+}
+
+// githubUser is the author shape gh prints for issues, PRs and comments.
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubComment struct {
+	Author    githubUser `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func labelNames(labels []githubLabel) string {
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		names[i] = label.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// writeComments appends a comment thread to b.
+func writeComments(b *strings.Builder, heading string, comments []githubComment) {
+	if len(comments) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s (%d)\n", heading, len(comments))
+	for _, comment := range comments {
+		fmt.Fprintf(b, "\n@%s on %s:\n%s\n", comment.Author.Login, comment.CreatedAt.Format("2006-01-02"), strings.TrimSpace(comment.Body))
+	}
+}
@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+const (
+	githubListDefaultLimit = 30
+	githubListMaxLimit     = 100
+)
+
+type githubIssue struct {
+	Number    int             `json:"number"`
+	Title     string          `json:"title"`
+	State     string          `json:"state"`
+	Author    githubUser      `json:"author"`
+	Labels    []githubLabel   `json:"labels"`
+	URL       string          `json:"url"`
+	Body      string          `json:"body"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Comments  []githubComment `json:"comments"`
+}
+
+// GitHubListIssuesTool lists a repository's issues.
+type GitHubListIssuesTool struct{ githubTool }
+
+// Declaration returns the function declaration for githubListIssues.
+func (t *GitHubListIssuesTool) Declaration() *ai.FunctionDeclaration {
+	properties := githubCommonProperties("checking the open bugs")
+	properties["state"] = &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Issue state to list: open (default), closed or all.",
+		Enum:        []string{"open", "closed", "all"},
+	}
+	properties["label"] = &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Optional label the issues must have.",
+		MaxLength:   100,
+	}
+	properties["search"] = &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Optional GitHub search query, e.g. 'flaky in:title'.",
+		MaxLength:   500,
+	}
+	properties["limit"] = &ai.Schema{
+		Type:        ai.TypeInteger,
+		Description: fmt.Sprintf("Maximum issues to return (1–%d, default %d).", githubListMaxLimit, githubListDefaultLimit),
+		Minimum:     1,
+		Maximum:     githubListMaxLimit,
+	}
+	return &ai.FunctionDeclaration{
+		Name:        "githubListIssues",
+		Description: "List issues of a GitHub repository with their number, state, title, labels and author.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for githubListIssues",
+			Properties:  properties,
+			Required:    []string{"_display_message"},
+		},
+		Response: githubResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for githubListIssues.
+func (t *GitHubListIssuesTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("githubListIssues", params); err != nil {
+			return nil, err
+		}
+		repo, err := repoArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		limit := githubListDefaultLimit
+		if v, ok := numberValue(params, "limit"); ok {
+			limit = int(max(1, min(v, githubListMaxLimit)))
+		}
+		state, _ := params["state"].(string)
+		if state == "" {
+			state = "open"
+		}
+		args := append([]string{"issue", "list", "--json", "number,title,state,author,labels",
+			"--state", state, "--limit", strconv.Itoa(limit)}, repo...)
+		if label, _ := params["label"].(string); label != "" {
+			args = append(args, "--label", label)
+		}
+		if search, _ := params["search"].(string); search != "" {
+			args = append(args, "--search", search)
+		}
+
+		var issues []githubIssue
+		if err := t.ghJSON(ctx, &issues, args...); err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		lines := make([]string, len(issues))
+		for i, issue := range issues {
+			line := fmt.Sprintf("#%d  [%s]  %s  (@%s)", issue.Number, strings.ToLower(issue.State), issue.Title, issue.Author.Login)
+			if labels := labelNames(issue.Labels); labels != "" {
+				line += "  {" + labels + "}"
+			}
+			lines[i] = line
+		}
+		results := strings.Join(lines, "\n")
+		if results == "" {
+			results = "(no matching issues)"
+		}
+		return map[string]any{"success": true, "results": results, "count": len(issues)}, nil
+	}
+}
+
+// FormatOutput formats the issue list for the host UI.
+func (t *GitHubListIssuesTool) FormatOutput(result map[string]interface{}) string {
+	return formatGitHubOutput("GitHub issues", result)
+}
+
+// GitHubIssueTool shows one issue with its comments.
+type GitHubIssueTool struct{ githubTool }
+
+// Declaration returns the function declaration for githubIssue.
+func (t *GitHubIssueTool) Declaration() *ai.FunctionDeclaration {
+	properties := githubCommonProperties("reading issue #42")
+	properties["number"] = &ai.Schema{
+		Type:        ai.TypeInteger,
+		Description: "Issue number.",
+		Minimum:     1,
+	}
+	return &ai.FunctionDeclaration{
+		Name:        "githubIssue",
+		Description: "Show a GitHub issue: title, state, labels, description and the full comment thread.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for githubIssue",
+			Properties:  properties,
+			Required:    []string{"number", "_display_message"},
+		},
+		Response: githubResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for githubIssue.
+func (t *GitHubIssueTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("githubIssue", params); err != nil {
+			return nil, err
+		}
+		repo, err := repoArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		number, err := requiredNumber(params, "number")
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		var issue githubIssue
+		args := append([]string{"issue", "view", strconv.FormatInt(number, 10),
+			"--json", "number,title,state,author,labels,url,body,createdAt,comments"}, repo...)
+		if err := t.ghJSON(ctx, &issue, args...); err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "#%d %s\n", issue.Number, issue.Title)
+		fmt.Fprintf(&b, "State: %s · opened by @%s on %s\n", strings.ToLower(issue.State), issue.Author.Login, issue.CreatedAt.Format("2006-01-02"))
+		if labels := labelNames(issue.Labels); labels != "" {
+			fmt.Fprintf(&b, "Labels: %s\n", labels)
+		}
+		if body := strings.TrimSpace(issue.Body); body != "" {
+			fmt.Fprintf(&b, "\n%s\n", body)
+		}
+		writeComments(&b, "Comments", issue.Comments)
+		return map[string]any{"success": true, "results": b.String(), "url": issue.URL}, nil
+	}
+}
+
+// FormatOutput formats the issue for the host UI.
+func (t *GitHubIssueTool) FormatOutput(result map[string]interface{}) string {
+	return formatGitHubOutput("GitHub issue", result)
+}
+
+// GitHubCreateIssueTool opens a new issue after the user confirms it.
+type GitHubCreateIssueTool struct{ githubTool }
+
+// Declaration returns the function declaration for githubCreateIssue.
+func (t *GitHubCreateIssueTool) Declaration() *ai.FunctionDeclaration {
+	properties := githubCommonProperties("filing the flaky test as an issue")
+	properties["title"] = &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Issue title.",
+		MinLength:   1,
+		MaxLength:   256,
+	}
+	properties["body"] = &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Issue description in GitHub-flavored markdown.",
+	}
+	properties["labels"] = &ai.Schema{
+		Type:        ai.TypeArray,
+		Description: "Optional labels to apply. They must already exist in the repository.",
+		Items:       &ai.Schema{Type: ai.TypeString},
+	}
+	return &ai.FunctionDeclaration{
+		Name:        "githubCreateIssue",
+		Description: "Open a new GitHub issue. The user is asked to confirm before it is created. Returns the issue URL.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for githubCreateIssue",
+			Properties:  properties,
+			Required:    []string{"title", "body", "_display_message"},
+		},
+		Response: githubResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for githubCreateIssue.
+func (t *GitHubCreateIssueTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("githubCreateIssue", params); err != nil {
+			return nil, err
+		}
+		repo, err := repoArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		title, _ := params["title"].(string)
+		body, _ := params["body"].(string)
+		if strings.TrimSpace(title) == "" {
+			return failResult("title is required"), nil
+		}
+
+		args := append([]string{"issue", "create", "--title", title, "--body-file", "-"}, repo...)
+		if labels, ok := params["labels"].([]any); ok {
+			for _, label := range labels {
+				if name, ok := label.(string); ok && name != "" {
+					args = append(args, "--label", name)
+				}
+			}
+		}
+
+		decision, err := t.confirm(ctx, "githubCreateIssue", fmt.Sprintf("Create GitHub issue %q", title), body)
+		if err != nil {
+			return failResult(fmt.Sprintf("confirmation failed: %v", err)), nil
+		}
+		if !decision.Confirmed {
+			return failResult(decision.Declined("issue creation cancelled by user")), nil
+		}
+
+		out, err := t.gh(ctx, []byte(body), args...)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		url := strings.TrimSpace(string(out))
+		return map[string]any{"success": true, "results": "Created " + url, "url": url}, nil
+	}
+}
+
+// FormatOutput formats the created issue for the host UI.
+func (t *GitHubCreateIssueTool) FormatOutput(result map[string]interface{}) string {
+	return formatGitHubOutput("GitHub issue created", result)
+}
@@ -0,0 +1,261 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+type githubPullRequest struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	State       string          `json:"state"`
+	IsDraft     bool            `json:"isDraft"`
+	Author      githubUser      `json:"author"`
+	BaseRefName string          `json:"baseRefName"`
+	HeadRefName string          `json:"headRefName"`
+	URL         string          `json:"url"`
+	Body        string          `json:"body"`
+	Additions   int             `json:"additions"`
+	Deletions   int             `json:"deletions"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Comments    []githubComment `json:"comments"`
+	Reviews     []struct {
+		Author      githubUser `json:"author"`
+		Body        string     `json:"body"`
+		State       string     `json:"state"`
+		SubmittedAt time.Time  `json:"submittedAt"`
+	} `json:"reviews"`
+	Files []struct {
+		Path      string `json:"path"`
+		Additions int    `json:"additions"`
+		Deletions int    `json:"deletions"`
+	} `json:"files"`
+}
+
+// githubReviewComment is an inline comment as the REST API returns it.
+type githubReviewComment struct {
+	User githubUser `json:"user"`
+	Path string     `json:"path"`
+	Line int        `json:"line"`
+	Body string     `json:"body"`
+}
+
+// GitHubPullRequestTool fetches a pull request with its discussion and diff.
+type GitHubPullRequestTool struct{ githubTool }
+
+// Declaration returns the function declaration for githubPullRequest.
+func (t *GitHubPullRequestTool) Declaration() *ai.FunctionDeclaration {
+	properties := githubCommonProperties("reading PR #123")
+	properties["number"] = &ai.Schema{
+		Type:        ai.TypeInteger,
+		Description: "Pull request number. Defaults to the pull request of the current branch.",
+		Minimum:     1,
+	}
+	properties["include_diff"] = &ai.Schema{
+		Type:        ai.TypeBoolean,
+		Description: "Include the unified diff (default true).",
+	}
+	return &ai.FunctionDeclaration{
+		Name: "githubPullRequest",
+		Description: "Fetch a GitHub pull request: title, description, branches, changed files, " +
+			"conversation comments, reviews, inline review comments and the unified diff.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for githubPullRequest",
+			Properties:  properties,
+			Required:    []string{"_display_message"},
+		},
+		Response: &ai.Schema{
+			Type: ai.TypeObject,
+			Properties: map[string]*ai.Schema{
+				"success": {Type: ai.TypeBoolean},
+				"results": {Type: ai.TypeString, Description: "Pull request details and discussion"},
+				"diff":    {Type: ai.TypeString, Description: "Unified diff of the pull request"},
+				"url":     {Type: ai.TypeString},
+				"error":   {Type: ai.TypeString},
+			},
+			Required: []string{"success"},
+		},
+	}
+}
+
+// Handler returns the function handler for githubPullRequest.
+func (t *GitHubPullRequestTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("githubPullRequest", params); err != nil {
+			return nil, err
+		}
+		repo, err := repoArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		var selector []string
+		if _, ok := params["number"]; ok {
+			number, err := requiredNumber(params, "number")
+			if err != nil {
+				return failResult(err.Error()), nil
+			}
+			selector = []string{strconv.FormatInt(number, 10)}
+		}
+
+		var pr githubPullRequest
+		args := append(append([]string{"pr", "view"}, selector...), "--json",
+			"number,title,state,isDraft,author,baseRefName,headRefName,url,body,additions,deletions,createdAt,comments,reviews,files")
+		if err := t.ghJSON(ctx, &pr, append(args, repo...)...); err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "PR #%d %s\n", pr.Number, pr.Title)
+		state := strings.ToLower(pr.State)
+		if pr.IsDraft {
+			state += " (draft)"
+		}
+		fmt.Fprintf(&b, "State: %s · @%s wants to merge %s into %s · +%d −%d\n",
+			state, pr.Author.Login, pr.HeadRefName, pr.BaseRefName, pr.Additions, pr.Deletions)
+		if body := strings.TrimSpace(pr.Body); body != "" {
+			fmt.Fprintf(&b, "\n%s\n", body)
+		}
+		if len(pr.Files) > 0 {
+			fmt.Fprintf(&b, "\n## Files (%d)\n", len(pr.Files))
+			for _, file := range pr.Files {
+				fmt.Fprintf(&b, "%s  +%d −%d\n", file.Path, file.Additions, file.Deletions)
+			}
+		}
+		writeComments(&b, "Comments", pr.Comments)
+		if len(pr.Reviews) > 0 {
+			fmt.Fprintf(&b, "\n## Reviews (%d)\n", len(pr.Reviews))
+			for _, review := range pr.Reviews {
+				fmt.Fprintf(&b, "\n@%s %s", review.Author.Login, strings.ToLower(review.State))
+				if body := strings.TrimSpace(review.Body); body != "" {
+					fmt.Fprintf(&b, ":\n%s", body)
+				}
+				b.WriteString("\n")
+			}
+		}
+
+		// Inline comments are only exposed by the REST API
+		if apiPath, err := apiRepo(params); err == nil {
+			var inline []githubReviewComment
+			path := fmt.Sprintf("repos/%s/pulls/%d/comments", apiPath, pr.Number)
+			if err := t.ghJSON(ctx, &inline, "api", path, "--paginate"); err == nil && len(inline) > 0 {
+				fmt.Fprintf(&b, "\n## Inline review comments (%d)\n", len(inline))
+				for _, comment := range inline {
+					fmt.Fprintf(&b, "\n@%s on %s:%d:\n%s\n", comment.User.Login, comment.Path, comment.Line, strings.TrimSpace(comment.Body))
+				}
+			}
+		}
+
+		result := map[string]any{"success": true, "results": b.String(), "url": pr.URL}
+		if includeDiff, ok := params["include_diff"].(bool); !ok || includeDiff {
+			diff, err := t.gh(ctx, nil, append([]string{"pr", "diff", strconv.Itoa(pr.Number)}, repo...)...)
+			if err != nil {
+				return failResult(err.Error()), nil
+			}
+			result["diff"] = string(diff)
+		}
+		return result, nil
+	}
+}
+
+// FormatOutput formats the pull request for the host UI.
+func (t *GitHubPullRequestTool) FormatOutput(result map[string]interface{}) string {
+	return formatGitHubOutput("GitHub pull request", result)
+}
+
+type githubCheck struct {
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	Bucket   string `json:"bucket"` // pass, fail, pending, skipping or cancel
+	Link     string `json:"link"`
+	Workflow string `json:"workflow"`
+}
+
+// GitHubChecksTool reports the CI status of a pull request.
+type GitHubChecksTool struct{ githubTool }
+
+// Declaration returns the function declaration for githubChecks.
+func (t *GitHubChecksTool) Declaration() *ai.FunctionDeclaration {
+	properties := githubCommonProperties("checking CI on PR #123")
+	properties["number"] = &ai.Schema{
+		Type:        ai.TypeInteger,
+		Description: "Pull request number. Defaults to the pull request of the current branch.",
+		Minimum:     1,
+	}
+	return &ai.FunctionDeclaration{
+		Name:        "githubChecks",
+		Description: "Show the CI checks of a GitHub pull request: each check's outcome (pass, fail, pending) and its link, plus an overall summary.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for githubChecks",
+			Properties:  properties,
+			Required:    []string{"_display_message"},
+		},
+		Response: githubResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for githubChecks.
+func (t *GitHubChecksTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("githubChecks", params); err != nil {
+			return nil, err
+		}
+		repo, err := repoArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		args := []string{"pr", "checks"}
+		if _, ok := params["number"]; ok {
+			number, err := requiredNumber(params, "number")
+			if err != nil {
+				return failResult(err.Error()), nil
+			}
+			args = append(args, strconv.FormatInt(number, 10))
+		}
+		args = append(append(args, "--json", "name,state,bucket,link,workflow"), repo...)
+
+		// gh exits non-zero when checks fail or are pending but still
+		// prints them; only a missing report is an error.
+		out, runErr := t.gh(ctx, nil, args...)
+		var checks []githubCheck
+		if err := json.Unmarshal(out, &checks); err != nil {
+			if runErr != nil {
+				return failResult(runErr.Error()), nil
+			}
+			return failResult(fmt.Sprintf("unexpected gh output: %v", err)), nil
+		}
+
+		counts := make(map[string]int)
+		lines := make([]string, len(checks))
+		for i, check := range checks {
+			counts[check.Bucket]++
+			name := check.Name
+			if check.Workflow != "" {
+				name = check.Workflow + " / " + name
+			}
+			lines[i] = fmt.Sprintf("%-8s %s  %s", check.Bucket, name, check.Link)
+		}
+		summary := fmt.Sprintf("%d checks: %d passed, %d failed, %d pending", len(checks), counts["pass"], counts["fail"], counts["pending"])
+		if len(checks) == 0 {
+			summary = "No checks reported"
+		}
+		return map[string]any{
+			"success": true,
+			"results": strings.Join(append([]string{summary}, lines...), "\n"),
+			"failed":  counts["fail"],
+			"pending": counts["pending"],
+		}, nil
+	}
+}
+
+// FormatOutput formats the checks for the host UI.
+func (t *GitHubChecksTool) FormatOutput(result map[string]interface{}) string {
+	return formatGitHubOutput("GitHub checks", result)
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// githubReviewEvents maps the review outcomes the model picks to the
+// REST API's review events.
+var githubReviewEvents = map[string]string{
+	"comment":         "COMMENT",
+	"approve":         "APPROVE",
+	"request_changes": "REQUEST_CHANGES",
+}
+
+type githubReviewRequest struct {
+	Body     string                     `json:"body,omitempty"`
+	Event    string                     `json:"event"`
+	Comments []githubReviewDraftComment `json:"comments,omitempty"`
+}
+
+type githubReviewDraftComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// GitHubReviewTool posts a pull request review after the user confirms it.
+type GitHubReviewTool struct{ githubTool }
+
+// Declaration returns the function declaration for githubReview.
+func (t *GitHubReviewTool) Declaration() *ai.FunctionDeclaration {
+	properties := githubCommonProperties("posting the review on PR #123")
+	properties["number"] = &ai.Schema{
+		Type:        ai.TypeInteger,
+		Description: "Pull request number.",
+		Minimum:     1,
+	}
+	properties["event"] = &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Review outcome: comment (default), approve or request_changes.",
+		Enum:        []string{"comment", "approve", "request_changes"},
+	}
+	properties["body"] = &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Overall review summary in GitHub-flavored markdown.",
+	}
+	properties["comments"] = &ai.Schema{
+		Type:        ai.TypeArray,
+		Description: "Inline comments on lines of the new version of changed files.",
+		Items: &ai.Schema{
+			Type: ai.TypeObject,
+			Properties: map[string]*ai.Schema{
+				"path": {Type: ai.TypeString, Description: "Repo-relative file path as shown in the diff."},
+				"line": {Type: ai.TypeInteger, Description: "Line number in the new version of the file; it must be part of the diff.", Minimum: 1},
+				"body": {Type: ai.TypeString, Description: "Comment text."},
+			},
+			Required: []string{"path", "line", "body"},
+		},
+	}
+	return &ai.FunctionDeclaration{
+		Name: "githubReview",
+		Description: "Post a review on a GitHub pull request with an overall summary and optional inline comments. " +
+			"The user sees the review and must confirm before it is posted. Draft the review from githubPullRequest first.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for githubReview",
+			Properties:  properties,
+			Required:    []string{"number", "body", "_display_message"},
+		},
+		Response: githubResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for githubReview.
+func (t *GitHubReviewTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("githubReview", params); err != nil {
+			return nil, err
+		}
+		repo, err := apiRepo(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		number, err := requiredNumber(params, "number")
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		outcome, _ := params["event"].(string)
+		if outcome == "" {
+			outcome = "comment"
+		}
+		event, ok := githubReviewEvents[outcome]
+		if !ok {
+			return failResult(fmt.Sprintf("event must be comment, approve or request_changes, got %q", outcome)), nil
+		}
+
+		review := githubReviewRequest{Event: event}
+		review.Body, _ = params["body"].(string)
+		if raw, ok := params["comments"].([]any); ok {
+			for i, item := range raw {
+				fields, _ := item.(map[string]any)
+				path, _ := fields["path"].(string)
+				body, _ := fields["body"].(string)
+				line, hasLine := numberValue(fields, "line")
+				if path == "" || body == "" || !hasLine || line < 1 {
+					return failResult(fmt.Sprintf("comment %d needs a path, a positive line and a body", i+1)), nil
+				}
+				review.Comments = append(review.Comments, githubReviewDraftComment{Path: path, Line: int(line), Side: "RIGHT", Body: body})
+			}
+		}
+		if strings.TrimSpace(review.Body) == "" && len(review.Comments) == 0 {
+			return failResult("a review needs a body or inline comments"), nil
+		}
+
+		summary := fmt.Sprintf("Post %s review on PR #%d", strings.ReplaceAll(outcome, "_", " "), number)
+		if len(review.Comments) > 0 {
+			summary += fmt.Sprintf(" with %d inline comment(s)", len(review.Comments))
+		}
+		preview := review.Body
+		for _, comment := range review.Comments {
+			preview += fmt.Sprintf("\n\n%s:%d\n%s", comment.Path, comment.Line, comment.Body)
+		}
+		decision, err := t.confirm(ctx, "githubReview", summary, preview)
+		if err != nil {
+			return failResult(fmt.Sprintf("confirmation failed: %v", err)), nil
+		}
+		if !decision.Confirmed {
+			return failResult(decision.Declined("review cancelled by user")), nil
+		}
+
+		payload, err := json.Marshal(review)
+		if err != nil {
+			return failResult(fmt.Sprintf("encode review: %v", err)), nil
+		}
+		out, err := t.gh(ctx, payload, "api", "--method", "POST",
+			fmt.Sprintf("repos/%s/pulls/%d/reviews", repo, number), "--input", "-")
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		var posted struct {
+			HTMLURL string `json:"html_url"`
+		}
+		_ = json.Unmarshal(out, &posted)
+		return map[string]any{"success": true, "results": summary + ": posted " + posted.HTMLURL, "url": posted.HTMLURL}, nil
+	}
+}
+
+// FormatOutput formats the posted review for the host UI.
+func (t *GitHubReviewTool) FormatOutput(result map[string]interface{}) string {
+	return formatGitHubOutput("GitHub review", result)
+}
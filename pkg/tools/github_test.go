@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub records gh invocations and answers them from canned output
+// keyed by the first two arguments.
+type fakeGitHub struct {
+	mu      sync.Mutex
+	calls   [][]string
+	stdin   [][]byte
+	dirs    []string
+	outputs map[string]string
+	errs    map[string]error
+}
+
+func (f *fakeGitHub) run(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, args)
+	f.stdin = append(f.stdin, stdin)
+	f.dirs = append(f.dirs, dir)
+	key := strings.Join(args[:min(2, len(args))], " ")
+	return []byte(f.outputs[key]), f.errs[key]
+}
+
+func githubToolByName(t *testing.T, bus events.EventBus, fake *fakeGitHub, name string) Tool {
+	t.Helper()
+	for _, tool := range NewGitHubTools(bus, fake.run) {
+		if tool.Declaration().Name == name {
+			return tool
+		}
+	}
+	t.Fatalf("no GitHub tool named %s", name)
+	return nil
+}
+
+func TestGitHubListIssues(t *testing.T) {
+	fake := &fakeGitHub{outputs: map[string]string{
+		"issue list": `[{"number":7,"title":"Flaky test","state":"OPEN","author":{"login":"ana"},"labels":[{"name":"bug"}]}]`,
+	}}
+	tool := githubToolByName(t, events.NewEventBus(), fake, "githubListIssues")
+
+	ctx := toolctx.WithWorkingDir(context.Background(), "/work/repo")
+	result, err := tool.Handler()(ctx, map[string]any{
+		"_display_message": "checking the open bugs",
+		"label":            "bug",
+		"limit":            float64(500),
+		"repo":             "kcaldas/genie",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, "#7  [open]  Flaky test  (@ana)  {bug}", result["results"])
+
+	require.Len(t, fake.calls, 1)
+	assert.Equal(t, "/work/repo", fake.dirs[0])
+	args := strings.Join(fake.calls[0], " ")
+	assert.Contains(t, args, "--limit 100")
+	assert.Contains(t, args, "--repo kcaldas/genie")
+	assert.Contains(t, args, "--label bug")
+}
+
+func TestGitHubRejectsMalformedRepo(t *testing.T) {
+	fake := &fakeGitHub{}
+	tool := githubToolByName(t, events.NewEventBus(), fake, "githubIssue")
+
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"_display_message": "reading issue #1",
+		"number":           float64(1),
+		"repo":             "--web",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	assert.Empty(t, fake.calls)
+}
+
+func TestGitHubCreateIssueRequiresConfirmation(t *testing.T) {
+	for _, confirmed := range []bool{false, true} {
+		bus := events.NewEventBus()
+		answerExecutionRequests(bus, confirmed)
+		fake := &fakeGitHub{outputs: map[string]string{
+			"issue create": "https://github.com/kcaldas/genie/issues/8\n",
+		}}
+		tool := githubToolByName(t, bus, fake, "githubCreateIssue")
+
+		result, err := tool.Handler()(context.Background(), map[string]any{
+			"_display_message": "filing the flaky test",
+			"title":            "Flaky test",
+			"body":             "It fails on CI.",
+			"labels":           []any{"bug"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, confirmed, result["success"])
+		if !confirmed {
+			assert.Empty(t, fake.calls, "gh must not run when the user declines")
+			continue
+		}
+		assert.Equal(t, "https://github.com/kcaldas/genie/issues/8", result["url"])
+		assert.Equal(t, "It fails on CI.", string(fake.stdin[0]))
+		assert.Contains(t, strings.Join(fake.calls[0], " "), "--label bug")
+	}
+}
+
+func TestGitHubReviewPostsInlineComments(t *testing.T) {
+	bus := events.NewEventBus()
+	answerExecutionRequests(bus, true)
+	fake := &fakeGitHub{outputs: map[string]string{
+		"api --method": `{"html_url":"https://github.com/kcaldas/genie/pull/123#pullrequestreview-1"}`,
+	}}
+	tool := githubToolByName(t, bus, fake, "githubReview")
+
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"_display_message": "posting the review",
+		"number":           float64(123),
+		"event":            "request_changes",
+		"body":             "Needs a test.",
+		"comments": []any{
+			map[string]any{"path": "main.go", "line": float64(10), "body": "Handle the error."},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Contains(t, result["url"], "pullrequestreview-1")
+
+	assert.Contains(t, fake.calls[0], "repos/{owner}/{repo}/pulls/123/reviews")
+	var payload githubReviewRequest
+	require.NoError(t, json.Unmarshal(fake.stdin[0], &payload))
+	assert.Equal(t, "REQUEST_CHANGES", payload.Event)
+	assert.Equal(t, []githubReviewDraftComment{{Path: "main.go", Line: 10, Side: "RIGHT", Body: "Handle the error."}}, payload.Comments)
+}
+
+func TestGitHubChecksReportsFailuresDespiteExitCode(t *testing.T) {
+	fake := &fakeGitHub{
+		outputs: map[string]string{"pr checks": `[
+			{"name":"test","state":"FAILURE","bucket":"fail","link":"https://ci/1","workflow":"CI"},
+			{"name":"lint","state":"SUCCESS","bucket":"pass","link":"https://ci/2","workflow":"CI"}]`},
+		errs: map[string]error{"pr checks": errors.New("gh pr checks: some checks were not successful")},
+	}
+	tool := githubToolByName(t, events.NewEventBus(), fake, "githubChecks")
+
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"_display_message": "checking CI",
+		"number":           float64(123),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, 1, result["failed"])
+	assert.True(t, strings.HasPrefix(result["results"].(string), "2 checks: 1 passed, 1 failed, 0 pending"))
+}
+
+func TestGitHubToolSetIsRegistered(t *testing.T) {
+	registry := NewDefaultRegistry(events.NewEventBus(), nil, nil, nil)
+	set, ok := registry.GetToolSet(GitHubToolSetName)
+	require.True(t, ok)
+	assert.Len(t, set, 6)
+	for _, tool := range set {
+		_, registered := registry.Get(tool.Declaration().Name)
+		assert.True(t, registered)
+	}
+}
+
+func TestGitHubToolsNeedGHOnPath(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	for _, tool := range NewGitHubTools(nil, nil) {
+		checker, ok := tool.(AvailabilityChecker)
+		require.True(t, ok, tool.Declaration().Name)
+		assert.ErrorContains(t, checker.CheckAvailable(), "GitHub CLI (gh) is not installed")
+	}
+
+	// A custom runner does not need gh
+	fake := &fakeGitHub{}
+	for _, tool := range NewGitHubTools(nil, fake.run) {
+		assert.NoError(t, tool.(AvailabilityChecker).CheckAvailable())
+	}
+}
@@ -155,7 +155,8 @@ func (g *GrepTool) Handler() ai.HandlerFunc {
 			return nil, err
 		}
 
-		args = append(args, resolvedPath)
+		// In a scoped session a search from above the scope covers only it
+		args = append(args, scopeSearchPaths(ctx, resolvedPath)...)
 
 		// Add file pattern if specified
 		if filePattern, exists := params["file_pattern"]; exists {
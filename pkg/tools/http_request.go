@@ -0,0 +1,402 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/httpclient"
+)
+
+const (
+	httpDefaultTimeout = 30 * time.Second
+	httpMaxTimeout     = 5 * time.Minute
+
+	// httpMaxResponseBytes caps the response body read; the rest is
+	// dropped and the result says so
+	httpMaxResponseBytes = 256 * 1024
+
+	// httpMaxRequestBytes caps the request body the model may send
+	httpMaxRequestBytes = 1024 * 1024
+
+	// httpPreviewBytes caps the body shown in the confirmation prompt
+	httpPreviewBytes = 2000
+
+	// httpMaxRedirects caps the same-host redirects followed
+	httpMaxRedirects = 10
+)
+
+var httpMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+// sensitiveHeaders carry credentials; their values never reach the
+// transcript, the confirmation prompt or the result.
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+	"api-key":             true,
+	"x-auth-token":        true,
+	"x-csrf-token":        true,
+}
+
+// redactHeader returns value with its credential replaced, keeping an
+// authorization scheme such as "Bearer" so the request stays readable.
+func redactHeader(name, value string) string {
+	if !sensitiveHeaders[strings.ToLower(name)] {
+		return value
+	}
+	if scheme, _, ok := strings.Cut(value, " "); ok && strings.HasSuffix(strings.ToLower(name), "authorization") {
+		return scheme + " [REDACTED]"
+	}
+	return "[REDACTED]"
+}
+
+// HTTPRequestTool sends an HTTP request after the user confirms it, for
+// debugging APIs and local services.
+type HTTPRequestTool struct {
+	publisher events.Publisher
+	confirmer Confirmer
+	client    *http.Client
+	clientErr error // Why the configured client could not be built
+}
+
+// NewHTTPRequestTool creates the httpRequest tool. Requests go through the
+// configured proxy and certificate authorities, and only redirects to the
+// confirmed host are followed.
+func NewHTTPRequestTool(eventBus events.EventBus) Tool {
+	tool := &HTTPRequestTool{publisher: eventBus}
+	tool.client, tool.clientErr = httpclient.New(config.NewConfigManager())
+	if tool.client != nil {
+		tool.client.CheckRedirect = sameHostRedirect
+	}
+	if eventBus != nil {
+		tool.confirmer = NewBusConfirmer(eventBus)
+	}
+	return tool
+}
+
+// sameHostRedirect follows redirects that stay on the host the user
+// confirmed. A redirect elsewhere is returned as the response, so sending
+// anything to the new host takes another confirmed request.
+func sameHostRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= httpMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", httpMaxRedirects)
+	}
+	if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		return http.ErrUseLastResponse
+	}
+	return nil
+}
+
+// Declaration returns the function declaration for httpRequest.
+func (t *HTTPRequestTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "httpRequest",
+		Description: "Send an HTTP request and return the status, headers and body, with JSON bodies pretty-printed. " +
+			"Use it to debug APIs, such as a service running locally. The user confirms every request. " +
+			fmt.Sprintf("Bodies over %d KB are cut off; credentials in headers are hidden from the user's transcript.", httpMaxResponseBytes/1024),
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for httpRequest",
+			Properties: map[string]*ai.Schema{
+				"method": {
+					Type:        ai.TypeString,
+					Description: "HTTP method. Default: GET.",
+					Enum:        httpMethods,
+				},
+				"url": {
+					Type:        ai.TypeString,
+					Description: "Absolute http or https URL, e.g. 'http://localhost:8080/api/users?limit=5'.",
+					MaxLength:   4000,
+				},
+				"headers": {
+					Type:        ai.TypeObject,
+					Description: "Optional request headers, e.g. {\"Content-Type\": \"application/json\"}.",
+				},
+				"body": {
+					Type:        ai.TypeString,
+					Description: "Optional request body.",
+				},
+				"timeout_seconds": {
+					Type:        ai.TypeInteger,
+					Description: fmt.Sprintf("Seconds to wait for the response (default %d, max %d).", int(httpDefaultTimeout.Seconds()), int(httpMaxTimeout.Seconds())),
+					Minimum:     1,
+					Maximum:     httpMaxTimeout.Seconds(),
+				},
+				"_display_message": {
+					Type:        ai.TypeString,
+					Description: "Short user-facing status (e.g. 'calling the local users endpoint').",
+					MinLength:   5,
+					MaxLength:   200,
+				},
+			},
+			Required: []string{"url", "_display_message"},
+		},
+		Response: &ai.Schema{
+			Type: ai.TypeObject,
+			Properties: map[string]*ai.Schema{
+				"success":     {Type: ai.TypeBoolean, Description: "Whether a response came back, whatever its status"},
+				"status_code": {Type: ai.TypeInteger},
+				"status":      {Type: ai.TypeString},
+				"headers":     {Type: ai.TypeString, Description: "Response headers, one per line"},
+				"body":        {Type: ai.TypeString, Description: "Response body; JSON is pretty-printed"},
+				"truncated":   {Type: ai.TypeBoolean, Description: "Whether the body was cut off"},
+				"redirect":    {Type: ai.TypeString, Description: "Set when a redirect to another host was not followed"},
+				"duration_ms": {Type: ai.TypeInteger},
+				"error":       {Type: ai.TypeString},
+			},
+			Required: []string{"success"},
+		},
+	}
+}
+
+// RedactParameters hides the credentials in the request headers.
+func (t *HTTPRequestTool) RedactParameters(params map[string]any) map[string]any {
+	headers, ok := params["headers"].(map[string]any)
+	if !ok {
+		return params
+	}
+	redacted := make(map[string]any, len(params))
+	for k, v := range params {
+		redacted[k] = v
+	}
+	safe := make(map[string]any, len(headers))
+	for name, value := range headers {
+		s, _ := value.(string)
+		safe[name] = redactHeader(name, s)
+	}
+	redacted["headers"] = safe
+	return redacted
+}
+
+// Handler returns the function handler for httpRequest.
+func (t *HTTPRequestTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if t.publisher != nil {
+			msg, ok := params["_display_message"].(string)
+			if !ok || msg == "" {
+				return nil, fmt.Errorf("_display_message parameter is required")
+			}
+			t.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{ToolName: "httpRequest", Message: msg})
+		}
+
+		if t.clientErr != nil {
+			return failResult(fmt.Sprintf("failed to set up the HTTP client: %v", t.clientErr)), nil
+		}
+		req, err := newHTTPRequest(ctx, params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		timeout := httpDefaultTimeout
+		if seconds, ok := numberValue(params, "timeout_seconds"); ok && seconds > 0 {
+			timeout = min(time.Duration(seconds)*time.Second, httpMaxTimeout)
+		}
+
+		if t.confirmer == nil {
+			return failResult("confirmation required but no confirmer is configured"), nil
+		}
+		requestLine := req.Method + " " + req.URL.Redacted()
+		decision, err := decideExecution(ctx, t.confirmer, events.ToolConfirmationRequest{
+			ExecutionID: uuid.New().String(),
+			ToolName:    "httpRequest",
+			Command:     describeHTTPRequest(req, params),
+			Message:     fmt.Sprintf("Send %s? [y/N]", requestLine),
+		})
+		if err != nil {
+			return failResult(fmt.Sprintf("confirmation failed: %v", err)), nil
+		}
+		if !decision.Confirmed {
+			return failResult(decision.Declined("request cancelled by user")), nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		start := time.Now()
+		resp, err := t.client.Do(req.WithContext(ctx))
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return failResult(fmt.Sprintf("%s timed out after %v", requestLine, timeout)), nil
+			}
+			return failResult(fmt.Sprintf("%s failed: %v", requestLine, err)), nil
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, httpMaxResponseBytes+1))
+		if err != nil {
+			return failResult(fmt.Sprintf("failed to read the response: %v", err)), nil
+		}
+		truncated := len(data) > httpMaxResponseBytes
+		if truncated {
+			data = data[:httpMaxResponseBytes]
+		}
+		result := map[string]any{
+			"success":     true,
+			"status_code": resp.StatusCode,
+			"status":      resp.Status,
+			"headers":     formatHTTPHeaders(resp.Header),
+			"body":        formatHTTPBody(data, resp.Header.Get("Content-Type"), truncated),
+			"truncated":   truncated,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}
+		if location, err := resp.Location(); err == nil && !strings.EqualFold(location.Host, req.URL.Host) {
+			result["redirect"] = fmt.Sprintf("Not followed: the redirect leads to another host, %s. Send a new httpRequest to that URL to follow it.", location.Redacted())
+		}
+		return result, nil
+	}
+}
+
+// newHTTPRequest builds the request the parameters describe.
+func newHTTPRequest(ctx context.Context, params map[string]any) (*http.Request, error) {
+	method := "GET"
+	if m, _ := params["method"].(string); m != "" {
+		method = strings.ToUpper(m)
+	}
+	known := false
+	for _, m := range httpMethods {
+		known = known || m == method
+	}
+	if !known {
+		return nil, fmt.Errorf("method must be one of %s, got %q", strings.Join(httpMethods, ", "), method)
+	}
+
+	rawURL, _ := params["url"].(string)
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http or https URL, got %q", rawURL)
+	}
+
+	body, _ := params["body"].(string)
+	if len(body) > httpMaxRequestBytes {
+		return nil, fmt.Errorf("body is %d bytes; the limit is %d", len(body), httpMaxRequestBytes)
+	}
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %v", err)
+	}
+	headers, _ := params["headers"].(map[string]any)
+	for name, value := range headers {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("header %s must be a string", name)
+		}
+		if strings.EqualFold(name, "Host") {
+			req.Host = s
+			continue
+		}
+		req.Header.Set(name, s)
+	}
+	if body != "" && req.Header.Get("Content-Type") == "" && json.Valid([]byte(body)) {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// describeHTTPRequest renders the request for the confirmation prompt,
+// with credentials hidden and a long body shortened.
+func describeHTTPRequest(req *http.Request, params map[string]any) string {
+	var b strings.Builder
+	b.WriteString(req.Method + " " + req.URL.Redacted())
+	for _, name := range sortedHeaderNames(req.Header) {
+		for _, value := range req.Header[name] {
+			fmt.Fprintf(&b, "\n%s: %s", name, redactHeader(name, value))
+		}
+	}
+	if body, _ := params["body"].(string); body != "" {
+		if len(body) > httpPreviewBytes {
+			body = body[:httpPreviewBytes] + fmt.Sprintf("\n... (%d more bytes)", len(body)-httpPreviewBytes)
+		}
+		b.WriteString("\n\n" + body)
+	}
+	return b.String()
+}
+
+// formatHTTPHeaders renders headers one per line, sorted, with
+// credentials hidden.
+func formatHTTPHeaders(header http.Header) string {
+	var lines []string
+	for _, name := range sortedHeaderNames(header) {
+		for _, value := range header[name] {
+			lines = append(lines, name+": "+redactHeader(name, value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sortedHeaderNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatHTTPBody returns the body as text: JSON indented, binary content
+// described instead of shown.
+func formatHTTPBody(data []byte, contentType string, truncated bool) string {
+	if len(data) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !truncated && (strings.Contains(mediaType, "json") || json.Valid(data)) {
+		var indented bytes.Buffer
+		if json.Indent(&indented, data, "", "  ") == nil {
+			return indented.String()
+		}
+	}
+	text := data
+	if truncated {
+		// The cut can split the last character
+		for i := 0; i < utf8.UTFMax && len(text) > 0 && !utf8.Valid(text); i++ {
+			text = text[:len(text)-1]
+		}
+	}
+	if !utf8.Valid(text) {
+		if mediaType == "" {
+			mediaType = "binary data"
+		}
+		return fmt.Sprintf("(%d bytes of %s, not shown)", len(data), mediaType)
+	}
+	return string(text)
+}
+
+// FormatOutput formats the response for the host UI.
+func (t *HTTPRequestTool) FormatOutput(result map[string]interface{}) string {
+	if success, _ := result["success"].(bool); !success {
+		msg, _ := result["error"].(string)
+		return fmt.Sprintf("**HTTP request failed**: %s", msg)
+	}
+	status, _ := result["status"].(string)
+	var b strings.Builder
+	fmt.Fprintf(&b, "**HTTP %s**", status)
+	if ms, ok := result["duration_ms"].(int64); ok {
+		fmt.Fprintf(&b, " (%d ms)", ms)
+	}
+	if body, _ := result["body"].(string); body != "" {
+		fmt.Fprintf(&b, "\n```\n%s\n```", strings.TrimRight(body, "\n"))
+	}
+	if truncated, _ := result["truncated"].(bool); truncated {
+		fmt.Fprintf(&b, "\n_Body cut off at %d KB_", httpMaxResponseBytes/1024)
+	}
+	return b.String()
+}
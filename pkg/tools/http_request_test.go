@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPRequestSendsAndPrettyPrintsJSON(t *testing.T) {
+	var gotAuth, gotBody, gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc123")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":7,"tags":["a"]}`))
+	}))
+	defer server.Close()
+
+	bus := events.NewEventBus()
+	var prompts []events.ToolConfirmationRequest
+	events.SubscribeTo(bus, func(req events.ToolConfirmationRequest) {
+		prompts = append(prompts, req)
+	})
+	answerExecutionRequests(bus, true)
+	tool := NewHTTPRequestTool(bus)
+
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"_display_message": "creating a user",
+		"method":           "post",
+		"url":              server.URL + "/users",
+		"headers":          map[string]any{"Authorization": "Bearer s3cret"},
+		"body":             `{"name":"ada"}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, 201, result["status_code"])
+	assert.Equal(t, "{\n  \"id\": 7,\n  \"tags\": [\n    \"a\"\n  ]\n}", result["body"])
+	assert.Contains(t, result["headers"], "Set-Cookie: [REDACTED]")
+
+	assert.Equal(t, "Bearer s3cret", gotAuth)
+	assert.Equal(t, `{"name":"ada"}`, gotBody)
+	assert.Equal(t, "application/json", gotType)
+
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0].Command, "POST "+server.URL+"/users")
+	assert.Contains(t, prompts[0].Command, "Authorization: Bearer [REDACTED]")
+	assert.NotContains(t, prompts[0].Command, "s3cret")
+}
+
+func TestHTTPRequestNeedsConfirmation(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer server.Close()
+
+	bus := events.NewEventBus()
+	answerExecutionRequests(bus, false)
+	result, err := NewHTTPRequestTool(bus).Handler()(context.Background(), map[string]any{
+		"_display_message": "deleting a user",
+		"method":           "DELETE",
+		"url":              server.URL + "/users/7",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "cancelled by user")
+	assert.Zero(t, hits)
+}
+
+func TestHTTPRequestCapsResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", httpMaxResponseBytes+100)))
+	}))
+	defer server.Close()
+
+	bus := events.NewEventBus()
+	answerExecutionRequests(bus, true)
+	result, err := NewHTTPRequestTool(bus).Handler()(context.Background(), map[string]any{
+		"_display_message": "fetching the export",
+		"url":              server.URL,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["truncated"])
+	assert.Len(t, result["body"], httpMaxResponseBytes)
+}
+
+func TestHTTPRequestRejectsBadRequests(t *testing.T) {
+	tool := NewHTTPRequestTool(nil)
+	for _, params := range []map[string]any{
+		{"url": "file:///etc/passwd"},
+		{"url": "localhost:8080/health"},
+		{"url": "http://localhost:8080", "method": "TRACE"},
+	} {
+		result, err := tool.Handler()(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, false, result["success"], params)
+	}
+}
+
+func TestFormatHTTPBody(t *testing.T) {
+	assert.Equal(t, "{\n  \"ok\": true\n}", formatHTTPBody([]byte(`{"ok":true}`), "text/plain", false))
+	assert.Equal(t, `{"ok":`, formatHTTPBody([]byte(`{"ok":`), "application/json", true))
+	assert.Equal(t, "(4 bytes of image/png, not shown)", formatHTTPBody([]byte{0x89, 'P', 0xff, 0xfe}, "image/png", false))
+	assert.Equal(t, "caf", formatHTTPBody([]byte("café")[:4], "text/plain", true), "a split character is dropped")
+}
+
+func TestHTTPRequestFollowsOnlySameHostRedirects(t *testing.T) {
+	otherHits := 0
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { otherHits++ }))
+	defer other.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/new":
+			_, _ = w.Write([]byte("moved here"))
+		default:
+			http.Redirect(w, r, other.URL+"/collect", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	bus := events.NewEventBus()
+	answerExecutionRequests(bus, true)
+	tool := NewHTTPRequestTool(bus)
+
+	result, err := tool.Handler()(context.Background(), map[string]any{"_display_message": "fetching the page", "url": server.URL + "/old"})
+	require.NoError(t, err)
+	assert.Equal(t, 200, result["status_code"])
+	assert.Equal(t, "moved here", result["body"])
+	assert.NotContains(t, result, "redirect")
+
+	result, err = tool.Handler()(context.Background(), map[string]any{"_display_message": "fetching the page", "url": server.URL + "/elsewhere"})
+	require.NoError(t, err)
+	assert.Equal(t, 302, result["status_code"])
+	assert.Contains(t, result["redirect"], other.URL+"/collect")
+	assert.Zero(t, otherHits)
+}
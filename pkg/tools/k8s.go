@@ -0,0 +1,446 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// KubernetesToolSetName is the toolset personas reference as "@k8s".
+const KubernetesToolSetName = "k8s"
+
+const (
+	// kubectlTimeout bounds a single kubectl invocation
+	kubectlTimeout = 60 * time.Second
+
+	// kubectlOutputLines caps the output returned to the model: its end
+	kubectlOutputLines = 400
+)
+
+// KubernetesSettings is the policy of the @k8s tools in
+// .genie/settings.json.
+type KubernetesSettings struct {
+	// AllowedVerbs are the mutating kubectl commands k8sCommand may run,
+	// each after confirmation: "scale", or "rollout restart" for one
+	// subcommand. Without them the tools only read.
+	AllowedVerbs []string `json:"allowedVerbs,omitempty"`
+}
+
+// kubectlReadOnlyVerbs are the kubectl commands that never change the
+// cluster. For commands with subcommands, only the listed ones are.
+var kubectlReadOnlyVerbs = map[string][]string{
+	"get":           nil,
+	"describe":      nil,
+	"logs":          nil,
+	"events":        nil,
+	"top":           nil,
+	"explain":       nil,
+	"api-resources": nil,
+	"api-versions":  nil,
+	"version":       nil,
+	"cluster-info":  nil,
+	"diff":          nil,
+	"rollout":       {"status", "history"},
+	"auth":          {"can-i", "whoami"},
+	"config":        {"view", "get-contexts", "current-context", "get-clusters"},
+}
+
+// kubectlInteractiveVerbs wait for a terminal or run until stopped, which
+// a tool call cannot do.
+var kubectlInteractiveVerbs = map[string]bool{
+	"edit": true, "attach": true, "port-forward": true, "proxy": true, "debug": true,
+}
+
+// kubectlNamePattern matches resource kinds, names and kind/name pairs;
+// a leading dash would read as a flag.
+var kubectlNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:/,-]*$`)
+
+// KubectlRunner runs kubectl with args in dir and returns its standard
+// output. The cluster and credentials come from kubectl's own
+// configuration (KUBECONFIG, ~/.kube/config).
+type KubectlRunner func(ctx context.Context, dir string, args ...string) ([]byte, error)
+
+// RunKubectl is the KubectlRunner backed by the kubectl binary on PATH.
+func RunKubectl(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return nil, fmt.Errorf("kubectl is not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kubectlTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		command := "kubectl " + strings.Join(args[:min(1, len(args))], " ")
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s timed out after %v", command, kubectlTimeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return stdout.Bytes(), fmt.Errorf("%s: %s", command, message)
+	}
+	return stdout.Bytes(), nil
+}
+
+// NewKubernetesTools returns the tools of the "@k8s" toolset. They drive
+// kubectl through run; pass nil for RunKubectl. They only read unless
+// .genie/settings.json allows a mutating verb, which k8sCommand then runs
+// after confirmation.
+func NewKubernetesTools(eventBus events.EventBus, run KubectlRunner) []Tool {
+	base := k8sTool{publisher: eventBus, run: run}
+	if run == nil {
+		base.run, base.usesCLI = RunKubectl, true
+	}
+	if eventBus != nil {
+		base.confirmer = NewBusConfirmer(eventBus)
+	}
+	return []Tool{
+		&K8sGetTool{base},
+		&K8sDescribeTool{base},
+		&K8sLogsTool{base},
+		&K8sEventsTool{base},
+		&K8sCommandTool{base},
+	}
+}
+
+// k8sTool holds what every Kubernetes tool shares.
+type k8sTool struct {
+	publisher events.Publisher
+	confirmer Confirmer
+	run       KubectlRunner
+	usesCLI   bool // run is RunKubectl, which needs kubectl on PATH
+}
+
+// CheckAvailable reports a missing kubectl, so personas that ask for @k8s
+// start without the tools instead of failing every call.
+func (k k8sTool) CheckAvailable() error {
+	if !k.usesCLI {
+		return nil
+	}
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return fmt.Errorf("kubectl is not installed")
+	}
+	return nil
+}
+
+func (k k8sTool) announce(toolName string, params map[string]any) error {
+	if k.publisher == nil {
+		return nil
+	}
+	msg, ok := params["_display_message"].(string)
+	if !ok || msg == "" {
+		return fmt.Errorf("_display_message parameter is required")
+	}
+	k.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{ToolName: toolName, Message: msg})
+	return nil
+}
+
+// kubectl runs kubectl in the session's working directory and returns
+// the end of its output.
+func (k k8sTool) kubectl(ctx context.Context, args ...string) (map[string]any, error) {
+	dir := ""
+	if cwd, ok := toolctx.WorkingDir(ctx); ok {
+		dir = cwd
+	}
+	out, err := k.run(ctx, dir, args...)
+	if err != nil {
+		return failResult(err.Error()), nil
+	}
+	results := lastLines(string(out), kubectlOutputLines)
+	if strings.TrimSpace(results) == "" {
+		results = "(no output)"
+	}
+	return map[string]any{"success": true, "results": results}, nil
+}
+
+// k8sCommonProperties are the parameters every Kubernetes tool accepts.
+func k8sCommonProperties(displayExample string) map[string]*ai.Schema {
+	return map[string]*ai.Schema{
+		"namespace": {
+			Type:        ai.TypeString,
+			Description: "Namespace to look in. Defaults to the kubeconfig context's namespace.",
+			MaxLength:   100,
+		},
+		"context": {
+			Type:        ai.TypeString,
+			Description: "Optional kubeconfig context (cluster) to use instead of the current one.",
+			MaxLength:   200,
+		},
+		"_display_message": {
+			Type:        ai.TypeString,
+			Description: fmt.Sprintf("Short user-facing status (e.g. '%s').", displayExample),
+			MinLength:   5,
+			MaxLength:   200,
+		},
+	}
+}
+
+// k8sScopeArgs returns the --context and --namespace flags the common
+// parameters ask for.
+func k8sScopeArgs(params map[string]any) ([]string, error) {
+	var args []string
+	if kubeContext, _ := params["context"].(string); kubeContext != "" {
+		if !kubectlNamePattern.MatchString(kubeContext) {
+			return nil, fmt.Errorf("invalid context %q", kubeContext)
+		}
+		args = append(args, "--context", kubeContext)
+	}
+	if namespace, _ := params["namespace"].(string); namespace != "" {
+		if !kubectlNamePattern.MatchString(namespace) {
+			return nil, fmt.Errorf("invalid namespace %q", namespace)
+		}
+		args = append(args, "--namespace", namespace)
+	}
+	return args, nil
+}
+
+// k8sName reads a required kind, name or kind/name parameter.
+func k8sName(params map[string]any, key string) (string, error) {
+	value, _ := params[key].(string)
+	if !kubectlNamePattern.MatchString(value) {
+		return "", fmt.Errorf("%s must be a Kubernetes name, got %q", key, value)
+	}
+	return value, nil
+}
+
+// k8sResponseSchema is the response shape shared by the Kubernetes tools.
+func k8sResponseSchema() *ai.Schema {
+	return &ai.Schema{
+		Type: ai.TypeObject,
+		Properties: map[string]*ai.Schema{
+			"success": {Type: ai.TypeBoolean},
+			"results": {Type: ai.TypeString, Description: "kubectl output"},
+			"error":   {Type: ai.TypeString},
+		},
+		Required: []string{"success"},
+	}
+}
+
+// formatK8sOutput renders a Kubernetes tool result for the host UI.
+func formatK8sOutput(title string, result map[string]interface{}) string {
+	if success, _ := result["success"].(bool); !success {
+		msg, _ := result["error"].(string)
+		return fmt.Sprintf("**%s failed**: %s", title, msg)
+	}
+	msg, _ := result["results"].(string)
+	return fmt.Sprintf("**%s**\n```\n%s\n```", title, strings.TrimRight(msg, "\n"))
+}
+
+// isSecretKind reports whether a get names secrets, whose values yaml,
+// json and templated output would print
+func isSecretKind(kinds string) bool {
+	for _, kind := range strings.Split(strings.ToLower(kinds), ",") {
+		kind, _, _ = strings.Cut(kind, "/")
+		kind, _, _ = strings.Cut(kind, ".")
+		if kind == "secret" || kind == "secrets" {
+			return true
+		}
+	}
+	return false
+}
+
+// kubectlVerb splits args into the command and, for commands with
+// subcommands, the subcommand.
+func kubectlVerb(args []string) (verb, sub string) {
+	if len(args) == 0 {
+		return "", ""
+	}
+	verb = args[0]
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		sub = args[1]
+	}
+	return verb, sub
+}
+
+// kubectlReadOnly reports whether args only read from the cluster.
+func kubectlReadOnly(args []string) bool {
+	verb, sub := kubectlVerb(args)
+	subs, ok := kubectlReadOnlyVerbs[verb]
+	if !ok {
+		return false
+	}
+	if subs == nil {
+		return true
+	}
+	for _, allowed := range subs {
+		if sub == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// kubectlAllowed reports whether the settings allow the mutating command
+// in args.
+func kubectlAllowed(args []string, settings KubernetesSettings) bool {
+	verb, sub := kubectlVerb(args)
+	for _, allowed := range settings.AllowedVerbs {
+		fields := strings.Fields(allowed)
+		switch {
+		case len(fields) == 1 && fields[0] == verb:
+			return true
+		case len(fields) == 2 && fields[0] == verb && fields[1] == sub:
+			return true
+		}
+	}
+	return false
+}
+
+// kubectlRevealsSecrets reports args that would print secret values or
+// the credentials in the kubeconfig.
+func kubectlRevealsSecrets(args []string) bool {
+	verb, sub := kubectlVerb(args)
+	for i, arg := range args {
+		if arg == "--raw" && verb == "config" {
+			return true
+		}
+		if verb != "get" || !isSecretKind(sub) {
+			continue
+		}
+		output := ""
+		switch {
+		case strings.HasPrefix(arg, "--output="), strings.HasPrefix(arg, "-o="):
+			_, output, _ = strings.Cut(arg, "=")
+		case arg == "-o" || arg == "--output":
+			if i+1 < len(args) {
+				output = args[i+1]
+			}
+		case strings.HasPrefix(arg, "-o"):
+			output = strings.TrimPrefix(arg, "-o")
+		}
+		if output != "" && output != "wide" && output != "name" {
+			return true
+		}
+	}
+	return false
+}
+
+// K8sCommandTool runs any non-interactive kubectl command: read-only ones
+// directly, mutating ones only when the settings allow their verb and the
+// user confirms.
+type K8sCommandTool struct{ k8sTool }
+
+// Declaration returns the function declaration for k8sCommand.
+func (t *K8sCommandTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "k8sCommand",
+		Description: "Run a kubectl command the other k8s tools don't cover, e.g. [\"top\", \"pods\"] or " +
+			"[\"rollout\", \"status\", \"deploy/api\"]. Read-only commands run directly. Commands that change " +
+			"the cluster (apply, delete, scale, rollout restart, exec, ...) are refused unless the project's " +
+			".genie/settings.json lists the verb under kubernetes.allowedVerbs, and then the user is asked to " +
+			"confirm each one. Interactive commands (edit, port-forward, attach, proxy) are not supported.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for k8sCommand",
+			Properties: map[string]*ai.Schema{
+				"args": {
+					Type:        ai.TypeArray,
+					Description: "kubectl arguments, starting with the command, without 'kubectl' itself.",
+					Items:       &ai.Schema{Type: ai.TypeString},
+					MinItems:    1,
+				},
+				"_display_message": {
+					Type:        ai.TypeString,
+					Description: "Short user-facing status (e.g. 'checking the rollout').",
+					MinLength:   5,
+					MaxLength:   200,
+				},
+			},
+			Required: []string{"args", "_display_message"},
+		},
+		Response: k8sResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for k8sCommand.
+func (t *K8sCommandTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("k8sCommand", params); err != nil {
+			return nil, err
+		}
+		var args []string
+		raw, _ := params["args"].([]any)
+		for _, arg := range raw {
+			if s, ok := arg.(string); ok {
+				args = append(args, s)
+			}
+		}
+		if len(args) > 0 && args[0] == "kubectl" {
+			args = args[1:]
+		}
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			return failResult("args must start with the kubectl command, e.g. [\"get\", \"pods\"]"), nil
+		}
+
+		verb, sub := kubectlVerb(args)
+		if kubectlInteractiveVerbs[verb] {
+			return failResult(fmt.Sprintf("kubectl %s is interactive and cannot run as a tool", verb)), nil
+		}
+		if kubectlRevealsSecrets(args) {
+			return failResult("this command would print secret values or kubeconfig credentials; describe the secret to see its keys"), nil
+		}
+		if kubectlReadOnly(args) {
+			return t.kubectl(ctx, args...)
+		}
+
+		command := strings.TrimSpace(verb + " " + sub)
+		if !kubectlAllowed(args, t.settings(ctx)) {
+			return failResult(fmt.Sprintf("kubectl %s changes the cluster and is not allowed; the user can allow it by adding %q "+
+				"to kubernetes.allowedVerbs in .genie/%s", command, verb, ProjectSettingsFile)), nil
+		}
+		if t.confirmer == nil {
+			return failResult("confirmation required but no confirmer is configured"), nil
+		}
+		line := "kubectl " + strings.Join(args, " ")
+		decision, err := decideExecution(ctx, t.confirmer, events.ToolConfirmationRequest{
+			ExecutionID: uuid.New().String(),
+			ToolName:    "k8sCommand",
+			Command:     line,
+			Message:     fmt.Sprintf("Run '%s'? [y/N]", line),
+		})
+		if err != nil {
+			return failResult(fmt.Sprintf("confirmation failed: %v", err)), nil
+		}
+		if !decision.Confirmed {
+			return failResult(decision.Declined("command cancelled by user")), nil
+		}
+		return t.kubectl(ctx, args...)
+	}
+}
+
+// settings returns the workspace's Kubernetes policy; an untrusted
+// workspace gets none.
+func (t *K8sCommandTool) settings(ctx context.Context) KubernetesSettings {
+	if trusted, ok := toolctx.WorkspaceTrusted(ctx); ok && !trusted {
+		return KubernetesSettings{}
+	}
+	root, ok := toolctx.WorkingDir(ctx)
+	if !ok {
+		return KubernetesSettings{}
+	}
+	settings, _ := LoadProjectSettings(root)
+	return settings.Kubernetes
+}
+
+// FormatOutput formats the command output for the host UI.
+func (t *K8sCommandTool) FormatOutput(result map[string]interface{}) string {
+	return formatK8sOutput("kubectl", result)
+}
@@ -0,0 +1,347 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+const (
+	k8sLogsDefaultTail = 200
+	k8sLogsMaxTail     = 2000
+)
+
+// k8sProperties returns the common parameters plus extra.
+func k8sProperties(displayExample string, extra map[string]*ai.Schema) map[string]*ai.Schema {
+	properties := k8sCommonProperties(displayExample)
+	for name, schema := range extra {
+		properties[name] = schema
+	}
+	return properties
+}
+
+// K8sGetTool lists or shows Kubernetes resources.
+type K8sGetTool struct{ k8sTool }
+
+// Declaration returns the function declaration for k8sGet.
+func (t *K8sGetTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "k8sGet",
+		Description: "List Kubernetes resources or show one (kubectl get). Read-only. Use it to see what runs " +
+			"in the cluster and its state: pods, deployments, services, nodes, ingresses, jobs, ...",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for k8sGet",
+			Properties: k8sProperties("listing pods in the payments namespace", map[string]*ai.Schema{
+				"resource": {
+					Type:        ai.TypeString,
+					Description: "Resource kind, e.g. 'pods', 'deploy', 'svc', 'nodes', or several: 'deploy,svc'.",
+					MaxLength:   200,
+				},
+				"name": {
+					Type:        ai.TypeString,
+					Description: "Optional name of one resource to show.",
+					MaxLength:   253,
+				},
+				"all_namespaces": {
+					Type:        ai.TypeBoolean,
+					Description: "List across all namespaces.",
+				},
+				"selector": {
+					Type:        ai.TypeString,
+					Description: "Optional label selector, e.g. 'app=api,tier!=cache'.",
+					MaxLength:   500,
+				},
+				"output": {
+					Type:        ai.TypeString,
+					Description: "Output format: 'wide' for more columns, or 'yaml' / 'json' for the full object. Default: the table.",
+					Enum:        []string{"wide", "yaml", "json"},
+				},
+			}),
+			Required: []string{"resource", "_display_message"},
+		},
+		Response: k8sResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for k8sGet.
+func (t *K8sGetTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("k8sGet", params); err != nil {
+			return nil, err
+		}
+		resource, err := k8sName(params, "resource")
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		args := []string{"get", resource}
+		if name, _ := params["name"].(string); name != "" {
+			if name, err = k8sName(params, "name"); err != nil {
+				return failResult(err.Error()), nil
+			}
+			args = append(args, name)
+		}
+		scope, err := k8sScopeArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		args = append(args, scope...)
+		if all, _ := params["all_namespaces"].(bool); all {
+			args = append(args, "--all-namespaces")
+		}
+		if selector, _ := params["selector"].(string); selector != "" {
+			if strings.HasPrefix(selector, "-") {
+				return failResult(fmt.Sprintf("invalid selector %q", selector)), nil
+			}
+			args = append(args, "--selector", selector)
+		}
+		switch output, _ := params["output"].(string); output {
+		case "":
+		case "wide", "yaml", "json":
+			if output != "wide" && isSecretKind(resource) {
+				return failResult("secret values are not shown; leave out output to list secrets, or use k8sDescribe to see their keys"), nil
+			}
+			args = append(args, "--output", output)
+		default:
+			return failResult(fmt.Sprintf("output must be wide, yaml or json, got %q", output)), nil
+		}
+		return t.kubectl(ctx, args...)
+	}
+}
+
+// FormatOutput formats the resources for the host UI.
+func (t *K8sGetTool) FormatOutput(result map[string]interface{}) string {
+	return formatK8sOutput("kubectl get", result)
+}
+
+// K8sDescribeTool shows the details and recent events of resources.
+type K8sDescribeTool struct{ k8sTool }
+
+// Declaration returns the function declaration for k8sDescribe.
+func (t *K8sDescribeTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "k8sDescribe",
+		Description: "Describe Kubernetes resources (kubectl describe): their configuration, status, conditions " +
+			"and recent events. Read-only. The first place to look when a pod does not start or keeps restarting.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for k8sDescribe",
+			Properties: k8sProperties("describing the crashing api pod", map[string]*ai.Schema{
+				"resource": {
+					Type:        ai.TypeString,
+					Description: "Resource kind, e.g. 'pod', 'deployment', 'node', or 'kind/name'.",
+					MaxLength:   300,
+				},
+				"name": {
+					Type:        ai.TypeString,
+					Description: "Name of the resource, or a prefix matching several. Optional with a selector.",
+					MaxLength:   253,
+				},
+				"selector": {
+					Type:        ai.TypeString,
+					Description: "Optional label selector instead of a name, e.g. 'app=api'.",
+					MaxLength:   500,
+				},
+			}),
+			Required: []string{"resource", "_display_message"},
+		},
+		Response: k8sResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for k8sDescribe.
+func (t *K8sDescribeTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("k8sDescribe", params); err != nil {
+			return nil, err
+		}
+		resource, err := k8sName(params, "resource")
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		args := []string{"describe", resource}
+		if name, _ := params["name"].(string); name != "" {
+			if name, err = k8sName(params, "name"); err != nil {
+				return failResult(err.Error()), nil
+			}
+			args = append(args, name)
+		}
+		if selector, _ := params["selector"].(string); selector != "" {
+			if strings.HasPrefix(selector, "-") {
+				return failResult(fmt.Sprintf("invalid selector %q", selector)), nil
+			}
+			args = append(args, "--selector", selector)
+		}
+		scope, err := k8sScopeArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		return t.kubectl(ctx, append(args, scope...)...)
+	}
+}
+
+// FormatOutput formats the description for the host UI.
+func (t *K8sDescribeTool) FormatOutput(result map[string]interface{}) string {
+	return formatK8sOutput("kubectl describe", result)
+}
+
+// K8sLogsTool reads the logs of a pod's containers.
+type K8sLogsTool struct{ k8sTool }
+
+// Declaration returns the function declaration for k8sLogs.
+func (t *K8sLogsTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "k8sLogs",
+		Description: "Read the end of a pod's logs (kubectl logs). Read-only. Use previous to see why the last " +
+			"run of a restarting container crashed.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for k8sLogs",
+			Properties: k8sProperties("reading the api pod's logs", map[string]*ai.Schema{
+				"pod": {
+					Type:        ai.TypeString,
+					Description: "Pod name, or 'kind/name' such as 'deploy/api' for one of its pods.",
+					MaxLength:   300,
+				},
+				"container": {
+					Type:        ai.TypeString,
+					Description: "Container to read, for pods with several.",
+					MaxLength:   253,
+				},
+				"tail": {
+					Type:        ai.TypeInteger,
+					Description: fmt.Sprintf("Lines from the end to return (1–%d, default %d).", k8sLogsMaxTail, k8sLogsDefaultTail),
+					Minimum:     1,
+					Maximum:     k8sLogsMaxTail,
+				},
+				"since": {
+					Type:        ai.TypeString,
+					Description: "Only logs newer than this duration, e.g. '10m' or '1h'.",
+					MaxLength:   20,
+				},
+				"previous": {
+					Type:        ai.TypeBoolean,
+					Description: "Read the previous, terminated run of the container.",
+				},
+			}),
+			Required: []string{"pod", "_display_message"},
+		},
+		Response: k8sResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for k8sLogs.
+func (t *K8sLogsTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("k8sLogs", params); err != nil {
+			return nil, err
+		}
+		pod, err := k8sName(params, "pod")
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		tail := int64(k8sLogsDefaultTail)
+		if v, ok := numberValue(params, "tail"); ok {
+			tail = max(1, min(v, k8sLogsMaxTail))
+		}
+		args := []string{"logs", pod, "--tail", fmt.Sprint(tail)}
+		if container, _ := params["container"].(string); container != "" {
+			if container, err = k8sName(params, "container"); err != nil {
+				return failResult(err.Error()), nil
+			}
+			args = append(args, "--container", container)
+		}
+		if since, _ := params["since"].(string); since != "" {
+			if strings.HasPrefix(since, "-") {
+				return failResult(fmt.Sprintf("since must be a duration, got %q", since)), nil
+			}
+			args = append(args, "--since", since)
+		}
+		if previous, _ := params["previous"].(bool); previous {
+			args = append(args, "--previous")
+		}
+		scope, err := k8sScopeArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		return t.kubectl(ctx, append(args, scope...)...)
+	}
+}
+
+// FormatOutput formats the logs for the host UI.
+func (t *K8sLogsTool) FormatOutput(result map[string]interface{}) string {
+	return formatK8sOutput("kubectl logs", result)
+}
+
+// K8sEventsTool lists recent cluster events, oldest first.
+type K8sEventsTool struct{ k8sTool }
+
+// Declaration returns the function declaration for k8sEvents.
+func (t *K8sEventsTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "k8sEvents",
+		Description: "List recent Kubernetes events, newest last: scheduling failures, image pull errors, " +
+			"OOM kills, probe failures. Read-only. Optionally only those of one object.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for k8sEvents",
+			Properties: k8sProperties("checking recent events", map[string]*ai.Schema{
+				"object": {
+					Type:        ai.TypeString,
+					Description: "Optional name of the object whose events to list, e.g. a pod name.",
+					MaxLength:   253,
+				},
+				"warnings_only": {
+					Type:        ai.TypeBoolean,
+					Description: "Only Warning events.",
+				},
+				"all_namespaces": {
+					Type:        ai.TypeBoolean,
+					Description: "List events across all namespaces.",
+				},
+			}),
+			Required: []string{"_display_message"},
+		},
+		Response: k8sResponseSchema(),
+	}
+}
+
+// Handler returns the function handler for k8sEvents.
+func (t *K8sEventsTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if err := t.announce("k8sEvents", params); err != nil {
+			return nil, err
+		}
+		args := []string{"get", "events", "--sort-by=.lastTimestamp"}
+		var fields []string
+		if object, _ := params["object"].(string); object != "" {
+			object, err := k8sName(params, "object")
+			if err != nil {
+				return failResult(err.Error()), nil
+			}
+			fields = append(fields, "involvedObject.name="+object)
+		}
+		if warnings, _ := params["warnings_only"].(bool); warnings {
+			fields = append(fields, "type=Warning")
+		}
+		if len(fields) > 0 {
+			args = append(args, "--field-selector", strings.Join(fields, ","))
+		}
+		scope, err := k8sScopeArgs(params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		args = append(args, scope...)
+		if all, _ := params["all_namespaces"].(bool); all {
+			args = append(args, "--all-namespaces")
+		}
+		return t.kubectl(ctx, args...)
+	}
+}
+
+// FormatOutput formats the events for the host UI.
+func (t *K8sEventsTool) FormatOutput(result map[string]interface{}) string {
+	return formatK8sOutput("kubectl events", result)
+}
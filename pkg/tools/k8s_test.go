@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKubectl records kubectl invocations and answers them with output.
+type fakeKubectl struct {
+	mu     sync.Mutex
+	calls  [][]string
+	output string
+}
+
+func (f *fakeKubectl) run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, args)
+	return []byte(f.output), nil
+}
+
+func k8sToolByName(t *testing.T, bus events.EventBus, fake *fakeKubectl, name string) Tool {
+	t.Helper()
+	for _, tool := range NewKubernetesTools(bus, fake.run) {
+		if tool.Declaration().Name == name {
+			return tool
+		}
+	}
+	t.Fatalf("no k8s tool named %s", name)
+	return nil
+}
+
+// k8sWorkspace returns a context for a workspace whose settings allow
+// the given verbs.
+func k8sWorkspace(t *testing.T, settings string) context.Context {
+	t.Helper()
+	root := t.TempDir()
+	if settings != "" {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ".genie"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".genie", ProjectSettingsFile), []byte(settings), 0644))
+	}
+	return toolctx.WithWorkingDir(context.Background(), root)
+}
+
+func TestK8sReadToolsBuildKubectlArgs(t *testing.T) {
+	tests := []struct {
+		tool   string
+		params map[string]any
+		want   []string
+	}{
+		{"k8sGet", map[string]any{"resource": "pods", "namespace": "payments", "selector": "app=api", "output": "wide"},
+			[]string{"get", "pods", "--namespace", "payments", "--selector", "app=api", "--output", "wide"}},
+		{"k8sDescribe", map[string]any{"resource": "deploy/api", "context": "staging"},
+			[]string{"describe", "deploy/api", "--context", "staging"}},
+		{"k8sLogs", map[string]any{"pod": "api-7d9f", "container": "app", "tail": float64(5000), "previous": true},
+			[]string{"logs", "api-7d9f", "--tail", "2000", "--container", "app", "--previous"}},
+		{"k8sEvents", map[string]any{"object": "api-7d9f", "warnings_only": true},
+			[]string{"get", "events", "--sort-by=.lastTimestamp", "--field-selector", "involvedObject.name=api-7d9f,type=Warning"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			fake := &fakeKubectl{output: "NAME READY\n"}
+			tt.params["_display_message"] = "looking at the cluster"
+
+			result, err := k8sToolByName(t, events.NewEventBus(), fake, tt.tool).Handler()(context.Background(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, true, result["success"], result["error"])
+			assert.Equal(t, [][]string{tt.want}, fake.calls)
+		})
+	}
+}
+
+func TestK8sRefusesFlagsAndSecretValues(t *testing.T) {
+	fake := &fakeKubectl{}
+	get := k8sToolByName(t, events.NewEventBus(), fake, "k8sGet")
+	command := k8sToolByName(t, events.NewEventBus(), fake, "k8sCommand")
+
+	for _, params := range []map[string]any{
+		{"resource": "--kubeconfig=/tmp/x"},
+		{"resource": "pods", "namespace": "-A"},
+		{"resource": "secrets", "name": "db", "output": "yaml"},
+	} {
+		params["_display_message"] = "looking at the cluster"
+		result, err := get.Handler()(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, false, result["success"], params)
+	}
+	for _, args := range [][]any{
+		{"get", "secret", "db", "-ojsonpath={.data}"},
+		{"config", "view", "--raw"},
+		{"port-forward", "svc/api", "8080"},
+	} {
+		result, err := command.Handler()(context.Background(), map[string]any{"_display_message": "looking at the cluster", "args": args})
+		require.NoError(t, err)
+		assert.Equal(t, false, result["success"], args)
+	}
+	assert.Empty(t, fake.calls)
+}
+
+func TestK8sCommandRunsReadOnlyCommands(t *testing.T) {
+	fake := &fakeKubectl{output: "deployment \"api\" successfully rolled out\n"}
+	tool := k8sToolByName(t, events.NewEventBus(), fake, "k8sCommand")
+
+	result, err := tool.Handler()(k8sWorkspace(t, ""), map[string]any{
+		"_display_message": "checking the rollout",
+		"args":             []any{"kubectl", "rollout", "status", "deploy/api"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, [][]string{{"rollout", "status", "deploy/api"}}, fake.calls)
+}
+
+func TestK8sCommandRefusesMutationsByDefault(t *testing.T) {
+	fake := &fakeKubectl{}
+	tool := k8sToolByName(t, events.NewEventBus(), fake, "k8sCommand")
+	ctx := k8sWorkspace(t, `{"kubernetes": {"allowedVerbs": ["scale"]}}`)
+
+	for _, args := range [][]any{
+		{"delete", "pod", "api-7d9f"},
+		{"rollout", "restart", "deploy/api"},
+		{"exec", "api-7d9f", "--", "sh"},
+	} {
+		result, err := tool.Handler()(ctx, map[string]any{"_display_message": "changing the cluster", "args": args})
+		require.NoError(t, err)
+		assert.Equal(t, false, result["success"])
+		assert.Contains(t, result["error"], "kubernetes.allowedVerbs")
+	}
+
+	// An untrusted workspace's settings allow nothing
+	untrusted := toolctx.WithWorkspaceTrusted(ctx, false)
+	result, err := tool.Handler()(untrusted, map[string]any{"_display_message": "scaling the api", "args": []any{"scale", "deploy/api", "--replicas=3"}})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	assert.Empty(t, fake.calls)
+}
+
+func TestK8sCommandAsksBeforeAllowedMutations(t *testing.T) {
+	for _, confirmed := range []bool{false, true} {
+		bus := events.NewEventBus()
+		answerExecutionRequests(bus, confirmed)
+		fake := &fakeKubectl{output: "deployment.apps/api restarted\n"}
+		tool := k8sToolByName(t, bus, fake, "k8sCommand")
+		ctx := k8sWorkspace(t, `{"kubernetes": {"allowedVerbs": ["rollout restart"]}}`)
+
+		result, err := tool.Handler()(ctx, map[string]any{
+			"_display_message": "restarting the api",
+			"args":             []any{"rollout", "restart", "deploy/api"},
+		})
+		require.NoError(t, err)
+		if !confirmed {
+			assert.Equal(t, false, result["success"])
+			assert.Contains(t, result["error"], "cancelled by user")
+			assert.Empty(t, fake.calls)
+			continue
+		}
+		assert.Equal(t, true, result["success"])
+		assert.Equal(t, [][]string{{"rollout", "restart", "deploy/api"}}, fake.calls)
+	}
+}
+
+func TestK8sToolSetIsRegistered(t *testing.T) {
+	registry := NewDefaultRegistry(events.NewEventBus(), nil, nil, nil)
+	set, ok := registry.GetToolSet(KubernetesToolSetName)
+	require.True(t, ok)
+	assert.Len(t, set, 5)
+	for _, tool := range set {
+		name := tool.Declaration().Name
+		_, registered := registry.Get(name)
+		assert.True(t, registered)
+		assert.True(t, NeedsNetwork(name), name)
+		assert.Equal(t, name != "k8sCommand", IsReadOnlyTool(name), name)
+	}
+}
+
+func TestK8sToolsNeedKubectlOnPath(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	for _, tool := range NewKubernetesTools(nil, nil) {
+		checker, ok := tool.(AvailabilityChecker)
+		require.True(t, ok, tool.Declaration().Name)
+		assert.ErrorContains(t, checker.CheckAvailable(), "kubectl is not installed")
+	}
+}
@@ -247,7 +247,9 @@ func (l *LsTool) Handler() ai.HandlerFunc {
 		}
 		config.path = resolvedPath
 
-		if config.maxDepth == 1 {
+		// Listing a directory above the scope shows only the way into it,
+		// which the walk filters and ls would not
+		if config.maxDepth == 1 && !scopeAncestor(ctx, config.path) {
 			// Single directory mode - use existing ls command logic
 			return l.handleSingleDirectory(ctx, config)
 		} else {
@@ -377,6 +379,17 @@ func (l *LsTool) handleRecursiveDirectory(ctx context.Context, config listConfig
 			return nil
 		}
 
+		// Leave out what the agent may not read, including what lies
+		// outside the session's scope
+		if path != config.path {
+			if err := CheckPathPolicy(ctx, path, IntentRead); err != nil {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
 		// Skip internal genie context files (already loaded in context)
 		baseName := info.Name()
 		if baseName == "CLAUDE.md" || baseName == "GENIE.md" {
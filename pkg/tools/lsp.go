@@ -0,0 +1,436 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/lsp"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// LSPToolSetName is the toolset personas reference as "@lsp".
+const LSPToolSetName = "lsp"
+
+const (
+	// lspDiagnosticsTimeout bounds the wait for a server to check a file
+	lspDiagnosticsTimeout = 30 * time.Second
+
+	// lspQueryTimeout bounds a definition or references request; the
+	// first one also waits for the server to start
+	lspQueryTimeout = 90 * time.Second
+
+	// lspMaxLocations caps the locations listed in one result
+	lspMaxLocations = 200
+)
+
+// LanguageServers is what the code intelligence tools need from
+// lsp.Manager.
+type LanguageServers interface {
+	ClientFor(ctx context.Context, root, path string) (*lsp.Client, error)
+}
+
+// NewLSPTools returns the tools of the "@lsp" toolset: compiler-grade
+// diagnostics, definitions and references from the language server
+// configured for each file, started on first use.
+func NewLSPTools(publisher events.Publisher, servers LanguageServers) []Tool {
+	base := lspTool{publisher: publisher, servers: servers}
+	return []Tool{
+		&lspDiagnosticsTool{base},
+		&lspDefinitionTool{base},
+		&lspReferencesTool{base},
+	}
+}
+
+type lspTool struct {
+	publisher events.Publisher
+	servers   LanguageServers
+}
+
+// lspPositionParameters are the parameters naming a symbol in a file
+func lspPositionParameters() map[string]*ai.Schema {
+	return map[string]*ai.Schema{
+		"file_path": {
+			Type:        ai.TypeString,
+			Description: "File containing the symbol, relative to the workspace.",
+			MinLength:   1,
+			MaxLength:   500,
+		},
+		"line": {
+			Type:        ai.TypeInteger,
+			Description: "1-indexed line the symbol is on, as readFile numbers lines.",
+			Minimum:     1,
+		},
+		"symbol": {
+			Type:        ai.TypeString,
+			Description: "The identifier on that line, e.g. 'NewClient'. Its first occurrence on the line is used.",
+			MaxLength:   200,
+		},
+		"column": {
+			Type:        ai.TypeInteger,
+			Description: "1-indexed character on the line, instead of symbol.",
+			Minimum:     1,
+		},
+		"_display_message": lspDisplayMessage(),
+	}
+}
+
+func lspDisplayMessage() *ai.Schema {
+	return &ai.Schema{
+		Type:        ai.TypeString,
+		Description: "Short user-facing status shown in the host UI while this tool runs (e.g. 'checking where this is used'). Separate channel from your chat reply — don't repeat it there.",
+		MinLength:   5,
+		MaxLength:   200,
+	}
+}
+
+func lspResponse(description string) *ai.Schema {
+	return &ai.Schema{
+		Type: ai.TypeObject,
+		Properties: map[string]*ai.Schema{
+			"success": {Type: ai.TypeBoolean},
+			"results": {Type: ai.TypeString, Description: description},
+			"count":   {Type: ai.TypeInteger, Description: "Number of items found"},
+			"server":  {Type: ai.TypeString, Description: "Language server that answered"},
+			"error":   {Type: ai.TypeString},
+		},
+		Required: []string{"success"},
+	}
+}
+
+// prepare publishes the display message and resolves the file and the
+// server for it.
+func (t lspTool) prepare(ctx context.Context, toolName string, params map[string]any) (string, string, *lsp.Client, error) {
+	if t.publisher != nil {
+		msg, _ := params["_display_message"].(string)
+		if msg == "" {
+			return "", "", nil, fmt.Errorf("_display_message parameter is required")
+		}
+		t.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{ToolName: toolName, Message: msg})
+	}
+
+	filePath, _ := params["file_path"].(string)
+	if strings.TrimSpace(filePath) == "" {
+		return "", "", nil, toolFailure("file_path parameter is required")
+	}
+	resolved, valid := ResolvePathWithWorkingDirectory(ctx, filePath)
+	if !valid {
+		return "", "", nil, toolFailure(FormatPathOutsideWorkspaceError(ctx, filePath).Error())
+	}
+	if err := CheckPathPolicy(ctx, resolved, IntentRead); err != nil {
+		return "", "", nil, toolFailure(err.Error())
+	}
+	if info, err := os.Stat(resolved); err != nil || info.IsDir() {
+		return "", "", nil, toolFailure(fmt.Sprintf("%s is not a file", filePath))
+	}
+
+	root, ok := toolctx.WorkingDir(ctx)
+	if !ok || root == "" {
+		root, _ = os.Getwd()
+	}
+	client, err := t.servers.ClientFor(ctx, root, resolved)
+	if err != nil {
+		return "", "", nil, toolFailure(err.Error())
+	}
+	return root, resolved, client, nil
+}
+
+// toolFailure marks an error the model should see as a failed result
+// rather than a handler error
+type toolFailure string
+
+func (f toolFailure) Error() string { return string(f) }
+
+func lspResult(err error) (map[string]any, error) {
+	if failure, ok := err.(toolFailure); ok {
+		return failResult(string(failure)), nil
+	}
+	return nil, err
+}
+
+// lspPosition turns the line and symbol or column parameters into a
+// protocol position.
+func lspPosition(path string, params map[string]any) (lsp.Position, error) {
+	line, ok := numberValue(params, "line")
+	if !ok || line < 1 {
+		return lsp.Position{}, toolFailure("line parameter is required (1-indexed)")
+	}
+	text, err := fileLine(path, int(line))
+	if err != nil {
+		return lsp.Position{}, toolFailure(err.Error())
+	}
+
+	if symbol, _ := params["symbol"].(string); strings.TrimSpace(symbol) != "" {
+		offset := strings.Index(text, strings.TrimSpace(symbol))
+		if offset < 0 {
+			return lsp.Position{}, toolFailure(fmt.Sprintf("%q is not on line %d: %s", symbol, line, strings.TrimSpace(text)))
+		}
+		return lsp.Position{Line: int(line) - 1, Character: lsp.UTF16Column(text, offset)}, nil
+	}
+	column, ok := numberValue(params, "column")
+	if !ok || column < 1 {
+		return lsp.Position{}, toolFailure("give the symbol on the line, or its 1-indexed column")
+	}
+	runes := []rune(text)
+	offset := len(string(runes[:min(int(column)-1, len(runes))]))
+	return lsp.Position{Line: int(line) - 1, Character: lsp.UTF16Column(text, offset)}, nil
+}
+
+// fileLine returns line number (1-indexed) of the file at path
+func fileLine(path string, number int) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for current := 1; scanner.Scan(); current++ {
+		if current == number {
+			return scanner.Text(), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("line %d is past the end of the file", number)
+}
+
+// formatLocations lists locations as "path:line:column: source line",
+// relative to root, grouped by file in order.
+func formatLocations(ctx context.Context, locations []lsp.Location) string {
+	sort.SliceStable(locations, func(i, j int) bool {
+		if locations[i].URI != locations[j].URI {
+			return locations[i].URI < locations[j].URI
+		}
+		return locations[i].Range.Start.Line < locations[j].Range.Start.Line
+	})
+
+	var b strings.Builder
+	lines := make(map[string][]string)
+	for i, location := range locations {
+		if i == lspMaxLocations {
+			fmt.Fprintf(&b, "... %d more\n", len(locations)-lspMaxLocations)
+			break
+		}
+		path := lsp.URIPath(location.URI)
+		if _, ok := lines[path]; !ok {
+			if data, err := os.ReadFile(path); err == nil {
+				lines[path] = strings.Split(string(data), "\n")
+			} else {
+				lines[path] = nil
+			}
+		}
+		start := location.Range.Start
+		text, column := "", start.Character+1
+		if start.Line < len(lines[path]) {
+			text = lines[path][start.Line]
+			column = len([]rune(text[:lsp.ByteColumn(text, start.Character)])) + 1
+		}
+		fmt.Fprintf(&b, "%s:%d:%d: %s\n", displayPath(ctx, path), start.Line+1, column, strings.TrimSpace(text))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// displayPath shows workspace files relative to it and others in full
+func displayPath(ctx context.Context, path string) string {
+	if root, ok := toolctx.WorkingDir(ctx); ok {
+		if relative, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(relative, "..") {
+			return relative
+		}
+	}
+	return path
+}
+
+type lspDiagnosticsTool struct{ lspTool }
+
+func (t *lspDiagnosticsTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "getDiagnostics",
+		Description: "Get the compiler and linter errors and warnings the language server reports " +
+			"for a file, as it is on disk now. Use after editing to check the change compiles, " +
+			"rather than running a full build.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for getDiagnostics",
+			Properties: map[string]*ai.Schema{
+				"file_path": {
+					Type:        ai.TypeString,
+					Description: "File to check, relative to the workspace.",
+					MinLength:   1,
+					MaxLength:   500,
+				},
+				"_display_message": lspDisplayMessage(),
+			},
+			Required: []string{"file_path", "_display_message"},
+		},
+		Response: lspResponse("One diagnostic per line: path:line:column: severity: message"),
+	}
+}
+
+func (t *lspDiagnosticsTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		_, path, client, err := t.prepare(ctx, "getDiagnostics", params)
+		if err != nil {
+			return lspResult(err)
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, lspDiagnosticsTimeout)
+		defer cancel()
+		diagnostics, complete, err := client.Diagnostics(waitCtx, path)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		sort.SliceStable(diagnostics, func(i, j int) bool {
+			return diagnostics[i].Range.Start.Line < diagnostics[j].Range.Start.Line
+		})
+		var b strings.Builder
+		for _, d := range diagnostics {
+			message := strings.ReplaceAll(strings.TrimSpace(d.Message), "\n", " ")
+			if d.Source != "" {
+				message += " (" + d.Source + ")"
+			}
+			fmt.Fprintf(&b, "%s:%d:%d: %s: %s\n", displayPath(ctx, path), d.Range.Start.Line+1, d.Range.Start.Character+1, d.Severity, message)
+		}
+		results := strings.TrimRight(b.String(), "\n")
+		if results == "" {
+			results = "no problems found"
+		}
+		if !complete {
+			results += "\n(the language server had not finished checking the file; the list may be incomplete)"
+		}
+		return map[string]any{
+			"success": true,
+			"results": results,
+			"count":   len(diagnostics),
+			"server":  client.Name(),
+		}, nil
+	}
+}
+
+func (t *lspDiagnosticsTool) FormatOutput(result map[string]interface{}) string {
+	return formatLSPOutput("Diagnostics", result)
+}
+
+type lspDefinitionTool struct{ lspTool }
+
+func (t *lspDefinitionTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "gotoDefinition",
+		Description: "Find where a symbol is defined, using the language server: the exact " +
+			"declaration, across packages and dependencies, where a text search would also " +
+			"match unrelated names.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for gotoDefinition",
+			Properties:  lspPositionParameters(),
+			Required:    []string{"file_path", "line", "_display_message"},
+		},
+		Response: lspResponse("One definition per line: path:line:column: source line"),
+	}
+}
+
+func (t *lspDefinitionTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		_, path, client, err := t.prepare(ctx, "gotoDefinition", params)
+		if err != nil {
+			return lspResult(err)
+		}
+		position, err := lspPosition(path, params)
+		if err != nil {
+			return lspResult(err)
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, lspQueryTimeout)
+		defer cancel()
+		locations, err := client.Definition(queryCtx, path, position)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		results := formatLocations(ctx, locations)
+		if results == "" {
+			results = "no definition found"
+		}
+		return map[string]any{
+			"success": true,
+			"results": results,
+			"count":   len(locations),
+			"server":  client.Name(),
+		}, nil
+	}
+}
+
+func (t *lspDefinitionTool) FormatOutput(result map[string]interface{}) string {
+	return formatLSPOutput("Definition", result)
+}
+
+type lspReferencesTool struct{ lspTool }
+
+func (t *lspReferencesTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "findReferences",
+		Description: "Find every use of a symbol, its declaration included, using the language " +
+			"server. Unlike a text search it skips other symbols with the same name, so use it " +
+			"before renaming or changing a signature.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for findReferences",
+			Properties:  lspPositionParameters(),
+			Required:    []string{"file_path", "line", "_display_message"},
+		},
+		Response: lspResponse("One reference per line: path:line:column: source line"),
+	}
+}
+
+func (t *lspReferencesTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		_, path, client, err := t.prepare(ctx, "findReferences", params)
+		if err != nil {
+			return lspResult(err)
+		}
+		position, err := lspPosition(path, params)
+		if err != nil {
+			return lspResult(err)
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, lspQueryTimeout)
+		defer cancel()
+		locations, err := client.References(queryCtx, path, position)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		results := formatLocations(ctx, locations)
+		if results == "" {
+			results = "no references found"
+		}
+		return map[string]any{
+			"success": true,
+			"results": results,
+			"count":   len(locations),
+			"server":  client.Name(),
+		}, nil
+	}
+}
+
+func (t *lspReferencesTool) FormatOutput(result map[string]interface{}) string {
+	return formatLSPOutput("References", result)
+}
+
+func formatLSPOutput(title string, result map[string]interface{}) string {
+	if success, _ := result["success"].(bool); !success {
+		if msg, _ := result["error"].(string); msg != "" {
+			return fmt.Sprintf("**%s failed**: %s", title, msg)
+		}
+		return fmt.Sprintf("**%s failed**", title)
+	}
+	results, _ := result["results"].(string)
+	return fmt.Sprintf("**%s**\n```\n%s\n```", title, results)
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/lsp"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noLanguageServers struct{}
+
+func (noLanguageServers) ClientFor(context.Context, string, string) (*lsp.Client, error) {
+	return nil, errors.New("no language server for .rb files")
+}
+
+func TestLSPPositionFromSymbolOrColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n\tmsg := \"é\" + greet()\n"), 0644))
+
+	position, err := lspPosition(path, map[string]any{"line": float64(2), "symbol": "greet"})
+	require.NoError(t, err)
+	// Tab, 'msg := "', é (one UTF-16 unit), '" + '
+	assert.Equal(t, lsp.Position{Line: 1, Character: 14}, position)
+
+	position, err = lspPosition(path, map[string]any{"line": float64(2), "column": float64(15)})
+	require.NoError(t, err)
+	assert.Equal(t, lsp.Position{Line: 1, Character: 14}, position)
+
+	_, err = lspPosition(path, map[string]any{"line": float64(2), "symbol": "farewell"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"farewell" is not on line 2`)
+
+	_, err = lspPosition(path, map[string]any{"line": float64(9), "symbol": "greet"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "past the end of the file")
+}
+
+func TestFormatLocationsShowsSourceLines(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "pkg", "a.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("func Greet() {}\n\n\tx := Greet()\n"), 0644))
+	ctx := toolctx.WithWorkingDir(context.Background(), root)
+
+	output := formatLocations(ctx, []lsp.Location{
+		{URI: lsp.FileURI(path), Range: lsp.Range{Start: lsp.Position{Line: 2, Character: 6}}},
+		{URI: lsp.FileURI(path), Range: lsp.Range{Start: lsp.Position{Line: 0, Character: 5}}},
+	})
+	assert.Equal(t, "pkg/a.go:1:6: func Greet() {}\npkg/a.go:3:7: x := Greet()", output)
+}
+
+func TestLSPToolsReportMissingServer(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.rb"), []byte("puts 1\n"), 0644))
+	ctx := toolctx.WithWorkingDir(context.Background(), root)
+
+	for _, tool := range NewLSPTools(nil, noLanguageServers{}) {
+		result, err := tool.Handler()(ctx, map[string]any{"file_path": "app.rb", "line": float64(1), "symbol": "puts"})
+		require.NoError(t, err)
+		assert.False(t, result["success"].(bool), tool.Declaration().Name)
+		assert.Contains(t, result["error"], "no language server for .rb files")
+	}
+}
+
+func TestLSPToolsRejectPathsOutsideWorkspace(t *testing.T) {
+	ctx := toolctx.WithWorkingDir(context.Background(), t.TempDir())
+	tool := NewLSPTools(nil, noLanguageServers{})[0]
+
+	result, err := tool.Handler()(ctx, map[string]any{"file_path": "../../etc/passwd"})
+	require.NoError(t, err)
+	assert.False(t, result["success"].(bool))
+}
@@ -0,0 +1,26 @@
+package tools
+
+// networkTools are the built-in tools that cannot work without network
+// access. Offline mode withholds them.
+var networkTools = map[string]bool{
+	"githubListIssues":  true,
+	"githubIssue":       true,
+	"githubCreateIssue": true,
+	"githubPullRequest": true,
+	"githubChecks":      true,
+	"githubReview":      true,
+
+	"httpRequest": true,
+
+	// kubectl talks to a cluster's API server
+	"k8sGet":      true,
+	"k8sDescribe": true,
+	"k8sLogs":     true,
+	"k8sEvents":   true,
+	"k8sCommand":  true,
+}
+
+// NeedsNetwork reports whether the named tool needs network access.
+func NeedsNetwork(name string) bool {
+	return networkTools[name]
+}
@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MaxNotebookReadSize caps notebooks readFile renders as cells. Notebooks
+// are often large only because of embedded images, which the rendering
+// leaves out, so the cap is the edit cap rather than MaxReadFileSize.
+const MaxNotebookReadSize = MaxEditFileSize
+
+// maxNotebookOutputChars bounds the outputs shown under each code cell
+const maxNotebookOutputChars = 2000
+
+// isNotebookPath reports whether path is a Jupyter notebook.
+func isNotebookPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".ipynb")
+}
+
+// notebook is a Jupyter notebook held as generic JSON, so that editing a
+// cell keeps every field Genie does not know about: metadata, outputs,
+// attachments, and whatever later nbformat versions add.
+type notebook struct {
+	doc    map[string]any
+	cells  []any
+	indent string
+}
+
+// parseNotebook decodes an .ipynb file. Numbers are kept as written.
+func parseNotebook(data []byte) (*notebook, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid notebook JSON: %w", err)
+	}
+	cells, ok := doc["cells"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid notebook: no cells list")
+	}
+	for i, cell := range cells {
+		if _, ok := cell.(map[string]any); !ok {
+			return nil, fmt.Errorf("invalid notebook: cell %d is not an object", i+1)
+		}
+	}
+	return &notebook{doc: doc, cells: cells, indent: detectJSONIndent(data)}, nil
+}
+
+// detectJSONIndent returns the indent of the first nested line, so a
+// rewritten notebook keeps its layout: Jupyter writes one space.
+func detectJSONIndent(data []byte) string {
+	lines := strings.SplitN(string(data), "\n", 3)
+	if len(lines) < 2 {
+		return " "
+	}
+	indent := lines[1][:len(lines[1])-len(strings.TrimLeft(lines[1], " \t"))]
+	if indent == "" {
+		return " "
+	}
+	return indent
+}
+
+// marshal encodes the notebook the way Jupyter does: sorted keys, the
+// file's indent, unescaped HTML and a final newline.
+func (nb *notebook) marshal() ([]byte, error) {
+	nb.doc["cells"] = nb.cells
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", nb.indent)
+	if err := encoder.Encode(nb.doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (nb *notebook) cell(index int) map[string]any {
+	return nb.cells[index].(map[string]any)
+}
+
+func (nb *notebook) cellType(index int) string {
+	cellType, _ := nb.cell(index)["cell_type"].(string)
+	return cellType
+}
+
+func (nb *notebook) source(index int) string {
+	return notebookText(nb.cell(index)["source"])
+}
+
+// setSource replaces a cell's source, in the list-of-lines form Jupyter
+// writes unless the cell held a single string.
+func (nb *notebook) setSource(index int, source string) {
+	cell := nb.cell(index)
+	if _, isString := cell["source"].(string); isString {
+		cell["source"] = source
+		return
+	}
+	cell["source"] = notebookLines(source)
+}
+
+// insertCell adds a cell of cellType before index; index may be the cell
+// count, to append.
+func (nb *notebook) insertCell(index int, cellType, source string) {
+	cell := map[string]any{
+		"cell_type": cellType,
+		"metadata":  map[string]any{},
+		"source":    notebookLines(source),
+	}
+	if cellType == "code" {
+		cell["execution_count"] = nil
+		cell["outputs"] = []any{}
+	}
+	// nbformat 4.5 gives every cell an id; match notebooks that have them
+	for i := range nb.cells {
+		if _, ok := nb.cell(i)["id"]; ok {
+			cell["id"] = newNotebookCellID()
+			break
+		}
+	}
+	nb.cells = append(nb.cells[:index], append([]any{cell}, nb.cells[index:]...)...)
+}
+
+func (nb *notebook) deleteCell(index int) {
+	nb.cells = append(nb.cells[:index], nb.cells[index+1:]...)
+}
+
+// render presents the notebook as numbered cells, with code cells followed
+// by their text outputs. Rich outputs such as images are named, not shown.
+func (nb *notebook) render() string {
+	var b strings.Builder
+	kernel := ""
+	if metadata, ok := nb.doc["metadata"].(map[string]any); ok {
+		if spec, ok := metadata["kernelspec"].(map[string]any); ok {
+			kernel, _ = spec["name"].(string)
+		}
+	}
+	fmt.Fprintf(&b, "Jupyter notebook: %d cells", len(nb.cells))
+	if kernel != "" {
+		fmt.Fprintf(&b, ", kernel %s", kernel)
+	}
+	b.WriteString(". Edit cells with editFile's cell parameters.\n")
+
+	for i := range nb.cells {
+		cell := nb.cell(i)
+		header := fmt.Sprintf("[%s]", nb.cellType(i))
+		if count, ok := cell["execution_count"].(json.Number); ok {
+			header += fmt.Sprintf(" In [%s]", count)
+		}
+		fmt.Fprintf(&b, "\n--- Cell %d %s ---\n%s\n", i+1, header, nb.source(i))
+
+		outputs, _ := cell["outputs"].([]any)
+		var text strings.Builder
+		for _, output := range outputs {
+			if o, ok := output.(map[string]any); ok {
+				text.WriteString(renderNotebookOutput(o))
+			}
+		}
+		if text.Len() > 0 {
+			b.WriteString("--- Output ---\n")
+			b.WriteString(truncateNotebookOutput(strings.TrimRight(text.String(), "\n")))
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func renderNotebookOutput(output map[string]any) string {
+	switch output["output_type"] {
+	case "stream":
+		return ensureNewline(notebookText(output["text"]))
+	case "error":
+		name, _ := output["ename"].(string)
+		value, _ := output["evalue"].(string)
+		return fmt.Sprintf("%s: %s\n", name, value)
+	case "execute_result", "display_data":
+		data, _ := output["data"].(map[string]any)
+		if text, ok := data["text/plain"]; ok {
+			return ensureNewline(notebookText(text))
+		}
+		var kinds []string
+		for kind := range data {
+			kinds = append(kinds, kind)
+		}
+		if len(kinds) > 0 {
+			sort.Strings(kinds)
+			return fmt.Sprintf("[%s output]\n", strings.Join(kinds, ", "))
+		}
+	}
+	return ""
+}
+
+func truncateNotebookOutput(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxNotebookOutputChars {
+		return text
+	}
+	return string(runes[:maxNotebookOutputChars]) + fmt.Sprintf("\n… (%d more characters)", len(runes)-maxNotebookOutputChars)
+}
+
+// notebookText joins a multiline string field, which nbformat stores as a
+// string or a list of lines.
+func notebookText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		var b strings.Builder
+		for _, line := range v {
+			if s, ok := line.(string); ok {
+				b.WriteString(s)
+			}
+		}
+		return b.String()
+	}
+	return ""
+}
+
+// notebookLines splits text into the list form: every line keeps its
+// newline except the last.
+func notebookLines(text string) []any {
+	lines := []any{}
+	for text != "" {
+		line, rest, found := strings.Cut(text, "\n")
+		if found {
+			line += "\n"
+		}
+		lines = append(lines, line)
+		text = rest
+	}
+	return lines
+}
+
+func ensureNewline(text string) string {
+	if text == "" || strings.HasSuffix(text, "\n") {
+		return text
+	}
+	return text + "\n"
+}
+
+func newNotebookCellID() string {
+	id := make([]byte, 4)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// applyNotebookStrReplace is str_replace mode for notebooks: old_string is
+// looked for in the cell sources, as readFile shows them, rather than in
+// the escaped JSON.
+func applyNotebookStrReplace(original []byte, params map[string]any) ([]byte, string, error) {
+	nb, err := parseNotebook(original)
+	if err != nil {
+		return nil, "", err
+	}
+	old, _ := params["old_string"].(string)
+	repl, _ := params["new_string"].(string)
+
+	matchedCell, count := -1, 0
+	for i := range nb.cells {
+		if n := strings.Count(nb.source(i), old); n > 0 {
+			matchedCell, count = i, count+n
+		}
+	}
+	switch count {
+	case 0:
+		return nil, "", fmt.Errorf(
+			"old_string was not found in any cell. The notebook may have changed since you last read it; re-read it and try again")
+	case 1:
+		// happy path
+	default:
+		return nil, "", fmt.Errorf(
+			"old_string matches %d places in the notebook's cells; add more surrounding context until it is unique", count)
+	}
+
+	nb.setSource(matchedCell, strings.Replace(nb.source(matchedCell), old, repl, 1))
+	updated, err := nb.marshal()
+	if err != nil {
+		return nil, "", err
+	}
+	return updated, fmt.Sprintf("replaced %d-byte span with %d-byte content in cell %d", len(old), len(repl), matchedCell+1), nil
+}
+
+// applyNotebookCellEdit is cell mode: replace, insert or delete one cell.
+// Replacing a cell's source keeps its outputs and metadata.
+func applyNotebookCellEdit(original []byte, params map[string]any) ([]byte, string, error) {
+	nb, err := parseNotebook(original)
+	if err != nil {
+		return nil, "", err
+	}
+	number, _ := numberValue(params, "cell")
+	action, _ := params["cell_action"].(string)
+	if action == "" {
+		action = "replace"
+	}
+	source, hasSource := params["cell_source"].(string)
+	cellType, _ := params["cell_type"].(string)
+	if cellType != "" && cellType != "code" && cellType != "markdown" && cellType != "raw" {
+		return nil, "", fmt.Errorf("cell_type must be code, markdown or raw (got %q)", cellType)
+	}
+
+	last := len(nb.cells)
+	if action == "insert" {
+		last++
+	}
+	if number < 1 || int(number) > last {
+		return nil, "", fmt.Errorf("cell %d is outside the notebook's %d cells", number, len(nb.cells))
+	}
+	index := int(number) - 1
+
+	var summary string
+	switch action {
+	case "replace":
+		if !hasSource {
+			return nil, "", fmt.Errorf("replacing a cell requires cell_source")
+		}
+		if cellType != "" && cellType != nb.cellType(index) {
+			return nil, "", fmt.Errorf("cell %d is a %s cell; delete it and insert a %s cell to change its type", number, nb.cellType(index), cellType)
+		}
+		nb.setSource(index, source)
+		summary = fmt.Sprintf("replaced the source of cell %d", number)
+	case "insert":
+		if cellType == "" {
+			cellType = "code"
+		}
+		nb.insertCell(index, cellType, source)
+		summary = fmt.Sprintf("inserted %s cell %d", cellType, number)
+	case "delete":
+		nb.deleteCell(index)
+		summary = fmt.Sprintf("deleted cell %d", number)
+	default:
+		return nil, "", fmt.Errorf("cell_action must be replace, insert or delete (got %q)", action)
+	}
+
+	updated, err := nb.marshal()
+	if err != nil {
+		return nil, "", err
+	}
+	return updated, summary, nil
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleNotebook is laid out the way Jupyter saves notebooks, so an edit
+// that preserves structure changes only the lines it touches
+const sampleNotebook = `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "id": "a1",
+   "metadata": {},
+   "source": [
+    "# Sales <2024>\n",
+    "Quarterly totals"
+   ]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": 3,
+   "id": "b2",
+   "metadata": {
+    "tags": [
+     "parameters"
+    ]
+   },
+   "outputs": [
+    {
+     "name": "stdout",
+     "output_type": "stream",
+     "text": [
+      "42\n"
+     ]
+    },
+    {
+     "data": {
+      "image/png": "iVBORw0KGgo="
+     },
+     "metadata": {},
+     "output_type": "display_data"
+    }
+   ],
+   "source": [
+    "total = 40 + 2\n",
+    "print(total)"
+   ]
+  }
+ ],
+ "metadata": {
+  "kernelspec": {
+   "display_name": "Python 3",
+   "language": "python",
+   "name": "python3"
+  }
+ },
+ "nbformat": 4,
+ "nbformat_minor": 5
+}
+`
+
+func writeNotebook(t *testing.T) (string, context.Context) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "sales.ipynb"), []byte(sampleNotebook), 0o644))
+	return workspace, toolctx.WithWorkingDir(context.Background(), workspace)
+}
+
+func editNotebook(t *testing.T, ctx context.Context, params map[string]any) map[string]any {
+	params["path"] = "sales.ipynb"
+	params["_display_message"] = "editing the notebook"
+	r, err := NewEditTool(&events.NoOpPublisher{}).Handler()(ctx, params)
+	require.NoError(t, err)
+	return r
+}
+
+func TestReadFileTool_RendersNotebookCells(t *testing.T) {
+	_, ctx := writeNotebook(t)
+
+	r, err := NewReadFileTool(&events.NoOpPublisher{}).Handler()(ctx, map[string]any{
+		"file_path":        "sales.ipynb",
+		"_display_message": "reading the notebook",
+	})
+	require.NoError(t, err)
+	require.True(t, r["success"].(bool))
+	assert.Equal(t, `Jupyter notebook: 2 cells, kernel python3. Edit cells with editFile's cell parameters.
+
+--- Cell 1 [markdown] ---
+# Sales <2024>
+Quarterly totals
+
+--- Cell 2 [code] In [3] ---
+total = 40 + 2
+print(total)
+--- Output ---
+42
+[image/png output]`, r["results"])
+
+	// A line range reads the JSON itself
+	r, err = NewReadFileTool(&events.NoOpPublisher{}).Handler()(ctx, map[string]any{
+		"file_path":        "sales.ipynb",
+		"start_line":       float64(1),
+		"end_line":         float64(2),
+		"_display_message": "reading the notebook",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n \"cells\": [", r["results"])
+}
+
+func TestEditTool_NotebookCellReplaceKeepsStructure(t *testing.T) {
+	workspace, ctx := writeNotebook(t)
+
+	r := editNotebook(t, ctx, map[string]any{"cell": float64(2), "cell_source": "total = 50\nprint(total)"})
+	require.True(t, r["success"].(bool), r["error"])
+
+	got, err := os.ReadFile(filepath.Join(workspace, "sales.ipynb"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(sampleNotebook, `"total = 40 + 2\n"`, `"total = 50\n"`, 1), string(got),
+		"only the edited line changes; outputs, metadata and layout stay")
+
+	r = editNotebook(t, ctx, map[string]any{"old_string": "Quarterly", "new_string": "Monthly"})
+	require.True(t, r["success"].(bool), r["error"])
+	assert.Contains(t, r["results"], "cell 1")
+	got, _ = os.ReadFile(filepath.Join(workspace, "sales.ipynb"))
+	assert.Contains(t, string(got), `"Monthly totals"`)
+}
+
+func TestEditTool_NotebookInsertAndDeleteCells(t *testing.T) {
+	workspace, ctx := writeNotebook(t)
+
+	r := editNotebook(t, ctx, map[string]any{"cell": float64(3), "cell_action": "insert", "cell_type": "markdown", "cell_source": "## Notes\nDone"})
+	require.True(t, r["success"].(bool), r["error"])
+	r = editNotebook(t, ctx, map[string]any{"cell": float64(1), "cell_action": "delete"})
+	require.True(t, r["success"].(bool), r["error"])
+
+	data, err := os.ReadFile(filepath.Join(workspace, "sales.ipynb"))
+	require.NoError(t, err)
+	nb, err := parseNotebook(data)
+	require.NoError(t, err)
+	require.Len(t, nb.cells, 2)
+	assert.Equal(t, "code", nb.cellType(0))
+	assert.Equal(t, "## Notes\nDone", nb.source(1))
+	assert.Len(t, nb.cell(1)["id"], 8, "new cells get an id like the others")
+
+	r = editNotebook(t, ctx, map[string]any{"start_line": float64(1), "end_line": float64(1), "replacement": "{"})
+	assert.False(t, r["success"].(bool))
+	assert.Contains(t, r["error"], "notebook cells")
+	r = editNotebook(t, ctx, map[string]any{"cell": float64(9), "cell_source": "x"})
+	assert.False(t, r["success"].(bool))
+	assert.Contains(t, r["error"], "outside the notebook's 2 cells")
+}
@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kcaldas/genie/pkg/config"
+)
+
+// DefaultOutputLimit is the size, in bytes, above which a string field of a
+// tool result is truncated before it reaches the model.
+const DefaultOutputLimit = 32 * 1024
+
+// OutputLimitConfigKey sets the truncation threshold in KB.
+const OutputLimitConfigKey = "GENIE_TOOL_OUTPUT_LIMIT_KB"
+
+// OutputRetention is how long stored output is kept. Older files are pruned
+// when a tool registry starts.
+const OutputRetention = 7 * 24 * time.Hour
+
+// outputHandlePattern guards handle lookups so model-supplied handles can
+// never escape the store directory.
+var outputHandlePattern = regexp.MustCompile(`^out_[0-9a-f]{16}$`)
+
+// OutputStore keeps oversized tool output on disk and replaces it in the
+// tool result with a truncated preview plus a handle. The model pages
+// through the rest with the readToolOutput tool; the TUI uses the same
+// handle to expand the full output of a tool call.
+type OutputStore struct {
+	dir   string
+	limit int
+}
+
+// NewOutputStore creates a store rooted at dir. A non-positive limit uses
+// DefaultOutputLimit.
+func NewOutputStore(dir string, limit int) *OutputStore {
+	if limit <= 0 {
+		limit = DefaultOutputLimit
+	}
+	return &OutputStore{dir: dir, limit: limit}
+}
+
+// NewConfiguredOutputStore creates a store in DefaultOutputDir with the
+// limit set in GENIE_TOOL_OUTPUT_LIMIT_KB. The prompt loader truncates with
+// it and readToolOutput pages with it, so both must agree: a page larger
+// than the loader's limit would be truncated again under a new handle.
+func NewConfiguredOutputStore(cfg config.Manager) *OutputStore {
+	limit := cfg.GetIntWithDefault(OutputLimitConfigKey, DefaultOutputLimit/1024) * 1024
+	return NewOutputStore(DefaultOutputDir(), limit)
+}
+
+// DefaultOutputDir returns the directory shared by every store of the
+// current user, ~/.genie/tool-output, so handles issued by one component can
+// be read by another. Without a home directory it falls back to a per-user
+// directory in the temp space.
+func DefaultOutputDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "genie-tool-output-"+strconv.Itoa(os.Getuid()))
+	}
+	return filepath.Join(home, ".genie", "tool-output")
+}
+
+// Limit returns the truncation threshold, which is also the read size, in bytes.
+func (s *OutputStore) Limit() int {
+	return s.limit
+}
+
+// Truncate returns a copy of result in which every string field larger than
+// the limit is cut down and annotated with a handle to the full text. The
+// handles are returned in field order; result is returned unchanged when
+// nothing needed truncating.
+func (s *OutputStore) Truncate(result map[string]any) (map[string]any, []string, error) {
+	var oversized []string
+	for key, value := range result {
+		if str, ok := value.(string); ok && len(str) > s.limit {
+			oversized = append(oversized, key)
+		}
+	}
+	if len(oversized) == 0 {
+		return result, nil, nil
+	}
+	sort.Strings(oversized)
+
+	truncated := make(map[string]any, len(result)+1)
+	for key, value := range result {
+		truncated[key] = value
+	}
+
+	handles := make([]string, 0, len(oversized))
+	for _, key := range oversized {
+		full := result[key].(string)
+		handle, err := s.save(full)
+		if err != nil {
+			return result, nil, err
+		}
+		handles = append(handles, handle)
+
+		head := truncateUTF8(full, s.limit)
+		truncated[key] = fmt.Sprintf("%s\n\n[output truncated: showing %d of %d bytes. Call readToolOutput with handle %q and offset %d to read the rest.]",
+			head, len(head), len(full), handle, len(head))
+	}
+	truncated["truncated"] = true
+
+	return truncated, handles, nil
+}
+
+// Load returns the full text stored under handle.
+func (s *OutputStore) Load(handle string) (string, error) {
+	if !outputHandlePattern.MatchString(handle) {
+		return "", fmt.Errorf("invalid output handle %q", handle)
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, handle+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no stored output for handle %q", handle)
+		}
+		return "", fmt.Errorf("failed to read stored output: %w", err)
+	}
+	return string(data), nil
+}
+
+// Read returns up to one limit's worth of the output stored under handle,
+// starting at byte offset. It also returns the offset to continue from (equal
+// to total once everything has been read) and the total size in bytes.
+func (s *OutputStore) Read(handle string, offset int) (string, int, int, error) {
+	full, err := s.Load(handle)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if offset < 0 || offset > len(full) {
+		return "", 0, len(full), fmt.Errorf("offset %d out of range (0-%d)", offset, len(full))
+	}
+
+	// Snap to a rune boundary so a stale offset cannot split a character.
+	for offset < len(full) && !utf8.RuneStart(full[offset]) {
+		offset++
+	}
+
+	chunk := truncateUTF8(full[offset:], s.limit)
+	if chunk == "" && offset < len(full) {
+		// A single rune wider than the limit; take it whole.
+		_, size := utf8.DecodeRuneInString(full[offset:])
+		chunk = full[offset : offset+size]
+	}
+	return chunk, offset + len(chunk), len(full), nil
+}
+
+// Prune removes stored output last written more than maxAge ago. A missing
+// store directory is not an error.
+func (s *OutputStore) Prune(maxAge time.Duration) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read tool output directory: %w", err)
+	}
+	cutoff := time.Now().Add(-maxAge)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !outputHandlePattern.MatchString(strings.TrimSuffix(name, ".txt")) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stored tool output: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *OutputStore) save(content string) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create tool output directory: %w", err)
+	}
+
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("failed to generate output handle: %w", err)
+	}
+	handle := "out_" + hex.EncodeToString(id[:])
+
+	if err := os.WriteFile(filepath.Join(s.dir, handle+".txt"), []byte(content), 0o600); err != nil {
+		return "", fmt.Errorf("failed to store tool output: %w", err)
+	}
+	return handle, nil
+}
+
+// truncateUTF8 cuts s to at most limit bytes without splitting a rune.
+func truncateUTF8(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputStore_TruncateLeavesSmallResultsAlone(t *testing.T) {
+	store := NewOutputStore(t.TempDir(), 16)
+	result := map[string]any{"success": true, "output": "short"}
+
+	truncated, handles, err := store.Truncate(result)
+
+	require.NoError(t, err)
+	assert.Empty(t, handles)
+	assert.Equal(t, result, truncated)
+}
+
+func TestOutputStore_TruncateStoresFullOutput(t *testing.T) {
+	store := NewOutputStore(t.TempDir(), 16)
+	full := strings.Repeat("abcdefgh", 10)
+	result := map[string]any{"success": true, "output": full}
+
+	truncated, handles, err := store.Truncate(result)
+
+	require.NoError(t, err)
+	require.Len(t, handles, 1)
+	assert.Equal(t, full, result["output"], "original result must not be mutated")
+	assert.Equal(t, true, truncated["truncated"])
+
+	output := truncated["output"].(string)
+	assert.True(t, strings.HasPrefix(output, full[:16]))
+	assert.Contains(t, output, handles[0])
+	assert.Contains(t, output, "offset 16")
+
+	stored, err := store.Load(handles[0])
+	require.NoError(t, err)
+	assert.Equal(t, full, stored)
+}
+
+func TestOutputStore_ReadPagesThroughOutput(t *testing.T) {
+	store := NewOutputStore(t.TempDir(), 16)
+	full := strings.Repeat("0123456789", 4)
+	_, handles, err := store.Truncate(map[string]any{"output": full})
+	require.NoError(t, err)
+
+	var collected strings.Builder
+	offset := 0
+	for {
+		chunk, next, total, err := store.Read(handles[0], offset)
+		require.NoError(t, err)
+		assert.Equal(t, len(full), total)
+		collected.WriteString(chunk)
+		if next == total {
+			break
+		}
+		offset = next
+	}
+	assert.Equal(t, full, collected.String())
+
+	_, _, _, err = store.Read(handles[0], len(full)+1)
+	assert.Error(t, err)
+}
+
+func TestOutputStore_TruncateDoesNotSplitRunes(t *testing.T) {
+	store := NewOutputStore(t.TempDir(), 5)
+	full := strings.Repeat("é", 10) // 2 bytes each
+
+	truncated, _, err := store.Truncate(map[string]any{"content": full})
+
+	require.NoError(t, err)
+	head := strings.SplitN(truncated["content"].(string), "\n", 2)[0]
+	assert.Equal(t, "éé", head)
+}
+
+func TestOutputStore_RejectsInvalidHandles(t *testing.T) {
+	store := NewOutputStore(t.TempDir(), 16)
+
+	_, err := store.Load("../../etc/passwd")
+	assert.Error(t, err)
+
+	_, err = store.Load("out_0000000000000000")
+	assert.Error(t, err)
+}
+
+func TestOutputStore_PruneRemovesOldOutput(t *testing.T) {
+	dir := t.TempDir()
+	store := NewOutputStore(dir, 4)
+	_, oldHandles, err := store.Truncate(map[string]any{"output": "old output"})
+	require.NoError(t, err)
+	_, newHandles, err := store.Truncate(map[string]any{"output": "new output"})
+	require.NoError(t, err)
+	unrelated := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(unrelated, []byte("keep"), 0o600))
+
+	stale := time.Now().Add(-2 * OutputRetention)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, oldHandles[0]+".txt"), stale, stale))
+	require.NoError(t, os.Chtimes(unrelated, stale, stale))
+
+	require.NoError(t, store.Prune(OutputRetention))
+
+	_, err = store.Load(oldHandles[0])
+	assert.Error(t, err)
+	_, err = store.Load(newHandles[0])
+	assert.NoError(t, err)
+	assert.FileExists(t, unrelated)
+}
+
+func TestOutputStore_PruneIgnoresMissingDirectory(t *testing.T) {
+	store := NewOutputStore(filepath.Join(t.TempDir(), "missing"), 0)
+	assert.NoError(t, store.Prune(OutputRetention))
+}
+
+func TestReadToolOutputTool_Handler(t *testing.T) {
+	store := NewOutputStore(t.TempDir(), 8)
+	_, handles, err := store.Truncate(map[string]any{"output": "0123456789abcdef"})
+	require.NoError(t, err)
+
+	tool := NewReadToolOutputTool(store)
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"handle": handles[0],
+		"offset": float64(8),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, "89abcdef", result["content"])
+	assert.Equal(t, 16, result["next_offset"])
+
+	result, err = tool.Handler()(context.Background(), map[string]any{"handle": "bogus"})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+}
+
+func TestNewConfiguredOutputStore(t *testing.T) {
+	t.Setenv(OutputLimitConfigKey, "4")
+	assert.Equal(t, 4*1024, NewConfiguredOutputStore(config.NewConfigManager()).Limit())
+}
@@ -57,6 +57,14 @@ func ReadOnlyPathsFromContext(ctx context.Context) []string {
 	return patterns
 }
 
+// ScopeFromContext returns the directories, relative to the workspace
+// root, that the session is scoped to. Empty when the whole workspace is
+// in scope.
+func ScopeFromContext(ctx context.Context) []string {
+	dirs, _ := toolctx.Scope(ctx)
+	return dirs
+}
+
 // CheckPathPolicy returns an error suitable for forwarding to the model
 // when the resolved path is not allowed for the given intent. Callers
 // should invoke it after ResolvePathWithWorkingDirectory and before
@@ -65,6 +73,9 @@ func ReadOnlyPathsFromContext(ctx context.Context) []string {
 //   - IntentRead is rejected when the path matches denied_paths.
 //   - IntentMutate is rejected when the path matches denied_paths OR
 //     read_only_paths.
+//   - Both are rejected for a workspace path outside the session's scope,
+//     except that directories leading to the scope may be read so
+//     listings and searches can walk into it.
 //
 // Pattern matching is location-aware. For a path inside the workspace,
 // patterns match against the workspace-relative form (e.g. "secrets/**"
@@ -86,6 +97,9 @@ func CheckPathPolicy(ctx context.Context, resolvedPath string, intent PathIntent
 			return fmt.Errorf("path %q is denied (matched %q)", resolvedPath, pattern)
 		}
 	}
+	if err := checkScope(ctx, resolvedPath, intent); err != nil {
+		return err
+	}
 	if intent == IntentMutate {
 		readOnlyPatterns := ReadOnlyPathsFromContext(ctx)
 		for _, c := range candidates {
@@ -97,6 +111,114 @@ func CheckPathPolicy(ctx context.Context, resolvedPath string, intent PathIntent
 	return nil
 }
 
+// checkScope rejects workspace paths outside the session's scope. Paths
+// outside the workspace, in allowed_dirs, are left to the other checks.
+func checkScope(ctx context.Context, resolvedPath string, intent PathIntent) error {
+	scope := ScopeFromContext(ctx)
+	if len(scope) == 0 {
+		return nil
+	}
+	absWorkspace, err := filepath.Abs(WorkingDirectoryFromContext(ctx))
+	if err != nil {
+		return nil
+	}
+	absTarget, err := filepath.Abs(resolvedPath)
+	if err != nil || !isWithinDir(absTarget, absWorkspace) {
+		return nil
+	}
+	for _, dir := range scopeDirs(ctx) {
+		if isWithinDir(absTarget, dir) {
+			return nil
+		}
+	}
+	if intent == IntentRead && scopeAncestor(ctx, absTarget) {
+		return nil
+	}
+	return fmt.Errorf("path %q is outside the session's scope (%s); work inside the scope or ask the user to widen it with :scope", resolvedPath, strings.Join(scope, ", "))
+}
+
+// checkRepoPolicy checks a repository root before a git tool changes
+// files in it. A root above the session's scope, as in a monorepo, passes
+// the scope check; the tool checks each path it stages or restores instead.
+func checkRepoPolicy(ctx context.Context, repoPath string) error {
+	if scopeAncestor(ctx, repoPath) {
+		ctx = toolctx.WithScope(ctx, nil)
+	}
+	return CheckPathPolicy(ctx, repoPath, IntentMutate)
+}
+
+// inScope reports whether path lies inside the session's scope; without a
+// scope everything does.
+func inScope(ctx context.Context, path string) bool {
+	dirs := scopeDirs(ctx)
+	if len(dirs) == 0 {
+		return true
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, dir := range dirs {
+		if isWithinDir(absPath, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeDirs returns the session's scope as absolute paths
+func scopeDirs(ctx context.Context) []string {
+	scope := ScopeFromContext(ctx)
+	if len(scope) == 0 {
+		return nil
+	}
+	absWorkspace, err := filepath.Abs(WorkingDirectoryFromContext(ctx))
+	if err != nil {
+		return nil
+	}
+	dirs := make([]string, len(scope))
+	for i, dir := range scope {
+		dirs[i] = filepath.Join(absWorkspace, filepath.FromSlash(dir))
+	}
+	return dirs
+}
+
+// scopeAncestor reports whether path is a directory above a scope
+// directory, such as the workspace root, which tools only pass through
+// on their way into the scope
+func scopeAncestor(ctx context.Context, path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, dir := range scopeDirs(ctx) {
+		if absPath != dir && isWithinDir(dir, absPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeSearchPaths returns what a recursive search of resolvedPath should
+// cover: the scope directories below it when it leads to the scope, and
+// resolvedPath itself otherwise
+func scopeSearchPaths(ctx context.Context, resolvedPath string) []string {
+	if !scopeAncestor(ctx, resolvedPath) {
+		return []string{resolvedPath}
+	}
+	absPath, err := filepath.Abs(resolvedPath)
+	if err != nil {
+		return []string{resolvedPath}
+	}
+	var paths []string
+	for _, dir := range scopeDirs(ctx) {
+		if isWithinDir(dir, absPath) {
+			paths = append(paths, dir)
+		}
+	}
+	return paths
+}
+
 // policyMatchCandidates returns every "logical" representation of
 // resolvedPath that policy patterns may legitimately reference. A path
 // gets at minimum its workspace-relative form; if it also falls inside an
@@ -17,6 +17,9 @@ func setProcAttr(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
 
+// setShellCmdLine is a no-op on Unix, where arguments reach the shell as is.
+func setShellCmdLine(cmd *exec.Cmd, shell Shell, command string) {}
+
 // ConfigureGroupKill isolates cmd in its own process group and makes
 // context cancellation kill that whole group, so grandchildren cannot
 // outlive the command and keep its output pipes open.
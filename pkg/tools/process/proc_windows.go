@@ -2,22 +2,56 @@
 
 package process
 
-import "os/exec"
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
 
 // setProcAttr is a no-op on Windows (no process group isolation).
 func setProcAttr(cmd *exec.Cmd) {}
 
-// ConfigureGroupKill is a no-op on Windows: exec's default cancel
-// behavior (killing the direct child) is the best available option.
-func ConfigureGroupKill(cmd *exec.Cmd) {}
+// setShellCmdLine passes command to cmd.exe verbatim. cmd.exe doesn't
+// follow the quoting rules exec uses for arguments, so "/s /c" plus the
+// command wrapped in one pair of quotes is the only reliable form.
+func setShellCmdLine(cmd *exec.Cmd, shell Shell, command string) {
+	if shell.Kind != ShellCmd {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: fmt.Sprintf(`%s /d /s /c "%s"`, syscall.EscapeArg(shell.Path), command),
+	}
+}
+
+// ConfigureGroupKill makes context cancellation kill cmd's whole process
+// tree, so programs started by the shell don't outlive it.
+func ConfigureGroupKill(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		if cmd.Process == nil {
+			return nil
+		}
+		return killTree(cmd)
+	}
+}
+
+// killTree ends the process and its descendants with taskkill, falling
+// back to killing the direct child alone.
+func killTree(cmd *exec.Cmd) error {
+	pid := strconv.Itoa(cmd.Process.Pid)
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", pid).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
 
-// killProcess terminates the process directly on Windows.
+// killProcess terminates the process tree on Windows.
 func killProcess(s *Session) error {
 	cmd := s.cmd
 	if cmd == nil || cmd.Process == nil {
 		return nil
 	}
-	return cmd.Process.Kill()
+	return killTree(cmd)
 }
 
 // startWithPTY is not supported on Windows; always returns false.
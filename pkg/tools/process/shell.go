@@ -23,6 +23,11 @@ func UserShell() string {
 	return shell
 }
 
+// DefaultShell returns the shell tool commands run in: UserShell.
+func DefaultShell() Shell {
+	return Shell{Path: UserShell(), Kind: ShellPOSIX}
+}
+
 // isTrustedShell checks whether the given path appears in /etc/shells.
 func isTrustedShell(shell string) bool {
 	f, err := os.Open("/etc/shells")
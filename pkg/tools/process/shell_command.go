@@ -0,0 +1,73 @@
+package process
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ShellConfigKey names the shell Windows commands run in: "bash" (Git
+// Bash), "pwsh", "powershell", "cmd", or a path to one of them. Unix
+// always uses $SHELL.
+const ShellConfigKey = "GENIE_SHELL"
+
+// ShellKind is the command syntax a shell understands.
+type ShellKind string
+
+const (
+	// ShellPOSIX covers sh, bash, zsh and other Unix shells
+	ShellPOSIX ShellKind = "posix"
+	// ShellPowerShell covers Windows PowerShell and PowerShell 7 (pwsh)
+	ShellPowerShell ShellKind = "powershell"
+	// ShellCmd is the Windows command prompt
+	ShellCmd ShellKind = "cmd"
+)
+
+// Shell is a program that tool commands run in.
+type Shell struct {
+	Path string
+	Kind ShellKind
+}
+
+// Args returns the arguments that make the shell run command and exit.
+// Login asks POSIX shells to read the user's profile first.
+func (s Shell) Args(command string, login bool) []string {
+	switch s.Kind {
+	case ShellPowerShell:
+		return []string{"-NoLogo", "-NoProfile", "-NonInteractive", "-Command", command}
+	case ShellCmd:
+		return []string{"/d", "/s", "/c", command}
+	default:
+		if login {
+			return []string{"-l", "-c", command}
+		}
+		return []string{"-c", command}
+	}
+}
+
+// Command builds a command running command in the shell.
+func (s Shell) Command(ctx context.Context, command string, login bool) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, s.Path, s.Args(command, login)...)
+	setShellCmdLine(cmd, s, command)
+	return cmd
+}
+
+// Name is the shell's program name without directory or extension, for
+// telling the model which syntax to use.
+func (s Shell) Name() string {
+	name := filepath.Base(s.Path)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// shellKindOf classifies a shell by its program name.
+func shellKindOf(path string) ShellKind {
+	switch strings.ToLower((Shell{Path: path}).Name()) {
+	case "pwsh", "powershell":
+		return ShellPowerShell
+	case "cmd":
+		return ShellCmd
+	default:
+		return ShellPOSIX
+	}
+}
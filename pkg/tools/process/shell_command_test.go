@@ -0,0 +1,34 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShellArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		shell Shell
+		login bool
+		want  []string
+	}{
+		{name: "posix", shell: Shell{Path: "/bin/bash", Kind: ShellPOSIX}, want: []string{"-c", "echo hi"}},
+		{name: "posix login", shell: Shell{Path: "/bin/zsh", Kind: ShellPOSIX}, login: true, want: []string{"-l", "-c", "echo hi"}},
+		{name: "powershell", shell: Shell{Path: "pwsh.exe", Kind: ShellPowerShell}, login: true, want: []string{"-NoLogo", "-NoProfile", "-NonInteractive", "-Command", "echo hi"}},
+		{name: "cmd", shell: Shell{Path: "cmd.exe", Kind: ShellCmd}, want: []string{"/d", "/s", "/c", "echo hi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.shell.Args("echo hi", tt.login))
+		})
+	}
+}
+
+func TestShellKindOf(t *testing.T) {
+	assert.Equal(t, ShellPowerShell, shellKindOf("pwsh.exe"))
+	assert.Equal(t, ShellPowerShell, shellKindOf("PowerShell.exe"))
+	assert.Equal(t, ShellCmd, shellKindOf("cmd.exe"))
+	assert.Equal(t, ShellPOSIX, shellKindOf("bash.exe"))
+	assert.Equal(t, ShellPOSIX, shellKindOf("/bin/zsh"))
+}
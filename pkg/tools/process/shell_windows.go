@@ -2,7 +2,44 @@
 
 package process
 
-// UserShell returns "bash" on Windows (expects Git Bash in PATH).
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// UserShell returns the path of the shell tool commands run in.
 func UserShell() string {
-	return "bash"
+	return DefaultShell().Path
+}
+
+// DefaultShell returns the shell named by GENIE_SHELL, or else the first
+// one found of Git Bash, PowerShell 7, Windows PowerShell and cmd. Bash
+// comes first because the model writes POSIX commands most reliably.
+func DefaultShell() Shell {
+	if configured := strings.TrimSpace(os.Getenv(ShellConfigKey)); configured != "" {
+		if path, err := exec.LookPath(configured); err == nil {
+			return Shell{Path: path, Kind: shellKindOf(path)}
+		}
+	}
+	for _, candidate := range []string{"bash", "pwsh", "powershell"} {
+		path, err := exec.LookPath(candidate)
+		if err != nil || (candidate == "bash" && isWSLLauncher(path)) {
+			continue
+		}
+		return Shell{Path: path, Kind: shellKindOf(path)}
+	}
+	comspec := os.Getenv("ComSpec")
+	if comspec == "" {
+		comspec = "cmd.exe"
+	}
+	return Shell{Path: comspec, Kind: ShellCmd}
+}
+
+// isWSLLauncher reports whether path is System32's bash.exe, which runs
+// commands inside WSL where Windows paths and tools don't apply.
+func isWSLLauncher(path string) bool {
+	system32 := filepath.Join(os.Getenv("SystemRoot"), "System32")
+	return strings.EqualFold(filepath.Dir(path), system32)
 }
@@ -0,0 +1,39 @@
+//go:build windows
+
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultShell_HonorsConfiguredShell(t *testing.T) {
+	t.Setenv(ShellConfigKey, "cmd")
+	shell := DefaultShell()
+	assert.Equal(t, ShellCmd, shell.Kind)
+}
+
+func TestDefaultShell_RunsCommands(t *testing.T) {
+	out, err := DefaultShell().Command(context.Background(), "echo hello", false).CombinedOutput()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "hello")
+}
+
+func TestCmdShell_PassesQuotesThrough(t *testing.T) {
+	t.Setenv(ShellConfigKey, "cmd")
+	out, err := DefaultShell().Command(context.Background(), `echo "a b"`, false).CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, `"a b"`, strings.TrimSpace(string(out)))
+}
+
+func TestIsWSLLauncher(t *testing.T) {
+	system32 := filepath.Join(os.Getenv("SystemRoot"), "System32")
+	assert.True(t, isWSLLauncher(filepath.Join(system32, "bash.exe")))
+	assert.False(t, isWSLLauncher(`C:\Program Files\Git\bin\bash.exe`))
+}
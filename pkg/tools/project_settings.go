@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectSettingsFile holds project settings for the tools, in the
+// workspace's .genie directory.
+const ProjectSettingsFile = "settings.json"
+
+// ProjectSettings is the structure of .genie/settings.json:
+//
+//	{
+//	  "checks": {
+//	    "build": "make build",
+//	    "test": "go test ./...",
+//	    "lint": ""
+//	  },
+//	  "kubernetes": {
+//	    "allowedVerbs": ["rollout restart", "scale"]
+//	  }
+//	}
+//
+// The settings name commands to run, so the tools ignore them in
+// workspaces the user has not trusted.
+type ProjectSettings struct {
+	// Checks are the commands runChecks runs by name. An empty command
+	// turns off a check that would otherwise be detected.
+	Checks map[string]string `json:"checks,omitempty"`
+
+	// Kubernetes is the policy of the @k8s tools
+	Kubernetes KubernetesSettings `json:"kubernetes,omitempty"`
+}
+
+// LoadProjectSettings reads .genie/settings.json in root; a missing file
+// is empty settings.
+func LoadProjectSettings(root string) (ProjectSettings, error) {
+	var settings ProjectSettings
+	path := filepath.Join(root, ".genie", ProjectSettingsFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return settings, nil
+}
@@ -31,7 +31,7 @@ func NewReadFileTool(publisher events.Publisher) Tool {
 func (r *ReadFileTool) Declaration() *ai.FunctionDeclaration {
 	return &ai.FunctionDeclaration{
 		Name:        "readFile",
-		Description: "Read and display the contents of a file. Use this when you need to see what's inside a file or examine file contents.",
+		Description: "Read and display the contents of a file. Use this when you need to see what's inside a file or examine file contents. Jupyter notebooks (.ipynb) are shown as numbered cells with their text outputs; pass start_line and end_line to see the raw JSON instead.",
 		Parameters: &ai.Schema{
 			Type:        ai.TypeObject,
 			Description: "Parameters for reading a file",
@@ -182,6 +182,15 @@ func (r *ReadFileTool) Handler() ai.HandlerFunc {
 				"error":   "path is a directory, not a file",
 			}, nil
 		}
+		if !hasStart && isNotebookPath(filePath) && info.Size() <= MaxNotebookReadSize {
+			if rendered, err := readNotebook(filePath); err == nil {
+				return map[string]any{
+					"success": true,
+					"results": rendered,
+				}, nil
+			}
+			// Not a valid notebook; fall through to its text
+		}
 		if !hasStart && info.Size() > MaxReadFileSize {
 			return map[string]any{
 				"success": false,
@@ -211,6 +220,20 @@ func (r *ReadFileTool) Handler() ai.HandlerFunc {
 	}
 }
 
+// readNotebook renders a Jupyter notebook as numbered cells instead of
+// its JSON.
+func readNotebook(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	nb, err := parseNotebook(data)
+	if err != nil {
+		return "", err
+	}
+	return nb.render(), nil
+}
+
 // readFileContent reads the file and optionally adds line numbers.
 // When rangeRequested is true, only lines [startLine, endLine] (1-indexed,
 // inclusive) are returned; line-numbered output preserves the original
@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"regexp"
+	"strings"
+)
+
+// readOnlyTools are the built-in tools that never change the workspace.
+// Anything not listed here (including MCP tools, whose effects are unknown)
+// is treated as mutating.
+var readOnlyTools = map[string]bool{
+	"readFile":       true,
+	"listFiles":      true,
+	"findFiles":      true,
+	"searchInFiles":  true,
+	"gitDiff":        true,
+	"gitLog":         true,
+	"gitShow":        true,
+	"gitStatus":      true,
+	"readToolOutput": true,
+	"thinking":       true,
+	"TodoRead":       true,
+	"TodoWrite":      true,
+	"Skill":          true,
+	"viewDocument":   true,
+	"viewImage":      true,
+
+	// GitHub tools that only fetch; creating issues and reviews is left out
+	"githubListIssues":  true,
+	"githubIssue":       true,
+	"githubPullRequest": true,
+	"githubChecks":      true,
+
+	// Container tools that only inspect
+	"dockerContainers": true,
+	"dockerImages":     true,
+	"dockerLogs":       true,
+
+	// Kubernetes tools that only inspect; k8sCommand can mutate
+	"k8sGet":      true,
+	"k8sDescribe": true,
+	"k8sLogs":     true,
+	"k8sEvents":   true,
+
+	// Language server queries
+	"getDiagnostics": true,
+	"findReferences": true,
+	"gotoDefinition": true,
+}
+
+// IsReadOnlyTool reports whether the named tool is safe to offer in
+// read-only mode. The bash tool is not: it is only allowed behind
+// IsReadOnlyCommand.
+func IsReadOnlyTool(name string) bool {
+	return readOnlyTools[name]
+}
+
+// readOnlyCommands are shell programs that only inspect the filesystem or
+// repository. Programs whose read-only-ness depends on arguments are
+// checked separately in readOnlyInvocation.
+var readOnlyCommands = map[string]bool{
+	"ls": true, "cat": true, "head": true, "tail": true,
+	"grep": true, "egrep": true, "fgrep": true, "rg": true, "ag": true,
+	"wc": true, "file": true, "stat": true, "du": true, "df": true,
+	"tree": true, "pwd": true, "echo": true, "printf": true, "which": true,
+	"whoami": true, "uname": true, "date": true, "diff": true,
+	"cut": true, "uniq": true, "tr": true, "nl": true,
+	"basename": true, "dirname": true, "realpath": true, "readlink": true,
+	"jq": true, "true": true, "false": true, "test": true,
+	"md5sum": true, "sha1sum": true, "sha256sum": true,
+}
+
+// readOnlyGitSubcommands are git subcommands that never write to the
+// repository or working tree.
+var readOnlyGitSubcommands = map[string]bool{
+	"status": true, "log": true, "diff": true, "show": true, "blame": true,
+	"ls-files": true, "ls-tree": true, "rev-parse": true, "describe": true,
+	"shortlog": true, "grep": true, "cat-file": true, "whatchanged": true,
+}
+
+// readOnlyGoSubcommands are go subcommands that only read the module.
+var readOnlyGoSubcommands = map[string]bool{
+	"list": true, "doc": true, "version": true, "env": true, "vet": true,
+}
+
+// readOnlyEnvAssignments are the variables a read-only command may set
+// before its program. Others can run code (PAGER, GIT_EXTERNAL_DIFF,
+// BASH_ENV, LD_PRELOAD), so any other assignment makes the command mutating.
+var readOnlyEnvAssignments = map[string]bool{
+	"LANG": true, "LC_ALL": true, "LC_COLLATE": true, "LC_CTYPE": true,
+	"LC_MESSAGES": true, "LC_NUMERIC": true, "TZ": true,
+}
+
+// shellSeparator splits a command line into the commands of a pipeline or
+// list so each one can be checked on its own.
+var shellSeparator = regexp.MustCompile(`\|\||&&|[|;&\n]`)
+
+// IsReadOnlyCommand reports whether a bash command line only reads. It is
+// deliberately conservative: output redirection, command and process
+// substitution and any program not known to be side-effect free make the
+// command mutating.
+func IsReadOnlyCommand(command string) bool {
+	if strings.TrimSpace(command) == "" {
+		return false
+	}
+	if strings.ContainsAny(command, ">`") || strings.Contains(command, "$(") || strings.Contains(command, "<(") {
+		return false
+	}
+	for _, segment := range shellSeparator.Split(command, -1) {
+		fields := strings.Fields(segment)
+		if len(fields) == 0 {
+			continue
+		}
+		// Skip leading VAR=value assignments of vetted variables only
+		for len(fields) > 0 && strings.Contains(fields[0], "=") && !strings.HasPrefix(fields[0], "-") {
+			name, _, _ := strings.Cut(fields[0], "=")
+			if !readOnlyEnvAssignments[name] {
+				return false
+			}
+			fields = fields[1:]
+		}
+		if len(fields) == 0 || !readOnlyInvocation(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+func readOnlyInvocation(fields []string) bool {
+	program := fields[0]
+	if i := strings.LastIndex(program, "/"); i >= 0 {
+		program = program[i+1:]
+	}
+	args := fields[1:]
+	switch program {
+	case "git":
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "--output") {
+				return false
+			}
+		}
+		for i, arg := range args {
+			if !strings.HasPrefix(arg, "-") {
+				if arg == "grep" && gitGrepRunsPager(args[i+1:]) {
+					return false
+				}
+				return readOnlyGitSubcommands[arg]
+			}
+		}
+		return false
+	case "go":
+		if len(args) == 0 || !readOnlyGoSubcommands[args[0]] {
+			return false
+		}
+		for _, arg := range args[1:] {
+			if !strings.HasPrefix(arg, "-") {
+				continue
+			}
+			flag := "-" + strings.TrimLeft(arg, "-")
+			switch {
+			case args[0] == "env" && (flag == "-w" || flag == "-u"):
+				// go env -w and -u write the go env file
+				return false
+			case strings.HasPrefix(flag, "-vettool"), strings.HasPrefix(flag, "-toolexec"), strings.HasPrefix(flag, "-exec"):
+				return false
+			}
+		}
+		return true
+	case "find":
+		for _, arg := range args {
+			switch arg {
+			case "-delete", "-exec", "-execdir", "-ok", "-okdir":
+				return false
+			}
+			// -fprint, -fprint0, -fprintf and -fls write to a file
+			if strings.HasPrefix(arg, "-fprint") || arg == "-fls" {
+				return false
+			}
+		}
+		return true
+	case "sort":
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "--output") || strings.HasPrefix(arg, "--compress-program") {
+				return false
+			}
+			// -o FILE, -oFILE and bundled forms such as -uo FILE
+			if shortFlagsContain(arg, 'o') {
+				return false
+			}
+		}
+		return true
+	case "uniq":
+		// uniq's second operand is the file it writes
+		return operandCount(args, "-f", "-s", "-w") <= 1
+	case "tree":
+		for _, arg := range args {
+			if arg == "--output" || shortFlagsContain(arg, 'o') {
+				return false
+			}
+		}
+		return true
+	case "rg":
+		for _, arg := range args {
+			// --pre runs a program on every searched file
+			if arg == "--pre" || strings.HasPrefix(arg, "--pre=") {
+				return false
+			}
+		}
+		return true
+	case "env":
+		// env runs its arguments as a command unless it only prints
+		return len(fields) == 1
+	}
+	return readOnlyCommands[program]
+}
+
+// gitGrepRunsPager reports whether git grep's arguments include -O or
+// --open-files-in-pager, which run a program on the matching files.
+func gitGrepRunsPager(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if strings.HasPrefix(arg, "--open-files-in-pager") || shortFlagsContain(arg, 'O') {
+			return true
+		}
+	}
+	return false
+}
+
+// shortFlagsContain reports whether arg is a single-dash flag group that
+// includes flag, like -o, -ofile or -uo.
+func shortFlagsContain(arg string, flag byte) bool {
+	return len(arg) > 1 && arg[0] == '-' && arg[1] != '-' && strings.IndexByte(arg[1:], flag) >= 0
+}
+
+// operandCount counts the arguments that are not flags, skipping the value
+// of each of valueFlags given as a separate argument.
+func operandCount(args []string, valueFlags ...string) int {
+	count := 0
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return count + len(args) - i - 1
+		case arg == "-" || !strings.HasPrefix(arg, "-"):
+			count++
+		default:
+			for _, flag := range valueFlags {
+				if arg == flag {
+					i++
+					break
+				}
+			}
+		}
+	}
+	return count
+}
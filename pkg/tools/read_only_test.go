@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlyCommand(t *testing.T) {
+	readOnly := []string{
+		"ls -la",
+		"cat main.go | grep func | wc -l",
+		"git status && git diff HEAD~1",
+		"git --no-pager log --oneline -5",
+		"find . -name '*.go'",
+		"LC_ALL=C sort go.mod",
+		"LANG=C TZ=UTC ls -l",
+		"go list ./...",
+		"go env GOPATH",
+		"go doc exec",
+		"go vet ./...",
+		"uniq -c a.txt",
+		"uniq -f 2 a.txt",
+		"sort -n -k2 a.txt",
+		"tree -L 2",
+		"rg --pre-glob '*.gz' foo",
+		"git grep -n foo",
+		"find . -print0",
+	}
+	for _, command := range readOnly {
+		assert.True(t, IsReadOnlyCommand(command), command)
+	}
+
+	mutating := []string{
+		"",
+		"rm -rf build",
+		"echo hi > out.txt",
+		"cat a >> b",
+		"ls; rm x",
+		"git commit -m wip",
+		"git diff --output=patch.diff",
+		"find . -name '*.tmp' -delete",
+		"find . -exec rm {} ;",
+		"sort -o sorted.txt input.txt",
+		"echo $(touch x)",
+		"env rm x",
+		"go build ./...",
+		"make",
+		"npm install",
+		"GIT_EXTERNAL_DIFF=rm git diff",
+		"PAGER=rm git log",
+		"LD_PRELOAD=/tmp/x.so ls",
+		"BASH_ENV=x cat f",
+		"cat <(touch /tmp/pwned)",
+		"diff <(ls a) <(ls b)",
+		"tee >(cat)",
+		"uniq a.txt b.txt",
+		"uniq -c -f 1 a.txt b.txt",
+		"uniq -- a.txt b.txt",
+		"tree -o out.txt",
+		"tree -ao out.txt",
+		"rg --pre ./x.sh foo",
+		"rg --pre=./x.sh foo",
+		"go env -w GOFLAGS=-x",
+		"go env -u GOFLAGS",
+		"go vet -vettool=/tmp/x ./...",
+		"go vet --vettool /tmp/x ./...",
+		"go list -toolexec /tmp/x ./...",
+		"find . -fprint0 out",
+		"find . -fprint out",
+		"find . -fprintf out %p",
+		"find . -fls out",
+		"git grep --open-files-in-pager=sh x",
+		"git grep -Osh x",
+		"git grep -n -O sh x",
+		"sort -ofile a",
+		"sort -o file a",
+		"sort -uo file a",
+		"sort --output=file a",
+		"sort --compress-program=sh a",
+	}
+	for _, command := range mutating {
+		assert.False(t, IsReadOnlyCommand(command), command)
+	}
+}
+
+func TestIsReadOnlyTool(t *testing.T) {
+	assert.True(t, IsReadOnlyTool("readFile"))
+	assert.True(t, IsReadOnlyTool("gitDiff"))
+	assert.False(t, IsReadOnlyTool("writeFile"))
+	assert.False(t, IsReadOnlyTool("bash"))
+	assert.False(t, IsReadOnlyTool("mcp_unknown"))
+}
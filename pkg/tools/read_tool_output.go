@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// ReadToolOutputTool pages through tool output that was truncated before it
+// reached the model.
+type ReadToolOutputTool struct {
+	store *OutputStore
+}
+
+// NewReadToolOutputTool creates a readToolOutput tool backed by store.
+func NewReadToolOutputTool(store *OutputStore) Tool {
+	return &ReadToolOutputTool{store: store}
+}
+
+// Declaration returns the function declaration for the readToolOutput tool
+func (t *ReadToolOutputTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name:        "readToolOutput",
+		Description: "Read more of a tool result that was truncated. Truncated results end with a note giving the handle and the byte offset to continue from.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for reading stored tool output",
+			Properties: map[string]*ai.Schema{
+				"handle": {
+					Type:        ai.TypeString,
+					Description: "Handle from the truncation note, e.g. 'out_0123456789abcdef'",
+				},
+				"offset": {
+					Type:        ai.TypeInteger,
+					Description: "Byte offset to start reading from, as given in the truncation note or a previous next_offset (default: 0)",
+					Minimum:     0,
+				},
+			},
+			Required: []string{"handle"},
+		},
+		Response: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "A slice of stored tool output",
+			Properties: map[string]*ai.Schema{
+				"success": {
+					Type:        ai.TypeBoolean,
+					Description: "Whether the output was read",
+				},
+				"content": {
+					Type:        ai.TypeString,
+					Description: "The requested slice of output",
+				},
+				"next_offset": {
+					Type:        ai.TypeInteger,
+					Description: "Offset to pass on the next call; equals total_bytes when done",
+				},
+				"total_bytes": {
+					Type:        ai.TypeInteger,
+					Description: "Total size of the stored output in bytes",
+				},
+				"error": {
+					Type:        ai.TypeString,
+					Description: "Error message if reading failed",
+				},
+			},
+			Required: []string{"success"},
+		},
+	}
+}
+
+// Handler returns the function handler for the readToolOutput tool
+func (t *ReadToolOutputTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		handle, _ := params["handle"].(string)
+		if handle == "" {
+			return nil, fmt.Errorf("handle parameter is required")
+		}
+
+		offset := 0
+		if offsetParam, ok := params["offset"].(float64); ok {
+			offset = int(offsetParam)
+		}
+
+		content, next, total, err := t.store.Read(handle, offset)
+		if err != nil {
+			return map[string]any{
+				"success": false,
+				"error":   err.Error(),
+			}, nil
+		}
+
+		return map[string]any{
+			"success":     true,
+			"content":     content,
+			"next_offset": next,
+			"total_bytes": total,
+		}, nil
+	}
+}
+
+// FormatOutput formats the readToolOutput result for user display
+func (t *ReadToolOutputTool) FormatOutput(result map[string]any) string {
+	if success, _ := result["success"].(bool); !success {
+		if errMsg, ok := result["error"].(string); ok {
+			return fmt.Sprintf("**Failed to read tool output**: %s", errMsg)
+		}
+		return "**Failed to read tool output**"
+	}
+	return fmt.Sprintf("Read stored output up to byte %v of %v", result["next_offset"], result["total_bytes"])
+}
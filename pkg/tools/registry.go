@@ -5,7 +5,9 @@ import (
 	"log/slog"
 	"sync"
 
+	"github.com/kcaldas/genie/pkg/config"
 	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/lsp"
 	"github.com/kcaldas/genie/pkg/skills"
 	"github.com/kcaldas/genie/pkg/tools/process"
 )
@@ -58,7 +60,9 @@ type DefaultRegistry struct {
 	mutex           sync.RWMutex
 	mcpClient       MCPClient
 	processRegistry *process.Registry
+	lspManager      *lsp.Manager
 	initialized     bool
+	conflicts       []ToolConflict
 }
 
 // NewRegistry creates a new empty tool registry
@@ -76,8 +80,8 @@ func NewDefaultRegistry(eventBus events.EventBus, todoManager TodoManager, skill
 }
 
 // NewDefaultRegistryWithoutTask creates the normal interactive registry but
-// omits Task. Native Task executors use this for child sessions to avoid
-// recursive task trees.
+// omits Task and runAgent. Native Task executors and sub-agents use this for
+// child sessions to avoid recursive task trees.
 func NewDefaultRegistryWithoutTask(eventBus events.EventBus, todoManager TodoManager, skillManager SkillManager, mcpClient MCPClient) Registry {
 	return newDefaultRegistry(eventBus, todoManager, skillManager, mcpClient, false)
 }
@@ -86,11 +90,21 @@ func newDefaultRegistry(eventBus events.EventBus, todoManager TodoManager, skill
 	// Create shared process registry for PTY/background session management
 	processRegistry := process.NewRegistry()
 
+	// Language servers start on the first code intelligence question
+	lspManager := lsp.NewManager()
+
+	// Reads back oversized output the prompt loader moved to disk
+	outputStore := NewConfiguredOutputStore(config.NewConfigManager())
+	if err := outputStore.Prune(OutputRetention); err != nil {
+		slog.Debug("Failed to prune stored tool output", "error", err)
+	}
+
 	registry := &DefaultRegistry{
 		tools:           make(map[string]Tool),
 		toolSets:        make(map[string][]Tool),
 		mcpClient:       mcpClient,
 		processRegistry: processRegistry,
+		lspManager:      lspManager,
 	}
 
 	// Register all tools
@@ -116,12 +130,35 @@ func newDefaultRegistry(eventBus events.EventBus, todoManager TodoManager, skill
 		NewGitCommitTool(eventBus),                    // Commit dirty files with host-attributed author
 		NewGitRestoreTool(eventBus),                   // Restore a path from history
 		NewTodoWriteTool(todoManager),                 // Todo write tool
+		NewTodoReadTool(todoManager),                  // Todo read tool
 		NewThinkingTool(eventBus),                     // Thinking tool
 		process.NewTool(processRegistry, eventBus),    // Process session management
+		NewReadToolOutputTool(outputStore),            // Page through truncated tool output
+		NewRunChecksTool(eventBus),                    // Project build, lint and test commands
+		NewHTTPRequestTool(eventBus),                  // Confirmed HTTP requests for API debugging
+		NewRunSnippetTool(eventBus),                   // Scratch Python, Node.js and Go programs
+		NewRememberTool(eventBus, nil),                // Long-term memory across sessions
 	}
 
+	// GitHub issues, pull requests, reviews and checks via the gh CLI
+	githubTools := NewGitHubTools(eventBus, nil)
+	tools = append(tools, githubTools...)
+
+	// Containers, images, logs, exec and builds via docker or podman
+	dockerTools := NewDockerTools(eventBus, nil)
+	tools = append(tools, dockerTools...)
+
+	// Read-only cluster inspection via kubectl; mutations are opt-in
+	k8sTools := NewKubernetesTools(eventBus, nil)
+	tools = append(tools, k8sTools...)
+
+	// Diagnostics, definitions and references from language servers
+	lspTools := NewLSPTools(eventBus, lspManager)
+	tools = append(tools, lspTools...)
+
 	if includeTask {
 		tools = append(tools, NewTaskTool(eventBus, taskOptions...)) // Task tool for async research
+		tools = append(tools, NewRunAgentTool())                     // Delegate scoped work to a sub-agent
 	}
 
 	// Add Skill tool if skill manager is available
@@ -137,7 +174,9 @@ func newDefaultRegistry(eventBus events.EventBus, todoManager TodoManager, skill
 	// Register "essentials" toolset
 	essentialsTools := []Tool{
 		NewTodoWriteTool(todoManager),
+		NewTodoReadTool(todoManager),
 		NewThinkingTool(eventBus),
+		NewReadToolOutputTool(outputStore),
 	}
 
 	// Add Skill tool to essentials if skillManager is available
@@ -147,6 +186,18 @@ func newDefaultRegistry(eventBus events.EventBus, todoManager TodoManager, skill
 
 	_ = registry.RegisterToolSet("essentials", essentialsTools) // Safe to ignore error as these are internal tools
 
+	// Register "github" toolset; an MCP server named github replaces it on Init
+	_ = registry.RegisterToolSet(GitHubToolSetName, githubTools)
+
+	// Register "docker" toolset; an MCP server named docker replaces it on Init
+	_ = registry.RegisterToolSet(DockerToolSetName, dockerTools)
+
+	// Register "k8s" toolset
+	_ = registry.RegisterToolSet(KubernetesToolSetName, k8sTools)
+
+	// Register "lsp" toolset
+	_ = registry.RegisterToolSet(LSPToolSetName, lspTools)
+
 	return registry
 }
 
@@ -294,6 +345,18 @@ func (r *DefaultRegistry) GetToolSetNames() []string {
 	return names
 }
 
+// DisableProjectConfig stops Init from loading the workspace's own MCP
+// configuration, and the language servers from reading its lsp.json, for
+// workspaces the user has not trusted.
+func (r *DefaultRegistry) DisableProjectConfig() {
+	if disabler, ok := r.mcpClient.(interface{ DisableProjectConfig() }); ok {
+		disabler.DisableProjectConfig()
+	}
+	if r.lspManager != nil {
+		r.lspManager.DisableProjectConfig()
+	}
+}
+
 // Init initializes the registry by initializing the MCP client with the working directory.
 // This triggers MCP config discovery and server connections for the specified directory.
 func (r *DefaultRegistry) Init(workingDir string) error {
@@ -310,14 +373,32 @@ func (r *DefaultRegistry) Init(workingDir string) error {
 			return fmt.Errorf("failed to initialize MCP client: %w", err)
 		}
 
+		// Let the client rename tools that clash with built-in tools or
+		// with each other
+		shadowed := make(map[string]bool)
+		if resolver, ok := r.mcpClient.(ToolNameResolver); ok {
+			builtin := make([]string, 0, len(r.tools))
+			for name := range r.tools {
+				builtin = append(builtin, name)
+			}
+			r.conflicts = resolver.ResolveToolNames(builtin)
+			for _, conflict := range r.conflicts {
+				slog.Warn("Tool name conflict", "conflict", conflict.String())
+				if conflict.ShadowsBuiltin() {
+					shadowed[conflict.Name] = true
+				}
+			}
+		}
+
 		// Register MCP tools now that client is initialized. Built-in
-		// tools always win a name collision: allowing an MCP server to
-		// shadow bash/readFile/writeFile would let it silently intercept
-		// every "safe" tool call and its arguments.
+		// tools win a name collision unless the server was configured to
+		// override them: letting any MCP server shadow bash/readFile/
+		// writeFile would let it silently intercept every "safe" tool
+		// call and its arguments.
 		mcpTools := r.mcpClient.GetTools()
 		for _, tool := range mcpTools {
 			name := tool.Declaration().Name
-			if _, exists := r.tools[name]; exists {
+			if _, exists := r.tools[name]; exists && !shadowed[name] {
 				slog.Warn("Ignoring MCP tool that collides with a built-in tool", "tool", name)
 				continue
 			}
@@ -337,6 +418,14 @@ func (r *DefaultRegistry) Init(workingDir string) error {
 	return nil
 }
 
+// ToolConflicts returns the tool names more than one source offered and
+// how each was settled, once Init has run.
+func (r *DefaultRegistry) ToolConflicts() []ToolConflict {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return append([]ToolConflict(nil), r.conflicts...)
+}
+
 // MCPServerErrors returns per-server connection errors from the MCP client.
 func (r *DefaultRegistry) MCPServerErrors() map[string]string {
 	r.mutex.RLock()
@@ -350,11 +439,15 @@ func (r *DefaultRegistry) MCPServerErrors() map[string]string {
 
 // Shutdown releases external resources owned by the registry:
 // terminates background PTY/process sessions (SIGTERM then SIGKILL,
-// process-group wide) and closes MCP server subprocesses.
+// process-group wide) and closes MCP server and language server
+// subprocesses.
 func (r *DefaultRegistry) Shutdown() {
 	if r.processRegistry != nil {
 		r.processRegistry.Shutdown()
 	}
+	if r.lspManager != nil {
+		_ = r.lspManager.Close()
+	}
 	if closer, ok := r.mcpClient.(interface{ Close() error }); ok && r.mcpClient != nil {
 		_ = closer.Close()
 	}
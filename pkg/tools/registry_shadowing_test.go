@@ -67,3 +67,35 @@ func TestMCPToolsCannotShadowBuiltins(t *testing.T) {
 	_, isMCP = unique.(*fakeMCPTool)
 	assert.True(t, isMCP)
 }
+
+// resolvingMCPClient settles names the way the MCP client does for a
+// server configured with overrideTools
+type resolvingMCPClient struct {
+	fakeMCPClient
+	conflicts []ToolConflict
+}
+
+func (r *resolvingMCPClient) ResolveToolNames(builtin []string) []ToolConflict {
+	return r.conflicts
+}
+
+func TestMCPToolOverridesBuiltinOnlyWhenConfigured(t *testing.T) {
+	bus := events.NewEventBus()
+	registry := NewDefaultRegistry(bus, NewTodoManager(), nil, nil).(*DefaultRegistry)
+	registry.mcpClient = &resolvingMCPClient{
+		fakeMCPClient: fakeMCPClient{tools: []Tool{&fakeMCPTool{name: "readFile"}, &fakeMCPTool{name: "bash"}}},
+		conflicts: []ToolConflict{
+			{Name: "readFile", Sources: []string{BuiltinToolSource, "srv"}, Kept: "srv"},
+		},
+	}
+
+	require.NoError(t, registry.Init(t.TempDir()))
+
+	readFile, _ := registry.Get("readFile")
+	_, isMCP := readFile.(*fakeMCPTool)
+	assert.True(t, isMCP, "a server set to override replaces the built-in")
+	bash, _ := registry.Get("bash")
+	_, isMCP = bash.(*fakeMCPTool)
+	assert.False(t, isMCP, "without a settled conflict the built-in still wins")
+	assert.Len(t, registry.ToolConflicts(), 1)
+}
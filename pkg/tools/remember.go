@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/config"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/memory"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+// RememberTool stores a fact in long-term memory, from which it is
+// recalled into later sessions when relevant.
+type RememberTool struct {
+	publisher events.Publisher
+	store     *memory.Store
+}
+
+// NewRememberTool creates the remember tool. A nil store opens the one in
+// ~/.genie on each call, so configuration changes apply.
+func NewRememberTool(publisher events.Publisher, store *memory.Store) Tool {
+	return &RememberTool{publisher: publisher, store: store}
+}
+
+// Declaration returns the function declaration for remember.
+func (t *RememberTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "remember",
+		Description: "Store a fact in long-term memory so it is recalled in future sessions when relevant. " +
+			"Use it only when the user asks you to remember something, or states a lasting preference or fact " +
+			"about themselves or the project. Write the fact so it stands on its own, e.g. 'The staging database " +
+			"listens on port 5433'. Do not store secrets.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for remember",
+			Properties: map[string]*ai.Schema{
+				"fact": {
+					Type:        ai.TypeString,
+					Description: "The fact to remember, as one self-contained sentence or short paragraph.",
+					MaxLength:   2000,
+				},
+				"scope": {
+					Type: ai.TypeString,
+					Description: "'project' (default) recalls the fact only in this workspace; 'global' recalls it " +
+						"in every project, for facts about the user.",
+					Enum: []string{"project", "global"},
+				},
+				"_display_message": {
+					Type:        ai.TypeString,
+					Description: "Short user-facing status (e.g. 'remembering the staging port').",
+					MinLength:   5,
+					MaxLength:   200,
+				},
+			},
+			Required: []string{"fact", "_display_message"},
+		},
+		Response: &ai.Schema{
+			Type: ai.TypeObject,
+			Properties: map[string]*ai.Schema{
+				"success": {Type: ai.TypeBoolean},
+				"id":      {Type: ai.TypeString, Description: "ID of the memory, for genie memory forget"},
+				"scope":   {Type: ai.TypeString},
+				"error":   {Type: ai.TypeString},
+			},
+			Required: []string{"success"},
+		},
+	}
+}
+
+// Handler returns the function handler for remember.
+func (t *RememberTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if t.publisher != nil {
+			msg, ok := params["_display_message"].(string)
+			if !ok || msg == "" {
+				return nil, fmt.Errorf("_display_message parameter is required")
+			}
+			t.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{ToolName: "remember", Message: msg})
+		}
+
+		fact, _ := params["fact"].(string)
+		if strings.TrimSpace(fact) == "" {
+			return failResult("fact is required"), nil
+		}
+		scope := memory.GlobalScope
+		switch name, _ := params["scope"].(string); name {
+		case "", "project":
+			cwd, ok := toolctx.WorkingDir(ctx)
+			if !ok || cwd == "" {
+				return failResult("no workspace to remember the fact for; use scope 'global'"), nil
+			}
+			scope = cwd
+		case "global":
+		default:
+			return failResult(fmt.Sprintf("scope must be project or global, got %q", name)), nil
+		}
+
+		store := t.store
+		if store == nil {
+			var err error
+			if store, err = memory.Open(config.NewConfigManager()); err != nil {
+				return failResult(err.Error()), nil
+			}
+		}
+		remembered, err := store.Add(ctx, fact, scope)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		result := map[string]any{"success": true, "id": remembered.ID, "scope": "project"}
+		if remembered.Scope == memory.GlobalScope {
+			result["scope"] = "global"
+		}
+		return result, nil
+	}
+}
+
+// FormatOutput formats the stored memory for the host UI.
+func (t *RememberTool) FormatOutput(result map[string]interface{}) string {
+	if success, _ := result["success"].(bool); !success {
+		msg, _ := result["error"].(string)
+		return fmt.Sprintf("**Could not remember**: %s", msg)
+	}
+	id, _ := result["id"].(string)
+	scope, _ := result["scope"].(string)
+	return fmt.Sprintf("**Remembered** (%s, id %s)", scope, id)
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/memory"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRememberStoresFactsForTheWorkspace(t *testing.T) {
+	store := memory.NewStore(filepath.Join(t.TempDir(), memory.FileName), memory.LocalEmbedder{})
+	workspace := t.TempDir()
+	ctx := toolctx.WithWorkingDir(context.Background(), workspace)
+	tool := NewRememberTool(events.NewEventBus(), store)
+
+	result, err := tool.Handler()(ctx, map[string]any{
+		"_display_message": "remembering the staging port",
+		"fact":             "The staging database listens on port 5433",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, "project", result["scope"])
+
+	result, err = tool.Handler()(ctx, map[string]any{
+		"_display_message": "remembering the user's name",
+		"fact":             "The user's name is Ana",
+		"scope":            "global",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "global", result["scope"])
+
+	inWorkspace, err := store.List(workspace)
+	require.NoError(t, err)
+	assert.Len(t, inWorkspace, 2)
+	elsewhere, err := store.List(t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, elsewhere, 1)
+	assert.Equal(t, "The user's name is Ana", elsewhere[0].Text)
+}
+
+func TestRememberNeedsAWorkspaceForProjectFacts(t *testing.T) {
+	store := memory.NewStore(filepath.Join(t.TempDir(), memory.FileName), memory.LocalEmbedder{})
+	result, err := NewRememberTool(nil, store).Handler()(context.Background(), map[string]any{"fact": "Ship on Fridays"})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+const (
+	defaultSubAgentMaxTokens = 200000
+	maxSubAgentMaxTokens     = 1000000
+)
+
+// DefaultSubAgentTools is the toolset a sub-agent receives when the caller
+// does not name one: read-only exploration, so delegated work cannot change
+// the workspace unless the model asks for write tools explicitly.
+var DefaultSubAgentTools = []string{
+	"listFiles", "findFiles", "searchInFiles", "readFile",
+	"gitStatus", "gitLog", "gitDiff", "gitShow", "thinking", "readToolOutput",
+}
+
+var errSubAgentRunnerNotConfigured = errors.New("sub-agent runner is not configured")
+
+// SubAgentRequest describes a task delegated to a sub-agent.
+type SubAgentRequest struct {
+	Task      string
+	Persona   string   // empty uses the parent's persona
+	Tools     []string // tool names the sub-agent may use
+	MaxTokens int64    // input + output token budget
+	Workspace string
+}
+
+// SubAgentResult is the sub-agent's final answer and what it cost.
+type SubAgentResult struct {
+	Output          string
+	InputTokens     int64
+	OutputTokens    int64
+	BudgetExhausted bool
+}
+
+// SubAgentRunner runs a delegated task to completion in an isolated session.
+type SubAgentRunner interface {
+	RunSubAgent(ctx context.Context, request SubAgentRequest) (SubAgentResult, error)
+}
+
+// RunAgentTool delegates a scoped task to a sub-agent and returns its
+// summarized result. The runner is installed by the host after start-up,
+// the same way the Task tool receives its executor.
+type RunAgentTool struct {
+	mu     sync.RWMutex
+	runner SubAgentRunner
+}
+
+// NewRunAgentTool creates a runAgent tool without a runner.
+func NewRunAgentTool() Tool {
+	return &RunAgentTool{}
+}
+
+// SetRunnerIfUnconfigured installs runner unless one is already set.
+func (t *RunAgentTool) SetRunnerIfUnconfigured(runner SubAgentRunner) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if runner == nil || t.runner != nil {
+		return false
+	}
+	t.runner = runner
+	return true
+}
+
+// Declaration returns the function declaration for the runAgent tool
+func (t *RunAgentTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name:        "runAgent",
+		Description: "Delegate a self-contained task (e.g. 'find all usages of X and explain how they differ') to a sub-agent with its own session, a restricted toolset and a token budget. Blocks until the sub-agent finishes and returns its summarized findings. By default the sub-agent can only read the workspace.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for delegating a task to a sub-agent",
+			Properties: map[string]*ai.Schema{
+				"task": {
+					Type:        ai.TypeString,
+					Description: "Complete, standalone description of what the sub-agent should do and what it should report back",
+				},
+				"persona": {
+					Type:        ai.TypeString,
+					Description: "Persona ID for the sub-agent (default: the current persona)",
+				},
+				"tools": {
+					Type:        ai.TypeArray,
+					Description: "Tool names the sub-agent may use (default: read-only tools: " + strings.Join(DefaultSubAgentTools, ", ") + ")",
+					Items:       &ai.Schema{Type: ai.TypeString},
+				},
+				"max_tokens": {
+					Type:        ai.TypeInteger,
+					Description: fmt.Sprintf("Token budget for the sub-agent, input plus output (default: %d, max: %d)", defaultSubAgentMaxTokens, maxSubAgentMaxTokens),
+					Minimum:     1000,
+					Maximum:     maxSubAgentMaxTokens,
+				},
+			},
+			Required: []string{"task"},
+		},
+		Response: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Result of the delegated task",
+			Properties: map[string]*ai.Schema{
+				"success": {
+					Type:        ai.TypeBoolean,
+					Description: "Whether the sub-agent completed",
+				},
+				"result": {
+					Type:        ai.TypeString,
+					Description: "The sub-agent's summarized findings",
+				},
+				"budget_exhausted": {
+					Type:        ai.TypeBoolean,
+					Description: "True when the sub-agent was stopped by its token budget; result may be partial or empty",
+				},
+				"tokens_used": {
+					Type:        ai.TypeInteger,
+					Description: "Input plus output tokens the sub-agent used",
+				},
+				"error": {
+					Type:        ai.TypeString,
+					Description: "Error message if the sub-agent failed",
+				},
+			},
+			Required: []string{"success"},
+		},
+	}
+}
+
+// Handler returns the function handler for the runAgent tool
+func (t *RunAgentTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		task, _ := params["task"].(string)
+		task = strings.TrimSpace(task)
+		if task == "" {
+			return nil, fmt.Errorf("task parameter is required")
+		}
+
+		t.mu.RLock()
+		runner := t.runner
+		t.mu.RUnlock()
+		if runner == nil {
+			return nil, errSubAgentRunnerNotConfigured
+		}
+
+		request := SubAgentRequest{
+			Task:      task,
+			Tools:     DefaultSubAgentTools,
+			MaxTokens: defaultSubAgentMaxTokens,
+		}
+		if persona, ok := params["persona"].(string); ok {
+			request.Persona = strings.TrimSpace(persona)
+		}
+		if rawTools, ok := params["tools"].([]any); ok && len(rawTools) > 0 {
+			request.Tools = nil
+			for _, raw := range rawTools {
+				if name, ok := raw.(string); ok && strings.TrimSpace(name) != "" {
+					request.Tools = append(request.Tools, strings.TrimSpace(name))
+				}
+			}
+		}
+		if maxTokens, ok := params["max_tokens"].(float64); ok && maxTokens > 0 {
+			request.MaxTokens = min(int64(maxTokens), maxSubAgentMaxTokens)
+		}
+		if dir, ok := toolctx.WorkingDir(ctx); ok {
+			request.Workspace = dir
+		}
+
+		result, err := runner.RunSubAgent(ctx, request)
+		response := map[string]any{
+			"success":          err == nil && !result.BudgetExhausted,
+			"result":           result.Output,
+			"budget_exhausted": result.BudgetExhausted,
+			"tokens_used":      result.InputTokens + result.OutputTokens,
+		}
+		if err != nil {
+			response["error"] = err.Error()
+		}
+		return response, nil
+	}
+}
+
+// FormatOutput formats the runAgent result for user display
+func (t *RunAgentTool) FormatOutput(result map[string]any) string {
+	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
+		return fmt.Sprintf("**Sub-agent failed**: %s", errMsg)
+	}
+	if exhausted, _ := result["budget_exhausted"].(bool); exhausted {
+		return fmt.Sprintf("**Sub-agent stopped at its token budget** (%v tokens)", result["tokens_used"])
+	}
+	output, _ := result["result"].(string)
+	return fmt.Sprintf("**Sub-agent finished** (%v tokens)\n\n%s", result["tokens_used"], output)
+}
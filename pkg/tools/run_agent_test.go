@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+)
+
+type fakeSubAgentRunner struct {
+	request SubAgentRequest
+	result  SubAgentResult
+	err     error
+}
+
+func (r *fakeSubAgentRunner) RunSubAgent(ctx context.Context, request SubAgentRequest) (SubAgentResult, error) {
+	r.request = request
+	return r.result, r.err
+}
+
+func TestRunAgentTool_DefaultsToReadOnlyTools(t *testing.T) {
+	runner := &fakeSubAgentRunner{result: SubAgentResult{Output: "found 3 usages", InputTokens: 100, OutputTokens: 20}}
+	tool := NewRunAgentTool().(*RunAgentTool)
+	require.True(t, tool.SetRunnerIfUnconfigured(runner))
+	assert.False(t, tool.SetRunnerIfUnconfigured(&fakeSubAgentRunner{}), "runner is only installed once")
+
+	ctx := toolctx.WithWorkingDir(context.Background(), "/work")
+	result, err := tool.Handler()(ctx, map[string]any{"task": "find usages of X"})
+	require.NoError(t, err)
+
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, "found 3 usages", result["result"])
+	assert.Equal(t, int64(120), result["tokens_used"])
+	assert.Equal(t, DefaultSubAgentTools, runner.request.Tools)
+	assert.Equal(t, int64(defaultSubAgentMaxTokens), runner.request.MaxTokens)
+	assert.Equal(t, "/work", runner.request.Workspace)
+}
+
+func TestRunAgentTool_ScopesRequest(t *testing.T) {
+	runner := &fakeSubAgentRunner{result: SubAgentResult{BudgetExhausted: true}}
+	tool := NewRunAgentTool().(*RunAgentTool)
+	tool.SetRunnerIfUnconfigured(runner)
+
+	result, err := tool.Handler()(context.Background(), map[string]any{
+		"task":       "refactor",
+		"persona":    "engineer",
+		"tools":      []any{"readFile", " ", "writeFile"},
+		"max_tokens": float64(5_000_000),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, false, result["success"])
+	assert.Equal(t, true, result["budget_exhausted"])
+	assert.Equal(t, "engineer", runner.request.Persona)
+	assert.Equal(t, []string{"readFile", "writeFile"}, runner.request.Tools)
+	assert.Equal(t, int64(maxSubAgentMaxTokens), runner.request.MaxTokens)
+}
+
+func TestRunAgentTool_RequiresRunner(t *testing.T) {
+	_, err := NewRunAgentTool().Handler()(context.Background(), map[string]any{"task": "x"})
+	assert.ErrorIs(t, err, errSubAgentRunnerNotConfigured)
+}
+
+func TestFilteredRegistry(t *testing.T) {
+	base := NewDefaultRegistryWithoutTask(nil, NewTodoManager(), nil, nil)
+	filtered := NewFilteredRegistry(base, []string{"readFile", "thinking"})
+
+	_, ok := filtered.Get("readFile")
+	assert.True(t, ok)
+	_, ok = filtered.Get("writeFile")
+	assert.False(t, ok)
+	assert.ElementsMatch(t, []string{"readFile", "thinking"}, filtered.Names())
+	assert.Len(t, filtered.GetAll(), 2)
+
+	essentials, ok := filtered.GetToolSet("essentials")
+	require.True(t, ok)
+	require.Len(t, essentials, 1)
+	assert.Equal(t, "thinking", essentials[0].Declaration().Name)
+}
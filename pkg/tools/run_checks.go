@@ -0,0 +1,383 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/kcaldas/genie/pkg/tools/process"
+)
+
+const (
+	// defaultCheckTimeout bounds each check; test suites take a while
+	defaultCheckTimeout = 10 * time.Minute
+
+	// maxCheckTimeout is the longest timeout_seconds accepts
+	maxCheckTimeout = time.Hour
+
+	// checkOutputLines is how much of a failed check's output is kept:
+	// its end, where the summary and the last failures are
+	checkOutputLines = 80
+
+	// maxCheckProblems caps the file locations picked out of one check
+	maxCheckProblems = 30
+)
+
+// RunChecksTool runs the project's build, lint and test commands and
+// reports which passed, with the failures located in files.
+type RunChecksTool struct {
+	publisher events.Publisher
+}
+
+// NewRunChecksTool creates the runChecks tool.
+func NewRunChecksTool(publisher events.Publisher) Tool {
+	return &RunChecksTool{publisher: publisher}
+}
+
+// Declaration returns the function declaration for runChecks.
+func (r *RunChecksTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "runChecks",
+		Description: "Run the project's checks — build, lint and test — and report which passed. " +
+			"The commands come from .genie/settings.json or are detected from go.mod, Cargo.toml, " +
+			"package.json, pyproject.toml or the Makefile. Use it to verify a change instead of " +
+			"guessing build commands for bash. Failed checks include the end of their output and " +
+			"the file:line locations it mentions. A failed build skips the checks after it.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for running checks",
+			Properties: map[string]*ai.Schema{
+				"checks": {
+					Type:        ai.TypeArray,
+					Description: "Checks to run by name, e.g. [\"test\"]. Default: all of them.",
+					Items:       &ai.Schema{Type: ai.TypeString},
+				},
+				"list": {
+					Type:        ai.TypeBoolean,
+					Description: "Only list the checks and their commands, without running them.",
+				},
+				"timeout_seconds": {
+					Type:        ai.TypeInteger,
+					Description: "Time limit for each check in seconds (default 600, max 3600).",
+					Minimum:     1,
+					Maximum:     3600,
+				},
+				"_display_message": {
+					Type:        ai.TypeString,
+					Description: "Short user-facing status shown in the host UI while this tool runs (e.g. 'running the tests'). Separate channel from your chat reply — don't repeat it there.",
+					MinLength:   5,
+					MaxLength:   200,
+				},
+			},
+			Required: []string{"_display_message"},
+		},
+		Response: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Outcome of the checks",
+			Properties: map[string]*ai.Schema{
+				"success": {Type: ai.TypeBoolean, Description: "Whether every check that ran passed"},
+				"results": {Type: ai.TypeString, Description: "One line per check, followed by the failures"},
+				"checks": {
+					Type:        ai.TypeArray,
+					Description: "Per check: name, command, status (passed, failed, timed out or skipped), exit_code, duration_ms, problems and output",
+					Items:       &ai.Schema{Type: ai.TypeObject},
+				},
+				"error": {Type: ai.TypeString, Description: "Why the checks could not run"},
+			},
+			Required: []string{"success"},
+		},
+	}
+}
+
+// Handler returns the function handler for runChecks.
+func (r *RunChecksTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if r.publisher != nil {
+			if msg, ok := params["_display_message"].(string); ok && msg != "" {
+				r.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{
+					ToolName: "runChecks",
+					Message:  msg,
+				})
+			} else {
+				return nil, fmt.Errorf("_display_message parameter is required")
+			}
+		}
+
+		root, ok := toolctx.WorkingDir(ctx)
+		if !ok || root == "" {
+			root, _ = os.Getwd()
+		}
+		// The settings name commands to run, so an untrusted workspace's
+		// are ignored
+		trusted, set := toolctx.WorkspaceTrusted(ctx)
+		checks, err := ProjectChecks(root, !set || trusted)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		if len(checks) == 0 {
+			return failResult("no checks found: add build, lint and test commands to .genie/" + ProjectSettingsFile +
+				`, e.g. {"checks": {"test": "make test"}}`), nil
+		}
+
+		checks, err = selectChecks(checks, params)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+
+		if list, _ := params["list"].(bool); list {
+			var b strings.Builder
+			reports := make([]any, 0, len(checks))
+			for _, check := range checks {
+				fmt.Fprintf(&b, "%s: %s (from %s)\n", check.Name, check.Command, check.Source)
+				reports = append(reports, map[string]any{"name": check.Name, "command": check.Command, "source": check.Source})
+			}
+			return map[string]any{
+				"success": true,
+				"results": strings.TrimRight(b.String(), "\n"),
+				"checks":  reports,
+			}, nil
+		}
+
+		timeout := defaultCheckTimeout
+		if seconds, ok := numberValue(params, "timeout_seconds"); ok && seconds > 0 {
+			timeout = min(time.Duration(seconds)*time.Second, maxCheckTimeout)
+		}
+
+		reports := make([]checkReport, 0, len(checks))
+		buildFailed := false
+		for _, check := range checks {
+			if ctx.Err() != nil {
+				break
+			}
+			if buildFailed {
+				reports = append(reports, checkReport{Check: check, Status: "skipped"})
+				continue
+			}
+			report := runCheck(ctx, root, check, timeout)
+			reports = append(reports, report)
+			if report.Status != "passed" && check.Name == "build" {
+				buildFailed = true
+			}
+		}
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return cancelledCommandResult(formatCheckReports(reports)), nil
+		}
+
+		passed := true
+		structured := make([]any, 0, len(reports))
+		for _, report := range reports {
+			if report.Status != "passed" && report.Status != "skipped" {
+				passed = false
+			}
+			structured = append(structured, report.toMap())
+		}
+		return map[string]any{
+			"success": passed,
+			"results": formatCheckReports(reports),
+			"checks":  structured,
+		}, nil
+	}
+}
+
+// selectChecks keeps the checks the "checks" parameter names, in the
+// project's order.
+func selectChecks(checks []Check, params map[string]any) ([]Check, error) {
+	names, _ := params["checks"].([]any)
+	if len(names) == 0 {
+		return checks, nil
+	}
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			wanted[strings.TrimSpace(s)] = true
+		}
+	}
+	var selected []Check
+	var available []string
+	for _, check := range checks {
+		available = append(available, check.Name)
+		if wanted[check.Name] {
+			selected = append(selected, check)
+			delete(wanted, check.Name)
+		}
+	}
+	if len(wanted) > 0 {
+		var unknown []string
+		for name := range wanted {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown check %s; this project has: %s",
+			strings.Join(unknown, ", "), strings.Join(available, ", "))
+	}
+	return selected, nil
+}
+
+// checkReport is the outcome of one check
+type checkReport struct {
+	Check
+	Status   string
+	ExitCode int
+	Duration time.Duration
+	Problems []string
+	Output   string
+}
+
+func (c checkReport) toMap() map[string]any {
+	m := map[string]any{
+		"name":    c.Name,
+		"command": c.Command,
+		"status":  c.Status,
+	}
+	if c.Status == "skipped" {
+		return m
+	}
+	m["exit_code"] = c.ExitCode
+	m["duration_ms"] = c.Duration.Milliseconds()
+	if len(c.Problems) > 0 {
+		m["problems"] = c.Problems
+	}
+	if c.Output != "" {
+		m["output"] = c.Output
+	}
+	return m
+}
+
+// runCheck runs one check in the workspace the way the bash tool runs
+// commands, keeping the output only when the check fails.
+func runCheck(ctx context.Context, root string, check Check, timeout time.Duration) checkReport {
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := process.DefaultShell().Command(execCtx, check.Command, true)
+	cmd.Dir = root
+	cmd.Env = os.Environ()
+	process.ConfigureGroupKill(cmd)
+	cmd.WaitDelay = 3 * time.Second
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	report := checkReport{Check: check, Status: "passed", Duration: time.Since(start)}
+	if err == nil {
+		return report
+	}
+
+	report.Status = "failed"
+	report.ExitCode = -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		report.ExitCode = exitErr.ExitCode()
+	}
+	if execCtx.Err() == context.DeadlineExceeded {
+		report.Status = "timed out"
+	}
+	report.Problems = checkProblems(string(output))
+	report.Output = lastLines(string(output), checkOutputLines)
+	if report.Output == "" {
+		report.Output = err.Error()
+	}
+	return report
+}
+
+var (
+	// problemPattern matches compiler, linter and test locations:
+	// "path/to/file.go:12:5: message" or "file_test.go:40: message"
+	problemPattern = regexp.MustCompile(`^\s*((?:[\w.\-]+/)*[\w.\-]+\.\w+):(\d+)(?::(\d+))?:?\s+(.+)$`)
+
+	// failedTestPattern matches Go's and other runners' failed test lines
+	failedTestPattern = regexp.MustCompile(`^\s*(--- FAIL: .+|FAILED .+|✕ .+)$`)
+)
+
+// checkProblems picks out the lines of output that point at a file
+// location or name a failed test.
+func checkProblems(output string) []string {
+	var problems []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !problemPattern.MatchString(line) && !failedTestPattern.MatchString(line) {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		problems = append(problems, line)
+		if len(problems) == maxCheckProblems {
+			break
+		}
+	}
+	return problems
+}
+
+// lastLines returns the last n lines of text
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n")
+	}
+	return fmt.Sprintf("... %d lines before\n%s", len(lines)-n, strings.Join(lines[len(lines)-n:], "\n"))
+}
+
+// formatCheckReports writes one line per check and then the failures,
+// for the model to act on.
+func formatCheckReports(reports []checkReport) string {
+	var b strings.Builder
+	for _, report := range reports {
+		switch report.Status {
+		case "skipped":
+			fmt.Fprintf(&b, "%s: skipped (the build failed) — %s\n", report.Name, report.Command)
+		case "passed":
+			fmt.Fprintf(&b, "%s: passed in %s — %s\n", report.Name, formatCheckDuration(report.Duration), report.Command)
+		default:
+			fmt.Fprintf(&b, "%s: %s (exit %d) in %s — %s\n", report.Name, strings.ToUpper(report.Status), report.ExitCode,
+				formatCheckDuration(report.Duration), report.Command)
+		}
+	}
+	for _, report := range reports {
+		if report.Status == "passed" || report.Status == "skipped" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", report.Name)
+		if len(report.Problems) > 0 {
+			b.WriteString("Problems:\n")
+			for _, problem := range report.Problems {
+				fmt.Fprintf(&b, "  %s\n", problem)
+			}
+		}
+		fmt.Fprintf(&b, "Output:\n%s\n", report.Output)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func formatCheckDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// FormatOutput shows the per-check summary lines.
+func (r *RunChecksTool) FormatOutput(result map[string]interface{}) string {
+	results, _ := result["results"].(string)
+	if success, _ := result["success"].(bool); !success && results == "" {
+		msg, _ := result["error"].(string)
+		return fmt.Sprintf("**Checks failed to run**: %s", msg)
+	}
+	summary, _, _ := strings.Cut(results, "\n\n")
+	title := "**Checks passed**"
+	if success, _ := result["success"].(bool); !success {
+		title = "**Checks failed**"
+	}
+	return fmt.Sprintf("%s\n```\n%s\n```", title, summary)
+}
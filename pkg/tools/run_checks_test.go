@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProjectFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func checkCommands(checks []Check) map[string]string {
+	commands := make(map[string]string)
+	for _, check := range checks {
+		commands[check.Name] = check.Command
+	}
+	return commands
+}
+
+func TestDetectChecksGoWithMakefile(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, "go.mod", "module example.com/x\n")
+	writeProjectFile(t, root, "Makefile", "build:\n\tgo build -o bin/x\n\ntest-race:\n\tgo test -race ./...\n")
+
+	checks := DetectChecks(root)
+	assert.Equal(t, map[string]string{
+		"build": "go build ./...",
+		"lint":  "go vet ./...",
+		"test":  "go test ./...",
+	}, checkCommands(checks))
+	assert.Equal(t, "build", checks[0].Name, "the build runs first")
+	assert.Equal(t, "test", checks[2].Name)
+}
+
+func TestDetectChecksNodeUsesLockfileRunner(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, "package.json", `{"scripts": {"build": "tsc", "lint": "eslint .", "test": "echo \"Error: no test specified\" && exit 1"}}`)
+	writeProjectFile(t, root, "yarn.lock", "")
+
+	assert.Equal(t, map[string]string{
+		"build": "yarn run build",
+		"lint":  "yarn run lint",
+	}, checkCommands(DetectChecks(root)), "npm's placeholder test script is not a test")
+}
+
+func TestProjectChecksSettingsOverrideDetection(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, "go.mod", "module example.com/x\n")
+	writeProjectFile(t, root, ".genie/settings.json", `{"checks": {"test": "make test", "lint": "", "e2e": "./e2e.sh"}}`)
+
+	checks, err := ProjectChecks(root, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"build": "go build ./...",
+		"test":  "make test",
+		"e2e":   "./e2e.sh",
+	}, checkCommands(checks))
+	assert.Equal(t, []string{"build", "test", "e2e"}, []string{checks[0].Name, checks[1].Name, checks[2].Name})
+
+	checks, err = ProjectChecks(root, false)
+	require.NoError(t, err)
+	assert.Equal(t, "go test ./...", checkCommands(checks)["test"], "untrusted settings are ignored")
+}
+
+func runChecks(t *testing.T, root string, params map[string]any) map[string]any {
+	t.Helper()
+	params["_display_message"] = "running checks"
+	ctx := toolctx.WithWorkingDir(context.Background(), root)
+	result, err := NewRunChecksTool(nil).Handler()(ctx, params)
+	require.NoError(t, err)
+	return result
+}
+
+func TestRunChecksReportsFailuresWithLocations(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, ".genie/settings.json", `{"checks": {
+		"build": "true",
+		"test": "echo ok; echo '    parse_test.go:42: expected 3, got 4'; echo '--- FAIL: TestParse (0.00s)'; exit 1"
+	}}`)
+
+	result := runChecks(t, root, map[string]any{})
+	assert.False(t, result["success"].(bool))
+	results := result["results"].(string)
+	assert.Contains(t, results, "build: passed")
+	assert.Contains(t, results, "test: FAILED (exit 1)")
+
+	checks := result["checks"].([]any)
+	require.Len(t, checks, 2)
+	test := checks[1].(map[string]any)
+	assert.Equal(t, "failed", test["status"])
+	assert.Equal(t, []string{"parse_test.go:42: expected 3, got 4", "--- FAIL: TestParse (0.00s)"}, test["problems"])
+	assert.Contains(t, test["output"], "ok")
+	assert.NotContains(t, checks[0].(map[string]any), "output", "passing checks keep their output out of the context")
+}
+
+func TestRunChecksSkipsTheRestWhenTheBuildFails(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, ".genie/settings.json", `{"checks": {"build": "echo 'main.go:3:1: syntax error'; exit 2", "test": "touch ran"}}`)
+
+	result := runChecks(t, root, map[string]any{})
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["results"], "test: skipped")
+	assert.NoFileExists(t, filepath.Join(root, "ran"))
+}
+
+func TestRunChecksSelectsAndLists(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, ".genie/settings.json", `{"checks": {"build": "exit 1", "test": "true"}}`)
+
+	result := runChecks(t, root, map[string]any{"checks": []any{"test"}})
+	assert.True(t, result["success"].(bool))
+	assert.Len(t, result["checks"], 1)
+
+	result = runChecks(t, root, map[string]any{"checks": []any{"bench"}})
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "unknown check bench; this project has: build, test")
+
+	result = runChecks(t, root, map[string]any{"list": true})
+	assert.True(t, result["success"].(bool))
+	assert.Equal(t, "build: exit 1 (from .genie/settings.json)\ntest: true (from .genie/settings.json)", result["results"])
+}
+
+func TestRunChecksIgnoresUntrustedSettings(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, ".genie/settings.json", `{"checks": {"test": "./steal.sh"}}`)
+
+	ctx := toolctx.WithWorkspaceTrusted(toolctx.WithWorkingDir(context.Background(), root), false)
+	result, err := NewRunChecksTool(nil).Handler()(ctx, map[string]any{"_display_message": "running checks", "list": true})
+	require.NoError(t, err)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "no checks found")
+}
+
+func TestRunChecksWithoutChecks(t *testing.T) {
+	result := runChecks(t, t.TempDir(), map[string]any{})
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "no checks found")
+}
@@ -0,0 +1,339 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/tools/process"
+)
+
+const (
+	defaultSnippetTimeout = 10 * time.Second
+	maxSnippetTimeout     = 2 * time.Minute
+
+	// snippetCompileTimeout bounds building a Go snippet, which the
+	// run's timeout does not cover
+	snippetCompileTimeout = 2 * time.Minute
+
+	defaultSnippetMemoryMB = 512
+	maxSnippetMemoryMB     = 4096
+
+	// snippetOutputBytes is how much of the start and of the end of each
+	// stream a result keeps
+	snippetOutputBytes = 16 * 1024
+)
+
+// snippetLanguage says how to run a snippet in one language.
+type snippetLanguage struct {
+	file string
+	// interpreters are tried in order; the first on PATH runs the file
+	interpreters []string
+	// compiled snippets are built with the interpreter, then run
+	compiled bool
+}
+
+var snippetLanguages = map[string]snippetLanguage{
+	"python": {file: "main.py", interpreters: []string{"python3", "python"}},
+	"node":   {file: "main.js", interpreters: []string{"node"}},
+	"go":     {file: "main.go", interpreters: []string{"go"}, compiled: true},
+}
+
+// snippetEnvVars are the environment variables a snippet and the Go
+// toolchain building one get; the rest, API keys and tokens included, are
+// withheld. Names are compared in upper case for Windows' sake.
+var snippetEnvVars = map[string]bool{
+	"PATH": true, "HOME": true, "USER": true, "LOGNAME": true,
+	"LANG": true, "LC_ALL": true, "LC_CTYPE": true, "TZ": true,
+	"TMPDIR": true, "TEMP": true, "TMP": true, "XDG_CACHE_HOME": true,
+	"GOROOT": true, "GOPATH": true, "GOCACHE": true, "GOMODCACHE": true,
+	"SYSTEMROOT": true, "WINDIR": true, "COMSPEC": true, "PATHEXT": true,
+	"USERPROFILE": true, "APPDATA": true, "LOCALAPPDATA": true,
+}
+
+// snippetEnv returns the allowed part of the environment.
+func snippetEnv() []string {
+	var env []string
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		if snippetEnvVars[strings.ToUpper(name)] {
+			env = append(env, entry)
+		}
+	}
+	return env
+}
+
+// RunSnippetTool runs a short program in a scratch directory, for
+// computing or checking something, after the user confirms it.
+type RunSnippetTool struct {
+	publisher events.Publisher
+	confirmer Confirmer
+}
+
+// NewRunSnippetTool creates the runSnippet tool.
+func NewRunSnippetTool(eventBus events.EventBus) Tool {
+	tool := &RunSnippetTool{publisher: eventBus}
+	if eventBus != nil {
+		tool.confirmer = NewBusConfirmer(eventBus)
+	}
+	return tool
+}
+
+// Declaration returns the function declaration for runSnippet.
+func (t *RunSnippetTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name: "runSnippet",
+		Description: "Run a short Python, Node.js or Go program and return what it prints. Use it to compute " +
+			"something, try out a library function or check an assumption instead of reasoning it through. " +
+			"The user confirms every snippet. It runs with the user's permissions, not in a sandbox, from an " +
+			"empty temporary directory that is deleted afterwards, with time and memory limits and without the " +
+			"user's credentials in its environment. Go snippets are a complete `package main` using the " +
+			"standard library only.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "Parameters for runSnippet",
+			Properties: map[string]*ai.Schema{
+				"language": {
+					Type:        ai.TypeString,
+					Description: "Language of the snippet.",
+					Enum:        []string{"python", "node", "go"},
+				},
+				"code": {
+					Type:        ai.TypeString,
+					Description: "The program's source code.",
+					MaxLength:   100000,
+				},
+				"stdin": {
+					Type:        ai.TypeString,
+					Description: "Optional standard input for the program.",
+				},
+				"timeout_seconds": {
+					Type: ai.TypeInteger,
+					Description: fmt.Sprintf("Seconds the program may run (default %d, max %d).",
+						int(defaultSnippetTimeout.Seconds()), int(maxSnippetTimeout.Seconds())),
+					Minimum: 1,
+					Maximum: maxSnippetTimeout.Seconds(),
+				},
+				"memory_mb": {
+					Type:        ai.TypeInteger,
+					Description: fmt.Sprintf("Memory the program may use in MB (default %d, max %d).", defaultSnippetMemoryMB, maxSnippetMemoryMB),
+					Minimum:     16,
+					Maximum:     maxSnippetMemoryMB,
+				},
+				"_display_message": {
+					Type:        ai.TypeString,
+					Description: "Short user-facing status (e.g. 'computing the checksum').",
+					MinLength:   5,
+					MaxLength:   200,
+				},
+			},
+			Required: []string{"language", "code", "_display_message"},
+		},
+		Response: &ai.Schema{
+			Type: ai.TypeObject,
+			Properties: map[string]*ai.Schema{
+				"success":     {Type: ai.TypeBoolean, Description: "Whether the program exited with status 0"},
+				"stdout":      {Type: ai.TypeString},
+				"stderr":      {Type: ai.TypeString, Description: "Standard error, or the compiler's errors"},
+				"exit_code":   {Type: ai.TypeInteger},
+				"timed_out":   {Type: ai.TypeBoolean},
+				"duration_ms": {Type: ai.TypeInteger},
+				"error":       {Type: ai.TypeString},
+			},
+			Required: []string{"success"},
+		},
+	}
+}
+
+// Handler returns the function handler for runSnippet.
+func (t *RunSnippetTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		if t.publisher != nil {
+			msg, ok := params["_display_message"].(string)
+			if !ok || msg == "" {
+				return nil, fmt.Errorf("_display_message parameter is required")
+			}
+			t.publisher.Publish("tool.call.message", events.ToolCallMessageEvent{ToolName: "runSnippet", Message: msg})
+		}
+
+		name, _ := params["language"].(string)
+		language, ok := snippetLanguages[strings.ToLower(name)]
+		if !ok {
+			return failResult(fmt.Sprintf("language must be python, node or go, got %q", name)), nil
+		}
+		code, _ := params["code"].(string)
+		if strings.TrimSpace(code) == "" {
+			return failResult("code is required"), nil
+		}
+		interpreter, err := findInterpreter(language.interpreters)
+		if err != nil {
+			return failResult(err.Error()), nil
+		}
+		timeout := defaultSnippetTimeout
+		if seconds, ok := numberValue(params, "timeout_seconds"); ok && seconds > 0 {
+			timeout = min(time.Duration(seconds)*time.Second, maxSnippetTimeout)
+		}
+		memoryMB := int64(defaultSnippetMemoryMB)
+		if mb, ok := numberValue(params, "memory_mb"); ok && mb > 0 {
+			memoryMB = min(mb, maxSnippetMemoryMB)
+		}
+
+		// The program can do anything the user can, so it runs only once
+		// approved, as it may have been edited
+		if t.confirmer == nil {
+			return failResult("confirmation required but no confirmer is configured"), nil
+		}
+		decision, err := decideExecution(ctx, t.confirmer, events.ToolConfirmationRequest{
+			ExecutionID: uuid.New().String(),
+			ToolName:    "runSnippet",
+			Command:     code,
+			Message:     fmt.Sprintf("Run this %s snippet? [y/N]", strings.ToLower(name)),
+		})
+		if err != nil {
+			return failResult(fmt.Sprintf("confirmation failed: %v", err)), nil
+		}
+		if !decision.Confirmed {
+			return failResult(decision.Declined("snippet cancelled by user")), nil
+		}
+		if decision.Edited != "" {
+			code = decision.Edited
+		}
+
+		dir, err := os.MkdirTemp("", "genie-snippet-")
+		if err != nil {
+			return failResult(fmt.Sprintf("failed to create the scratch directory: %v", err)), nil
+		}
+		defer os.RemoveAll(dir)
+		if err := os.WriteFile(filepath.Join(dir, language.file), []byte(code), 0600); err != nil {
+			return failResult(fmt.Sprintf("failed to write the snippet: %v", err)), nil
+		}
+
+		program := []string{interpreter, language.file}
+		if language.compiled {
+			binary, result := buildGoSnippet(ctx, dir, interpreter)
+			if result != nil {
+				return result, nil
+			}
+			program = []string{binary}
+		}
+		stdin, _ := params["stdin"].(string)
+		return runSnippet(ctx, dir, program, stdin, timeout, memoryMB), nil
+	}
+}
+
+// findInterpreter returns the path of the first of names on PATH.
+func findInterpreter(names []string) (string, error) {
+	for _, name := range names {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not installed", names[0])
+}
+
+// buildGoSnippet compiles main.go in dir. It returns the binary, or the
+// result to report when the snippet does not compile.
+func buildGoSnippet(ctx context.Context, dir, goTool string) (string, map[string]any) {
+	ctx, cancel := context.WithTimeout(ctx, snippetCompileTimeout)
+	defer cancel()
+
+	// The extension Windows needs is harmless elsewhere
+	binary := filepath.Join(dir, "snippet.exe")
+	cmd := exec.CommandContext(ctx, goTool, "build", "-o", binary, "main.go")
+	cmd.Dir = dir
+	// A go.work or GOFLAGS of the user's would change what builds, and
+	// standard library snippets need no module proxy
+	cmd.Env = append(snippetEnv(), "GOWORK=off", "GOFLAGS=", "GOPROXY=off")
+	process.ConfigureGroupKill(cmd)
+	output, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return "", cancelledCommandResult(string(output))
+	}
+	if err != nil {
+		stderr := strings.TrimSpace(string(output))
+		if stderr == "" {
+			stderr = err.Error()
+		}
+		return "", map[string]any{
+			"success":   false,
+			"stderr":    stderr,
+			"exit_code": -1,
+			"error":     "the snippet does not compile",
+		}
+	}
+	return binary, nil
+}
+
+// runSnippet runs program in dir within the limits and reports what it
+// printed.
+func runSnippet(ctx context.Context, dir string, program []string, stdin string, timeout time.Duration, memoryMB int64) map[string]any {
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := snippetCommand(execCtx, memoryMB, program[0], program[1:]...)
+	cmd.Dir = dir
+	cmd.Env = snippetEnv()
+	cmd.Stdin = strings.NewReader(stdin)
+	stdout := process.NewHeadTailBuffer(snippetOutputBytes, snippetOutputBytes)
+	stderr := process.NewHeadTailBuffer(snippetOutputBytes, snippetOutputBytes)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	process.ConfigureGroupKill(cmd)
+	cmd.WaitDelay = 3 * time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return cancelledCommandResult(stdout.Snapshot())
+	}
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+	result := map[string]any{
+		"success":     err == nil,
+		"stdout":      stdout.Snapshot(),
+		"stderr":      stderr.Snapshot(),
+		"exit_code":   exitCode,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	switch {
+	case err == nil:
+	case execCtx.Err() == context.DeadlineExceeded:
+		result["timed_out"] = true
+		result["error"] = fmt.Sprintf("the snippet was stopped after %v", timeout)
+	case exitCode == -1:
+		result["error"] = err.Error()
+	default:
+		result["error"] = fmt.Sprintf("the snippet exited with status %d", exitCode)
+	}
+	return result
+}
+
+// FormatOutput formats the program's output for the host UI.
+func (t *RunSnippetTool) FormatOutput(result map[string]interface{}) string {
+	var b strings.Builder
+	if success, _ := result["success"].(bool); success {
+		b.WriteString("**Snippet ran**")
+	} else {
+		msg, _ := result["error"].(string)
+		fmt.Fprintf(&b, "**Snippet failed**: %s", msg)
+	}
+	for _, stream := range []string{"stdout", "stderr"} {
+		if text, _ := result[stream].(string); strings.TrimSpace(text) != "" {
+			fmt.Fprintf(&b, "\n%s:\n```\n%s\n```", stream, strings.TrimRight(text, "\n"))
+		}
+	}
+	return b.String()
+}
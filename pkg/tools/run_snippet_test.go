@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runSnippetFor(t *testing.T, params map[string]any) map[string]any {
+	t.Helper()
+	language, _ := params["language"].(string)
+	if _, err := findInterpreter(snippetLanguages[language].interpreters); err != nil {
+		t.Skipf("%s is not installed", language)
+	}
+	params["_display_message"] = "running a snippet"
+	bus := events.NewEventBus()
+	answerExecutionRequests(bus, true)
+	result, err := NewRunSnippetTool(bus).Handler()(context.Background(), params)
+	require.NoError(t, err)
+	return result
+}
+
+func TestRunSnippetLanguages(t *testing.T) {
+	tests := []struct {
+		language string
+		code     string
+	}{
+		{"python", "import sys\nprint(sum(int(x) for x in sys.stdin.read().split()))"},
+		{"node", "let s='';process.stdin.on('data',d=>s+=d).on('end',()=>console.log(s.split(/\\s+/).filter(Boolean).map(Number).reduce((a,b)=>a+b,0)))"},
+		{"go", "package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"os\"\n\t\"strings\"\n\t\"strconv\"\n)\n\nfunc main() {\n\tdata, _ := io.ReadAll(os.Stdin)\n\tsum := 0\n\tfor _, f := range strings.Fields(string(data)) {\n\t\tn, _ := strconv.Atoi(f)\n\t\tsum += n\n\t}\n\tfmt.Println(sum)\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			result := runSnippetFor(t, map[string]any{"language": tt.language, "code": tt.code, "stdin": "1 2 39"})
+			assert.Equal(t, true, result["success"], result["stderr"])
+			assert.Equal(t, "42\n", result["stdout"])
+			assert.Equal(t, 0, result["exit_code"])
+		})
+	}
+}
+
+func TestRunSnippetReportsFailures(t *testing.T) {
+	result := runSnippetFor(t, map[string]any{"language": "python", "code": "import sys\nprint('partial')\nsys.exit('boom')"})
+	assert.Equal(t, false, result["success"])
+	assert.Equal(t, 1, result["exit_code"])
+	assert.Equal(t, "partial\n", result["stdout"])
+	assert.Contains(t, result["stderr"], "boom")
+
+	result = runSnippetFor(t, map[string]any{"language": "go", "code": "package main\n\nfunc main() { undefined() }\n"})
+	assert.Equal(t, false, result["success"])
+	assert.Equal(t, "the snippet does not compile", result["error"])
+	assert.Contains(t, result["stderr"], "undefined")
+}
+
+func TestRunSnippetEnforcesLimits(t *testing.T) {
+	result := runSnippetFor(t, map[string]any{"language": "python", "code": "while True: pass", "timeout_seconds": float64(1)})
+	assert.Equal(t, false, result["success"])
+	assert.Equal(t, true, result["timed_out"])
+
+	if runtime.GOOS != "linux" {
+		t.Skip("only Linux enforces the data size limit")
+	}
+	result = runSnippetFor(t, map[string]any{"language": "python", "code": "x = bytearray(200 * 1024 * 1024)\nprint('allocated')", "memory_mb": float64(64)})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["stderr"], "MemoryError")
+}
+
+func TestRunSnippetRunsInScratchDirectory(t *testing.T) {
+	result := runSnippetFor(t, map[string]any{"language": "python", "code": "import os\nprint(sorted(os.listdir('.')))"})
+	assert.Equal(t, "['main.py']\n", result["stdout"])
+}
+
+func TestRunSnippetWithholdsCredentials(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-secret")
+	result := runSnippetFor(t, map[string]any{"language": "python", "code": "import os\nprint(os.environ.get('OPENAI_API_KEY'), 'PATH' in os.environ)"})
+	assert.Equal(t, "None True\n", result["stdout"])
+}
+
+func TestRunSnippetNeedsConfirmation(t *testing.T) {
+	if _, err := findInterpreter(snippetLanguages["python"].interpreters); err != nil {
+		t.Skip("python is not installed")
+	}
+	marker := filepath.Join(t.TempDir(), "ran")
+	code := "open(" + strconv.Quote(marker) + ", 'w').close()"
+
+	bus := events.NewEventBus()
+	var prompts []events.ToolConfirmationRequest
+	events.SubscribeTo(bus, func(req events.ToolConfirmationRequest) {
+		prompts = append(prompts, req)
+	})
+	answerExecutionRequests(bus, false)
+	result, err := NewRunSnippetTool(bus).Handler()(context.Background(), map[string]any{"_display_message": "touching a file", "language": "python", "code": code})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "cancelled by user")
+	assert.NoFileExists(t, marker)
+	require.Len(t, prompts, 1)
+	assert.Equal(t, code, prompts[0].Command)
+
+	result, err = NewRunSnippetTool(nil).Handler()(context.Background(), map[string]any{"language": "python", "code": code})
+	require.NoError(t, err)
+	assert.Contains(t, result["error"], "no confirmer")
+	assert.NoFileExists(t, marker)
+}
+
+func TestRunSnippetRejectsUnknownLanguage(t *testing.T) {
+	result, err := NewRunSnippetTool(nil).Handler()(context.Background(), map[string]any{"language": "ruby", "code": "puts 1"})
+	require.NoError(t, err)
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "python, node or go")
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package tools
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+)
+
+// snippetCommand runs name with args under a data size limit of memoryMB,
+// which bounds the heap of Python, Node.js and Go programs alike. The
+// shell sets it between fork and exec, where Go cannot.
+func snippetCommand(ctx context.Context, memoryMB int64, name string, args ...string) *exec.Cmd {
+	kb := strconv.FormatInt(memoryMB*1024, 10)
+	return exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", `ulimit -d "$0" && exec "$@"`, kb, name}, args...)...)
+}
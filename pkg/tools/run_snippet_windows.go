@@ -0,0 +1,14 @@
+//go:build windows
+
+package tools
+
+import (
+	"context"
+	"os/exec"
+)
+
+// snippetCommand runs name with args. Windows has no per-process limit
+// to set from here, so only the timeout applies.
+func snippetCommand(ctx context.Context, memoryMB int64, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// Providers whose tool schema rules differ, by their GENIE_LLM_PROVIDER name
+const (
+	providerGemini    = "genai"
+	providerOpenAI    = "openai"
+	providerAnthropic = "anthropic"
+)
+
+var (
+	// OpenAI and Anthropic accept these tool names
+	portableToolName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+	// Gemini also accepts dots and colons, but not a leading digit or dash
+	geminiToolName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.:-]{0,63}$`)
+)
+
+// geminiStringFormats are the only string formats Gemini accepts
+var geminiStringFormats = []string{"", "enum", "date-time"}
+
+// ToolIssue is a problem with a tool's declaration
+type ToolIssue struct {
+	Tool string
+	// Path locates the problem in the declaration, e.g. "parameters.files"
+	Path    string
+	Message string
+	// Fatal issues make the providers below reject any request that
+	// includes the tool; the others only make it work worse
+	Fatal bool
+	// Providers the issue applies to, by GENIE_LLM_PROVIDER name. Empty
+	// means every provider.
+	Providers []string
+}
+
+// AppliesTo reports whether the issue affects a provider
+func (i ToolIssue) AppliesTo(provider string) bool {
+	if len(i.Providers) == 0 {
+		return true
+	}
+	return slices.Contains(i.Providers, strings.ToLower(provider))
+}
+
+func (i ToolIssue) String() string {
+	where := i.Tool
+	if i.Path != "" {
+		where += " " + i.Path
+	}
+	who := "every provider"
+	if len(i.Providers) > 0 {
+		names := make([]string, len(i.Providers))
+		for n, p := range i.Providers {
+			names[n] = providerDisplayName(p)
+		}
+		who = strings.Join(names, " and ")
+	}
+	if i.Fatal {
+		return fmt.Sprintf("%s: %s (rejected by %s)", where, i.Message, who)
+	}
+	return fmt.Sprintf("%s: %s (%s)", where, i.Message, who)
+}
+
+func providerDisplayName(provider string) string {
+	switch provider {
+	case providerGemini:
+		return "Gemini"
+	case providerOpenAI:
+		return "OpenAI"
+	case providerAnthropic:
+		return "Anthropic"
+	default:
+		return provider
+	}
+}
+
+// ValidateDeclaration checks a tool declaration against the JSON schema
+// rules every provider shares and the extra ones some of them enforce.
+func ValidateDeclaration(decl *ai.FunctionDeclaration) []ToolIssue {
+	if decl == nil {
+		return []ToolIssue{{Message: "declaration is missing", Fatal: true}}
+	}
+	v := &declarationValidator{tool: decl.Name}
+
+	switch {
+	case decl.Name == "":
+		v.fatal("", "name is empty")
+	case !portableToolName.MatchString(decl.Name):
+		if geminiToolName.MatchString(decl.Name) {
+			v.fatal("", "name may only use letters, digits, _ and -, up to 64 characters", providerOpenAI, providerAnthropic)
+		} else {
+			v.fatal("", "name may only use letters, digits, _ and -, up to 64 characters")
+		}
+	case !geminiToolName.MatchString(decl.Name):
+		v.fatal("", "name must start with a letter or _", providerGemini)
+	}
+
+	if strings.TrimSpace(decl.Description) == "" {
+		v.warn("", "has no description, so the model has to guess when to use it")
+	}
+
+	if decl.Parameters != nil {
+		if decl.Parameters.Type != ai.TypeObject {
+			v.fatal("parameters", "must be an object schema")
+		}
+		v.schema("parameters", decl.Parameters)
+	}
+	return v.issues
+}
+
+// ValidateTools checks every tool in the registry, including those only
+// reachable through a tool set such as an MCP server's, and returns the
+// issues sorted by tool name.
+func ValidateTools(registry Registry) []ToolIssue {
+	seen := make(map[string]bool)
+	var issues []ToolIssue
+	check := func(tool Tool) {
+		decl := tool.Declaration()
+		if decl != nil && seen[decl.Name] {
+			return
+		}
+		if decl != nil {
+			seen[decl.Name] = true
+		}
+		issues = append(issues, ValidateDeclaration(decl)...)
+	}
+
+	for _, tool := range registry.GetAll() {
+		check(tool)
+	}
+	for _, setName := range registry.GetToolSetNames() {
+		setTools, _ := registry.GetToolSet(setName)
+		for _, tool := range setTools {
+			check(tool)
+		}
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool { return issues[a].Tool < issues[b].Tool })
+	return issues
+}
+
+type declarationValidator struct {
+	tool   string
+	issues []ToolIssue
+}
+
+func (v *declarationValidator) fatal(path, message string, providers ...string) {
+	v.issues = append(v.issues, ToolIssue{Tool: v.tool, Path: path, Message: message, Fatal: true, Providers: providers})
+}
+
+func (v *declarationValidator) warn(path, message string, providers ...string) {
+	v.issues = append(v.issues, ToolIssue{Tool: v.tool, Path: path, Message: message, Providers: providers})
+}
+
+func (v *declarationValidator) schema(path string, s *ai.Schema) {
+	if s == nil {
+		v.fatal(path, "schema is missing")
+		return
+	}
+
+	switch s.Type {
+	case ai.TypeString, ai.TypeNumber, ai.TypeInteger, ai.TypeBoolean, ai.TypeArray, ai.TypeObject:
+	case 0:
+		v.warn(path, "has no type; it is sent as an object")
+	default:
+		v.fatal(path, fmt.Sprintf("has unknown type %d", s.Type))
+	}
+
+	if s.Type == ai.TypeArray && s.Items == nil {
+		v.fatal(path, "array has no items schema")
+	}
+	if s.Type != ai.TypeArray && s.Items != nil {
+		v.warn(path, "only arrays have items; they are ignored")
+	}
+	if s.Type != ai.TypeObject && s.Type != 0 && len(s.Properties) > 0 {
+		v.warn(path, "only objects have properties; they are ignored")
+	}
+	if len(s.Enum) > 0 && s.Type != ai.TypeString {
+		v.fatal(path, "enum is only allowed on strings", providerGemini)
+	}
+	if s.Type == ai.TypeString && !slices.Contains(geminiStringFormats, s.Format) {
+		v.fatal(path, fmt.Sprintf("string format %q is not supported; use enum or date-time, or describe it instead", s.Format), providerGemini)
+	}
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			v.fatal(path, fmt.Sprintf("pattern is not a valid regular expression: %v", err))
+		}
+	}
+	if s.Minimum != 0 && s.Maximum != 0 && s.Minimum > s.Maximum {
+		v.warn(path, fmt.Sprintf("minimum %g is above maximum %g", s.Minimum, s.Maximum))
+	}
+	if s.MaxLength > 0 && s.MinLength > s.MaxLength {
+		v.warn(path, fmt.Sprintf("minLength %d is above maxLength %d", s.MinLength, s.MaxLength))
+	}
+	if s.MaxItems > 0 && s.MinItems > s.MaxItems {
+		v.warn(path, fmt.Sprintf("minItems %d is above maxItems %d", s.MinItems, s.MaxItems))
+	}
+
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok {
+			v.fatal(path, fmt.Sprintf("requires %q, which is not one of its properties", name), providerGemini)
+		}
+	}
+
+	if s.Items != nil {
+		v.schema(path+"[]", s.Items)
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v.schema(path+"."+name, s.Properties[name])
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDeclaration(t *testing.T) {
+	decl := &ai.FunctionDeclaration{
+		Name:        "search",
+		Description: "Search the index",
+		Parameters: &ai.Schema{
+			Type:     ai.TypeObject,
+			Required: []string{"query", "limit"},
+			Properties: map[string]*ai.Schema{
+				"query": {Type: ai.TypeString, Format: "uri"},
+				"tags":  {Type: ai.TypeArray},
+				"level": {Type: ai.TypeInteger, Enum: []string{"1", "2"}},
+			},
+		},
+	}
+
+	var lines []string
+	for _, issue := range ValidateDeclaration(decl) {
+		lines = append(lines, issue.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`search parameters: requires "limit", which is not one of its properties (rejected by Gemini)`,
+		`search parameters.level: enum is only allowed on strings (rejected by Gemini)`,
+		`search parameters.query: string format "uri" is not supported; use enum or date-time, or describe it instead (rejected by Gemini)`,
+		`search parameters.tags: array has no items schema (rejected by every provider)`,
+	}, lines)
+}
+
+func TestValidateDeclarationNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []string // nil: every provider
+		fatal     bool
+	}{
+		{name: "read_file-2"},
+		{name: "fs.read", providers: []string{"openai", "anthropic"}, fatal: true},
+		{name: "2fa_code", providers: []string{"genai"}, fatal: true},
+		{name: "read file", fatal: true},
+		{name: strings.Repeat("a", 65), fatal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateDeclaration(&ai.FunctionDeclaration{Name: tt.name, Description: "d"})
+			if !tt.fatal {
+				assert.Empty(t, issues)
+				return
+			}
+			require.Len(t, issues, 1)
+			assert.True(t, issues[0].Fatal)
+			assert.Equal(t, tt.providers, issues[0].Providers)
+		})
+	}
+}
+
+func TestToolIssueAppliesTo(t *testing.T) {
+	gemini := ToolIssue{Fatal: true, Providers: []string{"genai"}}
+	assert.True(t, gemini.AppliesTo("GenAI"))
+	assert.False(t, gemini.AppliesTo("openai"))
+	assert.True(t, ToolIssue{Fatal: true}.AppliesTo("ollama"))
+}
+
+func TestValidateToolsBuiltInsAreValid(t *testing.T) {
+	registry := NewDefaultRegistry(&events.NoOpEventBus{}, NewTodoManager(), nil, nil)
+	assert.Empty(t, ValidateTools(registry), "built-in tool declarations must work with every provider")
+}
+
+func TestValidateToolsIncludesToolSets(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterToolSet("server", []Tool{&MockTool{name: "bad name"}}))
+
+	issues := ValidateTools(registry)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "bad name", issues[0].Tool)
+}
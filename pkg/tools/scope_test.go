@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scopedMonorepo lays out a workspace with two teams' services and scopes
+// the context to payments
+func scopedMonorepo(t *testing.T) (string, context.Context) {
+	workspace := t.TempDir()
+	for path, content := range map[string]string{
+		"services/payments/charge.go": "package payments // TODO refund",
+		"services/search/index.go":    "package search // TODO refund",
+		"libs/shared/money/money.go":  "package money // TODO refund",
+		"services/payments/README.md": "payments",
+		"services/search/README.md":   "search",
+		"libs/shared/money/README.md": "money",
+		"README.md":                   "monorepo",
+	} {
+		full := filepath.Join(workspace, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+	ctx := toolctx.WithWorkingDir(context.Background(), workspace)
+	return workspace, toolctx.WithScope(ctx, []string{"services/payments"})
+}
+
+func TestCheckPathPolicy_Scope(t *testing.T) {
+	workspace, ctx := scopedMonorepo(t)
+
+	inside := filepath.Join(workspace, "services", "payments", "charge.go")
+	assert.NoError(t, CheckPathPolicy(ctx, inside, IntentRead))
+	assert.NoError(t, CheckPathPolicy(ctx, inside, IntentMutate))
+
+	outside := filepath.Join(workspace, "services", "search", "index.go")
+	err := CheckPathPolicy(ctx, outside, IntentRead)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "outside the session's scope (services/payments)")
+	assert.Error(t, CheckPathPolicy(ctx, outside, IntentMutate))
+
+	// The way into the scope can be read, not changed
+	assert.NoError(t, CheckPathPolicy(ctx, filepath.Join(workspace, "services"), IntentRead))
+	assert.NoError(t, CheckPathPolicy(ctx, workspace, IntentRead))
+	assert.Error(t, CheckPathPolicy(ctx, filepath.Join(workspace, "README.md"), IntentRead))
+	assert.Error(t, CheckPathPolicy(ctx, filepath.Join(workspace, "services", "new.go"), IntentMutate))
+}
+
+func TestScope_SearchToolsStayInside(t *testing.T) {
+	_, ctx := scopedMonorepo(t)
+
+	grep, err := NewGrepTool(&events.NoOpPublisher{}).Handler()(ctx, map[string]any{
+		"pattern":          "TODO",
+		"_display_message": "searching",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, grep["results"], "services/payments/charge.go")
+	assert.NotContains(t, grep["results"], "search/index.go")
+	assert.NotContains(t, grep["results"], "money.go")
+
+	find, err := NewFindTool(&events.NoOpPublisher{}).Handler()(ctx, map[string]any{
+		"pattern":          "*.md",
+		"_display_message": "finding",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "services/payments/README.md", find["results"])
+
+	for _, depth := range []float64{1, 5} {
+		ls, err := NewLsTool(&events.NoOpPublisher{}).Handler()(ctx, map[string]any{
+			"path":             ".",
+			"max_depth":        depth,
+			"_display_message": "listing",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, ls["results"], "./services")
+		assert.NotContains(t, ls["results"], "libs")
+		assert.NotContains(t, ls["results"], "search")
+	}
+
+	_, err = NewGrepTool(&events.NoOpPublisher{}).Handler()(ctx, map[string]any{
+		"pattern":          "TODO",
+		"path":             "libs",
+		"_display_message": "searching",
+	})
+	assert.ErrorContains(t, err, "outside the session's scope")
+}
+
+func TestScope_GitToolsWorkInScopedRepo(t *testing.T) {
+	f := newGitFixture(t)
+	f.write(t, "services/payments/charge.go", "v1")
+	f.write(t, "services/search/index.go", "v1")
+	f.commit(t, "init", "tester", "t@x")
+	f.write(t, "services/payments/charge.go", "v2")
+	f.write(t, "services/search/index.go", "v2")
+
+	ctx := toolctx.WithScope(contextForGit(f.dir, "alice", "alice@x"), []string{"services/payments"})
+	commit := NewGitCommitTool(&events.NoOpPublisher{}).Handler()
+
+	r, err := commit(ctx, map[string]any{
+		"message":          "outside",
+		"paths":            []any{"services/search/index.go"},
+		"_display_message": "committing",
+	})
+	require.NoError(t, err)
+	assert.False(t, r["success"].(bool))
+	assert.Contains(t, r["error"], "outside the session's scope")
+
+	// Without paths, only the changes inside the scope are committed
+	r, err = commit(ctx, map[string]any{
+		"message":          "charge v2",
+		"_display_message": "committing",
+	})
+	require.NoError(t, err)
+	require.True(t, r["success"].(bool), r["error"])
+	assert.Contains(t, r["results"], "left out 1 changed files")
+
+	head, err := f.repo.Head()
+	require.NoError(t, err)
+	headCommit, err := f.repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	for path, want := range map[string]string{"services/payments/charge.go": "v2", "services/search/index.go": "v1"} {
+		file, err := headCommit.File(path)
+		require.NoError(t, err)
+		contents, err := file.Contents()
+		require.NoError(t, err)
+		assert.Equal(t, want, contents, path)
+	}
+
+	restore := NewGitRestoreTool(&events.NoOpPublisher{}).Handler()
+	r, err = restore(ctx, map[string]any{
+		"path":             "services/payments/charge.go",
+		"commit":           "HEAD~1",
+		"_display_message": "rolling back",
+	})
+	require.NoError(t, err)
+	assert.True(t, r["success"].(bool), r["error"])
+
+	r, err = restore(ctx, map[string]any{
+		"path":             "services/search/index.go",
+		"_display_message": "rolling back",
+	})
+	require.NoError(t, err)
+	assert.False(t, r["success"].(bool))
+	assert.Contains(t, r["error"], "outside the session's scope")
+}
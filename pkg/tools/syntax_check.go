@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcaldas/genie/pkg/ai"
+	"github.com/kcaldas/genie/pkg/syntax"
+)
+
+// allowSyntaxErrorsSchema lets the model write a file it means to leave
+// unparseable, such as a half-finished scaffold or a test fixture.
+func allowSyntaxErrorsSchema() *ai.Schema {
+	return &ai.Schema{
+		Type:        ai.TypeBoolean,
+		Description: "Write the file even if it would have syntax errors. Only for files meant to be incomplete or invalid; otherwise fix the reported errors.",
+	}
+}
+
+// syntaxErrorReport parses the file at path as the change would leave it
+// and, when the change introduces syntax errors, returns the message
+// refusing it so the model can repair the change before anything is
+// written or shown to the user. before is nil for a new file. It returns
+// "" when the language has no parser or allow_syntax_errors is set.
+func syntaxErrorReport(ctx context.Context, path string, before, after []byte, params map[string]any) string {
+	if allow, _ := params["allow_syntax_errors"].(bool); allow {
+		return ""
+	}
+	errs := syntax.Introduced(path, before, after)
+	if len(errs) == 0 {
+		return ""
+	}
+	display := ConvertToRelativePath(ctx, path)
+	var b strings.Builder
+	fmt.Fprintf(&b, "the change was not applied: it would leave %s with syntax errors:\n", display)
+	for _, err := range errs {
+		fmt.Fprintf(&b, "  %s:%s\n", display, err)
+	}
+	b.WriteString("Fix the change and try again, or set allow_syntax_errors if the file is meant to be incomplete.")
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcaldas/genie/pkg/events"
+	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validGo = "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"
+
+func TestEditToolRefusesEditThatBreaksSyntax(t *testing.T) {
+	workspace := t.TempDir()
+	path := filepath.Join(workspace, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte(validGo), 0o644))
+	handler := NewEditTool(&events.NoOpPublisher{}).Handler()
+	ctx := toolctx.WithWorkingDir(context.Background(), workspace)
+
+	params := map[string]any{
+		"path":             "main.go",
+		"old_string":       "println(\"hi\")",
+		"new_string":       "println(\"hi\"",
+		"_display_message": "editing",
+	}
+	r, err := handler(ctx, params)
+	require.NoError(t, err)
+	assert.False(t, r["success"].(bool))
+	assert.Contains(t, r["error"], "main.go:4:")
+	assert.Contains(t, r["error"], "syntax error")
+	got, _ := os.ReadFile(path)
+	assert.Equal(t, validGo, string(got), "a refused edit leaves the file alone")
+
+	params["allow_syntax_errors"] = true
+	r, err = handler(ctx, params)
+	require.NoError(t, err)
+	assert.True(t, r["success"].(bool))
+}
+
+func TestEditToolAllowsEditsToAlreadyBrokenFiles(t *testing.T) {
+	workspace := t.TempDir()
+	broken := "package main\n\nfunc main() {\n\tx := \n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "main.go"), []byte(broken), 0o644))
+	handler := NewEditTool(&events.NoOpPublisher{}).Handler()
+	ctx := toolctx.WithWorkingDir(context.Background(), workspace)
+
+	r, err := handler(ctx, map[string]any{
+		"path":             "main.go",
+		"old_string":       "func main",
+		"new_string":       "// main is broken\nfunc main",
+		"_display_message": "editing",
+	})
+	require.NoError(t, err)
+	assert.True(t, r["success"].(bool), "the edit did not add to the existing error")
+}
+
+func TestWriteToolRefusesBrokenContentBeforeConfirmation(t *testing.T) {
+	workspace := t.TempDir()
+	ctx := toolctx.WithWorkingDir(context.Background(), workspace)
+	bus := events.NewEventBus()
+	asked := false
+	bus.Subscribe("user.confirmation.request", func(any) { asked = true })
+	handler := NewWriteTool(bus, true).Handler()
+
+	r, err := handler(ctx, map[string]any{
+		"path":    "main.go",
+		"content": "package main\n\nfunc main() {\n",
+	})
+	require.NoError(t, err)
+	assert.False(t, r["success"].(bool))
+	assert.Contains(t, r["results"], "syntax error")
+	assert.False(t, asked, "the user is not asked about content the model has to redo")
+	assert.NoFileExists(t, filepath.Join(workspace, "main.go"))
+}
+
+func TestWriteToolSkipsLanguagesWithoutParser(t *testing.T) {
+	workspace := t.TempDir()
+	ctx := toolctx.WithWorkingDir(context.Background(), workspace)
+	handler := NewWriteTool(nil, false).Handler()
+
+	r, err := handler(ctx, map[string]any{"path": "notes.txt", "content": "unbalanced {"})
+	require.NoError(t, err)
+	assert.True(t, r["success"].(bool))
+}
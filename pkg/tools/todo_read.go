@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcaldas/genie/pkg/ai"
+)
+
+// TodoReadTool returns the session's current todo list
+type TodoReadTool struct {
+	manager TodoManager
+}
+
+// NewTodoReadTool creates a new TodoRead tool
+func NewTodoReadTool(manager TodoManager) Tool {
+	return &TodoReadTool{
+		manager: manager,
+	}
+}
+
+// Declaration returns the function declaration for the TodoRead tool
+func (t *TodoReadTool) Declaration() *ai.FunctionDeclaration {
+	return &ai.FunctionDeclaration{
+		Name:        "TodoRead",
+		Description: "Read the current todo list for this session. Use it before updating todos with TodoWrite, when resuming work, or when the user asks what is left.",
+		Parameters: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "No parameters required",
+			Properties:  map[string]*ai.Schema{},
+		},
+		Response: &ai.Schema{
+			Type:        ai.TypeObject,
+			Description: "The current todo list",
+			Properties: map[string]*ai.Schema{
+				"success": {
+					Type:        ai.TypeBoolean,
+					Description: "Whether the operation was successful",
+				},
+				"message": {
+					Type:        ai.TypeString,
+					Description: "Summary of the list",
+				},
+				"todos": {
+					Type:        ai.TypeArray,
+					Description: "The current todo items",
+					Items: &ai.Schema{
+						Type: ai.TypeObject,
+						Properties: map[string]*ai.Schema{
+							"id":       {Type: ai.TypeString},
+							"content":  {Type: ai.TypeString},
+							"status":   {Type: ai.TypeString},
+							"priority": {Type: ai.TypeString},
+						},
+						Required: []string{"id", "content", "status", "priority"},
+					},
+				},
+			},
+			Required: []string{"success", "message", "todos"},
+		},
+	}
+}
+
+// Handler returns the function handler for the TodoRead tool
+func (t *TodoReadTool) Handler() ai.HandlerFunc {
+	return func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		todos := t.manager.Read()
+
+		pending := 0
+		for _, todo := range todos {
+			if todo.Status != StatusCompleted {
+				pending++
+			}
+		}
+
+		return map[string]any{
+			"success": true,
+			"message": fmt.Sprintf("%d todo(s), %d not completed", len(todos), pending),
+			"todos":   todoItemsResponse(todos),
+		}, nil
+	}
+}
+
+// FormatOutput formats the tool's execution result for user display
+func (t *TodoReadTool) FormatOutput(result map[string]interface{}) string {
+	// Same shape as TodoWrite, so reuse its rendering
+	return (&TodoWriteTool{}).FormatOutput(result)
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoReadTool_ReturnsCurrentList(t *testing.T) {
+	manager := NewTodoManager()
+	require.NoError(t, manager.Write([]TodoItem{
+		{ID: "1", Content: "Write tests", Status: StatusCompleted, Priority: PriorityHigh},
+		{ID: "2", Content: "Ship it", Status: StatusInProgress, Priority: PriorityMedium},
+	}))
+
+	tool := NewTodoReadTool(manager)
+	assert.Equal(t, "TodoRead", tool.Declaration().Name)
+
+	result, err := tool.Handler()(context.Background(), map[string]any{})
+	require.NoError(t, err)
+
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, "2 todo(s), 1 not completed", result["message"])
+	todos, ok := result["todos"].([]map[string]interface{})
+	require.True(t, ok)
+	require.Len(t, todos, 2)
+	assert.Equal(t, "Ship it", todos[1]["content"])
+	assert.Equal(t, "in_progress", todos[1]["status"])
+}
+
+func TestTodoReadTool_EmptyList(t *testing.T) {
+	result, err := NewTodoReadTool(NewTodoManager()).Handler()(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "0 todo(s), 0 not completed", result["message"])
+}
@@ -267,21 +267,10 @@ func (t *TodoWriteTool) Handler() ai.HandlerFunc {
 		}
 
 		// Read the current state of todos from the manager to return the canonical list
-		currentTodos := t.manager.Read()
-		var responseTodos []map[string]interface{}
-		for _, item := range currentTodos {
-			responseTodos = append(responseTodos, map[string]interface{}{
-				"id":       item.ID,
-				"content":  item.Content,
-				"status":   string(item.Status),
-				"priority": string(item.Priority),
-			})
-		}
-
 		return map[string]any{
 			"success": true,
 			"message": fmt.Sprintf("Successfully updated %d todo(s)", len(todos)),
-			"todos":   responseTodos,
+			"todos":   todoItemsResponse(t.manager.Read()),
 		}, nil
 	}
 }
@@ -326,6 +315,20 @@ func (t *TodoWriteTool) FormatOutput(result map[string]interface{}) string {
 	return output
 }
 
+// todoItemsResponse converts todos to the map form returned by the todo tools
+func todoItemsResponse(items []TodoItem) []map[string]interface{} {
+	var responseTodos []map[string]interface{}
+	for _, item := range items {
+		responseTodos = append(responseTodos, map[string]interface{}{
+			"id":       item.ID,
+			"content":  item.Content,
+			"status":   string(item.Status),
+			"priority": string(item.Priority),
+		})
+	}
+	return responseTodos
+}
+
 func capitalizeFirstLetter(s string) string {
 	if len(s) == 0 {
 		return ""
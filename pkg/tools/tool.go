@@ -14,3 +14,17 @@ type Tool interface {
 	// The result parameter should match the tool's response schema
 	FormatOutput(result map[string]interface{}) string
 }
+
+// AvailabilityChecker is implemented by tools that depend on something
+// outside Genie, such as a binary on PATH. A tool whose check fails is
+// left out of the persona's prompt, and the error tells the user why.
+type AvailabilityChecker interface {
+	CheckAvailable() error
+}
+
+// ParameterRedactor is implemented by tools whose parameters can carry
+// credentials. The parameters shown and recorded for a call are the
+// redacted copy; the handler still gets the originals.
+type ParameterRedactor interface {
+	RedactParameters(params map[string]any) map[string]any
+}
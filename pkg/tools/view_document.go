@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/events"
@@ -16,13 +17,30 @@ import (
 
 const (
 	defaultMaxDocumentBytes = 20 * 1024 * 1024 // 20 MiB
+
+	// defaultMaxDocumentChars and maxDocumentChars bound the extracted text
+	// returned by one call; longer documents are read a range at a time
+	defaultMaxDocumentChars = 50_000
+	maxDocumentChars        = 200_000
 )
 
 var allowedDocumentMIMETypes = map[string]struct{}{
 	"application/pdf": {},
+	docxMIMEType:      {},
+	xlsxMIMEType:      {},
+}
+
+// documentExtensionMIMETypes covers the formats the system MIME table
+// often lacks; sniffing content only sees a zip archive
+var documentExtensionMIMETypes = map[string]string{
+	".pdf":  "application/pdf",
+	".docx": docxMIMEType,
+	".xlsx": xlsxMIMEType,
 }
 
-// ViewDocumentTool exposes documents (currently PDFs) to the LLM.
+// ViewDocumentTool exposes documents (PDF, Word and Excel) to the LLM. Their
+// text is extracted locally so every provider can read them; PDFs are also
+// attached whole for providers that read them natively.
 type ViewDocumentTool struct {
 	publisher events.Publisher
 	maxBytes  int64
@@ -57,7 +75,7 @@ func NewViewDocumentTool(publisher events.Publisher, opts ...ViewDocumentOption)
 func (v *ViewDocumentTool) Declaration() *ai.FunctionDeclaration {
 	return &ai.FunctionDeclaration{
 		Name:        "viewDocument",
-		Description: "Reads a document from the workspace (currently only PDF) and returns it for inspection.",
+		Description: "Reads a document from the workspace (PDF, Word .docx or Excel .xlsx) and returns its text for inspection. Long documents are returned a page range at a time; when truncated is true, call again with pages starting at next_page.",
 		Parameters: &ai.Schema{
 			Type:        ai.TypeObject,
 			Description: "Parameters required to view a document",
@@ -68,6 +86,17 @@ func (v *ViewDocumentTool) Declaration() *ai.FunctionDeclaration {
 					MinLength:   1,
 					MaxLength:   500,
 				},
+				"pages": {
+					Type:        ai.TypeString,
+					Description: "Pages to read, e.g. '1-3,7' or '5-'. For spreadsheets these are sheets; Word documents are a single page. Defaults to all.",
+					MaxLength:   100,
+				},
+				"max_chars": {
+					Type:        ai.TypeInteger,
+					Description: "Most characters of text to return. Defaults to 50000, at most 200000.",
+					Minimum:     1000,
+					Maximum:     maxDocumentChars,
+				},
 				"_display_message": {
 					Type:        ai.TypeString,
 					Description: "Short user-facing status shown in the host UI while this tool runs. Frame it in the user's terms (e.g., 'reading the document you sent', not 'viewing /tmp/doc.pdf'). Separate channel from your chat reply — don't repeat it there.",
@@ -93,9 +122,33 @@ func (v *ViewDocumentTool) Declaration() *ai.FunctionDeclaration {
 					Type:        ai.TypeInteger,
 					Description: "Size of the document in bytes.",
 				},
+				"text": {
+					Type:        ai.TypeString,
+					Description: "Text extracted from the requested pages, each headed by its page or sheet.",
+				},
+				"page_count": {
+					Type:        ai.TypeInteger,
+					Description: "Pages (or sheets) in the document.",
+				},
+				"pages": {
+					Type:        ai.TypeString,
+					Description: "Pages whose text was returned, e.g. '1-3'.",
+				},
+				"truncated": {
+					Type:        ai.TypeBoolean,
+					Description: "Whether text was left out to stay within max_chars.",
+				},
+				"next_page": {
+					Type:        ai.TypeInteger,
+					Description: "First page left out when truncated.",
+				},
+				"text_error": {
+					Type:        ai.TypeString,
+					Description: "Why no text could be extracted from a PDF that is still attached.",
+				},
 				"data_base64": {
 					Type:        ai.TypeString,
-					Description: "Document data encoded in base64. Present when success is true.",
+					Description: "PDF data encoded in base64, for providers that read PDFs natively.",
 				},
 				"data_url": {
 					Type:        ai.TypeString,
@@ -141,15 +194,73 @@ func (v *ViewDocumentTool) Handler() ai.HandlerFunc {
 
 		relativePath := ConvertToRelativePath(ctx, resolvedPath)
 
-		return map[string]any{
-			"success":     true,
-			"mime_type":   payload.mimeType,
-			"size_bytes":  payload.size,
-			"data_base64": payload.base64,
-			"data_url":    fmt.Sprintf("data:%s;base64,%s", payload.mimeType, payload.base64),
-			"path":        relativePath,
-		}, nil
+		result := map[string]any{
+			"success":    true,
+			"mime_type":  payload.mimeType,
+			"size_bytes": payload.size,
+			"path":       relativePath,
+		}
+		if payload.mimeType == "application/pdf" {
+			result["data_base64"] = payload.base64
+			result["data_url"] = fmt.Sprintf("data:%s;base64,%s", payload.mimeType, payload.base64)
+		}
+
+		pages, err := extractDocumentText(payload.mimeType, payload.data)
+		if err != nil {
+			if payload.mimeType != "application/pdf" {
+				return v.failure(err.Error())
+			}
+			// Providers that read PDFs natively can still use the attachment
+			result["text_error"] = err.Error()
+			return result, nil
+		}
+
+		selection, _ := params["pages"].(string)
+		selected, err := parsePageRanges(selection, len(pages))
+		if err != nil {
+			return v.failure(err.Error())
+		}
+		maxChars := min(max(intParam(params, "max_chars", defaultMaxDocumentChars), 1), maxDocumentChars)
+		text, returned, truncated := joinDocumentPages(pages, selected, maxChars)
+
+		result["text"] = text
+		result["page_count"] = len(pages)
+		result["pages"] = formatPageRanges(returned)
+		result["truncated"] = truncated
+		for _, number := range selected {
+			if truncated && number > returned[len(returned)-1] {
+				result["next_page"] = number
+				break
+			}
+		}
+		return result, nil
+	}
+}
+
+// joinDocumentPages joins the text of the selected pages, each under its
+// label, up to maxChars characters. It returns the pages included and
+// whether text was left out. A first page longer than maxChars is cut
+// short rather than left out, so every call makes progress.
+func joinDocumentPages(pages []documentPage, selected []int, maxChars int) (string, []int, bool) {
+	var text strings.Builder
+	var returned []int
+	chars := 0
+	for _, number := range selected {
+		page := pages[number-1]
+		section := fmt.Sprintf("--- %s ---\n%s\n\n", page.label, page.text)
+		length := utf8.RuneCountInString(section)
+		if chars+length > maxChars {
+			if len(returned) == 0 {
+				text.WriteString(string([]rune(section)[:maxChars]))
+				returned = append(returned, number)
+			}
+			return strings.TrimSpace(text.String()), returned, true
+		}
+		text.WriteString(section)
+		chars += length
+		returned = append(returned, number)
 	}
+	return strings.TrimSpace(text.String()), returned, false
 }
 
 func (v *ViewDocumentTool) FormatOutput(result map[string]interface{}) string {
@@ -169,11 +280,15 @@ func (v *ViewDocumentTool) FormatOutput(result map[string]interface{}) string {
 		path = "document"
 	}
 
+	formatted := fmt.Sprintf("Attached document `%s`", path)
 	if size > 0 {
-		return fmt.Sprintf("Attached document `%s` (%d bytes)", path, size)
+		formatted += fmt.Sprintf(" (%d bytes)", size)
 	}
-
-	return fmt.Sprintf("Attached document `%s`", path)
+	if pages, _ := result["pages"].(string); pages != "" {
+		pageCount, _ := result["page_count"].(int)
+		formatted += fmt.Sprintf(", text of pages %s of %d", pages, pageCount)
+	}
+	return formatted
 }
 
 func (v *ViewDocumentTool) loadDocument(path string) (*documentPayload, error) {
@@ -196,6 +311,7 @@ func (v *ViewDocumentTool) loadDocument(path string) (*documentPayload, error) {
 	}
 
 	return &documentPayload{
+		data:     data,
 		base64:   base64.StdEncoding.EncodeToString(data),
 		mimeType: mimeType,
 		size:     size,
@@ -204,6 +320,9 @@ func (v *ViewDocumentTool) loadDocument(path string) (*documentPayload, error) {
 
 func detectDocumentMIME(path string, data []byte) string {
 	if ext := strings.ToLower(filepath.Ext(path)); ext != "" {
+		if typ, ok := documentExtensionMIMETypes[ext]; ok {
+			return typ
+		}
 		if typ := mime.TypeByExtension(ext); typ != "" {
 			return typ
 		}
@@ -236,6 +355,7 @@ func (v *ViewDocumentTool) failure(message string) (map[string]any, error) {
 }
 
 type documentPayload struct {
+	data     []byte
 	base64   string
 	mimeType string
 	size     int64
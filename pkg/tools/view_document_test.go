@@ -1,10 +1,14 @@
 package tools_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/base64"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/kcaldas/genie/pkg/events"
@@ -137,3 +141,129 @@ func TestViewDocumentTool_UnsupportedType(t *testing.T) {
 	assert.False(t, result["success"].(bool))
 	assert.Contains(t, result["error"], "unsupported")
 }
+
+// buildPDF writes a PDF with one page of Helvetica text per entry, with
+// the byte offsets its cross-reference table needs
+func buildPDF(pageTexts ...string) []byte {
+	var objects []string
+	kids := make([]string, len(pageTexts))
+	for i := range pageTexts {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<</Type/Catalog/Pages 2 0 R>>",
+		fmt.Sprintf("<</Type/Pages/Kids[%s]/Count %d>>", strings.Join(kids, " "), len(pageTexts)),
+		"<</Type/Font/Subtype/Type1/BaseFont/Helvetica>>",
+	)
+	for i, text := range pageTexts {
+		stream := fmt.Sprintf("BT /F1 12 Tf 10 40 Td (%s) Tj ET", text)
+		objects = append(objects,
+			fmt.Sprintf("<</Type/Page/Parent 2 0 R/MediaBox[0 0 200 50]/Resources<</Font<</F1 3 0 R>>>>/Contents %d 0 R>>", 5+2*i),
+			fmt.Sprintf("<</Length %d>>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<</Size %d/Root 1 0 R>>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// buildZip writes an archive of the named files, as Word and Excel files are
+func buildZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func viewDocument(t *testing.T, name string, data []byte, params map[string]any) map[string]any {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), data, 0o600))
+	params["file_path"] = name
+	params["_display_message"] = "Reading the document"
+	result, err := tools.NewViewDocumentTool(&events.NoOpPublisher{}).Handler()(toolctx.WithWorkingDir(context.Background(), tmpDir), params)
+	require.NoError(t, err)
+	return result
+}
+
+func TestViewDocumentTool_ExtractsPDFText(t *testing.T) {
+	result := viewDocument(t, "report.pdf", buildPDF("First page", "Second page", "Third page"), map[string]any{"pages": "2-"})
+	require.True(t, result["success"].(bool), result["error"])
+	assert.Equal(t, "--- Page 2 ---\nSecond page\n\n--- Page 3 ---\nThird page", result["text"])
+	assert.Equal(t, 3, result["page_count"])
+	assert.Equal(t, "2-3", result["pages"])
+	assert.Equal(t, false, result["truncated"])
+	assert.NotEmpty(t, result["data_base64"], "PDFs stay attached for providers that read them")
+
+	// A PDF whose text cannot be read is still attached
+	data, err := base64.StdEncoding.DecodeString(samplePDFBase64)
+	require.NoError(t, err)
+	result = viewDocument(t, "doc.pdf", data, map[string]any{})
+	assert.True(t, result["success"].(bool))
+	assert.Contains(t, result["text_error"], "PDF")
+
+	result = viewDocument(t, "report.pdf", buildPDF("Only page"), map[string]any{"pages": "4"})
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "outside the document's 1 pages")
+}
+
+func TestViewDocumentTool_ExtractsWordText(t *testing.T) {
+	docx := buildZip(t, map[string]string{
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			`<w:p><w:pPr><w:tabs><w:tab w:val="left" w:pos="720"/></w:tabs></w:pPr><w:r><w:t>Quarterly</w:t></w:r><w:r><w:t xml:space="preserve"> report</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t>Revenue</w:t><w:tab/><w:t>12</w:t></w:r></w:p>` +
+			`</w:body></w:document>`,
+	})
+
+	result := viewDocument(t, "report.docx", docx, map[string]any{})
+	require.True(t, result["success"].(bool), result["error"])
+	assert.Equal(t, "--- Document ---\nQuarterly report\nRevenue\t12", result["text"])
+	assert.NotContains(t, result, "data_base64", "only PDFs are attached whole")
+}
+
+func TestViewDocumentTool_ReadsSpreadsheetSheetsWithinLimit(t *testing.T) {
+	longRow := strings.Repeat("x", 600)
+	xlsx := buildZip(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			`<sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Detail" sheetId="2" r:id="rId2"/><sheet name="Notes" sheetId="3" r:id="rId3"/>` +
+			`</sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="worksheets/sheet2.xml"/><Relationship Id="rId3" Target="/xl/worksheets/sheet3.xml"/>` +
+			`</Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>Region</t></si><si><r><t>To</t></r><r><t>tal</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+			`<row r="2"><c r="A2" t="inlineStr"><is><t>North</t></is></c><c r="B2" t="b"><v>1</v></c><c r="C2"><v>42.5</v></c></row>` +
+			`</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>` + longRow + `</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet3.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>` + longRow + `</t></is></c></row></sheetData></worksheet>`,
+	})
+
+	result := viewDocument(t, "sales.xlsx", xlsx, map[string]any{"max_chars": float64(1000)})
+	require.True(t, result["success"].(bool), result["error"])
+	assert.Equal(t, 3, result["page_count"])
+	assert.Equal(t, "1-2", result["pages"])
+	assert.Equal(t, true, result["truncated"])
+	assert.Equal(t, 3, result["next_page"])
+	assert.True(t, strings.HasPrefix(result["text"].(string), "--- Sheet 1: Summary ---\nRegion\t\tTotal\nNorth\ttrue\t42.5\n\n--- Sheet 2: Detail ---\n"))
+
+	result = viewDocument(t, "sales.xlsx", xlsx, map[string]any{"pages": "3"})
+	assert.Equal(t, "--- Sheet 3: Notes ---\n"+longRow, result["text"])
+}
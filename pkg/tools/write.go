@@ -10,6 +10,7 @@ import (
 	"github.com/kcaldas/genie/pkg/ai"
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/fileops"
+	"github.com/kcaldas/genie/pkg/hooks"
 )
 
 // WriteTool implements file writing with diff preview and confirmation
@@ -42,7 +43,7 @@ func NewWriteTool(eventBus events.EventBus, confirmationEnabled bool) Tool {
 func (w *WriteTool) Declaration() *ai.FunctionDeclaration {
 	return &ai.FunctionDeclaration{
 		Name:        "writeFile",
-		Description: "Write content to a file with diff preview and user confirmation. Always reads existing file content first to show changes, creates directories as needed, and requires confirmation before applying changes.",
+		Description: "Write content to a file with diff preview and user confirmation. Always reads existing file content first to show changes, creates directories as needed, and requires confirmation before applying changes. Content that would introduce syntax errors into a Go, Python, Java or JSON file is refused with the errors before the user sees it.",
 		Parameters: &ai.Schema{
 			Type: ai.TypeObject,
 			Properties: map[string]*ai.Schema{
@@ -62,6 +63,7 @@ func (w *WriteTool) Declaration() *ai.FunctionDeclaration {
 					Type:        ai.TypeString,
 					Description: "Whether to create a backup of existing file ('true' or 'false', optional)",
 				},
+				"allow_syntax_errors": allowSyntaxErrorsSchema(),
 			},
 			Required: []string{"path", "content"},
 		},
@@ -132,6 +134,19 @@ func (w *WriteTool) Handler() ai.HandlerFunc {
 		}
 		filePath = resolvedPath
 
+		// Send syntax errors back to the model before asking the user
+		// about a change it will have to redo
+		var before []byte
+		if w.fileManager.FileExists(filePath) {
+			before, _ = w.fileManager.ReadFile(filePath)
+		}
+		if report := syntaxErrorReport(ctx, filePath, before, []byte(content), args); report != "" {
+			return map[string]any{
+				"success": false,
+				"results": report,
+			}, nil
+		}
+
 		// Generate diff to show what will change
 		diffContent, err := w.diffGenerator.GenerateUnifiedDiff(filePath, content)
 		if err != nil {
@@ -149,8 +164,9 @@ func (w *WriteTool) Handler() ai.HandlerFunc {
 		}
 
 		// If confirmation is enabled, request user approval
+		edited := false
 		if w.confirmationEnabled {
-			confirmed, err := w.requestDiffConfirmation(ctx, filePath, diffContent)
+			decision, err := w.requestDiffConfirmation(ctx, filePath, diffContent, content)
 			if err != nil {
 				return map[string]any{
 					"success": false,
@@ -158,13 +174,23 @@ func (w *WriteTool) Handler() ai.HandlerFunc {
 				}, nil
 			}
 
-			if !confirmed {
+			if !decision.Confirmed {
 				return map[string]any{
 					"success": false,
-					"results": "File write operation cancelled by user",
+					"results": decision.Declined("File write operation cancelled by user"),
 					"diff":    diffContent,
 				}, nil
 			}
+
+			// The user may have changed the content before approving it;
+			// write theirs and report the diff that was actually applied
+			if decision.Edited != "" && decision.Edited != content {
+				content = decision.Edited
+				edited = true
+				if diff, err := w.diffGenerator.GenerateUnifiedDiff(filePath, content); err == nil {
+					diffContent = diff
+				}
+			}
 		}
 
 		// Create backup if requested and file exists
@@ -179,6 +205,22 @@ func (w *WriteTool) Handler() ai.HandlerFunc {
 			}
 		}
 
+		// The pre_edit hooks see the content as it will be written, after
+		// the user's edits, and may refuse or rewrite it
+		hooked, err := hooks.FromContext(ctx).RunPreEdit(ctx, "writeFile", filePath, []byte(content))
+		if err != nil {
+			return map[string]any{
+				"success": false,
+				"results": "The change was not applied: " + err.Error(),
+			}, nil
+		}
+		if string(hooked) != content {
+			content = string(hooked)
+			if diff, err := w.diffGenerator.GenerateUnifiedDiff(filePath, content); err == nil {
+				diffContent = diff
+			}
+		}
+
 		// Write the file
 		err = w.fileManager.WriteFile(filePath, []byte(content))
 		if err != nil {
@@ -198,16 +240,20 @@ func (w *WriteTool) Handler() ai.HandlerFunc {
 		if backupPath != "" {
 			result["backup_path"] = backupPath
 		}
+		if edited {
+			result["results"] = fmt.Sprintf("Successfully wrote file: %s (content edited by the user before approval; see diff)", filePath)
+		}
 
 		return result, nil
 	}
 }
 
-// requestDiffConfirmation requests user confirmation with diff preview
-func (w *WriteTool) requestDiffConfirmation(ctx context.Context, filePath, diffContent string) (bool, error) {
+// requestDiffConfirmation requests user confirmation with diff preview,
+// offering the new content for editing
+func (w *WriteTool) requestDiffConfirmation(ctx context.Context, filePath, diffContent, content string) (Decision, error) {
 	if w.confirmer == nil {
 		// No confirmer means no way to ask; refuse rather than write unconfirmed.
-		return false, fmt.Errorf("confirmation required but no confirmer is configured")
+		return Decision{}, fmt.Errorf("confirmation required but no confirmer is configured")
 	}
 
 	request := events.UserConfirmationRequest{
@@ -217,13 +263,14 @@ func (w *WriteTool) requestDiffConfirmation(ctx context.Context, filePath, diffC
 		Content:     diffContent,
 		ContentType: "diff",
 		Message:     fmt.Sprintf("Write changes to %s", filePath),
+		Editable:    content,
 	}
 
 	// Bound the wait so an unanswered confirmation cannot hang a turn forever.
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
-	return w.confirmer.ConfirmContent(ctx, request)
+	return decideContent(ctx, w.confirmer, request)
 }
 
 // createBackup creates a backup of the existing file
@@ -9,6 +9,7 @@ import (
 
 	"github.com/kcaldas/genie/pkg/events"
 	"github.com/kcaldas/genie/pkg/toolctx"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -52,3 +53,32 @@ func TestWriteToolConfirmationsDoNotLeakHandlers(t *testing.T) {
 	require.Equal(t, baseline, inMem.SubscriberCount(events.UserConfirmationResponse{}.Topic()),
 		"confirmed writes must not accumulate response handlers")
 }
+
+func TestWriteToolWritesContentEditedAtConfirmation(t *testing.T) {
+	bus := events.NewEventBus()
+	events.SubscribeTo(bus, func(req events.UserConfirmationRequest) {
+		assert.Equal(t, "proposed\n", req.Editable)
+		bus.Publish(events.UserConfirmationResponse{}.Topic(), events.UserConfirmationResponse{
+			ExecutionID: req.ExecutionID,
+			Confirmed:   true,
+			Content:     "edited\n",
+		})
+	})
+
+	dir := t.TempDir()
+	ctx := toolctx.WithWorkingDir(context.Background(), dir)
+	path := filepath.Join(dir, "notes.txt")
+
+	result, err := NewWriteTool(bus, true).Handler()(ctx, map[string]any{
+		"path":    path,
+		"content": "proposed\n",
+	})
+	require.NoError(t, err)
+	require.Equal(t, true, result["success"])
+	require.Contains(t, result["results"], "edited by the user")
+	require.Contains(t, result["diff"], "+edited")
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "edited\n", string(written))
+}
@@ -0,0 +1,177 @@
+// Package trust records which workspaces the user trusts.
+//
+// A workspace can ship its own personas, skills, slash commands, prompt
+// templates, MCP servers (.mcp.json) and commands Genie runs: hooks,
+// language servers and checks. Those run with the user's credentials, so a
+// freshly cloned repository could inject prompts or start arbitrary
+// processes. Genie asks once per workspace and remembers
+// the answer here; untrusted workspaces load only user-level and built-in
+// configuration.
+package trust
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the store file inside ~/.genie.
+const FileName = "trusted_workspaces.json"
+
+// projectConfigPaths are the workspace-relative paths that only load when
+// the workspace is trusted.
+var projectConfigPaths = []string{
+	filepath.Join(".genie", "personas"),
+	filepath.Join(".genie", "skills"),
+	filepath.Join(".genie", "commands"),
+	filepath.Join(".genie", "prompts"),
+	filepath.Join(".genie", "templates"),
+	filepath.Join(".genie", "team.yaml"),
+	filepath.Join(".genie", "lsp.json"),
+	filepath.Join(".genie", "settings.json"),
+	filepath.Join(".genie", "hooks"),
+	filepath.Join(".genie", "hooks.yaml"),
+	filepath.Join(".claude", "skills"),
+	filepath.Join(".claude", "commands"),
+	".mcp.json",
+}
+
+// Decision is a remembered answer for one workspace directory.
+type Decision struct {
+	Trusted   bool      `json:"trusted"`
+	DecidedAt time.Time `json:"decided_at"`
+}
+
+type storeFile struct {
+	Workspaces map[string]Decision `json:"workspaces"`
+}
+
+// Store persists trust decisions in a JSON file.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore returns a store backed by the file at path. The file is created
+// on the first Set.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultStore returns the store in ~/.genie/trusted_workspaces.json.
+func DefaultStore() (*Store, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return NewStore(filepath.Join(home, ".genie", FileName)), nil
+}
+
+// Lookup returns the decision that applies to dir: the one recorded for dir
+// itself or, failing that, for its nearest recorded ancestor. Trusting a
+// directory therefore trusts everything below it.
+func (s *Store) Lookup(dir string) (Decision, bool, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Decision{}, false, fmt.Errorf("failed to resolve workspace path: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return Decision{}, false, err
+	}
+	for {
+		if decision, ok := file.Workspaces[dir]; ok {
+			return decision, true, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Decision{}, false, nil
+		}
+		dir = parent
+	}
+}
+
+// Set records the decision for dir, replacing any earlier one.
+func (s *Store) Set(dir string, trusted bool) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve workspace path: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+	file.Workspaces[dir] = Decision{Trusted: trusted, DecidedAt: time.Now().UTC()}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trust store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create trust store directory: %w", err)
+	}
+	// Write to a temp file and rename so a crash never leaves a truncated store
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write trust store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write trust store: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) load() (*storeFile, error) {
+	file := &storeFile{Workspaces: make(map[string]Decision)}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust store %s: %w", s.path, err)
+	}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse trust store %s: %w", s.path, err)
+	}
+	if file.Workspaces == nil {
+		file.Workspaces = make(map[string]Decision)
+	}
+	return file, nil
+}
+
+// ProjectConfig returns the project-local configuration found in dir, as
+// workspace-relative paths. A workspace without any has nothing to gate,
+// so there is no need to ask about it.
+func ProjectConfig(dir string) []string {
+	var found []string
+	for _, rel := range projectConfigPaths {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			found = append(found, rel)
+		}
+	}
+	return found
+}
+
+// IsHomeDir reports whether dir is the user's home directory, whose .genie
+// folder is the user-level configuration and always trusted.
+func IsHomeDir(dir string) bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(filepath.Clean(abs), filepath.Clean(home))
+}
@@ -0,0 +1,83 @@
+package trust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_SetAndLookup(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "genie", FileName))
+	workspace := t.TempDir()
+
+	_, known, err := store.Lookup(workspace)
+	require.NoError(t, err)
+	assert.False(t, known, "missing store file means no decision")
+
+	require.NoError(t, store.Set(workspace, false))
+	decision, known, err := store.Lookup(workspace)
+	require.NoError(t, err)
+	assert.True(t, known)
+	assert.False(t, decision.Trusted)
+	assert.False(t, decision.DecidedAt.IsZero())
+
+	// A fresh store over the same file sees the persisted decision
+	require.NoError(t, store.Set(workspace, true))
+	decision, _, err = NewStore(store.path).Lookup(workspace)
+	require.NoError(t, err)
+	assert.True(t, decision.Trusted)
+}
+
+func TestStore_LookupInheritsFromAncestor(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), FileName))
+	parent := t.TempDir()
+	child := filepath.Join(parent, "services", "api")
+
+	require.NoError(t, store.Set(parent, true))
+	decision, known, err := store.Lookup(child)
+	require.NoError(t, err)
+	assert.True(t, known)
+	assert.True(t, decision.Trusted)
+
+	// The nearest decision wins
+	require.NoError(t, store.Set(filepath.Join(parent, "services"), false))
+	decision, _, err = store.Lookup(child)
+	require.NoError(t, err)
+	assert.False(t, decision.Trusted)
+}
+
+func TestStore_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+	_, _, err := NewStore(path).Lookup(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestProjectConfig(t *testing.T) {
+	workspace := t.TempDir()
+	assert.Empty(t, ProjectConfig(workspace))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, ".genie", "personas"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, ".mcp.json"), []byte("{}"), 0o644))
+	assert.Equal(t, []string{filepath.Join(".genie", "personas"), ".mcp.json"}, ProjectConfig(workspace))
+}
+
+// Each of these can make Genie run commands, so it alone must trigger the
+// trust prompt.
+func TestProjectConfig_DetectsCommandConfig(t *testing.T) {
+	for file, want := range map[string]string{
+		filepath.Join(".genie", "lsp.json"):            filepath.Join(".genie", "lsp.json"),
+		filepath.Join(".genie", "settings.json"):       filepath.Join(".genie", "settings.json"),
+		filepath.Join(".genie", "hooks", "notify.lua"): filepath.Join(".genie", "hooks"),
+		filepath.Join(".genie", "hooks.yaml"):          filepath.Join(".genie", "hooks.yaml"),
+	} {
+		workspace := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(workspace, file)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, file), []byte("{}"), 0o644))
+		assert.Equal(t, []string{want}, ProjectConfig(workspace), file)
+	}
+}
@@ -0,0 +1,92 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/kcaldas/genie/pkg/version"
+)
+
+// CheckConfigKey turns off the background check for new versions when
+// set to false.
+const CheckConfigKey = "GENIE_UPDATE_CHECK"
+
+// CheckInterval is how long the answer of a background check is reused,
+// so starting Genie often does not run into GitHub's rate limits.
+const CheckInterval = 24 * time.Hour
+
+// checkFile records the last background check in ~/.genie
+const checkFile = "update-check.json"
+
+type lastCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// NewerVersion returns the latest release when it is newer than the
+// running version, or "" when there is none. Unlike CheckForUpdates it
+// never reports development builds as outdated, and it asks GitHub at most
+// once per CheckInterval, reusing the answer recorded in ~/.genie.
+func (u *Updater) NewerVersion(ctx context.Context) (string, error) {
+	current, err := semver.NewVersion(version.GetVersion())
+	if err != nil {
+		return "", nil
+	}
+
+	path := checkPath()
+	last, ok := readCheck(path)
+	if !ok || time.Since(last.CheckedAt) > CheckInterval {
+		latest, err := u.GetLatestVersion(ctx)
+		if err != nil {
+			return "", err
+		}
+		last = lastCheck{CheckedAt: time.Now(), Latest: latest}
+		writeCheck(path, last)
+	}
+
+	latest, err := semver.NewVersion(last.Latest)
+	if err != nil || !latest.GreaterThan(current) {
+		return "", nil
+	}
+	return last.Latest, nil
+}
+
+func checkPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".genie", checkFile)
+}
+
+func readCheck(path string) (lastCheck, bool) {
+	var last lastCheck
+	if path == "" {
+		return last, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &last) != nil {
+		return last, false
+	}
+	return last, true
+}
+
+// writeCheck records a check; failing to is harmless, the next start
+// just asks GitHub again
+func writeCheck(path string, last lastCheck) {
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(last)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,77 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/creativeprojects/go-selfupdate"
+)
+
+func TestNewerVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentVersion string
+		want           string
+	}{
+		{"older release", "1.0.0", "1.3.0"},
+		{"same release", "1.3.0", ""},
+		{"newer release", "2.0.0", ""},
+		{"dev build is never outdated", "dev", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			setVersion(t, tt.currentVersion)
+			updater := newTestUpdater(t, &stubSource{
+				releases: []selfupdate.SourceRelease{releaseWithAssets("1.3.0", "")},
+			})
+
+			got, err := updater.NewerVersion(context.Background())
+			if err != nil {
+				t.Fatalf("NewerVersion returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NewerVersion = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewerVersionReusesRecentCheck(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	setVersion(t, "1.0.0")
+
+	updater := newTestUpdater(t, &stubSource{
+		releases: []selfupdate.SourceRelease{releaseWithAssets("1.3.0", "")},
+	})
+	if _, err := updater.NewerVersion(context.Background()); err != nil {
+		t.Fatalf("NewerVersion returned error: %v", err)
+	}
+
+	// GitHub is not asked again within the check interval
+	offline := newTestUpdater(t, &stubSource{listErr: errors.New("network down")})
+	got, err := offline.NewerVersion(context.Background())
+	if err != nil {
+		t.Fatalf("NewerVersion should reuse the recorded check, got error: %v", err)
+	}
+	if got != "1.3.0" {
+		t.Errorf("NewerVersion = %q, want %q", got, "1.3.0")
+	}
+
+	// Once the check is stale it is asked again
+	path := filepath.Join(home, ".genie", checkFile)
+	stale := time.Now().Add(-2 * CheckInterval)
+	writeCheck(path, lastCheck{CheckedAt: stale, Latest: "1.3.0"})
+	if _, err := offline.NewerVersion(context.Background()); err == nil {
+		t.Error("NewerVersion should ask GitHub again once the recorded check is stale")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("check file missing: %v", err)
+	}
+}